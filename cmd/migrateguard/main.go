@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	entmigrate "github.com/abisalde/authentication-service/internal/database/ent/migrate"
+	"github.com/abisalde/authentication-service/internal/database/migrationguard"
+)
+
+func main() {
+	since := flag.String("since", "", "only check migrations numbered at or after this version, e.g. 0006")
+	tables := flag.String("tables", strings.Join(migrationguard.DefaultLockSensitiveTables, ","), "comma separated tables where locking index builds and rebuilds are blocked")
+	allowUnsafe := flag.Bool("allow-unsafe", false, "report unsafe statements without failing")
+	flag.Parse()
+
+	files := flag.Args()
+	if len(files) == 0 {
+		matches, err := filepath.Glob(filepath.Join("migrations", "*.up.sql"))
+		if err != nil {
+			log.Fatalf("❌ Failed to list migrations: %v", err)
+		}
+		files = matches
+	}
+	sort.Strings(files)
+
+	opts := migrationguard.Options{
+		LockSensitiveTables: strings.Split(*tables, ","),
+		InUse:               migrationguard.InUseBySchema(entmigrate.Tables),
+	}
+
+	unsafe := 0
+	for _, file := range files {
+		name := filepath.Base(file)
+		if *since != "" && name < *since {
+			continue
+		}
+
+		script, err := os.ReadFile(file)
+		if err != nil {
+			log.Fatalf("❌ Failed to read %s: %v", file, err)
+		}
+		for _, f := range migrationguard.Inspect(string(script), opts) {
+			unsafe++
+			fmt.Printf("%s: %s\n", name, f)
+		}
+	}
+
+	if unsafe == 0 {
+		return
+	}
+	if *allowUnsafe {
+		log.Printf("⚠️ %d unsafe statement(s) allowed by -allow-unsafe", unsafe)
+		return
+	}
+	log.Printf("❌ %d unsafe statement(s); fix them, mark reviewed ones with -- guard:allow <rule>, or rerun with -allow-unsafe", unsafe)
+	os.Exit(1)
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	server "github.com/abisalde/authentication-service/cmd"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/diagnostics"
+	"github.com/abisalde/authentication-service/internal/redisbackup"
+)
+
+const usage = `usage: redisbackup export -out FILE [-keyspaces a,b] [-batch N]
+       redisbackup import -in FILE [-replace]
+
+The backup key is read from REDIS_BACKUP_KEY (openssl rand -base64 32).`
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	out := flags.String("out", "", "file the backup is written to")
+	in := flags.String("in", "", "backup file to restore")
+	keyspaces := flags.String("keyspaces", "", "comma separated keyspaces to export, every backed-up keyspace by default")
+	batch := flags.Int("batch", 0, "keys read per transaction; 0 takes a single point-in-time snapshot")
+	replace := flags.Bool("replace", false, "overwrite keys written since the backup")
+	timeout := flags.Duration("timeout", 10*time.Minute, "maximum time spent exporting or importing")
+	_ = flags.Parse(os.Args[2:])
+
+	key, err := redisbackup.ParseKey(os.Getenv("REDIS_BACKUP_KEY"))
+	if err != nil {
+		log.Fatalf("❌ REDIS_BACKUP_KEY: %v", err)
+	}
+
+	appCfgLoader, _, err := server.InitConfig()
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize configuration: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	if appCfgLoader.Embedded.Enabled {
+		log.Fatalf("❌ %v", database.ErrEmbeddedStore)
+	}
+
+	redisCache, err := database.InitRedis(ctx, appCfgLoader)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to Redis: %v", err)
+	}
+	defer redisCache.Close()
+
+	switch os.Args[1] {
+	case "export":
+		if *out == "" {
+			log.Fatal(usage)
+		}
+		patterns, err := backupPatterns(*keyspaces)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		// The file only appears under its name once the backup is complete.
+		file, err := os.OpenFile(*out+".partial", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			log.Fatalf("❌ Failed to create %s: %v", *out, err)
+		}
+		stats, err := redisbackup.Export(ctx, redisCache.RawClient(), file, key, redisbackup.ExportOptions{Patterns: patterns, Batch: *batch})
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			err = os.Rename(*out+".partial", *out)
+		}
+		if err != nil {
+			_ = os.Remove(*out + ".partial")
+			log.Fatalf("❌ Failed to export Redis: %v", err)
+		}
+		fmt.Printf("Exported %d keys to %s (%d expired while reading)\n", stats.Keys, *out, stats.Expired)
+
+	case "import":
+		if *in == "" {
+			log.Fatal(usage)
+		}
+		file, err := os.Open(*in)
+		if err != nil {
+			log.Fatalf("❌ Failed to open %s: %v", *in, err)
+		}
+		defer file.Close()
+
+		manifest, stats, err := redisbackup.Import(ctx, redisCache.RawClient(), file, key, redisbackup.ImportOptions{Replace: *replace})
+		if err != nil {
+			log.Fatalf("❌ Failed to import %s after %d keys: %v", *in, stats.Keys, err)
+		}
+		fmt.Printf("Restored %d keys from the backup of %s; kept %d existing, skipped %d expired\n",
+			stats.Keys, manifest.CreatedAt.Format(time.RFC3339), stats.Existing, stats.Expired)
+
+	default:
+		log.Fatal(usage)
+	}
+}
+
+func backupPatterns(names string) ([]string, error) {
+	var patterns []string
+	if names == "" {
+		for _, category := range diagnostics.DefaultKeyspaces {
+			if category.Backup {
+				patterns = append(patterns, category.Pattern)
+			}
+		}
+		return patterns, nil
+	}
+
+	for _, name := range strings.Split(names, ",") {
+		found := false
+		for _, category := range diagnostics.DefaultKeyspaces {
+			if category.Name == strings.TrimSpace(name) {
+				patterns = append(patterns, category.Pattern)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown keyspace %q", name)
+		}
+	}
+	return patterns, nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	server "github.com/abisalde/authentication-service/cmd"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/diagnostics"
+)
+
+func main() {
+	limit := flag.Int("offenders", 20, "keys without TTL listed per category")
+	timeout := flag.Duration("timeout", 2*time.Minute, "maximum time spent scanning Redis")
+	flag.Parse()
+
+	appCfgLoader, _, err := server.InitConfig()
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize configuration: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	if appCfgLoader.Embedded.Enabled {
+		log.Fatalf("❌ %v", database.ErrEmbeddedStore)
+	}
+
+	redisCache, err := database.InitRedis(ctx, appCfgLoader)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to Redis: %v", err)
+	}
+	defer redisCache.Close()
+
+	audits, err := diagnostics.RedisTTLAudit(ctx, redisCache.RawClient(), diagnostics.DefaultKeyspaces, *limit)
+	if err != nil {
+		log.Fatalf("❌ Failed to audit Redis keyspaces: %v", err)
+	}
+
+	var offending int64
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CATEGORY\tPATTERN\tKEYS\tNO EXPIRY\tEXAMPLES")
+	for _, a := range audits {
+		offending += a.NoExpiry
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\n", a.Category, a.Pattern, a.Keys, a.NoExpiry, strings.Join(a.Offenders, ","))
+	}
+	_ = w.Flush()
+
+	if offending > 0 {
+		os.Exit(1)
+	}
+}
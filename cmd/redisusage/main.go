@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	server "github.com/abisalde/authentication-service/cmd"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/diagnostics"
+)
+
+func main() {
+	sampleSize := flag.Int("sample", 100, "keys sampled with MEMORY USAGE per category")
+	timeout := flag.Duration("timeout", 2*time.Minute, "maximum time spent scanning Redis")
+	flag.Parse()
+
+	appCfgLoader, _, err := server.InitConfig()
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize configuration: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	if appCfgLoader.Embedded.Enabled {
+		log.Fatalf("❌ %v", database.ErrEmbeddedStore)
+	}
+
+	redisCache, err := database.InitRedis(ctx, appCfgLoader)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to Redis: %v", err)
+	}
+	defer redisCache.Close()
+
+	usages, err := diagnostics.RedisKeyspaceUsage(ctx, redisCache.RawClient(), diagnostics.DefaultKeyspaces, *sampleSize)
+	if err != nil {
+		log.Fatalf("❌ Failed to sample Redis keyspaces: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CATEGORY\tPATTERN\tKEYS\tSAMPLED\tSAMPLED BYTES\tESTIMATED BYTES")
+	for _, u := range usages {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\n", u.Category, u.Pattern, u.Keys, u.SampledKeys, u.SampledBytes, u.EstimatedBytes)
+	}
+	_ = w.Flush()
+}
@@ -1,33 +1,62 @@
 package main
 
 import (
+	"context"
 	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 
 	server "github.com/abisalde/authentication-service/cmd"
+	"github.com/abisalde/authentication-service/internal/lifecycle"
 	"github.com/abisalde/authentication-service/internal/utils"
 )
 
 func main() {
+	if err := run(); err != nil {
+		slog.Error("Authentication service failed", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("Authentication service stopped")
+}
+
+// run serves until SIGTERM or SIGINT, then drains the services before the
+// database, Redis and tracing are closed.
+func run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
 	appCfgLoader, appCfg, err := server.InitConfig()
 	if err != nil {
 		log.Fatalf("❌ Failed to initialize configuration: %v", err)
 	}
 
+	shutdownTracing := server.SetupTracing(appCfgLoader)
+	defer shutdownTracing()
+
 	db, redisClient, err := server.SetupDatabase(appCfgLoader)
 	if err != nil {
 		log.Fatalf("❌ Failed to setup database: %v", err)
 	}
 	defer db.Close()
+	defer redisClient.Close()
 
-	gqlSrv, auth, oauth := server.SetupGraphQLServer(db, redisClient, appCfgLoader)
+	services := lifecycle.New(appCfgLoader.Server.ShutdownTimeout)
 
-	authService := server.SetupFiberApp(db, gqlSrv, auth, oauth)
+	gqlSrv, adminSrv, auth, oauth, limits := server.SetupGraphQLServer(db, redisClient, appCfgLoader, services)
+
+	authService := server.SetupFiberApp(db, gqlSrv, adminSrv, auth, oauth, limits, appCfgLoader)
 
 	portHost := utils.GetListenAddress(appCfg)
 
-	log.Printf("🐳 Hello, Authentication MicroService from Docker <3 🚀::: 🔐 at http://localhost:%s", appCfg.HTTPPort)
-	log.Printf("〒 App Current Environment %s ㉿:", appCfg.AppEnv)
-	log.Printf("☞ ☞ %s", portHost)
-	log.Fatal(authService.Listen(portHost))
+	// Added last so they stop taking requests first.
+	if addr := appCfgLoader.Server.MetricsAddress; addr != "" {
+		services.Add(server.MetricsServer(addr))
+		slog.Info("Serving metrics", "address", addr)
+	}
+	services.Add(server.HTTPServer("http", authService, portHost))
+
+	slog.Info("Authentication service started", "port", appCfg.HTTPPort, "env", appCfg.AppEnv, "address", portHost)
+	return services.Run(ctx)
 }
@@ -2,7 +2,9 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"time"
 
@@ -12,18 +14,36 @@ import (
 	"github.com/99designs/gqlgen/graphql/handler/lru"
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/abisalde/authentication-service/internal/alerting"
+	"github.com/abisalde/authentication-service/internal/auth/automation"
+	"github.com/abisalde/authentication-service/internal/auth/cookies"
 	"github.com/abisalde/authentication-service/internal/auth/handler/oauth"
 	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/residency"
 	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/auth/sessionstore"
 	"github.com/abisalde/authentication-service/internal/configs"
 	"github.com/abisalde/authentication-service/internal/database"
 	"github.com/abisalde/authentication-service/internal/graph"
+	"github.com/abisalde/authentication-service/internal/graph/admin"
+	admin_resolvers "github.com/abisalde/authentication-service/internal/graph/admin/resolvers"
 	"github.com/abisalde/authentication-service/internal/graph/directives"
 	"github.com/abisalde/authentication-service/internal/graph/resolvers"
 	"github.com/abisalde/authentication-service/internal/handlers"
+	"github.com/abisalde/authentication-service/internal/lifecycle"
+	"github.com/abisalde/authentication-service/internal/metrics"
 	"github.com/abisalde/authentication-service/internal/middleware"
+	"github.com/abisalde/authentication-service/internal/ratelimit"
+	"github.com/abisalde/authentication-service/internal/siem"
+	"github.com/abisalde/authentication-service/internal/slo"
+	"github.com/abisalde/authentication-service/internal/tracing"
 	"github.com/abisalde/authentication-service/internal/worker"
+	"github.com/abisalde/authentication-service/pkg/idgen"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	app_logger "github.com/abisalde/authentication-service/pkg/logger"
 	"github.com/abisalde/authentication-service/pkg/mail"
+	"github.com/abisalde/authentication-service/pkg/qos"
+	"github.com/abisalde/authentication-service/pkg/scheduler"
 	"github.com/joho/godotenv"
 	"github.com/vektah/gqlparser/v2/ast"
 
@@ -31,7 +51,6 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/healthcheck"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 )
 
 const defaultPort = "8080"
@@ -45,13 +64,14 @@ func InitConfig() (*configs.Config, *AppConfig, error) {
 
 	err := godotenv.Load()
 	if err != nil {
-		log.Println("No .env file found, using environment variables")
+		slog.Info("No .env file found, using environment variables")
 	}
 
 	cfg, err := configs.Load(os.Getenv("APP_ENV"))
 	if err != nil {
 		return nil, nil, err
 	}
+	app_logger.Configure(app_logger.Options{Level: cfg.Logging.Level, Format: cfg.Logging.Format})
 
 	httpPort := os.Getenv("PORT")
 	if httpPort == "" {
@@ -63,11 +83,63 @@ func InitConfig() (*configs.Config, *AppConfig, error) {
 		AppEnv:   os.Getenv("APP_ENV"),
 	}
 
+	if err := jwt.Configure(jwt.OptionsFromConfig(cfg)); err != nil {
+		return nil, nil, err
+	}
+	slo.Default.SetObjectives(slo.ObjectivesFromConfig(cfg))
+
+	keys, err := jwt.LoadKeySet(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	jwt.SetKeySet(keys)
+	if keys != nil && cfg.JWT.SigningKeys.ReloadInterval > 0 {
+		go reloadSigningKeys(cfg, cfg.JWT.SigningKeys.ReloadInterval)
+	}
+	if cfg.OIDC.Enabled && keys == nil {
+		slog.Warn("OIDC ID tokens are signed with JWT_SECRET; relying parties cannot verify them without signing keys")
+	}
+
+	cookies.RequireSecureCookies(cfg.HTTPS.RequireSecureCookies)
+
 	mail.NewMailerService(cfg)
 
 	return cfg, appConfig, nil
 }
 
+// reloadSigningKeys re-reads the key files so a rotated primary is picked up
+// without a restart. Every replica signs with its own copy, so this is a local
+// ticker rather than a scheduled job. A broken reload keeps the current keys.
+func reloadSigningKeys(cfg *configs.Config, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		keys, err := jwt.LoadKeySet(cfg)
+		if err != nil || keys == nil {
+			slog.Warn("Failed to reload signing keys, keeping the current set", "error", err)
+			continue
+		}
+		jwt.SetKeySet(keys)
+	}
+}
+
+// SetupTracing installs the tracer provider. The returned function flushes
+// the spans still buffered and should run on shutdown.
+func SetupTracing(cfg *configs.Config) func() {
+	shutdown, err := tracing.Setup(context.Background(), cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed to set up tracing: %v", err)
+	}
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			slog.Error("Failed to flush traces", "error", err)
+		}
+	}
+}
+
 func SetupDatabase(cfg *configs.Config) (*database.Database, *database.RedisCache, error) {
 	db, err := database.Connect(cfg)
 	if err != nil {
@@ -89,14 +161,40 @@ func SetupDatabase(cfg *configs.Config) (*database.Database, *database.RedisCach
 		return nil, nil, redisErr
 	}
 
+	jwt.SetScopeStore(jwt.NewRedisScopeStore(redisCache.RawClient()))
+	jwt.SetClaimsStore(jwt.NewRedisClaimsStore(redisCache.RawClient()))
+
 	return db, redisCache, nil
 }
 
-func SetupGraphQLServer(db *database.Database, redisClient *database.RedisCache, cfg *configs.Config) (server *handler.Server, authResult *service.AuthService, oauth *service.OAuthService) {
+// SetupGraphQLServer builds the GraphQL servers and registers the workers
+// and scheduled jobs with services, which starts them.
+func SetupGraphQLServer(db *database.Database, redisClient *database.RedisCache, cfg *configs.Config, services *lifecycle.Manager) (server *handler.Server, adminServer *handler.Server, authResult *service.AuthService, oauth *service.OAuthService, limits *directives.RateLimitDirective) {
 
 	mailerService := mail.NewMailerService(cfg)
-	cacheService := database.NewCacheService(redisClient.RawClient())
-	userRepo := repository.NewUserRepository(db.Client)
+
+	// Every region runs this binary against its own stores; the router is
+	// the hook for deployments that reach several regions' stores.
+	residencyPolicy := residency.NewPolicy(cfg)
+	router := residency.StaticRouter{
+		residencyPolicy.Region: {DB: db.Client, Redis: redisClient.RawClient()},
+	}
+	stores, err := router.Route(residencyPolicy.Region)
+	if err != nil {
+		log.Fatalf("❌ Invalid residency configuration: %v", err)
+	}
+
+	cacheService := database.NewCacheService(stores.Redis).
+		WithTTLPolicy(cfg.Redis.RequireTTL, cfg.Redis.DefaultTTL)
+
+	ids, err := idgen.New(cfg.IDs.Strategy, cfg.IDs.NodeID)
+	if err != nil {
+		log.Fatalf("❌ Invalid id generator configuration: %v", err)
+	}
+	userRepo, err := repository.NewRegionalUserRepository(router, residencyPolicy, ids)
+	if err != nil {
+		log.Fatalf("❌ Invalid residency configuration: %v", err)
+	}
 
 	authService := service.NewAuthService(
 		userRepo,
@@ -104,33 +202,254 @@ func SetupGraphQLServer(db *database.Database, redisClient *database.RedisCache,
 		cacheService,
 		mailerService,
 	)
+	if err := authService.ScopePolicy().Validate(); err != nil {
+		log.Fatalf("❌ Invalid token scope configuration: %v", err)
+	}
+	if err := setupSessionStore(authService, db, cfg); err != nil {
+		log.Fatalf("❌ Failed to set up the session store: %v", err)
+	}
 
 	oauthService := service.NewOAuthService(authService)
 
-	worker := worker.NewLastLoginWorker(redisClient.RawClient(), authService)
-	consumerCtx, consumerCancel := context.WithCancel(context.Background())
-	go worker.Start(consumerCtx)
-	defer consumerCancel()
+	alerts := alerting.New(cfg)
+	oauthService.AlertOnDegradation(alerts)
+	authService.AlertOnSecurityEvents(alerts)
+
+	lastLogin := worker.NewLastLoginWorker(redisClient.RawClient(), authService, cfg, authService.Logger())
+	services.Go("last_login_worker", func(ctx context.Context) error {
+		lastLogin.Start(ctx)
+		return nil
+	})
+	jobs := startScheduler(redisClient, authService, cfg)
+	services.Go("scheduler", func(ctx context.Context) error {
+		jobs.Start(ctx)
+		<-ctx.Done()
+		jobs.Stop()
+		return nil
+	})
 
-	resolver := resolvers.NewResolver(db.Client, authService, oauthService)
-	auth := directives.NewAuthDirective()
-	rateLimit := directives.NewRateLimitDirective(redisClient)
+	limiter := ratelimit.New(redisClient).
+		WithAttackAlerts(alerts, cfg.Alerting.RateLimit.Rejections, cfg.Alerting.RateLimit.Window).
+		WithProbation(authService.ProbationPolicy()).
+		WithClientQuotas(ratelimit.QuotasFromConfig(cfg)).
+		OnReject(recordLockouts(authService))
+	resolver := resolvers.NewResolver(db.Client, authService, oauthService, limiter)
+	authDecisions := directives.NewAuthDecisions(cfg.AuthDecisions.CacheTTL)
+	authService.OnRoleChange(authDecisions.Invalidate)
+	auth := directives.NewAuthDirective().WithDecisionCache(authDecisions).WithMaintenanceScope(authService)
+	rateLimit := directives.NewRateLimitDirective(limiter)
 	constraint := directives.NewConstraint()
 	defaultDirective := directives.NewDefaultDirective()
+	onboardingDirective := directives.NewOnboardingDirective(authService.OnboardingPolicy())
 
-	srv := handler.New(graph.NewExecutableSchema(graph.Config{
+	schema := graph.NewExecutableSchema(graph.Config{
 		Resolvers: resolver,
 		Directives: graph.DirectiveRoot{
 			Auth:       auth.Auth,
 			RateLimit:  rateLimit.RateLimit,
 			Constraint: constraint.Constraints,
 			Default:    defaultDirective.Default,
+			Onboarded:  onboardingDirective.Onboarded,
 		},
-	}))
+	})
+
+	adminSchema := admin.NewExecutableSchema(admin.Config{
+		Resolvers: admin_resolvers.NewResolver(authService, oauthService),
+		Directives: admin.DirectiveRoot{
+			Auth:       auth.Auth,
+			RateLimit:  rateLimit.RateLimit,
+			Constraint: constraint.Constraints,
+			Default:    defaultDirective.Default,
+			Onboarded:  onboardingDirective.Onboarded,
+		},
+	})
+
+	for _, s := range []graphql.ExecutableSchema{schema, adminSchema} {
+		if err := rateLimit.Compile(s.Schema()); err != nil {
+			log.Fatalf("❌ Invalid rate limit configuration: %v", err)
+		}
+	}
+
+	return newGraphQLServer(schema, cfg), newGraphQLServer(adminSchema, cfg), authService, oauthService, rateLimit
+}
+
+// setupSessionStore moves refresh sessions out of Redis when sessions.store
+// says so. The sql store's table is created where the schema is migrated
+// automatically; elsewhere migrations/0011_add_sessions creates it.
+func setupSessionStore(authService *service.AuthService, db *database.Database, cfg *configs.Config) error {
+	switch cfg.Sessions.Store {
+	case "memory":
+		authService.SetSessionStore(sessionstore.NewMemoryStore())
+	case "sql":
+		store := sessionstore.NewSQLStore(db.SQLDB)
+		if cfg.DB.Migrate || cfg.Embedded.Enabled {
+			if err := store.Migrate(context.Background()); err != nil {
+				return err
+			}
+		}
+		authService.SetSessionStore(store)
+	}
+	return nil
+}
+
+// recordLockouts reports rate limited clients as security events.
+func recordLockouts(authService *service.AuthService) func(context.Context, ratelimit.Policy, ratelimit.Client) {
+	return func(ctx context.Context, policy ratelimit.Policy, client ratelimit.Client) {
+		event := service.SecurityEvent{
+			Email:  client.Email,
+			IP:     client.IP,
+			Reason: policy.Operation.String(),
+		}
+		if client.User != nil {
+			event.UserID = client.User.ID
+			if event.Email == "" {
+				event.Email = client.User.Email
+			}
+		}
+		authService.RecordLockout(ctx, ratelimit.CounterKey(policy, client), policy.Window, event)
+	}
+}
+
+// startScheduler registers the periodic jobs. Runs are locked in Redis, so
+// each job runs on one replica per tick. The jobs start with the returned
+// scheduler.
+func startScheduler(redisClient *database.RedisCache, authService *service.AuthService, cfg *configs.Config) *scheduler.Scheduler {
+	jobs := scheduler.New(
+		scheduler.NewRedisLocker(redisClient.RawClient()),
+		scheduler.NewRedisHistory(redisClient.RawClient(), scheduler.DefaultHistorySize),
+	)
+
+	pruneInterval := cfg.LoginHistory.PruneInterval
+	if pruneInterval <= 0 {
+		pruneInterval = time.Hour
+	}
+	err := jobs.Register("login_history_prune", fmt.Sprintf("@every %s", pruneInterval), func(ctx context.Context) error {
+		deleted, err := authService.PruneLoginHistory(ctx)
+		if deleted > 0 {
+			slog.Info("Pruned login attempts past retention", "deleted", deleted)
+		}
+		return err
+	})
+	if err != nil {
+		log.Fatalf("❌ Invalid scheduled job: %v", err)
+	}
+
+	purgeInterval := cfg.Deletion.PurgeInterval
+	if purgeInterval <= 0 {
+		purgeInterval = 6 * time.Hour
+	}
+	err = jobs.Register("account_purge", fmt.Sprintf("@every %s", purgeInterval), func(ctx context.Context) error {
+		purged, err := authService.PurgeDeletedAccounts(ctx)
+		if purged > 0 {
+			slog.Info("Purged accounts past the deletion retention", "purged", purged)
+		}
+		return err
+	})
+	if err != nil {
+		log.Fatalf("❌ Invalid scheduled job: %v", err)
+	}
+
+	actionInterval := cfg.AccountActions.ScanInterval
+	if actionInterval <= 0 {
+		actionInterval = time.Minute
+	}
+	err = jobs.Register("account_actions", fmt.Sprintf("@every %s", actionInterval), func(ctx context.Context) error {
+		ran, err := authService.RunDueAccountActions(ctx)
+		if ran > 0 {
+			slog.Info("Ran scheduled account actions", "ran", ran)
+		}
+		return err
+	})
+	if err != nil {
+		log.Fatalf("❌ Invalid scheduled job: %v", err)
+	}
+
+	nudgeInterval := cfg.VerificationNudges.ScanInterval
+	if nudgeInterval <= 0 {
+		nudgeInterval = 15 * time.Minute
+	}
+	err = jobs.Register("verification_nudges", fmt.Sprintf("@every %s", nudgeInterval), func(ctx context.Context) error {
+		sent, err := authService.NudgePendingVerifications(ctx)
+		if sent > 0 {
+			slog.Info("Sent verification nudges", "sent", sent)
+		}
+		return err
+	})
+	if err != nil {
+		log.Fatalf("❌ Invalid scheduled job: %v", err)
+	}
+
+	if cfg.RefreshReminder.Enabled {
+		scanInterval := cfg.RefreshReminder.ScanInterval
+		if scanInterval <= 0 {
+			scanInterval = 15 * time.Minute
+		}
+		err := jobs.Register("refresh_expiry_reminders", fmt.Sprintf("@every %s", scanInterval), func(ctx context.Context) error {
+			sent, err := authService.RemindExpiringRefreshTokens(ctx)
+			if sent > 0 {
+				slog.Info("Sent refresh token expiry reminders", "sent", sent)
+			}
+			return err
+		})
+		if err != nil {
+			log.Fatalf("❌ Invalid scheduled job: %v", err)
+		}
+	}
+
+	exporter, err := siem.New(cfg, redisClient.RawClient())
+	if err != nil {
+		log.Fatalf("❌ Invalid SIEM configuration: %v", err)
+	}
+	if exporter != nil {
+		exportInterval := cfg.SIEM.Interval
+		if exportInterval <= 0 {
+			exportInterval = 10 * time.Second
+		}
+		err := jobs.Register("siem_export", fmt.Sprintf("@every %s", exportInterval), func(ctx context.Context) error {
+			_, err := exporter.Export(ctx)
+			return err
+		})
+		if err != nil {
+			log.Fatalf("❌ Invalid scheduled job: %v", err)
+		}
+	}
+
+	return jobs
+}
+
+// HTTPServer serves app on addr until it is shut down, letting in-flight
+// requests finish.
+func HTTPServer(name string, app *fiber.App, addr string) lifecycle.Component {
+	return lifecycle.Component{
+		Name: name,
+		Run: func(context.Context) error {
+			return app.Listen(addr)
+		},
+		Stop: app.ShutdownWithContext,
+	}
+}
+
+// MetricsServer serves /metrics and /slo apart from the app, so scrapes do
+// not go through its middleware or compete with its traffic.
+func MetricsServer(addr string) lifecycle.Component {
+	app := fiber.New(fiber.Config{AppName: "Authentication Service Metrics", DisableStartupMessage: true})
+	app.Get("/metrics", metrics.Handler())
+	app.Get("/slo", slo.Handler())
+	return HTTPServer("metrics", app, addr)
+}
+
+func newGraphQLServer(schema graphql.ExecutableSchema, cfg *configs.Config) *handler.Server {
+	srv := handler.New(schema)
 
 	srv.AddTransport(transport.Options{})
 	srv.AddTransport(transport.GET{})
 	srv.AddTransport(transport.POST{})
+	if cfg.Limits.UploadBytes > 0 {
+		srv.AddTransport(transport.MultipartForm{
+			MaxUploadSize: cfg.Limits.UploadBytes,
+			MaxMemory:     cfg.Limits.UploadMemoryBytes,
+		})
+	}
 
 	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
 	srv.SetErrorPresenter(middleware.ErrorPresenter)
@@ -140,17 +459,19 @@ func SetupGraphQLServer(db *database.Database, redisClient *database.RedisCache,
 		Cache: lru.New[string](100),
 	})
 
+	srv.AroundOperations(tracing.AroundOperations)
+	srv.AroundResponses(tracing.AroundResponses)
+	srv.AroundOperations(directives.MemoizeAuth)
 	srv.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
 		op := graphql.GetOperationContext(ctx)
-		log.Println("Operation Name:::::", op.OperationName)
-		log.Println("GraphQL operation received")
+		slog.Debug("GraphQL operation received", "operation", op.OperationName)
 		return next(ctx)
 	})
 
-	return srv, authService, oauthService
+	return srv
 }
 
-func SetupFiberApp(db *database.Database, gqlSrv *handler.Server, auth *service.AuthService, oauthService *service.OAuthService) *fiber.App {
+func SetupFiberApp(db *database.Database, gqlSrv *handler.Server, adminSrv *handler.Server, auth *service.AuthService, oauthService *service.OAuthService, limits *directives.RateLimitDirective, cfg *configs.Config) *fiber.App {
 	env := os.Getenv("APP_ENV")
 	trustedDockerNetworkCIDR := "172.18.0.0/16"
 
@@ -160,6 +481,8 @@ func SetupFiberApp(db *database.Database, gqlSrv *handler.Server, auth *service.
 		CaseSensitive:           true,
 		EnableTrustedProxyCheck: true,
 		TrustedProxies:          []string{trustedDockerNetworkCIDR},
+		BodyLimit:               cfg.Limits.BodyBytes,
+		ErrorHandler:            handlers.ErrorHandler,
 	})
 
 	authService.Use(func(c *fiber.Ctx) error {
@@ -169,6 +492,9 @@ func SetupFiberApp(db *database.Database, gqlSrv *handler.Server, auth *service.
 		return c.Next()
 	})
 
+	authService.Use(middleware.RequestIDMiddleware)
+	authService.Use(middleware.ClientHintsMiddleware)
+
 	authService.Use(healthcheck.New(healthcheck.Config{
 		LivenessProbe: func(c *fiber.Ctx) bool {
 			return true
@@ -176,19 +502,71 @@ func SetupFiberApp(db *database.Database, gqlSrv *handler.Server, auth *service.
 		LivenessEndpoint: "/health",
 	}))
 
-	authService.Use(logger.New(logger.Config{
-		Format: "[${ip}]:${port} ${status} - ${method} ${path}\n",
-	}))
+	authService.Use(middleware.TracingMiddleware)
+
+	authService.Use(middleware.HTTPSMiddleware(cfg))
+
+	authService.Use(middleware.RequestLogMiddleware(auth.Logger()))
 
 	authService.Use(cors.New(cors.Config{
 		AllowOrigins:     "http://localhost:8080,http://localhost:3000",
 		AllowMethods:     "GET,POST,OPTIONS",
-		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, X-CSRF-Token",
 		AllowCredentials: true,
 	}))
 
+	if cfg.QoS.Enabled {
+		authService.Use(handlers.QoS(qos.New(qos.Config{
+			MaxConcurrent: cfg.QoS.MaxConcurrent,
+			MaxQueue:      cfg.QoS.MaxQueue,
+			QueueTimeout:  cfg.QoS.QueueTimeout,
+			ShedLowestAt:  cfg.QoS.ShedLowestAt,
+		})))
+	}
+
+	authService.Use(middleware.IPReputationMiddleware(auth))
+
+	// OAuth sign-ins spend the budget of the login mutation, so switching
+	// between them buys a client no extra attempts.
+	loginPolicy, ok := limits.Policies()["Mutation.login"]
+	if !ok {
+		log.Fatal("❌ Mutation.login has no rate limit to share with OAuth sign-ins")
+	}
+	// The callbacks end their chain before the shared middleware below, so
+	// automation clients are identified for them here; otherwise the login
+	// budget would never see a client to exempt.
+	automationClients := middleware.AutomationMiddleware(automation.NewRegistry(cfg))
+	authService.Use("/service/oauth", automationClients)
 	oauthHandler := oauth.NewOAuthHandler(oauthService)
-	oauthHandler.RegisterRoutes(authService)
+	oauthHandler.RegisterRoutes(authService, middleware.RateLimitMiddleware(limits.Limiter(), loginPolicy))
+
+	authService.Get("/api/capabilities", middleware.RateLimitMiddleware(limits.Limiter(), handlers.CapabilitiesRateLimit), handlers.CapabilitiesHandler(cfg, oauthService.Health()))
+	authService.Get("/api/branding", middleware.RateLimitMiddleware(limits.Limiter(), handlers.BrandingRateLimit), handlers.BrandingHandler(auth))
+	authService.Get("/.well-known/jwks.json", handlers.JWKSHandler())
+	if cfg.OIDC.Enabled {
+		authService.Get("/.well-known/openid-configuration", handlers.OpenIDConfigurationHandler(cfg))
+	}
+	adminAccess, err := middleware.AdminAccessMiddleware(cfg.AdminAPI.AllowedNetworks, automation.NewAdminRegistry(cfg))
+	if err != nil {
+		log.Fatalf("❌ Invalid admin API configuration: %v", err)
+	}
+
+	// With a metrics listener of its own, the public app does not expose
+	// them too. Without one they are served to the admin networks and keys
+	// only, since they reveal traffic and error rates.
+	if cfg.Server.MetricsAddress == "" {
+		authService.Get("/metrics", adminAccess, metrics.Handler())
+		authService.Get("/slo", adminAccess, slo.Handler())
+	}
+
+	if cfg.Sandbox.Enabled && cfg.Sandbox.CaptureMail {
+		authService.Get("/sandbox/mail", handlers.SandboxMailHandler)
+		authService.Delete("/sandbox/mail", handlers.SandboxMailResetHandler)
+	}
+
+	if cfg.Sandbox.Enabled && cfg.Sandbox.TestTokens {
+		authService.Post("/sandbox/token", handlers.SandboxTokenHandler(auth, cfg.Sandbox.BootstrapSecret))
+	}
 
 	authService.Get("/health", func(c *fiber.Ctx) error {
 		if err := db.HealthCheck(context.Background()); err != nil {
@@ -199,8 +577,17 @@ func SetupFiberApp(db *database.Database, gqlSrv *handler.Server, auth *service.
 
 	authService.Use(adaptor.HTTPMiddleware(middleware.AuthMiddleware(db.Client, auth)))
 	authService.Use(middleware.FiberWebMiddleware)
+	authService.Use(middleware.SessionTakeoverMiddleware(auth))
+	authService.Use(automationClients)
+	authService.Use(middleware.QuotaMiddleware(limits.Limiter()))
 
-	authService.All("/graphql", handlers.GraphQLHandler(gqlSrv))
+	graphqlLimit := handlers.GraphQLBodyLimit(cfg.Limits.GraphQLBytes)
+	authService.All("/graphql", graphqlLimit, handlers.GraphQLHandler(gqlSrv))
+	authService.Post("/introspect", handlers.IntrospectionHandler(auth))
+	authService.Post("/oauth/introspect", handlers.OAuthIntrospectionHandler(auth, automation.NewIntrospectionRegistry(cfg)))
+	authService.Post("/service-accounts/token", handlers.ServiceAccountTokenHandler(auth))
+
+	authService.All("/admin/graphql", adminAccess, graphqlLimit, handlers.GraphQLHandler(adminSrv))
 
 	if env == "production" {
 		authService.All("/", func(c *fiber.Ctx) error {
@@ -216,6 +603,9 @@ func SetupFiberApp(db *database.Database, gqlSrv *handler.Server, auth *service.
 		authService.Get("/playground", adaptor.HTTPHandlerFunc(
 			playground.ApolloSandboxHandler("Authentication Service Playground", "/graphql"),
 		))
+		authService.Get("/admin/playground", adminAccess, adaptor.HTTPHandlerFunc(
+			playground.ApolloSandboxHandler("Authentication Service Admin Playground", "/admin/graphql"),
+		))
 	}
 
 	return authService
@@ -0,0 +1,103 @@
+// Command mobile-backend is an API serving a mobile app. It validates the
+// auth service's access tokens locally, without cookies or a round trip per
+// request, and checks they were minted for the calling device.
+//
+// Run it with the auth service's JWT_SECRET, then exercise the client side of
+// the lifecycle with -demo:
+//
+//	JWT_SECRET=... go run ./examples/mobile-backend
+//	JWT_SECRET=... go run ./examples/mobile-backend -demo -email a@b.c -password ...
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/abisalde/authentication-service/pkg/session"
+)
+
+func main() {
+	addr := flag.String("addr", ":9090", "listen address of the example API")
+	authURL := flag.String("auth", "http://localhost:8080/graphql", "auth service GraphQL endpoint")
+	demo := flag.Bool("demo", false, "sign in as a mobile client and call the example API")
+	email := flag.String("email", "", "demo account email")
+	password := flag.String("password", "", "demo account password")
+	flag.Parse()
+
+	if *demo {
+		if err := runDemo(*authURL, "http://localhost"+*addr, *email, *password); err != nil {
+			log.Fatalf("demo failed: %v", err)
+		}
+		return
+	}
+
+	http.HandleFunc("/api/me", requireAccessToken(func(w http.ResponseWriter, r *http.Request, userID string) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"userId": userID})
+	}))
+
+	log.Printf("mobile backend listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
+
+// requireAccessToken accepts bearer tokens only. Refresh tokens never reach
+// this API; the app exchanges them with the auth service directly.
+func requireAccessToken(next func(http.ResponseWriter, *http.Request, string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		claims, err := session.ValidateAccessToken(token, r.Header.Get(session.DeviceIDHeader))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		next(w, r, claims.Subject)
+	}
+}
+
+// runDemo walks the client side: sign in once, then let the session client
+// refresh the short-lived access token as needed before each API call.
+func runDemo(authURL, apiURL, email, password string) error {
+	ctx := context.Background()
+
+	// Generate the device ID once per install and keep it with the tokens.
+	deviceID := "example-install-1"
+	client := session.NewClient(authURL, deviceID, session.NewMemoryStore())
+
+	tokens, err := client.Login(ctx, email, password)
+	if err != nil {
+		return err
+	}
+	log.Printf("signed in as %s, access token valid until %s", tokens.UserID, tokens.AccessExpiresAt.Format(time.RFC3339))
+
+	accessToken, err := client.AccessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL+"/api/me", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set(session.DeviceIDHeader, deviceID)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	fmt.Println("GET /api/me:", resp.Status)
+
+	return client.Logout(ctx)
+}
@@ -0,0 +1,182 @@
+// Package alerting routes security relevant events to the channels that page
+// humans, such as Slack and PagerDuty.
+package alerting
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/pkg/workerpool"
+)
+
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+const (
+	defaultCooldown = 10 * time.Minute
+	notifyTimeout   = 10 * time.Second
+)
+
+var severityRank = map[Severity]int{
+	SeverityInfo:     1,
+	SeverityWarning:  2,
+	SeverityCritical: 3,
+}
+
+func ParseSeverity(s string) Severity {
+	severity := Severity(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := severityRank[severity]; !ok {
+		return SeverityWarning
+	}
+	return severity
+}
+
+// AtLeast reports whether s is as severe as min.
+func (s Severity) AtLeast(min Severity) bool {
+	return severityRank[s] >= severityRank[min]
+}
+
+// Alert describes one incident. Alerts sharing a DedupKey are the same
+// incident: repeats are suppressed during the cooldown and a Resolved alert
+// closes it.
+type Alert struct {
+	Source      string
+	Title       string
+	Summary     string
+	Severity    Severity
+	DedupKey    string
+	Details     map[string]string
+	Resolved    bool
+	Environment string
+	At          time.Time
+}
+
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// Route sends alerts at or above MinSeverity to the notifier.
+type Route struct {
+	Notifier    Notifier
+	MinSeverity Severity
+}
+
+// Dispatcher fans alerts out to the configured notifiers on a bounded pool,
+// so a slow webhook never holds up the request that raised the alert. A nil
+// Dispatcher drops every alert.
+type Dispatcher struct {
+	routes      []Route
+	cooldown    time.Duration
+	environment string
+	pool        *workerpool.Pool
+
+	mu     sync.Mutex
+	active map[string]time.Time
+}
+
+// New builds the dispatcher from config. It returns nil when alerting is
+// disabled or no channel has a target.
+func New(cfg *configs.Config) *Dispatcher {
+	if cfg == nil || !cfg.Alerting.Enabled {
+		return nil
+	}
+
+	var routes []Route
+	if target := cfg.Alerting.Slack.Target; target != "" {
+		routes = append(routes, Route{NewSlackNotifier(target), ParseSeverity(cfg.Alerting.Slack.MinSeverity)})
+	}
+	if target := cfg.Alerting.PagerDuty.Target; target != "" {
+		routes = append(routes, Route{NewPagerDutyNotifier(target), ParseSeverity(cfg.Alerting.PagerDuty.MinSeverity)})
+	}
+
+	if len(routes) == 0 {
+		slog.Warn("Alerting is enabled but no channel is configured")
+		return nil
+	}
+
+	return NewDispatcher(cfg.Alerting.Cooldown, cfg.Env.CurrentEnv, routes...)
+}
+
+func NewDispatcher(cooldown time.Duration, environment string, routes ...Route) *Dispatcher {
+	if cooldown <= 0 {
+		cooldown = defaultCooldown
+	}
+
+	return &Dispatcher{
+		routes:      routes,
+		cooldown:    cooldown,
+		environment: environment,
+		pool:        workerpool.New("alerts", 2, 64, workerpool.PolicyDrop),
+		active:      make(map[string]time.Time),
+	}
+}
+
+// Fire queues the alert for every channel that accepts its severity.
+func (d *Dispatcher) Fire(alert Alert) {
+	if d == nil {
+		return
+	}
+
+	if alert.At.IsZero() {
+		alert.At = time.Now()
+	}
+	if alert.Environment == "" {
+		alert.Environment = d.environment
+	}
+	if alert.DedupKey == "" {
+		alert.DedupKey = alert.Source + ":" + alert.Title
+	}
+
+	if !d.admit(alert) {
+		return
+	}
+
+	for _, r := range d.routes {
+		if !alert.Severity.AtLeast(r.MinSeverity) {
+			continue
+		}
+
+		notifier := r.Notifier
+		err := d.pool.Submit(context.Background(), func() {
+			ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+			defer cancel()
+
+			if err := notifier.Notify(ctx, alert); err != nil {
+				slog.Error("Alert failed", "alert", alert.DedupKey, "channel", notifier.Name(), "error", err)
+			}
+		})
+		if err != nil {
+			slog.Warn("Alert dropped", "alert", alert.DedupKey, "channel", notifier.Name(), "error", err)
+		}
+	}
+}
+
+// admit suppresses repeats of an open incident within the cooldown and only
+// lets a resolution through for incidents that were raised.
+func (d *Dispatcher) admit(alert Alert) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	last, open := d.active[alert.DedupKey]
+	if alert.Resolved {
+		delete(d.active, alert.DedupKey)
+		return open
+	}
+
+	if open && alert.At.Sub(last) < d.cooldown {
+		return false
+	}
+
+	d.active[alert.DedupKey] = alert.At
+	return true
+}
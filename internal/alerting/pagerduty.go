@@ -0,0 +1,96 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDutyNotifier triggers and resolves incidents through the Events API
+// v2. The alert's DedupKey is the incident key, so a resolved alert closes
+// the incident its trigger opened.
+type PagerDutyNotifier struct {
+	routingKey string
+	endpoint   string
+	client     *http.Client
+}
+
+func NewPagerDutyNotifier(routingKey string) *PagerDutyNotifier {
+	return &PagerDutyNotifier{
+		routingKey: routingKey,
+		endpoint:   pagerDutyEventsURL,
+		client:     &http.Client{Timeout: notifyTimeout},
+	}
+}
+
+func (p *PagerDutyNotifier) Name() string {
+	return "pagerduty"
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	Timestamp     string            `json:"timestamp"`
+	Component     string            `json:"component,omitempty"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+func (p *PagerDutyNotifier) Notify(ctx context.Context, alert Alert) error {
+	event := pagerDutyEvent{
+		RoutingKey:  p.routingKey,
+		EventAction: "trigger",
+		DedupKey:    alert.DedupKey,
+	}
+
+	if alert.Resolved {
+		event.EventAction = "resolve"
+	} else {
+		summary := alert.Title
+		if alert.Summary != "" {
+			summary = alert.Title + ": " + alert.Summary
+		}
+		event.Payload = &pagerDutyPayload{
+			Summary:       summary,
+			Source:        "authentication-service/" + alert.Environment,
+			Severity:      string(alert.Severity),
+			Timestamp:     alert.At.UTC().Format(time.RFC3339),
+			Component:     alert.Source,
+			CustomDetails: alert.Details,
+		}
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("pagerduty returned %s", resp.Status)
+	}
+	return nil
+}
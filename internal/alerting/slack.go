@@ -0,0 +1,87 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SlackNotifier posts alerts to an incoming webhook.
+type SlackNotifier struct {
+	webhookURL string
+	client     *http.Client
+}
+
+func NewSlackNotifier(webhookURL string) *SlackNotifier {
+	return &SlackNotifier{
+		webhookURL: webhookURL,
+		client:     &http.Client{Timeout: notifyTimeout},
+	}
+}
+
+func (s *SlackNotifier) Name() string {
+	return "slack"
+}
+
+func (s *SlackNotifier) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(map[string]string{"text": slackText(alert)})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("slack webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+func slackText(alert Alert) string {
+	icon := map[Severity]string{
+		SeverityInfo:     ":information_source:",
+		SeverityWarning:  ":warning:",
+		SeverityCritical: ":rotating_light:",
+	}[alert.Severity]
+
+	status := strings.ToUpper(string(alert.Severity))
+	if alert.Resolved {
+		icon, status = ":white_check_mark:", "RESOLVED"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s *[%s] %s*", icon, status, alert.Title)
+	if alert.Environment != "" {
+		fmt.Fprintf(&b, " (%s)", alert.Environment)
+	}
+	if alert.Summary != "" {
+		fmt.Fprintf(&b, "\n%s", alert.Summary)
+	}
+
+	keys := make([]string, 0, len(alert.Details))
+	for k := range alert.Details {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "\n• %s: `%s`", k, alert.Details[k])
+	}
+
+	fmt.Fprintf(&b, "\n_%s · %s_", alert.Source, alert.At.UTC().Format(time.RFC3339))
+	return b.String()
+}
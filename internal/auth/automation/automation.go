@@ -0,0 +1,122 @@
+package automation
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"strings"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+)
+
+const (
+	DefaultHeader      = "X-Automation-Key"
+	DefaultAdminHeader = "X-Admin-Key"
+)
+
+// Registry identifies trusted internal automation (synthetic monitoring, CI
+// smoke tests) either by a shared API key or by a verified mTLS identity.
+// Identified clients are exempt from login throttling but are still audited.
+type Registry struct {
+	header     string
+	keys       map[[sha256.Size]byte]string
+	identities map[string]bool
+}
+
+func NewRegistry(cfg *configs.Config) *Registry {
+	if cfg == nil {
+		return newRegistry(DefaultHeader, nil, nil)
+	}
+
+	header := DefaultHeader
+	if cfg.Automation.Header != "" {
+		header = cfg.Automation.Header
+	}
+	return newRegistry(header, cfg.Automation.APIKeys, cfg.Automation.TrustedIdentities)
+}
+
+// NewAdminRegistry identifies callers allowed to reach the admin endpoint.
+// Its keys and identities are configured apart from automation ones, so an
+// automation key never opens the admin schema.
+func NewAdminRegistry(cfg *configs.Config) *Registry {
+	if cfg == nil {
+		return newRegistry(DefaultAdminHeader, nil, nil)
+	}
+
+	header := DefaultAdminHeader
+	if cfg.AdminAPI.Header != "" {
+		header = cfg.AdminAPI.Header
+	}
+	return newRegistry(header, cfg.AdminAPI.APIKeys, cfg.AdminAPI.TrustedIdentities)
+}
+
+// NewIntrospectionRegistry identifies resource servers by the client
+// credentials they present at /oauth/introspect.
+func NewIntrospectionRegistry(cfg *configs.Config) *Registry {
+	if cfg == nil {
+		return newRegistry("", nil, nil)
+	}
+	return newRegistry("", cfg.Introspection.Clients, nil)
+}
+
+func newRegistry(header string, apiKeys map[string]string, identities []string) *Registry {
+	r := &Registry{
+		header:     header,
+		keys:       make(map[[sha256.Size]byte]string),
+		identities: make(map[string]bool),
+	}
+
+	for name, key := range apiKeys {
+		if name == "" || key == "" {
+			continue
+		}
+		r.keys[sha256.Sum256([]byte(key))] = name
+	}
+
+	for _, identity := range identities {
+		if identity = strings.TrimSpace(identity); identity != "" {
+			r.identities[identity] = true
+		}
+	}
+
+	return r
+}
+
+func (r *Registry) Header() string {
+	return r.header
+}
+
+func (r *Registry) Enabled() bool {
+	return r != nil && (len(r.keys) > 0 || len(r.identities) > 0)
+}
+
+// IdentifyKey returns the client name registered for the API key. Keys are
+// compared by digest so lookups don't leak timing on the raw secret.
+func (r *Registry) IdentifyKey(key string) (string, bool) {
+	if r == nil || key == "" {
+		return "", false
+	}
+	name, ok := r.keys[sha256.Sum256([]byte(key))]
+	return name, ok
+}
+
+// IdentifyClient returns clientID when clientSecret is the secret
+// registered for it.
+func (r *Registry) IdentifyClient(clientID, clientSecret string) (string, bool) {
+	name, ok := r.IdentifyKey(clientSecret)
+	if !ok || subtle.ConstantTimeCompare([]byte(name), []byte(clientID)) != 1 {
+		return "", false
+	}
+	return name, true
+}
+
+// IdentifyCertificate returns the identity when the verified client
+// certificate subject is on the trusted list.
+func (r *Registry) IdentifyCertificate(commonName string) (string, bool) {
+	if r == nil || commonName == "" {
+		return "", false
+	}
+	if r.identities[commonName] {
+		return "mtls:" + commonName, true
+	}
+	return "", false
+}
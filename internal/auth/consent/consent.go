@@ -0,0 +1,119 @@
+package consent
+
+import (
+	"strings"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+)
+
+// Requirements is what registration needs from a user signing up from one
+// country.
+type Requirements struct {
+	Country               string
+	MarketingOptInDefault bool
+	MinimumAge            int
+	TermsVersion          string
+}
+
+// Consents is what the user supplied at registration.
+type Consents struct {
+	DateOfBirth    *time.Time
+	AcceptTerms    bool
+	TermsVersion   string
+	MarketingOptIn *bool
+}
+
+// Violation names the register field that failed a country requirement.
+type Violation struct {
+	Field   string
+	Message string
+}
+
+func (v *Violation) Error() string { return v.Message }
+
+// Policy resolves the consent requirements of a signup country.
+type Policy struct {
+	CountryHeader string
+	fallback      Requirements
+	countries     map[string]Requirements
+}
+
+func NewPolicy(cfg *configs.Config) Policy {
+	policy := Policy{countries: make(map[string]Requirements)}
+	if cfg == nil {
+		return policy
+	}
+
+	policy.CountryHeader = cfg.Consent.CountryHeader
+	policy.fallback = fromConfig("", cfg.Consent.Default)
+	for code, reqs := range cfg.Consent.Countries {
+		code = NormalizeCountry(code)
+		policy.countries[code] = fromConfig(code, reqs)
+	}
+
+	return policy
+}
+
+func fromConfig(country string, reqs configs.ConsentRequirements) Requirements {
+	return Requirements{
+		Country:               country,
+		MarketingOptInDefault: reqs.MarketingOptInDefault,
+		MinimumAge:            reqs.MinimumAge,
+		TermsVersion:          strings.TrimSpace(reqs.TermsVersion),
+	}
+}
+
+// NormalizeCountry upper-cases an ISO 3166-1 alpha-2 code.
+func NormalizeCountry(country string) string {
+	return strings.ToUpper(strings.TrimSpace(country))
+}
+
+// For returns the requirements of country, or the default ones when the
+// country is unknown or has no entry.
+func (p Policy) For(country string) Requirements {
+	if reqs, ok := p.countries[NormalizeCountry(country)]; ok {
+		return reqs
+	}
+	return p.fallback
+}
+
+// Check reports the first requirement the consents do not meet.
+func (r Requirements) Check(c Consents, now time.Time) error {
+	if r.MinimumAge > 0 {
+		if c.DateOfBirth == nil {
+			return &Violation{Field: "dateOfBirth", Message: "Date of birth is required"}
+		}
+		if Age(*c.DateOfBirth, now) < r.MinimumAge {
+			return &Violation{Field: "dateOfBirth", Message: "You are not old enough to register"}
+		}
+	}
+
+	if r.TermsVersion != "" {
+		if !c.AcceptTerms {
+			return &Violation{Field: "acceptTerms", Message: "Terms of service must be accepted"}
+		}
+		if c.TermsVersion != r.TermsVersion {
+			return &Violation{Field: "termsVersion", Message: "Accept the terms of service for your country"}
+		}
+	}
+
+	return nil
+}
+
+// MarketingOptIn applies the country default when the user made no choice.
+func (r Requirements) MarketingOptIn(c Consents) bool {
+	if c.MarketingOptIn != nil {
+		return *c.MarketingOptIn
+	}
+	return r.MarketingOptInDefault
+}
+
+// Age returns the completed years between birth and now.
+func Age(birth, now time.Time) int {
+	years := now.Year() - birth.Year()
+	if now.Month() < birth.Month() || (now.Month() == birth.Month() && now.Day() < birth.Day()) {
+		years--
+	}
+	return years
+}
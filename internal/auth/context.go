@@ -2,7 +2,7 @@ package auth
 
 import (
 	"context"
-	"log"
+	"log/slog"
 
 	"github.com/abisalde/authentication-service/internal/database/ent"
 	"github.com/gofiber/fiber/v2"
@@ -11,15 +11,19 @@ import (
 type contextKey string
 
 var (
-	CurrentUserKey        = contextKey("currentUser")
-	ClientIPKey           = contextKey("clientIP")
-	FiberContextWeb       = contextKey("fiberContextWebApplications")
-	HTTPResponseWriterKey = contextKey("httpResponseWriterForRequest")
-	JWTTokenKey           = contextKey("JWTTokenKey")
-	OAuthStateKey         = contextKey("serviceOAuthState")
-	OAuthPlatformKey      = contextKey("serviceOAuthPlatform")
-	OAuthModeKey          = contextKey("serviceOAuthPasswordLessMode")
-	OAuthUUIDKey          = contextKey("serviceOAuthUUID")
+	CurrentUserKey         = contextKey("currentUser")
+	ClientIPKey            = contextKey("clientIP")
+	FiberContextWeb        = contextKey("fiberContextWebApplications")
+	HTTPResponseWriterKey  = contextKey("httpResponseWriterForRequest")
+	JWTTokenKey            = contextKey("JWTTokenKey")
+	OAuthStateKey          = contextKey("serviceOAuthState")
+	OAuthPlatformKey       = contextKey("serviceOAuthPlatform")
+	OAuthModeKey           = contextKey("serviceOAuthPasswordLessMode")
+	OAuthUUIDKey           = contextKey("serviceOAuthUUID")
+	AutomationClientKey    = contextKey("trustedAutomationClient")
+	IntrospectionClientKey = contextKey("introspectionClient")
+	CookieSessionKey       = contextKey("browserCookieSession")
+	CSRFTokenKey           = contextKey("csrfTokenHeader")
 )
 
 func GetCurrentUser(ctx context.Context) *ent.User {
@@ -37,6 +41,51 @@ func GetIPFromContext(ctx context.Context) string {
 	return ""
 }
 
+// GetAutomationClient returns the trusted automation client identified for the
+// request, or an empty string for regular traffic.
+func GetAutomationClient(ctx context.Context) string {
+	if client, ok := ctx.Value(AutomationClientKey).(string); ok {
+		return client
+	}
+	return ""
+}
+
+// GetIntrospectionClient returns the resource server that authenticated with
+// client credentials at /oauth/introspect.
+func GetIntrospectionClient(ctx context.Context) string {
+	if client, ok := ctx.Value(IntrospectionClientKey).(string); ok {
+		return client
+	}
+	return ""
+}
+
+// GetCookieSession returns the browser session binding when the request was
+// authenticated with cookies rather than an Authorization header.
+func GetCookieSession(ctx context.Context) (string, bool) {
+	session, ok := ctx.Value(CookieSessionKey).(string)
+	return session, ok
+}
+
+func GetCSRFToken(ctx context.Context) string {
+	if token, ok := ctx.Value(CSRFTokenKey).(string); ok {
+		return token
+	}
+	return ""
+}
+
+// RequestIDHeader carries the ID that ties a request's logs and metrics
+// exemplars together.
+const RequestIDHeader = "X-Request-ID"
+
+// GetRequestID returns the ID of the request behind ctx, empty outside a
+// Fiber request.
+func GetRequestID(ctx context.Context) string {
+	if fiberCtx, ok := GetFiberWebContext(ctx); ok {
+		return fiberCtx.Get(RequestIDHeader)
+	}
+	return ""
+}
+
 func GetFiberWebContext(ctx context.Context) (*fiber.Ctx, bool) {
 	if fiberCtx, ok := ctx.Value(FiberContextWeb).(*fiber.Ctx); ok {
 		return fiberCtx, true
@@ -44,23 +93,15 @@ func GetFiberWebContext(ctx context.Context) (*fiber.Ctx, bool) {
 	return nil, false
 }
 
+// DebugContext logs what the request context carries, at debug level.
 func DebugContext(ctx context.Context) {
-	log.Println("=== Context Debug START ===")
-	if ip := GetIPFromContext(ctx); ip != "" {
-		log.Printf("Auth Debug: Client IP in context: %s", ip)
+	if !slog.Default().Enabled(ctx, slog.LevelDebug) {
+		return
 	}
 
+	attrs := []any{"ip", GetIPFromContext(ctx), "fiber_context", ctx.Value(FiberContextWeb) != nil}
 	if user := GetCurrentUser(ctx); user != nil {
-		log.Printf("Auth Debug: User in context: ID=%d, Email=%s, Role=%s", user.ID, user.Email, user.Role)
-	} else {
-		log.Println("Auth Debug: No user in context.")
+		attrs = append(attrs, "user_id", user.ID, "email", user.Email, "role", user.Role)
 	}
-
-	if v := ctx.Value(FiberContextWeb); v != nil {
-		log.Printf("FiberWebKey: %v", v)
-	} else {
-		log.Println("FiberWebKey: nil")
-	}
-
-	log.Println("=========== Context Debug END =============")
+	slog.DebugContext(ctx, "Request context", attrs...)
 }
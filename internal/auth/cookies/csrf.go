@@ -0,0 +1,26 @@
+package cookies
+
+import (
+	"context"
+
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/pkg/verification"
+)
+
+// CSRFHeader carries the token returned by the csrfToken query on
+// cookie-authenticated mutations.
+const CSRFHeader = "X-CSRF-Token"
+
+// CSRFTokenFor issues the CSRF token for the user's current browser session.
+// Requests authenticated with a bearer header are bound to an empty session.
+func CSRFTokenFor(ctx context.Context, u *ent.User) (string, error) {
+	binding, _ := auth.GetCookieSession(ctx)
+	return verification.SignCSRF(u.PublicID.String(), binding, string(u.Role))
+}
+
+// VerifyCSRF checks the request's CSRF header against the user's session.
+func VerifyCSRF(ctx context.Context, u *ent.User) error {
+	binding, _ := auth.GetCookieSession(ctx)
+	return verification.VerifyCSRF(auth.GetCSRFToken(ctx), u.PublicID.String(), binding, string(u.Role))
+}
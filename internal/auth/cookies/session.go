@@ -1,6 +1,7 @@
 package cookies
 
 import (
+	"errors"
 	"os"
 	"time"
 
@@ -12,9 +13,29 @@ var (
 	BrowserAccessTokenName  = "authentication_service_access_token"
 )
 
+var ErrInsecureTransport = errors.New("refusing to issue cookies over an insecure connection")
+
+var requireSecureCookies bool
+
+// RequireSecureCookies marks every auth cookie Secure and refuses to issue
+// them on requests that did not arrive over TLS.
+func RequireSecureCookies(require bool) {
+	requireSecureCookies = require
+}
+
+// SecureCookies reports whether auth cookies should carry the Secure flag.
+func SecureCookies() bool {
+	return requireSecureCookies || os.Getenv("APP_ENV") == "production"
+}
+
 func CreateBrowserSession(generatedTokens TokenPair, ctx *fiber.Ctx) error {
 
 	isProd := os.Getenv("APP_ENV") == "production"
+	secure := SecureCookies()
+
+	if requireSecureCookies && !ctx.Secure() {
+		return ErrInsecureTransport
+	}
 
 	site := fiber.CookieSameSiteLaxMode
 
@@ -26,7 +47,7 @@ func CreateBrowserSession(generatedTokens TokenPair, ctx *fiber.Ctx) error {
 	accessTokenExpiration := time.Now().Add(LoginAccessTokenExpiry)
 
 	ctx.Cookie(&fiber.Cookie{
-		Secure:   isProd,
+		Secure:   secure,
 		Expires:  refreshTokenExpiration,
 		Name:     BrowserSessionTokenName,
 		Value:    generatedTokens.RefreshToken,
@@ -36,7 +57,7 @@ func CreateBrowserSession(generatedTokens TokenPair, ctx *fiber.Ctx) error {
 	})
 
 	ctx.Cookie(&fiber.Cookie{
-		Secure:   isProd,
+		Secure:   secure,
 		Expires:  accessTokenExpiration,
 		Name:     BrowserAccessTokenName,
 		Value:    generatedTokens.AccessToken,
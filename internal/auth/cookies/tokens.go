@@ -1,9 +1,12 @@
 package cookies
 
 import (
+	"context"
 	"time"
 
+	"github.com/abisalde/authentication-service/internal/auth"
 	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/gofiber/fiber/v2"
 )
 
 const (
@@ -15,10 +18,14 @@ const (
 type TokenPair struct {
 	AccessToken  string
 	RefreshToken string
+	// IDToken is set when the service acts as an OpenID Connect provider.
+	IDToken string
 }
 
-func GenerateAccessToken(userID int64) (string, error) {
-	accessToken, err := jwt.GenerateToken(userID, jwt.TokenTypeAccess, AccessTokenExpiry)
+// GenerateAccessToken issues the access token of a refresh. scopes come
+// from the scope policy, evaluated again for every token.
+func GenerateAccessToken(ctx context.Context, subject string, device *jwt.DeviceClaim, scopes []string) (string, error) {
+	accessToken, err := jwt.GenerateTokenWithExtras(ctx, subject, jwt.TokenTypeAccess, AccessTokenExpiry, jwt.Extras{Device: device, Scopes: scopes})
 	if err != nil {
 		return "", err
 	}
@@ -26,14 +33,14 @@ func GenerateAccessToken(userID int64) (string, error) {
 	return accessToken, nil
 }
 
-func GenerateLoginTokenPair(userID int64) (*TokenPair, error) {
-	accessToken, err := jwt.GenerateToken(userID, jwt.TokenTypeAccess, LoginAccessTokenExpiry)
+func GenerateLoginTokenPair(ctx context.Context, subject string, device *jwt.DeviceClaim, scopes []string) (*TokenPair, error) {
+	accessToken, err := jwt.GenerateTokenWithExtras(ctx, subject, jwt.TokenTypeAccess, LoginAccessTokenExpiry, jwt.Extras{Device: device, Scopes: scopes})
 
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := jwt.GenerateToken(userID, jwt.TokenTypeRefresh, RefreshTokenExpiry)
+	refreshToken, err := jwt.GenerateToken(subject, jwt.TokenTypeRefresh, RefreshTokenExpiry)
 
 	if err != nil {
 		return nil, err
@@ -41,3 +48,20 @@ func GenerateLoginTokenPair(userID int64) (*TokenPair, error) {
 
 	return &TokenPair{AccessToken: accessToken, RefreshToken: refreshToken}, nil
 }
+
+const DeviceIDHeader = "X-Device-ID"
+
+func DeviceFromFiber(c *fiber.Ctx) *jwt.DeviceClaim {
+	if c == nil {
+		return nil
+	}
+	return jwt.NewDeviceClaim(c.Get(DeviceIDHeader), c.Get(fiber.HeaderUserAgent))
+}
+
+func DeviceFromContext(ctx context.Context) *jwt.DeviceClaim {
+	fiberCtx, ok := auth.GetFiberWebContext(ctx)
+	if !ok {
+		return nil
+	}
+	return DeviceFromFiber(fiberCtx)
+}
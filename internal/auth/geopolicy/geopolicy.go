@@ -0,0 +1,95 @@
+// Package geopolicy decides, from the country a sign-in comes from, whether
+// it goes through, needs a CAPTCHA or is refused. Organizations can replace
+// the deployment's rules for their members.
+package geopolicy
+
+import (
+	"strings"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+)
+
+// Decision is what the policy makes of a sign-in's country.
+type Decision string
+
+const (
+	DecisionAllow  Decision = "allow"
+	DecisionStepUp Decision = "step_up"
+	DecisionBlock  Decision = "block"
+)
+
+// rules holds the countries of a GeoRules entry with regions expanded.
+type rules struct {
+	block  map[string]bool
+	stepUp map[string]bool
+}
+
+// Policy maps countries to decisions.
+type Policy struct {
+	CountryHeader string
+	rules         rules
+	organizations map[string]rules
+}
+
+func NewPolicy(cfg *configs.Config) Policy {
+	policy := Policy{organizations: make(map[string]rules)}
+	if cfg == nil {
+		return policy
+	}
+
+	geo := cfg.GeoPolicy
+	policy.CountryHeader = geo.CountryHeader
+	policy.rules = expand(geo.GeoRules, geo.Regions)
+	for slug, r := range geo.Organizations {
+		policy.organizations[slug] = expand(r, geo.Regions)
+	}
+	return policy
+}
+
+func expand(r configs.GeoRules, regions map[string][]string) rules {
+	countries := func(entries []string) map[string]bool {
+		set := make(map[string]bool)
+		for _, entry := range entries {
+			if region, ok := regions[entry]; ok {
+				for _, country := range region {
+					set[normalize(country)] = true
+				}
+				continue
+			}
+			set[normalize(entry)] = true
+		}
+		return set
+	}
+	return rules{block: countries(r.Block), stepUp: countries(r.StepUp)}
+}
+
+// HasOverrides reports whether any organization replaces the rules, so
+// callers can skip looking the organization up otherwise.
+func (p Policy) HasOverrides() bool {
+	return len(p.organizations) > 0
+}
+
+// Decide returns the decision for a sign-in from country by a member of the
+// organization with the given slug, empty for none. An unknown country is
+// allowed.
+func (p Policy) Decide(country, organization string) Decision {
+	r, ok := p.organizations[organization]
+	if !ok || organization == "" {
+		r = p.rules
+	}
+
+	country = normalize(country)
+	switch {
+	case country == "":
+		return DecisionAllow
+	case r.block[country]:
+		return DecisionBlock
+	case r.stepUp[country]:
+		return DecisionStepUp
+	}
+	return DecisionAllow
+}
+
+func normalize(country string) string {
+	return strings.ToUpper(strings.TrimSpace(country))
+}
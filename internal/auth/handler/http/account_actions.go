@@ -0,0 +1,78 @@
+package http
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/database/ent/scheduledaction"
+	"github.com/abisalde/authentication-service/internal/graph/converters"
+	"github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+func (h *UsersHandler) ScheduleAccountAction(ctx context.Context, input model.ScheduleAccountActionInput) (*model.ScheduledAccountAction, error) {
+	var note string
+	if input.Note != nil {
+		note = strings.TrimSpace(*input.Note)
+	}
+
+	action, err := h.authService.ScheduleAccountAction(ctx, auth.GetCurrentUser(ctx), input.UserID,
+		scheduledaction.Action(input.Action), input.RunAt, note)
+	if err != nil {
+		return nil, accountActionError(err, "schedule account action")
+	}
+	return converters.ScheduledActionToGraph(action), nil
+}
+
+func (h *UsersHandler) CancelScheduledAccountAction(ctx context.Context, id string) (*model.ScheduledAccountAction, error) {
+	actionID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, accountActionError(service.ErrScheduledActionNotFound, "cancel account action")
+	}
+
+	action, err := h.authService.CancelScheduledAccountAction(ctx, auth.GetCurrentUser(ctx), actionID)
+	if err != nil {
+		return nil, accountActionError(err, "cancel account action")
+	}
+	return converters.ScheduledActionToGraph(action), nil
+}
+
+func (h *UsersHandler) ListScheduledAccountActions(ctx context.Context, userID string) ([]*model.ScheduledAccountAction, error) {
+	actions, err := h.authService.ListScheduledAccountActions(ctx, userID)
+	if err != nil {
+		return nil, accountActionError(err, "list account actions")
+	}
+
+	result := make([]*model.ScheduledAccountAction, 0, len(actions))
+	for _, action := range actions {
+		result = append(result, converters.ScheduledActionToGraph(action))
+	}
+	return result, nil
+}
+
+func accountActionError(err error, action string) error {
+	switch {
+	case err == service.ErrScheduledActionNotFound:
+		return errors.NewTypedError("Scheduled account action not found", model.ErrorTypeNotFound, nil)
+	case err == service.ErrScheduledActionNotPending:
+		return errors.NewTypedError("The action already ran or was cancelled", model.ErrorTypeConflict, nil)
+	case err == service.ErrRunAtNotInFuture:
+		return errors.NewTypedError("runAt must be in the future", model.ErrorTypeInvalidInput, nil)
+	case err == service.ErrAlreadyDeleted:
+		return errors.NewTypedError("User is already deleted", model.ErrorTypeConflict, nil)
+	case err == service.ErrManageOwnAccount:
+		return errors.NewTypedError("You cannot schedule actions on your own account", model.ErrorTypeForbidden, nil)
+	case err == errors.UserNotFound || ent.IsNotFound(err):
+		return errors.UserNotFound
+	case ent.IsValidationError(err):
+		return errors.NewTypedError(err.Error(), model.ErrorTypeInvalidInput, nil)
+	}
+
+	slog.Error("Failed to "+action, "error", err)
+	return errors.ErrSomethingWentWrong
+}
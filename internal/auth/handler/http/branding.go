@@ -0,0 +1,67 @@
+package http
+
+import (
+	"context"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/graph/converters"
+	"github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+func (h *UsersHandler) SetOrganizationBranding(ctx context.Context, input model.OrganizationBrandingInput) (*model.Organization, error) {
+	org, err := h.authService.SaveOrganizationBranding(ctx, repository.OrganizationBranding{
+		Slug:         input.Slug,
+		Name:         input.Name,
+		ProductName:  valueOrEmpty(input.ProductName),
+		LogoURL:      valueOrEmpty(input.LogoURL),
+		PrimaryColor: valueOrEmpty(input.PrimaryColor),
+		SupportEmail: valueOrEmpty(input.SupportEmail),
+	})
+	if ent.IsValidationError(err) {
+		return nil, errors.NewTypedError(err.Error(), model.ErrorTypeInvalidInput, nil)
+	}
+	if err != nil {
+		h.logger.Error("Failed to save the organization branding", "organization", input.Slug, "error", err)
+		return nil, errors.ErrSomethingWentWrong
+	}
+	return converters.OrganizationToGraph(org), nil
+}
+
+func (h *UsersHandler) GetOrganization(ctx context.Context, slug string) (*model.Organization, error) {
+	org, err := h.authService.GetOrganization(ctx, slug)
+	if err == service.ErrOrganizationNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		h.logger.Error("Failed to load the organization", "organization", slug, "error", err)
+		return nil, errors.ErrSomethingWentWrong
+	}
+	return converters.OrganizationToGraph(org), nil
+}
+
+func (h *UsersHandler) SetUserOrganization(ctx context.Context, userID string, organization *string) (*model.User, error) {
+	updated, err := h.authService.AssignOrganization(ctx, userID, organization)
+	switch {
+	case err == nil:
+		return converters.UserToGraph(updated), nil
+	case err == service.ErrOrganizationNotFound:
+		return nil, errors.NewTypedError("Organization not found", model.ErrorTypeNotFound, nil)
+	case err == errors.UserNotFound || ent.IsNotFound(err):
+		return nil, errors.UserNotFound
+	case err == service.ErrServiceAccount:
+		return nil, errors.NewTypedError("Service accounts cannot change organization", model.ErrorTypeForbidden, nil)
+	}
+
+	h.logger.Error("Failed to set the organization of a user", "user", userID, "error", err)
+	return nil, errors.ErrSomethingWentWrong
+}
+
+func valueOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
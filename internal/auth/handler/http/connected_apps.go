@@ -0,0 +1,64 @@
+package http
+
+import (
+	"context"
+
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+func (h *ProfileHandler) GetConnectedApps(ctx context.Context) ([]*model.ConnectedApp, error) {
+	currentUser := auth.GetCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, errors.AuthenticationRequired
+	}
+
+	grants, err := h.authService.ConnectedApps(ctx, currentUser.ID)
+	if err != nil {
+		h.logger.Error("Failed to list the connected apps", "user_id", currentUser.ID, "error", err)
+		return nil, errors.ErrSomethingWentWrong
+	}
+	return connectedAppsToGraph(grants), nil
+}
+
+// RevokeConnectedApp is refused to connected apps themselves, so one app
+// acting for the user cannot cut off the others.
+func (h *ProfileHandler) RevokeConnectedApp(ctx context.Context, client string) ([]*model.ConnectedApp, error) {
+	currentUser := auth.GetCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, errors.AuthenticationRequired
+	}
+	if auth.GetAutomationClient(ctx) != "" {
+		return nil, errors.NewTypedError("Connected apps can only be revoked by the user", model.ErrorTypeForbidden, nil)
+	}
+
+	switch err := h.authService.RevokeConnectedApp(ctx, currentUser.ID, client); err {
+	case nil:
+	case service.ErrConnectedAppNotFound:
+		return nil, errors.NewTypedError("Connected app not found", model.ErrorTypeNotFound, nil)
+	default:
+		h.logger.Error("Failed to revoke a connected app", "user_id", currentUser.ID, "client", client, "error", err)
+		return nil, errors.ErrSomethingWentWrong
+	}
+
+	return h.GetConnectedApps(ctx)
+}
+
+func connectedAppsToGraph(grants []service.AppGrant) []*model.ConnectedApp {
+	apps := make([]*model.ConnectedApp, 0, len(grants))
+	for _, grant := range grants {
+		scopes := grant.Scopes
+		if scopes == nil {
+			scopes = []string{}
+		}
+		apps = append(apps, &model.ConnectedApp{
+			Client:     grant.Client,
+			Scopes:     scopes,
+			GrantedAt:  grant.GrantedAt,
+			LastUsedAt: grant.LastUsedAt,
+		})
+	}
+	return apps
+}
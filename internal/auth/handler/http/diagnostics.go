@@ -0,0 +1,113 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+const maxKeyspaceSampleSize = 1000
+
+type DiagnosticsHandler struct {
+	authService *service.AuthService
+	logger      *slog.Logger
+}
+
+func NewDiagnosticsHandler(authService *service.AuthService) *DiagnosticsHandler {
+	return &DiagnosticsHandler{authService: authService, logger: authService.Logger()}
+}
+
+func (h *DiagnosticsHandler) GetRedisKeyspaceUsage(ctx context.Context, sampleSize *int) ([]*model.KeyspaceUsage, error) {
+	// Omitted, the sample size is left to the default.
+	size := 0
+	if sampleSize != nil {
+		size = *sampleSize
+		if size < 1 || size > maxKeyspaceSampleSize {
+			return nil, errors.NewTypedError("Sample size must be between 1 and 1000", model.ErrorTypeBadRequest, map[string]interface{}{
+				"field": "sampleSize",
+			})
+		}
+	}
+
+	usages, err := h.authService.RedisKeyspaceUsage(ctx, size)
+	if err != nil {
+		h.logger.Error("Failed to sample Redis keyspaces", "error", err)
+		return nil, errors.ErrSomethingWentWrong
+	}
+
+	result := make([]*model.KeyspaceUsage, 0, len(usages))
+	for _, u := range usages {
+		result = append(result, &model.KeyspaceUsage{
+			Category:       u.Category,
+			Pattern:        u.Pattern,
+			Keys:           int(u.Keys),
+			SampledKeys:    int(u.SampledKeys),
+			SampledBytes:   int(u.SampledBytes),
+			EstimatedBytes: int(u.EstimatedBytes),
+		})
+	}
+
+	return result, nil
+}
+
+func (h *DiagnosticsHandler) GetTokenStats(ctx context.Context, hours *int32) (*model.TokenStats, error) {
+	n := 24
+	if hours != nil {
+		n = int(*hours)
+	}
+
+	maxHours := int(service.TokenStatsRetention / time.Hour)
+	if n < 1 || n > maxHours {
+		return nil, errors.NewTypedError(fmt.Sprintf("Hours must be between 1 and %d", maxHours), model.ErrorTypeBadRequest, map[string]interface{}{
+			"field": "hours",
+		})
+	}
+
+	stats, err := h.authService.TokenStats(ctx, n)
+	if err != nil {
+		h.logger.Error("Failed to read token stats", "error", err)
+		return nil, errors.ErrSomethingWentWrong
+	}
+
+	result := &model.TokenStats{
+		Hours:            int32(n),
+		BlacklistSize:    int(stats.BlacklistSize),
+		ValidationErrors: []*model.TokenValidationErrorCount{},
+		PerHour:          make([]*model.TokenStatsHour, 0, len(stats.Hours)),
+	}
+
+	for _, hour := range stats.Hours {
+		var rejected int64
+		for _, count := range hour.ValidationErrors {
+			rejected += count
+		}
+
+		result.AccessIssued += int32(hour.AccessIssued)
+		result.RefreshIssued += int32(hour.RefreshIssued)
+		result.RefreshSucceeded += int32(hour.RefreshSucceeded)
+		result.RefreshFailed += int32(hour.RefreshFailed)
+		result.PerHour = append(result.PerHour, &model.TokenStatsHour{
+			Hour:             hour.Hour,
+			AccessIssued:     int32(hour.AccessIssued),
+			RefreshIssued:    int32(hour.RefreshIssued),
+			RefreshSucceeded: int32(hour.RefreshSucceeded),
+			RefreshFailed:    int32(hour.RefreshFailed),
+			ValidationErrors: int32(rejected),
+		})
+	}
+
+	reasons, totals := stats.ValidationErrorTotals()
+	for _, reason := range reasons {
+		result.ValidationErrors = append(result.ValidationErrors, &model.TokenValidationErrorCount{
+			Reason: reason,
+			Count:  int32(totals[reason]),
+		})
+	}
+
+	return result, nil
+}
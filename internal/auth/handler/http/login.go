@@ -2,14 +2,18 @@ package http
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/abisalde/authentication-service/internal/auth"
 	"github.com/abisalde/authentication-service/internal/auth/cookies"
 	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/database/ent/loginattempt"
 	"github.com/abisalde/authentication-service/internal/graph/errors"
 	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/slo"
 	"github.com/abisalde/authentication-service/pkg/jwt"
 	"github.com/abisalde/authentication-service/pkg/password"
 	"github.com/gofiber/fiber/v2"
@@ -17,37 +21,117 @@ import (
 
 type LoginHandler struct {
 	authService *service.AuthService
+	logger      *slog.Logger
 }
 
 func NewLoginHandler(authService *service.AuthService) *LoginHandler {
-	return &LoginHandler{authService: authService}
+	return &LoginHandler{authService: authService, logger: authService.Logger()}
 }
 
-func (h *LoginHandler) EmailLogin(ctx context.Context, input model.LoginInput) (*model.LoginResponse, error) {
+func (h *LoginHandler) EmailLogin(ctx context.Context, input model.LoginInput) (resp *model.LoginResponse, err error) {
+	started := time.Now()
+	defer func() {
+		slo.ObserveLogin(time.Since(started), err == nil, auth.GetRequestID(ctx))
+	}()
+
+	if err := h.authService.RequireIPStepUp(ctx, input.CaptchaToken); err != nil {
+		return nil, stepUpError(err)
+	}
 
 	user, err := h.authService.InitiateLogin(ctx, input.Email)
+	if err == nil && service.IsDeleted(user) {
+		user, err = nil, service.ErrAlreadyDeleted
+	}
 	if err != nil {
+		h.recordFailure(ctx, nil, input.Email, loginattempt.FailureReasonUNKNOWN_ACCOUNT)
 		return nil, errors.InvalidCredentialsEmail
 	}
 
 	err = password.CheckPasswordHash(input.Password, user.PasswordHash)
 	if err != nil {
+		h.recordFailure(ctx, user, input.Email, loginattempt.FailureReasonINVALID_PASSWORD)
 		return nil, errors.InvalidCredentialsPassword
 	}
 
-	tokens, err := cookies.GenerateLoginTokenPair(user.ID)
+	// Checked after the password so the suspension is only disclosed to the
+	// account owner.
+	if service.IsSuspended(user) {
+		h.recordFailure(ctx, user, input.Email, loginattempt.FailureReasonACCOUNT_SUSPENDED)
+		return nil, errors.AccountSuspended(service.SuspensionReason(user))
+	}
 
+	// Also after the password: organizations can override the policy, so
+	// it needs the user.
+	fiberCtx, _ := auth.GetFiberWebContext(ctx)
+	if err := h.authService.CheckGeoPolicy(ctx, fiberCtx, user, input.CaptchaToken); err != nil {
+		if err == service.ErrGeoBlocked {
+			return nil, errors.SignInCountryBlocked
+		}
+		return nil, stepUpError(err)
+	}
+
+	// Signing in again is how the owner restores a deactivated account.
+	restored, err := h.authService.LiftDeactivation(ctx, user)
 	if err != nil {
-		log.Printf("This is error from cookies.GenerateLoginTokenPair: %v", err)
+		h.logger.Error("Failed to lift the deactivation of a user", "user_id", user.ID, "error", err)
+		return nil, errors.ErrSomethingWentWrong
+	}
+	user = restored
+
+	if synced, syncErr := h.authService.SyncOnboardingStep(ctx, user); syncErr == nil {
+		user = synced
+	}
+
+	scopes, err := h.authService.TokenScopes(ctx, user)
+	if err != nil {
+		h.logger.Error("Failed to evaluate the token scopes", "user_id", user.ID, "error", err)
+		return nil, errors.ErrSomethingWentWrong
+	}
+
+	// Admitted before any token is minted, so a refused login is not
+	// counted as issued tokens.
+	device := cookies.DeviceFromContext(ctx)
+	if err := h.authService.AdmitProbationLogin(ctx, user, device); err != nil {
+		switch err {
+		case service.ErrEmailVerificationRequired:
+			return nil, errors.EmailVerificationRequired
+		case service.ErrSessionLimitReached:
+			return nil, errors.SessionLimitReached
+		}
+		h.logger.Error("Failed to admit a probation login", "user_id", user.ID, "error", err)
+		return nil, errors.ErrSomethingWentWrong
+	}
+
+	tokens, err := cookies.GenerateLoginTokenPair(ctx, user.PublicID.String(), device, scopes)
+
+	if err != nil {
+		h.logger.Error("Failed to generate the login token pair", "user_id", user.ID, "error", err)
+		return nil, errors.ErrSomethingWentWrong
+	}
+	h.authService.RecordTokenIssued(ctx, jwt.TokenTypeAccess, jwt.TokenTypeRefresh)
+
+	var nonce string
+	if input.Nonce != nil {
+		nonce = *input.Nonce
+	}
+	idToken, err := h.authService.IssueIDToken(ctx, user, nonce)
+	if err != nil {
+		h.logger.Error("Failed to issue an ID token", "user_id", user.ID, "error", err)
 		return nil, errors.ErrSomethingWentWrong
 	}
 
 	// Store and Hash the RefreshToken
-	hashedToken, refreshErr := h.authService.StoreRefreshToken(ctx, user.ID, tokens.RefreshToken)
+	hashedToken, refreshErr := h.authService.StoreRefreshToken(ctx, user, tokens.RefreshToken)
 
 	if refreshErr != nil {
 		return nil, errors.ErrSomethingWentWrong
 	}
+	if err := h.authService.TrackSessionToken(ctx, user.ID, tokens.AccessToken); err != nil {
+		h.logger.Warn("Failed to track the access token", "user_id", user.ID, "error", err)
+	}
+	if err := h.authService.RecordAppGrant(ctx, user.ID, scopes, tokens.AccessToken); err != nil {
+		h.logger.Warn("Failed to record the connected app", "user_id", user.ID, "error", err)
+	}
 
 	if fiberCtx, ok := ctx.Value(auth.FiberContextWeb).(*fiber.Ctx); ok {
 		err = cookies.CreateBrowserSession(cookies.TokenPair{
@@ -64,12 +148,34 @@ func (h *LoginHandler) EmailLogin(ctx context.Context, input model.LoginInput) (
 		return nil, errors.ErrSomethingWentWrong
 	}
 
-	return &model.LoginResponse{
+	h.authService.RecordLoginAttempt(ctx, service.LoginAttemptInput{
+		User:    user,
+		Email:   input.Email,
+		Method:  loginattempt.MethodPASSWORD,
+		Outcome: loginattempt.OutcomeSUCCESS,
+	})
+
+	response := &model.LoginResponse{
 		UserId:       user.ID,
+		PublicId:     user.PublicID.String(),
 		Token:        tokens.AccessToken,
 		RefreshToken: hashedToken,
 		Email:        user.Email,
-	}, nil
+	}
+	if idToken != "" {
+		response.IDToken = &idToken
+	}
+	return response, nil
+}
+
+func (h *LoginHandler) recordFailure(ctx context.Context, user *ent.User, email string, reason loginattempt.FailureReason) {
+	h.authService.RecordLoginAttempt(ctx, service.LoginAttemptInput{
+		User:          user,
+		Email:         email,
+		Method:        loginattempt.MethodPASSWORD,
+		Outcome:       loginattempt.OutcomeFAILURE,
+		FailureReason: reason,
+	})
 }
 
 func (h *LoginHandler) ProcessLogout(ctx context.Context) (bool, error) {
@@ -79,15 +185,20 @@ func (h *LoginHandler) ProcessLogout(ctx context.Context) (bool, error) {
 	}
 
 	_ = h.authService.InvalidateRefreshToken(ctx, currentUser.ID)
+	h.authService.RecordRevocation(ctx, currentUser.ID, model.RevocationReasonUserLogout)
 
-	token, ok := ctx.Value(auth.JWTTokenKey).(string)
-	if ok && token != "" {
-		remainingTTL := jwt.GetTokenRemainingTTL(token)
-		if remainingTTL > 0 {
-			h.authService.BlacklistToken(ctx, token, remainingTTL)
-		}
+	if token := auth.GetToken(ctx); token != "" {
+		h.authService.RevokeAccessToken(ctx, token)
 	}
 
+	clearSessionCookies(ctx)
+
+	return true, nil
+}
+
+// clearSessionCookies drops the browser session cookies on whichever
+// transport served the request.
+func clearSessionCookies(ctx context.Context) {
 	if fiberCtx, ok := ctx.Value(auth.FiberContextWeb).(*fiber.Ctx); ok {
 		fiberCtx.ClearCookie(cookies.BrowserAccessTokenName)
 		fiberCtx.ClearCookie(cookies.BrowserSessionTokenName)
@@ -96,6 +207,4 @@ func (h *LoginHandler) ProcessLogout(ctx context.Context) (bool, error) {
 		http.SetCookie(w, &http.Cookie{Name: cookies.BrowserAccessTokenName, MaxAge: -1, Path: "/"})
 		http.SetCookie(w, &http.Cookie{Name: cookies.BrowserSessionTokenName, MaxAge: -1, Path: "/"})
 	}
-
-	return true, nil
 }
@@ -0,0 +1,22 @@
+package http
+
+import (
+	"context"
+
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+func (h *UsersHandler) RevokeUnrecognizedSignIn(ctx context.Context, token string) (bool, error) {
+	err := h.authService.RevokeUnrecognizedSignIn(ctx, token)
+	switch err {
+	case nil:
+		return true, nil
+	case service.ErrSignInAlertTokenInvalid:
+		return false, errors.NewTypedError("The link is invalid, expired or was already used", model.ErrorTypeToken, nil)
+	}
+
+	h.logger.Error("Failed to revoke sessions after an unrecognized sign-in", "error", err)
+	return false, errors.ErrSomethingWentWrong
+}
@@ -0,0 +1,140 @@
+package http
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/database/ent/loginattempt"
+	"github.com/abisalde/authentication-service/internal/graph/converters"
+	"github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+const maxLoginHistoryPage = 100
+
+func (h *UsersHandler) GetLoginAttempts(ctx context.Context, filter *model.LoginAttemptFilter, first *int32, after *string) (*model.LoginAttemptConnection, error) {
+	limit, beforeID, err := loginHistoryPage(first, after, 50)
+	if err != nil {
+		return nil, err
+	}
+
+	var query repository.LoginAttemptFilter
+	if filter != nil {
+		if filter.UserID != nil {
+			u, err := h.authService.ResolveUserReference(ctx, *filter.UserID)
+			if err != nil {
+				return emptyLoginAttemptConnection(), nil
+			}
+			query.UserID = &u.ID
+		}
+		if filter.Email != nil {
+			query.Email = *filter.Email
+		}
+		if filter.IP != nil {
+			query.IP = *filter.IP
+		}
+		if filter.Outcome != nil {
+			outcome := loginattempt.Outcome(*filter.Outcome)
+			query.Outcome = &outcome
+		}
+		query.Since = filter.Since
+		query.Until = filter.Until
+	}
+
+	return h.listLoginAttempts(ctx, query, beforeID, limit)
+}
+
+// GetLoginActivity lists the current user's successful sign-ins; failed
+// attempts stay visible to admins only.
+func (h *ProfileHandler) GetLoginActivity(ctx context.Context, first *int32, after *string) (*model.LoginAttemptConnection, error) {
+	currentUser := auth.GetCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, errors.AuthenticationRequired
+	}
+
+	limit, beforeID, err := loginHistoryPage(first, after, 20)
+	if err != nil {
+		return nil, err
+	}
+
+	success := loginattempt.OutcomeSUCCESS
+	query := repository.LoginAttemptFilter{UserID: &currentUser.ID, Outcome: &success}
+
+	attempts, err := h.authService.ListLoginAttempts(ctx, query, beforeID, limit+1)
+	if err != nil {
+		h.logger.Error("Failed to list login activity", "user_id", currentUser.ID, "error", err)
+		return nil, errors.ErrSomethingWentWrong
+	}
+
+	return buildLoginAttemptConnection(attempts, limit), nil
+}
+
+func (h *UsersHandler) listLoginAttempts(ctx context.Context, query repository.LoginAttemptFilter, beforeID int64, limit int) (*model.LoginAttemptConnection, error) {
+	attempts, err := h.authService.ListLoginAttempts(ctx, query, beforeID, limit+1)
+	if err != nil {
+		h.logger.Error("Failed to list login attempts", "error", err)
+		return nil, errors.ErrSomethingWentWrong
+	}
+
+	return buildLoginAttemptConnection(attempts, limit), nil
+}
+
+func loginHistoryPage(first *int32, after *string, defaultLimit int) (int, int64, error) {
+	limit := defaultLimit
+	if first != nil {
+		limit = int(*first)
+	}
+	if limit < 1 || limit > maxLoginHistoryPage {
+		return 0, 0, errors.NewTypedError("first must be between 1 and 100", model.ErrorTypeBadRequest, map[string]interface{}{
+			"field": "first",
+		})
+	}
+
+	var beforeID int64
+	if after != nil && *after != "" {
+		id, err := strconv.ParseInt(*after, 10, 64)
+		if err != nil || id <= 0 {
+			return 0, 0, errors.NewTypedError("Invalid cursor", model.ErrorTypeBadRequest, map[string]interface{}{
+				"field": "after",
+			})
+		}
+		beforeID = id
+	}
+
+	return limit, beforeID, nil
+}
+
+// buildLoginAttemptConnection expects up to limit+1 attempts; the extra one
+// only signals that another page exists.
+func buildLoginAttemptConnection(attempts []*ent.LoginAttempt, limit int) *model.LoginAttemptConnection {
+	hasNext := len(attempts) > limit
+	if hasNext {
+		attempts = attempts[:limit]
+	}
+
+	conn := emptyLoginAttemptConnection()
+	conn.PageInfo.HasNextPage = hasNext
+
+	for _, attempt := range attempts {
+		node := converters.LoginAttemptToGraph(attempt)
+		conn.Edges = append(conn.Edges, &model.LoginAttemptEdge{Node: node, Cursor: node.ID})
+	}
+
+	if len(conn.Edges) > 0 {
+		start, end := conn.Edges[0].Cursor, conn.Edges[len(conn.Edges)-1].Cursor
+		conn.PageInfo.StartCursor = &start
+		conn.PageInfo.EndCursor = &end
+	}
+
+	return conn
+}
+
+func emptyLoginAttemptConnection() *model.LoginAttemptConnection {
+	return &model.LoginAttemptConnection{
+		Edges:    []*model.LoginAttemptEdge{},
+		PageInfo: &model.PageInfo{},
+	}
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/maintenance"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/database/ent/user"
+	"github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+func (h *UsersHandler) RequestMaintenanceToken(ctx context.Context, input model.MaintenanceTokenInput) (*model.MaintenanceGrant, error) {
+	grant, err := h.authService.RequestMaintenanceToken(ctx, auth.GetCurrentUser(ctx), input.Operations, input.Reason, input.Organization)
+	if err != nil {
+		return nil, maintenanceError(err)
+	}
+	return h.maintenanceGrantToGraph(grant), nil
+}
+
+func (h *UsersHandler) ApproveMaintenanceToken(ctx context.Context, id string) (*model.MaintenanceGrant, error) {
+	grant, err := h.authService.ApproveMaintenanceToken(ctx, auth.GetCurrentUser(ctx), id)
+	if err != nil {
+		return nil, maintenanceError(err)
+	}
+	return h.maintenanceGrantToGraph(grant), nil
+}
+
+func (h *UsersHandler) IssueMaintenanceToken(ctx context.Context, id string) (*model.MaintenanceToken, error) {
+	token, grant, err := h.authService.IssueMaintenanceToken(ctx, auth.GetCurrentUser(ctx), id)
+	if err != nil {
+		return nil, maintenanceError(err)
+	}
+	return &model.MaintenanceToken{
+		Token:     token,
+		ExpiresAt: grant.ExpiresAt,
+		Grant:     h.maintenanceGrantToGraph(grant),
+	}, nil
+}
+
+// GetMaintenanceGrant shows a grant to its operator and to admins only.
+func (h *UsersHandler) GetMaintenanceGrant(ctx context.Context, id string) (*model.MaintenanceGrant, error) {
+	grant, err := h.authService.GetMaintenanceGrant(ctx, id)
+	if err != nil {
+		return nil, nil
+	}
+
+	currentUser := auth.GetCurrentUser(ctx)
+	if grant.OperatorID != currentUser.ID && currentUser.Role != user.RoleADMIN {
+		return nil, nil
+	}
+	return h.maintenanceGrantToGraph(grant), nil
+}
+
+func (h *UsersHandler) maintenanceGrantToGraph(grant *maintenance.Grant) *model.MaintenanceGrant {
+	return &model.MaintenanceGrant{
+		ID:                grant.ID,
+		OperatorID:        grant.Operator,
+		Operations:        grant.Operations,
+		Reason:            grant.Reason,
+		Organization:      organizationSlug(grant),
+		Status:            model.MaintenanceGrantStatus(grant.Status),
+		Approvals:         int32(len(grant.ApprovedBy)),
+		ApprovalsRequired: int32(h.authService.MaintenancePolicy().Approvals),
+		CreatedAt:         grant.CreatedAt,
+		ExpiresAt:         grant.ExpiresAt,
+	}
+}
+
+func organizationSlug(grant *maintenance.Grant) *string {
+	if grant.OrganizationID == nil {
+		return nil
+	}
+	return &grant.Organization
+}
+
+func maintenanceError(err error) error {
+	switch err {
+	case service.ErrMaintenanceDisabled:
+		return errors.NewTypedError("Maintenance tokens are not enabled", model.ErrorTypeForbidden, nil)
+	case service.ErrOperationNotGrantable:
+		return errors.NewTypedError("One of the operations cannot be granted to maintenance tokens", model.ErrorTypeBadRequest, map[string]interface{}{
+			"field": "operations",
+		})
+	case service.ErrMaintenanceReasonRequired:
+		return errors.NewTypedError("A reason of at most 500 characters is required", model.ErrorTypeBadRequest, map[string]interface{}{
+			"field": "reason",
+		})
+	case service.ErrOrganizationNotFound:
+		return errors.NewTypedError("Organization not found", model.ErrorTypeNotFound, map[string]interface{}{
+			"field": "organization",
+		})
+	case service.ErrGrantNotFound:
+		return errors.NewTypedError("Maintenance grant not found or expired", model.ErrorTypeNotFound, nil)
+	case service.ErrSelfApproval:
+		return errors.NewTypedError("Operators cannot approve their own maintenance grant", model.ErrorTypeForbidden, nil)
+	case service.ErrGrantAlreadyApproved:
+		return errors.NewTypedError("Maintenance grant no longer needs your approval", model.ErrorTypeConflict, nil)
+	case service.ErrGrantNotApproved:
+		return errors.NewTypedError("Maintenance grant is not approved or its token was already issued", model.ErrorTypeConflict, nil)
+	case service.ErrNotGrantOperator:
+		return errors.NewTypedError("Maintenance grant belongs to another operator", model.ErrorTypeForbidden, nil)
+	}
+
+	slog.Error("Failed to process a maintenance grant", "error", err)
+	return errors.ErrSomethingWentWrong
+}
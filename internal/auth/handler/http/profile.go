@@ -2,9 +2,12 @@ package http
 
 import (
 	"context"
+	"log/slog"
 
 	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/cookies"
 	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/database/ent"
 	"github.com/abisalde/authentication-service/internal/graph/converters"
 	"github.com/abisalde/authentication-service/internal/graph/errors"
 	"github.com/abisalde/authentication-service/internal/graph/model"
@@ -13,10 +16,11 @@ import (
 
 type ProfileHandler struct {
 	authService *service.AuthService
+	logger      *slog.Logger
 }
 
 func NewProfileHandler(authService *service.AuthService) *ProfileHandler {
-	return &ProfileHandler{authService: authService}
+	return &ProfileHandler{authService: authService, logger: authService.Logger()}
 }
 
 func (h *ProfileHandler) GetUserProfile(ctx context.Context) (*model.User, error) {
@@ -29,6 +33,21 @@ func (h *ProfileHandler) GetUserProfile(ctx context.Context) (*model.User, error
 	return converters.UserToGraph(currentUser), nil
 }
 
+func (h *ProfileHandler) GetCSRFToken(ctx context.Context) (string, error) {
+	currentUser := auth.GetCurrentUser(ctx)
+
+	if currentUser == nil {
+		return "", errors.AuthenticationRequired
+	}
+
+	token, err := cookies.CSRFTokenFor(ctx, currentUser)
+	if err != nil {
+		return "", errors.NewTypedError("Unable to issue CSRF token", model.ErrorTypeInternalServerError, nil)
+	}
+
+	return token, nil
+}
+
 func (h *ProfileHandler) HandlePasswordChange(ctx context.Context, input model.ChangePasswordInput) (bool, error) {
 	_, err := password.VerifyPasswords(&input)
 	if err != nil {
@@ -39,6 +58,9 @@ func (h *ProfileHandler) HandlePasswordChange(ctx context.Context, input model.C
 	if currentUser == nil {
 		return false, errors.AuthenticationRequired
 	}
+	if service.IsServiceAccount(currentUser) {
+		return false, errors.NewTypedError("Service accounts authenticate with keys, not passwords", model.ErrorTypeForbidden, nil)
+	}
 
 	if err := password.CheckPasswordHash(input.OldPassword, currentUser.PasswordHash); err != nil {
 		return false, errors.InvalidCredentialsPassword
@@ -56,6 +78,107 @@ func (h *ProfileHandler) HandlePasswordChange(ctx context.Context, input model.C
 	return true, nil
 }
 
+func (h *ProfileHandler) ChangeEmail(ctx context.Context, input model.ChangeEmailInput) (bool, error) {
+	currentUser := auth.GetCurrentUser(ctx)
+	if currentUser == nil {
+		return false, errors.AuthenticationRequired
+	}
+
+	if currentUser.PasswordHash != "" {
+		if input.Password == nil || password.CheckPasswordHash(*input.Password, currentUser.PasswordHash) != nil {
+			return false, errors.InvalidCredentialsPassword
+		}
+	}
+
+	if err := h.authService.RequestEmailChange(ctx, currentUser, input.NewEmail); err != nil {
+		return false, emailChangeError(err)
+	}
+	return true, nil
+}
+
+func (h *ProfileHandler) ConfirmEmailChange(ctx context.Context, code string) (bool, error) {
+	currentUser := auth.GetCurrentUser(ctx)
+	if currentUser == nil {
+		return false, errors.AuthenticationRequired
+	}
+
+	if _, err := h.authService.ConfirmEmailChange(ctx, currentUser, code); err != nil {
+		return false, emailChangeError(err)
+	}
+	return true, nil
+}
+
+func (h *ProfileHandler) DeactivateAccount(ctx context.Context, pass *string) (bool, error) {
+	currentUser, err := h.confirmAccountOwner(ctx, pass)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := h.authService.DeactivateAccount(ctx, currentUser); err != nil {
+		if err == service.ErrAlreadyDeactivated {
+			return false, errors.NewTypedError("Account is already deactivated", model.ErrorTypeBadRequest, nil)
+		}
+		h.logger.Error("Failed to deactivate the account", "user_id", currentUser.ID, "error", err)
+		return false, errors.ErrSomethingWentWrong
+	}
+
+	clearSessionCookies(ctx)
+	return true, nil
+}
+
+func (h *ProfileHandler) DeleteAccount(ctx context.Context, pass *string) (bool, error) {
+	currentUser, err := h.confirmAccountOwner(ctx, pass)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := h.authService.DeleteAccount(ctx, currentUser); err != nil {
+		h.logger.Error("Failed to delete the account", "user_id", currentUser.ID, "error", err)
+		return false, errors.ErrSomethingWentWrong
+	}
+
+	clearSessionCookies(ctx)
+	return true, nil
+}
+
+// confirmAccountOwner returns the signed in user, checking the password of
+// accounts that have one before the account is closed.
+func (h *ProfileHandler) confirmAccountOwner(ctx context.Context, pass *string) (*ent.User, error) {
+	currentUser := auth.GetCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, errors.AuthenticationRequired
+	}
+
+	if currentUser.PasswordHash != "" {
+		if pass == nil || password.CheckPasswordHash(*pass, currentUser.PasswordHash) != nil {
+			return nil, errors.InvalidCredentialsPassword
+		}
+	}
+	return currentUser, nil
+}
+
+func emailChangeError(err error) error {
+	switch err {
+	case service.ErrEmailUnchanged:
+		return errors.NewTypedError("The new email is your current email", model.ErrorTypeBadRequest, map[string]interface{}{
+			"field": "newEmail",
+		})
+	case service.ErrEmailTaken:
+		return errors.NewTypedError("This email is already in use", model.ErrorTypeEmailExists, map[string]interface{}{
+			"field": "newEmail",
+		})
+	case service.ErrNoEmailChange, service.ErrEmailChangeExpired:
+		return errors.OTPCodeExpire
+	case service.ErrEmailChangeCode:
+		return errors.OTPCodeNotValid
+	case service.ErrServiceAccount:
+		return errors.NewTypedError("Service accounts have no email to change", model.ErrorTypeForbidden, nil)
+	}
+
+	slog.Error("Failed to change email", "error", err)
+	return errors.ErrSomethingWentWrong
+}
+
 func (h *ProfileHandler) UpdateUserProfile(ctx context.Context, input model.UpdateProfileInput) (*model.User, error) {
 	currentUser := auth.GetCurrentUser(ctx)
 	if currentUser == nil {
@@ -80,10 +203,47 @@ func (h *ProfileHandler) UpdateUserProfile(ctx context.Context, input model.Upda
 		}
 	}
 
-	updatedUser, err := h.authService.FindUserProfileById(ctx, currentUser.ID)
+	updatedUser, err := h.authService.UpdateUserProfile(ctx, currentUser.ID, input)
+	if err != nil {
+		return nil, errors.ErrSomethingWentWrong
+	}
+
+	return converters.UserToGraph(updatedUser), nil
+}
+
+func (h *ProfileHandler) GetOnboardingStatus(ctx context.Context) (*model.OnboardingStatus, error) {
+	currentUser := auth.GetCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, errors.AuthenticationRequired
+	}
+
+	return h.authService.GetOnboardingStatus(currentUser), nil
+}
+
+func (h *ProfileHandler) AcceptTerms(ctx context.Context) (*model.User, error) {
+	currentUser := auth.GetCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, errors.AuthenticationRequired
+	}
+
+	updatedUser, err := h.authService.AcceptTerms(ctx, currentUser.ID)
 	if err != nil {
-		return nil, err
+		return nil, errors.ErrSomethingWentWrong
 	}
 
 	return converters.UserToGraph(updatedUser), nil
 }
+
+func (h *ProfileHandler) GetSecurityCheckup(ctx context.Context) (*model.SecurityCheckup, error) {
+	currentUser := auth.GetCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, errors.AuthenticationRequired
+	}
+
+	checkup, err := h.authService.SecurityCheckup(ctx, currentUser)
+	if err != nil {
+		h.logger.Error("Failed to run the security checkup", "user_id", currentUser.ID, "error", err)
+		return nil, errors.ErrSomethingWentWrong
+	}
+	return checkup, nil
+}
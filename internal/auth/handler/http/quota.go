@@ -0,0 +1,46 @@
+package http
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/ratelimit"
+)
+
+type QuotaHandler struct {
+	limiter *ratelimit.Limiter
+}
+
+func NewQuotaHandler(limiter *ratelimit.Limiter) *QuotaHandler {
+	return &QuotaHandler{limiter: limiter}
+}
+
+// GetAPIClientQuota reports the quotas of the API client making the
+// request; it is meaningless for anyone else.
+func (h *QuotaHandler) GetAPIClientQuota(ctx context.Context) (*model.APIClientQuota, error) {
+	client := auth.GetAutomationClient(ctx)
+	if client == "" {
+		return nil, errors.AuthenticationRequired
+	}
+
+	usage, err := h.limiter.QuotaUsage(ctx, client)
+	if err != nil {
+		slog.Error("Failed to read client quota", "client", client, "error", err)
+		return nil, errors.ErrSomethingWentWrong
+	}
+
+	quota := &model.APIClientQuota{Client: client, Usage: make([]*model.QuotaUsage, 0, len(usage))}
+	for _, u := range usage {
+		quota.Usage = append(quota.Usage, &model.QuotaUsage{
+			Dimension: u.Dimension,
+			Limit:     int(u.Limit),
+			Used:      int(u.Used),
+			Remaining: int(u.Remaining()),
+			ResetsAt:  u.ResetsAt,
+		})
+	}
+	return quota, nil
+}
@@ -2,25 +2,44 @@ package http
 
 import (
 	"context"
+	"log/slog"
+	"strings"
 	"time"
 
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/consent"
+	"github.com/abisalde/authentication-service/internal/auth/onboarding"
 	"github.com/abisalde/authentication-service/internal/auth/service"
 	"github.com/abisalde/authentication-service/internal/graph/errors"
 	"github.com/abisalde/authentication-service/internal/graph/model"
 	"github.com/abisalde/authentication-service/pkg/password"
-	"github.com/abisalde/authentication-service/pkg/verification"
 )
 
 type RegisterHandler struct {
 	authService *service.AuthService
+	logger      *slog.Logger
 }
 
 func NewRegisterHandler(authService *service.AuthService) *RegisterHandler {
-	return &RegisterHandler{authService: authService}
+	return &RegisterHandler{authService: authService, logger: authService.Logger()}
 }
 
 func (h *RegisterHandler) Register(ctx context.Context, input model.RegisterInput) (*model.RegisterResponse, error) {
 
+	if err := h.validateRegistrationFields(input); err != nil {
+		return nil, err
+	}
+
+	if err := h.authService.RequireIPStepUp(ctx, input.CaptchaToken); err != nil {
+		return nil, stepUpError(err)
+	}
+
+	requirements := h.authService.ConsentPolicy().For(h.signupCountry(ctx, input.Country))
+	consents := consentsFromInput(input)
+	if err := requirements.Check(consents, time.Now()); err != nil {
+		return nil, consentError(err, requirements)
+	}
+
 	emailExist, err := h.authService.InitiateRegistration(ctx, input)
 	if err != nil {
 		return nil, errors.ErrSomethingWentWrong
@@ -35,7 +54,7 @@ func (h *RegisterHandler) Register(ctx context.Context, input model.RegisterInpu
 		return nil, errors.ErrSomethingWentWrong
 	}
 
-	code := verification.GenerateVerificationCode()
+	code := h.authService.NewVerificationCode(input.Email)
 	expiresAt := time.Now().Add(5 * time.Minute)
 
 	pendingUser := model.PendingUser{
@@ -46,6 +65,22 @@ func (h *RegisterHandler) Register(ctx context.Context, input model.RegisterInpu
 		ExpiresAt:        expiresAt,
 	}
 
+	if input.FirstName != nil {
+		pendingUser.FirstName = strings.TrimSpace(*input.FirstName)
+	}
+	if input.LastName != nil {
+		pendingUser.LastName = strings.TrimSpace(*input.LastName)
+	}
+	if input.AcceptTerms != nil && *input.AcceptTerms {
+		acceptedAt := time.Now()
+		pendingUser.TermsAcceptedAt = &acceptedAt
+		pendingUser.TermsVersion = consents.TermsVersion
+	}
+	if input.Country != nil {
+		pendingUser.Country = consent.NormalizeCountry(*input.Country)
+	}
+	pendingUser.MarketingOptIn = requirements.MarketingOptIn(consents)
+
 	err = h.authService.CreatePendingUser(ctx, pendingUser)
 
 	if err != nil {
@@ -66,6 +101,92 @@ func (h *RegisterHandler) Register(ctx context.Context, input model.RegisterInpu
 	}, nil
 }
 
+// validateRegistrationFields enforces the conditional registration fields when
+// the deployment collects onboarding data up front instead of after sign up.
+func (h *RegisterHandler) validateRegistrationFields(input model.RegisterInput) error {
+	policy := h.authService.OnboardingPolicy()
+	if !policy.RequireAtRegistration {
+		return nil
+	}
+
+	if policy.Requires(onboarding.StepCompleteProfile) {
+		if input.FirstName == nil || strings.TrimSpace(*input.FirstName) == "" {
+			return errors.NewTypedError("First name is required", model.ErrorTypeInvalidInput, map[string]interface{}{"field": "firstName"})
+		}
+		if input.LastName == nil || strings.TrimSpace(*input.LastName) == "" {
+			return errors.NewTypedError("Last name is required", model.ErrorTypeInvalidInput, map[string]interface{}{"field": "lastName"})
+		}
+	}
+
+	if policy.Requires(onboarding.StepAcceptTerms) && (input.AcceptTerms == nil || !*input.AcceptTerms) {
+		return errors.NewTypedError("Terms of service must be accepted", model.ErrorTypeInvalidInput, map[string]interface{}{"field": "acceptTerms"})
+	}
+
+	return nil
+}
+
+// RegistrationRequirements tells clients which consents register will ask
+// for, so the sign-up form can show the right fields and terms.
+func (h *RegisterHandler) RegistrationRequirements(ctx context.Context, country *string) (*model.RegistrationRequirements, error) {
+	requirements := h.authService.ConsentPolicy().For(h.signupCountry(ctx, country))
+
+	result := &model.RegistrationRequirements{
+		MinimumAge:            int32(requirements.MinimumAge),
+		MarketingOptInDefault: requirements.MarketingOptInDefault,
+	}
+	if requirements.Country != "" {
+		result.Country = &requirements.Country
+	}
+	if requirements.TermsVersion != "" {
+		result.TermsVersion = &requirements.TermsVersion
+	}
+
+	return result, nil
+}
+
+// signupCountry prefers the country the user picked over the one the CDN
+// detected from the request.
+func (h *RegisterHandler) signupCountry(ctx context.Context, country *string) string {
+	if country != nil && strings.TrimSpace(*country) != "" {
+		return *country
+	}
+
+	header := h.authService.ConsentPolicy().CountryHeader
+	if fiberCtx, ok := auth.GetFiberWebContext(ctx); ok && header != "" {
+		return fiberCtx.Get(header)
+	}
+	return ""
+}
+
+func consentsFromInput(input model.RegisterInput) consent.Consents {
+	consents := consent.Consents{
+		DateOfBirth:    input.DateOfBirth,
+		AcceptTerms:    input.AcceptTerms != nil && *input.AcceptTerms,
+		MarketingOptIn: input.MarketingOptIn,
+	}
+	if input.TermsVersion != nil {
+		consents.TermsVersion = strings.TrimSpace(*input.TermsVersion)
+	}
+	return consents
+}
+
+func consentError(err error, requirements consent.Requirements) error {
+	violation, ok := err.(*consent.Violation)
+	if !ok {
+		return errors.ErrSomethingWentWrong
+	}
+
+	extensions := map[string]interface{}{"field": violation.Field}
+	switch violation.Field {
+	case "dateOfBirth":
+		extensions["minimumAge"] = requirements.MinimumAge
+	case "acceptTerms", "termsVersion":
+		extensions["termsVersion"] = requirements.TermsVersion
+	}
+
+	return errors.NewTypedError(violation.Message, model.ErrorTypeInvalidInput, extensions)
+}
+
 func (h *RegisterHandler) VerifyUserEmail(ctx context.Context, input model.AccountVerification) (bool, error) {
 	user, err := h.authService.VerifyCodeAndCreateUser(ctx, input.Email, input.Code)
 	if err != nil {
@@ -86,7 +207,7 @@ func (h *RegisterHandler) ResendVerificationCodeEmail(ctx context.Context, input
 		return false, errors.UserNotFound
 	}
 
-	newCode := verification.GenerateVerificationCode()
+	newCode := h.authService.NewVerificationCode(pendingUser.Email)
 	newExpiration := time.Now().Add(5 * time.Minute)
 
 	pendingUser.VerificationCode = newCode
@@ -102,3 +223,41 @@ func (h *RegisterHandler) ResendVerificationCodeEmail(ctx context.Context, input
 
 	return true, nil
 }
+
+func (h *RegisterHandler) StopVerificationReminders(ctx context.Context, token string) (bool, error) {
+	err := h.authService.StopVerificationNudges(ctx, token)
+	switch err {
+	case nil:
+		return true, nil
+	case service.ErrUnsubscribeTokenInvalid:
+		return false, errors.NewTypedError("The link is invalid, expired or was already used", model.ErrorTypeToken, nil)
+	}
+
+	h.logger.Error("Failed to stop verification reminders", "error", err)
+	return false, errors.ErrSomethingWentWrong
+}
+
+func (h *RegisterHandler) EmailStatus(ctx context.Context, email string, captchaToken *string) (*model.EmailStatus, error) {
+	token := ""
+	if captchaToken != nil {
+		token = *captchaToken
+	}
+
+	status, err := h.authService.EmailStatus(ctx, email, token, auth.GetIPFromContext(ctx))
+	switch {
+	case err == service.ErrCaptchaRequired:
+		return nil, errors.CaptchaRequired
+	case err != nil:
+		return nil, errors.ErrSomethingWentWrong
+	}
+
+	return &model.EmailStatus{Email: email, Status: status}, nil
+}
+
+func stepUpError(err error) error {
+	if err == service.ErrCaptchaRequired {
+		return errors.CaptchaRequired
+	}
+	slog.Error("Failed to verify the CAPTCHA of a stepped-up client", "error", err)
+	return errors.ErrSomethingWentWrong
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/graph/converters"
+	"github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+func (h *UsersHandler) CreateServiceAccount(ctx context.Context, input model.CreateServiceAccountInput) (*model.ServiceAccountCredentials, error) {
+	credentials, err := h.authService.CreateServiceAccount(ctx, input.Organization, input.Name)
+	if err != nil {
+		return nil, serviceAccountError(err, "create service account")
+	}
+	return credentialsToGraph(credentials), nil
+}
+
+func (h *UsersHandler) ListServiceAccounts(ctx context.Context, organization string) ([]*model.ServiceAccount, error) {
+	accounts, err := h.authService.ListServiceAccounts(ctx, organization)
+	if err != nil {
+		return nil, serviceAccountError(err, "list service accounts")
+	}
+
+	result := make([]*model.ServiceAccount, 0, len(accounts))
+	for _, account := range accounts {
+		result = append(result, converters.ServiceAccountToGraph(account))
+	}
+	return result, nil
+}
+
+func (h *UsersHandler) RotateServiceAccountKey(ctx context.Context, id string) (*model.ServiceAccountCredentials, error) {
+	credentials, err := h.authService.RotateServiceAccountKey(ctx, id)
+	if err != nil {
+		return nil, serviceAccountError(err, "rotate the key of service account")
+	}
+	return credentialsToGraph(credentials), nil
+}
+
+func (h *UsersHandler) RevokeServiceAccountKey(ctx context.Context, id, keyID string) (*model.ServiceAccount, error) {
+	account, err := h.authService.RevokeServiceAccountKey(ctx, id, keyID)
+	if err != nil {
+		return nil, serviceAccountError(err, "revoke the key of service account")
+	}
+	return converters.ServiceAccountToGraph(account), nil
+}
+
+func credentialsToGraph(credentials *service.ServiceAccountCredentials) *model.ServiceAccountCredentials {
+	return &model.ServiceAccountCredentials{
+		Account: converters.ServiceAccountToGraph(credentials.Account),
+		KeyID:   credentials.KeyID,
+		Secret:  credentials.Secret,
+	}
+}
+
+func serviceAccountError(err error, action string) error {
+	switch {
+	case err == service.ErrOrganizationNotFound:
+		return errors.NewTypedError("Organization not found", model.ErrorTypeNotFound, nil)
+	case err == service.ErrServiceAccountKeyNotFound:
+		return errors.NewTypedError("Service account key not found", model.ErrorTypeNotFound, nil)
+	case err == service.ErrServiceAccountNotFound || err == errors.UserNotFound || ent.IsNotFound(err):
+		return errors.NewTypedError("Service account not found", model.ErrorTypeNotFound, nil)
+	case ent.IsValidationError(err):
+		return errors.NewTypedError(err.Error(), model.ErrorTypeInvalidInput, nil)
+	}
+
+	slog.Error("Failed to "+action, "error", err)
+	return errors.ErrSomethingWentWrong
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"context"
+
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+func (h *ProfileHandler) GetActiveSessions(ctx context.Context) ([]*model.ActiveSession, error) {
+	currentUser := auth.GetCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, errors.AuthenticationRequired
+	}
+
+	sessions, err := h.authService.ActiveSessions(ctx, currentUser.ID)
+	if err != nil {
+		h.logger.Error("Failed to list the sessions", "user_id", currentUser.ID, "error", err)
+		return nil, errors.ErrSomethingWentWrong
+	}
+	return activeSessionsToGraph(sessions, currentJTI(ctx)), nil
+}
+
+// RevokeSession, like the other session revocations, is refused to
+// connected apps, so an app acting for the user cannot sign the user out.
+func (h *ProfileHandler) RevokeSession(ctx context.Context, id string) ([]*model.ActiveSession, error) {
+	currentUser := auth.GetCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, errors.AuthenticationRequired
+	}
+	if auth.GetAutomationClient(ctx) != "" {
+		return nil, errors.NewTypedError("Sessions can only be revoked by the user", model.ErrorTypeForbidden, nil)
+	}
+
+	switch err := h.authService.RevokeSession(ctx, currentUser.ID, id); err {
+	case nil:
+	case service.ErrSessionNotFound:
+		return nil, errors.NewTypedError("Session not found", model.ErrorTypeNotFound, nil)
+	default:
+		h.logger.Error("Failed to revoke a session", "user_id", currentUser.ID, "session", id, "error", err)
+		return nil, errors.ErrSomethingWentWrong
+	}
+
+	return h.GetActiveSessions(ctx)
+}
+
+func (h *ProfileHandler) RevokeOtherSessions(ctx context.Context) ([]*model.ActiveSession, error) {
+	currentUser := auth.GetCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, errors.AuthenticationRequired
+	}
+	if auth.GetAutomationClient(ctx) != "" {
+		return nil, errors.NewTypedError("Sessions can only be revoked by the user", model.ErrorTypeForbidden, nil)
+	}
+
+	if _, err := h.authService.RevokeOtherSessions(ctx, currentUser.ID, currentJTI(ctx)); err != nil {
+		h.logger.Error("Failed to revoke the other sessions", "user_id", currentUser.ID, "error", err)
+		return nil, errors.ErrSomethingWentWrong
+	}
+
+	return h.GetActiveSessions(ctx)
+}
+
+func (h *ProfileHandler) LogoutAllDevices(ctx context.Context) (bool, error) {
+	currentUser := auth.GetCurrentUser(ctx)
+	if currentUser == nil {
+		return false, errors.AuthenticationRequired
+	}
+	if auth.GetAutomationClient(ctx) != "" {
+		return false, errors.NewTypedError("Sessions can only be revoked by the user", model.ErrorTypeForbidden, nil)
+	}
+
+	if err := h.authService.LogoutAllDevices(ctx, currentUser.ID); err != nil {
+		h.logger.Error("Failed to sign out every device", "user_id", currentUser.ID, "error", err)
+		return false, errors.ErrSomethingWentWrong
+	}
+
+	clearSessionCookies(ctx)
+	return true, nil
+}
+
+// currentJTI is the token ID of the session making the request.
+func currentJTI(ctx context.Context) string {
+	if claims := auth.GetClaims(ctx); claims != nil {
+		return claims.ID
+	}
+	return ""
+}
+
+func activeSessionsToGraph(sessions []service.ActiveSession, current string) []*model.ActiveSession {
+	out := make([]*model.ActiveSession, 0, len(sessions))
+	for _, session := range sessions {
+		out = append(out, &model.ActiveSession{
+			ID:         session.ID,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    current != "" && session.ID == current,
+			Suspicious: session.Suspicious,
+		})
+	}
+	return out
+}
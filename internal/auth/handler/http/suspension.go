@@ -0,0 +1,65 @@
+package http
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/graph/converters"
+	"github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+func (h *UsersHandler) SuspendUser(ctx context.Context, userID string, reason model.SuspensionReason) (*model.User, error) {
+	suspended, err := h.authService.SuspendUser(ctx, userID, reason)
+	if err != nil {
+		return nil, suspensionError(err, "suspend")
+	}
+	return converters.UserToGraph(suspended), nil
+}
+
+func (h *UsersHandler) LiftSuspension(ctx context.Context, userID string) (*model.User, error) {
+	reinstated, err := h.authService.LiftSuspension(ctx, userID)
+	if err != nil {
+		return nil, suspensionError(err, "lift the suspension of")
+	}
+	return converters.UserToGraph(reinstated), nil
+}
+
+func (h *UsersHandler) GetSuspensionAppeal(ctx context.Context, userID string) (*model.SuspensionAppeal, error) {
+	appeal, err := h.authService.GetSuspensionAppeal(ctx, userID)
+	if err != nil {
+		return nil, suspensionError(err, "read the appeal of")
+	}
+	return appeal, nil
+}
+
+func (h *UsersHandler) AppealSuspension(ctx context.Context, token, message string) (bool, error) {
+	err := h.authService.SubmitSuspensionAppeal(ctx, token, message)
+	switch err {
+	case nil:
+		return true, nil
+	case service.ErrAppealTokenInvalid:
+		return false, errors.NewTypedError("The appeal link is invalid, expired or was already used", model.ErrorTypeToken, nil)
+	case service.ErrNotSuspended:
+		return false, errors.NewTypedError("This account is not suspended", model.ErrorTypeConflict, nil)
+	}
+
+	h.logger.Error("Failed to store a suspension appeal", "error", err)
+	return false, errors.ErrSomethingWentWrong
+}
+
+func suspensionError(err error, action string) error {
+	switch {
+	case err == service.ErrAlreadySuspended:
+		return errors.NewTypedError("User is already suspended", model.ErrorTypeConflict, nil)
+	case err == service.ErrNotSuspended:
+		return errors.NewTypedError("User is not suspended", model.ErrorTypeConflict, nil)
+	case err == errors.UserNotFound || ent.IsNotFound(err):
+		return errors.UserNotFound
+	}
+
+	slog.Error("Failed to "+action+" user", "error", err)
+	return errors.ErrSomethingWentWrong
+}
@@ -2,46 +2,113 @@ package http
 
 import (
 	"context"
-	"log"
+	"log/slog"
+	"time"
 
+	"github.com/abisalde/authentication-service/internal/auth"
 	"github.com/abisalde/authentication-service/internal/auth/cookies"
 	"github.com/abisalde/authentication-service/internal/auth/service"
 	"github.com/abisalde/authentication-service/internal/graph/errors"
 	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/slo"
+	"github.com/abisalde/authentication-service/pkg/jwt"
 )
 
 type TokenHandler struct {
 	authService *service.AuthService
+	logger      *slog.Logger
 }
 
 func NewTokenHandler(authService *service.AuthService) *TokenHandler {
-	return &TokenHandler{authService: authService}
+	return &TokenHandler{authService: authService, logger: authService.Logger()}
 }
 
 func (h *TokenHandler) HandleRefreshToken(
-	ctx context.Context, token string, uid int32,
-) (*model.RefreshTokenResponse, error) {
+	ctx context.Context, token string, uid string,
+) (resp *model.RefreshTokenResponse, err error) {
+	started := time.Now()
+	defer func() {
+		slo.ObserveRefresh(time.Since(started), err == nil, auth.GetRequestID(ctx))
+	}()
 
-	userID := int64(uid)
+	user, err := h.authService.ResolveUserReference(ctx, uid)
+	if err == nil && h.authService.IsRefreshCanary(user, token) {
+		h.authService.TripRefreshCanary(ctx, user)
+		h.authService.RecordRefresh(ctx, false)
+		return nil, errors.InvalidRefreshTokenValidation
+	}
+	if err != nil || service.IsDeleted(user) || service.IsDeactivated(user) {
+		h.authService.RecordRefresh(ctx, false)
+		return nil, errors.InvalidRefreshTokenValidation
+	}
+	userID := user.ID
 
 	ok, err := h.authService.ValidateRefreshToken(ctx, userID, token)
 	if !ok {
-		log.Printf("Error from validating refresh token: %v", err)
+		h.logger.Warn("Failed to validate the refresh token", "error", err)
+		h.authService.RecordRefresh(ctx, false)
+		return nil, errors.InvalidRefreshTokenValidation
+	}
+
+	// A refresh is a use of the session, so it slides the idle timeout too.
+	if err := h.authService.UpdateSessionActivity(ctx, userID); err == service.ErrSessionIdle {
+		h.authService.RecordRefresh(ctx, false)
+		return nil, errors.InvalidRefreshTokenValidation
+	}
+
+	if service.IsSuspended(user) {
+		h.authService.RecordRefresh(ctx, false)
+		return nil, errors.AccountSuspended(service.SuspensionReason(user))
+	}
+
+	switch h.authService.CheckRefreshOrigin(ctx, userID) {
+	case service.ErrReauthRequired:
+		h.authService.RecordRefresh(ctx, false)
+		return nil, errors.ReauthenticationRequired
+	case service.ErrRefreshOriginBlocked:
+		h.authService.RecordRefresh(ctx, false)
+		return nil, errors.InvalidRefreshTokenValidation
+	}
+
+	// A connected app the user revoked keeps its refresh token but no longer
+	// gets access tokens for it.
+	if err := h.authService.CheckConnectedApp(ctx, userID); err == service.ErrConnectedAppRevoked {
+		h.authService.RecordRefresh(ctx, false)
 		return nil, errors.InvalidRefreshTokenValidation
 	}
 
 	err = h.authService.CheckIfRefreshTokenMatchClaims(ctx, userID)
-	log.Printf("h.authService.CheckIfRefreshTokenMatchClaims %v", err)
 	if err != nil {
+		h.logger.Warn("Refresh token does not match its claims", "user_id", userID, "error", err)
+		h.authService.RecordRefresh(ctx, false)
 		return nil, err
 	}
 
-	accessToken, err := cookies.GenerateAccessToken(userID)
+	// Re-evaluated on every refresh, so a downgraded role loses its scopes
+	// with the next access token.
+	scopes, err := h.authService.TokenScopes(ctx, user)
 	if err != nil {
-		log.Printf("Error from generating access token: %v", err)
+		h.logger.Error("Failed to evaluate the token scopes", "user_id", userID, "error", err)
+		h.authService.RecordRefresh(ctx, false)
 		return nil, errors.AccessTokenGeneration
 	}
 
+	accessToken, err := cookies.GenerateAccessToken(ctx, user.PublicID.String(), cookies.DeviceFromContext(ctx), scopes)
+	if err != nil {
+		h.logger.Error("Failed to generate the access token", "user_id", userID, "error", err)
+		h.authService.RecordRefresh(ctx, false)
+		return nil, errors.AccessTokenGeneration
+	}
+	if err := h.authService.TrackSessionToken(ctx, userID, accessToken); err != nil {
+		h.logger.Warn("Failed to track the access token", "user_id", userID, "error", err)
+	}
+	if err := h.authService.RecordAppGrant(ctx, userID, scopes, accessToken); err != nil {
+		h.logger.Warn("Failed to record the connected app", "user_id", userID, "error", err)
+	}
+
+	h.authService.RecordRefresh(ctx, true)
+	h.authService.RecordTokenIssued(ctx, jwt.TokenTypeAccess)
+
 	return &model.RefreshTokenResponse{
 		Token: accessToken,
 	}, nil
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/graph/converters"
+	"github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+func (h *UsersHandler) ReactivateUser(ctx context.Context, userID string) (*model.User, error) {
+	reactivated, err := h.authService.ReactivateUser(ctx, userID)
+	if err != nil {
+		return nil, userManagementError(err, "reactivate")
+	}
+	return converters.UserToGraph(reactivated), nil
+}
+
+func (h *UsersHandler) ForceLogoutUser(ctx context.Context, userID string) (bool, error) {
+	if err := h.authService.ForceLogoutUser(ctx, userID); err != nil {
+		return false, userManagementError(err, "sign out")
+	}
+	return true, nil
+}
+
+func (h *UsersHandler) ChangeUserRole(ctx context.Context, userID string, role model.UserRole) (*model.User, error) {
+	updated, err := h.authService.ChangeUserRole(ctx, auth.GetCurrentUser(ctx), userID, role)
+	if err != nil {
+		return nil, userManagementError(err, "change the role of")
+	}
+	return converters.UserToGraph(updated), nil
+}
+
+func (h *UsersHandler) DeleteUser(ctx context.Context, userID string) (*model.User, error) {
+	deleted, err := h.authService.DeleteUser(ctx, auth.GetCurrentUser(ctx), userID)
+	if err != nil {
+		return nil, userManagementError(err, "delete")
+	}
+	return converters.UserToGraph(deleted), nil
+}
+
+func userManagementError(err error, action string) error {
+	switch {
+	case err == service.ErrAlreadyDeleted:
+		return errors.NewTypedError("User is already deleted", model.ErrorTypeConflict, nil)
+	case err == service.ErrAccountActive:
+		return errors.NewTypedError("User is neither deleted nor suspended", model.ErrorTypeConflict, nil)
+	case err == service.ErrRoleUnchanged:
+		return errors.NewTypedError("User already has this role", model.ErrorTypeConflict, nil)
+	case err == service.ErrManageOwnAccount:
+		return errors.NewTypedError("You cannot delete or change the role of your own account", model.ErrorTypeForbidden, nil)
+	case err == errors.UserNotFound || ent.IsNotFound(err):
+		return errors.UserNotFound
+	}
+
+	slog.Error("Failed to "+action+" user", "error", err)
+	return errors.ErrSomethingWentWrong
+}
@@ -2,17 +2,29 @@ package http
 
 import (
 	"context"
+	"log/slog"
+	"time"
 
+	"github.com/abisalde/authentication-service/internal/auth/repository"
 	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/graph/errors"
 	"github.com/abisalde/authentication-service/internal/graph/model"
 )
 
+const (
+	defaultReloginBatchSize = 500
+	maxReloginBatchSize     = 5000
+	defaultReloginPause     = 100 * time.Millisecond
+	maxReloginPauseMs       = 60000
+)
+
 type UsersHandler struct {
 	authService *service.AuthService
+	logger      *slog.Logger
 }
 
 func NewUsersHandler(authService *service.AuthService) *UsersHandler {
-	return &UsersHandler{authService: authService}
+	return &UsersHandler{authService: authService, logger: authService.Logger()}
 }
 
 func (h *UsersHandler) GetAllUsers(ctx context.Context, role *model.UserRole, first *int, after *string) (*model.UserConnection, error) {
@@ -32,3 +44,57 @@ func (h *UsersHandler) SearchUsernamesAvailability(ctx context.Context, query st
 
 	return h.authService.CheckUsernameAvailability(ctx, query)
 }
+
+func (h *UsersHandler) ForceRelogin(ctx context.Context, input model.ForceReloginInput) (*model.ReloginCampaign, error) {
+	batchSize := defaultReloginBatchSize
+	if input.BatchSize != nil {
+		batchSize = int(*input.BatchSize)
+	}
+	if batchSize < 1 || batchSize > maxReloginBatchSize {
+		return nil, errors.NewTypedError("Batch size must be between 1 and 5000", model.ErrorTypeBadRequest, map[string]interface{}{
+			"field": "batchSize",
+		})
+	}
+
+	pause := defaultReloginPause
+	if input.PauseMs != nil {
+		if *input.PauseMs < 0 || *input.PauseMs > maxReloginPauseMs {
+			return nil, errors.NewTypedError("Pause must be between 0 and 60000 milliseconds", model.ErrorTypeBadRequest, map[string]interface{}{
+				"field": "pauseMs",
+			})
+		}
+		pause = time.Duration(*input.PauseMs) * time.Millisecond
+	}
+
+	cohort := repository.UserCohort{
+		CreatedBefore:   input.CreatedBefore,
+		LastLoginBefore: input.LastLoginBefore,
+		Role:            input.Role,
+		Provider:        input.Provider,
+	}
+
+	dryRun := input.DryRun != nil && *input.DryRun
+
+	campaign, err := h.authService.StartReloginCampaign(ctx, cohort, batchSize, pause, dryRun)
+	if err == service.ErrTooManyCampaigns {
+		return nil, errors.NewTypedError("Too many re-login campaigns are queued, try again later", model.ErrorTypeRateLimited, nil)
+	}
+	if err != nil {
+		h.logger.Error("Failed to start a relogin campaign", "error", err)
+		return nil, errors.ErrSomethingWentWrong
+	}
+
+	return campaign, nil
+}
+
+func (h *UsersHandler) GetReloginCampaign(ctx context.Context, id string) (*model.ReloginCampaign, error) {
+	campaign, err := h.authService.GetReloginCampaign(ctx, id)
+	if err == service.ErrReloginCampaignNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		h.logger.Error("Failed to read a relogin campaign", "campaign", id, "error", err)
+		return nil, errors.ErrSomethingWentWrong
+	}
+	return campaign, nil
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+func (h *UsersHandler) SendTestWebhook(ctx context.Context) (*model.WebhookDelivery, error) {
+	delivery, err := h.authService.SendTestWebhook(ctx)
+	if err != nil {
+		return nil, webhookError(err, "send test webhook")
+	}
+	return webhookDeliveryToGraph(*delivery), nil
+}
+
+func (h *UsersHandler) RedeliverWebhook(ctx context.Context, id string) (*model.WebhookDelivery, error) {
+	delivery, err := h.authService.RedeliverWebhook(ctx, id)
+	if err != nil {
+		return nil, webhookError(err, "redeliver webhook")
+	}
+	return webhookDeliveryToGraph(*delivery), nil
+}
+
+func (h *UsersHandler) ListWebhookDeliveries(ctx context.Context, limit int) ([]*model.WebhookDelivery, error) {
+	deliveries, err := h.authService.ListWebhookDeliveries(ctx, limit)
+	if err != nil {
+		return nil, webhookError(err, "list webhook deliveries")
+	}
+
+	result := make([]*model.WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		result = append(result, webhookDeliveryToGraph(delivery))
+	}
+	return result, nil
+}
+
+func webhookDeliveryToGraph(delivery service.WebhookDelivery) *model.WebhookDelivery {
+	result := &model.WebhookDelivery{
+		ID:          delivery.ID,
+		EventType:   delivery.EventType,
+		URL:         delivery.URL,
+		LatencyMs:   int32(delivery.Latency.Milliseconds()),
+		Test:        delivery.Test,
+		DeliveredAt: delivery.DeliveredAt,
+		Payload:     delivery.Payload,
+	}
+	if delivery.StatusCode != 0 {
+		status := int32(delivery.StatusCode)
+		result.StatusCode = &status
+	}
+	if delivery.Error != "" {
+		result.Error = &delivery.Error
+	}
+	if delivery.RedeliveryOf != "" {
+		result.RedeliveryOf = &delivery.RedeliveryOf
+	}
+	return result
+}
+
+func webhookError(err error, action string) error {
+	switch err {
+	case service.ErrWebhookNotConfigured:
+		return errors.NewTypedError("No webhook is configured", model.ErrorTypeConflict, nil)
+	case service.ErrWebhookDeliveryNotFound:
+		return errors.NewTypedError("Webhook delivery not found", model.ErrorTypeNotFound, nil)
+	}
+
+	slog.Error("Failed to "+action, "error", err)
+	return errors.ErrSomethingWentWrong
+}
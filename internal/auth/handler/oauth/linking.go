@@ -0,0 +1,81 @@
+package oauth
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/graph/converters"
+	"github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/google/uuid"
+)
+
+func (h *OAuthHandler) LinkOAuthAccount(ctx context.Context, input model.LinkOAuthAccountInput) (*model.PasswordLessResponse, error) {
+	currentUser := auth.GetCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, errors.AuthenticationRequired
+	}
+
+	provider, err := providerOf(input.Provider, input.ProviderName)
+	if err != nil {
+		return nil, errors.NewTypedError(err.Error(), model.ErrorTypeBadRequest, nil)
+	}
+
+	stateUUID := uuid.NewString()
+	authURL, state, err := h.oauthService.StartLink(ctx, currentUser, provider, input.Platform, stateUUID)
+	if err != nil {
+		return nil, linkError(err)
+	}
+
+	return startResponse(ctx, input.Platform, authURL, state, stateUUID), nil
+}
+
+func (h *OAuthHandler) LinkedAccounts(ctx context.Context) ([]*model.LinkedAccount, error) {
+	currentUser := auth.GetCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, errors.AuthenticationRequired
+	}
+
+	identities, err := h.oauthService.LinkedAccounts(ctx, currentUser)
+	if err != nil {
+		return nil, linkError(err)
+	}
+	return converters.LinkedAccountsToGraph(identities), nil
+}
+
+func (h *OAuthHandler) UnlinkOAuthAccount(ctx context.Context, id string) ([]*model.LinkedAccount, error) {
+	currentUser := auth.GetCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, errors.AuthenticationRequired
+	}
+
+	identityID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, linkError(service.ErrIdentityNotFound)
+	}
+
+	remaining, err := h.oauthService.UnlinkOAuthAccount(ctx, currentUser, identityID)
+	if err != nil {
+		return nil, linkError(err)
+	}
+	return converters.LinkedAccountsToGraph(remaining), nil
+}
+
+func linkError(err error) error {
+	switch err {
+	case service.ErrIdentityNotFound:
+		return errors.NewTypedError("Linked account not found", model.ErrorTypeNotFound, nil)
+	case service.ErrLastSignInMethod:
+		return errors.NewTypedError("Set a password or link another account before unlinking this one", model.ErrorTypeConflict, nil)
+	case service.ErrServiceAccount:
+		return errors.NewTypedError("Service accounts authenticate with keys only", model.ErrorTypeForbidden, nil)
+	case errors.ErrSomethingWentWrong:
+		return err
+	}
+
+	slog.Error("Failed to manage linked accounts", "error", err)
+	return errors.ErrSomethingWentWrong
+}
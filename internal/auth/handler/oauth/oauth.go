@@ -3,8 +3,8 @@ package oauth
 import (
 	"context"
 	"errors"
-	"log"
-	"os"
+	"log/slog"
+	"sort"
 	"strings"
 	"time"
 
@@ -13,20 +13,21 @@ import (
 	"github.com/abisalde/authentication-service/internal/auth/service"
 	"github.com/abisalde/authentication-service/internal/graph/model"
 	oauthPKCE "github.com/abisalde/authentication-service/pkg/oauth"
+	"github.com/abisalde/authentication-service/pkg/verification"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
 
 type OAuthHandler struct {
 	oauthService *service.OAuthService
+	logger       *slog.Logger
 }
 
 func NewOAuthHandler(oauthService *service.OAuthService) *OAuthHandler {
-	return &OAuthHandler{oauthService: oauthService}
+	return &OAuthHandler{oauthService: oauthService, logger: oauthService.Logger()}
 }
 
 func (h *OAuthHandler) InitOAuth(ctx context.Context, input model.OAuthLoginInput) (*model.PasswordLessResponse, error) {
-	isProd := os.Getenv("APP_ENV") == "production"
 	stateUUID := uuid.NewString()
 
 	ctx = context.WithValue(ctx, auth.OAuthModeKey, input.Mode)
@@ -40,11 +41,33 @@ func (h *OAuthHandler) InitOAuth(ctx context.Context, input model.OAuthLoginInpu
 
 	mode, _ := ctx.Value(auth.OAuthModeKey).(model.PasswordLessMode)
 
-	authURL, state, err := h.oauthService.GetAuthPKCEURL(ctx, string(input.Provider), platform, stateUUID, mode)
+	provider, err := providerOf(input.Provider, input.ProviderName)
 	if err != nil {
 		return nil, err
 	}
 
+	authURL, state, err := h.oauthService.GetAuthPKCEURL(ctx, provider, platform, stateUUID, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	return startResponse(ctx, platform, authURL, state, stateUUID), nil
+}
+
+// providerOf picks the provider named by an OAuth input.
+func providerOf(builtin *model.OAuthProvider, name *string) (string, error) {
+	switch {
+	case name != nil:
+		return *name, nil
+	case builtin != nil:
+		return string(*builtin), nil
+	}
+	return "", errors.New("provider or providerName is required")
+}
+
+// startResponse hands the authorization URL to the client and keeps the
+// state for the callback: in a cookie on the web, returned on mobile.
+func startResponse(ctx context.Context, platform model.OAuthPlatform, authURL, state, stateUUID string) *model.PasswordLessResponse {
 	ctx = context.WithValue(ctx, auth.OAuthStateKey, state)
 	ctx = context.WithValue(ctx, auth.OAuthUUIDKey, stateUUID)
 	if fiberCtx, ok := ctx.Value(auth.FiberContextWeb).(*fiber.Ctx); ok {
@@ -64,7 +87,7 @@ func (h *OAuthHandler) InitOAuth(ctx context.Context, input model.OAuthLoginInpu
 		if fiberCtx, ok := ctx.Value(auth.FiberContextWeb).(*fiber.Ctx); ok {
 			fiberCtx.Set("Cross-Origin-Opener-Policy", "same-origin-allow-popups")
 			fiberCtx.Cookie(&fiber.Cookie{
-				Secure:   isProd,
+				Secure:   cookies.SecureCookies(),
 				Name:     string(auth.OAuthUUIDKey),
 				Value:    stateUUID,
 				HTTPOnly: true,
@@ -73,11 +96,11 @@ func (h *OAuthHandler) InitOAuth(ctx context.Context, input model.OAuthLoginInpu
 				MaxAge:   int((11 * time.Minute).Seconds()),
 			})
 		} else {
-			log.Printf("⚠️ Fiber context not available, can't set cookie for state")
+			slog.Warn("Fiber context not available, cannot set the OAuth state cookie")
 		}
 	}
 
-	return response, nil
+	return response
 }
 
 func (h *OAuthHandler) UnifiedOauthCallBack(c *fiber.Ctx) error {
@@ -87,12 +110,13 @@ func (h *OAuthHandler) UnifiedOauthCallBack(c *fiber.Ctx) error {
 		cookiesStateUUID string
 	)
 
+	// Apple posts the callback as a form; the other providers redirect with
+	// a query string. FormValue reads either.
 	provider := strings.ToLower(c.Params("provider"))
-	state := c.Query("state")
-	code := c.Query("code")
+	state := c.FormValue("state")
+	code := c.FormValue("code")
 
 	if val := c.Locals(auth.OAuthStateKey); val != nil {
-		log.Printf("Expected state from Locals: %s", expectedState)
 		expectedState = val.(string)
 	}
 
@@ -103,7 +127,6 @@ func (h *OAuthHandler) UnifiedOauthCallBack(c *fiber.Ctx) error {
 
 	platform = model.OAuthPlatformWeb
 	if val := c.Locals(auth.OAuthPlatformKey); val != nil {
-		log.Printf("Expected auth.OAuthPlatformKey from Locals: %s", val)
 		if platformStr, ok := val.(string); ok {
 			switch platformStr {
 			case string(model.OAuthPlatformMobile):
@@ -111,19 +134,19 @@ func (h *OAuthHandler) UnifiedOauthCallBack(c *fiber.Ctx) error {
 			case string(model.OAuthPlatformWeb):
 				platform = model.OAuthPlatformWeb
 			default:
-				log.Printf("Unknown platform override: %s. Keeping default: %s", platformStr, platform)
+				h.logger.Warn("Unknown OAuth platform override, keeping the default", "override", platformStr, "platform", platform)
 			}
 		}
 	}
 
-	if state != expectedState || state == "" {
+	if state == "" || !verification.Equal(state, expectedState) {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error":   "Invalid Authentication",
 			"message": "Please try again with your request",
 		})
 	}
 
-	log.Printf("This is the state UUID %s", cookiesStateUUID)
+	h.logger.Debug("OAuth callback", "provider", provider, "platform", platform, "state_cookie", cookiesStateUUID != "")
 
 	stateUUID, platformKey, mode, err := oauthPKCE.DecodeState(state)
 	if err != nil {
@@ -161,14 +184,14 @@ func (h *OAuthHandler) UnifiedOauthCallBack(c *fiber.Ctx) error {
 			}
 		}
 		c.Cookies(string(auth.OAuthUUIDKey), "")
-		redirectURL := h.oauthService.GetFrontEndRedirectURL(platform, tokens.AccessToken, tokens.RefreshToken, user.Email)
+		redirectURL := h.oauthService.GetFrontEndRedirectURL(platform, tokens.AccessToken, tokens.RefreshToken, user.Email, tokens.IDToken)
 		c.Set("Cross-Origin-Opener-Policy", "same-origin-allow-popups")
-		return c.Redirect(redirectURL, fiber.StatusTemporaryRedirect)
+		return c.Redirect(redirectURL, redirectStatus(c))
 	}
 
 	if platform == model.OAuthPlatformMobile {
-		redirectURL := h.oauthService.GetFrontEndRedirectURL(platform, tokens.AccessToken, tokens.RefreshToken, user.Email)
-		return c.Redirect(redirectURL, fiber.StatusTemporaryRedirect)
+		redirectURL := h.oauthService.GetFrontEndRedirectURL(platform, tokens.AccessToken, tokens.RefreshToken, user.Email, tokens.IDToken)
+		return c.Redirect(redirectURL, redirectStatus(c))
 	}
 
 	return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
@@ -176,3 +199,41 @@ func (h *OAuthHandler) UnifiedOauthCallBack(c *fiber.Ctx) error {
 		"message": "Unable to process the request at this time.",
 	})
 }
+
+// redirectStatus answers form posted callbacks with 303 so the browser
+// follows with a GET instead of posting the form to the app.
+func redirectStatus(c *fiber.Ctx) int {
+	if c.Method() == fiber.MethodPost {
+		return fiber.StatusSeeOther
+	}
+	return fiber.StatusTemporaryRedirect
+}
+
+func (h *OAuthHandler) ProviderHealth(ctx context.Context) ([]*model.OAuthProviderHealth, error) {
+	health := h.oauthService.Health()
+	windowSeconds := int32(health.Window().Seconds())
+
+	statuses := health.Statuses()
+	result := make([]*model.OAuthProviderHealth, 0, len(statuses))
+	for _, status := range statuses {
+		counts := make([]*model.OAuthErrorCount, 0, len(status.Errors))
+		for category, count := range status.Errors {
+			counts = append(counts, &model.OAuthErrorCount{Category: string(category), Count: int32(count)})
+		}
+		sort.Slice(counts, func(i, j int) bool { return counts[i].Category < counts[j].Category })
+
+		result = append(result, &model.OAuthProviderHealth{
+			Provider:      status.Provider,
+			WindowSeconds: windowSeconds,
+			Attempts:      int32(status.Attempts),
+			Failures:      int32(status.Failures),
+			FailureRate:   status.FailureRate,
+			AvgLatencyMs:  int32(status.AvgLatency.Milliseconds()),
+			P95LatencyMs:  int32(status.P95Latency.Milliseconds()),
+			Errors:        counts,
+			Degraded:      status.Degraded,
+		})
+	}
+
+	return result, nil
+}
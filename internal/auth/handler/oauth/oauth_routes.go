@@ -5,9 +5,18 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func (h *OAuthHandler) RegisterRoutes(appService *fiber.App) {
+// RegisterRoutes mounts the provider callbacks behind signInLimit, the rate
+// limit they share with the other ways to sign in.
+func (h *OAuthHandler) RegisterRoutes(appService *fiber.App, signInLimit fiber.Handler) {
 	oauthGroup := appService.Group("/service/oauth")
 	oauthGroup.Get("/:provider/callback",
+		signInLimit,
+		middleware.OAuthStateMiddleware(),
+		h.UnifiedOauthCallBack,
+	)
+	// Sign in with Apple uses response_mode=form_post.
+	oauthGroup.Post("/:provider/callback",
+		signInLimit,
 		middleware.OAuthStateMiddleware(),
 		h.UnifiedOauthCallBack,
 	)
@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/abisalde/authentication-service/internal/auth/maintenance"
+	"github.com/abisalde/authentication-service/internal/auth/reputation"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+)
+
+var (
+	// ClaimsKey holds the validated claims of the request's access token.
+	ClaimsKey = contextKey("validatedTokenClaims")
+	// RevocationKey holds why a presented token was rejected as revoked.
+	RevocationKey = contextKey("tokenRevocationReason")
+	// MaintenanceGrantKey holds the grant of a break-glass maintenance token.
+	MaintenanceGrantKey = contextKey("maintenanceGrant")
+	// IPReputationKey holds the reputation assessment of the client IP.
+	IPReputationKey = contextKey("clientIPReputation")
+)
+
+// Identity is everything the middleware stacks learned about the caller.
+// The net/http auth middleware and the Fiber middlewares write it field by
+// field through the With* helpers; handlers and resolvers read it in one go
+// with IdentityFrom instead of probing individual context keys.
+type Identity struct {
+	// User is nil for anonymous requests and rejected tokens.
+	User   *ent.User
+	Claims *jwt.Claims
+	// Token is the raw bearer or cookie token, set even when it failed
+	// validation so logout can still blacklist it.
+	Token string
+	IP    string
+	// CookieSession is set when the request authenticated with cookies.
+	CookieSession    string
+	CSRFToken        string
+	AutomationClient string
+	// Revocation is the reason code when the token was valid but revoked.
+	Revocation string
+	// Maintenance is set when the token is a break-glass maintenance token,
+	// which may only call the grant's operations.
+	Maintenance *maintenance.Grant
+}
+
+// Authenticated reports whether the request carries a valid access token for
+// a known user.
+func (i Identity) Authenticated() bool {
+	return i.User != nil
+}
+
+// ViaCookie reports whether the request authenticated with browser cookies
+// rather than an Authorization header.
+func (i Identity) ViaCookie() bool {
+	return i.CookieSession != ""
+}
+
+// IdentityFrom collects the caller's identity from ctx. The individual
+// getters remain for existing callers and read the same keys.
+func IdentityFrom(ctx context.Context) Identity {
+	session, _ := GetCookieSession(ctx)
+	return Identity{
+		User:             GetCurrentUser(ctx),
+		Claims:           GetClaims(ctx),
+		Token:            GetToken(ctx),
+		IP:               GetIPFromContext(ctx),
+		CookieSession:    session,
+		CSRFToken:        GetCSRFToken(ctx),
+		AutomationClient: GetAutomationClient(ctx),
+		Revocation:       GetRevocation(ctx),
+		Maintenance:      GetMaintenanceGrant(ctx),
+	}
+}
+
+// WithToken records the raw token presented by the caller.
+func WithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, JWTTokenKey, token)
+}
+
+// WithUser records the authenticated user together with the claims of the
+// access token that identified them.
+func WithUser(ctx context.Context, user *ent.User, claims *jwt.Claims) context.Context {
+	ctx = context.WithValue(ctx, CurrentUserKey, user)
+	return context.WithValue(ctx, ClaimsKey, claims)
+}
+
+// WithCookieSession records the browser session binding and the CSRF token
+// sent alongside it.
+func WithCookieSession(ctx context.Context, session, csrfToken string) context.Context {
+	ctx = context.WithValue(ctx, CookieSessionKey, session)
+	return context.WithValue(ctx, CSRFTokenKey, csrfToken)
+}
+
+// WithClientIP records the caller's address as resolved by Fiber, which
+// honours the configured proxy headers.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, ClientIPKey, ip)
+}
+
+// WithAutomationClient records the trusted automation client behind the
+// request.
+func WithAutomationClient(ctx context.Context, client string) context.Context {
+	return context.WithValue(ctx, AutomationClientKey, client)
+}
+
+// WithIntrospectionClient records the resource server behind an
+// introspection request.
+func WithIntrospectionClient(ctx context.Context, client string) context.Context {
+	return context.WithValue(ctx, IntrospectionClientKey, client)
+}
+
+// WithRevocation records that the presented token was revoked and why, so
+// the @auth directive can tell the client instead of a bare
+// authentication error.
+func WithRevocation(ctx context.Context, reason string) context.Context {
+	return context.WithValue(ctx, RevocationKey, reason)
+}
+
+// WithMaintenanceGrant records the grant behind a maintenance token.
+func WithMaintenanceGrant(ctx context.Context, grant *maintenance.Grant) context.Context {
+	return context.WithValue(ctx, MaintenanceGrantKey, grant)
+}
+
+// WithIPReputation records the reputation assessment of the client IP.
+func WithIPReputation(ctx context.Context, assessment *reputation.Assessment) context.Context {
+	return context.WithValue(ctx, IPReputationKey, assessment)
+}
+
+// GetClaims returns the validated access token claims, or nil when the
+// request is not authenticated.
+func GetClaims(ctx context.Context) *jwt.Claims {
+	if claims, ok := ctx.Value(ClaimsKey).(*jwt.Claims); ok {
+		return claims
+	}
+	return nil
+}
+
+// GetToken returns the raw token presented by the caller, validated or not.
+func GetToken(ctx context.Context) string {
+	if token, ok := ctx.Value(JWTTokenKey).(string); ok {
+		return token
+	}
+	return ""
+}
+
+// GetRevocation returns the revocation reason of the presented token, or an
+// empty string when it was not revoked.
+func GetRevocation(ctx context.Context) string {
+	if reason, ok := ctx.Value(RevocationKey).(string); ok {
+		return reason
+	}
+	return ""
+}
+
+// GetMaintenanceGrant returns the grant of a maintenance token, or nil for
+// regular tokens.
+func GetMaintenanceGrant(ctx context.Context) *maintenance.Grant {
+	if grant, ok := ctx.Value(MaintenanceGrantKey).(*maintenance.Grant); ok {
+		return grant
+	}
+	return nil
+}
+
+// GetIPReputation returns the reputation assessment of the client IP, or nil
+// when no feed was consulted.
+func GetIPReputation(ctx context.Context) *reputation.Assessment {
+	if assessment, ok := ctx.Value(IPReputationKey).(*reputation.Assessment); ok {
+		return assessment
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package maintenance
+
+import (
+	"slices"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+)
+
+type Status string
+
+const (
+	StatusPending  Status = "PENDING"
+	StatusApproved Status = "APPROVED"
+	StatusIssued   Status = "ISSUED"
+)
+
+const (
+	DefaultTokenTTL       = 15 * time.Minute
+	DefaultApprovalWindow = time.Hour
+	DefaultApprovals      = 2
+)
+
+// reserved operations can never be granted, so a maintenance token cannot
+// approve or mint further maintenance tokens.
+var reserved = map[string]bool{
+	"requestMaintenanceToken": true,
+	"approveMaintenanceToken": true,
+	"issueMaintenanceToken":   true,
+}
+
+// Grant is a break-glass request of an operator. Once enough admins approved
+// it, the operator mints one token that may call Operations until ExpiresAt.
+type Grant struct {
+	ID         string   `json:"id"`
+	OperatorID int64    `json:"operator_id"`
+	Operator   string   `json:"operator"`
+	Operations []string `json:"operations"`
+	Reason     string   `json:"reason"`
+	// OrganizationID, when set, limits the token to the users and resources
+	// of one organization, whose slug is Organization.
+	OrganizationID *int64    `json:"organization_id,omitempty"`
+	Organization   string    `json:"organization,omitempty"`
+	Status         Status    `json:"status"`
+	ApprovedBy     []int64   `json:"approved_by,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	// ExpiresAt ends the approval window, and once issued the token.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Allows reports whether the grant covers the admin operation.
+func (g *Grant) Allows(operation string) bool {
+	return g.Status == StatusIssued && time.Now().Before(g.ExpiresAt) && slices.Contains(g.Operations, operation)
+}
+
+func (g *Grant) ApprovedByUser(userID int64) bool {
+	return slices.Contains(g.ApprovedBy, userID)
+}
+
+// Policy lists the admin operations operators may request and how grants
+// are approved.
+type Policy struct {
+	TokenTTL       time.Duration
+	ApprovalWindow time.Duration
+	Approvals      int
+	operations     map[string]bool
+}
+
+func NewPolicy(cfg *configs.Config) Policy {
+	policy := Policy{
+		TokenTTL:       DefaultTokenTTL,
+		ApprovalWindow: DefaultApprovalWindow,
+		Approvals:      DefaultApprovals,
+		operations:     map[string]bool{},
+	}
+	if cfg == nil {
+		return policy
+	}
+
+	m := cfg.Maintenance
+	if m.TokenTTL > 0 && m.TokenTTL < DefaultTokenTTL {
+		policy.TokenTTL = m.TokenTTL
+	}
+	if m.ApprovalWindow > 0 {
+		policy.ApprovalWindow = m.ApprovalWindow
+	}
+	if m.Approvals > DefaultApprovals {
+		policy.Approvals = m.Approvals
+	}
+	for _, operation := range m.Operations {
+		if !reserved[operation] {
+			policy.operations[operation] = true
+		}
+	}
+
+	return policy
+}
+
+func (p Policy) Enabled() bool {
+	return len(p.operations) > 0
+}
+
+// Grantable reports whether operators may request the operation.
+func (p Policy) Grantable(operation string) bool {
+	return p.operations[operation]
+}
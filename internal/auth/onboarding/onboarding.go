@@ -0,0 +1,118 @@
+package onboarding
+
+import (
+	"strings"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+)
+
+type Step string
+
+const (
+	StepVerifyEmail     Step = "VERIFY_EMAIL"
+	StepCompleteProfile Step = "COMPLETE_PROFILE"
+	StepAcceptTerms     Step = "ACCEPT_TERMS"
+	StepDone            Step = "DONE"
+)
+
+// stepOrder is the order the state machine walks through:
+// email verified → profile completed → terms accepted → done.
+var stepOrder = []Step{StepVerifyEmail, StepCompleteProfile, StepAcceptTerms}
+
+// Policy decides which onboarding steps a deployment requires and
+// whether incomplete users are gated from the application.
+type Policy struct {
+	Enforce               bool
+	RequireAtRegistration bool
+	required              map[Step]bool
+}
+
+func NewPolicy(cfg *configs.Config) Policy {
+	policy := Policy{required: make(map[Step]bool)}
+	if cfg == nil {
+		return policy
+	}
+
+	policy.Enforce = cfg.Onboarding.Enforce
+	policy.RequireAtRegistration = cfg.Onboarding.RequireAtRegistration
+
+	for _, s := range cfg.Onboarding.RequiredSteps {
+		step := Step(strings.ToUpper(strings.TrimSpace(s)))
+		if step.IsValid() {
+			policy.required[step] = true
+		}
+	}
+
+	return policy
+}
+
+func (s Step) IsValid() bool {
+	for _, step := range stepOrder {
+		if s == step {
+			return true
+		}
+	}
+	return false
+}
+
+// Requires reports whether the deployment requires the given step.
+func (p Policy) Requires(step Step) bool {
+	return p.required[step]
+}
+
+// RequiredSteps returns the configured steps in state machine order.
+func (p Policy) RequiredSteps() []Step {
+	steps := make([]Step, 0, len(p.required))
+	for _, step := range stepOrder {
+		if p.required[step] {
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+// NextStep returns the first required step the user has not satisfied,
+// or StepDone once every required step is complete.
+func (p Policy) NextStep(u *ent.User) Step {
+	for _, step := range stepOrder {
+		if p.required[step] && !IsStepCompleted(u, step) {
+			return step
+		}
+	}
+	return StepDone
+}
+
+// CompletedSteps returns every step the user has satisfied, required or not.
+func (p Policy) CompletedSteps(u *ent.User) []Step {
+	steps := make([]Step, 0, len(stepOrder))
+	for _, step := range stepOrder {
+		if IsStepCompleted(u, step) {
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+func (p Policy) IsComplete(u *ent.User) bool {
+	return p.NextStep(u) == StepDone
+}
+
+func IsStepCompleted(u *ent.User, step Step) bool {
+	if u == nil {
+		return false
+	}
+
+	switch step {
+	case StepVerifyEmail:
+		return u.IsEmailVerified
+	case StepCompleteProfile:
+		return strings.TrimSpace(u.FirstName) != "" && strings.TrimSpace(u.LastName) != ""
+	case StepAcceptTerms:
+		return u.TermsAcceptedAt != nil
+	case StepDone:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,58 @@
+package probation
+
+import (
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+)
+
+// Policy applies extra restrictions to accounts younger than Window, to
+// limit abuse from throwaway registrations. A zero Window disables it.
+type Policy struct {
+	Window               time.Duration
+	RateLimitDivisor     int
+	RequireVerifiedEmail bool
+	MaxSessions          int
+}
+
+func NewPolicy(cfg *configs.Config) Policy {
+	if cfg == nil {
+		return Policy{}
+	}
+
+	return Policy{
+		Window:               cfg.Probation.Window,
+		RateLimitDivisor:     cfg.Probation.RateLimitDivisor,
+		RequireVerifiedEmail: cfg.Probation.RequireVerifiedEmail,
+		MaxSessions:          cfg.Probation.MaxSessions,
+	}
+}
+
+// Applies reports whether the user is still within the probation window.
+func (p Policy) Applies(u *ent.User) bool {
+	return p.Window > 0 && u != nil && time.Now().Before(p.EndsAt(u))
+}
+
+func (p Policy) EndsAt(u *ent.User) time.Time {
+	return u.CreatedAt.Add(p.Window)
+}
+
+// RateLimit divides a limit for users on probation, never below one request.
+func (p Policy) RateLimit(u *ent.User, limit int64) int64 {
+	if p.RateLimitDivisor <= 1 || !p.Applies(u) {
+		return limit
+	}
+	return max(limit/int64(p.RateLimitDivisor), 1)
+}
+
+// BlocksUnverified reports whether the user may not receive login tokens
+// until their email is verified.
+func (p Policy) BlocksUnverified(u *ent.User) bool {
+	return p.RequireVerifiedEmail && p.Applies(u) && !u.IsEmailVerified
+}
+
+// LimitsSessions reports whether the user's concurrent sessions are capped.
+func (p Policy) LimitsSessions(u *ent.User) bool {
+	return p.MaxSessions > 0 && p.Applies(u)
+}
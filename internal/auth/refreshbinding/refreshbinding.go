@@ -0,0 +1,150 @@
+package refreshbinding
+
+import (
+	"net/netip"
+	"strings"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/abisalde/authentication-service/pkg/useragent"
+)
+
+type Mode string
+
+const (
+	ModeOff    Mode = "off"
+	ModeLog    Mode = "log"
+	ModeStepUp Mode = "step_up"
+	ModeBlock  Mode = "block"
+)
+
+// Mismatch kinds, used as the metrics label.
+const (
+	MismatchNetwork   = "network"
+	MismatchUserAgent = "user_agent"
+)
+
+const (
+	defaultIPv4Prefix = 16
+	defaultIPv6Prefix = 48
+	maxFamilyLength   = 32
+)
+
+// Origin is where a refresh token was issued to or presented from, coarse
+// enough that mobile carriers and browser updates do not change it.
+type Origin struct {
+	Network  string `json:"network,omitempty"`
+	UAFamily string `json:"uaFamily,omitempty"`
+}
+
+// Policy compares the origin of a refresh request with the one recorded
+// when the token was issued.
+type Policy struct {
+	Mode       Mode
+	Network    bool
+	UserAgent  bool
+	ipv4Prefix int
+	ipv6Prefix int
+	allowed    []netip.Prefix
+}
+
+func NewPolicy(cfg *configs.Config) Policy {
+	policy := Policy{Mode: ModeOff, ipv4Prefix: defaultIPv4Prefix, ipv6Prefix: defaultIPv6Prefix}
+	if cfg == nil {
+		return policy
+	}
+
+	binding := cfg.RefreshBinding
+	if binding.Mode != "" {
+		policy.Mode = Mode(binding.Mode)
+	}
+	policy.Network = binding.Network
+	policy.UserAgent = binding.UserAgent
+	if binding.IPv4Prefix > 0 && binding.IPv4Prefix <= 32 {
+		policy.ipv4Prefix = binding.IPv4Prefix
+	}
+	if binding.IPv6Prefix > 0 && binding.IPv6Prefix <= 128 {
+		policy.ipv6Prefix = binding.IPv6Prefix
+	}
+	for _, network := range binding.AllowedNetworks {
+		if prefix, err := netip.ParsePrefix(network); err == nil {
+			policy.allowed = append(policy.allowed, prefix.Masked())
+		}
+	}
+
+	return policy
+}
+
+func (p Policy) Enabled() bool {
+	return p.Mode != ModeOff && (p.Network || p.UserAgent)
+}
+
+// OriginOf reduces an IP to its network prefix and a user agent to its
+// family.
+func (p Policy) OriginOf(ip, userAgent string) Origin {
+	origin := Origin{UAFamily: UAFamily(userAgent)}
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return origin
+	}
+	addr = addr.Unmap()
+
+	bits := p.ipv6Prefix
+	if addr.Is4() {
+		bits = p.ipv4Prefix
+	}
+	if prefix, err := addr.Prefix(bits); err == nil {
+		origin.Network = prefix.String()
+	}
+	return origin
+}
+
+// Mismatches lists what differs between the issuing and the current origin.
+// An unknown side never mismatches, so tokens issued before the binding was
+// enabled keep refreshing.
+func (p Policy) Mismatches(issued, current Origin, currentIP string) []string {
+	var kinds []string
+
+	if p.Network && issued.Network != "" && current.Network != "" && issued.Network != current.Network && !p.isAllowed(currentIP) {
+		kinds = append(kinds, MismatchNetwork)
+	}
+	if p.UserAgent && issued.UAFamily != "" && current.UAFamily != "" && issued.UAFamily != current.UAFamily {
+		kinds = append(kinds, MismatchUserAgent)
+	}
+
+	return kinds
+}
+
+func (p Policy) isAllowed(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range p.allowed {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// UAFamily is the browser or client family and the platform, without
+// versions, e.g. "chrome/windows" or "okhttp/other".
+func UAFamily(userAgent string) string {
+	if strings.TrimSpace(userAgent) == "" {
+		return ""
+	}
+
+	family := useragent.Parse(userAgent).Family()
+	if family == "" {
+		family, _, _ = strings.Cut(strings.ToLower(userAgent), "/")
+		family = strings.TrimSpace(family)
+	}
+	if len(family) > maxFamilyLength {
+		family = family[:maxFamilyLength]
+	}
+
+	return family + "/" + jwt.DetectPlatform(userAgent)
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/database/ent/useridentity"
+)
+
+// Identity is an OAuth account to link, keyed by the user.provider value
+// and oauth_id the provider registry stores it under.
+type Identity struct {
+	Provider string
+	OAuthID  string
+	Email    string
+}
+
+// GetIdentity looks a linked OAuth account up, with the user it belongs to.
+func (r *userRepository) GetIdentity(ctx context.Context, provider, oauthID string) (*ent.UserIdentity, error) {
+	return r.client.UserIdentity.Query().
+		Where(
+			useridentity.ProviderEQ(useridentity.Provider(provider)),
+			useridentity.OauthIDEQ(oauthID),
+		).
+		WithOwner().
+		Only(ctx)
+}
+
+// LinkIdentity links an OAuth account to the user. The unique index refuses
+// an account that is linked already.
+func (r *userRepository) LinkIdentity(ctx context.Context, userID int64, identity Identity) (*ent.UserIdentity, error) {
+	return r.client.UserIdentity.Create().
+		SetUserID(userID).
+		SetProvider(useridentity.Provider(identity.Provider)).
+		SetOauthID(identity.OAuthID).
+		SetEmail(identity.Email).
+		Save(ctx)
+}
+
+// ListIdentities returns the OAuth accounts linked to the user, oldest
+// first.
+func (r *userRepository) ListIdentities(ctx context.Context, userID int64) ([]*ent.UserIdentity, error) {
+	return r.client.UserIdentity.Query().
+		Where(useridentity.UserIDEQ(userID)).
+		Order(ent.Asc(useridentity.FieldID)).
+		All(ctx)
+}
+
+// DeleteIdentity unlinks one OAuth account of the user and reports whether
+// it was linked.
+func (r *userRepository) DeleteIdentity(ctx context.Context, userID, identityID int64) (bool, error) {
+	deleted, err := r.client.UserIdentity.Delete().
+		Where(useridentity.UserIDEQ(userID), useridentity.IDEQ(identityID)).
+		Exec(ctx)
+	return deleted > 0, err
+}
+
+func (r *userRepository) TouchIdentity(ctx context.Context, provider, oauthID string, usedAt time.Time) error {
+	return r.client.UserIdentity.Update().
+		Where(
+			useridentity.ProviderEQ(useridentity.Provider(provider)),
+			useridentity.OauthIDEQ(oauthID),
+		).
+		SetLastUsedAt(usedAt).
+		Exec(ctx)
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/database/ent/loginattempt"
+	"github.com/abisalde/authentication-service/pkg/useragent"
+)
+
+// LoginAttemptRecord is one sign-in to persist. UserID is nil when the email
+// does not belong to an account.
+type LoginAttemptRecord struct {
+	UserID        *int64
+	Email         string
+	IP            string
+	DeviceID      string
+	Platform      string
+	UserAgent     string
+	ClientHints   useragent.ClientHints
+	Method        loginattempt.Method
+	Outcome       loginattempt.Outcome
+	FailureReason *loginattempt.FailureReason
+	RiskScore     int
+}
+
+// LoginAttemptFilter narrows login history listings. Zero values match
+// everything.
+type LoginAttemptFilter struct {
+	UserID  *int64
+	Email   string
+	IP      string
+	Outcome *loginattempt.Outcome
+	Since   *time.Time
+	Until   *time.Time
+}
+
+// LoginRiskSignals are the facts the risk score is derived from.
+type LoginRiskSignals struct {
+	RecentEmailFailures int
+	RecentIPFailures    int
+	HasPriorSuccess     bool
+	KnownIP             bool
+	KnownDevice         bool
+}
+
+const maxUserAgentLength = 512
+
+func (r *userRepository) RecordLoginAttempt(ctx context.Context, record LoginAttemptRecord) (*ent.LoginAttempt, error) {
+	userAgent := record.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	create := r.client.LoginAttempt.Create().
+		SetNillableUserID(record.UserID).
+		SetEmail(record.Email).
+		SetIP(record.IP).
+		SetDeviceID(record.DeviceID).
+		SetPlatform(record.Platform).
+		SetUserAgent(userAgent).
+		SetMethod(record.Method).
+		SetOutcome(record.Outcome).
+		SetNillableFailureReason(record.FailureReason).
+		SetRiskScore(record.RiskScore)
+	if !record.ClientHints.IsZero() {
+		create.SetClientHints(record.ClientHints)
+	}
+
+	return create.Save(ctx)
+}
+
+// ListLoginAttempts returns the newest attempts first, keyset paginated on
+// the ID of the last attempt of the previous page.
+func (r *userRepository) ListLoginAttempts(ctx context.Context, filter LoginAttemptFilter, beforeID int64, limit int) ([]*ent.LoginAttempt, error) {
+	query := r.client.LoginAttempt.Query()
+
+	if filter.UserID != nil {
+		query = query.Where(loginattempt.UserIDEQ(*filter.UserID))
+	}
+	if filter.Email != "" {
+		query = query.Where(loginattempt.EmailEQ(filter.Email))
+	}
+	if filter.IP != "" {
+		query = query.Where(loginattempt.IPEQ(filter.IP))
+	}
+	if filter.Outcome != nil {
+		query = query.Where(loginattempt.OutcomeEQ(*filter.Outcome))
+	}
+	if filter.Since != nil {
+		query = query.Where(loginattempt.CreatedAtGTE(*filter.Since))
+	}
+	if filter.Until != nil {
+		query = query.Where(loginattempt.CreatedAtLT(*filter.Until))
+	}
+	if beforeID > 0 {
+		query = query.Where(loginattempt.IDLT(beforeID))
+	}
+
+	return query.
+		WithUser().
+		Order(ent.Desc(loginattempt.FieldID)).
+		Limit(limit).
+		All(ctx)
+}
+
+func (r *userRepository) LoginRiskSignals(ctx context.Context, userID *int64, email, ip, deviceID string, since time.Time) (LoginRiskSignals, error) {
+	var (
+		signals LoginRiskSignals
+		err     error
+	)
+
+	failures := r.client.LoginAttempt.Query().
+		Where(loginattempt.OutcomeEQ(loginattempt.OutcomeFAILURE), loginattempt.CreatedAtGTE(since))
+
+	if signals.RecentEmailFailures, err = failures.Clone().Where(loginattempt.EmailEQ(email)).Count(ctx); err != nil {
+		return signals, err
+	}
+
+	if ip != "" {
+		if signals.RecentIPFailures, err = failures.Clone().Where(loginattempt.IPEQ(ip)).Count(ctx); err != nil {
+			return signals, err
+		}
+	}
+
+	if userID == nil {
+		return signals, nil
+	}
+
+	successes := r.client.LoginAttempt.Query().
+		Where(loginattempt.UserIDEQ(*userID), loginattempt.OutcomeEQ(loginattempt.OutcomeSUCCESS))
+
+	if signals.HasPriorSuccess, err = successes.Clone().Exist(ctx); err != nil || !signals.HasPriorSuccess {
+		return signals, err
+	}
+
+	if ip != "" {
+		if signals.KnownIP, err = successes.Clone().Where(loginattempt.IPEQ(ip)).Exist(ctx); err != nil {
+			return signals, err
+		}
+	}
+
+	if deviceID != "" {
+		if signals.KnownDevice, err = successes.Clone().Where(loginattempt.DeviceIDEQ(deviceID)).Exist(ctx); err != nil {
+			return signals, err
+		}
+	}
+
+	return signals, nil
+}
+
+// PruneLoginAttempts deletes at most batchSize attempts older than before,
+// oldest first, and returns how many were removed.
+func (r *userRepository) PruneLoginAttempts(ctx context.Context, before time.Time, batchSize int) (int, error) {
+	ids, err := r.client.LoginAttempt.Query().
+		Where(loginattempt.CreatedAtLT(before)).
+		Order(ent.Asc(loginattempt.FieldID)).
+		Limit(batchSize).
+		IDs(ctx)
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	return r.client.LoginAttempt.Delete().
+		Where(loginattempt.IDIn(ids...)).
+		Exec(ctx)
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/database/ent/organization"
+)
+
+// OrganizationBranding is an organization and the brand its members see.
+// Empty branding fields fall back to the default brand.
+type OrganizationBranding struct {
+	Slug         string
+	Name         string
+	ProductName  string
+	LogoURL      string
+	PrimaryColor string
+	SupportEmail string
+}
+
+func (r *userRepository) GetOrganization(ctx context.Context, id int64) (*ent.Organization, error) {
+	return r.client.Organization.Get(ctx, id)
+}
+
+func (r *userRepository) GetOrganizationBySlug(ctx context.Context, slug string) (*ent.Organization, error) {
+	return r.client.Organization.Query().
+		Where(organization.SlugEQ(slug)).
+		Only(ctx)
+}
+
+// SaveOrganization creates the organization with the given slug, or
+// replaces the name and branding of the existing one.
+func (r *userRepository) SaveOrganization(ctx context.Context, input OrganizationBranding) (*ent.Organization, error) {
+	existing, err := r.GetOrganizationBySlug(ctx, input.Slug)
+	if ent.IsNotFound(err) {
+		return r.client.Organization.Create().
+			SetSlug(input.Slug).
+			SetName(input.Name).
+			SetProductName(input.ProductName).
+			SetLogoURL(input.LogoURL).
+			SetPrimaryColor(input.PrimaryColor).
+			SetSupportEmail(input.SupportEmail).
+			Save(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return existing.Update().
+		SetName(input.Name).
+		SetProductName(input.ProductName).
+		SetLogoURL(input.LogoURL).
+		SetPrimaryColor(input.PrimaryColor).
+		SetSupportEmail(input.SupportEmail).
+		SetUpdatedAt(time.Now()).
+		Save(ctx)
+}
+
+// SetUserOrganization moves the user into the organization, or out of any
+// when organizationID is nil.
+func (r *userRepository) SetUserOrganization(ctx context.Context, userID int64, organizationID *int64) (*ent.User, error) {
+	update := r.client.User.UpdateOneID(userID).
+		SetUpdatedAt(time.Now())
+	if organizationID == nil {
+		update.ClearOrganizationID()
+	} else {
+		update.SetOrganizationID(*organizationID)
+	}
+	return update.Save(ctx)
+}
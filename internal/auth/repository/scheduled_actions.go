@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/database/ent/scheduledaction"
+	"github.com/google/uuid"
+)
+
+// ScheduledAction is a new action on the account of UserID, due at RunAt.
+type ScheduledAction struct {
+	UserID    int64
+	Action    scheduledaction.Action
+	RunAt     time.Time
+	Note      string
+	CreatedBy *uuid.UUID
+}
+
+func (r *userRepository) CreateScheduledAction(ctx context.Context, action ScheduledAction) (*ent.ScheduledAction, error) {
+	created, err := r.client.ScheduledAction.Create().
+		SetUserID(action.UserID).
+		SetAction(action.Action).
+		SetRunAt(action.RunAt).
+		SetNote(action.Note).
+		SetNillableCreatedBy(action.CreatedBy).
+		Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetScheduledAction(ctx, created.ID)
+}
+
+// GetScheduledAction loads the action with its target account, deleted or
+// not.
+func (r *userRepository) GetScheduledAction(ctx context.Context, id int64) (*ent.ScheduledAction, error) {
+	return r.client.ScheduledAction.Query().
+		Where(scheduledaction.ID(id)).
+		WithUser().
+		Only(ctx)
+}
+
+// ListScheduledActions returns every action of the user, latest due first.
+func (r *userRepository) ListScheduledActions(ctx context.Context, userID int64) ([]*ent.ScheduledAction, error) {
+	return r.client.ScheduledAction.Query().
+		Where(scheduledaction.UserID(userID)).
+		Order(ent.Desc(scheduledaction.FieldRunAt), ent.Desc(scheduledaction.FieldID)).
+		WithUser().
+		All(ctx)
+}
+
+// DueScheduledActions returns up to limit pending actions due by now, the
+// longest overdue first.
+func (r *userRepository) DueScheduledActions(ctx context.Context, now time.Time, limit int) ([]*ent.ScheduledAction, error) {
+	return r.client.ScheduledAction.Query().
+		Where(
+			scheduledaction.StatusEQ(scheduledaction.StatusPENDING),
+			scheduledaction.RunAtLTE(now),
+		).
+		Order(ent.Asc(scheduledaction.FieldRunAt), ent.Asc(scheduledaction.FieldID)).
+		Limit(limit).
+		All(ctx)
+}
+
+// CancelScheduledAction cancels the action if it is still pending, reporting
+// whether it was.
+func (r *userRepository) CancelScheduledAction(ctx context.Context, id int64, by *uuid.UUID, at time.Time) (bool, error) {
+	n, err := r.client.ScheduledAction.Update().
+		Where(
+			scheduledaction.ID(id),
+			scheduledaction.StatusEQ(scheduledaction.StatusPENDING),
+		).
+		SetStatus(scheduledaction.StatusCANCELLED).
+		SetNillableCancelledBy(by).
+		SetFinishedAt(at).
+		Save(ctx)
+	return n > 0, err
+}
+
+// ClaimScheduledAction marks a pending action as running, reporting whether
+// it was still pending.
+func (r *userRepository) ClaimScheduledAction(ctx context.Context, id int64) (bool, error) {
+	n, err := r.client.ScheduledAction.Update().
+		Where(
+			scheduledaction.ID(id),
+			scheduledaction.StatusEQ(scheduledaction.StatusPENDING),
+		).
+		SetStatus(scheduledaction.StatusRUNNING).
+		Save(ctx)
+	return n > 0, err
+}
+
+// FinishScheduledAction records the outcome of a claimed action; failure is
+// empty when it completed.
+func (r *userRepository) FinishScheduledAction(ctx context.Context, id int64, failure string, at time.Time) (*ent.ScheduledAction, error) {
+	update := r.client.ScheduledAction.UpdateOneID(id).
+		SetStatus(scheduledaction.StatusCOMPLETED).
+		SetFinishedAt(at)
+	if failure != "" {
+		update.SetStatus(scheduledaction.StatusFAILED).SetError(failure)
+	}
+	if _, err := update.Save(ctx); err != nil {
+		return nil, err
+	}
+	return r.GetScheduledAction(ctx, id)
+}
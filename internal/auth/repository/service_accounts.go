@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/database/ent/serviceaccountkey"
+	"github.com/abisalde/authentication-service/internal/database/ent/user"
+)
+
+// ServiceAccount is a non-human account of an organization. The email is
+// generated, never delivered to, and only keeps the column unique.
+type ServiceAccount struct {
+	OrganizationID int64
+	Name           string
+	Email          string
+}
+
+// ServiceAccountKey is a new key; SecretHash is all that is stored of the
+// secret.
+type ServiceAccountKey struct {
+	KeyID      string
+	SecretHash string
+}
+
+// CreateServiceAccount creates the account with its first key. Service
+// accounts start verified and onboarded since nobody completes either.
+func (r *userRepository) CreateServiceAccount(ctx context.Context, account ServiceAccount, key ServiceAccountKey) (*ent.User, error) {
+	tx, err := r.client.Tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	create := tx.User.Create().
+		SetKind(user.KindSERVICE).
+		SetEmail(account.Email).
+		SetFirstName(account.Name).
+		SetIsEmailVerified(true).
+		SetOnboardingStep(user.OnboardingStepDONE).
+		SetOrganizationID(account.OrganizationID)
+	create, err = r.assignID(create)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	created, err := r.saveUser(ctx, tx.User, create)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	if _, err := tx.ServiceAccountKey.Create().
+		SetUserID(created.ID).
+		SetKeyID(key.KeyID).
+		SetSecretHash(key.SecretHash).
+		Save(ctx); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetServiceAccount(ctx, created.ID)
+}
+
+// GetServiceAccount loads the service account with its keys and owner.
+func (r *userRepository) GetServiceAccount(ctx context.Context, userID int64) (*ent.User, error) {
+	return r.users(ctx).
+		Where(user.IDEQ(userID), user.KindEQ(user.KindSERVICE)).
+		WithOrganization().
+		WithServiceAccountKeys(func(q *ent.ServiceAccountKeyQuery) {
+			q.Order(ent.Asc(serviceaccountkey.FieldID))
+		}).
+		Only(ctx)
+}
+
+// ListServiceAccounts returns the organization's service accounts with
+// their keys, oldest first.
+func (r *userRepository) ListServiceAccounts(ctx context.Context, organizationID int64) ([]*ent.User, error) {
+	return r.users(ctx).
+		Where(user.OrganizationIDEQ(organizationID), user.KindEQ(user.KindSERVICE)).
+		WithOrganization().
+		WithServiceAccountKeys(func(q *ent.ServiceAccountKeyQuery) {
+			q.Order(ent.Asc(serviceaccountkey.FieldID))
+		}).
+		Order(ent.Asc(user.FieldID)).
+		All(ctx)
+}
+
+// GetServiceAccountKey looks a key up by its public ID, with its owner.
+func (r *userRepository) GetServiceAccountKey(ctx context.Context, keyID string) (*ent.ServiceAccountKey, error) {
+	return r.client.ServiceAccountKey.Query().
+		Where(serviceaccountkey.KeyIDEQ(keyID)).
+		WithOwner().
+		Only(ctx)
+}
+
+// AddServiceAccountKey adds a key and schedules the account's other keys to
+// expire at retireAt, unless they expire sooner already.
+func (r *userRepository) AddServiceAccountKey(ctx context.Context, userID int64, key ServiceAccountKey, retireAt time.Time) error {
+	tx, err := r.client.Tx(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ServiceAccountKey.Update().
+		Where(
+			serviceaccountkey.UserIDEQ(userID),
+			serviceaccountkey.Or(serviceaccountkey.ExpiresAtIsNil(), serviceaccountkey.ExpiresAtGT(retireAt)),
+		).
+		SetExpiresAt(retireAt).
+		Save(ctx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if _, err := tx.ServiceAccountKey.Create().
+		SetUserID(userID).
+		SetKeyID(key.KeyID).
+		SetSecretHash(key.SecretHash).
+		Save(ctx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteServiceAccountKey removes one key of the account and reports whether
+// it existed.
+func (r *userRepository) DeleteServiceAccountKey(ctx context.Context, userID int64, keyID string) (bool, error) {
+	deleted, err := r.client.ServiceAccountKey.Delete().
+		Where(serviceaccountkey.UserIDEQ(userID), serviceaccountkey.KeyIDEQ(keyID)).
+		Exec(ctx)
+	return deleted > 0, err
+}
+
+func (r *userRepository) TouchServiceAccountKey(ctx context.Context, id int64, usedAt time.Time) error {
+	return r.client.ServiceAccountKey.UpdateOneID(id).
+		SetLastUsedAt(usedAt).
+		Exec(ctx)
+}
@@ -3,17 +3,26 @@ package repository
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strconv"
 	"time"
 
+	"github.com/abisalde/authentication-service/internal/auth/residency"
 	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/database/ent/loginattempt"
+	"github.com/abisalde/authentication-service/internal/database/ent/scheduledaction"
+	"github.com/abisalde/authentication-service/internal/database/ent/serviceaccountkey"
 	"github.com/abisalde/authentication-service/internal/database/ent/user"
+	"github.com/abisalde/authentication-service/internal/database/ent/useridentity"
 	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/pkg/idgen"
+	"github.com/google/uuid"
 )
 
 type UserRepository interface {
 	GetByEmail(ctx context.Context, email string) (*ent.User, error)
 	GetByID(ctx context.Context, id int64) (*ent.User, error)
+	GetByPublicID(ctx context.Context, publicID uuid.UUID) (*ent.User, error)
 	CreateNewUser(ctx context.Context, input *model.RegisterVerifiedUser) (*ent.User, error)
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
 	ExistsByUsername(ctx context.Context, username string) (bool, error)
@@ -21,9 +30,59 @@ type UserRepository interface {
 	UpdateUsername(ctx context.Context, userID int64, username string) error
 	UpdateLoginTime(ctx context.Context, userID int64) error
 	UpdateNewPassword(ctx context.Context, userID int64, passwordHash string) error
+	UpdateEmail(ctx context.Context, userID int64, email string) (*ent.User, error)
 	FindByOAuthID(ctx context.Context, provider, oauthID string) (*ent.User, error)
 	CreateUserFromOAuth(ctx context.Context, provider string, userInfo *model.OAuthUserResponse) (*ent.User, error)
 	FindAllUsers(ctx context.Context, role *model.UserRole, pagination *model.PaginationInput) (*model.UserConnection, error)
+	UpdateProfile(ctx context.Context, userID int64, input model.UpdateProfileInput) error
+	AcceptTerms(ctx context.Context, userID int64, acceptedAt time.Time) error
+	UpdateOnboardingStep(ctx context.Context, userID int64, step string) error
+	Suspend(ctx context.Context, userID int64, reason user.SuspensionReason, suspendedAt time.Time) (*ent.User, error)
+	LiftSuspension(ctx context.Context, userID int64) (*ent.User, error)
+	SoftDelete(ctx context.Context, userID int64, deletedAt time.Time) (*ent.User, error)
+	Reactivate(ctx context.Context, userID int64) (*ent.User, error)
+	Deactivate(ctx context.Context, userID int64, deactivatedAt time.Time) (*ent.User, error)
+	ClearDeactivation(ctx context.Context, userID int64) (*ent.User, error)
+	PurgeDeleted(ctx context.Context, before time.Time, batchSize int) (int, error)
+	UpdateRole(ctx context.Context, userID int64, role user.Role) (*ent.User, error)
+	CountCohort(ctx context.Context, cohort UserCohort) (int, error)
+	ListCohortIDs(ctx context.Context, cohort UserCohort, afterID int64, limit int) ([]int64, error)
+	PublicIDs(ctx context.Context, ids []int64) (map[int64]uuid.UUID, error)
+	RecordLoginAttempt(ctx context.Context, record LoginAttemptRecord) (*ent.LoginAttempt, error)
+	ListLoginAttempts(ctx context.Context, filter LoginAttemptFilter, beforeID int64, limit int) ([]*ent.LoginAttempt, error)
+	LoginRiskSignals(ctx context.Context, userID *int64, email, ip, deviceID string, since time.Time) (LoginRiskSignals, error)
+	PruneLoginAttempts(ctx context.Context, before time.Time, batchSize int) (int, error)
+	GetOrganization(ctx context.Context, id int64) (*ent.Organization, error)
+	GetOrganizationBySlug(ctx context.Context, slug string) (*ent.Organization, error)
+	SaveOrganization(ctx context.Context, input OrganizationBranding) (*ent.Organization, error)
+	SetUserOrganization(ctx context.Context, userID int64, organizationID *int64) (*ent.User, error)
+	CreateServiceAccount(ctx context.Context, account ServiceAccount, key ServiceAccountKey) (*ent.User, error)
+	GetServiceAccount(ctx context.Context, userID int64) (*ent.User, error)
+	ListServiceAccounts(ctx context.Context, organizationID int64) ([]*ent.User, error)
+	GetServiceAccountKey(ctx context.Context, keyID string) (*ent.ServiceAccountKey, error)
+	AddServiceAccountKey(ctx context.Context, userID int64, key ServiceAccountKey, retireAt time.Time) error
+	DeleteServiceAccountKey(ctx context.Context, userID int64, keyID string) (bool, error)
+	TouchServiceAccountKey(ctx context.Context, id int64, usedAt time.Time) error
+	GetIdentity(ctx context.Context, provider, oauthID string) (*ent.UserIdentity, error)
+	LinkIdentity(ctx context.Context, userID int64, identity Identity) (*ent.UserIdentity, error)
+	ListIdentities(ctx context.Context, userID int64) ([]*ent.UserIdentity, error)
+	DeleteIdentity(ctx context.Context, userID, identityID int64) (bool, error)
+	TouchIdentity(ctx context.Context, provider, oauthID string, usedAt time.Time) error
+	CreateScheduledAction(ctx context.Context, action ScheduledAction) (*ent.ScheduledAction, error)
+	GetScheduledAction(ctx context.Context, id int64) (*ent.ScheduledAction, error)
+	ListScheduledActions(ctx context.Context, userID int64) ([]*ent.ScheduledAction, error)
+	DueScheduledActions(ctx context.Context, now time.Time, limit int) ([]*ent.ScheduledAction, error)
+	CancelScheduledAction(ctx context.Context, id int64, by *uuid.UUID, at time.Time) (bool, error)
+	ClaimScheduledAction(ctx context.Context, id int64) (bool, error)
+	FinishScheduledAction(ctx context.Context, id int64, failure string, at time.Time) (*ent.ScheduledAction, error)
+}
+
+// UserCohort selects users for bulk admin operations. Nil filters match everyone.
+type UserCohort struct {
+	CreatedBefore   *time.Time
+	LastLoginBefore *time.Time
+	Role            *model.UserRole
+	Provider        *model.AuthProvider
 }
 
 const (
@@ -31,28 +90,132 @@ const (
 	maxLimit     = 100
 )
 
+type includeDeletedKey struct{}
+
+// IncludeDeleted lets lookups made with the returned context see soft
+// deleted accounts, for the admin operations that restore or inspect them.
+func IncludeDeleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, includeDeletedKey{}, true)
+}
+
 type userRepository struct {
-	client *ent.Client
+	client    *ent.Client
+	ids       idgen.Generator
+	residency residency.Policy
 }
 
 func NewUserRepository(client *ent.Client) UserRepository {
-	return &userRepository{client: client}
+	return &userRepository{client: client, ids: idgen.Database{}}
+}
+
+// NewUserRepositoryWithIDs assigns new user IDs from the given generator
+// instead of the auto increment column.
+func NewUserRepositoryWithIDs(client *ent.Client, ids idgen.Generator) UserRepository {
+	return &userRepository{client: client, ids: ids}
+}
+
+// NewRegionalUserRepository stores users in the database the router returns
+// for the deployment's region. New accounts are tagged with their region and
+// accounts of other regions are refused when the policy enforces residency.
+func NewRegionalUserRepository(router residency.Router, policy residency.Policy, ids idgen.Generator) (UserRepository, error) {
+	stores, err := router.Route(policy.Region)
+	if err != nil {
+		return nil, err
+	}
+	return &userRepository{client: stores.DB, ids: ids, residency: policy}, nil
+}
+
+// users queries the accounts visible to ctx: soft deleted ones are left out
+// unless the context includes them.
+func (r *userRepository) users(ctx context.Context) *ent.UserQuery {
+	query := r.client.User.Query()
+	if include, _ := ctx.Value(includeDeletedKey{}).(bool); !include {
+		query = query.Where(user.DeletedAtIsNil())
+	}
+	return query
+}
+
+// resident refuses users whose data belongs to another region.
+func (r *userRepository) resident(u *ent.User, err error) (*ent.User, error) {
+	if err != nil {
+		return nil, err
+	}
+	if err := r.residency.Check(u.Residency); err != nil {
+		slog.Warn("Residency violation", "user_id", u.ID, "user_region", u.Residency, "region", r.residency.Region)
+		return nil, err
+	}
+	return u, nil
+}
+
+// regionFor tags a new account, refusing it when its region is stored
+// elsewhere.
+func (r *userRepository) regionFor(create *ent.UserCreate, country string) (*ent.UserCreate, error) {
+	region := r.residency.RegionFor(country)
+	if err := r.residency.Check(region); err != nil {
+		return nil, err
+	}
+	if region != "" {
+		create = create.SetResidency(region)
+	}
+	return create, nil
+}
+
+// maxIDAttempts bounds the inserts of a user whose randomly drawn ID keeps
+// colliding with existing ones.
+const maxIDAttempts = 3
+
+// saveUser saves create, drawing a new ID when the generator's random one
+// was already taken. users is the client create belongs to, so the check
+// runs in the same transaction.
+func (r *userRepository) saveUser(ctx context.Context, users *ent.UserClient, create *ent.UserCreate) (*ent.User, error) {
+	for attempt := 1; ; attempt++ {
+		created, err := create.Save(ctx)
+		if err == nil || !ent.IsConstraintError(err) || !idgen.MayCollide(r.ids) || attempt == maxIDAttempts {
+			return created, err
+		}
+
+		id, _ := create.Mutation().ID()
+		if taken, _ := users.Query().Where(user.IDEQ(id)).Exist(ctx); !taken {
+			return nil, err
+		}
+		slog.Warn("User id was already taken, drawing another", "user_id", id)
+		if create, err = r.assignID(create); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (r *userRepository) assignID(create *ent.UserCreate) (*ent.UserCreate, error) {
+	id, ok, err := r.ids.NextID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate user id: %w", err)
+	}
+	if ok {
+		create = create.SetID(id)
+	}
+	return create, nil
 }
 
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*ent.User, error) {
-	return r.client.User.
-		Query().
+	return r.resident(r.users(ctx).
 		Where(user.EmailEQ(email)).
-		Only(ctx)
+		Only(ctx))
 }
 
 func (r *userRepository) GetByID(ctx context.Context, id int64) (*ent.User, error) {
-	return r.client.User.
-		Query().
+	return r.resident(r.users(ctx).
 		Where(user.IDEQ(id)).
-		Only(ctx)
+		Only(ctx))
+}
+
+func (r *userRepository) GetByPublicID(ctx context.Context, publicID uuid.UUID) (*ent.User, error) {
+	return r.resident(r.users(ctx).
+		Where(user.PublicIDEQ(publicID)).
+		Only(ctx))
 }
 
+// ExistsByEmail and ExistsByUsername also see soft deleted accounts, which
+// keep their unique email and username until they are purged.
 func (r *userRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
 	return r.client.User.
 		Query().
@@ -68,10 +231,9 @@ func (r *userRepository) ExistsByUsername(ctx context.Context, username string)
 }
 
 func (r *userRepository) GetByUsername(ctx context.Context, username string) (*ent.User, error) {
-	return r.client.User.
-		Query().
+	return r.resident(r.users(ctx).
 		Where(user.UsernameEQ(username)).
-		Only(ctx)
+		Only(ctx))
 }
 
 func (r *userRepository) UpdateUsername(ctx context.Context, userID int64, username string) error {
@@ -91,9 +253,158 @@ func (r *userRepository) CreateNewUser(ctx context.Context, input *model.Registe
 		SetNillableIsEmailVerified(&input.IsEmailVerified).
 		SetNillableOauthID(input.OauthId).
 		SetFirstName(firstName).
-		SetLastName(lastName)
+		SetLastName(lastName).
+		SetNillableTermsAcceptedAt(input.TermsAcceptedAt).
+		SetTermsVersion(input.TermsVersion).
+		SetCountry(input.Country).
+		SetMarketingOptIn(input.MarketingOptIn)
+	if input.Password != "" {
+		create.SetPasswordChangedAt(time.Now())
+	}
 
-	return create.Save(ctx)
+	create, err := r.regionFor(create, input.Country)
+	if err != nil {
+		return nil, err
+	}
+
+	create, err = r.assignID(create)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.saveUser(ctx, r.client.User, create)
+}
+
+func (r *userRepository) UpdateProfile(ctx context.Context, userID int64, input model.UpdateProfileInput) error {
+	update := r.client.User.UpdateOneID(userID).
+		SetFirstName(input.FirstName).
+		SetLastName(input.LastName).
+		SetMarketingOptIn(input.MarketingOptIn).
+		SetNillablePhoneNumber(input.PhoneNumber).
+		SetUpdatedAt(time.Now())
+
+	if input.Address != nil {
+		update = update.
+			SetNillableStreetName(input.Address.StreetName).
+			SetNillableCity(input.Address.City).
+			SetNillableZipCode(input.Address.ZipCode).
+			SetNillableCountry(input.Address.Country).
+			SetNillableState(input.Address.State)
+	}
+
+	return update.Exec(ctx)
+}
+
+func (r *userRepository) AcceptTerms(ctx context.Context, userID int64, acceptedAt time.Time) error {
+	return r.client.User.UpdateOneID(userID).
+		SetTermsAcceptedAt(acceptedAt).
+		SetUpdatedAt(time.Now()).
+		Exec(ctx)
+}
+
+func (r *userRepository) UpdateOnboardingStep(ctx context.Context, userID int64, step string) error {
+	return r.client.User.UpdateOneID(userID).
+		SetOnboardingStep(user.OnboardingStep(step)).
+		SetUpdatedAt(time.Now()).
+		Exec(ctx)
+}
+
+func (r *userRepository) Suspend(ctx context.Context, userID int64, reason user.SuspensionReason, suspendedAt time.Time) (*ent.User, error) {
+	return r.client.User.UpdateOneID(userID).
+		SetSuspendedAt(suspendedAt).
+		SetSuspensionReason(reason).
+		SetUpdatedAt(time.Now()).
+		Save(ctx)
+}
+
+func (r *userRepository) LiftSuspension(ctx context.Context, userID int64) (*ent.User, error) {
+	return r.client.User.UpdateOneID(userID).
+		ClearSuspendedAt().
+		ClearSuspensionReason().
+		SetUpdatedAt(time.Now()).
+		Save(ctx)
+}
+
+func (r *userRepository) SoftDelete(ctx context.Context, userID int64, deletedAt time.Time) (*ent.User, error) {
+	return r.client.User.UpdateOneID(userID).
+		SetDeletedAt(deletedAt).
+		SetUpdatedAt(time.Now()).
+		Save(ctx)
+}
+
+// Reactivate clears the deletion, deactivation and any suspension of the
+// account.
+func (r *userRepository) Reactivate(ctx context.Context, userID int64) (*ent.User, error) {
+	return r.client.User.UpdateOneID(userID).
+		ClearDeletedAt().
+		ClearDeactivatedAt().
+		ClearSuspendedAt().
+		ClearSuspensionReason().
+		SetUpdatedAt(time.Now()).
+		Save(ctx)
+}
+
+func (r *userRepository) Deactivate(ctx context.Context, userID int64, deactivatedAt time.Time) (*ent.User, error) {
+	return r.client.User.UpdateOneID(userID).
+		SetDeactivatedAt(deactivatedAt).
+		SetUpdatedAt(time.Now()).
+		Save(ctx)
+}
+
+func (r *userRepository) ClearDeactivation(ctx context.Context, userID int64) (*ent.User, error) {
+	return r.client.User.UpdateOneID(userID).
+		ClearDeactivatedAt().
+		SetUpdatedAt(time.Now()).
+		Save(ctx)
+}
+
+// PurgeDeleted hard deletes up to batchSize accounts soft deleted before
+// the cutoff, together with their login history, which still holds the
+// email, their linked OAuth accounts, any service account keys and their
+// scheduled actions.
+func (r *userRepository) PurgeDeleted(ctx context.Context, before time.Time, batchSize int) (int, error) {
+	ids, err := r.client.User.Query().
+		Where(user.DeletedAtLT(before)).
+		Order(ent.Asc(user.FieldID)).
+		Limit(batchSize).
+		IDs(ctx)
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	tx, err := r.client.Tx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.LoginAttempt.Delete().Where(loginattempt.UserIDIn(ids...)).Exec(ctx); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	if _, err := tx.ServiceAccountKey.Delete().Where(serviceaccountkey.UserIDIn(ids...)).Exec(ctx); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	if _, err := tx.UserIdentity.Delete().Where(useridentity.UserIDIn(ids...)).Exec(ctx); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	if _, err := tx.ScheduledAction.Delete().Where(scheduledaction.UserIDIn(ids...)).Exec(ctx); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	purged, err := tx.User.Delete().Where(user.IDIn(ids...)).Exec(ctx)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	return purged, tx.Commit()
+}
+
+func (r *userRepository) UpdateRole(ctx context.Context, userID int64, role user.Role) (*ent.User, error) {
+	return r.client.User.UpdateOneID(userID).
+		SetRole(role).
+		SetUpdatedAt(time.Now()).
+		Save(ctx)
 }
 
 func (r *userRepository) UpdateLoginTime(ctx context.Context, userID int64) error {
@@ -104,30 +415,47 @@ func (r *userRepository) UpdateLoginTime(ctx context.Context, userID int64) erro
 }
 
 func (r *userRepository) UpdateNewPassword(ctx context.Context, userID int64, passwordHash string) error {
+	now := time.Now()
 	err := r.client.User.UpdateOneID(userID).
 		SetPasswordHash(passwordHash).
-		SetUpdatedAt(time.Now()).Exec(ctx)
+		SetPasswordChangedAt(now).
+		SetUpdatedAt(now).Exec(ctx)
 
 	return err
 }
 
+func (r *userRepository) UpdateEmail(ctx context.Context, userID int64, email string) (*ent.User, error) {
+	return r.client.User.UpdateOneID(userID).
+		SetEmail(email).
+		SetIsEmailVerified(true).
+		SetUpdatedAt(time.Now()).
+		Save(ctx)
+}
+
+// FindByOAuthID finds the user an OAuth account is linked to.
 func (r *userRepository) FindByOAuthID(ctx context.Context, provider, oauthID string) (*ent.User, error) {
-	return r.client.User.
-		Query().
-		Where(
-			user.OauthIDEQ(oauthID),
-			user.ProviderEQ(user.Provider(provider)),
-		).
-		Only(ctx)
+	return r.resident(r.users(ctx).
+		Where(user.HasIdentitiesWith(
+			useridentity.ProviderEQ(useridentity.Provider(provider)),
+			useridentity.OauthIDEQ(oauthID),
+		)).
+		Only(ctx))
 }
 
+// CreateUserFromOAuth creates the user with the OAuth account linked. The
+// provider and oauth_id columns keep the account it was created with.
 func (r *userRepository) CreateUserFromOAuth(ctx context.Context, provider string, userInfo *model.OAuthUserResponse) (*ent.User, error) {
 	firstName := userInfo.FirstName
 	lastName := userInfo.LastName
 	providerEnum := user.Provider(provider)
 	emailVerified := true
 
-	create := r.client.User.
+	tx, err := r.client.Tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	create := tx.User.
 		Create().
 		SetEmail(userInfo.Email).
 		SetNillableIsEmailVerified(&emailVerified).
@@ -136,7 +464,38 @@ func (r *userRepository) CreateUserFromOAuth(ctx context.Context, provider strin
 		SetNillableProvider(&providerEnum).
 		SetLastName(lastName)
 
-	return create.Save(ctx)
+	// OAuth profiles carry no country, so these accounts get the default
+	// region.
+	create, err = r.regionFor(create, "")
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	create, err = r.assignID(create)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	created, err := r.saveUser(ctx, tx.User, create)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	if _, err := tx.UserIdentity.Create().
+		SetUserID(created.ID).
+		SetProvider(useridentity.Provider(provider)).
+		SetOauthID(userInfo.ID).
+		SetEmail(userInfo.Email).
+		Save(ctx); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created.Unwrap(), nil
 }
 
 func (r *userRepository) FindAllUsers(ctx context.Context, role *model.UserRole, pagination *model.PaginationInput) (*model.UserConnection, error) {
@@ -149,7 +508,7 @@ func (r *userRepository) FindAllUsers(ctx context.Context, role *model.UserRole,
 		}, fmt.Errorf("invalid pagination: %w", err)
 	}
 
-	query := r.client.User.Query().
+	query := r.users(ctx).
 		Order(ent.Desc(user.FieldID)).Limit(limit)
 
 	if role != nil {
@@ -207,6 +566,7 @@ func mapEntUserToModelUser(u *ent.User) *model.User {
 		Provider:        model.AuthProvider(u.Provider),
 		Role:            model.UserRole(u.Role),
 		LastLoginAt:     u.LastLoginAt,
+		OnboardingStep:  model.OnboardingStep(u.OnboardingStep),
 		CreatedAt:       u.CreatedAt,
 		UpdatedAt:       u.UpdatedAt,
 	}
@@ -234,6 +594,58 @@ func buildUserConnection(users []*ent.User, limit int) *model.UserConnection {
 	}
 }
 
+func (r *userRepository) CountCohort(ctx context.Context, cohort UserCohort) (int, error) {
+	return applyCohort(r.users(ctx), cohort).Count(ctx)
+}
+
+// ListCohortIDs pages through the cohort in ascending ID order using keyset
+// pagination, so batches stay stable while earlier ones are processed.
+func (r *userRepository) ListCohortIDs(ctx context.Context, cohort UserCohort, afterID int64, limit int) ([]int64, error) {
+	return applyCohort(r.users(ctx), cohort).
+		Where(user.IDGT(afterID)).
+		Order(ent.Asc(user.FieldID)).
+		Limit(limit).
+		IDs(ctx)
+}
+
+// PublicIDs maps the given users to their public IDs; unknown users are
+// left out.
+func (r *userRepository) PublicIDs(ctx context.Context, ids []int64) (map[int64]uuid.UUID, error) {
+	var rows []struct {
+		ID       int64     `json:"id"`
+		PublicID uuid.UUID `json:"public_id"`
+	}
+	err := r.users(ctx).
+		Where(user.IDIn(ids...)).
+		Select(user.FieldID, user.FieldPublicID).
+		Scan(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	publicIDs := make(map[int64]uuid.UUID, len(rows))
+	for _, row := range rows {
+		publicIDs[row.ID] = row.PublicID
+	}
+	return publicIDs, nil
+}
+
+func applyCohort(query *ent.UserQuery, cohort UserCohort) *ent.UserQuery {
+	if cohort.CreatedBefore != nil {
+		query = query.Where(user.CreatedAtLT(*cohort.CreatedBefore))
+	}
+	if cohort.LastLoginBefore != nil {
+		query = query.Where(user.Or(
+			user.LastLoginAtIsNil(),
+			user.LastLoginAtLT(*cohort.LastLoginBefore),
+		))
+	}
+	if cohort.Provider != nil {
+		query = query.Where(user.ProviderEQ(user.Provider(*cohort.Provider)))
+	}
+	return applyFilters(query, cohort.Role)
+}
+
 func applyFilters(query *ent.UserQuery, role *model.UserRole) *ent.UserQuery {
 	if role != nil {
 		query = query.Where(user.RoleEQ(user.Role(*role)))
@@ -0,0 +1,99 @@
+package reputation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HTTPFeed reads a score from a JSON HTTP API. URL holds an {ip}
+// placeholder; ScoreField is a dotted path to a 0-100 number in the
+// response, "score" by default.
+type HTTPFeed struct {
+	Name       string
+	URL        string
+	Header     string
+	Key        string
+	ScoreField string
+	Client     *http.Client
+}
+
+// AbuseIPDB looks IPs up in the AbuseIPDB check API, scoring them by its
+// abuse confidence over the last 90 days.
+func AbuseIPDB(apiKey string) *HTTPFeed {
+	return &HTTPFeed{
+		Name:       "abuseipdb",
+		URL:        "https://api.abuseipdb.com/api/v2/check?maxAgeInDays=90&ipAddress={ip}",
+		Header:     "Key",
+		Key:        apiKey,
+		ScoreField: "data.abuseConfidenceScore",
+	}
+}
+
+var defaultFeedClient = &http.Client{Timeout: 2 * time.Second}
+
+func (f *HTTPFeed) Lookup(ctx context.Context, ip string) (Report, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.ReplaceAll(f.URL, "{ip}", url.QueryEscape(ip)), nil)
+	if err != nil {
+		return Report{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if f.Header != "" && f.Key != "" {
+		req.Header.Set(f.Header, f.Key)
+	}
+
+	client := f.Client
+	if client == nil {
+		client = defaultFeedClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Report{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Report{}, fmt.Errorf("%s returned %d", f.Name, resp.StatusCode)
+	}
+
+	var body any
+	decoder := json.NewDecoder(resp.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&body); err != nil {
+		return Report{}, err
+	}
+
+	field := f.ScoreField
+	if field == "" {
+		field = "score"
+	}
+	score, err := scoreAt(body, field)
+	if err != nil {
+		return Report{}, fmt.Errorf("%s: %w", f.Name, err)
+	}
+	return Report{Score: min(max(score, 0), 100), Source: f.Name}, nil
+}
+
+func scoreAt(body any, path string) (int, error) {
+	for _, key := range strings.Split(path, ".") {
+		fields, ok := body.(map[string]any)
+		if !ok {
+			return 0, fmt.Errorf("no %s in response", path)
+		}
+		body = fields[key]
+	}
+
+	number, ok := body.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("%s is not a number", path)
+	}
+	score, err := number.Float64()
+	if err != nil {
+		return 0, err
+	}
+	return int(score), nil
+}
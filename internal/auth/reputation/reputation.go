@@ -0,0 +1,108 @@
+// Package reputation scores client IPs with an external feed and decides,
+// from configured thresholds, whether a request from one goes through,
+// needs a CAPTCHA or is refused.
+package reputation
+
+import (
+	"context"
+	"net/netip"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+)
+
+// Decision is what a reputation score means for a request.
+type Decision string
+
+const (
+	DecisionAllow Decision = "allow"
+	// DecisionStepUp lets the request through, but sign-ins and
+	// registrations need a CAPTCHA.
+	DecisionStepUp Decision = "step_up"
+	DecisionBlock  Decision = "block"
+)
+
+const defaultCacheTTL = time.Hour
+
+// Report is a feed's view of one IP. Score runs from 0 for a clean address
+// to 100 for a certainly abusive one.
+type Report struct {
+	Score  int    `json:"score"`
+	Source string `json:"source"`
+}
+
+// Provider looks IPs up in a reputation feed.
+type Provider interface {
+	Lookup(ctx context.Context, ip string) (Report, error)
+}
+
+// Assessment is the decision for one request's IP and the report behind it.
+type Assessment struct {
+	IP       string
+	Report   Report
+	Decision Decision
+}
+
+// Policy maps scores to decisions. A zero threshold disables its decision.
+type Policy struct {
+	StepUpScore int
+	BlockScore  int
+	CacheTTL    time.Duration
+}
+
+func NewPolicy(cfg *configs.Config) Policy {
+	policy := Policy{CacheTTL: defaultCacheTTL}
+	if cfg == nil {
+		return policy
+	}
+
+	r := cfg.IPReputation
+	policy.StepUpScore = r.StepUpScore
+	policy.BlockScore = r.BlockScore
+	if r.CacheTTL > 0 {
+		policy.CacheTTL = r.CacheTTL
+	}
+	return policy
+}
+
+// Decide returns the decision for a score.
+func (p Policy) Decide(score int) Decision {
+	switch {
+	case p.BlockScore > 0 && score >= p.BlockScore:
+		return DecisionBlock
+	case p.StepUpScore > 0 && score >= p.StepUpScore:
+		return DecisionStepUp
+	}
+	return DecisionAllow
+}
+
+// NewProvider returns the configured feed, or nil when none is.
+func NewProvider(cfg *configs.Config) Provider {
+	r := cfg.IPReputation
+	switch r.Provider {
+	case "abuseipdb":
+		return AbuseIPDB(r.APIKey)
+	case "feed":
+		return &HTTPFeed{Name: "feed", URL: r.FeedURL, Header: "Authorization", Key: bearer(r.APIKey), ScoreField: r.ScoreField}
+	}
+	return nil
+}
+
+// Routable reports whether ip is worth looking up. Private, loopback and
+// link-local addresses are never in a feed and belong to the deployment's
+// own network.
+func Routable(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate()
+}
+
+func bearer(key string) string {
+	if key == "" {
+		return ""
+	}
+	return "Bearer " + key
+}
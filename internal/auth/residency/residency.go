@@ -0,0 +1,90 @@
+package residency
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrForeignResident is returned for accounts whose data belongs to
+	// another region's stores.
+	ErrForeignResident = errors.New("account data is stored in another region")
+	ErrNoStores        = errors.New("no stores registered for region")
+)
+
+// Policy assigns new accounts their data region and checks that accounts
+// served here belong to the deployment's region.
+type Policy struct {
+	Region    string
+	Default   string
+	Enforce   bool
+	countries map[string]string
+}
+
+func NewPolicy(cfg *configs.Config) Policy {
+	if cfg == nil {
+		return Policy{}
+	}
+
+	r := cfg.Residency
+	policy := Policy{
+		Region:    strings.ToLower(r.Region),
+		Default:   strings.ToLower(r.Default),
+		Enforce:   r.Enforce,
+		countries: make(map[string]string, len(r.Countries)),
+	}
+	if policy.Default == "" {
+		policy.Default = policy.Region
+	}
+	for country, region := range r.Countries {
+		policy.countries[strings.ToUpper(country)] = strings.ToLower(region)
+	}
+	return policy
+}
+
+// RegionFor returns the region of an account signing up from country.
+func (p Policy) RegionFor(country string) string {
+	if region, ok := p.countries[strings.ToUpper(strings.TrimSpace(country))]; ok {
+		return region
+	}
+	return p.Default
+}
+
+// Check fails with ErrForeignResident when enforcement is on and the
+// account's region is not the deployment's. Untagged accounts predate
+// residency and are kept where they are.
+func (p Policy) Check(region string) error {
+	if !p.Enforce || region == "" || region == p.Region {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrForeignResident, region)
+}
+
+// Stores are the database and Redis instances holding one region's data.
+type Stores struct {
+	DB    *ent.Client
+	Redis *redis.Client
+}
+
+// Router resolves the stores of a region. The same service code runs in
+// every region: a deployment registers the stores it owns, and the
+// repositories and session store are built from the stores of its region.
+type Router interface {
+	Route(region string) (Stores, error)
+}
+
+// StaticRouter routes regions to a fixed set of stores.
+type StaticRouter map[string]Stores
+
+func (r StaticRouter) Route(region string) (Stores, error) {
+	stores, ok := r[strings.ToLower(region)]
+	if !ok {
+		return Stores{}, fmt.Errorf("%w %q", ErrNoStores, region)
+	}
+	return stores, nil
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/database/ent/scheduledaction"
+	"github.com/abisalde/authentication-service/internal/events"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/metrics"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// AccountActionStreamKey receives an account_action event each time a
+	// scheduled action is scheduled, cancelled or run, for audit consumers
+	// next to login_events.
+	AccountActionStreamKey = "account_action_events"
+
+	defaultAccountActionBatch = 100
+	maxAccountActionError     = 500
+)
+
+var (
+	ErrScheduledActionNotFound   = errors.New("scheduled account action not found")
+	ErrScheduledActionNotPending = errors.New("scheduled account action already ran or was cancelled")
+	ErrRunAtNotInFuture          = errors.New("scheduled account actions must run in the future")
+)
+
+var accountActionRuns = metrics.Default.NewCounterVec(
+	"scheduled_account_actions_total",
+	"Scheduled account actions run, by action and outcome: completed or failed.",
+	"action", "outcome",
+)
+
+// AccountActionEvent is the audit record of a scheduled account action.
+type AccountActionEvent struct {
+	events.Metadata
+	ActionID  int64     `json:"action_id"`
+	UserID    int64     `json:"user_id"`
+	Action    string    `json:"action"`
+	Status    string    `json:"status"`
+	RunAt     time.Time `json:"run_at"`
+	Actor     string    `json:"actor,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	EventType string    `json:"event_type"`
+}
+
+// ScheduleAccountAction schedules action on the account at runAt. Admins
+// cannot schedule actions on their own account, and deleted accounts take
+// none.
+func (s *AuthService) ScheduleAccountAction(ctx context.Context, actor *ent.User, ref string, action scheduledaction.Action, runAt time.Time, note string) (*ent.ScheduledAction, error) {
+	if !runAt.After(time.Now()) {
+		return nil, ErrRunAtNotInFuture
+	}
+	target, err := s.ResolveUserReference(repository.IncludeDeleted(ctx), ref)
+	if err != nil {
+		return nil, err
+	}
+	if actor != nil && actor.ID == target.ID {
+		return nil, ErrManageOwnAccount
+	}
+	if IsDeleted(target) {
+		return nil, ErrAlreadyDeleted
+	}
+
+	scheduled, err := s.userRepo.CreateScheduledAction(ctx, repository.ScheduledAction{
+		UserID:    target.ID,
+		Action:    action,
+		RunAt:     runAt,
+		Note:      note,
+		CreatedBy: publicIDOf(actor),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Account action scheduled", "action", action, "user_id", target.ID, "run_at", runAt)
+	s.recordAccountAction(ctx, scheduled, actor)
+	return scheduled, nil
+}
+
+// CancelScheduledAccountAction cancels an action that has not run yet.
+func (s *AuthService) CancelScheduledAccountAction(ctx context.Context, actor *ent.User, id int64) (*ent.ScheduledAction, error) {
+	cancelled, err := s.userRepo.CancelScheduledAction(ctx, id, publicIDOf(actor), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	action, err := s.userRepo.GetScheduledAction(ctx, id)
+	if ent.IsNotFound(err) {
+		return nil, ErrScheduledActionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		return nil, ErrScheduledActionNotPending
+	}
+
+	s.logger.Info("Scheduled account action cancelled", "action", action.Action, "user_id", action.UserID, "run_at", action.RunAt)
+	s.recordAccountAction(ctx, action, actor)
+	return action, nil
+}
+
+// ListScheduledAccountActions returns the actions of the account, pending
+// or not, latest due first.
+func (s *AuthService) ListScheduledAccountActions(ctx context.Context, ref string) ([]*ent.ScheduledAction, error) {
+	target, err := s.ResolveUserReference(repository.IncludeDeleted(ctx), ref)
+	if err != nil {
+		return nil, err
+	}
+	return s.userRepo.ListScheduledActions(ctx, target.ID)
+}
+
+// RunDueAccountActions runs the pending actions that are due, in batches
+// until none are left, and returns how many ran. Each action is claimed
+// before it runs, so one cancelled meanwhile is skipped. A failed action is
+// recorded on the action and does not stop the others.
+func (s *AuthService) RunDueAccountActions(ctx context.Context) (int, error) {
+	batch := defaultAccountActionBatch
+	if s.cfg != nil && s.cfg.AccountActions.Batch > 0 {
+		batch = s.cfg.AccountActions.Batch
+	}
+
+	total := 0
+	for {
+		due, err := s.userRepo.DueScheduledActions(ctx, time.Now(), batch)
+		if err != nil {
+			return total, err
+		}
+		for _, action := range due {
+			claimed, err := s.userRepo.ClaimScheduledAction(ctx, action.ID)
+			if err != nil {
+				return total, err
+			}
+			if !claimed {
+				continue
+			}
+			if err := s.finishAccountAction(ctx, action, s.runAccountAction(ctx, action)); err != nil {
+				return total, err
+			}
+			total++
+		}
+		if len(due) < batch {
+			return total, nil
+		}
+	}
+}
+
+func (s *AuthService) runAccountAction(ctx context.Context, action *ent.ScheduledAction) error {
+	target, err := s.userRepo.GetByID(repository.IncludeDeleted(ctx), action.UserID)
+	if err != nil {
+		return err
+	}
+
+	switch action.Action {
+	case scheduledaction.ActionDEACTIVATE:
+		if IsDeleted(target) || IsDeactivated(target) {
+			return nil
+		}
+		_, err = s.DeactivateAccount(ctx, target)
+	case scheduledaction.ActionREVOKE_SESSIONS:
+		err = s.RevokeUserTokens(ctx, []int64{target.ID}, time.Now(), model.RevocationReasonScheduledRevocation)
+	case scheduledaction.ActionDELETE:
+		if IsDeleted(target) {
+			return nil
+		}
+		_, err = s.DeleteAccount(ctx, target)
+	default:
+		err = fmt.Errorf("unknown action %s", action.Action)
+	}
+	return err
+}
+
+func (s *AuthService) finishAccountAction(ctx context.Context, action *ent.ScheduledAction, runErr error) error {
+	var failure string
+	outcome := "completed"
+	if runErr != nil {
+		failure, outcome = truncateError(runErr, maxAccountActionError), "failed"
+		s.logger.Error("Scheduled account action failed", "action", action.Action, "user_id", action.UserID, "error", runErr)
+	} else {
+		s.logger.Info("Scheduled account action completed", "action", action.Action, "user_id", action.UserID)
+	}
+	accountActionRuns.Inc(string(action.Action), outcome)
+
+	finished, err := s.userRepo.FinishScheduledAction(ctx, action.ID, failure, time.Now())
+	if err != nil {
+		return err
+	}
+	s.recordAccountAction(ctx, finished, nil)
+	return nil
+}
+
+func (s *AuthService) recordAccountAction(ctx context.Context, action *ent.ScheduledAction, actor *ent.User) {
+	event := AccountActionEvent{
+		Metadata:  events.Stamp(events.AccountAction),
+		ActionID:  action.ID,
+		UserID:    action.UserID,
+		Action:    string(action.Action),
+		Status:    string(action.Status),
+		RunAt:     action.RunAt,
+		Error:     action.Error,
+		Timestamp: time.Now(),
+		EventType: events.AccountAction,
+	}
+	if id := publicIDOf(actor); id != nil {
+		event.Actor = id.String()
+	}
+	eventData, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	err = s.cache.RawClient().XAdd(ctx, &redis.XAddArgs{
+		Stream: AccountActionStreamKey,
+		MaxLen: 100000,
+		Approx: true,
+		Values: map[string]interface{}{"event": eventData},
+	}).Err()
+	if err != nil {
+		s.logger.Warn("Failed to publish account action event", "action_id", action.ID, "error", err)
+	}
+}
+
+func publicIDOf(u *ent.User) *uuid.UUID {
+	if u == nil {
+		return nil
+	}
+	id := u.PublicID
+	return &id
+}
+
+func truncateError(err error, max int) string {
+	msg := err.Error()
+	if len(msg) > max {
+		return msg[:max]
+	}
+	return msg
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+// OAuthModeLink is the passwordless mode of a link started by a signed in
+// user. Clients cannot ask for it; the callback links the account to the
+// user who started it instead of looking one up.
+const OAuthModeLink model.PasswordLessMode = "LINK"
+
+// oauthLinkTTL matches the PKCE verifier, so a link expires with its flow.
+const oauthLinkTTL = 10 * time.Minute
+
+var (
+	ErrIdentityNotFound = errors.New("linked account not found")
+	ErrIdentityLinked   = errors.New("oauth account is linked to another user")
+	ErrLastSignInMethod = errors.New("cannot unlink the last way to sign in")
+	ErrLinkExpired      = errors.New("account link expired")
+)
+
+// StartLink starts linking an OAuth account to u and returns the
+// authorization URL and state, like GetAuthPKCEURL.
+func (s *OAuthService) StartLink(ctx context.Context, u *ent.User, provider string, platform model.OAuthPlatform, stateUUID string) (string, string, error) {
+	if IsServiceAccount(u) {
+		return "", "", ErrServiceAccount
+	}
+	if err := s.authService.cache.Set(ctx, oauthLinkKey(string(platform), stateUUID), u.ID, oauthLinkTTL); err != nil {
+		return "", "", err
+	}
+	return s.GetAuthPKCEURL(ctx, provider, platform, stateUUID, OAuthModeLink)
+}
+
+// LinkedAccounts lists the OAuth accounts u can sign in with.
+func (s *OAuthService) LinkedAccounts(ctx context.Context, u *ent.User) ([]*ent.UserIdentity, error) {
+	return s.authService.userRepo.ListIdentities(ctx, u.ID)
+}
+
+// UnlinkOAuthAccount unlinks one of u's OAuth accounts and returns the ones
+// left. An account without a password keeps its last one.
+func (s *OAuthService) UnlinkOAuthAccount(ctx context.Context, u *ent.User, identityID int64) ([]*ent.UserIdentity, error) {
+	repo := s.authService.userRepo
+	identities, err := repo.ListIdentities(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := make([]*ent.UserIdentity, 0, len(identities))
+	for _, identity := range identities {
+		if identity.ID != identityID {
+			remaining = append(remaining, identity)
+		}
+	}
+	switch {
+	case len(remaining) == len(identities):
+		return nil, ErrIdentityNotFound
+	case len(remaining) == 0 && u.PasswordHash == "":
+		return nil, ErrLastSignInMethod
+	}
+
+	deleted, err := repo.DeleteIdentity(ctx, u.ID, identityID)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted {
+		return nil, ErrIdentityNotFound
+	}
+
+	s.authService.logger.Info("OAuth account unlinked", "user_id", u.ID, "identity_id", identityID)
+	return remaining, nil
+}
+
+// oauthUser finds or creates the account a callback signs in to.
+func (s *OAuthService) oauthUser(ctx context.Context, mode model.PasswordLessMode, platform, stateUUID, provider string, profile *model.OAuthUserResponse) (*ent.User, error) {
+	repo := s.authService.userRepo
+	if mode == OAuthModeLink {
+		return s.completeLink(ctx, platform, stateUUID, provider, profile)
+	}
+
+	u, err := repo.FindByOAuthID(ctx, provider, profile.ID)
+	if !ent.IsNotFound(err) {
+		return u, err
+	}
+
+	merged, mergeErr := s.authService.linkByVerifiedEmail(ctx, provider, profile)
+	if merged != nil || mergeErr != nil {
+		return merged, mergeErr
+	}
+
+	if mode == model.PasswordLessModeRegister {
+		return repo.CreateUserFromOAuth(ctx, provider, profile)
+	}
+	return nil, err
+}
+
+// completeLink links the account to the user who started the link. An
+// account linked to someone else stays with them.
+func (s *OAuthService) completeLink(ctx context.Context, platform, stateUUID, provider string, profile *model.OAuthUserResponse) (*ent.User, error) {
+	key := oauthLinkKey(platform, stateUUID)
+	var userID int64
+	if err := s.authService.cache.Get(ctx, key, &userID); err != nil {
+		return nil, ErrLinkExpired
+	}
+	_ = s.authService.cache.Delete(ctx, key)
+
+	repo := s.authService.userRepo
+	owner, err := repo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := repo.GetIdentity(ctx, provider, profile.ID)
+	switch {
+	case err == nil && existing.UserID == owner.ID:
+		return owner, nil
+	case err == nil:
+		return nil, ErrIdentityLinked
+	case !ent.IsNotFound(err):
+		return nil, err
+	}
+
+	if _, err := repo.LinkIdentity(ctx, owner.ID, identityFor(provider, profile)); err != nil {
+		if ent.IsConstraintError(err) {
+			return nil, ErrIdentityLinked
+		}
+		return nil, err
+	}
+	s.authService.logger.Info("OAuth account linked", "user_id", owner.ID, "provider", provider)
+	return owner, nil
+}
+
+// linkByVerifiedEmail links the OAuth account to the user with the same
+// email, when both the provider and the user have verified it. If either
+// had not, whoever controls one side could take over the other. It returns
+// nil when there is no such user.
+func (s *AuthService) linkByVerifiedEmail(ctx context.Context, provider string, profile *model.OAuthUserResponse) (*ent.User, error) {
+	if !profile.IsEmailVerified || profile.Email == "" {
+		return nil, nil
+	}
+
+	existing, err := s.userRepo.GetByEmail(ctx, profile.Email)
+	if ent.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !existing.IsEmailVerified || IsServiceAccount(existing) {
+		return nil, nil
+	}
+
+	if _, err := s.userRepo.LinkIdentity(ctx, existing.ID, identityFor(provider, profile)); err != nil {
+		return nil, err
+	}
+	s.logger.Info("OAuth account linked by verified email", "user_id", existing.ID, "provider", provider)
+	return existing, nil
+}
+
+// canAddPassword reports whether registering with the account's email adds
+// a password to it rather than being refused: the account only signs in
+// through OAuth and its email is verified, as the registration's will be.
+func canAddPassword(u *ent.User) bool {
+	return u.PasswordHash == "" && u.IsEmailVerified && !IsServiceAccount(u) && !IsDeleted(u)
+}
+
+func identityFor(provider string, profile *model.OAuthUserResponse) repository.Identity {
+	return repository.Identity{Provider: provider, OAuthID: profile.ID, Email: profile.Email}
+}
+
+func oauthLinkKey(platform, stateUUID string) string {
+	return fmt.Sprintf("oauth:link:%s:%s", platform, stateUUID)
+}
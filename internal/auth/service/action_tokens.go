@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/abisalde/authentication-service/pkg/verification"
+)
+
+func (s *AuthService) IssueActionToken(ctx context.Context, purpose verification.Purpose, subject string, data map[string]string, ttl time.Duration) (string, error) {
+	return verification.NewOneTimeTokens(s.cache.RawClient()).Issue(ctx, purpose, subject, data, ttl)
+}
+
+func (s *AuthService) ConsumeActionToken(ctx context.Context, token string, purpose verification.Purpose) (*verification.OneTimePayload, error) {
+	return verification.NewOneTimeTokens(s.cache.RawClient()).Consume(ctx, token, purpose)
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/events"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrSessionNotFound is returned for a token ID that is not one of the
+// user's active sessions.
+var ErrSessionNotFound = errors.New("session not found")
+
+// ActiveSession is an unexpired access token issued to the user's session.
+type ActiveSession struct {
+	ID         string
+	ExpiresAt  time.Time
+	Suspicious bool
+}
+
+// activeSessionTokens returns the unexpired access tokens tracked for the
+// user, latest first. Every session token lives as long, so the first was
+// issued last, to the device holding the user's refresh token.
+func (s *AuthService) activeSessionTokens(ctx context.Context, userID int64) ([]redis.Z, error) {
+	tokens, err := s.cache.RawClient().ZRevRangeByScoreWithScores(ctx, fmt.Sprintf("%s%d", SessionTokensPrefix, userID), &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(time.Now().Unix(), 10),
+		Max: "+inf",
+	}).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	return tokens, err
+}
+
+// ActiveSessions lists the user's sessions, latest first.
+func (s *AuthService) ActiveSessions(ctx context.Context, userID int64) ([]ActiveSession, error) {
+	tokens, err := s.activeSessionTokens(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]ActiveSession, 0, len(tokens))
+	for _, token := range tokens {
+		jti, _ := token.Member.(string)
+		sessions = append(sessions, ActiveSession{
+			ID:         jti,
+			ExpiresAt:  time.Unix(int64(token.Score), 0),
+			Suspicious: s.IsSessionSuspicious(ctx, jti),
+		})
+	}
+	return sessions, nil
+}
+
+// RevokeSession signs one session of the user out by blacklisting its
+// access token. Revoking the latest session also ends the refresh session,
+// which belongs to the same device.
+func (s *AuthService) RevokeSession(ctx context.Context, userID int64, jti string) error {
+	tokens, err := s.activeSessionTokens(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for i, token := range tokens {
+		if token.Member == jti {
+			return s.revokeSessionTokens(ctx, userID, tokens[i:i+1], i == 0)
+		}
+	}
+	return ErrSessionNotFound
+}
+
+// RevokeOtherSessions signs every session of the user but currentJTI out
+// and returns how many were. Unless the current session is the latest, the
+// refresh session belongs to another device and ends too, so the current
+// one lasts until its access token expires.
+func (s *AuthService) RevokeOtherSessions(ctx context.Context, userID int64, currentJTI string) (int, error) {
+	tokens, err := s.activeSessionTokens(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	others := make([]redis.Z, 0, len(tokens))
+	for _, token := range tokens {
+		if token.Member != currentJTI {
+			others = append(others, token)
+		}
+	}
+	if len(others) == 0 {
+		return 0, nil
+	}
+
+	latest := tokens[0].Member != currentJTI
+	if err := s.revokeSessionTokens(ctx, userID, others, latest); err != nil {
+		return 0, err
+	}
+	return len(others), nil
+}
+
+// LogoutAllDevices signs the user out everywhere, the way the "this wasn't
+// me" link of a sign-in alert does.
+func (s *AuthService) LogoutAllDevices(ctx context.Context, userID int64) error {
+	return s.RevokeUserTokens(ctx, []int64{userID}, time.Now(), model.RevocationReasonUserLogout)
+}
+
+// revokeSessionTokens blacklists the tokens under the jti blacklist the
+// session validators share and emits a session_revoked event listing them.
+// endRefresh drops the user's refresh session as well.
+func (s *AuthService) revokeSessionTokens(ctx context.Context, userID int64, tokens []redis.Z, endRefresh bool) error {
+	if endRefresh {
+		if err := s.sessions.Delete(ctx, userID); err != nil {
+			return err
+		}
+	}
+
+	subject := s.sessionSubjects(ctx, []int64{userID})[userID]
+	now := time.Now()
+
+	pipe := s.cache.RawClient().TxPipeline()
+	if endRefresh {
+		pipe.Del(ctx, fmt.Sprintf("%s%d%s", RefreshCachePrefix, userID, refreshOriginSuffix),
+			fmt.Sprintf("%s%d", ProbationSessionsPrefix, userID))
+		if s.refreshRemindersEnabled() {
+			pipe.ZRem(ctx, RefreshExpiryKey, userID)
+		}
+	}
+	blacklisted, err := addSessionTokenBlacklist(ctx, pipe, userID, tokens, now)
+	if err != nil {
+		return err
+	}
+	addSessionEvent(ctx, pipe, events.SessionRevoked, userID, subject, model.RevocationReasonSessionSignedOut, blacklisted, now)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	s.logger.Info("Sessions signed out", "user_id", userID, "tokens", len(blacklisted), "refresh_ended", endRefresh)
+	return nil
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	oauthPKCE "github.com/abisalde/authentication-service/pkg/oauth"
+	"github.com/abisalde/authentication-service/pkg/verification"
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+const (
+	AppleIssuer  = "https://appleid.apple.com"
+	AppleKeysURL = AppleIssuer + "/auth/keys"
+
+	// Apple accepts client secrets valid for up to six months; every
+	// exchange signs a fresh one instead so no long-lived secret exists.
+	appleClientSecretTTL = 5 * time.Minute
+
+	appleKeysTTL          = 24 * time.Hour
+	appleKeysRefetchDelay = time.Minute
+)
+
+var appleEndpoint = oauth2.Endpoint{
+	AuthURL:   AppleIssuer + "/auth/authorize",
+	TokenURL:  AppleIssuer + "/auth/token",
+	AuthStyle: oauth2.AuthStyleInParams,
+}
+
+var ErrAppleIDToken = errors.New("apple id_token rejected")
+
+// AppleIDTokenClaims are the claims of the id_token Apple returns from the
+// code exchange. Apple has no userinfo endpoint, so this is the profile.
+type AppleIDTokenClaims struct {
+	Email          string    `json:"email"`
+	EmailVerified  appleBool `json:"email_verified"`
+	IsPrivateEmail appleBool `json:"is_private_email"`
+	Nonce          string    `json:"nonce"`
+	gojwt.RegisteredClaims
+}
+
+// appleBool accepts the "true"/"false" strings Apple sends for boolean
+// claims as well as JSON booleans.
+type appleBool bool
+
+func (b *appleBool) UnmarshalJSON(data []byte) error {
+	switch strings.Trim(string(data), `"`) {
+	case "true":
+		*b = true
+	case "false", "null", "":
+		*b = false
+	default:
+		return fmt.Errorf("invalid boolean claim %s", data)
+	}
+	return nil
+}
+
+// AppleIDTokens verifies Apple id_tokens against Apple's published keys.
+// Keys are cached for a day and refetched, at most once a minute, when a
+// token names a kid Apple rotated in.
+type AppleIDTokens struct {
+	clientID string
+	keysURL  string
+	client   *http.Client
+
+	mu          sync.Mutex
+	keys        map[string]*jwt.SigningKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+}
+
+func NewAppleIDTokens(clientID, keysURL string) *AppleIDTokens {
+	return &AppleIDTokens{
+		clientID: clientID,
+		keysURL:  keysURL,
+		client:   &http.Client{Timeout: 5 * time.Second},
+		keys:     make(map[string]*jwt.SigningKey),
+	}
+}
+
+// Verify checks the signature, issuer, audience and expiry of an id_token,
+// and its nonce when one was sent with the authorization request.
+func (v *AppleIDTokens) Verify(ctx context.Context, idToken, nonce string) (*AppleIDTokenClaims, error) {
+	if idToken == "" {
+		return nil, fmt.Errorf("%w: missing from the token response", ErrAppleIDToken)
+	}
+
+	claims := &AppleIDTokenClaims{}
+	err := jwt.ParseWithKeys(idToken, claims, func(kid string) (*jwt.SigningKey, bool) {
+		return v.key(ctx, kid)
+	}, gojwt.WithIssuer(AppleIssuer), gojwt.WithAudience(v.clientID), gojwt.WithExpirationRequired(), gojwt.WithLeeway(time.Minute))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrAppleIDToken, err)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: no subject", ErrAppleIDToken)
+	}
+	if nonce != "" && !verification.Equal(claims.Nonce, nonce) {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrAppleIDToken)
+	}
+	return claims, nil
+}
+
+func (v *AppleIDTokens) key(ctx context.Context, kid string) (*jwt.SigningKey, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	key, ok := v.keys[kid]
+	fresh := time.Since(v.fetchedAt) < appleKeysTTL
+	if (ok && fresh) || time.Since(v.attemptedAt) < appleKeysRefetchDelay {
+		return key, ok
+	}
+
+	// Keys from the last successful fetch stay usable when Apple is down.
+	if err := v.fetch(ctx); err != nil {
+		slog.Warn("Failed to fetch Apple signing keys", "error", err)
+	}
+	key, ok = v.keys[kid]
+	return key, ok
+}
+
+func (v *AppleIDTokens) fetch(ctx context.Context) error {
+	v.attemptedAt = time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.keysURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var set jwt.JWKS
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return err
+	}
+
+	keys := make(map[string]*jwt.SigningKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		key, err := jwt.ParseJWK(jwk)
+		if err != nil {
+			slog.Warn("Skipping Apple signing key", "error", err)
+			continue
+		}
+		keys[key.ID] = key
+	}
+	if len(keys) == 0 {
+		return errors.New("no usable keys")
+	}
+
+	v.keys = keys
+	v.fetchedAt = v.attemptedAt
+	return nil
+}
+
+// AppleClientSecret signs the ES256 client secret Apple expects at its token
+// endpoint: issued by the developer team for the Services ID.
+func AppleClientSecret(teamID, clientID string, key *jwt.SigningKey, now time.Time) (string, error) {
+	return jwt.SignWith(key, gojwt.RegisteredClaims{
+		Issuer:    teamID,
+		Subject:   clientID,
+		Audience:  gojwt.ClaimStrings{AppleIssuer},
+		IssuedAt:  gojwt.NewNumericDate(now),
+		ExpiresAt: gojwt.NewNumericDate(now.Add(appleClientSecretTTL)),
+	})
+}
+
+// appleUser is the user form field Apple posts to the callback on the first
+// authorization only. Its email is unsigned, so only the name is taken.
+type appleUser struct {
+	Name struct {
+		FirstName string `json:"firstName"`
+		LastName  string `json:"lastName"`
+	} `json:"name"`
+}
+
+// AppleProfile builds the provider profile from a verified id_token and the
+// user form field, which carries the name on the first sign-in only. Later
+// sign-ins keep the name captured at registration.
+func AppleProfile(claims *AppleIDTokenClaims, userField string) *model.OAuthUserResponse {
+	profile := &model.OAuthUserResponse{
+		ID:              claims.Subject,
+		Email:           claims.Email,
+		IsEmailVerified: bool(claims.EmailVerified),
+	}
+
+	var u appleUser
+	if userField != "" && json.Unmarshal([]byte(userField), &u) == nil {
+		profile.FirstName = strings.TrimSpace(u.Name.FirstName)
+		profile.LastName = strings.TrimSpace(u.Name.LastName)
+		if name := strings.TrimSpace(profile.FirstName + " " + profile.LastName); name != "" {
+			profile.Name = &name
+		}
+	}
+	return profile
+}
+
+// appleNonce binds the id_token to the authorization request through the
+// PKCE verifier cached for the state, since Apple takes no code challenge.
+func appleNonce(codeVerifier string) string {
+	return oauthPKCE.GeneratePKCEChallenge(codeVerifier)
+}
+
+type appleProvider struct {
+	config   *oauth2.Config
+	teamID   string
+	key      *jwt.SigningKey
+	idTokens *AppleIDTokens
+}
+
+// newAppleProvider returns nil when Sign in with Apple is not configured.
+func newAppleProvider(cfg *configs.Config) (*appleProvider, error) {
+	p := cfg.Providers
+	if p.AppleClientID == "" || p.AppleTeamID == "" || p.AppleKeyID == "" || p.ApplePrivateKeyFile == "" {
+		return nil, nil
+	}
+
+	pemBytes, err := os.ReadFile(p.ApplePrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("apple private key: %w", err)
+	}
+	key, err := jwt.ParseSigningKey(p.AppleKeyID, "ES256", pemBytes)
+	if err != nil {
+		return nil, fmt.Errorf("apple private key: %w", err)
+	}
+
+	return &appleProvider{
+		config: &oauth2.Config{
+			ClientID:    p.AppleClientID,
+			RedirectURL: GetRedirectUrl(cfg, "apple"),
+			Scopes:      []string{"name", "email"},
+			Endpoint:    appleEndpoint,
+		},
+		teamID:   p.AppleTeamID,
+		key:      key,
+		idTokens: NewAppleIDTokens(p.AppleClientID, AppleKeysURL),
+	}, nil
+}
+
+// authCodeURL asks Apple to post the callback as a form, which it requires
+// when the name or email scope is requested.
+func (p *appleProvider) authCodeURL(state, codeVerifier string) string {
+	return p.config.AuthCodeURL(state,
+		oauth2.SetAuthURLParam("response_mode", "form_post"),
+		oauth2.SetAuthURLParam("nonce", appleNonce(codeVerifier)))
+}
+
+// appleErrorCategory files rejected id_tokens under decode failures for
+// provider health, like unreadable profiles of the other providers.
+func appleErrorCategory(err error) OAuthErrorCategory {
+	if errors.Is(err, ErrAppleIDToken) {
+		return OAuthErrorDecode
+	}
+	return OAuthErrorExchange
+}
+
+// exchange trades the code for tokens with a freshly signed client secret
+// and verifies the returned id_token.
+func (p *appleProvider) exchange(ctx context.Context, code, codeVerifier string) (*AppleIDTokenClaims, error) {
+	secret, err := AppleClientSecret(p.teamID, p.config.ClientID, p.key, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	config := *p.config
+	config.ClientSecret = secret
+	token, err := config.Exchange(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	idToken, _ := token.Extra("id_token").(string)
+	return p.idTokens.Verify(ctx, idToken, appleNonce(codeVerifier))
+}
+
+// fetchUser reads the profile from the id_token of the code exchange, since
+// Apple has no userinfo endpoint. The name only arrives in the user form
+// field Apple posts on the first sign-in.
+func (p *appleProvider) fetchUser(ctx context.Context, callback oauthCallback) (*model.OAuthUserResponse, OAuthErrorCategory, error) {
+	claims, err := p.exchange(ctx, callback.Code, callback.Verifier)
+	if err != nil {
+		return nil, appleErrorCategory(err), err
+	}
+	return AppleProfile(claims, callback.Form("user")), "", nil
+}
+
+func (p *appleProvider) account(profile *model.OAuthUserResponse) (string, string) {
+	return "APPLE", profile.ID
+}
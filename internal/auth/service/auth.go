@@ -4,19 +4,36 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"strconv"
 	"time"
 
-	"github.com/abisalde/authentication-service/internal/auth/cookies"
+	"github.com/abisalde/authentication-service/internal/alerting"
+	"github.com/abisalde/authentication-service/internal/auth/consent"
+	"github.com/abisalde/authentication-service/internal/auth/geopolicy"
+	"github.com/abisalde/authentication-service/internal/auth/maintenance"
+	"github.com/abisalde/authentication-service/internal/auth/onboarding"
+	"github.com/abisalde/authentication-service/internal/auth/probation"
+	"github.com/abisalde/authentication-service/internal/auth/refreshbinding"
 	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/reputation"
+	"github.com/abisalde/authentication-service/internal/auth/residency"
+	"github.com/abisalde/authentication-service/internal/auth/sessionstore"
+	"github.com/abisalde/authentication-service/internal/auth/takeover"
+	"github.com/abisalde/authentication-service/internal/auth/tokenscopes"
 	"github.com/abisalde/authentication-service/internal/configs"
 	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/events"
 	"github.com/abisalde/authentication-service/internal/graph/errors"
 	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/sandbox"
+	"github.com/abisalde/authentication-service/pkg/captcha"
 	"github.com/abisalde/authentication-service/pkg/jwt"
+	app_logger "github.com/abisalde/authentication-service/pkg/logger"
 	"github.com/abisalde/authentication-service/pkg/mail"
 	"github.com/abisalde/authentication-service/pkg/verification"
+	"github.com/abisalde/authentication-service/pkg/workerpool"
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"golang.org/x/sync/singleflight"
 )
@@ -24,10 +41,23 @@ import (
 const (
 	LoginStreamKey     = "login_events"
 	LoginGroup         = "login_event_group"
-	RefreshCachePrefix = "refresh_token:"
+	RefreshCachePrefix = sessionstore.RefreshTokenPrefix
+
+	// LoginDeadLetterStreamKey receives the login events the last-login
+	// worker gave up on, with the reason, for inspection and replay.
+	LoginDeadLetterStreamKey = "login_events_dead_letter"
+
+	// maxVerificationAttempts guesses use up a verification code.
+	maxVerificationAttempts = 5
+
+	// verificationAttemptsPrefix counts the guesses made against a stored
+	// code. The counter is keyed by the code's salt, so a resent code
+	// starts over.
+	verificationAttemptsPrefix = "verification_attempts:"
 )
 
 type LoginEvent struct {
+	events.Metadata
 	UserID    int64     `json:"user_id"`
 	Timestamp time.Time `json:"timestamp"`
 	EventType string    `json:"event_type"`
@@ -37,6 +67,7 @@ type CacheService interface {
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
 	Get(ctx context.Context, key string, dest interface{}) error
 	Delete(ctx context.Context, keys ...string) error
+	DeletePattern(ctx context.Context, pattern string) (int64, error)
 	RawClient() *redis.Client
 }
 
@@ -44,27 +75,118 @@ type AuthService struct {
 	userRepo    repository.UserRepository
 	cfg         *configs.Config
 	cache       CacheService
+	sessions    sessionstore.Store
 	mailService mail.Mailer
+	onboarding  onboarding.Policy
+	consent     consent.Policy
+	probation   probation.Policy
+	binding     refreshbinding.Policy
+	maintenance maintenance.Policy
+	residency   residency.Policy
+	takeover    takeover.Policy
+	reputation  reputation.Policy
+	scopes      tokenscopes.Policy
+	alerts      *alerting.Dispatcher
+	sandbox     *sandbox.Sandbox
+	captcha     *captcha.SiteVerify
+	ipFeed      reputation.Provider // nil when no IP reputation feed is configured
+	campaigns   *workerpool.Pool
+	roleHooks   []func(userID int64)
+	logger      *slog.Logger
+	geo         geopolicy.Policy
 	sfGroup     singleflight.Group // Prevents cache stampede for concurrent requests
 }
 
 func NewAuthService(userRepo repository.UserRepository, cfg *configs.Config, cache CacheService, mailService mail.Mailer) *AuthService {
+	var sessions sessionstore.Store
+	if cache != nil {
+		sessions = sessionstore.NewRedisStore(cache.RawClient())
+	}
 	return &AuthService{
 		userRepo:    userRepo,
 		cfg:         cfg,
 		cache:       cache,
+		sessions:    sessions,
 		mailService: mailService,
+		onboarding:  onboarding.NewPolicy(cfg),
+		consent:     consent.NewPolicy(cfg),
+		probation:   probation.NewPolicy(cfg),
+		binding:     refreshbinding.NewPolicy(cfg),
+		maintenance: maintenance.NewPolicy(cfg),
+		residency:   residency.NewPolicy(cfg),
+		takeover:    takeover.NewPolicy(cfg),
+		reputation:  reputation.NewPolicy(cfg),
+		scopes:      tokenscopes.NewPolicy(cfg),
+		sandbox:     sandbox.New(cfg),
+		captcha:     captcha.NewSiteVerify(cfg.Captcha.VerifyURL, cfg.Captcha.Secret),
+		ipFeed:      reputation.NewProvider(cfg),
+		campaigns:   newReloginPool(cfg),
+		logger:      slog.Default(),
+		geo:         geopolicy.NewPolicy(cfg),
+	}
+}
+
+// SetLogger replaces the logger of the service and of the handlers and
+// middleware that log through it.
+func (s *AuthService) SetLogger(logger *slog.Logger) {
+	s.logger = app_logger.Or(logger)
+}
+
+func (s *AuthService) Logger() *slog.Logger {
+	return s.logger
+}
+
+// SetSessionStore keeps refresh sessions in store instead of the cache's
+// Redis. Revocation markers, blacklists and session events stay in Redis.
+func (s *AuthService) SetSessionStore(store sessionstore.Store) {
+	s.sessions = store
+}
+
+// NewVerificationCode returns the deterministic sandbox code for allow-listed
+// test emails and a random code for everyone else.
+func (s *AuthService) NewVerificationCode(email string) string {
+	if code, ok := s.sandbox.VerificationCode(email); ok {
+		return code
 	}
+	return verification.GenerateVerificationCode()
 }
 
+// InitiateRegistration reports whether the email is taken. The email of an
+// account that only signs in through OAuth is not: verifying it adds the
+// password to that account.
 func (s *AuthService) InitiateRegistration(ctx context.Context, input model.RegisterInput) (bool, error) {
-	return s.userRepo.ExistsByEmail(ctx, input.Email)
+	exists, err := s.userRepo.ExistsByEmail(ctx, input.Email)
+	if err != nil || !exists {
+		return exists, err
+	}
+
+	existing, err := s.userRepo.GetByEmail(ctx, input.Email)
+	if ent.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !canAddPassword(existing), nil
 }
 
+// CreatePendingUser caches the registration until its email is verified,
+// keeping only the hash of its verification code.
 func (s *AuthService) CreatePendingUser(ctx context.Context, user model.PendingUser) error {
+	if err := hashPendingCode(&user); err != nil {
+		return err
+	}
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = time.Now()
+	}
 	key := fmt.Sprintf("pending_user:%s", user.Email)
-	expiration := 30 * 24 * time.Hour
-	return s.cache.Set(ctx, key, user, expiration)
+	if err := s.cache.Set(ctx, key, user, pendingUserTTL); err != nil {
+		return err
+	}
+	if err := s.trackPendingVerification(ctx, user); err != nil {
+		s.logger.Warn("Failed to track pending verification", "email", user.Email, "error", err)
+	}
+	return nil
 }
 
 func (s *AuthService) GetPendingUser(ctx context.Context, email string) (*model.PendingUser, error) {
@@ -76,48 +198,111 @@ func (s *AuthService) GetPendingUser(ctx context.Context, email string) (*model.
 	return &user, nil
 }
 
+// UpdatePendingUser stores the pending user again; a new VerificationCode
+// replaces the stored code, resets its attempts and pushes the next nudge
+// back. The registration keeps its expiry, the code has its own.
 func (s *AuthService) UpdatePendingUser(ctx context.Context, user model.PendingUser) error {
+	codeSent := user.VerificationCode != ""
+	if err := hashPendingCode(&user); err != nil {
+		return err
+	}
+	ttl := time.Until(user.CreatedAt.Add(pendingUserTTL))
+	if ttl <= 0 {
+		return errors.UserNotFound
+	}
 	key := fmt.Sprintf("pending_user:%s", user.Email)
-	return s.cache.Set(ctx, key, user, time.Until(user.ExpiresAt))
+	if err := s.cache.Set(ctx, key, user, ttl); err != nil {
+		return err
+	}
+
+	if codeSent || user.NudgesStopped {
+		pipe := s.cache.RawClient().TxPipeline()
+		s.scheduleNudge(ctx, pipe, user)
+		if _, err := pipe.Exec(ctx); err != nil {
+			s.logger.Warn("Failed to schedule verification nudge", "email", user.Email, "error", err)
+		}
+	}
+	return nil
+}
+
+func hashPendingCode(user *model.PendingUser) error {
+	if user.VerificationCode == "" {
+		return nil
+	}
+	code, err := verification.NewStoredCode(user.VerificationCode)
+	if err != nil {
+		return err
+	}
+	user.Code, user.VerificationCode = code, ""
+	return nil
 }
 
 func (s *AuthService) DeletePendingUser(ctx context.Context, email string) error {
 	key := fmt.Sprintf("pending_user:%s", email)
+	if err := s.untrackPendingVerification(ctx, email); err != nil {
+		s.logger.Warn("Failed to untrack pending verification", "email", email, "error", err)
+	}
 	return s.cache.Delete(ctx, key)
 }
 
 func (s *AuthService) StoreVerificationDetails(ctx context.Context, email, code string) error {
 	key := fmt.Sprintf("verification_code:%s", email)
 	expiration := 5 * time.Minute
-	err := s.cache.Set(ctx, key, code, expiration)
-
+	stored, err := verification.NewStoredCode(code)
 	if err != nil {
 		return err
 	}
-
-	return nil
+	return s.cache.Set(ctx, key, stored, expiration)
 }
 
 func (s *AuthService) CleanupTemporaryData(ctx context.Context, email string) error {
 	codeKey := fmt.Sprintf("verification_code:%s", email)
 	pendingUserKey := fmt.Sprintf("pending_user:%s", email)
 
-	if err := s.cache.Delete(ctx, codeKey); err != nil {
-		return err
+	return s.cache.Delete(ctx, codeKey, pendingUserKey)
+}
+
+// claimVerificationAttempt counts a guess against the code stored under key
+// before the guess is compared. INCR keeps the count atomic, so parallel
+// guesses cannot all read a count below maxVerificationAttempts; the counter
+// expires with the code. It reports whether the guess may be compared.
+func (s *AuthService) claimVerificationAttempt(ctx context.Context, key string, code verification.StoredCode) (bool, error) {
+	ttl, err := s.cache.RawClient().PTTL(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	if ttl <= 0 {
+		ttl = pendingUserTTL
+	}
+
+	counter := verificationAttemptsPrefix + key + ":" + code.Salt
+	pipe := s.cache.RawClient().TxPipeline()
+	attempts := pipe.Incr(ctx, counter)
+	pipe.PExpire(ctx, counter, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
 	}
-	return s.cache.Delete(ctx, pendingUserKey)
+	return attempts.Val() <= maxVerificationAttempts, nil
 }
 
 func (s *AuthService) ValidateVerificationCode(ctx context.Context, email, code string) (*model.PendingUser, error) {
 	codeKey := fmt.Sprintf("verification_code:%s", email)
 	pendingUserKey := fmt.Sprintf("pending_user:%s", email)
 
-	var storedCode string
-	err := s.cache.Get(ctx, codeKey, &storedCode)
+	var stored verification.StoredCode
+	err := s.cache.Get(ctx, codeKey, &stored)
 	if err != nil {
 		return nil, errors.OTPCodeExpire
 	}
-	if storedCode != code {
+	allowed, err := s.claimVerificationAttempt(ctx, codeKey, stored)
+	if err != nil {
+		s.logger.Warn("Failed to count verification attempt", "email", email, "error", err)
+		return nil, errors.OTPCodeNotValid
+	}
+	if !allowed {
+		return nil, errors.OTPCodeExpire
+	}
+	if !stored.Matches(code) {
 		return nil, errors.OTPCodeNotValid
 	}
 
@@ -132,6 +317,9 @@ func (s *AuthService) ValidateVerificationCode(ctx context.Context, email, code
 	return pendingUser, nil
 }
 
+// VerifyCodeAndCreateUser creates the account of a pending user once its
+// code matches. After maxVerificationAttempts guesses the code stops
+// working until a new one is sent.
 func (s *AuthService) VerifyCodeAndCreateUser(ctx context.Context, email, code string) (*ent.User, error) {
 
 	pendingUser, err := s.GetPendingUser(ctx, email)
@@ -139,28 +327,70 @@ func (s *AuthService) VerifyCodeAndCreateUser(ctx context.Context, email, code s
 		return nil, errors.UserNotFound
 	}
 
-	if pendingUser.VerificationCode != code {
+	if time.Now().After(pendingUser.ExpiresAt) {
 		return nil, errors.OTPCodeExpire
 	}
-	if time.Now().After(pendingUser.ExpiresAt) {
+	key := fmt.Sprintf("pending_user:%s", pendingUser.Email)
+	allowed, err := s.claimVerificationAttempt(ctx, key, pendingUser.Code)
+	if err != nil {
+		s.logger.Warn("Failed to count verification attempt", "email", pendingUser.Email, "error", err)
+		return nil, errors.OTPCodeNotValid
+	}
+	if !allowed {
+		return nil, errors.OTPCodeExpire
+	}
+	if !pendingUser.Code.Matches(code) {
 		return nil, errors.OTPCodeNotValid
 	}
 
+	if existing, err := s.userRepo.GetByEmail(ctx, pendingUser.Email); err == nil && canAddPassword(existing) {
+		user, err := s.addPassword(ctx, existing, pendingUser.HashPassword)
+		if err == nil {
+			recordVerification(pendingUser)
+		}
+		return user, err
+	}
+
 	user, err := s.userRepo.CreateNewUser(ctx, &model.RegisterVerifiedUser{
 		Email:           pendingUser.Email,
 		Password:        pendingUser.HashPassword,
 		IsEmailVerified: true,
+		FirstName:       pendingUser.FirstName,
+		LastName:        pendingUser.LastName,
+		TermsAcceptedAt: pendingUser.TermsAcceptedAt,
+		TermsVersion:    pendingUser.TermsVersion,
+		Country:         pendingUser.Country,
+		MarketingOptIn:  pendingUser.MarketingOptIn,
 	})
 	if err != nil {
 		return nil, errors.NewTypedError("Something went wrong, Please try again", model.ErrorTypeInternalServerError, map[string]interface{}{"METHOD": "USER_CREATION"})
 	}
 
+	if synced, syncErr := s.SyncOnboardingStep(ctx, user); syncErr == nil {
+		user = synced
+	}
+
 	_ = s.CleanupTemporaryData(ctx, email)
 	_ = s.DeletePendingUser(ctx, email)
+	recordVerification(pendingUser)
 
 	return user, nil
 }
 
+// addPassword completes a registration for the email of an OAuth account by
+// letting the account sign in with the password too.
+func (s *AuthService) addPassword(ctx context.Context, u *ent.User, passwordHash string) (*ent.User, error) {
+	if err := s.userRepo.UpdateNewPassword(ctx, u.ID, passwordHash); err != nil {
+		return nil, errors.NewTypedError("Something went wrong, Please try again", model.ErrorTypeInternalServerError, map[string]interface{}{"METHOD": "USER_CREATION"})
+	}
+	s.logger.Info("Password added by registering with the account email", "user_id", u.ID)
+
+	_ = s.CleanupTemporaryData(ctx, u.Email)
+	_ = s.DeletePendingUser(ctx, u.Email)
+
+	return s.userRepo.GetByID(ctx, u.ID)
+}
+
 func (s *AuthService) InitiateLogin(ctx context.Context, email string) (*ent.User, error) {
 	return s.userRepo.GetByEmail(ctx, email)
 }
@@ -175,9 +405,10 @@ func (s *AuthService) UpdateLastLogin(ctx context.Context, userID int64) error {
 
 func (s *AuthService) PublishLoginEvent(ctx context.Context, userID int64) error {
 	event := LoginEvent{
+		Metadata:  events.Stamp(events.UserLastLogin),
 		UserID:    userID,
 		Timestamp: time.Now(),
-		EventType: "user_last_login",
+		EventType: events.UserLastLogin,
 	}
 
 	eventData, err := json.Marshal(event)
@@ -194,14 +425,47 @@ func (s *AuthService) PublishLoginEvent(ctx context.Context, userID int64) error
 	return err
 }
 
+// BlacklistToken blacklists the JWT by its jti for ttl, so Redis never holds
+// the token itself.
 func (s *AuthService) BlacklistToken(ctx context.Context, token string, ttl time.Duration) error {
-	key := fmt.Sprintf("blacklist:%s", token)
-	return s.cache.Set(ctx, key, "blacklisted", ttl)
+	jti, _ := jwt.GetTokenID(token)
+	if jti == "" {
+		return ErrTokenWithoutID
+	}
+	return s.cache.Set(ctx, BlacklistedTokenIDPrefix+jti, "blacklisted", ttl)
 }
 
+// RevokeAccessToken deletes the claims of opaque tokens and blacklists JWTs
+// for the rest of their lifetime.
+func (s *AuthService) RevokeAccessToken(ctx context.Context, token string) {
+	if jwt.IsOpaqueToken(token) {
+		if err := jwt.RevokeOpaqueToken(ctx, token); err != nil {
+			s.logger.Warn("Failed to revoke opaque token", "error", err)
+		}
+		return
+	}
+
+	if remainingTTL := jwt.GetTokenRemainingTTL(token); remainingTTL > 0 {
+		if err := s.BlacklistToken(ctx, token, remainingTTL); err != nil {
+			s.logger.Warn("Failed to blacklist access token", "error", err)
+		}
+	}
+}
+
+// IsTokenBlacklisted reports whether the token's jti was blacklisted at
+// logout or when its session was revoked.
 func (s *AuthService) IsTokenBlacklisted(ctx context.Context, token string) bool {
 	var val string
-	err := s.cache.Get(ctx, fmt.Sprintf("blacklist:%s", token), &val)
+	jti, _ := jwt.GetTokenID(token)
+	if jti != "" {
+		if err := s.cache.Get(ctx, BlacklistedTokenIDPrefix+jti, &val); err == nil && val == "blacklisted" {
+			return true
+		}
+	}
+
+	// Keys written before blacklisting by jti were named after the whole
+	// token; they expire within one access token lifetime.
+	err := s.cache.Get(ctx, legacyBlacklistPrefix+token, &val)
 	return err == nil && val == "blacklisted"
 }
 
@@ -209,7 +473,14 @@ func (s *AuthService) UpdateUserPassword(ctx context.Context, userID int64, pass
 	return s.userRepo.UpdateNewPassword(ctx, userID, passwordHash)
 }
 
-func (s *AuthService) StoreRefreshToken(ctx context.Context, userID int64, token string) (string, error) {
+// StoreRefreshToken keeps the session in this deployment's session store,
+// refusing accounts whose residency places their sessions in another region.
+func (s *AuthService) StoreRefreshToken(ctx context.Context, u *ent.User, token string) (string, error) {
+	if err := s.residency.Check(u.Residency); err != nil {
+		s.logger.Warn("Residency violation, refusing to store the session", "user_id", u.ID, "region", u.Residency)
+		return "", err
+	}
+	userID := u.ID
 
 	encryptedToken, err := verification.EncryptToken(token)
 	if err != nil {
@@ -221,38 +492,61 @@ func (s *AuthService) StoreRefreshToken(ctx context.Context, userID int64, token
 		return "", err
 	}
 
-	cacheKey := fmt.Sprintf("%s%d", RefreshCachePrefix, userID)
-	err = s.cache.Set(ctx, cacheKey, encryptedToken, cookies.RefreshTokenExpiry)
-
-	if err != nil {
+	now := time.Now()
+	ttl := s.refreshTokenTTL()
+	session := sessionstore.Session{
+		UserID:         userID,
+		EncryptedToken: encryptedToken,
+		TokenHash:      hashedToken,
+		ExpiresAt:      now.Add(ttl),
+		EndsAt:         now.Add(max(ttl, s.sessionLifetime())),
+	}
+	// The idle clock starts with the session, so enabling the timeout
+	// never signs out sessions that were used without being tracked.
+	if s.idleTimeoutEnabled() {
+		session.StartedAt, session.LastActiveAt = now, now
+	}
+	if err := s.sessions.Create(ctx, session); err != nil {
 		return "", err
 	}
 
-	hashKey := fmt.Sprintf("%s%s", cacheKey, ":hash")
-	err = s.cache.Set(ctx, hashKey, hashedToken, cookies.RefreshTokenExpiry)
-
-	if err != nil {
+	pipe := s.cache.RawClient().TxPipeline()
+	s.addRefreshExpiry(ctx, pipe, userID, now.Add(ttl))
+	if err := s.addRefreshOrigin(ctx, pipe, userID); err != nil {
+		return "", err
+	}
+	if err := s.addRefreshCanary(ctx, pipe, u); err != nil {
+		return "", err
+	}
+	addSessionEvent(ctx, pipe, events.SessionCreated, userID, u.PublicID.String(), "", nil, now)
+	if _, err := pipe.Exec(ctx); err != nil {
 		return "", err
 	}
 
 	return hashedToken, nil
 }
 
-func (s *AuthService) ValidateRefreshToken(ctx context.Context, userID int64, token string) (bool, error) {
-	cacheKey := fmt.Sprintf("%s%d", RefreshCachePrefix, userID)
-	hashKey := fmt.Sprintf("%s%s", cacheKey, ":hash")
-
-	var storedHash string
-	err := s.cache.Get(ctx, hashKey, &storedHash)
+// setCached queues value on pipe encoded the way CacheService.Set stores it,
+// so it reads back with CacheService.Get.
+func setCached(ctx context.Context, pipe redis.Pipeliner, key string, value interface{}, ttl time.Duration) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	pipe.Set(ctx, key, encoded, ttl)
+	return nil
+}
 
+func (s *AuthService) ValidateRefreshToken(ctx context.Context, userID int64, token string) (bool, error) {
+	session, err := s.sessions.Get(ctx, userID)
 	if err != nil {
-		log.Printf("Error from stored hash %v", err)
+		s.logger.Debug("Failed to read the stored refresh token", "user_id", userID, "error", err)
 		return false, err
 	}
 
-	valid, err := verification.VerifyTokenHash(token, storedHash)
-	log.Printf("Error from VerifyTokenHash %v", err)
+	valid, err := verification.VerifyTokenHash(token, session.TokenHash)
 	if err != nil || !valid {
+		s.logger.Debug("Refresh token did not match the stored hash", "user_id", userID, "error", err)
 		return false, err
 	}
 
@@ -260,25 +554,39 @@ func (s *AuthService) ValidateRefreshToken(ctx context.Context, userID int64, to
 }
 
 func (s *AuthService) InvalidateRefreshToken(ctx context.Context, userID int64) error {
-	cacheKey := fmt.Sprintf("%s%d", RefreshCachePrefix, userID)
-	hashKey := fmt.Sprintf("%s%s", cacheKey, ":hash")
-
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	return s.cache.Delete(ctx, cacheKey, hashKey)
+	if err := s.sessions.Delete(ctx, userID); err != nil {
+		return err
+	}
+
+	subject := s.sessionSubjects(ctx, []int64{userID})[userID]
+	tokens := s.sessionTokens(ctx, []int64{userID})[userID]
+	now := time.Now()
+
+	pipe := s.cache.RawClient().TxPipeline()
+	pipe.Del(ctx, fmt.Sprintf("%s%d%s", RefreshCachePrefix, userID, refreshOriginSuffix),
+		fmt.Sprintf("%s%d", ProbationSessionsPrefix, userID))
+	if s.refreshRemindersEnabled() {
+		pipe.ZRem(ctx, RefreshExpiryKey, userID)
+	}
+	blacklisted, err := addSessionTokenBlacklist(ctx, pipe, userID, tokens, now)
+	if err != nil {
+		return err
+	}
+	addSessionEvent(ctx, pipe, events.SessionRevoked, userID, subject, "", blacklisted, now)
+	_, err = pipe.Exec(ctx)
+	return err
 }
 
 func (s *AuthService) CheckIfRefreshTokenMatchClaims(ctx context.Context, uid int64) error {
-	cacheKey := fmt.Sprintf("%s%d", RefreshCachePrefix, uid)
-	var token string
-	err := s.cache.Get(ctx, cacheKey, &token)
-
+	session, err := s.sessions.Get(ctx, uid)
 	if err != nil {
 		return errors.ErrSomethingWentWrong
 	}
 
-	decryptedToken, err := verification.DecryptToken(token)
+	decryptedToken, err := verification.DecryptToken(session.EncryptedToken)
 	if err != nil {
 		return errors.ErrSomethingWentWrong
 	}
@@ -288,13 +596,14 @@ func (s *AuthService) CheckIfRefreshTokenMatchClaims(ctx context.Context, uid in
 		return errors.InvalidToken
 	}
 
-	tokenUserID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	tokenUser, err := s.ResolveUserReference(ctx, claims.Subject)
 	if err != nil {
 		return errors.UserNotFound
 	}
+	tokenUserID := tokenUser.ID
 
 	if tokenUserID != uid {
-		log.Printf("user ID mismatch: expected %d, got %d", uid, tokenUserID)
+		s.logger.Warn("Refresh token belongs to another user", "user_id", uid, "token_user_id", tokenUserID)
 		errName := fmt.Sprintf("user ID mismatch: expected %d, got %d", uid, tokenUserID)
 		return errors.NewTypedError(errName, model.ErrorTypeBadRequest, map[string]interface{}{
 			"code": "BAD_REQUEST",
@@ -304,6 +613,20 @@ func (s *AuthService) CheckIfRefreshTokenMatchClaims(ctx context.Context, uid in
 	return nil
 }
 
+// ResolveUserReference looks a user up by public UUID. Numeric IDs are still
+// accepted so tokens and clients from before public IDs keep working.
+func (s *AuthService) ResolveUserReference(ctx context.Context, ref string) (*ent.User, error) {
+	if publicID, err := uuid.Parse(ref); err == nil {
+		return s.userRepo.GetByPublicID(ctx, publicID)
+	}
+
+	userID, err := strconv.ParseInt(ref, 10, 64)
+	if err != nil {
+		return nil, errors.UserNotFound
+	}
+	return s.userRepo.GetByID(ctx, userID)
+}
+
 func (s *AuthService) FindUsers(ctx context.Context, role *model.UserRole, pagination *model.PaginationInput) (*model.UserConnection, error) {
 	return s.userRepo.FindAllUsers(ctx, role, pagination)
 }
@@ -313,11 +636,11 @@ func (s *AuthService) CheckUsernameAvailability(ctx context.Context, username st
 	var exists bool
 	err := s.cache.Get(ctx, cacheKey, &exists)
 	if err == nil {
-		log.Printf("Cache HIT for username: %s", username)
+		s.logger.Debug("Username availability cache hit", "username", username)
 		return !exists, nil
 	}
 
-	log.Printf("Cache MISS for username: %s, querying database", username)
+	s.logger.Debug("Username availability cache miss", "username", username)
 	result, err, shared := s.sfGroup.Do(cacheKey, func() (interface{}, error) {
 		exists, err := s.userRepo.ExistsByUsername(ctx, username)
 		if err != nil {
@@ -325,16 +648,16 @@ func (s *AuthService) CheckUsernameAvailability(ctx context.Context, username st
 		}
 
 		if cacheErr := s.cache.Set(ctx, cacheKey, exists, 5*time.Minute); cacheErr != nil {
-			log.Printf("Warning: Failed to cache username '%s': %v", username, cacheErr)
+			s.logger.Warn("Failed to cache username availability", "username", username, "error", cacheErr)
 		} else {
-			log.Printf("Cached username '%s' (exists=%v)", username, exists)
+			s.logger.Debug("Cached username availability", "username", username, "exists", exists)
 		}
 
 		return !exists, nil
 	})
 
 	if shared {
-		log.Printf("Singleflight: Request for '%s' shared DB query result", username)
+		s.logger.Debug("Username availability query shared", "username", username)
 	}
 
 	if err != nil {
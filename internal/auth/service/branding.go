@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+)
+
+// Fallbacks for deployments that configure no default brand.
+const (
+	defaultBrandLogoURL      = "https://abisalde.dev/image/email-logo.png"
+	defaultBrandPrimaryColor = "#ffffff"
+)
+
+var ErrOrganizationNotFound = errors.New("organization not found")
+
+// Brand is what auth emails and redirect pages show. An empty SupportEmail
+// asks recipients to reply to the email instead.
+type Brand struct {
+	ProductName  string `json:"productName"`
+	LogoURL      string `json:"logoUrl"`
+	PrimaryColor string `json:"primaryColor"`
+	SupportEmail string `json:"supportEmail,omitempty"`
+}
+
+// DefaultBrand is the configured brand, used for accounts outside any
+// organization and for fields organizations leave empty.
+func (s *AuthService) DefaultBrand() Brand {
+	b := Brand{
+		ProductName:  s.cfg.Branding.ProductName,
+		LogoURL:      s.cfg.Branding.LogoURL,
+		PrimaryColor: s.cfg.Branding.PrimaryColor,
+		SupportEmail: s.cfg.Branding.SupportEmail,
+	}
+	if b.LogoURL == "" {
+		b.LogoURL = defaultBrandLogoURL
+	}
+	if b.PrimaryColor == "" {
+		b.PrimaryColor = defaultBrandPrimaryColor
+	}
+	return b
+}
+
+// BrandForUser is the brand of the user's organization. Lookup failures
+// fall back to the default brand so emails still go out.
+func (s *AuthService) BrandForUser(ctx context.Context, u *ent.User) Brand {
+	if u == nil || u.OrganizationID == nil {
+		return s.DefaultBrand()
+	}
+
+	org, err := s.userRepo.GetOrganization(ctx, *u.OrganizationID)
+	if err != nil {
+		s.logger.Warn("Failed to load organization for branding", "organization_id", *u.OrganizationID, "error", err)
+		return s.DefaultBrand()
+	}
+	return s.brandOf(org)
+}
+
+// BrandForOrganization is the brand redirect pages show for the
+// organization with the given slug.
+func (s *AuthService) BrandForOrganization(ctx context.Context, slug string) (Brand, error) {
+	org, err := s.GetOrganization(ctx, slug)
+	if err != nil {
+		return Brand{}, err
+	}
+	return s.brandOf(org), nil
+}
+
+// GetOrganization looks an organization up by slug.
+func (s *AuthService) GetOrganization(ctx context.Context, slug string) (*ent.Organization, error) {
+	org, err := s.userRepo.GetOrganizationBySlug(ctx, strings.ToLower(strings.TrimSpace(slug)))
+	if ent.IsNotFound(err) {
+		return nil, ErrOrganizationNotFound
+	}
+	return org, err
+}
+
+func (s *AuthService) brandOf(org *ent.Organization) Brand {
+	b := s.DefaultBrand()
+	if org.ProductName != "" {
+		b.ProductName = org.ProductName
+	}
+	if org.LogoURL != "" {
+		b.LogoURL = org.LogoURL
+	}
+	if org.PrimaryColor != "" {
+		b.PrimaryColor = org.PrimaryColor
+	}
+	if org.SupportEmail != "" {
+		b.SupportEmail = org.SupportEmail
+	}
+	return b
+}
+
+// SaveOrganizationBranding creates the organization or replaces its
+// branding.
+func (s *AuthService) SaveOrganizationBranding(ctx context.Context, input repository.OrganizationBranding) (*ent.Organization, error) {
+	input.Slug = strings.ToLower(strings.TrimSpace(input.Slug))
+	input.Name = strings.TrimSpace(input.Name)
+	input.ProductName = strings.TrimSpace(input.ProductName)
+	input.LogoURL = strings.TrimSpace(input.LogoURL)
+	input.PrimaryColor = strings.TrimSpace(input.PrimaryColor)
+	input.SupportEmail = strings.TrimSpace(input.SupportEmail)
+
+	return s.userRepo.SaveOrganization(ctx, input)
+}
+
+// AssignOrganization moves the referenced user into the organization with
+// the given slug, or out of any when slug is nil. Service accounts stay
+// with the organization that created them.
+func (s *AuthService) AssignOrganization(ctx context.Context, ref string, slug *string) (*ent.User, error) {
+	target, err := s.ResolveUserReference(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if IsServiceAccount(target) {
+		return nil, ErrServiceAccount
+	}
+
+	var organizationID *int64
+	if slug != nil {
+		org, err := s.GetOrganization(ctx, *slug)
+		if err != nil {
+			return nil, err
+		}
+		organizationID = &org.ID
+	}
+
+	return s.userRepo.SetUserOrganization(ctx, target.ID, organizationID)
+}
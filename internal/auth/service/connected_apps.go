@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/cookies"
+	"github.com/abisalde/authentication-service/internal/events"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// ConnectedAppsPrefix holds the user's grants to API clients, one field
+	// per client. It slides with the grants' refresh tokens.
+	ConnectedAppsPrefix = "connected_apps:"
+	// ConnectedAppTokensPrefix keeps the jti of each unexpired access token
+	// issued to one client for the user, scored by expiry.
+	ConnectedAppTokensPrefix = "connected_app_tokens:"
+	// ConnectedAppRevokedPrefix refuses refreshes of a client the user
+	// revoked until its refresh token would have expired or the client is
+	// granted access again.
+	ConnectedAppRevokedPrefix = "connected_app_revoked:"
+)
+
+var (
+	ErrConnectedAppNotFound = errors.New("connected app not found")
+	ErrConnectedAppRevoked  = errors.New("connected app access was revoked")
+)
+
+// AppGrant is access the user granted an API client by signing in through
+// it: which scopes, when it was first granted and when the client last
+// obtained a token.
+type AppGrant struct {
+	Client     string    `json:"client"`
+	Scopes     []string  `json:"scopes"`
+	GrantedAt  time.Time `json:"granted_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+func connectedAppsKey(userID int64) string {
+	return fmt.Sprintf("%s%d", ConnectedAppsPrefix, userID)
+}
+
+func connectedAppTokensKey(userID int64, client string) string {
+	return fmt.Sprintf("%s%d:%s", ConnectedAppTokensPrefix, userID, client)
+}
+
+func connectedAppRevokedKey(userID int64, client string) string {
+	return fmt.Sprintf("%s%d:%s", ConnectedAppRevokedPrefix, userID, client)
+}
+
+// RecordAppGrant records the access token issued at sign-in or refresh as a
+// grant to the API client behind ctx. Requests without a client are the
+// user's own sessions and are not recorded. Signing in through a revoked
+// client grants it access again.
+func (s *AuthService) RecordAppGrant(ctx context.Context, userID int64, scopes []string, accessToken string) error {
+	client := auth.GetAutomationClient(ctx)
+	if client == "" {
+		return nil
+	}
+
+	rdb := s.cache.RawClient()
+	now := time.Now()
+	grant := AppGrant{Client: client, Scopes: scopes, GrantedAt: now, LastUsedAt: now}
+	raw, err := rdb.HGet(ctx, connectedAppsKey(userID), client).Result()
+	if err != nil && err != redis.Nil {
+		return err
+	}
+	var previous AppGrant
+	if raw != "" && json.Unmarshal([]byte(raw), &previous) == nil {
+		grant.GrantedAt = previous.GrantedAt
+	}
+	encoded, err := json.Marshal(grant)
+	if err != nil {
+		return err
+	}
+
+	pipe := rdb.TxPipeline()
+	pipe.HSet(ctx, connectedAppsKey(userID), client, encoded)
+	pipe.Expire(ctx, connectedAppsKey(userID), cookies.RefreshTokenExpiry)
+	pipe.Del(ctx, connectedAppRevokedKey(userID, client))
+	if jti, expiresAt := jwt.GetTokenID(accessToken); jti != "" {
+		tokensKey := connectedAppTokensKey(userID, client)
+		pipe.ZRemRangeByScore(ctx, tokensKey, "-inf", strconv.FormatInt(now.Unix(), 10))
+		pipe.ZAdd(ctx, tokensKey, redis.Z{Score: float64(expiresAt.Unix()), Member: jti})
+		pipe.Expire(ctx, tokensKey, cookies.AccessTokenExpiry)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// CheckConnectedApp returns ErrConnectedAppRevoked when the user revoked the
+// API client behind ctx, so its refresh token no longer yields tokens.
+func (s *AuthService) CheckConnectedApp(ctx context.Context, userID int64) error {
+	client := auth.GetAutomationClient(ctx)
+	if client == "" {
+		return nil
+	}
+	revoked, err := s.cache.RawClient().Exists(ctx, connectedAppRevokedKey(userID, client)).Result()
+	if err != nil {
+		s.logger.Warn("Failed to check the connected app", "user_id", userID, "client", client, "error", err)
+		return nil
+	}
+	if revoked > 0 {
+		return ErrConnectedAppRevoked
+	}
+	return nil
+}
+
+// ConnectedApps returns the user's grants, the most recently used first.
+func (s *AuthService) ConnectedApps(ctx context.Context, userID int64) ([]AppGrant, error) {
+	fields, err := s.cache.RawClient().HGetAll(ctx, connectedAppsKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	grants := make([]AppGrant, 0, len(fields))
+	for client, raw := range fields {
+		var grant AppGrant
+		if err := json.Unmarshal([]byte(raw), &grant); err != nil {
+			s.logger.Warn("Skipping an unreadable connected app", "user_id", userID, "client", client, "error", err)
+			continue
+		}
+		grants = append(grants, grant)
+	}
+	sort.Slice(grants, func(i, j int) bool {
+		if grants[i].LastUsedAt.Equal(grants[j].LastUsedAt) {
+			return grants[i].Client < grants[j].Client
+		}
+		return grants[i].LastUsedAt.After(grants[j].LastUsedAt)
+	})
+	return grants, nil
+}
+
+// RevokeConnectedApp ends the client's access to the user's account: its
+// access tokens are blacklisted, its grant removed and its refresh token
+// refused. The user's own sessions are left signed in.
+func (s *AuthService) RevokeConnectedApp(ctx context.Context, userID int64, client string) error {
+	rdb := s.cache.RawClient()
+	if err := rdb.HGet(ctx, connectedAppsKey(userID), client).Err(); err == redis.Nil {
+		return ErrConnectedAppNotFound
+	} else if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	tokensKey := connectedAppTokensKey(userID, client)
+	tokens, err := rdb.ZRangeByScoreWithScores(ctx, tokensKey, &redis.ZRangeBy{Min: "(" + strconv.FormatInt(now.Unix(), 10), Max: "+inf"}).Result()
+	if err != nil && err != redis.Nil {
+		return err
+	}
+	subject := s.sessionSubjects(ctx, []int64{userID})[userID]
+
+	pipe := rdb.TxPipeline()
+	pipe.HDel(ctx, connectedAppsKey(userID), client)
+	pipe.Del(ctx, tokensKey)
+	pipe.Set(ctx, connectedAppRevokedKey(userID, client), now.Unix(), cookies.RefreshTokenExpiry)
+	blacklisted, err := addSessionTokenBlacklist(ctx, pipe, userID, tokens, now)
+	if err != nil {
+		return err
+	}
+	addSessionEvent(ctx, pipe, events.SessionRevoked, userID, subject, model.RevocationReasonAppAccessRevoked, blacklisted, now)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	s.logger.Info("Connected app revoked", "user_id", userID, "client", client, "tokens", len(blacklisted))
+	return nil
+}
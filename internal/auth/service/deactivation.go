@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+const (
+	defaultDeletionRetention = 30 * 24 * time.Hour
+	defaultPurgeBatch        = 500
+)
+
+var ErrAlreadyDeactivated = errors.New("account already deactivated")
+
+// IsDeactivated reports whether the owner paused the account. Signing in
+// again lifts the deactivation; until then every token is rejected.
+func IsDeactivated(u *ent.User) bool {
+	return u != nil && u.DeactivatedAt != nil
+}
+
+// DeactivateAccount pauses the owner's account and signs it out on every
+// device. The data is kept and the next successful sign-in restores it.
+func (s *AuthService) DeactivateAccount(ctx context.Context, u *ent.User) (*ent.User, error) {
+	if IsDeactivated(u) {
+		return nil, ErrAlreadyDeactivated
+	}
+
+	deactivatedAt := time.Now()
+	deactivated, err := s.userRepo.Deactivate(ctx, u.ID, deactivatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.RevokeUserTokens(ctx, []int64{u.ID}, deactivatedAt, model.RevocationReasonAccountDeactivated); err != nil {
+		s.logger.Error("Failed to revoke tokens of deactivated user", "user_id", u.ID, "error", err)
+	}
+	return deactivated, nil
+}
+
+// DeleteAccount soft deletes the owner's account. It disappears from every
+// lookup at once and is purged after the deletion retention; until then an
+// admin can reactivate it.
+func (s *AuthService) DeleteAccount(ctx context.Context, u *ent.User) (*ent.User, error) {
+	deletedAt := time.Now()
+	deleted, err := s.userRepo.SoftDelete(ctx, u.ID, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.RevokeUserTokens(ctx, []int64{u.ID}, deletedAt, model.RevocationReasonAccountDeleted); err != nil {
+		s.logger.Error("Failed to revoke tokens of deleted user", "user_id", u.ID, "error", err)
+	}
+	_ = s.CleanupTemporaryData(ctx, deleted.Email)
+
+	return deleted, nil
+}
+
+// LiftDeactivation restores a deactivated account on sign-in. Other
+// accounts are returned unchanged.
+func (s *AuthService) LiftDeactivation(ctx context.Context, u *ent.User) (*ent.User, error) {
+	if !IsDeactivated(u) {
+		return u, nil
+	}
+	return s.userRepo.ClearDeactivation(ctx, u.ID)
+}
+
+// PurgeDeletedAccounts hard deletes accounts whose retention has passed, in
+// batches until none are left.
+func (s *AuthService) PurgeDeletedAccounts(ctx context.Context) (int, error) {
+	retention, batch := defaultDeletionRetention, defaultPurgeBatch
+	if s.cfg != nil {
+		if s.cfg.Deletion.Retention > 0 {
+			retention = s.cfg.Deletion.Retention
+		}
+		if s.cfg.Deletion.PurgeBatch > 0 {
+			batch = s.cfg.Deletion.PurgeBatch
+		}
+	}
+
+	cutoff := time.Now().Add(-retention)
+	total := 0
+	for {
+		purged, err := s.userRepo.PurgeDeleted(ctx, cutoff, batch)
+		total += purged
+		if err != nil || purged < batch {
+			return total, err
+		}
+	}
+}
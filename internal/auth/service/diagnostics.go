@@ -0,0 +1,11 @@
+package service
+
+import (
+	"context"
+
+	"github.com/abisalde/authentication-service/internal/diagnostics"
+)
+
+func (s *AuthService) RedisKeyspaceUsage(ctx context.Context, sampleSize int) ([]diagnostics.KeyspaceUsage, error) {
+	return diagnostics.RedisKeyspaceUsage(ctx, s.cache.RawClient(), diagnostics.DefaultKeyspaces, sampleSize)
+}
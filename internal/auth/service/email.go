@@ -12,7 +12,13 @@ import (
 //go:embed templates/verification_email_template.html
 var emailTemplate embed.FS
 
+// SendVerificationCodeEmail sends a code under the default brand, for
+// addresses that do not belong to an account yet.
 func (s *AuthService) SendVerificationCodeEmail(ctx context.Context, email, code string) error {
+	return s.sendVerificationCodeEmail(ctx, s.DefaultBrand(), email, code)
+}
+
+func (s *AuthService) sendVerificationCodeEmail(ctx context.Context, brand Brand, email, code string) error {
 	tmplData, err := emailTemplate.ReadFile("templates/verification_email_template.html")
 
 	if err != nil {
@@ -24,22 +30,35 @@ func (s *AuthService) SendVerificationCodeEmail(ctx context.Context, email, code
 		return err
 	}
 
-	data := struct{ Code string }{Code: code}
+	data := struct {
+		Code  string
+		Brand Brand
+	}{Code: code, Brand: brand}
 
 	var htmlBody bytes.Buffer
 	if err := tmpl.Execute(&htmlBody, data); err != nil {
 		return err
 	}
 	subject := "Verify Your Email Address"
+	if brand.ProductName != "" {
+		subject = fmt.Sprintf("Verify Your Email Address for %s", brand.ProductName)
+	}
 	body := fmt.Sprintf(`
 		Here's your one-time passcode: %s
 		
 		This code will expire in 5 minutes
 
-		Problems? Just reply to this email
-	`, code)
+		%s
+	`, code, supportLine(brand))
 
 	plainTextBody := strings.TrimSpace(body)
 
 	return s.mailService.SendHTMLEmail(ctx, email, subject, htmlBody.String(), plainTextBody)
 }
+
+func supportLine(brand Brand) string {
+	if brand.SupportEmail != "" {
+		return "Problems? Contact " + brand.SupportEmail
+	}
+	return "Problems? Just reply to this email"
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/events"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/pkg/verification"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// EmailChangePrefix holds the pending change of a user until it is
+	// confirmed or expires.
+	EmailChangePrefix = "email_change:"
+	// EmailChangeStreamKey receives an email_changed event per confirmed
+	// change, for audit consumers next to login_events.
+	EmailChangeStreamKey = "email_change_events"
+
+	emailChangeTTL = 15 * time.Minute
+)
+
+var (
+	ErrEmailUnchanged     = errors.New("new email is the current email")
+	ErrEmailTaken         = errors.New("email belongs to another account")
+	ErrNoEmailChange      = errors.New("no pending email change")
+	ErrEmailChangeCode    = errors.New("email change code is invalid")
+	ErrEmailChangeExpired = errors.New("email change expired or had too many attempts")
+)
+
+type pendingEmailChange struct {
+	NewEmail  string                  `json:"new_email"`
+	Code      verification.StoredCode `json:"code_hash"`
+	ExpiresAt time.Time               `json:"expires_at"`
+}
+
+// EmailChangeEvent is the audit record of a confirmed email change.
+type EmailChangeEvent struct {
+	events.Metadata
+	UserID    int64     `json:"user_id"`
+	OldEmail  string    `json:"old_email"`
+	NewEmail  string    `json:"new_email"`
+	Timestamp time.Time `json:"timestamp"`
+	EventType string    `json:"event_type"`
+}
+
+// RequestEmailChange sends a code to the new address. The account keeps
+// signing in with its current email until the code is confirmed; a new
+// request replaces the previous one.
+func (s *AuthService) RequestEmailChange(ctx context.Context, u *ent.User, newEmail string) error {
+	if IsServiceAccount(u) {
+		return ErrServiceAccount
+	}
+
+	newEmail = strings.TrimSpace(newEmail)
+	if strings.EqualFold(newEmail, u.Email) {
+		return ErrEmailUnchanged
+	}
+
+	exists, err := s.userRepo.ExistsByEmail(ctx, newEmail)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrEmailTaken
+	}
+
+	code := s.NewVerificationCode(newEmail)
+	stored, err := verification.NewStoredCode(code)
+	if err != nil {
+		return err
+	}
+	change := pendingEmailChange{
+		NewEmail:  newEmail,
+		Code:      stored,
+		ExpiresAt: time.Now().Add(emailChangeTTL),
+	}
+	if err := s.cache.Set(ctx, emailChangeKey(u.ID), change, emailChangeTTL); err != nil {
+		return err
+	}
+
+	return s.sendVerificationCodeEmail(ctx, s.BrandForUser(ctx, u), newEmail, code)
+}
+
+// ConfirmEmailChange switches the account to the new email, drops the data
+// cached under either address and signs every session out.
+func (s *AuthService) ConfirmEmailChange(ctx context.Context, u *ent.User, code string) (*ent.User, error) {
+	key := emailChangeKey(u.ID)
+
+	var change pendingEmailChange
+	if err := s.cache.Get(ctx, key, &change); err != nil {
+		return nil, ErrNoEmailChange
+	}
+	allowed, err := s.claimVerificationAttempt(ctx, key, change.Code)
+	if err != nil {
+		s.logger.Warn("Failed to count email change attempt", "user_id", u.ID, "error", err)
+		return nil, ErrEmailChangeCode
+	}
+	if time.Now().After(change.ExpiresAt) || !allowed {
+		_ = s.cache.Delete(ctx, key)
+		return nil, ErrEmailChangeExpired
+	}
+
+	if !change.Code.Matches(code) {
+		return nil, ErrEmailChangeCode
+	}
+
+	exists, err := s.userRepo.ExistsByEmail(ctx, change.NewEmail)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		_ = s.cache.Delete(ctx, key)
+		return nil, ErrEmailTaken
+	}
+
+	updated, err := s.userRepo.UpdateEmail(ctx, u.ID, change.NewEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = s.cache.Delete(ctx, key)
+	_ = s.CleanupTemporaryData(ctx, u.Email)
+	_ = s.CleanupTemporaryData(ctx, change.NewEmail)
+
+	if err := s.RevokeUserTokens(ctx, []int64{u.ID}, time.Now(), model.RevocationReasonEmailChanged); err != nil {
+		s.logger.Error("Failed to revoke sessions after an email change", "user_id", u.ID, "error", err)
+	}
+
+	s.recordEmailChange(ctx, u.ID, u.Email, change.NewEmail)
+	return updated, nil
+}
+
+func (s *AuthService) recordEmailChange(ctx context.Context, userID int64, oldEmail, newEmail string) {
+	eventData, err := json.Marshal(EmailChangeEvent{
+		Metadata:  events.Stamp(events.EmailChanged),
+		UserID:    userID,
+		OldEmail:  oldEmail,
+		NewEmail:  newEmail,
+		Timestamp: time.Now(),
+		EventType: events.EmailChanged,
+	})
+	if err != nil {
+		return
+	}
+
+	s.logger.Info("Email changed", "user_id", userID)
+	err = s.cache.RawClient().XAdd(ctx, &redis.XAddArgs{
+		Stream: EmailChangeStreamKey,
+		MaxLen: 100000,
+		Approx: true,
+		Values: map[string]interface{}{"event": eventData},
+	}).Err()
+	if err != nil {
+		s.logger.Warn("Failed to publish email change event", "user_id", userID, "error", err)
+	}
+}
+
+func emailChangeKey(userID int64) string {
+	return fmt.Sprintf("%s%d", EmailChangePrefix, userID)
+}
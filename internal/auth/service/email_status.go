@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+var (
+	ErrCaptchaRequired      = errors.New("captcha verification required")
+	ErrCaptchaNotConfigured = errors.New("captcha required but no verifier is configured")
+)
+
+// EmailStatus reports whether email can register. Deployments configured as
+// neutral answer UNKNOWN for every email so the check cannot enumerate
+// accounts; the CAPTCHA is still enforced so the endpoint behaves the same.
+func (s *AuthService) EmailStatus(ctx context.Context, email, captchaToken, remoteIP string) (model.EmailRegistrationStatus, error) {
+	if s.cfg.EmailStatus.RequireCaptcha {
+		if err := s.verifyCaptcha(ctx, captchaToken, remoteIP); err != nil {
+			return "", err
+		}
+	}
+
+	if s.cfg.EmailStatus.Neutral {
+		return model.EmailRegistrationStatusUnknown, nil
+	}
+
+	exists, err := s.userRepo.ExistsByEmail(ctx, email)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return model.EmailRegistrationStatusRegistered, nil
+	}
+
+	// The cache reports a missing key as an error, so ask Redis directly to
+	// tell an unknown email apart from an outage.
+	pending, err := s.cache.RawClient().Exists(ctx, fmt.Sprintf("pending_user:%s", email)).Result()
+	if err != nil {
+		return "", err
+	}
+	if pending > 0 {
+		return model.EmailRegistrationStatusPendingVerification, nil
+	}
+
+	return model.EmailRegistrationStatusAvailable, nil
+}
+
+func (s *AuthService) verifyCaptcha(ctx context.Context, token, remoteIP string) error {
+	if s.captcha == nil {
+		return ErrCaptchaNotConfigured
+	}
+
+	ok, err := s.captcha.Verify(ctx, token, remoteIP)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrCaptchaRequired
+	}
+	return nil
+}
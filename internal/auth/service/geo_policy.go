@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/geopolicy"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/metrics"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrGeoBlocked refuses a sign-in from a country the geo policy blocks.
+var ErrGeoBlocked = errors.New("sign-ins from this country are not accepted")
+
+var geoPolicyDecisions = metrics.Default.NewCounterVec(
+	"geo_policy_decisions_total",
+	"Sign-ins stepped up or blocked because of the country they came from, by decision.",
+	"decision",
+)
+
+// GeoDecision applies the geo policy of the user's organization, or the
+// deployment's, to the country the CDN reported for the sign-in over c.
+// Blocked sign-ins are recorded as deny-list hits. A failed organization
+// lookup falls back to the deployment's rules.
+func (s *AuthService) GeoDecision(ctx context.Context, c *fiber.Ctx, u *ent.User) geopolicy.Decision {
+	if c == nil || s.geo.CountryHeader == "" {
+		return geopolicy.DecisionAllow
+	}
+	country := c.Get(s.geo.CountryHeader)
+
+	var organization string
+	if u.OrganizationID != nil && s.geo.HasOverrides() {
+		org, err := s.userRepo.GetOrganization(ctx, *u.OrganizationID)
+		if err != nil {
+			s.logger.Warn("Failed to load the organization for its geo policy", "user_id", u.ID, "error", err)
+		} else {
+			organization = org.Slug
+		}
+	}
+
+	decision := s.geo.Decide(country, organization)
+	if decision == geopolicy.DecisionAllow {
+		return decision
+	}
+	geoPolicyDecisions.Inc(string(decision))
+
+	if decision == geopolicy.DecisionBlock {
+		s.logger.Warn("Sign-in refused by the geo policy", "user_id", u.ID, "country", country, "organization", organization)
+		s.RecordSecurityEvent(ctx, SecurityEvent{
+			Action:    SecurityDenyListHit,
+			UserID:    u.ID,
+			Email:     u.Email,
+			IP:        c.IP(),
+			Reason:    DenyListGeoPolicy,
+			RequestID: c.Get(auth.RequestIDHeader),
+		})
+	}
+	return decision
+}
+
+// CheckGeoPolicy refuses blocked sign-ins and asks for a CAPTCHA when the
+// sign-in's country is stepped up.
+func (s *AuthService) CheckGeoPolicy(ctx context.Context, c *fiber.Ctx, u *ent.User, captchaToken *string) error {
+	switch s.GeoDecision(ctx, c, u) {
+	case geopolicy.DecisionBlock:
+		return ErrGeoBlocked
+	case geopolicy.DecisionStepUp:
+		if captchaToken == nil || *captchaToken == "" {
+			return ErrCaptchaRequired
+		}
+		return s.verifyCaptcha(ctx, *captchaToken, c.IP())
+	}
+	return nil
+}
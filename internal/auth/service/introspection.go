@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+)
+
+// IntrospectToken returns the claims of an active token, JWT or opaque. A
+// token is inactive once it fails validation, was blacklisted at logout or
+// predates a forced re-login of its user.
+func (s *AuthService) IntrospectToken(ctx context.Context, token string) (*jwt.Claims, bool) {
+	if token == "" || s.IsTokenBlacklisted(ctx, token) {
+		return nil, false
+	}
+
+	consumer := auth.GetAutomationClient(ctx)
+	if consumer == "" {
+		consumer = auth.GetIntrospectionClient(ctx)
+	}
+	if leeway, ok := s.cfg.JWT.ConsumerLeeway[consumer]; ok {
+		ctx = jwt.ContextWithLeeway(ctx, leeway)
+	}
+
+	claims, err := jwt.ValidateTokenContext(ctx, token)
+	if err != nil {
+		return nil, false
+	}
+
+	if claims.IsAccessToken() {
+		user, err := s.ResolveUserReference(ctx, claims.Subject)
+		if err != nil {
+			return nil, false
+		}
+		// Tokens without iat count as issued at the epoch, like in the auth
+		// middleware, so any forced re-login revokes them.
+		issuedAt := time.Unix(0, 0)
+		if claims.IssuedAt != nil {
+			issuedAt = claims.IssuedAt.Time
+		}
+		if s.IsTokenRevokedForUser(ctx, user.ID, issuedAt) {
+			return nil, false
+		}
+	}
+
+	return claims, true
+}
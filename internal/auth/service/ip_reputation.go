@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/reputation"
+	"github.com/abisalde/authentication-service/internal/metrics"
+)
+
+// IPReputationPrefix caches feed reports by IP.
+const IPReputationPrefix = "ip_reputation:"
+
+var (
+	ipReputationLookups = metrics.Default.NewCounterVec(
+		"ip_reputation_lookups_total",
+		"IP reputation lookups, by result: hit (cached), miss (asked the feed) or error.",
+		"result",
+	)
+	ipReputationDecisions = metrics.Default.NewCounterVec(
+		"ip_reputation_decisions_total",
+		"Requests stepped up or blocked because of the client IP's reputation, by decision and source.",
+		"decision", "source",
+	)
+)
+
+// SetIPReputationProvider replaces the configured reputation feed, for
+// commercial feeds that need their own client. nil turns lookups off.
+func (s *AuthService) SetIPReputationProvider(provider reputation.Provider) {
+	s.ipFeed = provider
+}
+
+// AssessIP scores ip with the reputation feed and decides what that means
+// for the request. Reports are cached; concurrent lookups of one IP share a
+// single feed request. Feed errors allow the request, so an outage never
+// locks users out.
+func (s *AuthService) AssessIP(ctx context.Context, ip string) reputation.Assessment {
+	assessment := reputation.Assessment{IP: ip, Decision: reputation.DecisionAllow}
+	if s.ipFeed == nil || !reputation.Routable(ip) {
+		return assessment
+	}
+
+	key := IPReputationPrefix + ip
+	var report reputation.Report
+	if err := s.cache.Get(ctx, key, &report); err == nil {
+		ipReputationLookups.Inc("hit")
+	} else {
+		result, err, _ := s.sfGroup.Do(key, func() (interface{}, error) {
+			report, err := s.ipFeed.Lookup(ctx, ip)
+			if err != nil {
+				return nil, err
+			}
+			if err := s.cache.Set(ctx, key, report, s.reputation.CacheTTL); err != nil {
+				s.logger.Warn("Failed to cache IP reputation", "ip", ip, "error", err)
+			}
+			return report, nil
+		})
+		if err != nil {
+			ipReputationLookups.Inc("error")
+			s.logger.Warn("IP reputation lookup failed, allowing", "ip", ip, "error", err)
+			return assessment
+		}
+		ipReputationLookups.Inc("miss")
+		report = result.(reputation.Report)
+	}
+
+	assessment.Report = report
+	assessment.Decision = s.reputation.Decide(report.Score)
+	if assessment.Decision != reputation.DecisionAllow {
+		ipReputationDecisions.Inc(string(assessment.Decision), report.Source)
+	}
+	return assessment
+}
+
+// RequireIPStepUp asks for a CAPTCHA when the request's IP was stepped up by
+// its reputation, and does nothing otherwise.
+func (s *AuthService) RequireIPStepUp(ctx context.Context, captchaToken *string) error {
+	assessment := auth.GetIPReputation(ctx)
+	if assessment == nil || assessment.Decision != reputation.DecisionStepUp {
+		return nil
+	}
+	if captchaToken == nil || *captchaToken == "" {
+		return ErrCaptchaRequired
+	}
+	return s.verifyCaptcha(ctx, *captchaToken, assessment.IP)
+}
+
+// ipReputationScore is the score behind the request's assessment, 0 when the
+// feed was not consulted.
+func ipReputationScore(ctx context.Context) int {
+	if assessment := auth.GetIPReputation(ctx); assessment != nil {
+		return assessment.Report.Score
+	}
+	return 0
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/database/ent/loginattempt"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/metrics"
+	"github.com/abisalde/authentication-service/pkg/useragent"
+	"github.com/abisalde/authentication-service/pkg/verification"
+)
+
+const (
+	defaultAlertLookback     = 90 * 24 * time.Hour
+	defaultAlertHistoryLimit = 20
+	defaultAlertLinkTTL      = 7 * 24 * time.Hour
+)
+
+var ErrSignInAlertTokenInvalid = errors.New("sign-in alert token invalid or already used")
+
+var loginAlerts = metrics.Default.NewCounterVec(
+	"login_alerts_total",
+	"New sign-in alerts, by whether the email was sent and whether the user revoked the sign-in.",
+	"result",
+)
+
+//go:embed templates/new_sign_in_email_template.html
+var newSignInTemplate embed.FS
+
+// alertUnrecognizedSignIn emails the user about a successful sign-in that
+// looks unlike their recent ones. Failures are logged and never fail the
+// login itself.
+func (s *AuthService) alertUnrecognizedSignIn(ctx context.Context, u *ent.User, record repository.LoginAttemptRecord) {
+	if s.cfg == nil || !s.cfg.LoginAlerts.Enabled || IsServiceAccount(u) || record.IP == "" {
+		return
+	}
+
+	unrecognized, err := s.unrecognizedSignIn(ctx, u.ID, record)
+	if err != nil {
+		s.logger.Warn("Failed to compare the sign-in with the login history", "user_id", u.ID, "error", err)
+		return
+	}
+	if !unrecognized {
+		return
+	}
+
+	if err := s.sendNewSignInEmail(ctx, u, record); err != nil {
+		loginAlerts.Inc("failed")
+		s.logger.Warn("Failed to send new sign-in email", "user_id", u.ID, "error", err)
+		return
+	}
+	loginAlerts.Inc("sent")
+}
+
+// unrecognizedSignIn compares the sign-in with the user's recent successful
+// ones. It only counts as unrecognized when both its network and its device
+// are new: a known laptop on hotel wifi or a new phone at home is routine.
+// The first sign-in of an account has nothing to compare with.
+func (s *AuthService) unrecognizedSignIn(ctx context.Context, userID int64, record repository.LoginAttemptRecord) (bool, error) {
+	lookback, limit := defaultAlertLookback, defaultAlertHistoryLimit
+	if s.cfg.LoginAlerts.Lookback > 0 {
+		lookback = s.cfg.LoginAlerts.Lookback
+	}
+	if s.cfg.LoginAlerts.HistoryLimit > 0 {
+		limit = s.cfg.LoginAlerts.HistoryLimit
+	}
+
+	since := time.Now().Add(-lookback)
+	success := loginattempt.OutcomeSUCCESS
+	recent, err := s.userRepo.ListLoginAttempts(ctx, repository.LoginAttemptFilter{UserID: &userID, Outcome: &success, Since: &since}, 0, limit)
+	if err != nil || len(recent) == 0 {
+		return false, err
+	}
+
+	return !s.signInRecognized(record.IP, record.UserAgent, record.DeviceID, recent), nil
+}
+
+// signInRecognized reports whether a sign-in shares its network or its device
+// with one of the prior ones.
+func (s *AuthService) signInRecognized(ip, userAgent, deviceID string, prior []*ent.LoginAttempt) bool {
+	current := s.binding.OriginOf(ip, userAgent)
+	for _, attempt := range prior {
+		if current.Network == "" || s.binding.OriginOf(attempt.IP, attempt.UserAgent).Network == current.Network {
+			return true
+		}
+		// Clients without a device ID header are identified by their user
+		// agent, as in the token device claim.
+		if deviceID != "" && attempt.DeviceID == deviceID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *AuthService) sendNewSignInEmail(ctx context.Context, u *ent.User, record repository.LoginAttemptRecord) error {
+	ttl := s.cfg.LoginAlerts.LinkTTL
+	if ttl <= 0 {
+		ttl = defaultAlertLinkTTL
+	}
+
+	signedInAt := time.Now().UTC()
+	data := struct {
+		SignedInAt    string
+		IP            string
+		Device        string
+		RevokeURL     string
+		RevokeExpires string
+		Brand         Brand
+	}{
+		SignedInAt: signedInAt.Format("2 January 2006 at 15:04 MST"),
+		IP:         record.IP,
+		Device:     deviceDescription(record),
+		Brand:      s.BrandForUser(ctx, u),
+	}
+
+	if s.cfg.LoginAlerts.RevokeURL != "" {
+		token, err := s.IssueActionToken(ctx, verification.PurposeSessionRevoke, u.PublicID.String(), map[string]string{"ip": record.IP}, ttl)
+		if err != nil {
+			// The alert is still worth sending; the user can sign out
+			// everywhere by changing their password.
+			s.logger.Warn("Failed to issue sign-in revocation token", "user_id", u.ID, "error", err)
+		} else {
+			data.RevokeURL = appealLink(s.cfg.LoginAlerts.RevokeURL, token)
+			data.RevokeExpires = signedInAt.Add(ttl).Format("2 January 2006")
+		}
+	}
+
+	tmplData, err := newSignInTemplate.ReadFile("templates/new_sign_in_email_template.html")
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := template.New("new_sign_in").Parse(string(tmplData))
+	if err != nil {
+		return err
+	}
+
+	var htmlBody bytes.Buffer
+	if err := tmpl.Execute(&htmlBody, data); err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Your account was just signed in to from a new device and network.\n\nWhen: %s\nIP address: %s", data.SignedInAt, data.IP)
+	if data.Device != "" {
+		body += "\nDevice: " + data.Device
+	}
+	body += "\n\nIf this was you, there is nothing to do."
+	if data.RevokeURL != "" {
+		body += fmt.Sprintf("\n\nIf it wasn't, sign out every device before %s, then change your password: %s", data.RevokeExpires, data.RevokeURL)
+	}
+
+	return s.mailService.SendHTMLEmail(ctx, u.Email, "New Sign-In to Your Account", htmlBody.String(), body)
+}
+
+// RevokeUnrecognizedSignIn consumes the token from a new sign-in email and
+// revokes every session of the user, the unrecognized one included.
+func (s *AuthService) RevokeUnrecognizedSignIn(ctx context.Context, token string) error {
+	payload, err := s.ConsumeActionToken(ctx, token, verification.PurposeSessionRevoke)
+	if err != nil {
+		return ErrSignInAlertTokenInvalid
+	}
+
+	owner, err := s.ResolveUserReference(ctx, payload.Subject)
+	if err != nil {
+		return ErrSignInAlertTokenInvalid
+	}
+
+	if err := s.RevokeUserTokens(ctx, []int64{owner.ID}, time.Now(), model.RevocationReasonUnrecognizedSignIn); err != nil {
+		return err
+	}
+
+	loginAlerts.Inc("revoked")
+	s.logger.Info("Every session revoked after an unrecognized sign-in", "user_id", owner.ID, "ip", payload.Data["ip"])
+	return nil
+}
+
+// deviceDescription renders the client as e.g. "Chrome 126 on Windows 11".
+func deviceDescription(record repository.LoginAttemptRecord) string {
+	return useragent.ParseWithHints(record.UserAgent, record.ClientHints).String()
+}
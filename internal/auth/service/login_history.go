@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/cookies"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/database/ent/loginattempt"
+	"github.com/abisalde/authentication-service/pkg/useragent"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultLoginRetention  = 90 * 24 * time.Hour
+	defaultLoginPruneBatch = 1000
+
+	// riskLookback is how far back failures count towards the risk score.
+	riskLookback = 15 * time.Minute
+)
+
+// LoginAttemptInput describes a sign-in to record. User is nil when the email
+// does not belong to an account; Request overrides the Fiber context carried
+// by ctx, for callbacks served outside GraphQL.
+type LoginAttemptInput struct {
+	User          *ent.User
+	Email         string
+	Method        loginattempt.Method
+	Outcome       loginattempt.Outcome
+	FailureReason loginattempt.FailureReason
+	Request       *fiber.Ctx
+}
+
+// RecordLoginAttempt scores and stores the attempt. Failures are logged and
+// never fail the login itself.
+func (s *AuthService) RecordLoginAttempt(ctx context.Context, in LoginAttemptInput) *ent.LoginAttempt {
+	record := repository.LoginAttemptRecord{
+		Email:   in.Email,
+		Method:  in.Method,
+		Outcome: in.Outcome,
+	}
+
+	if in.User != nil {
+		record.UserID = &in.User.ID
+		if record.Email == "" {
+			record.Email = in.User.Email
+		}
+	}
+	if in.Outcome == loginattempt.OutcomeFAILURE && in.FailureReason != "" {
+		reason := in.FailureReason
+		record.FailureReason = &reason
+	}
+
+	c := in.Request
+	if c == nil {
+		c, _ = auth.GetFiberWebContext(ctx)
+	}
+	if c != nil {
+		record.IP = c.IP()
+		record.UserAgent = c.Get(fiber.HeaderUserAgent)
+		record.ClientHints = useragent.HintsFrom(func(header string) string { return c.Get(header) })
+		if device := cookies.DeviceFromFiber(c); device != nil {
+			record.DeviceID = device.ID
+			record.Platform = device.Platform
+		}
+	}
+
+	signals, err := s.userRepo.LoginRiskSignals(ctx, record.UserID, record.Email, record.IP, record.DeviceID, time.Now().Add(-riskLookback))
+	if err != nil {
+		s.logger.Warn("Failed to load login risk signals", "email", record.Email, "error", err)
+	}
+	record.RiskScore = loginRiskScore(record, signals, ipReputationScore(ctx))
+
+	// Compared with the history before this attempt joins it.
+	if in.Outcome == loginattempt.OutcomeSUCCESS && in.User != nil {
+		s.alertUnrecognizedSignIn(ctx, in.User, record)
+	}
+
+	s.RecordSecurityEvent(ctx, securityLoginEvent(record, c))
+
+	attempt, err := s.userRepo.RecordLoginAttempt(ctx, record)
+	if err != nil {
+		s.logger.Warn("Failed to record login attempt", "email", record.Email, "error", err)
+		return nil
+	}
+	return attempt
+}
+
+// securityLoginEvent is the security event of a scored attempt made over c,
+// which is nil outside a request.
+func securityLoginEvent(record repository.LoginAttemptRecord, c *fiber.Ctx) SecurityEvent {
+	event := SecurityEvent{
+		Action:    SecurityLoginSucceeded,
+		Email:     record.Email,
+		IP:        record.IP,
+		RiskScore: record.RiskScore,
+	}
+	if record.UserID != nil {
+		event.UserID = *record.UserID
+	}
+	if record.Outcome == loginattempt.OutcomeFAILURE {
+		event.Action = SecurityLoginFailed
+		if record.FailureReason != nil {
+			event.Reason = string(*record.FailureReason)
+		}
+	}
+	if c != nil {
+		event.RequestID = c.Get(auth.RequestIDHeader)
+	}
+	return event
+}
+
+// loginRiskScore adds up weighted signals into a 0-100 score. Repeated
+// failures point at guessing; a success from an unseen IP or device after
+// earlier logins points at a takeover. A bad IP reputation weighs up to 30.
+func loginRiskScore(record repository.LoginAttemptRecord, signals repository.LoginRiskSignals, ipScore int) int {
+	score := ipScore * 30 / 100
+
+	if record.UserID == nil {
+		score += 30
+	}
+	score += min(signals.RecentEmailFailures*10, 40)
+	score += min(signals.RecentIPFailures*5, 30)
+
+	if record.Outcome == loginattempt.OutcomeSUCCESS && signals.HasPriorSuccess {
+		if record.IP != "" && !signals.KnownIP {
+			score += 20
+		}
+		if record.DeviceID != "" && !signals.KnownDevice {
+			score += 15
+		}
+	}
+
+	return min(score, 100)
+}
+
+func (s *AuthService) ListLoginAttempts(ctx context.Context, filter repository.LoginAttemptFilter, beforeID int64, limit int) ([]*ent.LoginAttempt, error) {
+	return s.userRepo.ListLoginAttempts(ctx, filter, beforeID, limit)
+}
+
+// PruneLoginHistory deletes attempts past the retention window in batches
+// until none are left.
+func (s *AuthService) PruneLoginHistory(ctx context.Context) (int, error) {
+	retention, batch := defaultLoginRetention, defaultLoginPruneBatch
+	if s.cfg != nil {
+		if s.cfg.LoginHistory.Retention > 0 {
+			retention = s.cfg.LoginHistory.Retention
+		}
+		if s.cfg.LoginHistory.PruneBatch > 0 {
+			batch = s.cfg.LoginHistory.PruneBatch
+		}
+	}
+
+	cutoff := time.Now().Add(-retention)
+	total := 0
+	for {
+		deleted, err := s.userRepo.PruneLoginAttempts(ctx, cutoff, batch)
+		total += deleted
+		if err != nil || deleted < batch {
+			return total, err
+		}
+	}
+}
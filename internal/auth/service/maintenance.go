@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/abisalde/authentication-service/internal/auth/maintenance"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// MaintenanceGrantPrefix holds a grant until its approval window or its
+	// token expires.
+	MaintenanceGrantPrefix = "maintenance_grant:"
+
+	maxMaintenanceReason = 500
+)
+
+var (
+	ErrMaintenanceDisabled       = errors.New("maintenance tokens are disabled")
+	ErrOperationNotGrantable     = errors.New("operation cannot be granted to maintenance tokens")
+	ErrMaintenanceReasonRequired = errors.New("a reason is required for maintenance tokens")
+	ErrGrantNotFound             = errors.New("maintenance grant not found or expired")
+	ErrSelfApproval              = errors.New("operators cannot approve their own maintenance grant")
+	ErrGrantAlreadyApproved      = errors.New("maintenance grant was already approved by this admin")
+	ErrGrantNotApproved          = errors.New("maintenance grant is not approved")
+	ErrNotGrantOperator          = errors.New("maintenance grant belongs to another operator")
+)
+
+func (s *AuthService) MaintenancePolicy() maintenance.Policy {
+	return s.maintenance
+}
+
+// RequestMaintenanceToken opens a break-glass request for the operations,
+// limited to the organization with the given slug when there is one. The
+// reason is logged with every approval and every use of the token.
+func (s *AuthService) RequestMaintenanceToken(ctx context.Context, operator *ent.User, operations []string, reason string, organization *string) (*maintenance.Grant, error) {
+	if !s.maintenance.Enabled() {
+		return nil, ErrMaintenanceDisabled
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" || utf8.RuneCountInString(reason) > maxMaintenanceReason {
+		return nil, ErrMaintenanceReasonRequired
+	}
+
+	var granted []string
+	for _, operation := range operations {
+		if !s.maintenance.Grantable(operation) {
+			return nil, ErrOperationNotGrantable
+		}
+		if !slices.Contains(granted, operation) {
+			granted = append(granted, operation)
+		}
+	}
+	if len(granted) == 0 {
+		return nil, ErrOperationNotGrantable
+	}
+
+	var org *ent.Organization
+	if organization != nil {
+		var err error
+		if org, err = s.GetOrganization(ctx, *organization); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	grant := &maintenance.Grant{
+		ID:         uuid.NewString(),
+		OperatorID: operator.ID,
+		Operator:   operator.PublicID.String(),
+		Operations: granted,
+		Reason:     reason,
+		Status:     maintenance.StatusPending,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(s.maintenance.ApprovalWindow),
+	}
+	if org != nil {
+		grant.OrganizationID = &org.ID
+		grant.Organization = org.Slug
+	}
+	if err := s.cache.Set(ctx, MaintenanceGrantPrefix+grant.ID, grant, s.maintenance.ApprovalWindow); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Maintenance grant requested", "grant", grant.ID, "user_id", operator.ID, "operations", granted, "organization", grant.Organization, "reason", reason)
+	return grant, nil
+}
+
+// ApproveMaintenanceToken adds the admin's approval. The grant becomes
+// APPROVED once the configured number of distinct admins, none of them the
+// operator, approved it.
+func (s *AuthService) ApproveMaintenanceToken(ctx context.Context, approver *ent.User, id string) (*maintenance.Grant, error) {
+	return s.updateMaintenanceGrant(ctx, id, func(grant *maintenance.Grant) (time.Duration, error) {
+		switch {
+		case grant.Status != maintenance.StatusPending:
+			return 0, ErrGrantAlreadyApproved
+		case grant.OperatorID == approver.ID:
+			return 0, ErrSelfApproval
+		case grant.ApprovedByUser(approver.ID):
+			return 0, ErrGrantAlreadyApproved
+		}
+
+		grant.ApprovedBy = append(grant.ApprovedBy, approver.ID)
+		if len(grant.ApprovedBy) >= s.maintenance.Approvals {
+			grant.Status = maintenance.StatusApproved
+		}
+
+		s.logger.Info("Maintenance grant approved", "grant", grant.ID, "admin_id", approver.ID, "approvals", len(grant.ApprovedBy), "required", s.maintenance.Approvals, "reason", grant.Reason)
+		return time.Until(grant.ExpiresAt), nil
+	})
+}
+
+// IssueMaintenanceToken mints the single access token of an approved grant.
+// It carries no refresh token and expires with the grant.
+func (s *AuthService) IssueMaintenanceToken(ctx context.Context, operator *ent.User, id string) (string, *maintenance.Grant, error) {
+	grant, err := s.updateMaintenanceGrant(ctx, id, func(grant *maintenance.Grant) (time.Duration, error) {
+		switch {
+		case grant.OperatorID != operator.ID:
+			return 0, ErrNotGrantOperator
+		case grant.Status != maintenance.StatusApproved:
+			return 0, ErrGrantNotApproved
+		}
+
+		grant.Status = maintenance.StatusIssued
+		grant.ExpiresAt = time.Now().Add(s.maintenance.TokenTTL)
+		return s.maintenance.TokenTTL, nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	scopes := make([]string, 0, len(grant.Operations))
+	for _, operation := range grant.Operations {
+		scopes = append(scopes, "maintenance:"+operation)
+	}
+
+	token, err := jwt.GenerateTokenWithExtras(ctx, grant.Operator, jwt.TokenTypeAccess, s.maintenance.TokenTTL, jwt.Extras{
+		Scopes:      scopes,
+		Maintenance: grant.ID,
+	})
+	if err != nil {
+		_ = s.cache.Delete(ctx, MaintenanceGrantPrefix+grant.ID)
+		return "", nil, err
+	}
+	s.RecordTokenIssued(ctx, jwt.TokenTypeAccess)
+
+	s.logger.Info("Maintenance token issued", "grant", grant.ID, "user_id", operator.ID, "expires_at", grant.ExpiresAt, "reason", grant.Reason)
+	return token, grant, nil
+}
+
+func (s *AuthService) GetMaintenanceGrant(ctx context.Context, id string) (*maintenance.Grant, error) {
+	var grant maintenance.Grant
+	if err := s.cache.Get(ctx, MaintenanceGrantPrefix+id, &grant); err != nil {
+		return nil, ErrGrantNotFound
+	}
+	return &grant, nil
+}
+
+// MaintenanceCallInOrganization reports whether a call with the given
+// arguments stays in the organization: every userId, input fields included,
+// names one of its members and every organization or slug names it. A call
+// naming none of them could reach any organization and is refused too.
+func (s *AuthService) MaintenanceCallInOrganization(ctx context.Context, organizationID int64, args map[string]any) (bool, error) {
+	targets := 0
+	var inOrganization func(args map[string]any) (bool, error)
+	inOrganization = func(args map[string]any) (bool, error) {
+		for key, value := range args {
+			switch value := value.(type) {
+			case map[string]any:
+				if ok, err := inOrganization(value); !ok || err != nil {
+					return ok, err
+				}
+			case string:
+				switch key {
+				case "userId":
+					target, err := s.ResolveUserReference(ctx, value)
+					if ent.IsNotFound(err) {
+						return false, nil
+					} else if err != nil {
+						return false, err
+					}
+					if target.OrganizationID == nil || *target.OrganizationID != organizationID {
+						return false, nil
+					}
+					targets++
+				case "organization", "slug":
+					org, err := s.GetOrganization(ctx, value)
+					if err == ErrOrganizationNotFound {
+						return false, nil
+					} else if err != nil {
+						return false, err
+					}
+					if org.ID != organizationID {
+						return false, nil
+					}
+					targets++
+				}
+			}
+		}
+		return true, nil
+	}
+
+	ok, err := inOrganization(args)
+	return ok && targets > 0, err
+}
+
+// ActiveMaintenanceGrant returns the issued grant a maintenance token was
+// minted for. Deleting the grant revokes the token.
+func (s *AuthService) ActiveMaintenanceGrant(ctx context.Context, id string, operatorID int64) (*maintenance.Grant, error) {
+	grant, err := s.GetMaintenanceGrant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if grant.Status != maintenance.StatusIssued || !time.Now().Before(grant.ExpiresAt) {
+		return nil, ErrGrantNotFound
+	}
+	if grant.OperatorID != operatorID {
+		return nil, ErrNotGrantOperator
+	}
+	return grant, nil
+}
+
+// updateMaintenanceGrant applies fn under WATCH so concurrent approvals are
+// never lost, retrying when another update won the race. fn returns the TTL
+// to store the grant with.
+func (s *AuthService) updateMaintenanceGrant(ctx context.Context, id string, fn func(*maintenance.Grant) (time.Duration, error)) (*maintenance.Grant, error) {
+	key := MaintenanceGrantPrefix + id
+	var grant maintenance.Grant
+
+	update := func(tx *redis.Tx) error {
+		grant = maintenance.Grant{}
+		raw, err := tx.Get(ctx, key).Bytes()
+		if err == redis.Nil {
+			return ErrGrantNotFound
+		} else if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(raw, &grant); err != nil {
+			return err
+		}
+
+		ttl, err := fn(&grant)
+		if err != nil {
+			return err
+		}
+		if ttl <= 0 {
+			return ErrGrantNotFound
+		}
+
+		updated, err := json.Marshal(&grant)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, updated, ttl)
+			return nil
+		})
+		return err
+	}
+
+	for attempt := 0; attempt < 3; attempt++ {
+		err := s.cache.RawClient().Watch(ctx, update, key)
+		if err == redis.TxFailedErr {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &grant, nil
+	}
+	return nil, redis.TxFailedErr
+}
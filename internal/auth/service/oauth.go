@@ -2,69 +2,94 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"log/slog"
+	"net/url"
 	"strings"
 	"time"
 
+	"github.com/abisalde/authentication-service/internal/alerting"
 	"github.com/abisalde/authentication-service/internal/auth/cookies"
+	"github.com/abisalde/authentication-service/internal/auth/geopolicy"
 	"github.com/abisalde/authentication-service/internal/configs"
 	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/database/ent/loginattempt"
 	"github.com/abisalde/authentication-service/internal/graph/errors"
 	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/sandbox"
+	"github.com/abisalde/authentication-service/pkg/jwt"
 	oauthPKCE "github.com/abisalde/authentication-service/pkg/oauth"
 	"github.com/gofiber/fiber/v2"
 	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/facebook"
-	"golang.org/x/oauth2/google"
 )
 
 type OAuthService struct {
-	googleOAuthConfig   *oauth2.Config
-	facebookOAuthConfig *oauth2.Config
-	authService         *AuthService
+	providers   oauthProviders
+	authService *AuthService
+	health      *ProviderHealth
 }
 
 func NewOAuthService(authService *AuthService) *OAuthService {
-	return &OAuthService{
-		googleOAuthConfig: &oauth2.Config{
-			ClientID:     authService.cfg.Providers.GoogleClientID,
-			ClientSecret: authService.cfg.Providers.GoogleClientSecret,
-			RedirectURL:  GetRedirectUrl(authService.cfg, "google"),
-			Scopes:       []string{"email", "profile"},
-			Endpoint:     google.Endpoint,
-		},
-
-		facebookOAuthConfig: &oauth2.Config{
-			ClientID:     authService.cfg.Providers.FBClientID,
-			ClientSecret: authService.cfg.Providers.FBClientSecret,
-			RedirectURL:  GetRedirectUrl(authService.cfg, "facebook"),
-			Scopes:       []string{"email"},
-			Endpoint:     facebook.Endpoint,
-		},
+	providers := newOAuthProviders(authService.cfg)
 
+	return &OAuthService{
+		providers:   providers,
 		authService: authService,
+		health:      NewProviderHealth(authService.cfg, providers.names()...),
 	}
 }
 
+// Logger is the logger of the auth service, for the OAuth handlers.
+func (s *OAuthService) Logger() *slog.Logger {
+	return s.authService.logger
+}
+
+// Health exposes the sliding window of OAuth exchange outcomes.
+func (s *OAuthService) Health() *ProviderHealth {
+	return s.health
+}
+
+// AlertOnDegradation raises an alert when a provider's failure rate crosses
+// the degraded threshold and resolves it once the provider recovers.
+func (s *OAuthService) AlertOnDegradation(alerts *alerting.Dispatcher) {
+	s.health.OnTransition(func(status ProviderStatus) {
+		alerts.Fire(alerting.Alert{
+			Source:   "oauth_health",
+			Title:    fmt.Sprintf("%s sign-in degraded", status.Provider),
+			Summary:  fmt.Sprintf("%d of %d exchanges failed in the last %s", status.Failures, status.Attempts, s.health.Window()),
+			Severity: alerting.SeverityCritical,
+			DedupKey: "oauth_health:" + status.Provider,
+			Resolved: !status.Degraded,
+			Details: map[string]string{
+				"provider":     status.Provider,
+				"failure_rate": fmt.Sprintf("%.2f", status.FailureRate),
+				"p95_latency":  status.P95Latency.String(),
+			},
+		})
+	})
+}
+
 func GetRedirectUrl(cfg *configs.Config, provider string) string {
 	provider = strings.ToLower(provider)
-	var baseApiUrl string
+	return fmt.Sprintf("%s/service/oauth/%s/callback", baseAPIURL(cfg), provider)
+}
+
+func baseAPIURL(cfg *configs.Config) string {
 	if cfg.Env.CurrentEnv == "production" {
-		baseApiUrl = cfg.Env.BaseAPIUrl
-	} else {
-		baseApiUrl = "http://localhost:8080"
+		return cfg.Env.BaseAPIUrl
 	}
-	return fmt.Sprintf("%s/service/oauth/%s/callback", baseApiUrl, provider)
+	return "http://localhost:8080"
 }
 
-func (s *OAuthService) GetFrontEndRedirectURL(platform model.OAuthPlatform, token, refresh, email string) string {
+// GetFrontEndRedirectURL hands the tokens to the app. idToken is only
+// appended when OIDC issued one.
+func (s *OAuthService) GetFrontEndRedirectURL(platform model.OAuthPlatform, token, refresh, email, idToken string) string {
 	cfg := s.authService.cfg
 	var redirectURL string
 	frontendURL := "https://authentication-service.netlify.app"
 
 	if platform == model.OAuthPlatformMobile {
-		return fmt.Sprintf("nativeoauthgraphql://passwordless-authentication?token=%s&email=%s&refresh=%s", token, email, refresh)
+		return withIDToken(fmt.Sprintf("nativeoauthgraphql://passwordless-authentication?token=%s&email=%s&refresh=%s", token, email, refresh), idToken)
 	}
 
 	if platform == model.OAuthPlatformWeb {
@@ -73,7 +98,7 @@ func (s *OAuthService) GetFrontEndRedirectURL(platform model.OAuthPlatform, toke
 		} else {
 			redirectURL = fmt.Sprintf("http://localhost:3000/saml/passwordless-authentication?token=%s&email=%s&refresh=%s", token, email, refresh)
 		}
-		return redirectURL
+		return withIDToken(redirectURL, idToken)
 	}
 
 	if cfg.Env.CurrentEnv == "production" {
@@ -84,36 +109,49 @@ func (s *OAuthService) GetFrontEndRedirectURL(platform model.OAuthPlatform, toke
 	return redirectURL
 }
 
+func withIDToken(redirectURL, idToken string) string {
+	if idToken == "" {
+		return redirectURL
+	}
+	return redirectURL + "&id_token=" + idToken
+}
+
 func (s *OAuthService) GetAuthPKCEURL(ctx context.Context, provider string, platform model.OAuthPlatform, stateUUID string, mode model.PasswordLessMode) (string, string, error) {
+	p, ok := s.providers.get(provider)
+	if !ok {
+		return "", "", errors.ErrSomethingWentWrong
+	}
+
 	verifier := oauth2.GenerateVerifier()
 
 	state := oauthPKCE.EncodeState(stateUUID, platform, mode)
 
-	var authURL string
-
 	cacheKey := fmt.Sprintf("oauth:%s:%s", platform, stateUUID)
 	if err := s.authService.cache.Set(ctx, cacheKey, verifier, 10*time.Minute); err != nil {
 		return "", "", errors.ErrSomethingWentWrong
 	}
 
-	switch model.OAuthProvider(provider) {
-	case model.OAuthProviderGoogle:
-		authURL = s.googleOAuthConfig.AuthCodeURL(state, oauth2.AccessTypeOffline, oauth2.S256ChallengeOption(verifier))
-	case model.OAuthProviderFacebook:
-		authURL = s.facebookOAuthConfig.AuthCodeURL(state)
-	default:
-		return "", "", errors.ErrSomethingWentWrong
+	if s.authService.sandbox.StubOAuth() {
+		authURL := fmt.Sprintf("%s?state=%s&code=%s", GetRedirectUrl(s.authService.cfg, provider), url.QueryEscape(state), url.QueryEscape(sandbox.OAuthCodePrefix+s.authService.sandbox.DefaultOAuthEmail()))
+		return authURL, state, nil
 	}
 
-	return authURL, state, nil
+	return p.authCodeURL(state, verifier), state, nil
 }
 
 func (s *OAuthService) HandleCallBack(c *fiber.Ctx, provider, platform, mode, code, stateUUID string) (*cookies.TokenPair, *ent.User, model.OAuthPlatform, error) {
 	cacheKey := fmt.Sprintf("oauth:%s:%s", platform, stateUUID)
-	providerKey := strings.ToUpper(provider)
 
 	ctx := c.Context()
 
+	p, ok := s.providers.get(provider)
+	if !ok {
+		return nil, nil, "", c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid Provider",
+			"message": "We couldn't find the provider at this time",
+		})
+	}
+
 	var codeVerifier string
 	err := s.authService.cache.Get(ctx, cacheKey, &codeVerifier)
 
@@ -124,104 +162,183 @@ func (s *OAuthService) HandleCallBack(c *fiber.Ctx, provider, platform, mode, co
 		})
 	}
 
-	var (
-		config      *oauth2.Config
-		userInfoURL string
-		token       *oauth2.Token
-		configErr   error
-	)
+	userInfo, ok := s.authService.sandbox.StubOAuthUser(provider, code)
+	if !ok {
+		userInfo, err = s.fetchProviderUser(c, ctx, provider, p, code, codeVerifier)
+		if err != nil || userInfo == nil {
+			return nil, nil, "", err
+		}
+	}
+
+	providerKey, oauthID := p.account(userInfo)
+	account := *userInfo
+	account.ID = oauthID
 
-	switch model.OAuthProvider(providerKey) {
-	case model.OAuthProviderGoogle:
-		config = s.googleOAuthConfig
-		userInfoURL = "https://www.googleapis.com/oauth2/v2/userinfo"
-		token, configErr = config.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
-	case model.OAuthProviderFacebook:
-		config = s.facebookOAuthConfig
-		userInfoURL = "https://graph.facebook.com/me?fields=id,name,email"
-		token, configErr = config.Exchange(ctx, code)
+	switch model.PasswordLessMode(mode) {
+	case model.PasswordLessModeRegister, model.PasswordLessModeLogin, OAuthModeLink:
 	default:
 		return nil, nil, "", c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "Invalid Provider",
-			"message": "We couldn't find the provider at this time",
+			"error":   "Invalid PasswordLess flow mode",
+			"message": "Please try again with the right flow",
 		})
 	}
 
-	if configErr != nil {
-		return nil, nil, "", c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "Authentication Exchange failed",
-			"message": "We couldn't find the complete your authentication at this time",
-		})
+	user, err := s.oauthUser(ctx, model.PasswordLessMode(mode), platform, stateUUID, providerKey, &account)
+	if err == nil && IsDeleted(user) {
+		err = ErrAlreadyDeleted
 	}
 
-	client := config.Client(ctx, token)
-	response, err := client.Get(userInfoURL)
-
+	if err == ErrIdentityLinked {
+		return nil, nil, "", c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":   "Account already linked",
+			"message": "This account is linked to another user",
+		})
+	}
 	if err != nil {
+		s.authService.RecordLoginAttempt(ctx, LoginAttemptInput{
+			Email:         userInfo.Email,
+			Method:        loginattempt.MethodOAUTH,
+			Outcome:       loginattempt.OutcomeFAILURE,
+			FailureReason: loginattempt.FailureReasonOAUTH_FAILED,
+			Request:       c,
+		})
 		return nil, nil, "", c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "User Authorization Failed",
-			"message": "We could not find this user at this time, please try again with a different email",
+			"error":   "Something went wrong",
+			"message": "Please try again",
 		})
 	}
-	defer response.Body.Close()
 
-	var userInfo *model.OAuthUserResponse
-	if err := json.NewDecoder(response.Body).Decode(&userInfo); err != nil {
-		return nil, nil, "", c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "User Profile fetching failed",
-			"message": "We could not find this user at this time, please try again",
+	if IsSuspended(user) {
+		s.authService.RecordLoginAttempt(ctx, LoginAttemptInput{
+			User:          user,
+			Email:         userInfo.Email,
+			Method:        loginattempt.MethodOAUTH,
+			Outcome:       loginattempt.OutcomeFAILURE,
+			FailureReason: loginattempt.FailureReasonACCOUNT_SUSPENDED,
+			Request:       c,
+		})
+		return nil, nil, "", c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error":   "Account suspended",
+			"message": "Your account has been suspended, check your email for how to appeal",
+			"reason":  SuspensionReason(user),
 		})
 	}
 
-	var user *ent.User
-	switch model.PasswordLessMode(mode) {
-
-	case model.PasswordLessModeRegister:
-		user, err = s.authService.userRepo.CreateUserFromOAuth(ctx, providerKey, userInfo)
-
-	case model.PasswordLessModeLogin:
-		userID := userInfo.ID
-		user, err = s.authService.userRepo.FindByOAuthID(ctx, providerKey, userID)
-
-	default:
-		return nil, nil, "", c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "Invalid PasswordLess flow mode",
-			"message": "Please try again with the right flow",
+	// The provider already challenged the user, so only blocks apply.
+	if s.authService.GeoDecision(ctx, c, user) == geopolicy.DecisionBlock {
+		return nil, nil, "", c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error":   "Sign-in blocked",
+			"message": "Sign-ins from your country are not accepted",
 		})
 	}
 
+	restored, err := s.authService.LiftDeactivation(ctx, user)
 	if err != nil {
 		return nil, nil, "", c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error":   "Something went wrong",
 			"message": "Please try again",
 		})
 	}
+	user = restored
+
+	if synced, syncErr := s.authService.SyncOnboardingStep(ctx, user); syncErr == nil {
+		user = synced
+	}
+
+	scopes, err := s.authService.TokenScopes(ctx, user)
+	if err != nil {
+		return nil, nil, "", errors.ErrSomethingWentWrong
+	}
+
+	device := cookies.DeviceFromFiber(c)
+	if err := s.authService.AdmitProbationLogin(ctx, user, device); err != nil {
+		return nil, nil, "", err
+	}
 
-	tokens, err := cookies.GenerateLoginTokenPair(int64(user.ID))
+	tokens, err := cookies.GenerateLoginTokenPair(ctx, user.PublicID.String(), device, scopes)
 	if err != nil {
 		return nil, nil, "", c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error":   "Something went wrong",
 			"message": "Access token failure",
 		})
 	}
+	s.authService.RecordTokenIssued(ctx, jwt.TokenTypeAccess, jwt.TokenTypeRefresh)
 
-	hashedToken, refreshErr := s.authService.StoreRefreshToken(ctx, user.ID, tokens.RefreshToken)
+	idToken, err := s.authService.IssueIDToken(ctx, user, "")
+	if err != nil {
+		return nil, nil, "", errors.ErrSomethingWentWrong
+	}
+
+	hashedToken, refreshErr := s.authService.StoreRefreshToken(ctx, user, tokens.RefreshToken)
 	if refreshErr != nil {
 		return nil, nil, "", c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error":   "Something went wrong",
 			"message": "Failed to create a token",
 		})
 	}
+	if err := s.authService.TrackSessionToken(ctx, user.ID, tokens.AccessToken); err != nil {
+		s.authService.logger.Warn("Failed to track the access token", "user_id", user.ID, "error", err)
+	}
 
 	err = s.authService.UpdateLastLogin(ctx, user.ID)
 	if err != nil {
 		return nil, nil, "", errors.ErrSomethingWentWrong
 	}
+	if err := s.authService.userRepo.TouchIdentity(ctx, providerKey, oauthID, time.Now()); err != nil {
+		s.authService.logger.Warn("Failed to record use of the OAuth account", "provider", providerKey, "user_id", user.ID, "error", err)
+	}
+
+	s.authService.RecordLoginAttempt(ctx, LoginAttemptInput{
+		User:    user,
+		Method:  loginattempt.MethodOAUTH,
+		Outcome: loginattempt.OutcomeSUCCESS,
+		Request: c,
+	})
 
 	tokePair := &cookies.TokenPair{
 		AccessToken:  tokens.AccessToken,
 		RefreshToken: hashedToken,
+		IDToken:      idToken,
 	}
 
 	return tokePair, user, model.OAuthPlatform(platform), err
 }
+
+// fetchProviderUser exchanges the authorization code and loads the user's
+// profile from the provider, recording the outcome for provider health.
+func (s *OAuthService) fetchProviderUser(c *fiber.Ctx, ctx context.Context, name string, p oauthProvider, code, codeVerifier string) (*model.OAuthUserResponse, error) {
+	started := time.Now()
+	userInfo, category, err := p.fetchUser(ctx, oauthCallback{Code: code, Verifier: codeVerifier, Form: func(key string) string {
+		return c.FormValue(key)
+	}})
+	if err == nil {
+		s.health.Record(name, time.Since(started), "")
+		return userInfo, nil
+	}
+
+	s.health.Record(name, time.Since(started), classifyOAuthError(category, err))
+	s.authService.logger.Warn("OAuth sign-in failed", "provider", name, "stage", category, "error", err)
+
+	switch {
+	case category == OAuthErrorExchange:
+		return nil, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Authentication Exchange failed",
+			"message": "We couldn't find the complete your authentication at this time",
+		})
+	case providerUnavailable(err):
+		return nil, c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"error":   "User Authorization Failed",
+			"message": "The provider is unavailable at the moment, please try again later",
+		})
+	case category == OAuthErrorDecode:
+		return nil, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "User Profile fetching failed",
+			"message": "We could not find this user at this time, please try again",
+		})
+	default:
+		return nil, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "User Authorization Failed",
+			"message": "We could not find this user at this time, please try again with a different email",
+		})
+	}
+}
@@ -0,0 +1,366 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/facebook"
+	"golang.org/x/oauth2/github"
+	"golang.org/x/oauth2/google"
+	"golang.org/x/oauth2/microsoft"
+)
+
+// ConfiguredOAuthProvider is the user.provider value shared by providers
+// registered through configuration; their oauth_id carries the name.
+const ConfiguredOAuthProvider = "OAUTH"
+
+// builtinOAuthProviders each have their own user.provider value. Configured
+// providers cannot take these names.
+var builtinOAuthProviders = []string{"google", "facebook", "apple", "github", "microsoft"}
+
+var errProviderUnavailable = errors.New("provider unavailable")
+
+// oauthProvider signs users in through one identity provider.
+type oauthProvider interface {
+	// authCodeURL starts the authorization; verifier is the PKCE verifier
+	// cached for the state.
+	authCodeURL(state, verifier string) string
+	// fetchUser exchanges the code and loads the profile. On failure the
+	// category names the step that failed, for provider health.
+	fetchUser(ctx context.Context, callback oauthCallback) (*model.OAuthUserResponse, OAuthErrorCategory, error)
+	// account returns the user.provider value and oauth_id the profile is
+	// stored under.
+	account(profile *model.OAuthUserResponse) (string, string)
+}
+
+type oauthCallback struct {
+	Code     string
+	Verifier string
+	// Form reads fields the provider sent along with the code.
+	Form func(key string) string
+}
+
+// oauthProviders is the registry of sign-in providers keyed by the
+// lowercase name used in callback URLs.
+type oauthProviders map[string]oauthProvider
+
+func newOAuthProviders(cfg *configs.Config) oauthProviders {
+	p := cfg.Providers
+	providers := oauthProviders{
+		"google": &userInfoProvider{
+			config: &oauth2.Config{
+				ClientID:     p.GoogleClientID,
+				ClientSecret: p.GoogleClientSecret,
+				RedirectURL:  GetRedirectUrl(cfg, "google"),
+				Scopes:       []string{"email", "profile"},
+				Endpoint:     google.Endpoint,
+			},
+			authParams:  []oauth2.AuthCodeOption{oauth2.AccessTypeOffline},
+			pkce:        true,
+			userInfoURL: "https://www.googleapis.com/oauth2/v2/userinfo",
+			claims:      configs.OAuthClaims{ID: "id", EmailVerified: "verified_email"},
+			storedAs:    "GOOGLE",
+		},
+		"facebook": &userInfoProvider{
+			config: &oauth2.Config{
+				ClientID:     p.FBClientID,
+				ClientSecret: p.FBClientSecret,
+				RedirectURL:  GetRedirectUrl(cfg, "facebook"),
+				Scopes:       []string{"email"},
+				Endpoint:     facebook.Endpoint,
+			},
+			userInfoURL: "https://graph.facebook.com/me?fields=id,name,email,first_name,last_name",
+			claims:      configs.OAuthClaims{ID: "id", FirstName: "first_name", LastName: "last_name"},
+			storedAs:    "FACEBOOK",
+		},
+		"github": &userInfoProvider{
+			config: &oauth2.Config{
+				ClientID:     p.GitHubClientID,
+				ClientSecret: p.GitHubClientSecret,
+				RedirectURL:  GetRedirectUrl(cfg, "github"),
+				Scopes:       []string{"read:user", "user:email"},
+				Endpoint:     github.Endpoint,
+			},
+			userInfoURL: "https://api.github.com/user",
+			claims:      configs.OAuthClaims{ID: "id"},
+			storedAs:    "GITHUB",
+			complete:    completeGitHubEmail,
+		},
+		"microsoft": &userInfoProvider{
+			config: &oauth2.Config{
+				ClientID:     p.MicrosoftClientID,
+				ClientSecret: p.MicrosoftClientSecret,
+				RedirectURL:  GetRedirectUrl(cfg, "microsoft"),
+				Scopes:       []string{"openid", "email", "profile", "User.Read"},
+				Endpoint:     microsoft.AzureADEndpoint(p.MicrosoftTenantID),
+			},
+			pkce:        true,
+			userInfoURL: "https://graph.microsoft.com/v1.0/me",
+			claims:      configs.OAuthClaims{ID: "id", Email: "mail", Name: "displayName", FirstName: "givenName", LastName: "surname"},
+			storedAs:    "MICROSOFT",
+			complete:    completeMicrosoftEmail,
+		},
+	}
+
+	apple, err := newAppleProvider(cfg)
+	if err != nil {
+		slog.Warn("Sign in with Apple disabled", "error", err)
+	}
+	if apple != nil {
+		providers["apple"] = apple
+	}
+
+	for name, provider := range cfg.OAuth.Providers {
+		if slices.Contains(builtinOAuthProviders, name) {
+			slog.Warn("Skipping configured OAuth provider, the name is built in", "provider", name)
+			continue
+		}
+		if provider.ClientID == "" || provider.ClientSecret == "" {
+			continue
+		}
+		providers[name] = &userInfoProvider{
+			config: &oauth2.Config{
+				ClientID:     provider.ClientID,
+				ClientSecret: provider.ClientSecret,
+				RedirectURL:  GetRedirectUrl(cfg, name),
+				Scopes:       provider.Scopes,
+				Endpoint:     oauth2.Endpoint{AuthURL: provider.AuthURL, TokenURL: provider.TokenURL},
+			},
+			pkce:        provider.PKCE,
+			userInfoURL: provider.UserInfoURL,
+			claims:      provider.Claims,
+			storedAs:    ConfiguredOAuthProvider,
+			namespace:   name,
+		}
+	}
+
+	return providers
+}
+
+func (r oauthProviders) get(name string) (oauthProvider, bool) {
+	provider, ok := r[strings.ToLower(name)]
+	return provider, ok
+}
+
+func (r oauthProviders) names() []string {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ConfiguredOAuthProviders lists the providers with client credentials,
+// built-in ones first, which are the ones clients can offer for sign-in.
+func ConfiguredOAuthProviders(cfg *configs.Config) []string {
+	p := cfg.Providers
+	providers := make([]string, 0, len(builtinOAuthProviders)+len(cfg.OAuth.Providers))
+	if p.GoogleClientID != "" && p.GoogleClientSecret != "" {
+		providers = append(providers, "google")
+	}
+	if p.FBClientID != "" && p.FBClientSecret != "" {
+		providers = append(providers, "facebook")
+	}
+	if p.AppleClientID != "" && p.AppleTeamID != "" && p.AppleKeyID != "" && p.ApplePrivateKeyFile != "" {
+		providers = append(providers, "apple")
+	}
+	if p.GitHubClientID != "" && p.GitHubClientSecret != "" {
+		providers = append(providers, "github")
+	}
+	if p.MicrosoftClientID != "" && p.MicrosoftClientSecret != "" {
+		providers = append(providers, "microsoft")
+	}
+
+	configured := make([]string, 0, len(cfg.OAuth.Providers))
+	for name, provider := range cfg.OAuth.Providers {
+		if !slices.Contains(builtinOAuthProviders, name) && provider.ClientID != "" && provider.ClientSecret != "" {
+			configured = append(configured, name)
+		}
+	}
+	sort.Strings(configured)
+	return append(providers, configured...)
+}
+
+// userInfoProvider exchanges the code and reads the profile from a JSON
+// userinfo endpoint, mapping its fields through claims.
+type userInfoProvider struct {
+	config      *oauth2.Config
+	authParams  []oauth2.AuthCodeOption
+	pkce        bool
+	userInfoURL string
+	claims      configs.OAuthClaims
+	storedAs    string
+	// namespace prefixes the oauth_id of providers sharing a user.provider
+	// value, so subjects of different providers never collide.
+	namespace string
+	// complete fills in profile fields the userinfo response left out.
+	complete func(ctx context.Context, client *http.Client, fields map[string]any, profile *model.OAuthUserResponse) error
+}
+
+func (p *userInfoProvider) authCodeURL(state, verifier string) string {
+	opts := p.authParams
+	if p.pkce {
+		opts = append(slices.Clip(opts), oauth2.S256ChallengeOption(verifier))
+	}
+	return p.config.AuthCodeURL(state, opts...)
+}
+
+func (p *userInfoProvider) fetchUser(ctx context.Context, callback oauthCallback) (*model.OAuthUserResponse, OAuthErrorCategory, error) {
+	var opts []oauth2.AuthCodeOption
+	if p.pkce {
+		opts = append(opts, oauth2.VerifierOption(callback.Verifier))
+	}
+	token, err := p.config.Exchange(ctx, callback.Code, opts...)
+	if err != nil {
+		return nil, OAuthErrorExchange, err
+	}
+
+	client := p.config.Client(ctx, token)
+	var fields map[string]any
+	if category, err := getProviderJSON(ctx, client, p.userInfoURL, &fields); err != nil {
+		return nil, category, err
+	}
+
+	profile, err := OAuthProfile(fields, p.claims)
+	if err != nil {
+		return nil, OAuthErrorDecode, err
+	}
+	if p.complete != nil {
+		if err := p.complete(ctx, client, fields, profile); err != nil {
+			return nil, OAuthErrorUserInfo, err
+		}
+	}
+	return profile, "", nil
+}
+
+func (p *userInfoProvider) account(profile *model.OAuthUserResponse) (string, string) {
+	if p.namespace == "" {
+		return p.storedAs, profile.ID
+	}
+	return p.storedAs, p.namespace + ":" + profile.ID
+}
+
+// OAuthProfile maps a userinfo response to a provider profile. Empty claim
+// names default to the OpenID Connect standard claims; numeric IDs are kept
+// as their decimal text.
+func OAuthProfile(fields map[string]any, claims configs.OAuthClaims) (*model.OAuthUserResponse, error) {
+	claim := func(key, fallback string) string {
+		if key == "" {
+			key = fallback
+		}
+		switch v := fields[key].(type) {
+		case string:
+			return strings.TrimSpace(v)
+		case json.Number:
+			return v.String()
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		}
+		return ""
+	}
+
+	profile := &model.OAuthUserResponse{
+		ID:        claim(claims.ID, "sub"),
+		Email:     claim(claims.Email, "email"),
+		FirstName: claim(claims.FirstName, "given_name"),
+		LastName:  claim(claims.LastName, "family_name"),
+	}
+	if name := claim(claims.Name, "name"); name != "" {
+		profile.Name = &name
+	}
+	// Some providers send email_verified as the string "true".
+	verifiedKey := claims.EmailVerified
+	if verifiedKey == "" {
+		verifiedKey = "email_verified"
+	}
+	switch v := fields[verifiedKey].(type) {
+	case bool:
+		profile.IsEmailVerified = v && profile.Email != ""
+	case string:
+		profile.IsEmailVerified = v == "true" && profile.Email != ""
+	}
+	if profile.ID == "" {
+		return nil, errors.New("userinfo response has no subject")
+	}
+	return profile, nil
+}
+
+// getProviderJSON decodes a provider API response into v. Server errors wrap
+// errProviderUnavailable so the callback can answer with a bad gateway.
+func getProviderJSON(ctx context.Context, client *http.Client, url string, v any) (OAuthErrorCategory, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return OAuthErrorUserInfo, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return OAuthErrorUserInfo, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return OAuthErrorUserInfo, fmt.Errorf("%w: status %d", errProviderUnavailable, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return OAuthErrorUserInfo, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return OAuthErrorDecode, err
+	}
+	return "", nil
+}
+
+func providerUnavailable(err error) bool {
+	return errors.Is(err, errProviderUnavailable)
+}
+
+// completeGitHubEmail looks up the primary verified address when the user
+// keeps their profile email private.
+func completeGitHubEmail(ctx context.Context, client *http.Client, _ map[string]any, profile *model.OAuthUserResponse) error {
+	if profile.Email != "" {
+		return nil
+	}
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if _, err := getProviderJSON(ctx, client, "https://api.github.com/user/emails", &emails); err != nil {
+		return err
+	}
+	for _, e := range emails {
+		if e.Primary && e.Verified {
+			profile.Email = e.Email
+			profile.IsEmailVerified = true
+			return nil
+		}
+	}
+	return errors.New("no verified primary email")
+}
+
+// completeMicrosoftEmail falls back to the sign-in name, which is an email
+// address, for accounts without a mailbox.
+func completeMicrosoftEmail(_ context.Context, _ *http.Client, fields map[string]any, profile *model.OAuthUserResponse) error {
+	if profile.Email == "" {
+		profile.Email, _ = fields["userPrincipalName"].(string)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+)
+
+const defaultIDTokenTTL = time.Hour
+
+// OIDCIssuer is the issuer of ID tokens and the base of the discovery
+// document, falling back to the API base URL.
+func OIDCIssuer(cfg *configs.Config) string {
+	if cfg.OIDC.Issuer != "" {
+		return strings.TrimSuffix(cfg.OIDC.Issuer, "/")
+	}
+	return baseAPIURL(cfg)
+}
+
+// IssueIDToken returns an ID token for the user with the standard email and
+// profile claims, or an empty string when OIDC is disabled. nonce is echoed
+// back for relying parties that sent one.
+func (s *AuthService) IssueIDToken(ctx context.Context, u *ent.User, nonce string) (string, error) {
+	if !s.cfg.OIDC.Enabled {
+		return "", nil
+	}
+
+	ttl := s.cfg.OIDC.IDTokenTTL
+	if ttl <= 0 {
+		ttl = defaultIDTokenTTL
+	}
+
+	claims := jwt.IDTokenClaims{
+		Nonce:             nonce,
+		Email:             u.Email,
+		EmailVerified:     u.IsEmailVerified,
+		GivenName:         u.FirstName,
+		FamilyName:        u.LastName,
+		Name:              strings.TrimSpace(u.FirstName + " " + u.LastName),
+		PreferredUsername: u.Username,
+	}
+
+	return jwt.GenerateIDToken(OIDCIssuer(s.cfg), u.PublicID.String(), s.cfg.OIDC.ClientID, ttl, claims)
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/consent"
+	"github.com/abisalde/authentication-service/internal/auth/onboarding"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+func (s *AuthService) OnboardingPolicy() onboarding.Policy {
+	return s.onboarding
+}
+
+func (s *AuthService) ConsentPolicy() consent.Policy {
+	return s.consent
+}
+
+// SyncOnboardingStep re-evaluates the user against the deployment policy and
+// persists the next required step when it has moved.
+func (s *AuthService) SyncOnboardingStep(ctx context.Context, u *ent.User) (*ent.User, error) {
+	next := s.onboarding.NextStep(u)
+	if string(u.OnboardingStep) == string(next) {
+		return u, nil
+	}
+
+	if err := s.userRepo.UpdateOnboardingStep(ctx, u.ID, string(next)); err != nil {
+		return u, err
+	}
+
+	return s.userRepo.GetByID(ctx, u.ID)
+}
+
+func (s *AuthService) GetOnboardingStatus(u *ent.User) *model.OnboardingStatus {
+	return &model.OnboardingStatus{
+		NextStep:       model.OnboardingStep(s.onboarding.NextStep(u)),
+		CompletedSteps: toGraphSteps(s.onboarding.CompletedSteps(u)),
+		RequiredSteps:  toGraphSteps(s.onboarding.RequiredSteps()),
+		IsComplete:     s.onboarding.IsComplete(u),
+	}
+}
+
+func (s *AuthService) UpdateUserProfile(ctx context.Context, userID int64, input model.UpdateProfileInput) (*ent.User, error) {
+	if err := s.userRepo.UpdateProfile(ctx, userID, input); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.SyncOnboardingStep(ctx, user)
+}
+
+func (s *AuthService) AcceptTerms(ctx context.Context, userID int64) (*ent.User, error) {
+	if err := s.userRepo.AcceptTerms(ctx, userID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.SyncOnboardingStep(ctx, user)
+}
+
+func toGraphSteps(steps []onboarding.Step) []model.OnboardingStep {
+	result := make([]model.OnboardingStep, 0, len(steps))
+	for _, step := range steps {
+		result = append(result, model.OnboardingStep(step))
+	}
+	return result
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/cookies"
+	"github.com/abisalde/authentication-service/internal/auth/probation"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/redis/go-redis/v9"
+)
+
+const ProbationSessionsPrefix = "probation_sessions:"
+
+var (
+	ErrEmailVerificationRequired = errors.New("email verification required for new accounts")
+	ErrSessionLimitReached       = errors.New("too many active sessions for a new account")
+)
+
+// admitSessionScript drops expired sessions, then admits the session unless
+// it is new and the user already holds the maximum. The admitted login takes
+// over the user's one refresh session, so the session that held it lives on
+// only until its access token expires. The set itself expires when the
+// probation window ends.
+var admitSessionScript = redis.NewScript(`
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+if not redis.call('ZSCORE', KEYS[1], ARGV[3]) and redis.call('ZCARD', KEYS[1]) >= tonumber(ARGV[4]) then
+	return 0
+end
+for _, held in ipairs(redis.call('ZRANGEBYSCORE', KEYS[1], '(' .. ARGV[6], '+inf')) do
+	redis.call('ZADD', KEYS[1], ARGV[6], held)
+end
+redis.call('ZADD', KEYS[1], ARGV[2], ARGV[3])
+redis.call('EXPIREAT', KEYS[1], ARGV[5])
+return 1
+`)
+
+func (s *AuthService) ProbationPolicy() probation.Policy {
+	return s.probation
+}
+
+// AdmitProbationLogin applies the probation restrictions before login tokens
+// are issued: unverified emails are refused and the session of device counts
+// against the session cap.
+func (s *AuthService) AdmitProbationLogin(ctx context.Context, u *ent.User, device *jwt.DeviceClaim) error {
+	if s.probation.BlocksUnverified(u) {
+		return ErrEmailVerificationRequired
+	}
+	if !s.probation.LimitsSessions(u) {
+		return nil
+	}
+
+	now := time.Now()
+	admitted, err := admitSessionScript.Run(ctx, s.cache.RawClient(),
+		[]string{fmt.Sprintf("%s%d", ProbationSessionsPrefix, u.ID)},
+		now.Unix(),
+		now.Add(cookies.RefreshTokenExpiry).Unix(),
+		probationSessionID(device),
+		s.probation.MaxSessions,
+		s.probation.EndsAt(u).Unix(),
+		now.Add(cookies.LoginAccessTokenExpiry).Unix(),
+	).Int()
+	if err != nil {
+		return err
+	}
+	if admitted == 0 {
+		return ErrSessionLimitReached
+	}
+	return nil
+}
+
+// probationSessionID identifies a session by its device, so signing in again
+// from the same device keeps its slot.
+func probationSessionID(device *jwt.DeviceClaim) string {
+	if device == nil || device.ID == "" {
+		return "device:unknown"
+	}
+	return "device:" + device.ID
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/metrics"
+)
+
+// OAuthErrorCategory groups provider failures by the step that failed.
+type OAuthErrorCategory string
+
+const (
+	OAuthErrorExchange OAuthErrorCategory = "exchange"
+	OAuthErrorUserInfo OAuthErrorCategory = "userinfo"
+	OAuthErrorDecode   OAuthErrorCategory = "decode"
+	OAuthErrorTimeout  OAuthErrorCategory = "timeout"
+)
+
+const (
+	defaultHealthWindow      = 5 * time.Minute
+	defaultHealthMinSamples  = 20
+	defaultHealthFailureRate = 0.5
+
+	// maxHealthEvents bounds memory per provider during a traffic spike; the
+	// oldest events are dropped first.
+	maxHealthEvents = 10000
+)
+
+var (
+	oauthExchangeDuration = metrics.Default.NewHistogramVec(
+		"oauth_exchange_duration_seconds",
+		"Latency of the OAuth code exchange and profile fetch.",
+		nil, "provider", "outcome",
+	)
+	oauthExchangeErrors = metrics.Default.NewCounterVec(
+		"oauth_exchange_errors_total",
+		"OAuth exchange failures by provider and category.",
+		"provider", "category",
+	)
+	oauthProviderDegraded = metrics.Default.NewGaugeVec(
+		"oauth_provider_degraded",
+		"1 while the provider's failure rate is above the degraded threshold.",
+		"provider",
+	)
+)
+
+type providerEvent struct {
+	at       time.Time
+	latency  time.Duration
+	category OAuthErrorCategory
+}
+
+// ProviderStatus summarises a provider's exchanges over the health window.
+type ProviderStatus struct {
+	Provider    string
+	Attempts    int
+	Failures    int
+	FailureRate float64
+	AvgLatency  time.Duration
+	P95Latency  time.Duration
+	Errors      map[OAuthErrorCategory]int
+	Degraded    bool
+}
+
+// ProviderHealth keeps a sliding window of OAuth exchange outcomes per
+// provider and flags providers whose failure rate spikes.
+type ProviderHealth struct {
+	mu          sync.Mutex
+	providers   []string
+	events      map[string][]providerEvent
+	window      time.Duration
+	minSamples  int
+	failureRate float64
+	now         func() time.Time
+	degraded    map[string]bool
+	onChange    func(ProviderStatus)
+}
+
+func NewProviderHealth(cfg *configs.Config, providers ...string) *ProviderHealth {
+	h := &ProviderHealth{
+		providers:   providers,
+		events:      make(map[string][]providerEvent),
+		window:      defaultHealthWindow,
+		minSamples:  defaultHealthMinSamples,
+		failureRate: defaultHealthFailureRate,
+		now:         time.Now,
+		degraded:    make(map[string]bool),
+	}
+
+	if cfg != nil {
+		if cfg.OAuthHealth.Window > 0 {
+			h.window = cfg.OAuthHealth.Window
+		}
+		if cfg.OAuthHealth.MinSamples > 0 {
+			h.minSamples = cfg.OAuthHealth.MinSamples
+		}
+		if cfg.OAuthHealth.FailureRate > 0 && cfg.OAuthHealth.FailureRate <= 1 {
+			h.failureRate = cfg.OAuthHealth.FailureRate
+		}
+	}
+
+	return h
+}
+
+// Record stores one exchange outcome. An empty category is a success.
+func (h *ProviderHealth) Record(provider string, latency time.Duration, category OAuthErrorCategory) {
+	provider = strings.ToLower(provider)
+
+	outcome := "success"
+	if category != "" {
+		outcome = "failure"
+		oauthExchangeErrors.Inc(provider, string(category))
+	}
+	oauthExchangeDuration.Observe(latency.Seconds(), provider, outcome)
+
+	h.mu.Lock()
+	events := h.prune(provider)
+	events = append(events, providerEvent{at: h.now(), latency: latency, category: category})
+	if len(events) > maxHealthEvents {
+		events = events[len(events)-maxHealthEvents:]
+	}
+	h.events[provider] = events
+	status := h.summarise(provider, events)
+	h.mu.Unlock()
+
+	h.publish(status)
+}
+
+// Status returns the provider's health over the current window.
+func (h *ProviderHealth) Status(provider string) ProviderStatus {
+	provider = strings.ToLower(provider)
+
+	h.mu.Lock()
+	status := h.summarise(provider, h.prune(provider))
+	h.mu.Unlock()
+
+	h.publish(status)
+	return status
+}
+
+// Statuses returns every configured provider and any other provider that
+// has recorded traffic, sorted by name.
+func (h *ProviderHealth) Statuses() []ProviderStatus {
+	h.mu.Lock()
+	names := make(map[string]bool, len(h.providers)+len(h.events))
+	for _, p := range h.providers {
+		names[strings.ToLower(p)] = true
+	}
+	for p := range h.events {
+		names[p] = true
+	}
+	h.mu.Unlock()
+
+	sorted := make([]string, 0, len(names))
+	for p := range names {
+		sorted = append(sorted, p)
+	}
+	sort.Strings(sorted)
+
+	statuses := make([]ProviderStatus, 0, len(sorted))
+	for _, p := range sorted {
+		statuses = append(statuses, h.Status(p))
+	}
+	return statuses
+}
+
+// DegradedProviders lists providers clients should present as temporarily
+// unavailable.
+func (h *ProviderHealth) DegradedProviders() []string {
+	degraded := make([]string, 0)
+	for _, status := range h.Statuses() {
+		if status.Degraded {
+			degraded = append(degraded, status.Provider)
+		}
+	}
+	return degraded
+}
+
+// OnTransition registers fn to be called whenever a provider becomes
+// degraded or recovers.
+func (h *ProviderHealth) OnTransition(fn func(ProviderStatus)) {
+	h.mu.Lock()
+	h.onChange = fn
+	h.mu.Unlock()
+}
+
+func (h *ProviderHealth) Window() time.Duration {
+	return h.window
+}
+
+// prune drops events older than the window. Callers hold h.mu.
+func (h *ProviderHealth) prune(provider string) []providerEvent {
+	events := h.events[provider]
+	cutoff := h.now().Add(-h.window)
+
+	i := sort.Search(len(events), func(i int) bool { return events[i].at.After(cutoff) })
+	if i > 0 {
+		events = append(events[:0], events[i:]...)
+		h.events[provider] = events
+	}
+	return events
+}
+
+func (h *ProviderHealth) summarise(provider string, events []providerEvent) ProviderStatus {
+	status := ProviderStatus{
+		Provider: provider,
+		Attempts: len(events),
+		Errors:   make(map[OAuthErrorCategory]int),
+	}
+	if len(events) == 0 {
+		return status
+	}
+
+	latencies := make([]time.Duration, 0, len(events))
+	var total time.Duration
+	for _, e := range events {
+		if e.category != "" {
+			status.Failures++
+			status.Errors[e.category]++
+		}
+		total += e.latency
+		latencies = append(latencies, e.latency)
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	status.AvgLatency = total / time.Duration(len(events))
+	status.P95Latency = latencies[(len(latencies)*95-1)/100]
+	status.FailureRate = float64(status.Failures) / float64(status.Attempts)
+	status.Degraded = status.Attempts >= h.minSamples && status.FailureRate >= h.failureRate
+
+	return status
+}
+
+func (h *ProviderHealth) publish(status ProviderStatus) {
+	value := 0.0
+	if status.Degraded {
+		value = 1
+	}
+	oauthProviderDegraded.Set(value, status.Provider)
+
+	h.mu.Lock()
+	changed := h.degraded[status.Provider] != status.Degraded
+	h.degraded[status.Provider] = status.Degraded
+	onChange := h.onChange
+	h.mu.Unlock()
+
+	if changed && onChange != nil {
+		onChange(status)
+	}
+}
+
+// classifyOAuthError reports timeouts separately from the step that failed,
+// since they usually point at the provider rather than the request.
+func classifyOAuthError(step OAuthErrorCategory, err error) OAuthErrorCategory {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return OAuthErrorTimeout
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return OAuthErrorTimeout
+	}
+
+	return step
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/cookies"
+	"github.com/abisalde/authentication-service/internal/auth/refreshbinding"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/metrics"
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// refreshOriginSuffix extends the refresh_token:<id> key, so the origin lives
+// and dies with the token it describes.
+const refreshOriginSuffix = ":origin"
+
+var (
+	ErrRefreshOriginBlocked = errors.New("refresh requested from another network or device")
+	ErrReauthRequired       = errors.New("refresh origin changed, sign in again")
+)
+
+var refreshOriginMismatches = metrics.Default.NewCounterVec(
+	"refresh_origin_mismatch_total",
+	"Refresh requests whose network or user agent family differs from the issuing session, by kind and enforcement mode.",
+	"kind", "mode",
+)
+
+func (s *AuthService) RefreshBindingPolicy() refreshbinding.Policy {
+	return s.binding
+}
+
+// requestOrigin returns the coarse origin of the request in ctx, and its IP
+// for the allow-list.
+func (s *AuthService) requestOrigin(ctx context.Context) (refreshbinding.Origin, string, bool) {
+	c, ok := auth.GetFiberWebContext(ctx)
+	if !ok {
+		return refreshbinding.Origin{}, "", false
+	}
+	ip := c.IP()
+	return s.binding.OriginOf(ip, c.Get(fiber.HeaderUserAgent)), ip, true
+}
+
+// addRefreshOrigin queues where the refresh token being stored on pipe was
+// issued to.
+func (s *AuthService) addRefreshOrigin(ctx context.Context, pipe redis.Pipeliner, userID int64) error {
+	if !s.binding.Enabled() {
+		return nil
+	}
+
+	origin, _, ok := s.requestOrigin(ctx)
+	if !ok {
+		return nil
+	}
+
+	key := fmt.Sprintf("%s%d%s", RefreshCachePrefix, userID, refreshOriginSuffix)
+	return setCached(ctx, pipe, key, origin, cookies.RefreshTokenExpiry)
+}
+
+// CheckRefreshOrigin compares the refresh request with the issuing session.
+// In log mode a mismatch is only counted; step_up refuses the refresh but
+// keeps the token, so the original device is unaffected; block also revokes
+// the refresh token. Tokens without a recorded origin always pass.
+func (s *AuthService) CheckRefreshOrigin(ctx context.Context, userID int64) error {
+	if !s.binding.Enabled() {
+		return nil
+	}
+
+	var issued refreshbinding.Origin
+	key := fmt.Sprintf("%s%d%s", RefreshCachePrefix, userID, refreshOriginSuffix)
+	if err := s.cache.Get(ctx, key, &issued); err != nil {
+		return nil
+	}
+
+	current, ip, ok := s.requestOrigin(ctx)
+	if !ok {
+		return nil
+	}
+
+	mismatches := s.binding.Mismatches(issued, current, ip)
+	if len(mismatches) == 0 {
+		return nil
+	}
+
+	mode := string(s.binding.Mode)
+	for _, kind := range mismatches {
+		refreshOriginMismatches.Inc(kind, mode)
+	}
+	s.logger.Warn("Refresh origin mismatch", "user_id", userID, "mismatches", mismatches, "issued", fmt.Sprintf("%+v", issued), "current", fmt.Sprintf("%+v", current))
+
+	switch s.binding.Mode {
+	case refreshbinding.ModeStepUp:
+		return ErrReauthRequired
+	case refreshbinding.ModeBlock:
+		if err := s.InvalidateRefreshToken(ctx, userID); err != nil {
+			s.logger.Error("Failed to revoke refresh token", "user_id", userID, "error", err)
+		}
+		s.RecordRevocation(ctx, userID, model.RevocationReasonSuspiciousRefresh)
+		return ErrRefreshOriginBlocked
+	}
+	return nil
+}
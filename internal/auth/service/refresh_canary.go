@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/alerting"
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/cookies"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/metrics"
+	"github.com/abisalde/authentication-service/pkg/verification"
+	"github.com/redis/go-redis/v9"
+)
+
+// refreshCanarySuffix names the canary like a spare session, so it looks
+// as usable as the real token to whoever reads a dump.
+const refreshCanarySuffix = ":standby"
+
+var refreshCanaryTrips = metrics.Default.NewCounterVec(
+	"refresh_canary_tripped_total",
+	"Refreshes presenting a canary token, which only exists in Redis.",
+)
+
+// refreshCanary derives the user's canary the way refresh tokens are
+// hashed, so it is indistinguishable from one. Being deterministic, the
+// canary in a backup of any age still matches.
+func refreshCanary(u *ent.User) (string, error) {
+	return verification.HashToken("refresh-canary:" + u.PublicID.String())
+}
+
+// addRefreshCanary queues the canary next to the refresh token being stored
+// on pipe. It is never handed to a client or tied to a device.
+func (s *AuthService) addRefreshCanary(ctx context.Context, pipe redis.Pipeliner, u *ent.User) error {
+	if !s.cfg.RefreshCanary.Enabled {
+		return nil
+	}
+
+	canary, err := refreshCanary(u)
+	if err != nil {
+		return err
+	}
+	key := fmt.Sprintf("%s%d%s", RefreshCachePrefix, u.ID, refreshCanarySuffix)
+	return setCached(ctx, pipe, key, canary, cookies.RefreshTokenExpiry)
+}
+
+// IsRefreshCanary reports whether token is the user's canary.
+func (s *AuthService) IsRefreshCanary(u *ent.User, token string) bool {
+	if !s.cfg.RefreshCanary.Enabled || u == nil || token == "" {
+		return false
+	}
+	canary, err := refreshCanary(u)
+	return err == nil && verification.Equal(token, canary)
+}
+
+// TripRefreshCanary handles a presented canary: Redis or a backup of it
+// leaked, so every token of the user is revoked and a critical alert fires.
+func (s *AuthService) TripRefreshCanary(ctx context.Context, u *ent.User) {
+	refreshCanaryTrips.Inc()
+	s.logger.Warn("Refresh canary presented, revoking every session", "user_id", u.ID)
+
+	if err := s.RevokeUserTokens(ctx, []int64{u.ID}, time.Now(), model.RevocationReasonCredentialTheft); err != nil {
+		s.logger.Error("Failed to revoke tokens after a canary refresh", "user_id", u.ID, "error", err)
+	}
+
+	s.alerts.Fire(alerting.Alert{
+		Source:   "refresh_canary",
+		Title:    "Refresh canary token used",
+		Summary:  fmt.Sprintf("The refresh canary of user %s was presented; session data in Redis or its backups has likely leaked", u.PublicID),
+		Severity: alerting.SeverityCritical,
+		DedupKey: "refresh_canary:" + u.PublicID.String(),
+		Details: map[string]string{
+			"user": u.PublicID.String(),
+			"ip":   auth.GetIPFromContext(ctx),
+		},
+	})
+}
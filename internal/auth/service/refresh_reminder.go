@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/cookies"
+	"github.com/abisalde/authentication-service/internal/events"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// RefreshExpiryKey is a sorted set of user IDs scored by the expiry of
+	// their refresh token.
+	RefreshExpiryKey = "refresh_expiry"
+	// RefreshReminderStreamKey receives a refresh_token_expiring event per
+	// reminder, next to login_events.
+	RefreshReminderStreamKey = "refresh_reminder_events"
+
+	defaultReminderLead = 24 * time.Hour
+	reminderWebhookWait = 10 * time.Second
+)
+
+var reminderClient = &http.Client{Timeout: reminderWebhookWait}
+
+// RefreshReminderEvent tells clients to prompt the user to sign in again
+// before the refresh token expires, rather than failing mid-action.
+type RefreshReminderEvent struct {
+	events.Metadata
+	UserID       int64     `json:"user_id"`
+	UserPublicID string    `json:"user_public_id"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	Timestamp    time.Time `json:"timestamp"`
+	EventType    string    `json:"event_type"`
+}
+
+func (s *AuthService) refreshRemindersEnabled() bool {
+	return s.cfg != nil && s.cfg.RefreshReminder.Enabled
+}
+
+func (s *AuthService) reminderLead() time.Duration {
+	if s.cfg.RefreshReminder.Lead > 0 {
+		return s.cfg.RefreshReminder.Lead
+	}
+	return defaultReminderLead
+}
+
+// addRefreshExpiry queues the expiry of the refresh token being stored on
+// pipe. A new token replaces the previous entry of the user.
+func (s *AuthService) addRefreshExpiry(ctx context.Context, pipe redis.Pipeliner, userID int64, expiresAt time.Time) {
+	if !s.refreshRemindersEnabled() {
+		return
+	}
+
+	pipe.ZAdd(ctx, RefreshExpiryKey, redis.Z{Score: float64(expiresAt.Unix()), Member: userID})
+	// The newest token expires last, so the set never outlives it.
+	pipe.Expire(ctx, RefreshExpiryKey, cookies.RefreshTokenExpiry)
+}
+
+// RemindExpiringRefreshTokens emits one reminder per refresh token expiring
+// within the configured lead and returns how many were sent. Each entry is
+// removed before its event is published, so a token is reminded at most
+// once; tokens that already expired are dropped without a reminder.
+func (s *AuthService) RemindExpiringRefreshTokens(ctx context.Context) (int, error) {
+	if !s.refreshRemindersEnabled() {
+		return 0, nil
+	}
+
+	rdb := s.cache.RawClient()
+	now := time.Now()
+
+	if err := rdb.ZRemRangeByScore(ctx, RefreshExpiryKey, "-inf", strconv.FormatInt(now.Unix(), 10)).Err(); err != nil {
+		return 0, err
+	}
+
+	due, err := rdb.ZRangeByScoreWithScores(ctx, RefreshExpiryKey, &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(now.Unix(), 10),
+		Max: strconv.FormatInt(now.Add(s.reminderLead()).Unix(), 10),
+	}).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, entry := range due {
+		member, _ := entry.Member.(string)
+		userID, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			rdb.ZRem(ctx, RefreshExpiryKey, member)
+			continue
+		}
+
+		// Another scan or a fresh login may have claimed the entry first.
+		removed, err := rdb.ZRem(ctx, RefreshExpiryKey, member).Result()
+		if err != nil {
+			return sent, err
+		}
+		if removed == 0 {
+			continue
+		}
+
+		u, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			continue
+		}
+
+		event := RefreshReminderEvent{
+			Metadata:     events.Stamp(events.RefreshTokenExpiring),
+			UserID:       userID,
+			UserPublicID: u.PublicID.String(),
+			ExpiresAt:    time.Unix(int64(entry.Score), 0).UTC(),
+			Timestamp:    now,
+			EventType:    events.RefreshTokenExpiring,
+		}
+		if err := s.publishRefreshReminder(ctx, event); err != nil {
+			s.logger.Warn("Failed to publish refresh reminder", "user_id", userID, "error", err)
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// publishRefreshReminder appends the event to the reminder stream and, when
+// a webhook is configured, posts it there as well. A failing webhook is
+// logged and recorded with the other deliveries; the stream stays the
+// source of truth.
+func (s *AuthService) publishRefreshReminder(ctx context.Context, event RefreshReminderEvent) error {
+	eventData, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal refresh reminder: %w", err)
+	}
+
+	_, err = s.cache.RawClient().XAdd(ctx, &redis.XAddArgs{
+		Stream: RefreshReminderStreamKey,
+		MaxLen: 100000,
+		Approx: true,
+		Values: map[string]interface{}{"event": eventData},
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	if url := s.cfg.RefreshReminder.WebhookURL; url != "" {
+		if _, err := s.deliverWebhook(ctx, url, events.RefreshTokenExpiring, eventData, false, ""); err != nil {
+			s.logger.Warn("Refresh reminder webhook failed", "user_id", event.UserID, "error", err)
+		}
+	}
+	return nil
+}
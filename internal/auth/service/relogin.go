@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/cookies"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/events"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/pkg/workerpool"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	ReloginCampaignPrefix    = "relogin_campaign:"
+	TokenRevokedBeforePrefix = "token_revoked_before:"
+
+	reloginCampaignTTL = 7 * 24 * time.Hour
+
+	// secondsCutoffBelow tells a cutoff in seconds, below it for another
+	// thirty thousand years, from one in milliseconds, above it since 2001.
+	secondsCutoffBelow = 1e12
+)
+
+var (
+	ErrTooManyCampaigns        = errors.New("too many relogin campaigns queued")
+	ErrReloginCampaignNotFound = errors.New("relogin campaign not found")
+)
+
+// newReloginPool runs campaigns one at a time by default; each one already
+// throttles itself between batches.
+func newReloginPool(cfg *configs.Config) *workerpool.Pool {
+	size, queue, policy := 1, 8, workerpool.PolicyDrop
+	if cfg != nil {
+		if cfg.Workers.Relogin.Size > 0 {
+			size = cfg.Workers.Relogin.Size
+		}
+		if cfg.Workers.Relogin.Queue > 0 {
+			queue = cfg.Workers.Relogin.Queue
+		}
+		if cfg.Workers.Relogin.Policy != "" {
+			policy = workerpool.Policy(cfg.Workers.Relogin.Policy)
+		}
+	}
+	return workerpool.New("relogin", size, queue, policy)
+}
+
+// StartReloginCampaign counts the cohort and revokes its tokens in the
+// background, recording progress in Redis so any instance can report it.
+func (s *AuthService) StartReloginCampaign(ctx context.Context, cohort repository.UserCohort, batchSize int, pause time.Duration, dryRun bool) (*model.ReloginCampaign, error) {
+	matched, err := s.userRepo.CountCohort(ctx, cohort)
+	if err != nil {
+		return nil, err
+	}
+
+	campaign := &model.ReloginCampaign{
+		ID:        uuid.NewString(),
+		Status:    model.ReloginCampaignStatusRunning,
+		DryRun:    dryRun,
+		Matched:   matched,
+		StartedAt: time.Now(),
+	}
+
+	if dryRun {
+		campaign.Status = model.ReloginCampaignStatusCompleted
+		campaign.FinishedAt = &campaign.StartedAt
+	}
+
+	if err := s.saveReloginCampaign(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	if !dryRun {
+		run := *campaign
+		if err := s.campaigns.Submit(ctx, func() { s.runReloginCampaign(run, cohort, batchSize, pause) }); err != nil {
+			s.finishReloginCampaign(ctx, campaign, err)
+			return nil, ErrTooManyCampaigns
+		}
+	}
+
+	return campaign, nil
+}
+
+// GetReloginCampaign returns ErrReloginCampaignNotFound once the campaign
+// is unknown or its record has expired.
+func (s *AuthService) GetReloginCampaign(ctx context.Context, id string) (*model.ReloginCampaign, error) {
+	val, err := s.cache.RawClient().Get(ctx, ReloginCampaignPrefix+id).Result()
+	if err == redis.Nil {
+		return nil, ErrReloginCampaignNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var campaign model.ReloginCampaign
+	if err := json.Unmarshal([]byte(val), &campaign); err != nil {
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+func (s *AuthService) runReloginCampaign(campaign model.ReloginCampaign, cohort repository.UserCohort, batchSize int, pause time.Duration) {
+	ctx := context.Background()
+	revokedAt := time.Now()
+
+	var afterID int64
+	for {
+		ids, err := s.userRepo.ListCohortIDs(ctx, cohort, afterID, batchSize)
+		if err == nil && len(ids) > 0 {
+			err = s.RevokeUserTokens(ctx, ids, revokedAt, model.RevocationReasonForcedRelogin)
+		}
+		if err != nil {
+			s.finishReloginCampaign(ctx, &campaign, err)
+			return
+		}
+		if len(ids) == 0 {
+			break
+		}
+
+		afterID = ids[len(ids)-1]
+		campaign.Processed += len(ids)
+		s.logger.Info("Relogin campaign progress", "campaign", campaign.ID, "processed", campaign.Processed, "matched", campaign.Matched)
+
+		if err := s.saveReloginCampaign(ctx, &campaign); err != nil {
+			s.logger.Warn("Failed to record relogin campaign progress", "campaign", campaign.ID, "error", err)
+		}
+
+		if len(ids) < batchSize {
+			break
+		}
+		time.Sleep(pause)
+	}
+
+	s.finishReloginCampaign(ctx, &campaign, nil)
+}
+
+func (s *AuthService) finishReloginCampaign(ctx context.Context, campaign *model.ReloginCampaign, err error) {
+	finishedAt := time.Now()
+	campaign.FinishedAt = &finishedAt
+	campaign.Status = model.ReloginCampaignStatusCompleted
+
+	if err != nil {
+		msg := err.Error()
+		campaign.Status = model.ReloginCampaignStatusFailed
+		campaign.Error = &msg
+		s.logger.Error("Relogin campaign failed", "campaign", campaign.ID, "processed", campaign.Processed, "error", err)
+	}
+
+	if saveErr := s.saveReloginCampaign(ctx, campaign); saveErr != nil {
+		s.logger.Warn("Failed to record relogin campaign result", "campaign", campaign.ID, "error", saveErr)
+	}
+}
+
+func (s *AuthService) saveReloginCampaign(ctx context.Context, campaign *model.ReloginCampaign) error {
+	return s.cache.Set(ctx, ReloginCampaignPrefix+campaign.ID, campaign, reloginCampaignTTL)
+}
+
+// RevokeUserTokens drops the users' refresh tokens and marks every access
+// token issued up to revokedAt as revoked, recording the reason so rejected
+// clients can tell the user why they were signed out. The access tokens of
+// their sessions are blacklisted by jti too, for validators that only check
+// the blacklist, and a user_sessions_revoked event per user lists them. The
+// markers only need to outlive the longest token lifetime.
+func (s *AuthService) RevokeUserTokens(ctx context.Context, userIDs []int64, revokedAt time.Time, reason model.RevocationReason) error {
+	if err := s.sessions.Delete(ctx, userIDs...); err != nil {
+		return err
+	}
+
+	subjects := s.sessionSubjects(ctx, userIDs)
+	tokens := s.sessionTokens(ctx, userIDs)
+	now := time.Now()
+
+	pipe := s.cache.RawClient().Pipeline()
+	for _, id := range userIDs {
+		pipe.Unlink(ctx, fmt.Sprintf("%s%d%s", RefreshCachePrefix, id, refreshOriginSuffix))
+		pipe.ZRem(ctx, RefreshExpiryKey, id)
+		pipe.Set(ctx, fmt.Sprintf("%s%d", TokenRevokedBeforePrefix, id), revokedAt.UnixMilli(), cookies.RefreshTokenExpiry)
+		pipe.Set(ctx, fmt.Sprintf("%s%d", TokenRevocationReasonPrefix, id), string(reason), cookies.RefreshTokenExpiry)
+		addRevocationEvent(ctx, pipe, id, reason, revokedAt)
+		blacklisted, err := addSessionTokenBlacklist(ctx, pipe, id, tokens[id], now)
+		if err != nil {
+			return err
+		}
+		addSessionEvent(ctx, pipe, events.UserSessionsRevoked, id, subjects[id], reason, blacklisted, revokedAt)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// IsTokenRevokedForUser reports whether a token issued at issuedAt predates a
+// forced re-login for the user. The cutoff is kept in milliseconds and only
+// tokens issued strictly before it are revoked; iat carries whole seconds,
+// so a token from the second of the revocation counts from its start.
+func (s *AuthService) IsTokenRevokedForUser(ctx context.Context, userID int64, issuedAt time.Time) bool {
+	val, err := s.cache.RawClient().Get(ctx, fmt.Sprintf("%s%d", TokenRevokedBeforePrefix, userID)).Result()
+	if err != nil {
+		// redis.Nil: no forced re-login recorded for the user.
+		return false
+	}
+
+	revokedBefore, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return false
+	}
+	// Cutoffs recorded before they moved to milliseconds are in seconds;
+	// they expire with the longest token lifetime.
+	if revokedBefore < secondsCutoffBelow {
+		revokedBefore *= 1000
+	}
+
+	return issuedAt.UnixMilli() < revokedBefore
+}
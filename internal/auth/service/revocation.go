@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/events"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// TokenRevocationReasonPrefix holds the reason of the user's latest
+	// token_revoked_before marker.
+	TokenRevocationReasonPrefix = "token_revocation_reason:"
+	// RevocationStreamKey receives a token_revoked event per revocation, for
+	// audit consumers next to login_events.
+	RevocationStreamKey = "token_revocation_events"
+)
+
+// RevocationEvent is the payload of every revocation, whatever its path.
+type RevocationEvent struct {
+	events.Metadata
+	UserID    int64                  `json:"user_id"`
+	Reason    model.RevocationReason `json:"reason"`
+	Timestamp time.Time              `json:"timestamp"`
+	EventType string                 `json:"event_type"`
+}
+
+func addRevocationEvent(ctx context.Context, pipe redis.Pipeliner, userID int64, reason model.RevocationReason, at time.Time) {
+	eventData, err := json.Marshal(RevocationEvent{
+		Metadata:  events.Stamp(events.TokenRevoked),
+		UserID:    userID,
+		Reason:    reason,
+		Timestamp: at,
+		EventType: events.TokenRevoked,
+	})
+	if err != nil {
+		return
+	}
+
+	slog.Info("Revoked tokens", "user_id", userID, "reason", reason)
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: RevocationStreamKey,
+		MaxLen: 100000,
+		Approx: true,
+		Values: map[string]interface{}{"event": eventData},
+	})
+}
+
+// RecordRevocation publishes the event of a revocation that does not go
+// through RevokeUserTokens, such as a logout. Failures are logged; the
+// revocation itself already happened.
+func (s *AuthService) RecordRevocation(ctx context.Context, userID int64, reason model.RevocationReason) {
+	pipe := s.cache.RawClient().Pipeline()
+	addRevocationEvent(ctx, pipe, userID, reason, time.Now())
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.logger.Warn("Failed to publish revocation event", "user_id", userID, "error", err)
+	}
+}
+
+// RevocationReasonFor returns why the user's tokens were last revoked.
+// Markers written before reasons were recorded count as a forced re-login.
+func (s *AuthService) RevocationReasonFor(ctx context.Context, userID int64) model.RevocationReason {
+	reason, err := s.cache.RawClient().Get(ctx, fmt.Sprintf("%s%d", TokenRevocationReasonPrefix, userID)).Result()
+	if err != nil || !model.RevocationReason(reason).IsValid() {
+		return model.RevocationReasonForcedRelogin
+	}
+	return model.RevocationReason(reason)
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/database/ent/loginattempt"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+const (
+	// checkupLookback is how far back sign-ins count towards the checkup.
+	checkupLookback     = 30 * 24 * time.Hour
+	checkupHistoryLimit = 200
+
+	// Passwords older than passwordWarnAge warn, older than passwordFailAge
+	// fail.
+	passwordWarnAge = 180 * 24 * time.Hour
+	passwordFailAge = 365 * 24 * time.Hour
+
+	// Successful sign-ins scoring at least suspiciousRiskScore count as
+	// suspicious, like repeated failures do.
+	suspiciousRiskScore = 50
+)
+
+// securityCheckWeights are the points of each check. Two-factor
+// authentication weighs most, as it protects against every other lapse.
+var securityCheckWeights = map[model.SecurityCheckKind]int32{
+	model.SecurityCheckKindPasswordAge:        20,
+	model.SecurityCheckKindTwoFactor:          25,
+	model.SecurityCheckKindActiveSessions:     10,
+	model.SecurityCheckKindUnknownDevices:     15,
+	model.SecurityCheckKindRecoveryEmail:      10,
+	model.SecurityCheckKindSuspiciousActivity: 20,
+}
+
+// SecurityCheckup sums up the security of the account. Two-factor
+// authentication and recovery emails are not offered by the service, so
+// their checks are UNAVAILABLE and left out of the score.
+func (s *AuthService) SecurityCheckup(ctx context.Context, u *ent.User) (*model.SecurityCheckup, error) {
+	now := time.Now()
+	since := now.Add(-checkupLookback)
+	attempts, err := s.userRepo.ListLoginAttempts(ctx, repository.LoginAttemptFilter{UserID: &u.ID, Since: &since}, 0, checkupHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, takenOver, err := s.checkupSessions(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	checkup := &model.SecurityCheckup{
+		PasswordChangedAt:      u.PasswordChangedAt,
+		ActiveSessions:         int32(sessions),
+		UnknownDevices:         int32(s.unknownDevices(attempts)),
+		RecentSuspiciousEvents: int32(suspiciousAttempts(attempts)),
+	}
+
+	passwordAge := model.SecurityCheckStatusUnavailable
+	switch {
+	case u.PasswordHash == "":
+	case u.PasswordChangedAt == nil:
+		passwordAge = model.SecurityCheckStatusWarn
+	default:
+		age := now.Sub(*u.PasswordChangedAt)
+		days := int32(age / (24 * time.Hour))
+		checkup.PasswordAgeDays = &days
+		passwordAge = graded(age >= passwordWarnAge, age >= passwordFailAge)
+	}
+
+	sessionStatus := model.SecurityCheckStatusPass
+	if takenOver {
+		sessionStatus = model.SecurityCheckStatusFail
+	}
+
+	unknownDevices := model.SecurityCheckStatusPass
+	if checkup.UnknownDevices > 0 {
+		unknownDevices = model.SecurityCheckStatusWarn
+	}
+
+	checkup.Checks = []*model.SecurityCheck{
+		securityCheck(model.SecurityCheckKindPasswordAge, passwordAge),
+		securityCheck(model.SecurityCheckKindTwoFactor, model.SecurityCheckStatusUnavailable),
+		securityCheck(model.SecurityCheckKindActiveSessions, sessionStatus),
+		securityCheck(model.SecurityCheckKindUnknownDevices, unknownDevices),
+		securityCheck(model.SecurityCheckKindRecoveryEmail, model.SecurityCheckStatusUnavailable),
+		securityCheck(model.SecurityCheckKindSuspiciousActivity, graded(checkup.RecentSuspiciousEvents > 0, riskySignIns(attempts) > 0)),
+	}
+	checkup.Score = securityScore(checkup.Checks)
+	return checkup, nil
+}
+
+// checkupSessions counts the refresh session of the user and reports
+// whether an access token issued to it was flagged as taken over.
+func (s *AuthService) checkupSessions(ctx context.Context, userID int64) (int, bool, error) {
+	sessions, err := s.sessions.ListByUser(ctx, userID)
+	if err != nil {
+		return 0, false, err
+	}
+
+	tokens := s.sessionTokens(ctx, []int64{userID})[userID]
+	if len(tokens) == 0 {
+		return len(sessions), false, nil
+	}
+	keys := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if jti, ok := token.Member.(string); ok {
+			keys = append(keys, SessionSuspiciousPrefix+jti)
+		}
+	}
+	flagged, err := s.cache.RawClient().Exists(ctx, keys...).Result()
+	if err != nil {
+		return 0, false, err
+	}
+	return len(sessions), flagged > 0, nil
+}
+
+// unknownDevices counts the devices of the successful sign-ins that matched
+// neither the network nor the device of an earlier one, the way login alerts
+// judge them. The oldest sign-in has nothing to compare with.
+func (s *AuthService) unknownDevices(attempts []*ent.LoginAttempt) int {
+	successes := make([]*ent.LoginAttempt, 0, len(attempts))
+	for _, attempt := range attempts {
+		if attempt.Outcome == loginattempt.OutcomeSUCCESS {
+			successes = append(successes, attempt)
+		}
+	}
+	sort.Slice(successes, func(i, j int) bool { return successes[i].ID < successes[j].ID })
+
+	unknown := map[string]bool{}
+	for i := 1; i < len(successes); i++ {
+		attempt := successes[i]
+		if s.signInRecognized(attempt.IP, attempt.UserAgent, attempt.DeviceID, successes[:i]) {
+			continue
+		}
+		device := attempt.DeviceID
+		if device == "" {
+			device = attempt.UserAgent
+		}
+		unknown[device] = true
+	}
+	return len(unknown)
+}
+
+func suspiciousAttempts(attempts []*ent.LoginAttempt) int {
+	n := riskySignIns(attempts)
+	for _, attempt := range attempts {
+		if attempt.FailureReason != nil && *attempt.FailureReason == loginattempt.FailureReasonINVALID_PASSWORD {
+			n++
+		}
+	}
+	return n
+}
+
+func riskySignIns(attempts []*ent.LoginAttempt) int {
+	n := 0
+	for _, attempt := range attempts {
+		if attempt.Outcome == loginattempt.OutcomeSUCCESS && attempt.RiskScore >= suspiciousRiskScore {
+			n++
+		}
+	}
+	return n
+}
+
+func graded(warn, fail bool) model.SecurityCheckStatus {
+	switch {
+	case fail:
+		return model.SecurityCheckStatusFail
+	case warn:
+		return model.SecurityCheckStatusWarn
+	default:
+		return model.SecurityCheckStatusPass
+	}
+}
+
+func securityCheck(kind model.SecurityCheckKind, status model.SecurityCheckStatus) *model.SecurityCheck {
+	return &model.SecurityCheck{Kind: kind, Status: status, Weight: securityCheckWeights[kind]}
+}
+
+// securityScore is the share of the points of the available checks earned,
+// all of them on PASS and half on WARN.
+func securityScore(checks []*model.SecurityCheck) int32 {
+	var earned, available int32
+	for _, check := range checks {
+		switch check.Status {
+		case model.SecurityCheckStatusPass:
+			earned += 2 * check.Weight
+		case model.SecurityCheckStatusWarn:
+			earned += check.Weight
+		case model.SecurityCheckStatusUnavailable:
+			continue
+		}
+		available += 2 * check.Weight
+	}
+	if available == 0 {
+		return 100
+	}
+	return earned * 100 / available
+}
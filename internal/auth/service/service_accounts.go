@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/database/ent/user"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/abisalde/authentication-service/pkg/verification"
+)
+
+const (
+	// ServiceAccountScope marks access tokens minted for service accounts,
+	// so resource servers can tell them apart at introspection.
+	ServiceAccountScope = "service_account"
+
+	serviceAccountKeyPrefix   = "sa_"
+	serviceAccountKeyIDBytes  = 12
+	serviceAccountSecretBytes = 32
+	// Generated addresses sit under a reserved TLD, so nothing sent to them
+	// could ever be delivered.
+	serviceAccountEmailDomain = "service-accounts.invalid"
+
+	defaultServiceAccountTokenTTL      = 15 * time.Minute
+	defaultServiceAccountRotationGrace = 24 * time.Hour
+)
+
+var (
+	ErrServiceAccountNotFound    = errors.New("service account not found")
+	ErrServiceAccountKeyNotFound = errors.New("service account key not found")
+	ErrInvalidServiceAccountKey  = errors.New("invalid service account key")
+	// ErrServiceAccount refuses flows meant for people, such as password
+	// and email changes, on service accounts.
+	ErrServiceAccount = errors.New("not available to service accounts")
+)
+
+// IsServiceAccount reports whether the account belongs to an organization's
+// automations rather than a person.
+func IsServiceAccount(u *ent.User) bool {
+	return u != nil && u.Kind == user.KindSERVICE
+}
+
+// ServiceAccountCredentials is a freshly issued key. The secret is never
+// stored and cannot be shown again.
+type ServiceAccountCredentials struct {
+	Account *ent.User
+	KeyID   string
+	Secret  string
+}
+
+// CreateServiceAccount creates a service account owned by the organization
+// with the given slug, together with its first key.
+func (s *AuthService) CreateServiceAccount(ctx context.Context, slug, name string) (*ServiceAccountCredentials, error) {
+	org, err := s.GetOrganization(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	key, secret, err := newServiceAccountKey()
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.userRepo.CreateServiceAccount(ctx, repository.ServiceAccount{
+		OrganizationID: org.ID,
+		Name:           strings.TrimSpace(name),
+		Email:          fmt.Sprintf("%s@%s.%s", key.KeyID, org.Slug, serviceAccountEmailDomain),
+	}, key)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Service account created", "service_account", account.PublicID, "organization", org.Slug)
+	return &ServiceAccountCredentials{Account: account, KeyID: key.KeyID, Secret: secret}, nil
+}
+
+// ListServiceAccounts returns the service accounts of the organization with
+// the given slug.
+func (s *AuthService) ListServiceAccounts(ctx context.Context, slug string) ([]*ent.User, error) {
+	org, err := s.GetOrganization(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return s.userRepo.ListServiceAccounts(ctx, org.ID)
+}
+
+// RotateServiceAccountKey issues a new key. The account's other keys keep
+// working for the rotation grace period, then expire.
+func (s *AuthService) RotateServiceAccountKey(ctx context.Context, ref string) (*ServiceAccountCredentials, error) {
+	account, err := s.serviceAccount(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	key, secret, err := newServiceAccountKey()
+	if err != nil {
+		return nil, err
+	}
+	retireAt := time.Now().Add(s.serviceAccountRotationGrace())
+	if err := s.userRepo.AddServiceAccountKey(ctx, account.ID, key, retireAt); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Service account key rotated", "service_account", account.PublicID, "key_id", key.KeyID, "retire_at", retireAt)
+	account, err = s.userRepo.GetServiceAccount(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &ServiceAccountCredentials{Account: account, KeyID: key.KeyID, Secret: secret}, nil
+}
+
+// RevokeServiceAccountKey deletes one key at once and revokes the access
+// tokens the account holds, since any of them may have come from that key.
+func (s *AuthService) RevokeServiceAccountKey(ctx context.Context, ref, keyID string) (*ent.User, error) {
+	account, err := s.serviceAccount(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := s.userRepo.DeleteServiceAccountKey(ctx, account.ID, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted {
+		return nil, ErrServiceAccountKeyNotFound
+	}
+
+	if err := s.RevokeUserTokens(ctx, []int64{account.ID}, time.Now(), model.RevocationReasonServiceAccountKeyRevoked); err != nil {
+		s.logger.Error("Failed to revoke tokens of service account", "user_id", account.ID, "error", err)
+	}
+
+	s.logger.Info("Service account key revoked", "service_account", account.PublicID, "key_id", keyID)
+	return s.userRepo.GetServiceAccount(ctx, account.ID)
+}
+
+// ExchangeServiceAccountKey trades a key for an access token. There is no
+// refresh token; the account presents its key again instead.
+func (s *AuthService) ExchangeServiceAccountKey(ctx context.Context, keyID, secret string) (string, time.Duration, error) {
+	key, err := s.userRepo.GetServiceAccountKey(ctx, keyID)
+	if ent.IsNotFound(err) {
+		return "", 0, ErrInvalidServiceAccountKey
+	}
+	if err != nil {
+		return "", 0, err
+	}
+
+	now := time.Now()
+	owner := key.Edges.Owner
+	switch {
+	case !verification.Equal(key.SecretHash, hashServiceAccountSecret(secret)):
+		return "", 0, ErrInvalidServiceAccountKey
+	case key.ExpiresAt != nil && !now.Before(*key.ExpiresAt):
+		return "", 0, ErrInvalidServiceAccountKey
+	case !IsServiceAccount(owner) || IsDeleted(owner) || IsSuspended(owner):
+		return "", 0, ErrInvalidServiceAccountKey
+	}
+
+	ttl := s.serviceAccountTokenTTL()
+	token, err := jwt.GenerateTokenWithExtras(ctx, owner.PublicID.String(), jwt.TokenTypeAccess, ttl, jwt.Extras{
+		Scopes: []string{ServiceAccountScope},
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	s.RecordTokenIssued(ctx, jwt.TokenTypeAccess)
+
+	if err := s.userRepo.TouchServiceAccountKey(ctx, key.ID, now); err != nil {
+		s.logger.Warn("Failed to record use of service account key", "key_id", key.KeyID, "error", err)
+	}
+	return token, ttl, nil
+}
+
+func (s *AuthService) serviceAccount(ctx context.Context, ref string) (*ent.User, error) {
+	target, err := s.ResolveUserReference(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if !IsServiceAccount(target) {
+		return nil, ErrServiceAccountNotFound
+	}
+	return s.userRepo.GetServiceAccount(ctx, target.ID)
+}
+
+func (s *AuthService) serviceAccountTokenTTL() time.Duration {
+	if s.cfg.ServiceAccounts.TokenTTL > 0 {
+		return s.cfg.ServiceAccounts.TokenTTL
+	}
+	return defaultServiceAccountTokenTTL
+}
+
+func (s *AuthService) serviceAccountRotationGrace() time.Duration {
+	if s.cfg.ServiceAccounts.RotationGrace > 0 {
+		return s.cfg.ServiceAccounts.RotationGrace
+	}
+	return defaultServiceAccountRotationGrace
+}
+
+func newServiceAccountKey() (repository.ServiceAccountKey, string, error) {
+	buf := make([]byte, serviceAccountKeyIDBytes+serviceAccountSecretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return repository.ServiceAccountKey{}, "", err
+	}
+
+	secret := base64.RawURLEncoding.EncodeToString(buf[serviceAccountKeyIDBytes:])
+	return repository.ServiceAccountKey{
+		KeyID:      serviceAccountKeyPrefix + hex.EncodeToString(buf[:serviceAccountKeyIDBytes]),
+		SecretHash: hashServiceAccountSecret(secret),
+	}, secret, nil
+}
+
+// hashServiceAccountSecret needs no salt or stretching: the secret is 256
+// random bits, not a password.
+func hashServiceAccountSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/cookies"
+	"github.com/abisalde/authentication-service/internal/auth/sessionstore"
+)
+
+const (
+	// SessionActivityPrefix keeps when the refresh session of a user started
+	// and when it was last used, until its maximum lifetime.
+	SessionActivityPrefix = sessionstore.ActivityPrefix
+
+	// sessionActivityResolution throttles activity writes: requests closer
+	// together than this, or than a quarter of the idle timeout, only read
+	// the session.
+	sessionActivityResolution = time.Minute
+)
+
+var ErrSessionIdle = errors.New("session idle for longer than the idle timeout")
+
+func (s *AuthService) idleTimeoutEnabled() bool {
+	return s.cfg != nil && s.cfg.Sessions.IdleTimeout > 0
+}
+
+// sessionLifetime is how long a session may be extended for after sign-in,
+// never longer than the refresh token itself is valid.
+func (s *AuthService) sessionLifetime() time.Duration {
+	if max := s.cfg.Sessions.MaxLifetime; max > 0 && max < cookies.RefreshTokenExpiry {
+		return max
+	}
+	return cookies.RefreshTokenExpiry
+}
+
+// refreshTokenTTL is how long a refresh token stored now lives: its whole
+// validity, or the first idle window while the idle timeout is enabled.
+func (s *AuthService) refreshTokenTTL() time.Duration {
+	if !s.idleTimeoutEnabled() {
+		return cookies.RefreshTokenExpiry
+	}
+	return min(s.cfg.Sessions.IdleTimeout, s.sessionLifetime())
+}
+
+// UpdateSessionActivity records a use of the user's session and slides the
+// refresh token's expiry to one idle window from now, up to the session's
+// maximum lifetime. The store checks and slides in one step, so concurrent
+// requests never slide a session that another just found idle. A session
+// unused for longer than the idle timeout fails with ErrSessionIdle, even
+// while its access token is still valid. Sessions started before the idle
+// timeout was enabled, and store errors, pass.
+func (s *AuthService) UpdateSessionActivity(ctx context.Context, userID int64) error {
+	if !s.idleTimeoutEnabled() {
+		return nil
+	}
+
+	idle := s.cfg.Sessions.IdleTimeout
+	expiresAt, err := s.sessions.Touch(ctx, userID, time.Now(), sessionstore.Activity{
+		Idle:       idle,
+		Resolution: min(sessionActivityResolution, idle/4),
+		Lifetime:   s.sessionLifetime(),
+	})
+	if err == sessionstore.ErrIdle {
+		return ErrSessionIdle
+	}
+	if err != nil {
+		s.logger.Warn("Failed to update session activity", "user_id", userID, "error", err)
+		return nil
+	}
+
+	if !expiresAt.IsZero() && s.refreshRemindersEnabled() {
+		pipe := s.cache.RawClient().Pipeline()
+		s.addRefreshExpiry(ctx, pipe, userID, expiresAt)
+		if _, err := pipe.Exec(ctx); err != nil {
+			s.logger.Warn("Failed to move the refresh reminder", "user_id", userID, "error", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/events"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/redis/go-redis/v9"
+)
+
+// SessionEventStreamKey receives a session_created, session_revoked or
+// user_sessions_revoked event per session change, so downstream services
+// caching sessions or users can drop them at once. pkg/session tails it.
+const SessionEventStreamKey = "session_events"
+
+// SessionEvent tells downstream services a session of the user started or
+// ended. UserPublicID is the subject of the user's tokens; TokenIDs lists
+// the jtis of the access tokens blacklisted with the session.
+type SessionEvent struct {
+	events.Metadata
+	UserID       int64                  `json:"user_id"`
+	UserPublicID string                 `json:"user_public_id"`
+	Reason       model.RevocationReason `json:"reason,omitempty"`
+	TokenIDs     []string               `json:"token_ids,omitempty"`
+	Timestamp    time.Time              `json:"timestamp"`
+	EventType    string                 `json:"event_type"`
+}
+
+func addSessionEvent(ctx context.Context, pipe redis.Pipeliner, eventType string, userID int64, publicID string, reason model.RevocationReason, tokenIDs []string, at time.Time) {
+	eventData, err := json.Marshal(SessionEvent{
+		Metadata:     events.Stamp(eventType),
+		UserID:       userID,
+		UserPublicID: publicID,
+		Reason:       reason,
+		TokenIDs:     tokenIDs,
+		Timestamp:    at,
+		EventType:    eventType,
+	})
+	if err != nil {
+		return
+	}
+
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: SessionEventStreamKey,
+		MaxLen: 100000,
+		Approx: true,
+		Values: map[string]interface{}{"event": eventData},
+	})
+}
+
+// sessionSubjects returns the public IDs of the users, deleted or not, for
+// session events. A failed lookup is logged and leaves them empty; the
+// events still carry the numeric IDs.
+func (s *AuthService) sessionSubjects(ctx context.Context, userIDs []int64) map[int64]string {
+	subjects := make(map[int64]string, len(userIDs))
+	publicIDs, err := s.userRepo.PublicIDs(repository.IncludeDeleted(ctx), userIDs)
+	if err != nil {
+		s.logger.Warn("Failed to look up the subjects of session events", "events", len(userIDs), "error", err)
+		return subjects
+	}
+	for id, publicID := range publicIDs {
+		subjects[id] = publicID.String()
+	}
+	return subjects
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/alerting"
+	"github.com/abisalde/authentication-service/internal/auth/takeover"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/metrics"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+)
+
+const (
+	// SessionUsePrefix keeps the recent uses of an access token, by jti.
+	SessionUsePrefix = "session_use:"
+	// SessionSuspiciousPrefix marks a token flagged as taken over until it
+	// expires.
+	SessionSuspiciousPrefix = "session_suspicious:"
+)
+
+var ErrSessionTakeover = errors.New("session used from two places at once")
+
+var sessionTakeovers = metrics.Default.NewCounterVec(
+	"session_takeover_suspected_total",
+	"Access tokens used from conflicting networks or user agent families, by kind and mode.",
+	"kind", "mode",
+)
+
+func (s *AuthService) TakeoverPolicy() takeover.Policy {
+	return s.takeover
+}
+
+// AlertOnSecurityEvents sends an alert for every session flagged as taken
+// over and every refresh canary presented.
+func (s *AuthService) AlertOnSecurityEvents(alerts *alerting.Dispatcher) {
+	s.alerts = alerts
+}
+
+// CheckSessionUse records this use of the access token and compares it with
+// the uses inside the takeover window. A conflict marks the session
+// suspicious and alerts once per token; in restrict mode it also revokes
+// every token of the user and fails with ErrSessionTakeover. Redis errors
+// let the request through.
+func (s *AuthService) CheckSessionUse(ctx context.Context, u *ent.User, claims *jwt.Claims, ip, userAgent string) error {
+	if !s.takeover.Enabled() || u == nil || claims == nil || claims.ID == "" {
+		return nil
+	}
+
+	rdb := s.cache.RawClient()
+	usesKey := SessionUsePrefix + claims.ID
+	current := s.takeover.SampleOf(ip, userAgent, time.Now())
+
+	raw, err := rdb.LRange(ctx, usesKey, 0, int64(s.takeover.Samples-1)).Result()
+	if err != nil {
+		return nil
+	}
+	recent := make([]takeover.Sample, 0, len(raw))
+	for _, entry := range raw {
+		var sample takeover.Sample
+		if json.Unmarshal([]byte(entry), &sample) == nil {
+			recent = append(recent, sample)
+		}
+	}
+
+	if data, err := json.Marshal(current); err == nil {
+		pipe := rdb.Pipeline()
+		pipe.LPush(ctx, usesKey, data)
+		pipe.LTrim(ctx, usesKey, 0, int64(s.takeover.Samples-1))
+		pipe.Expire(ctx, usesKey, s.takeover.Window)
+		if _, err := pipe.Exec(ctx); err != nil {
+			s.logger.Warn("Failed to record session use", "jti", claims.ID, "error", err)
+		}
+	}
+
+	kinds := s.takeover.Conflicts(recent, current, ip)
+	if len(kinds) == 0 {
+		return nil
+	}
+
+	ttl := s.takeover.Window
+	if claims.ExpiresAt != nil {
+		ttl = max(time.Until(claims.ExpiresAt.Time), time.Second)
+	}
+	marked, err := rdb.SetNX(ctx, SessionSuspiciousPrefix+claims.ID, strings.Join(kinds, ","), ttl).Result()
+	if err != nil {
+		s.logger.Warn("Failed to mark session suspicious", "jti", claims.ID, "error", err)
+	}
+	if marked {
+		s.reportSessionTakeover(u, claims.ID, kinds, recent, current)
+	}
+
+	if !s.takeover.Restricts() {
+		return nil
+	}
+	if marked {
+		if err := s.RevokeUserTokens(ctx, []int64{u.ID}, time.Now(), model.RevocationReasonSessionTakeover); err != nil {
+			s.logger.Error("Failed to revoke tokens after a session takeover", "user_id", u.ID, "error", err)
+		}
+	}
+	return ErrSessionTakeover
+}
+
+// IsSessionSuspicious reports whether the token was flagged as taken over.
+func (s *AuthService) IsSessionSuspicious(ctx context.Context, jti string) bool {
+	n, err := s.cache.RawClient().Exists(ctx, SessionSuspiciousPrefix+jti).Result()
+	return err == nil && n > 0
+}
+
+func (s *AuthService) reportSessionTakeover(u *ent.User, jti string, kinds []string, recent []takeover.Sample, current takeover.Sample) {
+	mode := string(s.takeover.Mode)
+	for _, kind := range kinds {
+		sessionTakeovers.Inc(kind, mode)
+	}
+	s.logger.Warn("Session used from conflicting origins", "jti", jti, "user_id", u.ID, "conflicts", kinds, "origin", fmt.Sprintf("%+v", current.Origin), "recent", fmt.Sprintf("%+v", recent))
+
+	s.alerts.Fire(alerting.Alert{
+		Source:   "session_takeover",
+		Title:    "Possible session takeover",
+		Summary:  fmt.Sprintf("Access token of user %s used from conflicting %s within %s", u.PublicID, strings.Join(kinds, " and "), s.takeover.Window),
+		Severity: alerting.SeverityWarning,
+		DedupKey: "session_takeover:" + jti,
+		Details: map[string]string{
+			"user":      u.PublicID.String(),
+			"session":   jti,
+			"mode":      mode,
+			"network":   current.Network,
+			"ua_family": current.UAFamily,
+		},
+	})
+}
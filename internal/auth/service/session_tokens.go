@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/cookies"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/abisalde/authentication-service/pkg/session"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// SessionTokensPrefix keeps the jti of each unexpired access token issued
+	// to the user's session, scored by expiry, so revoking the session can
+	// blacklist them.
+	SessionTokensPrefix = "session_tokens:"
+	// BlacklistedTokenIDPrefix blacklists an access token by jti until it
+	// expires. Services validating tokens with pkg/session check the same
+	// keys.
+	BlacklistedTokenIDPrefix = session.BlacklistedTokenIDPrefix
+
+	legacyBlacklistPrefix = "blacklist:"
+)
+
+// ErrTokenWithoutID is returned for tokens that cannot be blacklisted because
+// they carry no jti, such as opaque tokens.
+var ErrTokenWithoutID = errors.New("token has no jti")
+
+// TrackSessionToken records the access token issued to the user's session
+// at sign-in or refresh. Opaque tokens are revoked through their own store
+// and are not tracked.
+func (s *AuthService) TrackSessionToken(ctx context.Context, userID int64, accessToken string) error {
+	jti, expiresAt := jwt.GetTokenID(accessToken)
+	if jti == "" {
+		return nil
+	}
+
+	key := fmt.Sprintf("%s%d", SessionTokensPrefix, userID)
+	pipe := s.cache.RawClient().TxPipeline()
+	pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(time.Now().Unix(), 10))
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(expiresAt.Unix()), Member: jti})
+	// No session access token outlives AccessTokenExpiry.
+	pipe.Expire(ctx, key, cookies.AccessTokenExpiry)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// sessionTokens returns the unexpired access tokens tracked for the users.
+// A failed lookup is logged and leaves them out; the revocation markers of
+// RevokeUserTokens still reject them.
+func (s *AuthService) sessionTokens(ctx context.Context, userIDs []int64) map[int64][]redis.Z {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	pipe := s.cache.RawClient().Pipeline()
+	cmds := make(map[int64]*redis.ZSliceCmd, len(userIDs))
+	for _, id := range userIDs {
+		cmds[id] = pipe.ZRangeByScoreWithScores(ctx, fmt.Sprintf("%s%d", SessionTokensPrefix, id), &redis.ZRangeBy{Min: "(" + now, Max: "+inf"})
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		s.logger.Warn("Failed to look up the access tokens of sessions", "sessions", len(userIDs), "error", err)
+		return nil
+	}
+
+	tokens := make(map[int64][]redis.Z, len(userIDs))
+	for id, cmd := range cmds {
+		if tracked := cmd.Val(); len(tracked) > 0 {
+			tokens[id] = tracked
+		}
+	}
+	return tokens
+}
+
+// addSessionTokenBlacklist queues the blacklisting of the tokens for the
+// rest of their lifetime and untracks them, and returns their jtis. Tokens
+// issued since they were read stay tracked for the next revocation.
+func addSessionTokenBlacklist(ctx context.Context, pipe redis.Pipeliner, userID int64, tokens []redis.Z, now time.Time) ([]string, error) {
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	jtis := make([]string, 0, len(tokens))
+	members := make([]interface{}, 0, len(tokens))
+	for _, token := range tokens {
+		jti, _ := token.Member.(string)
+		members = append(members, jti)
+		ttl := time.Unix(int64(token.Score), 0).Sub(now)
+		if ttl <= 0 {
+			continue
+		}
+		if err := setCached(ctx, pipe, BlacklistedTokenIDPrefix+jti, "blacklisted", ttl); err != nil {
+			return nil, err
+		}
+		jtis = append(jtis, jti)
+	}
+	pipe.ZRem(ctx, fmt.Sprintf("%s%d", SessionTokensPrefix, userID), members...)
+	return jtis, nil
+}
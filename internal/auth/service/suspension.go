@@ -0,0 +1,208 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/database/ent/user"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/pkg/verification"
+)
+
+const (
+	SuspensionAppealPrefix = "suspension_appeal:"
+
+	defaultAppealTTL    = 30 * 24 * time.Hour
+	suspensionAppealTTL = 90 * 24 * time.Hour
+)
+
+var (
+	ErrAlreadySuspended   = errors.New("account already suspended")
+	ErrNotSuspended       = errors.New("account not suspended")
+	ErrAppealTokenInvalid = errors.New("appeal token invalid or already used")
+)
+
+//go:embed templates/suspension_email_template.html
+var suspensionTemplate embed.FS
+
+// IsSuspended reports whether an admin has suspended the account. Suspended
+// users keep their data, unlike soft deleted ones, but cannot sign in,
+// refresh tokens or pass the @auth directive.
+func IsSuspended(u *ent.User) bool {
+	return u != nil && u.SuspendedAt != nil
+}
+
+// SuspensionReason returns the reason code of a suspended account.
+func SuspensionReason(u *ent.User) string {
+	if u == nil || u.SuspensionReason == nil {
+		return string(user.SuspensionReasonOTHER)
+	}
+	return string(*u.SuspensionReason)
+}
+
+// SuspendUser marks the account suspended, revokes every token issued so far
+// and emails the user the reason with a signed appeal link. The database
+// state is authoritative: every request reloads the user, so a failed Redis
+// revocation or email is logged rather than undoing the suspension.
+func (s *AuthService) SuspendUser(ctx context.Context, ref string, reason model.SuspensionReason) (*ent.User, error) {
+	target, err := s.ResolveUserReference(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if IsSuspended(target) {
+		return nil, ErrAlreadySuspended
+	}
+
+	suspendedAt := time.Now()
+	suspended, err := s.userRepo.Suspend(ctx, target.ID, user.SuspensionReason(reason), suspendedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.RevokeUserTokens(ctx, []int64{suspended.ID}, suspendedAt, model.RevocationReasonAccountSuspended); err != nil {
+		s.logger.Error("Failed to revoke tokens of suspended user", "user_id", suspended.ID, "error", err)
+	}
+
+	if err := s.sendSuspensionEmail(ctx, suspended); err != nil {
+		s.logger.Warn("Failed to send suspension email", "user_id", suspended.ID, "error", err)
+	}
+
+	return suspended, nil
+}
+
+// LiftSuspension reinstates the account and discards its pending appeal.
+// Tokens revoked at suspension stay revoked; the user signs in again.
+func (s *AuthService) LiftSuspension(ctx context.Context, ref string) (*ent.User, error) {
+	target, err := s.ResolveUserReference(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if !IsSuspended(target) {
+		return nil, ErrNotSuspended
+	}
+
+	reinstated, err := s.userRepo.LiftSuspension(ctx, target.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.Delete(ctx, fmt.Sprintf("%s%d", SuspensionAppealPrefix, reinstated.ID)); err != nil {
+		s.logger.Warn("Failed to clear suspension appeal", "user_id", reinstated.ID, "error", err)
+	}
+
+	return reinstated, nil
+}
+
+// SubmitSuspensionAppeal consumes the token from the suspension email and
+// stores the user's message for admins. A newer appeal replaces an older one.
+func (s *AuthService) SubmitSuspensionAppeal(ctx context.Context, token, message string) error {
+	payload, err := s.ConsumeActionToken(ctx, token, verification.PurposeAppeal)
+	if err != nil {
+		return ErrAppealTokenInvalid
+	}
+
+	appellant, err := s.ResolveUserReference(ctx, payload.Subject)
+	if err != nil {
+		return ErrAppealTokenInvalid
+	}
+	if !IsSuspended(appellant) {
+		return ErrNotSuspended
+	}
+
+	appeal := &model.SuspensionAppeal{
+		UserID:      appellant.PublicID.String(),
+		Reason:      model.SuspensionReason(SuspensionReason(appellant)),
+		Message:     strings.TrimSpace(message),
+		SubmittedAt: time.Now(),
+	}
+
+	return s.cache.Set(ctx, fmt.Sprintf("%s%d", SuspensionAppealPrefix, appellant.ID), appeal, suspensionAppealTTL)
+}
+
+// GetSuspensionAppeal returns the latest appeal of the user, or nil when none
+// was submitted.
+func (s *AuthService) GetSuspensionAppeal(ctx context.Context, ref string) (*model.SuspensionAppeal, error) {
+	target, err := s.ResolveUserReference(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var appeal model.SuspensionAppeal
+	if err := s.cache.Get(ctx, fmt.Sprintf("%s%d", SuspensionAppealPrefix, target.ID), &appeal); err != nil {
+		return nil, nil
+	}
+	return &appeal, nil
+}
+
+func (s *AuthService) sendSuspensionEmail(ctx context.Context, u *ent.User) error {
+	if IsServiceAccount(u) {
+		return nil
+	}
+
+	ttl := s.cfg.Suspension.AppealTTL
+	if ttl <= 0 {
+		ttl = defaultAppealTTL
+	}
+
+	reason := SuspensionReason(u)
+	brand := s.BrandForUser(ctx, u)
+	data := struct {
+		Reason        string
+		AppealURL     string
+		AppealExpires string
+		Brand         Brand
+	}{Reason: reason, Brand: brand}
+
+	if s.cfg.Suspension.AppealURL != "" {
+		token, err := s.IssueActionToken(ctx, verification.PurposeAppeal, u.PublicID.String(), map[string]string{"reason": reason}, ttl)
+		if err != nil {
+			// Still tell the user why they were suspended; support can
+			// resend an appeal link.
+			s.logger.Warn("Failed to issue appeal token", "user_id", u.ID, "error", err)
+		} else {
+			data.AppealURL = appealLink(s.cfg.Suspension.AppealURL, token)
+			data.AppealExpires = time.Now().Add(ttl).UTC().Format("2 January 2006")
+		}
+	}
+
+	tmplData, err := suspensionTemplate.ReadFile("templates/suspension_email_template.html")
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := template.New("suspension").Parse(string(tmplData))
+	if err != nil {
+		return err
+	}
+
+	var htmlBody bytes.Buffer
+	if err := tmpl.Execute(&htmlBody, data); err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Your account has been suspended and you have been signed out of every device.\n\nReason code: %s", reason)
+	if brand.SupportEmail != "" {
+		body += fmt.Sprintf("\n\nQuote the reason code when contacting %s.", brand.SupportEmail)
+	}
+	if data.AppealURL != "" {
+		body += fmt.Sprintf("\n\nIf you believe this is a mistake, appeal before %s: %s", data.AppealExpires, data.AppealURL)
+	}
+
+	return s.mailService.SendHTMLEmail(ctx, u.Email, "Your Account Has Been Suspended", htmlBody.String(), body)
+}
+
+func appealLink(base, token string) string {
+	separator := "?"
+	if strings.Contains(base, "?") {
+		separator = "&"
+	}
+	return base + separator + "token=" + url.QueryEscape(token)
+}
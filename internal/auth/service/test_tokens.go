@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/abisalde/authentication-service/internal/auth/cookies"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/abisalde/authentication-service/pkg/password"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTestTokensDisabled = errors.New("sandbox test tokens are disabled")
+	ErrNotTestIdentity    = errors.New("email is not an allow-listed sandbox identity")
+)
+
+// IssueTestTokens signs a sandbox identity in without registration or email
+// verification, so QA pipelines can authenticate in one request. The user is
+// created verified on first use with a random password nobody knows. Only
+// allow-listed emails qualify, so real accounts in a shared staging database
+// cannot be impersonated.
+func (s *AuthService) IssueTestTokens(ctx context.Context, email string) (*ent.User, *cookies.TokenPair, error) {
+	if !s.sandbox.TestTokens() {
+		return nil, nil, ErrTestTokensDisabled
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	if !s.sandbox.IsAllowed(email) {
+		return nil, nil, ErrNotTestIdentity
+	}
+
+	u, err := s.userRepo.GetByEmail(ctx, email)
+	if ent.IsNotFound(err) {
+		u, err = s.createTestUser(ctx, email)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if IsSuspended(u) {
+		return nil, nil, ErrAlreadySuspended
+	}
+	if IsDeleted(u) {
+		return nil, nil, ErrAlreadyDeleted
+	}
+	// Signing in lifts a deactivation, as login does; tokens of a paused
+	// account would be refused on first use.
+	if u, err = s.LiftDeactivation(ctx, u); err != nil {
+		return nil, nil, err
+	}
+
+	scopes, err := s.TokenScopes(ctx, u)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := cookies.GenerateLoginTokenPair(ctx, u.PublicID.String(), cookies.DeviceFromContext(ctx), scopes)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.RecordTokenIssued(ctx, jwt.TokenTypeAccess, jwt.TokenTypeRefresh)
+
+	hashedToken, err := s.StoreRefreshToken(ctx, u, tokens.RefreshToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.TrackSessionToken(ctx, u.ID, tokens.AccessToken); err != nil {
+		return nil, nil, err
+	}
+
+	return u, &cookies.TokenPair{AccessToken: tokens.AccessToken, RefreshToken: hashedToken}, nil
+}
+
+func (s *AuthService) createTestUser(ctx context.Context, email string) (*ent.User, error) {
+	hash, err := password.HashPassword(uuid.NewString())
+	if err != nil {
+		return nil, err
+	}
+
+	local, _, _ := strings.Cut(email, "@")
+	return s.userRepo.CreateNewUser(ctx, &model.RegisterVerifiedUser{
+		Email:           email,
+		Password:        hash,
+		IsEmailVerified: true,
+		FirstName:       local,
+		LastName:        "Sandbox",
+	})
+}
@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/database/ent/scheduledaction"
+	"github.com/abisalde/authentication-service/internal/events"
+)
+
+func TestAccountActions_ScheduleAndCancel(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(rdb), &mockMailService{})
+
+	admin := createVerifiedUser(t, client, "actions_admin@example.com")
+	member := createVerifiedUser(t, client, "actions_member@example.com")
+	ref := member.PublicID.String()
+	nextWeek := time.Now().Add(7 * 24 * time.Hour)
+
+	if _, err := authService.ScheduleAccountAction(ctx, admin, ref, scheduledaction.ActionDEACTIVATE, time.Now().Add(-time.Minute), ""); err != service.ErrRunAtNotInFuture {
+		t.Errorf("Expected ErrRunAtNotInFuture for a past date, got %v", err)
+	}
+	if _, err := authService.ScheduleAccountAction(ctx, admin, admin.PublicID.String(), scheduledaction.ActionDELETE, nextWeek, ""); err != service.ErrManageOwnAccount {
+		t.Errorf("Expected admins not to schedule actions on their own account, got %v", err)
+	}
+
+	action, err := authService.ScheduleAccountAction(ctx, admin, ref, scheduledaction.ActionDEACTIVATE, nextWeek, "Contract ends")
+	if err != nil {
+		t.Fatalf("Failed to schedule the action: %v", err)
+	}
+	if action.Status != scheduledaction.StatusPENDING || action.Edges.User == nil || *action.CreatedBy != admin.PublicID {
+		t.Errorf("Expected a pending action created by the admin, got %+v", action)
+	}
+
+	cancelled, err := authService.CancelScheduledAccountAction(ctx, admin, action.ID)
+	if err != nil {
+		t.Fatalf("Failed to cancel the action: %v", err)
+	}
+	if cancelled.Status != scheduledaction.StatusCANCELLED || cancelled.FinishedAt == nil {
+		t.Errorf("Expected the action to be cancelled, got %+v", cancelled)
+	}
+	if _, err := authService.CancelScheduledAccountAction(ctx, admin, action.ID); err != service.ErrScheduledActionNotPending {
+		t.Errorf("Expected a second cancellation to fail, got %v", err)
+	}
+	if _, err := authService.CancelScheduledAccountAction(ctx, admin, action.ID+1000); err != service.ErrScheduledActionNotFound {
+		t.Errorf("Expected ErrScheduledActionNotFound, got %v", err)
+	}
+
+	listed, err := authService.ListScheduledAccountActions(ctx, ref)
+	if err != nil || len(listed) != 1 {
+		t.Fatalf("Expected the cancelled action to be listed, got %d (%v)", len(listed), err)
+	}
+
+	entries, err := rdb.XRange(ctx, service.AccountActionStreamKey, "-", "+").Result()
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("Expected scheduled and cancelled events, got %d (%v)", len(entries), err)
+	}
+	var event service.AccountActionEvent
+	if err := json.Unmarshal([]byte(entries[1].Values["event"].(string)), &event); err != nil {
+		t.Fatalf("Failed to decode the event: %v", err)
+	}
+	if event.Status != "CANCELLED" || event.Actor != admin.PublicID.String() || event.EventType != events.AccountAction {
+		t.Errorf("Expected a cancellation event by the admin, got %+v", event)
+	}
+}
+
+func TestAccountActions_RunsDueActions(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(rdb), &mockMailService{})
+
+	admin := createVerifiedUser(t, client, "actions_run_admin@example.com")
+	contractor := createVerifiedUser(t, client, "actions_contractor@example.com")
+	leaver := createVerifiedUser(t, client, "actions_leaver@example.com")
+	intern := createVerifiedUser(t, client, "actions_intern@example.com")
+
+	soon := time.Now().Add(200 * time.Millisecond)
+	scheduled := map[scheduledaction.Action]int64{}
+	for action, id := range map[scheduledaction.Action]string{
+		scheduledaction.ActionREVOKE_SESSIONS: contractor.PublicID.String(),
+		scheduledaction.ActionDELETE:          leaver.PublicID.String(),
+		scheduledaction.ActionDEACTIVATE:      intern.PublicID.String(),
+	} {
+		created, err := authService.ScheduleAccountAction(ctx, admin, id, action, soon, "")
+		if err != nil {
+			t.Fatalf("Failed to schedule %s: %v", action, err)
+		}
+		scheduled[action] = created.ID
+	}
+	later, err := authService.ScheduleAccountAction(ctx, admin, intern.PublicID.String(), scheduledaction.ActionDELETE, time.Now().Add(time.Hour), "")
+	if err != nil {
+		t.Fatalf("Failed to schedule the later action: %v", err)
+	}
+
+	if ran, err := authService.RunDueAccountActions(ctx); err != nil || ran != 0 {
+		t.Errorf("Expected nothing to run before the actions are due, ran %d (%v)", ran, err)
+	}
+	time.Sleep(300 * time.Millisecond)
+	if ran, err := authService.RunDueAccountActions(ctx); err != nil || ran != 3 {
+		t.Fatalf("Expected the three due actions to run, ran %d (%v)", ran, err)
+	}
+	if ran, _ := authService.RunDueAccountActions(ctx); ran != 0 {
+		t.Errorf("Expected actions to run once, ran %d again", ran)
+	}
+
+	repo := repository.NewUserRepository(client)
+	for action, id := range scheduled {
+		got, err := repo.GetScheduledAction(ctx, id)
+		if err != nil {
+			t.Fatalf("Failed to load %s: %v", action, err)
+		}
+		if got.Status != scheduledaction.StatusCOMPLETED || got.FinishedAt == nil {
+			t.Errorf("Expected %s to complete, got %s (%s)", action, got.Status, got.Error)
+		}
+	}
+	if pending, _ := repo.GetScheduledAction(ctx, later.ID); pending.Status != scheduledaction.StatusPENDING {
+		t.Errorf("Expected the action due later to stay pending, got %s", pending.Status)
+	}
+
+	if !authService.IsTokenRevokedForUser(ctx, contractor.ID, time.Now().Add(-time.Minute)) {
+		t.Error("Expected the contractor's tokens to be revoked")
+	}
+	if reason := authService.RevocationReasonFor(ctx, contractor.ID); reason != "SCHEDULED_REVOCATION" {
+		t.Errorf("Expected the scheduled revocation reason, got %s", reason)
+	}
+	if u, _ := repo.GetByID(repository.IncludeDeleted(ctx), leaver.ID); !service.IsDeleted(u) {
+		t.Error("Expected the leaver's account to be deleted")
+	}
+	if u, _ := repo.GetByID(ctx, intern.ID); !service.IsDeactivated(u) {
+		t.Error("Expected the intern's account to be deactivated")
+	}
+}
@@ -0,0 +1,226 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/database/ent/useridentity"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/pkg/password"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestAccountLinking_UnlinkKeepsASignInMethod(t *testing.T) {
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := repository.NewUserRepository(client)
+	authService := service.NewAuthService(repo, &configs.Config{}, redisCache, &mockMailService{})
+	oauthService := service.NewOAuthService(authService)
+
+	created, err := repo.CreateUserFromOAuth(ctx, "GOOGLE", &model.OAuthUserResponse{ID: "g-1", Email: "linked@example.com"})
+	if err != nil {
+		t.Fatalf("Failed to create OAuth user: %v", err)
+	}
+	if _, err := repo.LinkIdentity(ctx, created.ID, repository.Identity{Provider: "FACEBOOK", OAuthID: "fb-1"}); err != nil {
+		t.Fatalf("Failed to link a second account: %v", err)
+	}
+	if _, err := repo.LinkIdentity(ctx, created.ID, repository.Identity{Provider: "FACEBOOK", OAuthID: "fb-1"}); err == nil {
+		t.Error("Expected an account to be linked at most once")
+	}
+
+	if u, err := repo.FindByOAuthID(ctx, "FACEBOOK", "fb-1"); err != nil || u.ID != created.ID {
+		t.Fatalf("Expected the linked account to sign in to the user, got %v, %v", u, err)
+	}
+
+	identities, err := oauthService.LinkedAccounts(ctx, created)
+	if err != nil || len(identities) != 2 || identities[0].Provider != useridentity.ProviderGOOGLE {
+		t.Fatalf("Expected both linked accounts, got %v, %v", identities, err)
+	}
+
+	if _, err := oauthService.UnlinkOAuthAccount(ctx, created, identities[1].ID+100); err != service.ErrIdentityNotFound {
+		t.Errorf("Expected ErrIdentityNotFound, got %v", err)
+	}
+	remaining, err := oauthService.UnlinkOAuthAccount(ctx, created, identities[0].ID)
+	if err != nil || len(remaining) != 1 || remaining[0].OauthID != "fb-1" {
+		t.Fatalf("Expected the Google account unlinked, got %v, %v", remaining, err)
+	}
+	if _, err := repo.FindByOAuthID(ctx, "GOOGLE", "g-1"); err == nil {
+		t.Error("Expected an unlinked account to stop signing in, even as the one the user was created with")
+	}
+	if _, err := oauthService.UnlinkOAuthAccount(ctx, created, remaining[0].ID); err != service.ErrLastSignInMethod {
+		t.Errorf("Expected the last way to sign in to stay linked, got %v", err)
+	}
+
+	exists, err := authService.InitiateRegistration(ctx, model.RegisterInput{Email: "linked@example.com"})
+	if err != nil || exists {
+		t.Errorf("Expected registering with the email of an OAuth account to add a password, got %v, %v", exists, err)
+	}
+	hash, _ := password.HashPassword("Password1!")
+	if err := repo.UpdateNewPassword(ctx, created.ID, hash); err != nil {
+		t.Fatalf("Failed to set password: %v", err)
+	}
+	if exists, _ := authService.InitiateRegistration(ctx, model.RegisterInput{Email: "linked@example.com"}); !exists {
+		t.Error("Expected the email taken once the account has a password")
+	}
+	withPassword, _ := repo.GetByID(ctx, created.ID)
+	if _, err := oauthService.UnlinkOAuthAccount(ctx, withPassword, remaining[0].ID); err != nil {
+		t.Errorf("Expected an account with a password to unlink every OAuth account, got %v", err)
+	}
+}
+
+func TestAccountLinking_EmailVerifiedClaim(t *testing.T) {
+	profile := func(body string, claims configs.OAuthClaims) *model.OAuthUserResponse {
+		t.Helper()
+		var fields map[string]any
+		if err := json.Unmarshal([]byte(body), &fields); err != nil {
+			t.Fatalf("Failed to decode userinfo: %v", err)
+		}
+		p, err := service.OAuthProfile(fields, claims)
+		if err != nil {
+			t.Fatalf("Failed to map profile: %v", err)
+		}
+		return p
+	}
+
+	if !profile(`{"sub":"1","email":"a@example.com","email_verified":true}`, configs.OAuthClaims{}).IsEmailVerified {
+		t.Error("Expected the standard email_verified claim to be read")
+	}
+	if !profile(`{"sub":"1","email":"a@example.com","email_verified":"true"}`, configs.OAuthClaims{}).IsEmailVerified {
+		t.Error("Expected a string email_verified to be accepted")
+	}
+	if !profile(`{"id":"1","email":"a@example.com","verified_email":true}`, configs.OAuthClaims{ID: "id", EmailVerified: "verified_email"}).IsEmailVerified {
+		t.Error("Expected the configured claim to be read")
+	}
+	if profile(`{"sub":"1","email":"a@example.com"}`, configs.OAuthClaims{}).IsEmailVerified {
+		t.Error("Expected a missing claim to leave the email unverified")
+	}
+	if profile(`{"sub":"1","email_verified":true}`, configs.OAuthClaims{}).IsEmailVerified {
+		t.Error("Expected no verified email without an email")
+	}
+}
+
+func TestAccountLinking_CallbackLinksAccounts(t *testing.T) {
+	t.Setenv("JWT_SECRET", "account-linking-test-secret")
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	redisCache := database.NewCacheService(embeddedRedis(t))
+
+	ctx := context.Background()
+
+	userinfo := `{}`
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/token":
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "acme-access", "token_type": "Bearer"})
+		case "/userinfo":
+			_, _ = w.Write([]byte(userinfo))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer idp.Close()
+
+	cfg := &configs.Config{}
+	cfg.OAuth.Providers = map[string]configs.OAuthProvider{
+		"acme": {
+			AuthURL:      idp.URL + "/authorize",
+			TokenURL:     idp.URL + "/token",
+			UserInfoURL:  idp.URL + "/userinfo",
+			ClientID:     "acme-client",
+			ClientSecret: "acme-secret",
+		},
+	}
+	repo := repository.NewUserRepository(client)
+	authService := service.NewAuthService(repo, cfg, redisCache, &mockMailService{})
+	oauthService := service.NewOAuthService(authService)
+
+	hash, _ := password.HashPassword("Password1!")
+	owner, err := client.User.Create().SetEmail("owner@example.com").SetPasswordHash(hash).SetIsEmailVerified(true).Save(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	app := fiber.New()
+	app.Get("/callback/:mode/:state", func(c *fiber.Ctx) error {
+		_, _, _, err := oauthService.HandleCallBack(c, "acme", string(model.OAuthPlatformWeb), c.Params("mode"), "code", c.Params("state"))
+		return err
+	})
+	callback := func(mode model.PasswordLessMode, state string) int {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest("GET", "/callback/"+string(mode)+"/"+state, nil), -1)
+		if err != nil {
+			t.Fatalf("Callback failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	signIn := func(mode model.PasswordLessMode, state string) int {
+		t.Helper()
+		if _, _, err := oauthService.GetAuthPKCEURL(ctx, "acme", model.OAuthPlatformWeb, state, mode); err != nil {
+			t.Fatalf("Failed to start the authorization: %v", err)
+		}
+		return callback(mode, state)
+	}
+
+	// Without a verified email the sign-in is refused rather than merged.
+	userinfo = `{"sub":"unverified","email":"owner@example.com"}`
+	signIn(model.PasswordLessModeLogin, "state-unverified")
+	if _, err := repo.FindByOAuthID(ctx, service.ConfiguredOAuthProvider, "acme:unverified"); err == nil {
+		t.Error("Expected an unverified email not to link the account")
+	}
+
+	userinfo = `{"sub":"verified","email":"owner@example.com","email_verified":true}`
+	signIn(model.PasswordLessModeLogin, "state-verified")
+	if u, err := repo.FindByOAuthID(ctx, service.ConfiguredOAuthProvider, "acme:verified"); err != nil || u.ID != owner.ID {
+		t.Fatalf("Expected the verified email to link the account, got %v, %v", u, err)
+	}
+
+	userinfo = `{"sub":"second","email":"other@example.com"}`
+	if _, _, err := oauthService.StartLink(ctx, owner, "acme", model.OAuthPlatformWeb, "state-link"); err != nil {
+		t.Fatalf("Failed to start the link: %v", err)
+	}
+	callback(service.OAuthModeLink, "state-link")
+	if u, err := repo.FindByOAuthID(ctx, service.ConfiguredOAuthProvider, "acme:second"); err != nil || u.ID != owner.ID {
+		t.Fatalf("Expected the link to attach the account to the user who started it, got %v, %v", u, err)
+	}
+
+	other := createVerifiedUser(t, client, "someone@example.com")
+	if _, _, err := oauthService.StartLink(ctx, other, "acme", model.OAuthPlatformWeb, "state-taken"); err != nil {
+		t.Fatalf("Failed to start the link: %v", err)
+	}
+	if status := callback(service.OAuthModeLink, "state-taken"); status != fiber.StatusConflict {
+		t.Errorf("Expected an account linked to someone else to be refused, got %d", status)
+	}
+
+	identities, _ := oauthService.LinkedAccounts(ctx, owner)
+	if len(identities) != 2 || identities[0].LastUsedAt == nil || !identities[0].LastUsedAt.After(time.Now().Add(-time.Minute)) {
+		t.Errorf("Expected two linked accounts with their last use, got %v", identities)
+	}
+
+	// Registering with the email of an OAuth-only account adds a password.
+	oauthOnly, err := repo.CreateUserFromOAuth(ctx, "GOOGLE", &model.OAuthUserResponse{ID: "g-2", Email: "oauth-only@example.com"})
+	if err != nil {
+		t.Fatalf("Failed to create OAuth user: %v", err)
+	}
+	if err := authService.CreatePendingUser(ctx, model.PendingUser{Email: oauthOnly.Email, HashPassword: hash, VerificationCode: "1234", ExpiresAt: time.Now().Add(time.Minute)}); err != nil {
+		t.Fatalf("Failed to create pending user: %v", err)
+	}
+	merged, err := authService.VerifyCodeAndCreateUser(ctx, oauthOnly.Email, "1234")
+	if err != nil || merged.ID != oauthOnly.ID || password.CheckPasswordHash("Password1!", merged.PasswordHash) != nil {
+		t.Errorf("Expected the password added to the OAuth account, got %v, %v", merged, err)
+	}
+	if _, err := repo.FindByOAuthID(ctx, "GOOGLE", "g-2"); err != nil {
+		t.Errorf("Expected the Google account to stay linked, got %v", err)
+	}
+}
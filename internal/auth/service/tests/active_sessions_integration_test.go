@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/cookies"
+	handler "github.com/abisalde/authentication-service/internal/auth/handler/http"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/events"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/abisalde/authentication-service/pkg/session"
+)
+
+func TestActiveSessions_ListAndRevoke(t *testing.T) {
+	t.Setenv("JWT_SECRET", "active-sessions-test-secret")
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(rdb), &mockMailService{})
+	profile := handler.NewProfileHandler(authService)
+	owner := createVerifiedUser(t, client, "active_sessions_owner@example.com")
+
+	signIn := func() (string, context.Context) {
+		t.Helper()
+		token, err := cookies.GenerateAccessToken(ctx, owner.PublicID.String(), nil, nil)
+		if err != nil {
+			t.Fatalf("Failed to issue an access token: %v", err)
+		}
+		if err := authService.TrackSessionToken(ctx, owner.ID, token); err != nil {
+			t.Fatalf("Failed to track the access token: %v", err)
+		}
+		claims, err := jwt.ValidateToken(token)
+		if err != nil {
+			t.Fatalf("Failed to validate the access token: %v", err)
+		}
+		return claims.ID, auth.WithUser(ctx, owner, claims)
+	}
+	refreshKey := fmt.Sprintf("%s%d", service.RefreshCachePrefix, owner.ID)
+
+	laptop, _ := signIn()
+	phone, phoneCtx := signIn()
+	// Tokens are scored by their expiry in seconds, so the latest sign-in
+	// has to come a second later to be told apart.
+	time.Sleep(1100 * time.Millisecond)
+	tablet, _ := signIn()
+	if _, err := authService.StoreRefreshToken(ctx, owner, "active-sessions-refresh-token"); err != nil {
+		t.Fatalf("Failed to store the refresh token: %v", err)
+	}
+
+	sessions, err := profile.GetActiveSessions(phoneCtx)
+	if err != nil || len(sessions) != 3 {
+		t.Fatalf("Expected three sessions, got %+v, %v", sessions, err)
+	}
+	if sessions[0].ID != tablet {
+		t.Errorf("Expected the latest session first, got %s", sessions[0].ID)
+	}
+	for _, s := range sessions {
+		if s.Current != (s.ID == phone) {
+			t.Errorf("Expected only the phone's session to be current, got %+v", s)
+		}
+	}
+
+	if _, err := profile.RevokeSession(auth.WithAutomationClient(phoneCtx, "reporting-bot"), laptop); err == nil {
+		t.Error("Expected a connected app not to be able to revoke sessions")
+	}
+	if _, err := profile.RevokeSession(phoneCtx, "unknown-session"); err == nil {
+		t.Error("Expected revoking an unknown session to fail")
+	}
+
+	remaining, err := profile.RevokeSession(phoneCtx, laptop)
+	if err != nil || len(remaining) != 2 {
+		t.Fatalf("Expected two sessions to remain, got %+v, %v", remaining, err)
+	}
+	if n := rdb.Exists(ctx, session.BlacklistedTokenIDPrefix+laptop).Val(); n != 1 {
+		t.Error("Expected the revoked session's token to be blacklisted where session validators look")
+	}
+	if n := rdb.Exists(ctx, refreshKey).Val(); n != 1 {
+		t.Error("Expected revoking an older session to keep the refresh session")
+	}
+
+	remaining, err = profile.RevokeOtherSessions(phoneCtx)
+	if err != nil || len(remaining) != 1 || remaining[0].ID != phone || !remaining[0].Current {
+		t.Fatalf("Expected only the current session to remain, got %+v, %v", remaining, err)
+	}
+	if n := rdb.Exists(ctx, session.BlacklistedTokenIDPrefix+tablet).Val(); n != 1 {
+		t.Error("Expected the other session's token to be blacklisted")
+	}
+	if n := rdb.Exists(ctx, refreshKey).Val(); n != 0 {
+		t.Error("Expected the refresh session of the latest device to end with it")
+	}
+
+	if ok, err := profile.LogoutAllDevices(phoneCtx); err != nil || !ok {
+		t.Fatalf("Failed to sign out every device: %v", err)
+	}
+	if n := rdb.Exists(ctx, session.BlacklistedTokenIDPrefix+phone).Val(); n != 1 {
+		t.Error("Expected the current session to be signed out too")
+	}
+	if sessions, err := profile.GetActiveSessions(phoneCtx); err != nil || len(sessions) != 0 {
+		t.Errorf("Expected no sessions left, got %+v, %v", sessions, err)
+	}
+
+	entries, err := rdb.XRange(ctx, service.SessionEventStreamKey, "-", "+").Result()
+	if err != nil {
+		t.Fatalf("Failed to read the session events: %v", err)
+	}
+	var reasons []model.RevocationReason
+	for _, entry := range entries {
+		var event service.SessionEvent
+		if err := json.Unmarshal([]byte(entry.Values["event"].(string)), &event); err != nil {
+			t.Fatalf("Failed to decode the event: %v", err)
+		}
+		if event.EventType == events.SessionRevoked || event.EventType == events.UserSessionsRevoked {
+			reasons = append(reasons, event.Reason)
+		}
+	}
+	if len(reasons) != 3 || reasons[0] != model.RevocationReasonSessionSignedOut || reasons[1] != model.RevocationReasonSessionSignedOut || reasons[2] != model.RevocationReasonUserLogout {
+		t.Errorf("Expected two sessions signed out and a logout everywhere, got %v", reasons)
+	}
+}
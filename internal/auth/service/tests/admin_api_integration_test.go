@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abisalde/authentication-service/internal/auth/automation"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/graph"
+	"github.com/abisalde/authentication-service/internal/graph/admin"
+	"github.com/abisalde/authentication-service/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestAdminAPI_SchemasSplitOperations(t *testing.T) {
+	public := graph.NewExecutableSchema(graph.Config{}).Schema()
+	adminSchema := admin.NewExecutableSchema(admin.Config{}).Schema()
+
+	for _, field := range []string{"users", "loginAttempts", "redisKeyspaceUsage", "oauthProviderHealth"} {
+		if public.Query.Fields.ForName(field) != nil {
+			t.Errorf("Expected %s to be removed from the public schema", field)
+		}
+		if adminSchema.Query.Fields.ForName(field) == nil {
+			t.Errorf("Expected %s on the admin schema", field)
+		}
+	}
+
+	if public.Mutation.Fields.ForName("forceRelogin") != nil || adminSchema.Mutation.Fields.ForName("forceRelogin") == nil {
+		t.Errorf("Expected forceRelogin on the admin schema only")
+	}
+	if adminSchema.Mutation.Fields.ForName("login") != nil || public.Mutation.Fields.ForName("login") == nil {
+		t.Errorf("Expected login on the public schema only")
+	}
+}
+
+func TestAdminAPI_AccessGate(t *testing.T) {
+	cfg := &configs.Config{}
+	cfg.AdminAPI.APIKeys = map[string]string{"ops": "admin-secret"}
+	registry := automation.NewAdminRegistry(cfg)
+
+	if _, err := middleware.AdminAccessMiddleware([]string{"not-a-network"}, registry); err == nil {
+		t.Fatalf("Expected an invalid network to be rejected")
+	}
+
+	newApp := func(networks []string) *fiber.App {
+		gate, err := middleware.AdminAccessMiddleware(networks, registry)
+		if err != nil {
+			t.Fatalf("Failed to build admin gate: %v", err)
+		}
+		app := fiber.New()
+		app.All("/admin/graphql", gate, func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+		return app
+	}
+
+	cases := []struct {
+		name     string
+		networks []string
+		header   string
+		key      string
+		want     int
+	}{
+		{"outside network without key", []string{"10.0.0.0/8"}, "", "", fiber.StatusNotFound},
+		{"admin key", nil, automation.DefaultAdminHeader, "admin-secret", fiber.StatusOK},
+		{"wrong key", nil, automation.DefaultAdminHeader, "guess", fiber.StatusNotFound},
+		{"automation header", nil, automation.DefaultHeader, "admin-secret", fiber.StatusNotFound},
+		{"internal network", []string{"0.0.0.0/0"}, "", "", fiber.StatusOK},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest("POST", "/admin/graphql", nil)
+		if tc.header != "" {
+			req.Header.Set(tc.header, tc.key)
+		}
+
+		resp, err := newApp(tc.networks).Test(req)
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", tc.name, err)
+		}
+		if resp.StatusCode != tc.want {
+			t.Errorf("%s: expected status %d, got %d", tc.name, tc.want, resp.StatusCode)
+		}
+	}
+}
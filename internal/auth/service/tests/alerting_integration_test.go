@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/alerting"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+)
+
+type recordingNotifier struct {
+	alerts chan alerting.Alert
+}
+
+func (n *recordingNotifier) Name() string { return "recording" }
+
+func (n *recordingNotifier) Notify(ctx context.Context, alert alerting.Alert) error {
+	n.alerts <- alert
+	return nil
+}
+
+func (n *recordingNotifier) next(t *testing.T) alerting.Alert {
+	t.Helper()
+	select {
+	case alert := <-n.alerts:
+		return alert
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected an alert to be delivered")
+		return alerting.Alert{}
+	}
+}
+
+func (n *recordingNotifier) none(t *testing.T) {
+	t.Helper()
+	select {
+	case alert := <-n.alerts:
+		t.Fatalf("Expected no alert, got %+v", alert)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestAlerting_ProviderDegradationPagesAndResolves(t *testing.T) {
+	pager := &recordingNotifier{alerts: make(chan alerting.Alert, 8)}
+	chat := &recordingNotifier{alerts: make(chan alerting.Alert, 8)}
+
+	alerts := alerting.NewDispatcher(time.Hour, "test",
+		alerting.Route{Notifier: pager, MinSeverity: alerting.SeverityCritical},
+		alerting.Route{Notifier: chat, MinSeverity: alerting.SeverityInfo},
+	)
+
+	cfg := &configs.Config{}
+	cfg.OAuthHealth.MinSamples = 2
+	health := service.NewProviderHealth(cfg, "google")
+	health.OnTransition(func(status service.ProviderStatus) {
+		alerts.Fire(alerting.Alert{
+			Source:   "oauth_health",
+			Title:    status.Provider + " sign-in degraded",
+			Severity: alerting.SeverityCritical,
+			DedupKey: "oauth_health:" + status.Provider,
+			Resolved: !status.Degraded,
+		})
+	})
+
+	health.Record("google", time.Second, service.OAuthErrorExchange)
+	health.Record("google", time.Second, service.OAuthErrorExchange)
+
+	if alert := pager.next(t); alert.Resolved || alert.Environment != "test" {
+		t.Fatalf("Expected a triggered alert for the test environment, got %+v", alert)
+	}
+	chat.next(t)
+
+	alerts.Fire(alerting.Alert{Source: "oauth_health", Title: "repeat", Severity: alerting.SeverityCritical, DedupKey: "oauth_health:google"})
+	pager.none(t)
+
+	alerts.Fire(alerting.Alert{Source: "rate_limit", Title: "noisy", Severity: alerting.SeverityWarning})
+	chat.next(t)
+	pager.none(t)
+
+	for i := 0; i < 4; i++ {
+		health.Record("google", 100*time.Millisecond, "")
+	}
+
+	if alert := pager.next(t); !alert.Resolved {
+		t.Fatalf("Expected the incident to resolve once google recovered, got %+v", alert)
+	}
+}
@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+func TestApple_ClientSecret(t *testing.T) {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate EC key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("Failed to encode EC key: %v", err)
+	}
+	key, err := jwt.ParseSigningKey("ABC123DEFG", "ES256", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("Failed to parse the .p8 key: %v", err)
+	}
+
+	secret, err := service.AppleClientSecret("TEAM123456", "com.example.web", key, time.Now())
+	if err != nil {
+		t.Fatalf("Failed to sign the client secret: %v", err)
+	}
+
+	claims := &gojwt.RegisteredClaims{}
+	err = jwt.ParseWithKeys(secret, claims, func(kid string) (*jwt.SigningKey, bool) {
+		return key, kid == "ABC123DEFG"
+	}, gojwt.WithIssuer("TEAM123456"), gojwt.WithAudience(service.AppleIssuer), gojwt.WithExpirationRequired())
+	if err != nil {
+		t.Fatalf("Expected an ES256 client secret naming the key, got %v", err)
+	}
+	if claims.Subject != "com.example.web" {
+		t.Errorf("Expected the Services ID as subject, got %q", claims.Subject)
+	}
+}
+
+func TestApple_IDTokenVerification(t *testing.T) {
+	apple, rotated, stranger := rsaSigningKey(t, "apple-1"), rsaSigningKey(t, "apple-2"), rsaSigningKey(t, "apple-3")
+
+	published := []*jwt.SigningKey{apple}
+	fetches := 0
+	keysServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		set := jwt.JWKS{}
+		for _, k := range published {
+			set.Keys = append(set.Keys, k.JWK())
+		}
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	defer keysServer.Close()
+
+	verifier := service.NewAppleIDTokens("com.example.web", keysServer.URL)
+	sign := func(key *jwt.SigningKey, audience, nonce string, expires time.Time) string {
+		t.Helper()
+		token, err := jwt.SignWith(key, gojwt.MapClaims{
+			"iss":            service.AppleIssuer,
+			"aud":            audience,
+			"sub":            "001234.abcdef.0001",
+			"exp":            expires.Unix(),
+			"iat":            time.Now().Unix(),
+			"email":          "relay@privaterelay.appleid.com",
+			"email_verified": "true",
+			"nonce":          nonce,
+		})
+		if err != nil {
+			t.Fatalf("Failed to sign id_token: %v", err)
+		}
+		return token
+	}
+	ctx := context.Background()
+	inAnHour := time.Now().Add(time.Hour)
+
+	claims, err := verifier.Verify(ctx, sign(apple, "com.example.web", "n-1", inAnHour), "n-1")
+	if err != nil {
+		t.Fatalf("Expected a valid id_token, got %v", err)
+	}
+	if claims.Subject != "001234.abcdef.0001" || claims.Email != "relay@privaterelay.appleid.com" || !claims.EmailVerified {
+		t.Errorf("Expected the profile claims with a string email_verified, got %+v", claims)
+	}
+
+	rejected := map[string]string{
+		"another audience": sign(apple, "com.example.other", "n-1", inAnHour),
+		"another nonce":    sign(apple, "com.example.web", "n-2", inAnHour),
+		"expired":          sign(apple, "com.example.web", "n-1", time.Now().Add(-time.Hour)),
+		"unknown key":      sign(stranger, "com.example.web", "n-1", inAnHour),
+	}
+	for name, token := range rejected {
+		if _, err := verifier.Verify(ctx, token, "n-1"); err == nil {
+			t.Errorf("Expected an id_token with %s to be rejected", name)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("Expected unknown kids to be refetched at most once a minute, got %d fetches", fetches)
+	}
+
+	// A key rotated in after the last fetch waits for the refetch delay.
+	published = append(published, rotated)
+	if _, err := verifier.Verify(ctx, sign(rotated, "com.example.web", "", inAnHour), ""); err == nil {
+		t.Error("Expected a freshly rotated key to wait for the refetch delay")
+	}
+	if _, err := service.NewAppleIDTokens("com.example.web", keysServer.URL).Verify(ctx, sign(rotated, "com.example.web", "", inAnHour), ""); err != nil {
+		t.Errorf("Expected the rotated key to verify after a fetch, got %v", err)
+	}
+}
+
+func TestApple_Profile(t *testing.T) {
+	claims := &service.AppleIDTokenClaims{Email: "owner@example.com", EmailVerified: true}
+	claims.Subject = "001234.abcdef.0001"
+
+	first := service.AppleProfile(claims, `{"name":{"firstName":"Ada","lastName":"Lovelace"},"email":"spoofed@example.com"}`)
+	if first.ID != claims.Subject || first.FirstName != "Ada" || first.LastName != "Lovelace" || first.Name == nil || *first.Name != "Ada Lovelace" {
+		t.Errorf("Expected the name of the first sign-in to be captured, got %+v", first)
+	}
+	if first.Email != "owner@example.com" || !first.IsEmailVerified {
+		t.Errorf("Expected the email to come from the signed id_token only, got %q", first.Email)
+	}
+
+	later := service.AppleProfile(claims, "")
+	if later.FirstName != "" || later.Name != nil || later.Email != "owner@example.com" {
+		t.Errorf("Expected later sign-ins to carry only the id_token profile, got %+v", later)
+	}
+}
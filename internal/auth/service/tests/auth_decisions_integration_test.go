@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/database/ent/user"
+	"github.com/abisalde/authentication-service/internal/graph/directives"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/metrics"
+	"github.com/abisalde/authentication-service/pkg/verification"
+	"github.com/google/uuid"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// authDecisionCount reads auth_decisions_total for source from the default
+// registry.
+func authDecisionCount(t testing.TB, source string) int {
+	t.Helper()
+	var out strings.Builder
+	if _, err := metrics.Default.WriteTo(&out); err != nil {
+		t.Fatalf("Failed to render metrics: %v", err)
+	}
+	prefix := fmt.Sprintf(`auth_decisions_total{source=%q} `, source)
+	for _, line := range strings.Split(out.String(), "\n") {
+		if value, found := strings.CutPrefix(line, prefix); found {
+			n, _ := strconv.ParseFloat(value, 64)
+			return int(n)
+		}
+	}
+	return 0
+}
+
+// withOperation runs fn inside an operation wrapped by MemoizeAuth.
+func withOperation(ctx context.Context, fn func(ctx context.Context)) {
+	directives.MemoizeAuth(ctx, func(ctx context.Context) graphql.ResponseHandler {
+		fn(ctx)
+		return nil
+	})
+}
+
+func guardedField(ctx context.Context, name string) context.Context {
+	return graphql.WithFieldContext(ctx, &graphql.FieldContext{
+		Object: "Query",
+		Field:  graphql.CollectedField{Field: &ast.Field{Name: name}},
+	})
+}
+
+func TestAuthDecisions_RoleChangeInvalidates(t *testing.T) {
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, redisCache, &mockMailService{})
+	decisions := directives.NewAuthDecisions(time.Minute)
+	authService.OnRoleChange(decisions.Invalidate)
+
+	admin := createVerifiedUser(t, client, "decisions_admin@example.com")
+	member := createVerifiedUser(t, client, "decisions_member@example.com")
+
+	if !decisions.Allowed(member.ID, user.RoleUSER, user.RoleUSER) {
+		t.Fatal("Expected a user to pass a USER check")
+	}
+	hits := authDecisionCount(t, "cache")
+	if !decisions.Allowed(member.ID, user.RoleUSER, user.RoleUSER) {
+		t.Fatal("Expected the cached decision to allow")
+	}
+	if got := authDecisionCount(t, "cache"); got != hits+1 {
+		t.Errorf("Expected the second check to come from the cache, hits went from %d to %d", hits, got)
+	}
+
+	if _, err := authService.ChangeUserRole(ctx, admin, member.PublicID.String(), model.UserRoleAdmin); err != nil {
+		t.Fatalf("Failed to change role: %v", err)
+	}
+	hits = authDecisionCount(t, "cache")
+	decisions.Allowed(member.ID, user.RoleUSER, user.RoleUSER)
+	if got := authDecisionCount(t, "cache"); got != hits {
+		t.Error("Expected the role change to drop the user's cached decisions")
+	}
+
+	// A role changed on another instance shows up in the reloaded user.
+	if !decisions.Allowed(member.ID, user.RoleADMIN, user.RoleADMIN) {
+		t.Fatal("Expected an admin to pass an ADMIN check")
+	}
+	if decisions.Allowed(member.ID, user.RoleUSER, user.RoleADMIN) {
+		t.Error("Expected a decision made for another role not to be reused")
+	}
+}
+
+func TestAuthDecisions_MemoizedPerOperation(t *testing.T) {
+	member := &ent.User{ID: 7, Role: user.RoleUSER}
+	ctx := auth.WithUser(context.Background(), member, nil)
+	next := graphql.Resolver(func(ctx context.Context) (interface{}, error) {
+		return true, nil
+	})
+	directive := directives.NewAuthDirective()
+	userRole := model.UserRoleUser
+	adminRole := model.UserRoleAdmin
+
+	memoHits := authDecisionCount(t, "memo")
+	withOperation(ctx, func(ctx context.Context) {
+		for _, field := range []string{"me", "sessions", "devices"} {
+			if _, err := directive.Auth(guardedField(ctx, field), nil, next, &userRole); err != nil {
+				t.Errorf("Expected %s to be allowed, got %v", field, err)
+			}
+		}
+		for _, field := range []string{"users", "auditLog"} {
+			if _, err := directive.Auth(guardedField(ctx, field), nil, next, &adminRole); err == nil {
+				t.Errorf("Expected %s to be denied to a user", field)
+			}
+		}
+	})
+	if got := authDecisionCount(t, "memo") - memoHits; got != 3 {
+		t.Errorf("Expected each required role to be checked once per operation, got %d memo hits", got)
+	}
+
+	memoHits = authDecisionCount(t, "memo")
+	withOperation(ctx, func(ctx context.Context) {
+		if _, err := directive.Auth(guardedField(ctx, "me"), nil, next, &userRole); err != nil {
+			t.Errorf("Expected me to be allowed, got %v", err)
+		}
+	})
+	if authDecisionCount(t, "memo") != memoHits {
+		t.Error("Expected a new operation to start with an empty memo")
+	}
+}
+
+// The benchmarks resolve a cookie-authenticated mutation of 20 guarded
+// fields, with the role and CSRF checks run per field, as before the memo,
+// and with the memo and decision cache.
+func benchmarkAuthDirective(b *testing.B, directive *directives.AuthDirective, memoize bool) {
+	member := &ent.User{ID: 7, Role: user.RoleUSER, PublicID: uuid.New()}
+	csrfToken, err := verification.SignCSRF(member.PublicID.String(), "session-binding", string(member.Role))
+	if err != nil {
+		b.Fatalf("Failed to sign the CSRF token: %v", err)
+	}
+
+	ctx := auth.WithUser(context.Background(), member, nil)
+	ctx = auth.WithCookieSession(ctx, "session-binding", csrfToken)
+	ctx = graphql.WithOperationContext(ctx, &graphql.OperationContext{
+		Operation: &ast.OperationDefinition{Operation: ast.Mutation},
+	})
+	next := graphql.Resolver(func(ctx context.Context) (interface{}, error) {
+		return true, nil
+	})
+	userRole := model.UserRoleUser
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		resolve := func(ctx context.Context) {
+			for range 20 {
+				if _, err := directive.Auth(guardedField(ctx, "field"), nil, next, &userRole); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+		if memoize {
+			withOperation(ctx, resolve)
+		} else {
+			resolve(ctx)
+		}
+	}
+}
+
+func BenchmarkAuthDirective_PerField(b *testing.B) {
+	benchmarkAuthDirective(b, directives.NewAuthDirective(), false)
+}
+
+func BenchmarkAuthDirective_Memoized(b *testing.B) {
+	decisions := directives.NewAuthDecisions(time.Minute)
+	benchmarkAuthDirective(b, directives.NewAuthDirective().WithDecisionCache(decisions), true)
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/abisalde/authentication-service/pkg/authsvc"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/abisalde/authentication-service/pkg/password"
+)
+
+func TestAuthsvc_EmbeddedLoginAndAuthenticate(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	db, err := sql.Open("sqlite3", "file:ent?mode=memory&cache=shared&_fk=1")
+	if err != nil {
+		t.Fatalf("Failed to open the user store: %v", err)
+	}
+	defer db.Close()
+	users := authsvc.NewUserRepository("sqlite3", db)
+	cache := authsvc.NewRedisCache(embeddedRedis(t))
+
+	// New installs process wide token settings; put the defaults back for
+	// the rest of the package.
+	t.Setenv("JWT_SECRET", "authsvc-test-secret")
+	t.Cleanup(func() {
+		_ = jwt.Configure(jwt.DefaultOptions())
+		jwt.SetKeySet(nil)
+		jwt.SetScopeStore(nil)
+		jwt.SetClaimsStore(nil)
+	})
+
+	cfg := authsvc.DefaultConfig()
+	if _, err := authsvc.New(cfg, authsvc.Deps{Cache: cache}); err == nil {
+		t.Error("Expected New to require a user repository")
+	}
+
+	deps := authsvc.Deps{Users: users, Cache: cache, Mailer: &mockMailService{}}
+	svc, err := authsvc.New(cfg, deps)
+	if err != nil {
+		t.Fatalf("Failed to embed the auth service: %v", err)
+	}
+	if _, err := authsvc.New(cfg, deps); err != authsvc.ErrAlreadyEmbedded {
+		t.Errorf("Expected a second Service to be refused, got %v", err)
+	}
+
+	ctx := context.Background()
+	hash, err := password.HashPassword("Str0ngPassw0rd!")
+	if err != nil {
+		t.Fatalf("Failed to hash password: %v", err)
+	}
+	client.User.Create().SetEmail("embedded@example.com").SetPasswordHash(hash).SetIsEmailVerified(true).SaveX(ctx)
+
+	if _, err := svc.Login(ctx, "embedded@example.com", "WrongPassw0rd!"); err != authsvc.ErrInvalidCredentials {
+		t.Errorf("Expected invalid credentials for a wrong password, got %v", err)
+	}
+	if _, err := svc.Login(ctx, "missing@example.com", "Str0ngPassw0rd!"); err != authsvc.ErrInvalidCredentials {
+		t.Errorf("Expected invalid credentials for an unknown email, got %v", err)
+	}
+
+	if _, err := svc.Authenticate(ctx, "not-a-token"); err != authsvc.ErrInvalidToken {
+		t.Errorf("Expected ErrInvalidToken, got %v", err)
+	}
+
+	session, err := svc.Login(ctx, "embedded@example.com", "Str0ngPassw0rd!")
+	if err != nil {
+		t.Fatalf("Failed to sign in: %v", err)
+	}
+	user, err := svc.Authenticate(ctx, session.AccessToken)
+	if err != nil || user.PublicID != session.User.PublicID || user.Email != "embedded@example.com" || !user.EmailVerified {
+		t.Errorf("Expected the access token to authenticate the user, got %+v (%v)", user, err)
+	}
+}
@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/abisalde/authentication-service/internal/handlers"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestBodyLimits_Return413(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler})
+	app.Post("/graphql", handlers.GraphQLBodyLimit(1024), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	// fasthttp reports bodies over the app's BodyLimit to the error handler
+	// with this error; app.Test surfaces it as a transport error instead.
+	app.Post("/oversized", func(c *fiber.Ctx) error {
+		return fiber.ErrRequestEntityTooLarge
+	})
+
+	post := func(path string, size int) (int, map[string]any) {
+		t.Helper()
+		body := `{"query":"` + strings.Repeat("a", size) + `"}`
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		defer resp.Body.Close()
+
+		var payload map[string]any
+		_ = json.NewDecoder(resp.Body).Decode(&payload)
+		return resp.StatusCode, payload
+	}
+
+	if status, _ := post("/graphql", 100); status != fiber.StatusOK {
+		t.Errorf("Expected a small body to pass, got %d", status)
+	}
+
+	status, payload := post("/graphql", 2048)
+	if status != fiber.StatusRequestEntityTooLarge || payload["errors"] == nil {
+		t.Errorf("Expected a GraphQL 413 above the GraphQL limit, got %d %v", status, payload)
+	}
+
+	status, payload = post("/oversized", 10)
+	if status != fiber.StatusRequestEntityTooLarge || payload["error"] != "PAYLOAD_TOO_LARGE" {
+		t.Errorf("Expected a structured 413 above the app limit, got %d %v", status, payload)
+	}
+}
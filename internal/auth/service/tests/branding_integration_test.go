@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+func TestBranding_OrganizationOverridesDefault(t *testing.T) {
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	mailer := &recordingMailService{}
+	cfg := &configs.Config{}
+	cfg.Branding.ProductName = "Auth Service"
+	cfg.Branding.LogoURL = "https://cdn.example.com/default.png"
+	authService := service.NewAuthService(repository.NewUserRepository(client), cfg, redisCache, mailer)
+
+	// No logo: the organization keeps the default one.
+	if _, err := authService.SaveOrganizationBranding(ctx, repository.OrganizationBranding{
+		Slug:         " Acme ",
+		Name:         "Acme Corp",
+		ProductName:  "Acme Cloud",
+		PrimaryColor: "#1a73e8",
+		SupportEmail: "help@acme.example",
+	}); err != nil {
+		t.Fatalf("Failed to save organization branding: %v", err)
+	}
+	if _, err := authService.SaveOrganizationBranding(ctx, repository.OrganizationBranding{Slug: "acme", Name: "Acme Corp", PrimaryColor: "blue"}); err == nil {
+		t.Error("Expected an invalid primary color to be rejected")
+	}
+
+	member := createVerifiedUser(t, client, "member@acme.example")
+	outsider := createVerifiedUser(t, client, "outsider@example.com")
+
+	slug := "acme"
+	if _, err := authService.AssignOrganization(ctx, member.PublicID.String(), &slug); err != nil {
+		t.Fatalf("Failed to assign organization: %v", err)
+	}
+	unknown := "globex"
+	if _, err := authService.AssignOrganization(ctx, outsider.PublicID.String(), &unknown); err != service.ErrOrganizationNotFound {
+		t.Errorf("Expected ErrOrganizationNotFound, got %v", err)
+	}
+
+	brand, err := authService.BrandForOrganization(ctx, "acme")
+	if err != nil {
+		t.Fatalf("Failed to load organization brand: %v", err)
+	}
+	want := service.Brand{ProductName: "Acme Cloud", LogoURL: "https://cdn.example.com/default.png", PrimaryColor: "#1a73e8", SupportEmail: "help@acme.example"}
+	if brand != want {
+		t.Errorf("Expected %+v, got %+v", want, brand)
+	}
+	if _, err := authService.BrandForOrganization(ctx, "globex"); err != service.ErrOrganizationNotFound {
+		t.Errorf("Expected ErrOrganizationNotFound, got %v", err)
+	}
+
+	for _, u := range []string{member.PublicID.String(), outsider.PublicID.String()} {
+		if _, err := authService.SuspendUser(ctx, u, model.SuspensionReasonSpam); err != nil {
+			t.Fatalf("Failed to suspend user: %v", err)
+		}
+	}
+	if len(mailer.sent) != 2 {
+		t.Fatalf("Expected two suspension emails, got %d", len(mailer.sent))
+	}
+
+	if mail := mailer.sent[0]; !strings.Contains(mail.html, "#1a73e8") || !strings.Contains(mail.html, "help@acme.example") || !strings.Contains(mail.plain, "help@acme.example") {
+		t.Errorf("Expected the member's email in Acme branding, got %s", mail.html)
+	}
+	if mail := mailer.sent[1]; strings.Contains(mail.html, "Acme") || !strings.Contains(mail.html, "https://cdn.example.com/default.png") || !strings.Contains(mail.html, "#ffffff") {
+		t.Errorf("Expected the outsider's email in the default brand, got %s", mail.html)
+	}
+
+	if moved, err := authService.AssignOrganization(ctx, member.PublicID.String(), nil); err != nil || moved.OrganizationID != nil {
+		t.Errorf("Expected the member removed from the organization, got %v %v", moved, err)
+	}
+}
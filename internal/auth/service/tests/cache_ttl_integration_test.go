@@ -0,0 +1,201 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/diagnostics"
+	"github.com/redis/go-redis/v9"
+)
+
+// commandLog records each command sent to Redis as its name and key count,
+// so a test can tell a DEL from a batch of UNLINKs.
+type commandLog struct {
+	mu   sync.Mutex
+	sent []string
+}
+
+func (l *commandLog) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (l *commandLog) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		l.record(cmd)
+		return next(ctx, cmd)
+	}
+}
+
+func (l *commandLog) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			l.record(cmd)
+		}
+		return next(ctx, cmds)
+	}
+}
+
+func (l *commandLog) record(cmd redis.Cmder) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sent = append(l.sent, fmt.Sprintf("%s %d", cmd.Name(), len(cmd.Args())-1))
+}
+
+// take returns the commands named one of names sent since the last call.
+func (l *commandLog) take(names ...string) []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var taken []string
+	for _, sent := range l.sent {
+		if slices.Contains(names, strings.Fields(sent)[0]) {
+			taken = append(taken, sent)
+		}
+	}
+	l.sent = nil
+	return taken
+}
+
+func seedKeys(t *testing.T, rdb *redis.Client, prefix string, n int) []string {
+	t.Helper()
+	keys := make([]string, n)
+	pipe := rdb.Pipeline()
+	for i := range keys {
+		keys[i] = fmt.Sprintf("%s%d", prefix, i)
+		pipe.Set(context.Background(), keys[i], "1", time.Hour)
+	}
+	if _, err := pipe.Exec(context.Background()); err != nil {
+		t.Fatalf("Failed to seed %s keys: %v", prefix, err)
+	}
+	return keys
+}
+
+func TestCache_TTLAuditReportsKeysWithoutExpiry(t *testing.T) {
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+
+	rdb.Set(ctx, "refresh_token:1", "a", time.Hour)
+	rdb.Set(ctx, "refresh_token:2", "b", 0)
+	rdb.Set(ctx, "refresh_token:3", "c", 0)
+	rdb.Set(ctx, "rate_limit:login:1", "1", time.Minute)
+	rdb.Set(ctx, "feature_flags:beta", "on", 0)
+
+	audits, err := diagnostics.RedisTTLAudit(ctx, rdb, []diagnostics.KeyspaceCategory{
+		{Name: "refresh_tokens", Pattern: "refresh_token:*"},
+		{Name: "rate_limit", Pattern: "rate_limit:*"},
+		{Name: "feature_flags", Pattern: "feature_flags:*", Persistent: true},
+	}, 1)
+	if err != nil {
+		t.Fatalf("Failed to audit TTLs: %v", err)
+	}
+
+	if len(audits) != 2 {
+		t.Fatalf("Expected persistent keyspaces to be skipped, got %+v", audits)
+	}
+	refresh, limits := audits[0], audits[1]
+	if refresh.Keys != 3 || refresh.NoExpiry != 2 {
+		t.Errorf("Expected 2 of 3 refresh tokens without expiry, got %d of %d", refresh.NoExpiry, refresh.Keys)
+	}
+	if len(refresh.Offenders) != 1 || !slices.Contains([]string{"refresh_token:2", "refresh_token:3"}, refresh.Offenders[0]) {
+		t.Errorf("Expected one offender within the limit, got %v", refresh.Offenders)
+	}
+	if limits.Keys != 1 || limits.NoExpiry != 0 || len(limits.Offenders) != 0 {
+		t.Errorf("Expected the rate limit keyspace to be clean, got %+v", limits)
+	}
+}
+
+func TestCache_TTLPolicy(t *testing.T) {
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	cache := database.NewCacheService(rdb)
+
+	if err := cache.WithTTLPolicy(true, time.Hour).Set(ctx, "defaulted", "v", 0); err != nil {
+		t.Fatalf("Expected the default TTL to apply, got %v", err)
+	}
+	if ttl := rdb.TTL(ctx, "defaulted").Val(); ttl <= 59*time.Minute || ttl > time.Hour {
+		t.Errorf("Expected a key written without TTL to get the 1h default, got %s", ttl)
+	}
+
+	if err := cache.WithTTLPolicy(false, time.Hour).Set(ctx, "explicit", "v", time.Minute); err != nil {
+		t.Fatalf("Failed to write with an explicit TTL: %v", err)
+	}
+	if ttl := rdb.TTL(ctx, "explicit").Val(); ttl > time.Minute {
+		t.Errorf("Expected an explicit TTL to win over the default, got %s", ttl)
+	}
+
+	err := cache.WithTTLPolicy(true, 0).Set(ctx, "refused", "v", 0)
+	if !errors.Is(err, database.ErrMissingTTL) {
+		t.Errorf("Expected ErrMissingTTL without a default, got %v", err)
+	}
+	if rdb.Exists(ctx, "refused").Val() != 0 {
+		t.Error("Expected a refused key not to be written")
+	}
+
+	if err := cache.Set(ctx, "unmanaged", "v", 0); err != nil {
+		t.Fatalf("Failed to write without a policy: %v", err)
+	}
+	if ttl := rdb.TTL(ctx, "unmanaged").Val(); ttl != -1 {
+		t.Errorf("Expected no policy to leave the key without expiry, got %s", ttl)
+	}
+}
+
+func TestCache_DeletePatternRemovesOnlyMatchingKeys(t *testing.T) {
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	cache := database.NewCacheService(rdb)
+
+	matching := seedKeys(t, rdb, "session_use:42:", 1200)
+	// Keys sharing the prefix up to the glob, or the rest of the name, stay.
+	kept := append(seedKeys(t, rdb, "session_use:421:", 3), seedKeys(t, rdb, "refresh_token:42:", 3)...)
+
+	deleted, err := cache.DeletePattern(ctx, "session_use:42:*")
+	if err != nil {
+		t.Fatalf("Failed to delete by pattern: %v", err)
+	}
+	if deleted != int64(len(matching)) {
+		t.Errorf("Expected %d keys deleted, got %d", len(matching), deleted)
+	}
+	if left := rdb.Exists(ctx, matching...).Val(); left != 0 {
+		t.Errorf("Expected every matching key gone, %d left", left)
+	}
+	if left := rdb.Exists(ctx, kept...).Val(); left != int64(len(kept)) {
+		t.Errorf("Expected the %d other keys kept, got %d", len(kept), left)
+	}
+}
+
+func TestCache_DeleteBatchesLargeDeletesWithUnlink(t *testing.T) {
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	sent := &commandLog{}
+	rdb.AddHook(sent)
+	cache := database.NewCacheService(rdb)
+
+	cases := []struct {
+		name string
+		keys int
+		want []string
+	}{
+		{"below the threshold", 63, []string{"del 63"}},
+		{"at the threshold", 64, []string{"unlink 64"}},
+		{"across batches", 1200, []string{"unlink 500", "unlink 500", "unlink 200"}},
+	}
+
+	for _, tc := range cases {
+		keys := seedKeys(t, rdb, tc.name+":", tc.keys)
+		sent.take()
+
+		if err := cache.Delete(ctx, keys...); err != nil {
+			t.Fatalf("%s: failed to delete: %v", tc.name, err)
+		}
+		if got := sent.take("del", "unlink"); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+		if left := rdb.Exists(ctx, keys...).Val(); left != 0 {
+			t.Errorf("%s: expected every key deleted, %d left", tc.name, left)
+		}
+	}
+}
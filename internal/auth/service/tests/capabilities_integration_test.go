@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/handlers"
+	"github.com/abisalde/authentication-service/internal/middleware"
+	"github.com/abisalde/authentication-service/internal/ratelimit"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestCapabilities_ResponseAndCaching(t *testing.T) {
+	cfg := &configs.Config{}
+	cfg.TwoFactor.Enabled = true
+	cfg.Providers.GoogleClientID = "google-client"
+	cfg.Providers.GoogleClientSecret = "google-secret"
+
+	limiter := ratelimit.New(database.NewCacheService(embeddedRedis(t)))
+	app := fiber.New()
+	app.Get("/api/capabilities", middleware.RateLimitMiddleware(limiter, handlers.CapabilitiesRateLimit), handlers.CapabilitiesHandler(cfg, nil))
+
+	get := func(ifNoneMatch string) (int, string, string, []byte) {
+		req := httptest.NewRequest("GET", "/api/capabilities", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set(fiber.HeaderIfNoneMatch, ifNoneMatch)
+		}
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("Capabilities request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, resp.Header.Get(fiber.HeaderETag), resp.Header.Get(fiber.HeaderCacheControl), body
+	}
+
+	status, etag, cacheControl, body := get("")
+	if status != fiber.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", status, body)
+	}
+	if cacheControl != "public, max-age=60, s-maxage=30" {
+		t.Errorf("Expected a minute in browsers and 30s in shared caches, got %q", cacheControl)
+	}
+	if etag == "" {
+		t.Error("Expected an ETag")
+	}
+
+	var caps handlers.Capabilities
+	if err := json.Unmarshal(body, &caps); err != nil {
+		t.Fatalf("Failed to decode capabilities: %v", err)
+	}
+	if caps.SchemaVersion != handlers.CapabilitiesSchemaVersion {
+		t.Errorf("Expected schema version %d, got %d", handlers.CapabilitiesSchemaVersion, caps.SchemaVersion)
+	}
+	if !caps.TwoFactor {
+		t.Error("Expected twoFactor to follow the config")
+	}
+	if len(caps.Providers) != 1 || caps.Providers[0] != "google" || !caps.PasswordLess {
+		t.Errorf("Expected google as the only provider, got %v", caps.Providers)
+	}
+	if caps.Token.Algorithm == "" || caps.Token.AccessTokenTTLSeconds <= 0 || caps.Token.RefreshTokenTTLSeconds <= 0 {
+		t.Errorf("Expected the token capabilities filled in, got %+v", caps.Token)
+	}
+
+	status, revalidated, _, body := get(etag)
+	if status != fiber.StatusNotModified || len(body) != 0 || revalidated != etag {
+		t.Errorf("Expected an empty 304 for the current ETag, got %d with %d bytes", status, len(body))
+	}
+	if status, _, _, _ := get(`"stale"`); status != fiber.StatusOK {
+		t.Errorf("Expected a stale ETag to get the payload, got %d", status)
+	}
+
+	for range handlers.CapabilitiesRateLimit.Limit - 3 {
+		get("")
+	}
+	if status, _, _, _ := get(""); status != fiber.StatusTooManyRequests {
+		t.Errorf("Expected the request over the limit to get 429, got %d", status)
+	}
+}
@@ -0,0 +1,238 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	gqlhandler "github.com/99designs/gqlgen/graphql/handler"
+	server "github.com/abisalde/authentication-service/cmd"
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/automation"
+	"github.com/abisalde/authentication-service/internal/auth/handler/http"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/graph"
+	"github.com/abisalde/authentication-service/internal/graph/directives"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/middleware"
+	"github.com/abisalde/authentication-service/internal/ratelimit"
+	"github.com/gofiber/fiber/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestClientQuota_RequestsPerDay(t *testing.T) {
+	cfg := &configs.Config{}
+	cfg.Automation.APIKeys = map[string]string{"reports": "reports-secret", "billing": "billing-secret"}
+	cfg.Automation.Quotas.Default = configs.ClientQuota{RequestsPerDay: 3}
+	cfg.Automation.Quotas.Clients = map[string]configs.ClientQuota{"billing": {RequestsPerDay: 10}}
+
+	limiter := ratelimit.New(database.NewCacheService(embeddedRedis(t))).WithClientQuotas(ratelimit.QuotasFromConfig(cfg))
+
+	app := fiber.New()
+	app.Use(middleware.AutomationMiddleware(automation.NewRegistry(cfg)))
+	app.Use(middleware.QuotaMiddleware(limiter))
+	app.Get("/ping", func(c *fiber.Ctx) error {
+		return c.SendString("pong")
+	})
+	call := func(key string) (int, string) {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/ping", nil)
+		if key != "" {
+			req.Header.Set(automation.DefaultHeader, key)
+		}
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode, resp.Header.Get(fiber.HeaderRetryAfter)
+	}
+
+	for i := 0; i < 3; i++ {
+		if status, _ := call("reports-secret"); status != fiber.StatusOK {
+			t.Fatalf("Expected request %d within the quota to pass, got %d", i+1, status)
+		}
+	}
+	status, retryAfter := call("reports-secret")
+	if status != fiber.StatusTooManyRequests || retryAfter == "" {
+		t.Errorf("Expected 429 with Retry-After once the quota is spent, got %d %q", status, retryAfter)
+	}
+
+	if status, _ := call("billing-secret"); status != fiber.StatusOK {
+		t.Errorf("Expected a client with its own quota to pass, got %d", status)
+	}
+	if status, _ := call(""); status != fiber.StatusOK {
+		t.Errorf("Expected requests without an API client to skip quotas, got %d", status)
+	}
+
+	quota, err := http.NewQuotaHandler(limiter).GetAPIClientQuota(auth.WithAutomationClient(context.Background(), "reports"))
+	if err != nil {
+		t.Fatalf("Failed to read quota: %v", err)
+	}
+	if len(quota.Usage) != 1 {
+		t.Fatalf("Expected only the limited dimension, got %+v", quota.Usage)
+	}
+	usage := quota.Usage[0]
+	if usage.Dimension != model.QuotaDimensionRequestsPerDay || usage.Used != 3 || usage.Remaining != 0 || usage.ResetsAt.Hour() != 0 {
+		t.Errorf("Expected rejected requests not to count, got %+v", usage)
+	}
+
+	if _, err := http.NewQuotaHandler(limiter).GetAPIClientQuota(context.Background()); err == nil {
+		t.Error("Expected the quota query to require an API client")
+	}
+}
+
+func TestClientQuota_LoginsPerHour(t *testing.T) {
+	cfg := &configs.Config{}
+	cfg.Automation.Quotas.Default = configs.ClientQuota{LoginsPerHour: 2}
+
+	limiter := ratelimit.New(database.NewCacheService(embeddedRedis(t))).WithClientQuotas(ratelimit.QuotasFromConfig(cfg))
+	rateLimit := directives.NewRateLimitDirective(limiter)
+	if err := rateLimit.Compile(graph.NewExecutableSchema(graph.Config{}).Schema()); err != nil {
+		t.Fatalf("Failed to compile rate limits: %v", err)
+	}
+	login := rateLimit.Policies()["Mutation.login"]
+
+	next := graphql.Resolver(func(ctx context.Context) (interface{}, error) {
+		return true, nil
+	})
+	signIn := func() error {
+		ctx := graphql.WithFieldContext(auth.WithAutomationClient(context.Background(), "sso-bridge"), &graphql.FieldContext{
+			Object: "Mutation",
+			Field:  graphql.CollectedField{Field: &ast.Field{Name: "login"}},
+		})
+		_, err := rateLimit.RateLimit(ctx, nil, next, login.Operation, int32(login.Limit), nil, nil, nil, nil, nil)
+		return err
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := signIn(); err != nil {
+			t.Fatalf("Expected sign-in %d within the quota to pass, got %v", i+1, err)
+		}
+	}
+	err := signIn()
+	gqlErr, ok := err.(*gqlerror.Error)
+	if !ok {
+		t.Fatalf("Expected a quota error, got %v", err)
+	}
+	quota, _ := gqlErr.Extensions["quota"].(map[string]interface{})
+	if gqlErr.Extensions["code"] != model.ErrorTypeRateLimited || quota["dimension"] != model.QuotaDimensionLoginsPerHour {
+		t.Errorf("Expected the exhausted dimension in the error, got %+v", gqlErr.Extensions)
+	}
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(auth.WithAutomationClient(c.UserContext(), "sso-bridge"))
+		return c.Next()
+	})
+	app.Get("/callback", middleware.RateLimitMiddleware(limiter, login), func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+	resp, err := app.Test(httptest.NewRequest("GET", "/callback", nil), -1)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != fiber.StatusTooManyRequests {
+		t.Errorf("Expected the HTTP sign-in to share the login quota, got %d", resp.StatusCode)
+	}
+}
+
+func TestClientQuota_AutomationStaysLimitedOutsideLogin(t *testing.T) {
+	limiter := ratelimit.New(database.NewCacheService(embeddedRedis(t)))
+	rateLimit := directives.NewRateLimitDirective(limiter)
+	if err := rateLimit.Compile(graph.NewExecutableSchema(graph.Config{}).Schema()); err != nil {
+		t.Fatalf("Failed to compile rate limits: %v", err)
+	}
+	verify := rateLimit.Policies()["Mutation.verifyAccount"]
+
+	next := graphql.Resolver(func(ctx context.Context) (interface{}, error) {
+		return true, nil
+	})
+	guess := func() error {
+		ctx := graphql.WithFieldContext(auth.WithAutomationClient(context.Background(), "leaked-key"), &graphql.FieldContext{
+			Object: "Mutation",
+			Field:  graphql.CollectedField{Field: &ast.Field{Name: "verifyAccount"}},
+			Args:   map[string]interface{}{"input": model.AccountVerification{Email: "victim@example.com", Code: "0000"}},
+		})
+		_, err := rateLimit.RateLimit(ctx, nil, next, verify.Operation, int32(verify.Limit), nil, nil, nil, nil, nil)
+		return err
+	}
+
+	for i := int64(0); i < verify.Limit; i++ {
+		if err := guess(); err != nil {
+			t.Fatalf("Expected guess %d within the limit to pass, got %v", i+1, err)
+		}
+	}
+	if err := guess(); err == nil {
+		t.Error("Expected an automation key to be limited on verifyAccount like anyone else")
+	}
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(auth.WithAutomationClient(c.UserContext(), "leaked-key"))
+		return c.Next()
+	})
+	app.Get("/resend", middleware.RateLimitMiddleware(limiter, ratelimit.Policy{Operation: model.RateLimitMethodsResendVerificationCode, Limit: 1, Window: time.Hour, Key: model.RateLimitKeyIP, Algorithm: model.RateLimitAlgorithmFixedWindow}), func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+	statuses := make([]int, 0, 2)
+	for i := 0; i < 2; i++ {
+		resp, err := app.Test(httptest.NewRequest("GET", "/resend", nil), -1)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+		statuses = append(statuses, resp.StatusCode)
+	}
+	if statuses[0] != fiber.StatusOK || statuses[1] != fiber.StatusTooManyRequests {
+		t.Errorf("Expected the middleware to limit automation outside login, got %v", statuses)
+	}
+}
+
+// TestClientQuota_OAuthSignInOnServerRoutes builds the routes in the order
+// the server mounts them, where the OAuth callbacks end their chain ahead of
+// the shared middleware.
+func TestClientQuota_OAuthSignInOnServerRoutes(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := &configs.Config{}
+	cfg.Automation.APIKeys = map[string]string{"sso-bridge": "bridge-secret"}
+	cfg.Automation.Quotas.Default = configs.ClientQuota{LoginsPerHour: 1}
+
+	cache := database.NewCacheService(embeddedRedis(t))
+	authService := service.NewAuthService(repository.NewUserRepository(client), cfg, cache, &mockMailService{})
+	limits := directives.NewRateLimitDirective(ratelimit.New(cache).WithClientQuotas(ratelimit.QuotasFromConfig(cfg)))
+	schema := graph.NewExecutableSchema(graph.Config{})
+	if err := limits.Compile(schema.Schema()); err != nil {
+		t.Fatalf("Failed to compile rate limits: %v", err)
+	}
+	gqlSrv := gqlhandler.New(schema)
+	app := server.SetupFiberApp(&database.Database{Client: client}, gqlSrv, gqlSrv, authService, service.NewOAuthService(authService), limits, cfg)
+
+	var status int
+	var body []byte
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest("GET", "/service/oauth/google/callback?code=c&state=s", nil)
+		req.Header.Set(automation.DefaultHeader, "bridge-secret")
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		status = resp.StatusCode
+		body, _ = io.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+	if status != fiber.StatusTooManyRequests || !strings.Contains(string(body), "quota exceeded") {
+		t.Errorf("Expected the second OAuth sign-in to spend the client's login quota, got %d %s", status, body)
+	}
+}
@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/internal/metrics"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+// signWithClock signs a token as an issuer whose clock is offset from ours.
+func signWithClock(t *testing.T, private *rsa.PrivateKey, issuedAt, expiresAt time.Time) string {
+	t.Helper()
+
+	claims := &jwt.Claims{
+		Type: jwt.TokenTypeAccess,
+		RegisteredClaims: gojwt.RegisteredClaims{
+			Subject:   "user-public-id",
+			Issuer:    jwt.DefaultIssuer,
+			IssuedAt:  gojwt.NewNumericDate(issuedAt),
+			ExpiresAt: gojwt.NewNumericDate(expiresAt),
+		},
+	}
+	token := gojwt.NewWithClaims(gojwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "skew"
+
+	signed, err := token.SignedString(private)
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return signed
+}
+
+func TestClockSkew_MetricsAndConsumerLeeway(t *testing.T) {
+	configureTokenBudget(t, jwt.DefaultOptions())
+	t.Cleanup(func() { jwt.SetKeySet(nil) })
+
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	key, err := jwt.ParseSigningKey("skew", "RS256", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(private)}))
+	if err != nil {
+		t.Fatalf("Failed to parse signing key: %v", err)
+	}
+	ks, _ := jwt.NewKeySet("skew", key)
+	jwt.SetKeySet(ks)
+
+	now := time.Now()
+	ahead := signWithClock(t, private, now.Add(90*time.Second), now.Add(10*time.Minute))
+	if _, err := jwt.ValidateToken(ahead); err != nil {
+		t.Fatalf("Expected a token from a fast issuer clock to validate, got %v", err)
+	}
+
+	// Expired 20s ago: inside the default 30s leeway, outside a strict
+	// consumer's.
+	lapsed := signWithClock(t, private, now.Add(-10*time.Minute), now.Add(-20*time.Second))
+	if _, err := jwt.ValidateToken(lapsed); err != nil {
+		t.Errorf("Expected the default leeway to accept the token, got %v", err)
+	}
+	strict := jwt.ContextWithLeeway(context.Background(), 0)
+	if _, err := jwt.ValidateTokenContext(strict, lapsed); err != errors.ExpiredToken {
+		t.Errorf("Expected a zero leeway consumer to reject the token as expired, got %v", err)
+	}
+
+	var out strings.Builder
+	if _, err := metrics.Default.WriteTo(&out); err != nil {
+		t.Fatalf("Failed to render metrics: %v", err)
+	}
+	for _, want := range []string{`jwt_future_iat_seconds_count{issuer="authentication-service"}`, `jwt_expired_by_seconds_count{issuer="authentication-service"}`} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %s in metrics output", want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/cookies"
+	handler "github.com/abisalde/authentication-service/internal/auth/handler/http"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+)
+
+func TestConnectedApps_RevokingAnAppLeavesTheUsersSessions(t *testing.T) {
+	t.Setenv("JWT_SECRET", "connected-apps-test-secret")
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(rdb), &mockMailService{})
+	profile := handler.NewProfileHandler(authService)
+	owner := createVerifiedUser(t, client, "connected_apps_owner@example.com")
+
+	issue := func(ctx context.Context, scopes []string) string {
+		t.Helper()
+		token, err := cookies.GenerateAccessToken(ctx, owner.PublicID.String(), nil, scopes)
+		if err != nil {
+			t.Fatalf("Failed to issue an access token: %v", err)
+		}
+		if err := authService.TrackSessionToken(ctx, owner.ID, token); err != nil {
+			t.Fatalf("Failed to track the access token: %v", err)
+		}
+		if err := authService.RecordAppGrant(ctx, owner.ID, scopes, token); err != nil {
+			t.Fatalf("Failed to record the grant: %v", err)
+		}
+		return token
+	}
+
+	reporting := auth.WithAutomationClient(ctx, "reporting-bot")
+	backup := auth.WithAutomationClient(ctx, "backup-tool")
+
+	own := issue(ctx, nil)
+	reportingToken := issue(reporting, []string{"profile:read"})
+	backupToken := issue(backup, []string{"profile:read", "profile:write"})
+	time.Sleep(10 * time.Millisecond)
+	refreshed := issue(reporting, []string{"profile:read"})
+
+	userCtx := auth.WithUser(ctx, owner, nil)
+	apps, err := profile.GetConnectedApps(userCtx)
+	if err != nil || len(apps) != 2 {
+		t.Fatalf("Expected the two API clients and not the user's own session, got %+v, %v", apps, err)
+	}
+	if apps[0].Client != "reporting-bot" || apps[1].Client != "backup-tool" {
+		t.Errorf("Expected the most recently used app first, got %s then %s", apps[0].Client, apps[1].Client)
+	}
+	if !apps[0].GrantedAt.Before(apps[0].LastUsedAt) {
+		t.Errorf("Expected a refresh to keep the grant time and move the last use, got %v and %v", apps[0].GrantedAt, apps[0].LastUsedAt)
+	}
+	if len(apps[1].Scopes) != 2 {
+		t.Errorf("Expected the granted scopes, got %v", apps[1].Scopes)
+	}
+
+	if _, err := profile.RevokeConnectedApp(auth.WithUser(backup, owner, nil), "reporting-bot"); err == nil {
+		t.Error("Expected a connected app not to be able to revoke another")
+	}
+	if _, err := profile.RevokeConnectedApp(userCtx, "unknown-client"); err == nil {
+		t.Error("Expected revoking an app that was never granted access to fail")
+	}
+
+	remaining, err := profile.RevokeConnectedApp(userCtx, "reporting-bot")
+	if err != nil || len(remaining) != 1 || remaining[0].Client != "backup-tool" {
+		t.Fatalf("Expected only the backup tool to remain, got %+v, %v", remaining, err)
+	}
+	for _, token := range []string{reportingToken, refreshed} {
+		if !authService.IsTokenBlacklisted(ctx, token) {
+			t.Error("Expected the revoked app's access tokens to be blacklisted")
+		}
+	}
+	for _, token := range []string{own, backupToken} {
+		if authService.IsTokenBlacklisted(ctx, token) {
+			t.Error("Expected the user's session and other apps to stay signed in")
+		}
+	}
+	if err := authService.CheckConnectedApp(reporting, owner.ID); err != service.ErrConnectedAppRevoked {
+		t.Errorf("Expected the revoked app's refreshes to be refused, got %v", err)
+	}
+	if err := authService.CheckConnectedApp(backup, owner.ID); err != nil {
+		t.Errorf("Expected the other app to keep refreshing, got %v", err)
+	}
+
+	entries, err := rdb.XRange(ctx, service.SessionEventStreamKey, "-", "+").Result()
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("Expected one session event, got %d (%v)", len(entries), err)
+	}
+	var event service.SessionEvent
+	if err := json.Unmarshal([]byte(entries[0].Values["event"].(string)), &event); err != nil {
+		t.Fatalf("Failed to decode the event: %v", err)
+	}
+	if event.Reason != model.RevocationReasonAppAccessRevoked || len(event.TokenIDs) != 2 {
+		t.Errorf("Expected the event to list the app's two tokens, got %+v", event)
+	}
+	if jti, _ := jwt.GetTokenID(own); rdb.ZScore(ctx, fmt.Sprintf("%s%d", service.SessionTokensPrefix, owner.ID), jti).Err() != nil {
+		t.Error("Expected the user's own token to stay tracked")
+	}
+
+	issue(reporting, []string{"profile:read"})
+	if err := authService.CheckConnectedApp(reporting, owner.ID); err != nil {
+		t.Errorf("Expected signing in through the app again to grant it access, got %v", err)
+	}
+}
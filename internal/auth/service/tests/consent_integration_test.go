@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/consent"
+	"github.com/abisalde/authentication-service/internal/auth/handler/http"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func consentConfig() *configs.Config {
+	cfg := &configs.Config{}
+	cfg.Consent.Default = configs.ConsentRequirements{MinimumAge: 13}
+	cfg.Consent.Countries = map[string]configs.ConsentRequirements{
+		"de": {MinimumAge: 16, TermsVersion: "2025-eu"},
+		"US": {MinimumAge: 13, MarketingOptInDefault: true},
+	}
+	return cfg
+}
+
+func TestConsentPolicy_CountryRequirements(t *testing.T) {
+	policy := consent.NewPolicy(consentConfig())
+
+	if reqs := policy.For("DE"); reqs.Country != "DE" || reqs.MinimumAge != 16 || reqs.TermsVersion != "2025-eu" {
+		t.Errorf("Expected German requirements from the lower-case config key, got %+v", reqs)
+	}
+	if reqs := policy.For("xx"); reqs.Country != "" || reqs.MinimumAge != 13 {
+		t.Errorf("Expected default requirements for an unknown country, got %+v", reqs)
+	}
+
+	now := time.Date(2025, time.June, 15, 12, 0, 0, 0, time.UTC)
+	sixteenTomorrow := time.Date(2009, time.June, 16, 0, 0, 0, 0, time.UTC)
+	sixteenToday := time.Date(2009, time.June, 15, 0, 0, 0, 0, time.UTC)
+
+	germany := policy.For("de")
+	cases := []struct {
+		name     string
+		consents consent.Consents
+		field    string
+	}{
+		{"missing birth date", consent.Consents{AcceptTerms: true, TermsVersion: "2025-eu"}, "dateOfBirth"},
+		{"one day too young", consent.Consents{DateOfBirth: &sixteenTomorrow, AcceptTerms: true, TermsVersion: "2025-eu"}, "dateOfBirth"},
+		{"terms not accepted", consent.Consents{DateOfBirth: &sixteenToday}, "acceptTerms"},
+		{"other terms variant", consent.Consents{DateOfBirth: &sixteenToday, AcceptTerms: true, TermsVersion: "2025-us"}, "termsVersion"},
+		{"all consents", consent.Consents{DateOfBirth: &sixteenToday, AcceptTerms: true, TermsVersion: "2025-eu"}, ""},
+	}
+
+	for _, tc := range cases {
+		err := germany.Check(tc.consents, now)
+		if tc.field == "" {
+			if err != nil {
+				t.Errorf("%s: expected no violation, got %v", tc.name, err)
+			}
+			continue
+		}
+		violation, ok := err.(*consent.Violation)
+		if !ok || violation.Field != tc.field {
+			t.Errorf("%s: expected violation on %s, got %v", tc.name, tc.field, err)
+		}
+	}
+
+	optOut := false
+	if !policy.For("US").MarketingOptIn(consent.Consents{}) {
+		t.Error("Expected US marketing opt-in to default to true")
+	}
+	if policy.For("US").MarketingOptIn(consent.Consents{MarketingOptIn: &optOut}) {
+		t.Error("Expected an explicit opt-out to override the country default")
+	}
+}
+
+func TestConsent_RegisterEnforcesCountryRequirements(t *testing.T) {
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	authService := service.NewAuthService(repository.NewUserRepository(client), consentConfig(), redisCache, &mockMailService{})
+	registerHandler := http.NewRegisterHandler(authService)
+
+	ctx := context.Background()
+	country := "DE"
+	dob := time.Now().AddDate(-15, 0, 0)
+	accept := true
+
+	_, err := registerHandler.Register(ctx, model.RegisterInput{
+		Email:       "consent_de@example.com",
+		Password:    "Str0ngPassw0rd!",
+		Country:     &country,
+		DateOfBirth: &dob,
+		AcceptTerms: &accept,
+	})
+
+	gqlErr, ok := err.(*gqlerror.Error)
+	if !ok || gqlErr.Extensions["field"] != "dateOfBirth" || gqlErr.Extensions["minimumAge"] != 16 {
+		t.Fatalf("Expected dateOfBirth violation with minimum age 16, got %v", err)
+	}
+
+	reqs, err := registerHandler.RegistrationRequirements(ctx, &country)
+	if err != nil {
+		t.Fatalf("Failed to load registration requirements: %v", err)
+	}
+	if reqs.Country == nil || *reqs.Country != "DE" || reqs.MinimumAge != 16 || reqs.TermsVersion == nil || *reqs.TermsVersion != "2025-eu" {
+		t.Errorf("Unexpected requirements for DE: %+v", reqs)
+	}
+
+	reqs, err = registerHandler.RegistrationRequirements(ctx, nil)
+	if err != nil {
+		t.Fatalf("Failed to load default requirements: %v", err)
+	}
+	if reqs.Country != nil || reqs.MinimumAge != 13 || reqs.TermsVersion != nil {
+		t.Errorf("Unexpected default requirements: %+v", reqs)
+	}
+}
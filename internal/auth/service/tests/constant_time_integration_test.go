@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/handler/oauth"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/pkg/verification"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestConstantTime_Equal(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want bool
+	}{
+		{"5b1f0c1e-state", "5b1f0c1e-state", true},
+		{"", "", true},
+		{"5b1f0c1e-state", "5b1f0c1e-statf", false},
+		{"5b1f0c1e-state", "6b1f0c1e-state", false},
+		// A prefix of the secret must not match, whatever its length.
+		{"5b1f0c1e-state", "5b1f0c1e", false},
+		{"5b1f0c1e-state", "", false},
+		{"", "5b1f0c1e-state", false},
+	}
+	for _, tc := range cases {
+		if got := verification.Equal(tc.a, tc.b); got != tc.want {
+			t.Errorf("Equal(%q, %q) = %v, expected %v", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestConstantTime_VerificationCodes(t *testing.T) {
+	stored, err := verification.NewStoredCode("4821")
+	if err != nil {
+		t.Fatalf("Failed to hash code: %v", err)
+	}
+
+	for code, want := range map[string]bool{
+		"4821":  true,
+		"4820":  false,
+		"482":   false,
+		"48211": false,
+		"":      false,
+	} {
+		if got := stored.Matches(code); got != want {
+			t.Errorf("Matches(%q) = %v, expected %v", code, got, want)
+		}
+	}
+	if (verification.StoredCode{}).Matches("") {
+		t.Error("Expected a missing code to match nothing")
+	}
+}
+
+func TestConstantTime_RefreshTokenHashes(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(embeddedRedis(t)), &mockMailService{})
+	u := createVerifiedUser(t, client, "constant_time@example.com")
+
+	hashed, err := authService.StoreRefreshToken(ctx, u, "refresh-token-value")
+	if err != nil {
+		t.Fatalf("Failed to store refresh token: %v", err)
+	}
+
+	for token, want := range map[string]bool{
+		hashed:                  true,
+		hashed[:len(hashed)-1]:  false,
+		hashed + "=":            false,
+		"refresh-token-value":   false,
+		strings.ToUpper(hashed): false,
+		"":                      false,
+	} {
+		if valid, _ := authService.ValidateRefreshToken(ctx, u.ID, token); valid != want {
+			t.Errorf("ValidateRefreshToken(%q) = %v, expected %v", token, valid, want)
+		}
+	}
+}
+
+func TestConstantTime_OAuthState(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(embeddedRedis(t)), &mockMailService{})
+	handler := oauth.NewOAuthHandler(service.NewOAuthService(authService))
+
+	app := fiber.New()
+	app.Get("/oauth/:provider/callback", func(c *fiber.Ctx) error {
+		c.Locals(auth.OAuthStateKey, "5b1f0c1e-state")
+		return c.Next()
+	}, handler.UnifiedOauthCallBack)
+
+	refused := func(state string) bool {
+		resp, err := app.Test(httptest.NewRequest("GET", "/oauth/google/callback?code=c&state="+state, nil), -1)
+		if err != nil {
+			t.Fatalf("Callback request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode == fiber.StatusBadRequest && strings.Contains(string(body), "Invalid Authentication")
+	}
+
+	for _, state := range []string{"", "5b1f0c1e-statf", "5b1f0c1e", "5b1f0c1e-state-and-more"} {
+		if !refused(state) {
+			t.Errorf("Expected state %q to be refused", state)
+		}
+	}
+	if refused("5b1f0c1e-state") {
+		t.Error("Expected the expected state to pass the state check")
+	}
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/handler/http"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/database/ent/user"
+	"github.com/abisalde/authentication-service/internal/graph/directives"
+	"github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const browserSession = "browser-session-binding"
+
+// operationRequest builds the context the auth middleware and gqlgen hand to
+// the directive for an operation of kind sent by u. A cookie session is
+// recorded only when cookie is set; otherwise the request carried a bearer
+// token.
+func operationRequest(u *ent.User, kind ast.Operation, cookie bool, csrfToken string) context.Context {
+	ctx := auth.WithUser(context.Background(), u, nil)
+	if cookie {
+		ctx = auth.WithCookieSession(ctx, browserSession, csrfToken)
+	}
+	return graphql.WithOperationContext(ctx, &graphql.OperationContext{
+		Operation: &ast.OperationDefinition{Operation: kind},
+	})
+}
+
+func TestCSRF_CookieMutations(t *testing.T) {
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, redisCache, &mockMailService{})
+	profile := http.NewProfileHandler(authService)
+	member := createVerifiedUser(t, client, "csrf@example.com")
+
+	// The browser fetches its token with the csrfToken query over the same
+	// cookie session.
+	issued, err := profile.GetCSRFToken(operationRequest(member, ast.Query, true, ""))
+	if err != nil {
+		t.Fatalf("Failed to issue a CSRF token: %v", err)
+	}
+	otherSession, err := profile.GetCSRFToken(auth.WithCookieSession(auth.WithUser(context.Background(), member, nil), "another-browser", ""))
+	if err != nil {
+		t.Fatalf("Failed to issue a CSRF token: %v", err)
+	}
+	promoted := *member
+	promoted.Role = user.RoleADMIN
+
+	next := graphql.Resolver(func(ctx context.Context) (interface{}, error) {
+		return true, nil
+	})
+	userRole := model.UserRoleUser
+
+	cases := []struct {
+		name string
+		ctx  context.Context
+		want error
+	}{
+		{"cookie mutation without a token", operationRequest(member, ast.Mutation, true, ""), errors.InvalidCSRFToken},
+		{"cookie mutation with a wrong token", operationRequest(member, ast.Mutation, true, "not-the-token"), errors.InvalidCSRFToken},
+		{"cookie mutation with the csrfToken query's token", operationRequest(member, ast.Mutation, true, issued), nil},
+		{"token minted for another session", operationRequest(member, ast.Mutation, true, otherSession), errors.InvalidCSRFToken},
+		{"token minted before a role change", operationRequest(&promoted, ast.Mutation, true, issued), errors.InvalidCSRFToken},
+		{"bearer mutation without a token", operationRequest(member, ast.Mutation, false, ""), nil},
+		{"cookie query without a token", operationRequest(member, ast.Query, true, ""), nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := directives.NewAuthDirective().Auth(guardedField(tc.ctx, "updateProfile"), nil, next, &userRole)
+			if err != tc.want {
+				t.Errorf("Expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
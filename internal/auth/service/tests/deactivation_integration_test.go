@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+)
+
+func TestDeactivation_DeactivateAndLift(t *testing.T) {
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, redisCache, &mockMailService{})
+
+	owner := createVerifiedUser(t, client, "deactivate_owner@example.com")
+
+	deactivated, err := authService.DeactivateAccount(ctx, owner)
+	if err != nil {
+		t.Fatalf("Failed to deactivate account: %v", err)
+	}
+	if !service.IsDeactivated(deactivated) {
+		t.Fatal("Expected deactivatedAt to be set")
+	}
+	if _, err := authService.DeactivateAccount(ctx, deactivated); err != service.ErrAlreadyDeactivated {
+		t.Errorf("Expected ErrAlreadyDeactivated, got %v", err)
+	}
+
+	restored, err := authService.LiftDeactivation(ctx, deactivated)
+	if err != nil {
+		t.Fatalf("Failed to lift deactivation: %v", err)
+	}
+	if service.IsDeactivated(restored) {
+		t.Errorf("Expected the account restored, got deactivatedAt %v", restored.DeactivatedAt)
+	}
+}
+
+func TestDeactivation_DeletedAccountsHiddenAndPurged(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := repository.NewUserRepository(client)
+
+	stale := createVerifiedUser(t, client, "purge_stale@example.com")
+	recent := createVerifiedUser(t, client, "purge_recent@example.com")
+
+	if _, err := repo.SoftDelete(ctx, stale.ID, time.Now().Add(-48*time.Hour)); err != nil {
+		t.Fatalf("Failed to soft delete: %v", err)
+	}
+	if _, err := repo.SoftDelete(ctx, recent.ID, time.Now()); err != nil {
+		t.Fatalf("Failed to soft delete: %v", err)
+	}
+
+	if _, err := repo.GetByEmail(ctx, stale.Email); err == nil {
+		t.Error("Expected a deleted account to be hidden from lookups")
+	}
+	if _, err := repo.GetByID(repository.IncludeDeleted(ctx), stale.ID); err != nil {
+		t.Errorf("Expected IncludeDeleted to find the deleted account, got %v", err)
+	}
+	if exists, _ := repo.ExistsByEmail(ctx, stale.Email); !exists {
+		t.Error("Expected a deleted account to keep its email reserved")
+	}
+
+	purged, err := repo.PurgeDeleted(ctx, time.Now().Add(-24*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("Failed to purge deleted accounts: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("Expected 1 purged account, got %d", purged)
+	}
+	if _, err := repo.GetByID(repository.IncludeDeleted(ctx), stale.ID); err == nil {
+		t.Error("Expected the stale account to be purged")
+	}
+	if _, err := repo.GetByID(repository.IncludeDeleted(ctx), recent.ID); err != nil {
+		t.Errorf("Expected the recent deletion to be kept, got %v", err)
+	}
+}
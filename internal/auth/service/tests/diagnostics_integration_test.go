@@ -0,0 +1,36 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/abisalde/authentication-service/internal/auth/handler/http"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+)
+
+func TestDiagnostics_KeyspaceSampleSizeBounds(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(embeddedRedis(t)), &mockMailService{})
+	handler := http.NewDiagnosticsHandler(authService)
+
+	for _, size := range []int{0, -1, 1001} {
+		if _, err := handler.GetRedisKeyspaceUsage(ctx, &size); err == nil {
+			t.Errorf("Expected sample size %d to be rejected", size)
+		}
+	}
+
+	if _, err := handler.GetRedisKeyspaceUsage(ctx, nil); err != nil {
+		t.Errorf("Expected an omitted sample size to use the default, got %v", err)
+	}
+	for _, size := range []int{1, 1000} {
+		if _, err := handler.GetRedisKeyspaceUsage(ctx, &size); err != nil {
+			t.Errorf("Expected sample size %d to be accepted, got %v", size, err)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/handler/http"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/pkg/password"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestEmailChange_RejectedBeforeAnyCodeIsSent(t *testing.T) {
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	mailer := &recordingMailService{}
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, redisCache, mailer)
+	profileHandler := http.NewProfileHandler(authService)
+
+	ctx := context.Background()
+	hash, err := password.HashPassword("Str0ngPassw0rd!")
+	if err != nil {
+		t.Fatalf("Failed to hash password: %v", err)
+	}
+	owner := client.User.Create().SetEmail("email_change_owner@example.com").SetPasswordHash(hash).SaveX(ctx)
+	createVerifiedUser(t, client, "email_change_taken@example.com")
+
+	userCtx := auth.WithUser(ctx, owner, nil)
+	wrong := "WrongPassw0rd!"
+	right := "Str0ngPassw0rd!"
+
+	cases := []struct {
+		name  string
+		input model.ChangeEmailInput
+		code  interface{}
+	}{
+		{"missing password", model.ChangeEmailInput{NewEmail: "email_change_new@example.com"}, model.ErrorTypeWeakPassword},
+		{"wrong password", model.ChangeEmailInput{NewEmail: "email_change_new@example.com", Password: &wrong}, model.ErrorTypeWeakPassword},
+		{"current email", model.ChangeEmailInput{NewEmail: "Email_Change_Owner@example.com", Password: &right}, model.ErrorTypeBadRequest},
+		{"taken email", model.ChangeEmailInput{NewEmail: "email_change_taken@example.com", Password: &right}, model.ErrorTypeEmailExists},
+	}
+
+	for _, tc := range cases {
+		_, err := profileHandler.ChangeEmail(userCtx, tc.input)
+		gqlErr, ok := err.(*gqlerror.Error)
+		if !ok || gqlErr.Extensions["code"] != tc.code {
+			t.Errorf("%s: expected %v error, got %v", tc.name, tc.code, err)
+		}
+	}
+
+	if len(mailer.sent) != 0 {
+		t.Errorf("Expected no verification code to be sent, got %d emails", len(mailer.sent))
+	}
+}
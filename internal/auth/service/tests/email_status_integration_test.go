@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+// fakeSiteVerify accepts only the "human" token, like a siteverify endpoint.
+func fakeSiteVerify(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		ok := r.PostForm.Get("secret") == "captcha-secret" && r.PostForm.Get("response") == "human"
+		_ = json.NewEncoder(w).Encode(map[string]bool{"success": ok})
+	}))
+}
+
+func TestEmailStatus_Registered(t *testing.T) {
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, redisCache, &mockMailService{})
+	createVerifiedUser(t, client, "taken@example.com")
+
+	status, err := authService.EmailStatus(context.Background(), "taken@example.com", "", "")
+	if err != nil || status != model.EmailRegistrationStatusRegistered {
+		t.Fatalf("Expected REGISTERED, got %s (%v)", status, err)
+	}
+}
+
+func TestEmailStatus_NeutralWithCaptcha(t *testing.T) {
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	srv := fakeSiteVerify(t)
+	defer srv.Close()
+
+	cfg := &configs.Config{}
+	cfg.EmailStatus.Neutral = true
+	cfg.EmailStatus.RequireCaptcha = true
+	cfg.Captcha.VerifyURL = srv.URL
+	cfg.Captcha.Secret = "captcha-secret"
+
+	authService := service.NewAuthService(repository.NewUserRepository(client), cfg, redisCache, &mockMailService{})
+	createVerifiedUser(t, client, "hidden@example.com")
+
+	ctx := context.Background()
+	if _, err := authService.EmailStatus(ctx, "hidden@example.com", "", "203.0.113.7"); err != service.ErrCaptchaRequired {
+		t.Errorf("Expected ErrCaptchaRequired without a token, got %v", err)
+	}
+	if _, err := authService.EmailStatus(ctx, "hidden@example.com", "bot", "203.0.113.7"); err != service.ErrCaptchaRequired {
+		t.Errorf("Expected ErrCaptchaRequired for a rejected token, got %v", err)
+	}
+
+	for _, email := range []string{"hidden@example.com", "nobody@example.com"} {
+		status, err := authService.EmailStatus(ctx, email, "human", "203.0.113.7")
+		if err != nil || status != model.EmailRegistrationStatusUnknown {
+			t.Errorf("Expected UNKNOWN for %s, got %s (%v)", email, status, err)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/abisalde/authentication-service/pkg/scheduler"
+	"github.com/redis/go-redis/v9"
+)
+
+func embeddedRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	cfg := &configs.Config{}
+	cfg.Embedded.Enabled = true
+
+	cache, err := database.InitRedis(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Failed to start the embedded store: %v", err)
+	}
+	t.Cleanup(func() { _ = cache.Close() })
+	return cache.RawClient()
+}
+
+func TestEmbeddedMode_SessionStoreCommands(t *testing.T) {
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+
+	if err := rdb.Set(ctx, "refresh:abc", "user-1", time.Minute).Err(); err != nil {
+		t.Fatalf("SET failed: %v", err)
+	}
+	if got, err := rdb.Get(ctx, "refresh:abc").Result(); err != nil || got != "user-1" {
+		t.Errorf("Expected GET to return user-1, got %q (%v)", got, err)
+	}
+	if ttl := rdb.TTL(ctx, "refresh:abc").Val(); ttl <= 0 || ttl > time.Minute {
+		t.Errorf("Expected a TTL of at most a minute, got %v", ttl)
+	}
+	if err := rdb.Get(ctx, "refresh:missing").Err(); !errors.Is(err, redis.Nil) {
+		t.Errorf("Expected redis.Nil for a missing key, got %v", err)
+	}
+
+	if err := rdb.Set(ctx, "short", "1", 20*time.Millisecond).Err(); err != nil {
+		t.Fatalf("SET failed: %v", err)
+	}
+	// The store's clock moves in ticks, so give it a few.
+	time.Sleep(300 * time.Millisecond)
+	if n := rdb.Exists(ctx, "short").Val(); n != 0 {
+		t.Error("Expected the key to expire")
+	}
+
+	if err := rdb.ZAdd(ctx, "sessions:user-1",
+		redis.Z{Score: 1, Member: "a"}, redis.Z{Score: 2, Member: "b"}, redis.Z{Score: 3, Member: "c"}).Err(); err != nil {
+		t.Fatalf("ZADD failed: %v", err)
+	}
+	if err := rdb.ZRemRangeByScore(ctx, "sessions:user-1", "-inf", "1").Err(); err != nil {
+		t.Fatalf("ZREMRANGEBYSCORE failed: %v", err)
+	}
+	if members := rdb.ZRangeByScore(ctx, "sessions:user-1", &redis.ZRangeBy{Min: "-inf", Max: "+inf"}).Val(); len(members) != 2 || members[0] != "b" {
+		t.Errorf("Expected members [b c], got %v", members)
+	}
+
+	if err := rdb.HSet(ctx, "device:1", "ua", "firefox", "ip", "10.0.0.1").Err(); err != nil {
+		t.Fatalf("HSET failed: %v", err)
+	}
+	if fields := rdb.HGetAll(ctx, "device:1").Val(); fields["ua"] != "firefox" || fields["ip"] != "10.0.0.1" {
+		t.Errorf("Unexpected hash fields: %v", fields)
+	}
+
+	if err := rdb.LPush(ctx, "history", "3", "2", "1").Err(); err != nil {
+		t.Fatalf("LPUSH failed: %v", err)
+	}
+	rdb.LTrim(ctx, "history", 0, 1)
+	if items := rdb.LRange(ctx, "history", 0, -1).Val(); len(items) != 2 || items[0] != "1" {
+		t.Errorf("Expected the trimmed list [1 2], got %v", items)
+	}
+
+	if err := rdb.Set(ctx, "typed", "x", time.Minute).Err(); err != nil {
+		t.Fatalf("SET failed: %v", err)
+	}
+	if err := rdb.HGet(ctx, "typed", "field").Err(); err == nil || errors.Is(err, redis.Nil) {
+		t.Errorf("Expected WRONGTYPE for a hash command on a string, got %v", err)
+	}
+}
+
+func TestEmbeddedMode_RefusesUnauthenticatedClients(t *testing.T) {
+	rdb := embeddedRedis(t)
+	if !strings.HasPrefix(rdb.Options().Addr, "127.0.0.1:") {
+		t.Errorf("Expected the store to listen on loopback only, got %s", rdb.Options().Addr)
+	}
+
+	stranger := redis.NewClient(&redis.Options{Addr: rdb.Options().Addr})
+	defer stranger.Close()
+	if err := stranger.Ping(context.Background()).Err(); err == nil {
+		t.Error("Expected a client without the generated password to be refused")
+	}
+}
+
+func TestEmbeddedMode_TransactionsAndWatch(t *testing.T) {
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+
+	cmds, err := rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Incr(ctx, "counter")
+		pipe.Incr(ctx, "counter")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("MULTI/EXEC failed: %v", err)
+	}
+	if n := cmds[1].(*redis.IntCmd).Val(); n != 2 {
+		t.Errorf("Expected the second INCR to return 2, got %d", n)
+	}
+
+	err = rdb.Watch(ctx, func(tx *redis.Tx) error {
+		// Another client changes the watched key before EXEC.
+		if err := rdb.Set(ctx, "counter", "10", 0).Err(); err != nil {
+			return err
+		}
+		_, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Incr(ctx, "counter")
+			return nil
+		})
+		return err
+	}, "counter")
+	if !errors.Is(err, redis.TxFailedErr) {
+		t.Errorf("Expected the transaction to abort after a concurrent write, got %v", err)
+	}
+	if got := rdb.Get(ctx, "counter").Val(); got != "10" {
+		t.Errorf("Expected the concurrent write to stand, got %q", got)
+	}
+}
+
+func TestEmbeddedMode_LuaScripts(t *testing.T) {
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+
+	locker := scheduler.NewRedisLocker(rdb)
+	release, acquired, err := locker.TryLock(ctx, "janitor", time.Minute)
+	if err != nil || !acquired {
+		t.Fatalf("Expected to acquire the lock, got %v (%v)", acquired, err)
+	}
+	if _, again, _ := locker.TryLock(ctx, "janitor", time.Minute); again {
+		t.Error("Expected the held lock to be refused")
+	}
+	release()
+	if _, again, err := locker.TryLock(ctx, "janitor", time.Minute); err != nil || !again {
+		t.Errorf("Expected the released lock to be acquired again, got %v (%v)", again, err)
+	}
+
+	store := jwt.NewRedisScopeStore(rdb)
+	if err := store.Put(ctx, "ref-1", []string{"profile:read", "sessions:write"}, time.Minute); err != nil {
+		t.Fatalf("Failed to store scopes: %v", err)
+	}
+	scopes, err := store.Get(ctx, "ref-1")
+	if err != nil || len(scopes) != 2 {
+		t.Errorf("Expected two scopes back, got %v (%v)", scopes, err)
+	}
+
+	if n, err := rdb.Eval(ctx, "return redis.call('INCR', KEYS[1])", []string{"ad-hoc"}).Int(); err != nil || n != 1 {
+		t.Errorf("Expected any script to run, got %d (%v)", n, err)
+	}
+}
+
+func TestEmbeddedMode_StreamsBlockingRead(t *testing.T) {
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+
+	result := make(chan []redis.XStream, 1)
+	go func() {
+		streams, _ := rdb.XRead(ctx, &redis.XReadArgs{Streams: []string{"events", "$"}, Block: 2 * time.Second}).Result()
+		result <- streams
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if err := rdb.XAdd(ctx, &redis.XAddArgs{Stream: "events", Values: map[string]any{"type": "login"}}).Err(); err != nil {
+		t.Fatalf("XADD failed: %v", err)
+	}
+
+	select {
+	case streams := <-result:
+		if len(streams) != 1 || len(streams[0].Messages) != 1 || streams[0].Messages[0].Values["type"] != "login" {
+			t.Errorf("Expected the blocked read to return the new entry, got %v", streams)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Expected the blocked read to wake up on XADD")
+	}
+}
+
+func TestEmbeddedMode_LongPipeline(t *testing.T) {
+	rdb := embeddedRedis(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// Far more replies than fit a socket buffer, all sent before the client
+	// reads any of them.
+	pipe := rdb.Pipeline()
+	for i := range 5000 {
+		pipe.Set(ctx, fmt.Sprintf("pipelined:%d", i), i, time.Minute)
+	}
+	cmds, err := pipe.Exec(ctx)
+	if err != nil {
+		t.Fatalf("Expected a long pipeline to complete, got %v", err)
+	}
+	if len(cmds) != 5000 {
+		t.Errorf("Expected 5000 replies, got %d", len(cmds))
+	}
+	if v := rdb.Get(ctx, "pipelined:4999").Val(); v != "4999" {
+		t.Errorf("Expected the last pipelined write to land, got %q", v)
+	}
+}
+
+func TestEmbeddedMode_SQLiteDatabase(t *testing.T) {
+	cfg := &configs.Config{}
+	cfg.Embedded.Enabled = true
+	cfg.Embedded.SQLitePath = filepath.Join(t.TempDir(), "nested", "auth.db")
+
+	db, err := database.Connect(cfg)
+	if err != nil {
+		t.Fatalf("Failed to open the embedded database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if err := db.HealthCheck(ctx); err != nil {
+		t.Errorf("Expected the embedded database to be healthy, got %v", err)
+	}
+	if _, err := db.Client.User.Query().Count(ctx); err != nil {
+		t.Errorf("Expected the schema to be migrated on start, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/events"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+func TestEvents_SchemaVersionsStayBackwardCompatible(t *testing.T) {
+	for _, eventType := range events.Default.Types() {
+		if err := events.Default.CheckCompatibility(eventType); err != nil {
+			t.Errorf("Expected %s to stay backward compatible: %v", eventType, err)
+		}
+	}
+}
+
+func TestEvents_EmittedPayloadsMatchTheirSchema(t *testing.T) {
+	now := time.Now()
+	emitted := map[string]interface{}{
+		events.UserLastLogin: service.LoginEvent{
+			Metadata:  events.Stamp(events.UserLastLogin),
+			UserID:    1,
+			Timestamp: now,
+			EventType: events.UserLastLogin,
+		},
+		events.TokenRevoked: service.RevocationEvent{
+			Metadata:  events.Stamp(events.TokenRevoked),
+			UserID:    1,
+			Reason:    model.RevocationReasonUserLogout,
+			Timestamp: now,
+			EventType: events.TokenRevoked,
+		},
+		events.RefreshTokenExpiring: service.RefreshReminderEvent{
+			Metadata:     events.Stamp(events.RefreshTokenExpiring),
+			UserID:       1,
+			UserPublicID: "00000000-0000-0000-0000-000000000001",
+			ExpiresAt:    now.Add(time.Hour),
+			Timestamp:    now,
+			EventType:    events.RefreshTokenExpiring,
+		},
+		events.EmailChanged: service.EmailChangeEvent{
+			Metadata:  events.Stamp(events.EmailChanged),
+			UserID:    1,
+			OldEmail:  "old@example.com",
+			NewEmail:  "new@example.com",
+			Timestamp: now,
+			EventType: events.EmailChanged,
+		},
+		events.AccountAction: service.AccountActionEvent{
+			Metadata:  events.Stamp(events.AccountAction),
+			ActionID:  1,
+			UserID:    1,
+			Action:    "DEACTIVATE",
+			Status:    "COMPLETED",
+			RunAt:     now,
+			Timestamp: now,
+			EventType: events.AccountAction,
+		},
+		events.SessionCreated: service.SessionEvent{
+			Metadata:     events.Stamp(events.SessionCreated),
+			UserID:       1,
+			UserPublicID: "00000000-0000-0000-0000-000000000001",
+			Timestamp:    now,
+			EventType:    events.SessionCreated,
+		},
+		events.SessionRevoked: service.SessionEvent{
+			Metadata:     events.Stamp(events.SessionRevoked),
+			UserID:       1,
+			UserPublicID: "00000000-0000-0000-0000-000000000001",
+			TokenIDs:     []string{"00000000-0000-0000-0000-000000000002"},
+			Timestamp:    now,
+			EventType:    events.SessionRevoked,
+		},
+		events.UserSessionsRevoked: service.SessionEvent{
+			Metadata:     events.Stamp(events.UserSessionsRevoked),
+			UserID:       1,
+			UserPublicID: "00000000-0000-0000-0000-000000000001",
+			Reason:       model.RevocationReasonUserLogout,
+			TokenIDs:     []string{"00000000-0000-0000-0000-000000000002"},
+			Timestamp:    now,
+			EventType:    events.UserSessionsRevoked,
+		},
+		events.SecurityEvent: service.SecurityEvent{
+			Metadata:  events.Stamp(events.SecurityEvent),
+			Action:    service.SecurityLoginFailed,
+			Email:     "user@example.com",
+			IP:        "203.0.113.7",
+			Reason:    "INVALID_PASSWORD",
+			RiskScore: 40,
+			Timestamp: now,
+			EventType: events.SecurityEvent,
+		},
+	}
+
+	for _, eventType := range events.Default.Types() {
+		if _, ok := emitted[eventType]; !ok {
+			t.Errorf("Expected a sample payload for registered event %s", eventType)
+		}
+	}
+
+	for eventType, event := range emitted {
+		if events.Default.Latest(eventType) == 0 {
+			t.Errorf("Expected %s to have a registered schema", eventType)
+			continue
+		}
+		payload, err := json.Marshal(event)
+		if err != nil {
+			t.Fatalf("Failed to marshal %s: %v", eventType, err)
+		}
+		if err := events.Default.Validate(eventType, payload); err != nil {
+			t.Errorf("Expected %s payload to match its schema: %v", eventType, err)
+		}
+	}
+}
+
+func TestEvents_DetectsBreakingChanges(t *testing.T) {
+	v1 := `{"type":"object","properties":{"user_id":{"type":"integer"},"reason":{"type":"string"}},"required":["user_id","reason"]}`
+	cases := map[string]string{
+		"removed field":  `{"type":"object","properties":{"user_id":{"type":"integer"}},"required":["user_id"]}`,
+		"retyped field":  `{"type":"object","properties":{"user_id":{"type":"string"},"reason":{"type":"string"}},"required":["user_id","reason"]}`,
+		"optional field": `{"type":"object","properties":{"user_id":{"type":"integer"},"reason":{"type":"string"}},"required":["user_id"]}`,
+	}
+
+	for name, v2 := range cases {
+		registry, err := events.Load(fstest.MapFS{
+			"schemas/sample.v1.json": {Data: []byte(v1)},
+			"schemas/sample.v2.json": {Data: []byte(v2)},
+		})
+		if err != nil {
+			t.Fatalf("Failed to load schemas: %v", err)
+		}
+		if err := registry.CheckCompatibility("sample"); !errors.Is(err, events.ErrIncompatible) {
+			t.Errorf("%s: expected ErrIncompatible, got %v", name, err)
+		}
+	}
+
+	additive, err := events.Load(fstest.MapFS{
+		"schemas/sample.v1.json": {Data: []byte(v1)},
+		"schemas/sample.v2.json": {Data: []byte(`{"type":"object","properties":{"user_id":{"type":"integer"},"reason":{"type":"string"},"ip":{"type":"string"}},"required":["user_id","reason"]}`)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to load schemas: %v", err)
+	}
+	if err := additive.CheckCompatibility("sample"); err != nil {
+		t.Errorf("Expected an added optional field to stay compatible, got %v", err)
+	}
+}
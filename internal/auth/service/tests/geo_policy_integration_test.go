@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abisalde/authentication-service/internal/auth/geopolicy"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/gofiber/fiber/v2"
+)
+
+func geoConfig() *configs.Config {
+	cfg := &configs.Config{}
+	cfg.GeoPolicy.CountryHeader = "CF-IPCountry"
+	cfg.GeoPolicy.Regions = map[string][]string{"embargoed": {"KP", "sy"}}
+	cfg.GeoPolicy.Block = []string{"embargoed", "RU"}
+	cfg.GeoPolicy.StepUp = []string{"BR", "RU"}
+	cfg.GeoPolicy.Organizations = map[string]configs.GeoRules{
+		"acme": {Block: []string{"embargoed"}, StepUp: []string{"FR"}},
+	}
+	return cfg
+}
+
+func TestGeoPolicy_Decide(t *testing.T) {
+	policy := geopolicy.NewPolicy(geoConfig())
+	for _, tc := range []struct {
+		country, organization string
+		want                  geopolicy.Decision
+	}{
+		{"KP", "", geopolicy.DecisionBlock},
+		{"SY", "", geopolicy.DecisionBlock},
+		{" sy ", "", geopolicy.DecisionBlock},
+		// Block wins over a step-up of the same country.
+		{"RU", "", geopolicy.DecisionBlock},
+		{"BR", "", geopolicy.DecisionStepUp},
+		{"FR", "", geopolicy.DecisionAllow},
+		{"", "", geopolicy.DecisionAllow},
+		// An organization's rules replace the deployment's.
+		{"RU", "acme", geopolicy.DecisionAllow},
+		{"BR", "acme", geopolicy.DecisionAllow},
+		{"FR", "acme", geopolicy.DecisionStepUp},
+		{"KP", "acme", geopolicy.DecisionBlock},
+		{"BR", "globex", geopolicy.DecisionStepUp},
+	} {
+		if got := policy.Decide(tc.country, tc.organization); got != tc.want {
+			t.Errorf("Expected %q in %q to %s, got %s", tc.country, tc.organization, tc.want, got)
+		}
+	}
+
+	if got := geopolicy.NewPolicy(&configs.Config{}).Decide("KP", ""); got != geopolicy.DecisionAllow {
+		t.Errorf("Expected no rules to allow everything, got %s", got)
+	}
+}
+
+func TestGeoPolicy_LoginChecks(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	repo := repository.NewUserRepository(client)
+	authService := service.NewAuthService(repo, geoConfig(), database.NewCacheService(rdb), &mockMailService{})
+
+	member := createVerifiedUser(t, client, "geo_member@example.com")
+	outsider := createVerifiedUser(t, client, "geo_outsider@example.com")
+	org, err := repo.SaveOrganization(ctx, repository.OrganizationBranding{Slug: "acme", Name: "Acme"})
+	if err != nil {
+		t.Fatalf("Failed to create organization: %v", err)
+	}
+	if member, err = repo.SetUserOrganization(ctx, member.ID, &org.ID); err != nil {
+		t.Fatalf("Failed to join organization: %v", err)
+	}
+
+	app := fiber.New()
+	app.Get("/:email", func(c *fiber.Ctx) error {
+		u := outsider
+		if c.Params("email") == member.Email {
+			u = member
+		}
+		switch err := authService.CheckGeoPolicy(c.Context(), c, u, nil); err {
+		case nil:
+			return c.SendString("ok")
+		case service.ErrCaptchaRequired:
+			return c.SendStatus(fiber.StatusUnauthorized)
+		case service.ErrGeoBlocked:
+			return c.SendStatus(fiber.StatusForbidden)
+		default:
+			return err
+		}
+	})
+
+	for _, tc := range []struct {
+		email, country string
+		want           int
+	}{
+		{outsider.Email, "RU", fiber.StatusForbidden},
+		{outsider.Email, "BR", fiber.StatusUnauthorized},
+		{outsider.Email, "FR", fiber.StatusOK},
+		{outsider.Email, "", fiber.StatusOK},
+		{member.Email, "RU", fiber.StatusOK},
+		{member.Email, "FR", fiber.StatusUnauthorized},
+		{member.Email, "KP", fiber.StatusForbidden},
+	} {
+		req := httptest.NewRequest("GET", "/"+tc.email, nil)
+		if tc.country != "" {
+			req.Header.Set("CF-IPCountry", tc.country)
+		}
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.want {
+			t.Errorf("Expected %d for %s from %q, got %d", tc.want, tc.email, tc.country, resp.StatusCode)
+		}
+	}
+
+	// Only the two blocked sign-ins are audited.
+	entries, err := rdb.XRange(ctx, service.SecurityEventStreamKey, "-", "+").Result()
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("Expected two security events, got %d (%v)", len(entries), err)
+	}
+	for i, want := range []int64{outsider.ID, member.ID} {
+		var event service.SecurityEvent
+		if err := json.Unmarshal([]byte(entries[i].Values["event"].(string)), &event); err != nil {
+			t.Fatalf("Failed to decode security event: %v", err)
+		}
+		if event.Action != service.SecurityDenyListHit || event.Reason != service.DenyListGeoPolicy || event.UserID != want || event.IP == "" {
+			t.Errorf("Expected a geo_policy deny-list hit for user %d, got %+v", want, event)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+// app.Test connects from 0.0.0.0, so trusting that address stands in for
+// the proxy and trusting only the Docker network makes the peer a client.
+const (
+	testPeerProxy   = "0.0.0.0"
+	dockerNetwork   = "172.18.0.0/16"
+	forwardedSecure = "https"
+)
+
+func httpsApp(cfg *configs.Config, trustedProxy string) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ProxyHeader:             fiber.HeaderXForwardedFor,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          []string{trustedProxy},
+	})
+	app.Use(middleware.HTTPSMiddleware(cfg))
+	app.Get("/me", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	return app
+}
+
+func httpsConfig(mode string) *configs.Config {
+	cfg := &configs.Config{}
+	cfg.HTTPS.Enforce = true
+	cfg.HTTPS.Mode = mode
+	cfg.HTTPS.HSTS.Enabled = true
+	cfg.HTTPS.HSTS.MaxAge = 365 * 24 * time.Hour
+	cfg.HTTPS.HSTS.IncludeSubdomains = true
+	return cfg
+}
+
+func TestHTTPS_ForwardedProto(t *testing.T) {
+	cases := []struct {
+		name     string
+		mode     string
+		proxy    string
+		proto    string
+		status   int
+		location string
+		hsts     string
+		// absoluteForm sends the full URL on the request line, which
+		// HTTP/1.1 lets a proxy do.
+		absoluteForm bool
+	}{
+		{
+			name:     "trusted proxy forwarding plain HTTP is redirected",
+			mode:     middleware.HTTPSModeRedirect,
+			proxy:    testPeerProxy,
+			proto:    "http",
+			status:   fiber.StatusPermanentRedirect,
+			location: "https://auth.example.com/me?tab=security",
+		},
+		{
+			name:   "trusted proxy forwarding plain HTTP is rejected",
+			mode:   middleware.HTTPSModeReject,
+			proxy:  testPeerProxy,
+			proto:  "http",
+			status: fiber.StatusUpgradeRequired,
+		},
+		{
+			name:   "trusted proxy forwarding HTTPS gets HSTS",
+			mode:   middleware.HTTPSModeReject,
+			proxy:  testPeerProxy,
+			proto:  forwardedSecure,
+			status: fiber.StatusNoContent,
+			hsts:   "max-age=31536000; includeSubDomains",
+		},
+		{
+			name:   "untrusted peer spoofing HTTPS is rejected",
+			mode:   middleware.HTTPSModeReject,
+			proxy:  dockerNetwork,
+			proto:  forwardedSecure,
+			status: fiber.StatusUpgradeRequired,
+		},
+		{
+			name:         "untrusted peer spoofing HTTPS is redirected",
+			mode:         middleware.HTTPSModeRedirect,
+			proxy:        dockerNetwork,
+			proto:        forwardedSecure,
+			status:       fiber.StatusPermanentRedirect,
+			location:     "https://auth.example.com/me?tab=security",
+			absoluteForm: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/me?tab=security", nil)
+			req.Host = "auth.example.com"
+			if tc.absoluteForm {
+				req = httptest.NewRequest("GET", "http://auth.example.com/me?tab=security", nil)
+			}
+			req.Header.Set(fiber.HeaderXForwardedProto, tc.proto)
+
+			resp, err := httpsApp(httpsConfig(tc.mode), tc.proxy).Test(req, -1)
+			if err != nil {
+				t.Fatalf("Failed to send the request: %v", err)
+			}
+			if resp.StatusCode != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, resp.StatusCode)
+			}
+			if got := resp.Header.Get(fiber.HeaderLocation); got != tc.location {
+				t.Errorf("Expected Location %q, got %q", tc.location, got)
+			}
+			if got := resp.Header.Get(fiber.HeaderStrictTransportSecurity); got != tc.hsts {
+				t.Errorf("Expected Strict-Transport-Security %q, got %q", tc.hsts, got)
+			}
+		})
+	}
+}
+
+func TestHTTPS_NotEnforced(t *testing.T) {
+	cfg := httpsConfig(middleware.HTTPSModeReject)
+	cfg.HTTPS.Enforce = false
+	cfg.HTTPS.HSTS.MaxAge = 0
+	cfg.HTTPS.HSTS.IncludeSubdomains = false
+
+	for proto, hsts := range map[string]string{"http": "", forwardedSecure: "max-age=15552000"} {
+		req := httptest.NewRequest("GET", "/me", nil)
+		req.Header.Set(fiber.HeaderXForwardedProto, proto)
+
+		resp, err := httpsApp(cfg, testPeerProxy).Test(req, -1)
+		if err != nil {
+			t.Fatalf("Failed to send the request: %v", err)
+		}
+		if resp.StatusCode != fiber.StatusNoContent {
+			t.Errorf("%s: expected the request through, got %d", proto, resp.StatusCode)
+		}
+		if got := resp.Header.Get(fiber.HeaderStrictTransportSecurity); got != hsts {
+			t.Errorf("%s: expected Strict-Transport-Security %q, got %q", proto, hsts, got)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/pkg/idgen"
+)
+
+func TestUserRepository_SnowflakeIDs(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ids, err := idgen.New(idgen.StrategySnowflake, 7)
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	userRepo := repository.NewUserRepositoryWithIDs(client, ids)
+
+	ctx := context.Background()
+	var previous int64
+	for _, email := range []string{"snowflake_a@example.com", "snowflake_b@example.com"} {
+		user, err := userRepo.CreateNewUser(ctx, &model.RegisterVerifiedUser{Email: email, IsEmailVerified: true})
+		if err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+		if user.ID <= previous {
+			t.Errorf("Expected increasing generated IDs, got %d after %d", user.ID, previous)
+		}
+		if node := (user.ID >> 12) & idgen.MaxNodeID; node != 7 {
+			t.Errorf("Expected node 7 encoded in ID %d, got %d", user.ID, node)
+		}
+		previous = user.ID
+	}
+}
+
+// scriptedIDs hands out the given IDs in order, drawn at random as far as
+// the repository knows when random is set.
+type scriptedIDs struct {
+	ids    []int64
+	random bool
+}
+
+func (s *scriptedIDs) NextID() (int64, bool, error) {
+	id := s.ids[0]
+	s.ids = s.ids[1:]
+	return id, true, nil
+}
+
+func (s *scriptedIDs) MayCollide() bool {
+	return s.random
+}
+
+func TestUserRepository_RetriesCollidingRandomIDs(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	create := func(ids idgen.Generator, email string) (*ent.User, error) {
+		return repository.NewUserRepositoryWithIDs(client, ids).
+			CreateNewUser(ctx, &model.RegisterVerifiedUser{Email: email, IsEmailVerified: true})
+	}
+
+	if _, err := create(&scriptedIDs{ids: []int64{4242}}, "collision_first@example.com"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	user, err := create(&scriptedIDs{ids: []int64{4242, 4243}, random: true}, "collision_second@example.com")
+	if err != nil {
+		t.Fatalf("Expected the colliding random ID to be drawn again, got %v", err)
+	}
+	if user.ID != 4243 {
+		t.Errorf("Expected the second ID to be used, got %d", user.ID)
+	}
+
+	if _, err := create(&scriptedIDs{ids: []int64{4242, 4244}}, "collision_third@example.com"); !ent.IsConstraintError(err) {
+		t.Errorf("Expected a generator that cannot collide not to be retried, got %v", err)
+	}
+	duplicate := &scriptedIDs{ids: []int64{4244, 4245}, random: true}
+	if _, err := create(duplicate, "collision_second@example.com"); !ent.IsConstraintError(err) || len(duplicate.ids) != 1 {
+		t.Errorf("Expected a duplicate email not to be retried, got %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/reputation"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/database/ent/loginattempt"
+	"github.com/abisalde/authentication-service/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeReputationFeed struct {
+	scores  map[string]int
+	err     error
+	lookups int
+}
+
+func (f *fakeReputationFeed) Lookup(ctx context.Context, ip string) (reputation.Report, error) {
+	f.lookups++
+	if f.err != nil {
+		return reputation.Report{}, f.err
+	}
+	return reputation.Report{Score: f.scores[ip], Source: "fake"}, nil
+}
+
+func reputationConfig(stepUp, block int) *configs.Config {
+	cfg := &configs.Config{}
+	cfg.IPReputation.StepUpScore = stepUp
+	cfg.IPReputation.BlockScore = block
+	return cfg
+}
+
+func TestIPReputation_Policy(t *testing.T) {
+	policy := reputation.NewPolicy(reputationConfig(50, 90))
+	for score, want := range map[int]reputation.Decision{
+		0:   reputation.DecisionAllow,
+		49:  reputation.DecisionAllow,
+		50:  reputation.DecisionStepUp,
+		89:  reputation.DecisionStepUp,
+		90:  reputation.DecisionBlock,
+		100: reputation.DecisionBlock,
+	} {
+		if got := policy.Decide(score); got != want {
+			t.Errorf("Expected score %d to %s, got %s", score, want, got)
+		}
+	}
+
+	if got := reputation.NewPolicy(reputationConfig(0, 90)).Decide(80); got != reputation.DecisionAllow {
+		t.Errorf("Expected a zero step-up threshold to be off, got %s", got)
+	}
+	if reputation.Routable("10.0.0.1") || reputation.Routable("127.0.0.1") || reputation.Routable("not-an-ip") || !reputation.Routable("203.0.113.7") {
+		t.Error("Expected only public addresses to be looked up")
+	}
+}
+
+func TestIPReputation_AbuseIPDBFeed(t *testing.T) {
+	feed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Key") != "abuse-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("ipAddress") {
+		case "203.0.113.7":
+			_, _ = w.Write([]byte(`{"data":{"ipAddress":"203.0.113.7","abuseConfidenceScore":87}}`))
+		default:
+			_, _ = w.Write([]byte(`{"data":{}}`))
+		}
+	}))
+	defer feed.Close()
+
+	abuse := reputation.AbuseIPDB("abuse-key")
+	abuse.URL = feed.URL + "/check?ipAddress={ip}"
+
+	ctx := context.Background()
+	report, err := abuse.Lookup(ctx, "203.0.113.7")
+	if err != nil || report.Score != 87 || report.Source != "abuseipdb" {
+		t.Fatalf("Expected the abuse confidence score, got %+v, %v", report, err)
+	}
+	if _, err := abuse.Lookup(ctx, "198.51.100.1"); err == nil {
+		t.Error("Expected a response without a score to fail")
+	}
+
+	abuse.Key = "wrong"
+	if _, err := abuse.Lookup(ctx, "203.0.113.7"); err == nil {
+		t.Error("Expected a rejected key to fail")
+	}
+}
+
+func TestIPReputation_Assessment(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	redisCache := database.NewCacheService(embeddedRedis(t))
+
+	ctx := context.Background()
+	authService := service.NewAuthService(repository.NewUserRepository(client), reputationConfig(50, 90), redisCache, &mockMailService{})
+	if got := authService.AssessIP(ctx, "203.0.113.7"); got.Decision != reputation.DecisionAllow {
+		t.Errorf("Expected no feed to allow everyone, got %+v", got)
+	}
+
+	feed := &fakeReputationFeed{scores: map[string]int{"203.0.113.7": 95, "203.0.113.8": 60, "10.0.0.1": 100}}
+	authService.SetIPReputationProvider(feed)
+
+	if got := authService.AssessIP(ctx, "203.0.113.7"); got.Decision != reputation.DecisionBlock || got.Report.Score != 95 {
+		t.Errorf("Expected 95 to block, got %+v", got)
+	}
+	if got := authService.AssessIP(ctx, "203.0.113.8"); got.Decision != reputation.DecisionStepUp {
+		t.Errorf("Expected 60 to step up, got %+v", got)
+	}
+	lookups := feed.lookups
+	if got := authService.AssessIP(ctx, "10.0.0.1"); got.Decision != reputation.DecisionAllow || feed.lookups != lookups {
+		t.Errorf("Expected private addresses to skip the feed, got %+v", got)
+	}
+
+	feed.err = errors.New("feed down")
+	if got := authService.AssessIP(ctx, "203.0.113.9"); got.Decision != reputation.DecisionAllow {
+		t.Errorf("Expected a feed outage to allow the request, got %+v", got)
+	}
+
+	lookups = feed.lookups
+	if got := authService.AssessIP(ctx, "203.0.113.7"); got.Decision != reputation.DecisionBlock || feed.lookups != lookups {
+		t.Errorf("Expected the cached report to be reused, got %+v after %d lookups", got, feed.lookups-lookups)
+	}
+}
+
+func TestIPReputation_MiddlewareAndStepUp(t *testing.T) {
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	authService := service.NewAuthService(repository.NewUserRepository(client), reputationConfig(50, 90), redisCache, &mockMailService{})
+	authService.SetIPReputationProvider(&fakeReputationFeed{scores: map[string]int{"203.0.113.7": 95, "203.0.113.8": 60}})
+
+	app := fiber.New(fiber.Config{ProxyHeader: fiber.HeaderXForwardedFor})
+	app.Use(middleware.IPReputationMiddleware(authService))
+	app.Use(middleware.FiberWebMiddleware)
+	app.Get("/", func(c *fiber.Ctx) error {
+		if err := authService.RequireIPStepUp(c.UserContext(), nil); err != nil {
+			return c.Status(fiber.StatusUnauthorized).SendString(err.Error())
+		}
+		return c.SendString("ok")
+	})
+
+	for ip, want := range map[string]int{
+		"203.0.113.7":  fiber.StatusForbidden,
+		"203.0.113.8":  fiber.StatusUnauthorized,
+		"198.51.100.1": fiber.StatusOK,
+	} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(fiber.HeaderXForwardedFor, ip)
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("Request from %s failed: %v", ip, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("Expected %d for %s, got %d", want, ip, resp.StatusCode)
+		}
+	}
+}
+
+func TestIPReputation_RaisesLoginRisk(t *testing.T) {
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, redisCache, &mockMailService{})
+	ctx := auth.WithIPReputation(context.Background(), &reputation.Assessment{
+		IP:       "203.0.113.7",
+		Report:   reputation.Report{Score: 100, Source: "fake"},
+		Decision: reputation.DecisionAllow,
+	})
+
+	attempt := authService.RecordLoginAttempt(ctx, service.LoginAttemptInput{
+		Email:         "reputation@example.com",
+		Method:        loginattempt.MethodPASSWORD,
+		Outcome:       loginattempt.OutcomeFAILURE,
+		FailureReason: loginattempt.FailureReasonUNKNOWN_ACCOUNT,
+	})
+	if attempt == nil || attempt.RiskScore != 60 {
+		t.Fatalf("Expected the worst reputation to add 30 to the unknown account's 30, got %+v", attempt)
+	}
+}
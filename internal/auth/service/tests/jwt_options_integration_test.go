@@ -0,0 +1,185 @@
+package tests
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ownIssuer     = "https://auth.example.com"
+	legacyIssuer  = "https://legacy-auth.example.com"
+	foreignIssuer = "https://auth.attacker.example"
+)
+
+// configureOptionsKeySet applies opts and signs with a fresh RSA key, so the
+// test can mint tokens with any claims.
+func configureOptionsKeySet(t *testing.T, opts jwt.Options) *rsa.PrivateKey {
+	t.Helper()
+	configureTokenBudget(t, opts)
+	t.Cleanup(func() { jwt.SetKeySet(nil) })
+
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	key, err := jwt.ParseSigningKey("options", "RS256", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(private)}))
+	if err != nil {
+		t.Fatalf("Failed to parse signing key: %v", err)
+	}
+	ks, _ := jwt.NewKeySet("options", key)
+	jwt.SetKeySet(ks)
+	return private
+}
+
+func signClaimsWith(t *testing.T, private *rsa.PrivateKey, registered gojwt.RegisteredClaims) string {
+	t.Helper()
+	token := gojwt.NewWithClaims(gojwt.SigningMethodRS256, &jwt.Claims{Type: jwt.TokenTypeAccess, RegisteredClaims: registered})
+	token.Header["kid"] = "options"
+
+	signed, err := token.SignedString(private)
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return signed
+}
+
+func TestJWTOptions_Issuers(t *testing.T) {
+	opts := jwt.DefaultOptions()
+	opts.Issuer = ownIssuer
+	opts.AcceptedIssuers = []string{legacyIssuer}
+	private := configureOptionsKeySet(t, opts)
+
+	issued, err := jwt.GenerateToken("user-public-id", jwt.TokenTypeAccess, time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	if claims, err := jwt.ValidateToken(issued); err != nil || claims.Issuer != ownIssuer {
+		t.Fatalf("Expected an issued token to carry %s and validate, got %+v (%v)", ownIssuer, claims, err)
+	}
+
+	tokenFrom := func(issuer string) string {
+		now := time.Now()
+		return signClaimsWith(t, private, gojwt.RegisteredClaims{
+			Subject:   "user-public-id",
+			Issuer:    issuer,
+			IssuedAt:  gojwt.NewNumericDate(now),
+			ExpiresAt: gojwt.NewNumericDate(now.Add(time.Minute)),
+		})
+	}
+
+	for issuer, want := range map[string]error{
+		ownIssuer:         nil,
+		legacyIssuer:      nil,
+		foreignIssuer:     errors.InvalidToken,
+		jwt.DefaultIssuer: errors.InvalidToken,
+		"":                errors.InvalidToken,
+	} {
+		if _, err := jwt.ValidateToken(tokenFrom(issuer)); err != want {
+			t.Errorf("Expected issuer %q to give %v, got %v", issuer, want, err)
+		}
+	}
+
+	opts.SkipIssuerCheck = true
+	if err := jwt.Configure(opts); err != nil {
+		t.Fatalf("Failed to configure jwt: %v", err)
+	}
+	if _, err := jwt.ValidateToken(tokenFrom(foreignIssuer)); err != nil {
+		t.Errorf("Expected SkipIssuerCheck to accept any issuer, got %v", err)
+	}
+}
+
+func TestJWTOptions_Leeway(t *testing.T) {
+	opts := jwt.DefaultOptions()
+	opts.Leeway = time.Minute
+	private := configureOptionsKeySet(t, opts)
+
+	// 10s either side of the leeway keeps the cases clear of the test's
+	// own running time and of whole-second claim precision.
+	now := time.Now()
+	cases := []struct {
+		name      string
+		expiresAt time.Time
+		notBefore time.Time
+		want      error
+	}{
+		{"expired just inside the leeway", now.Add(-50 * time.Second), time.Time{}, nil},
+		{"expired just outside the leeway", now.Add(-70 * time.Second), time.Time{}, errors.ExpiredToken},
+		{"not yet valid just inside the leeway", now.Add(time.Hour), now.Add(50 * time.Second), nil},
+		{"not yet valid just outside the leeway", now.Add(time.Hour), now.Add(70 * time.Second), errors.InvalidToken},
+	}
+
+	for _, tc := range cases {
+		registered := gojwt.RegisteredClaims{
+			Subject:   "user-public-id",
+			Issuer:    jwt.DefaultIssuer,
+			IssuedAt:  gojwt.NewNumericDate(now.Add(-10 * time.Minute)),
+			ExpiresAt: gojwt.NewNumericDate(tc.expiresAt),
+		}
+		if !tc.notBefore.IsZero() {
+			registered.NotBefore = gojwt.NewNumericDate(tc.notBefore)
+		}
+
+		if _, err := jwt.ValidateToken(signClaimsWith(t, private, registered)); err != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+
+	for _, leeway := range []time.Duration{-time.Second, 5*time.Minute + time.Second} {
+		bad := jwt.DefaultOptions()
+		bad.Leeway = leeway
+		if err := jwt.Configure(bad); err == nil {
+			t.Errorf("Expected a leeway of %s to be rejected", leeway)
+		}
+	}
+}
+
+func TestJWTOptions_ZeroDurationsFromYAML(t *testing.T) {
+	cases := []struct {
+		name      string
+		yaml      string
+		clockSkew time.Duration
+		leeway    time.Duration
+	}{
+		{"absent", "jwt:\n  issuer: x\n", jwt.DefaultClockSkew, jwt.DefaultLeeway},
+		{"explicit zero", "jwt:\n  clock_skew: 0s\n  leeway: 0s\n", 0, 0},
+		{"explicit values", "jwt:\n  clock_skew: 5s\n  leeway: 1m\n", 5 * time.Second, time.Minute},
+	}
+
+	for _, tc := range cases {
+		var cfg configs.Config
+		if err := yaml.Unmarshal([]byte(tc.yaml), &cfg); err != nil {
+			t.Fatalf("%s: failed to decode config: %v", tc.name, err)
+		}
+		opts := jwt.OptionsFromConfig(&cfg)
+		if opts.ClockSkew != tc.clockSkew || opts.Leeway != tc.leeway {
+			t.Errorf("%s: expected clock skew %s and leeway %s, got %s and %s", tc.name, tc.clockSkew, tc.leeway, opts.ClockSkew, opts.Leeway)
+		}
+	}
+
+	// With the leeway off, a token is refused the moment it expires.
+	var cfg configs.Config
+	if err := yaml.Unmarshal([]byte("jwt:\n  leeway: 0s\n"), &cfg); err != nil {
+		t.Fatalf("Failed to decode config: %v", err)
+	}
+	private := configureOptionsKeySet(t, jwt.OptionsFromConfig(&cfg))
+	now := time.Now()
+	expired := signClaimsWith(t, private, gojwt.RegisteredClaims{
+		Subject:   "user-public-id",
+		Issuer:    jwt.DefaultIssuer,
+		IssuedAt:  gojwt.NewNumericDate(now.Add(-time.Minute)),
+		ExpiresAt: gojwt.NewNumericDate(now.Add(-2 * time.Second)),
+	})
+	if _, err := jwt.ValidateToken(expired); err != errors.ExpiredToken {
+		t.Errorf("Expected a token expired 2s ago to be refused without leeway, got %v", err)
+	}
+}
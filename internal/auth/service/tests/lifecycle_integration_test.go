@@ -0,0 +1,163 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/lifecycle"
+	"github.com/abisalde/authentication-service/internal/worker"
+)
+
+// stopLog records the order components stopped in.
+type stopLog struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func (l *stopLog) add(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.names = append(l.names, name)
+}
+
+func (l *stopLog) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return strings.Join(l.names, ",")
+}
+
+func waitingComponent(name string, stopped *stopLog) func(context.Context) error {
+	return func(ctx context.Context) error {
+		<-ctx.Done()
+		stopped.add(name)
+		return nil
+	}
+}
+
+func TestLifecycle_DrainsComponentsInReverseOrder(t *testing.T) {
+	stopped := &stopLog{}
+	requests := make(chan struct{})
+	services := lifecycle.New(time.Second).
+		Go("worker", waitingComponent("worker", stopped)).
+		Go("scheduler", waitingComponent("scheduler", stopped)).
+		Add(lifecycle.Component{
+			Name: "http",
+			Run: func(context.Context) error {
+				<-requests
+				stopped.add("http")
+				return nil
+			},
+			Stop: func(context.Context) error {
+				close(requests)
+				return nil
+			},
+		})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- services.Run(ctx) }()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Expected a clean shutdown, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the services to drain")
+	}
+	if got := stopped.String(); got != "http,scheduler,worker" {
+		t.Errorf("Expected the app to stop taking requests before the workers stop, got %s", got)
+	}
+}
+
+func TestLifecycle_FailedComponentShutsDownTheRest(t *testing.T) {
+	stopped := &stopLog{}
+	services := lifecycle.New(time.Second).
+		Go("worker", waitingComponent("worker", stopped)).
+		Go("http", func(context.Context) error {
+			return errors.New("address already in use")
+		})
+
+	err := services.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "http: address already in use") {
+		t.Fatalf("Expected the failure of the app, got %v", err)
+	}
+	if got := stopped.String(); got != "worker" {
+		t.Errorf("Expected the worker to be stopped, got %q", got)
+	}
+}
+
+func TestLifecycle_GivesUpOnComponentsThatDoNotStop(t *testing.T) {
+	stuck := make(chan struct{})
+	defer close(stuck)
+	services := lifecycle.New(100*time.Millisecond).
+		Go("stuck", func(context.Context) error {
+			<-stuck
+			return nil
+		})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	started := time.Now()
+	err := services.Run(ctx)
+	if err == nil || !strings.Contains(err.Error(), "stuck did not stop") {
+		t.Fatalf("Expected the drain to time out, got %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Errorf("Expected Run to return at the shutdown timeout, took %v", elapsed)
+	}
+}
+
+func TestLifecycle_LastLoginWorkerStopsOnShutdown(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	rdb := embeddedRedis(t)
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(rdb), &mockMailService{})
+	user := createVerifiedUser(t, client, "lifecycle_worker@example.com")
+
+	lastLogin := worker.NewLastLoginWorker(rdb, authService, &configs.Config{}, authService.Logger())
+	services := lifecycle.New(10*time.Second).Go("last_login_worker", func(ctx context.Context) error {
+		lastLogin.Start(ctx)
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- services.Run(ctx) }()
+
+	if err := authService.PublishLoginEvent(context.Background(), user.ID); err != nil {
+		t.Fatalf("Failed to publish the login event: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		u, err := client.User.Get(context.Background(), user.ID)
+		if err == nil && u.LastLoginAt != nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the worker to record the login")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Expected the worker to stop cleanly, got %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected the worker to stop on shutdown")
+	}
+}
@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/cookies"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/database/ent/loginattempt"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+const firefoxLinuxUA = "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0"
+
+var revokeLink = regexp.MustCompile(`https://auth\.example\.com/not-me\?token=[^\s"]+`)
+
+func loginAlertConfig() *configs.Config {
+	cfg := &configs.Config{}
+	cfg.LoginAlerts.Enabled = true
+	cfg.LoginAlerts.RevokeURL = "https://auth.example.com/not-me"
+	return cfg
+}
+
+// signInFrom records a successful password login as sent from ip by the
+// device.
+func signInFrom(t *testing.T, authService *service.AuthService, u *ent.User, ip, deviceID, userAgent string) {
+	t.Helper()
+	app := fiber.New(fiber.Config{ProxyHeader: fiber.HeaderXForwardedFor})
+	app.Post("/login", func(c *fiber.Ctx) error {
+		authService.RecordLoginAttempt(context.Background(), service.LoginAttemptInput{
+			User:    u,
+			Method:  loginattempt.MethodPASSWORD,
+			Outcome: loginattempt.OutcomeSUCCESS,
+			Request: c,
+		})
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	req := httptest.NewRequest("POST", "/login", nil)
+	req.Header.Set(fiber.HeaderXForwardedFor, ip)
+	req.Header.Set(fiber.HeaderUserAgent, userAgent)
+	if deviceID != "" {
+		req.Header.Set(cookies.DeviceIDHeader, deviceID)
+	}
+	if _, err := app.Test(req, -1); err != nil {
+		t.Fatalf("Failed to sign in: %v", err)
+	}
+}
+
+func TestLoginAlerts_UnrecognizedSignIn(t *testing.T) {
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	mailer := &recordingMailService{}
+	authService := service.NewAuthService(repository.NewUserRepository(client), loginAlertConfig(), redisCache, mailer)
+	u := createVerifiedUser(t, client, "alerts@example.com")
+
+	steps := []struct {
+		name      string
+		ip        string
+		deviceID  string
+		userAgent string
+		alert     bool
+	}{
+		{"the first sign-in", "203.0.113.7", "laptop", chromeWindows, false},
+		{"a new device at home", "203.0.200.1", "phone", firefoxLinuxUA, false},
+		{"the laptop on hotel wifi", "198.51.100.9", "laptop", chromeWindows, false},
+		{"a new device on a new network", "192.0.2.10", "stranger", firefoxLinuxUA, true},
+	}
+
+	for _, step := range steps {
+		before := len(mailer.sent)
+		signInFrom(t, authService, u, step.ip, step.deviceID, step.userAgent)
+		if alerted := len(mailer.sent) > before; alerted != step.alert {
+			t.Fatalf("%s: expected alert %v, got %v", step.name, step.alert, alerted)
+		}
+	}
+
+	mail := mailer.sent[0]
+	if mail.recipient != u.Email || !strings.Contains(mail.plain, "192.0.2.10") || !strings.Contains(mail.html, "Firefox 128 on Linux") {
+		t.Errorf("Expected the alert to describe the sign-in, got %+v", mail)
+	}
+}
+
+func TestLoginAlerts_RevokeFromEmail(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	redisCache := database.NewCacheService(embeddedRedis(t))
+
+	ctx := context.Background()
+
+	mailer := &recordingMailService{}
+	authService := service.NewAuthService(repository.NewUserRepository(client), loginAlertConfig(), redisCache, mailer)
+	u := createVerifiedUser(t, client, "not-me@example.com")
+
+	signInFrom(t, authService, u, "203.0.113.7", "laptop", chromeWindows)
+	signInFrom(t, authService, u, "192.0.2.10", "stranger", firefoxLinuxUA)
+	if len(mailer.sent) != 1 {
+		t.Fatalf("Expected one alert, got %d", len(mailer.sent))
+	}
+
+	link := revokeLink.FindString(mailer.sent[0].plain)
+	parsed, err := url.Parse(link)
+	if err != nil || parsed.Query().Get("token") == "" {
+		t.Fatalf("Expected a revocation link in the alert, got %q", link)
+	}
+	token := parsed.Query().Get("token")
+
+	// Cutoffs are in milliseconds and the embedded store answers within one.
+	signedIn := time.Now().Add(-time.Millisecond)
+	if err := authService.RevokeUnrecognizedSignIn(ctx, token); err != nil {
+		t.Fatalf("Failed to revoke the sign-in: %v", err)
+	}
+	if !authService.IsTokenRevokedForUser(ctx, u.ID, signedIn) {
+		t.Error("Expected every session of the user to be revoked")
+	}
+	if reason := authService.RevocationReasonFor(ctx, u.ID); reason != model.RevocationReasonUnrecognizedSignIn {
+		t.Errorf("Expected the revocation reason UNRECOGNIZED_SIGN_IN, got %s", reason)
+	}
+
+	if err := authService.RevokeUnrecognizedSignIn(ctx, token); err != service.ErrSignInAlertTokenInvalid {
+		t.Errorf("Expected the link to work once, got %v", err)
+	}
+}
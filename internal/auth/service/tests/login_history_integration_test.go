@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database/ent/loginattempt"
+)
+
+func TestLoginHistory_RecordsAndScoresAttempts(t *testing.T) {
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, redisCache, &mockMailService{})
+	user := createVerifiedUser(t, client, "history@example.com")
+
+	first := authService.RecordLoginAttempt(ctx, service.LoginAttemptInput{
+		User:    user,
+		Method:  loginattempt.MethodPASSWORD,
+		Outcome: loginattempt.OutcomeSUCCESS,
+	})
+	if first == nil || first.RiskScore != 0 || first.Email != user.Email {
+		t.Fatalf("Expected a zero risk first success, got %+v", first)
+	}
+
+	for i := 0; i < 2; i++ {
+		authService.RecordLoginAttempt(ctx, service.LoginAttemptInput{
+			User:          user,
+			Method:        loginattempt.MethodPASSWORD,
+			Outcome:       loginattempt.OutcomeFAILURE,
+			FailureReason: loginattempt.FailureReasonINVALID_PASSWORD,
+		})
+	}
+
+	unknown := authService.RecordLoginAttempt(ctx, service.LoginAttemptInput{
+		Email:         "nobody@example.com",
+		Method:        loginattempt.MethodPASSWORD,
+		Outcome:       loginattempt.OutcomeFAILURE,
+		FailureReason: loginattempt.FailureReasonUNKNOWN_ACCOUNT,
+	})
+	if unknown == nil || unknown.UserID != nil || unknown.RiskScore != 30 {
+		t.Fatalf("Expected an unattributed attempt scored 30, got %+v", unknown)
+	}
+
+	success := authService.RecordLoginAttempt(ctx, service.LoginAttemptInput{
+		User:    user,
+		Method:  loginattempt.MethodPASSWORD,
+		Outcome: loginattempt.OutcomeSUCCESS,
+	})
+	if success == nil || success.RiskScore != 20 {
+		t.Fatalf("Expected recent failures to raise the risk score to 20, got %+v", success)
+	}
+
+	outcome := loginattempt.OutcomeSUCCESS
+	activity, err := authService.ListLoginAttempts(ctx, repository.LoginAttemptFilter{UserID: &user.ID, Outcome: &outcome}, 0, 10)
+	if err != nil {
+		t.Fatalf("Failed to list login activity: %v", err)
+	}
+	if len(activity) != 2 || activity[0].ID != success.ID {
+		t.Fatalf("Expected two successes newest first, got %d", len(activity))
+	}
+	if activity[0].Edges.User == nil || activity[0].Edges.User.PublicID != user.PublicID {
+		t.Errorf("Expected the user edge to be loaded")
+	}
+
+	page, err := authService.ListLoginAttempts(ctx, repository.LoginAttemptFilter{}, success.ID, 10)
+	if err != nil || len(page) != 4 {
+		t.Fatalf("Expected four attempts before the cursor, got %d (%v)", len(page), err)
+	}
+}
+
+func TestLoginHistory_PrunesPastRetention(t *testing.T) {
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cfg := &configs.Config{}
+	cfg.LoginHistory.Retention = time.Hour
+	cfg.LoginHistory.PruneBatch = 2
+
+	authService := service.NewAuthService(repository.NewUserRepository(client), cfg, redisCache, &mockMailService{})
+
+	for i := 0; i < 5; i++ {
+		client.LoginAttempt.Create().
+			SetEmail("old@example.com").
+			SetOutcome(loginattempt.OutcomeFAILURE).
+			SetCreatedAt(time.Now().Add(-2 * time.Hour)).
+			SaveX(ctx)
+	}
+	recent := client.LoginAttempt.Create().
+		SetEmail("new@example.com").
+		SetOutcome(loginattempt.OutcomeSUCCESS).
+		SaveX(ctx)
+
+	deleted, err := authService.PruneLoginHistory(ctx)
+	if err != nil {
+		t.Fatalf("Failed to prune login history: %v", err)
+	}
+	if deleted != 5 {
+		t.Errorf("Expected 5 attempts pruned in batches, got %d", deleted)
+	}
+
+	remaining := client.LoginAttempt.Query().AllX(ctx)
+	if len(remaining) != 1 || remaining[0].ID != recent.ID {
+		t.Errorf("Expected only the recent attempt to remain, got %d", len(remaining))
+	}
+}
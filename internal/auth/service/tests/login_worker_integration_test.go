@@ -0,0 +1,223 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/events"
+	"github.com/abisalde/authentication-service/internal/worker"
+	"github.com/redis/go-redis/v9"
+)
+
+func loginWorkerConfig(consumer string, claimIdle time.Duration, maxDeliveries int64) *configs.Config {
+	cfg := &configs.Config{}
+	cfg.LoginWorker.Consumer = consumer
+	cfg.LoginWorker.ClaimIdle = claimIdle
+	cfg.LoginWorker.MaxDeliveries = maxDeliveries
+	return cfg
+}
+
+// runLoginWorker runs w until the returned function stops it.
+func runLoginWorker(w *worker.LastLoginWorker) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.Start(ctx)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func waitForLastLogin(t *testing.T, client *ent.Client, userID int64) *ent.User {
+	t.Helper()
+	deadline := time.Now().Add(8 * time.Second)
+	for {
+		u, err := client.User.Get(context.Background(), userID)
+		if err == nil && u.LastLoginAt != nil {
+			return u
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the worker to record the login of user %d", userID)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func pendingLoginEvents(t *testing.T, rdb *redis.Client) []redis.XPendingExt {
+	t.Helper()
+	pending, err := rdb.XPendingExt(context.Background(), &redis.XPendingExtArgs{
+		Stream: service.LoginStreamKey,
+		Group:  service.LoginGroup,
+		Start:  "-",
+		End:    "+",
+		Count:  100,
+	}).Result()
+	if err != nil {
+		t.Fatalf("Failed to list the pending events: %v", err)
+	}
+	return pending
+}
+
+func TestLoginWorker_RestartResumesAfterAcknowledgedEvents(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(rdb), &mockMailService{})
+	user := createVerifiedUser(t, client, "login_worker_restart@example.com")
+
+	// An event published before the first worker starts is still applied.
+	if err := authService.PublishLoginEvent(ctx, user.ID); err != nil {
+		t.Fatalf("Failed to publish the login event: %v", err)
+	}
+	stop := runLoginWorker(worker.NewLastLoginWorker(rdb, authService, loginWorkerConfig("replica-a", time.Minute, 5), authService.Logger()))
+	first := waitForLastLogin(t, client, user.ID)
+	stop()
+
+	if pending := pendingLoginEvents(t, rdb); len(pending) != 0 {
+		t.Fatalf("Expected the event to be acknowledged, got %d pending", len(pending))
+	}
+
+	applied := metricValue(t, `login_events_total{outcome="applied"}`)
+	stop = runLoginWorker(worker.NewLastLoginWorker(rdb, authService, loginWorkerConfig("replica-a", time.Minute, 5), authService.Logger()))
+	time.Sleep(300 * time.Millisecond)
+	stop()
+
+	if got := metricValue(t, `login_events_total{outcome="applied"}`); got != applied {
+		t.Errorf("Expected a restart not to replay acknowledged events, %v were applied again", got-applied)
+	}
+	u, err := client.User.Get(ctx, user.ID)
+	if err != nil || !u.LastLoginAt.Equal(*first.LastLoginAt) {
+		t.Errorf("Expected the last login to be left as recorded, got %v (%v)", u.LastLoginAt, err)
+	}
+}
+
+func TestLoginWorker_ReplicasShareTheStream(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(rdb), &mockMailService{})
+
+	applied := metricValue(t, `login_events_total{outcome="applied"}`)
+	stopA := runLoginWorker(worker.NewLastLoginWorker(rdb, authService, loginWorkerConfig("replica-a", time.Minute, 5), authService.Logger()))
+	stopB := runLoginWorker(worker.NewLastLoginWorker(rdb, authService, loginWorkerConfig("replica-b", time.Minute, 5), authService.Logger()))
+	defer stopB()
+	defer stopA()
+
+	users := []*ent.User{
+		createVerifiedUser(t, client, "login_worker_share_1@example.com"),
+		createVerifiedUser(t, client, "login_worker_share_2@example.com"),
+		createVerifiedUser(t, client, "login_worker_share_3@example.com"),
+	}
+	for _, u := range users {
+		if err := authService.PublishLoginEvent(ctx, u.ID); err != nil {
+			t.Fatalf("Failed to publish the login event: %v", err)
+		}
+	}
+	for _, u := range users {
+		waitForLastLogin(t, client, u.ID)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	if got := metricValue(t, `login_events_total{outcome="applied"}`) - applied; got != float64(len(users)) {
+		t.Errorf("Expected each event to be applied once across replicas, got %v applications", got)
+	}
+}
+
+func TestLoginWorker_ClaimsEventsOfADeadConsumer(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(rdb), &mockMailService{})
+	user := createVerifiedUser(t, client, "login_worker_claim@example.com")
+
+	if err := rdb.XGroupCreateMkStream(ctx, service.LoginStreamKey, service.LoginGroup, "0").Err(); err != nil {
+		t.Fatalf("Failed to create the group: %v", err)
+	}
+	if err := authService.PublishLoginEvent(ctx, user.ID); err != nil {
+		t.Fatalf("Failed to publish the login event: %v", err)
+	}
+	// A replica reads the event and dies before acknowledging it.
+	read, err := rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    service.LoginGroup,
+		Consumer: "crashed-replica",
+		Streams:  []string{service.LoginStreamKey, ">"},
+		Block:    -1,
+	}).Result()
+	if err != nil || len(read) != 1 || len(read[0].Messages) != 1 {
+		t.Fatalf("Expected the crashed replica to hold the event, got %v (%v)", read, err)
+	}
+
+	stop := runLoginWorker(worker.NewLastLoginWorker(rdb, authService, loginWorkerConfig("replica-a", 100*time.Millisecond, 5), authService.Logger()))
+	defer stop()
+
+	waitForLastLogin(t, client, user.ID)
+	time.Sleep(100 * time.Millisecond)
+	if pending := pendingLoginEvents(t, rdb); len(pending) != 0 {
+		t.Errorf("Expected the claimed event to be acknowledged, got %+v", pending)
+	}
+}
+
+func TestLoginWorker_DeadLettersEventsThatKeepFailing(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(rdb), &mockMailService{})
+
+	// An unreadable event can never apply, and a deleted user never will.
+	if err := rdb.XAdd(ctx, &redis.XAddArgs{Stream: service.LoginStreamKey, Values: map[string]interface{}{"event": "{not json"}}).Err(); err != nil {
+		t.Fatalf("Failed to add the event: %v", err)
+	}
+	missing, _ := json.Marshal(service.LoginEvent{UserID: 987654321, EventType: events.UserLastLogin, Timestamp: time.Now()})
+	missingID, err := rdb.XAdd(ctx, &redis.XAddArgs{Stream: service.LoginStreamKey, Values: map[string]interface{}{"event": string(missing)}}).Result()
+	if err != nil {
+		t.Fatalf("Failed to add the event: %v", err)
+	}
+
+	failed := metricValue(t, `login_events_total{outcome="failed"}`)
+	stop := runLoginWorker(worker.NewLastLoginWorker(rdb, authService, loginWorkerConfig("replica-a", 100*time.Millisecond, 2), authService.Logger()))
+	defer stop()
+
+	var dead []redis.XMessage
+	deadline := time.Now().Add(15 * time.Second)
+	for len(dead) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected both events to be dead-lettered, got %+v", dead)
+		}
+		time.Sleep(50 * time.Millisecond)
+		dead, err = rdb.XRange(ctx, service.LoginDeadLetterStreamKey, "-", "+").Result()
+		if err != nil {
+			t.Fatalf("Failed to read the dead-letter stream: %v", err)
+		}
+	}
+
+	if dead[0].Values["reason"] != "unreadable event" || dead[0].Values["event"] != "{not json" {
+		t.Errorf("Expected the unreadable event to be dead-lettered at once, got %+v", dead[0].Values)
+	}
+	if dead[1].Values["id"] != missingID || dead[1].Values["reason"] != "failed 2 deliveries" || dead[1].Values["event"] != string(missing) {
+		t.Errorf("Expected the failing event to be dead-lettered after two deliveries, got %+v", dead[1].Values)
+	}
+	if got := metricValue(t, `login_events_total{outcome="failed"}`) - failed; got != 2 {
+		t.Errorf("Expected two failed attempts before giving up, got %v", got)
+	}
+	if pending := pendingLoginEvents(t, rdb); len(pending) != 0 {
+		t.Errorf("Expected dead-lettered events to be acknowledged, got %+v", pending)
+	}
+}
@@ -0,0 +1,173 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/ratelimit"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/abisalde/authentication-service/pkg/scheduler"
+	"github.com/redis/go-redis/v9"
+)
+
+// minutesLeft rounds the TTL of key to minutes, coarse enough to ignore the
+// time the scenario itself takes.
+func minutesLeft(ctx context.Context, rdb *redis.Client, key string) string {
+	ttl, err := rdb.PTTL(ctx, key).Result()
+	if err != nil {
+		return err.Error()
+	}
+	if ttl < 0 {
+		return fmt.Sprintf("ttl %d", ttl)
+	}
+	return fmt.Sprintf("%dm", ttl.Round(time.Minute)/time.Minute)
+}
+
+// TestLuaScripts_Outcomes drives every Lua script through the API that runs
+// it against the embedded store.
+func TestLuaScripts_Outcomes(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	user := createVerifiedUser(t, client, "lua_scripts@example.com")
+	authService := func(rdb *redis.Client, cfg *configs.Config) *service.AuthService {
+		return service.NewAuthService(repository.NewUserRepository(client), cfg, database.NewCacheService(rdb), &mockMailService{})
+	}
+	limiterCalls := func(algorithm model.RateLimitAlgorithm) func(context.Context, *redis.Client) []string {
+		return func(ctx context.Context, rdb *redis.Client) []string {
+			limiter := ratelimit.New(database.NewCacheService(rdb))
+			policy := ratelimit.Policy{
+				Operation: model.RateLimitMethodsLogin,
+				Limit:     2,
+				Burst:     2,
+				Window:    time.Minute,
+				Key:       model.RateLimitKeyClient,
+				Algorithm: algorithm,
+			}
+			var seen []string
+			for range 3 {
+				allowed, err := limiter.Allow(ctx, policy, ratelimit.Client{IP: "198.51.100.7"})
+				seen = append(seen, fmt.Sprint(allowed, err))
+			}
+			return seen
+		}
+	}
+
+	scenarios := []struct {
+		name string
+		run  func(ctx context.Context, rdb *redis.Client) []string
+		want []string
+	}{
+		{
+			name: "sliding window",
+			run:  limiterCalls(model.RateLimitAlgorithmSlidingWindow),
+			want: []string{"true <nil>", "true <nil>", "false <nil>"},
+		},
+		{
+			name: "token bucket",
+			run:  limiterCalls(model.RateLimitAlgorithmTokenBucket),
+			want: []string{"true <nil>", "true <nil>", "false <nil>"},
+		},
+		{
+			name: "probation sessions",
+			run: func(ctx context.Context, rdb *redis.Client) []string {
+				cfg := &configs.Config{}
+				cfg.Probation.Window = time.Hour
+				cfg.Probation.MaxSessions = 2
+				svc := authService(rdb, cfg)
+				key := fmt.Sprintf("%s%d", service.ProbationSessionsPrefix, user.ID)
+
+				var seen []string
+				for _, device := range []string{"laptop", "phone", "tablet", "phone"} {
+					seen = append(seen, fmt.Sprint(svc.AdmitProbationLogin(ctx, user, jwt.NewDeviceClaim(device, ""))))
+				}
+				sessions := rdb.ZRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{Min: "-inf", Max: "+inf"}).Val()
+				for _, z := range sessions {
+					left := time.Until(time.Unix(int64(z.Score), 0)).Round(time.Minute)
+					seen = append(seen, fmt.Sprintf("%v", left))
+				}
+				return seen
+			},
+			want: []string{"<nil>", "<nil>", service.ErrSessionLimitReached.Error(), "<nil>", "10m0s", "360h0m0s"},
+		},
+		{
+			name: "session activity",
+			run: func(ctx context.Context, rdb *redis.Client) []string {
+				cfg := &configs.Config{}
+				cfg.Sessions.IdleTimeout = time.Hour
+				svc := authService(rdb, cfg)
+				activityKey := fmt.Sprintf("%s%d", service.SessionActivityPrefix, user.ID)
+				refreshKey := fmt.Sprintf("%s%d", service.RefreshCachePrefix, user.ID)
+
+				if _, err := svc.StoreRefreshToken(ctx, user, "lua-scripts-refresh-token"); err != nil {
+					return []string{err.Error()}
+				}
+				rdb.Expire(ctx, refreshKey, 30*time.Minute)
+				seen := []string{fmt.Sprint(svc.UpdateSessionActivity(ctx, user.ID)), minutesLeft(ctx, rdb, refreshKey)}
+
+				rdb.HSet(ctx, activityKey, "last_active", time.Now().Add(-10*time.Minute).Unix())
+				seen = append(seen, fmt.Sprint(svc.UpdateSessionActivity(ctx, user.ID)), minutesLeft(ctx, rdb, refreshKey))
+
+				rdb.HSet(ctx, activityKey, "last_active", time.Now().Add(-2*time.Hour).Unix())
+				return append(seen, fmt.Sprint(svc.UpdateSessionActivity(ctx, user.ID)))
+			},
+			want: []string{"<nil>", "30m", "<nil>", "60m", service.ErrSessionIdle.Error()},
+		},
+		{
+			name: "token scopes",
+			run: func(ctx context.Context, rdb *redis.Client) []string {
+				store := jwt.NewRedisScopeStore(rdb)
+				var seen []string
+				for _, put := range []struct {
+					scopes []string
+					ttl    time.Duration
+				}{{[]string{"profile"}, time.Hour}, {[]string{"email"}, time.Minute}, {[]string{"admin"}, 2 * time.Hour}} {
+					err := store.Put(ctx, "ref", put.scopes, put.ttl)
+					scopes, _ := store.Get(ctx, "ref")
+					seen = append(seen, fmt.Sprintf("%v %s %s", err, strings.Join(scopes, ","), minutesLeft(ctx, rdb, jwt.ScopeRefPrefix+"ref")))
+				}
+				return seen
+			},
+			want: []string{"<nil> profile 60m", "<nil> profile 60m", "<nil> admin 120m"},
+		},
+		{
+			name: "scheduler lock",
+			run: func(ctx context.Context, rdb *redis.Client) []string {
+				locker := scheduler.NewRedisLocker(rdb)
+				key := scheduler.LockPrefix + "job"
+
+				release, ok, err := locker.TryLock(ctx, "job", time.Minute)
+				seen := []string{fmt.Sprint(ok, err)}
+				_, ok, err = locker.TryLock(ctx, "job", time.Minute)
+				seen = append(seen, fmt.Sprint(ok, err))
+				release()
+				seen = append(seen, fmt.Sprint(rdb.Exists(ctx, key).Val()))
+
+				// A holder whose lock lapsed must not release the next one's.
+				stale, _, _ := locker.TryLock(ctx, "job", time.Minute)
+				rdb.Del(ctx, key)
+				_, ok, _ = locker.TryLock(ctx, "job", time.Minute)
+				stale()
+				return append(seen, fmt.Sprint(ok, rdb.Exists(ctx, key).Val()))
+			},
+			want: []string{"true <nil>", "false <nil>", "0", "true 1"},
+		},
+	}
+
+	for _, sc := range scenarios {
+		t.Run(sc.name, func(t *testing.T) {
+			got := sc.run(context.Background(), embeddedRedis(t))
+			if fmt.Sprint(got) != fmt.Sprint(sc.want) {
+				t.Errorf("Expected %v, got %v", sc.want, got)
+			}
+		})
+	}
+}
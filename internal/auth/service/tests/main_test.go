@@ -0,0 +1,25 @@
+package tests
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"testing"
+)
+
+// TestMain configures the token secrets before any test runs.
+// pkg/verification reads them once per process, so a test that sets them
+// itself may come too late.
+func TestMain(m *testing.M) {
+	for _, name := range []string{"REFRESH_TOKEN_HASH_SECRET", "REFRESH_TOKEN_ENC_SECRET"} {
+		if os.Getenv(name) != "" {
+			continue
+		}
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			panic(err)
+		}
+		os.Setenv(name, base64.StdEncoding.EncodeToString(secret))
+	}
+	os.Exit(m.Run())
+}
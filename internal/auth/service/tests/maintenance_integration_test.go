@@ -0,0 +1,266 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/maintenance"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/database/ent/user"
+	"github.com/abisalde/authentication-service/internal/graph/directives"
+	"github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/middleware"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestMaintenancePolicy_LimitsGrants(t *testing.T) {
+	cfg := &configs.Config{}
+	cfg.Maintenance.Operations = []string{"suspendUser", "approveMaintenanceToken"}
+	cfg.Maintenance.TokenTTL = time.Hour
+	cfg.Maintenance.Approvals = 1
+
+	policy := maintenance.NewPolicy(cfg)
+	if !policy.Grantable("suspendUser") {
+		t.Error("Expected suspendUser to be grantable")
+	}
+	if policy.Grantable("approveMaintenanceToken") {
+		t.Error("Expected maintenance mutations never to be grantable")
+	}
+	if policy.TokenTTL != maintenance.DefaultTokenTTL {
+		t.Errorf("Expected token TTL capped at %s, got %s", maintenance.DefaultTokenTTL, policy.TokenTTL)
+	}
+	if policy.Approvals != 2 {
+		t.Errorf("Expected at least two approvals, got %d", policy.Approvals)
+	}
+	if maintenance.NewPolicy(&configs.Config{}).Enabled() {
+		t.Error("Expected maintenance tokens disabled without operations")
+	}
+}
+
+func TestMaintenance_DirectiveOnlyAllowsGrantedMutations(t *testing.T) {
+	operator := &ent.User{ID: 42, Role: user.RoleUSER}
+	grant := &maintenance.Grant{
+		ID:         "grant-1",
+		OperatorID: operator.ID,
+		Operations: []string{"suspendUser"},
+		Reason:     "Spam wave from a compromised partner integration",
+		Status:     maintenance.StatusIssued,
+		ExpiresAt:  time.Now().Add(time.Minute),
+	}
+
+	ctx := auth.WithMaintenanceGrant(auth.WithUser(context.Background(), operator, nil), grant)
+	fieldCtx := func(object, name string) context.Context {
+		return graphql.WithFieldContext(ctx, &graphql.FieldContext{
+			Object: object,
+			Field:  graphql.CollectedField{Field: &ast.Field{Name: name}},
+		})
+	}
+
+	next := graphql.Resolver(func(ctx context.Context) (interface{}, error) {
+		return true, nil
+	})
+	admin := model.UserRoleAdmin
+	userRole := model.UserRoleUser
+
+	if _, err := directives.NewAuthDirective().Auth(fieldCtx("Mutation", "suspendUser"), nil, next, &admin); err != nil {
+		t.Errorf("Expected the granted admin mutation to run, got %v", err)
+	}
+	if _, err := directives.NewAuthDirective().Auth(fieldCtx("Mutation", "liftSuspension"), nil, next, &admin); err == nil {
+		t.Error("Expected a mutation outside the grant to be denied")
+	}
+	if _, err := directives.NewAuthDirective().Auth(fieldCtx("Query", "profile"), nil, next, &userRole); err == nil {
+		t.Error("Expected queries to be denied to maintenance tokens")
+	}
+
+	grant.ExpiresAt = time.Now().Add(-time.Second)
+	if _, err := directives.NewAuthDirective().Auth(fieldCtx("Mutation", "suspendUser"), nil, next, &admin); err == nil {
+		t.Error("Expected an expired grant to be denied")
+	}
+}
+
+// maintenanceCall sends the token through AuthMiddleware and resolves the
+// mutation behind @auth(requires: ADMIN), as the GraphQL handler would.
+func maintenanceCall(t *testing.T, client *ent.Client, authService *service.AuthService, token, mutation string) error {
+	t.Helper()
+	var result error
+	resolve := nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		ctx := graphql.WithFieldContext(r.Context(), &graphql.FieldContext{
+			Object: "Mutation",
+			Field:  graphql.CollectedField{Field: &ast.Field{Name: mutation}},
+		})
+		admin := model.UserRoleAdmin
+		_, result = directives.NewAuthDirective().Auth(ctx, nil, func(ctx context.Context) (interface{}, error) {
+			return true, nil
+		}, &admin)
+	})
+
+	req := httptest.NewRequest("POST", "/graphql", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	middleware.AuthMiddleware(client, authService)(resolve).ServeHTTP(httptest.NewRecorder(), req)
+	return result
+}
+
+func TestMaintenance_BreakGlassFlow(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	configureTokenBudget(t, jwt.DefaultOptions())
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	cfg := &configs.Config{}
+	cfg.Maintenance.Operations = []string{"suspendUser", "liftSuspension"}
+	authService := service.NewAuthService(repository.NewUserRepository(client), cfg, database.NewCacheService(rdb), &mockMailService{})
+
+	operator := createVerifiedUser(t, client, "maintenance_operator@example.com")
+	outsider := createVerifiedUser(t, client, "maintenance_outsider@example.com")
+	var admins []*ent.User
+	for _, email := range []string{"maintenance_admin1@example.com", "maintenance_admin2@example.com"} {
+		admins = append(admins, client.User.UpdateOne(createVerifiedUser(t, client, email)).SetRole(user.RoleADMIN).SaveX(ctx))
+	}
+
+	grant, err := authService.RequestMaintenanceToken(ctx, operator, []string{"suspendUser"}, "Spam wave from a compromised partner integration", nil)
+	if err != nil {
+		t.Fatalf("Failed to request a maintenance token: %v", err)
+	}
+
+	if _, err := authService.ApproveMaintenanceToken(ctx, operator, grant.ID); err != service.ErrSelfApproval {
+		t.Errorf("Expected the operator's own approval to be refused, got %v", err)
+	}
+	approved, err := authService.ApproveMaintenanceToken(ctx, admins[0], grant.ID)
+	if err != nil || approved.Status != maintenance.StatusPending {
+		t.Fatalf("Expected one approval to leave the grant pending, got %v (%v)", approved, err)
+	}
+	if _, err := authService.ApproveMaintenanceToken(ctx, admins[0], grant.ID); err != service.ErrGrantAlreadyApproved {
+		t.Errorf("Expected a second approval by the same admin to be refused, got %v", err)
+	}
+	if _, _, err := authService.IssueMaintenanceToken(ctx, operator, grant.ID); err != service.ErrGrantNotApproved {
+		t.Errorf("Expected issuing before enough approvals to fail, got %v", err)
+	}
+	approved, err = authService.ApproveMaintenanceToken(ctx, admins[1], grant.ID)
+	if err != nil || approved.Status != maintenance.StatusApproved {
+		t.Fatalf("Expected the second admin to approve the grant, got %v (%v)", approved, err)
+	}
+
+	if _, _, err := authService.IssueMaintenanceToken(ctx, outsider, grant.ID); err != service.ErrNotGrantOperator {
+		t.Errorf("Expected only the operator to issue the token, got %v", err)
+	}
+	token, issued, err := authService.IssueMaintenanceToken(ctx, operator, grant.ID)
+	if err != nil || issued.Status != maintenance.StatusIssued {
+		t.Fatalf("Failed to issue the maintenance token: %v", err)
+	}
+	if _, _, err := authService.IssueMaintenanceToken(ctx, operator, grant.ID); err != service.ErrGrantNotApproved {
+		t.Errorf("Expected a grant to mint a single token, got %v", err)
+	}
+
+	if err := maintenanceCall(t, client, authService, token, "suspendUser"); err != nil {
+		t.Errorf("Expected the granted mutation to run, got %v", err)
+	}
+	if err := maintenanceCall(t, client, authService, token, "liftSuspension"); err == nil {
+		t.Error("Expected a mutation outside the grant to be denied")
+	}
+
+	// A grant past its expiry no longer backs the token, even while the key
+	// is still around.
+	key := service.MaintenanceGrantPrefix + grant.ID
+	expired := *issued
+	expired.ExpiresAt = time.Now().Add(-time.Second)
+	raw, _ := json.Marshal(&expired)
+	rdb.Set(ctx, key, raw, time.Minute)
+	if err := maintenanceCall(t, client, authService, token, "suspendUser"); err != errors.AuthenticationRequired {
+		t.Errorf("Expected a token of an expired grant to be rejected, got %v", err)
+	}
+
+	raw, _ = json.Marshal(issued)
+	rdb.Set(ctx, key, raw, time.Minute)
+	if err := maintenanceCall(t, client, authService, token, "suspendUser"); err != nil {
+		t.Fatalf("Expected the restored grant to work again, got %v", err)
+	}
+	rdb.Del(ctx, key)
+	if err := maintenanceCall(t, client, authService, token, "suspendUser"); err != errors.AuthenticationRequired {
+		t.Errorf("Expected deleting the grant to revoke the token, got %v", err)
+	}
+}
+
+func TestMaintenance_OrganizationLimitedGrant(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cfg := &configs.Config{}
+	cfg.Maintenance.Operations = []string{"suspendUser", "setUserOrganization", "setOrganizationBranding", "forceRelogin"}
+	authService := service.NewAuthService(repository.NewUserRepository(client), cfg, database.NewCacheService(embeddedRedis(t)), &mockMailService{})
+
+	for _, slug := range []string{"acme", "globex"} {
+		if _, err := authService.SaveOrganizationBranding(ctx, repository.OrganizationBranding{Slug: slug, Name: slug}); err != nil {
+			t.Fatalf("Failed to create organization %s: %v", slug, err)
+		}
+	}
+	acme, globex, unknown := "acme", "globex", "initech"
+	operator := createVerifiedUser(t, client, "tenant_operator@example.com")
+	member := createVerifiedUser(t, client, "tenant_member@example.com")
+	if _, err := authService.AssignOrganization(ctx, member.PublicID.String(), &acme); err != nil {
+		t.Fatalf("Failed to assign the organization: %v", err)
+	}
+	outsider := createVerifiedUser(t, client, "tenant_outsider@example.com")
+
+	reason := "Tenant asked us to lock accounts taken over in a phishing wave"
+	if _, err := authService.RequestMaintenanceToken(ctx, operator, cfg.Maintenance.Operations, reason, &unknown); err != service.ErrOrganizationNotFound {
+		t.Errorf("Expected an unknown organization to be refused, got %v", err)
+	}
+	grant, err := authService.RequestMaintenanceToken(ctx, operator, cfg.Maintenance.Operations, reason, &acme)
+	if err != nil || grant.OrganizationID == nil || grant.Organization != "acme" {
+		t.Fatalf("Expected a grant limited to acme, got %+v, %v", grant, err)
+	}
+	grant.Status = maintenance.StatusIssued
+	grant.ExpiresAt = time.Now().Add(time.Minute)
+
+	ctx = auth.WithMaintenanceGrant(auth.WithUser(ctx, operator, nil), grant)
+	next := graphql.Resolver(func(ctx context.Context) (interface{}, error) {
+		return true, nil
+	})
+	call := func(directive *directives.AuthDirective, mutation string, args map[string]any) error {
+		t.Helper()
+		admin := model.UserRoleAdmin
+		_, err := directive.Auth(graphql.WithFieldContext(ctx, &graphql.FieldContext{
+			Object: "Mutation",
+			Field:  graphql.CollectedField{Field: &ast.Field{Name: mutation}},
+			Args:   args,
+		}), nil, next, &admin)
+		return err
+	}
+	directive := directives.NewAuthDirective().WithMaintenanceScope(authService)
+
+	if err := call(directive, "suspendUser", map[string]any{"userId": member.PublicID.String()}); err != nil {
+		t.Errorf("Expected a member of the organization to be reachable, got %v", err)
+	}
+	if err := call(directive, "suspendUser", map[string]any{"userId": outsider.PublicID.String()}); err == nil {
+		t.Error("Expected a user outside the organization to be out of reach")
+	}
+	if err := call(directive, "setUserOrganization", map[string]any{"userId": member.PublicID.String(), "organization": &globex}); err == nil {
+		t.Error("Expected moving a member to another organization to be denied")
+	}
+	if err := call(directive, "setOrganizationBranding", map[string]any{"input": model.OrganizationBrandingInput{Slug: "acme", Name: "Acme"}}); err != nil {
+		t.Errorf("Expected the organization's own branding to be reachable, got %v", err)
+	}
+	if err := call(directive, "setOrganizationBranding", map[string]any{"input": model.OrganizationBrandingInput{Slug: "globex", Name: "Globex"}}); err == nil {
+		t.Error("Expected another organization's branding to be out of reach")
+	}
+	if err := call(directive, "forceRelogin", map[string]any{"input": model.ForceReloginInput{}}); err == nil {
+		t.Error("Expected a call naming no user or organization to be denied")
+	}
+	if err := call(directives.NewAuthDirective(), "suspendUser", map[string]any{"userId": member.PublicID.String()}); err == nil {
+		t.Error("Expected a limited grant to be denied without a maintenance scope")
+	}
+}
@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"testing"
+
+	entmigrate "github.com/abisalde/authentication-service/internal/database/ent/migrate"
+	"github.com/abisalde/authentication-service/internal/database/migrationguard"
+)
+
+func TestMigrationGuard_Inspect(t *testing.T) {
+	opts := migrationguard.Options{InUse: migrationguard.InUseBySchema(entmigrate.Tables)}
+
+	cases := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{"add nullable column", "ALTER TABLE users ADD COLUMN nickname VARCHAR(30) NULL;", nil},
+		{"drop column in use", "ALTER TABLE `users` DROP COLUMN `email`;", []string{migrationguard.RuleDropColumn}},
+		{"drop column no longer in the schema", "ALTER TABLE users DROP COLUMN legacy_flag, DROP INDEX idx_legacy;", nil},
+		{"drop table in use", "DROP TABLE IF EXISTS login_attempts;", []string{migrationguard.RuleDropTable}},
+		{"locking index on users", "CREATE INDEX idx_users_created_at ON users(created_at);", []string{migrationguard.RuleLockingIndex}},
+		{"online index on users", "ALTER TABLE users ADD INDEX idx_users_created_at (created_at), ALGORITHM=INPLACE, LOCK=NONE;", nil},
+		{"index on another table", "CREATE INDEX idx_attempts_ip ON login_attempts(ip);", nil},
+		{"rename column", "ALTER TABLE login_attempts RENAME COLUMN ip TO ip_address;", []string{migrationguard.RuleRenameColumn}},
+		{"rebuild users", "ALTER TABLE users MODIFY COLUMN first_name VARCHAR(100) NOT NULL;", []string{migrationguard.RuleTableRebuild}},
+		{
+			"reviewed statement",
+			"-- guard:allow locking-index\nCREATE UNIQUE INDEX idx_users_public_id ON users(public_id);\nCREATE INDEX idx_users_role ON users(role);",
+			[]string{migrationguard.RuleLockingIndex},
+		},
+	}
+
+	for _, tc := range cases {
+		got := migrationguard.Inspect(tc.script, opts)
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i].Rule != tc.want[i] {
+				t.Errorf("%s: expected rule %s, got %s", tc.name, tc.want[i], got[i].Rule)
+			}
+		}
+	}
+
+	if err := migrationguard.Check("ALTER TABLE users DROP COLUMN email;", migrationguard.Options{}); err == nil {
+		t.Error("Expected Check to block dropping a column without schema information")
+	}
+}
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/abisalde/authentication-service/pkg/session"
+)
+
+// fakeAuthEndpoint answers login and refreshToken like the GraphQL API, minting
+// real tokens so the client reads their expiry. Refreshes fail once the
+// refresh token is rejected.
+func fakeAuthEndpoint(t *testing.T, accessTTL time.Duration, refreshes *int32, rejectRefresh *atomic.Bool) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query string `json:"query"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		device := jwt.NewDeviceClaim(r.Header.Get(session.DeviceIDHeader), "")
+		token, err := jwt.GenerateTokenWithDevice("user-public-id", jwt.TokenTypeAccess, accessTTL, device)
+		if err != nil {
+			t.Errorf("Failed to mint token: %v", err)
+		}
+
+		var resp map[string]any
+		switch {
+		case strings.Contains(req.Query, "login("):
+			resp = map[string]any{"data": map[string]any{"login": map[string]any{
+				"token": token, "refreshToken": "opaque-refresh", "publicId": "user-public-id",
+			}}}
+		case strings.Contains(req.Query, "refreshToken("):
+			atomic.AddInt32(refreshes, 1)
+			if rejectRefresh.Load() {
+				resp = map[string]any{"errors": []any{map[string]any{
+					"message": "Invalid refresh token", "extensions": map[string]any{"code": "REFRESH_TOKEN"},
+				}}}
+			} else {
+				resp = map[string]any{"data": map[string]any{"refreshToken": map[string]any{"token": token}}}
+			}
+		default:
+			resp = map[string]any{"data": map[string]any{"logout": true}}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func TestMobileSession_RefreshLifecycle(t *testing.T) {
+	t.Setenv("JWT_SECRET", "mobile-session-test-secret")
+
+	var refreshes int32
+	var rejectRefresh atomic.Bool
+	// Shorter than the refresh skew, so every AccessToken call refreshes.
+	srv := fakeAuthEndpoint(t, 30*time.Second, &refreshes, &rejectRefresh)
+	defer srv.Close()
+
+	ctx := context.Background()
+	store := session.NewMemoryStore()
+	client := session.NewClient(srv.URL, "install-1", store)
+
+	tokens, err := client.Login(ctx, "mobile@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if tokens.UserID != "user-public-id" || tokens.RefreshToken != "opaque-refresh" {
+		t.Fatalf("Unexpected tokens: %+v", tokens)
+	}
+
+	access, err := client.AccessToken(ctx)
+	if err != nil || refreshes != 1 {
+		t.Fatalf("Expected one refresh, got %d (%v)", refreshes, err)
+	}
+
+	if _, err := session.ValidateAccessToken(access, "install-1"); err != nil {
+		t.Errorf("Expected the refreshed token to validate for its device, got %v", err)
+	}
+	if _, err := session.ValidateAccessToken(access, "install-2"); err != session.ErrDeviceMismatch {
+		t.Errorf("Expected ErrDeviceMismatch for another device, got %v", err)
+	}
+
+	rejectRefresh.Store(true)
+	if _, err := client.AccessToken(ctx); err != session.ErrSessionExpired {
+		t.Fatalf("Expected ErrSessionExpired, got %v", err)
+	}
+	if _, err := store.Load(ctx); err != session.ErrNoTokens {
+		t.Errorf("Expected the store cleared after a rejected refresh, got %v", err)
+	}
+}
+
+func TestMobileSession_ReusesValidAccessToken(t *testing.T) {
+	t.Setenv("JWT_SECRET", "mobile-session-test-secret")
+
+	var refreshes int32
+	var rejectRefresh atomic.Bool
+	srv := fakeAuthEndpoint(t, time.Hour, &refreshes, &rejectRefresh)
+	defer srv.Close()
+
+	ctx := context.Background()
+	client := session.NewClient(srv.URL, "install-1", session.NewMemoryStore())
+
+	tokens, err := client.Login(ctx, "mobile@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+
+	access, err := client.AccessToken(ctx)
+	if err != nil || access != tokens.AccessToken || refreshes != 0 {
+		t.Fatalf("Expected the stored token without a refresh, got %d refreshes (%v)", refreshes, err)
+	}
+
+	if err := client.Logout(ctx); err != nil {
+		t.Fatalf("Logout failed: %v", err)
+	}
+	if _, err := client.AccessToken(ctx); err != session.ErrNoTokens {
+		t.Errorf("Expected ErrNoTokens after logout, got %v", err)
+	}
+}
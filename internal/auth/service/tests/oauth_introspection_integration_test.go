@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/abisalde/authentication-service/internal/auth/automation"
+	"github.com/abisalde/authentication-service/internal/auth/cookies"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/handlers"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestOAuthIntrospection_ClientCredentials(t *testing.T) {
+	configureTokenBudget(t, jwt.DefaultOptions())
+
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := &configs.Config{}
+	cfg.Introspection.Clients = map[string]string{"orders-api": "s3cret:with/symbols"}
+	authService := service.NewAuthService(repository.NewUserRepository(client), cfg, redisCache, &mockMailService{})
+	user := createVerifiedUser(t, client, "oauth_introspect@example.com")
+
+	tokens, err := cookies.GenerateLoginTokenPair(context.Background(), user.PublicID.String(), nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to issue tokens: %v", err)
+	}
+
+	app := fiber.New()
+	app.Post("/oauth/introspect", handlers.OAuthIntrospectionHandler(authService, automation.NewIntrospectionRegistry(cfg)))
+
+	post := func(form url.Values, basicID, basicSecret string) (int, string, handlers.Introspection) {
+		t.Helper()
+		req := httptest.NewRequest("POST", "/oauth/introspect", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if basicID != "" {
+			req.SetBasicAuth(url.QueryEscape(basicID), url.QueryEscape(basicSecret))
+		}
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("Introspection request failed: %v", err)
+		}
+		defer resp.Body.Close()
+
+		var body handlers.Introspection
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, resp.Header.Get("WWW-Authenticate"), body
+	}
+
+	if status, challenge, _ := post(url.Values{"token": {tokens.AccessToken}}, "orders-api", "wrong"); status != fiber.StatusUnauthorized || challenge == "" {
+		t.Errorf("Expected 401 with a Basic challenge for a wrong secret, got %d %q", status, challenge)
+	}
+	if status, _, _ := post(url.Values{"token": {tokens.AccessToken}}, "billing-api", "s3cret:with/symbols"); status != fiber.StatusUnauthorized {
+		t.Errorf("Expected 401 for another client's secret, got %d", status)
+	}
+
+	status, _, body := post(url.Values{"token": {tokens.AccessToken}}, "orders-api", "s3cret:with/symbols")
+	if status != fiber.StatusOK || !body.Active || body.Subject != user.PublicID.String() || body.TokenType != "access" || body.ExpiresAt == 0 || body.IssuedAt == 0 {
+		t.Fatalf("Expected an active access token over Basic auth, got %d %+v", status, body)
+	}
+
+	form := url.Values{"token": {tokens.RefreshToken}, "client_id": {"orders-api"}, "client_secret": {"s3cret:with/symbols"}}
+	if status, _, body := post(form, "", ""); status != fiber.StatusOK || !body.Active || body.TokenType != "refresh" {
+		t.Errorf("Expected an active refresh token over form credentials, got %d %+v", status, body)
+	}
+
+	if _, _, body := post(url.Values{"token": {"not-a-token"}}, "orders-api", "s3cret:with/symbols"); body.Active || body.Subject != "" {
+		t.Errorf("Expected an inactive token to carry nothing but active=false, got %+v", body)
+	}
+}
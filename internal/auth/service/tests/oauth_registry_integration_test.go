@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/database/ent/user"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestOAuthRegistry_ConfiguredProviders(t *testing.T) {
+	cfg := &configs.Config{}
+	cfg.Providers.GoogleClientID, cfg.Providers.GoogleClientSecret = "google-client", "google-secret"
+	cfg.Providers.MicrosoftClientID, cfg.Providers.MicrosoftClientSecret = "ms-client", "ms-secret"
+	cfg.Providers.GitHubClientID = "github-client-without-secret"
+	cfg.OAuth.Providers = map[string]configs.OAuthProvider{
+		"zitadel": {ClientID: "z-client", ClientSecret: "z-secret"},
+		"acme":    {ClientID: "acme-client", ClientSecret: "acme-secret"},
+		"pending": {},
+		"google":  {ClientID: "shadow", ClientSecret: "shadow"},
+	}
+
+	got := service.ConfiguredOAuthProviders(cfg)
+	want := []string{"google", "microsoft", "acme", "zitadel"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestOAuthRegistry_Profile(t *testing.T) {
+	decode := func(body string) map[string]any {
+		t.Helper()
+		var fields map[string]any
+		decoder := json.NewDecoder(strings.NewReader(body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&fields); err != nil {
+			t.Fatalf("Failed to decode userinfo: %v", err)
+		}
+		return fields
+	}
+
+	oidc, err := service.OAuthProfile(decode(`{"sub":"u-1","email":"ada@example.com","name":"Ada Lovelace","given_name":"Ada","family_name":"Lovelace"}`), configs.OAuthClaims{})
+	if err != nil || oidc.ID != "u-1" || oidc.Email != "ada@example.com" || oidc.FirstName != "Ada" || oidc.LastName != "Lovelace" || oidc.Name == nil {
+		t.Errorf("Expected the standard claims by default, got %+v, %v", oidc, err)
+	}
+
+	// GitHub user IDs are large JSON numbers.
+	numeric, err := service.OAuthProfile(decode(`{"id":12345678901,"login":"ada"}`), configs.OAuthClaims{ID: "id"})
+	if err != nil || numeric.ID != "12345678901" || numeric.Name != nil {
+		t.Errorf("Expected the numeric id kept exactly, got %+v, %v", numeric, err)
+	}
+
+	if _, err := service.OAuthProfile(decode(`{"email":"ada@example.com"}`), configs.OAuthClaims{}); err == nil {
+		t.Error("Expected a profile without a subject to be rejected")
+	}
+}
+
+func TestOAuthRegistry_ConfiguredProviderSignIn(t *testing.T) {
+	t.Setenv("JWT_SECRET", "oauth-registry-test-secret")
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	redisCache := database.NewCacheService(embeddedRedis(t))
+
+	var verifier string
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/token":
+			_ = r.ParseForm()
+			verifier = r.PostForm.Get("code_verifier")
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "acme-access", "token_type": "Bearer"})
+		case "/userinfo":
+			if r.Header.Get("Authorization") != "Bearer acme-access" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"user_id":4242,"mail":"registry@example.com","first":"Ada","last":"Lovelace"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer idp.Close()
+
+	cfg := &configs.Config{}
+	cfg.OAuth.Providers = map[string]configs.OAuthProvider{
+		"acme": {
+			AuthURL:      idp.URL + "/authorize",
+			TokenURL:     idp.URL + "/token",
+			UserInfoURL:  idp.URL + "/userinfo",
+			Scopes:       []string{"profile"},
+			PKCE:         true,
+			Claims:       configs.OAuthClaims{ID: "user_id", Email: "mail", FirstName: "first", LastName: "last"},
+			ClientID:     "acme-client",
+			ClientSecret: "acme-secret",
+		},
+	}
+	authService := service.NewAuthService(repository.NewUserRepository(client), cfg, redisCache, &mockMailService{})
+	oauthService := service.NewOAuthService(authService)
+
+	ctx := context.Background()
+	if _, _, err := oauthService.GetAuthPKCEURL(ctx, "unknown", model.OAuthPlatformWeb, "state-unknown", model.PasswordLessModeRegister); err == nil {
+		t.Error("Expected an unregistered provider to be refused")
+	}
+
+	authURL, _, err := oauthService.GetAuthPKCEURL(ctx, "acme", model.OAuthPlatformWeb, "state-acme", model.PasswordLessModeRegister)
+	if err != nil {
+		t.Fatalf("Failed to start the authorization: %v", err)
+	}
+	parsed, _ := url.Parse(authURL)
+	query := parsed.Query()
+	if !strings.HasPrefix(authURL, idp.URL+"/authorize?") || query.Get("code_challenge") == "" || !strings.HasSuffix(query.Get("redirect_uri"), "/service/oauth/acme/callback") {
+		t.Fatalf("Expected a PKCE authorization at the configured endpoint, got %s", authURL)
+	}
+
+	app := fiber.New()
+	app.Get("/service/oauth/:provider/callback", func(c *fiber.Ctx) error {
+		_, _, _, err := oauthService.HandleCallBack(c, c.Params("provider"), string(model.OAuthPlatformWeb), string(model.PasswordLessModeRegister), c.Query("code"), "state-acme")
+		return err
+	})
+	resp, err := app.Test(httptest.NewRequest("GET", "/service/oauth/acme/callback?code=acme-code", nil), -1)
+	if err != nil {
+		t.Fatalf("Callback failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if verifier == "" {
+		t.Error("Expected the PKCE verifier to be sent with the code exchange")
+	}
+	created, err := client.User.Query().Where(user.EmailEQ("registry@example.com")).Only(ctx)
+	if err != nil {
+		t.Fatalf("Expected the configured provider to register the user: %v", err)
+	}
+	if created.Provider != user.ProviderOAUTH || created.OauthID != "acme:4242" || created.FirstName != "Ada" {
+		t.Errorf("Expected an OAUTH account namespaced by provider, got %s %q %q", created.Provider, created.OauthID, created.FirstName)
+	}
+}
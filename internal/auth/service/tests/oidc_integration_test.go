@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/handlers"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestOIDC_IDTokenAndDiscovery(t *testing.T) {
+	configureTokenBudget(t, jwt.DefaultOptions())
+
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cfg := &configs.Config{}
+	cfg.OIDC.Enabled = true
+	cfg.OIDC.Issuer = "https://auth.example.com/"
+	cfg.OIDC.ClientID = "first-party"
+	cfg.OIDC.AuthorizationEndpoint = "https://app.example.com/login"
+	authService := service.NewAuthService(repository.NewUserRepository(client), cfg, redisCache, &mockMailService{})
+
+	user := createVerifiedUser(t, client, "oidc@example.com")
+	user, err := client.User.UpdateOne(user).SetFirstName("Ada").SetLastName("Lovelace").Save(ctx)
+	if err != nil {
+		t.Fatalf("Failed to set the user's name: %v", err)
+	}
+
+	idToken, err := authService.IssueIDToken(ctx, user, "n-0S6_WzA2Mj")
+	if err != nil || idToken == "" {
+		t.Fatalf("Failed to issue ID token: %q %v", idToken, err)
+	}
+
+	claims, err := jwt.ValidateIDToken(idToken, "https://auth.example.com", "first-party")
+	if err != nil {
+		t.Fatalf("Failed to validate ID token: %v", err)
+	}
+	if claims.Subject != user.PublicID.String() || claims.Email != "oidc@example.com" || !claims.EmailVerified ||
+		claims.Name != "Ada Lovelace" || claims.Nonce != "n-0S6_WzA2Mj" || claims.AuthTime == nil {
+		t.Errorf("Unexpected ID token claims: %+v", claims)
+	}
+	if _, err := jwt.ValidateIDToken(idToken, "https://auth.example.com", "another-app"); err == nil {
+		t.Error("Expected an ID token for another audience to be rejected")
+	}
+	if _, err := jwt.ValidateToken(idToken); err == nil {
+		t.Error("Expected an ID token to be rejected as an access token")
+	}
+
+	app := fiber.New()
+	app.Get("/.well-known/openid-configuration", handlers.OpenIDConfigurationHandler(cfg))
+	resp, err := app.Test(httptest.NewRequest("GET", "/.well-known/openid-configuration", nil), -1)
+	if err != nil {
+		t.Fatalf("Discovery request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var discovery handlers.OpenIDConfiguration
+	if err := json.NewDecoder(resp.Body).Decode(&discovery); err != nil {
+		t.Fatalf("Failed to decode discovery document: %v", err)
+	}
+	if discovery.Issuer != claims.Issuer || discovery.JWKSURI != "https://auth.example.com/.well-known/jwks.json" ||
+		discovery.AuthorizationEndpoint != "https://app.example.com/login" || len(discovery.IDTokenSigningAlgValuesSupported) != 1 || discovery.IDTokenSigningAlgValuesSupported[0] != "HS256" {
+		t.Errorf("Unexpected discovery document: %+v", discovery)
+	}
+
+	cfg.OIDC.Enabled = false
+	if idToken, err := authService.IssueIDToken(ctx, user, ""); err != nil || idToken != "" {
+		t.Errorf("Expected no ID token with OIDC disabled, got %q %v", idToken, err)
+	}
+}
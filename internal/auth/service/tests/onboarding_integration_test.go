@@ -0,0 +1,164 @@
+package tests
+
+import (
+	"context"
+	nethttp "net/http"
+	"strings"
+	"testing"
+
+	gqlclient "github.com/99designs/gqlgen/client"
+	gqlhandler "github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/onboarding"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/graph"
+	"github.com/abisalde/authentication-service/internal/graph/directives"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/graph/resolvers"
+)
+
+func setupOnboardingAuthService(t *testing.T, requiredSteps ...string) (*service.AuthService, *ent.Client, func()) {
+	t.Helper()
+
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	userRepo := repository.NewUserRepository(client)
+
+	cfg := &configs.Config{}
+	cfg.Onboarding.Enforce = true
+	cfg.Onboarding.RequiredSteps = requiredSteps
+
+	authService := service.NewAuthService(userRepo, cfg, redisCache, &mockMailService{})
+	return authService, client, cleanup
+}
+
+func createVerifiedUser(t *testing.T, client *ent.Client, email string) *ent.User {
+	t.Helper()
+
+	user, err := client.User.Create().
+		SetEmail(email).
+		SetIsEmailVerified(true).
+		Save(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	return user
+}
+
+func TestOnboarding_StateMachineProgression(t *testing.T) {
+	authService, client, cleanup := setupOnboardingAuthService(t, "VERIFY_EMAIL", "COMPLETE_PROFILE", "ACCEPT_TERMS")
+	defer cleanup()
+
+	ctx := context.Background()
+
+	created := createVerifiedUser(t, client, "onboarding_flow@example.com")
+
+	if step := authService.OnboardingPolicy().NextStep(created); step != onboarding.StepCompleteProfile {
+		t.Fatalf("Expected next step %s after verification, got %s", onboarding.StepCompleteProfile, step)
+	}
+
+	updated, err := authService.UpdateUserProfile(ctx, created.ID, model.UpdateProfileInput{
+		FirstName: "Ada",
+		LastName:  "Lovelace",
+	})
+	if err != nil {
+		t.Fatalf("Failed to update profile: %v", err)
+	}
+	if string(updated.OnboardingStep) != string(onboarding.StepAcceptTerms) {
+		t.Errorf("Expected stored step %s, got %s", onboarding.StepAcceptTerms, updated.OnboardingStep)
+	}
+
+	accepted, err := authService.AcceptTerms(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("Failed to accept terms: %v", err)
+	}
+	if string(accepted.OnboardingStep) != string(onboarding.StepDone) {
+		t.Errorf("Expected stored step %s, got %s", onboarding.StepDone, accepted.OnboardingStep)
+	}
+
+	status := authService.GetOnboardingStatus(accepted)
+	if !status.IsComplete || len(status.CompletedSteps) != 3 {
+		t.Errorf("Expected complete onboarding with 3 steps, got %+v", status)
+	}
+}
+
+func TestOnboarding_SkipsStepsNotRequired(t *testing.T) {
+	authService, client, cleanup := setupOnboardingAuthService(t, "ACCEPT_TERMS")
+	defer cleanup()
+
+	ctx := context.Background()
+
+	created := createVerifiedUser(t, client, "onboarding_terms_only@example.com")
+
+	if step := authService.OnboardingPolicy().NextStep(created); step != onboarding.StepAcceptTerms {
+		t.Fatalf("Expected profile step to be skipped, got %s", step)
+	}
+
+	accepted, err := authService.AcceptTerms(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("Failed to accept terms: %v", err)
+	}
+
+	if !authService.OnboardingPolicy().IsComplete(accepted) {
+		t.Errorf("Expected onboarding to be complete without a profile")
+	}
+}
+
+func TestOnboarding_GatesAppFieldsUntilComplete(t *testing.T) {
+	authService, client, cleanup := setupOnboardingAuthService(t, "VERIFY_EMAIL", "COMPLETE_PROFILE", "ACCEPT_TERMS")
+	defer cleanup()
+
+	onboarded := directives.NewOnboardingDirective(authService.OnboardingPolicy())
+	schema := graph.NewExecutableSchema(graph.Config{
+		Resolvers: resolvers.NewResolver(client, authService, service.NewOAuthService(authService), nil),
+		Directives: graph.DirectiveRoot{
+			Auth:      directives.NewAuthDirective().Auth,
+			Onboarded: onboarded.Onboarded,
+		},
+	})
+	srv := gqlhandler.New(schema)
+	srv.AddTransport(transport.POST{})
+
+	user := createVerifiedUser(t, client, "onboarding_gate@example.com")
+	as := func(u *ent.User) *gqlclient.Client {
+		return gqlclient.New(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			srv.ServeHTTP(w, r.WithContext(auth.WithUser(r.Context(), u, nil)))
+		}))
+	}
+
+	var status struct {
+		OnboardingStatus struct{ NextStep string }
+	}
+	if err := as(user).Post(`{ onboardingStatus { nextStep } }`, &status); err != nil || status.OnboardingStatus.NextStep != string(onboarding.StepCompleteProfile) {
+		t.Fatalf("Expected onboardingStatus to stay open, got %+v (%v)", status, err)
+	}
+
+	// The onboarding screens read the profile to prefill the steps left.
+	var profile struct {
+		Profile struct{ Email string }
+	}
+	if err := as(user).Post(`{ profile { email } }`, &profile); err != nil || profile.Profile.Email != user.Email {
+		t.Fatalf("Expected an unfinished user to read the profile, got %+v (%v)", profile, err)
+	}
+	var activity map[string]interface{}
+	err := as(user).Post(`{ loginActivity { edges { cursor } } }`, &activity)
+	if err == nil || !strings.Contains(err.Error(), "Complete your onboarding") {
+		t.Errorf("Expected an unfinished user to be refused the login activity, got %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := authService.UpdateUserProfile(ctx, user.ID, model.UpdateProfileInput{FirstName: "Ada", LastName: "Lovelace"}); err != nil {
+		t.Fatalf("Failed to update profile: %v", err)
+	}
+	done, err := authService.AcceptTerms(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("Failed to accept terms: %v", err)
+	}
+	if err := as(done).Post(`{ loginActivity { edges { cursor } } }`, &activity); err != nil {
+		t.Errorf("Expected an onboarded user to read the login activity, got %v", err)
+	}
+}
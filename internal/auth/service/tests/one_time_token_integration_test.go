@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/pkg/verification"
+)
+
+// tamperPayload swaps the subject inside a token while keeping its original
+// signature.
+func tamperPayload(t *testing.T, token, from, to string) string {
+	t.Helper()
+	encoded, signature, _ := strings.Cut(token, ".")
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("Failed to decode the token payload: %v", err)
+	}
+	forged := strings.Replace(string(raw), from, to, 1)
+	return base64.RawURLEncoding.EncodeToString([]byte(forged)) + "." + signature
+}
+
+func TestOneTimeTokens_SingleUse(t *testing.T) {
+	ctx := context.Background()
+	tokens := verification.NewOneTimeTokens(embeddedRedis(t))
+
+	token, err := tokens.Issue(ctx, verification.PurposeUnsubscribe, "user-1", map[string]string{"list": "digest"}, time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to issue token: %v", err)
+	}
+
+	payload, err := tokens.Consume(ctx, token, verification.PurposeUnsubscribe)
+	if err != nil {
+		t.Fatalf("Expected the first use to succeed, got %v", err)
+	}
+	if payload.Subject != "user-1" || payload.Data["list"] != "digest" {
+		t.Errorf("Expected the issued payload back, got %+v", payload)
+	}
+
+	if _, err := tokens.Consume(ctx, token, verification.PurposeUnsubscribe); err != verification.ErrTokenConsumed {
+		t.Errorf("Expected a replay to get ErrTokenConsumed, got %v", err)
+	}
+}
+
+func TestOneTimeTokens_Rejections(t *testing.T) {
+	ctx := context.Background()
+	tokens := verification.NewOneTimeTokens(embeddedRedis(t))
+
+	issue := func(purpose verification.Purpose, ttl time.Duration) string {
+		token, err := tokens.Issue(ctx, purpose, "user-1", nil, ttl)
+		if err != nil {
+			t.Fatalf("Failed to issue token: %v", err)
+		}
+		return token
+	}
+
+	appeal := issue(verification.PurposeAppeal, time.Hour)
+	if _, err := tokens.Consume(ctx, appeal, verification.PurposeSessionRevoke); err != verification.ErrPurposeInvalid {
+		t.Errorf("Expected a token used for another purpose to get ErrPurposeInvalid, got %v", err)
+	}
+	if _, err := tokens.Consume(ctx, appeal, verification.PurposeAppeal); err != nil {
+		t.Errorf("Expected the misused token to stay valid for its own purpose, got %v", err)
+	}
+
+	unsubscribe := issue(verification.PurposeUnsubscribe, time.Hour)
+	payload, signature, _ := strings.Cut(unsubscribe, ".")
+	flipped := []byte(signature)
+	flipped[0] ^= 'A' ^ 'B'
+	for name, forged := range map[string]string{
+		"tampered signature": payload + "." + string(flipped),
+		"tampered payload":   tamperPayload(t, unsubscribe, "user-1", "user-2"),
+		"missing signature":  payload,
+	} {
+		if _, err := tokens.Consume(ctx, forged, verification.PurposeUnsubscribe); err != verification.ErrInvalidToken {
+			t.Errorf("%s: expected ErrInvalidToken, got %v", name, err)
+		}
+	}
+
+	revoked := issue(verification.PurposeSessionRevoke, time.Hour)
+	if err := tokens.Revoke(ctx, revoked, verification.PurposeSessionRevoke); err != nil {
+		t.Fatalf("Failed to revoke token: %v", err)
+	}
+	if _, err := tokens.Consume(ctx, revoked, verification.PurposeSessionRevoke); err != verification.ErrTokenConsumed {
+		t.Errorf("Expected a revoked token to be unusable, got %v", err)
+	}
+
+	expiring := issue(verification.PurposeMagicLink, time.Second)
+	time.Sleep(2100 * time.Millisecond)
+	if _, err := tokens.Consume(ctx, expiring, verification.PurposeMagicLink); err != verification.ErrTokenExpired {
+		t.Errorf("Expected an expired token to get ErrTokenExpired, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/automation"
+	"github.com/abisalde/authentication-service/internal/auth/cookies"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/handlers"
+	"github.com/abisalde/authentication-service/internal/middleware"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/gofiber/fiber/v2"
+)
+
+type memoryClaimsStore struct {
+	mu     sync.Mutex
+	claims map[string]jwt.Claims
+}
+
+func (s *memoryClaimsStore) Save(ctx context.Context, key string, claims *jwt.Claims, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.claims[key] = *claims
+	return nil
+}
+
+func (s *memoryClaimsStore) Load(ctx context.Context, key string) (*jwt.Claims, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	claims, ok := s.claims[key]
+	if !ok {
+		return nil, jwt.ErrClaimsStoreNotConfigured
+	}
+	return &claims, nil
+}
+
+func (s *memoryClaimsStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.claims, key)
+	return nil
+}
+
+func introspect(t *testing.T, app *fiber.App, key, token string) (int, handlers.Introspection) {
+	t.Helper()
+
+	req := httptest.NewRequest("POST", "/introspect", strings.NewReader(url.Values{"token": {token}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if key != "" {
+		req.Header.Set(automation.DefaultHeader, key)
+	}
+
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("Introspection request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body handlers.Introspection
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+	return resp.StatusCode, body
+}
+
+func TestOpaqueTokens_IntrospectAndRevoke(t *testing.T) {
+	opts := jwt.DefaultOptions()
+	opts.Format = jwt.FormatOpaque
+	configureTokenBudget(t, opts)
+	jwt.SetClaimsStore(&memoryClaimsStore{claims: make(map[string]jwt.Claims)})
+	t.Cleanup(func() { jwt.SetClaimsStore(nil) })
+
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := &configs.Config{}
+	cfg.Automation.APIKeys = map[string]string{"orders-api": "introspect-secret"}
+	authService := service.NewAuthService(repository.NewUserRepository(client), cfg, redisCache, &mockMailService{})
+	user := createVerifiedUser(t, client, "opaque@example.com")
+
+	token, err := cookies.GenerateAccessToken(context.Background(), user.PublicID.String(), jwt.NewDeviceClaim("install-1", ""), nil)
+	if err != nil {
+		t.Fatalf("Failed to issue opaque token: %v", err)
+	}
+	if !jwt.IsOpaqueToken(token) || strings.Count(token, ".") != 0 {
+		t.Fatalf("Expected an opaque token, got %q", token)
+	}
+
+	app := fiber.New()
+	app.Use(middleware.AutomationMiddleware(automation.NewRegistry(cfg)))
+	app.Post("/introspect", handlers.IntrospectionHandler(authService))
+
+	if status, _ := introspect(t, app, "", token); status != fiber.StatusUnauthorized {
+		t.Errorf("Expected 401 without an automation key, got %d", status)
+	}
+
+	status, body := introspect(t, app, "introspect-secret", token)
+	if status != fiber.StatusOK || !body.Active || body.Subject != user.PublicID.String() || body.TokenType != "access" || body.DeviceID == "" {
+		t.Fatalf("Expected an active access token, got %d %+v", status, body)
+	}
+
+	authService.RevokeAccessToken(context.Background(), token)
+
+	if _, err := jwt.ValidateToken(token); err == nil {
+		t.Errorf("Expected a revoked opaque token to fail validation")
+	}
+	if _, body := introspect(t, app, "introspect-secret", token); body.Active {
+		t.Errorf("Expected a revoked opaque token to be inactive, got %+v", body)
+	}
+}
@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/cookies"
+	"github.com/abisalde/authentication-service/internal/auth/probation"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestProbation_PolicyAppliesToNewAccounts(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	policy := probation.Policy{Window: 72 * time.Hour, RateLimitDivisor: 2, RequireVerifiedEmail: true, MaxSessions: 2}
+
+	fresh := createVerifiedUser(t, client, "fresh@example.com")
+	veteran := client.User.Create().
+		SetEmail("veteran@example.com").
+		SetCreatedAt(time.Now().Add(-30 * 24 * time.Hour)).
+		SaveX(ctx)
+
+	if !policy.Applies(fresh) || policy.Applies(veteran) || policy.Applies(nil) {
+		t.Fatalf("Expected probation to apply to the new account only")
+	}
+
+	if got := policy.RateLimit(fresh, 5); got != 2 {
+		t.Errorf("Expected the limit halved to 2, got %d", got)
+	}
+	if got := policy.RateLimit(fresh, 1); got != 1 {
+		t.Errorf("Expected the limit to stay at least 1, got %d", got)
+	}
+	if got := policy.RateLimit(veteran, 5); got != 5 {
+		t.Errorf("Expected an established account to keep its limit, got %d", got)
+	}
+
+	if policy.BlocksUnverified(fresh) || policy.BlocksUnverified(veteran) {
+		t.Errorf("Expected verified and established accounts to receive tokens")
+	}
+	if !policy.LimitsSessions(fresh) || policy.LimitsSessions(veteran) {
+		t.Errorf("Expected sessions capped for the new account only")
+	}
+
+	if (probation.Policy{}).Applies(fresh) {
+		t.Errorf("Expected a zero window to disable probation")
+	}
+}
+
+func TestProbation_RefusesTokensForUnverifiedNewAccounts(t *testing.T) {
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cfg := &configs.Config{}
+	cfg.Probation.Window = time.Hour
+	cfg.Probation.RequireVerifiedEmail = true
+
+	authService := service.NewAuthService(repository.NewUserRepository(client), cfg, redisCache, &mockMailService{})
+
+	unverified := client.User.Create().
+		SetEmail("unverified@example.com").
+		SetIsEmailVerified(false).
+		SaveX(ctx)
+
+	if err := authService.AdmitProbationLogin(ctx, unverified, nil); err != service.ErrEmailVerificationRequired {
+		t.Fatalf("Expected ErrEmailVerificationRequired, got %v", err)
+	}
+
+	verified := createVerifiedUser(t, client, "verified@example.com")
+	if err := authService.AdmitProbationLogin(ctx, verified, nil); err != nil {
+		t.Fatalf("Expected a verified account without a session cap to be admitted, got %v", err)
+	}
+}
+
+func TestProbation_ReplacedSessionsFreeTheirSlot(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	cfg := &configs.Config{}
+	cfg.Probation.Window = time.Hour
+	cfg.Probation.MaxSessions = 2
+	authService := service.NewAuthService(repository.NewUserRepository(client), cfg, database.NewCacheService(rdb), &mockMailService{})
+
+	user := createVerifiedUser(t, client, "probation_slots@example.com")
+	key := fmt.Sprintf("%s%d", service.ProbationSessionsPrefix, user.ID)
+	laptop := jwt.NewDeviceClaim("laptop", "")
+	phone := jwt.NewDeviceClaim("phone", "")
+	tablet := jwt.NewDeviceClaim("tablet", "")
+
+	for _, device := range []*jwt.DeviceClaim{laptop, phone} {
+		if err := authService.AdmitProbationLogin(ctx, user, device); err != nil {
+			t.Fatalf("Expected the login from %s to be admitted, got %v", device.ID, err)
+		}
+	}
+
+	// The phone took over the refresh session; the laptop keeps its slot
+	// only while its access token is valid.
+	replaced, err := rdb.ZScore(ctx, key, "device:"+laptop.ID).Result()
+	if err != nil {
+		t.Fatalf("Failed to read the laptop slot: %v", err)
+	}
+	if limit := time.Now().Add(cookies.LoginAccessTokenExpiry).Unix(); int64(replaced) > limit {
+		t.Errorf("Expected the replaced session to expire with its access token by %d, got %v", limit, replaced)
+	}
+	if err := authService.AdmitProbationLogin(ctx, user, tablet); err != service.ErrSessionLimitReached {
+		t.Fatalf("Expected a third device to wait while both access tokens are valid, got %v", err)
+	}
+
+	// Once the laptop's access token has expired, its slot no longer counts.
+	rdb.ZAdd(ctx, key, redis.Z{Score: float64(time.Now().Add(-time.Second).Unix()), Member: "device:" + laptop.ID})
+	if err := authService.AdmitProbationLogin(ctx, user, tablet); err != nil {
+		t.Fatalf("Expected the third login to take the freed slot, got %v", err)
+	}
+	if sessions := rdb.ZRangeByScore(ctx, key, &redis.ZRangeBy{Min: "-inf", Max: "+inf"}).Val(); len(sessions) != 2 {
+		t.Errorf("Expected the phone and the tablet to hold the slots, got %v", sessions)
+	}
+
+	// Invalidating the refresh session revokes every session with it.
+	if err := authService.InvalidateRefreshToken(ctx, user.ID); err != nil {
+		t.Fatalf("Failed to invalidate the refresh session: %v", err)
+	}
+	if n := rdb.Exists(ctx, key).Val(); n != 0 {
+		t.Errorf("Expected the session slots to be released with the refresh session")
+	}
+}
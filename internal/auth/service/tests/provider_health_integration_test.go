@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/handlers"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestProviderHealth_FlagsDegradedProvider(t *testing.T) {
+	cfg := &configs.Config{}
+	cfg.OAuthHealth.Window = time.Minute
+	cfg.OAuthHealth.MinSamples = 4
+	cfg.OAuthHealth.FailureRate = 0.5
+	cfg.Providers.GoogleClientID = "google-client"
+	cfg.Providers.GoogleClientSecret = "google-secret"
+
+	health := service.NewProviderHealth(cfg, "google", "facebook")
+
+	health.Record("GOOGLE", 120*time.Millisecond, "")
+	health.Record("GOOGLE", 900*time.Millisecond, service.OAuthErrorExchange)
+	health.Record("GOOGLE", 5*time.Second, service.OAuthErrorTimeout)
+
+	if degraded := health.DegradedProviders(); len(degraded) != 0 {
+		t.Fatalf("Expected no degraded providers below min samples, got %v", degraded)
+	}
+
+	health.Record("GOOGLE", 800*time.Millisecond, service.OAuthErrorExchange)
+
+	status := health.Status("google")
+	if status.Attempts != 4 || status.Failures != 3 || !status.Degraded {
+		t.Fatalf("Expected google degraded after 3/4 failures, got %+v", status)
+	}
+	if status.Errors[service.OAuthErrorExchange] != 2 || status.Errors[service.OAuthErrorTimeout] != 1 {
+		t.Errorf("Unexpected error categories: %v", status.Errors)
+	}
+
+	app := fiber.New()
+	app.Get("/api/capabilities", handlers.CapabilitiesHandler(cfg, health))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/capabilities", nil))
+	if err != nil {
+		t.Fatalf("Capabilities request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var caps handlers.Capabilities
+	if err := json.NewDecoder(resp.Body).Decode(&caps); err != nil {
+		t.Fatalf("Failed to decode capabilities: %v", err)
+	}
+
+	if len(caps.DegradedProviders) != 1 || caps.DegradedProviders[0] != "google" {
+		t.Errorf("Expected google flagged as degraded, got %v", caps.DegradedProviders)
+	}
+	if len(caps.Notices) != 1 || caps.Notices[0] != "Google sign-in temporarily unavailable" {
+		t.Errorf("Unexpected notices: %v", caps.Notices)
+	}
+}
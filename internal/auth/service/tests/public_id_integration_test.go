@@ -0,0 +1,35 @@
+package tests
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestResolveUserReference_PublicAndLegacyIDs(t *testing.T) {
+	authService, client, cleanup := setupTestAuthService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	created := createVerifiedUser(t, client, "public_id@example.com")
+
+	if created.PublicID == uuid.Nil {
+		t.Fatalf("Expected a public ID to be assigned on create")
+	}
+
+	for _, ref := range []string{created.PublicID.String(), strconv.FormatInt(created.ID, 10)} {
+		user, err := authService.ResolveUserReference(ctx, ref)
+		if err != nil {
+			t.Fatalf("Failed to resolve %q: %v", ref, err)
+		}
+		if user.ID != created.ID {
+			t.Errorf("Expected user %d for %q, got %d", created.ID, ref, user.ID)
+		}
+	}
+
+	if _, err := authService.ResolveUserReference(ctx, "not-an-id"); err == nil {
+		t.Errorf("Expected an error for an invalid reference")
+	}
+}
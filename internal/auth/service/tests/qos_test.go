@@ -0,0 +1,109 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/pkg/qos"
+)
+
+func waitForQueued(t *testing.T, s *qos.Scheduler, want int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		total := 0
+		_, queued := s.Stats()
+		for _, n := range queued {
+			total += n
+		}
+		if total == want {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Expected %d queued requests", want)
+}
+
+func TestQoS_ServesValidationBeforeRegistration(t *testing.T) {
+	s := qos.New(qos.Config{MaxConcurrent: 1, MaxQueue: 2, QueueTimeout: time.Second, ShedLowestAt: 1})
+	ctx := context.Background()
+
+	release, err := s.Acquire(ctx, qos.Login)
+	if err != nil {
+		t.Fatalf("Expected a free slot, got %v", err)
+	}
+
+	order := make(chan qos.Class, 2)
+	acquire := func(class qos.Class) {
+		next, err := s.Acquire(ctx, class)
+		if err != nil {
+			t.Errorf("Expected %s to be admitted, got %v", class, err)
+			return
+		}
+		order <- class
+		next()
+	}
+
+	go acquire(qos.Registration)
+	waitForQueued(t, s, 1)
+	go acquire(qos.Validation)
+	waitForQueued(t, s, 2)
+
+	if _, err := s.Acquire(ctx, qos.Registration); !errors.Is(err, qos.ErrShed) {
+		t.Errorf("Expected registration to be shed with a full queue, got %v", err)
+	}
+
+	release()
+	if first := <-order; first != qos.Validation {
+		t.Errorf("Expected validation to get the freed slot first, got %s", first)
+	}
+	if second := <-order; second != qos.Registration {
+		t.Errorf("Expected registration to run after validation, got %s", second)
+	}
+}
+
+func TestQoS_ShedsLowestClassFirst(t *testing.T) {
+	s := qos.New(qos.Config{MaxConcurrent: 2, MaxQueue: 1, QueueTimeout: time.Second, ShedLowestAt: 0.5})
+	ctx := context.Background()
+
+	release, err := s.Acquire(ctx, qos.Validation)
+	if err != nil {
+		t.Fatalf("Expected a free slot, got %v", err)
+	}
+	defer release()
+
+	if _, err := s.Acquire(ctx, qos.Analytics); !errors.Is(err, qos.ErrShed) {
+		t.Errorf("Expected analytics to be shed above the threshold, got %v", err)
+	}
+
+	refresh, err := s.Acquire(ctx, qos.Refresh)
+	if err != nil {
+		t.Fatalf("Expected refresh to take the remaining slot, got %v", err)
+	}
+
+	registration := make(chan error, 1)
+	go func() {
+		_, err := s.Acquire(ctx, qos.Registration)
+		registration <- err
+	}()
+	waitForQueued(t, s, 1)
+
+	login := make(chan error, 1)
+	go func() {
+		next, err := s.Acquire(ctx, qos.Login)
+		if err == nil {
+			next()
+		}
+		login <- err
+	}()
+
+	if err := <-registration; !errors.Is(err, qos.ErrShed) {
+		t.Errorf("Expected the queued registration to be evicted by login, got %v", err)
+	}
+	refresh()
+	if err := <-login; err != nil {
+		t.Errorf("Expected login to get the freed slot, got %v", err)
+	}
+}
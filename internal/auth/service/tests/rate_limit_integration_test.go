@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/graph"
+	"github.com/abisalde/authentication-service/internal/graph/directives"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/middleware"
+	"github.com/abisalde/authentication-service/internal/ratelimit"
+	"github.com/gofiber/fiber/v2"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestRateLimit_CompilesSchemaPolicies(t *testing.T) {
+	rateLimit := directives.NewRateLimitDirective(ratelimit.New(nil))
+
+	schema := graph.NewExecutableSchema(graph.Config{})
+	if err := rateLimit.Compile(schema.Schema()); err != nil {
+		t.Fatalf("Failed to compile rate limits: %v", err)
+	}
+
+	policies := rateLimit.Policies()
+
+	login, ok := policies["Mutation.login"]
+	if !ok {
+		t.Fatalf("Expected a policy for Mutation.login, got %v", policies)
+	}
+	if login.Limit != 5 || login.Window != time.Hour || login.Key != model.RateLimitKeyClient || login.Algorithm != model.RateLimitAlgorithmFixedWindow {
+		t.Errorf("Unexpected login policy: %+v", login)
+	}
+
+	resend := policies["Mutation.resendVerificationCode"]
+	if resend.Key != model.RateLimitKeyEmail || resend.Algorithm != model.RateLimitAlgorithmSlidingWindow {
+		t.Errorf("Unexpected resend policy: %+v", resend)
+	}
+
+	if refresh := policies["Mutation.refreshToken"]; refresh.Window != 12*time.Hour || refresh.Burst != refresh.Limit {
+		t.Errorf("Unexpected refresh policy: %+v", refresh)
+	}
+}
+
+func TestRateLimit_RejectsInvalidPolicies(t *testing.T) {
+	cases := map[string]string{
+		`limit: 5, window: "soon"`:          "invalid window",
+		`limit: 5`:                          "window or duration is required",
+		`limit: 5, window: "500ms"`:         "at least 1s",
+		`limit: 5, window: "1m", burst: 10`: "TOKEN_BUCKET",
+		`limit: 0, window: "1m"`:            "limit must be at least 1",
+		`limit: 5, window: "1m", burst: 10, algorithm: TOKEN_BUCKET`: "",
+	}
+
+	for args, want := range cases {
+		schema := loadRateLimitSchema(t, args)
+
+		err := directives.NewRateLimitDirective(ratelimit.New(nil)).Compile(schema)
+		switch {
+		case want == "" && err != nil:
+			t.Errorf("%s: unexpected error %v", args, err)
+		case want != "" && (err == nil || !strings.Contains(err.Error(), want)):
+			t.Errorf("%s: expected error containing %q, got %v", args, want, err)
+		}
+	}
+}
+
+func TestRateLimit_HTTPAndGraphQLShareOneBudget(t *testing.T) {
+	rateLimit := directives.NewRateLimitDirective(ratelimit.New(database.NewCacheService(embeddedRedis(t))))
+	if err := rateLimit.Compile(graph.NewExecutableSchema(graph.Config{}).Schema()); err != nil {
+		t.Fatalf("Failed to compile rate limits: %v", err)
+	}
+	login := rateLimit.Policies()["Mutation.login"]
+
+	app := fiber.New(fiber.Config{ProxyHeader: fiber.HeaderXForwardedFor})
+	app.Get("/callback", middleware.RateLimitMiddleware(rateLimit.Limiter(), login), func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+	viaHTTP := func(ip string) int {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/callback", nil)
+		req.Header.Set(fiber.HeaderXForwardedFor, ip)
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("Request from %s failed: %v", ip, err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	next := graphql.Resolver(func(ctx context.Context) (interface{}, error) {
+		return true, nil
+	})
+	viaGraphQL := func(ip string) error {
+		ctx := graphql.WithFieldContext(auth.WithClientIP(context.Background(), ip), &graphql.FieldContext{
+			Object: "Mutation",
+			Field:  graphql.CollectedField{Field: &ast.Field{Name: "login"}},
+		})
+		_, err := rateLimit.RateLimit(ctx, nil, next, login.Operation, int32(login.Limit), nil, nil, nil, nil, nil)
+		return err
+	}
+
+	// Alternating endpoints spends the same budget.
+	attacker := "203.0.113.9"
+	for i := int64(0); i < login.Limit; i++ {
+		if i%2 == 0 {
+			if status := viaHTTP(attacker); status != fiber.StatusOK {
+				t.Fatalf("Expected attempt %d over HTTP to pass, got %d", i+1, status)
+			}
+		} else if err := viaGraphQL(attacker); err != nil {
+			t.Fatalf("Expected attempt %d over GraphQL to pass, got %v", i+1, err)
+		}
+	}
+	if status := viaHTTP(attacker); status != fiber.StatusTooManyRequests {
+		t.Errorf("Expected HTTP to be limited once the budget is spent, got %d", status)
+	}
+	if err := viaGraphQL(attacker); err == nil {
+		t.Error("Expected GraphQL to be limited once the budget is spent")
+	}
+
+	if status := viaHTTP("198.51.100.4"); status != fiber.StatusOK {
+		t.Errorf("Expected other clients to keep their budget, got %d", status)
+	}
+}
+
+func loadRateLimitSchema(t *testing.T, args string) *ast.Schema {
+	t.Helper()
+
+	schema, err := gqlparser.LoadSchema(&ast.Source{Input: `
+		enum RateLimitMethods { LOGIN }
+		enum RateLimitKey { CLIENT IP EMAIL GLOBAL }
+		enum RateLimitAlgorithm { FIXED_WINDOW SLIDING_WINDOW TOKEN_BUCKET }
+		directive @rateLimit(
+			operation: RateLimitMethods!
+			limit: Int!
+			duration: Int
+			window: String
+			key: RateLimitKey = CLIENT
+			burst: Int
+			algorithm: RateLimitAlgorithm = FIXED_WINDOW
+		) on FIELD_DEFINITION
+		type Query {
+			login: Boolean! @rateLimit(operation: LOGIN, ` + args + `)
+		}
+	`})
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	return schema
+}
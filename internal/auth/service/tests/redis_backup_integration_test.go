@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/redisbackup"
+)
+
+func backupKey(t *testing.T) []byte {
+	t.Helper()
+	raw := make([]byte, 32)
+	_, _ = rand.Read(raw)
+	key, err := redisbackup.ParseKey(base64.StdEncoding.EncodeToString(raw))
+	if err != nil {
+		t.Fatalf("Failed to parse backup key: %v", err)
+	}
+	return key
+}
+
+func TestRedisBackup_Encryption(t *testing.T) {
+	key := backupKey(t)
+	if _, err := redisbackup.ParseKey("c2hvcnQ="); err != redisbackup.ErrInvalidKey {
+		t.Errorf("Expected a short key to be refused, got %v", err)
+	}
+
+	// Spans several frames.
+	plain := bytes.Repeat([]byte("refresh_token:42 "), 20000)
+	var sealed bytes.Buffer
+	w, err := redisbackup.NewEncryptWriter(&sealed, key)
+	if err != nil {
+		t.Fatalf("Failed to start the backup: %v", err)
+	}
+	_, _ = w.Write(plain)
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to finish the backup: %v", err)
+	}
+	if bytes.Contains(sealed.Bytes(), []byte("refresh_token")) {
+		t.Fatal("Expected the backup to be encrypted")
+	}
+
+	open := func(data, key []byte) ([]byte, error) {
+		r, err := redisbackup.NewDecryptReader(bytes.NewReader(data), key)
+		if err != nil {
+			return nil, err
+		}
+		return io.ReadAll(r)
+	}
+
+	got, err := open(sealed.Bytes(), key)
+	if err != nil || !bytes.Equal(got, plain) {
+		t.Fatalf("Expected the backup to round trip, got %d bytes, %v", len(got), err)
+	}
+
+	if _, err := open(sealed.Bytes(), backupKey(t)); !errors.Is(err, redisbackup.ErrCorrupt) {
+		t.Errorf("Expected another key to fail, got %v", err)
+	}
+
+	tampered := bytes.Clone(sealed.Bytes())
+	tampered[len(tampered)/2] ^= 1
+	if _, err := open(tampered, key); !errors.Is(err, redisbackup.ErrCorrupt) {
+		t.Errorf("Expected a flipped bit to fail, got %v", err)
+	}
+
+	// Cut exactly after the first frame, so only the missing last frame
+	// gives the truncation away.
+	firstFrame := 8 + 12 + 5 + 64<<10 + 16
+	if _, err := open(sealed.Bytes()[:firstFrame], key); !errors.Is(err, redisbackup.ErrTruncated) {
+		t.Errorf("Expected a truncated backup to fail, got %v", err)
+	}
+
+	if _, err := open([]byte("not a backup at all"), key); err != redisbackup.ErrNotBackup {
+		t.Errorf("Expected a foreign file to be refused, got %v", err)
+	}
+}
+
+func TestRedisBackup_ExportImport(t *testing.T) {
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+
+	rdb.Set(ctx, "refresh_token:1", "token-one", time.Hour)
+	rdb.Set(ctx, "refresh_token:2", "token-two", time.Hour)
+	// The embedded store dumps string keys only, so the sorted sets of the
+	// keyspace stay out of this backup.
+	rdb.Set(ctx, "refresh_token:1:origin", "web", time.Hour)
+	rdb.Set(ctx, "username_exists:ada", "true", time.Hour)
+
+	key := backupKey(t)
+	var backup bytes.Buffer
+	stats, err := redisbackup.Export(ctx, rdb, &backup, key, redisbackup.ExportOptions{
+		Patterns: []string{"refresh_token:*"},
+	})
+	if err != nil || stats.Keys != 3 {
+		t.Fatalf("Expected 3 keys exported, got %+v, %v", stats, err)
+	}
+
+	// Redis is lost; the service already issued a new token for user 2.
+	rdb.FlushDB(ctx)
+	rdb.Set(ctx, "refresh_token:2", "token-two-reissued", time.Hour)
+
+	manifest, stats, err := redisbackup.Import(ctx, rdb, bytes.NewReader(backup.Bytes()), key, redisbackup.ImportOptions{})
+	if err != nil {
+		t.Fatalf("Failed to import: %v", err)
+	}
+	if stats.Keys != 2 || stats.Existing != 1 || manifest.Version != 1 {
+		t.Errorf("Expected 2 keys restored and 1 kept, got %+v", stats)
+	}
+
+	if got := rdb.Get(ctx, "refresh_token:1").Val(); got != "token-one" {
+		t.Errorf("Expected the refresh token restored, got %q", got)
+	}
+	if ttl := rdb.TTL(ctx, "refresh_token:1").Val(); ttl <= 0 || ttl > time.Hour {
+		t.Errorf("Expected the original expiry restored, got %s", ttl)
+	}
+	if got := rdb.Get(ctx, "refresh_token:2").Val(); got != "token-two-reissued" {
+		t.Errorf("Expected keys written after the loss to be kept, got %q", got)
+	}
+	if got := rdb.Get(ctx, "refresh_token:1:origin").Val(); got != "web" {
+		t.Errorf("Expected the refresh token origin restored, got %q", got)
+	}
+	if rdb.Exists(ctx, "username_exists:ada").Val() != 0 {
+		t.Error("Expected keyspaces outside the patterns to stay out of the backup")
+	}
+}
@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/abisalde/authentication-service/internal/auth/refreshbinding"
+	"github.com/abisalde/authentication-service/internal/configs"
+)
+
+const (
+	chromeWindows  = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36"
+	chromeWindows2 = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/127.0.0.0 Safari/537.36"
+	firefoxLinux   = "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0"
+)
+
+func TestRefreshBinding_Mismatches(t *testing.T) {
+	cfg := &configs.Config{}
+	cfg.RefreshBinding.Mode = "step_up"
+	cfg.RefreshBinding.Network = true
+	cfg.RefreshBinding.UserAgent = true
+	cfg.RefreshBinding.AllowedNetworks = []string{"198.51.100.0/24"}
+	policy := refreshbinding.NewPolicy(cfg)
+
+	issued := policy.OriginOf("203.0.113.7", chromeWindows)
+	if issued.Network != "203.0.0.0/16" || issued.UAFamily != "chrome/windows" {
+		t.Fatalf("Unexpected origin %+v", issued)
+	}
+
+	cases := []struct {
+		name      string
+		ip        string
+		userAgent string
+		want      []string
+	}{
+		{"same network, browser update", "203.0.200.1", chromeWindows2, nil},
+		{"other network", "192.0.2.10", chromeWindows, []string{refreshbinding.MismatchNetwork}},
+		{"allow-listed network", "198.51.100.20", chromeWindows, nil},
+		{"other browser", "203.0.113.7", firefoxLinux, []string{refreshbinding.MismatchUserAgent}},
+		{"unknown user agent", "203.0.113.7", "", nil},
+	}
+
+	for _, tc := range cases {
+		got := policy.Mismatches(issued, policy.OriginOf(tc.ip, tc.userAgent), tc.ip)
+		if len(got) != len(tc.want) || (len(got) > 0 && got[0] != tc.want[0]) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+
+	if v6 := policy.OriginOf("2001:db8:abcd:12::1", chromeWindows); v6.Network != "2001:db8:abcd::/48" {
+		t.Errorf("Expected an IPv6 /48, got %s", v6.Network)
+	}
+	if refreshbinding.NewPolicy(&configs.Config{}).Enabled() {
+		t.Error("Expected the binding to be off by default")
+	}
+}
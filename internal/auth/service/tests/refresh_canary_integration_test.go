@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/alerting"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/pkg/verification"
+)
+
+func TestRefreshCanary_TripRevokesAndAlerts(t *testing.T) {
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	user := createVerifiedUser(t, client, "refresh_canary@example.com")
+
+	// Pinned on purpose: changing the derivation would stop canaries in
+	// existing backups from matching.
+	canary, err := verification.HashToken("refresh-canary:" + user.PublicID.String())
+	if err != nil {
+		t.Fatalf("Failed to derive the canary: %v", err)
+	}
+
+	disabled := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, redisCache, &mockMailService{})
+	if disabled.IsRefreshCanary(user, canary) {
+		t.Error("Expected canaries to be ignored while disabled")
+	}
+
+	cfg := &configs.Config{}
+	cfg.RefreshCanary.Enabled = true
+	authService := service.NewAuthService(repository.NewUserRepository(client), cfg, redisCache, &mockMailService{})
+
+	if !authService.IsRefreshCanary(user, canary) {
+		t.Fatal("Expected the user's canary to be recognised")
+	}
+	if other := createVerifiedUser(t, client, "refresh_canary_other@example.com"); authService.IsRefreshCanary(other, canary) {
+		t.Error("Expected a canary to belong to one user only")
+	}
+	if authService.IsRefreshCanary(user, "not-the-canary") {
+		t.Error("Expected ordinary tokens not to trip the canary")
+	}
+
+	pager := &recordingNotifier{alerts: make(chan alerting.Alert, 4)}
+	authService.AlertOnSecurityEvents(alerting.NewDispatcher(time.Hour, "test",
+		alerting.Route{Notifier: pager, MinSeverity: alerting.SeverityCritical}))
+
+	ctx := context.Background()
+	authService.TripRefreshCanary(ctx, user)
+
+	alert := pager.next(t)
+	if alert.Source != "refresh_canary" || alert.Details["user"] != user.PublicID.String() {
+		t.Errorf("Expected a critical canary alert for the user, got %+v", alert)
+	}
+	if redisCache.RawClient().Ping(ctx).Err() != nil {
+		t.Log("Redis not available, skipping the revocation check")
+		return
+	}
+	if !authService.IsTokenRevokedForUser(ctx, user.ID, time.Now().Add(-time.Minute)) {
+		t.Error("Expected every token of the user to be revoked")
+	}
+}
@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/handler/http"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+func TestRelogin_CutoffSeparatesTokensWithinTheSecond(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(rdb), &mockMailService{})
+	user := createVerifiedUser(t, client, "relogin_cutoff@example.com")
+
+	revokedAt := time.Now().Truncate(time.Second).Add(500 * time.Millisecond)
+	if err := authService.RevokeUserTokens(ctx, []int64{user.ID}, revokedAt, model.RevocationReasonForcedRelogin); err != nil {
+		t.Fatalf("Failed to revoke the tokens: %v", err)
+	}
+
+	if !authService.IsTokenRevokedForUser(ctx, user.ID, revokedAt.Add(-time.Millisecond)) {
+		t.Error("Expected a token issued just before the cutoff to be revoked")
+	}
+	if authService.IsTokenRevokedForUser(ctx, user.ID, revokedAt) {
+		t.Error("Expected a token issued at the cutoff to stay valid")
+	}
+	if authService.IsTokenRevokedForUser(ctx, user.ID, revokedAt.Add(time.Millisecond)) {
+		t.Error("Expected a token issued just after the cutoff to stay valid")
+	}
+
+	// A cutoff recorded in seconds still revokes what predates it.
+	rdb.Set(ctx, fmt.Sprintf("%s%d", service.TokenRevokedBeforePrefix, user.ID), revokedAt.Unix(), time.Hour)
+	if !authService.IsTokenRevokedForUser(ctx, user.ID, revokedAt.Add(-time.Minute)) {
+		t.Error("Expected a cutoff in seconds to revoke earlier tokens")
+	}
+	if authService.IsTokenRevokedForUser(ctx, user.ID, revokedAt.Add(time.Minute)) {
+		t.Error("Expected a cutoff in seconds to leave later tokens valid")
+	}
+}
+
+func TestRelogin_CampaignLookupSeparatesMissingFromFailing(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(rdb), &mockMailService{})
+	users := http.NewUsersHandler(authService)
+
+	campaign, err := users.GetReloginCampaign(ctx, "unknown-campaign")
+	if campaign != nil || err != nil {
+		t.Fatalf("Expected an unknown campaign to resolve to null, got %v (%v)", campaign, err)
+	}
+
+	rdb.Set(ctx, service.ReloginCampaignPrefix+"corrupt", "{not json", time.Hour)
+	campaign, err = users.GetReloginCampaign(ctx, "corrupt")
+	if campaign != nil || err != errors.ErrSomethingWentWrong {
+		t.Errorf("Expected an unreadable campaign to fail, got %v (%v)", campaign, err)
+	}
+
+	_ = rdb.Close()
+	campaign, err = users.GetReloginCampaign(ctx, "unknown-campaign")
+	if campaign != nil || err != errors.ErrSomethingWentWrong {
+		t.Errorf("Expected a failing store not to read as a missing campaign, got %v (%v)", campaign, err)
+	}
+}
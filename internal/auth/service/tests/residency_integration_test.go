@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/residency"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/pkg/idgen"
+)
+
+func residencyConfig() *configs.Config {
+	cfg := &configs.Config{}
+	cfg.Residency.Region = "eu"
+	cfg.Residency.Default = "us"
+	cfg.Residency.Countries = map[string]string{"de": "EU", "FR": "eu"}
+	cfg.Residency.Enforce = true
+	return cfg
+}
+
+func TestResidency_RepositoryKeepsForeignResidentsOut(t *testing.T) {
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := residencyConfig()
+	policy := residency.NewPolicy(cfg)
+	router := residency.StaticRouter{"eu": {DB: client}}
+
+	userRepo, err := repository.NewRegionalUserRepository(router, policy, idgen.Database{})
+	if err != nil {
+		t.Fatalf("Failed to create regional repository: %v", err)
+	}
+	if _, err := repository.NewRegionalUserRepository(router, residency.NewPolicy(&configs.Config{}), idgen.Database{}); !errors.Is(err, residency.ErrNoStores) {
+		t.Errorf("Expected ErrNoStores for a region without stores, got %v", err)
+	}
+
+	ctx := context.Background()
+	resident, err := userRepo.CreateNewUser(ctx, &model.RegisterVerifiedUser{Email: "residency_de@example.com", Country: "DE", IsEmailVerified: true})
+	if err != nil {
+		t.Fatalf("Failed to create EU resident: %v", err)
+	}
+	if resident.Residency != "eu" {
+		t.Errorf("Expected residency eu, got %q", resident.Residency)
+	}
+
+	if _, err := userRepo.CreateNewUser(ctx, &model.RegisterVerifiedUser{Email: "residency_us@example.com", Country: "US", IsEmailVerified: true}); !errors.Is(err, residency.ErrForeignResident) {
+		t.Errorf("Expected ErrForeignResident when creating a US resident, got %v", err)
+	}
+
+	foreign := client.User.Create().SetEmail("residency_foreign@example.com").SetResidency("us").SaveX(ctx)
+	if _, err := userRepo.GetByEmail(ctx, foreign.Email); !errors.Is(err, residency.ErrForeignResident) {
+		t.Errorf("Expected ErrForeignResident reading a US resident, got %v", err)
+	}
+	if _, err := userRepo.GetByID(ctx, resident.ID); err != nil {
+		t.Errorf("Expected the EU resident to be readable, got %v", err)
+	}
+
+	authService := service.NewAuthService(userRepo, cfg, redisCache, &mockMailService{})
+	if _, err := authService.StoreRefreshToken(ctx, foreign, "refresh-token"); !errors.Is(err, residency.ErrForeignResident) {
+		t.Errorf("Expected the session store to refuse a US resident, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/events"
+	"github.com/abisalde/authentication-service/internal/graph/directives"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/middleware"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// profileCall sends the token through AuthMiddleware and resolves profile
+// behind @auth(requires: USER), as the GraphQL handler would.
+func profileCall(t *testing.T, client *ent.Client, authService *service.AuthService, token string) error {
+	t.Helper()
+	var result error
+	resolve := nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		ctx := graphql.WithFieldContext(r.Context(), &graphql.FieldContext{
+			Object: "Query",
+			Field:  graphql.CollectedField{Field: &ast.Field{Name: "profile"}},
+		})
+		role := model.UserRoleUser
+		_, result = directives.NewAuthDirective().Auth(ctx, nil, func(ctx context.Context) (interface{}, error) {
+			return true, nil
+		}, &role)
+	})
+
+	req := httptest.NewRequest("POST", "/graphql", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	middleware.AuthMiddleware(client, authService)(resolve).ServeHTTP(httptest.NewRecorder(), req)
+	return result
+}
+
+func expectSessionRevoked(t *testing.T, err error, reason model.RevocationReason) {
+	t.Helper()
+	gqlErr, ok := err.(*gqlerror.Error)
+	if !ok {
+		t.Fatalf("Expected a GraphQL error, got %v", err)
+	}
+	if gqlErr.Extensions["code"] != model.ErrorTypeSessionRevoked || gqlErr.Extensions["reason"] != string(reason) {
+		t.Errorf("Expected SESSION_REVOKED with reason %s, got %v", reason, gqlErr.Extensions)
+	}
+}
+
+func TestRevocationReason_EndToEnd(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	configureTokenBudget(t, jwt.DefaultOptions())
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(rdb), &mockMailService{})
+
+	u := createVerifiedUser(t, client, "revocation_reason@example.com")
+	token, _ := jwt.GenerateToken(u.PublicID.String(), jwt.TokenTypeAccess, time.Minute)
+	if err := profileCall(t, client, authService, token); err != nil {
+		t.Fatalf("Expected the token to reach profile before the revocation, got %v", err)
+	}
+
+	revokedAt := time.Now()
+	if err := authService.RevokeUserTokens(ctx, []int64{u.ID}, revokedAt, model.RevocationReasonSuspiciousRefresh); err != nil {
+		t.Fatalf("Failed to revoke tokens: %v", err)
+	}
+
+	// The reason is stored next to the cutoff, for as long as the cutoff.
+	cutoffKey := fmt.Sprintf("%s%d", service.TokenRevokedBeforePrefix, u.ID)
+	reasonKey := fmt.Sprintf("%s%d", service.TokenRevocationReasonPrefix, u.ID)
+	if cutoff, _ := rdb.Get(ctx, cutoffKey).Int64(); cutoff != revokedAt.UnixMilli() {
+		t.Errorf("Expected the cutoff at %d, got %d", revokedAt.UnixMilli(), cutoff)
+	}
+	if reason, _ := rdb.Get(ctx, reasonKey).Result(); reason != string(model.RevocationReasonSuspiciousRefresh) {
+		t.Errorf("Expected the reason stored with the cutoff, got %q", reason)
+	}
+	if cutoffTTL, reasonTTL := rdb.TTL(ctx, cutoffKey).Val(), rdb.TTL(ctx, reasonKey).Val(); reasonTTL <= 0 || reasonTTL != cutoffTTL {
+		t.Errorf("Expected the reason to expire with the cutoff, got %v and %v", reasonTTL, cutoffTTL)
+	}
+
+	entries, err := rdb.XRange(ctx, service.RevocationStreamKey, "-", "+").Result()
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("Expected one revocation event, got %d (%v)", len(entries), err)
+	}
+	var event service.RevocationEvent
+	if err := json.Unmarshal([]byte(entries[0].Values["event"].(string)), &event); err != nil {
+		t.Fatalf("Failed to decode revocation event: %v", err)
+	}
+	if event.EventType != events.TokenRevoked || event.UserID != u.ID || event.Reason != model.RevocationReasonSuspiciousRefresh || !event.Timestamp.Equal(revokedAt) {
+		t.Errorf("Expected a token_revoked event for the user with the reason, got %+v", event)
+	}
+
+	expectSessionRevoked(t, profileCall(t, client, authService, token), model.RevocationReasonSuspiciousRefresh)
+
+	// A later revocation replaces the reason the client is given.
+	time.Sleep(time.Millisecond)
+	if err := authService.RevokeUserTokens(ctx, []int64{u.ID}, time.Now(), model.RevocationReasonEmailChanged); err != nil {
+		t.Fatalf("Failed to revoke tokens: %v", err)
+	}
+	expectSessionRevoked(t, profileCall(t, client, authService, token), model.RevocationReasonEmailChanged)
+
+	// A token signed out on its own is blacklisted, not cut off.
+	other := createVerifiedUser(t, client, "revocation_logout@example.com")
+	loggedOut, _ := jwt.GenerateToken(other.PublicID.String(), jwt.TokenTypeAccess, time.Minute)
+	authService.RevokeAccessToken(ctx, loggedOut)
+	expectSessionRevoked(t, profileCall(t, client, authService, loggedOut), model.RevocationReasonUserLogout)
+}
@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/database/ent/user"
+	"github.com/abisalde/authentication-service/internal/handlers"
+	"github.com/abisalde/authentication-service/internal/sandbox"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestSandbox_DeterministicVerificationCode(t *testing.T) {
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := &configs.Config{}
+	cfg.Sandbox.Enabled = true
+	cfg.Sandbox.VerificationCode = "4242"
+	cfg.Sandbox.AllowedEmails = []string{"@sandbox.test", "qa@example.com"}
+
+	authService := service.NewAuthService(repository.NewUserRepository(client), cfg, redisCache, &mockMailService{})
+
+	for _, email := range []string{"e2e@sandbox.test", "QA@example.com"} {
+		if code := authService.NewVerificationCode(email); code != "4242" {
+			t.Errorf("Expected deterministic code for %s, got %s", email, code)
+		}
+	}
+
+	if code := authService.NewVerificationCode("someone@example.com"); len(code) != 4 {
+		t.Errorf("Expected a random 4 digit code for non allow-listed email, got %q", code)
+	}
+}
+
+func TestSandbox_DefaultOAuthEmailFollowsConfigOrder(t *testing.T) {
+	cfg := &configs.Config{}
+	cfg.Sandbox.Enabled = true
+	cfg.Sandbox.AllowedEmails = []string{"@sandbox.test", " First@Example.com", "second@example.com", "third@example.com", "first@example.com"}
+
+	// Map iteration would pick a different entry from run to run.
+	for range 20 {
+		if email := sandbox.New(cfg).DefaultOAuthEmail(); email != "first@example.com" {
+			t.Fatalf("Expected the first exact entry, got %q", email)
+		}
+	}
+
+	cfg.Sandbox.AllowedEmails = []string{"@sandbox.test"}
+	if email := sandbox.New(cfg).DefaultOAuthEmail(); email != "" {
+		t.Errorf("Expected no default with only domain entries, got %q", email)
+	}
+}
+
+func TestSandbox_TestTokenEndpointGuards(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	redisCache := database.NewCacheService(embeddedRedis(t))
+
+	configureTokenBudget(t, jwt.DefaultOptions())
+	secret := "qa-bootstrap-secret-0123456789abcdef"
+	cfg := &configs.Config{}
+	cfg.Sandbox.Enabled = true
+	cfg.Sandbox.TestTokens = true
+	cfg.Sandbox.AllowedEmails = []string{"@sandbox.test"}
+
+	authService := service.NewAuthService(repository.NewUserRepository(client), cfg, redisCache, &mockMailService{})
+	app := fiber.New()
+	app.Post("/sandbox/token", handlers.SandboxTokenHandler(authService, secret))
+
+	request := func(secretHeader, email string) (int, handlers.TestTokens) {
+		req := httptest.NewRequest("POST", "/sandbox/token", strings.NewReader(`{"email":"`+email+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(handlers.SandboxSecretHeader, secretHeader)
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("Sandbox token request failed: %v", err)
+		}
+		defer resp.Body.Close()
+
+		var tokens handlers.TestTokens
+		if resp.StatusCode == fiber.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+				t.Fatalf("Failed to decode test tokens: %v", err)
+			}
+		}
+		return resp.StatusCode, tokens
+	}
+
+	if status, _ := request("wrong", "qa@sandbox.test"); status != fiber.StatusUnauthorized {
+		t.Errorf("Expected 401 with a wrong secret, got %d", status)
+	}
+	if status, _ := request(secret, "someone@example.com"); status != fiber.StatusForbidden {
+		t.Errorf("Expected 403 for an email outside the allow-list, got %d", status)
+	}
+
+	status, first := request(secret, "QA@sandbox.test")
+	if status != fiber.StatusOK {
+		t.Fatalf("Expected tokens for an allow-listed email, got %d", status)
+	}
+	claims, err := jwt.ValidateToken(first.Token)
+	if err != nil {
+		t.Fatalf("Expected the access token to validate, got %v", err)
+	}
+	if claims.Subject != first.PublicID || first.Email != "qa@sandbox.test" || first.RefreshToken == "" {
+		t.Errorf("Expected tokens for the lowercased sandbox user, got %+v (subject %s)", first, claims.Subject)
+	}
+	created, err := client.User.Get(context.Background(), first.UserID)
+	if err != nil {
+		t.Fatalf("Failed to load the sandbox user: %v", err)
+	}
+	if !created.IsEmailVerified {
+		t.Error("Expected the sandbox user to be created verified")
+	}
+
+	_, second := request(secret, "qa@sandbox.test")
+	if second.UserID != first.UserID {
+		t.Errorf("Expected the second call to reuse user %d, got %d", first.UserID, second.UserID)
+	}
+	if n := client.User.Query().Where(user.EmailEQ("qa@sandbox.test")).CountX(context.Background()); n != 1 {
+		t.Errorf("Expected one sandbox user, got %d", n)
+	}
+
+	if _, err := authService.DeactivateAccount(context.Background(), created); err != nil {
+		t.Fatalf("Failed to deactivate the sandbox user: %v", err)
+	}
+	if status, _ := request(secret, "qa@sandbox.test"); status != fiber.StatusOK {
+		t.Errorf("Expected tokens for a deactivated sandbox user, got %d", status)
+	}
+	if reloaded := client.User.GetX(context.Background(), first.UserID); service.IsDeactivated(reloaded) {
+		t.Error("Expected signing in to lift the deactivation, as login does")
+	}
+
+	cfg.Sandbox.TestTokens = false
+	disabled := service.NewAuthService(repository.NewUserRepository(client), cfg, redisCache, &mockMailService{})
+	if _, _, err := disabled.IssueTestTokens(context.Background(), "qa@sandbox.test"); err != service.ErrTestTokensDisabled {
+		t.Errorf("Expected test tokens to be refused when disabled, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/pkg/scheduler"
+)
+
+// sharedLocker stands in for Redis: one lock table shared by every replica.
+type sharedLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+func (l *sharedLocker) TryLock(ctx context.Context, job string, ttl time.Duration) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held[job] {
+		return nil, false, nil
+	}
+	l.held[job] = true
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.held, job)
+	}, true, nil
+}
+
+func TestScheduler_SingletonAcrossReplicas(t *testing.T) {
+	locker := &sharedLocker{held: make(map[string]bool)}
+	history := scheduler.NewMemoryHistory(10)
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	slow := func(ctx context.Context) error {
+		close(started)
+		<-finish
+		return nil
+	}
+
+	replicaA := scheduler.New(locker, history)
+	replicaB := scheduler.New(locker, history)
+	if err := replicaA.Register("janitor", "@every 1h", slow); err != nil {
+		t.Fatalf("Failed to register job: %v", err)
+	}
+	if err := replicaB.Register("janitor", "@every 1h", func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatalf("Failed to register job: %v", err)
+	}
+
+	ctx := context.Background()
+	done := make(chan string)
+	go func() { done <- replicaA.RunOnce(ctx, "janitor") }()
+	<-started
+
+	if outcome := replicaB.RunOnce(ctx, "janitor"); outcome != scheduler.OutcomeSkipped {
+		t.Errorf("Expected the second replica to skip while the lock is held, got %s", outcome)
+	}
+
+	close(finish)
+	if outcome := <-done; outcome != scheduler.OutcomeSuccess {
+		t.Fatalf("Expected the first replica to succeed, got %s", outcome)
+	}
+	if outcome := replicaB.RunOnce(ctx, "janitor"); outcome != scheduler.OutcomeSuccess {
+		t.Errorf("Expected the lock to be released after the run, got %s", outcome)
+	}
+
+	runs, err := history.Runs(ctx, "janitor", 0)
+	if err != nil || len(runs) != 2 {
+		t.Fatalf("Expected two recorded runs, got %d (%v)", len(runs), err)
+	}
+}
+
+func TestScheduler_RecordsFailuresAndPanics(t *testing.T) {
+	jobs := scheduler.New(nil, nil)
+
+	if err := jobs.Register("broken", "@every 1m", func(ctx context.Context) error { return errors.New("boom") }); err != nil {
+		t.Fatalf("Failed to register job: %v", err)
+	}
+	if err := jobs.Register("panicky", "@hourly", func(ctx context.Context) error { panic("oops") }); err != nil {
+		t.Fatalf("Failed to register job: %v", err)
+	}
+	if err := jobs.Register("broken", "@daily", func(ctx context.Context) error { return nil }); err != scheduler.ErrDuplicateJob {
+		t.Errorf("Expected ErrDuplicateJob, got %v", err)
+	}
+	for _, schedule := range []string{"*/5 * * * *", "@every 10ms", "@every soon"} {
+		if err := jobs.Register("bad_"+schedule, schedule, func(ctx context.Context) error { return nil }); err == nil {
+			t.Errorf("Expected schedule %q to be rejected", schedule)
+		}
+	}
+
+	ctx := context.Background()
+	for _, name := range []string{"broken", "panicky"} {
+		if outcome := jobs.RunOnce(ctx, name); outcome != scheduler.OutcomeFailure {
+			t.Errorf("Expected %s to fail, got %s", name, outcome)
+		}
+		runs, _ := jobs.History(ctx, name, 1)
+		if len(runs) != 1 || runs[0].Error == "" {
+			t.Errorf("Expected a failed run of %s in history, got %+v", name, runs)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/database/ent/loginattempt"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+func checkStatuses(checkup *model.SecurityCheckup) map[model.SecurityCheckKind]model.SecurityCheckStatus {
+	statuses := map[model.SecurityCheckKind]model.SecurityCheckStatus{}
+	for _, check := range checkup.Checks {
+		statuses[check.Kind] = check.Status
+	}
+	return statuses
+}
+
+func TestSecurityCheckup_HealthyAccount(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(embeddedRedis(t)), &mockMailService{})
+	u := createVerifiedUser(t, client, "checkup_healthy@example.com")
+	u = client.User.UpdateOne(u).SetPasswordHash("hash").SetPasswordChangedAt(time.Now().Add(-24 * time.Hour)).SaveX(ctx)
+
+	checkup, err := authService.SecurityCheckup(ctx, u)
+	if err != nil {
+		t.Fatalf("Failed to run the checkup: %v", err)
+	}
+
+	statuses := checkStatuses(checkup)
+	if statuses[model.SecurityCheckKindTwoFactor] != model.SecurityCheckStatusUnavailable || statuses[model.SecurityCheckKindRecoveryEmail] != model.SecurityCheckStatusUnavailable {
+		t.Errorf("Expected checks without a backing feature to be UNAVAILABLE, got %v", statuses)
+	}
+	if checkup.Score != 100 {
+		t.Errorf("Expected unavailable checks to be left out of a perfect score, got %d: %v", checkup.Score, statuses)
+	}
+	if checkup.PasswordAgeDays == nil || *checkup.PasswordAgeDays != 1 {
+		t.Errorf("Expected the password to be a day old, got %v", checkup.PasswordAgeDays)
+	}
+	if checkup.ActiveSessions != 0 {
+		t.Errorf("Expected no active sessions, got %d", checkup.ActiveSessions)
+	}
+}
+
+func TestSecurityCheckup_FlagsRiskyAccount(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(embeddedRedis(t)), &mockMailService{})
+	u := createVerifiedUser(t, client, "checkup_risky@example.com")
+	u = client.User.UpdateOne(u).SetPasswordHash("hash").SetPasswordChangedAt(time.Now().Add(-400 * 24 * time.Hour)).SaveX(ctx)
+
+	signInFrom(t, authService, u, "203.0.113.7", "laptop", chromeWindows)
+	signInFrom(t, authService, u, "198.51.100.9", "laptop", chromeWindows)
+	signInFrom(t, authService, u, "192.0.2.10", "stranger", firefoxLinuxUA)
+	authService.RecordLoginAttempt(ctx, service.LoginAttemptInput{
+		User:          u,
+		Method:        loginattempt.MethodPASSWORD,
+		Outcome:       loginattempt.OutcomeFAILURE,
+		FailureReason: loginattempt.FailureReasonINVALID_PASSWORD,
+	})
+
+	checkup, err := authService.SecurityCheckup(ctx, u)
+	if err != nil {
+		t.Fatalf("Failed to run the checkup: %v", err)
+	}
+
+	statuses := checkStatuses(checkup)
+	if statuses[model.SecurityCheckKindPasswordAge] != model.SecurityCheckStatusFail {
+		t.Errorf("Expected a year-old password to fail, got %v", statuses[model.SecurityCheckKindPasswordAge])
+	}
+	if checkup.UnknownDevices != 1 || statuses[model.SecurityCheckKindUnknownDevices] != model.SecurityCheckStatusWarn {
+		t.Errorf("Expected the stranger to be the only unknown device, got %d (%v)", checkup.UnknownDevices, statuses[model.SecurityCheckKindUnknownDevices])
+	}
+	if checkup.RecentSuspiciousEvents < 1 || statuses[model.SecurityCheckKindSuspiciousActivity] == model.SecurityCheckStatusPass {
+		t.Errorf("Expected the failed password to count as suspicious, got %d (%v)", checkup.RecentSuspiciousEvents, statuses[model.SecurityCheckKindSuspiciousActivity])
+	}
+	if checkup.Score >= 60 {
+		t.Errorf("Expected a low score, got %d: %v", checkup.Score, statuses)
+	}
+}
@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+)
+
+func TestServiceAccounts_KeyLifecycle(t *testing.T) {
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	t.Setenv("JWT_SECRET", "service-accounts-test-secret")
+
+	ctx := context.Background()
+	cfg := &configs.Config{}
+	cfg.ServiceAccounts.TokenTTL = 5 * time.Minute
+	cfg.ServiceAccounts.RotationGrace = 50 * time.Millisecond
+	authService := service.NewAuthService(repository.NewUserRepository(client), cfg, redisCache, &recordingMailService{})
+
+	if _, err := authService.CreateServiceAccount(ctx, "acme", "ci"); err != service.ErrOrganizationNotFound {
+		t.Errorf("Expected ErrOrganizationNotFound, got %v", err)
+	}
+	if _, err := authService.SaveOrganizationBranding(ctx, repository.OrganizationBranding{Slug: "acme", Name: "Acme Corp"}); err != nil {
+		t.Fatalf("Failed to create organization: %v", err)
+	}
+
+	created, err := authService.CreateServiceAccount(ctx, "acme", " Deploy bot ")
+	if err != nil {
+		t.Fatalf("Failed to create service account: %v", err)
+	}
+	account := created.Account
+	if !service.IsServiceAccount(account) || account.FirstName != "Deploy bot" || account.PasswordHash != "" {
+		t.Errorf("Expected a passwordless service account named after its purpose, got %+v", account)
+	}
+	if !strings.HasSuffix(account.Email, "@acme.service-accounts.invalid") {
+		t.Errorf("Expected an undeliverable address, got %s", account.Email)
+	}
+
+	token, ttl, err := authService.ExchangeServiceAccountKey(ctx, created.KeyID, created.Secret)
+	if err != nil {
+		t.Fatalf("Failed to exchange the key: %v", err)
+	}
+	claims, err := jwt.ValidateToken(token)
+	if err != nil || claims.Subject != account.PublicID.String() || !slices.Contains(claims.Scopes, service.ServiceAccountScope) || ttl != 5*time.Minute {
+		t.Errorf("Expected a service account access token, got %+v, %v, %s", claims, err, ttl)
+	}
+	if _, _, err := authService.ExchangeServiceAccountKey(ctx, created.KeyID, "wrong"); err != service.ErrInvalidServiceAccountKey {
+		t.Errorf("Expected a wrong secret to be refused, got %v", err)
+	}
+
+	rotated, err := authService.RotateServiceAccountKey(ctx, account.PublicID.String())
+	if err != nil {
+		t.Fatalf("Failed to rotate the key: %v", err)
+	}
+	if len(rotated.Account.Edges.ServiceAccountKeys) != 2 {
+		t.Fatalf("Expected both keys listed during the grace period, got %d", len(rotated.Account.Edges.ServiceAccountKeys))
+	}
+	time.Sleep(100 * time.Millisecond)
+	if _, _, err := authService.ExchangeServiceAccountKey(ctx, created.KeyID, created.Secret); err != service.ErrInvalidServiceAccountKey {
+		t.Errorf("Expected the old key to expire after the grace period, got %v", err)
+	}
+	if _, _, err := authService.ExchangeServiceAccountKey(ctx, rotated.KeyID, rotated.Secret); err != nil {
+		t.Errorf("Expected the new key to work, got %v", err)
+	}
+
+	listed, err := authService.ListServiceAccounts(ctx, "acme")
+	if err != nil || len(listed) != 1 || listed[0].ID != account.ID || listed[0].Edges.ServiceAccountKeys[1].LastUsedAt == nil {
+		t.Errorf("Expected the account listed with the use of its new key, got %v, %v", listed, err)
+	}
+
+	if _, err := authService.RevokeServiceAccountKey(ctx, account.PublicID.String(), "sa_unknown"); err != service.ErrServiceAccountKeyNotFound {
+		t.Errorf("Expected ErrServiceAccountKeyNotFound, got %v", err)
+	}
+	revoked, err := authService.RevokeServiceAccountKey(ctx, account.PublicID.String(), rotated.KeyID)
+	if err != nil || len(revoked.Edges.ServiceAccountKeys) != 1 {
+		t.Fatalf("Expected the key deleted, got %v, %v", revoked, err)
+	}
+	if _, _, err := authService.ExchangeServiceAccountKey(ctx, rotated.KeyID, rotated.Secret); err != service.ErrInvalidServiceAccountKey {
+		t.Errorf("Expected a revoked key to be refused, got %v", err)
+	}
+
+	person := createVerifiedUser(t, client, "person@acme.example")
+	if _, err := authService.RotateServiceAccountKey(ctx, person.PublicID.String()); err != service.ErrServiceAccountNotFound {
+		t.Errorf("Expected people to have no service account keys, got %v", err)
+	}
+}
+
+func TestServiceAccounts_ExcludedFromHumanFlows(t *testing.T) {
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	mailer := &recordingMailService{}
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, redisCache, mailer)
+	if _, err := authService.SaveOrganizationBranding(ctx, repository.OrganizationBranding{Slug: "acme", Name: "Acme Corp"}); err != nil {
+		t.Fatalf("Failed to create organization: %v", err)
+	}
+	created, err := authService.CreateServiceAccount(ctx, "acme", "Importer")
+	if err != nil {
+		t.Fatalf("Failed to create service account: %v", err)
+	}
+	ref := created.Account.PublicID.String()
+
+	if err := authService.RequestEmailChange(ctx, created.Account, "importer@example.com"); err != service.ErrServiceAccount {
+		t.Errorf("Expected email changes to be refused, got %v", err)
+	}
+	if _, err := authService.AssignOrganization(ctx, ref, nil); err != service.ErrServiceAccount {
+		t.Errorf("Expected the account to stay with its organization, got %v", err)
+	}
+
+	if _, err := authService.SuspendUser(ctx, ref, model.SuspensionReasonSpam); err != nil {
+		t.Fatalf("Failed to suspend service account: %v", err)
+	}
+	if len(mailer.sent) != 0 {
+		t.Errorf("Expected no email to a service account, got %d", len(mailer.sent))
+	}
+	if _, _, err := authService.ExchangeServiceAccountKey(ctx, created.KeyID, created.Secret); err != service.ErrInvalidServiceAccountKey {
+		t.Errorf("Expected a suspended account's key to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/pkg/session"
+)
+
+func TestSessionEvents_SubscribersLearnAboutRevocations(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rdb := embeddedRedis(t)
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(rdb), &mockMailService{})
+
+	user := createVerifiedUser(t, client, "session_events@example.com")
+	other := createVerifiedUser(t, client, "session_events_other@example.com")
+
+	received := make(chan session.SessionEvent, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- session.NewSubscriber(rdb).After("0").Run(ctx, func(_ context.Context, event session.SessionEvent) {
+			received <- event
+		})
+	}()
+
+	next := func() session.SessionEvent {
+		t.Helper()
+		select {
+		case event := <-received:
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for a session event")
+			return session.SessionEvent{}
+		}
+	}
+
+	if err := authService.InvalidateRefreshToken(ctx, user.ID); err != nil {
+		t.Fatalf("Failed to invalidate the refresh token: %v", err)
+	}
+	event := next()
+	if event.Type != session.EventSessionRevoked || event.UserID != user.ID || event.Subject != user.PublicID.String() || event.ID == "" {
+		t.Errorf("Expected a session_revoked event for the user, got %+v", event)
+	}
+
+	if err := authService.RevokeUserTokens(ctx, []int64{user.ID, other.ID}, time.Now(), model.RevocationReasonForcedRelogin); err != nil {
+		t.Fatalf("Failed to revoke the users' tokens: %v", err)
+	}
+	subjects := map[int64]string{user.ID: user.PublicID.String(), other.ID: other.PublicID.String()}
+	for range 2 {
+		event := next()
+		if event.Type != session.EventUserSessionsRevoked || event.Subject != subjects[event.UserID] || event.Reason != string(model.RevocationReasonForcedRelogin) {
+			t.Errorf("Expected a user_sessions_revoked event with the reason, got %+v", event)
+		}
+		delete(subjects, event.UserID)
+	}
+
+	if _, err := authService.StoreRefreshToken(ctx, other, "refresh-token"); err == nil {
+		if event := next(); event.Type != session.EventSessionCreated || event.Subject != other.PublicID.String() {
+			t.Errorf("Expected a session_created event for the sign-in, got %+v", event)
+		}
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("Expected the subscriber to stop with the context, got %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected the subscriber to stop once the context is cancelled")
+	}
+}
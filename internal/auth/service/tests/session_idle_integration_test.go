@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+)
+
+func TestSessionIdle_SlidesUpToMaxLifetime(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	cfg := &configs.Config{}
+	cfg.Sessions.IdleTimeout = time.Hour
+	cfg.Sessions.MaxLifetime = 24 * time.Hour
+	authService := service.NewAuthService(repository.NewUserRepository(client), cfg, database.NewCacheService(rdb), &mockMailService{})
+
+	user := createVerifiedUser(t, client, "session_idle@example.com")
+	if _, err := authService.StoreRefreshToken(ctx, user, "refresh-token"); err != nil {
+		t.Fatalf("Failed to store the refresh token: %v", err)
+	}
+
+	refreshKey := fmt.Sprintf("%s%d", service.RefreshCachePrefix, user.ID)
+	activityKey := fmt.Sprintf("%s%d", service.SessionActivityPrefix, user.ID)
+	if ttl := rdb.TTL(ctx, refreshKey).Val(); ttl <= 0 || ttl > time.Hour {
+		t.Errorf("Expected a new session to expire after one idle window, got %v", ttl)
+	}
+
+	// Recent activity is not written again.
+	if err := authService.UpdateSessionActivity(ctx, user.ID); err != nil {
+		t.Fatalf("Expected an active session to pass, got %v", err)
+	}
+
+	now := time.Now()
+	rdb.HSet(ctx, activityKey, "last_active", now.Add(-30*time.Minute).Unix())
+	rdb.Expire(ctx, refreshKey, 30*time.Minute)
+	if err := authService.UpdateSessionActivity(ctx, user.ID); err != nil {
+		t.Fatalf("Expected a session used within the idle window to pass, got %v", err)
+	}
+	if ttl := rdb.TTL(ctx, refreshKey).Val(); ttl <= 50*time.Minute {
+		t.Errorf("Expected activity to slide the refresh expiry to an hour, got %v", ttl)
+	}
+
+	// Near the end of its lifetime the session is only extended to it.
+	rdb.HSet(ctx, activityKey,
+		"started_at", now.Add(-24*time.Hour+10*time.Minute).Unix(),
+		"last_active", now.Add(-5*time.Minute).Unix())
+	if err := authService.UpdateSessionActivity(ctx, user.ID); err != nil {
+		t.Fatalf("Expected the session to pass, got %v", err)
+	}
+	if ttl := rdb.TTL(ctx, refreshKey).Val(); ttl <= 0 || ttl > 10*time.Minute {
+		t.Errorf("Expected the refresh expiry capped at the max lifetime, got %v", ttl)
+	}
+
+	rdb.HSet(ctx, activityKey, "last_active", now.Add(-2*time.Hour).Unix())
+	if err := authService.UpdateSessionActivity(ctx, user.ID); err != service.ErrSessionIdle {
+		t.Errorf("Expected an idle session to fail with ErrSessionIdle, got %v", err)
+	}
+
+	if err := authService.InvalidateRefreshToken(ctx, user.ID); err != nil {
+		t.Fatalf("Failed to sign out: %v", err)
+	}
+	if rdb.Exists(ctx, activityKey).Val() != 0 {
+		t.Error("Expected signing out to drop the session activity")
+	}
+}
+
+func TestSessionIdle_DisabledAndUntrackedSessionsPass(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	user := createVerifiedUser(t, client, "session_untracked@example.com")
+	activityKey := fmt.Sprintf("%s%d", service.SessionActivityPrefix, user.ID)
+	idle := time.Now().Add(-48 * time.Hour).Unix()
+	rdb.HSet(ctx, activityKey, "started_at", idle, "last_active", idle)
+
+	disabled := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(rdb), &mockMailService{})
+	if err := disabled.UpdateSessionActivity(ctx, user.ID); err != nil {
+		t.Errorf("Expected no idle timeout while disabled, got %v", err)
+	}
+
+	cfg := &configs.Config{}
+	cfg.Sessions.IdleTimeout = time.Hour
+	enabled := service.NewAuthService(repository.NewUserRepository(client), cfg, database.NewCacheService(rdb), &mockMailService{})
+	other := createVerifiedUser(t, client, "session_untracked_other@example.com")
+	if err := enabled.UpdateSessionActivity(ctx, other.ID); err != nil {
+		t.Errorf("Expected a session started before the idle timeout to pass, got %v", err)
+	}
+	if err := enabled.UpdateSessionActivity(ctx, user.ID); err != service.ErrSessionIdle {
+		t.Errorf("Expected the idle session to fail, got %v", err)
+	}
+}
@@ -0,0 +1,208 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/auth/sessionstore"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/pkg/verification"
+)
+
+func TestSessionStore_ConcurrentSignInsStayConsistent(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	cache := database.NewCacheService(rdb)
+	cfg := &configs.Config{}
+	cfg.Sessions.IdleTimeout = time.Hour
+	authService := service.NewAuthService(repository.NewUserRepository(client), cfg, cache, &mockMailService{})
+
+	user := createVerifiedUser(t, client, "session_store@example.com")
+	if _, err := authService.StoreRefreshToken(ctx, user, "refresh-token"); err != nil {
+		t.Fatalf("Failed to store the refresh token: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := authService.StoreRefreshToken(ctx, user, fmt.Sprintf("refresh-token-%d", i)); err != nil {
+				t.Errorf("Failed to store refresh token %d: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// Whichever sign-in won, its token and hash were written together.
+	var encrypted string
+	if err := cache.Get(ctx, fmt.Sprintf("%s%d", service.RefreshCachePrefix, user.ID), &encrypted); err != nil {
+		t.Fatalf("Failed to read the stored refresh token: %v", err)
+	}
+	token, err := verification.DecryptToken(encrypted)
+	if err != nil {
+		t.Fatalf("Failed to decrypt the stored refresh token: %v", err)
+	}
+	hashed, err := verification.HashToken(token)
+	if err != nil {
+		t.Fatalf("Failed to hash the stored refresh token: %v", err)
+	}
+	if valid, err := authService.ValidateRefreshToken(ctx, user.ID, hashed); !valid {
+		t.Errorf("Expected the stored token to match the stored hash, got %v", err)
+	}
+
+	if err := authService.InvalidateRefreshToken(ctx, user.ID); err != nil {
+		t.Fatalf("Failed to sign out: %v", err)
+	}
+	if n := rdb.Exists(ctx,
+		fmt.Sprintf("%s%d", service.RefreshCachePrefix, user.ID),
+		fmt.Sprintf("%s%d:hash", service.RefreshCachePrefix, user.ID),
+		fmt.Sprintf("%s%d", service.SessionActivityPrefix, user.ID),
+	).Val(); n != 0 {
+		t.Errorf("Expected signing out to drop the whole session, %d keys left", n)
+	}
+}
+
+func TestSessionStore_Backends(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite3", "file:sessionstore?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("Failed to open the session database: %v", err)
+	}
+	defer db.Close()
+	sqlStore := sessionstore.NewSQLStore(db)
+	if err := sqlStore.Migrate(ctx); err != nil {
+		t.Fatalf("Failed to create the sessions table: %v", err)
+	}
+
+	stores := map[string]sessionstore.Store{
+		"redis":  sessionstore.NewRedisStore(embeddedRedis(t)),
+		"memory": sessionstore.NewMemoryStore(),
+		"sql":    sqlStore,
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now().Truncate(time.Second)
+			const userID = 4242
+
+			if _, err := store.Get(ctx, userID); err != sessionstore.ErrNotFound {
+				t.Fatalf("Expected ErrNotFound before sign-in, got %v", err)
+			}
+			if expiresAt, err := store.Touch(ctx, userID, now, sessionstore.Activity{Idle: time.Hour, Resolution: time.Minute, Lifetime: 24 * time.Hour}); err != nil || !expiresAt.IsZero() {
+				t.Errorf("Expected touching a missing session to do nothing, got %s (%v)", expiresAt, err)
+			}
+
+			session := sessionstore.Session{
+				UserID:         userID,
+				EncryptedToken: "encrypted",
+				TokenHash:      "hash",
+				StartedAt:      now.Add(-10 * time.Minute),
+				LastActiveAt:   now.Add(-10 * time.Minute),
+				ExpiresAt:      now.Add(time.Hour),
+				EndsAt:         now.Add(24 * time.Hour),
+			}
+			if err := store.Create(ctx, session); err != nil {
+				t.Fatalf("Failed to create the session: %v", err)
+			}
+			got, err := store.Get(ctx, userID)
+			if err != nil {
+				t.Fatalf("Failed to read the session: %v", err)
+			}
+			if got.EncryptedToken != "encrypted" || got.TokenHash != "hash" || !got.StartedAt.Equal(session.StartedAt) || !got.LastActiveAt.Equal(session.LastActiveAt) {
+				t.Errorf("Expected the stored session back, got %+v", got)
+			}
+			if left := got.ExpiresAt.Sub(now); left < time.Hour-2*time.Second || left > time.Hour {
+				t.Errorf("Expected the session to expire in an hour, got %s", left)
+			}
+
+			if _, err := store.Touch(ctx, userID, now, sessionstore.Activity{Idle: 5 * time.Minute, Resolution: time.Minute, Lifetime: 24 * time.Hour}); err != sessionstore.ErrIdle {
+				t.Errorf("Expected a session unused for 10m to be idle under a 5m timeout, got %v", err)
+			}
+			expiresAt, err := store.Touch(ctx, userID, now, sessionstore.Activity{Idle: 2 * time.Hour, Resolution: time.Minute, Lifetime: 24 * time.Hour})
+			if err != nil {
+				t.Fatalf("Failed to touch the session: %v", err)
+			}
+			if !expiresAt.Equal(now.Add(2 * time.Hour)) {
+				t.Errorf("Expected the session to slide to %s, got %s", now.Add(2*time.Hour), expiresAt)
+			}
+			if again, err := store.Touch(ctx, userID, now, sessionstore.Activity{Idle: 2 * time.Hour, Resolution: time.Minute, Lifetime: 24 * time.Hour}); err != nil || !again.IsZero() {
+				t.Errorf("Expected a use within the resolution not to slide the session, got %s (%v)", again, err)
+			}
+			got, err = store.Get(ctx, userID)
+			if err != nil {
+				t.Fatalf("Failed to read the touched session: %v", err)
+			}
+			if !got.LastActiveAt.Equal(now) {
+				t.Errorf("Expected the session to be last used at %s, got %s", now, got.LastActiveAt)
+			}
+			if left := got.ExpiresAt.Sub(now); left < 2*time.Hour-2*time.Second || left > 2*time.Hour {
+				t.Errorf("Expected touching to move the expiry two hours out, got %s", left)
+			}
+
+			session.TokenHash = "rotated"
+			if err := store.Create(ctx, session); err != nil {
+				t.Fatalf("Failed to replace the session: %v", err)
+			}
+			sessions, err := store.ListByUser(ctx, userID)
+			if err != nil || len(sessions) != 1 || sessions[0].TokenHash != "rotated" {
+				t.Errorf("Expected the new sign-in to replace the session, got %+v (%v)", sessions, err)
+			}
+
+			if err := store.Delete(ctx, userID, userID+1); err != nil {
+				t.Fatalf("Failed to delete the session: %v", err)
+			}
+			if sessions, err := store.ListByUser(ctx, userID); err != nil || len(sessions) != 0 {
+				t.Errorf("Expected no sessions after deleting, got %+v (%v)", sessions, err)
+			}
+		})
+	}
+}
+
+func TestSessionStore_AuthServiceOffRedis(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	cfg := &configs.Config{}
+	cfg.Sessions.IdleTimeout = time.Hour
+	authService := service.NewAuthService(repository.NewUserRepository(client), cfg, database.NewCacheService(rdb), &mockMailService{})
+	store := sessionstore.NewMemoryStore()
+	authService.SetSessionStore(store)
+
+	user := createVerifiedUser(t, client, "session_store_memory@example.com")
+	hashed, err := authService.StoreRefreshToken(ctx, user, "refresh-token")
+	if err != nil {
+		t.Fatalf("Failed to store the refresh token: %v", err)
+	}
+	if n := rdb.Exists(ctx, fmt.Sprintf("%s%d", service.RefreshCachePrefix, user.ID)).Val(); n != 0 {
+		t.Error("Expected the refresh token to stay out of Redis")
+	}
+	session, err := store.Get(ctx, user.ID)
+	if err != nil || session.TokenHash != hashed {
+		t.Fatalf("Expected the session in the configured store, got %+v (%v)", session, err)
+	}
+	if err := authService.CheckIfRefreshTokenMatchClaims(ctx, user.ID); err == nil {
+		t.Error("Expected a token that is not a JWT to be refused")
+	}
+	if err := authService.UpdateSessionActivity(ctx, user.ID); err != nil {
+		t.Errorf("Expected a fresh session to be active, got %v", err)
+	}
+
+	if err := authService.InvalidateRefreshToken(ctx, user.ID); err != nil {
+		t.Fatalf("Failed to sign out: %v", err)
+	}
+	if _, err := store.Get(ctx, user.ID); err != sessionstore.ErrNotFound {
+		t.Errorf("Expected signing out to drop the session, got %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/refreshbinding"
+	"github.com/abisalde/authentication-service/internal/auth/takeover"
+	"github.com/abisalde/authentication-service/internal/configs"
+)
+
+func TestSessionTakeover_Conflicts(t *testing.T) {
+	cfg := &configs.Config{}
+	cfg.SessionTakeover.Mode = "restrict"
+	cfg.SessionTakeover.Network = true
+	cfg.SessionTakeover.UserAgent = true
+	cfg.SessionTakeover.Window = 5 * time.Minute
+	cfg.RefreshBinding.AllowedNetworks = []string{"198.51.100.0/24"}
+	policy := takeover.NewPolicy(cfg)
+
+	if !policy.Enabled() || !policy.Restricts() {
+		t.Fatalf("Expected an enabled restricting policy, got %+v", policy)
+	}
+
+	now := time.Now()
+	recent := []takeover.Sample{
+		policy.SampleOf("203.0.113.7", chromeWindows, now.Add(-time.Minute)),
+		policy.SampleOf("192.0.2.10", firefoxLinux, now.Add(-time.Hour)),
+	}
+
+	cases := []struct {
+		name      string
+		ip        string
+		userAgent string
+		want      []string
+	}{
+		{"same network, browser update", "203.0.200.1", chromeWindows2, nil},
+		{"other network", "192.0.2.10", chromeWindows, []string{refreshbinding.MismatchNetwork}},
+		{"allow-listed network", "198.51.100.20", chromeWindows, nil},
+		{"other browser", "203.0.113.7", firefoxLinux, []string{refreshbinding.MismatchUserAgent}},
+	}
+
+	for _, tc := range cases {
+		got := policy.Conflicts(recent, policy.SampleOf(tc.ip, tc.userAgent, now), tc.ip)
+		if len(got) != len(tc.want) || (len(got) > 0 && got[0] != tc.want[0]) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+
+	if takeover.NewPolicy(&configs.Config{}).Enabled() {
+		t.Error("Expected takeover detection to be off by default")
+	}
+}
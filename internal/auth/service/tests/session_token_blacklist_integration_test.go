@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/cookies"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/events"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+)
+
+func TestSessionTokenBlacklist_RevokingSessionsBlacklistsTheirAccessTokens(t *testing.T) {
+	t.Setenv("JWT_SECRET", "session-token-blacklist-test-secret")
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(rdb), &mockMailService{})
+
+	issue := func(u int64, subject string) string {
+		t.Helper()
+		token, err := cookies.GenerateAccessToken(ctx, subject, nil, nil)
+		if err != nil {
+			t.Fatalf("Failed to issue an access token: %v", err)
+		}
+		if err := authService.TrackSessionToken(ctx, u, token); err != nil {
+			t.Fatalf("Failed to track the access token: %v", err)
+		}
+		return token
+	}
+
+	leaver := createVerifiedUser(t, client, "blacklist_leaver@example.com")
+	suspect := createVerifiedUser(t, client, "blacklist_suspect@example.com")
+	bystander := createVerifiedUser(t, client, "blacklist_bystander@example.com")
+
+	signIn := issue(leaver.ID, leaver.PublicID.String())
+	refreshed := issue(leaver.ID, leaver.PublicID.String())
+	stolen := issue(suspect.ID, suspect.PublicID.String())
+	unrelated := issue(bystander.ID, bystander.PublicID.String())
+
+	if authService.IsTokenBlacklisted(ctx, signIn) {
+		t.Fatal("Expected tokens of live sessions not to be blacklisted")
+	}
+
+	if err := authService.InvalidateRefreshToken(ctx, leaver.ID); err != nil {
+		t.Fatalf("Failed to revoke the session: %v", err)
+	}
+	for _, token := range []string{signIn, refreshed} {
+		if !authService.IsTokenBlacklisted(ctx, token) {
+			t.Error("Expected every access token of the revoked session to be blacklisted")
+		}
+	}
+	if n := rdb.ZCard(ctx, fmt.Sprintf("%s%d", service.SessionTokensPrefix, leaver.ID)).Val(); n != 0 {
+		t.Errorf("Expected the blacklisted tokens to be untracked, %d left", n)
+	}
+	jti, expiresAt := jwt.GetTokenID(signIn)
+	if ttl := rdb.TTL(ctx, service.BlacklistedTokenIDPrefix+jti).Val(); ttl <= 0 || ttl > time.Until(expiresAt)+time.Second {
+		t.Errorf("Expected the blacklist entry to last as long as the token, got %v", ttl)
+	}
+
+	if err := authService.RevokeUserTokens(ctx, []int64{suspect.ID}, time.Now(), model.RevocationReasonSessionTakeover); err != nil {
+		t.Fatalf("Failed to revoke the user's sessions: %v", err)
+	}
+	if !authService.IsTokenBlacklisted(ctx, stolen) {
+		t.Error("Expected the stolen access token to be blacklisted")
+	}
+	if authService.IsTokenBlacklisted(ctx, unrelated) {
+		t.Error("Expected other users' tokens to stay valid")
+	}
+
+	entries, err := rdb.XRange(ctx, service.SessionEventStreamKey, "-", "+").Result()
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("Expected two session events, got %d (%v)", len(entries), err)
+	}
+	stolenID, _ := jwt.GetTokenID(stolen)
+	var event service.SessionEvent
+	if err := json.Unmarshal([]byte(entries[1].Values["event"].(string)), &event); err != nil {
+		t.Fatalf("Failed to decode the event: %v", err)
+	}
+	if event.EventType != events.UserSessionsRevoked || len(event.TokenIDs) != 1 || event.TokenIDs[0] != stolenID {
+		t.Errorf("Expected the event to list the blacklisted token, got %+v", event)
+	}
+}
+
+func TestSessionTokenBlacklist_LogoutKeysTheBlacklistByTokenID(t *testing.T) {
+	t.Setenv("JWT_SECRET", "session-token-blacklist-test-secret")
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(rdb), &mockMailService{})
+
+	u := createVerifiedUser(t, client, "blacklist_logout@example.com")
+	token, err := cookies.GenerateAccessToken(ctx, u.PublicID.String(), nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to issue an access token: %v", err)
+	}
+
+	authService.RevokeAccessToken(ctx, token)
+	if !authService.IsTokenBlacklisted(ctx, token) {
+		t.Fatal("Expected the logged out token to be blacklisted")
+	}
+
+	keys := rdb.Keys(ctx, "blacklist:*").Val()
+	jti, _ := jwt.GetTokenID(token)
+	if len(keys) != 1 || keys[0] != service.BlacklistedTokenIDPrefix+jti {
+		t.Errorf("Expected a single blacklist key named after the jti, got %v", keys)
+	}
+
+	// Tokens blacklisted before the switch stay rejected until they expire.
+	legacy, err := cookies.GenerateAccessToken(ctx, u.PublicID.String(), nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to issue an access token: %v", err)
+	}
+	rdb.Set(ctx, "blacklist:"+legacy, `"blacklisted"`, time.Minute)
+	if !authService.IsTokenBlacklisted(ctx, legacy) {
+		t.Error("Expected tokens blacklisted under the old key to stay blacklisted")
+	}
+}
@@ -0,0 +1,214 @@
+package tests
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	customErrors "github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/abisalde/authentication-service/pkg/session"
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+// publishedValidator serves the active key set the way /.well-known/jwks.json
+// does and returns a validator reading it.
+func publishedValidator(t *testing.T) *session.Validator {
+	t.Helper()
+	configureTokenBudget(t, jwt.DefaultOptions())
+	t.Cleanup(func() { jwt.SetKeySet(nil) })
+
+	ks, _ := jwt.NewKeySet("2025-01", rsaSigningKey(t, "2025-01"))
+	jwt.SetKeySet(ks)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jwt.CurrentKeySet().JWKS())
+	}))
+	t.Cleanup(srv.Close)
+
+	keys := session.NewJWKSProvider(srv.URL)
+	if err := keys.Refresh(context.Background()); err != nil {
+		t.Fatalf("Failed to fetch keys: %v", err)
+	}
+	return session.NewValidator(keys)
+}
+
+func TestSessionValidator_ValidAndExpired(t *testing.T) {
+	ctx := context.Background()
+	validator := publishedValidator(t)
+
+	token, _ := jwt.GenerateToken("user-public-id", jwt.TokenTypeAccess, time.Minute)
+	claims, err := validator.ValidateAccessToken(ctx, token, "")
+	if err != nil {
+		t.Fatalf("Expected a valid token to pass, got %v", err)
+	}
+	if claims.Subject != "user-public-id" || claims.ID == "" {
+		t.Errorf("Expected the token's subject and jti, got %+v", claims)
+	}
+
+	// Past the default leeway, so only the expiry rejects it.
+	expired, _ := jwt.GenerateToken("user-public-id", jwt.TokenTypeAccess, -time.Hour)
+	if _, err := validator.ValidateAccessToken(ctx, expired, ""); err != customErrors.ExpiredToken {
+		t.Errorf("Expected an expired token to be rejected as expired, got %v", err)
+	}
+
+	// A token signed by a key the service never published.
+	forged := gojwt.NewWithClaims(gojwt.SigningMethodRS256, &jwt.Claims{Type: jwt.TokenTypeAccess, RegisteredClaims: gojwt.RegisteredClaims{
+		Subject:   "user-public-id",
+		ExpiresAt: gojwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}})
+	forged.Header["kid"] = "2025-01"
+	private := configureOptionsKeySet(t, jwt.DefaultOptions())
+	signed, _ := forged.SignedString(private)
+	if _, err := validator.ValidateAccessToken(ctx, signed, ""); err == nil {
+		t.Error("Expected a token signed with an unpublished key to be rejected")
+	}
+}
+
+func TestSessionValidator_RevokedSession(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	validator := publishedValidator(t)
+	rdb := embeddedRedis(t)
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(rdb), &mockMailService{})
+
+	if session.BlacklistedTokenIDPrefix != service.BlacklistedTokenIDPrefix {
+		t.Fatalf("Expected the validator to read the service's blacklist, got %q and %q", session.BlacklistedTokenIDPrefix, service.BlacklistedTokenIDPrefix)
+	}
+
+	u := createVerifiedUser(t, client, "validator_revoked@example.com")
+	token, _ := jwt.GenerateToken(u.PublicID.String(), jwt.TokenTypeAccess, time.Minute)
+	if err := authService.TrackSessionToken(ctx, u.ID, token); err != nil {
+		t.Fatalf("Failed to track session token: %v", err)
+	}
+
+	validator.WithRevocations(rdb)
+	if _, err := validator.ValidateAccessToken(ctx, token, ""); err != nil {
+		t.Fatalf("Expected the token to pass before the session is revoked, got %v", err)
+	}
+
+	if err := authService.RevokeUserTokens(ctx, []int64{u.ID}, time.Now(), model.RevocationReasonForcedRelogin); err != nil {
+		t.Fatalf("Failed to revoke sessions: %v", err)
+	}
+	if _, err := validator.ValidateAccessToken(ctx, token, ""); err != session.ErrTokenRevoked {
+		t.Errorf("Expected the revoked session's token to be rejected, got %v", err)
+	}
+
+	// Logging out blacklists the token by its jti as well.
+	other, _ := jwt.GenerateToken(u.PublicID.String(), jwt.TokenTypeAccess, time.Minute)
+	authService.RevokeAccessToken(ctx, other)
+	if _, err := validator.ValidateAccessToken(ctx, other, ""); err != session.ErrTokenRevoked {
+		t.Errorf("Expected a logged out token to be rejected, got %v", err)
+	}
+	if keys := rdb.Keys(ctx, "blacklist:*").Val(); len(keys) == 0 || strings.Contains(strings.Join(keys, " "), other) {
+		t.Errorf("Expected the blacklist keyed by jti alone, got %v", keys)
+	}
+
+	// Without the blacklist the validator only sees a valid signature.
+	if _, err := validator.WithRevocations(nil).ValidateAccessToken(ctx, token, ""); err != nil {
+		t.Errorf("Expected signature-only validation to pass, got %v", err)
+	}
+
+	// Fail closed when the blacklist cannot be read.
+	down := embeddedRedis(t)
+	down.Close()
+	if _, err := validator.WithRevocations(down).ValidateAccessToken(ctx, token, ""); err == nil {
+		t.Error("Expected the token to be rejected when the blacklist is unreachable")
+	}
+}
+
+func TestSessionValidator_PinnedPublicKey(t *testing.T) {
+	ctx := context.Background()
+	private := configureOptionsKeySet(t, jwt.DefaultOptions())
+
+	validator, err := session.NewPublicKeyValidator(&private.PublicKey)
+	if err != nil {
+		t.Fatalf("Failed to build validator: %v", err)
+	}
+
+	sign := func(method gojwt.SigningMethod, key interface{}) string {
+		token := gojwt.NewWithClaims(method, &jwt.Claims{Type: jwt.TokenTypeAccess, RegisteredClaims: gojwt.RegisteredClaims{
+			Subject:   "user-public-id",
+			Issuer:    jwt.DefaultIssuer,
+			ExpiresAt: gojwt.NewNumericDate(time.Now().Add(time.Minute)),
+		}})
+		// The pinned key is used whatever the kid says.
+		token.Header["kid"] = "unpublished"
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("Failed to sign %s token: %v", method.Alg(), err)
+		}
+		return signed
+	}
+
+	for _, method := range []gojwt.SigningMethod{gojwt.SigningMethodRS256, gojwt.SigningMethodRS384, gojwt.SigningMethodRS512} {
+		if _, err := validator.ValidateAccessToken(ctx, sign(method, private), ""); err != nil {
+			t.Errorf("Expected a %s token to validate against the pinned key, got %v", method.Alg(), err)
+		}
+	}
+	if _, err := validator.ValidateAccessToken(ctx, sign(gojwt.SigningMethodPS256, private), ""); err == nil {
+		t.Error("Expected PS256 tokens to be rejected")
+	}
+
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	if _, err := validator.ValidateAccessToken(ctx, sign(gojwt.SigningMethodRS256, other), ""); err == nil {
+		t.Error("Expected a token signed with another key to be rejected")
+	}
+
+	// HS256 stays a fallback only while the service still accepts it.
+	jwt.SetKeySet(nil)
+	legacy, _ := jwt.GenerateToken("user-public-id", jwt.TokenTypeAccess, time.Minute)
+	if _, err := validator.ValidateAccessToken(ctx, legacy, ""); err == nil {
+		t.Error("Expected HS256 tokens to be rejected without AcceptHS256")
+	}
+	opts := jwt.DefaultOptions()
+	opts.AcceptHS256 = true
+	configureTokenBudget(t, opts)
+	if _, err := validator.ValidateAccessToken(ctx, legacy, ""); err != nil {
+		t.Errorf("Expected HS256 tokens to validate with AcceptHS256, got %v", err)
+	}
+
+	weak, _ := rsa.GenerateKey(rand.Reader, 1024)
+	if _, err := session.NewPublicKeyValidator(&weak.PublicKey); err == nil {
+		t.Error("Expected keys under 2048 bits to be refused")
+	}
+	if _, err := session.NewPublicKeyValidator(nil); err == nil {
+		t.Error("Expected a missing key to be refused")
+	}
+}
+
+func TestSessionValidator_RevocationsNeedTokenID(t *testing.T) {
+	ctx := context.Background()
+	private := configureOptionsKeySet(t, jwt.DefaultOptions())
+	validator, err := session.NewPublicKeyValidator(&private.PublicKey)
+	if err != nil {
+		t.Fatalf("Failed to create the validator: %v", err)
+	}
+
+	// The blacklist names tokens by jti, so one without could never be
+	// revoked.
+	token := signClaimsWith(t, private, gojwt.RegisteredClaims{
+		Subject:   "user-public-id",
+		Issuer:    jwt.DefaultIssuer,
+		ExpiresAt: gojwt.NewNumericDate(time.Now().Add(time.Minute)),
+	})
+	if _, err := validator.ValidateAccessToken(ctx, token, ""); err != nil {
+		t.Fatalf("Expected signature-only validation to pass, got %v", err)
+	}
+	if _, err := validator.WithRevocations(embeddedRedis(t)).ValidateAccessToken(ctx, token, ""); err != session.ErrMalformedTokens {
+		t.Errorf("Expected a token without a jti to be refused, got %v", err)
+	}
+}
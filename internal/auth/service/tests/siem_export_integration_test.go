@@ -0,0 +1,209 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/database/ent/loginattempt"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/siem"
+)
+
+// collector is an HTTP SIEM collector answering status, recording the lines
+// of every batch it accepts.
+type collector struct {
+	mu      sync.Mutex
+	status  int
+	batches [][]string
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.status != 0 {
+		w.WriteHeader(c.status)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	c.batches = append(c.batches, strings.Split(strings.TrimSuffix(string(body), "\n"), "\n"))
+}
+
+func (c *collector) lines() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var lines []string
+	for _, batch := range c.batches {
+		lines = append(lines, batch...)
+	}
+	return lines
+}
+
+func TestSIEMExport_DeliversSecurityEventsAsCEF(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(rdb), &mockMailService{})
+
+	authService.RecordLoginAttempt(ctx, service.LoginAttemptInput{
+		Email:         "nobody@example.com",
+		Method:        loginattempt.MethodPASSWORD,
+		Outcome:       loginattempt.OutcomeFAILURE,
+		FailureReason: loginattempt.FailureReasonUNKNOWN_ACCOUNT,
+	})
+	lockout := service.SecurityEvent{IP: "203.0.113.7", Reason: "LOGIN"}
+	authService.RecordLockout(ctx, "rate_limit:LOGIN:ip:203.0.113.7", time.Minute, lockout)
+	authService.RecordLockout(ctx, "rate_limit:LOGIN:ip:203.0.113.7", time.Minute, lockout)
+	authService.RecordRevocation(ctx, 42, model.RevocationReasonUserLogout)
+
+	sink := &collector{}
+	server := httptest.NewServer(sink)
+	defer server.Close()
+
+	exporter := siem.NewExporter(rdb, siem.NewHTTPSink(server.URL, siem.FormatCEF, "", time.Second), siem.FormatCEF, 0, 0)
+	exported, err := exporter.Export(ctx)
+	if err != nil || exported != 3 {
+		t.Fatalf("Expected the login failure, one lockout and the revocation, got %d, %v", exported, err)
+	}
+
+	lines := sink.lines()
+	for _, want := range []string{
+		"|login_failed|Login failed|5|",
+		"|lockout|Client locked out by rate limiting|7|",
+		"|token_revoked|Tokens revoked|5|",
+	} {
+		found := false
+		for _, line := range lines {
+			found = found || strings.HasPrefix(line, "CEF:0|abisalde|authentication-service|") && strings.Contains(line, want)
+		}
+		if !found {
+			t.Errorf("Expected a CEF line with %q, got %q", want, lines)
+		}
+	}
+	if !strings.Contains(lines[0], "suser=nobody@example.com") || !strings.Contains(lines[0], "reason=UNKNOWN_ACCOUNT") {
+		t.Errorf("Expected the login failure to carry its email and reason, got %q", lines[0])
+	}
+
+	if exported, err := exporter.Export(ctx); err != nil || exported != 0 {
+		t.Errorf("Expected delivered events not to be sent again, got %d, %v", exported, err)
+	}
+}
+
+func TestSIEMExport_KeepsEventsWhileCollectorIsDown(t *testing.T) {
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	authService := service.NewAuthService(nil, &configs.Config{}, database.NewCacheService(rdb), &mockMailService{})
+	for i := 0; i < 5; i++ {
+		authService.RecordSecurityEvent(ctx, service.SecurityEvent{Action: service.SecurityDenyListHit, IP: "198.51.100." + strconv.Itoa(i), Reason: service.DenyListRevokedToken})
+	}
+
+	sink := &collector{status: http.StatusServiceUnavailable}
+	server := httptest.NewServer(sink)
+	defer server.Close()
+
+	failures := metricValue(t, `siem_batches_total{sink="http",result="failure"}`)
+	exporter := siem.NewExporter(rdb, siem.NewHTTPSink(server.URL, siem.FormatJSONLines, "", time.Second), siem.FormatJSONLines, 2, 10)
+	if exported, err := exporter.Export(ctx); err == nil || exported != 0 {
+		t.Fatalf("Expected the export to fail while the collector is down, got %d, %v", exported, err)
+	}
+	if got := metricValue(t, `siem_batches_total{sink="http",result="failure"}`); got != failures+1 {
+		t.Errorf("Expected one failed batch, got %v", got-failures)
+	}
+	if lag := metricValue(t, "siem_export_lag_seconds"); lag <= 0 {
+		t.Errorf("Expected the lag to be reported, got %v", lag)
+	}
+
+	sink.mu.Lock()
+	sink.status = 0
+	sink.mu.Unlock()
+	if exported, err := exporter.Export(ctx); err != nil || exported != 5 {
+		t.Fatalf("Expected every held event once the collector is back, got %d, %v", exported, err)
+	}
+	if len(sink.batches) != 3 {
+		t.Errorf("Expected batches of at most 2 events, got %d batches", len(sink.batches))
+	}
+	lines := sink.lines()
+	if !strings.Contains(lines[0], `"action":"deny_list_hit"`) || !strings.Contains(lines[0], `"ip":"198.51.100.0"`) {
+		t.Errorf("Expected the oldest event first as JSON, got %q", lines[0])
+	}
+	if lag := metricValue(t, "siem_export_lag_seconds"); lag != 0 {
+		t.Errorf("Expected no lag once caught up, got %v", lag)
+	}
+}
+
+func TestSIEMExport_WritesOctetCountedSyslog(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		var messages []string
+		for {
+			length, err := reader.ReadString(' ')
+			if err != nil {
+				break
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(length))
+			msg := make([]byte, n)
+			if _, err := io.ReadFull(reader, msg); err != nil {
+				break
+			}
+			messages = append(messages, string(msg))
+		}
+		received <- messages
+	}()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	authService := service.NewAuthService(nil, &configs.Config{}, database.NewCacheService(rdb), &mockMailService{})
+	authService.RecordSecurityEvent(ctx, service.SecurityEvent{Action: service.SecurityLoginSucceeded, UserID: 7, Email: "a|b=c@example.com"})
+
+	cfg := &configs.Config{}
+	cfg.SIEM.Enabled = true
+	cfg.SIEM.Endpoint = "tcp://" + listener.Addr().String()
+	exporter, err := siem.New(cfg, rdb)
+	if err != nil {
+		t.Fatalf("Failed to build the exporter: %v", err)
+	}
+	if exported, err := exporter.Export(ctx); err != nil || exported != 1 {
+		t.Fatalf("Expected one event, got %d, %v", exported, err)
+	}
+
+	select {
+	case messages := <-received:
+		if len(messages) != 1 {
+			t.Fatalf("Expected one syslog message, got %q", messages)
+		}
+		if !strings.HasPrefix(messages[0], "<85>1 ") || !strings.Contains(messages[0], " authentication-service - - - CEF:0|") {
+			t.Errorf("Expected an RFC 5424 authpriv message, got %q", messages[0])
+		}
+		if !strings.Contains(messages[0], `suid=7 suser=a|b\=c@example.com`) {
+			t.Errorf("Expected CEF extension values to be escaped, got %q", messages[0])
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the collector to receive the batch")
+	}
+}
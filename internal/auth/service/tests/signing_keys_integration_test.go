@@ -0,0 +1,219 @@
+package tests
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/handlers"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/abisalde/authentication-service/pkg/session"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+func rsaSigningKey(t *testing.T, id string) *jwt.SigningKey {
+	t.Helper()
+
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(private)})
+
+	key, err := jwt.ParseSigningKey(id, "RS256", pemBytes)
+	if err != nil {
+		t.Fatalf("Failed to parse signing key: %v", err)
+	}
+	return key
+}
+
+func TestSigningKeys_RotationAndJWKS(t *testing.T) {
+	opts := jwt.DefaultOptions()
+	configureTokenBudget(t, opts)
+	t.Cleanup(func() { jwt.SetKeySet(nil) })
+
+	legacy, err := jwt.GenerateToken("user-public-id", jwt.TokenTypeAccess, time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to issue HS256 token: %v", err)
+	}
+
+	current, next := rsaSigningKey(t, "2025-01"), rsaSigningKey(t, "2025-02")
+	ks, err := jwt.NewKeySet("2025-01", current, next)
+	if err != nil {
+		t.Fatalf("Failed to build key set: %v", err)
+	}
+	jwt.SetKeySet(ks)
+
+	if alg := jwt.SigningAlgorithm(); alg != "RS256" {
+		t.Errorf("Expected RS256 signing, got %s", alg)
+	}
+
+	signed, err := jwt.GenerateToken("user-public-id", jwt.TokenTypeAccess, time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to issue RS256 token: %v", err)
+	}
+	if _, err := jwt.ValidateToken(signed); err != nil {
+		t.Errorf("Expected the RS256 token to validate, got %v", err)
+	}
+	if _, err := jwt.ValidateToken(legacy); err == nil {
+		t.Error("Expected HS256 tokens to be rejected once a key set is active")
+	}
+
+	// Promote the next key: tokens signed with the old primary stay valid
+	// while it is still published.
+	rotated, err := jwt.NewKeySet("2025-02", current, next)
+	if err != nil {
+		t.Fatalf("Failed to rotate key set: %v", err)
+	}
+	jwt.SetKeySet(rotated)
+	if _, err := jwt.ValidateToken(signed); err != nil {
+		t.Errorf("Expected a token of the previous primary to validate, got %v", err)
+	}
+
+	retired, _ := jwt.NewKeySet("2025-02", next)
+	jwt.SetKeySet(retired)
+	if _, err := jwt.ValidateToken(signed); err == nil {
+		t.Error("Expected a token of a retired key to be rejected")
+	}
+
+	jwt.SetKeySet(rotated)
+	app := fiber.New()
+	app.Get("/.well-known/jwks.json", handlers.JWKSHandler())
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/.well-known/jwks.json", nil), -1)
+	if err != nil {
+		t.Fatalf("JWKS request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var set jwt.JWKS
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		t.Fatalf("Failed to decode JWKS: %v", err)
+	}
+	if len(set.Keys) != 2 || set.Keys[0].KeyID != "2025-02" || set.Keys[0].KeyType != "RSA" || set.Keys[0].N == "" {
+		t.Errorf("Expected both keys with the primary first, got %+v", set.Keys)
+	}
+}
+
+func TestSigningKeys_ValidatorFollowsRotation(t *testing.T) {
+	configureTokenBudget(t, jwt.DefaultOptions())
+	t.Cleanup(func() { jwt.SetKeySet(nil) })
+
+	current, next := rsaSigningKey(t, "2025-01"), rsaSigningKey(t, "2025-02")
+	ks, _ := jwt.NewKeySet("2025-01", current)
+	jwt.SetKeySet(ks)
+
+	var fetches int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		_ = json.NewEncoder(w).Encode(jwt.CurrentKeySet().JWKS())
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	keys := session.NewJWKSProvider(srv.URL)
+	keys.Start(ctx, time.Hour)
+	validator := session.NewValidator(keys)
+
+	token, _ := jwt.GenerateToken("user-public-id", jwt.TokenTypeAccess, time.Minute)
+	if _, err := validator.ValidateAccessToken(ctx, token, ""); err != nil {
+		t.Fatalf("Expected the token to validate against the published keys, got %v", err)
+	}
+
+	refresh, _ := jwt.GenerateToken("user-public-id", jwt.TokenTypeRefresh, time.Minute)
+	if _, err := validator.ValidateAccessToken(ctx, refresh, ""); err != session.ErrNotAccessToken {
+		t.Errorf("Expected refresh tokens to be rejected, got %v", err)
+	}
+
+	// Rotate before the next background refresh: the unknown kid is only
+	// fetched again once the refetch interval has passed.
+	rotated, _ := jwt.NewKeySet("2025-02", current, next)
+	jwt.SetKeySet(rotated)
+	rotatedToken, _ := jwt.GenerateToken("user-public-id", jwt.TokenTypeAccess, time.Minute)
+
+	before := atomic.LoadInt32(&fetches)
+	if _, err := validator.ValidateAccessToken(ctx, rotatedToken, ""); err == nil {
+		t.Error("Expected an unknown kid to be rejected within the refetch interval")
+	}
+	if atomic.LoadInt32(&fetches) != before {
+		t.Error("Expected no refetch within the refetch interval")
+	}
+
+	if err := keys.Refresh(ctx); err != nil {
+		t.Fatalf("Failed to refresh keys: %v", err)
+	}
+	if _, err := validator.ValidateAccessToken(ctx, rotatedToken, ""); err != nil {
+		t.Errorf("Expected the rotated token to validate after a refresh, got %v", err)
+	}
+}
+
+func TestSigningKeys_JWKSRevalidatesUntilRotation(t *testing.T) {
+	configureTokenBudget(t, jwt.DefaultOptions())
+	t.Cleanup(func() { jwt.SetKeySet(nil) })
+
+	current, next := rsaSigningKey(t, "2025-01"), rsaSigningKey(t, "2025-02")
+	ks, _ := jwt.NewKeySet("2025-01", current, next)
+	jwt.SetKeySet(ks)
+
+	var statuses []int
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		err := c.Next()
+		statuses = append(statuses, c.Response().StatusCode())
+		return err
+	})
+	app.Get("/.well-known/jwks.json", handlers.JWKSHandler())
+
+	srv := httptest.NewServer(adaptor.FiberApp(app))
+	defer srv.Close()
+	endpoint := srv.URL + "/.well-known/jwks.json"
+
+	resp, err := http.Get(endpoint)
+	if err != nil {
+		t.Fatalf("JWKS request failed: %v", err)
+	}
+	resp.Body.Close()
+	etag := resp.Header.Get("ETag")
+	if etag == "" || resp.Header.Get("Cache-Control") != "public, max-age=300, s-maxage=60" {
+		t.Fatalf("Expected an ETag and cache directives, got %q and %q", etag, resp.Header.Get("Cache-Control"))
+	}
+
+	ctx := context.Background()
+	keys := session.NewJWKSProvider(endpoint)
+	for i := 0; i < 2; i++ {
+		if err := keys.Refresh(ctx); err != nil {
+			t.Fatalf("Failed to refresh keys: %v", err)
+		}
+	}
+	if got := statuses[1:]; len(got) != 2 || got[0] != http.StatusOK || got[1] != http.StatusNotModified {
+		t.Errorf("Expected the second refresh to revalidate with a 304, got %v", got)
+	}
+	if key, err := keys.Key(ctx, "2025-02"); err != nil || key == nil {
+		t.Errorf("Expected a 304 to keep the cached keys, got %v", err)
+	}
+
+	// Rotating swaps the key set, which busts the ETag.
+	rotated, _ := jwt.NewKeySet("2025-02", current, next)
+	jwt.SetKeySet(rotated)
+	req, _ := http.NewRequest("GET", endpoint, nil)
+	req.Header.Set("If-None-Match", etag)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("JWKS request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("ETag") == etag {
+		t.Errorf("Expected a rotation to serve the new set under a new ETag, got %d %q", resp.StatusCode, resp.Header.Get("ETag"))
+	}
+}
@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/metrics"
+	"github.com/abisalde/authentication-service/internal/middleware"
+	"github.com/abisalde/authentication-service/internal/slo"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestSLO_BurnRates(t *testing.T) {
+	tracker := slo.NewTracker(slo.DefaultObjectives())
+
+	for i := 0; i < 100; i++ {
+		tracker.ObserveLogin(100*time.Millisecond, i%2 == 0, "")
+		tracker.ObserveValidation(time.Millisecond, "valid", i < 10, "")
+		tracker.ObserveRefresh(50*time.Millisecond, i >= 4, "")
+	}
+
+	alerts := map[slo.Indicator]string{}
+	for _, s := range tracker.Report().SLOs {
+		alerts[s.Indicator] = s.Alert
+		if len(s.Windows) != 4 || s.Windows[0].Events != 100 {
+			t.Errorf("Expected four windows counting 100 %s events, got %+v", s.Indicator, s.Windows)
+		}
+	}
+
+	// Failed sign-ins still count as good when they were fast.
+	if alerts[slo.LoginLatency] != "ok" {
+		t.Errorf("Expected fast logins to burn nothing, got %q", alerts[slo.LoginLatency])
+	}
+	// 10% errors against a 0.1% budget burn 100 times too fast.
+	if alerts[slo.ValidationErrors] != "page" {
+		t.Errorf("Expected validation failures to page, got %q", alerts[slo.ValidationErrors])
+	}
+	// 4% failures against a 0.5% budget burn 8 times too fast.
+	if alerts[slo.RefreshSuccess] != "ticket" {
+		t.Errorf("Expected refresh failures to open a ticket, got %q", alerts[slo.RefreshSuccess])
+	}
+
+	tracker.ObserveLogin(2*time.Second, true, "")
+	report := tracker.Report()
+	if w := report.SLOs[0].Windows[0]; w.Bad != 1 || w.BurnRate <= 0 {
+		t.Errorf("Expected a slow login to spend budget, got %+v", w)
+	}
+}
+
+func TestSLO_ExemplarsAndEndpoint(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.RequestIDMiddleware)
+	app.Get("/slo", slo.Handler())
+	app.Get("/metrics", metrics.Handler())
+	app.Get("/echo", func(c *fiber.Ctx) error {
+		return c.SendString(c.Get(auth.RequestIDHeader))
+	})
+
+	req := httptest.NewRequest("GET", "/echo", nil)
+	req.Header.Set(auth.RequestIDHeader, "req-slo-1")
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if got := resp.Header.Get(auth.RequestIDHeader); got != "req-slo-1" {
+		t.Errorf("Expected the forwarded request ID to be echoed, got %q", got)
+	}
+	resp, err = app.Test(httptest.NewRequest("GET", "/echo", nil), -1)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if resp.Header.Get(auth.RequestIDHeader) == "" {
+		t.Error("Expected a request ID to be assigned")
+	}
+
+	slo.ObserveLogin(30*time.Millisecond, true, "req-slo-1")
+
+	var plain, open bytes.Buffer
+	if _, err := metrics.Default.WriteTo(&plain); err != nil {
+		t.Fatalf("Failed to render metrics: %v", err)
+	}
+	if _, err := metrics.Default.WriteOpenMetrics(&open); err != nil {
+		t.Fatalf("Failed to render OpenMetrics: %v", err)
+	}
+	if strings.Contains(plain.String(), `request_id="req-slo-1"`) {
+		t.Error("Expected no exemplars in the Prometheus text format")
+	}
+	if !strings.Contains(open.String(), `# {request_id="req-slo-1"} 0.03 `) {
+		t.Errorf("Expected the login exemplar in OpenMetrics, got:\n%s", open.String())
+	}
+	if !strings.HasSuffix(open.String(), "# EOF\n") {
+		t.Error("Expected OpenMetrics output to end with # EOF")
+	}
+
+	req = httptest.NewRequest("GET", "/metrics", nil)
+	req.Header.Set(fiber.HeaderAccept, "application/openmetrics-text; version=1.0.0")
+	resp, err = app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("Metrics request failed: %v", err)
+	}
+	if ct := resp.Header.Get(fiber.HeaderContentType); !strings.HasPrefix(ct, "application/openmetrics-text") {
+		t.Errorf("Expected OpenMetrics to be negotiated, got %q", ct)
+	}
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/slo", nil), -1)
+	if err != nil {
+		t.Fatalf("SLO request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	var report slo.Report
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatalf("Failed to decode /slo: %v", err)
+	}
+	if len(report.SLOs) != 3 || report.SLOs[0].Indicator != slo.LoginLatency || report.SLOs[0].Windows[0].Events == 0 {
+		t.Errorf("Expected the login SLO to report the observed sign-in, got %+v", report.SLOs)
+	}
+}
@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/graph/directives"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+type sentMail struct {
+	recipient string
+	subject   string
+	html      string
+	plain     string
+}
+
+type recordingMailService struct {
+	sent []sentMail
+}
+
+func (m *recordingMailService) SendHTMLEmail(ctx context.Context, recipientEmail, subject, htmlBody, plainTextBody string, overrideSenderEmail ...string) error {
+	m.sent = append(m.sent, sentMail{recipient: recipientEmail, subject: subject, html: htmlBody, plain: plainTextBody})
+	return nil
+}
+
+func TestSuspension_SuspendAndLift(t *testing.T) {
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	mailer := &recordingMailService{}
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, redisCache, mailer)
+
+	created := createVerifiedUser(t, client, "suspended@example.com")
+
+	suspended, err := authService.SuspendUser(ctx, created.PublicID.String(), model.SuspensionReasonFraud)
+	if err != nil {
+		t.Fatalf("Failed to suspend user: %v", err)
+	}
+	if !service.IsSuspended(suspended) || service.SuspensionReason(suspended) != "FRAUD" {
+		t.Fatalf("Expected user suspended for FRAUD, got %v %v", suspended.SuspendedAt, suspended.SuspensionReason)
+	}
+
+	if len(mailer.sent) != 1 {
+		t.Fatalf("Expected one suspension email, got %d", len(mailer.sent))
+	}
+	if mail := mailer.sent[0]; mail.recipient != created.Email || !strings.Contains(mail.html, "FRAUD") || !strings.Contains(mail.plain, "FRAUD") {
+		t.Errorf("Expected suspension email with reason code to %s, got %+v", created.Email, mail)
+	}
+
+	if _, err := authService.SuspendUser(ctx, created.PublicID.String(), model.SuspensionReasonAbuse); err != service.ErrAlreadySuspended {
+		t.Errorf("Expected ErrAlreadySuspended, got %v", err)
+	}
+
+	reinstated, err := authService.LiftSuspension(ctx, created.PublicID.String())
+	if err != nil {
+		t.Fatalf("Failed to lift suspension: %v", err)
+	}
+	if service.IsSuspended(reinstated) || reinstated.SuspensionReason != nil {
+		t.Errorf("Expected suspension cleared, got %v %v", reinstated.SuspendedAt, reinstated.SuspensionReason)
+	}
+
+	if _, err := authService.LiftSuspension(ctx, created.PublicID.String()); err != service.ErrNotSuspended {
+		t.Errorf("Expected ErrNotSuspended, got %v", err)
+	}
+}
+
+func TestSuspension_AuthDirectiveRejectsSuspendedUser(t *testing.T) {
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, redisCache, &recordingMailService{})
+
+	created := createVerifiedUser(t, client, "directive_suspended@example.com")
+	suspended, err := authService.SuspendUser(ctx, created.PublicID.String(), model.SuspensionReasonSpam)
+	if err != nil {
+		t.Fatalf("Failed to suspend user: %v", err)
+	}
+
+	called := false
+	next := graphql.Resolver(func(ctx context.Context) (interface{}, error) {
+		called = true
+		return true, nil
+	})
+
+	role := model.UserRoleUser
+	_, err = directives.NewAuthDirective().Auth(auth.WithUser(ctx, suspended, nil), nil, next, &role)
+	if called {
+		t.Fatal("Expected the resolver not to run for a suspended user")
+	}
+
+	gqlErr, ok := err.(*gqlerror.Error)
+	if !ok || gqlErr.Extensions["code"] != model.ErrorTypeAccountSuspended || gqlErr.Extensions["reason"] != "SPAM" {
+		t.Errorf("Expected ACCOUNT_SUSPENDED error with reason SPAM, got %v", err)
+	}
+}
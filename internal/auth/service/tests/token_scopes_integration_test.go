@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/abisalde/authentication-service/internal/auth/cookies"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/auth/tokenscopes"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database/ent/user"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+)
+
+func scopeConfig(defaults []string, roles, organizations map[string][]string) *configs.Config {
+	cfg := &configs.Config{}
+	cfg.TokenScopes.Default = defaults
+	cfg.TokenScopes.Roles = roles
+	cfg.TokenScopes.Organizations = organizations
+	return cfg
+}
+
+func TestTokenScopes_Validation(t *testing.T) {
+	valid := scopeConfig([]string{"profile"}, map[string][]string{"ADMIN": {"admin", "admin:users"}, "USER": {"orders:read"}}, map[string][]string{"acme": {"billing:read"}})
+	if err := tokenscopes.NewPolicy(valid).Validate(); err != nil {
+		t.Fatalf("Expected admin scopes granted to ADMIN to be valid, got %v", err)
+	}
+
+	invalid := map[string]*configs.Config{
+		"admin in the default":       scopeConfig([]string{"admin"}, nil, nil),
+		"admin:* to USER":            scopeConfig(nil, map[string][]string{"USER": {"admin:users"}}, nil),
+		"admin:* to an organization": scopeConfig(nil, nil, map[string][]string{"acme": {"admin:billing"}}),
+		"a maintenance scope":        scopeConfig(nil, map[string][]string{"ADMIN": {"maintenance:purgeUser"}}, nil),
+		"the service account scope":  scopeConfig([]string{"service_account"}, nil, nil),
+		"an unknown role":            scopeConfig(nil, map[string][]string{"OWNER": {"orders:read"}}, nil),
+		"a scope with whitespace":    scopeConfig([]string{"orders read"}, nil, nil),
+		"a lowercase role":           scopeConfig(nil, map[string][]string{"admin": {"admin"}}, nil),
+	}
+	for name, cfg := range invalid {
+		if err := tokenscopes.NewPolicy(cfg).Validate(); err == nil {
+			t.Errorf("Expected %s to be refused", name)
+		}
+	}
+}
+
+func TestTokenScopes_NoImplicitAdmin(t *testing.T) {
+	// Skips Validate, as a policy built in code could.
+	policy := tokenscopes.NewPolicy(scopeConfig(
+		[]string{"profile", "admin"},
+		map[string][]string{"USER": {"admin:users", "maintenance:purgeUser"}, "ADMIN": {"admin:users"}},
+		map[string][]string{"acme": {"admin:billing", "billing:read"}},
+	))
+
+	for _, role := range []user.Role{user.RoleUSER, user.RoleADMIN} {
+		for _, scope := range policy.For(role, "acme") {
+			if tokenscopes.IsReserved(scope) {
+				t.Errorf("Expected %s never to get the reserved scope %s", role, scope)
+			}
+			if tokenscopes.IsAdmin(scope) && (role != user.RoleADMIN || scope != "admin:users") {
+				t.Errorf("Expected %s not to get %s implicitly", role, scope)
+			}
+		}
+	}
+
+	if got := policy.For(user.RoleADMIN, "acme"); !slices.Equal(got, []string{"admin:users", "billing:read", "profile"}) {
+		t.Errorf("Expected only the ADMIN role's own admin scope, got %v", got)
+	}
+	if got := tokenscopes.NewPolicy(nil).For(user.RoleADMIN, ""); got != nil {
+		t.Errorf("Expected no scopes without a policy, got %v", got)
+	}
+}
+
+func TestTokenScopes_ReevaluatedAtIssuance(t *testing.T) {
+	configureTokenBudget(t, jwt.DefaultOptions())
+
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cfg := scopeConfig(
+		[]string{"profile"},
+		map[string][]string{"ADMIN": {"admin:users"}},
+		map[string][]string{"acme": {"billing:read"}},
+	)
+	authService := service.NewAuthService(repository.NewUserRepository(client), cfg, redisCache, &mockMailService{})
+
+	if _, err := authService.SaveOrganizationBranding(ctx, repository.OrganizationBranding{Slug: "acme", Name: "Acme Corp"}); err != nil {
+		t.Fatalf("Failed to create organization: %v", err)
+	}
+	member := createVerifiedUser(t, client, "scopes@acme.example")
+	slug := "acme"
+	if _, err := authService.AssignOrganization(ctx, member.PublicID.String(), &slug); err != nil {
+		t.Fatalf("Failed to assign organization: %v", err)
+	}
+	admin, err := client.User.UpdateOneID(member.ID).SetRole(user.RoleADMIN).Save(ctx)
+	if err != nil {
+		t.Fatalf("Failed to promote user: %v", err)
+	}
+
+	scopes, err := authService.TokenScopes(ctx, admin)
+	if err != nil || !slices.Equal(scopes, []string{"admin:users", "billing:read", "profile"}) {
+		t.Fatalf("Expected default, role and organization scopes, got %v, %v", scopes, err)
+	}
+	token, err := cookies.GenerateAccessToken(ctx, admin.PublicID.String(), nil, scopes)
+	if err != nil {
+		t.Fatalf("Failed to issue access token: %v", err)
+	}
+	claims, err := jwt.ValidateToken(token)
+	if err != nil || !slices.Equal(claims.Scopes, scopes) {
+		t.Fatalf("Expected the scopes in the access token, got %v, %v", claims, err)
+	}
+
+	// The next refresh loads the user again: the downgrade and the left
+	// organization shrink the scopes.
+	if _, err := authService.AssignOrganization(ctx, member.PublicID.String(), nil); err != nil {
+		t.Fatalf("Failed to leave organization: %v", err)
+	}
+	downgraded, err := client.User.UpdateOneID(member.ID).SetRole(user.RoleUSER).Save(ctx)
+	if err != nil {
+		t.Fatalf("Failed to downgrade user: %v", err)
+	}
+	if scopes, err := authService.TokenScopes(ctx, downgraded); err != nil || !slices.Equal(scopes, []string{"profile"}) {
+		t.Errorf("Expected only the default scope after the downgrade, got %v, %v", scopes, err)
+	}
+}
@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/metrics"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+)
+
+type memoryScopeStore struct {
+	mu   sync.Mutex
+	sets map[string][]string
+}
+
+func (s *memoryScopeStore) Put(ctx context.Context, ref string, scopes []string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sets[ref] = scopes
+	return nil
+}
+
+func (s *memoryScopeStore) Get(ctx context.Context, ref string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	scopes, ok := s.sets[ref]
+	if !ok {
+		return nil, fmt.Errorf("unknown scope reference %s", ref)
+	}
+	return scopes, nil
+}
+
+func configureTokenBudget(t *testing.T, opts jwt.Options) {
+	t.Helper()
+	t.Setenv("JWT_SECRET", "token-size-test-secret")
+
+	if err := jwt.Configure(opts); err != nil {
+		t.Fatalf("Failed to configure jwt: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = jwt.Configure(jwt.DefaultOptions())
+		jwt.SetScopeStore(nil)
+	})
+}
+
+func manyScopes(n int) []string {
+	scopes := make([]string, n)
+	for i := range scopes {
+		scopes[i] = fmt.Sprintf("org:%03d:projects:read", i)
+	}
+	return scopes
+}
+
+func TestTokenSize_CompactRoles(t *testing.T) {
+	opts := jwt.DefaultOptions()
+	opts.CompactRoles = true
+	configureTokenBudget(t, opts)
+
+	token, err := jwt.GenerateTokenWithExtras(context.Background(), "user-1", jwt.TokenTypeAccess, time.Minute, jwt.Extras{Role: "ADMIN"})
+	if err != nil {
+		t.Fatalf("Failed to issue token: %v", err)
+	}
+
+	claims, err := jwt.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("Failed to validate token: %v", err)
+	}
+	if claims.Role != "" || claims.RoleCode == 0 || claims.RoleName() != "ADMIN" {
+		t.Errorf("Expected a compacted ADMIN role, got %+v", claims)
+	}
+}
+
+func TestTokenSize_OversizedScopesBecomeReference(t *testing.T) {
+	opts := jwt.DefaultOptions()
+	opts.MaxTokenBytes = 1024
+	configureTokenBudget(t, opts)
+
+	ctx := context.Background()
+	scopes := manyScopes(60)
+
+	if _, err := jwt.GenerateTokenWithExtras(ctx, "user-1", jwt.TokenTypeAccess, time.Minute, jwt.Extras{Scopes: scopes}); err != jwt.ErrTokenTooLarge {
+		t.Fatalf("Expected ErrTokenTooLarge without a scope store, got %v", err)
+	}
+
+	jwt.SetScopeStore(&memoryScopeStore{sets: make(map[string][]string)})
+
+	token, err := jwt.GenerateTokenWithExtras(ctx, "user-1", jwt.TokenTypeAccess, time.Minute, jwt.Extras{Scopes: scopes})
+	if err != nil {
+		t.Fatalf("Failed to issue token with a scope store: %v", err)
+	}
+	if len(token) > opts.MaxTokenBytes {
+		t.Fatalf("Token of %d bytes exceeds the budget", len(token))
+	}
+
+	claims, err := jwt.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("Failed to validate token: %v", err)
+	}
+	if claims.ScopeRef == "" || len(claims.Scopes) != 0 {
+		t.Fatalf("Expected scopes behind a reference, got %+v", claims)
+	}
+
+	resolved, err := jwt.ResolveScopes(ctx, claims)
+	if err != nil || len(resolved) != len(scopes) {
+		t.Fatalf("Expected %d resolved scopes, got %d (%v)", len(scopes), len(resolved), err)
+	}
+
+	var out strings.Builder
+	if _, err := metrics.Default.WriteTo(&out); err != nil {
+		t.Fatalf("Failed to render metrics: %v", err)
+	}
+	for _, want := range []string{`jwt_token_budget_total{type="access",outcome="trimmed"}`, `jwt_token_budget_total{type="access",outcome="rejected"}`} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %s in metrics output", want)
+		}
+	}
+}
+
+// subjectForSize returns a subject that makes a plain access token about
+// size bytes long under the configured signing key.
+func subjectForSize(t *testing.T, size int) string {
+	t.Helper()
+	base, err := jwt.GenerateToken("u", jwt.TokenTypeAccess, time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to issue token: %v", err)
+	}
+	// Every 3 bytes of claims add 4 bytes of base64.
+	return strings.Repeat("u", 1+(size-len(base))*3/4)
+}
+
+func TestTokenSize_DeviceClaimWithinBudget(t *testing.T) {
+	opts := jwt.DefaultOptions()
+	opts.MaxTokenBytes = 8192
+	configureTokenBudget(t, opts)
+	device := jwt.NewDeviceClaim("device-1", "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)")
+
+	// Probe the sizes under a roomy budget, then tighten it to 512 bytes.
+	fits := subjectForSize(t, 500)
+	overflows := subjectForSize(t, 600)
+	opts.MaxTokenBytes = 512
+	if err := jwt.Configure(opts); err != nil {
+		t.Fatalf("Failed to configure jwt: %v", err)
+	}
+
+	token, err := jwt.GenerateTokenWithDevice("user-1", jwt.TokenTypeAccess, time.Minute, device)
+	if err != nil {
+		t.Fatalf("Failed to issue token: %v", err)
+	}
+	claims, err := jwt.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("Failed to validate token: %v", err)
+	}
+	if claims.Device == nil || *claims.Device != *device {
+		t.Errorf("Expected a token within budget to keep the device claim, got %+v", claims.Device)
+	}
+
+	// The device claim alone tips this one over the budget.
+	token, err = jwt.GenerateTokenWithDevice(fits, jwt.TokenTypeAccess, time.Minute, device)
+	if err != nil {
+		t.Fatalf("Expected the device claim to be dropped instead of failing, got %v", err)
+	}
+	if len(token) > opts.MaxTokenBytes {
+		t.Fatalf("Token of %d bytes exceeds the budget", len(token))
+	}
+	claims, err = jwt.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("Expected the trimmed token to validate, got %v", err)
+	}
+	if claims.Device != nil || claims.Subject != fits {
+		t.Errorf("Expected the device claim dropped and the subject kept, got %+v", claims)
+	}
+
+	if _, err := jwt.GenerateTokenWithDevice(overflows, jwt.TokenTypeAccess, time.Minute, device); err != jwt.ErrTokenTooLarge {
+		t.Errorf("Expected ErrTokenTooLarge when dropping the device claim is not enough, got %v", err)
+	}
+}
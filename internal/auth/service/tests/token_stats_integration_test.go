@@ -0,0 +1,35 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/abisalde/authentication-service/internal/auth/handler/http"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/graph/errors"
+)
+
+func TestTokenStats_ValidationReasons(t *testing.T) {
+	cases := map[error]string{
+		errors.ExpiredToken:     service.TokenExpired,
+		errors.InvalidTokenType: service.TokenWrongType,
+		errors.InvalidToken:     service.TokenInvalid,
+	}
+	for err, want := range cases {
+		if got := service.TokenValidationReason(err); got != want {
+			t.Errorf("Expected reason %s for %v, got %s", want, err, got)
+		}
+	}
+}
+
+func TestTokenStats_RejectsHoursOutsideRetention(t *testing.T) {
+	authService, _, cleanup := setupTestAuthService(t)
+	defer cleanup()
+
+	handler := http.NewDiagnosticsHandler(authService)
+	for _, hours := range []int32{0, -1, 169} {
+		if _, err := handler.GetTokenStats(context.Background(), &hours); err == nil {
+			t.Errorf("Expected hours=%d to be rejected", hours)
+		}
+	}
+}
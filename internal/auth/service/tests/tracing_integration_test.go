@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/graph"
+	"github.com/abisalde/authentication-service/internal/graph/directives"
+	"github.com/abisalde/authentication-service/internal/graph/resolvers"
+	"github.com/abisalde/authentication-service/internal/handlers"
+	"github.com/abisalde/authentication-service/internal/middleware"
+	"github.com/abisalde/authentication-service/internal/ratelimit"
+	"github.com/abisalde/authentication-service/internal/tracing"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing_RequestSpansGraphQLSQLAndRedis(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	if _, err := tracing.Setup(context.Background(), &configs.Config{}); err != nil {
+		t.Fatalf("Failed to set up propagation: %v", err)
+	}
+
+	drv, err := entsql.Open("sqlite3", "file:tracing?mode=memory&cache=shared&_fk=1")
+	if err != nil {
+		t.Fatalf("Failed to open the database: %v", err)
+	}
+	client := ent.NewClient(ent.Driver(tracing.Driver(drv)))
+	defer client.Close()
+	if err := client.Schema.Create(context.Background()); err != nil {
+		t.Fatalf("Failed to create the schema: %v", err)
+	}
+
+	rdb := embeddedRedis(t)
+	tracing.InstrumentRedis(rdb)
+	cache := database.NewCacheService(rdb)
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, cache, &mockMailService{})
+	limiter := ratelimit.New(cache)
+
+	srv := handler.New(graph.NewExecutableSchema(graph.Config{
+		Resolvers: resolvers.NewResolver(client, authService, service.NewOAuthService(authService), limiter),
+		Directives: graph.DirectiveRoot{
+			RateLimit:  directives.NewRateLimitDirective(limiter).RateLimit,
+			Constraint: directives.NewConstraint().Constraints,
+		},
+	}))
+	srv.AddTransport(transport.POST{})
+	srv.AroundOperations(tracing.AroundOperations)
+	srv.AroundResponses(tracing.AroundResponses)
+
+	app := fiber.New()
+	app.Use(middleware.TracingMiddleware)
+	app.Use(adaptor.HTTPMiddleware(middleware.AuthMiddleware(client, authService)))
+	app.Use(middleware.FiberWebMiddleware)
+	app.Post("/graphql", handlers.GraphQLHandler(srv))
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest("POST", "/graphql", strings.NewReader(`{"query":"query CheckEmail { emailStatus(email: \"traced@example.com\") { status } }","operationName":"CheckEmail"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != fiber.StatusOK || !strings.Contains(string(body), "AVAILABLE") {
+		t.Fatalf("Expected the email status, got %d %s", resp.StatusCode, body)
+	}
+
+	byName := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range spans.Ended() {
+		if span.SpanContext().TraceID().String() != traceID {
+			t.Errorf("Expected span %q to continue the caller's trace, got trace %s", span.Name(), span.SpanContext().TraceID())
+		}
+		if _, seen := byName[span.Name()]; !seen {
+			byName[span.Name()] = span
+		}
+	}
+
+	request, ok := byName["POST /graphql"]
+	if !ok {
+		t.Fatalf("Expected a server span for the request, got %v", spanNames(spans))
+	}
+	operation, ok := byName["query CheckEmail"]
+	if !ok || operation.Parent().SpanID() != request.SpanContext().SpanID() {
+		t.Fatalf("Expected the operation span under the request span, got %v", spanNames(spans))
+	}
+	for _, name := range []string{"db.select", "redis.exists"} {
+		span, ok := byName[name]
+		if !ok {
+			t.Errorf("Expected a %s span, got %v", name, spanNames(spans))
+			continue
+		}
+		if !descendsFrom(spans, span, operation) {
+			t.Errorf("Expected %s to run under the operation span", name)
+		}
+	}
+}
+
+func spanNames(recorder *tracetest.SpanRecorder) []string {
+	var names []string
+	for _, span := range recorder.Ended() {
+		names = append(names, span.Name())
+	}
+	return names
+}
+
+// descendsFrom reports whether ancestor is on the parent chain of span.
+func descendsFrom(recorder *tracetest.SpanRecorder, span, ancestor sdktrace.ReadOnlySpan) bool {
+	parents := map[string]string{}
+	for _, s := range recorder.Ended() {
+		parents[s.SpanContext().SpanID().String()] = s.Parent().SpanID().String()
+	}
+	for id := span.Parent().SpanID().String(); id != ""; id = parents[id] {
+		if id == ancestor.SpanContext().SpanID().String() {
+			return true
+		}
+		if _, ok := parents[id]; !ok {
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database/ent/user"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+func TestUserManagement_DeleteAndReactivate(t *testing.T) {
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, redisCache, &mockMailService{})
+
+	admin := createVerifiedUser(t, client, "management_admin@example.com")
+	target := createVerifiedUser(t, client, "management_target@example.com")
+
+	if _, err := authService.DeleteUser(ctx, admin, admin.PublicID.String()); err != service.ErrManageOwnAccount {
+		t.Errorf("Expected ErrManageOwnAccount deleting own account, got %v", err)
+	}
+	if _, err := authService.ReactivateUser(ctx, target.PublicID.String()); err != service.ErrAccountActive {
+		t.Errorf("Expected ErrAccountActive for an active account, got %v", err)
+	}
+
+	deleted, err := authService.DeleteUser(ctx, admin, target.PublicID.String())
+	if err != nil {
+		t.Fatalf("Failed to delete user: %v", err)
+	}
+	if !service.IsDeleted(deleted) {
+		t.Fatal("Expected deletedAt to be set")
+	}
+	if _, err := authService.DeleteUser(ctx, admin, target.PublicID.String()); err != service.ErrAlreadyDeleted {
+		t.Errorf("Expected ErrAlreadyDeleted, got %v", err)
+	}
+
+	reactivated, err := authService.ReactivateUser(ctx, target.PublicID.String())
+	if err != nil {
+		t.Fatalf("Failed to reactivate user: %v", err)
+	}
+	if service.IsDeleted(reactivated) || service.IsSuspended(reactivated) {
+		t.Errorf("Expected the account restored, got deletedAt %v suspendedAt %v", reactivated.DeletedAt, reactivated.SuspendedAt)
+	}
+}
+
+func TestUserManagement_ChangeUserRole(t *testing.T) {
+	client, redisCache, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, redisCache, &mockMailService{})
+
+	admin := createVerifiedUser(t, client, "role_admin@example.com")
+	target := createVerifiedUser(t, client, "role_target@example.com")
+
+	if _, err := authService.ChangeUserRole(ctx, admin, admin.PublicID.String(), model.UserRoleUser); err != service.ErrManageOwnAccount {
+		t.Errorf("Expected ErrManageOwnAccount changing own role, got %v", err)
+	}
+	if _, err := authService.ChangeUserRole(ctx, admin, target.PublicID.String(), model.UserRoleUser); err != service.ErrRoleUnchanged {
+		t.Errorf("Expected ErrRoleUnchanged, got %v", err)
+	}
+
+	promoted, err := authService.ChangeUserRole(ctx, admin, target.PublicID.String(), model.UserRoleAdmin)
+	if err != nil {
+		t.Fatalf("Failed to change role: %v", err)
+	}
+	if promoted.Role != user.RoleADMIN {
+		t.Errorf("Expected ADMIN role, got %s", promoted.Role)
+	}
+}
@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/abisalde/authentication-service/internal/auth/refreshbinding"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/graph/converters"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/abisalde/authentication-service/pkg/useragent"
+)
+
+func TestUserAgent_Parse(t *testing.T) {
+	cases := []struct {
+		name      string
+		userAgent string
+		want      useragent.DeviceInfo
+	}{
+		{
+			"chrome on windows",
+			chromeWindows,
+			useragent.DeviceInfo{Browser: "Chrome", BrowserVersion: "126.0.0.0", OS: "Windows", OSVersion: "10", Type: useragent.DeviceDesktop},
+		},
+		{
+			"firefox on linux",
+			firefoxLinux,
+			useragent.DeviceInfo{Browser: "Firefox", BrowserVersion: "128.0", OS: "Linux", Type: useragent.DeviceDesktop},
+		},
+		{
+			"edge carries a chrome token",
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.2478.80",
+			useragent.DeviceInfo{Browser: "Edge", BrowserVersion: "124.0.2478.80", OS: "Windows", OSVersion: "10", Type: useragent.DeviceDesktop},
+		},
+		{
+			"safari on iphone",
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 17_4_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4.1 Mobile/15E148 Safari/604.1",
+			useragent.DeviceInfo{Browser: "Safari", BrowserVersion: "17.4.1", OS: "iOS", OSVersion: "17.4.1", Type: useragent.DeviceMobile},
+		},
+		{
+			"safari on mac",
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Safari/605.1.15",
+			useragent.DeviceInfo{Browser: "Safari", BrowserVersion: "17.4", OS: "macOS", OSVersion: "10.15.7", Type: useragent.DeviceDesktop},
+		},
+		{
+			"samsung internet on an android phone",
+			"Mozilla/5.0 (Linux; Android 13; SM-S911B) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/24.0 Chrome/117.0.0.0 Mobile Safari/537.36",
+			useragent.DeviceInfo{Browser: "Samsung Internet", BrowserVersion: "24.0", OS: "Android", OSVersion: "13", Model: "SM-S911B", Type: useragent.DeviceMobile},
+		},
+		{
+			"chrome on an android tablet",
+			"Mozilla/5.0 (Linux; Android 14; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/125.0.0.0 Safari/537.36",
+			useragent.DeviceInfo{Browser: "Chrome", BrowserVersion: "125.0.0.0", OS: "Android", OSVersion: "14", Type: useragent.DeviceTablet},
+		},
+		{
+			"ipad",
+			"Mozilla/5.0 (iPad; CPU OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/125.0.6422.80 Mobile/15E148 Safari/604.1",
+			useragent.DeviceInfo{Browser: "Chrome", BrowserVersion: "125.0.6422.80", OS: "iPadOS", OSVersion: "16.6", Type: useragent.DeviceTablet},
+		},
+		{
+			"native http client",
+			"okhttp/4.12.0",
+			useragent.DeviceInfo{Browser: "okhttp", BrowserVersion: "4.12.0", Type: useragent.DeviceOther},
+		},
+		{
+			"search crawler",
+			"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			useragent.DeviceInfo{Browser: "Googlebot", BrowserVersion: "2.1", Type: useragent.DeviceBot, Bot: true},
+		},
+		{
+			"unnamed crawler",
+			"Mozilla/5.0 (compatible; AhrefsBot/7.0; +http://ahrefs.com/robot/)",
+			useragent.DeviceInfo{Browser: "AhrefsBot", BrowserVersion: "7.0", Type: useragent.DeviceBot, Bot: true},
+		},
+		{
+			"headless browser",
+			"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) HeadlessChrome/126.0.0.0 Safari/537.36",
+			useragent.DeviceInfo{Browser: "HeadlessChrome", BrowserVersion: "126.0.0.0", OS: "Linux", Type: useragent.DeviceBot, Bot: true},
+		},
+		{"empty", "", useragent.DeviceInfo{}},
+	}
+
+	for _, tc := range cases {
+		if got := useragent.Parse(tc.userAgent); got != tc.want {
+			t.Errorf("%s: expected %+v, got %+v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestUserAgent_ClientHints(t *testing.T) {
+	// Chromium's reduced User-Agent freezes versions and hides Windows 11.
+	hints := useragent.HintsFrom(headers{
+		useragent.HeaderUA:              `"Chromium";v="126", "Google Chrome";v="126", "Not-A.Brand";v="8"`,
+		useragent.HeaderMobile:          "?0",
+		useragent.HeaderPlatform:        `"Windows"`,
+		useragent.HeaderPlatformVersion: `"15.0.0"`,
+		useragent.HeaderFullVersionList: `"Chromium";v="126.0.6478.127", "Google Chrome";v="126.0.6478.127", "Not-A.Brand";v="8.0.0.0"`,
+	}.get)
+
+	got := useragent.ParseWithHints(chromeWindows, hints)
+	want := useragent.DeviceInfo{Browser: "Chrome", BrowserVersion: "126.0.6478.127", OS: "Windows", OSVersion: "11", Type: useragent.DeviceDesktop}
+	if got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+	if got.String() != "Chrome 126 on Windows 11" {
+		t.Errorf("Unexpected description %q", got.String())
+	}
+
+	// Low-entropy hints alone name the brand the User-Agent hides.
+	brave := useragent.HintsFrom(headers{
+		useragent.HeaderUA:       `"Not/A)Brand";v="8", "Chromium";v="126", "Brave";v="126"`,
+		useragent.HeaderMobile:   "?1",
+		useragent.HeaderPlatform: `"Android"`,
+		useragent.HeaderModel:    `"Pixel 8"`,
+	}.get)
+	got = useragent.ParseWithHints("Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Mobile Safari/537.36", brave)
+	want = useragent.DeviceInfo{Browser: "Brave", BrowserVersion: "126.0.0.0", OS: "Android", OSVersion: "10", Model: "Pixel 8", Type: useragent.DeviceMobile}
+	if got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+
+	attempt := converters.LoginAttemptToGraph(&ent.LoginAttempt{UserAgent: chromeWindows, ClientHints: hints})
+	if d := attempt.Device; d.Os == nil || *d.Os != "Windows" || d.OsVersion == nil || *d.OsVersion != "11" || d.Type == nil || *d.Type != model.DeviceTypeDesktop {
+		t.Errorf("Expected login history to list the hinted device, got %+v", d)
+	}
+
+	if !useragent.HintsFrom(headers{}.get).IsZero() {
+		t.Error("Expected no hints from a request without them")
+	}
+}
+
+func TestUserAgent_PlatformsAndFamiliesStayStable(t *testing.T) {
+	cases := []struct {
+		userAgent, platform, family string
+	}{
+		{chromeWindows, "windows", "chrome/windows"},
+		{firefoxLinux, "linux", "firefox/linux"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1", "ios", "safari/ios"},
+		{"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Mobile Safari/537.36", "android", "chrome/android"},
+		{"MyApp/3.1 CFNetwork/1494.0.7 Darwin/23.4.0", "other", "cfnetwork/other"},
+		{"okhttp/4.12.0", "other", "okhttp/other"},
+		{"", "", ""},
+	}
+
+	for _, tc := range cases {
+		if got := jwt.DetectPlatform(tc.userAgent); got != tc.platform {
+			t.Errorf("%q: expected platform %q, got %q", tc.userAgent, tc.platform, got)
+		}
+		if got := refreshbinding.UAFamily(tc.userAgent); got != tc.family {
+			t.Errorf("%q: expected family %q, got %q", tc.userAgent, tc.family, got)
+		}
+	}
+}
+
+type headers map[string]string
+
+func (h headers) get(name string) string {
+	return h[name]
+}
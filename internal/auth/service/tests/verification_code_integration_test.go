@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/pkg/verification"
+)
+
+func TestVerificationCode_HashedAtRestWithAttempts(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(rdb), &mockMailService{})
+
+	email := "hashed_code@example.com"
+	pending := model.PendingUser{Email: email, HashPassword: "hash", VerificationCode: "4821", ExpiresAt: time.Now().Add(5 * time.Minute)}
+	if err := authService.CreatePendingUser(ctx, pending); err != nil {
+		t.Fatalf("Failed to create the pending user: %v", err)
+	}
+
+	raw := rdb.Get(ctx, "pending_user:"+email).Val()
+	if strings.Contains(raw, "4821") || !strings.Contains(raw, "code_hash") {
+		t.Fatalf("Expected only the hash of the code to be stored, got %s", raw)
+	}
+
+	for i := 0; i < 5; i++ {
+		if _, err := authService.VerifyCodeAndCreateUser(ctx, email, fmt.Sprintf("000%d", i)); err != errors.OTPCodeNotValid {
+			t.Fatalf("Expected wrong code %d to be refused, got %v", i, err)
+		}
+	}
+	if _, err := authService.VerifyCodeAndCreateUser(ctx, email, "4821"); err != errors.OTPCodeExpire {
+		t.Fatalf("Expected the code used up after five wrong guesses, got %v", err)
+	}
+
+	// A resent code starts over.
+	stored, err := authService.GetPendingUser(ctx, email)
+	if err != nil {
+		t.Fatalf("Failed to load the pending user: %v", err)
+	}
+	stored.VerificationCode = "7310"
+	if err := authService.UpdatePendingUser(ctx, *stored); err != nil {
+		t.Fatalf("Failed to resend the code: %v", err)
+	}
+	user, err := authService.VerifyCodeAndCreateUser(ctx, email, "7310")
+	if err != nil || user == nil || !user.IsEmailVerified {
+		t.Fatalf("Expected the resent code to create the account, got %v, %v", user, err)
+	}
+}
+
+func TestVerificationCode_SaltedPerCode(t *testing.T) {
+	first, err := verification.NewStoredCode("1234")
+	if err != nil {
+		t.Fatalf("Failed to hash the code: %v", err)
+	}
+	second, _ := verification.NewStoredCode("1234")
+
+	if first.Hash == second.Hash || first.Salt == second.Salt {
+		t.Error("Expected equal codes to be stored under different salts and hashes")
+	}
+	if !first.Matches("1234") || first.Matches("1235") || (verification.StoredCode{}).Matches("") {
+		t.Error("Expected a stored code to match only its own code")
+	}
+}
+
+func TestVerificationCode_ConcurrentGuessesCounted(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(embeddedRedis(t)), &mockMailService{})
+
+	email := "parallel_guesses@example.com"
+	pending := model.PendingUser{Email: email, HashPassword: "hash", VerificationCode: "4821", ExpiresAt: time.Now().Add(5 * time.Minute)}
+	if err := authService.CreatePendingUser(ctx, pending); err != nil {
+		t.Fatalf("Failed to create the pending user: %v", err)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		compared int
+	)
+	for i := 0; i < 40; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := authService.VerifyCodeAndCreateUser(ctx, email, fmt.Sprintf("%04d", 5000+i))
+			if err == errors.OTPCodeNotValid {
+				mu.Lock()
+				compared++
+				mu.Unlock()
+			} else if err != errors.OTPCodeExpire {
+				t.Errorf("Expected guess %d to be refused, got %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if compared != 5 {
+		t.Errorf("Expected exactly five of the parallel guesses to be compared, got %d", compared)
+	}
+	if _, err := authService.VerifyCodeAndCreateUser(ctx, email, "4821"); err != errors.OTPCodeExpire {
+		t.Fatalf("Expected the code used up by the parallel guesses, got %v", err)
+	}
+}
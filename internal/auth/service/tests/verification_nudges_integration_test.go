@@ -0,0 +1,228 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/metrics"
+	"github.com/abisalde/authentication-service/pkg/mail"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	nudgeCode        = regexp.MustCompile(`passcode: (\S+)`)
+	nudgeUnsubscribe = regexp.MustCompile(`Stop these reminders: (\S+)`)
+)
+
+func nudgeConfig() *configs.Config {
+	cfg := &configs.Config{}
+	cfg.VerificationNudges.Enabled = true
+	cfg.VerificationNudges.Delay = time.Hour
+	cfg.VerificationNudges.UnsubscribeURL = "https://app.example.com/stop-reminders"
+	return cfg
+}
+
+// makeNudgeDue moves the next nudge of the registration to now.
+func makeNudgeDue(t *testing.T, rdb *redis.Client, email string) {
+	t.Helper()
+	if err := rdb.ZAdd(context.Background(), service.VerificationNudgesKey, redis.Z{Score: float64(time.Now().Add(-time.Minute).Unix()), Member: email}).Err(); err != nil {
+		t.Fatalf("Failed to make the nudge due: %v", err)
+	}
+}
+
+// metricValue reads one series from the default registry.
+func metricValue(t *testing.T, series string) float64 {
+	t.Helper()
+	var out strings.Builder
+	if _, err := metrics.Default.WriteTo(&out); err != nil {
+		t.Fatalf("Failed to render metrics: %v", err)
+	}
+	for _, line := range strings.Split(out.String(), "\n") {
+		if value, found := strings.CutPrefix(line, series+" "); found {
+			n, _ := strconv.ParseFloat(value, 64)
+			return n
+		}
+	}
+	return 0
+}
+
+func TestVerificationNudges_SendsFreshCode(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	outbox := &mail.CaptureMailService{}
+	authService := service.NewAuthService(repository.NewUserRepository(client), nudgeConfig(), database.NewCacheService(rdb), outbox)
+
+	email := "nudged@example.com"
+	pending := model.PendingUser{Email: email, HashPassword: "hash", VerificationCode: "4821", ExpiresAt: time.Now().Add(5 * time.Minute)}
+	if err := authService.CreatePendingUser(ctx, pending); err != nil {
+		t.Fatalf("Failed to create the pending user: %v", err)
+	}
+
+	if sent, err := authService.NudgePendingVerifications(ctx); err != nil || sent != 0 {
+		t.Fatalf("Expected no nudge before the delay, got %d, %v", sent, err)
+	}
+
+	makeNudgeDue(t, rdb, email)
+	if sent, err := authService.NudgePendingVerifications(ctx); err != nil || sent != 1 {
+		t.Fatalf("Expected one nudge, got %d, %v", sent, err)
+	}
+	messages := outbox.Messages(email)
+	if len(messages) != 1 || !strings.HasPrefix(messages[0].Subject, "Finish Signing Up") {
+		t.Fatalf("Expected a nudge email, got %+v", messages)
+	}
+	match := nudgeCode.FindStringSubmatch(messages[0].TextBody)
+	if match == nil {
+		t.Fatalf("Expected a fresh code in the nudge, got %q", messages[0].TextBody)
+	}
+
+	// One nudge per registration by default.
+	makeNudgeDue(t, rdb, email)
+	if sent, _ := authService.NudgePendingVerifications(ctx); sent != 0 {
+		t.Errorf("Expected a registration to be nudged once, got %d more", sent)
+	}
+	if rdb.ZScore(ctx, service.VerificationNudgesKey, email).Err() != redis.Nil {
+		t.Error("Expected no further nudge to be scheduled")
+	}
+
+	if _, err := authService.VerifyCodeAndCreateUser(ctx, email, "4821"); err != errors.OTPCodeNotValid {
+		t.Errorf("Expected the replaced code to be refused, got %v", err)
+	}
+
+	verifiedAfterNudge := metricValue(t, `email_verifications_total{nudged="true"}`)
+	user, err := authService.VerifyCodeAndCreateUser(ctx, email, match[1])
+	if err != nil || user == nil {
+		t.Fatalf("Expected the nudged code to create the account, got %v", err)
+	}
+	if got := metricValue(t, `email_verifications_total{nudged="true"}`); got != verifiedAfterNudge+1 {
+		t.Errorf("Expected the verification to count as nudged, got %v after %v", got, verifiedAfterNudge)
+	}
+	if rdb.ZScore(ctx, service.PendingVerificationsKey, email).Err() != redis.Nil {
+		t.Error("Expected a verified registration to stop counting as pending")
+	}
+}
+
+func TestVerificationNudges_HourlyCapAndUnsubscribe(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	outbox := &mail.CaptureMailService{}
+	cfg := nudgeConfig()
+	cfg.VerificationNudges.MaxPerRegistration = 3
+	cfg.VerificationNudges.HourlyCap = 1
+	authService := service.NewAuthService(repository.NewUserRepository(client), cfg, database.NewCacheService(rdb), outbox)
+
+	emails := []string{"capped_one@example.com", "capped_two@example.com"}
+	for _, email := range emails {
+		pending := model.PendingUser{Email: email, HashPassword: "hash", VerificationCode: "4821", ExpiresAt: time.Now().Add(5 * time.Minute)}
+		if err := authService.CreatePendingUser(ctx, pending); err != nil {
+			t.Fatalf("Failed to create the pending user: %v", err)
+		}
+		makeNudgeDue(t, rdb, email)
+	}
+
+	if sent, err := authService.NudgePendingVerifications(ctx); err != nil || sent != 1 {
+		t.Fatalf("Expected the hourly cap to allow one nudge, got %d, %v", sent, err)
+	}
+	if due := rdb.ZCount(ctx, service.VerificationNudgesKey, "-inf", strconv.FormatInt(time.Now().Unix(), 10)).Val(); due != 1 {
+		t.Errorf("Expected the capped registration to stay due, got %d due", due)
+	}
+
+	var nudged string
+	var messages []mail.CapturedMessage
+	for _, email := range emails {
+		if messages = outbox.Messages(email); len(messages) == 1 {
+			nudged = email
+			break
+		}
+	}
+	match := nudgeUnsubscribe.FindStringSubmatch(messages[0].TextBody)
+	if match == nil {
+		t.Fatalf("Expected an unsubscribe link in the nudge, got %q", messages[0].TextBody)
+	}
+	link, err := url.Parse(match[1])
+	if err != nil {
+		t.Fatalf("Invalid unsubscribe link %q: %v", match[1], err)
+	}
+	token := link.Query().Get("token")
+
+	if err := authService.StopVerificationNudges(ctx, token); err != nil {
+		t.Fatalf("Failed to stop the nudges: %v", err)
+	}
+	if err := authService.StopVerificationNudges(ctx, token); err != service.ErrUnsubscribeTokenInvalid {
+		t.Errorf("Expected the unsubscribe token to work once, got %v", err)
+	}
+	if rdb.ZScore(ctx, service.VerificationNudgesKey, nudged).Err() != redis.Nil {
+		t.Error("Expected an unsubscribed registration to get no more nudges")
+	}
+
+	// A resent code does not bring the nudges back.
+	stored, err := authService.GetPendingUser(ctx, nudged)
+	if err != nil {
+		t.Fatalf("Expected the registration to stay after unsubscribing: %v", err)
+	}
+	stored.VerificationCode = "7310"
+	if err := authService.UpdatePendingUser(ctx, *stored); err != nil {
+		t.Fatalf("Failed to resend the code: %v", err)
+	}
+	if rdb.ZScore(ctx, service.VerificationNudgesKey, nudged).Err() != redis.Nil {
+		t.Error("Expected a resend to keep the nudges stopped")
+	}
+}
+
+func TestVerificationNudges_PendingCohorts(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	authService := service.NewAuthService(repository.NewUserRepository(client), &configs.Config{}, database.NewCacheService(rdb), &mockMailService{})
+
+	for i, age := range []time.Duration{10 * time.Minute, 2 * time.Hour, 3 * time.Hour, 3 * 24 * time.Hour} {
+		pending := model.PendingUser{
+			Email:            fmt.Sprintf("cohort_%d@example.com", i),
+			HashPassword:     "hash",
+			VerificationCode: "4821",
+			CreatedAt:        time.Now().Add(-age),
+			ExpiresAt:        time.Now().Add(5 * time.Minute),
+		}
+		if err := authService.CreatePendingUser(ctx, pending); err != nil {
+			t.Fatalf("Failed to create the pending user: %v", err)
+		}
+	}
+	// Expired registrations fall out of the counts.
+	rdb.ZAdd(ctx, service.PendingVerificationsKey, redis.Z{Score: float64(time.Now().Add(-40 * 24 * time.Hour).Unix()), Member: "expired@example.com"})
+
+	if sent, err := authService.NudgePendingVerifications(ctx); err != nil || sent != 0 {
+		t.Fatalf("Expected metrics only with nudges disabled, got %d, %v", sent, err)
+	}
+
+	want := map[string]float64{"under_1h": 1, "1h_to_1d": 2, "1d_to_7d": 1, "7d_to_30d": 0}
+	for cohort, n := range want {
+		if got := metricValue(t, fmt.Sprintf(`pending_email_verifications{cohort=%q}`, cohort)); got != n {
+			t.Errorf("Expected %v pending in cohort %s, got %v", n, cohort, got)
+		}
+	}
+	if rdb.ZScore(ctx, service.PendingVerificationsKey, "expired@example.com").Err() != redis.Nil {
+		t.Error("Expected the expired registration to be dropped")
+	}
+	if rdb.Exists(ctx, service.VerificationNudgesKey).Val() != 0 {
+		t.Error("Expected no nudges scheduled while they are disabled")
+	}
+}
@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+)
+
+func TestWebhookDeliveries_TestEventsAndSignedReplay(t *testing.T) {
+	client, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	var (
+		mu       sync.Mutex
+		status   = http.StatusOK
+		received []*http.Request
+		bodies   []string
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, r)
+		bodies = append(bodies, string(body))
+		w.WriteHeader(status)
+	}))
+	defer receiver.Close()
+
+	ctx := context.Background()
+	rdb := embeddedRedis(t)
+	cfg := &configs.Config{}
+	unconfigured := service.NewAuthService(repository.NewUserRepository(client), cfg, database.NewCacheService(rdb), &mockMailService{})
+	if _, err := unconfigured.SendTestWebhook(ctx); err != service.ErrWebhookNotConfigured {
+		t.Fatalf("Expected ErrWebhookNotConfigured without a webhook, got %v", err)
+	}
+
+	cfg = &configs.Config{}
+	cfg.RefreshReminder.WebhookURL = receiver.URL
+	cfg.RefreshReminder.WebhookSecret = "whsec-test"
+	authService := service.NewAuthService(repository.NewUserRepository(client), cfg, database.NewCacheService(rdb), &mockMailService{})
+
+	delivered, err := authService.SendTestWebhook(ctx)
+	if err != nil {
+		t.Fatalf("Failed to send the test webhook: %v", err)
+	}
+	if delivered.StatusCode != http.StatusOK || delivered.Error != "" || !delivered.Test {
+		t.Errorf("Expected an accepted test delivery, got %+v", delivered)
+	}
+	if received[0].Header.Get(service.WebhookTestHeader) != "true" {
+		t.Error("Expected the test header on a test event")
+	}
+	if !strings.Contains(bodies[0], `"event_type":"refresh_token_expiring"`) {
+		t.Errorf("Expected a refresh_token_expiring sample, got %s", bodies[0])
+	}
+	assertWebhookSignature(t, received[0].Header.Get(service.WebhookSignatureHeader), bodies[0])
+
+	mu.Lock()
+	status = http.StatusInternalServerError
+	mu.Unlock()
+	failed, err := authService.SendTestWebhook(ctx)
+	if err != nil {
+		t.Fatalf("Expected a rejected delivery to be returned, got %v", err)
+	}
+	if failed.StatusCode != http.StatusInternalServerError || failed.Error == "" {
+		t.Errorf("Expected the receiver's 500 to be recorded, got %+v", failed)
+	}
+
+	mu.Lock()
+	status = http.StatusOK
+	mu.Unlock()
+	replayed, err := authService.RedeliverWebhook(ctx, failed.ID)
+	if err != nil {
+		t.Fatalf("Failed to redeliver: %v", err)
+	}
+	if replayed.RedeliveryOf != failed.ID || replayed.StatusCode != http.StatusOK || replayed.Payload != failed.Payload {
+		t.Errorf("Expected the failed event delivered again, got %+v", replayed)
+	}
+	if bodies[2] != bodies[1] {
+		t.Error("Expected the redelivery to post the original body")
+	}
+	assertWebhookSignature(t, received[2].Header.Get(service.WebhookSignatureHeader), bodies[2])
+
+	if _, err := authService.RedeliverWebhook(ctx, "0-1"); err != service.ErrWebhookDeliveryNotFound {
+		t.Errorf("Expected ErrWebhookDeliveryNotFound, got %v", err)
+	}
+
+	deliveries, err := authService.ListWebhookDeliveries(ctx, 10)
+	if err != nil || len(deliveries) != 3 {
+		t.Fatalf("Expected three recorded deliveries, got %d (%v)", len(deliveries), err)
+	}
+	if deliveries[0].ID != replayed.ID || deliveries[2].ID != delivered.ID {
+		t.Error("Expected deliveries newest first")
+	}
+	if deliveries[1].StatusCode != http.StatusInternalServerError || deliveries[1].URL != receiver.URL {
+		t.Errorf("Expected the failed delivery with its status and URL, got %+v", deliveries[1])
+	}
+}
+
+func assertWebhookSignature(t *testing.T, header, body string) {
+	t.Helper()
+	timestamp, _, ok := strings.Cut(strings.TrimPrefix(header, "t="), ",")
+	if !ok {
+		t.Fatalf("Expected a signature header, got %q", header)
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || time.Since(time.Unix(seconds, 0)) > time.Minute {
+		t.Errorf("Expected a fresh signature timestamp, got %q", timestamp)
+	}
+	if expected := service.SignWebhook("whsec-test", time.Unix(seconds, 0), []byte(body)); header != expected {
+		t.Errorf("Expected signature %q, got %q", expected, header)
+	}
+}
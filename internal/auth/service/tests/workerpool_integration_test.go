@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abisalde/authentication-service/pkg/workerpool"
+)
+
+func TestWorkerPool_DropsJobsBeyondQueue(t *testing.T) {
+	pool := workerpool.New("test_drop", 1, 1, workerpool.PolicyDrop)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var ran sync.WaitGroup
+
+	ran.Add(2)
+	if err := pool.Submit(context.Background(), func() {
+		close(started)
+		<-release
+		ran.Done()
+	}); err != nil {
+		t.Fatalf("Expected first job to be accepted: %v", err)
+	}
+	<-started
+
+	if err := pool.Submit(context.Background(), func() { ran.Done() }); err != nil {
+		t.Fatalf("Expected queued job to be accepted: %v", err)
+	}
+
+	if err := pool.Submit(context.Background(), func() {}); err != workerpool.ErrQueueFull {
+		t.Fatalf("Expected ErrQueueFull once the queue is full, got %v", err)
+	}
+
+	close(release)
+	ran.Wait()
+	pool.Close()
+
+	if err := pool.Submit(context.Background(), func() {}); err != workerpool.ErrClosed {
+		t.Errorf("Expected ErrClosed after Close, got %v", err)
+	}
+}
+
+func TestWorkerPool_CloseWakesBlockedSubmitters(t *testing.T) {
+	pool := workerpool.New("test_block_close", 1, 1, workerpool.PolicyBlock)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var queuedRan sync.WaitGroup
+
+	if err := pool.Submit(context.Background(), func() {
+		close(started)
+		<-release
+	}); err != nil {
+		t.Fatalf("Expected first job to be accepted: %v", err)
+	}
+	<-started
+
+	queuedRan.Add(1)
+	if err := pool.Submit(context.Background(), func() { queuedRan.Done() }); err != nil {
+		t.Fatalf("Expected queued job to be accepted: %v", err)
+	}
+
+	// The queue is full, so this submitter waits for space.
+	blocked := make(chan error, 1)
+	go func() {
+		blocked <- pool.Submit(context.Background(), func() {})
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		pool.Close()
+		close(closed)
+	}()
+
+	select {
+	case err := <-blocked:
+		if err != workerpool.ErrClosed {
+			t.Errorf("Expected the blocked submitter to get ErrClosed, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected Close to wake the blocked submitter")
+	}
+
+	close(release)
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected Close to return once the queued jobs finished")
+	}
+	queuedRan.Wait()
+}
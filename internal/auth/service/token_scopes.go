@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+
+	"github.com/abisalde/authentication-service/internal/auth/tokenscopes"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+)
+
+func (s *AuthService) ScopePolicy() tokenscopes.Policy {
+	return s.scopes
+}
+
+// TokenScopes evaluates the scope policy for u. It runs at every issuance,
+// refreshes included, so a downgraded role or a left organization shrinks
+// the scopes of the next access token.
+func (s *AuthService) TokenScopes(ctx context.Context, u *ent.User) ([]string, error) {
+	if !s.scopes.Enabled() {
+		return nil, nil
+	}
+
+	organization := ""
+	if u.OrganizationID != nil && len(s.scopes.Organizations) > 0 {
+		org, err := s.userRepo.GetOrganization(ctx, *u.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		organization = org.Slug
+	}
+	return s.scopes.For(u.Role, organization), nil
+}
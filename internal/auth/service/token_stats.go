@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	TokenStatsPrefix = "token_stats:"
+
+	// TokenStatsRetention bounds how far back tokenStats can aggregate.
+	TokenStatsRetention = 7 * 24 * time.Hour
+
+	tokenStatsHourLayout = "2006010215"
+)
+
+// Validation failure reasons counted by RecordTokenValidationError.
+const (
+	TokenInvalid     = "invalid"
+	TokenExpired     = "expired"
+	TokenWrongType   = "wrong_type"
+	TokenBlacklisted = "blacklisted"
+	TokenRevoked     = "revoked"
+	TokenIdle        = "idle"
+	TokenUnknownUser = "unknown_user"
+)
+
+const (
+	statIssuedPrefix     = "issued:"
+	statRefreshSucceeded = "refresh:success"
+	statRefreshFailed    = "refresh:failure"
+	statValidationPrefix = "validation:"
+)
+
+// TokenStatsHour holds the counters of one UTC hour.
+type TokenStatsHour struct {
+	Hour             time.Time
+	AccessIssued     int64
+	RefreshIssued    int64
+	RefreshSucceeded int64
+	RefreshFailed    int64
+	ValidationErrors map[string]int64
+}
+
+type TokenStats struct {
+	Hours         []TokenStatsHour
+	BlacklistSize int64
+}
+
+// RecordTokenIssued counts issued tokens in the current hour. Counting is
+// best effort and never fails issuance.
+func (s *AuthService) RecordTokenIssued(ctx context.Context, types ...jwt.TokenType) {
+	fields := make([]string, 0, len(types))
+	for _, t := range types {
+		fields = append(fields, statIssuedPrefix+string(t))
+	}
+	s.incrTokenStats(ctx, fields...)
+}
+
+func (s *AuthService) RecordRefresh(ctx context.Context, succeeded bool) {
+	if succeeded {
+		s.incrTokenStats(ctx, statRefreshSucceeded)
+		return
+	}
+	s.incrTokenStats(ctx, statRefreshFailed)
+}
+
+func (s *AuthService) RecordTokenValidationError(ctx context.Context, reason string) {
+	s.incrTokenStats(ctx, statValidationPrefix+reason)
+}
+
+// TokenValidationReason maps a jwt.ValidateToken error onto a reason.
+func TokenValidationReason(err error) string {
+	switch err {
+	case errors.ExpiredToken:
+		return TokenExpired
+	case errors.InvalidTokenType:
+		return TokenWrongType
+	default:
+		return TokenInvalid
+	}
+}
+
+// ServiceSideTokenFailure reports whether a validation failure reason points
+// at the service, such as tokens it cannot verify or whose user it cannot
+// find, rather than at a client holding an expired or revoked token.
+func ServiceSideTokenFailure(reason string) bool {
+	switch reason {
+	case TokenInvalid, TokenWrongType, TokenUnknownUser:
+		return true
+	default:
+		return false
+	}
+}
+
+func tokenStatsKey(hour time.Time) string {
+	return TokenStatsPrefix + hour.UTC().Format(tokenStatsHourLayout)
+}
+
+func (s *AuthService) incrTokenStats(ctx context.Context, fields ...string) {
+	if len(fields) == 0 {
+		return
+	}
+
+	key := tokenStatsKey(time.Now())
+	pipe := s.cache.RawClient().Pipeline()
+	for _, field := range fields {
+		pipe.HIncrBy(ctx, key, field, 1)
+	}
+	pipe.Expire(ctx, key, TokenStatsRetention)
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.logger.Warn("Failed to record token stats", "error", err)
+	}
+}
+
+// TokenStats returns the counters of the last hours UTC hours, oldest first,
+// including the current partial hour.
+func (s *AuthService) TokenStats(ctx context.Context, hours int) (*TokenStats, error) {
+	client := s.cache.RawClient()
+	now := time.Now().UTC().Truncate(time.Hour)
+
+	pipe := client.Pipeline()
+	starts := make([]time.Time, hours)
+	cmds := make([]*redis.MapStringStringCmd, hours)
+	for i := range starts {
+		starts[i] = now.Add(-time.Duration(hours-1-i) * time.Hour)
+		cmds[i] = pipe.HGetAll(ctx, tokenStatsKey(starts[i]))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	stats := &TokenStats{Hours: make([]TokenStatsHour, 0, hours)}
+	for i, cmd := range cmds {
+		stats.Hours = append(stats.Hours, parseTokenStatsHour(starts[i], cmd.Val()))
+	}
+
+	blacklisted, err := s.countKeys(ctx, "blacklist:*")
+	if err != nil {
+		return nil, err
+	}
+	stats.BlacklistSize = blacklisted
+	return stats, nil
+}
+
+func parseTokenStatsHour(hour time.Time, values map[string]string) TokenStatsHour {
+	h := TokenStatsHour{Hour: hour, ValidationErrors: make(map[string]int64)}
+	for field, raw := range values {
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			continue
+		}
+		switch {
+		case field == statIssuedPrefix+string(jwt.TokenTypeAccess):
+			h.AccessIssued = n
+		case field == statIssuedPrefix+string(jwt.TokenTypeRefresh):
+			h.RefreshIssued = n
+		case field == statRefreshSucceeded:
+			h.RefreshSucceeded = n
+		case field == statRefreshFailed:
+			h.RefreshFailed = n
+		case strings.HasPrefix(field, statValidationPrefix):
+			h.ValidationErrors[strings.TrimPrefix(field, statValidationPrefix)] = n
+		}
+	}
+	return h
+}
+
+// ValidationErrorTotals sums validation errors per reason, sorted by reason.
+func (s *TokenStats) ValidationErrorTotals() ([]string, map[string]int64) {
+	totals := make(map[string]int64)
+	for _, h := range s.Hours {
+		for reason, n := range h.ValidationErrors {
+			totals[reason] += n
+		}
+	}
+	reasons := make([]string, 0, len(totals))
+	for reason := range totals {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	return reasons, totals
+}
+
+func (s *AuthService) countKeys(ctx context.Context, pattern string) (int64, error) {
+	var (
+		cursor uint64
+		total  int64
+	)
+	for {
+		keys, next, err := s.cache.RawClient().Scan(ctx, cursor, pattern, 1000).Result()
+		if err != nil {
+			return 0, fmt.Errorf("scan %s: %w", pattern, err)
+		}
+		total += int64(len(keys))
+		cursor = next
+		if cursor == 0 {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/database/ent/user"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+var (
+	ErrAlreadyDeleted   = errors.New("account already deleted")
+	ErrAccountActive    = errors.New("account is neither deleted, deactivated nor suspended")
+	ErrRoleUnchanged    = errors.New("user already has the role")
+	ErrManageOwnAccount = errors.New("admins cannot delete or change the role of their own account")
+)
+
+// IsDeleted reports whether the account was soft deleted, by an admin or its
+// owner. Repository lookups leave deleted accounts out, so sign-in, refresh
+// and the @auth directive treat them as unknown until reactivated or purged.
+func IsDeleted(u *ent.User) bool {
+	return u != nil && u.DeletedAt != nil
+}
+
+// DeleteUser soft deletes the account and revokes every token issued so
+// far. As with suspensions, the database state is authoritative and a
+// failed revocation is only logged.
+func (s *AuthService) DeleteUser(ctx context.Context, actor *ent.User, ref string) (*ent.User, error) {
+	target, err := s.ResolveUserReference(repository.IncludeDeleted(ctx), ref)
+	if err != nil {
+		return nil, err
+	}
+	if actor != nil && actor.ID == target.ID {
+		return nil, ErrManageOwnAccount
+	}
+	if IsDeleted(target) {
+		return nil, ErrAlreadyDeleted
+	}
+
+	return s.DeleteAccount(ctx, target)
+}
+
+// ReactivateUser restores a deleted, deactivated or suspended account that
+// has not been purged yet. Tokens revoked at the time stay revoked.
+func (s *AuthService) ReactivateUser(ctx context.Context, ref string) (*ent.User, error) {
+	target, err := s.ResolveUserReference(repository.IncludeDeleted(ctx), ref)
+	if err != nil {
+		return nil, err
+	}
+	if !IsDeleted(target) && !IsDeactivated(target) && !IsSuspended(target) {
+		return nil, ErrAccountActive
+	}
+
+	return s.userRepo.Reactivate(ctx, target.ID)
+}
+
+// ForceLogoutUser signs the user out on every device.
+func (s *AuthService) ForceLogoutUser(ctx context.Context, ref string) error {
+	target, err := s.ResolveUserReference(ctx, ref)
+	if err != nil {
+		return err
+	}
+	return s.RevokeUserTokens(ctx, []int64{target.ID}, time.Now(), model.RevocationReasonForcedRelogin)
+}
+
+// ChangeUserRole grants or revokes the admin role. Every request reloads
+// the user, so the new role applies to existing sessions right away.
+func (s *AuthService) ChangeUserRole(ctx context.Context, actor *ent.User, ref string, role model.UserRole) (*ent.User, error) {
+	target, err := s.ResolveUserReference(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if actor != nil && actor.ID == target.ID {
+		return nil, ErrManageOwnAccount
+	}
+	if target.Role == user.Role(role) {
+		return nil, ErrRoleUnchanged
+	}
+
+	updated, err := s.userRepo.UpdateRole(ctx, target.ID, user.Role(role))
+	if err != nil {
+		return nil, err
+	}
+	for _, hook := range s.roleHooks {
+		hook(updated.ID)
+	}
+	return updated, nil
+}
+
+// OnRoleChange registers fn to run after a user's role changed, so caches
+// keyed by user can drop what they decided under the old role. Hooks are
+// registered at startup, before the service handles requests.
+func (s *AuthService) OnRoleChange(fn func(userID int64)) {
+	s.roleHooks = append(s.roleHooks, fn)
+}
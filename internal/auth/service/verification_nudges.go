@@ -0,0 +1,351 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/metrics"
+	"github.com/abisalde/authentication-service/pkg/verification"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// PendingVerificationsKey is a sorted set of the emails of unverified
+	// registrations, scored by when they signed up.
+	PendingVerificationsKey = "pending_verifications"
+	// VerificationNudgesKey is a sorted set of the same emails, scored by
+	// when their next nudge is due. A registration leaves it once it has
+	// been nudged enough or asked to stop.
+	VerificationNudgesKey = "verification_nudges"
+
+	verificationNudgeCapPrefix = "verification_nudge_cap:"
+
+	// pendingUserTTL is how long a registration waits for its email to be
+	// verified.
+	pendingUserTTL = 30 * 24 * time.Hour
+
+	defaultNudgeDelay   = 24 * time.Hour
+	defaultNudgeMax     = 1
+	defaultNudgeCodeTTL = 24 * time.Hour
+	nudgeScanBatch      = 100
+)
+
+var ErrUnsubscribeTokenInvalid = errors.New("unsubscribe token invalid or already used")
+
+// verificationCohorts bucket pending registrations by how long ago they
+// signed up. Each cohort holds the registrations younger than its age and
+// older than the previous one's.
+var verificationCohorts = []struct {
+	name string
+	age  time.Duration
+}{
+	{"under_1h", time.Hour},
+	{"1h_to_1d", 24 * time.Hour},
+	{"1d_to_7d", 7 * 24 * time.Hour},
+	{"7d_to_30d", pendingUserTTL},
+}
+
+var (
+	pendingVerifications = metrics.Default.NewGaugeVec(
+		"pending_email_verifications",
+		"Registrations waiting for their email to be verified, by how long ago they signed up.",
+		"cohort",
+	)
+	emailVerifications = metrics.Default.NewCounterVec(
+		"email_verifications_total",
+		"Registrations verified, by whether a nudge was sent first.",
+		"nudged",
+	)
+	verificationNudges = metrics.Default.NewCounterVec(
+		"verification_nudges_total",
+		"Verification nudges, by whether they were sent, failed, held back by the hourly cap or stopped on request.",
+		"result",
+	)
+)
+
+//go:embed templates/verification_nudge_email_template.html
+var verificationNudgeTemplate embed.FS
+
+func (s *AuthService) verificationNudgesEnabled() bool {
+	return s.cfg != nil && s.cfg.VerificationNudges.Enabled
+}
+
+func (s *AuthService) nudgeDelay() time.Duration {
+	if s.cfg.VerificationNudges.Delay > 0 {
+		return s.cfg.VerificationNudges.Delay
+	}
+	return defaultNudgeDelay
+}
+
+func (s *AuthService) nudgeMax() int {
+	if s.cfg.VerificationNudges.MaxPerRegistration > 0 {
+		return s.cfg.VerificationNudges.MaxPerRegistration
+	}
+	return defaultNudgeMax
+}
+
+func (s *AuthService) nudgeCodeTTL() time.Duration {
+	if s.cfg.VerificationNudges.CodeTTL > 0 {
+		return s.cfg.VerificationNudges.CodeTTL
+	}
+	return defaultNudgeCodeTTL
+}
+
+// trackPendingVerification counts a new registration as pending and, when
+// nudges are enabled, schedules its first nudge.
+func (s *AuthService) trackPendingVerification(ctx context.Context, user model.PendingUser) error {
+	pipe := s.cache.RawClient().TxPipeline()
+	pipe.ZAdd(ctx, PendingVerificationsKey, redis.Z{Score: float64(user.CreatedAt.Unix()), Member: user.Email})
+	pipe.Expire(ctx, PendingVerificationsKey, pendingUserTTL)
+	s.scheduleNudge(ctx, pipe, user)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// scheduleNudge queues the next nudge of the registration a delay after its
+// latest code, unless it had all its nudges or asked for none.
+func (s *AuthService) scheduleNudge(ctx context.Context, pipe redis.Pipeliner, user model.PendingUser) {
+	if !s.verificationNudgesEnabled() {
+		return
+	}
+	if user.NudgesStopped || user.Nudges >= s.nudgeMax() {
+		pipe.ZRem(ctx, VerificationNudgesKey, user.Email)
+		return
+	}
+
+	due := time.Now().Add(s.nudgeDelay())
+	pipe.ZAdd(ctx, VerificationNudgesKey, redis.Z{Score: float64(due.Unix()), Member: user.Email})
+	pipe.Expire(ctx, VerificationNudgesKey, pendingUserTTL)
+}
+
+// untrackPendingVerification forgets a registration that was verified or
+// abandoned.
+func (s *AuthService) untrackPendingVerification(ctx context.Context, email string) error {
+	pipe := s.cache.RawClient().TxPipeline()
+	pipe.ZRem(ctx, PendingVerificationsKey, email)
+	pipe.ZRem(ctx, VerificationNudgesKey, email)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// NudgePendingVerifications refreshes the pending verification metrics and,
+// when nudges are enabled, emails a fresh code to every registration due a
+// nudge. It returns how many were sent. Once the hourly cap is reached the
+// remaining registrations wait for the next scan.
+func (s *AuthService) NudgePendingVerifications(ctx context.Context) (int, error) {
+	if err := s.recordPendingVerifications(ctx); err != nil {
+		return 0, err
+	}
+	if !s.verificationNudgesEnabled() {
+		return 0, nil
+	}
+
+	rdb := s.cache.RawClient()
+	now := time.Now()
+	due, err := rdb.ZRangeByScore(ctx, VerificationNudgesKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.Unix(), 10),
+		Count: nudgeScanBatch,
+	}).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, email := range due {
+		// Another scan, a verification or a resend may have claimed the
+		// entry first.
+		removed, err := rdb.ZRem(ctx, VerificationNudgesKey, email).Result()
+		if err != nil {
+			return sent, err
+		}
+		if removed == 0 {
+			continue
+		}
+
+		pending, err := s.GetPendingUser(ctx, email)
+		if err != nil {
+			// The registration expired without being verified.
+			rdb.ZRem(ctx, PendingVerificationsKey, email)
+			continue
+		}
+		if pending.NudgesStopped || pending.Nudges >= s.nudgeMax() {
+			continue
+		}
+
+		allowed, err := s.takeNudgeSlot(ctx, now)
+		if err != nil || !allowed {
+			rdb.ZAdd(ctx, VerificationNudgesKey, redis.Z{Score: float64(now.Unix()), Member: email})
+			if err != nil {
+				return sent, err
+			}
+			verificationNudges.Inc("capped")
+			break
+		}
+
+		if err := s.sendVerificationNudge(ctx, pending); err != nil {
+			verificationNudges.Inc("failed")
+			s.logger.Warn("Failed to send verification nudge", "email", email, "error", err)
+			continue
+		}
+		verificationNudges.Inc("sent")
+		sent++
+	}
+	return sent, nil
+}
+
+// recordPendingVerifications drops the registrations that expired and
+// counts the others by cohort.
+func (s *AuthService) recordPendingVerifications(ctx context.Context) error {
+	rdb := s.cache.RawClient()
+	now := time.Now()
+
+	expired := strconv.FormatInt(now.Add(-pendingUserTTL).Unix(), 10)
+	if err := rdb.ZRemRangeByScore(ctx, PendingVerificationsKey, "-inf", expired).Err(); err != nil {
+		return err
+	}
+
+	pipe := rdb.Pipeline()
+	counts := make([]*redis.IntCmd, len(verificationCohorts))
+	newest := "+inf"
+	for i, cohort := range verificationCohorts {
+		oldest := "(" + strconv.FormatInt(now.Add(-cohort.age).Unix(), 10)
+		counts[i] = pipe.ZCount(ctx, PendingVerificationsKey, oldest, newest)
+		newest = strconv.FormatInt(now.Add(-cohort.age).Unix(), 10)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+	for i, cohort := range verificationCohorts {
+		pendingVerifications.Set(float64(counts[i].Val()), cohort.name)
+	}
+	return nil
+}
+
+// takeNudgeSlot counts a nudge against the hourly cap shared by every
+// instance, reporting false once the cap is reached.
+func (s *AuthService) takeNudgeSlot(ctx context.Context, now time.Time) (bool, error) {
+	limit := s.cfg.VerificationNudges.HourlyCap
+	if limit <= 0 {
+		return true, nil
+	}
+
+	hour := now.Truncate(time.Hour)
+	key := fmt.Sprintf("%s%d", verificationNudgeCapPrefix, hour.Unix())
+	rdb := s.cache.RawClient()
+
+	pipe := rdb.TxPipeline()
+	count := pipe.Incr(ctx, key)
+	pipe.ExpireAt(ctx, key, hour.Add(2*time.Hour))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+	if count.Val() > limit {
+		rdb.Decr(ctx, key)
+		return false, nil
+	}
+	return true, nil
+}
+
+// sendVerificationNudge replaces the code of the registration with a fresh
+// one, valid for longer than a code the user asked for since the email may
+// sit unread for a while, and emails it.
+func (s *AuthService) sendVerificationNudge(ctx context.Context, pending *model.PendingUser) error {
+	code := s.NewVerificationCode(pending.Email)
+	pending.VerificationCode = code
+	pending.ExpiresAt = time.Now().Add(s.nudgeCodeTTL())
+	pending.Nudges++
+	if err := s.UpdatePendingUser(ctx, *pending); err != nil {
+		return err
+	}
+
+	brand := s.DefaultBrand()
+	data := struct {
+		Code           string
+		CodeExpires    string
+		UnsubscribeURL string
+		Brand          Brand
+	}{
+		Code:        code,
+		CodeExpires: pending.ExpiresAt.UTC().Format("2 January 2006 at 15:04 MST"),
+		Brand:       brand,
+	}
+
+	if base := s.cfg.VerificationNudges.UnsubscribeURL; base != "" {
+		ttl := time.Until(pending.CreatedAt.Add(pendingUserTTL))
+		token, err := s.IssueActionToken(ctx, verification.PurposeUnsubscribe, pending.Email, map[string]string{"list": VerificationNudgesKey}, ttl)
+		if err != nil {
+			// The code is still worth sending; nudges stop on their own.
+			s.logger.Warn("Failed to issue nudge unsubscribe token", "email", pending.Email, "error", err)
+		} else {
+			data.UnsubscribeURL = appealLink(base, token)
+		}
+	}
+
+	tmplData, err := verificationNudgeTemplate.ReadFile("templates/verification_nudge_email_template.html")
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := template.New("verification_nudge").Parse(string(tmplData))
+	if err != nil {
+		return err
+	}
+
+	var htmlBody bytes.Buffer
+	if err := tmpl.Execute(&htmlBody, data); err != nil {
+		return err
+	}
+
+	subject := "Finish Signing Up"
+	if brand.ProductName != "" {
+		subject = fmt.Sprintf("Finish Signing Up for %s", brand.ProductName)
+	}
+	body := fmt.Sprintf(`
+		You started signing up but have not verified your email yet.
+
+		Here's a fresh one-time passcode: %s
+
+		This code will expire on %s
+
+		%s
+	`, code, data.CodeExpires, supportLine(brand))
+	if data.UnsubscribeURL != "" {
+		body += "\n\nStop these reminders: " + data.UnsubscribeURL
+	}
+
+	return s.mailService.SendHTMLEmail(ctx, pending.Email, subject, htmlBody.String(), strings.TrimSpace(body))
+}
+
+// StopVerificationNudges consumes the token from the unsubscribe link of a
+// nudge and sends the registration no more. The registration itself stays
+// and can still be verified.
+func (s *AuthService) StopVerificationNudges(ctx context.Context, token string) error {
+	payload, err := s.ConsumeActionToken(ctx, token, verification.PurposeUnsubscribe)
+	if err != nil || payload.Data["list"] != VerificationNudgesKey {
+		return ErrUnsubscribeTokenInvalid
+	}
+
+	verificationNudges.Inc("stopped")
+	pending, err := s.GetPendingUser(ctx, payload.Subject)
+	if err != nil {
+		// Verified or expired already; there is nothing left to send.
+		return nil
+	}
+	pending.NudgesStopped = true
+	return s.UpdatePendingUser(ctx, *pending)
+}
+
+// recordVerification counts a verified registration by whether it was
+// nudged.
+func recordVerification(pending *model.PendingUser) {
+	emailVerifications.Inc(strconv.FormatBool(pending.Nudges > 0))
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/events"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// WebhookDeliveryStreamKey keeps the latest attempts to deliver events to
+	// the refresh reminder webhook, with the body sent, so integrators can
+	// inspect and replay them.
+	WebhookDeliveryStreamKey = "webhook_deliveries"
+
+	// WebhookSignatureHeader carries t=<unix>,v1=<hex HMAC-SHA256 of
+	// "<t>.<body>"> when a webhook secret is configured. Receivers should
+	// reject timestamps far from their clock to stop replays.
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	// WebhookTestHeader marks test events sent from the admin API.
+	WebhookTestHeader = "X-Webhook-Test"
+
+	webhookDeliveryLogSize = 1000
+	maxWebhookDeliveries   = 200
+)
+
+var (
+	ErrWebhookNotConfigured    = errors.New("no webhook configured")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+// WebhookDelivery is one attempt to post an event to the webhook.
+// StatusCode is 0 when no response came back.
+type WebhookDelivery struct {
+	ID           string
+	EventType    string
+	URL          string
+	StatusCode   int
+	Latency      time.Duration
+	Error        string
+	Test         bool
+	RedeliveryOf string
+	DeliveredAt  time.Time
+	Payload      string
+}
+
+// deliverWebhook posts body to url, signed with a fresh timestamp, and
+// records the attempt. The returned error is the delivery's, if it failed.
+func (s *AuthService) deliverWebhook(ctx context.Context, url, eventType string, body []byte, test bool, redeliveryOf string) (*WebhookDelivery, error) {
+	delivery := &WebhookDelivery{
+		EventType:    eventType,
+		URL:          url,
+		Test:         test,
+		RedeliveryOf: redeliveryOf,
+		DeliveredAt:  time.Now(),
+		Payload:      string(body),
+	}
+
+	deliveryErr := s.postWebhook(ctx, delivery, body)
+	delivery.Latency = time.Since(delivery.DeliveredAt)
+	if deliveryErr != nil {
+		delivery.Error = deliveryErr.Error()
+	}
+
+	id, err := s.cache.RawClient().XAdd(ctx, &redis.XAddArgs{
+		Stream: WebhookDeliveryStreamKey,
+		MaxLen: webhookDeliveryLogSize,
+		Approx: true,
+		Values: map[string]interface{}{
+			"event_type":    delivery.EventType,
+			"url":           delivery.URL,
+			"status_code":   delivery.StatusCode,
+			"latency_ms":    delivery.Latency.Milliseconds(),
+			"error":         delivery.Error,
+			"test":          strconv.FormatBool(delivery.Test),
+			"redelivery_of": delivery.RedeliveryOf,
+			"delivered_at":  delivery.DeliveredAt.Unix(),
+			"payload":       delivery.Payload,
+		},
+	}).Result()
+	if err != nil {
+		return delivery, errors.Join(deliveryErr, fmt.Errorf("failed to record webhook delivery: %w", err))
+	}
+	delivery.ID = id
+	return delivery, deliveryErr
+}
+
+func (s *AuthService) postWebhook(ctx context.Context, delivery *WebhookDelivery, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret := s.cfg.RefreshReminder.WebhookSecret; secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhook(secret, time.Now(), body))
+	}
+	if delivery.Test {
+		req.Header.Set(WebhookTestHeader, "true")
+	}
+
+	resp, err := reminderClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	delivery.StatusCode = resp.StatusCode
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// SignWebhook returns the signature header value of body sent at t.
+func SignWebhook(secret string, t time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SendTestWebhook posts a sample refresh_token_expiring event for no real
+// user to the webhook, marked with WebhookTestHeader.
+func (s *AuthService) SendTestWebhook(ctx context.Context) (*WebhookDelivery, error) {
+	url := s.cfg.RefreshReminder.WebhookURL
+	if url == "" {
+		return nil, ErrWebhookNotConfigured
+	}
+
+	now := time.Now()
+	body, err := json.Marshal(RefreshReminderEvent{
+		Metadata:     events.Stamp(events.RefreshTokenExpiring),
+		UserPublicID: uuid.Nil.String(),
+		ExpiresAt:    now.Add(s.reminderLead()),
+		Timestamp:    now,
+		EventType:    events.RefreshTokenExpiring,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := s.deliverWebhook(ctx, url, events.RefreshTokenExpiring, body, true, "")
+	if delivery.ID == "" {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// RedeliverWebhook posts the body of an earlier delivery again, to the
+// webhook configured now and with a fresh signature. A failed redelivery
+// is recorded and returned like any other.
+func (s *AuthService) RedeliverWebhook(ctx context.Context, id string) (*WebhookDelivery, error) {
+	url := s.cfg.RefreshReminder.WebhookURL
+	if url == "" {
+		return nil, ErrWebhookNotConfigured
+	}
+
+	entries, err := s.cache.RawClient().XRange(ctx, WebhookDeliveryStreamKey, id, id).Result()
+	if err != nil || len(entries) == 0 {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	original := webhookDeliveryFromEntry(entries[0])
+
+	delivery, err := s.deliverWebhook(ctx, url, original.EventType, []byte(original.Payload), original.Test, original.ID)
+	if delivery.ID == "" {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// ListWebhookDeliveries returns up to limit deliveries, newest first.
+func (s *AuthService) ListWebhookDeliveries(ctx context.Context, limit int) ([]WebhookDelivery, error) {
+	if limit <= 0 || limit > maxWebhookDeliveries {
+		limit = maxWebhookDeliveries
+	}
+
+	entries, err := s.cache.RawClient().XRevRangeN(ctx, WebhookDeliveryStreamKey, "+", "-", int64(limit)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]WebhookDelivery, 0, len(entries))
+	for _, entry := range entries {
+		deliveries = append(deliveries, webhookDeliveryFromEntry(entry))
+	}
+	return deliveries, nil
+}
+
+func webhookDeliveryFromEntry(entry redis.XMessage) WebhookDelivery {
+	field := func(name string) string {
+		value, _ := entry.Values[name].(string)
+		return value
+	}
+	statusCode, _ := strconv.Atoi(field("status_code"))
+	latency, _ := strconv.ParseInt(field("latency_ms"), 10, 64)
+	deliveredAt, _ := strconv.ParseInt(field("delivered_at"), 10, 64)
+
+	return WebhookDelivery{
+		ID:           entry.ID,
+		EventType:    field("event_type"),
+		URL:          field("url"),
+		StatusCode:   statusCode,
+		Latency:      time.Duration(latency) * time.Millisecond,
+		Error:        field("error"),
+		Test:         field("test") == "true",
+		RedeliveryOf: field("redelivery_of"),
+		DeliveredAt:  time.Unix(deliveredAt, 0),
+		Payload:      field("payload"),
+	}
+}
@@ -0,0 +1,95 @@
+package sessionstore
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps sessions in the process, for single-instance
+// deployments and tests. Sessions are lost on restart.
+type MemoryStore struct {
+	mu       sync.Mutex
+	sessions map[int64]Session
+	now      func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{sessions: make(map[int64]Session), now: time.Now}
+}
+
+func (m *MemoryStore) Create(_ context.Context, session Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[session.UserID] = truncate(session)
+	return nil
+}
+
+func (m *MemoryStore) Get(_ context.Context, userID int64) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	session, ok := m.live(userID)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &session, nil
+}
+
+func (m *MemoryStore) ListByUser(ctx context.Context, userID int64) ([]Session, error) {
+	return list(m.Get(ctx, userID))
+}
+
+func (m *MemoryStore) Touch(_ context.Context, userID int64, at time.Time, activity Activity) (time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	session, ok := m.sessions[userID]
+	if !ok || session.LastActiveAt.IsZero() || !m.now().Before(session.EndsAt) {
+		return time.Time{}, nil
+	}
+	at = at.Truncate(time.Second)
+	expiresAt, err := activity.slide(session.StartedAt, session.LastActiveAt, at)
+	if err != nil || expiresAt.IsZero() {
+		return time.Time{}, err
+	}
+	if !m.now().Before(session.ExpiresAt) {
+		return time.Time{}, nil
+	}
+	session.LastActiveAt = at
+	session.ExpiresAt = expiresAt
+	m.sessions[userID] = session
+	return expiresAt, nil
+}
+
+func (m *MemoryStore) Delete(_ context.Context, userIDs ...int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range userIDs {
+		delete(m.sessions, id)
+	}
+	return nil
+}
+
+// live returns the user's unexpired session. An expired one is kept until
+// it ends, so Touch can still tell it was idle. The caller holds mu.
+func (m *MemoryStore) live(userID int64) (Session, bool) {
+	session, ok := m.sessions[userID]
+	if !ok {
+		return Session{}, false
+	}
+	now := m.now()
+	if !now.Before(session.ExpiresAt) {
+		if !now.Before(session.EndsAt) {
+			delete(m.sessions, userID)
+		}
+		return Session{}, false
+	}
+	return session, true
+}
+
+func truncate(session Session) Session {
+	session.StartedAt = session.StartedAt.Truncate(time.Second)
+	session.LastActiveAt = session.LastActiveAt.Truncate(time.Second)
+	session.ExpiresAt = session.ExpiresAt.Truncate(time.Second)
+	session.EndsAt = session.EndsAt.Truncate(time.Second)
+	return session
+}
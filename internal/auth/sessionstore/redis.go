@@ -0,0 +1,186 @@
+package sessionstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// RefreshTokenPrefix keys a user's encrypted refresh token; the key with
+	// TokenHashSuffix appended holds its hash.
+	RefreshTokenPrefix = "refresh_token:"
+	TokenHashSuffix    = ":hash"
+	// ActivityPrefix keys when a user's session started and was last used,
+	// until the session's end.
+	ActivityPrefix = "session_activity:"
+
+	startedField    = "started_at"
+	lastActiveField = "last_active"
+)
+
+// touchScript checks the session for idleness and, unless it was used
+// within the resolution, records the use and slides the refresh token to
+// one idle window, cut short by the end of the session's lifetime. EXPIRE
+// skips missing keys, so a signed out session stays out. It returns -1 for
+// an idle session, the new TTL when the token slid and 0 otherwise.
+var touchScript = redis.NewScript(`
+local fields = redis.call('HMGET', KEYS[1], 'started_at', 'last_active')
+local started, last = tonumber(fields[1]), tonumber(fields[2])
+if not started or not last then
+	return 0
+end
+local now, idle = tonumber(ARGV[1]), tonumber(ARGV[2])
+if now - last > idle then
+	return -1
+end
+local ttl = math.min(idle, started + tonumber(ARGV[4]) - now)
+if now - last < tonumber(ARGV[3]) or ttl <= 0 then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'last_active', ARGV[1])
+if redis.call('EXPIRE', KEYS[2], ttl) == 0 then
+	return 0
+end
+redis.call('EXPIRE', KEYS[3], ttl)
+return ttl
+`)
+
+// RedisStore keeps sessions under the refresh_token and session_activity
+// keys. Token values are JSON encoded, the way the cache service stores
+// them.
+type RedisStore struct {
+	rdb *redis.Client
+}
+
+func NewRedisStore(rdb *redis.Client) *RedisStore {
+	return &RedisStore{rdb: rdb}
+}
+
+func tokenKey(userID int64) string {
+	return fmt.Sprintf("%s%d", RefreshTokenPrefix, userID)
+}
+
+func activityKey(userID int64) string {
+	return fmt.Sprintf("%s%d", ActivityPrefix, userID)
+}
+
+// Create writes the token, its hash and any activity in one MULTI, so
+// concurrent sign-ins never leave the token of one next to the hash of
+// another.
+func (r *RedisStore) Create(ctx context.Context, session Session) error {
+	token, err := json.Marshal(session.EncryptedToken)
+	if err != nil {
+		return err
+	}
+	hash, err := json.Marshal(session.TokenHash)
+	if err != nil {
+		return err
+	}
+
+	key := tokenKey(session.UserID)
+	expiry := redis.SetArgs{ExpireAt: session.ExpiresAt}
+	pipe := r.rdb.TxPipeline()
+	pipe.SetArgs(ctx, key, token, expiry)
+	pipe.SetArgs(ctx, key+TokenHashSuffix, hash, expiry)
+	if session.StartedAt.IsZero() {
+		pipe.Del(ctx, activityKey(session.UserID))
+	} else {
+		pipe.HSet(ctx, activityKey(session.UserID),
+			startedField, session.StartedAt.Unix(),
+			lastActiveField, session.LastActiveAt.Unix())
+		pipe.ExpireAt(ctx, activityKey(session.UserID), session.EndsAt)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (r *RedisStore) Get(ctx context.Context, userID int64) (*Session, error) {
+	key := tokenKey(userID)
+	pipe := r.rdb.Pipeline()
+	token := pipe.Get(ctx, key)
+	hash := pipe.Get(ctx, key+TokenHashSuffix)
+	ttl := pipe.PTTL(ctx, key)
+	activity := pipe.HMGet(ctx, activityKey(userID), startedField, lastActiveField)
+	activityTTL := pipe.PTTL(ctx, activityKey(userID))
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+	if token.Err() == redis.Nil || hash.Err() == redis.Nil {
+		return nil, ErrNotFound
+	}
+
+	now := time.Now()
+	session := &Session{UserID: userID}
+	if err := json.Unmarshal([]byte(token.Val()), &session.EncryptedToken); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(hash.Val()), &session.TokenHash); err != nil {
+		return nil, err
+	}
+	if left := ttl.Val(); left > 0 {
+		session.ExpiresAt = now.Add(left).Truncate(time.Second)
+	}
+	fields := activity.Val()
+	if started, ok := unixField(fields[0]); ok {
+		session.StartedAt = started
+	}
+	if last, ok := unixField(fields[1]); ok {
+		session.LastActiveAt = last
+	}
+	if left := activityTTL.Val(); left > 0 {
+		session.EndsAt = now.Add(left).Truncate(time.Second)
+	}
+	return session, nil
+}
+
+func (r *RedisStore) ListByUser(ctx context.Context, userID int64) ([]Session, error) {
+	return list(r.Get(ctx, userID))
+}
+
+func (r *RedisStore) Touch(ctx context.Context, userID int64, at time.Time, activity Activity) (time.Time, error) {
+	key := tokenKey(userID)
+	result, err := touchScript.Run(ctx, r.rdb,
+		[]string{activityKey(userID), key, key + TokenHashSuffix},
+		at.Unix(),
+		int64(activity.Idle.Seconds()),
+		int64(activity.Resolution.Seconds()),
+		int64(activity.Lifetime.Seconds()),
+	).Int64()
+	switch {
+	case err != nil:
+		return time.Time{}, err
+	case result < 0:
+		return time.Time{}, ErrIdle
+	case result == 0:
+		return time.Time{}, nil
+	}
+	return time.Unix(at.Unix()+result, 0), nil
+}
+
+func (r *RedisStore) Delete(ctx context.Context, userIDs ...int64) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, 3*len(userIDs))
+	for _, id := range userIDs {
+		keys = append(keys, tokenKey(id), tokenKey(id)+TokenHashSuffix, activityKey(id))
+	}
+	return r.rdb.Unlink(ctx, keys...).Err()
+}
+
+func unixField(value interface{}) (time.Time, bool) {
+	s, ok := value.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	seconds, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0), true
+}
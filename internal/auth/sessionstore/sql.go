@@ -0,0 +1,134 @@
+package sessionstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+)
+
+// createSessionsTable is the sessions table of migrations/0011_add_sessions,
+// in SQL both MySQL and SQLite accept.
+const createSessionsTable = `CREATE TABLE IF NOT EXISTS sessions (
+    user_id BIGINT NOT NULL,
+    encrypted_token TEXT NOT NULL,
+    token_hash VARCHAR(255) NOT NULL,
+    started_at TIMESTAMP NULL,
+    last_active_at TIMESTAMP NULL,
+    expires_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id)
+)`
+
+// SQLStore keeps sessions in the sessions table, one row per user. A row
+// outlives its expiry until the user signs in or out again; reads skip it,
+// and Touch uses it to tell an idle session from a missing one.
+type SQLStore struct {
+	db *sql.DB
+}
+
+// NewSQLStore keeps sessions in db, a MySQL DSN with parseTime=true or a
+// SQLite database.
+func NewSQLStore(db *sql.DB) *SQLStore {
+	return &SQLStore{db: db}
+}
+
+// Migrate creates the sessions table if it is missing, for databases the
+// migrations are not applied to by hand.
+func (s *SQLStore) Migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, createSessionsTable)
+	return err
+}
+
+func (s *SQLStore) Create(ctx context.Context, session Session) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM sessions WHERE user_id = ?`, session.UserID); err != nil {
+		return err
+	}
+	session = truncate(session)
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO sessions (user_id, encrypted_token, token_hash, started_at, last_active_at, expires_at, ends_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		session.UserID, session.EncryptedToken, session.TokenHash,
+		nullTime(session.StartedAt), nullTime(session.LastActiveAt), session.ExpiresAt.UTC(), session.EndsAt.UTC())
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLStore) Get(ctx context.Context, userID int64) (*Session, error) {
+	session := &Session{UserID: userID}
+	var started, lastActive sql.NullTime
+	err := s.db.QueryRowContext(ctx,
+		`SELECT encrypted_token, token_hash, started_at, last_active_at, expires_at, ends_at FROM sessions WHERE user_id = ? AND expires_at > ?`,
+		userID, time.Now().UTC()).
+		Scan(&session.EncryptedToken, &session.TokenHash, &started, &lastActive, &session.ExpiresAt, &session.EndsAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	session.StartedAt = started.Time
+	session.LastActiveAt = lastActive.Time
+	return session, nil
+}
+
+func (s *SQLStore) ListByUser(ctx context.Context, userID int64) ([]Session, error) {
+	return list(s.Get(ctx, userID))
+}
+
+// Touch updates the row only if no other request touched it since it was
+// read, so concurrent requests never slide a session another found idle.
+func (s *SQLStore) Touch(ctx context.Context, userID int64, at time.Time, activity Activity) (time.Time, error) {
+	var started, lastActive sql.NullTime
+	err := s.db.QueryRowContext(ctx,
+		`SELECT started_at, last_active_at FROM sessions WHERE user_id = ? AND ends_at > ?`,
+		userID, time.Now().UTC()).
+		Scan(&started, &lastActive)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !(started.Valid && lastActive.Valid)) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	at = at.Truncate(time.Second)
+	expiresAt, err := activity.slide(started.Time, lastActive.Time, at)
+	if err != nil || expiresAt.IsZero() {
+		return time.Time{}, err
+	}
+	result, err := s.db.ExecContext(ctx,
+		`UPDATE sessions SET last_active_at = ?, expires_at = ? WHERE user_id = ? AND last_active_at = ? AND expires_at > ?`,
+		at.UTC(), expiresAt.UTC(), userID, lastActive.Time.UTC(), time.Now().UTC())
+	if err != nil {
+		return time.Time{}, err
+	}
+	if updated, err := result.RowsAffected(); err != nil || updated == 0 {
+		return time.Time{}, err
+	}
+	return expiresAt, nil
+}
+
+func (s *SQLStore) Delete(ctx context.Context, userIDs ...int64) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	args := make([]interface{}, len(userIDs))
+	for i, id := range userIDs {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(userIDs)), ", ")
+	_, err := s.db.ExecContext(ctx, `DELETE FROM sessions WHERE user_id IN (`+placeholders+`)`, args...)
+	return err
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t.UTC(), Valid: !t.IsZero()}
+}
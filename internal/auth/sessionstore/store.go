@@ -0,0 +1,90 @@
+// Package sessionstore keeps users' refresh sessions: the encrypted refresh
+// token, its hash and when the session started and was last used. The
+// service keeps one session per user, so a new sign-in replaces the last.
+// Redis is the default store; the in-memory and SQL stores serve
+// deployments and tests without Redis.
+package sessionstore
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned for a user without an unexpired session.
+	ErrNotFound = errors.New("session not found")
+	// ErrIdle is returned by Touch for a session unused for longer than the
+	// idle timeout, whether or not its refresh token has lapsed since.
+	ErrIdle = errors.New("session idle")
+)
+
+// Session is a user's refresh session. StartedAt and LastActiveAt are zero
+// for sessions stored while activity was not tracked.
+type Session struct {
+	UserID         int64
+	EncryptedToken string
+	TokenHash      string
+	StartedAt      time.Time
+	LastActiveAt   time.Time
+	// ExpiresAt is when the refresh token lapses unless the session is
+	// touched; EndsAt is when the store may forget the session.
+	ExpiresAt time.Time
+	EndsAt    time.Time
+}
+
+// Activity tunes Touch.
+type Activity struct {
+	// Idle is how long a session may go unused.
+	Idle time.Duration
+	// Resolution throttles writes: uses closer to the last one are not
+	// recorded.
+	Resolution time.Duration
+	// Lifetime caps how far past its start a session slides.
+	Lifetime time.Duration
+}
+
+// Store keeps refresh sessions. Implementations are safe for concurrent use
+// and store whole-second times.
+type Store interface {
+	// Create stores the session, replacing the user's current one.
+	Create(ctx context.Context, session Session) error
+	// Get returns the user's session, or ErrNotFound.
+	Get(ctx context.Context, userID int64) (*Session, error)
+	// ListByUser returns the user's unexpired sessions, none or one.
+	ListByUser(ctx context.Context, userID int64) ([]Session, error)
+	// Touch records a use of the user's session at at and slides its expiry
+	// to one idle window past at, never beyond its lifetime, returning the
+	// new expiry. Uses within the resolution only check the session and
+	// return the zero time, as do sessions without activity. A session
+	// unused for longer than the idle window fails with ErrIdle, even once
+	// its refresh token has lapsed.
+	Touch(ctx context.Context, userID int64, at time.Time, activity Activity) (time.Time, error)
+	// Delete drops the sessions of the users. Missing ones are skipped.
+	Delete(ctx context.Context, userIDs ...int64) error
+}
+
+// list wraps the result of Get for ListByUser.
+func list(session *Session, err error) ([]Session, error) {
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []Session{*session}, nil
+}
+
+// slide decides a Touch at at of a session that started at started and was
+// last used at last: the new expiry, the zero time when the use is not
+// recorded, or ErrIdle.
+func (a Activity) slide(started, last, at time.Time) (time.Time, error) {
+	if at.Sub(last) > a.Idle {
+		return time.Time{}, ErrIdle
+	}
+	ttl := min(a.Idle, started.Add(a.Lifetime).Sub(at))
+	if at.Sub(last) < a.Resolution || ttl <= 0 {
+		return time.Time{}, nil
+	}
+	return at.Add(ttl), nil
+}
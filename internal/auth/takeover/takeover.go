@@ -0,0 +1,93 @@
+package takeover
+
+import (
+	"slices"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/refreshbinding"
+	"github.com/abisalde/authentication-service/internal/configs"
+)
+
+type Mode string
+
+const (
+	ModeOff Mode = "off"
+	// ModeAlert marks the session suspicious and raises an alert.
+	ModeAlert Mode = "alert"
+	// ModeRestrict also revokes the user's tokens, signing out both the
+	// owner and whoever took the session over.
+	ModeRestrict Mode = "restrict"
+)
+
+const (
+	defaultWindow  = 10 * time.Minute
+	defaultSamples = 8
+)
+
+// Sample is one use of a session, reduced to its coarse origin.
+type Sample struct {
+	refreshbinding.Origin
+	At time.Time `json:"at"`
+}
+
+// Policy flags an access token used from two networks or user agent
+// families within Window. Networks are compared at the refresh binding's
+// prefixes and its allowed networks never conflict.
+type Policy struct {
+	Mode    Mode
+	Window  time.Duration
+	Samples int
+	origins refreshbinding.Policy
+}
+
+func NewPolicy(cfg *configs.Config) Policy {
+	policy := Policy{Mode: ModeOff, Window: defaultWindow, Samples: defaultSamples}
+	if cfg == nil {
+		return policy
+	}
+
+	t := cfg.SessionTakeover
+	if t.Mode != "" {
+		policy.Mode = Mode(t.Mode)
+	}
+	if t.Window > 0 {
+		policy.Window = t.Window
+	}
+	if t.Samples > 0 {
+		policy.Samples = t.Samples
+	}
+	policy.origins = refreshbinding.NewPolicy(cfg)
+	policy.origins.Network = t.Network
+	policy.origins.UserAgent = t.UserAgent
+	return policy
+}
+
+func (p Policy) Enabled() bool {
+	return p.Mode != ModeOff && (p.origins.Network || p.origins.UserAgent)
+}
+
+func (p Policy) Restricts() bool {
+	return p.Mode == ModeRestrict
+}
+
+// SampleOf records a use of the session from ip with userAgent.
+func (p Policy) SampleOf(ip, userAgent string, at time.Time) Sample {
+	return Sample{Origin: p.origins.OriginOf(ip, userAgent), At: at}
+}
+
+// Conflicts lists the mismatch kinds between the current use and the
+// recent ones still inside the window.
+func (p Policy) Conflicts(recent []Sample, current Sample, currentIP string) []string {
+	var kinds []string
+	for _, sample := range recent {
+		if current.At.Sub(sample.At) > p.Window {
+			continue
+		}
+		for _, kind := range p.origins.Mismatches(sample.Origin, current.Origin, currentIP) {
+			if !slices.Contains(kinds, kind) {
+				kinds = append(kinds, kind)
+			}
+		}
+	}
+	return kinds
+}
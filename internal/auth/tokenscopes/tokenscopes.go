@@ -0,0 +1,120 @@
+// Package tokenscopes decides which scopes a user's access tokens carry,
+// from a default set and the sets configured for roles and organizations.
+package tokenscopes
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database/ent/user"
+)
+
+// Admin is the scope of administrators. It and every admin:* scope may only
+// be granted to the ADMIN role.
+const Admin = "admin"
+
+// IsAdmin reports whether scope is reserved to administrators.
+func IsAdmin(scope string) bool {
+	return scope == Admin || strings.HasPrefix(scope, Admin+":")
+}
+
+// IsReserved reports whether scope is issued by a dedicated flow, such as
+// maintenance tokens and service account keys, and never by the policy.
+func IsReserved(scope string) bool {
+	return scope == "service_account" || strings.HasPrefix(scope, "maintenance:")
+}
+
+// Policy grants Default to every user, plus the scopes of the user's role
+// and organization, keyed by organization slug.
+type Policy struct {
+	Default       []string
+	Roles         map[user.Role][]string
+	Organizations map[string][]string
+}
+
+func NewPolicy(cfg *configs.Config) Policy {
+	policy := Policy{Roles: map[user.Role][]string{}, Organizations: map[string][]string{}}
+	if cfg == nil {
+		return policy
+	}
+
+	s := cfg.TokenScopes
+	policy.Default = s.Default
+	for role, scopes := range s.Roles {
+		policy.Roles[user.Role(role)] = scopes
+	}
+	for slug, scopes := range s.Organizations {
+		policy.Organizations[slug] = scopes
+	}
+	return policy
+}
+
+// Enabled reports whether the policy grants any scope at all.
+func (p Policy) Enabled() bool {
+	return len(p.Default) > 0 || len(p.Roles) > 0 || len(p.Organizations) > 0
+}
+
+// Validate refuses policies that grant admin scopes to anyone but the ADMIN
+// role, grant reserved scopes, or name unknown roles.
+func (p Policy) Validate() error {
+	check := func(where string, scopes []string, admin bool) error {
+		for _, scope := range scopes {
+			switch {
+			case scope == "" || strings.ContainsAny(scope, " \t\n"):
+				return fmt.Errorf("token_scopes.%s: %q is not a valid scope", where, scope)
+			case IsReserved(scope):
+				return fmt.Errorf("token_scopes.%s: %q is issued by its own flow and cannot be granted", where, scope)
+			case IsAdmin(scope) && !admin:
+				return fmt.Errorf("token_scopes.%s: %q may only be granted to the ADMIN role", where, scope)
+			}
+		}
+		return nil
+	}
+
+	if err := check("default", p.Default, false); err != nil {
+		return err
+	}
+	for role, scopes := range p.Roles {
+		if user.RoleValidator(role) != nil {
+			return fmt.Errorf("token_scopes.roles: unknown role %q", role)
+		}
+		if err := check("roles."+string(role), scopes, role == user.RoleADMIN); err != nil {
+			return err
+		}
+	}
+	for slug, scopes := range p.Organizations {
+		if err := check("organizations."+slug, scopes, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// For returns the sorted scopes of a user with role in the organization
+// with the given slug, empty for none. Admin scopes only ever come from the
+// ADMIN role's own set, and reserved scopes never, even when a policy that
+// skipped Validate grants them elsewhere.
+func (p Policy) For(role user.Role, organization string) []string {
+	var scopes []string
+	grant := func(granted []string, admin bool) {
+		for _, scope := range granted {
+			if scope != "" && !IsReserved(scope) && (admin || !IsAdmin(scope)) {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+
+	grant(p.Default, false)
+	grant(p.Roles[role], role == user.RoleADMIN)
+	if organization != "" {
+		grant(p.Organizations[organization], false)
+	}
+
+	if len(scopes) == 0 {
+		return nil
+	}
+	slices.Sort(scopes)
+	return slices.Compact(scopes)
+}
@@ -2,9 +2,13 @@ package configs
 
 import (
 	"fmt"
+	"net/netip"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"gopkg.in/yaml.v3"
@@ -20,14 +24,36 @@ type Config struct {
 		Name     string `yaml:"dbname"`
 		SSLMode  string `yaml:"sslmode"`
 		Migrate  bool   `yaml:"migrate"`
+
+		// Guard blocks auto-migrations with unsafe operations unless
+		// AllowUnsafe, or MIGRATION_ALLOW_UNSAFE=true, overrides it for one
+		// deploy. LockSensitiveTables default to users.
+		Guard struct {
+			Enabled             bool     `yaml:"enabled"`
+			AllowUnsafe         bool     `yaml:"allow_unsafe"`
+			LockSensitiveTables []string `yaml:"lock_sensitive_tables"`
+		} `yaml:"guard"`
 	} `yaml:"database"`
 
 	Redis struct {
 		Addr     string `yaml:"redis_addr"`
 		Password string `yaml:"redis_password"`
 		DB       int    `yaml:"redis_db"`
+		// RequireTTL rejects cache writes without expiration; DefaultTTL is
+		// applied to them instead when set.
+		RequireTTL bool          `yaml:"require_ttl"`
+		DefaultTTL time.Duration `yaml:"default_ttl"`
 	} `yaml:"redis"`
 
+	// Embedded runs the service without MySQL and Redis, for demos and small
+	// single-instance deployments: the database is the SQLite file at
+	// SQLitePath, migrated on start, and the session state lives in process,
+	// so a restart signs every user out. EMBEDDED_MODE=true turns it on.
+	Embedded struct {
+		Enabled    bool   `yaml:"enabled"`
+		SQLitePath string `yaml:"sqlite_path"`
+	} `yaml:"embedded"`
+
 	Mail struct {
 		SMTPHost     string `mapstructure:"smtpHost"`
 		SMTPPort     string `mapstructure:"smtpPort"`
@@ -42,14 +68,581 @@ type Config struct {
 		BaseAPIUrl string `mapstructure:"baseAPIUrl"`
 	}
 
+	IDs struct {
+		Strategy string `yaml:"strategy"`
+		NodeID   int64  `yaml:"node_id"`
+	} `yaml:"ids"`
+
+	JWT struct {
+		Format string `yaml:"format"`
+		Issuer string `yaml:"issuer"`
+		// ClockSkew and Leeway keep the package defaults when absent; an
+		// explicit 0s turns them off.
+		ClockSkew       *time.Duration `yaml:"clock_skew"`
+		Leeway          *time.Duration `yaml:"leeway"`
+		AcceptedIssuers []string       `yaml:"accepted_issuers"`
+		SkipIssuerCheck bool           `yaml:"skip_issuer_check"`
+		MaxTokenBytes   int            `yaml:"max_token_bytes"`
+		WarnTokenBytes  int            `yaml:"warn_token_bytes"`
+		CompactRoles    bool           `yaml:"compact_roles"`
+		ReferenceScopes bool           `yaml:"reference_scopes"`
+		AcceptHS256     bool           `yaml:"accept_hs256"`
+		// ConsumerLeeway replaces Leeway for tokens introspected by the named
+		// automation or introspection client, for resource servers with
+		// drifting clocks.
+		ConsumerLeeway map[string]time.Duration `yaml:"consumer_leeway"`
+		SigningKeys    struct {
+			Primary        string           `yaml:"primary"`
+			ReloadInterval time.Duration    `yaml:"reload_interval"`
+			Keys           []SigningKeyFile `yaml:"keys"`
+		} `yaml:"signing_keys"`
+	} `yaml:"jwt"`
+
+	Onboarding struct {
+		Enforce               bool     `yaml:"enforce"`
+		RequiredSteps         []string `yaml:"required_steps"`
+		RequireAtRegistration bool     `yaml:"require_at_registration"`
+	} `yaml:"onboarding"`
+
+	// TwoFactor is advertised to clients by /api/capabilities, so sign-in
+	// screens offer a second factor only where the deployment has one.
+	TwoFactor struct {
+		Enabled bool `yaml:"enabled"`
+	} `yaml:"two_factor"`
+
+	// Probation restricts accounts younger than Window: rate limits are
+	// divided by RateLimitDivisor, tokens require a verified email and at
+	// most MaxSessions devices may be signed in at once.
+	Probation struct {
+		Window               time.Duration `yaml:"window"`
+		RateLimitDivisor     int           `yaml:"rate_limit_divisor"`
+		RequireVerifiedEmail bool          `yaml:"require_verified_email"`
+		MaxSessions          int           `yaml:"max_sessions"`
+	} `yaml:"probation"`
+
+	// RefreshBinding ties a refresh token to the coarse network (IPv4/IPv6
+	// prefix) and user agent family it was issued to. Mode is off, log,
+	// step_up (refuse the refresh, keep the session) or block (refuse and
+	// revoke). AllowedNetworks, such as VPN egress ranges, never mismatch.
+	RefreshBinding struct {
+		Mode            string   `yaml:"mode"`
+		Network         bool     `yaml:"network"`
+		UserAgent       bool     `yaml:"user_agent"`
+		IPv4Prefix      int      `yaml:"ipv4_prefix"`
+		IPv6Prefix      int      `yaml:"ipv6_prefix"`
+		AllowedNetworks []string `yaml:"allowed_networks"`
+	} `yaml:"refresh_binding"`
+
+	// SessionTakeover flags an access token used from two networks or user
+	// agent families within Window, keeping the last Samples uses per token.
+	// Mode is off, alert (mark the session and alert) or restrict (also
+	// revoke the user's tokens). Prefixes and allowed networks are shared
+	// with RefreshBinding.
+	SessionTakeover struct {
+		Mode      string        `yaml:"mode"`
+		Network   bool          `yaml:"network"`
+		UserAgent bool          `yaml:"user_agent"`
+		Window    time.Duration `yaml:"window"`
+		Samples   int           `yaml:"samples"`
+	} `yaml:"session_takeover"`
+
+	// RefreshCanary keeps a decoy refresh token per user next to the real
+	// one in Redis. It is never handed to a client, so a refresh with it
+	// means Redis or a backup of it leaked: every token of the user is
+	// revoked and a critical alert fires.
+	RefreshCanary struct {
+		Enabled bool `yaml:"enabled"`
+	} `yaml:"refresh_canary"`
+
+	// Consent sets what registration requires per signup country (ISO 3166-1
+	// alpha-2). The country comes from the register input, else from
+	// CountryHeader set by the CDN; countries without an entry use Default.
+	Consent struct {
+		CountryHeader string                         `yaml:"country_header"`
+		Default       ConsentRequirements            `yaml:"default"`
+		Countries     map[string]ConsentRequirements `yaml:"countries"`
+	} `yaml:"consent"`
+
+	// Residency tags accounts with the data region holding their PII and
+	// sessions. Countries maps signup countries (ISO 3166-1 alpha-2) to
+	// regions, other countries get Default. Region is the one this
+	// deployment stores; with Enforce, repositories and the session store
+	// refuse accounts of other regions.
+	Residency struct {
+		Region    string            `yaml:"region"`
+		Default   string            `yaml:"default"`
+		Countries map[string]string `yaml:"countries"`
+		Enforce   bool              `yaml:"enforce"`
+	} `yaml:"residency"`
+
+	// Suspension configures the appeal link emailed to suspended users:
+	// AppealURL is the page that submits the appeal and receives the signed
+	// token as its token query parameter, valid for AppealTTL.
+	Suspension struct {
+		AppealURL string        `yaml:"appeal_url"`
+		AppealTTL time.Duration `yaml:"appeal_ttl"`
+	} `yaml:"suspension"`
+
+	// LoginAlerts emails the user when a sign-in comes from a network and a
+	// device missing from their last HistoryLimit successful logins within
+	// Lookback. RevokeURL is the page that receives the signed "this wasn't
+	// me" token as its token query parameter, valid for LinkTTL.
+	LoginAlerts struct {
+		Enabled      bool          `yaml:"enabled"`
+		Lookback     time.Duration `yaml:"lookback"`
+		HistoryLimit int           `yaml:"history_limit"`
+		RevokeURL    string        `yaml:"revoke_url"`
+		LinkTTL      time.Duration `yaml:"link_ttl"`
+	} `yaml:"login_alerts"`
+
+	// Deletion keeps soft deleted accounts for Retention, during which an
+	// admin can still reactivate them, then purges them in batches of
+	// PurgeBatch every PurgeInterval.
+	Deletion struct {
+		Retention     time.Duration `yaml:"retention"`
+		PurgeBatch    int           `yaml:"purge_batch"`
+		PurgeInterval time.Duration `yaml:"purge_interval"`
+	} `yaml:"deletion"`
+
+	// AccountActions runs the scheduled account actions that are due every
+	// ScanInterval, Batch at a time.
+	AccountActions struct {
+		ScanInterval time.Duration `yaml:"scan_interval"`
+		Batch        int           `yaml:"batch"`
+	} `yaml:"account_actions"`
+
+	// Branding is the default brand of auth emails and redirect pages.
+	// Organizations override it field by field; fields they leave empty
+	// fall back to these.
+	Branding struct {
+		ProductName  string `yaml:"product_name"`
+		LogoURL      string `yaml:"logo_url"`
+		PrimaryColor string `yaml:"primary_color"`
+		SupportEmail string `yaml:"support_email"`
+	} `yaml:"branding"`
+
+	HTTPS struct {
+		Enforce              bool   `yaml:"enforce"`
+		Mode                 string `yaml:"mode"`
+		RequireSecureCookies bool   `yaml:"require_secure_cookies"`
+		HSTS                 struct {
+			Enabled           bool          `yaml:"enabled"`
+			MaxAge            time.Duration `yaml:"max_age"`
+			IncludeSubdomains bool          `yaml:"include_subdomains"`
+		} `yaml:"hsts"`
+	} `yaml:"https"`
+
+	Sandbox struct {
+		Enabled          bool     `yaml:"enabled"`
+		VerificationCode string   `yaml:"verification_code"`
+		AllowedEmails    []string `yaml:"allowed_emails"`
+		CaptureMail      bool     `yaml:"capture_mail"`
+		StubOAuth        bool     `yaml:"stub_oauth"`
+		// TestTokens serves POST /sandbox/token, which signs allow-listed
+		// emails in without email verification. Callers send BootstrapSecret,
+		// read from SANDBOX_BOOTSTRAP_SECRET, in the X-Sandbox-Secret header.
+		TestTokens      bool   `yaml:"test_tokens"`
+		BootstrapSecret string `yaml:"-"`
+	} `yaml:"sandbox"`
+
+	Automation struct {
+		Header            string   `yaml:"header"`
+		TrustedIdentities []string `yaml:"trusted_identities"`
+		APIKeys           map[string]string
+
+		// Quotas cap each API client whatever IP it calls from, as clients
+		// are exempt from the IP rate limits. A client listed under Clients
+		// gets its entry instead of the default.
+		Quotas struct {
+			Default ClientQuota            `yaml:"default"`
+			Clients map[string]ClientQuota `yaml:"clients"`
+		} `yaml:"quotas"`
+	} `yaml:"automation"`
+
+	// Introspection holds the client credentials resource servers use at
+	// POST /oauth/introspect, read from INTROSPECTION_CLIENTS as
+	// client_id=client_secret pairs.
+	Introspection struct {
+		Clients map[string]string
+	} `yaml:"introspection"`
+
+	// OIDC makes the service an OpenID Connect provider for first-party
+	// apps: sign-ins also return an ID token for ClientID, and
+	// /.well-known/openid-configuration describes Issuer. Relying parties
+	// can only verify ID tokens themselves with jwt signing keys.
+	OIDC struct {
+		Enabled               bool          `yaml:"enabled"`
+		Issuer                string        `yaml:"issuer"`
+		ClientID              string        `yaml:"client_id"`
+		AuthorizationEndpoint string        `yaml:"authorization_endpoint"`
+		IDTokenTTL            time.Duration `yaml:"id_token_ttl"`
+	} `yaml:"oidc"`
+
+	// SLO sets the objectives /slo reports burn rates against: the share of
+	// password sign-ins faster than LoginLatency, of token validations that
+	// did not fail on the service side and of refreshes that succeeded.
+	SLO struct {
+		LoginTarget      float64       `yaml:"login_target"`
+		LoginLatency     time.Duration `yaml:"login_latency"`
+		ValidationTarget float64       `yaml:"validation_target"`
+		RefreshTarget    float64       `yaml:"refresh_target"`
+	} `yaml:"slo"`
+
+	// AdminAPI gates /admin/graphql to AllowedNetworks, verified client
+	// certificates on TrustedIdentities and the APIKeys sent in Header.
+	AdminAPI struct {
+		Header            string   `yaml:"header"`
+		AllowedNetworks   []string `yaml:"allowed_networks"`
+		TrustedIdentities []string `yaml:"trusted_identities"`
+		APIKeys           map[string]string
+	} `yaml:"admin_api"`
+
+	// Maintenance lets operators without an admin account call the listed
+	// admin mutations through a break-glass token. A token is minted once
+	// Approvals distinct admins approved the request within ApprovalWindow
+	// and lives for TokenTTL, at most 15 minutes. No operations disables it.
+	Maintenance struct {
+		Operations     []string      `yaml:"operations"`
+		TokenTTL       time.Duration `yaml:"token_ttl"`
+		ApprovalWindow time.Duration `yaml:"approval_window"`
+		Approvals      int           `yaml:"approvals"`
+	} `yaml:"maintenance"`
+
+	// ServiceAccounts trade their keys for access tokens living TokenTTL, at
+	// most an hour. A rotated-out key keeps working for RotationGrace.
+	ServiceAccounts struct {
+		TokenTTL      time.Duration `yaml:"token_ttl"`
+		RotationGrace time.Duration `yaml:"rotation_grace"`
+	} `yaml:"service_accounts"`
+
+	// TokenScopes grants scopes to user access tokens at sign-in and again at
+	// every refresh, so a role change applies from the next refresh.
+	// Default goes to everyone; Roles (ADMIN, USER) and Organizations (by
+	// slug) add to it. admin and admin:* scopes may only be granted to ADMIN.
+	TokenScopes struct {
+		Default       []string            `yaml:"default"`
+		Roles         map[string][]string `yaml:"roles"`
+		Organizations map[string][]string `yaml:"organizations"`
+	} `yaml:"token_scopes"`
+
+	// AuthDecisions caches the role checks of the @auth directive per user
+	// for CacheTTL, on top of the per-request memo. Role changes invalidate
+	// the user's decisions; 0 disables the cache.
+	AuthDecisions struct {
+		CacheTTL time.Duration `yaml:"cache_ttl"`
+	} `yaml:"auth_decisions"`
+
+	// EmailStatus tunes the emailStatus pre-check. Neutral answers UNKNOWN
+	// for every email so account existence never leaks; RequireCaptcha makes
+	// callers pass a token that Captcha verifies.
+	EmailStatus struct {
+		Neutral        bool `yaml:"neutral"`
+		RequireCaptcha bool `yaml:"require_captcha"`
+	} `yaml:"email_status"`
+
+	// Captcha verifies widget tokens against a siteverify endpoint. The
+	// secret comes from CAPTCHA_SECRET.
+	Captcha struct {
+		VerifyURL string `yaml:"verify_url"`
+		Secret    string
+	} `yaml:"captcha"`
+
+	// IPReputation scores client IPs with Provider: "abuseipdb", "feed" for
+	// any JSON API at FeedURL (with an {ip} placeholder) reporting a 0-100
+	// score at ScoreField, or empty to disable. Scores from StepUpScore
+	// require a CAPTCHA to sign in or register; scores from BlockScore are
+	// refused. Lookups are cached for CacheTTL. The key comes from
+	// IP_REPUTATION_API_KEY.
+	IPReputation struct {
+		Provider    string        `yaml:"provider"`
+		FeedURL     string        `yaml:"feed_url"`
+		ScoreField  string        `yaml:"score_field"`
+		StepUpScore int           `yaml:"step_up_score"`
+		BlockScore  int           `yaml:"block_score"`
+		CacheTTL    time.Duration `yaml:"cache_ttl"`
+		APIKey      string        `yaml:"-"`
+	} `yaml:"ip_reputation"`
+
+	// Limits caps request bodies. BodyBytes applies to every route and is
+	// enforced before the body is buffered; GraphQLBytes is a tighter cap for
+	// GraphQL requests. Multipart uploads stay disabled while UploadBytes is 0.
+	Limits struct {
+		BodyBytes         int   `yaml:"body_bytes"`
+		GraphQLBytes      int64 `yaml:"graphql_bytes"`
+		UploadBytes       int64 `yaml:"upload_bytes"`
+		UploadMemoryBytes int64 `yaml:"upload_memory_bytes"`
+	} `yaml:"limits"`
+
+	// QoS runs at most MaxConcurrent requests and queues the rest by class:
+	// token validation, refresh, login, registration, then analytics. Queued
+	// requests wait up to QueueTimeout; analytics is shed outright once
+	// ShedLowestAt of the slots are busy.
+	QoS struct {
+		Enabled       bool          `yaml:"enabled"`
+		MaxConcurrent int           `yaml:"max_concurrent"`
+		MaxQueue      int           `yaml:"max_queue"`
+		QueueTimeout  time.Duration `yaml:"queue_timeout"`
+		ShedLowestAt  float64       `yaml:"shed_lowest_at"`
+	} `yaml:"qos"`
+
+	// Server bounds the drain on SIGTERM or SIGINT: the app stops taking
+	// requests, then the scheduled jobs and workers stop, all within
+	// ShutdownTimeout. MetricsAddress, or METRICS_ADDRESS, moves /metrics
+	// and /slo off the app to a listener of their own, such as ":9090";
+	// without one the app serves them behind the admin API access rules.
+	Server struct {
+		ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+		MetricsAddress  string        `yaml:"metrics_address"`
+	} `yaml:"server"`
+
+	// LoginWorker reads login_events in the consumer group Group as
+	// Consumer, or LOGIN_WORKER_CONSUMER, defaulting to the hostname so
+	// replicas share the work. Entries a consumer left unacknowledged for
+	// ClaimIdle are claimed by another; after MaxDeliveries attempts they
+	// move to the dead-letter stream.
+	LoginWorker struct {
+		Group         string        `yaml:"group"`
+		Consumer      string        `yaml:"consumer"`
+		ClaimIdle     time.Duration `yaml:"claim_idle"`
+		MaxDeliveries int64         `yaml:"max_deliveries"`
+	} `yaml:"login_worker"`
+
+	// LoginHistory keeps login attempts for Retention, pruned in batches of
+	// PruneBatch every PruneInterval.
+	LoginHistory struct {
+		Retention     time.Duration `yaml:"retention"`
+		PruneBatch    int           `yaml:"prune_batch"`
+		PruneInterval time.Duration `yaml:"prune_interval"`
+	} `yaml:"login_history"`
+
+	// RefreshReminder emits a refresh_token_expiring event Lead before a
+	// refresh token expires, checked every ScanInterval. The optional
+	// webhook URL, and the secret its deliveries are signed with, come from
+	// the environment.
+	RefreshReminder struct {
+		Enabled       bool          `yaml:"enabled"`
+		Lead          time.Duration `yaml:"lead"`
+		ScanInterval  time.Duration `yaml:"scan_interval"`
+		WebhookURL    string        `yaml:"-"`
+		WebhookSecret string        `yaml:"-"`
+	} `yaml:"refresh_reminder"`
+
+	// VerificationNudges emails a fresh code, valid for CodeTTL, to
+	// registrations left unverified for Delay, checked every ScanInterval.
+	// A registration is nudged at most MaxPerRegistration times, and at
+	// most HourlyCap nudges go out an hour. Each email links to
+	// UnsubscribeURL, whose page posts the token to
+	// stopVerificationReminders.
+	VerificationNudges struct {
+		Enabled            bool          `yaml:"enabled"`
+		Delay              time.Duration `yaml:"delay"`
+		MaxPerRegistration int           `yaml:"max_per_registration"`
+		HourlyCap          int64         `yaml:"hourly_cap"`
+		CodeTTL            time.Duration `yaml:"code_ttl"`
+		ScanInterval       time.Duration `yaml:"scan_interval"`
+		UnsubscribeURL     string        `yaml:"unsubscribe_url"`
+	} `yaml:"verification_nudges"`
+
+	// Sessions ends refresh sessions left unused for IdleTimeout: every
+	// authenticated request slides the refresh token's expiry by one idle
+	// window, up to MaxLifetime after sign-in (at most the refresh token's
+	// own lifetime). 0 keeps the absolute expiry only. Store keeps the
+	// sessions in redis (the default), memory (one instance only) or sql,
+	// the service's database.
+	Sessions struct {
+		IdleTimeout time.Duration `yaml:"idle_timeout"`
+		MaxLifetime time.Duration `yaml:"max_lifetime"`
+		Store       string        `yaml:"store"`
+	} `yaml:"sessions"`
+
+	// Workers bounds the goroutines spawned by background paths; jobs beyond
+	// a full queue are dropped or block according to the pool's policy.
+	Workers struct {
+		Mail    WorkerPool `yaml:"mail"`
+		Relogin WorkerPool `yaml:"relogin"`
+	} `yaml:"workers"`
+
+	// Alerting pages humans on security relevant spikes. Webhook URLs and
+	// routing keys come from the environment, not the yaml files.
+	Alerting struct {
+		Enabled   bool          `yaml:"enabled"`
+		Cooldown  time.Duration `yaml:"cooldown"`
+		Slack     AlertChannel  `yaml:"slack"`
+		PagerDuty AlertChannel  `yaml:"pagerduty"`
+		RateLimit struct {
+			// Rejections across all clients within Window that count as a
+			// sustained attack on one operation.
+			Rejections int           `yaml:"rejections"`
+			Window     time.Duration `yaml:"window"`
+		} `yaml:"rate_limit"`
+	} `yaml:"alerting"`
+
+	// OAuthHealth flags a provider as degraded once its failure rate over the
+	// window reaches FailureRate with at least MinSamples attempts.
+	OAuthHealth struct {
+		Window      time.Duration `yaml:"window"`
+		MinSamples  int           `yaml:"min_samples"`
+		FailureRate float64       `yaml:"failure_rate"`
+	} `yaml:"oauth_health"`
+
+	// Logging sets the minimum level (debug, info, warn, error) and the
+	// format (text or json) of the service logs. LOG_LEVEL and LOG_FORMAT
+	// override them.
+	Logging struct {
+		Level  string `yaml:"level"`
+		Format string `yaml:"format"`
+	} `yaml:"logging"`
+
+	// Tracing exports OpenTelemetry spans over OTLP/HTTP to Endpoint, e.g.
+	// http://otel-collector:4318. Requests arriving without a sampled
+	// traceparent are kept at SampleRatio. OTEL_EXPORTER_OTLP_ENDPOINT
+	// overrides the endpoint and OTEL_EXPORTER_OTLP_HEADERS adds headers,
+	// such as the collector's API key.
+	Tracing struct {
+		Enabled     bool    `yaml:"enabled"`
+		ServiceName string  `yaml:"service_name"`
+		Endpoint    string  `yaml:"endpoint"`
+		SampleRatio float64 `yaml:"sample_ratio"`
+	} `yaml:"tracing"`
+
+	// SIEM exports login outcomes, lockouts, deny-list hits, revocations and
+	// account audit events to the SOC's collector as CEF or JSON Lines
+	// (Format "cef" or "jsonl"). Endpoint is an http(s) URL receiving
+	// newline separated batches, or a syslog target such as
+	// tcp://siem:514, udp://siem:514 or tls://siem:6514. SIEM_ENDPOINT
+	// overrides it and SIEM_AUTHORIZATION sets the Authorization header
+	// sent to HTTP collectors. Every Interval, at most MaxBatches batches
+	// of BatchSize events are delivered.
+	SIEM struct {
+		Enabled    bool          `yaml:"enabled"`
+		Format     string        `yaml:"format"`
+		Endpoint   string        `yaml:"endpoint"`
+		BatchSize  int           `yaml:"batch_size"`
+		MaxBatches int           `yaml:"max_batches"`
+		Interval   time.Duration `yaml:"interval"`
+		Timeout    time.Duration `yaml:"timeout"`
+
+		Authorization string `yaml:"-"`
+	} `yaml:"siem"`
+
+	// OAuth registers sign-in providers beyond the built-in ones, keyed by
+	// the name used in callback URLs and in OAuthLoginInput.providerName.
+	OAuth struct {
+		Providers map[string]OAuthProvider `yaml:"providers"`
+	} `yaml:"oauth"`
+
+	// GeoPolicy blocks or steps up sign-ins by the country (ISO 3166-1
+	// alpha-2) the CDN reports in CountryHeader. Rules list countries or the
+	// names of Regions; a step-up requires a CAPTCHA. Organizations, keyed
+	// by slug, replace the rules for their members. Requests without the
+	// header are allowed.
+	GeoPolicy struct {
+		CountryHeader string              `yaml:"country_header"`
+		Regions       map[string][]string `yaml:"regions"`
+		GeoRules      `yaml:",inline"`
+		Organizations map[string]GeoRules `yaml:"organizations"`
+	} `yaml:"geo_policy"`
+
 	Providers struct {
 		GoogleClientID     string `mapstructure:"googleClientID"`
 		GoogleClientSecret string `mapstructure:"googleClientSecret"`
 		FBClientID         string `mapstructure:"fbClientID"`
 		FBClientSecret     string `mapstructure:"fbClientSecret"`
+		// Sign in with Apple: the Services ID, the developer team, and the
+		// ID and .p8 file of the key that signs client secrets.
+		AppleClientID       string `mapstructure:"appleClientID"`
+		AppleTeamID         string `mapstructure:"appleTeamID"`
+		AppleKeyID          string `mapstructure:"appleKeyID"`
+		ApplePrivateKeyFile string `mapstructure:"applePrivateKeyFile"`
+		GitHubClientID      string `mapstructure:"githubClientID"`
+		GitHubClientSecret  string `mapstructure:"githubClientSecret"`
+		// MicrosoftTenantID restricts sign-in to one Azure AD tenant; empty
+		// accepts work, school and personal accounts alike.
+		MicrosoftClientID     string `mapstructure:"microsoftClientID"`
+		MicrosoftClientSecret string `mapstructure:"microsoftClientSecret"`
+		MicrosoftTenantID     string `mapstructure:"microsoftTenantID"`
 	}
 }
 
+// OAuthProvider describes an OAuth 2.0 provider with a JSON userinfo
+// endpoint. Its client credentials are read from OAUTH_<NAME>_CLIENT_ID and
+// OAUTH_<NAME>_CLIENT_SECRET, with dashes in the name as underscores.
+type OAuthProvider struct {
+	AuthURL     string      `yaml:"auth_url"`
+	TokenURL    string      `yaml:"token_url"`
+	UserInfoURL string      `yaml:"userinfo_url"`
+	Scopes      []string    `yaml:"scopes"`
+	PKCE        bool        `yaml:"pkce"`
+	Claims      OAuthClaims `yaml:"claims"`
+
+	ClientID     string `yaml:"-"`
+	ClientSecret string `yaml:"-"`
+}
+
+// OAuthClaims names the userinfo fields holding the profile. Empty fields
+// default to the OpenID Connect standard claims.
+type OAuthClaims struct {
+	ID        string `yaml:"id"`
+	Email     string `yaml:"email"`
+	Name      string `yaml:"name"`
+	FirstName string `yaml:"first_name"`
+	LastName  string `yaml:"last_name"`
+	// EmailVerified lets sign-ins link to an existing account with the
+	// same verified email. Leave the claim out of providers that do not
+	// verify addresses.
+	EmailVerified string `yaml:"email_verified"`
+}
+
+// ClientQuota limits one API client; zero leaves a dimension unlimited.
+// Counters roll over at the start of each UTC hour, day and month.
+type ClientQuota struct {
+	RequestsPerDay   int64 `yaml:"requests_per_day"`
+	RequestsPerMonth int64 `yaml:"requests_per_month"`
+	LoginsPerHour    int64 `yaml:"logins_per_hour"`
+}
+
+type AlertChannel struct {
+	MinSeverity string `yaml:"min_severity"`
+	Target      string
+}
+
+// ConsentRequirements applies to one country. A country entry replaces the
+// default entirely; a zero MinimumAge skips the age check and an empty
+// TermsVersion means no terms have to be accepted at registration.
+type ConsentRequirements struct {
+	MarketingOptInDefault bool   `yaml:"marketing_opt_in_default"`
+	MinimumAge            int    `yaml:"minimum_age"`
+	TermsVersion          string `yaml:"terms_version"`
+}
+
+// SigningKeyFile is a PEM key on disk. A public key only verifies tokens, for
+// keys being rotated in or out.
+type SigningKeyFile struct {
+	ID        string `yaml:"kid"`
+	Algorithm string `yaml:"algorithm"`
+	File      string `yaml:"file"`
+}
+
+type WorkerPool struct {
+	Size   int    `yaml:"size"`
+	Queue  int    `yaml:"queue"`
+	Policy string `yaml:"policy"`
+}
+
+// oauthProviderName keeps configured provider names usable as a callback
+// path segment and an environment variable prefix.
+var oauthProviderName = regexp.MustCompile(`^[a-z][a-z0-9-]{0,29}$`)
+
+// GeoRules lists where sign-ins are refused or need a CAPTCHA. A country in
+// both lists is blocked.
+type GeoRules struct {
+	Block  []string `yaml:"block"`
+	StepUp []string `yaml:"step_up"`
+}
+
+// countryCodePattern matches ISO 3166-1 alpha-2 codes in either case.
+var countryCodePattern = regexp.MustCompile(`^[A-Za-z]{2}$`)
+
 func Load(env string) (*Config, error) {
 	var cfg Config
 	configFile := "dev.yml"
@@ -84,15 +677,205 @@ func Load(env string) (*Config, error) {
 	cfg.Providers.GoogleClientSecret = os.Getenv("GOOGLE_CLIENT_SECRET")
 	cfg.Providers.FBClientID = os.Getenv("FACEBOOK_CLIENT_ID")
 	cfg.Providers.FBClientSecret = os.Getenv("FACEBOOK_CLIENT_SECRET")
+	cfg.Providers.AppleClientID = os.Getenv("APPLE_CLIENT_ID")
+	cfg.Providers.AppleTeamID = os.Getenv("APPLE_TEAM_ID")
+	cfg.Providers.AppleKeyID = os.Getenv("APPLE_KEY_ID")
+	cfg.Providers.ApplePrivateKeyFile = os.Getenv("APPLE_PRIVATE_KEY_FILE")
+	cfg.Providers.GitHubClientID = os.Getenv("GITHUB_CLIENT_ID")
+	cfg.Providers.GitHubClientSecret = os.Getenv("GITHUB_CLIENT_SECRET")
+	cfg.Providers.MicrosoftClientID = os.Getenv("MICROSOFT_CLIENT_ID")
+	cfg.Providers.MicrosoftClientSecret = os.Getenv("MICROSOFT_CLIENT_SECRET")
+	cfg.Providers.MicrosoftTenantID = os.Getenv("MICROSOFT_TENANT_ID")
+	for name, provider := range cfg.OAuth.Providers {
+		envName := "OAUTH_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+		provider.ClientID = os.Getenv(envName + "_CLIENT_ID")
+		provider.ClientSecret = os.Getenv(envName + "_CLIENT_SECRET")
+		cfg.OAuth.Providers[name] = provider
+	}
+
+	if nodeID := os.Getenv("ID_NODE_ID"); nodeID != "" {
+		parsed, err := strconv.ParseInt(nodeID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ID_NODE_ID: %w", err)
+		}
+		cfg.IDs.NodeID = parsed
+	}
+
+	cfg.Automation.APIKeys = parseAutomationKeys(os.Getenv("AUTOMATION_API_KEYS"))
+	cfg.AdminAPI.APIKeys = parseAutomationKeys(os.Getenv("ADMIN_API_KEYS"))
+	cfg.Introspection.Clients = parseAutomationKeys(os.Getenv("INTROSPECTION_CLIENTS"))
+	cfg.Captcha.Secret = os.Getenv("CAPTCHA_SECRET")
+	cfg.IPReputation.APIKey = os.Getenv("IP_REPUTATION_API_KEY")
+	cfg.Alerting.Slack.Target = os.Getenv("ALERT_SLACK_WEBHOOK_URL")
+	cfg.Alerting.PagerDuty.Target = os.Getenv("ALERT_PAGERDUTY_ROUTING_KEY")
+	cfg.RefreshReminder.WebhookURL = os.Getenv("REFRESH_REMINDER_WEBHOOK_URL")
+	cfg.RefreshReminder.WebhookSecret = os.Getenv("REFRESH_REMINDER_WEBHOOK_SECRET")
+	cfg.Sandbox.BootstrapSecret = os.Getenv("SANDBOX_BOOTSTRAP_SECRET")
+
+	if os.Getenv("MIGRATION_ALLOW_UNSAFE") == "true" {
+		cfg.DB.Guard.AllowUnsafe = true
+	}
+	if os.Getenv("EMBEDDED_MODE") == "true" {
+		cfg.Embedded.Enabled = true
+	}
+	if level := os.Getenv("LOG_LEVEL"); level != "" {
+		cfg.Logging.Level = level
+	}
+	if format := os.Getenv("LOG_FORMAT"); format != "" {
+		cfg.Logging.Format = format
+	}
+	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); endpoint != "" {
+		cfg.Tracing.Endpoint = endpoint
+	}
+	if endpoint := os.Getenv("SIEM_ENDPOINT"); endpoint != "" {
+		cfg.SIEM.Endpoint = endpoint
+	}
+	cfg.SIEM.Authorization = os.Getenv("SIEM_AUTHORIZATION")
+	if addr := os.Getenv("METRICS_ADDRESS"); addr != "" {
+		cfg.Server.MetricsAddress = addr
+	}
+	if consumer := os.Getenv("LOGIN_WORKER_CONSUMER"); consumer != "" {
+		cfg.LoginWorker.Consumer = consumer
+	}
 
 	cfg.Env.CurrentEnv = os.Getenv("APP_ENV")
 	cfg.Env.BaseAPIUrl = os.Getenv("PRO_BASE_API_URL")
 
 	expandConfig(&cfg, env)
 
+	if cfg.Sandbox.Enabled && (env == "production" || cfg.Env.CurrentEnv == "production") {
+		return nil, fmt.Errorf("sandbox mode cannot be enabled in production")
+	}
+
+	if cfg.Sandbox.TestTokens && len(cfg.Sandbox.BootstrapSecret) < 32 {
+		return nil, fmt.Errorf("sandbox.test_tokens needs SANDBOX_BOOTSTRAP_SECRET of at least 32 characters")
+	}
+
+	for consumer, leeway := range cfg.JWT.ConsumerLeeway {
+		if leeway < 0 || leeway > 5*time.Minute {
+			return nil, fmt.Errorf("jwt.consumer_leeway.%s must be between 0s and 5m, got %s", consumer, leeway)
+		}
+	}
+
+	switch cfg.RefreshBinding.Mode {
+	case "", "off", "log", "step_up", "block":
+	default:
+		return nil, fmt.Errorf("refresh_binding.mode must be off, log, step_up or block, got %q", cfg.RefreshBinding.Mode)
+	}
+	for _, network := range cfg.RefreshBinding.AllowedNetworks {
+		if _, err := netip.ParsePrefix(network); err != nil {
+			return nil, fmt.Errorf("refresh_binding.allowed_networks: %w", err)
+		}
+	}
+
+	switch cfg.SessionTakeover.Mode {
+	case "", "off", "alert", "restrict":
+	default:
+		return nil, fmt.Errorf("session_takeover.mode must be off, alert or restrict, got %q", cfg.SessionTakeover.Mode)
+	}
+
+	for name, provider := range cfg.OAuth.Providers {
+		if !oauthProviderName.MatchString(name) {
+			return nil, fmt.Errorf("oauth.providers: %q must be lowercase letters, digits and dashes", name)
+		}
+		if provider.AuthURL == "" || provider.TokenURL == "" || provider.UserInfoURL == "" {
+			return nil, fmt.Errorf("oauth.providers.%s needs auth_url, token_url and userinfo_url", name)
+		}
+	}
+
+	switch cfg.Sessions.Store {
+	case "", "redis", "memory", "sql":
+	default:
+		return nil, fmt.Errorf("sessions.store must be redis, memory or sql, got %q", cfg.Sessions.Store)
+	}
+
+	if cfg.Residency.Enforce && cfg.Residency.Region == "" {
+		return nil, fmt.Errorf("residency.enforce needs residency.region")
+	}
+
+	if cfg.Maintenance.TokenTTL < 0 || cfg.Maintenance.TokenTTL > 15*time.Minute {
+		return nil, fmt.Errorf("maintenance.token_ttl must be between 0s and 15m, got %s", cfg.Maintenance.TokenTTL)
+	}
+	if cfg.Maintenance.Approvals != 0 && cfg.Maintenance.Approvals < 2 {
+		return nil, fmt.Errorf("maintenance.approvals must be at least 2, got %d", cfg.Maintenance.Approvals)
+	}
+
+	if cfg.ServiceAccounts.TokenTTL < 0 || cfg.ServiceAccounts.TokenTTL > time.Hour {
+		return nil, fmt.Errorf("service_accounts.token_ttl must be between 0s and 1h, got %s", cfg.ServiceAccounts.TokenTTL)
+	}
+	if cfg.ServiceAccounts.RotationGrace < 0 {
+		return nil, fmt.Errorf("service_accounts.rotation_grace must not be negative, got %s", cfg.ServiceAccounts.RotationGrace)
+	}
+
+	if cfg.Limits.BodyBytes > 0 && cfg.Limits.UploadBytes > int64(cfg.Limits.BodyBytes) {
+		return nil, fmt.Errorf("limits.upload_bytes cannot exceed limits.body_bytes")
+	}
+
+	if cfg.QoS.Enabled && (cfg.QoS.MaxConcurrent <= 0 || cfg.QoS.MaxQueue < 0 || cfg.QoS.ShedLowestAt <= 0 || cfg.QoS.ShedLowestAt > 1) {
+		return nil, fmt.Errorf("qos needs a positive max_concurrent, a non-negative max_queue and shed_lowest_at in (0, 1]")
+	}
+
+	if cfg.EmailStatus.RequireCaptcha && (cfg.Captcha.VerifyURL == "" || cfg.Captcha.Secret == "") {
+		return nil, fmt.Errorf("email_status.require_captcha needs captcha.verify_url and CAPTCHA_SECRET")
+	}
+
+	switch reputation := cfg.IPReputation; {
+	case reputation.Provider != "" && reputation.Provider != "abuseipdb" && reputation.Provider != "feed":
+		return nil, fmt.Errorf("ip_reputation.provider must be abuseipdb, feed or empty, got %q", reputation.Provider)
+	case reputation.Provider == "feed" && reputation.FeedURL == "":
+		return nil, fmt.Errorf("ip_reputation.provider feed needs ip_reputation.feed_url")
+	case reputation.StepUpScore < 0 || reputation.BlockScore < 0 || reputation.StepUpScore > 100 || reputation.BlockScore > 100:
+		return nil, fmt.Errorf("ip_reputation scores must be between 0 and 100")
+	case reputation.StepUpScore > 0 && reputation.BlockScore > 0 && reputation.StepUpScore > reputation.BlockScore:
+		return nil, fmt.Errorf("ip_reputation.step_up_score cannot exceed ip_reputation.block_score")
+	case reputation.StepUpScore > 0 && (cfg.Captcha.VerifyURL == "" || cfg.Captcha.Secret == ""):
+		return nil, fmt.Errorf("ip_reputation.step_up_score needs captcha.verify_url and CAPTCHA_SECRET")
+	}
+
+	if err := cfg.validateGeoPolicy(); err != nil {
+		return nil, err
+	}
+
 	return &cfg, nil
 }
 
+// validateGeoPolicy checks that rules have a header to read the country
+// from and only name countries or configured regions.
+func (c *Config) validateGeoPolicy() error {
+	geo := c.GeoPolicy
+	for name, countries := range geo.Regions {
+		for _, country := range countries {
+			if !countryCodePattern.MatchString(strings.TrimSpace(country)) {
+				return fmt.Errorf("geo_policy.regions.%s: %q is not a country code", name, country)
+			}
+		}
+	}
+
+	rules := map[string]GeoRules{"geo_policy": geo.GeoRules}
+	for slug, r := range geo.Organizations {
+		rules["geo_policy.organizations."+slug] = r
+	}
+	for path, r := range rules {
+		if len(r.Block) == 0 && len(r.StepUp) == 0 {
+			continue
+		}
+		if geo.CountryHeader == "" {
+			return fmt.Errorf("%s needs geo_policy.country_header", path)
+		}
+		if len(r.StepUp) > 0 && (c.Captcha.VerifyURL == "" || c.Captcha.Secret == "") {
+			return fmt.Errorf("%s.step_up needs captcha.verify_url and CAPTCHA_SECRET", path)
+		}
+		for _, entries := range [][]string{r.Block, r.StepUp} {
+			for _, entry := range entries {
+				if _, ok := geo.Regions[entry]; !ok && !countryCodePattern.MatchString(strings.TrimSpace(entry)) {
+					return fmt.Errorf("%s: %q is neither a country code nor a region", path, entry)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func (c *Config) SQL_DSB() string {
 	if c.Env.CurrentEnv == "production" {
 		urlString := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true&timeout=30s", c.DB.User, c.DB.Password, c.DB.Host, c.DB.Port, c.DB.Name)
@@ -171,6 +954,22 @@ func getRedisPassword() string {
 	return password
 }
 
+// parseAutomationKeys reads "name=key" pairs separated by commas.
+func parseAutomationKeys(raw string) map[string]string {
+	keys := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		name, key, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+		name, key = strings.TrimSpace(name), strings.TrimSpace(key)
+		if name != "" && key != "" {
+			keys[name] = key
+		}
+	}
+	return keys
+}
+
 func expandConfig(cfg *Config, env string) {
 	dbPassVar := "DEV_DB_PASSWORD"
 	if env == "production" {
@@ -1,10 +1,13 @@
 package database
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -13,9 +16,15 @@ import (
 	"entgo.io/ent/dialect/sql/schema"
 	"github.com/abisalde/authentication-service/internal/configs"
 	"github.com/abisalde/authentication-service/internal/database/ent"
+	entmigrate "github.com/abisalde/authentication-service/internal/database/ent/migrate"
+	"github.com/abisalde/authentication-service/internal/database/migrationguard"
+	"github.com/abisalde/authentication-service/internal/tracing"
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/mattn/go-sqlite3"
 )
 
+const defaultSQLitePath = "data/authservice.db"
+
 var (
 	client     *ent.Client
 	clientOnce sync.Once
@@ -36,7 +45,13 @@ func Connect(cfg *configs.Config) (*Database, error) {
 
 	clientOnce.Do(func() {
 		var err error
-		sqlDB, err = initDatabase(cfg)
+		driverName := dialect.MySQL
+		if cfg.Embedded.Enabled {
+			driverName = dialect.SQLite
+			sqlDB, err = initSQLite(cfg)
+		} else {
+			sqlDB, err = initDatabase(cfg)
+		}
 		if err != nil {
 			initErr = fmt.Errorf("🛑 Database initialization failed: %w", err)
 			return
@@ -44,11 +59,12 @@ func Connect(cfg *configs.Config) (*Database, error) {
 		env := cfg.Env.CurrentEnv
 		isDev := env != "production"
 
-		drv := entsql.OpenDB(dialect.MySQL, sqlDB)
-		dbClient = ent.NewClient(ent.Driver(drv), ent.Debug(), ent.Log(log.Print))
+		drv := tracing.Driver(entsql.OpenDB(driverName, sqlDB))
+		dbClient = ent.NewClient(ent.Driver(drv), ent.Debug(), ent.Log(func(v ...any) { slog.Debug(fmt.Sprint(v...)) }))
 
-		if cfg.DB.Migrate {
-			if err := migrate(context.Background(), dbClient, isDev); err != nil {
+		// Nothing else creates the embedded database's schema.
+		if cfg.DB.Migrate || cfg.Embedded.Enabled {
+			if err := migrate(context.Background(), dbClient, cfg, isDev); err != nil {
 				initErr = fmt.Errorf("🛠️ Database migration failed: %w", err)
 				_ = dbClient.Close()
 				_ = sqlDB.Close()
@@ -82,22 +98,41 @@ func (db *Database) Close() error {
 	return nil
 }
 
-func migrate(ctx context.Context, client *ent.Client, isDev bool) error {
-	if isDev {
-		return client.Schema.Create(
-			ctx,
-			schema.WithDropIndex(true),
-			schema.WithDropColumn(true),
-			schema.WithForeignKeys(true),
-		)
+func migrate(ctx context.Context, client *ent.Client, cfg *configs.Config, isDev bool) error {
+	opts := []schema.MigrateOption{
+		schema.WithDropIndex(isDev),
+		schema.WithDropColumn(isDev),
+		schema.WithForeignKeys(true),
 	}
 
-	return client.Schema.Create(
-		ctx,
-		schema.WithDropIndex(false),
-		schema.WithDropColumn(false),
-		schema.WithForeignKeys(true),
-	)
+	// The guard reads MySQL migration plans; a single-instance SQLite file
+	// has no live traffic to lock out.
+	if cfg.DB.Guard.Enabled && !cfg.Embedded.Enabled {
+		if err := guardMigration(ctx, client, cfg, opts); err != nil {
+			return err
+		}
+	}
+
+	return client.Schema.Create(ctx, opts...)
+}
+
+// guardMigration plans the auto-migration and refuses to apply it when the
+// plan has operations that need downtime, unless the guard is overridden.
+func guardMigration(ctx context.Context, client *ent.Client, cfg *configs.Config, opts []schema.MigrateOption) error {
+	var plan bytes.Buffer
+	if err := client.Schema.WriteTo(ctx, &plan, opts...); err != nil {
+		return fmt.Errorf("failed to plan migration: %w", err)
+	}
+
+	err := migrationguard.Check(plan.String(), migrationguard.Options{
+		LockSensitiveTables: cfg.DB.Guard.LockSensitiveTables,
+		InUse:               migrationguard.InUseBySchema(entmigrate.Tables),
+	})
+	if err != nil && cfg.DB.Guard.AllowUnsafe {
+		slog.Warn("Migration guard overridden", "error", err)
+		return nil
+	}
+	return err
 }
 
 func initDatabase(cfg *configs.Config) (*sql.DB, error) {
@@ -123,6 +158,33 @@ func initDatabase(cfg *configs.Config) (*sql.DB, error) {
 
 }
 
+// initSQLite opens the embedded database file, creating its directory.
+// SQLite allows one writer at a time, so a single connection serializes
+// writes instead of failing them with "database is locked".
+func initSQLite(cfg *configs.Config) (*sql.DB, error) {
+	path := cfg.Embedded.SQLitePath
+	if path == "" {
+		path = defaultSQLitePath
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("❌ Failed to create the SQLite directory: %w", err)
+	}
+
+	sqlDB, err := sql.Open(dialect.SQLite, "file:"+path+"?_fk=1&_journal_mode=WAL&_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to open SQLite database: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := sqlDB.Ping(); err != nil {
+		_ = sqlDB.Close()
+		return nil, fmt.Errorf("⚙️ SQLite ping failed: %w", err)
+	}
+
+	slog.Info("Running embedded with SQLite", "path", path)
+	return sqlDB, nil
+}
+
 func (db *Database) HealthCheck(ctx context.Context) error {
 	if db.SQLDB == nil {
 		return fmt.Errorf("🎛️ Database connection not initialized")
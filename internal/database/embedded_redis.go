@@ -0,0 +1,55 @@
+package database
+
+import (
+	"crypto/rand"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// embeddedClockTick is how often the embedded store's TTLs are advanced, and
+// so how late past its expiry a key may still be read.
+const embeddedClockTick = 100 * time.Millisecond
+
+// startEmbeddedRedis runs miniredis inside the process for embedded mode. It
+// executes the service's Lua scripts, streams and transactions as Redis does,
+// so no command needs a separate implementation.
+//
+// miniredis only listens on TCP, so it is bound to loopback and protected by
+// a password generated per start. Its TTLs only move when told to, so a
+// ticker advances them with the wall clock.
+func startEmbeddedRedis() (*redis.Client, func(), error) {
+	mr := miniredis.NewMiniRedis()
+	if err := mr.Start(); err != nil {
+		return nil, nil, err
+	}
+	password := rand.Text()
+	mr.RequireAuth(password)
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(embeddedClockTick)
+		defer ticker.Stop()
+		last := time.Now()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				mr.FastForward(now.Sub(last))
+				last = now
+			}
+		}
+	}()
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     mr.Addr(),
+		Password: password,
+	})
+	stop := func() {
+		close(done)
+		mr.Close()
+	}
+	return rdb, stop, nil
+}
@@ -12,8 +12,13 @@ import (
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/abisalde/authentication-service/internal/database/ent/loginattempt"
+	"github.com/abisalde/authentication-service/internal/database/ent/organization"
+	"github.com/abisalde/authentication-service/internal/database/ent/scheduledaction"
+	"github.com/abisalde/authentication-service/internal/database/ent/serviceaccountkey"
 	"github.com/abisalde/authentication-service/internal/database/ent/user"
 	"github.com/abisalde/authentication-service/internal/database/ent/useraddress"
+	"github.com/abisalde/authentication-service/internal/database/ent/useridentity"
 )
 
 // ent aliases to avoid import conflicts in user's code.
@@ -71,14 +76,19 @@ var (
 )
 
 // checkColumn checks if the column exists in the given table.
-func checkColumn(table, column string) error {
+func checkColumn(t, c string) error {
 	initCheck.Do(func() {
 		columnCheck = sql.NewColumnCheck(map[string]func(string) bool{
-			user.Table:        user.ValidColumn,
-			useraddress.Table: useraddress.ValidColumn,
+			loginattempt.Table:      loginattempt.ValidColumn,
+			organization.Table:      organization.ValidColumn,
+			scheduledaction.Table:   scheduledaction.ValidColumn,
+			serviceaccountkey.Table: serviceaccountkey.ValidColumn,
+			user.Table:              user.ValidColumn,
+			useraddress.Table:       useraddress.ValidColumn,
+			useridentity.Table:      useridentity.ValidColumn,
 		})
 	})
-	return columnCheck(table, column)
+	return columnCheck(t, c)
 }
 
 // Asc applies the given fields in ASC order.
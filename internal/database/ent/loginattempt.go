@@ -0,0 +1,268 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/abisalde/authentication-service/internal/database/ent/loginattempt"
+	"github.com/abisalde/authentication-service/internal/database/ent/user"
+	"github.com/abisalde/authentication-service/pkg/useragent"
+)
+
+// LoginAttempt is the model entity for the LoginAttempt schema.
+type LoginAttempt struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int64 `json:"id,omitempty"`
+	// UserID holds the value of the "user_id" field.
+	UserID *int64 `json:"userId"`
+	// Email holds the value of the "email" field.
+	Email string `json:"email,omitempty"`
+	// IP holds the value of the "ip" field.
+	IP string `json:"ip,omitempty"`
+	// DeviceID holds the value of the "device_id" field.
+	DeviceID string `json:"deviceId"`
+	// Platform holds the value of the "platform" field.
+	Platform string `json:"platform,omitempty"`
+	// UserAgent holds the value of the "user_agent" field.
+	UserAgent string `json:"userAgent"`
+	// ClientHints holds the value of the "client_hints" field.
+	ClientHints useragent.ClientHints `json:"clientHints,omitempty"`
+	// Method holds the value of the "method" field.
+	Method loginattempt.Method `json:"method,omitempty"`
+	// Outcome holds the value of the "outcome" field.
+	Outcome loginattempt.Outcome `json:"outcome,omitempty"`
+	// FailureReason holds the value of the "failure_reason" field.
+	FailureReason *loginattempt.FailureReason `json:"failureReason"`
+	// RiskScore holds the value of the "risk_score" field.
+	RiskScore int `json:"riskScore"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"createdAt"`
+	// Edges holds the relations/edges for other nodes in the graph.
+	// The values are being populated by the LoginAttemptQuery when eager-loading is set.
+	Edges        LoginAttemptEdges `json:"edges"`
+	selectValues sql.SelectValues
+}
+
+// LoginAttemptEdges holds the relations/edges for other nodes in the graph.
+type LoginAttemptEdges struct {
+	// User holds the value of the user edge.
+	User *User `json:"user,omitempty"`
+	// loadedTypes holds the information for reporting if a
+	// type was loaded (or requested) in eager-loading or not.
+	loadedTypes [1]bool
+}
+
+// UserOrErr returns the User value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e LoginAttemptEdges) UserOrErr() (*User, error) {
+	if e.User != nil {
+		return e.User, nil
+	} else if e.loadedTypes[0] {
+		return nil, &NotFoundError{label: user.Label}
+	}
+	return nil, &NotLoadedError{edge: "user"}
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*LoginAttempt) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case loginattempt.FieldClientHints:
+			values[i] = new([]byte)
+		case loginattempt.FieldID, loginattempt.FieldUserID, loginattempt.FieldRiskScore:
+			values[i] = new(sql.NullInt64)
+		case loginattempt.FieldEmail, loginattempt.FieldIP, loginattempt.FieldDeviceID, loginattempt.FieldPlatform, loginattempt.FieldUserAgent, loginattempt.FieldMethod, loginattempt.FieldOutcome, loginattempt.FieldFailureReason:
+			values[i] = new(sql.NullString)
+		case loginattempt.FieldCreatedAt:
+			values[i] = new(sql.NullTime)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the LoginAttempt fields.
+func (_m *LoginAttempt) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case loginattempt.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			_m.ID = int64(value.Int64)
+		case loginattempt.FieldUserID:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field user_id", values[i])
+			} else if value.Valid {
+				_m.UserID = new(int64)
+				*_m.UserID = value.Int64
+			}
+		case loginattempt.FieldEmail:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field email", values[i])
+			} else if value.Valid {
+				_m.Email = value.String
+			}
+		case loginattempt.FieldIP:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field ip", values[i])
+			} else if value.Valid {
+				_m.IP = value.String
+			}
+		case loginattempt.FieldDeviceID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field device_id", values[i])
+			} else if value.Valid {
+				_m.DeviceID = value.String
+			}
+		case loginattempt.FieldPlatform:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field platform", values[i])
+			} else if value.Valid {
+				_m.Platform = value.String
+			}
+		case loginattempt.FieldUserAgent:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field user_agent", values[i])
+			} else if value.Valid {
+				_m.UserAgent = value.String
+			}
+		case loginattempt.FieldClientHints:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field client_hints", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &_m.ClientHints); err != nil {
+					return fmt.Errorf("unmarshal field client_hints: %w", err)
+				}
+			}
+		case loginattempt.FieldMethod:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field method", values[i])
+			} else if value.Valid {
+				_m.Method = loginattempt.Method(value.String)
+			}
+		case loginattempt.FieldOutcome:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field outcome", values[i])
+			} else if value.Valid {
+				_m.Outcome = loginattempt.Outcome(value.String)
+			}
+		case loginattempt.FieldFailureReason:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field failure_reason", values[i])
+			} else if value.Valid {
+				_m.FailureReason = new(loginattempt.FailureReason)
+				*_m.FailureReason = loginattempt.FailureReason(value.String)
+			}
+		case loginattempt.FieldRiskScore:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field risk_score", values[i])
+			} else if value.Valid {
+				_m.RiskScore = int(value.Int64)
+			}
+		case loginattempt.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				_m.CreatedAt = value.Time
+			}
+		default:
+			_m.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the LoginAttempt.
+// This includes values selected through modifiers, order, etc.
+func (_m *LoginAttempt) Value(name string) (ent.Value, error) {
+	return _m.selectValues.Get(name)
+}
+
+// QueryUser queries the "user" edge of the LoginAttempt entity.
+func (_m *LoginAttempt) QueryUser() *UserQuery {
+	return NewLoginAttemptClient(_m.config).QueryUser(_m)
+}
+
+// Update returns a builder for updating this LoginAttempt.
+// Note that you need to call LoginAttempt.Unwrap() before calling this method if this LoginAttempt
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (_m *LoginAttempt) Update() *LoginAttemptUpdateOne {
+	return NewLoginAttemptClient(_m.config).UpdateOne(_m)
+}
+
+// Unwrap unwraps the LoginAttempt entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (_m *LoginAttempt) Unwrap() *LoginAttempt {
+	_tx, ok := _m.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: LoginAttempt is not a transactional entity")
+	}
+	_m.config.driver = _tx.drv
+	return _m
+}
+
+// String implements the fmt.Stringer.
+func (_m *LoginAttempt) String() string {
+	var builder strings.Builder
+	builder.WriteString("LoginAttempt(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", _m.ID))
+	if v := _m.UserID; v != nil {
+		builder.WriteString("user_id=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("email=")
+	builder.WriteString(_m.Email)
+	builder.WriteString(", ")
+	builder.WriteString("ip=")
+	builder.WriteString(_m.IP)
+	builder.WriteString(", ")
+	builder.WriteString("device_id=")
+	builder.WriteString(_m.DeviceID)
+	builder.WriteString(", ")
+	builder.WriteString("platform=")
+	builder.WriteString(_m.Platform)
+	builder.WriteString(", ")
+	builder.WriteString("user_agent=")
+	builder.WriteString(_m.UserAgent)
+	builder.WriteString(", ")
+	builder.WriteString("client_hints=")
+	builder.WriteString(fmt.Sprintf("%v", _m.ClientHints))
+	builder.WriteString(", ")
+	builder.WriteString("method=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Method))
+	builder.WriteString(", ")
+	builder.WriteString("outcome=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Outcome))
+	builder.WriteString(", ")
+	if v := _m.FailureReason; v != nil {
+		builder.WriteString("failure_reason=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("risk_score=")
+	builder.WriteString(fmt.Sprintf("%v", _m.RiskScore))
+	builder.WriteString(", ")
+	builder.WriteString("created_at=")
+	builder.WriteString(_m.CreatedAt.Format(time.ANSIC))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// LoginAttempts is a parsable slice of LoginAttempt.
+type LoginAttempts []*LoginAttempt
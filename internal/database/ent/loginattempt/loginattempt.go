@@ -0,0 +1,259 @@
+// Code generated by ent, DO NOT EDIT.
+
+package loginattempt
+
+import (
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+)
+
+const (
+	// Label holds the string label denoting the loginattempt type in the database.
+	Label = "login_attempt"
+	// FieldID holds the string denoting the id field in the database.
+	FieldID = "id"
+	// FieldUserID holds the string denoting the user_id field in the database.
+	FieldUserID = "user_id"
+	// FieldEmail holds the string denoting the email field in the database.
+	FieldEmail = "email"
+	// FieldIP holds the string denoting the ip field in the database.
+	FieldIP = "ip"
+	// FieldDeviceID holds the string denoting the device_id field in the database.
+	FieldDeviceID = "device_id"
+	// FieldPlatform holds the string denoting the platform field in the database.
+	FieldPlatform = "platform"
+	// FieldUserAgent holds the string denoting the user_agent field in the database.
+	FieldUserAgent = "user_agent"
+	// FieldClientHints holds the string denoting the client_hints field in the database.
+	FieldClientHints = "client_hints"
+	// FieldMethod holds the string denoting the method field in the database.
+	FieldMethod = "method"
+	// FieldOutcome holds the string denoting the outcome field in the database.
+	FieldOutcome = "outcome"
+	// FieldFailureReason holds the string denoting the failure_reason field in the database.
+	FieldFailureReason = "failure_reason"
+	// FieldRiskScore holds the string denoting the risk_score field in the database.
+	FieldRiskScore = "risk_score"
+	// FieldCreatedAt holds the string denoting the created_at field in the database.
+	FieldCreatedAt = "created_at"
+	// EdgeUser holds the string denoting the user edge name in mutations.
+	EdgeUser = "user"
+	// Table holds the table name of the loginattempt in the database.
+	Table = "login_attempts"
+	// UserTable is the table that holds the user relation/edge.
+	UserTable = "login_attempts"
+	// UserInverseTable is the table name for the User entity.
+	// It exists in this package in order to avoid circular dependency with the "user" package.
+	UserInverseTable = "users"
+	// UserColumn is the table column denoting the user relation/edge.
+	UserColumn = "user_id"
+)
+
+// Columns holds all SQL columns for loginattempt fields.
+var Columns = []string{
+	FieldID,
+	FieldUserID,
+	FieldEmail,
+	FieldIP,
+	FieldDeviceID,
+	FieldPlatform,
+	FieldUserAgent,
+	FieldClientHints,
+	FieldMethod,
+	FieldOutcome,
+	FieldFailureReason,
+	FieldRiskScore,
+	FieldCreatedAt,
+}
+
+// ValidColumn reports if the column name is valid (part of the table columns).
+func ValidColumn(column string) bool {
+	for i := range Columns {
+		if column == Columns[i] {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// EmailValidator is a validator for the "email" field. It is called by the builders before save.
+	EmailValidator func(string) error
+	// DefaultIP holds the default value on creation for the "ip" field.
+	DefaultIP string
+	// IPValidator is a validator for the "ip" field. It is called by the builders before save.
+	IPValidator func(string) error
+	// DefaultDeviceID holds the default value on creation for the "device_id" field.
+	DefaultDeviceID string
+	// DeviceIDValidator is a validator for the "device_id" field. It is called by the builders before save.
+	DeviceIDValidator func(string) error
+	// DefaultPlatform holds the default value on creation for the "platform" field.
+	DefaultPlatform string
+	// PlatformValidator is a validator for the "platform" field. It is called by the builders before save.
+	PlatformValidator func(string) error
+	// DefaultUserAgent holds the default value on creation for the "user_agent" field.
+	DefaultUserAgent string
+	// UserAgentValidator is a validator for the "user_agent" field. It is called by the builders before save.
+	UserAgentValidator func(string) error
+	// DefaultRiskScore holds the default value on creation for the "risk_score" field.
+	DefaultRiskScore int
+	// RiskScoreValidator is a validator for the "risk_score" field. It is called by the builders before save.
+	RiskScoreValidator func(int) error
+	// DefaultCreatedAt holds the default value on creation for the "created_at" field.
+	DefaultCreatedAt func() time.Time
+)
+
+// Method defines the type for the "method" enum field.
+type Method string
+
+// MethodPASSWORD is the default value of the Method enum.
+const DefaultMethod = MethodPASSWORD
+
+// Method values.
+const (
+	MethodPASSWORD Method = "PASSWORD"
+	MethodOAUTH    Method = "OAUTH"
+)
+
+func (m Method) String() string {
+	return string(m)
+}
+
+// MethodValidator is a validator for the "method" field enum values. It is called by the builders before save.
+func MethodValidator(m Method) error {
+	switch m {
+	case MethodPASSWORD, MethodOAUTH:
+		return nil
+	default:
+		return fmt.Errorf("loginattempt: invalid enum value for method field: %q", m)
+	}
+}
+
+// Outcome defines the type for the "outcome" enum field.
+type Outcome string
+
+// Outcome values.
+const (
+	OutcomeSUCCESS Outcome = "SUCCESS"
+	OutcomeFAILURE Outcome = "FAILURE"
+)
+
+func (o Outcome) String() string {
+	return string(o)
+}
+
+// OutcomeValidator is a validator for the "outcome" field enum values. It is called by the builders before save.
+func OutcomeValidator(o Outcome) error {
+	switch o {
+	case OutcomeSUCCESS, OutcomeFAILURE:
+		return nil
+	default:
+		return fmt.Errorf("loginattempt: invalid enum value for outcome field: %q", o)
+	}
+}
+
+// FailureReason defines the type for the "failure_reason" enum field.
+type FailureReason string
+
+// FailureReason values.
+const (
+	FailureReasonUNKNOWN_ACCOUNT   FailureReason = "UNKNOWN_ACCOUNT"
+	FailureReasonINVALID_PASSWORD  FailureReason = "INVALID_PASSWORD"
+	FailureReasonOAUTH_FAILED      FailureReason = "OAUTH_FAILED"
+	FailureReasonACCOUNT_SUSPENDED FailureReason = "ACCOUNT_SUSPENDED"
+	FailureReasonINTERNAL_ERROR    FailureReason = "INTERNAL_ERROR"
+)
+
+func (fr FailureReason) String() string {
+	return string(fr)
+}
+
+// FailureReasonValidator is a validator for the "failure_reason" field enum values. It is called by the builders before save.
+func FailureReasonValidator(fr FailureReason) error {
+	switch fr {
+	case FailureReasonUNKNOWN_ACCOUNT, FailureReasonINVALID_PASSWORD, FailureReasonOAUTH_FAILED, FailureReasonACCOUNT_SUSPENDED, FailureReasonINTERNAL_ERROR:
+		return nil
+	default:
+		return fmt.Errorf("loginattempt: invalid enum value for failure_reason field: %q", fr)
+	}
+}
+
+// OrderOption defines the ordering options for the LoginAttempt queries.
+type OrderOption func(*sql.Selector)
+
+// ByID orders the results by the id field.
+func ByID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldID, opts...).ToFunc()
+}
+
+// ByUserID orders the results by the user_id field.
+func ByUserID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldUserID, opts...).ToFunc()
+}
+
+// ByEmail orders the results by the email field.
+func ByEmail(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldEmail, opts...).ToFunc()
+}
+
+// ByIP orders the results by the ip field.
+func ByIP(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldIP, opts...).ToFunc()
+}
+
+// ByDeviceID orders the results by the device_id field.
+func ByDeviceID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldDeviceID, opts...).ToFunc()
+}
+
+// ByPlatform orders the results by the platform field.
+func ByPlatform(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldPlatform, opts...).ToFunc()
+}
+
+// ByUserAgent orders the results by the user_agent field.
+func ByUserAgent(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldUserAgent, opts...).ToFunc()
+}
+
+// ByMethod orders the results by the method field.
+func ByMethod(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldMethod, opts...).ToFunc()
+}
+
+// ByOutcome orders the results by the outcome field.
+func ByOutcome(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldOutcome, opts...).ToFunc()
+}
+
+// ByFailureReason orders the results by the failure_reason field.
+func ByFailureReason(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldFailureReason, opts...).ToFunc()
+}
+
+// ByRiskScore orders the results by the risk_score field.
+func ByRiskScore(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldRiskScore, opts...).ToFunc()
+}
+
+// ByCreatedAt orders the results by the created_at field.
+func ByCreatedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldCreatedAt, opts...).ToFunc()
+}
+
+// ByUserField orders the results by user field.
+func ByUserField(field string, opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newUserStep(), sql.OrderByField(field, opts...))
+	}
+}
+func newUserStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(UserInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.M2O, true, UserTable, UserColumn),
+	)
+}
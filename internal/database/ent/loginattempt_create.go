@@ -0,0 +1,509 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/abisalde/authentication-service/internal/database/ent/loginattempt"
+	"github.com/abisalde/authentication-service/internal/database/ent/user"
+	"github.com/abisalde/authentication-service/pkg/useragent"
+)
+
+// LoginAttemptCreate is the builder for creating a LoginAttempt entity.
+type LoginAttemptCreate struct {
+	config
+	mutation *LoginAttemptMutation
+	hooks    []Hook
+}
+
+// SetUserID sets the "user_id" field.
+func (_c *LoginAttemptCreate) SetUserID(v int64) *LoginAttemptCreate {
+	_c.mutation.SetUserID(v)
+	return _c
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (_c *LoginAttemptCreate) SetNillableUserID(v *int64) *LoginAttemptCreate {
+	if v != nil {
+		_c.SetUserID(*v)
+	}
+	return _c
+}
+
+// SetEmail sets the "email" field.
+func (_c *LoginAttemptCreate) SetEmail(v string) *LoginAttemptCreate {
+	_c.mutation.SetEmail(v)
+	return _c
+}
+
+// SetIP sets the "ip" field.
+func (_c *LoginAttemptCreate) SetIP(v string) *LoginAttemptCreate {
+	_c.mutation.SetIP(v)
+	return _c
+}
+
+// SetNillableIP sets the "ip" field if the given value is not nil.
+func (_c *LoginAttemptCreate) SetNillableIP(v *string) *LoginAttemptCreate {
+	if v != nil {
+		_c.SetIP(*v)
+	}
+	return _c
+}
+
+// SetDeviceID sets the "device_id" field.
+func (_c *LoginAttemptCreate) SetDeviceID(v string) *LoginAttemptCreate {
+	_c.mutation.SetDeviceID(v)
+	return _c
+}
+
+// SetNillableDeviceID sets the "device_id" field if the given value is not nil.
+func (_c *LoginAttemptCreate) SetNillableDeviceID(v *string) *LoginAttemptCreate {
+	if v != nil {
+		_c.SetDeviceID(*v)
+	}
+	return _c
+}
+
+// SetPlatform sets the "platform" field.
+func (_c *LoginAttemptCreate) SetPlatform(v string) *LoginAttemptCreate {
+	_c.mutation.SetPlatform(v)
+	return _c
+}
+
+// SetNillablePlatform sets the "platform" field if the given value is not nil.
+func (_c *LoginAttemptCreate) SetNillablePlatform(v *string) *LoginAttemptCreate {
+	if v != nil {
+		_c.SetPlatform(*v)
+	}
+	return _c
+}
+
+// SetUserAgent sets the "user_agent" field.
+func (_c *LoginAttemptCreate) SetUserAgent(v string) *LoginAttemptCreate {
+	_c.mutation.SetUserAgent(v)
+	return _c
+}
+
+// SetNillableUserAgent sets the "user_agent" field if the given value is not nil.
+func (_c *LoginAttemptCreate) SetNillableUserAgent(v *string) *LoginAttemptCreate {
+	if v != nil {
+		_c.SetUserAgent(*v)
+	}
+	return _c
+}
+
+// SetClientHints sets the "client_hints" field.
+func (_c *LoginAttemptCreate) SetClientHints(v useragent.ClientHints) *LoginAttemptCreate {
+	_c.mutation.SetClientHints(v)
+	return _c
+}
+
+// SetNillableClientHints sets the "client_hints" field if the given value is not nil.
+func (_c *LoginAttemptCreate) SetNillableClientHints(v *useragent.ClientHints) *LoginAttemptCreate {
+	if v != nil {
+		_c.SetClientHints(*v)
+	}
+	return _c
+}
+
+// SetMethod sets the "method" field.
+func (_c *LoginAttemptCreate) SetMethod(v loginattempt.Method) *LoginAttemptCreate {
+	_c.mutation.SetMethod(v)
+	return _c
+}
+
+// SetNillableMethod sets the "method" field if the given value is not nil.
+func (_c *LoginAttemptCreate) SetNillableMethod(v *loginattempt.Method) *LoginAttemptCreate {
+	if v != nil {
+		_c.SetMethod(*v)
+	}
+	return _c
+}
+
+// SetOutcome sets the "outcome" field.
+func (_c *LoginAttemptCreate) SetOutcome(v loginattempt.Outcome) *LoginAttemptCreate {
+	_c.mutation.SetOutcome(v)
+	return _c
+}
+
+// SetFailureReason sets the "failure_reason" field.
+func (_c *LoginAttemptCreate) SetFailureReason(v loginattempt.FailureReason) *LoginAttemptCreate {
+	_c.mutation.SetFailureReason(v)
+	return _c
+}
+
+// SetNillableFailureReason sets the "failure_reason" field if the given value is not nil.
+func (_c *LoginAttemptCreate) SetNillableFailureReason(v *loginattempt.FailureReason) *LoginAttemptCreate {
+	if v != nil {
+		_c.SetFailureReason(*v)
+	}
+	return _c
+}
+
+// SetRiskScore sets the "risk_score" field.
+func (_c *LoginAttemptCreate) SetRiskScore(v int) *LoginAttemptCreate {
+	_c.mutation.SetRiskScore(v)
+	return _c
+}
+
+// SetNillableRiskScore sets the "risk_score" field if the given value is not nil.
+func (_c *LoginAttemptCreate) SetNillableRiskScore(v *int) *LoginAttemptCreate {
+	if v != nil {
+		_c.SetRiskScore(*v)
+	}
+	return _c
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (_c *LoginAttemptCreate) SetCreatedAt(v time.Time) *LoginAttemptCreate {
+	_c.mutation.SetCreatedAt(v)
+	return _c
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (_c *LoginAttemptCreate) SetNillableCreatedAt(v *time.Time) *LoginAttemptCreate {
+	if v != nil {
+		_c.SetCreatedAt(*v)
+	}
+	return _c
+}
+
+// SetID sets the "id" field.
+func (_c *LoginAttemptCreate) SetID(v int64) *LoginAttemptCreate {
+	_c.mutation.SetID(v)
+	return _c
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (_c *LoginAttemptCreate) SetUser(v *User) *LoginAttemptCreate {
+	return _c.SetUserID(v.ID)
+}
+
+// Mutation returns the LoginAttemptMutation object of the builder.
+func (_c *LoginAttemptCreate) Mutation() *LoginAttemptMutation {
+	return _c.mutation
+}
+
+// Save creates the LoginAttempt in the database.
+func (_c *LoginAttemptCreate) Save(ctx context.Context) (*LoginAttempt, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *LoginAttemptCreate) SaveX(ctx context.Context) *LoginAttempt {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *LoginAttemptCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *LoginAttemptCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *LoginAttemptCreate) defaults() {
+	if _, ok := _c.mutation.IP(); !ok {
+		v := loginattempt.DefaultIP
+		_c.mutation.SetIP(v)
+	}
+	if _, ok := _c.mutation.DeviceID(); !ok {
+		v := loginattempt.DefaultDeviceID
+		_c.mutation.SetDeviceID(v)
+	}
+	if _, ok := _c.mutation.Platform(); !ok {
+		v := loginattempt.DefaultPlatform
+		_c.mutation.SetPlatform(v)
+	}
+	if _, ok := _c.mutation.UserAgent(); !ok {
+		v := loginattempt.DefaultUserAgent
+		_c.mutation.SetUserAgent(v)
+	}
+	if _, ok := _c.mutation.Method(); !ok {
+		v := loginattempt.DefaultMethod
+		_c.mutation.SetMethod(v)
+	}
+	if _, ok := _c.mutation.RiskScore(); !ok {
+		v := loginattempt.DefaultRiskScore
+		_c.mutation.SetRiskScore(v)
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		v := loginattempt.DefaultCreatedAt()
+		_c.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *LoginAttemptCreate) check() error {
+	if _, ok := _c.mutation.Email(); !ok {
+		return &ValidationError{Name: "email", err: errors.New(`ent: missing required field "LoginAttempt.email"`)}
+	}
+	if v, ok := _c.mutation.Email(); ok {
+		if err := loginattempt.EmailValidator(v); err != nil {
+			return &ValidationError{Name: "email", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.email": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.IP(); !ok {
+		return &ValidationError{Name: "ip", err: errors.New(`ent: missing required field "LoginAttempt.ip"`)}
+	}
+	if v, ok := _c.mutation.IP(); ok {
+		if err := loginattempt.IPValidator(v); err != nil {
+			return &ValidationError{Name: "ip", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.ip": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.DeviceID(); !ok {
+		return &ValidationError{Name: "device_id", err: errors.New(`ent: missing required field "LoginAttempt.device_id"`)}
+	}
+	if v, ok := _c.mutation.DeviceID(); ok {
+		if err := loginattempt.DeviceIDValidator(v); err != nil {
+			return &ValidationError{Name: "device_id", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.device_id": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Platform(); !ok {
+		return &ValidationError{Name: "platform", err: errors.New(`ent: missing required field "LoginAttempt.platform"`)}
+	}
+	if v, ok := _c.mutation.Platform(); ok {
+		if err := loginattempt.PlatformValidator(v); err != nil {
+			return &ValidationError{Name: "platform", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.platform": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.UserAgent(); !ok {
+		return &ValidationError{Name: "user_agent", err: errors.New(`ent: missing required field "LoginAttempt.user_agent"`)}
+	}
+	if v, ok := _c.mutation.UserAgent(); ok {
+		if err := loginattempt.UserAgentValidator(v); err != nil {
+			return &ValidationError{Name: "user_agent", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.user_agent": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Method(); !ok {
+		return &ValidationError{Name: "method", err: errors.New(`ent: missing required field "LoginAttempt.method"`)}
+	}
+	if v, ok := _c.mutation.Method(); ok {
+		if err := loginattempt.MethodValidator(v); err != nil {
+			return &ValidationError{Name: "method", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.method": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Outcome(); !ok {
+		return &ValidationError{Name: "outcome", err: errors.New(`ent: missing required field "LoginAttempt.outcome"`)}
+	}
+	if v, ok := _c.mutation.Outcome(); ok {
+		if err := loginattempt.OutcomeValidator(v); err != nil {
+			return &ValidationError{Name: "outcome", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.outcome": %w`, err)}
+		}
+	}
+	if v, ok := _c.mutation.FailureReason(); ok {
+		if err := loginattempt.FailureReasonValidator(v); err != nil {
+			return &ValidationError{Name: "failure_reason", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.failure_reason": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.RiskScore(); !ok {
+		return &ValidationError{Name: "risk_score", err: errors.New(`ent: missing required field "LoginAttempt.risk_score"`)}
+	}
+	if v, ok := _c.mutation.RiskScore(); ok {
+		if err := loginattempt.RiskScoreValidator(v); err != nil {
+			return &ValidationError{Name: "risk_score", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.risk_score": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "LoginAttempt.created_at"`)}
+	}
+	return nil
+}
+
+func (_c *LoginAttemptCreate) sqlSave(ctx context.Context) (*LoginAttempt, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != _node.ID {
+		id := _spec.ID.Value.(int64)
+		_node.ID = int64(id)
+	}
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *LoginAttemptCreate) createSpec() (*LoginAttempt, *sqlgraph.CreateSpec) {
+	var (
+		_node = &LoginAttempt{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(loginattempt.Table, sqlgraph.NewFieldSpec(loginattempt.FieldID, field.TypeInt64))
+	)
+	if id, ok := _c.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = id
+	}
+	if value, ok := _c.mutation.Email(); ok {
+		_spec.SetField(loginattempt.FieldEmail, field.TypeString, value)
+		_node.Email = value
+	}
+	if value, ok := _c.mutation.IP(); ok {
+		_spec.SetField(loginattempt.FieldIP, field.TypeString, value)
+		_node.IP = value
+	}
+	if value, ok := _c.mutation.DeviceID(); ok {
+		_spec.SetField(loginattempt.FieldDeviceID, field.TypeString, value)
+		_node.DeviceID = value
+	}
+	if value, ok := _c.mutation.Platform(); ok {
+		_spec.SetField(loginattempt.FieldPlatform, field.TypeString, value)
+		_node.Platform = value
+	}
+	if value, ok := _c.mutation.UserAgent(); ok {
+		_spec.SetField(loginattempt.FieldUserAgent, field.TypeString, value)
+		_node.UserAgent = value
+	}
+	if value, ok := _c.mutation.ClientHints(); ok {
+		_spec.SetField(loginattempt.FieldClientHints, field.TypeJSON, value)
+		_node.ClientHints = value
+	}
+	if value, ok := _c.mutation.Method(); ok {
+		_spec.SetField(loginattempt.FieldMethod, field.TypeEnum, value)
+		_node.Method = value
+	}
+	if value, ok := _c.mutation.Outcome(); ok {
+		_spec.SetField(loginattempt.FieldOutcome, field.TypeEnum, value)
+		_node.Outcome = value
+	}
+	if value, ok := _c.mutation.FailureReason(); ok {
+		_spec.SetField(loginattempt.FieldFailureReason, field.TypeEnum, value)
+		_node.FailureReason = &value
+	}
+	if value, ok := _c.mutation.RiskScore(); ok {
+		_spec.SetField(loginattempt.FieldRiskScore, field.TypeInt, value)
+		_node.RiskScore = value
+	}
+	if value, ok := _c.mutation.CreatedAt(); ok {
+		_spec.SetField(loginattempt.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if nodes := _c.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   loginattempt.UserTable,
+			Columns: []string{loginattempt.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.UserID = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// LoginAttemptCreateBulk is the builder for creating many LoginAttempt entities in bulk.
+type LoginAttemptCreateBulk struct {
+	config
+	err      error
+	builders []*LoginAttemptCreate
+}
+
+// Save creates the LoginAttempt entities in the database.
+func (_c *LoginAttemptCreateBulk) Save(ctx context.Context) ([]*LoginAttempt, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*LoginAttempt, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*LoginAttemptMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil && nodes[i].ID == 0 {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int64(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *LoginAttemptCreateBulk) SaveX(ctx context.Context) []*LoginAttempt {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *LoginAttemptCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *LoginAttemptCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
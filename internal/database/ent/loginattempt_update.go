@@ -0,0 +1,799 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/abisalde/authentication-service/internal/database/ent/loginattempt"
+	"github.com/abisalde/authentication-service/internal/database/ent/predicate"
+	"github.com/abisalde/authentication-service/internal/database/ent/user"
+	"github.com/abisalde/authentication-service/pkg/useragent"
+)
+
+// LoginAttemptUpdate is the builder for updating LoginAttempt entities.
+type LoginAttemptUpdate struct {
+	config
+	hooks    []Hook
+	mutation *LoginAttemptMutation
+}
+
+// Where appends a list predicates to the LoginAttemptUpdate builder.
+func (_u *LoginAttemptUpdate) Where(ps ...predicate.LoginAttempt) *LoginAttemptUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetUserID sets the "user_id" field.
+func (_u *LoginAttemptUpdate) SetUserID(v int64) *LoginAttemptUpdate {
+	_u.mutation.SetUserID(v)
+	return _u
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (_u *LoginAttemptUpdate) SetNillableUserID(v *int64) *LoginAttemptUpdate {
+	if v != nil {
+		_u.SetUserID(*v)
+	}
+	return _u
+}
+
+// ClearUserID clears the value of the "user_id" field.
+func (_u *LoginAttemptUpdate) ClearUserID() *LoginAttemptUpdate {
+	_u.mutation.ClearUserID()
+	return _u
+}
+
+// SetEmail sets the "email" field.
+func (_u *LoginAttemptUpdate) SetEmail(v string) *LoginAttemptUpdate {
+	_u.mutation.SetEmail(v)
+	return _u
+}
+
+// SetNillableEmail sets the "email" field if the given value is not nil.
+func (_u *LoginAttemptUpdate) SetNillableEmail(v *string) *LoginAttemptUpdate {
+	if v != nil {
+		_u.SetEmail(*v)
+	}
+	return _u
+}
+
+// SetIP sets the "ip" field.
+func (_u *LoginAttemptUpdate) SetIP(v string) *LoginAttemptUpdate {
+	_u.mutation.SetIP(v)
+	return _u
+}
+
+// SetNillableIP sets the "ip" field if the given value is not nil.
+func (_u *LoginAttemptUpdate) SetNillableIP(v *string) *LoginAttemptUpdate {
+	if v != nil {
+		_u.SetIP(*v)
+	}
+	return _u
+}
+
+// SetDeviceID sets the "device_id" field.
+func (_u *LoginAttemptUpdate) SetDeviceID(v string) *LoginAttemptUpdate {
+	_u.mutation.SetDeviceID(v)
+	return _u
+}
+
+// SetNillableDeviceID sets the "device_id" field if the given value is not nil.
+func (_u *LoginAttemptUpdate) SetNillableDeviceID(v *string) *LoginAttemptUpdate {
+	if v != nil {
+		_u.SetDeviceID(*v)
+	}
+	return _u
+}
+
+// SetPlatform sets the "platform" field.
+func (_u *LoginAttemptUpdate) SetPlatform(v string) *LoginAttemptUpdate {
+	_u.mutation.SetPlatform(v)
+	return _u
+}
+
+// SetNillablePlatform sets the "platform" field if the given value is not nil.
+func (_u *LoginAttemptUpdate) SetNillablePlatform(v *string) *LoginAttemptUpdate {
+	if v != nil {
+		_u.SetPlatform(*v)
+	}
+	return _u
+}
+
+// SetUserAgent sets the "user_agent" field.
+func (_u *LoginAttemptUpdate) SetUserAgent(v string) *LoginAttemptUpdate {
+	_u.mutation.SetUserAgent(v)
+	return _u
+}
+
+// SetNillableUserAgent sets the "user_agent" field if the given value is not nil.
+func (_u *LoginAttemptUpdate) SetNillableUserAgent(v *string) *LoginAttemptUpdate {
+	if v != nil {
+		_u.SetUserAgent(*v)
+	}
+	return _u
+}
+
+// SetClientHints sets the "client_hints" field.
+func (_u *LoginAttemptUpdate) SetClientHints(v useragent.ClientHints) *LoginAttemptUpdate {
+	_u.mutation.SetClientHints(v)
+	return _u
+}
+
+// SetNillableClientHints sets the "client_hints" field if the given value is not nil.
+func (_u *LoginAttemptUpdate) SetNillableClientHints(v *useragent.ClientHints) *LoginAttemptUpdate {
+	if v != nil {
+		_u.SetClientHints(*v)
+	}
+	return _u
+}
+
+// ClearClientHints clears the value of the "client_hints" field.
+func (_u *LoginAttemptUpdate) ClearClientHints() *LoginAttemptUpdate {
+	_u.mutation.ClearClientHints()
+	return _u
+}
+
+// SetMethod sets the "method" field.
+func (_u *LoginAttemptUpdate) SetMethod(v loginattempt.Method) *LoginAttemptUpdate {
+	_u.mutation.SetMethod(v)
+	return _u
+}
+
+// SetNillableMethod sets the "method" field if the given value is not nil.
+func (_u *LoginAttemptUpdate) SetNillableMethod(v *loginattempt.Method) *LoginAttemptUpdate {
+	if v != nil {
+		_u.SetMethod(*v)
+	}
+	return _u
+}
+
+// SetOutcome sets the "outcome" field.
+func (_u *LoginAttemptUpdate) SetOutcome(v loginattempt.Outcome) *LoginAttemptUpdate {
+	_u.mutation.SetOutcome(v)
+	return _u
+}
+
+// SetNillableOutcome sets the "outcome" field if the given value is not nil.
+func (_u *LoginAttemptUpdate) SetNillableOutcome(v *loginattempt.Outcome) *LoginAttemptUpdate {
+	if v != nil {
+		_u.SetOutcome(*v)
+	}
+	return _u
+}
+
+// SetFailureReason sets the "failure_reason" field.
+func (_u *LoginAttemptUpdate) SetFailureReason(v loginattempt.FailureReason) *LoginAttemptUpdate {
+	_u.mutation.SetFailureReason(v)
+	return _u
+}
+
+// SetNillableFailureReason sets the "failure_reason" field if the given value is not nil.
+func (_u *LoginAttemptUpdate) SetNillableFailureReason(v *loginattempt.FailureReason) *LoginAttemptUpdate {
+	if v != nil {
+		_u.SetFailureReason(*v)
+	}
+	return _u
+}
+
+// ClearFailureReason clears the value of the "failure_reason" field.
+func (_u *LoginAttemptUpdate) ClearFailureReason() *LoginAttemptUpdate {
+	_u.mutation.ClearFailureReason()
+	return _u
+}
+
+// SetRiskScore sets the "risk_score" field.
+func (_u *LoginAttemptUpdate) SetRiskScore(v int) *LoginAttemptUpdate {
+	_u.mutation.ResetRiskScore()
+	_u.mutation.SetRiskScore(v)
+	return _u
+}
+
+// SetNillableRiskScore sets the "risk_score" field if the given value is not nil.
+func (_u *LoginAttemptUpdate) SetNillableRiskScore(v *int) *LoginAttemptUpdate {
+	if v != nil {
+		_u.SetRiskScore(*v)
+	}
+	return _u
+}
+
+// AddRiskScore adds value to the "risk_score" field.
+func (_u *LoginAttemptUpdate) AddRiskScore(v int) *LoginAttemptUpdate {
+	_u.mutation.AddRiskScore(v)
+	return _u
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (_u *LoginAttemptUpdate) SetUser(v *User) *LoginAttemptUpdate {
+	return _u.SetUserID(v.ID)
+}
+
+// Mutation returns the LoginAttemptMutation object of the builder.
+func (_u *LoginAttemptUpdate) Mutation() *LoginAttemptMutation {
+	return _u.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (_u *LoginAttemptUpdate) ClearUser() *LoginAttemptUpdate {
+	_u.mutation.ClearUser()
+	return _u
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *LoginAttemptUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *LoginAttemptUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *LoginAttemptUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *LoginAttemptUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *LoginAttemptUpdate) check() error {
+	if v, ok := _u.mutation.Email(); ok {
+		if err := loginattempt.EmailValidator(v); err != nil {
+			return &ValidationError{Name: "email", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.email": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.IP(); ok {
+		if err := loginattempt.IPValidator(v); err != nil {
+			return &ValidationError{Name: "ip", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.ip": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.DeviceID(); ok {
+		if err := loginattempt.DeviceIDValidator(v); err != nil {
+			return &ValidationError{Name: "device_id", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.device_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Platform(); ok {
+		if err := loginattempt.PlatformValidator(v); err != nil {
+			return &ValidationError{Name: "platform", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.platform": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.UserAgent(); ok {
+		if err := loginattempt.UserAgentValidator(v); err != nil {
+			return &ValidationError{Name: "user_agent", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.user_agent": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Method(); ok {
+		if err := loginattempt.MethodValidator(v); err != nil {
+			return &ValidationError{Name: "method", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.method": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Outcome(); ok {
+		if err := loginattempt.OutcomeValidator(v); err != nil {
+			return &ValidationError{Name: "outcome", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.outcome": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.FailureReason(); ok {
+		if err := loginattempt.FailureReasonValidator(v); err != nil {
+			return &ValidationError{Name: "failure_reason", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.failure_reason": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.RiskScore(); ok {
+		if err := loginattempt.RiskScoreValidator(v); err != nil {
+			return &ValidationError{Name: "risk_score", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.risk_score": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *LoginAttemptUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(loginattempt.Table, loginattempt.Columns, sqlgraph.NewFieldSpec(loginattempt.FieldID, field.TypeInt64))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.Email(); ok {
+		_spec.SetField(loginattempt.FieldEmail, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.IP(); ok {
+		_spec.SetField(loginattempt.FieldIP, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.DeviceID(); ok {
+		_spec.SetField(loginattempt.FieldDeviceID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Platform(); ok {
+		_spec.SetField(loginattempt.FieldPlatform, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.UserAgent(); ok {
+		_spec.SetField(loginattempt.FieldUserAgent, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.ClientHints(); ok {
+		_spec.SetField(loginattempt.FieldClientHints, field.TypeJSON, value)
+	}
+	if _u.mutation.ClientHintsCleared() {
+		_spec.ClearField(loginattempt.FieldClientHints, field.TypeJSON)
+	}
+	if value, ok := _u.mutation.Method(); ok {
+		_spec.SetField(loginattempt.FieldMethod, field.TypeEnum, value)
+	}
+	if value, ok := _u.mutation.Outcome(); ok {
+		_spec.SetField(loginattempt.FieldOutcome, field.TypeEnum, value)
+	}
+	if value, ok := _u.mutation.FailureReason(); ok {
+		_spec.SetField(loginattempt.FieldFailureReason, field.TypeEnum, value)
+	}
+	if _u.mutation.FailureReasonCleared() {
+		_spec.ClearField(loginattempt.FieldFailureReason, field.TypeEnum)
+	}
+	if value, ok := _u.mutation.RiskScore(); ok {
+		_spec.SetField(loginattempt.FieldRiskScore, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedRiskScore(); ok {
+		_spec.AddField(loginattempt.FieldRiskScore, field.TypeInt, value)
+	}
+	if _u.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   loginattempt.UserTable,
+			Columns: []string{loginattempt.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt64),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   loginattempt.UserTable,
+			Columns: []string{loginattempt.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{loginattempt.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// LoginAttemptUpdateOne is the builder for updating a single LoginAttempt entity.
+type LoginAttemptUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *LoginAttemptMutation
+}
+
+// SetUserID sets the "user_id" field.
+func (_u *LoginAttemptUpdateOne) SetUserID(v int64) *LoginAttemptUpdateOne {
+	_u.mutation.SetUserID(v)
+	return _u
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (_u *LoginAttemptUpdateOne) SetNillableUserID(v *int64) *LoginAttemptUpdateOne {
+	if v != nil {
+		_u.SetUserID(*v)
+	}
+	return _u
+}
+
+// ClearUserID clears the value of the "user_id" field.
+func (_u *LoginAttemptUpdateOne) ClearUserID() *LoginAttemptUpdateOne {
+	_u.mutation.ClearUserID()
+	return _u
+}
+
+// SetEmail sets the "email" field.
+func (_u *LoginAttemptUpdateOne) SetEmail(v string) *LoginAttemptUpdateOne {
+	_u.mutation.SetEmail(v)
+	return _u
+}
+
+// SetNillableEmail sets the "email" field if the given value is not nil.
+func (_u *LoginAttemptUpdateOne) SetNillableEmail(v *string) *LoginAttemptUpdateOne {
+	if v != nil {
+		_u.SetEmail(*v)
+	}
+	return _u
+}
+
+// SetIP sets the "ip" field.
+func (_u *LoginAttemptUpdateOne) SetIP(v string) *LoginAttemptUpdateOne {
+	_u.mutation.SetIP(v)
+	return _u
+}
+
+// SetNillableIP sets the "ip" field if the given value is not nil.
+func (_u *LoginAttemptUpdateOne) SetNillableIP(v *string) *LoginAttemptUpdateOne {
+	if v != nil {
+		_u.SetIP(*v)
+	}
+	return _u
+}
+
+// SetDeviceID sets the "device_id" field.
+func (_u *LoginAttemptUpdateOne) SetDeviceID(v string) *LoginAttemptUpdateOne {
+	_u.mutation.SetDeviceID(v)
+	return _u
+}
+
+// SetNillableDeviceID sets the "device_id" field if the given value is not nil.
+func (_u *LoginAttemptUpdateOne) SetNillableDeviceID(v *string) *LoginAttemptUpdateOne {
+	if v != nil {
+		_u.SetDeviceID(*v)
+	}
+	return _u
+}
+
+// SetPlatform sets the "platform" field.
+func (_u *LoginAttemptUpdateOne) SetPlatform(v string) *LoginAttemptUpdateOne {
+	_u.mutation.SetPlatform(v)
+	return _u
+}
+
+// SetNillablePlatform sets the "platform" field if the given value is not nil.
+func (_u *LoginAttemptUpdateOne) SetNillablePlatform(v *string) *LoginAttemptUpdateOne {
+	if v != nil {
+		_u.SetPlatform(*v)
+	}
+	return _u
+}
+
+// SetUserAgent sets the "user_agent" field.
+func (_u *LoginAttemptUpdateOne) SetUserAgent(v string) *LoginAttemptUpdateOne {
+	_u.mutation.SetUserAgent(v)
+	return _u
+}
+
+// SetNillableUserAgent sets the "user_agent" field if the given value is not nil.
+func (_u *LoginAttemptUpdateOne) SetNillableUserAgent(v *string) *LoginAttemptUpdateOne {
+	if v != nil {
+		_u.SetUserAgent(*v)
+	}
+	return _u
+}
+
+// SetClientHints sets the "client_hints" field.
+func (_u *LoginAttemptUpdateOne) SetClientHints(v useragent.ClientHints) *LoginAttemptUpdateOne {
+	_u.mutation.SetClientHints(v)
+	return _u
+}
+
+// SetNillableClientHints sets the "client_hints" field if the given value is not nil.
+func (_u *LoginAttemptUpdateOne) SetNillableClientHints(v *useragent.ClientHints) *LoginAttemptUpdateOne {
+	if v != nil {
+		_u.SetClientHints(*v)
+	}
+	return _u
+}
+
+// ClearClientHints clears the value of the "client_hints" field.
+func (_u *LoginAttemptUpdateOne) ClearClientHints() *LoginAttemptUpdateOne {
+	_u.mutation.ClearClientHints()
+	return _u
+}
+
+// SetMethod sets the "method" field.
+func (_u *LoginAttemptUpdateOne) SetMethod(v loginattempt.Method) *LoginAttemptUpdateOne {
+	_u.mutation.SetMethod(v)
+	return _u
+}
+
+// SetNillableMethod sets the "method" field if the given value is not nil.
+func (_u *LoginAttemptUpdateOne) SetNillableMethod(v *loginattempt.Method) *LoginAttemptUpdateOne {
+	if v != nil {
+		_u.SetMethod(*v)
+	}
+	return _u
+}
+
+// SetOutcome sets the "outcome" field.
+func (_u *LoginAttemptUpdateOne) SetOutcome(v loginattempt.Outcome) *LoginAttemptUpdateOne {
+	_u.mutation.SetOutcome(v)
+	return _u
+}
+
+// SetNillableOutcome sets the "outcome" field if the given value is not nil.
+func (_u *LoginAttemptUpdateOne) SetNillableOutcome(v *loginattempt.Outcome) *LoginAttemptUpdateOne {
+	if v != nil {
+		_u.SetOutcome(*v)
+	}
+	return _u
+}
+
+// SetFailureReason sets the "failure_reason" field.
+func (_u *LoginAttemptUpdateOne) SetFailureReason(v loginattempt.FailureReason) *LoginAttemptUpdateOne {
+	_u.mutation.SetFailureReason(v)
+	return _u
+}
+
+// SetNillableFailureReason sets the "failure_reason" field if the given value is not nil.
+func (_u *LoginAttemptUpdateOne) SetNillableFailureReason(v *loginattempt.FailureReason) *LoginAttemptUpdateOne {
+	if v != nil {
+		_u.SetFailureReason(*v)
+	}
+	return _u
+}
+
+// ClearFailureReason clears the value of the "failure_reason" field.
+func (_u *LoginAttemptUpdateOne) ClearFailureReason() *LoginAttemptUpdateOne {
+	_u.mutation.ClearFailureReason()
+	return _u
+}
+
+// SetRiskScore sets the "risk_score" field.
+func (_u *LoginAttemptUpdateOne) SetRiskScore(v int) *LoginAttemptUpdateOne {
+	_u.mutation.ResetRiskScore()
+	_u.mutation.SetRiskScore(v)
+	return _u
+}
+
+// SetNillableRiskScore sets the "risk_score" field if the given value is not nil.
+func (_u *LoginAttemptUpdateOne) SetNillableRiskScore(v *int) *LoginAttemptUpdateOne {
+	if v != nil {
+		_u.SetRiskScore(*v)
+	}
+	return _u
+}
+
+// AddRiskScore adds value to the "risk_score" field.
+func (_u *LoginAttemptUpdateOne) AddRiskScore(v int) *LoginAttemptUpdateOne {
+	_u.mutation.AddRiskScore(v)
+	return _u
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (_u *LoginAttemptUpdateOne) SetUser(v *User) *LoginAttemptUpdateOne {
+	return _u.SetUserID(v.ID)
+}
+
+// Mutation returns the LoginAttemptMutation object of the builder.
+func (_u *LoginAttemptUpdateOne) Mutation() *LoginAttemptMutation {
+	return _u.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (_u *LoginAttemptUpdateOne) ClearUser() *LoginAttemptUpdateOne {
+	_u.mutation.ClearUser()
+	return _u
+}
+
+// Where appends a list predicates to the LoginAttemptUpdate builder.
+func (_u *LoginAttemptUpdateOne) Where(ps ...predicate.LoginAttempt) *LoginAttemptUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *LoginAttemptUpdateOne) Select(field string, fields ...string) *LoginAttemptUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated LoginAttempt entity.
+func (_u *LoginAttemptUpdateOne) Save(ctx context.Context) (*LoginAttempt, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *LoginAttemptUpdateOne) SaveX(ctx context.Context) *LoginAttempt {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *LoginAttemptUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *LoginAttemptUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *LoginAttemptUpdateOne) check() error {
+	if v, ok := _u.mutation.Email(); ok {
+		if err := loginattempt.EmailValidator(v); err != nil {
+			return &ValidationError{Name: "email", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.email": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.IP(); ok {
+		if err := loginattempt.IPValidator(v); err != nil {
+			return &ValidationError{Name: "ip", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.ip": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.DeviceID(); ok {
+		if err := loginattempt.DeviceIDValidator(v); err != nil {
+			return &ValidationError{Name: "device_id", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.device_id": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Platform(); ok {
+		if err := loginattempt.PlatformValidator(v); err != nil {
+			return &ValidationError{Name: "platform", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.platform": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.UserAgent(); ok {
+		if err := loginattempt.UserAgentValidator(v); err != nil {
+			return &ValidationError{Name: "user_agent", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.user_agent": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Method(); ok {
+		if err := loginattempt.MethodValidator(v); err != nil {
+			return &ValidationError{Name: "method", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.method": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Outcome(); ok {
+		if err := loginattempt.OutcomeValidator(v); err != nil {
+			return &ValidationError{Name: "outcome", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.outcome": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.FailureReason(); ok {
+		if err := loginattempt.FailureReasonValidator(v); err != nil {
+			return &ValidationError{Name: "failure_reason", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.failure_reason": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.RiskScore(); ok {
+		if err := loginattempt.RiskScoreValidator(v); err != nil {
+			return &ValidationError{Name: "risk_score", err: fmt.Errorf(`ent: validator failed for field "LoginAttempt.risk_score": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *LoginAttemptUpdateOne) sqlSave(ctx context.Context) (_node *LoginAttempt, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(loginattempt.Table, loginattempt.Columns, sqlgraph.NewFieldSpec(loginattempt.FieldID, field.TypeInt64))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "LoginAttempt.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, loginattempt.FieldID)
+		for _, f := range fields {
+			if !loginattempt.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != loginattempt.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.Email(); ok {
+		_spec.SetField(loginattempt.FieldEmail, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.IP(); ok {
+		_spec.SetField(loginattempt.FieldIP, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.DeviceID(); ok {
+		_spec.SetField(loginattempt.FieldDeviceID, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Platform(); ok {
+		_spec.SetField(loginattempt.FieldPlatform, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.UserAgent(); ok {
+		_spec.SetField(loginattempt.FieldUserAgent, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.ClientHints(); ok {
+		_spec.SetField(loginattempt.FieldClientHints, field.TypeJSON, value)
+	}
+	if _u.mutation.ClientHintsCleared() {
+		_spec.ClearField(loginattempt.FieldClientHints, field.TypeJSON)
+	}
+	if value, ok := _u.mutation.Method(); ok {
+		_spec.SetField(loginattempt.FieldMethod, field.TypeEnum, value)
+	}
+	if value, ok := _u.mutation.Outcome(); ok {
+		_spec.SetField(loginattempt.FieldOutcome, field.TypeEnum, value)
+	}
+	if value, ok := _u.mutation.FailureReason(); ok {
+		_spec.SetField(loginattempt.FieldFailureReason, field.TypeEnum, value)
+	}
+	if _u.mutation.FailureReasonCleared() {
+		_spec.ClearField(loginattempt.FieldFailureReason, field.TypeEnum)
+	}
+	if value, ok := _u.mutation.RiskScore(); ok {
+		_spec.SetField(loginattempt.FieldRiskScore, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedRiskScore(); ok {
+		_spec.AddField(loginattempt.FieldRiskScore, field.TypeInt, value)
+	}
+	if _u.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   loginattempt.UserTable,
+			Columns: []string{loginattempt.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt64),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   loginattempt.UserTable,
+			Columns: []string{loginattempt.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &LoginAttempt{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{loginattempt.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
@@ -8,6 +8,140 @@ import (
 )
 
 var (
+	// LoginAttemptsColumns holds the columns for the "login_attempts" table.
+	LoginAttemptsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt64, Increment: true},
+		{Name: "email", Type: field.TypeString, Size: 255},
+		{Name: "ip", Type: field.TypeString, Size: 45, Default: ""},
+		{Name: "device_id", Type: field.TypeString, Size: 128, Default: ""},
+		{Name: "platform", Type: field.TypeString, Size: 32, Default: ""},
+		{Name: "user_agent", Type: field.TypeString, Size: 512, Default: ""},
+		{Name: "client_hints", Type: field.TypeJSON, Nullable: true},
+		{Name: "method", Type: field.TypeEnum, Enums: []string{"PASSWORD", "OAUTH"}, Default: "PASSWORD"},
+		{Name: "outcome", Type: field.TypeEnum, Enums: []string{"SUCCESS", "FAILURE"}},
+		{Name: "failure_reason", Type: field.TypeEnum, Nullable: true, Enums: []string{"UNKNOWN_ACCOUNT", "INVALID_PASSWORD", "OAUTH_FAILED", "ACCOUNT_SUSPENDED", "INTERNAL_ERROR"}},
+		{Name: "risk_score", Type: field.TypeInt, Default: 0},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "user_id", Type: field.TypeInt64, Nullable: true},
+	}
+	// LoginAttemptsTable holds the schema information for the "login_attempts" table.
+	LoginAttemptsTable = &schema.Table{
+		Name:       "login_attempts",
+		Columns:    LoginAttemptsColumns,
+		PrimaryKey: []*schema.Column{LoginAttemptsColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "login_attempts_users_login_attempts",
+				Columns:    []*schema.Column{LoginAttemptsColumns[12]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.SetNull,
+			},
+		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "loginattempt_user_id_created_at",
+				Unique:  false,
+				Columns: []*schema.Column{LoginAttemptsColumns[12], LoginAttemptsColumns[11]},
+			},
+			{
+				Name:    "loginattempt_ip_created_at",
+				Unique:  false,
+				Columns: []*schema.Column{LoginAttemptsColumns[2], LoginAttemptsColumns[11]},
+			},
+			{
+				Name:    "loginattempt_email_created_at",
+				Unique:  false,
+				Columns: []*schema.Column{LoginAttemptsColumns[1], LoginAttemptsColumns[11]},
+			},
+			{
+				Name:    "loginattempt_created_at",
+				Unique:  false,
+				Columns: []*schema.Column{LoginAttemptsColumns[11]},
+			},
+		},
+	}
+	// OrganizationsColumns holds the columns for the "organizations" table.
+	OrganizationsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt64, Increment: true},
+		{Name: "slug", Type: field.TypeString, Unique: true, Size: 64},
+		{Name: "name", Type: field.TypeString, Size: 100},
+		{Name: "product_name", Type: field.TypeString, Size: 100, Default: ""},
+		{Name: "logo_url", Type: field.TypeString, Size: 512, Default: ""},
+		{Name: "primary_color", Type: field.TypeString, Size: 7, Default: ""},
+		{Name: "support_email", Type: field.TypeString, Size: 255, Default: ""},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "updated_at", Type: field.TypeTime},
+	}
+	// OrganizationsTable holds the schema information for the "organizations" table.
+	OrganizationsTable = &schema.Table{
+		Name:       "organizations",
+		Columns:    OrganizationsColumns,
+		PrimaryKey: []*schema.Column{OrganizationsColumns[0]},
+	}
+	// ScheduledActionsColumns holds the columns for the "scheduled_actions" table.
+	ScheduledActionsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt64, Increment: true},
+		{Name: "action", Type: field.TypeEnum, Enums: []string{"DEACTIVATE", "REVOKE_SESSIONS", "DELETE"}},
+		{Name: "run_at", Type: field.TypeTime},
+		{Name: "status", Type: field.TypeEnum, Enums: []string{"PENDING", "RUNNING", "COMPLETED", "FAILED", "CANCELLED"}, Default: "PENDING"},
+		{Name: "note", Type: field.TypeString, Nullable: true, Size: 500},
+		{Name: "created_by", Type: field.TypeUUID, Nullable: true},
+		{Name: "cancelled_by", Type: field.TypeUUID, Nullable: true},
+		{Name: "error", Type: field.TypeString, Nullable: true, Size: 500},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "finished_at", Type: field.TypeTime, Nullable: true},
+		{Name: "user_id", Type: field.TypeInt64},
+	}
+	// ScheduledActionsTable holds the schema information for the "scheduled_actions" table.
+	ScheduledActionsTable = &schema.Table{
+		Name:       "scheduled_actions",
+		Columns:    ScheduledActionsColumns,
+		PrimaryKey: []*schema.Column{ScheduledActionsColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "scheduled_actions_users_scheduled_actions",
+				Columns:    []*schema.Column{ScheduledActionsColumns[10]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.NoAction,
+			},
+		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "scheduledaction_status_run_at",
+				Unique:  false,
+				Columns: []*schema.Column{ScheduledActionsColumns[3], ScheduledActionsColumns[2]},
+			},
+			{
+				Name:    "scheduledaction_user_id_run_at",
+				Unique:  false,
+				Columns: []*schema.Column{ScheduledActionsColumns[10], ScheduledActionsColumns[2]},
+			},
+		},
+	}
+	// ServiceAccountKeysColumns holds the columns for the "service_account_keys" table.
+	ServiceAccountKeysColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt64, Increment: true},
+		{Name: "key_id", Type: field.TypeString, Unique: true, Size: 32},
+		{Name: "secret_hash", Type: field.TypeString, Size: 64},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "expires_at", Type: field.TypeTime, Nullable: true},
+		{Name: "last_used_at", Type: field.TypeTime, Nullable: true},
+		{Name: "user_id", Type: field.TypeInt64},
+	}
+	// ServiceAccountKeysTable holds the schema information for the "service_account_keys" table.
+	ServiceAccountKeysTable = &schema.Table{
+		Name:       "service_account_keys",
+		Columns:    ServiceAccountKeysColumns,
+		PrimaryKey: []*schema.Column{ServiceAccountKeysColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "service_account_keys_users_service_account_keys",
+				Columns:    []*schema.Column{ServiceAccountKeysColumns[6]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.NoAction,
+			},
+		},
+	}
 	// UsersColumns holds the columns for the "users" table.
 	UsersColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeInt64, Increment: true},
@@ -19,11 +153,13 @@ var (
 		{Name: "zip_code", Type: field.TypeString, Size: 20, Default: ""},
 		{Name: "country", Type: field.TypeString, Size: 160, Default: ""},
 		{Name: "state", Type: field.TypeString, Size: 100, Default: ""},
+		{Name: "public_id", Type: field.TypeUUID, Unique: true},
 		{Name: "email", Type: field.TypeString, Unique: true},
 		{Name: "username", Type: field.TypeString, Unique: true, Nullable: true, Size: 30},
 		{Name: "password_hash", Type: field.TypeString, Nullable: true},
+		{Name: "password_changed_at", Type: field.TypeTime, Nullable: true},
 		{Name: "oauth_id", Type: field.TypeString, Nullable: true, Size: 255},
-		{Name: "provider", Type: field.TypeEnum, Enums: []string{"GOOGLE", "FACEBOOK", "EMAIL"}, Default: "EMAIL"},
+		{Name: "provider", Type: field.TypeEnum, Enums: []string{"GOOGLE", "FACEBOOK", "EMAIL", "APPLE", "GITHUB", "MICROSOFT", "OAUTH"}, Default: "EMAIL"},
 		{Name: "first_name", Type: field.TypeString, Size: 50, Default: ""},
 		{Name: "last_name", Type: field.TypeString, Size: 50, Default: ""},
 		{Name: "phone_number", Type: field.TypeString, Nullable: true},
@@ -31,7 +167,15 @@ var (
 		{Name: "is_email_verified", Type: field.TypeBool, Default: false},
 		{Name: "marketing_opt_in", Type: field.TypeBool, Default: false},
 		{Name: "terms_accepted_at", Type: field.TypeTime, Nullable: true},
+		{Name: "terms_version", Type: field.TypeString, Nullable: true, Size: 32},
 		{Name: "last_login_at", Type: field.TypeTime, Nullable: true},
+		{Name: "onboarding_step", Type: field.TypeEnum, Enums: []string{"VERIFY_EMAIL", "COMPLETE_PROFILE", "ACCEPT_TERMS", "DONE"}, Default: "VERIFY_EMAIL"},
+		{Name: "suspended_at", Type: field.TypeTime, Nullable: true},
+		{Name: "suspension_reason", Type: field.TypeEnum, Nullable: true, Enums: []string{"TERMS_VIOLATION", "FRAUD", "ABUSE", "SPAM", "SECURITY", "OTHER"}},
+		{Name: "residency", Type: field.TypeString, Nullable: true, Size: 16},
+		{Name: "deactivated_at", Type: field.TypeTime, Nullable: true},
+		{Name: "kind", Type: field.TypeEnum, Enums: []string{"HUMAN", "SERVICE"}, Default: "HUMAN"},
+		{Name: "organization_id", Type: field.TypeInt64, Nullable: true},
 		{Name: "user_address", Type: field.TypeInt, Nullable: true},
 	}
 	// UsersTable holds the schema information for the "users" table.
@@ -40,9 +184,15 @@ var (
 		Columns:    UsersColumns,
 		PrimaryKey: []*schema.Column{UsersColumns[0]},
 		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "users_organizations_users",
+				Columns:    []*schema.Column{UsersColumns[31]},
+				RefColumns: []*schema.Column{OrganizationsColumns[0]},
+				OnDelete:   schema.SetNull,
+			},
 			{
 				Symbol:     "users_user_addresses_address",
-				Columns:    []*schema.Column{UsersColumns[22]},
+				Columns:    []*schema.Column{UsersColumns[32]},
 				RefColumns: []*schema.Column{UserAddressesColumns[0]},
 				OnDelete:   schema.SetNull,
 			},
@@ -51,27 +201,37 @@ var (
 			{
 				Name:    "user_email",
 				Unique:  false,
-				Columns: []*schema.Column{UsersColumns[9]},
+				Columns: []*schema.Column{UsersColumns[10]},
 			},
 			{
 				Name:    "user_username",
 				Unique:  false,
-				Columns: []*schema.Column{UsersColumns[10]},
+				Columns: []*schema.Column{UsersColumns[11]},
 			},
 			{
 				Name:    "user_oauth_id_provider",
 				Unique:  true,
-				Columns: []*schema.Column{UsersColumns[12], UsersColumns[13]},
+				Columns: []*schema.Column{UsersColumns[14], UsersColumns[15]},
 			},
 			{
 				Name:    "user_last_login_at",
 				Unique:  false,
-				Columns: []*schema.Column{UsersColumns[21]},
+				Columns: []*schema.Column{UsersColumns[24]},
 			},
 			{
 				Name:    "user_is_email_verified",
 				Unique:  false,
-				Columns: []*schema.Column{UsersColumns[18]},
+				Columns: []*schema.Column{UsersColumns[20]},
+			},
+			{
+				Name:    "user_onboarding_step",
+				Unique:  false,
+				Columns: []*schema.Column{UsersColumns[25]},
+			},
+			{
+				Name:    "user_residency",
+				Unique:  false,
+				Columns: []*schema.Column{UsersColumns[28]},
 			},
 		},
 	}
@@ -85,13 +245,54 @@ var (
 		Columns:    UserAddressesColumns,
 		PrimaryKey: []*schema.Column{UserAddressesColumns[0]},
 	}
+	// UserIdentitiesColumns holds the columns for the "user_identities" table.
+	UserIdentitiesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt64, Increment: true},
+		{Name: "provider", Type: field.TypeEnum, Enums: []string{"GOOGLE", "FACEBOOK", "APPLE", "GITHUB", "MICROSOFT", "OAUTH"}},
+		{Name: "oauth_id", Type: field.TypeString, Size: 255},
+		{Name: "email", Type: field.TypeString, Nullable: true, Size: 255},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "last_used_at", Type: field.TypeTime, Nullable: true},
+		{Name: "user_id", Type: field.TypeInt64},
+	}
+	// UserIdentitiesTable holds the schema information for the "user_identities" table.
+	UserIdentitiesTable = &schema.Table{
+		Name:       "user_identities",
+		Columns:    UserIdentitiesColumns,
+		PrimaryKey: []*schema.Column{UserIdentitiesColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "user_identities_users_identities",
+				Columns:    []*schema.Column{UserIdentitiesColumns[6]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.NoAction,
+			},
+		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "useridentity_provider_oauth_id",
+				Unique:  true,
+				Columns: []*schema.Column{UserIdentitiesColumns[1], UserIdentitiesColumns[2]},
+			},
+		},
+	}
 	// Tables holds all the tables in the schema.
 	Tables = []*schema.Table{
+		LoginAttemptsTable,
+		OrganizationsTable,
+		ScheduledActionsTable,
+		ServiceAccountKeysTable,
 		UsersTable,
 		UserAddressesTable,
+		UserIdentitiesTable,
 	}
 )
 
 func init() {
-	UsersTable.ForeignKeys[0].RefTable = UserAddressesTable
+	LoginAttemptsTable.ForeignKeys[0].RefTable = UsersTable
+	ScheduledActionsTable.ForeignKeys[0].RefTable = UsersTable
+	ServiceAccountKeysTable.ForeignKeys[0].RefTable = UsersTable
+	UsersTable.ForeignKeys[0].RefTable = OrganizationsTable
+	UsersTable.ForeignKeys[1].RefTable = UserAddressesTable
+	UserIdentitiesTable.ForeignKeys[0].RefTable = UsersTable
 }
@@ -6,8 +6,23 @@ import (
 	"entgo.io/ent/dialect/sql"
 )
 
+// LoginAttempt is the predicate function for loginattempt builders.
+type LoginAttempt func(*sql.Selector)
+
+// Organization is the predicate function for organization builders.
+type Organization func(*sql.Selector)
+
+// ScheduledAction is the predicate function for scheduledaction builders.
+type ScheduledAction func(*sql.Selector)
+
+// ServiceAccountKey is the predicate function for serviceaccountkey builders.
+type ServiceAccountKey func(*sql.Selector)
+
 // User is the predicate function for user builders.
 type User func(*sql.Selector)
 
 // UserAddress is the predicate function for useraddress builders.
 type UserAddress func(*sql.Selector)
+
+// UserIdentity is the predicate function for useridentity builders.
+type UserIdentity func(*sql.Selector)
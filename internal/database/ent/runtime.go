@@ -5,14 +5,174 @@ package ent
 import (
 	"time"
 
+	"github.com/abisalde/authentication-service/internal/database/ent/loginattempt"
+	"github.com/abisalde/authentication-service/internal/database/ent/organization"
+	"github.com/abisalde/authentication-service/internal/database/ent/scheduledaction"
 	"github.com/abisalde/authentication-service/internal/database/ent/schema"
+	"github.com/abisalde/authentication-service/internal/database/ent/serviceaccountkey"
 	"github.com/abisalde/authentication-service/internal/database/ent/user"
+	"github.com/abisalde/authentication-service/internal/database/ent/useridentity"
+	"github.com/google/uuid"
 )
 
 // The init function reads all schema descriptors with runtime code
 // (default values, validators, hooks and policies) and stitches it
 // to their package variables.
 func init() {
+	loginattemptFields := schema.LoginAttempt{}.Fields()
+	_ = loginattemptFields
+	// loginattemptDescEmail is the schema descriptor for email field.
+	loginattemptDescEmail := loginattemptFields[2].Descriptor()
+	// loginattempt.EmailValidator is a validator for the "email" field. It is called by the builders before save.
+	loginattempt.EmailValidator = loginattemptDescEmail.Validators[0].(func(string) error)
+	// loginattemptDescIP is the schema descriptor for ip field.
+	loginattemptDescIP := loginattemptFields[3].Descriptor()
+	// loginattempt.DefaultIP holds the default value on creation for the ip field.
+	loginattempt.DefaultIP = loginattemptDescIP.Default.(string)
+	// loginattempt.IPValidator is a validator for the "ip" field. It is called by the builders before save.
+	loginattempt.IPValidator = loginattemptDescIP.Validators[0].(func(string) error)
+	// loginattemptDescDeviceID is the schema descriptor for device_id field.
+	loginattemptDescDeviceID := loginattemptFields[4].Descriptor()
+	// loginattempt.DefaultDeviceID holds the default value on creation for the device_id field.
+	loginattempt.DefaultDeviceID = loginattemptDescDeviceID.Default.(string)
+	// loginattempt.DeviceIDValidator is a validator for the "device_id" field. It is called by the builders before save.
+	loginattempt.DeviceIDValidator = loginattemptDescDeviceID.Validators[0].(func(string) error)
+	// loginattemptDescPlatform is the schema descriptor for platform field.
+	loginattemptDescPlatform := loginattemptFields[5].Descriptor()
+	// loginattempt.DefaultPlatform holds the default value on creation for the platform field.
+	loginattempt.DefaultPlatform = loginattemptDescPlatform.Default.(string)
+	// loginattempt.PlatformValidator is a validator for the "platform" field. It is called by the builders before save.
+	loginattempt.PlatformValidator = loginattemptDescPlatform.Validators[0].(func(string) error)
+	// loginattemptDescUserAgent is the schema descriptor for user_agent field.
+	loginattemptDescUserAgent := loginattemptFields[6].Descriptor()
+	// loginattempt.DefaultUserAgent holds the default value on creation for the user_agent field.
+	loginattempt.DefaultUserAgent = loginattemptDescUserAgent.Default.(string)
+	// loginattempt.UserAgentValidator is a validator for the "user_agent" field. It is called by the builders before save.
+	loginattempt.UserAgentValidator = loginattemptDescUserAgent.Validators[0].(func(string) error)
+	// loginattemptDescRiskScore is the schema descriptor for risk_score field.
+	loginattemptDescRiskScore := loginattemptFields[11].Descriptor()
+	// loginattempt.DefaultRiskScore holds the default value on creation for the risk_score field.
+	loginattempt.DefaultRiskScore = loginattemptDescRiskScore.Default.(int)
+	// loginattempt.RiskScoreValidator is a validator for the "risk_score" field. It is called by the builders before save.
+	loginattempt.RiskScoreValidator = func() func(int) error {
+		validators := loginattemptDescRiskScore.Validators
+		fns := [...]func(int) error{
+			validators[0].(func(int) error),
+			validators[1].(func(int) error),
+		}
+		return func(risk_score int) error {
+			for _, fn := range fns {
+				if err := fn(risk_score); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}()
+	// loginattemptDescCreatedAt is the schema descriptor for created_at field.
+	loginattemptDescCreatedAt := loginattemptFields[12].Descriptor()
+	// loginattempt.DefaultCreatedAt holds the default value on creation for the created_at field.
+	loginattempt.DefaultCreatedAt = loginattemptDescCreatedAt.Default.(func() time.Time)
+	organizationFields := schema.Organization{}.Fields()
+	_ = organizationFields
+	// organizationDescSlug is the schema descriptor for slug field.
+	organizationDescSlug := organizationFields[1].Descriptor()
+	// organization.SlugValidator is a validator for the "slug" field. It is called by the builders before save.
+	organization.SlugValidator = func() func(string) error {
+		validators := organizationDescSlug.Validators
+		fns := [...]func(string) error{
+			validators[0].(func(string) error),
+			validators[1].(func(string) error),
+		}
+		return func(slug string) error {
+			for _, fn := range fns {
+				if err := fn(slug); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}()
+	// organizationDescName is the schema descriptor for name field.
+	organizationDescName := organizationFields[2].Descriptor()
+	// organization.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	organization.NameValidator = organizationDescName.Validators[0].(func(string) error)
+	// organizationDescProductName is the schema descriptor for product_name field.
+	organizationDescProductName := organizationFields[3].Descriptor()
+	// organization.DefaultProductName holds the default value on creation for the product_name field.
+	organization.DefaultProductName = organizationDescProductName.Default.(string)
+	// organization.ProductNameValidator is a validator for the "product_name" field. It is called by the builders before save.
+	organization.ProductNameValidator = organizationDescProductName.Validators[0].(func(string) error)
+	// organizationDescLogoURL is the schema descriptor for logo_url field.
+	organizationDescLogoURL := organizationFields[4].Descriptor()
+	// organization.DefaultLogoURL holds the default value on creation for the logo_url field.
+	organization.DefaultLogoURL = organizationDescLogoURL.Default.(string)
+	// organization.LogoURLValidator is a validator for the "logo_url" field. It is called by the builders before save.
+	organization.LogoURLValidator = organizationDescLogoURL.Validators[0].(func(string) error)
+	// organizationDescPrimaryColor is the schema descriptor for primary_color field.
+	organizationDescPrimaryColor := organizationFields[5].Descriptor()
+	// organization.DefaultPrimaryColor holds the default value on creation for the primary_color field.
+	organization.DefaultPrimaryColor = organizationDescPrimaryColor.Default.(string)
+	// organization.PrimaryColorValidator is a validator for the "primary_color" field. It is called by the builders before save.
+	organization.PrimaryColorValidator = func() func(string) error {
+		validators := organizationDescPrimaryColor.Validators
+		fns := [...]func(string) error{
+			validators[0].(func(string) error),
+			validators[1].(func(string) error),
+		}
+		return func(primary_color string) error {
+			for _, fn := range fns {
+				if err := fn(primary_color); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}()
+	// organizationDescSupportEmail is the schema descriptor for support_email field.
+	organizationDescSupportEmail := organizationFields[6].Descriptor()
+	// organization.DefaultSupportEmail holds the default value on creation for the support_email field.
+	organization.DefaultSupportEmail = organizationDescSupportEmail.Default.(string)
+	// organization.SupportEmailValidator is a validator for the "support_email" field. It is called by the builders before save.
+	organization.SupportEmailValidator = organizationDescSupportEmail.Validators[0].(func(string) error)
+	// organizationDescCreatedAt is the schema descriptor for created_at field.
+	organizationDescCreatedAt := organizationFields[7].Descriptor()
+	// organization.DefaultCreatedAt holds the default value on creation for the created_at field.
+	organization.DefaultCreatedAt = organizationDescCreatedAt.Default.(func() time.Time)
+	// organizationDescUpdatedAt is the schema descriptor for updated_at field.
+	organizationDescUpdatedAt := organizationFields[8].Descriptor()
+	// organization.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	organization.DefaultUpdatedAt = organizationDescUpdatedAt.Default.(func() time.Time)
+	// organization.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	organization.UpdateDefaultUpdatedAt = organizationDescUpdatedAt.UpdateDefault.(func() time.Time)
+	scheduledactionFields := schema.ScheduledAction{}.Fields()
+	_ = scheduledactionFields
+	// scheduledactionDescNote is the schema descriptor for note field.
+	scheduledactionDescNote := scheduledactionFields[5].Descriptor()
+	// scheduledaction.NoteValidator is a validator for the "note" field. It is called by the builders before save.
+	scheduledaction.NoteValidator = scheduledactionDescNote.Validators[0].(func(string) error)
+	// scheduledactionDescError is the schema descriptor for error field.
+	scheduledactionDescError := scheduledactionFields[8].Descriptor()
+	// scheduledaction.ErrorValidator is a validator for the "error" field. It is called by the builders before save.
+	scheduledaction.ErrorValidator = scheduledactionDescError.Validators[0].(func(string) error)
+	// scheduledactionDescCreatedAt is the schema descriptor for created_at field.
+	scheduledactionDescCreatedAt := scheduledactionFields[9].Descriptor()
+	// scheduledaction.DefaultCreatedAt holds the default value on creation for the created_at field.
+	scheduledaction.DefaultCreatedAt = scheduledactionDescCreatedAt.Default.(func() time.Time)
+	serviceaccountkeyFields := schema.ServiceAccountKey{}.Fields()
+	_ = serviceaccountkeyFields
+	// serviceaccountkeyDescKeyID is the schema descriptor for key_id field.
+	serviceaccountkeyDescKeyID := serviceaccountkeyFields[2].Descriptor()
+	// serviceaccountkey.KeyIDValidator is a validator for the "key_id" field. It is called by the builders before save.
+	serviceaccountkey.KeyIDValidator = serviceaccountkeyDescKeyID.Validators[0].(func(string) error)
+	// serviceaccountkeyDescSecretHash is the schema descriptor for secret_hash field.
+	serviceaccountkeyDescSecretHash := serviceaccountkeyFields[3].Descriptor()
+	// serviceaccountkey.SecretHashValidator is a validator for the "secret_hash" field. It is called by the builders before save.
+	serviceaccountkey.SecretHashValidator = serviceaccountkeyDescSecretHash.Validators[0].(func(string) error)
+	// serviceaccountkeyDescCreatedAt is the schema descriptor for created_at field.
+	serviceaccountkeyDescCreatedAt := serviceaccountkeyFields[4].Descriptor()
+	// serviceaccountkey.DefaultCreatedAt holds the default value on creation for the created_at field.
+	serviceaccountkey.DefaultCreatedAt = serviceaccountkeyDescCreatedAt.Default.(func() time.Time)
 	userMixin := schema.User{}.Mixin()
 	userMixinFields0 := userMixin[0].Fields()
 	_ = userMixinFields0
@@ -60,12 +220,16 @@ func init() {
 	user.DefaultState = userDescState.Default.(string)
 	// user.StateValidator is a validator for the "state" field. It is called by the builders before save.
 	user.StateValidator = userDescState.Validators[0].(func(string) error)
+	// userDescPublicID is the schema descriptor for public_id field.
+	userDescPublicID := userFields[1].Descriptor()
+	// user.DefaultPublicID holds the default value on creation for the public_id field.
+	user.DefaultPublicID = userDescPublicID.Default.(func() uuid.UUID)
 	// userDescEmail is the schema descriptor for email field.
-	userDescEmail := userFields[1].Descriptor()
+	userDescEmail := userFields[2].Descriptor()
 	// user.EmailValidator is a validator for the "email" field. It is called by the builders before save.
 	user.EmailValidator = userDescEmail.Validators[0].(func(string) error)
 	// userDescUsername is the schema descriptor for username field.
-	userDescUsername := userFields[2].Descriptor()
+	userDescUsername := userFields[3].Descriptor()
 	// user.UsernameValidator is a validator for the "username" field. It is called by the builders before save.
 	user.UsernameValidator = func() func(string) error {
 		validators := userDescUsername.Validators
@@ -84,31 +248,53 @@ func init() {
 		}
 	}()
 	// userDescOauthID is the schema descriptor for oauth_id field.
-	userDescOauthID := userFields[4].Descriptor()
+	userDescOauthID := userFields[6].Descriptor()
 	// user.OauthIDValidator is a validator for the "oauth_id" field. It is called by the builders before save.
 	user.OauthIDValidator = userDescOauthID.Validators[0].(func(string) error)
 	// userDescFirstName is the schema descriptor for first_name field.
-	userDescFirstName := userFields[6].Descriptor()
+	userDescFirstName := userFields[8].Descriptor()
 	// user.DefaultFirstName holds the default value on creation for the first_name field.
 	user.DefaultFirstName = userDescFirstName.Default.(string)
 	// user.FirstNameValidator is a validator for the "first_name" field. It is called by the builders before save.
 	user.FirstNameValidator = userDescFirstName.Validators[0].(func(string) error)
 	// userDescLastName is the schema descriptor for last_name field.
-	userDescLastName := userFields[7].Descriptor()
+	userDescLastName := userFields[9].Descriptor()
 	// user.DefaultLastName holds the default value on creation for the last_name field.
 	user.DefaultLastName = userDescLastName.Default.(string)
 	// user.LastNameValidator is a validator for the "last_name" field. It is called by the builders before save.
 	user.LastNameValidator = userDescLastName.Validators[0].(func(string) error)
 	// userDescPhoneNumber is the schema descriptor for phone_number field.
-	userDescPhoneNumber := userFields[8].Descriptor()
+	userDescPhoneNumber := userFields[10].Descriptor()
 	// user.PhoneNumberValidator is a validator for the "phone_number" field. It is called by the builders before save.
 	user.PhoneNumberValidator = userDescPhoneNumber.Validators[0].(func(string) error)
 	// userDescIsEmailVerified is the schema descriptor for is_email_verified field.
-	userDescIsEmailVerified := userFields[10].Descriptor()
+	userDescIsEmailVerified := userFields[12].Descriptor()
 	// user.DefaultIsEmailVerified holds the default value on creation for the is_email_verified field.
 	user.DefaultIsEmailVerified = userDescIsEmailVerified.Default.(bool)
 	// userDescMarketingOptIn is the schema descriptor for marketing_opt_in field.
-	userDescMarketingOptIn := userFields[11].Descriptor()
+	userDescMarketingOptIn := userFields[13].Descriptor()
 	// user.DefaultMarketingOptIn holds the default value on creation for the marketing_opt_in field.
 	user.DefaultMarketingOptIn = userDescMarketingOptIn.Default.(bool)
+	// userDescTermsVersion is the schema descriptor for terms_version field.
+	userDescTermsVersion := userFields[15].Descriptor()
+	// user.TermsVersionValidator is a validator for the "terms_version" field. It is called by the builders before save.
+	user.TermsVersionValidator = userDescTermsVersion.Validators[0].(func(string) error)
+	// userDescResidency is the schema descriptor for residency field.
+	userDescResidency := userFields[20].Descriptor()
+	// user.ResidencyValidator is a validator for the "residency" field. It is called by the builders before save.
+	user.ResidencyValidator = userDescResidency.Validators[0].(func(string) error)
+	useridentityFields := schema.UserIdentity{}.Fields()
+	_ = useridentityFields
+	// useridentityDescOauthID is the schema descriptor for oauth_id field.
+	useridentityDescOauthID := useridentityFields[3].Descriptor()
+	// useridentity.OauthIDValidator is a validator for the "oauth_id" field. It is called by the builders before save.
+	useridentity.OauthIDValidator = useridentityDescOauthID.Validators[0].(func(string) error)
+	// useridentityDescEmail is the schema descriptor for email field.
+	useridentityDescEmail := useridentityFields[4].Descriptor()
+	// useridentity.EmailValidator is a validator for the "email" field. It is called by the builders before save.
+	useridentity.EmailValidator = useridentityDescEmail.Validators[0].(func(string) error)
+	// useridentityDescCreatedAt is the schema descriptor for created_at field.
+	useridentityDescCreatedAt := useridentityFields[5].Descriptor()
+	// useridentity.DefaultCreatedAt holds the default value on creation for the created_at field.
+	useridentity.DefaultCreatedAt = useridentityDescCreatedAt.Default.(func() time.Time)
 }
@@ -0,0 +1,246 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/abisalde/authentication-service/internal/database/ent/scheduledaction"
+	"github.com/abisalde/authentication-service/internal/database/ent/user"
+	"github.com/google/uuid"
+)
+
+// ScheduledAction is the model entity for the ScheduledAction schema.
+type ScheduledAction struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int64 `json:"id,omitempty"`
+	// UserID holds the value of the "user_id" field.
+	UserID int64 `json:"userId"`
+	// Action holds the value of the "action" field.
+	Action scheduledaction.Action `json:"action,omitempty"`
+	// RunAt holds the value of the "run_at" field.
+	RunAt time.Time `json:"runAt"`
+	// Status holds the value of the "status" field.
+	Status scheduledaction.Status `json:"status,omitempty"`
+	// Note holds the value of the "note" field.
+	Note string `json:"note,omitempty"`
+	// CreatedBy holds the value of the "created_by" field.
+	CreatedBy *uuid.UUID `json:"createdBy"`
+	// CancelledBy holds the value of the "cancelled_by" field.
+	CancelledBy *uuid.UUID `json:"cancelledBy"`
+	// Error holds the value of the "error" field.
+	Error string `json:"error,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"createdAt"`
+	// FinishedAt holds the value of the "finished_at" field.
+	FinishedAt *time.Time `json:"finishedAt"`
+	// Edges holds the relations/edges for other nodes in the graph.
+	// The values are being populated by the ScheduledActionQuery when eager-loading is set.
+	Edges        ScheduledActionEdges `json:"edges"`
+	selectValues sql.SelectValues
+}
+
+// ScheduledActionEdges holds the relations/edges for other nodes in the graph.
+type ScheduledActionEdges struct {
+	// User holds the value of the user edge.
+	User *User `json:"user,omitempty"`
+	// loadedTypes holds the information for reporting if a
+	// type was loaded (or requested) in eager-loading or not.
+	loadedTypes [1]bool
+}
+
+// UserOrErr returns the User value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e ScheduledActionEdges) UserOrErr() (*User, error) {
+	if e.User != nil {
+		return e.User, nil
+	} else if e.loadedTypes[0] {
+		return nil, &NotFoundError{label: user.Label}
+	}
+	return nil, &NotLoadedError{edge: "user"}
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*ScheduledAction) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case scheduledaction.FieldCreatedBy, scheduledaction.FieldCancelledBy:
+			values[i] = &sql.NullScanner{S: new(uuid.UUID)}
+		case scheduledaction.FieldID, scheduledaction.FieldUserID:
+			values[i] = new(sql.NullInt64)
+		case scheduledaction.FieldAction, scheduledaction.FieldStatus, scheduledaction.FieldNote, scheduledaction.FieldError:
+			values[i] = new(sql.NullString)
+		case scheduledaction.FieldRunAt, scheduledaction.FieldCreatedAt, scheduledaction.FieldFinishedAt:
+			values[i] = new(sql.NullTime)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the ScheduledAction fields.
+func (_m *ScheduledAction) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case scheduledaction.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			_m.ID = int64(value.Int64)
+		case scheduledaction.FieldUserID:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field user_id", values[i])
+			} else if value.Valid {
+				_m.UserID = value.Int64
+			}
+		case scheduledaction.FieldAction:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field action", values[i])
+			} else if value.Valid {
+				_m.Action = scheduledaction.Action(value.String)
+			}
+		case scheduledaction.FieldRunAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field run_at", values[i])
+			} else if value.Valid {
+				_m.RunAt = value.Time
+			}
+		case scheduledaction.FieldStatus:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field status", values[i])
+			} else if value.Valid {
+				_m.Status = scheduledaction.Status(value.String)
+			}
+		case scheduledaction.FieldNote:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field note", values[i])
+			} else if value.Valid {
+				_m.Note = value.String
+			}
+		case scheduledaction.FieldCreatedBy:
+			if value, ok := values[i].(*sql.NullScanner); !ok {
+				return fmt.Errorf("unexpected type %T for field created_by", values[i])
+			} else if value.Valid {
+				_m.CreatedBy = new(uuid.UUID)
+				*_m.CreatedBy = *value.S.(*uuid.UUID)
+			}
+		case scheduledaction.FieldCancelledBy:
+			if value, ok := values[i].(*sql.NullScanner); !ok {
+				return fmt.Errorf("unexpected type %T for field cancelled_by", values[i])
+			} else if value.Valid {
+				_m.CancelledBy = new(uuid.UUID)
+				*_m.CancelledBy = *value.S.(*uuid.UUID)
+			}
+		case scheduledaction.FieldError:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field error", values[i])
+			} else if value.Valid {
+				_m.Error = value.String
+			}
+		case scheduledaction.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				_m.CreatedAt = value.Time
+			}
+		case scheduledaction.FieldFinishedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field finished_at", values[i])
+			} else if value.Valid {
+				_m.FinishedAt = new(time.Time)
+				*_m.FinishedAt = value.Time
+			}
+		default:
+			_m.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the ScheduledAction.
+// This includes values selected through modifiers, order, etc.
+func (_m *ScheduledAction) Value(name string) (ent.Value, error) {
+	return _m.selectValues.Get(name)
+}
+
+// QueryUser queries the "user" edge of the ScheduledAction entity.
+func (_m *ScheduledAction) QueryUser() *UserQuery {
+	return NewScheduledActionClient(_m.config).QueryUser(_m)
+}
+
+// Update returns a builder for updating this ScheduledAction.
+// Note that you need to call ScheduledAction.Unwrap() before calling this method if this ScheduledAction
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (_m *ScheduledAction) Update() *ScheduledActionUpdateOne {
+	return NewScheduledActionClient(_m.config).UpdateOne(_m)
+}
+
+// Unwrap unwraps the ScheduledAction entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (_m *ScheduledAction) Unwrap() *ScheduledAction {
+	_tx, ok := _m.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: ScheduledAction is not a transactional entity")
+	}
+	_m.config.driver = _tx.drv
+	return _m
+}
+
+// String implements the fmt.Stringer.
+func (_m *ScheduledAction) String() string {
+	var builder strings.Builder
+	builder.WriteString("ScheduledAction(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", _m.ID))
+	builder.WriteString("user_id=")
+	builder.WriteString(fmt.Sprintf("%v", _m.UserID))
+	builder.WriteString(", ")
+	builder.WriteString("action=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Action))
+	builder.WriteString(", ")
+	builder.WriteString("run_at=")
+	builder.WriteString(_m.RunAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("status=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Status))
+	builder.WriteString(", ")
+	builder.WriteString("note=")
+	builder.WriteString(_m.Note)
+	builder.WriteString(", ")
+	if v := _m.CreatedBy; v != nil {
+		builder.WriteString("created_by=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	if v := _m.CancelledBy; v != nil {
+		builder.WriteString("cancelled_by=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("error=")
+	builder.WriteString(_m.Error)
+	builder.WriteString(", ")
+	builder.WriteString("created_at=")
+	builder.WriteString(_m.CreatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	if v := _m.FinishedAt; v != nil {
+		builder.WriteString("finished_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// ScheduledActions is a parsable slice of ScheduledAction.
+type ScheduledActions []*ScheduledAction
@@ -0,0 +1,575 @@
+// Code generated by ent, DO NOT EDIT.
+
+package scheduledaction
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/abisalde/authentication-service/internal/database/ent/predicate"
+	"github.com/google/uuid"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id int64) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id int64) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id int64) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...int64) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...int64) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id int64) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id int64) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id int64) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id int64) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldLTE(FieldID, id))
+}
+
+// UserID applies equality check predicate on the "user_id" field. It's identical to UserIDEQ.
+func UserID(v int64) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldEQ(FieldUserID, v))
+}
+
+// RunAt applies equality check predicate on the "run_at" field. It's identical to RunAtEQ.
+func RunAt(v time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldEQ(FieldRunAt, v))
+}
+
+// Note applies equality check predicate on the "note" field. It's identical to NoteEQ.
+func Note(v string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldEQ(FieldNote, v))
+}
+
+// CreatedBy applies equality check predicate on the "created_by" field. It's identical to CreatedByEQ.
+func CreatedBy(v uuid.UUID) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldEQ(FieldCreatedBy, v))
+}
+
+// CancelledBy applies equality check predicate on the "cancelled_by" field. It's identical to CancelledByEQ.
+func CancelledBy(v uuid.UUID) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldEQ(FieldCancelledBy, v))
+}
+
+// Error applies equality check predicate on the "error" field. It's identical to ErrorEQ.
+func Error(v string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldEQ(FieldError, v))
+}
+
+// CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
+func CreatedAt(v time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// FinishedAt applies equality check predicate on the "finished_at" field. It's identical to FinishedAtEQ.
+func FinishedAt(v time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldEQ(FieldFinishedAt, v))
+}
+
+// UserIDEQ applies the EQ predicate on the "user_id" field.
+func UserIDEQ(v int64) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldEQ(FieldUserID, v))
+}
+
+// UserIDNEQ applies the NEQ predicate on the "user_id" field.
+func UserIDNEQ(v int64) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNEQ(FieldUserID, v))
+}
+
+// UserIDIn applies the In predicate on the "user_id" field.
+func UserIDIn(vs ...int64) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldIn(FieldUserID, vs...))
+}
+
+// UserIDNotIn applies the NotIn predicate on the "user_id" field.
+func UserIDNotIn(vs ...int64) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNotIn(FieldUserID, vs...))
+}
+
+// ActionEQ applies the EQ predicate on the "action" field.
+func ActionEQ(v Action) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldEQ(FieldAction, v))
+}
+
+// ActionNEQ applies the NEQ predicate on the "action" field.
+func ActionNEQ(v Action) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNEQ(FieldAction, v))
+}
+
+// ActionIn applies the In predicate on the "action" field.
+func ActionIn(vs ...Action) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldIn(FieldAction, vs...))
+}
+
+// ActionNotIn applies the NotIn predicate on the "action" field.
+func ActionNotIn(vs ...Action) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNotIn(FieldAction, vs...))
+}
+
+// RunAtEQ applies the EQ predicate on the "run_at" field.
+func RunAtEQ(v time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldEQ(FieldRunAt, v))
+}
+
+// RunAtNEQ applies the NEQ predicate on the "run_at" field.
+func RunAtNEQ(v time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNEQ(FieldRunAt, v))
+}
+
+// RunAtIn applies the In predicate on the "run_at" field.
+func RunAtIn(vs ...time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldIn(FieldRunAt, vs...))
+}
+
+// RunAtNotIn applies the NotIn predicate on the "run_at" field.
+func RunAtNotIn(vs ...time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNotIn(FieldRunAt, vs...))
+}
+
+// RunAtGT applies the GT predicate on the "run_at" field.
+func RunAtGT(v time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldGT(FieldRunAt, v))
+}
+
+// RunAtGTE applies the GTE predicate on the "run_at" field.
+func RunAtGTE(v time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldGTE(FieldRunAt, v))
+}
+
+// RunAtLT applies the LT predicate on the "run_at" field.
+func RunAtLT(v time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldLT(FieldRunAt, v))
+}
+
+// RunAtLTE applies the LTE predicate on the "run_at" field.
+func RunAtLTE(v time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldLTE(FieldRunAt, v))
+}
+
+// StatusEQ applies the EQ predicate on the "status" field.
+func StatusEQ(v Status) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldEQ(FieldStatus, v))
+}
+
+// StatusNEQ applies the NEQ predicate on the "status" field.
+func StatusNEQ(v Status) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNEQ(FieldStatus, v))
+}
+
+// StatusIn applies the In predicate on the "status" field.
+func StatusIn(vs ...Status) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldIn(FieldStatus, vs...))
+}
+
+// StatusNotIn applies the NotIn predicate on the "status" field.
+func StatusNotIn(vs ...Status) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNotIn(FieldStatus, vs...))
+}
+
+// NoteEQ applies the EQ predicate on the "note" field.
+func NoteEQ(v string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldEQ(FieldNote, v))
+}
+
+// NoteNEQ applies the NEQ predicate on the "note" field.
+func NoteNEQ(v string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNEQ(FieldNote, v))
+}
+
+// NoteIn applies the In predicate on the "note" field.
+func NoteIn(vs ...string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldIn(FieldNote, vs...))
+}
+
+// NoteNotIn applies the NotIn predicate on the "note" field.
+func NoteNotIn(vs ...string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNotIn(FieldNote, vs...))
+}
+
+// NoteGT applies the GT predicate on the "note" field.
+func NoteGT(v string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldGT(FieldNote, v))
+}
+
+// NoteGTE applies the GTE predicate on the "note" field.
+func NoteGTE(v string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldGTE(FieldNote, v))
+}
+
+// NoteLT applies the LT predicate on the "note" field.
+func NoteLT(v string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldLT(FieldNote, v))
+}
+
+// NoteLTE applies the LTE predicate on the "note" field.
+func NoteLTE(v string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldLTE(FieldNote, v))
+}
+
+// NoteContains applies the Contains predicate on the "note" field.
+func NoteContains(v string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldContains(FieldNote, v))
+}
+
+// NoteHasPrefix applies the HasPrefix predicate on the "note" field.
+func NoteHasPrefix(v string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldHasPrefix(FieldNote, v))
+}
+
+// NoteHasSuffix applies the HasSuffix predicate on the "note" field.
+func NoteHasSuffix(v string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldHasSuffix(FieldNote, v))
+}
+
+// NoteIsNil applies the IsNil predicate on the "note" field.
+func NoteIsNil() predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldIsNull(FieldNote))
+}
+
+// NoteNotNil applies the NotNil predicate on the "note" field.
+func NoteNotNil() predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNotNull(FieldNote))
+}
+
+// NoteEqualFold applies the EqualFold predicate on the "note" field.
+func NoteEqualFold(v string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldEqualFold(FieldNote, v))
+}
+
+// NoteContainsFold applies the ContainsFold predicate on the "note" field.
+func NoteContainsFold(v string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldContainsFold(FieldNote, v))
+}
+
+// CreatedByEQ applies the EQ predicate on the "created_by" field.
+func CreatedByEQ(v uuid.UUID) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldEQ(FieldCreatedBy, v))
+}
+
+// CreatedByNEQ applies the NEQ predicate on the "created_by" field.
+func CreatedByNEQ(v uuid.UUID) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNEQ(FieldCreatedBy, v))
+}
+
+// CreatedByIn applies the In predicate on the "created_by" field.
+func CreatedByIn(vs ...uuid.UUID) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldIn(FieldCreatedBy, vs...))
+}
+
+// CreatedByNotIn applies the NotIn predicate on the "created_by" field.
+func CreatedByNotIn(vs ...uuid.UUID) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNotIn(FieldCreatedBy, vs...))
+}
+
+// CreatedByGT applies the GT predicate on the "created_by" field.
+func CreatedByGT(v uuid.UUID) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldGT(FieldCreatedBy, v))
+}
+
+// CreatedByGTE applies the GTE predicate on the "created_by" field.
+func CreatedByGTE(v uuid.UUID) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldGTE(FieldCreatedBy, v))
+}
+
+// CreatedByLT applies the LT predicate on the "created_by" field.
+func CreatedByLT(v uuid.UUID) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldLT(FieldCreatedBy, v))
+}
+
+// CreatedByLTE applies the LTE predicate on the "created_by" field.
+func CreatedByLTE(v uuid.UUID) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldLTE(FieldCreatedBy, v))
+}
+
+// CreatedByIsNil applies the IsNil predicate on the "created_by" field.
+func CreatedByIsNil() predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldIsNull(FieldCreatedBy))
+}
+
+// CreatedByNotNil applies the NotNil predicate on the "created_by" field.
+func CreatedByNotNil() predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNotNull(FieldCreatedBy))
+}
+
+// CancelledByEQ applies the EQ predicate on the "cancelled_by" field.
+func CancelledByEQ(v uuid.UUID) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldEQ(FieldCancelledBy, v))
+}
+
+// CancelledByNEQ applies the NEQ predicate on the "cancelled_by" field.
+func CancelledByNEQ(v uuid.UUID) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNEQ(FieldCancelledBy, v))
+}
+
+// CancelledByIn applies the In predicate on the "cancelled_by" field.
+func CancelledByIn(vs ...uuid.UUID) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldIn(FieldCancelledBy, vs...))
+}
+
+// CancelledByNotIn applies the NotIn predicate on the "cancelled_by" field.
+func CancelledByNotIn(vs ...uuid.UUID) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNotIn(FieldCancelledBy, vs...))
+}
+
+// CancelledByGT applies the GT predicate on the "cancelled_by" field.
+func CancelledByGT(v uuid.UUID) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldGT(FieldCancelledBy, v))
+}
+
+// CancelledByGTE applies the GTE predicate on the "cancelled_by" field.
+func CancelledByGTE(v uuid.UUID) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldGTE(FieldCancelledBy, v))
+}
+
+// CancelledByLT applies the LT predicate on the "cancelled_by" field.
+func CancelledByLT(v uuid.UUID) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldLT(FieldCancelledBy, v))
+}
+
+// CancelledByLTE applies the LTE predicate on the "cancelled_by" field.
+func CancelledByLTE(v uuid.UUID) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldLTE(FieldCancelledBy, v))
+}
+
+// CancelledByIsNil applies the IsNil predicate on the "cancelled_by" field.
+func CancelledByIsNil() predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldIsNull(FieldCancelledBy))
+}
+
+// CancelledByNotNil applies the NotNil predicate on the "cancelled_by" field.
+func CancelledByNotNil() predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNotNull(FieldCancelledBy))
+}
+
+// ErrorEQ applies the EQ predicate on the "error" field.
+func ErrorEQ(v string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldEQ(FieldError, v))
+}
+
+// ErrorNEQ applies the NEQ predicate on the "error" field.
+func ErrorNEQ(v string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNEQ(FieldError, v))
+}
+
+// ErrorIn applies the In predicate on the "error" field.
+func ErrorIn(vs ...string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldIn(FieldError, vs...))
+}
+
+// ErrorNotIn applies the NotIn predicate on the "error" field.
+func ErrorNotIn(vs ...string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNotIn(FieldError, vs...))
+}
+
+// ErrorGT applies the GT predicate on the "error" field.
+func ErrorGT(v string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldGT(FieldError, v))
+}
+
+// ErrorGTE applies the GTE predicate on the "error" field.
+func ErrorGTE(v string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldGTE(FieldError, v))
+}
+
+// ErrorLT applies the LT predicate on the "error" field.
+func ErrorLT(v string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldLT(FieldError, v))
+}
+
+// ErrorLTE applies the LTE predicate on the "error" field.
+func ErrorLTE(v string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldLTE(FieldError, v))
+}
+
+// ErrorContains applies the Contains predicate on the "error" field.
+func ErrorContains(v string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldContains(FieldError, v))
+}
+
+// ErrorHasPrefix applies the HasPrefix predicate on the "error" field.
+func ErrorHasPrefix(v string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldHasPrefix(FieldError, v))
+}
+
+// ErrorHasSuffix applies the HasSuffix predicate on the "error" field.
+func ErrorHasSuffix(v string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldHasSuffix(FieldError, v))
+}
+
+// ErrorIsNil applies the IsNil predicate on the "error" field.
+func ErrorIsNil() predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldIsNull(FieldError))
+}
+
+// ErrorNotNil applies the NotNil predicate on the "error" field.
+func ErrorNotNil() predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNotNull(FieldError))
+}
+
+// ErrorEqualFold applies the EqualFold predicate on the "error" field.
+func ErrorEqualFold(v string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldEqualFold(FieldError, v))
+}
+
+// ErrorContainsFold applies the ContainsFold predicate on the "error" field.
+func ErrorContainsFold(v string) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldContainsFold(FieldError, v))
+}
+
+// CreatedAtEQ applies the EQ predicate on the "created_at" field.
+func CreatedAtEQ(v time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtNEQ applies the NEQ predicate on the "created_at" field.
+func CreatedAtNEQ(v time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtIn applies the In predicate on the "created_at" field.
+func CreatedAtIn(vs ...time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtNotIn applies the NotIn predicate on the "created_at" field.
+func CreatedAtNotIn(vs ...time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNotIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtGT applies the GT predicate on the "created_at" field.
+func CreatedAtGT(v time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldGT(FieldCreatedAt, v))
+}
+
+// CreatedAtGTE applies the GTE predicate on the "created_at" field.
+func CreatedAtGTE(v time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldGTE(FieldCreatedAt, v))
+}
+
+// CreatedAtLT applies the LT predicate on the "created_at" field.
+func CreatedAtLT(v time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldLT(FieldCreatedAt, v))
+}
+
+// CreatedAtLTE applies the LTE predicate on the "created_at" field.
+func CreatedAtLTE(v time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldLTE(FieldCreatedAt, v))
+}
+
+// FinishedAtEQ applies the EQ predicate on the "finished_at" field.
+func FinishedAtEQ(v time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldEQ(FieldFinishedAt, v))
+}
+
+// FinishedAtNEQ applies the NEQ predicate on the "finished_at" field.
+func FinishedAtNEQ(v time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNEQ(FieldFinishedAt, v))
+}
+
+// FinishedAtIn applies the In predicate on the "finished_at" field.
+func FinishedAtIn(vs ...time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldIn(FieldFinishedAt, vs...))
+}
+
+// FinishedAtNotIn applies the NotIn predicate on the "finished_at" field.
+func FinishedAtNotIn(vs ...time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNotIn(FieldFinishedAt, vs...))
+}
+
+// FinishedAtGT applies the GT predicate on the "finished_at" field.
+func FinishedAtGT(v time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldGT(FieldFinishedAt, v))
+}
+
+// FinishedAtGTE applies the GTE predicate on the "finished_at" field.
+func FinishedAtGTE(v time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldGTE(FieldFinishedAt, v))
+}
+
+// FinishedAtLT applies the LT predicate on the "finished_at" field.
+func FinishedAtLT(v time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldLT(FieldFinishedAt, v))
+}
+
+// FinishedAtLTE applies the LTE predicate on the "finished_at" field.
+func FinishedAtLTE(v time.Time) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldLTE(FieldFinishedAt, v))
+}
+
+// FinishedAtIsNil applies the IsNil predicate on the "finished_at" field.
+func FinishedAtIsNil() predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldIsNull(FieldFinishedAt))
+}
+
+// FinishedAtNotNil applies the NotNil predicate on the "finished_at" field.
+func FinishedAtNotNil() predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.FieldNotNull(FieldFinishedAt))
+}
+
+// HasUser applies the HasEdge predicate on the "user" edge.
+func HasUser() predicate.ScheduledAction {
+	return predicate.ScheduledAction(func(s *sql.Selector) {
+		step := sqlgraph.NewStep(
+			sqlgraph.From(Table, FieldID),
+			sqlgraph.Edge(sqlgraph.M2O, true, UserTable, UserColumn),
+		)
+		sqlgraph.HasNeighbors(s, step)
+	})
+}
+
+// HasUserWith applies the HasEdge predicate on the "user" edge with a given conditions (other predicates).
+func HasUserWith(preds ...predicate.User) predicate.ScheduledAction {
+	return predicate.ScheduledAction(func(s *sql.Selector) {
+		step := newUserStep()
+		sqlgraph.HasNeighborsWith(s, step, func(s *sql.Selector) {
+			for _, p := range preds {
+				p(s)
+			}
+		})
+	})
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.ScheduledAction) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.ScheduledAction) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.ScheduledAction) predicate.ScheduledAction {
+	return predicate.ScheduledAction(sql.NotPredicates(p))
+}
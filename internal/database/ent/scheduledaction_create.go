@@ -0,0 +1,411 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/abisalde/authentication-service/internal/database/ent/scheduledaction"
+	"github.com/abisalde/authentication-service/internal/database/ent/user"
+	"github.com/google/uuid"
+)
+
+// ScheduledActionCreate is the builder for creating a ScheduledAction entity.
+type ScheduledActionCreate struct {
+	config
+	mutation *ScheduledActionMutation
+	hooks    []Hook
+}
+
+// SetUserID sets the "user_id" field.
+func (_c *ScheduledActionCreate) SetUserID(v int64) *ScheduledActionCreate {
+	_c.mutation.SetUserID(v)
+	return _c
+}
+
+// SetAction sets the "action" field.
+func (_c *ScheduledActionCreate) SetAction(v scheduledaction.Action) *ScheduledActionCreate {
+	_c.mutation.SetAction(v)
+	return _c
+}
+
+// SetRunAt sets the "run_at" field.
+func (_c *ScheduledActionCreate) SetRunAt(v time.Time) *ScheduledActionCreate {
+	_c.mutation.SetRunAt(v)
+	return _c
+}
+
+// SetStatus sets the "status" field.
+func (_c *ScheduledActionCreate) SetStatus(v scheduledaction.Status) *ScheduledActionCreate {
+	_c.mutation.SetStatus(v)
+	return _c
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (_c *ScheduledActionCreate) SetNillableStatus(v *scheduledaction.Status) *ScheduledActionCreate {
+	if v != nil {
+		_c.SetStatus(*v)
+	}
+	return _c
+}
+
+// SetNote sets the "note" field.
+func (_c *ScheduledActionCreate) SetNote(v string) *ScheduledActionCreate {
+	_c.mutation.SetNote(v)
+	return _c
+}
+
+// SetNillableNote sets the "note" field if the given value is not nil.
+func (_c *ScheduledActionCreate) SetNillableNote(v *string) *ScheduledActionCreate {
+	if v != nil {
+		_c.SetNote(*v)
+	}
+	return _c
+}
+
+// SetCreatedBy sets the "created_by" field.
+func (_c *ScheduledActionCreate) SetCreatedBy(v uuid.UUID) *ScheduledActionCreate {
+	_c.mutation.SetCreatedBy(v)
+	return _c
+}
+
+// SetNillableCreatedBy sets the "created_by" field if the given value is not nil.
+func (_c *ScheduledActionCreate) SetNillableCreatedBy(v *uuid.UUID) *ScheduledActionCreate {
+	if v != nil {
+		_c.SetCreatedBy(*v)
+	}
+	return _c
+}
+
+// SetCancelledBy sets the "cancelled_by" field.
+func (_c *ScheduledActionCreate) SetCancelledBy(v uuid.UUID) *ScheduledActionCreate {
+	_c.mutation.SetCancelledBy(v)
+	return _c
+}
+
+// SetNillableCancelledBy sets the "cancelled_by" field if the given value is not nil.
+func (_c *ScheduledActionCreate) SetNillableCancelledBy(v *uuid.UUID) *ScheduledActionCreate {
+	if v != nil {
+		_c.SetCancelledBy(*v)
+	}
+	return _c
+}
+
+// SetError sets the "error" field.
+func (_c *ScheduledActionCreate) SetError(v string) *ScheduledActionCreate {
+	_c.mutation.SetError(v)
+	return _c
+}
+
+// SetNillableError sets the "error" field if the given value is not nil.
+func (_c *ScheduledActionCreate) SetNillableError(v *string) *ScheduledActionCreate {
+	if v != nil {
+		_c.SetError(*v)
+	}
+	return _c
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (_c *ScheduledActionCreate) SetCreatedAt(v time.Time) *ScheduledActionCreate {
+	_c.mutation.SetCreatedAt(v)
+	return _c
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (_c *ScheduledActionCreate) SetNillableCreatedAt(v *time.Time) *ScheduledActionCreate {
+	if v != nil {
+		_c.SetCreatedAt(*v)
+	}
+	return _c
+}
+
+// SetFinishedAt sets the "finished_at" field.
+func (_c *ScheduledActionCreate) SetFinishedAt(v time.Time) *ScheduledActionCreate {
+	_c.mutation.SetFinishedAt(v)
+	return _c
+}
+
+// SetNillableFinishedAt sets the "finished_at" field if the given value is not nil.
+func (_c *ScheduledActionCreate) SetNillableFinishedAt(v *time.Time) *ScheduledActionCreate {
+	if v != nil {
+		_c.SetFinishedAt(*v)
+	}
+	return _c
+}
+
+// SetID sets the "id" field.
+func (_c *ScheduledActionCreate) SetID(v int64) *ScheduledActionCreate {
+	_c.mutation.SetID(v)
+	return _c
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (_c *ScheduledActionCreate) SetUser(v *User) *ScheduledActionCreate {
+	return _c.SetUserID(v.ID)
+}
+
+// Mutation returns the ScheduledActionMutation object of the builder.
+func (_c *ScheduledActionCreate) Mutation() *ScheduledActionMutation {
+	return _c.mutation
+}
+
+// Save creates the ScheduledAction in the database.
+func (_c *ScheduledActionCreate) Save(ctx context.Context) (*ScheduledAction, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *ScheduledActionCreate) SaveX(ctx context.Context) *ScheduledAction {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *ScheduledActionCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *ScheduledActionCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *ScheduledActionCreate) defaults() {
+	if _, ok := _c.mutation.Status(); !ok {
+		v := scheduledaction.DefaultStatus
+		_c.mutation.SetStatus(v)
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		v := scheduledaction.DefaultCreatedAt()
+		_c.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *ScheduledActionCreate) check() error {
+	if _, ok := _c.mutation.UserID(); !ok {
+		return &ValidationError{Name: "user_id", err: errors.New(`ent: missing required field "ScheduledAction.user_id"`)}
+	}
+	if _, ok := _c.mutation.Action(); !ok {
+		return &ValidationError{Name: "action", err: errors.New(`ent: missing required field "ScheduledAction.action"`)}
+	}
+	if v, ok := _c.mutation.Action(); ok {
+		if err := scheduledaction.ActionValidator(v); err != nil {
+			return &ValidationError{Name: "action", err: fmt.Errorf(`ent: validator failed for field "ScheduledAction.action": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.RunAt(); !ok {
+		return &ValidationError{Name: "run_at", err: errors.New(`ent: missing required field "ScheduledAction.run_at"`)}
+	}
+	if _, ok := _c.mutation.Status(); !ok {
+		return &ValidationError{Name: "status", err: errors.New(`ent: missing required field "ScheduledAction.status"`)}
+	}
+	if v, ok := _c.mutation.Status(); ok {
+		if err := scheduledaction.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "ScheduledAction.status": %w`, err)}
+		}
+	}
+	if v, ok := _c.mutation.Note(); ok {
+		if err := scheduledaction.NoteValidator(v); err != nil {
+			return &ValidationError{Name: "note", err: fmt.Errorf(`ent: validator failed for field "ScheduledAction.note": %w`, err)}
+		}
+	}
+	if v, ok := _c.mutation.Error(); ok {
+		if err := scheduledaction.ErrorValidator(v); err != nil {
+			return &ValidationError{Name: "error", err: fmt.Errorf(`ent: validator failed for field "ScheduledAction.error": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "ScheduledAction.created_at"`)}
+	}
+	if len(_c.mutation.UserIDs()) == 0 {
+		return &ValidationError{Name: "user", err: errors.New(`ent: missing required edge "ScheduledAction.user"`)}
+	}
+	return nil
+}
+
+func (_c *ScheduledActionCreate) sqlSave(ctx context.Context) (*ScheduledAction, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != _node.ID {
+		id := _spec.ID.Value.(int64)
+		_node.ID = int64(id)
+	}
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *ScheduledActionCreate) createSpec() (*ScheduledAction, *sqlgraph.CreateSpec) {
+	var (
+		_node = &ScheduledAction{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(scheduledaction.Table, sqlgraph.NewFieldSpec(scheduledaction.FieldID, field.TypeInt64))
+	)
+	if id, ok := _c.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = id
+	}
+	if value, ok := _c.mutation.Action(); ok {
+		_spec.SetField(scheduledaction.FieldAction, field.TypeEnum, value)
+		_node.Action = value
+	}
+	if value, ok := _c.mutation.RunAt(); ok {
+		_spec.SetField(scheduledaction.FieldRunAt, field.TypeTime, value)
+		_node.RunAt = value
+	}
+	if value, ok := _c.mutation.Status(); ok {
+		_spec.SetField(scheduledaction.FieldStatus, field.TypeEnum, value)
+		_node.Status = value
+	}
+	if value, ok := _c.mutation.Note(); ok {
+		_spec.SetField(scheduledaction.FieldNote, field.TypeString, value)
+		_node.Note = value
+	}
+	if value, ok := _c.mutation.CreatedBy(); ok {
+		_spec.SetField(scheduledaction.FieldCreatedBy, field.TypeUUID, value)
+		_node.CreatedBy = &value
+	}
+	if value, ok := _c.mutation.CancelledBy(); ok {
+		_spec.SetField(scheduledaction.FieldCancelledBy, field.TypeUUID, value)
+		_node.CancelledBy = &value
+	}
+	if value, ok := _c.mutation.Error(); ok {
+		_spec.SetField(scheduledaction.FieldError, field.TypeString, value)
+		_node.Error = value
+	}
+	if value, ok := _c.mutation.CreatedAt(); ok {
+		_spec.SetField(scheduledaction.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := _c.mutation.FinishedAt(); ok {
+		_spec.SetField(scheduledaction.FieldFinishedAt, field.TypeTime, value)
+		_node.FinishedAt = &value
+	}
+	if nodes := _c.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   scheduledaction.UserTable,
+			Columns: []string{scheduledaction.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.UserID = nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// ScheduledActionCreateBulk is the builder for creating many ScheduledAction entities in bulk.
+type ScheduledActionCreateBulk struct {
+	config
+	err      error
+	builders []*ScheduledActionCreate
+}
+
+// Save creates the ScheduledAction entities in the database.
+func (_c *ScheduledActionCreateBulk) Save(ctx context.Context) ([]*ScheduledAction, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*ScheduledAction, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*ScheduledActionMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil && nodes[i].ID == 0 {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int64(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *ScheduledActionCreateBulk) SaveX(ctx context.Context) []*ScheduledAction {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *ScheduledActionCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *ScheduledActionCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
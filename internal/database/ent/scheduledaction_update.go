@@ -0,0 +1,477 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/abisalde/authentication-service/internal/database/ent/predicate"
+	"github.com/abisalde/authentication-service/internal/database/ent/scheduledaction"
+	"github.com/google/uuid"
+)
+
+// ScheduledActionUpdate is the builder for updating ScheduledAction entities.
+type ScheduledActionUpdate struct {
+	config
+	hooks    []Hook
+	mutation *ScheduledActionMutation
+}
+
+// Where appends a list predicates to the ScheduledActionUpdate builder.
+func (_u *ScheduledActionUpdate) Where(ps ...predicate.ScheduledAction) *ScheduledActionUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetStatus sets the "status" field.
+func (_u *ScheduledActionUpdate) SetStatus(v scheduledaction.Status) *ScheduledActionUpdate {
+	_u.mutation.SetStatus(v)
+	return _u
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (_u *ScheduledActionUpdate) SetNillableStatus(v *scheduledaction.Status) *ScheduledActionUpdate {
+	if v != nil {
+		_u.SetStatus(*v)
+	}
+	return _u
+}
+
+// SetNote sets the "note" field.
+func (_u *ScheduledActionUpdate) SetNote(v string) *ScheduledActionUpdate {
+	_u.mutation.SetNote(v)
+	return _u
+}
+
+// SetNillableNote sets the "note" field if the given value is not nil.
+func (_u *ScheduledActionUpdate) SetNillableNote(v *string) *ScheduledActionUpdate {
+	if v != nil {
+		_u.SetNote(*v)
+	}
+	return _u
+}
+
+// ClearNote clears the value of the "note" field.
+func (_u *ScheduledActionUpdate) ClearNote() *ScheduledActionUpdate {
+	_u.mutation.ClearNote()
+	return _u
+}
+
+// SetCancelledBy sets the "cancelled_by" field.
+func (_u *ScheduledActionUpdate) SetCancelledBy(v uuid.UUID) *ScheduledActionUpdate {
+	_u.mutation.SetCancelledBy(v)
+	return _u
+}
+
+// SetNillableCancelledBy sets the "cancelled_by" field if the given value is not nil.
+func (_u *ScheduledActionUpdate) SetNillableCancelledBy(v *uuid.UUID) *ScheduledActionUpdate {
+	if v != nil {
+		_u.SetCancelledBy(*v)
+	}
+	return _u
+}
+
+// ClearCancelledBy clears the value of the "cancelled_by" field.
+func (_u *ScheduledActionUpdate) ClearCancelledBy() *ScheduledActionUpdate {
+	_u.mutation.ClearCancelledBy()
+	return _u
+}
+
+// SetError sets the "error" field.
+func (_u *ScheduledActionUpdate) SetError(v string) *ScheduledActionUpdate {
+	_u.mutation.SetError(v)
+	return _u
+}
+
+// SetNillableError sets the "error" field if the given value is not nil.
+func (_u *ScheduledActionUpdate) SetNillableError(v *string) *ScheduledActionUpdate {
+	if v != nil {
+		_u.SetError(*v)
+	}
+	return _u
+}
+
+// ClearError clears the value of the "error" field.
+func (_u *ScheduledActionUpdate) ClearError() *ScheduledActionUpdate {
+	_u.mutation.ClearError()
+	return _u
+}
+
+// SetFinishedAt sets the "finished_at" field.
+func (_u *ScheduledActionUpdate) SetFinishedAt(v time.Time) *ScheduledActionUpdate {
+	_u.mutation.SetFinishedAt(v)
+	return _u
+}
+
+// SetNillableFinishedAt sets the "finished_at" field if the given value is not nil.
+func (_u *ScheduledActionUpdate) SetNillableFinishedAt(v *time.Time) *ScheduledActionUpdate {
+	if v != nil {
+		_u.SetFinishedAt(*v)
+	}
+	return _u
+}
+
+// ClearFinishedAt clears the value of the "finished_at" field.
+func (_u *ScheduledActionUpdate) ClearFinishedAt() *ScheduledActionUpdate {
+	_u.mutation.ClearFinishedAt()
+	return _u
+}
+
+// Mutation returns the ScheduledActionMutation object of the builder.
+func (_u *ScheduledActionUpdate) Mutation() *ScheduledActionMutation {
+	return _u.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *ScheduledActionUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *ScheduledActionUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *ScheduledActionUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *ScheduledActionUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *ScheduledActionUpdate) check() error {
+	if v, ok := _u.mutation.Status(); ok {
+		if err := scheduledaction.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "ScheduledAction.status": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Note(); ok {
+		if err := scheduledaction.NoteValidator(v); err != nil {
+			return &ValidationError{Name: "note", err: fmt.Errorf(`ent: validator failed for field "ScheduledAction.note": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Error(); ok {
+		if err := scheduledaction.ErrorValidator(v); err != nil {
+			return &ValidationError{Name: "error", err: fmt.Errorf(`ent: validator failed for field "ScheduledAction.error": %w`, err)}
+		}
+	}
+	if _u.mutation.UserCleared() && len(_u.mutation.UserIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "ScheduledAction.user"`)
+	}
+	return nil
+}
+
+func (_u *ScheduledActionUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(scheduledaction.Table, scheduledaction.Columns, sqlgraph.NewFieldSpec(scheduledaction.FieldID, field.TypeInt64))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.Status(); ok {
+		_spec.SetField(scheduledaction.FieldStatus, field.TypeEnum, value)
+	}
+	if value, ok := _u.mutation.Note(); ok {
+		_spec.SetField(scheduledaction.FieldNote, field.TypeString, value)
+	}
+	if _u.mutation.NoteCleared() {
+		_spec.ClearField(scheduledaction.FieldNote, field.TypeString)
+	}
+	if _u.mutation.CreatedByCleared() {
+		_spec.ClearField(scheduledaction.FieldCreatedBy, field.TypeUUID)
+	}
+	if value, ok := _u.mutation.CancelledBy(); ok {
+		_spec.SetField(scheduledaction.FieldCancelledBy, field.TypeUUID, value)
+	}
+	if _u.mutation.CancelledByCleared() {
+		_spec.ClearField(scheduledaction.FieldCancelledBy, field.TypeUUID)
+	}
+	if value, ok := _u.mutation.Error(); ok {
+		_spec.SetField(scheduledaction.FieldError, field.TypeString, value)
+	}
+	if _u.mutation.ErrorCleared() {
+		_spec.ClearField(scheduledaction.FieldError, field.TypeString)
+	}
+	if value, ok := _u.mutation.FinishedAt(); ok {
+		_spec.SetField(scheduledaction.FieldFinishedAt, field.TypeTime, value)
+	}
+	if _u.mutation.FinishedAtCleared() {
+		_spec.ClearField(scheduledaction.FieldFinishedAt, field.TypeTime)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{scheduledaction.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// ScheduledActionUpdateOne is the builder for updating a single ScheduledAction entity.
+type ScheduledActionUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *ScheduledActionMutation
+}
+
+// SetStatus sets the "status" field.
+func (_u *ScheduledActionUpdateOne) SetStatus(v scheduledaction.Status) *ScheduledActionUpdateOne {
+	_u.mutation.SetStatus(v)
+	return _u
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (_u *ScheduledActionUpdateOne) SetNillableStatus(v *scheduledaction.Status) *ScheduledActionUpdateOne {
+	if v != nil {
+		_u.SetStatus(*v)
+	}
+	return _u
+}
+
+// SetNote sets the "note" field.
+func (_u *ScheduledActionUpdateOne) SetNote(v string) *ScheduledActionUpdateOne {
+	_u.mutation.SetNote(v)
+	return _u
+}
+
+// SetNillableNote sets the "note" field if the given value is not nil.
+func (_u *ScheduledActionUpdateOne) SetNillableNote(v *string) *ScheduledActionUpdateOne {
+	if v != nil {
+		_u.SetNote(*v)
+	}
+	return _u
+}
+
+// ClearNote clears the value of the "note" field.
+func (_u *ScheduledActionUpdateOne) ClearNote() *ScheduledActionUpdateOne {
+	_u.mutation.ClearNote()
+	return _u
+}
+
+// SetCancelledBy sets the "cancelled_by" field.
+func (_u *ScheduledActionUpdateOne) SetCancelledBy(v uuid.UUID) *ScheduledActionUpdateOne {
+	_u.mutation.SetCancelledBy(v)
+	return _u
+}
+
+// SetNillableCancelledBy sets the "cancelled_by" field if the given value is not nil.
+func (_u *ScheduledActionUpdateOne) SetNillableCancelledBy(v *uuid.UUID) *ScheduledActionUpdateOne {
+	if v != nil {
+		_u.SetCancelledBy(*v)
+	}
+	return _u
+}
+
+// ClearCancelledBy clears the value of the "cancelled_by" field.
+func (_u *ScheduledActionUpdateOne) ClearCancelledBy() *ScheduledActionUpdateOne {
+	_u.mutation.ClearCancelledBy()
+	return _u
+}
+
+// SetError sets the "error" field.
+func (_u *ScheduledActionUpdateOne) SetError(v string) *ScheduledActionUpdateOne {
+	_u.mutation.SetError(v)
+	return _u
+}
+
+// SetNillableError sets the "error" field if the given value is not nil.
+func (_u *ScheduledActionUpdateOne) SetNillableError(v *string) *ScheduledActionUpdateOne {
+	if v != nil {
+		_u.SetError(*v)
+	}
+	return _u
+}
+
+// ClearError clears the value of the "error" field.
+func (_u *ScheduledActionUpdateOne) ClearError() *ScheduledActionUpdateOne {
+	_u.mutation.ClearError()
+	return _u
+}
+
+// SetFinishedAt sets the "finished_at" field.
+func (_u *ScheduledActionUpdateOne) SetFinishedAt(v time.Time) *ScheduledActionUpdateOne {
+	_u.mutation.SetFinishedAt(v)
+	return _u
+}
+
+// SetNillableFinishedAt sets the "finished_at" field if the given value is not nil.
+func (_u *ScheduledActionUpdateOne) SetNillableFinishedAt(v *time.Time) *ScheduledActionUpdateOne {
+	if v != nil {
+		_u.SetFinishedAt(*v)
+	}
+	return _u
+}
+
+// ClearFinishedAt clears the value of the "finished_at" field.
+func (_u *ScheduledActionUpdateOne) ClearFinishedAt() *ScheduledActionUpdateOne {
+	_u.mutation.ClearFinishedAt()
+	return _u
+}
+
+// Mutation returns the ScheduledActionMutation object of the builder.
+func (_u *ScheduledActionUpdateOne) Mutation() *ScheduledActionMutation {
+	return _u.mutation
+}
+
+// Where appends a list predicates to the ScheduledActionUpdate builder.
+func (_u *ScheduledActionUpdateOne) Where(ps ...predicate.ScheduledAction) *ScheduledActionUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *ScheduledActionUpdateOne) Select(field string, fields ...string) *ScheduledActionUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated ScheduledAction entity.
+func (_u *ScheduledActionUpdateOne) Save(ctx context.Context) (*ScheduledAction, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *ScheduledActionUpdateOne) SaveX(ctx context.Context) *ScheduledAction {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *ScheduledActionUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *ScheduledActionUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *ScheduledActionUpdateOne) check() error {
+	if v, ok := _u.mutation.Status(); ok {
+		if err := scheduledaction.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "ScheduledAction.status": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Note(); ok {
+		if err := scheduledaction.NoteValidator(v); err != nil {
+			return &ValidationError{Name: "note", err: fmt.Errorf(`ent: validator failed for field "ScheduledAction.note": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Error(); ok {
+		if err := scheduledaction.ErrorValidator(v); err != nil {
+			return &ValidationError{Name: "error", err: fmt.Errorf(`ent: validator failed for field "ScheduledAction.error": %w`, err)}
+		}
+	}
+	if _u.mutation.UserCleared() && len(_u.mutation.UserIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "ScheduledAction.user"`)
+	}
+	return nil
+}
+
+func (_u *ScheduledActionUpdateOne) sqlSave(ctx context.Context) (_node *ScheduledAction, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(scheduledaction.Table, scheduledaction.Columns, sqlgraph.NewFieldSpec(scheduledaction.FieldID, field.TypeInt64))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "ScheduledAction.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, scheduledaction.FieldID)
+		for _, f := range fields {
+			if !scheduledaction.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != scheduledaction.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.Status(); ok {
+		_spec.SetField(scheduledaction.FieldStatus, field.TypeEnum, value)
+	}
+	if value, ok := _u.mutation.Note(); ok {
+		_spec.SetField(scheduledaction.FieldNote, field.TypeString, value)
+	}
+	if _u.mutation.NoteCleared() {
+		_spec.ClearField(scheduledaction.FieldNote, field.TypeString)
+	}
+	if _u.mutation.CreatedByCleared() {
+		_spec.ClearField(scheduledaction.FieldCreatedBy, field.TypeUUID)
+	}
+	if value, ok := _u.mutation.CancelledBy(); ok {
+		_spec.SetField(scheduledaction.FieldCancelledBy, field.TypeUUID, value)
+	}
+	if _u.mutation.CancelledByCleared() {
+		_spec.ClearField(scheduledaction.FieldCancelledBy, field.TypeUUID)
+	}
+	if value, ok := _u.mutation.Error(); ok {
+		_spec.SetField(scheduledaction.FieldError, field.TypeString, value)
+	}
+	if _u.mutation.ErrorCleared() {
+		_spec.ClearField(scheduledaction.FieldError, field.TypeString)
+	}
+	if value, ok := _u.mutation.FinishedAt(); ok {
+		_spec.SetField(scheduledaction.FieldFinishedAt, field.TypeTime, value)
+	}
+	if _u.mutation.FinishedAtCleared() {
+		_spec.ClearField(scheduledaction.FieldFinishedAt, field.TypeTime)
+	}
+	_node = &ScheduledAction{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{scheduledaction.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
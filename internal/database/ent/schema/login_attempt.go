@@ -0,0 +1,98 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/abisalde/authentication-service/pkg/useragent"
+)
+
+// LoginAttempt records every sign-in, successful or not. Attempts against
+// unknown emails have no user.
+type LoginAttempt struct {
+	ent.Schema
+}
+
+func (LoginAttempt) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int64("id").
+			Immutable(),
+
+		field.Int64("user_id").
+			Optional().
+			Nillable().
+			StructTag(`json:"userId"`),
+
+		field.String("email").
+			MaxLen(255),
+
+		field.String("ip").
+			Default("").
+			MaxLen(45),
+
+		field.String("device_id").
+			Default("").
+			MaxLen(128).
+			StructTag(`json:"deviceId"`),
+
+		field.String("platform").
+			Default("").
+			MaxLen(32),
+
+		field.String("user_agent").
+			Default("").
+			MaxLen(512).
+			StructTag(`json:"userAgent"`),
+
+		// Sec-CH-UA headers sent with the attempt, parsed again with the
+		// user agent when listing devices.
+		field.JSON("client_hints", useragent.ClientHints{}).
+			Optional().
+			StructTag(`json:"clientHints,omitempty"`),
+
+		field.Enum("method").
+			Values("PASSWORD", "OAUTH").
+			Default("PASSWORD"),
+
+		field.Enum("outcome").
+			Values("SUCCESS", "FAILURE"),
+
+		field.Enum("failure_reason").
+			Values("UNKNOWN_ACCOUNT", "INVALID_PASSWORD", "OAUTH_FAILED", "ACCOUNT_SUSPENDED", "INTERNAL_ERROR").
+			Optional().
+			Nillable().
+			StructTag(`json:"failureReason"`),
+
+		field.Int("risk_score").
+			Min(0).
+			Max(100).
+			Default(0).
+			StructTag(`json:"riskScore"`),
+
+		field.Time("created_at").
+			Immutable().
+			Default(time.Now).
+			StructTag(`json:"createdAt"`),
+	}
+}
+
+func (LoginAttempt) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("login_attempts").
+			Field("user_id").
+			Unique(),
+	}
+}
+
+func (LoginAttempt) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("user_id", "created_at"),
+		index.Fields("ip", "created_at"),
+		index.Fields("email", "created_at"),
+		index.Fields("created_at"),
+	}
+}
@@ -0,0 +1,69 @@
+package schema
+
+import (
+	"regexp"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// Organization is a tenant whose members see its branding, instead of the
+// default brand, in auth emails and on the pages they are redirected to.
+type Organization struct {
+	ent.Schema
+}
+
+func (Organization) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int64("id").
+			Immutable(),
+
+		field.String("slug").
+			Unique().
+			MaxLen(64).
+			Match(regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)),
+
+		field.String("name").
+			MaxLen(100),
+
+		// Branding. Empty values fall back to the default brand.
+		field.String("product_name").
+			Default("").
+			MaxLen(100).
+			StructTag(`json:"productName"`),
+
+		field.String("logo_url").
+			Default("").
+			MaxLen(512).
+			StructTag(`json:"logoUrl"`),
+
+		field.String("primary_color").
+			Default("").
+			MaxLen(7).
+			Match(regexp.MustCompile(`^(#[0-9a-fA-F]{6})?$`)).
+			StructTag(`json:"primaryColor"`),
+
+		field.String("support_email").
+			Default("").
+			MaxLen(255).
+			StructTag(`json:"supportEmail"`),
+
+		field.Time("created_at").
+			Immutable().
+			Default(time.Now).
+			StructTag(`json:"createdAt"`),
+
+		field.Time("updated_at").
+			Default(time.Now).
+			UpdateDefault(time.Now).
+			StructTag(`json:"updatedAt"`),
+	}
+}
+
+func (Organization) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("users", User.Type),
+	}
+}
@@ -0,0 +1,92 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+// ScheduledAction is an admin action on an account that runs at a later
+// date, such as deactivating it when a contract ends. The scheduler runs
+// due actions; a pending action can be cancelled until then.
+type ScheduledAction struct {
+	ent.Schema
+}
+
+func (ScheduledAction) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int64("id").
+			Immutable(),
+
+		field.Int64("user_id").
+			Immutable().
+			StructTag(`json:"userId"`),
+
+		field.Enum("action").
+			Values("DEACTIVATE", "REVOKE_SESSIONS", "DELETE").
+			Immutable(),
+
+		field.Time("run_at").
+			Immutable().
+			StructTag(`json:"runAt"`),
+
+		// RUNNING is set when the scheduler claims the action, so a
+		// cancellation cannot race its execution.
+		field.Enum("status").
+			Values("PENDING", "RUNNING", "COMPLETED", "FAILED", "CANCELLED").
+			Default("PENDING"),
+
+		field.String("note").
+			Optional().
+			MaxLen(500),
+
+		// Public IDs of the admins who scheduled and cancelled the action.
+		field.UUID("created_by", uuid.UUID{}).
+			Optional().
+			Nillable().
+			Immutable().
+			StructTag(`json:"createdBy"`),
+
+		field.UUID("cancelled_by", uuid.UUID{}).
+			Optional().
+			Nillable().
+			StructTag(`json:"cancelledBy"`),
+
+		// Why the action failed, empty otherwise.
+		field.String("error").
+			Optional().
+			MaxLen(500),
+
+		field.Time("created_at").
+			Immutable().
+			Default(time.Now).
+			StructTag(`json:"createdAt"`),
+
+		field.Time("finished_at").
+			Optional().
+			Nillable().
+			StructTag(`json:"finishedAt"`),
+	}
+}
+
+func (ScheduledAction) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("scheduled_actions").
+			Field("user_id").
+			Immutable().
+			Unique().
+			Required(),
+	}
+}
+
+func (ScheduledAction) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("status", "run_at"),
+		index.Fields("user_id", "run_at"),
+	}
+}
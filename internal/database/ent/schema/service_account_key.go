@@ -0,0 +1,66 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// ServiceAccountKey is a credential a service account trades for access
+// tokens. Only a hash of the secret is kept; the secret is shown once.
+type ServiceAccountKey struct {
+	ent.Schema
+}
+
+func (ServiceAccountKey) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int64("id").
+			Immutable(),
+
+		field.Int64("user_id").
+			Immutable().
+			StructTag(`json:"userId"`),
+
+		// Public half of the key, sent as the client_id.
+		field.String("key_id").
+			Unique().
+			Immutable().
+			MaxLen(32).
+			StructTag(`json:"keyId"`),
+
+		field.String("secret_hash").
+			Sensitive().
+			Immutable().
+			MaxLen(64),
+
+		field.Time("created_at").
+			Immutable().
+			Default(time.Now).
+			StructTag(`json:"createdAt"`),
+
+		// Set when the key is rotated out; it keeps working until then so
+		// deployments can switch to the new key.
+		field.Time("expires_at").
+			Optional().
+			Nillable().
+			StructTag(`json:"expiresAt"`),
+
+		field.Time("last_used_at").
+			Optional().
+			Nillable().
+			StructTag(`json:"lastUsedAt"`),
+	}
+}
+
+func (ServiceAccountKey) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("owner", User.Type).
+			Ref("service_account_keys").
+			Field("user_id").
+			Immutable().
+			Unique().
+			Required(),
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"entgo.io/ent/schema/field"
 	"entgo.io/ent/schema/index"
 	"entgo.io/ent/schema/mixin"
+	"github.com/google/uuid"
 )
 
 type UserAddressMixin struct {
@@ -86,6 +87,12 @@ func (User) Fields() []ent.Field {
 			Immutable().
 			StorageKey("id"),
 
+		field.UUID("public_id", uuid.UUID{}).
+			Default(uuid.New).
+			Unique().
+			Immutable().
+			StructTag(`json:"publicId"`),
+
 		field.String("email").
 			Unique().
 			Match(regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)),
@@ -101,13 +108,20 @@ func (User) Fields() []ent.Field {
 			Sensitive().
 			Optional(),
 
+		// Null on accounts without a password and on those whose password
+		// was last set before the column existed.
+		field.Time("password_changed_at").
+			Optional().
+			Nillable().
+			StructTag(`json:"passwordChangedAt"`),
+
 		field.String("oauth_id").
 			Optional().
 			MaxLen(255).
 			StructTag(`json:"oauthId"`),
 
 		field.Enum("provider").
-			Values("GOOGLE", "FACEBOOK", "EMAIL").
+			Values("GOOGLE", "FACEBOOK", "EMAIL", "APPLE", "GITHUB", "MICROSOFT", "OAUTH").
 			Default("EMAIL"),
 
 		field.String("first_name").
@@ -140,10 +154,60 @@ func (User) Fields() []ent.Field {
 			Nillable().
 			StructTag(`json:"termsAcceptedAt"`),
 
+		field.String("terms_version").
+			Optional().
+			MaxLen(32).
+			StructTag(`json:"termsVersion"`),
+
 		field.Time("last_login_at").
 			Optional().
 			Nillable().
 			StructTag(`json:"lastLoginAt"`),
+
+		field.Enum("onboarding_step").
+			Values("VERIFY_EMAIL", "COMPLETE_PROFILE", "ACCEPT_TERMS", "DONE").
+			Default("VERIFY_EMAIL").
+			StructTag(`json:"onboardingStep"`),
+
+		// Suspension is separate from deleted_at: the account keeps its data
+		// and can be reinstated, but cannot sign in until an admin lifts it.
+		field.Time("suspended_at").
+			Optional().
+			Nillable().
+			StructTag(`json:"suspendedAt"`),
+
+		field.Enum("suspension_reason").
+			Values("TERMS_VIOLATION", "FRAUD", "ABUSE", "SPAM", "SECURITY", "OTHER").
+			Optional().
+			Nillable().
+			StructTag(`json:"suspensionReason"`),
+
+		// Residency is the data region, e.g. "eu", whose database and Redis
+		// hold the account's PII and sessions. Empty on accounts created
+		// before residency was configured.
+		field.String("residency").
+			Optional().
+			MaxLen(16).
+			StructTag(`json:"residency"`),
+
+		// Deactivation is the owner's own pause, separate from deleted_at: the
+		// account is signed out and hidden until the owner signs in again.
+		field.Time("deactivated_at").
+			Optional().
+			Nillable().
+			StructTag(`json:"deactivatedAt"`),
+
+		// Organization whose branding the account's emails use.
+		field.Int64("organization_id").
+			Optional().
+			Nillable().
+			StructTag(`json:"organizationId"`),
+
+		// Service accounts belong to an organization's automations. They
+		// authenticate with keys only: no password, no email.
+		field.Enum("kind").
+			Values("HUMAN", "SERVICE").
+			Default("HUMAN"),
 	}
 }
 
@@ -153,6 +217,23 @@ func (User) Edges() []ent.Edge {
 		edge.To("address", UserAddress.Type).
 			Unique().
 			StructTag(`json:"address"`),
+
+		edge.To("login_attempts", LoginAttempt.Type).
+			StructTag(`json:"loginAttempts"`),
+
+		edge.From("organization", Organization.Type).
+			Ref("users").
+			Field("organization_id").
+			Unique(),
+
+		edge.To("service_account_keys", ServiceAccountKey.Type).
+			StructTag(`json:"serviceAccountKeys"`),
+
+		edge.To("identities", UserIdentity.Type).
+			StructTag(`json:"identities"`),
+
+		edge.To("scheduled_actions", ScheduledAction.Type).
+			StructTag(`json:"scheduledActions"`),
 	}
 }
 
@@ -163,5 +244,7 @@ func (User) Indexes() []ent.Index {
 		index.Fields("oauth_id", "provider").Unique(),
 		index.Fields("last_login_at"),
 		index.Fields("is_email_verified"),
+		index.Fields("onboarding_step"),
+		index.Fields("residency"),
 	}
 }
@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// UserIdentity is an OAuth account linked to a user. A user may have one
+// per provider account; the provider and oauth_id columns on users only
+// record the one the account was created with.
+type UserIdentity struct {
+	ent.Schema
+}
+
+func (UserIdentity) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int64("id").
+			Immutable(),
+
+		field.Int64("user_id").
+			Immutable().
+			StructTag(`json:"userId"`),
+
+		field.Enum("provider").
+			Values("GOOGLE", "FACEBOOK", "APPLE", "GITHUB", "MICROSOFT", "OAUTH").
+			Immutable(),
+
+		// Subject at the provider. Configured providers share the OAUTH
+		// provider, so theirs is prefixed with the provider name.
+		field.String("oauth_id").
+			Immutable().
+			MaxLen(255).
+			StructTag(`json:"oauthId"`),
+
+		// Email the provider reported when the account was linked.
+		field.String("email").
+			Optional().
+			MaxLen(255),
+
+		field.Time("created_at").
+			Immutable().
+			Default(time.Now).
+			StructTag(`json:"createdAt"`),
+
+		field.Time("last_used_at").
+			Optional().
+			Nillable().
+			StructTag(`json:"lastUsedAt"`),
+	}
+}
+
+func (UserIdentity) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("owner", User.Type).
+			Ref("identities").
+			Field("user_id").
+			Immutable().
+			Unique().
+			Required(),
+	}
+}
+
+func (UserIdentity) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("provider", "oauth_id").Unique(),
+	}
+}
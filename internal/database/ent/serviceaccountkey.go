@@ -0,0 +1,195 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/abisalde/authentication-service/internal/database/ent/serviceaccountkey"
+	"github.com/abisalde/authentication-service/internal/database/ent/user"
+)
+
+// ServiceAccountKey is the model entity for the ServiceAccountKey schema.
+type ServiceAccountKey struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int64 `json:"id,omitempty"`
+	// UserID holds the value of the "user_id" field.
+	UserID int64 `json:"userId"`
+	// KeyID holds the value of the "key_id" field.
+	KeyID string `json:"keyId"`
+	// SecretHash holds the value of the "secret_hash" field.
+	SecretHash string `json:"-"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"createdAt"`
+	// ExpiresAt holds the value of the "expires_at" field.
+	ExpiresAt *time.Time `json:"expiresAt"`
+	// LastUsedAt holds the value of the "last_used_at" field.
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	// Edges holds the relations/edges for other nodes in the graph.
+	// The values are being populated by the ServiceAccountKeyQuery when eager-loading is set.
+	Edges        ServiceAccountKeyEdges `json:"edges"`
+	selectValues sql.SelectValues
+}
+
+// ServiceAccountKeyEdges holds the relations/edges for other nodes in the graph.
+type ServiceAccountKeyEdges struct {
+	// Owner holds the value of the owner edge.
+	Owner *User `json:"owner,omitempty"`
+	// loadedTypes holds the information for reporting if a
+	// type was loaded (or requested) in eager-loading or not.
+	loadedTypes [1]bool
+}
+
+// OwnerOrErr returns the Owner value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e ServiceAccountKeyEdges) OwnerOrErr() (*User, error) {
+	if e.Owner != nil {
+		return e.Owner, nil
+	} else if e.loadedTypes[0] {
+		return nil, &NotFoundError{label: user.Label}
+	}
+	return nil, &NotLoadedError{edge: "owner"}
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*ServiceAccountKey) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case serviceaccountkey.FieldID, serviceaccountkey.FieldUserID:
+			values[i] = new(sql.NullInt64)
+		case serviceaccountkey.FieldKeyID, serviceaccountkey.FieldSecretHash:
+			values[i] = new(sql.NullString)
+		case serviceaccountkey.FieldCreatedAt, serviceaccountkey.FieldExpiresAt, serviceaccountkey.FieldLastUsedAt:
+			values[i] = new(sql.NullTime)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the ServiceAccountKey fields.
+func (_m *ServiceAccountKey) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case serviceaccountkey.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			_m.ID = int64(value.Int64)
+		case serviceaccountkey.FieldUserID:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field user_id", values[i])
+			} else if value.Valid {
+				_m.UserID = value.Int64
+			}
+		case serviceaccountkey.FieldKeyID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field key_id", values[i])
+			} else if value.Valid {
+				_m.KeyID = value.String
+			}
+		case serviceaccountkey.FieldSecretHash:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field secret_hash", values[i])
+			} else if value.Valid {
+				_m.SecretHash = value.String
+			}
+		case serviceaccountkey.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				_m.CreatedAt = value.Time
+			}
+		case serviceaccountkey.FieldExpiresAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field expires_at", values[i])
+			} else if value.Valid {
+				_m.ExpiresAt = new(time.Time)
+				*_m.ExpiresAt = value.Time
+			}
+		case serviceaccountkey.FieldLastUsedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field last_used_at", values[i])
+			} else if value.Valid {
+				_m.LastUsedAt = new(time.Time)
+				*_m.LastUsedAt = value.Time
+			}
+		default:
+			_m.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the ServiceAccountKey.
+// This includes values selected through modifiers, order, etc.
+func (_m *ServiceAccountKey) Value(name string) (ent.Value, error) {
+	return _m.selectValues.Get(name)
+}
+
+// QueryOwner queries the "owner" edge of the ServiceAccountKey entity.
+func (_m *ServiceAccountKey) QueryOwner() *UserQuery {
+	return NewServiceAccountKeyClient(_m.config).QueryOwner(_m)
+}
+
+// Update returns a builder for updating this ServiceAccountKey.
+// Note that you need to call ServiceAccountKey.Unwrap() before calling this method if this ServiceAccountKey
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (_m *ServiceAccountKey) Update() *ServiceAccountKeyUpdateOne {
+	return NewServiceAccountKeyClient(_m.config).UpdateOne(_m)
+}
+
+// Unwrap unwraps the ServiceAccountKey entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (_m *ServiceAccountKey) Unwrap() *ServiceAccountKey {
+	_tx, ok := _m.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: ServiceAccountKey is not a transactional entity")
+	}
+	_m.config.driver = _tx.drv
+	return _m
+}
+
+// String implements the fmt.Stringer.
+func (_m *ServiceAccountKey) String() string {
+	var builder strings.Builder
+	builder.WriteString("ServiceAccountKey(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", _m.ID))
+	builder.WriteString("user_id=")
+	builder.WriteString(fmt.Sprintf("%v", _m.UserID))
+	builder.WriteString(", ")
+	builder.WriteString("key_id=")
+	builder.WriteString(_m.KeyID)
+	builder.WriteString(", ")
+	builder.WriteString("secret_hash=<sensitive>")
+	builder.WriteString(", ")
+	builder.WriteString("created_at=")
+	builder.WriteString(_m.CreatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	if v := _m.ExpiresAt; v != nil {
+		builder.WriteString("expires_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	if v := _m.LastUsedAt; v != nil {
+		builder.WriteString("last_used_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// ServiceAccountKeys is a parsable slice of ServiceAccountKey.
+type ServiceAccountKeys []*ServiceAccountKey
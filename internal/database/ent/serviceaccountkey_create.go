@@ -0,0 +1,327 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/abisalde/authentication-service/internal/database/ent/serviceaccountkey"
+	"github.com/abisalde/authentication-service/internal/database/ent/user"
+)
+
+// ServiceAccountKeyCreate is the builder for creating a ServiceAccountKey entity.
+type ServiceAccountKeyCreate struct {
+	config
+	mutation *ServiceAccountKeyMutation
+	hooks    []Hook
+}
+
+// SetUserID sets the "user_id" field.
+func (_c *ServiceAccountKeyCreate) SetUserID(v int64) *ServiceAccountKeyCreate {
+	_c.mutation.SetUserID(v)
+	return _c
+}
+
+// SetKeyID sets the "key_id" field.
+func (_c *ServiceAccountKeyCreate) SetKeyID(v string) *ServiceAccountKeyCreate {
+	_c.mutation.SetKeyID(v)
+	return _c
+}
+
+// SetSecretHash sets the "secret_hash" field.
+func (_c *ServiceAccountKeyCreate) SetSecretHash(v string) *ServiceAccountKeyCreate {
+	_c.mutation.SetSecretHash(v)
+	return _c
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (_c *ServiceAccountKeyCreate) SetCreatedAt(v time.Time) *ServiceAccountKeyCreate {
+	_c.mutation.SetCreatedAt(v)
+	return _c
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (_c *ServiceAccountKeyCreate) SetNillableCreatedAt(v *time.Time) *ServiceAccountKeyCreate {
+	if v != nil {
+		_c.SetCreatedAt(*v)
+	}
+	return _c
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (_c *ServiceAccountKeyCreate) SetExpiresAt(v time.Time) *ServiceAccountKeyCreate {
+	_c.mutation.SetExpiresAt(v)
+	return _c
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (_c *ServiceAccountKeyCreate) SetNillableExpiresAt(v *time.Time) *ServiceAccountKeyCreate {
+	if v != nil {
+		_c.SetExpiresAt(*v)
+	}
+	return _c
+}
+
+// SetLastUsedAt sets the "last_used_at" field.
+func (_c *ServiceAccountKeyCreate) SetLastUsedAt(v time.Time) *ServiceAccountKeyCreate {
+	_c.mutation.SetLastUsedAt(v)
+	return _c
+}
+
+// SetNillableLastUsedAt sets the "last_used_at" field if the given value is not nil.
+func (_c *ServiceAccountKeyCreate) SetNillableLastUsedAt(v *time.Time) *ServiceAccountKeyCreate {
+	if v != nil {
+		_c.SetLastUsedAt(*v)
+	}
+	return _c
+}
+
+// SetID sets the "id" field.
+func (_c *ServiceAccountKeyCreate) SetID(v int64) *ServiceAccountKeyCreate {
+	_c.mutation.SetID(v)
+	return _c
+}
+
+// SetOwnerID sets the "owner" edge to the User entity by ID.
+func (_c *ServiceAccountKeyCreate) SetOwnerID(id int64) *ServiceAccountKeyCreate {
+	_c.mutation.SetOwnerID(id)
+	return _c
+}
+
+// SetOwner sets the "owner" edge to the User entity.
+func (_c *ServiceAccountKeyCreate) SetOwner(v *User) *ServiceAccountKeyCreate {
+	return _c.SetOwnerID(v.ID)
+}
+
+// Mutation returns the ServiceAccountKeyMutation object of the builder.
+func (_c *ServiceAccountKeyCreate) Mutation() *ServiceAccountKeyMutation {
+	return _c.mutation
+}
+
+// Save creates the ServiceAccountKey in the database.
+func (_c *ServiceAccountKeyCreate) Save(ctx context.Context) (*ServiceAccountKey, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *ServiceAccountKeyCreate) SaveX(ctx context.Context) *ServiceAccountKey {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *ServiceAccountKeyCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *ServiceAccountKeyCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *ServiceAccountKeyCreate) defaults() {
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		v := serviceaccountkey.DefaultCreatedAt()
+		_c.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *ServiceAccountKeyCreate) check() error {
+	if _, ok := _c.mutation.UserID(); !ok {
+		return &ValidationError{Name: "user_id", err: errors.New(`ent: missing required field "ServiceAccountKey.user_id"`)}
+	}
+	if _, ok := _c.mutation.KeyID(); !ok {
+		return &ValidationError{Name: "key_id", err: errors.New(`ent: missing required field "ServiceAccountKey.key_id"`)}
+	}
+	if v, ok := _c.mutation.KeyID(); ok {
+		if err := serviceaccountkey.KeyIDValidator(v); err != nil {
+			return &ValidationError{Name: "key_id", err: fmt.Errorf(`ent: validator failed for field "ServiceAccountKey.key_id": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.SecretHash(); !ok {
+		return &ValidationError{Name: "secret_hash", err: errors.New(`ent: missing required field "ServiceAccountKey.secret_hash"`)}
+	}
+	if v, ok := _c.mutation.SecretHash(); ok {
+		if err := serviceaccountkey.SecretHashValidator(v); err != nil {
+			return &ValidationError{Name: "secret_hash", err: fmt.Errorf(`ent: validator failed for field "ServiceAccountKey.secret_hash": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "ServiceAccountKey.created_at"`)}
+	}
+	if len(_c.mutation.OwnerIDs()) == 0 {
+		return &ValidationError{Name: "owner", err: errors.New(`ent: missing required edge "ServiceAccountKey.owner"`)}
+	}
+	return nil
+}
+
+func (_c *ServiceAccountKeyCreate) sqlSave(ctx context.Context) (*ServiceAccountKey, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != _node.ID {
+		id := _spec.ID.Value.(int64)
+		_node.ID = int64(id)
+	}
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *ServiceAccountKeyCreate) createSpec() (*ServiceAccountKey, *sqlgraph.CreateSpec) {
+	var (
+		_node = &ServiceAccountKey{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(serviceaccountkey.Table, sqlgraph.NewFieldSpec(serviceaccountkey.FieldID, field.TypeInt64))
+	)
+	if id, ok := _c.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = id
+	}
+	if value, ok := _c.mutation.KeyID(); ok {
+		_spec.SetField(serviceaccountkey.FieldKeyID, field.TypeString, value)
+		_node.KeyID = value
+	}
+	if value, ok := _c.mutation.SecretHash(); ok {
+		_spec.SetField(serviceaccountkey.FieldSecretHash, field.TypeString, value)
+		_node.SecretHash = value
+	}
+	if value, ok := _c.mutation.CreatedAt(); ok {
+		_spec.SetField(serviceaccountkey.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := _c.mutation.ExpiresAt(); ok {
+		_spec.SetField(serviceaccountkey.FieldExpiresAt, field.TypeTime, value)
+		_node.ExpiresAt = &value
+	}
+	if value, ok := _c.mutation.LastUsedAt(); ok {
+		_spec.SetField(serviceaccountkey.FieldLastUsedAt, field.TypeTime, value)
+		_node.LastUsedAt = &value
+	}
+	if nodes := _c.mutation.OwnerIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   serviceaccountkey.OwnerTable,
+			Columns: []string{serviceaccountkey.OwnerColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.UserID = nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// ServiceAccountKeyCreateBulk is the builder for creating many ServiceAccountKey entities in bulk.
+type ServiceAccountKeyCreateBulk struct {
+	config
+	err      error
+	builders []*ServiceAccountKeyCreate
+}
+
+// Save creates the ServiceAccountKey entities in the database.
+func (_c *ServiceAccountKeyCreateBulk) Save(ctx context.Context) ([]*ServiceAccountKey, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*ServiceAccountKey, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*ServiceAccountKeyMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil && nodes[i].ID == 0 {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int64(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *ServiceAccountKeyCreateBulk) SaveX(ctx context.Context) []*ServiceAccountKey {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *ServiceAccountKeyCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *ServiceAccountKeyCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
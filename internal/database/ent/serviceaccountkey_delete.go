@@ -0,0 +1,88 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/abisalde/authentication-service/internal/database/ent/predicate"
+	"github.com/abisalde/authentication-service/internal/database/ent/serviceaccountkey"
+)
+
+// ServiceAccountKeyDelete is the builder for deleting a ServiceAccountKey entity.
+type ServiceAccountKeyDelete struct {
+	config
+	hooks    []Hook
+	mutation *ServiceAccountKeyMutation
+}
+
+// Where appends a list predicates to the ServiceAccountKeyDelete builder.
+func (_d *ServiceAccountKeyDelete) Where(ps ...predicate.ServiceAccountKey) *ServiceAccountKeyDelete {
+	_d.mutation.Where(ps...)
+	return _d
+}
+
+// Exec executes the deletion query and returns how many vertices were deleted.
+func (_d *ServiceAccountKeyDelete) Exec(ctx context.Context) (int, error) {
+	return withHooks(ctx, _d.sqlExec, _d.mutation, _d.hooks)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_d *ServiceAccountKeyDelete) ExecX(ctx context.Context) int {
+	n, err := _d.Exec(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func (_d *ServiceAccountKeyDelete) sqlExec(ctx context.Context) (int, error) {
+	_spec := sqlgraph.NewDeleteSpec(serviceaccountkey.Table, sqlgraph.NewFieldSpec(serviceaccountkey.FieldID, field.TypeInt64))
+	if ps := _d.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	affected, err := sqlgraph.DeleteNodes(ctx, _d.driver, _spec)
+	if err != nil && sqlgraph.IsConstraintError(err) {
+		err = &ConstraintError{msg: err.Error(), wrap: err}
+	}
+	_d.mutation.done = true
+	return affected, err
+}
+
+// ServiceAccountKeyDeleteOne is the builder for deleting a single ServiceAccountKey entity.
+type ServiceAccountKeyDeleteOne struct {
+	_d *ServiceAccountKeyDelete
+}
+
+// Where appends a list predicates to the ServiceAccountKeyDelete builder.
+func (_d *ServiceAccountKeyDeleteOne) Where(ps ...predicate.ServiceAccountKey) *ServiceAccountKeyDeleteOne {
+	_d._d.mutation.Where(ps...)
+	return _d
+}
+
+// Exec executes the deletion query.
+func (_d *ServiceAccountKeyDeleteOne) Exec(ctx context.Context) error {
+	n, err := _d._d.Exec(ctx)
+	switch {
+	case err != nil:
+		return err
+	case n == 0:
+		return &NotFoundError{serviceaccountkey.Label}
+	default:
+		return nil
+	}
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_d *ServiceAccountKeyDeleteOne) ExecX(ctx context.Context) {
+	if err := _d.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
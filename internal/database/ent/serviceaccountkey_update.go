@@ -0,0 +1,302 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/abisalde/authentication-service/internal/database/ent/predicate"
+	"github.com/abisalde/authentication-service/internal/database/ent/serviceaccountkey"
+)
+
+// ServiceAccountKeyUpdate is the builder for updating ServiceAccountKey entities.
+type ServiceAccountKeyUpdate struct {
+	config
+	hooks    []Hook
+	mutation *ServiceAccountKeyMutation
+}
+
+// Where appends a list predicates to the ServiceAccountKeyUpdate builder.
+func (_u *ServiceAccountKeyUpdate) Where(ps ...predicate.ServiceAccountKey) *ServiceAccountKeyUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (_u *ServiceAccountKeyUpdate) SetExpiresAt(v time.Time) *ServiceAccountKeyUpdate {
+	_u.mutation.SetExpiresAt(v)
+	return _u
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (_u *ServiceAccountKeyUpdate) SetNillableExpiresAt(v *time.Time) *ServiceAccountKeyUpdate {
+	if v != nil {
+		_u.SetExpiresAt(*v)
+	}
+	return _u
+}
+
+// ClearExpiresAt clears the value of the "expires_at" field.
+func (_u *ServiceAccountKeyUpdate) ClearExpiresAt() *ServiceAccountKeyUpdate {
+	_u.mutation.ClearExpiresAt()
+	return _u
+}
+
+// SetLastUsedAt sets the "last_used_at" field.
+func (_u *ServiceAccountKeyUpdate) SetLastUsedAt(v time.Time) *ServiceAccountKeyUpdate {
+	_u.mutation.SetLastUsedAt(v)
+	return _u
+}
+
+// SetNillableLastUsedAt sets the "last_used_at" field if the given value is not nil.
+func (_u *ServiceAccountKeyUpdate) SetNillableLastUsedAt(v *time.Time) *ServiceAccountKeyUpdate {
+	if v != nil {
+		_u.SetLastUsedAt(*v)
+	}
+	return _u
+}
+
+// ClearLastUsedAt clears the value of the "last_used_at" field.
+func (_u *ServiceAccountKeyUpdate) ClearLastUsedAt() *ServiceAccountKeyUpdate {
+	_u.mutation.ClearLastUsedAt()
+	return _u
+}
+
+// Mutation returns the ServiceAccountKeyMutation object of the builder.
+func (_u *ServiceAccountKeyUpdate) Mutation() *ServiceAccountKeyMutation {
+	return _u.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *ServiceAccountKeyUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *ServiceAccountKeyUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *ServiceAccountKeyUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *ServiceAccountKeyUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *ServiceAccountKeyUpdate) check() error {
+	if _u.mutation.OwnerCleared() && len(_u.mutation.OwnerIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "ServiceAccountKey.owner"`)
+	}
+	return nil
+}
+
+func (_u *ServiceAccountKeyUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(serviceaccountkey.Table, serviceaccountkey.Columns, sqlgraph.NewFieldSpec(serviceaccountkey.FieldID, field.TypeInt64))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.ExpiresAt(); ok {
+		_spec.SetField(serviceaccountkey.FieldExpiresAt, field.TypeTime, value)
+	}
+	if _u.mutation.ExpiresAtCleared() {
+		_spec.ClearField(serviceaccountkey.FieldExpiresAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.LastUsedAt(); ok {
+		_spec.SetField(serviceaccountkey.FieldLastUsedAt, field.TypeTime, value)
+	}
+	if _u.mutation.LastUsedAtCleared() {
+		_spec.ClearField(serviceaccountkey.FieldLastUsedAt, field.TypeTime)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{serviceaccountkey.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// ServiceAccountKeyUpdateOne is the builder for updating a single ServiceAccountKey entity.
+type ServiceAccountKeyUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *ServiceAccountKeyMutation
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (_u *ServiceAccountKeyUpdateOne) SetExpiresAt(v time.Time) *ServiceAccountKeyUpdateOne {
+	_u.mutation.SetExpiresAt(v)
+	return _u
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (_u *ServiceAccountKeyUpdateOne) SetNillableExpiresAt(v *time.Time) *ServiceAccountKeyUpdateOne {
+	if v != nil {
+		_u.SetExpiresAt(*v)
+	}
+	return _u
+}
+
+// ClearExpiresAt clears the value of the "expires_at" field.
+func (_u *ServiceAccountKeyUpdateOne) ClearExpiresAt() *ServiceAccountKeyUpdateOne {
+	_u.mutation.ClearExpiresAt()
+	return _u
+}
+
+// SetLastUsedAt sets the "last_used_at" field.
+func (_u *ServiceAccountKeyUpdateOne) SetLastUsedAt(v time.Time) *ServiceAccountKeyUpdateOne {
+	_u.mutation.SetLastUsedAt(v)
+	return _u
+}
+
+// SetNillableLastUsedAt sets the "last_used_at" field if the given value is not nil.
+func (_u *ServiceAccountKeyUpdateOne) SetNillableLastUsedAt(v *time.Time) *ServiceAccountKeyUpdateOne {
+	if v != nil {
+		_u.SetLastUsedAt(*v)
+	}
+	return _u
+}
+
+// ClearLastUsedAt clears the value of the "last_used_at" field.
+func (_u *ServiceAccountKeyUpdateOne) ClearLastUsedAt() *ServiceAccountKeyUpdateOne {
+	_u.mutation.ClearLastUsedAt()
+	return _u
+}
+
+// Mutation returns the ServiceAccountKeyMutation object of the builder.
+func (_u *ServiceAccountKeyUpdateOne) Mutation() *ServiceAccountKeyMutation {
+	return _u.mutation
+}
+
+// Where appends a list predicates to the ServiceAccountKeyUpdate builder.
+func (_u *ServiceAccountKeyUpdateOne) Where(ps ...predicate.ServiceAccountKey) *ServiceAccountKeyUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *ServiceAccountKeyUpdateOne) Select(field string, fields ...string) *ServiceAccountKeyUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated ServiceAccountKey entity.
+func (_u *ServiceAccountKeyUpdateOne) Save(ctx context.Context) (*ServiceAccountKey, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *ServiceAccountKeyUpdateOne) SaveX(ctx context.Context) *ServiceAccountKey {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *ServiceAccountKeyUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *ServiceAccountKeyUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *ServiceAccountKeyUpdateOne) check() error {
+	if _u.mutation.OwnerCleared() && len(_u.mutation.OwnerIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "ServiceAccountKey.owner"`)
+	}
+	return nil
+}
+
+func (_u *ServiceAccountKeyUpdateOne) sqlSave(ctx context.Context) (_node *ServiceAccountKey, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(serviceaccountkey.Table, serviceaccountkey.Columns, sqlgraph.NewFieldSpec(serviceaccountkey.FieldID, field.TypeInt64))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "ServiceAccountKey.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, serviceaccountkey.FieldID)
+		for _, f := range fields {
+			if !serviceaccountkey.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != serviceaccountkey.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.ExpiresAt(); ok {
+		_spec.SetField(serviceaccountkey.FieldExpiresAt, field.TypeTime, value)
+	}
+	if _u.mutation.ExpiresAtCleared() {
+		_spec.ClearField(serviceaccountkey.FieldExpiresAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.LastUsedAt(); ok {
+		_spec.SetField(serviceaccountkey.FieldLastUsedAt, field.TypeTime, value)
+	}
+	if _u.mutation.LastUsedAtCleared() {
+		_spec.ClearField(serviceaccountkey.FieldLastUsedAt, field.TypeTime)
+	}
+	_node = &ServiceAccountKey{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{serviceaccountkey.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
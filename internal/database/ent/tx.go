@@ -12,10 +12,20 @@ import (
 // Tx is a transactional client that is created by calling Client.Tx().
 type Tx struct {
 	config
+	// LoginAttempt is the client for interacting with the LoginAttempt builders.
+	LoginAttempt *LoginAttemptClient
+	// Organization is the client for interacting with the Organization builders.
+	Organization *OrganizationClient
+	// ScheduledAction is the client for interacting with the ScheduledAction builders.
+	ScheduledAction *ScheduledActionClient
+	// ServiceAccountKey is the client for interacting with the ServiceAccountKey builders.
+	ServiceAccountKey *ServiceAccountKeyClient
 	// User is the client for interacting with the User builders.
 	User *UserClient
 	// UserAddress is the client for interacting with the UserAddress builders.
 	UserAddress *UserAddressClient
+	// UserIdentity is the client for interacting with the UserIdentity builders.
+	UserIdentity *UserIdentityClient
 
 	// lazily loaded.
 	client     *Client
@@ -147,8 +157,13 @@ func (tx *Tx) Client() *Client {
 }
 
 func (tx *Tx) init() {
+	tx.LoginAttempt = NewLoginAttemptClient(tx.config)
+	tx.Organization = NewOrganizationClient(tx.config)
+	tx.ScheduledAction = NewScheduledActionClient(tx.config)
+	tx.ServiceAccountKey = NewServiceAccountKeyClient(tx.config)
 	tx.User = NewUserClient(tx.config)
 	tx.UserAddress = NewUserAddressClient(tx.config)
+	tx.UserIdentity = NewUserIdentityClient(tx.config)
 }
 
 // txDriver wraps the given dialect.Tx with a nop dialect.Driver implementation.
@@ -158,7 +173,7 @@ func (tx *Tx) init() {
 // of them in order to commit or rollback the transaction.
 //
 // If a closed transaction is embedded in one of the generated entities, and the entity
-// applies a query, for example: User.QueryXXX(), the query will be executed
+// applies a query, for example: LoginAttempt.QueryXXX(), the query will be executed
 // through the driver which created this transaction.
 //
 // Note that txDriver is not goroutine safe.
@@ -9,8 +9,10 @@ import (
 
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
+	"github.com/abisalde/authentication-service/internal/database/ent/organization"
 	"github.com/abisalde/authentication-service/internal/database/ent/user"
 	"github.com/abisalde/authentication-service/internal/database/ent/useraddress"
+	"github.com/google/uuid"
 )
 
 // User is the model entity for the User schema.
@@ -34,12 +36,16 @@ type User struct {
 	Country string `json:"country"`
 	// State holds the value of the "state" field.
 	State string `json:"state"`
+	// PublicID holds the value of the "public_id" field.
+	PublicID uuid.UUID `json:"publicId"`
 	// Email holds the value of the "email" field.
 	Email string `json:"email,omitempty"`
 	// Username holds the value of the "username" field.
 	Username string `json:"username,omitempty"`
 	// PasswordHash holds the value of the "password_hash" field.
 	PasswordHash string `json:"-"`
+	// PasswordChangedAt holds the value of the "password_changed_at" field.
+	PasswordChangedAt *time.Time `json:"passwordChangedAt"`
 	// OauthID holds the value of the "oauth_id" field.
 	OauthID string `json:"oauthId"`
 	// Provider holds the value of the "provider" field.
@@ -58,8 +64,24 @@ type User struct {
 	MarketingOptIn bool `json:"marketingOptIn"`
 	// TermsAcceptedAt holds the value of the "terms_accepted_at" field.
 	TermsAcceptedAt *time.Time `json:"termsAcceptedAt"`
+	// TermsVersion holds the value of the "terms_version" field.
+	TermsVersion string `json:"termsVersion"`
 	// LastLoginAt holds the value of the "last_login_at" field.
 	LastLoginAt *time.Time `json:"lastLoginAt"`
+	// OnboardingStep holds the value of the "onboarding_step" field.
+	OnboardingStep user.OnboardingStep `json:"onboardingStep"`
+	// SuspendedAt holds the value of the "suspended_at" field.
+	SuspendedAt *time.Time `json:"suspendedAt"`
+	// SuspensionReason holds the value of the "suspension_reason" field.
+	SuspensionReason *user.SuspensionReason `json:"suspensionReason"`
+	// Residency holds the value of the "residency" field.
+	Residency string `json:"residency"`
+	// DeactivatedAt holds the value of the "deactivated_at" field.
+	DeactivatedAt *time.Time `json:"deactivatedAt"`
+	// OrganizationID holds the value of the "organization_id" field.
+	OrganizationID *int64 `json:"organizationId"`
+	// Kind holds the value of the "kind" field.
+	Kind user.Kind `json:"kind,omitempty"`
 	// Edges holds the relations/edges for other nodes in the graph.
 	// The values are being populated by the UserQuery when eager-loading is set.
 	Edges        UserEdges `json:"edges"`
@@ -71,9 +93,19 @@ type User struct {
 type UserEdges struct {
 	// Address holds the value of the address edge.
 	Address *UserAddress `json:"address"`
+	// LoginAttempts holds the value of the login_attempts edge.
+	LoginAttempts []*LoginAttempt `json:"loginAttempts"`
+	// Organization holds the value of the organization edge.
+	Organization *Organization `json:"organization,omitempty"`
+	// ServiceAccountKeys holds the value of the service_account_keys edge.
+	ServiceAccountKeys []*ServiceAccountKey `json:"serviceAccountKeys"`
+	// Identities holds the value of the identities edge.
+	Identities []*UserIdentity `json:"identities"`
+	// ScheduledActions holds the value of the scheduled_actions edge.
+	ScheduledActions []*ScheduledAction `json:"scheduledActions"`
 	// loadedTypes holds the information for reporting if a
 	// type was loaded (or requested) in eager-loading or not.
-	loadedTypes [1]bool
+	loadedTypes [6]bool
 }
 
 // AddressOrErr returns the Address value or an error if the edge
@@ -87,6 +119,53 @@ func (e UserEdges) AddressOrErr() (*UserAddress, error) {
 	return nil, &NotLoadedError{edge: "address"}
 }
 
+// LoginAttemptsOrErr returns the LoginAttempts value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) LoginAttemptsOrErr() ([]*LoginAttempt, error) {
+	if e.loadedTypes[1] {
+		return e.LoginAttempts, nil
+	}
+	return nil, &NotLoadedError{edge: "login_attempts"}
+}
+
+// OrganizationOrErr returns the Organization value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e UserEdges) OrganizationOrErr() (*Organization, error) {
+	if e.Organization != nil {
+		return e.Organization, nil
+	} else if e.loadedTypes[2] {
+		return nil, &NotFoundError{label: organization.Label}
+	}
+	return nil, &NotLoadedError{edge: "organization"}
+}
+
+// ServiceAccountKeysOrErr returns the ServiceAccountKeys value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) ServiceAccountKeysOrErr() ([]*ServiceAccountKey, error) {
+	if e.loadedTypes[3] {
+		return e.ServiceAccountKeys, nil
+	}
+	return nil, &NotLoadedError{edge: "service_account_keys"}
+}
+
+// IdentitiesOrErr returns the Identities value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) IdentitiesOrErr() ([]*UserIdentity, error) {
+	if e.loadedTypes[4] {
+		return e.Identities, nil
+	}
+	return nil, &NotLoadedError{edge: "identities"}
+}
+
+// ScheduledActionsOrErr returns the ScheduledActions value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) ScheduledActionsOrErr() ([]*ScheduledAction, error) {
+	if e.loadedTypes[5] {
+		return e.ScheduledActions, nil
+	}
+	return nil, &NotLoadedError{edge: "scheduled_actions"}
+}
+
 // scanValues returns the types for scanning values from sql.Rows.
 func (*User) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
@@ -94,12 +173,14 @@ func (*User) scanValues(columns []string) ([]any, error) {
 		switch columns[i] {
 		case user.FieldIsEmailVerified, user.FieldMarketingOptIn:
 			values[i] = new(sql.NullBool)
-		case user.FieldID:
+		case user.FieldID, user.FieldOrganizationID:
 			values[i] = new(sql.NullInt64)
-		case user.FieldStreetName, user.FieldCity, user.FieldZipCode, user.FieldCountry, user.FieldState, user.FieldEmail, user.FieldUsername, user.FieldPasswordHash, user.FieldOauthID, user.FieldProvider, user.FieldFirstName, user.FieldLastName, user.FieldPhoneNumber, user.FieldRole:
+		case user.FieldStreetName, user.FieldCity, user.FieldZipCode, user.FieldCountry, user.FieldState, user.FieldEmail, user.FieldUsername, user.FieldPasswordHash, user.FieldOauthID, user.FieldProvider, user.FieldFirstName, user.FieldLastName, user.FieldPhoneNumber, user.FieldRole, user.FieldTermsVersion, user.FieldOnboardingStep, user.FieldSuspensionReason, user.FieldResidency, user.FieldKind:
 			values[i] = new(sql.NullString)
-		case user.FieldCreatedAt, user.FieldUpdatedAt, user.FieldDeletedAt, user.FieldTermsAcceptedAt, user.FieldLastLoginAt:
+		case user.FieldCreatedAt, user.FieldUpdatedAt, user.FieldDeletedAt, user.FieldPasswordChangedAt, user.FieldTermsAcceptedAt, user.FieldLastLoginAt, user.FieldSuspendedAt, user.FieldDeactivatedAt:
 			values[i] = new(sql.NullTime)
+		case user.FieldPublicID:
+			values[i] = new(uuid.UUID)
 		case user.ForeignKeys[0]: // user_address
 			values[i] = new(sql.NullInt64)
 		default:
@@ -111,7 +192,7 @@ func (*User) scanValues(columns []string) ([]any, error) {
 
 // assignValues assigns the values that were returned from sql.Rows (after scanning)
 // to the User fields.
-func (u *User) assignValues(columns []string, values []any) error {
+func (_m *User) assignValues(columns []string, values []any) error {
 	if m, n := len(values), len(columns); m < n {
 		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
 	}
@@ -122,145 +203,210 @@ func (u *User) assignValues(columns []string, values []any) error {
 			if !ok {
 				return fmt.Errorf("unexpected type %T for field id", value)
 			}
-			u.ID = int64(value.Int64)
+			_m.ID = int64(value.Int64)
 		case user.FieldCreatedAt:
 			if value, ok := values[i].(*sql.NullTime); !ok {
 				return fmt.Errorf("unexpected type %T for field created_at", values[i])
 			} else if value.Valid {
-				u.CreatedAt = value.Time
+				_m.CreatedAt = value.Time
 			}
 		case user.FieldUpdatedAt:
 			if value, ok := values[i].(*sql.NullTime); !ok {
 				return fmt.Errorf("unexpected type %T for field updated_at", values[i])
 			} else if value.Valid {
-				u.UpdatedAt = value.Time
+				_m.UpdatedAt = value.Time
 			}
 		case user.FieldDeletedAt:
 			if value, ok := values[i].(*sql.NullTime); !ok {
 				return fmt.Errorf("unexpected type %T for field deleted_at", values[i])
 			} else if value.Valid {
-				u.DeletedAt = new(time.Time)
-				*u.DeletedAt = value.Time
+				_m.DeletedAt = new(time.Time)
+				*_m.DeletedAt = value.Time
 			}
 		case user.FieldStreetName:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field street_name", values[i])
 			} else if value.Valid {
-				u.StreetName = value.String
+				_m.StreetName = value.String
 			}
 		case user.FieldCity:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field city", values[i])
 			} else if value.Valid {
-				u.City = value.String
+				_m.City = value.String
 			}
 		case user.FieldZipCode:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field zip_code", values[i])
 			} else if value.Valid {
-				u.ZipCode = value.String
+				_m.ZipCode = value.String
 			}
 		case user.FieldCountry:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field country", values[i])
 			} else if value.Valid {
-				u.Country = value.String
+				_m.Country = value.String
 			}
 		case user.FieldState:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field state", values[i])
 			} else if value.Valid {
-				u.State = value.String
+				_m.State = value.String
+			}
+		case user.FieldPublicID:
+			if value, ok := values[i].(*uuid.UUID); !ok {
+				return fmt.Errorf("unexpected type %T for field public_id", values[i])
+			} else if value != nil {
+				_m.PublicID = *value
 			}
 		case user.FieldEmail:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field email", values[i])
 			} else if value.Valid {
-				u.Email = value.String
+				_m.Email = value.String
 			}
 		case user.FieldUsername:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field username", values[i])
 			} else if value.Valid {
-				u.Username = value.String
+				_m.Username = value.String
 			}
 		case user.FieldPasswordHash:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field password_hash", values[i])
 			} else if value.Valid {
-				u.PasswordHash = value.String
+				_m.PasswordHash = value.String
+			}
+		case user.FieldPasswordChangedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field password_changed_at", values[i])
+			} else if value.Valid {
+				_m.PasswordChangedAt = new(time.Time)
+				*_m.PasswordChangedAt = value.Time
 			}
 		case user.FieldOauthID:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field oauth_id", values[i])
 			} else if value.Valid {
-				u.OauthID = value.String
+				_m.OauthID = value.String
 			}
 		case user.FieldProvider:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field provider", values[i])
 			} else if value.Valid {
-				u.Provider = user.Provider(value.String)
+				_m.Provider = user.Provider(value.String)
 			}
 		case user.FieldFirstName:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field first_name", values[i])
 			} else if value.Valid {
-				u.FirstName = value.String
+				_m.FirstName = value.String
 			}
 		case user.FieldLastName:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field last_name", values[i])
 			} else if value.Valid {
-				u.LastName = value.String
+				_m.LastName = value.String
 			}
 		case user.FieldPhoneNumber:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field phone_number", values[i])
 			} else if value.Valid {
-				u.PhoneNumber = value.String
+				_m.PhoneNumber = value.String
 			}
 		case user.FieldRole:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field role", values[i])
 			} else if value.Valid {
-				u.Role = user.Role(value.String)
+				_m.Role = user.Role(value.String)
 			}
 		case user.FieldIsEmailVerified:
 			if value, ok := values[i].(*sql.NullBool); !ok {
 				return fmt.Errorf("unexpected type %T for field is_email_verified", values[i])
 			} else if value.Valid {
-				u.IsEmailVerified = value.Bool
+				_m.IsEmailVerified = value.Bool
 			}
 		case user.FieldMarketingOptIn:
 			if value, ok := values[i].(*sql.NullBool); !ok {
 				return fmt.Errorf("unexpected type %T for field marketing_opt_in", values[i])
 			} else if value.Valid {
-				u.MarketingOptIn = value.Bool
+				_m.MarketingOptIn = value.Bool
 			}
 		case user.FieldTermsAcceptedAt:
 			if value, ok := values[i].(*sql.NullTime); !ok {
 				return fmt.Errorf("unexpected type %T for field terms_accepted_at", values[i])
 			} else if value.Valid {
-				u.TermsAcceptedAt = new(time.Time)
-				*u.TermsAcceptedAt = value.Time
+				_m.TermsAcceptedAt = new(time.Time)
+				*_m.TermsAcceptedAt = value.Time
+			}
+		case user.FieldTermsVersion:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field terms_version", values[i])
+			} else if value.Valid {
+				_m.TermsVersion = value.String
 			}
 		case user.FieldLastLoginAt:
 			if value, ok := values[i].(*sql.NullTime); !ok {
 				return fmt.Errorf("unexpected type %T for field last_login_at", values[i])
 			} else if value.Valid {
-				u.LastLoginAt = new(time.Time)
-				*u.LastLoginAt = value.Time
+				_m.LastLoginAt = new(time.Time)
+				*_m.LastLoginAt = value.Time
+			}
+		case user.FieldOnboardingStep:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field onboarding_step", values[i])
+			} else if value.Valid {
+				_m.OnboardingStep = user.OnboardingStep(value.String)
+			}
+		case user.FieldSuspendedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field suspended_at", values[i])
+			} else if value.Valid {
+				_m.SuspendedAt = new(time.Time)
+				*_m.SuspendedAt = value.Time
+			}
+		case user.FieldSuspensionReason:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field suspension_reason", values[i])
+			} else if value.Valid {
+				_m.SuspensionReason = new(user.SuspensionReason)
+				*_m.SuspensionReason = user.SuspensionReason(value.String)
+			}
+		case user.FieldResidency:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field residency", values[i])
+			} else if value.Valid {
+				_m.Residency = value.String
+			}
+		case user.FieldDeactivatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field deactivated_at", values[i])
+			} else if value.Valid {
+				_m.DeactivatedAt = new(time.Time)
+				*_m.DeactivatedAt = value.Time
+			}
+		case user.FieldOrganizationID:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field organization_id", values[i])
+			} else if value.Valid {
+				_m.OrganizationID = new(int64)
+				*_m.OrganizationID = value.Int64
+			}
+		case user.FieldKind:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field kind", values[i])
+			} else if value.Valid {
+				_m.Kind = user.Kind(value.String)
 			}
 		case user.ForeignKeys[0]:
 			if value, ok := values[i].(*sql.NullInt64); !ok {
 				return fmt.Errorf("unexpected type %T for edge-field user_address", value)
 			} else if value.Valid {
-				u.user_address = new(int)
-				*u.user_address = int(value.Int64)
+				_m.user_address = new(int)
+				*_m.user_address = int(value.Int64)
 			}
 		default:
-			u.selectValues.Set(columns[i], values[i])
+			_m.selectValues.Set(columns[i], values[i])
 		}
 	}
 	return nil
@@ -268,105 +414,170 @@ func (u *User) assignValues(columns []string, values []any) error {
 
 // Value returns the ent.Value that was dynamically selected and assigned to the User.
 // This includes values selected through modifiers, order, etc.
-func (u *User) Value(name string) (ent.Value, error) {
-	return u.selectValues.Get(name)
+func (_m *User) Value(name string) (ent.Value, error) {
+	return _m.selectValues.Get(name)
 }
 
 // QueryAddress queries the "address" edge of the User entity.
-func (u *User) QueryAddress() *UserAddressQuery {
-	return NewUserClient(u.config).QueryAddress(u)
+func (_m *User) QueryAddress() *UserAddressQuery {
+	return NewUserClient(_m.config).QueryAddress(_m)
+}
+
+// QueryLoginAttempts queries the "login_attempts" edge of the User entity.
+func (_m *User) QueryLoginAttempts() *LoginAttemptQuery {
+	return NewUserClient(_m.config).QueryLoginAttempts(_m)
+}
+
+// QueryOrganization queries the "organization" edge of the User entity.
+func (_m *User) QueryOrganization() *OrganizationQuery {
+	return NewUserClient(_m.config).QueryOrganization(_m)
+}
+
+// QueryServiceAccountKeys queries the "service_account_keys" edge of the User entity.
+func (_m *User) QueryServiceAccountKeys() *ServiceAccountKeyQuery {
+	return NewUserClient(_m.config).QueryServiceAccountKeys(_m)
+}
+
+// QueryIdentities queries the "identities" edge of the User entity.
+func (_m *User) QueryIdentities() *UserIdentityQuery {
+	return NewUserClient(_m.config).QueryIdentities(_m)
+}
+
+// QueryScheduledActions queries the "scheduled_actions" edge of the User entity.
+func (_m *User) QueryScheduledActions() *ScheduledActionQuery {
+	return NewUserClient(_m.config).QueryScheduledActions(_m)
 }
 
 // Update returns a builder for updating this User.
 // Note that you need to call User.Unwrap() before calling this method if this User
 // was returned from a transaction, and the transaction was committed or rolled back.
-func (u *User) Update() *UserUpdateOne {
-	return NewUserClient(u.config).UpdateOne(u)
+func (_m *User) Update() *UserUpdateOne {
+	return NewUserClient(_m.config).UpdateOne(_m)
 }
 
 // Unwrap unwraps the User entity that was returned from a transaction after it was closed,
 // so that all future queries will be executed through the driver which created the transaction.
-func (u *User) Unwrap() *User {
-	_tx, ok := u.config.driver.(*txDriver)
+func (_m *User) Unwrap() *User {
+	_tx, ok := _m.config.driver.(*txDriver)
 	if !ok {
 		panic("ent: User is not a transactional entity")
 	}
-	u.config.driver = _tx.drv
-	return u
+	_m.config.driver = _tx.drv
+	return _m
 }
 
 // String implements the fmt.Stringer.
-func (u *User) String() string {
+func (_m *User) String() string {
 	var builder strings.Builder
 	builder.WriteString("User(")
-	builder.WriteString(fmt.Sprintf("id=%v, ", u.ID))
+	builder.WriteString(fmt.Sprintf("id=%v, ", _m.ID))
 	builder.WriteString("created_at=")
-	builder.WriteString(u.CreatedAt.Format(time.ANSIC))
+	builder.WriteString(_m.CreatedAt.Format(time.ANSIC))
 	builder.WriteString(", ")
 	builder.WriteString("updated_at=")
-	builder.WriteString(u.UpdatedAt.Format(time.ANSIC))
+	builder.WriteString(_m.UpdatedAt.Format(time.ANSIC))
 	builder.WriteString(", ")
-	if v := u.DeletedAt; v != nil {
+	if v := _m.DeletedAt; v != nil {
 		builder.WriteString("deleted_at=")
 		builder.WriteString(v.Format(time.ANSIC))
 	}
 	builder.WriteString(", ")
 	builder.WriteString("street_name=")
-	builder.WriteString(u.StreetName)
+	builder.WriteString(_m.StreetName)
 	builder.WriteString(", ")
 	builder.WriteString("city=")
-	builder.WriteString(u.City)
+	builder.WriteString(_m.City)
 	builder.WriteString(", ")
 	builder.WriteString("zip_code=")
-	builder.WriteString(u.ZipCode)
+	builder.WriteString(_m.ZipCode)
 	builder.WriteString(", ")
 	builder.WriteString("country=")
-	builder.WriteString(u.Country)
+	builder.WriteString(_m.Country)
 	builder.WriteString(", ")
 	builder.WriteString("state=")
-	builder.WriteString(u.State)
+	builder.WriteString(_m.State)
+	builder.WriteString(", ")
+	builder.WriteString("public_id=")
+	builder.WriteString(fmt.Sprintf("%v", _m.PublicID))
 	builder.WriteString(", ")
 	builder.WriteString("email=")
-	builder.WriteString(u.Email)
+	builder.WriteString(_m.Email)
 	builder.WriteString(", ")
 	builder.WriteString("username=")
-	builder.WriteString(u.Username)
+	builder.WriteString(_m.Username)
 	builder.WriteString(", ")
 	builder.WriteString("password_hash=<sensitive>")
 	builder.WriteString(", ")
+	if v := _m.PasswordChangedAt; v != nil {
+		builder.WriteString("password_changed_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
 	builder.WriteString("oauth_id=")
-	builder.WriteString(u.OauthID)
+	builder.WriteString(_m.OauthID)
 	builder.WriteString(", ")
 	builder.WriteString("provider=")
-	builder.WriteString(fmt.Sprintf("%v", u.Provider))
+	builder.WriteString(fmt.Sprintf("%v", _m.Provider))
 	builder.WriteString(", ")
 	builder.WriteString("first_name=")
-	builder.WriteString(u.FirstName)
+	builder.WriteString(_m.FirstName)
 	builder.WriteString(", ")
 	builder.WriteString("last_name=")
-	builder.WriteString(u.LastName)
+	builder.WriteString(_m.LastName)
 	builder.WriteString(", ")
 	builder.WriteString("phone_number=")
-	builder.WriteString(u.PhoneNumber)
+	builder.WriteString(_m.PhoneNumber)
 	builder.WriteString(", ")
 	builder.WriteString("role=")
-	builder.WriteString(fmt.Sprintf("%v", u.Role))
+	builder.WriteString(fmt.Sprintf("%v", _m.Role))
 	builder.WriteString(", ")
 	builder.WriteString("is_email_verified=")
-	builder.WriteString(fmt.Sprintf("%v", u.IsEmailVerified))
+	builder.WriteString(fmt.Sprintf("%v", _m.IsEmailVerified))
 	builder.WriteString(", ")
 	builder.WriteString("marketing_opt_in=")
-	builder.WriteString(fmt.Sprintf("%v", u.MarketingOptIn))
+	builder.WriteString(fmt.Sprintf("%v", _m.MarketingOptIn))
 	builder.WriteString(", ")
-	if v := u.TermsAcceptedAt; v != nil {
+	if v := _m.TermsAcceptedAt; v != nil {
 		builder.WriteString("terms_accepted_at=")
 		builder.WriteString(v.Format(time.ANSIC))
 	}
 	builder.WriteString(", ")
-	if v := u.LastLoginAt; v != nil {
+	builder.WriteString("terms_version=")
+	builder.WriteString(_m.TermsVersion)
+	builder.WriteString(", ")
+	if v := _m.LastLoginAt; v != nil {
 		builder.WriteString("last_login_at=")
 		builder.WriteString(v.Format(time.ANSIC))
 	}
+	builder.WriteString(", ")
+	builder.WriteString("onboarding_step=")
+	builder.WriteString(fmt.Sprintf("%v", _m.OnboardingStep))
+	builder.WriteString(", ")
+	if v := _m.SuspendedAt; v != nil {
+		builder.WriteString("suspended_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	if v := _m.SuspensionReason; v != nil {
+		builder.WriteString("suspension_reason=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("residency=")
+	builder.WriteString(_m.Residency)
+	builder.WriteString(", ")
+	if v := _m.DeactivatedAt; v != nil {
+		builder.WriteString("deactivated_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	if v := _m.OrganizationID; v != nil {
+		builder.WriteString("organization_id=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("kind=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Kind))
 	builder.WriteByte(')')
 	return builder.String()
 }
@@ -8,6 +8,7 @@ import (
 
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/google/uuid"
 )
 
 const (
@@ -31,12 +32,16 @@ const (
 	FieldCountry = "country"
 	// FieldState holds the string denoting the state field in the database.
 	FieldState = "state"
+	// FieldPublicID holds the string denoting the public_id field in the database.
+	FieldPublicID = "public_id"
 	// FieldEmail holds the string denoting the email field in the database.
 	FieldEmail = "email"
 	// FieldUsername holds the string denoting the username field in the database.
 	FieldUsername = "username"
 	// FieldPasswordHash holds the string denoting the password_hash field in the database.
 	FieldPasswordHash = "password_hash"
+	// FieldPasswordChangedAt holds the string denoting the password_changed_at field in the database.
+	FieldPasswordChangedAt = "password_changed_at"
 	// FieldOauthID holds the string denoting the oauth_id field in the database.
 	FieldOauthID = "oauth_id"
 	// FieldProvider holds the string denoting the provider field in the database.
@@ -55,10 +60,36 @@ const (
 	FieldMarketingOptIn = "marketing_opt_in"
 	// FieldTermsAcceptedAt holds the string denoting the terms_accepted_at field in the database.
 	FieldTermsAcceptedAt = "terms_accepted_at"
+	// FieldTermsVersion holds the string denoting the terms_version field in the database.
+	FieldTermsVersion = "terms_version"
 	// FieldLastLoginAt holds the string denoting the last_login_at field in the database.
 	FieldLastLoginAt = "last_login_at"
+	// FieldOnboardingStep holds the string denoting the onboarding_step field in the database.
+	FieldOnboardingStep = "onboarding_step"
+	// FieldSuspendedAt holds the string denoting the suspended_at field in the database.
+	FieldSuspendedAt = "suspended_at"
+	// FieldSuspensionReason holds the string denoting the suspension_reason field in the database.
+	FieldSuspensionReason = "suspension_reason"
+	// FieldResidency holds the string denoting the residency field in the database.
+	FieldResidency = "residency"
+	// FieldDeactivatedAt holds the string denoting the deactivated_at field in the database.
+	FieldDeactivatedAt = "deactivated_at"
+	// FieldOrganizationID holds the string denoting the organization_id field in the database.
+	FieldOrganizationID = "organization_id"
+	// FieldKind holds the string denoting the kind field in the database.
+	FieldKind = "kind"
 	// EdgeAddress holds the string denoting the address edge name in mutations.
 	EdgeAddress = "address"
+	// EdgeLoginAttempts holds the string denoting the login_attempts edge name in mutations.
+	EdgeLoginAttempts = "login_attempts"
+	// EdgeOrganization holds the string denoting the organization edge name in mutations.
+	EdgeOrganization = "organization"
+	// EdgeServiceAccountKeys holds the string denoting the service_account_keys edge name in mutations.
+	EdgeServiceAccountKeys = "service_account_keys"
+	// EdgeIdentities holds the string denoting the identities edge name in mutations.
+	EdgeIdentities = "identities"
+	// EdgeScheduledActions holds the string denoting the scheduled_actions edge name in mutations.
+	EdgeScheduledActions = "scheduled_actions"
 	// Table holds the table name of the user in the database.
 	Table = "users"
 	// AddressTable is the table that holds the address relation/edge.
@@ -68,6 +99,41 @@ const (
 	AddressInverseTable = "user_addresses"
 	// AddressColumn is the table column denoting the address relation/edge.
 	AddressColumn = "user_address"
+	// LoginAttemptsTable is the table that holds the login_attempts relation/edge.
+	LoginAttemptsTable = "login_attempts"
+	// LoginAttemptsInverseTable is the table name for the LoginAttempt entity.
+	// It exists in this package in order to avoid circular dependency with the "loginattempt" package.
+	LoginAttemptsInverseTable = "login_attempts"
+	// LoginAttemptsColumn is the table column denoting the login_attempts relation/edge.
+	LoginAttemptsColumn = "user_id"
+	// OrganizationTable is the table that holds the organization relation/edge.
+	OrganizationTable = "users"
+	// OrganizationInverseTable is the table name for the Organization entity.
+	// It exists in this package in order to avoid circular dependency with the "organization" package.
+	OrganizationInverseTable = "organizations"
+	// OrganizationColumn is the table column denoting the organization relation/edge.
+	OrganizationColumn = "organization_id"
+	// ServiceAccountKeysTable is the table that holds the service_account_keys relation/edge.
+	ServiceAccountKeysTable = "service_account_keys"
+	// ServiceAccountKeysInverseTable is the table name for the ServiceAccountKey entity.
+	// It exists in this package in order to avoid circular dependency with the "serviceaccountkey" package.
+	ServiceAccountKeysInverseTable = "service_account_keys"
+	// ServiceAccountKeysColumn is the table column denoting the service_account_keys relation/edge.
+	ServiceAccountKeysColumn = "user_id"
+	// IdentitiesTable is the table that holds the identities relation/edge.
+	IdentitiesTable = "user_identities"
+	// IdentitiesInverseTable is the table name for the UserIdentity entity.
+	// It exists in this package in order to avoid circular dependency with the "useridentity" package.
+	IdentitiesInverseTable = "user_identities"
+	// IdentitiesColumn is the table column denoting the identities relation/edge.
+	IdentitiesColumn = "user_id"
+	// ScheduledActionsTable is the table that holds the scheduled_actions relation/edge.
+	ScheduledActionsTable = "scheduled_actions"
+	// ScheduledActionsInverseTable is the table name for the ScheduledAction entity.
+	// It exists in this package in order to avoid circular dependency with the "scheduledaction" package.
+	ScheduledActionsInverseTable = "scheduled_actions"
+	// ScheduledActionsColumn is the table column denoting the scheduled_actions relation/edge.
+	ScheduledActionsColumn = "user_id"
 )
 
 // Columns holds all SQL columns for user fields.
@@ -81,9 +147,11 @@ var Columns = []string{
 	FieldZipCode,
 	FieldCountry,
 	FieldState,
+	FieldPublicID,
 	FieldEmail,
 	FieldUsername,
 	FieldPasswordHash,
+	FieldPasswordChangedAt,
 	FieldOauthID,
 	FieldProvider,
 	FieldFirstName,
@@ -93,7 +161,15 @@ var Columns = []string{
 	FieldIsEmailVerified,
 	FieldMarketingOptIn,
 	FieldTermsAcceptedAt,
+	FieldTermsVersion,
 	FieldLastLoginAt,
+	FieldOnboardingStep,
+	FieldSuspendedAt,
+	FieldSuspensionReason,
+	FieldResidency,
+	FieldDeactivatedAt,
+	FieldOrganizationID,
+	FieldKind,
 }
 
 // ForeignKeys holds the SQL foreign-keys that are owned by the "users"
@@ -144,6 +220,8 @@ var (
 	DefaultState string
 	// StateValidator is a validator for the "state" field. It is called by the builders before save.
 	StateValidator func(string) error
+	// DefaultPublicID holds the default value on creation for the "public_id" field.
+	DefaultPublicID func() uuid.UUID
 	// EmailValidator is a validator for the "email" field. It is called by the builders before save.
 	EmailValidator func(string) error
 	// UsernameValidator is a validator for the "username" field. It is called by the builders before save.
@@ -164,6 +242,10 @@ var (
 	DefaultIsEmailVerified bool
 	// DefaultMarketingOptIn holds the default value on creation for the "marketing_opt_in" field.
 	DefaultMarketingOptIn bool
+	// TermsVersionValidator is a validator for the "terms_version" field. It is called by the builders before save.
+	TermsVersionValidator func(string) error
+	// ResidencyValidator is a validator for the "residency" field. It is called by the builders before save.
+	ResidencyValidator func(string) error
 )
 
 // Provider defines the type for the "provider" enum field.
@@ -174,9 +256,13 @@ const DefaultProvider = ProviderEMAIL
 
 // Provider values.
 const (
-	ProviderGOOGLE   Provider = "GOOGLE"
-	ProviderFACEBOOK Provider = "FACEBOOK"
-	ProviderEMAIL    Provider = "EMAIL"
+	ProviderGOOGLE    Provider = "GOOGLE"
+	ProviderFACEBOOK  Provider = "FACEBOOK"
+	ProviderEMAIL     Provider = "EMAIL"
+	ProviderAPPLE     Provider = "APPLE"
+	ProviderGITHUB    Provider = "GITHUB"
+	ProviderMICROSOFT Provider = "MICROSOFT"
+	ProviderOAUTH     Provider = "OAUTH"
 )
 
 func (pr Provider) String() string {
@@ -186,7 +272,7 @@ func (pr Provider) String() string {
 // ProviderValidator is a validator for the "provider" field enum values. It is called by the builders before save.
 func ProviderValidator(pr Provider) error {
 	switch pr {
-	case ProviderGOOGLE, ProviderFACEBOOK, ProviderEMAIL:
+	case ProviderGOOGLE, ProviderFACEBOOK, ProviderEMAIL, ProviderAPPLE, ProviderGITHUB, ProviderMICROSOFT, ProviderOAUTH:
 		return nil
 	default:
 		return fmt.Errorf("user: invalid enum value for provider field: %q", pr)
@@ -219,6 +305,87 @@ func RoleValidator(r Role) error {
 	}
 }
 
+// OnboardingStep defines the type for the "onboarding_step" enum field.
+type OnboardingStep string
+
+// OnboardingStepVERIFY_EMAIL is the default value of the OnboardingStep enum.
+const DefaultOnboardingStep = OnboardingStepVERIFY_EMAIL
+
+// OnboardingStep values.
+const (
+	OnboardingStepVERIFY_EMAIL     OnboardingStep = "VERIFY_EMAIL"
+	OnboardingStepCOMPLETE_PROFILE OnboardingStep = "COMPLETE_PROFILE"
+	OnboardingStepACCEPT_TERMS     OnboardingStep = "ACCEPT_TERMS"
+	OnboardingStepDONE             OnboardingStep = "DONE"
+)
+
+func (os OnboardingStep) String() string {
+	return string(os)
+}
+
+// OnboardingStepValidator is a validator for the "onboarding_step" field enum values. It is called by the builders before save.
+func OnboardingStepValidator(os OnboardingStep) error {
+	switch os {
+	case OnboardingStepVERIFY_EMAIL, OnboardingStepCOMPLETE_PROFILE, OnboardingStepACCEPT_TERMS, OnboardingStepDONE:
+		return nil
+	default:
+		return fmt.Errorf("user: invalid enum value for onboarding_step field: %q", os)
+	}
+}
+
+// SuspensionReason defines the type for the "suspension_reason" enum field.
+type SuspensionReason string
+
+// SuspensionReason values.
+const (
+	SuspensionReasonTERMS_VIOLATION SuspensionReason = "TERMS_VIOLATION"
+	SuspensionReasonFRAUD           SuspensionReason = "FRAUD"
+	SuspensionReasonABUSE           SuspensionReason = "ABUSE"
+	SuspensionReasonSPAM            SuspensionReason = "SPAM"
+	SuspensionReasonSECURITY        SuspensionReason = "SECURITY"
+	SuspensionReasonOTHER           SuspensionReason = "OTHER"
+)
+
+func (sr SuspensionReason) String() string {
+	return string(sr)
+}
+
+// SuspensionReasonValidator is a validator for the "suspension_reason" field enum values. It is called by the builders before save.
+func SuspensionReasonValidator(sr SuspensionReason) error {
+	switch sr {
+	case SuspensionReasonTERMS_VIOLATION, SuspensionReasonFRAUD, SuspensionReasonABUSE, SuspensionReasonSPAM, SuspensionReasonSECURITY, SuspensionReasonOTHER:
+		return nil
+	default:
+		return fmt.Errorf("user: invalid enum value for suspension_reason field: %q", sr)
+	}
+}
+
+// Kind defines the type for the "kind" enum field.
+type Kind string
+
+// KindHUMAN is the default value of the Kind enum.
+const DefaultKind = KindHUMAN
+
+// Kind values.
+const (
+	KindHUMAN   Kind = "HUMAN"
+	KindSERVICE Kind = "SERVICE"
+)
+
+func (k Kind) String() string {
+	return string(k)
+}
+
+// KindValidator is a validator for the "kind" field enum values. It is called by the builders before save.
+func KindValidator(k Kind) error {
+	switch k {
+	case KindHUMAN, KindSERVICE:
+		return nil
+	default:
+		return fmt.Errorf("user: invalid enum value for kind field: %q", k)
+	}
+}
+
 // OrderOption defines the ordering options for the User queries.
 type OrderOption func(*sql.Selector)
 
@@ -267,6 +434,11 @@ func ByState(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldState, opts...).ToFunc()
 }
 
+// ByPublicID orders the results by the public_id field.
+func ByPublicID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldPublicID, opts...).ToFunc()
+}
+
 // ByEmail orders the results by the email field.
 func ByEmail(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldEmail, opts...).ToFunc()
@@ -282,6 +454,11 @@ func ByPasswordHash(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldPasswordHash, opts...).ToFunc()
 }
 
+// ByPasswordChangedAt orders the results by the password_changed_at field.
+func ByPasswordChangedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldPasswordChangedAt, opts...).ToFunc()
+}
+
 // ByOauthID orders the results by the oauth_id field.
 func ByOauthID(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldOauthID, opts...).ToFunc()
@@ -327,17 +504,120 @@ func ByTermsAcceptedAt(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldTermsAcceptedAt, opts...).ToFunc()
 }
 
+// ByTermsVersion orders the results by the terms_version field.
+func ByTermsVersion(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldTermsVersion, opts...).ToFunc()
+}
+
 // ByLastLoginAt orders the results by the last_login_at field.
 func ByLastLoginAt(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldLastLoginAt, opts...).ToFunc()
 }
 
+// ByOnboardingStep orders the results by the onboarding_step field.
+func ByOnboardingStep(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldOnboardingStep, opts...).ToFunc()
+}
+
+// BySuspendedAt orders the results by the suspended_at field.
+func BySuspendedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldSuspendedAt, opts...).ToFunc()
+}
+
+// BySuspensionReason orders the results by the suspension_reason field.
+func BySuspensionReason(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldSuspensionReason, opts...).ToFunc()
+}
+
+// ByResidency orders the results by the residency field.
+func ByResidency(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldResidency, opts...).ToFunc()
+}
+
+// ByDeactivatedAt orders the results by the deactivated_at field.
+func ByDeactivatedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldDeactivatedAt, opts...).ToFunc()
+}
+
+// ByOrganizationID orders the results by the organization_id field.
+func ByOrganizationID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldOrganizationID, opts...).ToFunc()
+}
+
+// ByKind orders the results by the kind field.
+func ByKind(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldKind, opts...).ToFunc()
+}
+
 // ByAddressField orders the results by address field.
 func ByAddressField(field string, opts ...sql.OrderTermOption) OrderOption {
 	return func(s *sql.Selector) {
 		sqlgraph.OrderByNeighborTerms(s, newAddressStep(), sql.OrderByField(field, opts...))
 	}
 }
+
+// ByLoginAttemptsCount orders the results by login_attempts count.
+func ByLoginAttemptsCount(opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborsCount(s, newLoginAttemptsStep(), opts...)
+	}
+}
+
+// ByLoginAttempts orders the results by login_attempts terms.
+func ByLoginAttempts(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newLoginAttemptsStep(), append([]sql.OrderTerm{term}, terms...)...)
+	}
+}
+
+// ByOrganizationField orders the results by organization field.
+func ByOrganizationField(field string, opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newOrganizationStep(), sql.OrderByField(field, opts...))
+	}
+}
+
+// ByServiceAccountKeysCount orders the results by service_account_keys count.
+func ByServiceAccountKeysCount(opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborsCount(s, newServiceAccountKeysStep(), opts...)
+	}
+}
+
+// ByServiceAccountKeys orders the results by service_account_keys terms.
+func ByServiceAccountKeys(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newServiceAccountKeysStep(), append([]sql.OrderTerm{term}, terms...)...)
+	}
+}
+
+// ByIdentitiesCount orders the results by identities count.
+func ByIdentitiesCount(opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborsCount(s, newIdentitiesStep(), opts...)
+	}
+}
+
+// ByIdentities orders the results by identities terms.
+func ByIdentities(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newIdentitiesStep(), append([]sql.OrderTerm{term}, terms...)...)
+	}
+}
+
+// ByScheduledActionsCount orders the results by scheduled_actions count.
+func ByScheduledActionsCount(opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborsCount(s, newScheduledActionsStep(), opts...)
+	}
+}
+
+// ByScheduledActions orders the results by scheduled_actions terms.
+func ByScheduledActions(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newScheduledActionsStep(), append([]sql.OrderTerm{term}, terms...)...)
+	}
+}
 func newAddressStep() *sqlgraph.Step {
 	return sqlgraph.NewStep(
 		sqlgraph.From(Table, FieldID),
@@ -345,3 +625,38 @@ func newAddressStep() *sqlgraph.Step {
 		sqlgraph.Edge(sqlgraph.M2O, false, AddressTable, AddressColumn),
 	)
 }
+func newLoginAttemptsStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(LoginAttemptsInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.O2M, false, LoginAttemptsTable, LoginAttemptsColumn),
+	)
+}
+func newOrganizationStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(OrganizationInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.M2O, true, OrganizationTable, OrganizationColumn),
+	)
+}
+func newServiceAccountKeysStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(ServiceAccountKeysInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.O2M, false, ServiceAccountKeysTable, ServiceAccountKeysColumn),
+	)
+}
+func newIdentitiesStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(IdentitiesInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.O2M, false, IdentitiesTable, IdentitiesColumn),
+	)
+}
+func newScheduledActionsStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(ScheduledActionsInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.O2M, false, ScheduledActionsTable, ScheduledActionsColumn),
+	)
+}
@@ -10,8 +10,14 @@ import (
 
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"entgo.io/ent/schema/field"
+	"github.com/abisalde/authentication-service/internal/database/ent/loginattempt"
+	"github.com/abisalde/authentication-service/internal/database/ent/organization"
+	"github.com/abisalde/authentication-service/internal/database/ent/scheduledaction"
+	"github.com/abisalde/authentication-service/internal/database/ent/serviceaccountkey"
 	"github.com/abisalde/authentication-service/internal/database/ent/user"
 	"github.com/abisalde/authentication-service/internal/database/ent/useraddress"
+	"github.com/abisalde/authentication-service/internal/database/ent/useridentity"
+	"github.com/google/uuid"
 )
 
 // UserCreate is the builder for creating a User entity.
@@ -22,330 +28,535 @@ type UserCreate struct {
 }
 
 // SetCreatedAt sets the "created_at" field.
-func (uc *UserCreate) SetCreatedAt(t time.Time) *UserCreate {
-	uc.mutation.SetCreatedAt(t)
-	return uc
+func (_c *UserCreate) SetCreatedAt(v time.Time) *UserCreate {
+	_c.mutation.SetCreatedAt(v)
+	return _c
 }
 
 // SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
-func (uc *UserCreate) SetNillableCreatedAt(t *time.Time) *UserCreate {
-	if t != nil {
-		uc.SetCreatedAt(*t)
+func (_c *UserCreate) SetNillableCreatedAt(v *time.Time) *UserCreate {
+	if v != nil {
+		_c.SetCreatedAt(*v)
 	}
-	return uc
+	return _c
 }
 
 // SetUpdatedAt sets the "updated_at" field.
-func (uc *UserCreate) SetUpdatedAt(t time.Time) *UserCreate {
-	uc.mutation.SetUpdatedAt(t)
-	return uc
+func (_c *UserCreate) SetUpdatedAt(v time.Time) *UserCreate {
+	_c.mutation.SetUpdatedAt(v)
+	return _c
 }
 
 // SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
-func (uc *UserCreate) SetNillableUpdatedAt(t *time.Time) *UserCreate {
-	if t != nil {
-		uc.SetUpdatedAt(*t)
+func (_c *UserCreate) SetNillableUpdatedAt(v *time.Time) *UserCreate {
+	if v != nil {
+		_c.SetUpdatedAt(*v)
 	}
-	return uc
+	return _c
 }
 
 // SetDeletedAt sets the "deleted_at" field.
-func (uc *UserCreate) SetDeletedAt(t time.Time) *UserCreate {
-	uc.mutation.SetDeletedAt(t)
-	return uc
+func (_c *UserCreate) SetDeletedAt(v time.Time) *UserCreate {
+	_c.mutation.SetDeletedAt(v)
+	return _c
 }
 
 // SetNillableDeletedAt sets the "deleted_at" field if the given value is not nil.
-func (uc *UserCreate) SetNillableDeletedAt(t *time.Time) *UserCreate {
-	if t != nil {
-		uc.SetDeletedAt(*t)
+func (_c *UserCreate) SetNillableDeletedAt(v *time.Time) *UserCreate {
+	if v != nil {
+		_c.SetDeletedAt(*v)
 	}
-	return uc
+	return _c
 }
 
 // SetStreetName sets the "street_name" field.
-func (uc *UserCreate) SetStreetName(s string) *UserCreate {
-	uc.mutation.SetStreetName(s)
-	return uc
+func (_c *UserCreate) SetStreetName(v string) *UserCreate {
+	_c.mutation.SetStreetName(v)
+	return _c
 }
 
 // SetNillableStreetName sets the "street_name" field if the given value is not nil.
-func (uc *UserCreate) SetNillableStreetName(s *string) *UserCreate {
-	if s != nil {
-		uc.SetStreetName(*s)
+func (_c *UserCreate) SetNillableStreetName(v *string) *UserCreate {
+	if v != nil {
+		_c.SetStreetName(*v)
 	}
-	return uc
+	return _c
 }
 
 // SetCity sets the "city" field.
-func (uc *UserCreate) SetCity(s string) *UserCreate {
-	uc.mutation.SetCity(s)
-	return uc
+func (_c *UserCreate) SetCity(v string) *UserCreate {
+	_c.mutation.SetCity(v)
+	return _c
 }
 
 // SetNillableCity sets the "city" field if the given value is not nil.
-func (uc *UserCreate) SetNillableCity(s *string) *UserCreate {
-	if s != nil {
-		uc.SetCity(*s)
+func (_c *UserCreate) SetNillableCity(v *string) *UserCreate {
+	if v != nil {
+		_c.SetCity(*v)
 	}
-	return uc
+	return _c
 }
 
 // SetZipCode sets the "zip_code" field.
-func (uc *UserCreate) SetZipCode(s string) *UserCreate {
-	uc.mutation.SetZipCode(s)
-	return uc
+func (_c *UserCreate) SetZipCode(v string) *UserCreate {
+	_c.mutation.SetZipCode(v)
+	return _c
 }
 
 // SetNillableZipCode sets the "zip_code" field if the given value is not nil.
-func (uc *UserCreate) SetNillableZipCode(s *string) *UserCreate {
-	if s != nil {
-		uc.SetZipCode(*s)
+func (_c *UserCreate) SetNillableZipCode(v *string) *UserCreate {
+	if v != nil {
+		_c.SetZipCode(*v)
 	}
-	return uc
+	return _c
 }
 
 // SetCountry sets the "country" field.
-func (uc *UserCreate) SetCountry(s string) *UserCreate {
-	uc.mutation.SetCountry(s)
-	return uc
+func (_c *UserCreate) SetCountry(v string) *UserCreate {
+	_c.mutation.SetCountry(v)
+	return _c
 }
 
 // SetNillableCountry sets the "country" field if the given value is not nil.
-func (uc *UserCreate) SetNillableCountry(s *string) *UserCreate {
-	if s != nil {
-		uc.SetCountry(*s)
+func (_c *UserCreate) SetNillableCountry(v *string) *UserCreate {
+	if v != nil {
+		_c.SetCountry(*v)
 	}
-	return uc
+	return _c
 }
 
 // SetState sets the "state" field.
-func (uc *UserCreate) SetState(s string) *UserCreate {
-	uc.mutation.SetState(s)
-	return uc
+func (_c *UserCreate) SetState(v string) *UserCreate {
+	_c.mutation.SetState(v)
+	return _c
 }
 
 // SetNillableState sets the "state" field if the given value is not nil.
-func (uc *UserCreate) SetNillableState(s *string) *UserCreate {
-	if s != nil {
-		uc.SetState(*s)
+func (_c *UserCreate) SetNillableState(v *string) *UserCreate {
+	if v != nil {
+		_c.SetState(*v)
 	}
-	return uc
+	return _c
+}
+
+// SetPublicID sets the "public_id" field.
+func (_c *UserCreate) SetPublicID(v uuid.UUID) *UserCreate {
+	_c.mutation.SetPublicID(v)
+	return _c
+}
+
+// SetNillablePublicID sets the "public_id" field if the given value is not nil.
+func (_c *UserCreate) SetNillablePublicID(v *uuid.UUID) *UserCreate {
+	if v != nil {
+		_c.SetPublicID(*v)
+	}
+	return _c
 }
 
 // SetEmail sets the "email" field.
-func (uc *UserCreate) SetEmail(s string) *UserCreate {
-	uc.mutation.SetEmail(s)
-	return uc
+func (_c *UserCreate) SetEmail(v string) *UserCreate {
+	_c.mutation.SetEmail(v)
+	return _c
 }
 
 // SetUsername sets the "username" field.
-func (uc *UserCreate) SetUsername(s string) *UserCreate {
-	uc.mutation.SetUsername(s)
-	return uc
+func (_c *UserCreate) SetUsername(v string) *UserCreate {
+	_c.mutation.SetUsername(v)
+	return _c
 }
 
 // SetNillableUsername sets the "username" field if the given value is not nil.
-func (uc *UserCreate) SetNillableUsername(s *string) *UserCreate {
-	if s != nil {
-		uc.SetUsername(*s)
+func (_c *UserCreate) SetNillableUsername(v *string) *UserCreate {
+	if v != nil {
+		_c.SetUsername(*v)
 	}
-	return uc
+	return _c
 }
 
 // SetPasswordHash sets the "password_hash" field.
-func (uc *UserCreate) SetPasswordHash(s string) *UserCreate {
-	uc.mutation.SetPasswordHash(s)
-	return uc
+func (_c *UserCreate) SetPasswordHash(v string) *UserCreate {
+	_c.mutation.SetPasswordHash(v)
+	return _c
 }
 
 // SetNillablePasswordHash sets the "password_hash" field if the given value is not nil.
-func (uc *UserCreate) SetNillablePasswordHash(s *string) *UserCreate {
-	if s != nil {
-		uc.SetPasswordHash(*s)
+func (_c *UserCreate) SetNillablePasswordHash(v *string) *UserCreate {
+	if v != nil {
+		_c.SetPasswordHash(*v)
+	}
+	return _c
+}
+
+// SetPasswordChangedAt sets the "password_changed_at" field.
+func (_c *UserCreate) SetPasswordChangedAt(v time.Time) *UserCreate {
+	_c.mutation.SetPasswordChangedAt(v)
+	return _c
+}
+
+// SetNillablePasswordChangedAt sets the "password_changed_at" field if the given value is not nil.
+func (_c *UserCreate) SetNillablePasswordChangedAt(v *time.Time) *UserCreate {
+	if v != nil {
+		_c.SetPasswordChangedAt(*v)
 	}
-	return uc
+	return _c
 }
 
 // SetOauthID sets the "oauth_id" field.
-func (uc *UserCreate) SetOauthID(s string) *UserCreate {
-	uc.mutation.SetOauthID(s)
-	return uc
+func (_c *UserCreate) SetOauthID(v string) *UserCreate {
+	_c.mutation.SetOauthID(v)
+	return _c
 }
 
 // SetNillableOauthID sets the "oauth_id" field if the given value is not nil.
-func (uc *UserCreate) SetNillableOauthID(s *string) *UserCreate {
-	if s != nil {
-		uc.SetOauthID(*s)
+func (_c *UserCreate) SetNillableOauthID(v *string) *UserCreate {
+	if v != nil {
+		_c.SetOauthID(*v)
 	}
-	return uc
+	return _c
 }
 
 // SetProvider sets the "provider" field.
-func (uc *UserCreate) SetProvider(u user.Provider) *UserCreate {
-	uc.mutation.SetProvider(u)
-	return uc
+func (_c *UserCreate) SetProvider(v user.Provider) *UserCreate {
+	_c.mutation.SetProvider(v)
+	return _c
 }
 
 // SetNillableProvider sets the "provider" field if the given value is not nil.
-func (uc *UserCreate) SetNillableProvider(u *user.Provider) *UserCreate {
-	if u != nil {
-		uc.SetProvider(*u)
+func (_c *UserCreate) SetNillableProvider(v *user.Provider) *UserCreate {
+	if v != nil {
+		_c.SetProvider(*v)
 	}
-	return uc
+	return _c
 }
 
 // SetFirstName sets the "first_name" field.
-func (uc *UserCreate) SetFirstName(s string) *UserCreate {
-	uc.mutation.SetFirstName(s)
-	return uc
+func (_c *UserCreate) SetFirstName(v string) *UserCreate {
+	_c.mutation.SetFirstName(v)
+	return _c
 }
 
 // SetNillableFirstName sets the "first_name" field if the given value is not nil.
-func (uc *UserCreate) SetNillableFirstName(s *string) *UserCreate {
-	if s != nil {
-		uc.SetFirstName(*s)
+func (_c *UserCreate) SetNillableFirstName(v *string) *UserCreate {
+	if v != nil {
+		_c.SetFirstName(*v)
 	}
-	return uc
+	return _c
 }
 
 // SetLastName sets the "last_name" field.
-func (uc *UserCreate) SetLastName(s string) *UserCreate {
-	uc.mutation.SetLastName(s)
-	return uc
+func (_c *UserCreate) SetLastName(v string) *UserCreate {
+	_c.mutation.SetLastName(v)
+	return _c
 }
 
 // SetNillableLastName sets the "last_name" field if the given value is not nil.
-func (uc *UserCreate) SetNillableLastName(s *string) *UserCreate {
-	if s != nil {
-		uc.SetLastName(*s)
+func (_c *UserCreate) SetNillableLastName(v *string) *UserCreate {
+	if v != nil {
+		_c.SetLastName(*v)
 	}
-	return uc
+	return _c
 }
 
 // SetPhoneNumber sets the "phone_number" field.
-func (uc *UserCreate) SetPhoneNumber(s string) *UserCreate {
-	uc.mutation.SetPhoneNumber(s)
-	return uc
+func (_c *UserCreate) SetPhoneNumber(v string) *UserCreate {
+	_c.mutation.SetPhoneNumber(v)
+	return _c
 }
 
 // SetNillablePhoneNumber sets the "phone_number" field if the given value is not nil.
-func (uc *UserCreate) SetNillablePhoneNumber(s *string) *UserCreate {
-	if s != nil {
-		uc.SetPhoneNumber(*s)
+func (_c *UserCreate) SetNillablePhoneNumber(v *string) *UserCreate {
+	if v != nil {
+		_c.SetPhoneNumber(*v)
 	}
-	return uc
+	return _c
 }
 
 // SetRole sets the "role" field.
-func (uc *UserCreate) SetRole(u user.Role) *UserCreate {
-	uc.mutation.SetRole(u)
-	return uc
+func (_c *UserCreate) SetRole(v user.Role) *UserCreate {
+	_c.mutation.SetRole(v)
+	return _c
 }
 
 // SetNillableRole sets the "role" field if the given value is not nil.
-func (uc *UserCreate) SetNillableRole(u *user.Role) *UserCreate {
-	if u != nil {
-		uc.SetRole(*u)
+func (_c *UserCreate) SetNillableRole(v *user.Role) *UserCreate {
+	if v != nil {
+		_c.SetRole(*v)
 	}
-	return uc
+	return _c
 }
 
 // SetIsEmailVerified sets the "is_email_verified" field.
-func (uc *UserCreate) SetIsEmailVerified(b bool) *UserCreate {
-	uc.mutation.SetIsEmailVerified(b)
-	return uc
+func (_c *UserCreate) SetIsEmailVerified(v bool) *UserCreate {
+	_c.mutation.SetIsEmailVerified(v)
+	return _c
 }
 
 // SetNillableIsEmailVerified sets the "is_email_verified" field if the given value is not nil.
-func (uc *UserCreate) SetNillableIsEmailVerified(b *bool) *UserCreate {
-	if b != nil {
-		uc.SetIsEmailVerified(*b)
+func (_c *UserCreate) SetNillableIsEmailVerified(v *bool) *UserCreate {
+	if v != nil {
+		_c.SetIsEmailVerified(*v)
 	}
-	return uc
+	return _c
 }
 
 // SetMarketingOptIn sets the "marketing_opt_in" field.
-func (uc *UserCreate) SetMarketingOptIn(b bool) *UserCreate {
-	uc.mutation.SetMarketingOptIn(b)
-	return uc
+func (_c *UserCreate) SetMarketingOptIn(v bool) *UserCreate {
+	_c.mutation.SetMarketingOptIn(v)
+	return _c
 }
 
 // SetNillableMarketingOptIn sets the "marketing_opt_in" field if the given value is not nil.
-func (uc *UserCreate) SetNillableMarketingOptIn(b *bool) *UserCreate {
-	if b != nil {
-		uc.SetMarketingOptIn(*b)
+func (_c *UserCreate) SetNillableMarketingOptIn(v *bool) *UserCreate {
+	if v != nil {
+		_c.SetMarketingOptIn(*v)
 	}
-	return uc
+	return _c
 }
 
 // SetTermsAcceptedAt sets the "terms_accepted_at" field.
-func (uc *UserCreate) SetTermsAcceptedAt(t time.Time) *UserCreate {
-	uc.mutation.SetTermsAcceptedAt(t)
-	return uc
+func (_c *UserCreate) SetTermsAcceptedAt(v time.Time) *UserCreate {
+	_c.mutation.SetTermsAcceptedAt(v)
+	return _c
 }
 
 // SetNillableTermsAcceptedAt sets the "terms_accepted_at" field if the given value is not nil.
-func (uc *UserCreate) SetNillableTermsAcceptedAt(t *time.Time) *UserCreate {
-	if t != nil {
-		uc.SetTermsAcceptedAt(*t)
+func (_c *UserCreate) SetNillableTermsAcceptedAt(v *time.Time) *UserCreate {
+	if v != nil {
+		_c.SetTermsAcceptedAt(*v)
 	}
-	return uc
+	return _c
+}
+
+// SetTermsVersion sets the "terms_version" field.
+func (_c *UserCreate) SetTermsVersion(v string) *UserCreate {
+	_c.mutation.SetTermsVersion(v)
+	return _c
+}
+
+// SetNillableTermsVersion sets the "terms_version" field if the given value is not nil.
+func (_c *UserCreate) SetNillableTermsVersion(v *string) *UserCreate {
+	if v != nil {
+		_c.SetTermsVersion(*v)
+	}
+	return _c
 }
 
 // SetLastLoginAt sets the "last_login_at" field.
-func (uc *UserCreate) SetLastLoginAt(t time.Time) *UserCreate {
-	uc.mutation.SetLastLoginAt(t)
-	return uc
+func (_c *UserCreate) SetLastLoginAt(v time.Time) *UserCreate {
+	_c.mutation.SetLastLoginAt(v)
+	return _c
 }
 
 // SetNillableLastLoginAt sets the "last_login_at" field if the given value is not nil.
-func (uc *UserCreate) SetNillableLastLoginAt(t *time.Time) *UserCreate {
-	if t != nil {
-		uc.SetLastLoginAt(*t)
+func (_c *UserCreate) SetNillableLastLoginAt(v *time.Time) *UserCreate {
+	if v != nil {
+		_c.SetLastLoginAt(*v)
+	}
+	return _c
+}
+
+// SetOnboardingStep sets the "onboarding_step" field.
+func (_c *UserCreate) SetOnboardingStep(v user.OnboardingStep) *UserCreate {
+	_c.mutation.SetOnboardingStep(v)
+	return _c
+}
+
+// SetNillableOnboardingStep sets the "onboarding_step" field if the given value is not nil.
+func (_c *UserCreate) SetNillableOnboardingStep(v *user.OnboardingStep) *UserCreate {
+	if v != nil {
+		_c.SetOnboardingStep(*v)
+	}
+	return _c
+}
+
+// SetSuspendedAt sets the "suspended_at" field.
+func (_c *UserCreate) SetSuspendedAt(v time.Time) *UserCreate {
+	_c.mutation.SetSuspendedAt(v)
+	return _c
+}
+
+// SetNillableSuspendedAt sets the "suspended_at" field if the given value is not nil.
+func (_c *UserCreate) SetNillableSuspendedAt(v *time.Time) *UserCreate {
+	if v != nil {
+		_c.SetSuspendedAt(*v)
+	}
+	return _c
+}
+
+// SetSuspensionReason sets the "suspension_reason" field.
+func (_c *UserCreate) SetSuspensionReason(v user.SuspensionReason) *UserCreate {
+	_c.mutation.SetSuspensionReason(v)
+	return _c
+}
+
+// SetNillableSuspensionReason sets the "suspension_reason" field if the given value is not nil.
+func (_c *UserCreate) SetNillableSuspensionReason(v *user.SuspensionReason) *UserCreate {
+	if v != nil {
+		_c.SetSuspensionReason(*v)
+	}
+	return _c
+}
+
+// SetResidency sets the "residency" field.
+func (_c *UserCreate) SetResidency(v string) *UserCreate {
+	_c.mutation.SetResidency(v)
+	return _c
+}
+
+// SetNillableResidency sets the "residency" field if the given value is not nil.
+func (_c *UserCreate) SetNillableResidency(v *string) *UserCreate {
+	if v != nil {
+		_c.SetResidency(*v)
+	}
+	return _c
+}
+
+// SetDeactivatedAt sets the "deactivated_at" field.
+func (_c *UserCreate) SetDeactivatedAt(v time.Time) *UserCreate {
+	_c.mutation.SetDeactivatedAt(v)
+	return _c
+}
+
+// SetNillableDeactivatedAt sets the "deactivated_at" field if the given value is not nil.
+func (_c *UserCreate) SetNillableDeactivatedAt(v *time.Time) *UserCreate {
+	if v != nil {
+		_c.SetDeactivatedAt(*v)
+	}
+	return _c
+}
+
+// SetOrganizationID sets the "organization_id" field.
+func (_c *UserCreate) SetOrganizationID(v int64) *UserCreate {
+	_c.mutation.SetOrganizationID(v)
+	return _c
+}
+
+// SetNillableOrganizationID sets the "organization_id" field if the given value is not nil.
+func (_c *UserCreate) SetNillableOrganizationID(v *int64) *UserCreate {
+	if v != nil {
+		_c.SetOrganizationID(*v)
 	}
-	return uc
+	return _c
+}
+
+// SetKind sets the "kind" field.
+func (_c *UserCreate) SetKind(v user.Kind) *UserCreate {
+	_c.mutation.SetKind(v)
+	return _c
+}
+
+// SetNillableKind sets the "kind" field if the given value is not nil.
+func (_c *UserCreate) SetNillableKind(v *user.Kind) *UserCreate {
+	if v != nil {
+		_c.SetKind(*v)
+	}
+	return _c
 }
 
 // SetID sets the "id" field.
-func (uc *UserCreate) SetID(i int64) *UserCreate {
-	uc.mutation.SetID(i)
-	return uc
+func (_c *UserCreate) SetID(v int64) *UserCreate {
+	_c.mutation.SetID(v)
+	return _c
 }
 
 // SetAddressID sets the "address" edge to the UserAddress entity by ID.
-func (uc *UserCreate) SetAddressID(id int) *UserCreate {
-	uc.mutation.SetAddressID(id)
-	return uc
+func (_c *UserCreate) SetAddressID(id int) *UserCreate {
+	_c.mutation.SetAddressID(id)
+	return _c
 }
 
 // SetNillableAddressID sets the "address" edge to the UserAddress entity by ID if the given value is not nil.
-func (uc *UserCreate) SetNillableAddressID(id *int) *UserCreate {
+func (_c *UserCreate) SetNillableAddressID(id *int) *UserCreate {
 	if id != nil {
-		uc = uc.SetAddressID(*id)
+		_c = _c.SetAddressID(*id)
 	}
-	return uc
+	return _c
 }
 
 // SetAddress sets the "address" edge to the UserAddress entity.
-func (uc *UserCreate) SetAddress(u *UserAddress) *UserCreate {
-	return uc.SetAddressID(u.ID)
+func (_c *UserCreate) SetAddress(v *UserAddress) *UserCreate {
+	return _c.SetAddressID(v.ID)
+}
+
+// AddLoginAttemptIDs adds the "login_attempts" edge to the LoginAttempt entity by IDs.
+func (_c *UserCreate) AddLoginAttemptIDs(ids ...int64) *UserCreate {
+	_c.mutation.AddLoginAttemptIDs(ids...)
+	return _c
+}
+
+// AddLoginAttempts adds the "login_attempts" edges to the LoginAttempt entity.
+func (_c *UserCreate) AddLoginAttempts(v ...*LoginAttempt) *UserCreate {
+	ids := make([]int64, len(v))
+	for i := range v {
+		ids[i] = v[i].ID
+	}
+	return _c.AddLoginAttemptIDs(ids...)
+}
+
+// SetOrganization sets the "organization" edge to the Organization entity.
+func (_c *UserCreate) SetOrganization(v *Organization) *UserCreate {
+	return _c.SetOrganizationID(v.ID)
+}
+
+// AddServiceAccountKeyIDs adds the "service_account_keys" edge to the ServiceAccountKey entity by IDs.
+func (_c *UserCreate) AddServiceAccountKeyIDs(ids ...int64) *UserCreate {
+	_c.mutation.AddServiceAccountKeyIDs(ids...)
+	return _c
+}
+
+// AddServiceAccountKeys adds the "service_account_keys" edges to the ServiceAccountKey entity.
+func (_c *UserCreate) AddServiceAccountKeys(v ...*ServiceAccountKey) *UserCreate {
+	ids := make([]int64, len(v))
+	for i := range v {
+		ids[i] = v[i].ID
+	}
+	return _c.AddServiceAccountKeyIDs(ids...)
+}
+
+// AddIdentityIDs adds the "identities" edge to the UserIdentity entity by IDs.
+func (_c *UserCreate) AddIdentityIDs(ids ...int64) *UserCreate {
+	_c.mutation.AddIdentityIDs(ids...)
+	return _c
+}
+
+// AddIdentities adds the "identities" edges to the UserIdentity entity.
+func (_c *UserCreate) AddIdentities(v ...*UserIdentity) *UserCreate {
+	ids := make([]int64, len(v))
+	for i := range v {
+		ids[i] = v[i].ID
+	}
+	return _c.AddIdentityIDs(ids...)
+}
+
+// AddScheduledActionIDs adds the "scheduled_actions" edge to the ScheduledAction entity by IDs.
+func (_c *UserCreate) AddScheduledActionIDs(ids ...int64) *UserCreate {
+	_c.mutation.AddScheduledActionIDs(ids...)
+	return _c
+}
+
+// AddScheduledActions adds the "scheduled_actions" edges to the ScheduledAction entity.
+func (_c *UserCreate) AddScheduledActions(v ...*ScheduledAction) *UserCreate {
+	ids := make([]int64, len(v))
+	for i := range v {
+		ids[i] = v[i].ID
+	}
+	return _c.AddScheduledActionIDs(ids...)
 }
 
 // Mutation returns the UserMutation object of the builder.
-func (uc *UserCreate) Mutation() *UserMutation {
-	return uc.mutation
+func (_c *UserCreate) Mutation() *UserMutation {
+	return _c.mutation
 }
 
 // Save creates the User in the database.
-func (uc *UserCreate) Save(ctx context.Context) (*User, error) {
-	uc.defaults()
-	return withHooks(ctx, uc.sqlSave, uc.mutation, uc.hooks)
+func (_c *UserCreate) Save(ctx context.Context) (*User, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
 }
 
 // SaveX calls Save and panics if Save returns an error.
-func (uc *UserCreate) SaveX(ctx context.Context) *User {
-	v, err := uc.Save(ctx)
+func (_c *UserCreate) SaveX(ctx context.Context) *User {
+	v, err := _c.Save(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -353,192 +564,238 @@ func (uc *UserCreate) SaveX(ctx context.Context) *User {
 }
 
 // Exec executes the query.
-func (uc *UserCreate) Exec(ctx context.Context) error {
-	_, err := uc.Save(ctx)
+func (_c *UserCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
 	return err
 }
 
 // ExecX is like Exec, but panics if an error occurs.
-func (uc *UserCreate) ExecX(ctx context.Context) {
-	if err := uc.Exec(ctx); err != nil {
+func (_c *UserCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
 		panic(err)
 	}
 }
 
 // defaults sets the default values of the builder before save.
-func (uc *UserCreate) defaults() {
-	if _, ok := uc.mutation.CreatedAt(); !ok {
+func (_c *UserCreate) defaults() {
+	if _, ok := _c.mutation.CreatedAt(); !ok {
 		v := user.DefaultCreatedAt()
-		uc.mutation.SetCreatedAt(v)
+		_c.mutation.SetCreatedAt(v)
 	}
-	if _, ok := uc.mutation.UpdatedAt(); !ok {
+	if _, ok := _c.mutation.UpdatedAt(); !ok {
 		v := user.DefaultUpdatedAt()
-		uc.mutation.SetUpdatedAt(v)
+		_c.mutation.SetUpdatedAt(v)
 	}
-	if _, ok := uc.mutation.StreetName(); !ok {
+	if _, ok := _c.mutation.StreetName(); !ok {
 		v := user.DefaultStreetName
-		uc.mutation.SetStreetName(v)
+		_c.mutation.SetStreetName(v)
 	}
-	if _, ok := uc.mutation.City(); !ok {
+	if _, ok := _c.mutation.City(); !ok {
 		v := user.DefaultCity
-		uc.mutation.SetCity(v)
+		_c.mutation.SetCity(v)
 	}
-	if _, ok := uc.mutation.ZipCode(); !ok {
+	if _, ok := _c.mutation.ZipCode(); !ok {
 		v := user.DefaultZipCode
-		uc.mutation.SetZipCode(v)
+		_c.mutation.SetZipCode(v)
 	}
-	if _, ok := uc.mutation.Country(); !ok {
+	if _, ok := _c.mutation.Country(); !ok {
 		v := user.DefaultCountry
-		uc.mutation.SetCountry(v)
+		_c.mutation.SetCountry(v)
 	}
-	if _, ok := uc.mutation.State(); !ok {
+	if _, ok := _c.mutation.State(); !ok {
 		v := user.DefaultState
-		uc.mutation.SetState(v)
+		_c.mutation.SetState(v)
 	}
-	if _, ok := uc.mutation.Provider(); !ok {
+	if _, ok := _c.mutation.PublicID(); !ok {
+		v := user.DefaultPublicID()
+		_c.mutation.SetPublicID(v)
+	}
+	if _, ok := _c.mutation.Provider(); !ok {
 		v := user.DefaultProvider
-		uc.mutation.SetProvider(v)
+		_c.mutation.SetProvider(v)
 	}
-	if _, ok := uc.mutation.FirstName(); !ok {
+	if _, ok := _c.mutation.FirstName(); !ok {
 		v := user.DefaultFirstName
-		uc.mutation.SetFirstName(v)
+		_c.mutation.SetFirstName(v)
 	}
-	if _, ok := uc.mutation.LastName(); !ok {
+	if _, ok := _c.mutation.LastName(); !ok {
 		v := user.DefaultLastName
-		uc.mutation.SetLastName(v)
+		_c.mutation.SetLastName(v)
 	}
-	if _, ok := uc.mutation.Role(); !ok {
+	if _, ok := _c.mutation.Role(); !ok {
 		v := user.DefaultRole
-		uc.mutation.SetRole(v)
+		_c.mutation.SetRole(v)
 	}
-	if _, ok := uc.mutation.IsEmailVerified(); !ok {
+	if _, ok := _c.mutation.IsEmailVerified(); !ok {
 		v := user.DefaultIsEmailVerified
-		uc.mutation.SetIsEmailVerified(v)
+		_c.mutation.SetIsEmailVerified(v)
 	}
-	if _, ok := uc.mutation.MarketingOptIn(); !ok {
+	if _, ok := _c.mutation.MarketingOptIn(); !ok {
 		v := user.DefaultMarketingOptIn
-		uc.mutation.SetMarketingOptIn(v)
+		_c.mutation.SetMarketingOptIn(v)
+	}
+	if _, ok := _c.mutation.OnboardingStep(); !ok {
+		v := user.DefaultOnboardingStep
+		_c.mutation.SetOnboardingStep(v)
+	}
+	if _, ok := _c.mutation.Kind(); !ok {
+		v := user.DefaultKind
+		_c.mutation.SetKind(v)
 	}
 }
 
 // check runs all checks and user-defined validators on the builder.
-func (uc *UserCreate) check() error {
-	if _, ok := uc.mutation.CreatedAt(); !ok {
+func (_c *UserCreate) check() error {
+	if _, ok := _c.mutation.CreatedAt(); !ok {
 		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "User.created_at"`)}
 	}
-	if _, ok := uc.mutation.UpdatedAt(); !ok {
+	if _, ok := _c.mutation.UpdatedAt(); !ok {
 		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "User.updated_at"`)}
 	}
-	if _, ok := uc.mutation.StreetName(); !ok {
+	if _, ok := _c.mutation.StreetName(); !ok {
 		return &ValidationError{Name: "street_name", err: errors.New(`ent: missing required field "User.street_name"`)}
 	}
-	if v, ok := uc.mutation.StreetName(); ok {
+	if v, ok := _c.mutation.StreetName(); ok {
 		if err := user.StreetNameValidator(v); err != nil {
 			return &ValidationError{Name: "street_name", err: fmt.Errorf(`ent: validator failed for field "User.street_name": %w`, err)}
 		}
 	}
-	if _, ok := uc.mutation.City(); !ok {
+	if _, ok := _c.mutation.City(); !ok {
 		return &ValidationError{Name: "city", err: errors.New(`ent: missing required field "User.city"`)}
 	}
-	if v, ok := uc.mutation.City(); ok {
+	if v, ok := _c.mutation.City(); ok {
 		if err := user.CityValidator(v); err != nil {
 			return &ValidationError{Name: "city", err: fmt.Errorf(`ent: validator failed for field "User.city": %w`, err)}
 		}
 	}
-	if _, ok := uc.mutation.ZipCode(); !ok {
+	if _, ok := _c.mutation.ZipCode(); !ok {
 		return &ValidationError{Name: "zip_code", err: errors.New(`ent: missing required field "User.zip_code"`)}
 	}
-	if v, ok := uc.mutation.ZipCode(); ok {
+	if v, ok := _c.mutation.ZipCode(); ok {
 		if err := user.ZipCodeValidator(v); err != nil {
 			return &ValidationError{Name: "zip_code", err: fmt.Errorf(`ent: validator failed for field "User.zip_code": %w`, err)}
 		}
 	}
-	if _, ok := uc.mutation.Country(); !ok {
+	if _, ok := _c.mutation.Country(); !ok {
 		return &ValidationError{Name: "country", err: errors.New(`ent: missing required field "User.country"`)}
 	}
-	if v, ok := uc.mutation.Country(); ok {
+	if v, ok := _c.mutation.Country(); ok {
 		if err := user.CountryValidator(v); err != nil {
 			return &ValidationError{Name: "country", err: fmt.Errorf(`ent: validator failed for field "User.country": %w`, err)}
 		}
 	}
-	if _, ok := uc.mutation.State(); !ok {
+	if _, ok := _c.mutation.State(); !ok {
 		return &ValidationError{Name: "state", err: errors.New(`ent: missing required field "User.state"`)}
 	}
-	if v, ok := uc.mutation.State(); ok {
+	if v, ok := _c.mutation.State(); ok {
 		if err := user.StateValidator(v); err != nil {
 			return &ValidationError{Name: "state", err: fmt.Errorf(`ent: validator failed for field "User.state": %w`, err)}
 		}
 	}
-	if _, ok := uc.mutation.Email(); !ok {
+	if _, ok := _c.mutation.PublicID(); !ok {
+		return &ValidationError{Name: "public_id", err: errors.New(`ent: missing required field "User.public_id"`)}
+	}
+	if _, ok := _c.mutation.Email(); !ok {
 		return &ValidationError{Name: "email", err: errors.New(`ent: missing required field "User.email"`)}
 	}
-	if v, ok := uc.mutation.Email(); ok {
+	if v, ok := _c.mutation.Email(); ok {
 		if err := user.EmailValidator(v); err != nil {
 			return &ValidationError{Name: "email", err: fmt.Errorf(`ent: validator failed for field "User.email": %w`, err)}
 		}
 	}
-	if v, ok := uc.mutation.Username(); ok {
+	if v, ok := _c.mutation.Username(); ok {
 		if err := user.UsernameValidator(v); err != nil {
 			return &ValidationError{Name: "username", err: fmt.Errorf(`ent: validator failed for field "User.username": %w`, err)}
 		}
 	}
-	if v, ok := uc.mutation.OauthID(); ok {
+	if v, ok := _c.mutation.OauthID(); ok {
 		if err := user.OauthIDValidator(v); err != nil {
 			return &ValidationError{Name: "oauth_id", err: fmt.Errorf(`ent: validator failed for field "User.oauth_id": %w`, err)}
 		}
 	}
-	if _, ok := uc.mutation.Provider(); !ok {
+	if _, ok := _c.mutation.Provider(); !ok {
 		return &ValidationError{Name: "provider", err: errors.New(`ent: missing required field "User.provider"`)}
 	}
-	if v, ok := uc.mutation.Provider(); ok {
+	if v, ok := _c.mutation.Provider(); ok {
 		if err := user.ProviderValidator(v); err != nil {
 			return &ValidationError{Name: "provider", err: fmt.Errorf(`ent: validator failed for field "User.provider": %w`, err)}
 		}
 	}
-	if _, ok := uc.mutation.FirstName(); !ok {
+	if _, ok := _c.mutation.FirstName(); !ok {
 		return &ValidationError{Name: "first_name", err: errors.New(`ent: missing required field "User.first_name"`)}
 	}
-	if v, ok := uc.mutation.FirstName(); ok {
+	if v, ok := _c.mutation.FirstName(); ok {
 		if err := user.FirstNameValidator(v); err != nil {
 			return &ValidationError{Name: "first_name", err: fmt.Errorf(`ent: validator failed for field "User.first_name": %w`, err)}
 		}
 	}
-	if _, ok := uc.mutation.LastName(); !ok {
+	if _, ok := _c.mutation.LastName(); !ok {
 		return &ValidationError{Name: "last_name", err: errors.New(`ent: missing required field "User.last_name"`)}
 	}
-	if v, ok := uc.mutation.LastName(); ok {
+	if v, ok := _c.mutation.LastName(); ok {
 		if err := user.LastNameValidator(v); err != nil {
 			return &ValidationError{Name: "last_name", err: fmt.Errorf(`ent: validator failed for field "User.last_name": %w`, err)}
 		}
 	}
-	if v, ok := uc.mutation.PhoneNumber(); ok {
+	if v, ok := _c.mutation.PhoneNumber(); ok {
 		if err := user.PhoneNumberValidator(v); err != nil {
 			return &ValidationError{Name: "phone_number", err: fmt.Errorf(`ent: validator failed for field "User.phone_number": %w`, err)}
 		}
 	}
-	if _, ok := uc.mutation.Role(); !ok {
+	if _, ok := _c.mutation.Role(); !ok {
 		return &ValidationError{Name: "role", err: errors.New(`ent: missing required field "User.role"`)}
 	}
-	if v, ok := uc.mutation.Role(); ok {
+	if v, ok := _c.mutation.Role(); ok {
 		if err := user.RoleValidator(v); err != nil {
 			return &ValidationError{Name: "role", err: fmt.Errorf(`ent: validator failed for field "User.role": %w`, err)}
 		}
 	}
-	if _, ok := uc.mutation.IsEmailVerified(); !ok {
+	if _, ok := _c.mutation.IsEmailVerified(); !ok {
 		return &ValidationError{Name: "is_email_verified", err: errors.New(`ent: missing required field "User.is_email_verified"`)}
 	}
-	if _, ok := uc.mutation.MarketingOptIn(); !ok {
+	if _, ok := _c.mutation.MarketingOptIn(); !ok {
 		return &ValidationError{Name: "marketing_opt_in", err: errors.New(`ent: missing required field "User.marketing_opt_in"`)}
 	}
+	if v, ok := _c.mutation.TermsVersion(); ok {
+		if err := user.TermsVersionValidator(v); err != nil {
+			return &ValidationError{Name: "terms_version", err: fmt.Errorf(`ent: validator failed for field "User.terms_version": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.OnboardingStep(); !ok {
+		return &ValidationError{Name: "onboarding_step", err: errors.New(`ent: missing required field "User.onboarding_step"`)}
+	}
+	if v, ok := _c.mutation.OnboardingStep(); ok {
+		if err := user.OnboardingStepValidator(v); err != nil {
+			return &ValidationError{Name: "onboarding_step", err: fmt.Errorf(`ent: validator failed for field "User.onboarding_step": %w`, err)}
+		}
+	}
+	if v, ok := _c.mutation.SuspensionReason(); ok {
+		if err := user.SuspensionReasonValidator(v); err != nil {
+			return &ValidationError{Name: "suspension_reason", err: fmt.Errorf(`ent: validator failed for field "User.suspension_reason": %w`, err)}
+		}
+	}
+	if v, ok := _c.mutation.Residency(); ok {
+		if err := user.ResidencyValidator(v); err != nil {
+			return &ValidationError{Name: "residency", err: fmt.Errorf(`ent: validator failed for field "User.residency": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Kind(); !ok {
+		return &ValidationError{Name: "kind", err: errors.New(`ent: missing required field "User.kind"`)}
+	}
+	if v, ok := _c.mutation.Kind(); ok {
+		if err := user.KindValidator(v); err != nil {
+			return &ValidationError{Name: "kind", err: fmt.Errorf(`ent: validator failed for field "User.kind": %w`, err)}
+		}
+	}
 	return nil
 }
 
-func (uc *UserCreate) sqlSave(ctx context.Context) (*User, error) {
-	if err := uc.check(); err != nil {
+func (_c *UserCreate) sqlSave(ctx context.Context) (*User, error) {
+	if err := _c.check(); err != nil {
 		return nil, err
 	}
-	_node, _spec := uc.createSpec()
-	if err := sqlgraph.CreateNode(ctx, uc.driver, _spec); err != nil {
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
 		if sqlgraph.IsConstraintError(err) {
 			err = &ConstraintError{msg: err.Error(), wrap: err}
 		}
@@ -548,105 +805,141 @@ func (uc *UserCreate) sqlSave(ctx context.Context) (*User, error) {
 		id := _spec.ID.Value.(int64)
 		_node.ID = int64(id)
 	}
-	uc.mutation.id = &_node.ID
-	uc.mutation.done = true
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
 	return _node, nil
 }
 
-func (uc *UserCreate) createSpec() (*User, *sqlgraph.CreateSpec) {
+func (_c *UserCreate) createSpec() (*User, *sqlgraph.CreateSpec) {
 	var (
-		_node = &User{config: uc.config}
+		_node = &User{config: _c.config}
 		_spec = sqlgraph.NewCreateSpec(user.Table, sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt64))
 	)
-	if id, ok := uc.mutation.ID(); ok {
+	if id, ok := _c.mutation.ID(); ok {
 		_node.ID = id
 		_spec.ID.Value = id
 	}
-	if value, ok := uc.mutation.CreatedAt(); ok {
+	if value, ok := _c.mutation.CreatedAt(); ok {
 		_spec.SetField(user.FieldCreatedAt, field.TypeTime, value)
 		_node.CreatedAt = value
 	}
-	if value, ok := uc.mutation.UpdatedAt(); ok {
+	if value, ok := _c.mutation.UpdatedAt(); ok {
 		_spec.SetField(user.FieldUpdatedAt, field.TypeTime, value)
 		_node.UpdatedAt = value
 	}
-	if value, ok := uc.mutation.DeletedAt(); ok {
+	if value, ok := _c.mutation.DeletedAt(); ok {
 		_spec.SetField(user.FieldDeletedAt, field.TypeTime, value)
 		_node.DeletedAt = &value
 	}
-	if value, ok := uc.mutation.StreetName(); ok {
+	if value, ok := _c.mutation.StreetName(); ok {
 		_spec.SetField(user.FieldStreetName, field.TypeString, value)
 		_node.StreetName = value
 	}
-	if value, ok := uc.mutation.City(); ok {
+	if value, ok := _c.mutation.City(); ok {
 		_spec.SetField(user.FieldCity, field.TypeString, value)
 		_node.City = value
 	}
-	if value, ok := uc.mutation.ZipCode(); ok {
+	if value, ok := _c.mutation.ZipCode(); ok {
 		_spec.SetField(user.FieldZipCode, field.TypeString, value)
 		_node.ZipCode = value
 	}
-	if value, ok := uc.mutation.Country(); ok {
+	if value, ok := _c.mutation.Country(); ok {
 		_spec.SetField(user.FieldCountry, field.TypeString, value)
 		_node.Country = value
 	}
-	if value, ok := uc.mutation.State(); ok {
+	if value, ok := _c.mutation.State(); ok {
 		_spec.SetField(user.FieldState, field.TypeString, value)
 		_node.State = value
 	}
-	if value, ok := uc.mutation.Email(); ok {
+	if value, ok := _c.mutation.PublicID(); ok {
+		_spec.SetField(user.FieldPublicID, field.TypeUUID, value)
+		_node.PublicID = value
+	}
+	if value, ok := _c.mutation.Email(); ok {
 		_spec.SetField(user.FieldEmail, field.TypeString, value)
 		_node.Email = value
 	}
-	if value, ok := uc.mutation.Username(); ok {
+	if value, ok := _c.mutation.Username(); ok {
 		_spec.SetField(user.FieldUsername, field.TypeString, value)
 		_node.Username = value
 	}
-	if value, ok := uc.mutation.PasswordHash(); ok {
+	if value, ok := _c.mutation.PasswordHash(); ok {
 		_spec.SetField(user.FieldPasswordHash, field.TypeString, value)
 		_node.PasswordHash = value
 	}
-	if value, ok := uc.mutation.OauthID(); ok {
+	if value, ok := _c.mutation.PasswordChangedAt(); ok {
+		_spec.SetField(user.FieldPasswordChangedAt, field.TypeTime, value)
+		_node.PasswordChangedAt = &value
+	}
+	if value, ok := _c.mutation.OauthID(); ok {
 		_spec.SetField(user.FieldOauthID, field.TypeString, value)
 		_node.OauthID = value
 	}
-	if value, ok := uc.mutation.Provider(); ok {
+	if value, ok := _c.mutation.Provider(); ok {
 		_spec.SetField(user.FieldProvider, field.TypeEnum, value)
 		_node.Provider = value
 	}
-	if value, ok := uc.mutation.FirstName(); ok {
+	if value, ok := _c.mutation.FirstName(); ok {
 		_spec.SetField(user.FieldFirstName, field.TypeString, value)
 		_node.FirstName = value
 	}
-	if value, ok := uc.mutation.LastName(); ok {
+	if value, ok := _c.mutation.LastName(); ok {
 		_spec.SetField(user.FieldLastName, field.TypeString, value)
 		_node.LastName = value
 	}
-	if value, ok := uc.mutation.PhoneNumber(); ok {
+	if value, ok := _c.mutation.PhoneNumber(); ok {
 		_spec.SetField(user.FieldPhoneNumber, field.TypeString, value)
 		_node.PhoneNumber = value
 	}
-	if value, ok := uc.mutation.Role(); ok {
+	if value, ok := _c.mutation.Role(); ok {
 		_spec.SetField(user.FieldRole, field.TypeEnum, value)
 		_node.Role = value
 	}
-	if value, ok := uc.mutation.IsEmailVerified(); ok {
+	if value, ok := _c.mutation.IsEmailVerified(); ok {
 		_spec.SetField(user.FieldIsEmailVerified, field.TypeBool, value)
 		_node.IsEmailVerified = value
 	}
-	if value, ok := uc.mutation.MarketingOptIn(); ok {
+	if value, ok := _c.mutation.MarketingOptIn(); ok {
 		_spec.SetField(user.FieldMarketingOptIn, field.TypeBool, value)
 		_node.MarketingOptIn = value
 	}
-	if value, ok := uc.mutation.TermsAcceptedAt(); ok {
+	if value, ok := _c.mutation.TermsAcceptedAt(); ok {
 		_spec.SetField(user.FieldTermsAcceptedAt, field.TypeTime, value)
 		_node.TermsAcceptedAt = &value
 	}
-	if value, ok := uc.mutation.LastLoginAt(); ok {
+	if value, ok := _c.mutation.TermsVersion(); ok {
+		_spec.SetField(user.FieldTermsVersion, field.TypeString, value)
+		_node.TermsVersion = value
+	}
+	if value, ok := _c.mutation.LastLoginAt(); ok {
 		_spec.SetField(user.FieldLastLoginAt, field.TypeTime, value)
 		_node.LastLoginAt = &value
 	}
-	if nodes := uc.mutation.AddressIDs(); len(nodes) > 0 {
+	if value, ok := _c.mutation.OnboardingStep(); ok {
+		_spec.SetField(user.FieldOnboardingStep, field.TypeEnum, value)
+		_node.OnboardingStep = value
+	}
+	if value, ok := _c.mutation.SuspendedAt(); ok {
+		_spec.SetField(user.FieldSuspendedAt, field.TypeTime, value)
+		_node.SuspendedAt = &value
+	}
+	if value, ok := _c.mutation.SuspensionReason(); ok {
+		_spec.SetField(user.FieldSuspensionReason, field.TypeEnum, value)
+		_node.SuspensionReason = &value
+	}
+	if value, ok := _c.mutation.Residency(); ok {
+		_spec.SetField(user.FieldResidency, field.TypeString, value)
+		_node.Residency = value
+	}
+	if value, ok := _c.mutation.DeactivatedAt(); ok {
+		_spec.SetField(user.FieldDeactivatedAt, field.TypeTime, value)
+		_node.DeactivatedAt = &value
+	}
+	if value, ok := _c.mutation.Kind(); ok {
+		_spec.SetField(user.FieldKind, field.TypeEnum, value)
+		_node.Kind = value
+	}
+	if nodes := _c.mutation.AddressIDs(); len(nodes) > 0 {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.M2O,
 			Inverse: false,
@@ -663,6 +956,87 @@ func (uc *UserCreate) createSpec() (*User, *sqlgraph.CreateSpec) {
 		_node.user_address = &nodes[0]
 		_spec.Edges = append(_spec.Edges, edge)
 	}
+	if nodes := _c.mutation.LoginAttemptsIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.LoginAttemptsTable,
+			Columns: []string{user.LoginAttemptsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(loginattempt.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := _c.mutation.OrganizationIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   user.OrganizationTable,
+			Columns: []string{user.OrganizationColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(organization.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.OrganizationID = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := _c.mutation.ServiceAccountKeysIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.ServiceAccountKeysTable,
+			Columns: []string{user.ServiceAccountKeysColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(serviceaccountkey.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := _c.mutation.IdentitiesIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.IdentitiesTable,
+			Columns: []string{user.IdentitiesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(useridentity.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := _c.mutation.ScheduledActionsIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.ScheduledActionsTable,
+			Columns: []string{user.ScheduledActionsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(scheduledaction.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
 	return _node, _spec
 }
 
@@ -674,16 +1048,16 @@ type UserCreateBulk struct {
 }
 
 // Save creates the User entities in the database.
-func (ucb *UserCreateBulk) Save(ctx context.Context) ([]*User, error) {
-	if ucb.err != nil {
-		return nil, ucb.err
-	}
-	specs := make([]*sqlgraph.CreateSpec, len(ucb.builders))
-	nodes := make([]*User, len(ucb.builders))
-	mutators := make([]Mutator, len(ucb.builders))
-	for i := range ucb.builders {
+func (_c *UserCreateBulk) Save(ctx context.Context) ([]*User, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*User, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
 		func(i int, root context.Context) {
-			builder := ucb.builders[i]
+			builder := _c.builders[i]
 			builder.defaults()
 			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
 				mutation, ok := m.(*UserMutation)
@@ -697,11 +1071,11 @@ func (ucb *UserCreateBulk) Save(ctx context.Context) ([]*User, error) {
 				var err error
 				nodes[i], specs[i] = builder.createSpec()
 				if i < len(mutators)-1 {
-					_, err = mutators[i+1].Mutate(root, ucb.builders[i+1].mutation)
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
 				} else {
 					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
 					// Invoke the actual operation on the latest mutation in the chain.
-					if err = sqlgraph.BatchCreate(ctx, ucb.driver, spec); err != nil {
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
 						if sqlgraph.IsConstraintError(err) {
 							err = &ConstraintError{msg: err.Error(), wrap: err}
 						}
@@ -725,7 +1099,7 @@ func (ucb *UserCreateBulk) Save(ctx context.Context) ([]*User, error) {
 		}(i, ctx)
 	}
 	if len(mutators) > 0 {
-		if _, err := mutators[0].Mutate(ctx, ucb.builders[0].mutation); err != nil {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
 			return nil, err
 		}
 	}
@@ -733,8 +1107,8 @@ func (ucb *UserCreateBulk) Save(ctx context.Context) ([]*User, error) {
 }
 
 // SaveX is like Save, but panics if an error occurs.
-func (ucb *UserCreateBulk) SaveX(ctx context.Context) []*User {
-	v, err := ucb.Save(ctx)
+func (_c *UserCreateBulk) SaveX(ctx context.Context) []*User {
+	v, err := _c.Save(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -742,14 +1116,14 @@ func (ucb *UserCreateBulk) SaveX(ctx context.Context) []*User {
 }
 
 // Exec executes the query.
-func (ucb *UserCreateBulk) Exec(ctx context.Context) error {
-	_, err := ucb.Save(ctx)
+func (_c *UserCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
 	return err
 }
 
 // ExecX is like Exec, but panics if an error occurs.
-func (ucb *UserCreateBulk) ExecX(ctx context.Context) {
-	if err := ucb.Exec(ctx); err != nil {
+func (_c *UserCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
 		panic(err)
 	}
 }
@@ -11,9 +11,14 @@ import (
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"entgo.io/ent/schema/field"
+	"github.com/abisalde/authentication-service/internal/database/ent/loginattempt"
+	"github.com/abisalde/authentication-service/internal/database/ent/organization"
 	"github.com/abisalde/authentication-service/internal/database/ent/predicate"
+	"github.com/abisalde/authentication-service/internal/database/ent/scheduledaction"
+	"github.com/abisalde/authentication-service/internal/database/ent/serviceaccountkey"
 	"github.com/abisalde/authentication-service/internal/database/ent/user"
 	"github.com/abisalde/authentication-service/internal/database/ent/useraddress"
+	"github.com/abisalde/authentication-service/internal/database/ent/useridentity"
 )
 
 // UserUpdate is the builder for updating User entities.
@@ -24,364 +29,687 @@ type UserUpdate struct {
 }
 
 // Where appends a list predicates to the UserUpdate builder.
-func (uu *UserUpdate) Where(ps ...predicate.User) *UserUpdate {
-	uu.mutation.Where(ps...)
-	return uu
+func (_u *UserUpdate) Where(ps ...predicate.User) *UserUpdate {
+	_u.mutation.Where(ps...)
+	return _u
 }
 
 // SetUpdatedAt sets the "updated_at" field.
-func (uu *UserUpdate) SetUpdatedAt(t time.Time) *UserUpdate {
-	uu.mutation.SetUpdatedAt(t)
-	return uu
+func (_u *UserUpdate) SetUpdatedAt(v time.Time) *UserUpdate {
+	_u.mutation.SetUpdatedAt(v)
+	return _u
 }
 
 // SetDeletedAt sets the "deleted_at" field.
-func (uu *UserUpdate) SetDeletedAt(t time.Time) *UserUpdate {
-	uu.mutation.SetDeletedAt(t)
-	return uu
+func (_u *UserUpdate) SetDeletedAt(v time.Time) *UserUpdate {
+	_u.mutation.SetDeletedAt(v)
+	return _u
 }
 
 // SetNillableDeletedAt sets the "deleted_at" field if the given value is not nil.
-func (uu *UserUpdate) SetNillableDeletedAt(t *time.Time) *UserUpdate {
-	if t != nil {
-		uu.SetDeletedAt(*t)
+func (_u *UserUpdate) SetNillableDeletedAt(v *time.Time) *UserUpdate {
+	if v != nil {
+		_u.SetDeletedAt(*v)
 	}
-	return uu
+	return _u
 }
 
 // ClearDeletedAt clears the value of the "deleted_at" field.
-func (uu *UserUpdate) ClearDeletedAt() *UserUpdate {
-	uu.mutation.ClearDeletedAt()
-	return uu
+func (_u *UserUpdate) ClearDeletedAt() *UserUpdate {
+	_u.mutation.ClearDeletedAt()
+	return _u
 }
 
 // SetStreetName sets the "street_name" field.
-func (uu *UserUpdate) SetStreetName(s string) *UserUpdate {
-	uu.mutation.SetStreetName(s)
-	return uu
+func (_u *UserUpdate) SetStreetName(v string) *UserUpdate {
+	_u.mutation.SetStreetName(v)
+	return _u
 }
 
 // SetNillableStreetName sets the "street_name" field if the given value is not nil.
-func (uu *UserUpdate) SetNillableStreetName(s *string) *UserUpdate {
-	if s != nil {
-		uu.SetStreetName(*s)
+func (_u *UserUpdate) SetNillableStreetName(v *string) *UserUpdate {
+	if v != nil {
+		_u.SetStreetName(*v)
 	}
-	return uu
+	return _u
 }
 
 // SetCity sets the "city" field.
-func (uu *UserUpdate) SetCity(s string) *UserUpdate {
-	uu.mutation.SetCity(s)
-	return uu
+func (_u *UserUpdate) SetCity(v string) *UserUpdate {
+	_u.mutation.SetCity(v)
+	return _u
 }
 
 // SetNillableCity sets the "city" field if the given value is not nil.
-func (uu *UserUpdate) SetNillableCity(s *string) *UserUpdate {
-	if s != nil {
-		uu.SetCity(*s)
+func (_u *UserUpdate) SetNillableCity(v *string) *UserUpdate {
+	if v != nil {
+		_u.SetCity(*v)
 	}
-	return uu
+	return _u
 }
 
 // SetZipCode sets the "zip_code" field.
-func (uu *UserUpdate) SetZipCode(s string) *UserUpdate {
-	uu.mutation.SetZipCode(s)
-	return uu
+func (_u *UserUpdate) SetZipCode(v string) *UserUpdate {
+	_u.mutation.SetZipCode(v)
+	return _u
 }
 
 // SetNillableZipCode sets the "zip_code" field if the given value is not nil.
-func (uu *UserUpdate) SetNillableZipCode(s *string) *UserUpdate {
-	if s != nil {
-		uu.SetZipCode(*s)
+func (_u *UserUpdate) SetNillableZipCode(v *string) *UserUpdate {
+	if v != nil {
+		_u.SetZipCode(*v)
 	}
-	return uu
+	return _u
 }
 
 // SetCountry sets the "country" field.
-func (uu *UserUpdate) SetCountry(s string) *UserUpdate {
-	uu.mutation.SetCountry(s)
-	return uu
+func (_u *UserUpdate) SetCountry(v string) *UserUpdate {
+	_u.mutation.SetCountry(v)
+	return _u
 }
 
 // SetNillableCountry sets the "country" field if the given value is not nil.
-func (uu *UserUpdate) SetNillableCountry(s *string) *UserUpdate {
-	if s != nil {
-		uu.SetCountry(*s)
+func (_u *UserUpdate) SetNillableCountry(v *string) *UserUpdate {
+	if v != nil {
+		_u.SetCountry(*v)
 	}
-	return uu
+	return _u
 }
 
 // SetState sets the "state" field.
-func (uu *UserUpdate) SetState(s string) *UserUpdate {
-	uu.mutation.SetState(s)
-	return uu
+func (_u *UserUpdate) SetState(v string) *UserUpdate {
+	_u.mutation.SetState(v)
+	return _u
 }
 
 // SetNillableState sets the "state" field if the given value is not nil.
-func (uu *UserUpdate) SetNillableState(s *string) *UserUpdate {
-	if s != nil {
-		uu.SetState(*s)
+func (_u *UserUpdate) SetNillableState(v *string) *UserUpdate {
+	if v != nil {
+		_u.SetState(*v)
 	}
-	return uu
+	return _u
 }
 
 // SetEmail sets the "email" field.
-func (uu *UserUpdate) SetEmail(s string) *UserUpdate {
-	uu.mutation.SetEmail(s)
-	return uu
+func (_u *UserUpdate) SetEmail(v string) *UserUpdate {
+	_u.mutation.SetEmail(v)
+	return _u
 }
 
 // SetNillableEmail sets the "email" field if the given value is not nil.
-func (uu *UserUpdate) SetNillableEmail(s *string) *UserUpdate {
-	if s != nil {
-		uu.SetEmail(*s)
+func (_u *UserUpdate) SetNillableEmail(v *string) *UserUpdate {
+	if v != nil {
+		_u.SetEmail(*v)
 	}
-	return uu
+	return _u
 }
 
 // SetUsername sets the "username" field.
-func (uu *UserUpdate) SetUsername(s string) *UserUpdate {
-	uu.mutation.SetUsername(s)
-	return uu
+func (_u *UserUpdate) SetUsername(v string) *UserUpdate {
+	_u.mutation.SetUsername(v)
+	return _u
 }
 
 // SetNillableUsername sets the "username" field if the given value is not nil.
-func (uu *UserUpdate) SetNillableUsername(s *string) *UserUpdate {
-	if s != nil {
-		uu.SetUsername(*s)
+func (_u *UserUpdate) SetNillableUsername(v *string) *UserUpdate {
+	if v != nil {
+		_u.SetUsername(*v)
 	}
-	return uu
+	return _u
 }
 
 // ClearUsername clears the value of the "username" field.
-func (uu *UserUpdate) ClearUsername() *UserUpdate {
-	uu.mutation.ClearUsername()
-	return uu
+func (_u *UserUpdate) ClearUsername() *UserUpdate {
+	_u.mutation.ClearUsername()
+	return _u
 }
 
 // SetPasswordHash sets the "password_hash" field.
-func (uu *UserUpdate) SetPasswordHash(s string) *UserUpdate {
-	uu.mutation.SetPasswordHash(s)
-	return uu
+func (_u *UserUpdate) SetPasswordHash(v string) *UserUpdate {
+	_u.mutation.SetPasswordHash(v)
+	return _u
 }
 
 // SetNillablePasswordHash sets the "password_hash" field if the given value is not nil.
-func (uu *UserUpdate) SetNillablePasswordHash(s *string) *UserUpdate {
-	if s != nil {
-		uu.SetPasswordHash(*s)
+func (_u *UserUpdate) SetNillablePasswordHash(v *string) *UserUpdate {
+	if v != nil {
+		_u.SetPasswordHash(*v)
 	}
-	return uu
+	return _u
 }
 
 // ClearPasswordHash clears the value of the "password_hash" field.
-func (uu *UserUpdate) ClearPasswordHash() *UserUpdate {
-	uu.mutation.ClearPasswordHash()
-	return uu
+func (_u *UserUpdate) ClearPasswordHash() *UserUpdate {
+	_u.mutation.ClearPasswordHash()
+	return _u
+}
+
+// SetPasswordChangedAt sets the "password_changed_at" field.
+func (_u *UserUpdate) SetPasswordChangedAt(v time.Time) *UserUpdate {
+	_u.mutation.SetPasswordChangedAt(v)
+	return _u
+}
+
+// SetNillablePasswordChangedAt sets the "password_changed_at" field if the given value is not nil.
+func (_u *UserUpdate) SetNillablePasswordChangedAt(v *time.Time) *UserUpdate {
+	if v != nil {
+		_u.SetPasswordChangedAt(*v)
+	}
+	return _u
+}
+
+// ClearPasswordChangedAt clears the value of the "password_changed_at" field.
+func (_u *UserUpdate) ClearPasswordChangedAt() *UserUpdate {
+	_u.mutation.ClearPasswordChangedAt()
+	return _u
 }
 
 // SetOauthID sets the "oauth_id" field.
-func (uu *UserUpdate) SetOauthID(s string) *UserUpdate {
-	uu.mutation.SetOauthID(s)
-	return uu
+func (_u *UserUpdate) SetOauthID(v string) *UserUpdate {
+	_u.mutation.SetOauthID(v)
+	return _u
 }
 
 // SetNillableOauthID sets the "oauth_id" field if the given value is not nil.
-func (uu *UserUpdate) SetNillableOauthID(s *string) *UserUpdate {
-	if s != nil {
-		uu.SetOauthID(*s)
+func (_u *UserUpdate) SetNillableOauthID(v *string) *UserUpdate {
+	if v != nil {
+		_u.SetOauthID(*v)
 	}
-	return uu
+	return _u
 }
 
 // ClearOauthID clears the value of the "oauth_id" field.
-func (uu *UserUpdate) ClearOauthID() *UserUpdate {
-	uu.mutation.ClearOauthID()
-	return uu
+func (_u *UserUpdate) ClearOauthID() *UserUpdate {
+	_u.mutation.ClearOauthID()
+	return _u
 }
 
 // SetProvider sets the "provider" field.
-func (uu *UserUpdate) SetProvider(u user.Provider) *UserUpdate {
-	uu.mutation.SetProvider(u)
-	return uu
+func (_u *UserUpdate) SetProvider(v user.Provider) *UserUpdate {
+	_u.mutation.SetProvider(v)
+	return _u
 }
 
 // SetNillableProvider sets the "provider" field if the given value is not nil.
-func (uu *UserUpdate) SetNillableProvider(u *user.Provider) *UserUpdate {
-	if u != nil {
-		uu.SetProvider(*u)
+func (_u *UserUpdate) SetNillableProvider(v *user.Provider) *UserUpdate {
+	if v != nil {
+		_u.SetProvider(*v)
 	}
-	return uu
+	return _u
 }
 
 // SetFirstName sets the "first_name" field.
-func (uu *UserUpdate) SetFirstName(s string) *UserUpdate {
-	uu.mutation.SetFirstName(s)
-	return uu
+func (_u *UserUpdate) SetFirstName(v string) *UserUpdate {
+	_u.mutation.SetFirstName(v)
+	return _u
 }
 
 // SetNillableFirstName sets the "first_name" field if the given value is not nil.
-func (uu *UserUpdate) SetNillableFirstName(s *string) *UserUpdate {
-	if s != nil {
-		uu.SetFirstName(*s)
+func (_u *UserUpdate) SetNillableFirstName(v *string) *UserUpdate {
+	if v != nil {
+		_u.SetFirstName(*v)
 	}
-	return uu
+	return _u
 }
 
 // SetLastName sets the "last_name" field.
-func (uu *UserUpdate) SetLastName(s string) *UserUpdate {
-	uu.mutation.SetLastName(s)
-	return uu
+func (_u *UserUpdate) SetLastName(v string) *UserUpdate {
+	_u.mutation.SetLastName(v)
+	return _u
 }
 
 // SetNillableLastName sets the "last_name" field if the given value is not nil.
-func (uu *UserUpdate) SetNillableLastName(s *string) *UserUpdate {
-	if s != nil {
-		uu.SetLastName(*s)
+func (_u *UserUpdate) SetNillableLastName(v *string) *UserUpdate {
+	if v != nil {
+		_u.SetLastName(*v)
 	}
-	return uu
+	return _u
 }
 
 // SetPhoneNumber sets the "phone_number" field.
-func (uu *UserUpdate) SetPhoneNumber(s string) *UserUpdate {
-	uu.mutation.SetPhoneNumber(s)
-	return uu
+func (_u *UserUpdate) SetPhoneNumber(v string) *UserUpdate {
+	_u.mutation.SetPhoneNumber(v)
+	return _u
 }
 
 // SetNillablePhoneNumber sets the "phone_number" field if the given value is not nil.
-func (uu *UserUpdate) SetNillablePhoneNumber(s *string) *UserUpdate {
-	if s != nil {
-		uu.SetPhoneNumber(*s)
+func (_u *UserUpdate) SetNillablePhoneNumber(v *string) *UserUpdate {
+	if v != nil {
+		_u.SetPhoneNumber(*v)
 	}
-	return uu
+	return _u
 }
 
 // ClearPhoneNumber clears the value of the "phone_number" field.
-func (uu *UserUpdate) ClearPhoneNumber() *UserUpdate {
-	uu.mutation.ClearPhoneNumber()
-	return uu
+func (_u *UserUpdate) ClearPhoneNumber() *UserUpdate {
+	_u.mutation.ClearPhoneNumber()
+	return _u
 }
 
 // SetRole sets the "role" field.
-func (uu *UserUpdate) SetRole(u user.Role) *UserUpdate {
-	uu.mutation.SetRole(u)
-	return uu
+func (_u *UserUpdate) SetRole(v user.Role) *UserUpdate {
+	_u.mutation.SetRole(v)
+	return _u
 }
 
 // SetNillableRole sets the "role" field if the given value is not nil.
-func (uu *UserUpdate) SetNillableRole(u *user.Role) *UserUpdate {
-	if u != nil {
-		uu.SetRole(*u)
+func (_u *UserUpdate) SetNillableRole(v *user.Role) *UserUpdate {
+	if v != nil {
+		_u.SetRole(*v)
 	}
-	return uu
+	return _u
 }
 
 // SetIsEmailVerified sets the "is_email_verified" field.
-func (uu *UserUpdate) SetIsEmailVerified(b bool) *UserUpdate {
-	uu.mutation.SetIsEmailVerified(b)
-	return uu
+func (_u *UserUpdate) SetIsEmailVerified(v bool) *UserUpdate {
+	_u.mutation.SetIsEmailVerified(v)
+	return _u
 }
 
 // SetNillableIsEmailVerified sets the "is_email_verified" field if the given value is not nil.
-func (uu *UserUpdate) SetNillableIsEmailVerified(b *bool) *UserUpdate {
-	if b != nil {
-		uu.SetIsEmailVerified(*b)
+func (_u *UserUpdate) SetNillableIsEmailVerified(v *bool) *UserUpdate {
+	if v != nil {
+		_u.SetIsEmailVerified(*v)
 	}
-	return uu
+	return _u
 }
 
 // SetMarketingOptIn sets the "marketing_opt_in" field.
-func (uu *UserUpdate) SetMarketingOptIn(b bool) *UserUpdate {
-	uu.mutation.SetMarketingOptIn(b)
-	return uu
+func (_u *UserUpdate) SetMarketingOptIn(v bool) *UserUpdate {
+	_u.mutation.SetMarketingOptIn(v)
+	return _u
 }
 
 // SetNillableMarketingOptIn sets the "marketing_opt_in" field if the given value is not nil.
-func (uu *UserUpdate) SetNillableMarketingOptIn(b *bool) *UserUpdate {
-	if b != nil {
-		uu.SetMarketingOptIn(*b)
+func (_u *UserUpdate) SetNillableMarketingOptIn(v *bool) *UserUpdate {
+	if v != nil {
+		_u.SetMarketingOptIn(*v)
 	}
-	return uu
+	return _u
 }
 
 // SetTermsAcceptedAt sets the "terms_accepted_at" field.
-func (uu *UserUpdate) SetTermsAcceptedAt(t time.Time) *UserUpdate {
-	uu.mutation.SetTermsAcceptedAt(t)
-	return uu
+func (_u *UserUpdate) SetTermsAcceptedAt(v time.Time) *UserUpdate {
+	_u.mutation.SetTermsAcceptedAt(v)
+	return _u
 }
 
 // SetNillableTermsAcceptedAt sets the "terms_accepted_at" field if the given value is not nil.
-func (uu *UserUpdate) SetNillableTermsAcceptedAt(t *time.Time) *UserUpdate {
-	if t != nil {
-		uu.SetTermsAcceptedAt(*t)
+func (_u *UserUpdate) SetNillableTermsAcceptedAt(v *time.Time) *UserUpdate {
+	if v != nil {
+		_u.SetTermsAcceptedAt(*v)
 	}
-	return uu
+	return _u
 }
 
 // ClearTermsAcceptedAt clears the value of the "terms_accepted_at" field.
-func (uu *UserUpdate) ClearTermsAcceptedAt() *UserUpdate {
-	uu.mutation.ClearTermsAcceptedAt()
-	return uu
+func (_u *UserUpdate) ClearTermsAcceptedAt() *UserUpdate {
+	_u.mutation.ClearTermsAcceptedAt()
+	return _u
+}
+
+// SetTermsVersion sets the "terms_version" field.
+func (_u *UserUpdate) SetTermsVersion(v string) *UserUpdate {
+	_u.mutation.SetTermsVersion(v)
+	return _u
+}
+
+// SetNillableTermsVersion sets the "terms_version" field if the given value is not nil.
+func (_u *UserUpdate) SetNillableTermsVersion(v *string) *UserUpdate {
+	if v != nil {
+		_u.SetTermsVersion(*v)
+	}
+	return _u
+}
+
+// ClearTermsVersion clears the value of the "terms_version" field.
+func (_u *UserUpdate) ClearTermsVersion() *UserUpdate {
+	_u.mutation.ClearTermsVersion()
+	return _u
 }
 
 // SetLastLoginAt sets the "last_login_at" field.
-func (uu *UserUpdate) SetLastLoginAt(t time.Time) *UserUpdate {
-	uu.mutation.SetLastLoginAt(t)
-	return uu
+func (_u *UserUpdate) SetLastLoginAt(v time.Time) *UserUpdate {
+	_u.mutation.SetLastLoginAt(v)
+	return _u
 }
 
 // SetNillableLastLoginAt sets the "last_login_at" field if the given value is not nil.
-func (uu *UserUpdate) SetNillableLastLoginAt(t *time.Time) *UserUpdate {
-	if t != nil {
-		uu.SetLastLoginAt(*t)
+func (_u *UserUpdate) SetNillableLastLoginAt(v *time.Time) *UserUpdate {
+	if v != nil {
+		_u.SetLastLoginAt(*v)
 	}
-	return uu
+	return _u
 }
 
 // ClearLastLoginAt clears the value of the "last_login_at" field.
-func (uu *UserUpdate) ClearLastLoginAt() *UserUpdate {
-	uu.mutation.ClearLastLoginAt()
-	return uu
+func (_u *UserUpdate) ClearLastLoginAt() *UserUpdate {
+	_u.mutation.ClearLastLoginAt()
+	return _u
+}
+
+// SetOnboardingStep sets the "onboarding_step" field.
+func (_u *UserUpdate) SetOnboardingStep(v user.OnboardingStep) *UserUpdate {
+	_u.mutation.SetOnboardingStep(v)
+	return _u
+}
+
+// SetNillableOnboardingStep sets the "onboarding_step" field if the given value is not nil.
+func (_u *UserUpdate) SetNillableOnboardingStep(v *user.OnboardingStep) *UserUpdate {
+	if v != nil {
+		_u.SetOnboardingStep(*v)
+	}
+	return _u
+}
+
+// SetSuspendedAt sets the "suspended_at" field.
+func (_u *UserUpdate) SetSuspendedAt(v time.Time) *UserUpdate {
+	_u.mutation.SetSuspendedAt(v)
+	return _u
+}
+
+// SetNillableSuspendedAt sets the "suspended_at" field if the given value is not nil.
+func (_u *UserUpdate) SetNillableSuspendedAt(v *time.Time) *UserUpdate {
+	if v != nil {
+		_u.SetSuspendedAt(*v)
+	}
+	return _u
+}
+
+// ClearSuspendedAt clears the value of the "suspended_at" field.
+func (_u *UserUpdate) ClearSuspendedAt() *UserUpdate {
+	_u.mutation.ClearSuspendedAt()
+	return _u
+}
+
+// SetSuspensionReason sets the "suspension_reason" field.
+func (_u *UserUpdate) SetSuspensionReason(v user.SuspensionReason) *UserUpdate {
+	_u.mutation.SetSuspensionReason(v)
+	return _u
+}
+
+// SetNillableSuspensionReason sets the "suspension_reason" field if the given value is not nil.
+func (_u *UserUpdate) SetNillableSuspensionReason(v *user.SuspensionReason) *UserUpdate {
+	if v != nil {
+		_u.SetSuspensionReason(*v)
+	}
+	return _u
+}
+
+// ClearSuspensionReason clears the value of the "suspension_reason" field.
+func (_u *UserUpdate) ClearSuspensionReason() *UserUpdate {
+	_u.mutation.ClearSuspensionReason()
+	return _u
+}
+
+// SetResidency sets the "residency" field.
+func (_u *UserUpdate) SetResidency(v string) *UserUpdate {
+	_u.mutation.SetResidency(v)
+	return _u
+}
+
+// SetNillableResidency sets the "residency" field if the given value is not nil.
+func (_u *UserUpdate) SetNillableResidency(v *string) *UserUpdate {
+	if v != nil {
+		_u.SetResidency(*v)
+	}
+	return _u
+}
+
+// ClearResidency clears the value of the "residency" field.
+func (_u *UserUpdate) ClearResidency() *UserUpdate {
+	_u.mutation.ClearResidency()
+	return _u
+}
+
+// SetDeactivatedAt sets the "deactivated_at" field.
+func (_u *UserUpdate) SetDeactivatedAt(v time.Time) *UserUpdate {
+	_u.mutation.SetDeactivatedAt(v)
+	return _u
+}
+
+// SetNillableDeactivatedAt sets the "deactivated_at" field if the given value is not nil.
+func (_u *UserUpdate) SetNillableDeactivatedAt(v *time.Time) *UserUpdate {
+	if v != nil {
+		_u.SetDeactivatedAt(*v)
+	}
+	return _u
+}
+
+// ClearDeactivatedAt clears the value of the "deactivated_at" field.
+func (_u *UserUpdate) ClearDeactivatedAt() *UserUpdate {
+	_u.mutation.ClearDeactivatedAt()
+	return _u
+}
+
+// SetOrganizationID sets the "organization_id" field.
+func (_u *UserUpdate) SetOrganizationID(v int64) *UserUpdate {
+	_u.mutation.SetOrganizationID(v)
+	return _u
+}
+
+// SetNillableOrganizationID sets the "organization_id" field if the given value is not nil.
+func (_u *UserUpdate) SetNillableOrganizationID(v *int64) *UserUpdate {
+	if v != nil {
+		_u.SetOrganizationID(*v)
+	}
+	return _u
+}
+
+// ClearOrganizationID clears the value of the "organization_id" field.
+func (_u *UserUpdate) ClearOrganizationID() *UserUpdate {
+	_u.mutation.ClearOrganizationID()
+	return _u
+}
+
+// SetKind sets the "kind" field.
+func (_u *UserUpdate) SetKind(v user.Kind) *UserUpdate {
+	_u.mutation.SetKind(v)
+	return _u
+}
+
+// SetNillableKind sets the "kind" field if the given value is not nil.
+func (_u *UserUpdate) SetNillableKind(v *user.Kind) *UserUpdate {
+	if v != nil {
+		_u.SetKind(*v)
+	}
+	return _u
 }
 
 // SetAddressID sets the "address" edge to the UserAddress entity by ID.
-func (uu *UserUpdate) SetAddressID(id int) *UserUpdate {
-	uu.mutation.SetAddressID(id)
-	return uu
+func (_u *UserUpdate) SetAddressID(id int) *UserUpdate {
+	_u.mutation.SetAddressID(id)
+	return _u
 }
 
 // SetNillableAddressID sets the "address" edge to the UserAddress entity by ID if the given value is not nil.
-func (uu *UserUpdate) SetNillableAddressID(id *int) *UserUpdate {
+func (_u *UserUpdate) SetNillableAddressID(id *int) *UserUpdate {
 	if id != nil {
-		uu = uu.SetAddressID(*id)
+		_u = _u.SetAddressID(*id)
 	}
-	return uu
+	return _u
 }
 
 // SetAddress sets the "address" edge to the UserAddress entity.
-func (uu *UserUpdate) SetAddress(u *UserAddress) *UserUpdate {
-	return uu.SetAddressID(u.ID)
+func (_u *UserUpdate) SetAddress(v *UserAddress) *UserUpdate {
+	return _u.SetAddressID(v.ID)
+}
+
+// AddLoginAttemptIDs adds the "login_attempts" edge to the LoginAttempt entity by IDs.
+func (_u *UserUpdate) AddLoginAttemptIDs(ids ...int64) *UserUpdate {
+	_u.mutation.AddLoginAttemptIDs(ids...)
+	return _u
+}
+
+// AddLoginAttempts adds the "login_attempts" edges to the LoginAttempt entity.
+func (_u *UserUpdate) AddLoginAttempts(v ...*LoginAttempt) *UserUpdate {
+	ids := make([]int64, len(v))
+	for i := range v {
+		ids[i] = v[i].ID
+	}
+	return _u.AddLoginAttemptIDs(ids...)
+}
+
+// SetOrganization sets the "organization" edge to the Organization entity.
+func (_u *UserUpdate) SetOrganization(v *Organization) *UserUpdate {
+	return _u.SetOrganizationID(v.ID)
+}
+
+// AddServiceAccountKeyIDs adds the "service_account_keys" edge to the ServiceAccountKey entity by IDs.
+func (_u *UserUpdate) AddServiceAccountKeyIDs(ids ...int64) *UserUpdate {
+	_u.mutation.AddServiceAccountKeyIDs(ids...)
+	return _u
+}
+
+// AddServiceAccountKeys adds the "service_account_keys" edges to the ServiceAccountKey entity.
+func (_u *UserUpdate) AddServiceAccountKeys(v ...*ServiceAccountKey) *UserUpdate {
+	ids := make([]int64, len(v))
+	for i := range v {
+		ids[i] = v[i].ID
+	}
+	return _u.AddServiceAccountKeyIDs(ids...)
+}
+
+// AddIdentityIDs adds the "identities" edge to the UserIdentity entity by IDs.
+func (_u *UserUpdate) AddIdentityIDs(ids ...int64) *UserUpdate {
+	_u.mutation.AddIdentityIDs(ids...)
+	return _u
+}
+
+// AddIdentities adds the "identities" edges to the UserIdentity entity.
+func (_u *UserUpdate) AddIdentities(v ...*UserIdentity) *UserUpdate {
+	ids := make([]int64, len(v))
+	for i := range v {
+		ids[i] = v[i].ID
+	}
+	return _u.AddIdentityIDs(ids...)
+}
+
+// AddScheduledActionIDs adds the "scheduled_actions" edge to the ScheduledAction entity by IDs.
+func (_u *UserUpdate) AddScheduledActionIDs(ids ...int64) *UserUpdate {
+	_u.mutation.AddScheduledActionIDs(ids...)
+	return _u
+}
+
+// AddScheduledActions adds the "scheduled_actions" edges to the ScheduledAction entity.
+func (_u *UserUpdate) AddScheduledActions(v ...*ScheduledAction) *UserUpdate {
+	ids := make([]int64, len(v))
+	for i := range v {
+		ids[i] = v[i].ID
+	}
+	return _u.AddScheduledActionIDs(ids...)
 }
 
 // Mutation returns the UserMutation object of the builder.
-func (uu *UserUpdate) Mutation() *UserMutation {
-	return uu.mutation
+func (_u *UserUpdate) Mutation() *UserMutation {
+	return _u.mutation
 }
 
 // ClearAddress clears the "address" edge to the UserAddress entity.
-func (uu *UserUpdate) ClearAddress() *UserUpdate {
-	uu.mutation.ClearAddress()
-	return uu
+func (_u *UserUpdate) ClearAddress() *UserUpdate {
+	_u.mutation.ClearAddress()
+	return _u
+}
+
+// ClearLoginAttempts clears all "login_attempts" edges to the LoginAttempt entity.
+func (_u *UserUpdate) ClearLoginAttempts() *UserUpdate {
+	_u.mutation.ClearLoginAttempts()
+	return _u
+}
+
+// RemoveLoginAttemptIDs removes the "login_attempts" edge to LoginAttempt entities by IDs.
+func (_u *UserUpdate) RemoveLoginAttemptIDs(ids ...int64) *UserUpdate {
+	_u.mutation.RemoveLoginAttemptIDs(ids...)
+	return _u
+}
+
+// RemoveLoginAttempts removes "login_attempts" edges to LoginAttempt entities.
+func (_u *UserUpdate) RemoveLoginAttempts(v ...*LoginAttempt) *UserUpdate {
+	ids := make([]int64, len(v))
+	for i := range v {
+		ids[i] = v[i].ID
+	}
+	return _u.RemoveLoginAttemptIDs(ids...)
+}
+
+// ClearOrganization clears the "organization" edge to the Organization entity.
+func (_u *UserUpdate) ClearOrganization() *UserUpdate {
+	_u.mutation.ClearOrganization()
+	return _u
+}
+
+// ClearServiceAccountKeys clears all "service_account_keys" edges to the ServiceAccountKey entity.
+func (_u *UserUpdate) ClearServiceAccountKeys() *UserUpdate {
+	_u.mutation.ClearServiceAccountKeys()
+	return _u
+}
+
+// RemoveServiceAccountKeyIDs removes the "service_account_keys" edge to ServiceAccountKey entities by IDs.
+func (_u *UserUpdate) RemoveServiceAccountKeyIDs(ids ...int64) *UserUpdate {
+	_u.mutation.RemoveServiceAccountKeyIDs(ids...)
+	return _u
+}
+
+// RemoveServiceAccountKeys removes "service_account_keys" edges to ServiceAccountKey entities.
+func (_u *UserUpdate) RemoveServiceAccountKeys(v ...*ServiceAccountKey) *UserUpdate {
+	ids := make([]int64, len(v))
+	for i := range v {
+		ids[i] = v[i].ID
+	}
+	return _u.RemoveServiceAccountKeyIDs(ids...)
+}
+
+// ClearIdentities clears all "identities" edges to the UserIdentity entity.
+func (_u *UserUpdate) ClearIdentities() *UserUpdate {
+	_u.mutation.ClearIdentities()
+	return _u
+}
+
+// RemoveIdentityIDs removes the "identities" edge to UserIdentity entities by IDs.
+func (_u *UserUpdate) RemoveIdentityIDs(ids ...int64) *UserUpdate {
+	_u.mutation.RemoveIdentityIDs(ids...)
+	return _u
+}
+
+// RemoveIdentities removes "identities" edges to UserIdentity entities.
+func (_u *UserUpdate) RemoveIdentities(v ...*UserIdentity) *UserUpdate {
+	ids := make([]int64, len(v))
+	for i := range v {
+		ids[i] = v[i].ID
+	}
+	return _u.RemoveIdentityIDs(ids...)
+}
+
+// ClearScheduledActions clears all "scheduled_actions" edges to the ScheduledAction entity.
+func (_u *UserUpdate) ClearScheduledActions() *UserUpdate {
+	_u.mutation.ClearScheduledActions()
+	return _u
+}
+
+// RemoveScheduledActionIDs removes the "scheduled_actions" edge to ScheduledAction entities by IDs.
+func (_u *UserUpdate) RemoveScheduledActionIDs(ids ...int64) *UserUpdate {
+	_u.mutation.RemoveScheduledActionIDs(ids...)
+	return _u
+}
+
+// RemoveScheduledActions removes "scheduled_actions" edges to ScheduledAction entities.
+func (_u *UserUpdate) RemoveScheduledActions(v ...*ScheduledAction) *UserUpdate {
+	ids := make([]int64, len(v))
+	for i := range v {
+		ids[i] = v[i].ID
+	}
+	return _u.RemoveScheduledActionIDs(ids...)
 }
 
 // Save executes the query and returns the number of nodes affected by the update operation.
-func (uu *UserUpdate) Save(ctx context.Context) (int, error) {
-	uu.defaults()
-	return withHooks(ctx, uu.sqlSave, uu.mutation, uu.hooks)
+func (_u *UserUpdate) Save(ctx context.Context) (int, error) {
+	_u.defaults()
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
 }
 
 // SaveX is like Save, but panics if an error occurs.
-func (uu *UserUpdate) SaveX(ctx context.Context) int {
-	affected, err := uu.Save(ctx)
+func (_u *UserUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -389,190 +717,257 @@ func (uu *UserUpdate) SaveX(ctx context.Context) int {
 }
 
 // Exec executes the query.
-func (uu *UserUpdate) Exec(ctx context.Context) error {
-	_, err := uu.Save(ctx)
+func (_u *UserUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
 	return err
 }
 
 // ExecX is like Exec, but panics if an error occurs.
-func (uu *UserUpdate) ExecX(ctx context.Context) {
-	if err := uu.Exec(ctx); err != nil {
+func (_u *UserUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
 		panic(err)
 	}
 }
 
 // defaults sets the default values of the builder before save.
-func (uu *UserUpdate) defaults() {
-	if _, ok := uu.mutation.UpdatedAt(); !ok {
+func (_u *UserUpdate) defaults() {
+	if _, ok := _u.mutation.UpdatedAt(); !ok {
 		v := user.UpdateDefaultUpdatedAt()
-		uu.mutation.SetUpdatedAt(v)
+		_u.mutation.SetUpdatedAt(v)
 	}
 }
 
 // check runs all checks and user-defined validators on the builder.
-func (uu *UserUpdate) check() error {
-	if v, ok := uu.mutation.StreetName(); ok {
+func (_u *UserUpdate) check() error {
+	if v, ok := _u.mutation.StreetName(); ok {
 		if err := user.StreetNameValidator(v); err != nil {
 			return &ValidationError{Name: "street_name", err: fmt.Errorf(`ent: validator failed for field "User.street_name": %w`, err)}
 		}
 	}
-	if v, ok := uu.mutation.City(); ok {
+	if v, ok := _u.mutation.City(); ok {
 		if err := user.CityValidator(v); err != nil {
 			return &ValidationError{Name: "city", err: fmt.Errorf(`ent: validator failed for field "User.city": %w`, err)}
 		}
 	}
-	if v, ok := uu.mutation.ZipCode(); ok {
+	if v, ok := _u.mutation.ZipCode(); ok {
 		if err := user.ZipCodeValidator(v); err != nil {
 			return &ValidationError{Name: "zip_code", err: fmt.Errorf(`ent: validator failed for field "User.zip_code": %w`, err)}
 		}
 	}
-	if v, ok := uu.mutation.Country(); ok {
+	if v, ok := _u.mutation.Country(); ok {
 		if err := user.CountryValidator(v); err != nil {
 			return &ValidationError{Name: "country", err: fmt.Errorf(`ent: validator failed for field "User.country": %w`, err)}
 		}
 	}
-	if v, ok := uu.mutation.State(); ok {
+	if v, ok := _u.mutation.State(); ok {
 		if err := user.StateValidator(v); err != nil {
 			return &ValidationError{Name: "state", err: fmt.Errorf(`ent: validator failed for field "User.state": %w`, err)}
 		}
 	}
-	if v, ok := uu.mutation.Email(); ok {
+	if v, ok := _u.mutation.Email(); ok {
 		if err := user.EmailValidator(v); err != nil {
 			return &ValidationError{Name: "email", err: fmt.Errorf(`ent: validator failed for field "User.email": %w`, err)}
 		}
 	}
-	if v, ok := uu.mutation.Username(); ok {
+	if v, ok := _u.mutation.Username(); ok {
 		if err := user.UsernameValidator(v); err != nil {
 			return &ValidationError{Name: "username", err: fmt.Errorf(`ent: validator failed for field "User.username": %w`, err)}
 		}
 	}
-	if v, ok := uu.mutation.OauthID(); ok {
+	if v, ok := _u.mutation.OauthID(); ok {
 		if err := user.OauthIDValidator(v); err != nil {
 			return &ValidationError{Name: "oauth_id", err: fmt.Errorf(`ent: validator failed for field "User.oauth_id": %w`, err)}
 		}
 	}
-	if v, ok := uu.mutation.Provider(); ok {
+	if v, ok := _u.mutation.Provider(); ok {
 		if err := user.ProviderValidator(v); err != nil {
 			return &ValidationError{Name: "provider", err: fmt.Errorf(`ent: validator failed for field "User.provider": %w`, err)}
 		}
 	}
-	if v, ok := uu.mutation.FirstName(); ok {
+	if v, ok := _u.mutation.FirstName(); ok {
 		if err := user.FirstNameValidator(v); err != nil {
 			return &ValidationError{Name: "first_name", err: fmt.Errorf(`ent: validator failed for field "User.first_name": %w`, err)}
 		}
 	}
-	if v, ok := uu.mutation.LastName(); ok {
+	if v, ok := _u.mutation.LastName(); ok {
 		if err := user.LastNameValidator(v); err != nil {
 			return &ValidationError{Name: "last_name", err: fmt.Errorf(`ent: validator failed for field "User.last_name": %w`, err)}
 		}
 	}
-	if v, ok := uu.mutation.PhoneNumber(); ok {
+	if v, ok := _u.mutation.PhoneNumber(); ok {
 		if err := user.PhoneNumberValidator(v); err != nil {
 			return &ValidationError{Name: "phone_number", err: fmt.Errorf(`ent: validator failed for field "User.phone_number": %w`, err)}
 		}
 	}
-	if v, ok := uu.mutation.Role(); ok {
+	if v, ok := _u.mutation.Role(); ok {
 		if err := user.RoleValidator(v); err != nil {
 			return &ValidationError{Name: "role", err: fmt.Errorf(`ent: validator failed for field "User.role": %w`, err)}
 		}
 	}
+	if v, ok := _u.mutation.TermsVersion(); ok {
+		if err := user.TermsVersionValidator(v); err != nil {
+			return &ValidationError{Name: "terms_version", err: fmt.Errorf(`ent: validator failed for field "User.terms_version": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.OnboardingStep(); ok {
+		if err := user.OnboardingStepValidator(v); err != nil {
+			return &ValidationError{Name: "onboarding_step", err: fmt.Errorf(`ent: validator failed for field "User.onboarding_step": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.SuspensionReason(); ok {
+		if err := user.SuspensionReasonValidator(v); err != nil {
+			return &ValidationError{Name: "suspension_reason", err: fmt.Errorf(`ent: validator failed for field "User.suspension_reason": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Residency(); ok {
+		if err := user.ResidencyValidator(v); err != nil {
+			return &ValidationError{Name: "residency", err: fmt.Errorf(`ent: validator failed for field "User.residency": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Kind(); ok {
+		if err := user.KindValidator(v); err != nil {
+			return &ValidationError{Name: "kind", err: fmt.Errorf(`ent: validator failed for field "User.kind": %w`, err)}
+		}
+	}
 	return nil
 }
 
-func (uu *UserUpdate) sqlSave(ctx context.Context) (n int, err error) {
-	if err := uu.check(); err != nil {
-		return n, err
+func (_u *UserUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
 	}
 	_spec := sqlgraph.NewUpdateSpec(user.Table, user.Columns, sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt64))
-	if ps := uu.mutation.predicates; len(ps) > 0 {
+	if ps := _u.mutation.predicates; len(ps) > 0 {
 		_spec.Predicate = func(selector *sql.Selector) {
 			for i := range ps {
 				ps[i](selector)
 			}
 		}
 	}
-	if value, ok := uu.mutation.UpdatedAt(); ok {
+	if value, ok := _u.mutation.UpdatedAt(); ok {
 		_spec.SetField(user.FieldUpdatedAt, field.TypeTime, value)
 	}
-	if value, ok := uu.mutation.DeletedAt(); ok {
+	if value, ok := _u.mutation.DeletedAt(); ok {
 		_spec.SetField(user.FieldDeletedAt, field.TypeTime, value)
 	}
-	if uu.mutation.DeletedAtCleared() {
+	if _u.mutation.DeletedAtCleared() {
 		_spec.ClearField(user.FieldDeletedAt, field.TypeTime)
 	}
-	if value, ok := uu.mutation.StreetName(); ok {
+	if value, ok := _u.mutation.StreetName(); ok {
 		_spec.SetField(user.FieldStreetName, field.TypeString, value)
 	}
-	if value, ok := uu.mutation.City(); ok {
+	if value, ok := _u.mutation.City(); ok {
 		_spec.SetField(user.FieldCity, field.TypeString, value)
 	}
-	if value, ok := uu.mutation.ZipCode(); ok {
+	if value, ok := _u.mutation.ZipCode(); ok {
 		_spec.SetField(user.FieldZipCode, field.TypeString, value)
 	}
-	if value, ok := uu.mutation.Country(); ok {
+	if value, ok := _u.mutation.Country(); ok {
 		_spec.SetField(user.FieldCountry, field.TypeString, value)
 	}
-	if value, ok := uu.mutation.State(); ok {
+	if value, ok := _u.mutation.State(); ok {
 		_spec.SetField(user.FieldState, field.TypeString, value)
 	}
-	if value, ok := uu.mutation.Email(); ok {
+	if value, ok := _u.mutation.Email(); ok {
 		_spec.SetField(user.FieldEmail, field.TypeString, value)
 	}
-	if value, ok := uu.mutation.Username(); ok {
+	if value, ok := _u.mutation.Username(); ok {
 		_spec.SetField(user.FieldUsername, field.TypeString, value)
 	}
-	if uu.mutation.UsernameCleared() {
+	if _u.mutation.UsernameCleared() {
 		_spec.ClearField(user.FieldUsername, field.TypeString)
 	}
-	if value, ok := uu.mutation.PasswordHash(); ok {
+	if value, ok := _u.mutation.PasswordHash(); ok {
 		_spec.SetField(user.FieldPasswordHash, field.TypeString, value)
 	}
-	if uu.mutation.PasswordHashCleared() {
+	if _u.mutation.PasswordHashCleared() {
 		_spec.ClearField(user.FieldPasswordHash, field.TypeString)
 	}
-	if value, ok := uu.mutation.OauthID(); ok {
+	if value, ok := _u.mutation.PasswordChangedAt(); ok {
+		_spec.SetField(user.FieldPasswordChangedAt, field.TypeTime, value)
+	}
+	if _u.mutation.PasswordChangedAtCleared() {
+		_spec.ClearField(user.FieldPasswordChangedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.OauthID(); ok {
 		_spec.SetField(user.FieldOauthID, field.TypeString, value)
 	}
-	if uu.mutation.OauthIDCleared() {
+	if _u.mutation.OauthIDCleared() {
 		_spec.ClearField(user.FieldOauthID, field.TypeString)
 	}
-	if value, ok := uu.mutation.Provider(); ok {
+	if value, ok := _u.mutation.Provider(); ok {
 		_spec.SetField(user.FieldProvider, field.TypeEnum, value)
 	}
-	if value, ok := uu.mutation.FirstName(); ok {
+	if value, ok := _u.mutation.FirstName(); ok {
 		_spec.SetField(user.FieldFirstName, field.TypeString, value)
 	}
-	if value, ok := uu.mutation.LastName(); ok {
+	if value, ok := _u.mutation.LastName(); ok {
 		_spec.SetField(user.FieldLastName, field.TypeString, value)
 	}
-	if value, ok := uu.mutation.PhoneNumber(); ok {
+	if value, ok := _u.mutation.PhoneNumber(); ok {
 		_spec.SetField(user.FieldPhoneNumber, field.TypeString, value)
 	}
-	if uu.mutation.PhoneNumberCleared() {
+	if _u.mutation.PhoneNumberCleared() {
 		_spec.ClearField(user.FieldPhoneNumber, field.TypeString)
 	}
-	if value, ok := uu.mutation.Role(); ok {
+	if value, ok := _u.mutation.Role(); ok {
 		_spec.SetField(user.FieldRole, field.TypeEnum, value)
 	}
-	if value, ok := uu.mutation.IsEmailVerified(); ok {
+	if value, ok := _u.mutation.IsEmailVerified(); ok {
 		_spec.SetField(user.FieldIsEmailVerified, field.TypeBool, value)
 	}
-	if value, ok := uu.mutation.MarketingOptIn(); ok {
+	if value, ok := _u.mutation.MarketingOptIn(); ok {
 		_spec.SetField(user.FieldMarketingOptIn, field.TypeBool, value)
 	}
-	if value, ok := uu.mutation.TermsAcceptedAt(); ok {
+	if value, ok := _u.mutation.TermsAcceptedAt(); ok {
 		_spec.SetField(user.FieldTermsAcceptedAt, field.TypeTime, value)
 	}
-	if uu.mutation.TermsAcceptedAtCleared() {
+	if _u.mutation.TermsAcceptedAtCleared() {
 		_spec.ClearField(user.FieldTermsAcceptedAt, field.TypeTime)
 	}
-	if value, ok := uu.mutation.LastLoginAt(); ok {
+	if value, ok := _u.mutation.TermsVersion(); ok {
+		_spec.SetField(user.FieldTermsVersion, field.TypeString, value)
+	}
+	if _u.mutation.TermsVersionCleared() {
+		_spec.ClearField(user.FieldTermsVersion, field.TypeString)
+	}
+	if value, ok := _u.mutation.LastLoginAt(); ok {
 		_spec.SetField(user.FieldLastLoginAt, field.TypeTime, value)
 	}
-	if uu.mutation.LastLoginAtCleared() {
+	if _u.mutation.LastLoginAtCleared() {
 		_spec.ClearField(user.FieldLastLoginAt, field.TypeTime)
 	}
-	if uu.mutation.AddressCleared() {
+	if value, ok := _u.mutation.OnboardingStep(); ok {
+		_spec.SetField(user.FieldOnboardingStep, field.TypeEnum, value)
+	}
+	if value, ok := _u.mutation.SuspendedAt(); ok {
+		_spec.SetField(user.FieldSuspendedAt, field.TypeTime, value)
+	}
+	if _u.mutation.SuspendedAtCleared() {
+		_spec.ClearField(user.FieldSuspendedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.SuspensionReason(); ok {
+		_spec.SetField(user.FieldSuspensionReason, field.TypeEnum, value)
+	}
+	if _u.mutation.SuspensionReasonCleared() {
+		_spec.ClearField(user.FieldSuspensionReason, field.TypeEnum)
+	}
+	if value, ok := _u.mutation.Residency(); ok {
+		_spec.SetField(user.FieldResidency, field.TypeString, value)
+	}
+	if _u.mutation.ResidencyCleared() {
+		_spec.ClearField(user.FieldResidency, field.TypeString)
+	}
+	if value, ok := _u.mutation.DeactivatedAt(); ok {
+		_spec.SetField(user.FieldDeactivatedAt, field.TypeTime, value)
+	}
+	if _u.mutation.DeactivatedAtCleared() {
+		_spec.ClearField(user.FieldDeactivatedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.Kind(); ok {
+		_spec.SetField(user.FieldKind, field.TypeEnum, value)
+	}
+	if _u.mutation.AddressCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.M2O,
 			Inverse: false,
@@ -585,7 +980,7 @@ func (uu *UserUpdate) sqlSave(ctx context.Context) (n int, err error) {
 		}
 		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
 	}
-	if nodes := uu.mutation.AddressIDs(); len(nodes) > 0 {
+	if nodes := _u.mutation.AddressIDs(); len(nodes) > 0 {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.M2O,
 			Inverse: false,
@@ -601,7 +996,216 @@ func (uu *UserUpdate) sqlSave(ctx context.Context) (n int, err error) {
 		}
 		_spec.Edges.Add = append(_spec.Edges.Add, edge)
 	}
-	if n, err = sqlgraph.UpdateNodes(ctx, uu.driver, _spec); err != nil {
+	if _u.mutation.LoginAttemptsCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.LoginAttemptsTable,
+			Columns: []string{user.LoginAttemptsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(loginattempt.FieldID, field.TypeInt64),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.RemovedLoginAttemptsIDs(); len(nodes) > 0 && !_u.mutation.LoginAttemptsCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.LoginAttemptsTable,
+			Columns: []string{user.LoginAttemptsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(loginattempt.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.LoginAttemptsIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.LoginAttemptsTable,
+			Columns: []string{user.LoginAttemptsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(loginattempt.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if _u.mutation.OrganizationCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   user.OrganizationTable,
+			Columns: []string{user.OrganizationColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(organization.FieldID, field.TypeInt64),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.OrganizationIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   user.OrganizationTable,
+			Columns: []string{user.OrganizationColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(organization.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if _u.mutation.ServiceAccountKeysCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.ServiceAccountKeysTable,
+			Columns: []string{user.ServiceAccountKeysColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(serviceaccountkey.FieldID, field.TypeInt64),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.RemovedServiceAccountKeysIDs(); len(nodes) > 0 && !_u.mutation.ServiceAccountKeysCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.ServiceAccountKeysTable,
+			Columns: []string{user.ServiceAccountKeysColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(serviceaccountkey.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.ServiceAccountKeysIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.ServiceAccountKeysTable,
+			Columns: []string{user.ServiceAccountKeysColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(serviceaccountkey.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if _u.mutation.IdentitiesCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.IdentitiesTable,
+			Columns: []string{user.IdentitiesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(useridentity.FieldID, field.TypeInt64),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.RemovedIdentitiesIDs(); len(nodes) > 0 && !_u.mutation.IdentitiesCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.IdentitiesTable,
+			Columns: []string{user.IdentitiesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(useridentity.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.IdentitiesIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.IdentitiesTable,
+			Columns: []string{user.IdentitiesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(useridentity.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if _u.mutation.ScheduledActionsCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.ScheduledActionsTable,
+			Columns: []string{user.ScheduledActionsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(scheduledaction.FieldID, field.TypeInt64),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.RemovedScheduledActionsIDs(); len(nodes) > 0 && !_u.mutation.ScheduledActionsCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.ScheduledActionsTable,
+			Columns: []string{user.ScheduledActionsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(scheduledaction.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.ScheduledActionsIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.ScheduledActionsTable,
+			Columns: []string{user.ScheduledActionsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(scheduledaction.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
 		if _, ok := err.(*sqlgraph.NotFoundError); ok {
 			err = &NotFoundError{user.Label}
 		} else if sqlgraph.IsConstraintError(err) {
@@ -609,384 +1213,707 @@ func (uu *UserUpdate) sqlSave(ctx context.Context) (n int, err error) {
 		}
 		return 0, err
 	}
-	uu.mutation.done = true
-	return n, nil
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// UserUpdateOne is the builder for updating a single User entity.
+type UserUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *UserMutation
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (_u *UserUpdateOne) SetUpdatedAt(v time.Time) *UserUpdateOne {
+	_u.mutation.SetUpdatedAt(v)
+	return _u
+}
+
+// SetDeletedAt sets the "deleted_at" field.
+func (_u *UserUpdateOne) SetDeletedAt(v time.Time) *UserUpdateOne {
+	_u.mutation.SetDeletedAt(v)
+	return _u
+}
+
+// SetNillableDeletedAt sets the "deleted_at" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableDeletedAt(v *time.Time) *UserUpdateOne {
+	if v != nil {
+		_u.SetDeletedAt(*v)
+	}
+	return _u
+}
+
+// ClearDeletedAt clears the value of the "deleted_at" field.
+func (_u *UserUpdateOne) ClearDeletedAt() *UserUpdateOne {
+	_u.mutation.ClearDeletedAt()
+	return _u
+}
+
+// SetStreetName sets the "street_name" field.
+func (_u *UserUpdateOne) SetStreetName(v string) *UserUpdateOne {
+	_u.mutation.SetStreetName(v)
+	return _u
+}
+
+// SetNillableStreetName sets the "street_name" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableStreetName(v *string) *UserUpdateOne {
+	if v != nil {
+		_u.SetStreetName(*v)
+	}
+	return _u
+}
+
+// SetCity sets the "city" field.
+func (_u *UserUpdateOne) SetCity(v string) *UserUpdateOne {
+	_u.mutation.SetCity(v)
+	return _u
+}
+
+// SetNillableCity sets the "city" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableCity(v *string) *UserUpdateOne {
+	if v != nil {
+		_u.SetCity(*v)
+	}
+	return _u
+}
+
+// SetZipCode sets the "zip_code" field.
+func (_u *UserUpdateOne) SetZipCode(v string) *UserUpdateOne {
+	_u.mutation.SetZipCode(v)
+	return _u
+}
+
+// SetNillableZipCode sets the "zip_code" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableZipCode(v *string) *UserUpdateOne {
+	if v != nil {
+		_u.SetZipCode(*v)
+	}
+	return _u
+}
+
+// SetCountry sets the "country" field.
+func (_u *UserUpdateOne) SetCountry(v string) *UserUpdateOne {
+	_u.mutation.SetCountry(v)
+	return _u
+}
+
+// SetNillableCountry sets the "country" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableCountry(v *string) *UserUpdateOne {
+	if v != nil {
+		_u.SetCountry(*v)
+	}
+	return _u
+}
+
+// SetState sets the "state" field.
+func (_u *UserUpdateOne) SetState(v string) *UserUpdateOne {
+	_u.mutation.SetState(v)
+	return _u
+}
+
+// SetNillableState sets the "state" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableState(v *string) *UserUpdateOne {
+	if v != nil {
+		_u.SetState(*v)
+	}
+	return _u
+}
+
+// SetEmail sets the "email" field.
+func (_u *UserUpdateOne) SetEmail(v string) *UserUpdateOne {
+	_u.mutation.SetEmail(v)
+	return _u
+}
+
+// SetNillableEmail sets the "email" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableEmail(v *string) *UserUpdateOne {
+	if v != nil {
+		_u.SetEmail(*v)
+	}
+	return _u
+}
+
+// SetUsername sets the "username" field.
+func (_u *UserUpdateOne) SetUsername(v string) *UserUpdateOne {
+	_u.mutation.SetUsername(v)
+	return _u
+}
+
+// SetNillableUsername sets the "username" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableUsername(v *string) *UserUpdateOne {
+	if v != nil {
+		_u.SetUsername(*v)
+	}
+	return _u
+}
+
+// ClearUsername clears the value of the "username" field.
+func (_u *UserUpdateOne) ClearUsername() *UserUpdateOne {
+	_u.mutation.ClearUsername()
+	return _u
+}
+
+// SetPasswordHash sets the "password_hash" field.
+func (_u *UserUpdateOne) SetPasswordHash(v string) *UserUpdateOne {
+	_u.mutation.SetPasswordHash(v)
+	return _u
+}
+
+// SetNillablePasswordHash sets the "password_hash" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillablePasswordHash(v *string) *UserUpdateOne {
+	if v != nil {
+		_u.SetPasswordHash(*v)
+	}
+	return _u
+}
+
+// ClearPasswordHash clears the value of the "password_hash" field.
+func (_u *UserUpdateOne) ClearPasswordHash() *UserUpdateOne {
+	_u.mutation.ClearPasswordHash()
+	return _u
+}
+
+// SetPasswordChangedAt sets the "password_changed_at" field.
+func (_u *UserUpdateOne) SetPasswordChangedAt(v time.Time) *UserUpdateOne {
+	_u.mutation.SetPasswordChangedAt(v)
+	return _u
+}
+
+// SetNillablePasswordChangedAt sets the "password_changed_at" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillablePasswordChangedAt(v *time.Time) *UserUpdateOne {
+	if v != nil {
+		_u.SetPasswordChangedAt(*v)
+	}
+	return _u
+}
+
+// ClearPasswordChangedAt clears the value of the "password_changed_at" field.
+func (_u *UserUpdateOne) ClearPasswordChangedAt() *UserUpdateOne {
+	_u.mutation.ClearPasswordChangedAt()
+	return _u
+}
+
+// SetOauthID sets the "oauth_id" field.
+func (_u *UserUpdateOne) SetOauthID(v string) *UserUpdateOne {
+	_u.mutation.SetOauthID(v)
+	return _u
+}
+
+// SetNillableOauthID sets the "oauth_id" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableOauthID(v *string) *UserUpdateOne {
+	if v != nil {
+		_u.SetOauthID(*v)
+	}
+	return _u
+}
+
+// ClearOauthID clears the value of the "oauth_id" field.
+func (_u *UserUpdateOne) ClearOauthID() *UserUpdateOne {
+	_u.mutation.ClearOauthID()
+	return _u
+}
+
+// SetProvider sets the "provider" field.
+func (_u *UserUpdateOne) SetProvider(v user.Provider) *UserUpdateOne {
+	_u.mutation.SetProvider(v)
+	return _u
+}
+
+// SetNillableProvider sets the "provider" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableProvider(v *user.Provider) *UserUpdateOne {
+	if v != nil {
+		_u.SetProvider(*v)
+	}
+	return _u
+}
+
+// SetFirstName sets the "first_name" field.
+func (_u *UserUpdateOne) SetFirstName(v string) *UserUpdateOne {
+	_u.mutation.SetFirstName(v)
+	return _u
+}
+
+// SetNillableFirstName sets the "first_name" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableFirstName(v *string) *UserUpdateOne {
+	if v != nil {
+		_u.SetFirstName(*v)
+	}
+	return _u
+}
+
+// SetLastName sets the "last_name" field.
+func (_u *UserUpdateOne) SetLastName(v string) *UserUpdateOne {
+	_u.mutation.SetLastName(v)
+	return _u
+}
+
+// SetNillableLastName sets the "last_name" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableLastName(v *string) *UserUpdateOne {
+	if v != nil {
+		_u.SetLastName(*v)
+	}
+	return _u
+}
+
+// SetPhoneNumber sets the "phone_number" field.
+func (_u *UserUpdateOne) SetPhoneNumber(v string) *UserUpdateOne {
+	_u.mutation.SetPhoneNumber(v)
+	return _u
+}
+
+// SetNillablePhoneNumber sets the "phone_number" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillablePhoneNumber(v *string) *UserUpdateOne {
+	if v != nil {
+		_u.SetPhoneNumber(*v)
+	}
+	return _u
+}
+
+// ClearPhoneNumber clears the value of the "phone_number" field.
+func (_u *UserUpdateOne) ClearPhoneNumber() *UserUpdateOne {
+	_u.mutation.ClearPhoneNumber()
+	return _u
+}
+
+// SetRole sets the "role" field.
+func (_u *UserUpdateOne) SetRole(v user.Role) *UserUpdateOne {
+	_u.mutation.SetRole(v)
+	return _u
+}
+
+// SetNillableRole sets the "role" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableRole(v *user.Role) *UserUpdateOne {
+	if v != nil {
+		_u.SetRole(*v)
+	}
+	return _u
+}
+
+// SetIsEmailVerified sets the "is_email_verified" field.
+func (_u *UserUpdateOne) SetIsEmailVerified(v bool) *UserUpdateOne {
+	_u.mutation.SetIsEmailVerified(v)
+	return _u
+}
+
+// SetNillableIsEmailVerified sets the "is_email_verified" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableIsEmailVerified(v *bool) *UserUpdateOne {
+	if v != nil {
+		_u.SetIsEmailVerified(*v)
+	}
+	return _u
 }
 
-// UserUpdateOne is the builder for updating a single User entity.
-type UserUpdateOne struct {
-	config
-	fields   []string
-	hooks    []Hook
-	mutation *UserMutation
+// SetMarketingOptIn sets the "marketing_opt_in" field.
+func (_u *UserUpdateOne) SetMarketingOptIn(v bool) *UserUpdateOne {
+	_u.mutation.SetMarketingOptIn(v)
+	return _u
 }
 
-// SetUpdatedAt sets the "updated_at" field.
-func (uuo *UserUpdateOne) SetUpdatedAt(t time.Time) *UserUpdateOne {
-	uuo.mutation.SetUpdatedAt(t)
-	return uuo
+// SetNillableMarketingOptIn sets the "marketing_opt_in" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableMarketingOptIn(v *bool) *UserUpdateOne {
+	if v != nil {
+		_u.SetMarketingOptIn(*v)
+	}
+	return _u
 }
 
-// SetDeletedAt sets the "deleted_at" field.
-func (uuo *UserUpdateOne) SetDeletedAt(t time.Time) *UserUpdateOne {
-	uuo.mutation.SetDeletedAt(t)
-	return uuo
+// SetTermsAcceptedAt sets the "terms_accepted_at" field.
+func (_u *UserUpdateOne) SetTermsAcceptedAt(v time.Time) *UserUpdateOne {
+	_u.mutation.SetTermsAcceptedAt(v)
+	return _u
 }
 
-// SetNillableDeletedAt sets the "deleted_at" field if the given value is not nil.
-func (uuo *UserUpdateOne) SetNillableDeletedAt(t *time.Time) *UserUpdateOne {
-	if t != nil {
-		uuo.SetDeletedAt(*t)
+// SetNillableTermsAcceptedAt sets the "terms_accepted_at" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableTermsAcceptedAt(v *time.Time) *UserUpdateOne {
+	if v != nil {
+		_u.SetTermsAcceptedAt(*v)
 	}
-	return uuo
+	return _u
 }
 
-// ClearDeletedAt clears the value of the "deleted_at" field.
-func (uuo *UserUpdateOne) ClearDeletedAt() *UserUpdateOne {
-	uuo.mutation.ClearDeletedAt()
-	return uuo
+// ClearTermsAcceptedAt clears the value of the "terms_accepted_at" field.
+func (_u *UserUpdateOne) ClearTermsAcceptedAt() *UserUpdateOne {
+	_u.mutation.ClearTermsAcceptedAt()
+	return _u
 }
 
-// SetStreetName sets the "street_name" field.
-func (uuo *UserUpdateOne) SetStreetName(s string) *UserUpdateOne {
-	uuo.mutation.SetStreetName(s)
-	return uuo
+// SetTermsVersion sets the "terms_version" field.
+func (_u *UserUpdateOne) SetTermsVersion(v string) *UserUpdateOne {
+	_u.mutation.SetTermsVersion(v)
+	return _u
 }
 
-// SetNillableStreetName sets the "street_name" field if the given value is not nil.
-func (uuo *UserUpdateOne) SetNillableStreetName(s *string) *UserUpdateOne {
-	if s != nil {
-		uuo.SetStreetName(*s)
+// SetNillableTermsVersion sets the "terms_version" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableTermsVersion(v *string) *UserUpdateOne {
+	if v != nil {
+		_u.SetTermsVersion(*v)
 	}
-	return uuo
+	return _u
 }
 
-// SetCity sets the "city" field.
-func (uuo *UserUpdateOne) SetCity(s string) *UserUpdateOne {
-	uuo.mutation.SetCity(s)
-	return uuo
+// ClearTermsVersion clears the value of the "terms_version" field.
+func (_u *UserUpdateOne) ClearTermsVersion() *UserUpdateOne {
+	_u.mutation.ClearTermsVersion()
+	return _u
 }
 
-// SetNillableCity sets the "city" field if the given value is not nil.
-func (uuo *UserUpdateOne) SetNillableCity(s *string) *UserUpdateOne {
-	if s != nil {
-		uuo.SetCity(*s)
-	}
-	return uuo
+// SetLastLoginAt sets the "last_login_at" field.
+func (_u *UserUpdateOne) SetLastLoginAt(v time.Time) *UserUpdateOne {
+	_u.mutation.SetLastLoginAt(v)
+	return _u
 }
 
-// SetZipCode sets the "zip_code" field.
-func (uuo *UserUpdateOne) SetZipCode(s string) *UserUpdateOne {
-	uuo.mutation.SetZipCode(s)
-	return uuo
+// SetNillableLastLoginAt sets the "last_login_at" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableLastLoginAt(v *time.Time) *UserUpdateOne {
+	if v != nil {
+		_u.SetLastLoginAt(*v)
+	}
+	return _u
 }
 
-// SetNillableZipCode sets the "zip_code" field if the given value is not nil.
-func (uuo *UserUpdateOne) SetNillableZipCode(s *string) *UserUpdateOne {
-	if s != nil {
-		uuo.SetZipCode(*s)
-	}
-	return uuo
+// ClearLastLoginAt clears the value of the "last_login_at" field.
+func (_u *UserUpdateOne) ClearLastLoginAt() *UserUpdateOne {
+	_u.mutation.ClearLastLoginAt()
+	return _u
 }
 
-// SetCountry sets the "country" field.
-func (uuo *UserUpdateOne) SetCountry(s string) *UserUpdateOne {
-	uuo.mutation.SetCountry(s)
-	return uuo
+// SetOnboardingStep sets the "onboarding_step" field.
+func (_u *UserUpdateOne) SetOnboardingStep(v user.OnboardingStep) *UserUpdateOne {
+	_u.mutation.SetOnboardingStep(v)
+	return _u
 }
 
-// SetNillableCountry sets the "country" field if the given value is not nil.
-func (uuo *UserUpdateOne) SetNillableCountry(s *string) *UserUpdateOne {
-	if s != nil {
-		uuo.SetCountry(*s)
+// SetNillableOnboardingStep sets the "onboarding_step" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableOnboardingStep(v *user.OnboardingStep) *UserUpdateOne {
+	if v != nil {
+		_u.SetOnboardingStep(*v)
 	}
-	return uuo
+	return _u
 }
 
-// SetState sets the "state" field.
-func (uuo *UserUpdateOne) SetState(s string) *UserUpdateOne {
-	uuo.mutation.SetState(s)
-	return uuo
+// SetSuspendedAt sets the "suspended_at" field.
+func (_u *UserUpdateOne) SetSuspendedAt(v time.Time) *UserUpdateOne {
+	_u.mutation.SetSuspendedAt(v)
+	return _u
 }
 
-// SetNillableState sets the "state" field if the given value is not nil.
-func (uuo *UserUpdateOne) SetNillableState(s *string) *UserUpdateOne {
-	if s != nil {
-		uuo.SetState(*s)
+// SetNillableSuspendedAt sets the "suspended_at" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableSuspendedAt(v *time.Time) *UserUpdateOne {
+	if v != nil {
+		_u.SetSuspendedAt(*v)
 	}
-	return uuo
+	return _u
 }
 
-// SetEmail sets the "email" field.
-func (uuo *UserUpdateOne) SetEmail(s string) *UserUpdateOne {
-	uuo.mutation.SetEmail(s)
-	return uuo
+// ClearSuspendedAt clears the value of the "suspended_at" field.
+func (_u *UserUpdateOne) ClearSuspendedAt() *UserUpdateOne {
+	_u.mutation.ClearSuspendedAt()
+	return _u
 }
 
-// SetNillableEmail sets the "email" field if the given value is not nil.
-func (uuo *UserUpdateOne) SetNillableEmail(s *string) *UserUpdateOne {
-	if s != nil {
-		uuo.SetEmail(*s)
+// SetSuspensionReason sets the "suspension_reason" field.
+func (_u *UserUpdateOne) SetSuspensionReason(v user.SuspensionReason) *UserUpdateOne {
+	_u.mutation.SetSuspensionReason(v)
+	return _u
+}
+
+// SetNillableSuspensionReason sets the "suspension_reason" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableSuspensionReason(v *user.SuspensionReason) *UserUpdateOne {
+	if v != nil {
+		_u.SetSuspensionReason(*v)
 	}
-	return uuo
+	return _u
 }
 
-// SetUsername sets the "username" field.
-func (uuo *UserUpdateOne) SetUsername(s string) *UserUpdateOne {
-	uuo.mutation.SetUsername(s)
-	return uuo
+// ClearSuspensionReason clears the value of the "suspension_reason" field.
+func (_u *UserUpdateOne) ClearSuspensionReason() *UserUpdateOne {
+	_u.mutation.ClearSuspensionReason()
+	return _u
 }
 
-// SetNillableUsername sets the "username" field if the given value is not nil.
-func (uuo *UserUpdateOne) SetNillableUsername(s *string) *UserUpdateOne {
-	if s != nil {
-		uuo.SetUsername(*s)
+// SetResidency sets the "residency" field.
+func (_u *UserUpdateOne) SetResidency(v string) *UserUpdateOne {
+	_u.mutation.SetResidency(v)
+	return _u
+}
+
+// SetNillableResidency sets the "residency" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableResidency(v *string) *UserUpdateOne {
+	if v != nil {
+		_u.SetResidency(*v)
 	}
-	return uuo
+	return _u
 }
 
-// ClearUsername clears the value of the "username" field.
-func (uuo *UserUpdateOne) ClearUsername() *UserUpdateOne {
-	uuo.mutation.ClearUsername()
-	return uuo
+// ClearResidency clears the value of the "residency" field.
+func (_u *UserUpdateOne) ClearResidency() *UserUpdateOne {
+	_u.mutation.ClearResidency()
+	return _u
 }
 
-// SetPasswordHash sets the "password_hash" field.
-func (uuo *UserUpdateOne) SetPasswordHash(s string) *UserUpdateOne {
-	uuo.mutation.SetPasswordHash(s)
-	return uuo
+// SetDeactivatedAt sets the "deactivated_at" field.
+func (_u *UserUpdateOne) SetDeactivatedAt(v time.Time) *UserUpdateOne {
+	_u.mutation.SetDeactivatedAt(v)
+	return _u
 }
 
-// SetNillablePasswordHash sets the "password_hash" field if the given value is not nil.
-func (uuo *UserUpdateOne) SetNillablePasswordHash(s *string) *UserUpdateOne {
-	if s != nil {
-		uuo.SetPasswordHash(*s)
+// SetNillableDeactivatedAt sets the "deactivated_at" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableDeactivatedAt(v *time.Time) *UserUpdateOne {
+	if v != nil {
+		_u.SetDeactivatedAt(*v)
 	}
-	return uuo
+	return _u
 }
 
-// ClearPasswordHash clears the value of the "password_hash" field.
-func (uuo *UserUpdateOne) ClearPasswordHash() *UserUpdateOne {
-	uuo.mutation.ClearPasswordHash()
-	return uuo
+// ClearDeactivatedAt clears the value of the "deactivated_at" field.
+func (_u *UserUpdateOne) ClearDeactivatedAt() *UserUpdateOne {
+	_u.mutation.ClearDeactivatedAt()
+	return _u
 }
 
-// SetOauthID sets the "oauth_id" field.
-func (uuo *UserUpdateOne) SetOauthID(s string) *UserUpdateOne {
-	uuo.mutation.SetOauthID(s)
-	return uuo
+// SetOrganizationID sets the "organization_id" field.
+func (_u *UserUpdateOne) SetOrganizationID(v int64) *UserUpdateOne {
+	_u.mutation.SetOrganizationID(v)
+	return _u
 }
 
-// SetNillableOauthID sets the "oauth_id" field if the given value is not nil.
-func (uuo *UserUpdateOne) SetNillableOauthID(s *string) *UserUpdateOne {
-	if s != nil {
-		uuo.SetOauthID(*s)
+// SetNillableOrganizationID sets the "organization_id" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableOrganizationID(v *int64) *UserUpdateOne {
+	if v != nil {
+		_u.SetOrganizationID(*v)
 	}
-	return uuo
+	return _u
 }
 
-// ClearOauthID clears the value of the "oauth_id" field.
-func (uuo *UserUpdateOne) ClearOauthID() *UserUpdateOne {
-	uuo.mutation.ClearOauthID()
-	return uuo
+// ClearOrganizationID clears the value of the "organization_id" field.
+func (_u *UserUpdateOne) ClearOrganizationID() *UserUpdateOne {
+	_u.mutation.ClearOrganizationID()
+	return _u
 }
 
-// SetProvider sets the "provider" field.
-func (uuo *UserUpdateOne) SetProvider(u user.Provider) *UserUpdateOne {
-	uuo.mutation.SetProvider(u)
-	return uuo
+// SetKind sets the "kind" field.
+func (_u *UserUpdateOne) SetKind(v user.Kind) *UserUpdateOne {
+	_u.mutation.SetKind(v)
+	return _u
 }
 
-// SetNillableProvider sets the "provider" field if the given value is not nil.
-func (uuo *UserUpdateOne) SetNillableProvider(u *user.Provider) *UserUpdateOne {
-	if u != nil {
-		uuo.SetProvider(*u)
+// SetNillableKind sets the "kind" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableKind(v *user.Kind) *UserUpdateOne {
+	if v != nil {
+		_u.SetKind(*v)
 	}
-	return uuo
+	return _u
 }
 
-// SetFirstName sets the "first_name" field.
-func (uuo *UserUpdateOne) SetFirstName(s string) *UserUpdateOne {
-	uuo.mutation.SetFirstName(s)
-	return uuo
+// SetAddressID sets the "address" edge to the UserAddress entity by ID.
+func (_u *UserUpdateOne) SetAddressID(id int) *UserUpdateOne {
+	_u.mutation.SetAddressID(id)
+	return _u
 }
 
-// SetNillableFirstName sets the "first_name" field if the given value is not nil.
-func (uuo *UserUpdateOne) SetNillableFirstName(s *string) *UserUpdateOne {
-	if s != nil {
-		uuo.SetFirstName(*s)
+// SetNillableAddressID sets the "address" edge to the UserAddress entity by ID if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableAddressID(id *int) *UserUpdateOne {
+	if id != nil {
+		_u = _u.SetAddressID(*id)
 	}
-	return uuo
+	return _u
 }
 
-// SetLastName sets the "last_name" field.
-func (uuo *UserUpdateOne) SetLastName(s string) *UserUpdateOne {
-	uuo.mutation.SetLastName(s)
-	return uuo
+// SetAddress sets the "address" edge to the UserAddress entity.
+func (_u *UserUpdateOne) SetAddress(v *UserAddress) *UserUpdateOne {
+	return _u.SetAddressID(v.ID)
 }
 
-// SetNillableLastName sets the "last_name" field if the given value is not nil.
-func (uuo *UserUpdateOne) SetNillableLastName(s *string) *UserUpdateOne {
-	if s != nil {
-		uuo.SetLastName(*s)
+// AddLoginAttemptIDs adds the "login_attempts" edge to the LoginAttempt entity by IDs.
+func (_u *UserUpdateOne) AddLoginAttemptIDs(ids ...int64) *UserUpdateOne {
+	_u.mutation.AddLoginAttemptIDs(ids...)
+	return _u
+}
+
+// AddLoginAttempts adds the "login_attempts" edges to the LoginAttempt entity.
+func (_u *UserUpdateOne) AddLoginAttempts(v ...*LoginAttempt) *UserUpdateOne {
+	ids := make([]int64, len(v))
+	for i := range v {
+		ids[i] = v[i].ID
 	}
-	return uuo
+	return _u.AddLoginAttemptIDs(ids...)
 }
 
-// SetPhoneNumber sets the "phone_number" field.
-func (uuo *UserUpdateOne) SetPhoneNumber(s string) *UserUpdateOne {
-	uuo.mutation.SetPhoneNumber(s)
-	return uuo
+// SetOrganization sets the "organization" edge to the Organization entity.
+func (_u *UserUpdateOne) SetOrganization(v *Organization) *UserUpdateOne {
+	return _u.SetOrganizationID(v.ID)
 }
 
-// SetNillablePhoneNumber sets the "phone_number" field if the given value is not nil.
-func (uuo *UserUpdateOne) SetNillablePhoneNumber(s *string) *UserUpdateOne {
-	if s != nil {
-		uuo.SetPhoneNumber(*s)
-	}
-	return uuo
+// AddServiceAccountKeyIDs adds the "service_account_keys" edge to the ServiceAccountKey entity by IDs.
+func (_u *UserUpdateOne) AddServiceAccountKeyIDs(ids ...int64) *UserUpdateOne {
+	_u.mutation.AddServiceAccountKeyIDs(ids...)
+	return _u
 }
 
-// ClearPhoneNumber clears the value of the "phone_number" field.
-func (uuo *UserUpdateOne) ClearPhoneNumber() *UserUpdateOne {
-	uuo.mutation.ClearPhoneNumber()
-	return uuo
+// AddServiceAccountKeys adds the "service_account_keys" edges to the ServiceAccountKey entity.
+func (_u *UserUpdateOne) AddServiceAccountKeys(v ...*ServiceAccountKey) *UserUpdateOne {
+	ids := make([]int64, len(v))
+	for i := range v {
+		ids[i] = v[i].ID
+	}
+	return _u.AddServiceAccountKeyIDs(ids...)
 }
 
-// SetRole sets the "role" field.
-func (uuo *UserUpdateOne) SetRole(u user.Role) *UserUpdateOne {
-	uuo.mutation.SetRole(u)
-	return uuo
+// AddIdentityIDs adds the "identities" edge to the UserIdentity entity by IDs.
+func (_u *UserUpdateOne) AddIdentityIDs(ids ...int64) *UserUpdateOne {
+	_u.mutation.AddIdentityIDs(ids...)
+	return _u
 }
 
-// SetNillableRole sets the "role" field if the given value is not nil.
-func (uuo *UserUpdateOne) SetNillableRole(u *user.Role) *UserUpdateOne {
-	if u != nil {
-		uuo.SetRole(*u)
+// AddIdentities adds the "identities" edges to the UserIdentity entity.
+func (_u *UserUpdateOne) AddIdentities(v ...*UserIdentity) *UserUpdateOne {
+	ids := make([]int64, len(v))
+	for i := range v {
+		ids[i] = v[i].ID
 	}
-	return uuo
+	return _u.AddIdentityIDs(ids...)
 }
 
-// SetIsEmailVerified sets the "is_email_verified" field.
-func (uuo *UserUpdateOne) SetIsEmailVerified(b bool) *UserUpdateOne {
-	uuo.mutation.SetIsEmailVerified(b)
-	return uuo
+// AddScheduledActionIDs adds the "scheduled_actions" edge to the ScheduledAction entity by IDs.
+func (_u *UserUpdateOne) AddScheduledActionIDs(ids ...int64) *UserUpdateOne {
+	_u.mutation.AddScheduledActionIDs(ids...)
+	return _u
 }
 
-// SetNillableIsEmailVerified sets the "is_email_verified" field if the given value is not nil.
-func (uuo *UserUpdateOne) SetNillableIsEmailVerified(b *bool) *UserUpdateOne {
-	if b != nil {
-		uuo.SetIsEmailVerified(*b)
+// AddScheduledActions adds the "scheduled_actions" edges to the ScheduledAction entity.
+func (_u *UserUpdateOne) AddScheduledActions(v ...*ScheduledAction) *UserUpdateOne {
+	ids := make([]int64, len(v))
+	for i := range v {
+		ids[i] = v[i].ID
 	}
-	return uuo
+	return _u.AddScheduledActionIDs(ids...)
 }
 
-// SetMarketingOptIn sets the "marketing_opt_in" field.
-func (uuo *UserUpdateOne) SetMarketingOptIn(b bool) *UserUpdateOne {
-	uuo.mutation.SetMarketingOptIn(b)
-	return uuo
+// Mutation returns the UserMutation object of the builder.
+func (_u *UserUpdateOne) Mutation() *UserMutation {
+	return _u.mutation
 }
 
-// SetNillableMarketingOptIn sets the "marketing_opt_in" field if the given value is not nil.
-func (uuo *UserUpdateOne) SetNillableMarketingOptIn(b *bool) *UserUpdateOne {
-	if b != nil {
-		uuo.SetMarketingOptIn(*b)
-	}
-	return uuo
+// ClearAddress clears the "address" edge to the UserAddress entity.
+func (_u *UserUpdateOne) ClearAddress() *UserUpdateOne {
+	_u.mutation.ClearAddress()
+	return _u
 }
 
-// SetTermsAcceptedAt sets the "terms_accepted_at" field.
-func (uuo *UserUpdateOne) SetTermsAcceptedAt(t time.Time) *UserUpdateOne {
-	uuo.mutation.SetTermsAcceptedAt(t)
-	return uuo
+// ClearLoginAttempts clears all "login_attempts" edges to the LoginAttempt entity.
+func (_u *UserUpdateOne) ClearLoginAttempts() *UserUpdateOne {
+	_u.mutation.ClearLoginAttempts()
+	return _u
 }
 
-// SetNillableTermsAcceptedAt sets the "terms_accepted_at" field if the given value is not nil.
-func (uuo *UserUpdateOne) SetNillableTermsAcceptedAt(t *time.Time) *UserUpdateOne {
-	if t != nil {
-		uuo.SetTermsAcceptedAt(*t)
+// RemoveLoginAttemptIDs removes the "login_attempts" edge to LoginAttempt entities by IDs.
+func (_u *UserUpdateOne) RemoveLoginAttemptIDs(ids ...int64) *UserUpdateOne {
+	_u.mutation.RemoveLoginAttemptIDs(ids...)
+	return _u
+}
+
+// RemoveLoginAttempts removes "login_attempts" edges to LoginAttempt entities.
+func (_u *UserUpdateOne) RemoveLoginAttempts(v ...*LoginAttempt) *UserUpdateOne {
+	ids := make([]int64, len(v))
+	for i := range v {
+		ids[i] = v[i].ID
 	}
-	return uuo
+	return _u.RemoveLoginAttemptIDs(ids...)
 }
 
-// ClearTermsAcceptedAt clears the value of the "terms_accepted_at" field.
-func (uuo *UserUpdateOne) ClearTermsAcceptedAt() *UserUpdateOne {
-	uuo.mutation.ClearTermsAcceptedAt()
-	return uuo
+// ClearOrganization clears the "organization" edge to the Organization entity.
+func (_u *UserUpdateOne) ClearOrganization() *UserUpdateOne {
+	_u.mutation.ClearOrganization()
+	return _u
 }
 
-// SetLastLoginAt sets the "last_login_at" field.
-func (uuo *UserUpdateOne) SetLastLoginAt(t time.Time) *UserUpdateOne {
-	uuo.mutation.SetLastLoginAt(t)
-	return uuo
+// ClearServiceAccountKeys clears all "service_account_keys" edges to the ServiceAccountKey entity.
+func (_u *UserUpdateOne) ClearServiceAccountKeys() *UserUpdateOne {
+	_u.mutation.ClearServiceAccountKeys()
+	return _u
 }
 
-// SetNillableLastLoginAt sets the "last_login_at" field if the given value is not nil.
-func (uuo *UserUpdateOne) SetNillableLastLoginAt(t *time.Time) *UserUpdateOne {
-	if t != nil {
-		uuo.SetLastLoginAt(*t)
+// RemoveServiceAccountKeyIDs removes the "service_account_keys" edge to ServiceAccountKey entities by IDs.
+func (_u *UserUpdateOne) RemoveServiceAccountKeyIDs(ids ...int64) *UserUpdateOne {
+	_u.mutation.RemoveServiceAccountKeyIDs(ids...)
+	return _u
+}
+
+// RemoveServiceAccountKeys removes "service_account_keys" edges to ServiceAccountKey entities.
+func (_u *UserUpdateOne) RemoveServiceAccountKeys(v ...*ServiceAccountKey) *UserUpdateOne {
+	ids := make([]int64, len(v))
+	for i := range v {
+		ids[i] = v[i].ID
 	}
-	return uuo
+	return _u.RemoveServiceAccountKeyIDs(ids...)
 }
 
-// ClearLastLoginAt clears the value of the "last_login_at" field.
-func (uuo *UserUpdateOne) ClearLastLoginAt() *UserUpdateOne {
-	uuo.mutation.ClearLastLoginAt()
-	return uuo
+// ClearIdentities clears all "identities" edges to the UserIdentity entity.
+func (_u *UserUpdateOne) ClearIdentities() *UserUpdateOne {
+	_u.mutation.ClearIdentities()
+	return _u
 }
 
-// SetAddressID sets the "address" edge to the UserAddress entity by ID.
-func (uuo *UserUpdateOne) SetAddressID(id int) *UserUpdateOne {
-	uuo.mutation.SetAddressID(id)
-	return uuo
+// RemoveIdentityIDs removes the "identities" edge to UserIdentity entities by IDs.
+func (_u *UserUpdateOne) RemoveIdentityIDs(ids ...int64) *UserUpdateOne {
+	_u.mutation.RemoveIdentityIDs(ids...)
+	return _u
 }
 
-// SetNillableAddressID sets the "address" edge to the UserAddress entity by ID if the given value is not nil.
-func (uuo *UserUpdateOne) SetNillableAddressID(id *int) *UserUpdateOne {
-	if id != nil {
-		uuo = uuo.SetAddressID(*id)
+// RemoveIdentities removes "identities" edges to UserIdentity entities.
+func (_u *UserUpdateOne) RemoveIdentities(v ...*UserIdentity) *UserUpdateOne {
+	ids := make([]int64, len(v))
+	for i := range v {
+		ids[i] = v[i].ID
 	}
-	return uuo
+	return _u.RemoveIdentityIDs(ids...)
 }
 
-// SetAddress sets the "address" edge to the UserAddress entity.
-func (uuo *UserUpdateOne) SetAddress(u *UserAddress) *UserUpdateOne {
-	return uuo.SetAddressID(u.ID)
+// ClearScheduledActions clears all "scheduled_actions" edges to the ScheduledAction entity.
+func (_u *UserUpdateOne) ClearScheduledActions() *UserUpdateOne {
+	_u.mutation.ClearScheduledActions()
+	return _u
 }
 
-// Mutation returns the UserMutation object of the builder.
-func (uuo *UserUpdateOne) Mutation() *UserMutation {
-	return uuo.mutation
+// RemoveScheduledActionIDs removes the "scheduled_actions" edge to ScheduledAction entities by IDs.
+func (_u *UserUpdateOne) RemoveScheduledActionIDs(ids ...int64) *UserUpdateOne {
+	_u.mutation.RemoveScheduledActionIDs(ids...)
+	return _u
 }
 
-// ClearAddress clears the "address" edge to the UserAddress entity.
-func (uuo *UserUpdateOne) ClearAddress() *UserUpdateOne {
-	uuo.mutation.ClearAddress()
-	return uuo
+// RemoveScheduledActions removes "scheduled_actions" edges to ScheduledAction entities.
+func (_u *UserUpdateOne) RemoveScheduledActions(v ...*ScheduledAction) *UserUpdateOne {
+	ids := make([]int64, len(v))
+	for i := range v {
+		ids[i] = v[i].ID
+	}
+	return _u.RemoveScheduledActionIDs(ids...)
 }
 
 // Where appends a list predicates to the UserUpdate builder.
-func (uuo *UserUpdateOne) Where(ps ...predicate.User) *UserUpdateOne {
-	uuo.mutation.Where(ps...)
-	return uuo
+func (_u *UserUpdateOne) Where(ps ...predicate.User) *UserUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
 }
 
 // Select allows selecting one or more fields (columns) of the returned entity.
 // The default is selecting all fields defined in the entity schema.
-func (uuo *UserUpdateOne) Select(field string, fields ...string) *UserUpdateOne {
-	uuo.fields = append([]string{field}, fields...)
-	return uuo
+func (_u *UserUpdateOne) Select(field string, fields ...string) *UserUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
 }
 
 // Save executes the query and returns the updated User entity.
-func (uuo *UserUpdateOne) Save(ctx context.Context) (*User, error) {
-	uuo.defaults()
-	return withHooks(ctx, uuo.sqlSave, uuo.mutation, uuo.hooks)
+func (_u *UserUpdateOne) Save(ctx context.Context) (*User, error) {
+	_u.defaults()
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
 }
 
 // SaveX is like Save, but panics if an error occurs.
-func (uuo *UserUpdateOne) SaveX(ctx context.Context) *User {
-	node, err := uuo.Save(ctx)
+func (_u *UserUpdateOne) SaveX(ctx context.Context) *User {
+	node, err := _u.Save(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -994,107 +1921,132 @@ func (uuo *UserUpdateOne) SaveX(ctx context.Context) *User {
 }
 
 // Exec executes the query on the entity.
-func (uuo *UserUpdateOne) Exec(ctx context.Context) error {
-	_, err := uuo.Save(ctx)
+func (_u *UserUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
 	return err
 }
 
 // ExecX is like Exec, but panics if an error occurs.
-func (uuo *UserUpdateOne) ExecX(ctx context.Context) {
-	if err := uuo.Exec(ctx); err != nil {
+func (_u *UserUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
 		panic(err)
 	}
 }
 
 // defaults sets the default values of the builder before save.
-func (uuo *UserUpdateOne) defaults() {
-	if _, ok := uuo.mutation.UpdatedAt(); !ok {
+func (_u *UserUpdateOne) defaults() {
+	if _, ok := _u.mutation.UpdatedAt(); !ok {
 		v := user.UpdateDefaultUpdatedAt()
-		uuo.mutation.SetUpdatedAt(v)
+		_u.mutation.SetUpdatedAt(v)
 	}
 }
 
 // check runs all checks and user-defined validators on the builder.
-func (uuo *UserUpdateOne) check() error {
-	if v, ok := uuo.mutation.StreetName(); ok {
+func (_u *UserUpdateOne) check() error {
+	if v, ok := _u.mutation.StreetName(); ok {
 		if err := user.StreetNameValidator(v); err != nil {
 			return &ValidationError{Name: "street_name", err: fmt.Errorf(`ent: validator failed for field "User.street_name": %w`, err)}
 		}
 	}
-	if v, ok := uuo.mutation.City(); ok {
+	if v, ok := _u.mutation.City(); ok {
 		if err := user.CityValidator(v); err != nil {
 			return &ValidationError{Name: "city", err: fmt.Errorf(`ent: validator failed for field "User.city": %w`, err)}
 		}
 	}
-	if v, ok := uuo.mutation.ZipCode(); ok {
+	if v, ok := _u.mutation.ZipCode(); ok {
 		if err := user.ZipCodeValidator(v); err != nil {
 			return &ValidationError{Name: "zip_code", err: fmt.Errorf(`ent: validator failed for field "User.zip_code": %w`, err)}
 		}
 	}
-	if v, ok := uuo.mutation.Country(); ok {
+	if v, ok := _u.mutation.Country(); ok {
 		if err := user.CountryValidator(v); err != nil {
 			return &ValidationError{Name: "country", err: fmt.Errorf(`ent: validator failed for field "User.country": %w`, err)}
 		}
 	}
-	if v, ok := uuo.mutation.State(); ok {
+	if v, ok := _u.mutation.State(); ok {
 		if err := user.StateValidator(v); err != nil {
 			return &ValidationError{Name: "state", err: fmt.Errorf(`ent: validator failed for field "User.state": %w`, err)}
 		}
 	}
-	if v, ok := uuo.mutation.Email(); ok {
+	if v, ok := _u.mutation.Email(); ok {
 		if err := user.EmailValidator(v); err != nil {
 			return &ValidationError{Name: "email", err: fmt.Errorf(`ent: validator failed for field "User.email": %w`, err)}
 		}
 	}
-	if v, ok := uuo.mutation.Username(); ok {
+	if v, ok := _u.mutation.Username(); ok {
 		if err := user.UsernameValidator(v); err != nil {
 			return &ValidationError{Name: "username", err: fmt.Errorf(`ent: validator failed for field "User.username": %w`, err)}
 		}
 	}
-	if v, ok := uuo.mutation.OauthID(); ok {
+	if v, ok := _u.mutation.OauthID(); ok {
 		if err := user.OauthIDValidator(v); err != nil {
 			return &ValidationError{Name: "oauth_id", err: fmt.Errorf(`ent: validator failed for field "User.oauth_id": %w`, err)}
 		}
 	}
-	if v, ok := uuo.mutation.Provider(); ok {
+	if v, ok := _u.mutation.Provider(); ok {
 		if err := user.ProviderValidator(v); err != nil {
 			return &ValidationError{Name: "provider", err: fmt.Errorf(`ent: validator failed for field "User.provider": %w`, err)}
 		}
 	}
-	if v, ok := uuo.mutation.FirstName(); ok {
+	if v, ok := _u.mutation.FirstName(); ok {
 		if err := user.FirstNameValidator(v); err != nil {
 			return &ValidationError{Name: "first_name", err: fmt.Errorf(`ent: validator failed for field "User.first_name": %w`, err)}
 		}
 	}
-	if v, ok := uuo.mutation.LastName(); ok {
+	if v, ok := _u.mutation.LastName(); ok {
 		if err := user.LastNameValidator(v); err != nil {
 			return &ValidationError{Name: "last_name", err: fmt.Errorf(`ent: validator failed for field "User.last_name": %w`, err)}
 		}
 	}
-	if v, ok := uuo.mutation.PhoneNumber(); ok {
+	if v, ok := _u.mutation.PhoneNumber(); ok {
 		if err := user.PhoneNumberValidator(v); err != nil {
 			return &ValidationError{Name: "phone_number", err: fmt.Errorf(`ent: validator failed for field "User.phone_number": %w`, err)}
 		}
 	}
-	if v, ok := uuo.mutation.Role(); ok {
+	if v, ok := _u.mutation.Role(); ok {
 		if err := user.RoleValidator(v); err != nil {
 			return &ValidationError{Name: "role", err: fmt.Errorf(`ent: validator failed for field "User.role": %w`, err)}
 		}
 	}
+	if v, ok := _u.mutation.TermsVersion(); ok {
+		if err := user.TermsVersionValidator(v); err != nil {
+			return &ValidationError{Name: "terms_version", err: fmt.Errorf(`ent: validator failed for field "User.terms_version": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.OnboardingStep(); ok {
+		if err := user.OnboardingStepValidator(v); err != nil {
+			return &ValidationError{Name: "onboarding_step", err: fmt.Errorf(`ent: validator failed for field "User.onboarding_step": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.SuspensionReason(); ok {
+		if err := user.SuspensionReasonValidator(v); err != nil {
+			return &ValidationError{Name: "suspension_reason", err: fmt.Errorf(`ent: validator failed for field "User.suspension_reason": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Residency(); ok {
+		if err := user.ResidencyValidator(v); err != nil {
+			return &ValidationError{Name: "residency", err: fmt.Errorf(`ent: validator failed for field "User.residency": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Kind(); ok {
+		if err := user.KindValidator(v); err != nil {
+			return &ValidationError{Name: "kind", err: fmt.Errorf(`ent: validator failed for field "User.kind": %w`, err)}
+		}
+	}
 	return nil
 }
 
-func (uuo *UserUpdateOne) sqlSave(ctx context.Context) (_node *User, err error) {
-	if err := uuo.check(); err != nil {
+func (_u *UserUpdateOne) sqlSave(ctx context.Context) (_node *User, err error) {
+	if err := _u.check(); err != nil {
 		return _node, err
 	}
 	_spec := sqlgraph.NewUpdateSpec(user.Table, user.Columns, sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt64))
-	id, ok := uuo.mutation.ID()
+	id, ok := _u.mutation.ID()
 	if !ok {
 		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "User.id" for update`)}
 	}
 	_spec.Node.ID.Value = id
-	if fields := uuo.fields; len(fields) > 0 {
+	if fields := _u.fields; len(fields) > 0 {
 		_spec.Node.Columns = make([]string, 0, len(fields))
 		_spec.Node.Columns = append(_spec.Node.Columns, user.FieldID)
 		for _, f := range fields {
@@ -1106,95 +2058,137 @@ func (uuo *UserUpdateOne) sqlSave(ctx context.Context) (_node *User, err error)
 			}
 		}
 	}
-	if ps := uuo.mutation.predicates; len(ps) > 0 {
+	if ps := _u.mutation.predicates; len(ps) > 0 {
 		_spec.Predicate = func(selector *sql.Selector) {
 			for i := range ps {
 				ps[i](selector)
 			}
 		}
 	}
-	if value, ok := uuo.mutation.UpdatedAt(); ok {
+	if value, ok := _u.mutation.UpdatedAt(); ok {
 		_spec.SetField(user.FieldUpdatedAt, field.TypeTime, value)
 	}
-	if value, ok := uuo.mutation.DeletedAt(); ok {
+	if value, ok := _u.mutation.DeletedAt(); ok {
 		_spec.SetField(user.FieldDeletedAt, field.TypeTime, value)
 	}
-	if uuo.mutation.DeletedAtCleared() {
+	if _u.mutation.DeletedAtCleared() {
 		_spec.ClearField(user.FieldDeletedAt, field.TypeTime)
 	}
-	if value, ok := uuo.mutation.StreetName(); ok {
+	if value, ok := _u.mutation.StreetName(); ok {
 		_spec.SetField(user.FieldStreetName, field.TypeString, value)
 	}
-	if value, ok := uuo.mutation.City(); ok {
+	if value, ok := _u.mutation.City(); ok {
 		_spec.SetField(user.FieldCity, field.TypeString, value)
 	}
-	if value, ok := uuo.mutation.ZipCode(); ok {
+	if value, ok := _u.mutation.ZipCode(); ok {
 		_spec.SetField(user.FieldZipCode, field.TypeString, value)
 	}
-	if value, ok := uuo.mutation.Country(); ok {
+	if value, ok := _u.mutation.Country(); ok {
 		_spec.SetField(user.FieldCountry, field.TypeString, value)
 	}
-	if value, ok := uuo.mutation.State(); ok {
+	if value, ok := _u.mutation.State(); ok {
 		_spec.SetField(user.FieldState, field.TypeString, value)
 	}
-	if value, ok := uuo.mutation.Email(); ok {
+	if value, ok := _u.mutation.Email(); ok {
 		_spec.SetField(user.FieldEmail, field.TypeString, value)
 	}
-	if value, ok := uuo.mutation.Username(); ok {
+	if value, ok := _u.mutation.Username(); ok {
 		_spec.SetField(user.FieldUsername, field.TypeString, value)
 	}
-	if uuo.mutation.UsernameCleared() {
+	if _u.mutation.UsernameCleared() {
 		_spec.ClearField(user.FieldUsername, field.TypeString)
 	}
-	if value, ok := uuo.mutation.PasswordHash(); ok {
+	if value, ok := _u.mutation.PasswordHash(); ok {
 		_spec.SetField(user.FieldPasswordHash, field.TypeString, value)
 	}
-	if uuo.mutation.PasswordHashCleared() {
+	if _u.mutation.PasswordHashCleared() {
 		_spec.ClearField(user.FieldPasswordHash, field.TypeString)
 	}
-	if value, ok := uuo.mutation.OauthID(); ok {
+	if value, ok := _u.mutation.PasswordChangedAt(); ok {
+		_spec.SetField(user.FieldPasswordChangedAt, field.TypeTime, value)
+	}
+	if _u.mutation.PasswordChangedAtCleared() {
+		_spec.ClearField(user.FieldPasswordChangedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.OauthID(); ok {
 		_spec.SetField(user.FieldOauthID, field.TypeString, value)
 	}
-	if uuo.mutation.OauthIDCleared() {
+	if _u.mutation.OauthIDCleared() {
 		_spec.ClearField(user.FieldOauthID, field.TypeString)
 	}
-	if value, ok := uuo.mutation.Provider(); ok {
+	if value, ok := _u.mutation.Provider(); ok {
 		_spec.SetField(user.FieldProvider, field.TypeEnum, value)
 	}
-	if value, ok := uuo.mutation.FirstName(); ok {
+	if value, ok := _u.mutation.FirstName(); ok {
 		_spec.SetField(user.FieldFirstName, field.TypeString, value)
 	}
-	if value, ok := uuo.mutation.LastName(); ok {
+	if value, ok := _u.mutation.LastName(); ok {
 		_spec.SetField(user.FieldLastName, field.TypeString, value)
 	}
-	if value, ok := uuo.mutation.PhoneNumber(); ok {
+	if value, ok := _u.mutation.PhoneNumber(); ok {
 		_spec.SetField(user.FieldPhoneNumber, field.TypeString, value)
 	}
-	if uuo.mutation.PhoneNumberCleared() {
+	if _u.mutation.PhoneNumberCleared() {
 		_spec.ClearField(user.FieldPhoneNumber, field.TypeString)
 	}
-	if value, ok := uuo.mutation.Role(); ok {
+	if value, ok := _u.mutation.Role(); ok {
 		_spec.SetField(user.FieldRole, field.TypeEnum, value)
 	}
-	if value, ok := uuo.mutation.IsEmailVerified(); ok {
+	if value, ok := _u.mutation.IsEmailVerified(); ok {
 		_spec.SetField(user.FieldIsEmailVerified, field.TypeBool, value)
 	}
-	if value, ok := uuo.mutation.MarketingOptIn(); ok {
+	if value, ok := _u.mutation.MarketingOptIn(); ok {
 		_spec.SetField(user.FieldMarketingOptIn, field.TypeBool, value)
 	}
-	if value, ok := uuo.mutation.TermsAcceptedAt(); ok {
+	if value, ok := _u.mutation.TermsAcceptedAt(); ok {
 		_spec.SetField(user.FieldTermsAcceptedAt, field.TypeTime, value)
 	}
-	if uuo.mutation.TermsAcceptedAtCleared() {
+	if _u.mutation.TermsAcceptedAtCleared() {
 		_spec.ClearField(user.FieldTermsAcceptedAt, field.TypeTime)
 	}
-	if value, ok := uuo.mutation.LastLoginAt(); ok {
+	if value, ok := _u.mutation.TermsVersion(); ok {
+		_spec.SetField(user.FieldTermsVersion, field.TypeString, value)
+	}
+	if _u.mutation.TermsVersionCleared() {
+		_spec.ClearField(user.FieldTermsVersion, field.TypeString)
+	}
+	if value, ok := _u.mutation.LastLoginAt(); ok {
 		_spec.SetField(user.FieldLastLoginAt, field.TypeTime, value)
 	}
-	if uuo.mutation.LastLoginAtCleared() {
+	if _u.mutation.LastLoginAtCleared() {
 		_spec.ClearField(user.FieldLastLoginAt, field.TypeTime)
 	}
-	if uuo.mutation.AddressCleared() {
+	if value, ok := _u.mutation.OnboardingStep(); ok {
+		_spec.SetField(user.FieldOnboardingStep, field.TypeEnum, value)
+	}
+	if value, ok := _u.mutation.SuspendedAt(); ok {
+		_spec.SetField(user.FieldSuspendedAt, field.TypeTime, value)
+	}
+	if _u.mutation.SuspendedAtCleared() {
+		_spec.ClearField(user.FieldSuspendedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.SuspensionReason(); ok {
+		_spec.SetField(user.FieldSuspensionReason, field.TypeEnum, value)
+	}
+	if _u.mutation.SuspensionReasonCleared() {
+		_spec.ClearField(user.FieldSuspensionReason, field.TypeEnum)
+	}
+	if value, ok := _u.mutation.Residency(); ok {
+		_spec.SetField(user.FieldResidency, field.TypeString, value)
+	}
+	if _u.mutation.ResidencyCleared() {
+		_spec.ClearField(user.FieldResidency, field.TypeString)
+	}
+	if value, ok := _u.mutation.DeactivatedAt(); ok {
+		_spec.SetField(user.FieldDeactivatedAt, field.TypeTime, value)
+	}
+	if _u.mutation.DeactivatedAtCleared() {
+		_spec.ClearField(user.FieldDeactivatedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.Kind(); ok {
+		_spec.SetField(user.FieldKind, field.TypeEnum, value)
+	}
+	if _u.mutation.AddressCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.M2O,
 			Inverse: false,
@@ -1207,7 +2201,7 @@ func (uuo *UserUpdateOne) sqlSave(ctx context.Context) (_node *User, err error)
 		}
 		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
 	}
-	if nodes := uuo.mutation.AddressIDs(); len(nodes) > 0 {
+	if nodes := _u.mutation.AddressIDs(); len(nodes) > 0 {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.M2O,
 			Inverse: false,
@@ -1223,10 +2217,219 @@ func (uuo *UserUpdateOne) sqlSave(ctx context.Context) (_node *User, err error)
 		}
 		_spec.Edges.Add = append(_spec.Edges.Add, edge)
 	}
-	_node = &User{config: uuo.config}
+	if _u.mutation.LoginAttemptsCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.LoginAttemptsTable,
+			Columns: []string{user.LoginAttemptsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(loginattempt.FieldID, field.TypeInt64),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.RemovedLoginAttemptsIDs(); len(nodes) > 0 && !_u.mutation.LoginAttemptsCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.LoginAttemptsTable,
+			Columns: []string{user.LoginAttemptsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(loginattempt.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.LoginAttemptsIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.LoginAttemptsTable,
+			Columns: []string{user.LoginAttemptsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(loginattempt.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if _u.mutation.OrganizationCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   user.OrganizationTable,
+			Columns: []string{user.OrganizationColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(organization.FieldID, field.TypeInt64),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.OrganizationIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   user.OrganizationTable,
+			Columns: []string{user.OrganizationColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(organization.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if _u.mutation.ServiceAccountKeysCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.ServiceAccountKeysTable,
+			Columns: []string{user.ServiceAccountKeysColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(serviceaccountkey.FieldID, field.TypeInt64),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.RemovedServiceAccountKeysIDs(); len(nodes) > 0 && !_u.mutation.ServiceAccountKeysCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.ServiceAccountKeysTable,
+			Columns: []string{user.ServiceAccountKeysColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(serviceaccountkey.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.ServiceAccountKeysIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.ServiceAccountKeysTable,
+			Columns: []string{user.ServiceAccountKeysColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(serviceaccountkey.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if _u.mutation.IdentitiesCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.IdentitiesTable,
+			Columns: []string{user.IdentitiesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(useridentity.FieldID, field.TypeInt64),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.RemovedIdentitiesIDs(); len(nodes) > 0 && !_u.mutation.IdentitiesCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.IdentitiesTable,
+			Columns: []string{user.IdentitiesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(useridentity.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.IdentitiesIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.IdentitiesTable,
+			Columns: []string{user.IdentitiesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(useridentity.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if _u.mutation.ScheduledActionsCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.ScheduledActionsTable,
+			Columns: []string{user.ScheduledActionsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(scheduledaction.FieldID, field.TypeInt64),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.RemovedScheduledActionsIDs(); len(nodes) > 0 && !_u.mutation.ScheduledActionsCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.ScheduledActionsTable,
+			Columns: []string{user.ScheduledActionsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(scheduledaction.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.ScheduledActionsIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.ScheduledActionsTable,
+			Columns: []string{user.ScheduledActionsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(scheduledaction.FieldID, field.TypeInt64),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &User{config: _u.config}
 	_spec.Assign = _node.assignValues
 	_spec.ScanValues = _node.scanValues
-	if err = sqlgraph.UpdateNode(ctx, uuo.driver, _spec); err != nil {
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
 		if _, ok := err.(*sqlgraph.NotFoundError); ok {
 			err = &NotFoundError{user.Label}
 		} else if sqlgraph.IsConstraintError(err) {
@@ -1234,6 +2437,6 @@ func (uuo *UserUpdateOne) sqlSave(ctx context.Context) (_node *User, err error)
 		}
 		return nil, err
 	}
-	uuo.mutation.done = true
+	_u.mutation.done = true
 	return _node, nil
 }
@@ -35,7 +35,7 @@ func (*UserAddress) scanValues(columns []string) ([]any, error) {
 
 // assignValues assigns the values that were returned from sql.Rows (after scanning)
 // to the UserAddress fields.
-func (ua *UserAddress) assignValues(columns []string, values []any) error {
+func (_m *UserAddress) assignValues(columns []string, values []any) error {
 	if m, n := len(values), len(columns); m < n {
 		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
 	}
@@ -46,9 +46,9 @@ func (ua *UserAddress) assignValues(columns []string, values []any) error {
 			if !ok {
 				return fmt.Errorf("unexpected type %T for field id", value)
 			}
-			ua.ID = int(value.Int64)
+			_m.ID = int(value.Int64)
 		default:
-			ua.selectValues.Set(columns[i], values[i])
+			_m.selectValues.Set(columns[i], values[i])
 		}
 	}
 	return nil
@@ -56,33 +56,33 @@ func (ua *UserAddress) assignValues(columns []string, values []any) error {
 
 // Value returns the ent.Value that was dynamically selected and assigned to the UserAddress.
 // This includes values selected through modifiers, order, etc.
-func (ua *UserAddress) Value(name string) (ent.Value, error) {
-	return ua.selectValues.Get(name)
+func (_m *UserAddress) Value(name string) (ent.Value, error) {
+	return _m.selectValues.Get(name)
 }
 
 // Update returns a builder for updating this UserAddress.
 // Note that you need to call UserAddress.Unwrap() before calling this method if this UserAddress
 // was returned from a transaction, and the transaction was committed or rolled back.
-func (ua *UserAddress) Update() *UserAddressUpdateOne {
-	return NewUserAddressClient(ua.config).UpdateOne(ua)
+func (_m *UserAddress) Update() *UserAddressUpdateOne {
+	return NewUserAddressClient(_m.config).UpdateOne(_m)
 }
 
 // Unwrap unwraps the UserAddress entity that was returned from a transaction after it was closed,
 // so that all future queries will be executed through the driver which created the transaction.
-func (ua *UserAddress) Unwrap() *UserAddress {
-	_tx, ok := ua.config.driver.(*txDriver)
+func (_m *UserAddress) Unwrap() *UserAddress {
+	_tx, ok := _m.config.driver.(*txDriver)
 	if !ok {
 		panic("ent: UserAddress is not a transactional entity")
 	}
-	ua.config.driver = _tx.drv
-	return ua
+	_m.config.driver = _tx.drv
+	return _m
 }
 
 // String implements the fmt.Stringer.
-func (ua *UserAddress) String() string {
+func (_m *UserAddress) String() string {
 	var builder strings.Builder
 	builder.WriteString("UserAddress(")
-	builder.WriteString(fmt.Sprintf("id=%v", ua.ID))
+	builder.WriteString(fmt.Sprintf("id=%v", _m.ID))
 	builder.WriteByte(')')
 	return builder.String()
 }
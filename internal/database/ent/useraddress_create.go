@@ -19,18 +19,18 @@ type UserAddressCreate struct {
 }
 
 // Mutation returns the UserAddressMutation object of the builder.
-func (uac *UserAddressCreate) Mutation() *UserAddressMutation {
-	return uac.mutation
+func (_c *UserAddressCreate) Mutation() *UserAddressMutation {
+	return _c.mutation
 }
 
 // Save creates the UserAddress in the database.
-func (uac *UserAddressCreate) Save(ctx context.Context) (*UserAddress, error) {
-	return withHooks(ctx, uac.sqlSave, uac.mutation, uac.hooks)
+func (_c *UserAddressCreate) Save(ctx context.Context) (*UserAddress, error) {
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
 }
 
 // SaveX calls Save and panics if Save returns an error.
-func (uac *UserAddressCreate) SaveX(ctx context.Context) *UserAddress {
-	v, err := uac.Save(ctx)
+func (_c *UserAddressCreate) SaveX(ctx context.Context) *UserAddress {
+	v, err := _c.Save(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -38,29 +38,29 @@ func (uac *UserAddressCreate) SaveX(ctx context.Context) *UserAddress {
 }
 
 // Exec executes the query.
-func (uac *UserAddressCreate) Exec(ctx context.Context) error {
-	_, err := uac.Save(ctx)
+func (_c *UserAddressCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
 	return err
 }
 
 // ExecX is like Exec, but panics if an error occurs.
-func (uac *UserAddressCreate) ExecX(ctx context.Context) {
-	if err := uac.Exec(ctx); err != nil {
+func (_c *UserAddressCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
 		panic(err)
 	}
 }
 
 // check runs all checks and user-defined validators on the builder.
-func (uac *UserAddressCreate) check() error {
+func (_c *UserAddressCreate) check() error {
 	return nil
 }
 
-func (uac *UserAddressCreate) sqlSave(ctx context.Context) (*UserAddress, error) {
-	if err := uac.check(); err != nil {
+func (_c *UserAddressCreate) sqlSave(ctx context.Context) (*UserAddress, error) {
+	if err := _c.check(); err != nil {
 		return nil, err
 	}
-	_node, _spec := uac.createSpec()
-	if err := sqlgraph.CreateNode(ctx, uac.driver, _spec); err != nil {
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
 		if sqlgraph.IsConstraintError(err) {
 			err = &ConstraintError{msg: err.Error(), wrap: err}
 		}
@@ -68,14 +68,14 @@ func (uac *UserAddressCreate) sqlSave(ctx context.Context) (*UserAddress, error)
 	}
 	id := _spec.ID.Value.(int64)
 	_node.ID = int(id)
-	uac.mutation.id = &_node.ID
-	uac.mutation.done = true
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
 	return _node, nil
 }
 
-func (uac *UserAddressCreate) createSpec() (*UserAddress, *sqlgraph.CreateSpec) {
+func (_c *UserAddressCreate) createSpec() (*UserAddress, *sqlgraph.CreateSpec) {
 	var (
-		_node = &UserAddress{config: uac.config}
+		_node = &UserAddress{config: _c.config}
 		_spec = sqlgraph.NewCreateSpec(useraddress.Table, sqlgraph.NewFieldSpec(useraddress.FieldID, field.TypeInt))
 	)
 	return _node, _spec
@@ -89,16 +89,16 @@ type UserAddressCreateBulk struct {
 }
 
 // Save creates the UserAddress entities in the database.
-func (uacb *UserAddressCreateBulk) Save(ctx context.Context) ([]*UserAddress, error) {
-	if uacb.err != nil {
-		return nil, uacb.err
+func (_c *UserAddressCreateBulk) Save(ctx context.Context) ([]*UserAddress, error) {
+	if _c.err != nil {
+		return nil, _c.err
 	}
-	specs := make([]*sqlgraph.CreateSpec, len(uacb.builders))
-	nodes := make([]*UserAddress, len(uacb.builders))
-	mutators := make([]Mutator, len(uacb.builders))
-	for i := range uacb.builders {
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*UserAddress, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
 		func(i int, root context.Context) {
-			builder := uacb.builders[i]
+			builder := _c.builders[i]
 			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
 				mutation, ok := m.(*UserAddressMutation)
 				if !ok {
@@ -111,11 +111,11 @@ func (uacb *UserAddressCreateBulk) Save(ctx context.Context) ([]*UserAddress, er
 				var err error
 				nodes[i], specs[i] = builder.createSpec()
 				if i < len(mutators)-1 {
-					_, err = mutators[i+1].Mutate(root, uacb.builders[i+1].mutation)
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
 				} else {
 					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
 					// Invoke the actual operation on the latest mutation in the chain.
-					if err = sqlgraph.BatchCreate(ctx, uacb.driver, spec); err != nil {
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
 						if sqlgraph.IsConstraintError(err) {
 							err = &ConstraintError{msg: err.Error(), wrap: err}
 						}
@@ -139,7 +139,7 @@ func (uacb *UserAddressCreateBulk) Save(ctx context.Context) ([]*UserAddress, er
 		}(i, ctx)
 	}
 	if len(mutators) > 0 {
-		if _, err := mutators[0].Mutate(ctx, uacb.builders[0].mutation); err != nil {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
 			return nil, err
 		}
 	}
@@ -147,8 +147,8 @@ func (uacb *UserAddressCreateBulk) Save(ctx context.Context) ([]*UserAddress, er
 }
 
 // SaveX is like Save, but panics if an error occurs.
-func (uacb *UserAddressCreateBulk) SaveX(ctx context.Context) []*UserAddress {
-	v, err := uacb.Save(ctx)
+func (_c *UserAddressCreateBulk) SaveX(ctx context.Context) []*UserAddress {
+	v, err := _c.Save(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -156,14 +156,14 @@ func (uacb *UserAddressCreateBulk) SaveX(ctx context.Context) []*UserAddress {
 }
 
 // Exec executes the query.
-func (uacb *UserAddressCreateBulk) Exec(ctx context.Context) error {
-	_, err := uacb.Save(ctx)
+func (_c *UserAddressCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
 	return err
 }
 
 // ExecX is like Exec, but panics if an error occurs.
-func (uacb *UserAddressCreateBulk) ExecX(ctx context.Context) {
-	if err := uacb.Exec(ctx); err != nil {
+func (_c *UserAddressCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
 		panic(err)
 	}
 }
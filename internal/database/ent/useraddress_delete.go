@@ -20,56 +20,56 @@ type UserAddressDelete struct {
 }
 
 // Where appends a list predicates to the UserAddressDelete builder.
-func (uad *UserAddressDelete) Where(ps ...predicate.UserAddress) *UserAddressDelete {
-	uad.mutation.Where(ps...)
-	return uad
+func (_d *UserAddressDelete) Where(ps ...predicate.UserAddress) *UserAddressDelete {
+	_d.mutation.Where(ps...)
+	return _d
 }
 
 // Exec executes the deletion query and returns how many vertices were deleted.
-func (uad *UserAddressDelete) Exec(ctx context.Context) (int, error) {
-	return withHooks(ctx, uad.sqlExec, uad.mutation, uad.hooks)
+func (_d *UserAddressDelete) Exec(ctx context.Context) (int, error) {
+	return withHooks(ctx, _d.sqlExec, _d.mutation, _d.hooks)
 }
 
 // ExecX is like Exec, but panics if an error occurs.
-func (uad *UserAddressDelete) ExecX(ctx context.Context) int {
-	n, err := uad.Exec(ctx)
+func (_d *UserAddressDelete) ExecX(ctx context.Context) int {
+	n, err := _d.Exec(ctx)
 	if err != nil {
 		panic(err)
 	}
 	return n
 }
 
-func (uad *UserAddressDelete) sqlExec(ctx context.Context) (int, error) {
+func (_d *UserAddressDelete) sqlExec(ctx context.Context) (int, error) {
 	_spec := sqlgraph.NewDeleteSpec(useraddress.Table, sqlgraph.NewFieldSpec(useraddress.FieldID, field.TypeInt))
-	if ps := uad.mutation.predicates; len(ps) > 0 {
+	if ps := _d.mutation.predicates; len(ps) > 0 {
 		_spec.Predicate = func(selector *sql.Selector) {
 			for i := range ps {
 				ps[i](selector)
 			}
 		}
 	}
-	affected, err := sqlgraph.DeleteNodes(ctx, uad.driver, _spec)
+	affected, err := sqlgraph.DeleteNodes(ctx, _d.driver, _spec)
 	if err != nil && sqlgraph.IsConstraintError(err) {
 		err = &ConstraintError{msg: err.Error(), wrap: err}
 	}
-	uad.mutation.done = true
+	_d.mutation.done = true
 	return affected, err
 }
 
 // UserAddressDeleteOne is the builder for deleting a single UserAddress entity.
 type UserAddressDeleteOne struct {
-	uad *UserAddressDelete
+	_d *UserAddressDelete
 }
 
 // Where appends a list predicates to the UserAddressDelete builder.
-func (uado *UserAddressDeleteOne) Where(ps ...predicate.UserAddress) *UserAddressDeleteOne {
-	uado.uad.mutation.Where(ps...)
-	return uado
+func (_d *UserAddressDeleteOne) Where(ps ...predicate.UserAddress) *UserAddressDeleteOne {
+	_d._d.mutation.Where(ps...)
+	return _d
 }
 
 // Exec executes the deletion query.
-func (uado *UserAddressDeleteOne) Exec(ctx context.Context) error {
-	n, err := uado.uad.Exec(ctx)
+func (_d *UserAddressDeleteOne) Exec(ctx context.Context) error {
+	n, err := _d._d.Exec(ctx)
 	switch {
 	case err != nil:
 		return err
@@ -81,8 +81,8 @@ func (uado *UserAddressDeleteOne) Exec(ctx context.Context) error {
 }
 
 // ExecX is like Exec, but panics if an error occurs.
-func (uado *UserAddressDeleteOne) ExecX(ctx context.Context) {
-	if err := uado.Exec(ctx); err != nil {
+func (_d *UserAddressDeleteOne) ExecX(ctx context.Context) {
+	if err := _d.Exec(ctx); err != nil {
 		panic(err)
 	}
 }
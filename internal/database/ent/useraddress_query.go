@@ -28,40 +28,40 @@ type UserAddressQuery struct {
 }
 
 // Where adds a new predicate for the UserAddressQuery builder.
-func (uaq *UserAddressQuery) Where(ps ...predicate.UserAddress) *UserAddressQuery {
-	uaq.predicates = append(uaq.predicates, ps...)
-	return uaq
+func (_q *UserAddressQuery) Where(ps ...predicate.UserAddress) *UserAddressQuery {
+	_q.predicates = append(_q.predicates, ps...)
+	return _q
 }
 
 // Limit the number of records to be returned by this query.
-func (uaq *UserAddressQuery) Limit(limit int) *UserAddressQuery {
-	uaq.ctx.Limit = &limit
-	return uaq
+func (_q *UserAddressQuery) Limit(limit int) *UserAddressQuery {
+	_q.ctx.Limit = &limit
+	return _q
 }
 
 // Offset to start from.
-func (uaq *UserAddressQuery) Offset(offset int) *UserAddressQuery {
-	uaq.ctx.Offset = &offset
-	return uaq
+func (_q *UserAddressQuery) Offset(offset int) *UserAddressQuery {
+	_q.ctx.Offset = &offset
+	return _q
 }
 
 // Unique configures the query builder to filter duplicate records on query.
 // By default, unique is set to true, and can be disabled using this method.
-func (uaq *UserAddressQuery) Unique(unique bool) *UserAddressQuery {
-	uaq.ctx.Unique = &unique
-	return uaq
+func (_q *UserAddressQuery) Unique(unique bool) *UserAddressQuery {
+	_q.ctx.Unique = &unique
+	return _q
 }
 
 // Order specifies how the records should be ordered.
-func (uaq *UserAddressQuery) Order(o ...useraddress.OrderOption) *UserAddressQuery {
-	uaq.order = append(uaq.order, o...)
-	return uaq
+func (_q *UserAddressQuery) Order(o ...useraddress.OrderOption) *UserAddressQuery {
+	_q.order = append(_q.order, o...)
+	return _q
 }
 
 // First returns the first UserAddress entity from the query.
 // Returns a *NotFoundError when no UserAddress was found.
-func (uaq *UserAddressQuery) First(ctx context.Context) (*UserAddress, error) {
-	nodes, err := uaq.Limit(1).All(setContextOp(ctx, uaq.ctx, ent.OpQueryFirst))
+func (_q *UserAddressQuery) First(ctx context.Context) (*UserAddress, error) {
+	nodes, err := _q.Limit(1).All(setContextOp(ctx, _q.ctx, ent.OpQueryFirst))
 	if err != nil {
 		return nil, err
 	}
@@ -72,8 +72,8 @@ func (uaq *UserAddressQuery) First(ctx context.Context) (*UserAddress, error) {
 }
 
 // FirstX is like First, but panics if an error occurs.
-func (uaq *UserAddressQuery) FirstX(ctx context.Context) *UserAddress {
-	node, err := uaq.First(ctx)
+func (_q *UserAddressQuery) FirstX(ctx context.Context) *UserAddress {
+	node, err := _q.First(ctx)
 	if err != nil && !IsNotFound(err) {
 		panic(err)
 	}
@@ -82,9 +82,9 @@ func (uaq *UserAddressQuery) FirstX(ctx context.Context) *UserAddress {
 
 // FirstID returns the first UserAddress ID from the query.
 // Returns a *NotFoundError when no UserAddress ID was found.
-func (uaq *UserAddressQuery) FirstID(ctx context.Context) (id int, err error) {
+func (_q *UserAddressQuery) FirstID(ctx context.Context) (id int, err error) {
 	var ids []int
-	if ids, err = uaq.Limit(1).IDs(setContextOp(ctx, uaq.ctx, ent.OpQueryFirstID)); err != nil {
+	if ids, err = _q.Limit(1).IDs(setContextOp(ctx, _q.ctx, ent.OpQueryFirstID)); err != nil {
 		return
 	}
 	if len(ids) == 0 {
@@ -95,8 +95,8 @@ func (uaq *UserAddressQuery) FirstID(ctx context.Context) (id int, err error) {
 }
 
 // FirstIDX is like FirstID, but panics if an error occurs.
-func (uaq *UserAddressQuery) FirstIDX(ctx context.Context) int {
-	id, err := uaq.FirstID(ctx)
+func (_q *UserAddressQuery) FirstIDX(ctx context.Context) int {
+	id, err := _q.FirstID(ctx)
 	if err != nil && !IsNotFound(err) {
 		panic(err)
 	}
@@ -106,8 +106,8 @@ func (uaq *UserAddressQuery) FirstIDX(ctx context.Context) int {
 // Only returns a single UserAddress entity found by the query, ensuring it only returns one.
 // Returns a *NotSingularError when more than one UserAddress entity is found.
 // Returns a *NotFoundError when no UserAddress entities are found.
-func (uaq *UserAddressQuery) Only(ctx context.Context) (*UserAddress, error) {
-	nodes, err := uaq.Limit(2).All(setContextOp(ctx, uaq.ctx, ent.OpQueryOnly))
+func (_q *UserAddressQuery) Only(ctx context.Context) (*UserAddress, error) {
+	nodes, err := _q.Limit(2).All(setContextOp(ctx, _q.ctx, ent.OpQueryOnly))
 	if err != nil {
 		return nil, err
 	}
@@ -122,8 +122,8 @@ func (uaq *UserAddressQuery) Only(ctx context.Context) (*UserAddress, error) {
 }
 
 // OnlyX is like Only, but panics if an error occurs.
-func (uaq *UserAddressQuery) OnlyX(ctx context.Context) *UserAddress {
-	node, err := uaq.Only(ctx)
+func (_q *UserAddressQuery) OnlyX(ctx context.Context) *UserAddress {
+	node, err := _q.Only(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -133,9 +133,9 @@ func (uaq *UserAddressQuery) OnlyX(ctx context.Context) *UserAddress {
 // OnlyID is like Only, but returns the only UserAddress ID in the query.
 // Returns a *NotSingularError when more than one UserAddress ID is found.
 // Returns a *NotFoundError when no entities are found.
-func (uaq *UserAddressQuery) OnlyID(ctx context.Context) (id int, err error) {
+func (_q *UserAddressQuery) OnlyID(ctx context.Context) (id int, err error) {
 	var ids []int
-	if ids, err = uaq.Limit(2).IDs(setContextOp(ctx, uaq.ctx, ent.OpQueryOnlyID)); err != nil {
+	if ids, err = _q.Limit(2).IDs(setContextOp(ctx, _q.ctx, ent.OpQueryOnlyID)); err != nil {
 		return
 	}
 	switch len(ids) {
@@ -150,8 +150,8 @@ func (uaq *UserAddressQuery) OnlyID(ctx context.Context) (id int, err error) {
 }
 
 // OnlyIDX is like OnlyID, but panics if an error occurs.
-func (uaq *UserAddressQuery) OnlyIDX(ctx context.Context) int {
-	id, err := uaq.OnlyID(ctx)
+func (_q *UserAddressQuery) OnlyIDX(ctx context.Context) int {
+	id, err := _q.OnlyID(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -159,18 +159,18 @@ func (uaq *UserAddressQuery) OnlyIDX(ctx context.Context) int {
 }
 
 // All executes the query and returns a list of UserAddresses.
-func (uaq *UserAddressQuery) All(ctx context.Context) ([]*UserAddress, error) {
-	ctx = setContextOp(ctx, uaq.ctx, ent.OpQueryAll)
-	if err := uaq.prepareQuery(ctx); err != nil {
+func (_q *UserAddressQuery) All(ctx context.Context) ([]*UserAddress, error) {
+	ctx = setContextOp(ctx, _q.ctx, ent.OpQueryAll)
+	if err := _q.prepareQuery(ctx); err != nil {
 		return nil, err
 	}
 	qr := querierAll[[]*UserAddress, *UserAddressQuery]()
-	return withInterceptors[[]*UserAddress](ctx, uaq, qr, uaq.inters)
+	return withInterceptors[[]*UserAddress](ctx, _q, qr, _q.inters)
 }
 
 // AllX is like All, but panics if an error occurs.
-func (uaq *UserAddressQuery) AllX(ctx context.Context) []*UserAddress {
-	nodes, err := uaq.All(ctx)
+func (_q *UserAddressQuery) AllX(ctx context.Context) []*UserAddress {
+	nodes, err := _q.All(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -178,20 +178,20 @@ func (uaq *UserAddressQuery) AllX(ctx context.Context) []*UserAddress {
 }
 
 // IDs executes the query and returns a list of UserAddress IDs.
-func (uaq *UserAddressQuery) IDs(ctx context.Context) (ids []int, err error) {
-	if uaq.ctx.Unique == nil && uaq.path != nil {
-		uaq.Unique(true)
+func (_q *UserAddressQuery) IDs(ctx context.Context) (ids []int, err error) {
+	if _q.ctx.Unique == nil && _q.path != nil {
+		_q.Unique(true)
 	}
-	ctx = setContextOp(ctx, uaq.ctx, ent.OpQueryIDs)
-	if err = uaq.Select(useraddress.FieldID).Scan(ctx, &ids); err != nil {
+	ctx = setContextOp(ctx, _q.ctx, ent.OpQueryIDs)
+	if err = _q.Select(useraddress.FieldID).Scan(ctx, &ids); err != nil {
 		return nil, err
 	}
 	return ids, nil
 }
 
 // IDsX is like IDs, but panics if an error occurs.
-func (uaq *UserAddressQuery) IDsX(ctx context.Context) []int {
-	ids, err := uaq.IDs(ctx)
+func (_q *UserAddressQuery) IDsX(ctx context.Context) []int {
+	ids, err := _q.IDs(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -199,17 +199,17 @@ func (uaq *UserAddressQuery) IDsX(ctx context.Context) []int {
 }
 
 // Count returns the count of the given query.
-func (uaq *UserAddressQuery) Count(ctx context.Context) (int, error) {
-	ctx = setContextOp(ctx, uaq.ctx, ent.OpQueryCount)
-	if err := uaq.prepareQuery(ctx); err != nil {
+func (_q *UserAddressQuery) Count(ctx context.Context) (int, error) {
+	ctx = setContextOp(ctx, _q.ctx, ent.OpQueryCount)
+	if err := _q.prepareQuery(ctx); err != nil {
 		return 0, err
 	}
-	return withInterceptors[int](ctx, uaq, querierCount[*UserAddressQuery](), uaq.inters)
+	return withInterceptors[int](ctx, _q, querierCount[*UserAddressQuery](), _q.inters)
 }
 
 // CountX is like Count, but panics if an error occurs.
-func (uaq *UserAddressQuery) CountX(ctx context.Context) int {
-	count, err := uaq.Count(ctx)
+func (_q *UserAddressQuery) CountX(ctx context.Context) int {
+	count, err := _q.Count(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -217,9 +217,9 @@ func (uaq *UserAddressQuery) CountX(ctx context.Context) int {
 }
 
 // Exist returns true if the query has elements in the graph.
-func (uaq *UserAddressQuery) Exist(ctx context.Context) (bool, error) {
-	ctx = setContextOp(ctx, uaq.ctx, ent.OpQueryExist)
-	switch _, err := uaq.FirstID(ctx); {
+func (_q *UserAddressQuery) Exist(ctx context.Context) (bool, error) {
+	ctx = setContextOp(ctx, _q.ctx, ent.OpQueryExist)
+	switch _, err := _q.FirstID(ctx); {
 	case IsNotFound(err):
 		return false, nil
 	case err != nil:
@@ -230,8 +230,8 @@ func (uaq *UserAddressQuery) Exist(ctx context.Context) (bool, error) {
 }
 
 // ExistX is like Exist, but panics if an error occurs.
-func (uaq *UserAddressQuery) ExistX(ctx context.Context) bool {
-	exist, err := uaq.Exist(ctx)
+func (_q *UserAddressQuery) ExistX(ctx context.Context) bool {
+	exist, err := _q.Exist(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -240,28 +240,28 @@ func (uaq *UserAddressQuery) ExistX(ctx context.Context) bool {
 
 // Clone returns a duplicate of the UserAddressQuery builder, including all associated steps. It can be
 // used to prepare common query builders and use them differently after the clone is made.
-func (uaq *UserAddressQuery) Clone() *UserAddressQuery {
-	if uaq == nil {
+func (_q *UserAddressQuery) Clone() *UserAddressQuery {
+	if _q == nil {
 		return nil
 	}
 	return &UserAddressQuery{
-		config:     uaq.config,
-		ctx:        uaq.ctx.Clone(),
-		order:      append([]useraddress.OrderOption{}, uaq.order...),
-		inters:     append([]Interceptor{}, uaq.inters...),
-		predicates: append([]predicate.UserAddress{}, uaq.predicates...),
+		config:     _q.config,
+		ctx:        _q.ctx.Clone(),
+		order:      append([]useraddress.OrderOption{}, _q.order...),
+		inters:     append([]Interceptor{}, _q.inters...),
+		predicates: append([]predicate.UserAddress{}, _q.predicates...),
 		// clone intermediate query.
-		sql:  uaq.sql.Clone(),
-		path: uaq.path,
+		sql:  _q.sql.Clone(),
+		path: _q.path,
 	}
 }
 
 // GroupBy is used to group vertices by one or more fields/columns.
 // It is often used with aggregate functions, like: count, max, mean, min, sum.
-func (uaq *UserAddressQuery) GroupBy(field string, fields ...string) *UserAddressGroupBy {
-	uaq.ctx.Fields = append([]string{field}, fields...)
-	grbuild := &UserAddressGroupBy{build: uaq}
-	grbuild.flds = &uaq.ctx.Fields
+func (_q *UserAddressQuery) GroupBy(field string, fields ...string) *UserAddressGroupBy {
+	_q.ctx.Fields = append([]string{field}, fields...)
+	grbuild := &UserAddressGroupBy{build: _q}
+	grbuild.flds = &_q.ctx.Fields
 	grbuild.label = useraddress.Label
 	grbuild.scan = grbuild.Scan
 	return grbuild
@@ -269,62 +269,62 @@ func (uaq *UserAddressQuery) GroupBy(field string, fields ...string) *UserAddres
 
 // Select allows the selection one or more fields/columns for the given query,
 // instead of selecting all fields in the entity.
-func (uaq *UserAddressQuery) Select(fields ...string) *UserAddressSelect {
-	uaq.ctx.Fields = append(uaq.ctx.Fields, fields...)
-	sbuild := &UserAddressSelect{UserAddressQuery: uaq}
+func (_q *UserAddressQuery) Select(fields ...string) *UserAddressSelect {
+	_q.ctx.Fields = append(_q.ctx.Fields, fields...)
+	sbuild := &UserAddressSelect{UserAddressQuery: _q}
 	sbuild.label = useraddress.Label
-	sbuild.flds, sbuild.scan = &uaq.ctx.Fields, sbuild.Scan
+	sbuild.flds, sbuild.scan = &_q.ctx.Fields, sbuild.Scan
 	return sbuild
 }
 
 // Aggregate returns a UserAddressSelect configured with the given aggregations.
-func (uaq *UserAddressQuery) Aggregate(fns ...AggregateFunc) *UserAddressSelect {
-	return uaq.Select().Aggregate(fns...)
+func (_q *UserAddressQuery) Aggregate(fns ...AggregateFunc) *UserAddressSelect {
+	return _q.Select().Aggregate(fns...)
 }
 
-func (uaq *UserAddressQuery) prepareQuery(ctx context.Context) error {
-	for _, inter := range uaq.inters {
+func (_q *UserAddressQuery) prepareQuery(ctx context.Context) error {
+	for _, inter := range _q.inters {
 		if inter == nil {
 			return fmt.Errorf("ent: uninitialized interceptor (forgotten import ent/runtime?)")
 		}
 		if trv, ok := inter.(Traverser); ok {
-			if err := trv.Traverse(ctx, uaq); err != nil {
+			if err := trv.Traverse(ctx, _q); err != nil {
 				return err
 			}
 		}
 	}
-	for _, f := range uaq.ctx.Fields {
+	for _, f := range _q.ctx.Fields {
 		if !useraddress.ValidColumn(f) {
 			return &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
 		}
 	}
-	if uaq.path != nil {
-		prev, err := uaq.path(ctx)
+	if _q.path != nil {
+		prev, err := _q.path(ctx)
 		if err != nil {
 			return err
 		}
-		uaq.sql = prev
+		_q.sql = prev
 	}
 	return nil
 }
 
-func (uaq *UserAddressQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*UserAddress, error) {
+func (_q *UserAddressQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*UserAddress, error) {
 	var (
 		nodes = []*UserAddress{}
-		_spec = uaq.querySpec()
+		_spec = _q.querySpec()
 	)
 	_spec.ScanValues = func(columns []string) ([]any, error) {
 		return (*UserAddress).scanValues(nil, columns)
 	}
 	_spec.Assign = func(columns []string, values []any) error {
-		node := &UserAddress{config: uaq.config}
+		node := &UserAddress{config: _q.config}
 		nodes = append(nodes, node)
 		return node.assignValues(columns, values)
 	}
 	for i := range hooks {
 		hooks[i](ctx, _spec)
 	}
-	if err := sqlgraph.QueryNodes(ctx, uaq.driver, _spec); err != nil {
+	if err := sqlgraph.QueryNodes(ctx, _q.driver, _spec); err != nil {
 		return nil, err
 	}
 	if len(nodes) == 0 {
@@ -333,24 +333,24 @@ func (uaq *UserAddressQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]
 	return nodes, nil
 }
 
-func (uaq *UserAddressQuery) sqlCount(ctx context.Context) (int, error) {
-	_spec := uaq.querySpec()
-	_spec.Node.Columns = uaq.ctx.Fields
-	if len(uaq.ctx.Fields) > 0 {
-		_spec.Unique = uaq.ctx.Unique != nil && *uaq.ctx.Unique
+func (_q *UserAddressQuery) sqlCount(ctx context.Context) (int, error) {
+	_spec := _q.querySpec()
+	_spec.Node.Columns = _q.ctx.Fields
+	if len(_q.ctx.Fields) > 0 {
+		_spec.Unique = _q.ctx.Unique != nil && *_q.ctx.Unique
 	}
-	return sqlgraph.CountNodes(ctx, uaq.driver, _spec)
+	return sqlgraph.CountNodes(ctx, _q.driver, _spec)
 }
 
-func (uaq *UserAddressQuery) querySpec() *sqlgraph.QuerySpec {
+func (_q *UserAddressQuery) querySpec() *sqlgraph.QuerySpec {
 	_spec := sqlgraph.NewQuerySpec(useraddress.Table, useraddress.Columns, sqlgraph.NewFieldSpec(useraddress.FieldID, field.TypeInt))
-	_spec.From = uaq.sql
-	if unique := uaq.ctx.Unique; unique != nil {
+	_spec.From = _q.sql
+	if unique := _q.ctx.Unique; unique != nil {
 		_spec.Unique = *unique
-	} else if uaq.path != nil {
+	} else if _q.path != nil {
 		_spec.Unique = true
 	}
-	if fields := uaq.ctx.Fields; len(fields) > 0 {
+	if fields := _q.ctx.Fields; len(fields) > 0 {
 		_spec.Node.Columns = make([]string, 0, len(fields))
 		_spec.Node.Columns = append(_spec.Node.Columns, useraddress.FieldID)
 		for i := range fields {
@@ -359,20 +359,20 @@ func (uaq *UserAddressQuery) querySpec() *sqlgraph.QuerySpec {
 			}
 		}
 	}
-	if ps := uaq.predicates; len(ps) > 0 {
+	if ps := _q.predicates; len(ps) > 0 {
 		_spec.Predicate = func(selector *sql.Selector) {
 			for i := range ps {
 				ps[i](selector)
 			}
 		}
 	}
-	if limit := uaq.ctx.Limit; limit != nil {
+	if limit := _q.ctx.Limit; limit != nil {
 		_spec.Limit = *limit
 	}
-	if offset := uaq.ctx.Offset; offset != nil {
+	if offset := _q.ctx.Offset; offset != nil {
 		_spec.Offset = *offset
 	}
-	if ps := uaq.order; len(ps) > 0 {
+	if ps := _q.order; len(ps) > 0 {
 		_spec.Order = func(selector *sql.Selector) {
 			for i := range ps {
 				ps[i](selector)
@@ -382,33 +382,33 @@ func (uaq *UserAddressQuery) querySpec() *sqlgraph.QuerySpec {
 	return _spec
 }
 
-func (uaq *UserAddressQuery) sqlQuery(ctx context.Context) *sql.Selector {
-	builder := sql.Dialect(uaq.driver.Dialect())
+func (_q *UserAddressQuery) sqlQuery(ctx context.Context) *sql.Selector {
+	builder := sql.Dialect(_q.driver.Dialect())
 	t1 := builder.Table(useraddress.Table)
-	columns := uaq.ctx.Fields
+	columns := _q.ctx.Fields
 	if len(columns) == 0 {
 		columns = useraddress.Columns
 	}
 	selector := builder.Select(t1.Columns(columns...)...).From(t1)
-	if uaq.sql != nil {
-		selector = uaq.sql
+	if _q.sql != nil {
+		selector = _q.sql
 		selector.Select(selector.Columns(columns...)...)
 	}
-	if uaq.ctx.Unique != nil && *uaq.ctx.Unique {
+	if _q.ctx.Unique != nil && *_q.ctx.Unique {
 		selector.Distinct()
 	}
-	for _, p := range uaq.predicates {
+	for _, p := range _q.predicates {
 		p(selector)
 	}
-	for _, p := range uaq.order {
+	for _, p := range _q.order {
 		p(selector)
 	}
-	if offset := uaq.ctx.Offset; offset != nil {
+	if offset := _q.ctx.Offset; offset != nil {
 		// limit is mandatory for offset clause. We start
 		// with default value, and override it below if needed.
 		selector.Offset(*offset).Limit(math.MaxInt32)
 	}
-	if limit := uaq.ctx.Limit; limit != nil {
+	if limit := _q.ctx.Limit; limit != nil {
 		selector.Limit(*limit)
 	}
 	return selector
@@ -421,41 +421,41 @@ type UserAddressGroupBy struct {
 }
 
 // Aggregate adds the given aggregation functions to the group-by query.
-func (uagb *UserAddressGroupBy) Aggregate(fns ...AggregateFunc) *UserAddressGroupBy {
-	uagb.fns = append(uagb.fns, fns...)
-	return uagb
+func (_g *UserAddressGroupBy) Aggregate(fns ...AggregateFunc) *UserAddressGroupBy {
+	_g.fns = append(_g.fns, fns...)
+	return _g
 }
 
 // Scan applies the selector query and scans the result into the given value.
-func (uagb *UserAddressGroupBy) Scan(ctx context.Context, v any) error {
-	ctx = setContextOp(ctx, uagb.build.ctx, ent.OpQueryGroupBy)
-	if err := uagb.build.prepareQuery(ctx); err != nil {
+func (_g *UserAddressGroupBy) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, _g.build.ctx, ent.OpQueryGroupBy)
+	if err := _g.build.prepareQuery(ctx); err != nil {
 		return err
 	}
-	return scanWithInterceptors[*UserAddressQuery, *UserAddressGroupBy](ctx, uagb.build, uagb, uagb.build.inters, v)
+	return scanWithInterceptors[*UserAddressQuery, *UserAddressGroupBy](ctx, _g.build, _g, _g.build.inters, v)
 }
 
-func (uagb *UserAddressGroupBy) sqlScan(ctx context.Context, root *UserAddressQuery, v any) error {
+func (_g *UserAddressGroupBy) sqlScan(ctx context.Context, root *UserAddressQuery, v any) error {
 	selector := root.sqlQuery(ctx).Select()
-	aggregation := make([]string, 0, len(uagb.fns))
-	for _, fn := range uagb.fns {
+	aggregation := make([]string, 0, len(_g.fns))
+	for _, fn := range _g.fns {
 		aggregation = append(aggregation, fn(selector))
 	}
 	if len(selector.SelectedColumns()) == 0 {
-		columns := make([]string, 0, len(*uagb.flds)+len(uagb.fns))
-		for _, f := range *uagb.flds {
+		columns := make([]string, 0, len(*_g.flds)+len(_g.fns))
+		for _, f := range *_g.flds {
 			columns = append(columns, selector.C(f))
 		}
 		columns = append(columns, aggregation...)
 		selector.Select(columns...)
 	}
-	selector.GroupBy(selector.Columns(*uagb.flds...)...)
+	selector.GroupBy(selector.Columns(*_g.flds...)...)
 	if err := selector.Err(); err != nil {
 		return err
 	}
 	rows := &sql.Rows{}
 	query, args := selector.Query()
-	if err := uagb.build.driver.Query(ctx, query, args, rows); err != nil {
+	if err := _g.build.driver.Query(ctx, query, args, rows); err != nil {
 		return err
 	}
 	defer rows.Close()
@@ -469,27 +469,27 @@ type UserAddressSelect struct {
 }
 
 // Aggregate adds the given aggregation functions to the selector query.
-func (uas *UserAddressSelect) Aggregate(fns ...AggregateFunc) *UserAddressSelect {
-	uas.fns = append(uas.fns, fns...)
-	return uas
+func (_s *UserAddressSelect) Aggregate(fns ...AggregateFunc) *UserAddressSelect {
+	_s.fns = append(_s.fns, fns...)
+	return _s
 }
 
 // Scan applies the selector query and scans the result into the given value.
-func (uas *UserAddressSelect) Scan(ctx context.Context, v any) error {
-	ctx = setContextOp(ctx, uas.ctx, ent.OpQuerySelect)
-	if err := uas.prepareQuery(ctx); err != nil {
+func (_s *UserAddressSelect) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, _s.ctx, ent.OpQuerySelect)
+	if err := _s.prepareQuery(ctx); err != nil {
 		return err
 	}
-	return scanWithInterceptors[*UserAddressQuery, *UserAddressSelect](ctx, uas.UserAddressQuery, uas, uas.inters, v)
+	return scanWithInterceptors[*UserAddressQuery, *UserAddressSelect](ctx, _s.UserAddressQuery, _s, _s.inters, v)
 }
 
-func (uas *UserAddressSelect) sqlScan(ctx context.Context, root *UserAddressQuery, v any) error {
+func (_s *UserAddressSelect) sqlScan(ctx context.Context, root *UserAddressQuery, v any) error {
 	selector := root.sqlQuery(ctx)
-	aggregation := make([]string, 0, len(uas.fns))
-	for _, fn := range uas.fns {
+	aggregation := make([]string, 0, len(_s.fns))
+	for _, fn := range _s.fns {
 		aggregation = append(aggregation, fn(selector))
 	}
-	switch n := len(*uas.selector.flds); {
+	switch n := len(*_s.selector.flds); {
 	case n == 0 && len(aggregation) > 0:
 		selector.Select(aggregation...)
 	case n != 0 && len(aggregation) > 0:
@@ -497,7 +497,7 @@ func (uas *UserAddressSelect) sqlScan(ctx context.Context, root *UserAddressQuer
 	}
 	rows := &sql.Rows{}
 	query, args := selector.Query()
-	if err := uas.driver.Query(ctx, query, args, rows); err != nil {
+	if err := _s.driver.Query(ctx, query, args, rows); err != nil {
 		return err
 	}
 	defer rows.Close()
@@ -22,24 +22,24 @@ type UserAddressUpdate struct {
 }
 
 // Where appends a list predicates to the UserAddressUpdate builder.
-func (uau *UserAddressUpdate) Where(ps ...predicate.UserAddress) *UserAddressUpdate {
-	uau.mutation.Where(ps...)
-	return uau
+func (_u *UserAddressUpdate) Where(ps ...predicate.UserAddress) *UserAddressUpdate {
+	_u.mutation.Where(ps...)
+	return _u
 }
 
 // Mutation returns the UserAddressMutation object of the builder.
-func (uau *UserAddressUpdate) Mutation() *UserAddressMutation {
-	return uau.mutation
+func (_u *UserAddressUpdate) Mutation() *UserAddressMutation {
+	return _u.mutation
 }
 
 // Save executes the query and returns the number of nodes affected by the update operation.
-func (uau *UserAddressUpdate) Save(ctx context.Context) (int, error) {
-	return withHooks(ctx, uau.sqlSave, uau.mutation, uau.hooks)
+func (_u *UserAddressUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
 }
 
 // SaveX is like Save, but panics if an error occurs.
-func (uau *UserAddressUpdate) SaveX(ctx context.Context) int {
-	affected, err := uau.Save(ctx)
+func (_u *UserAddressUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -47,28 +47,28 @@ func (uau *UserAddressUpdate) SaveX(ctx context.Context) int {
 }
 
 // Exec executes the query.
-func (uau *UserAddressUpdate) Exec(ctx context.Context) error {
-	_, err := uau.Save(ctx)
+func (_u *UserAddressUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
 	return err
 }
 
 // ExecX is like Exec, but panics if an error occurs.
-func (uau *UserAddressUpdate) ExecX(ctx context.Context) {
-	if err := uau.Exec(ctx); err != nil {
+func (_u *UserAddressUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
 		panic(err)
 	}
 }
 
-func (uau *UserAddressUpdate) sqlSave(ctx context.Context) (n int, err error) {
+func (_u *UserAddressUpdate) sqlSave(ctx context.Context) (_node int, err error) {
 	_spec := sqlgraph.NewUpdateSpec(useraddress.Table, useraddress.Columns, sqlgraph.NewFieldSpec(useraddress.FieldID, field.TypeInt))
-	if ps := uau.mutation.predicates; len(ps) > 0 {
+	if ps := _u.mutation.predicates; len(ps) > 0 {
 		_spec.Predicate = func(selector *sql.Selector) {
 			for i := range ps {
 				ps[i](selector)
 			}
 		}
 	}
-	if n, err = sqlgraph.UpdateNodes(ctx, uau.driver, _spec); err != nil {
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
 		if _, ok := err.(*sqlgraph.NotFoundError); ok {
 			err = &NotFoundError{useraddress.Label}
 		} else if sqlgraph.IsConstraintError(err) {
@@ -76,8 +76,8 @@ func (uau *UserAddressUpdate) sqlSave(ctx context.Context) (n int, err error) {
 		}
 		return 0, err
 	}
-	uau.mutation.done = true
-	return n, nil
+	_u.mutation.done = true
+	return _node, nil
 }
 
 // UserAddressUpdateOne is the builder for updating a single UserAddress entity.
@@ -89,31 +89,31 @@ type UserAddressUpdateOne struct {
 }
 
 // Mutation returns the UserAddressMutation object of the builder.
-func (uauo *UserAddressUpdateOne) Mutation() *UserAddressMutation {
-	return uauo.mutation
+func (_u *UserAddressUpdateOne) Mutation() *UserAddressMutation {
+	return _u.mutation
 }
 
 // Where appends a list predicates to the UserAddressUpdate builder.
-func (uauo *UserAddressUpdateOne) Where(ps ...predicate.UserAddress) *UserAddressUpdateOne {
-	uauo.mutation.Where(ps...)
-	return uauo
+func (_u *UserAddressUpdateOne) Where(ps ...predicate.UserAddress) *UserAddressUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
 }
 
 // Select allows selecting one or more fields (columns) of the returned entity.
 // The default is selecting all fields defined in the entity schema.
-func (uauo *UserAddressUpdateOne) Select(field string, fields ...string) *UserAddressUpdateOne {
-	uauo.fields = append([]string{field}, fields...)
-	return uauo
+func (_u *UserAddressUpdateOne) Select(field string, fields ...string) *UserAddressUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
 }
 
 // Save executes the query and returns the updated UserAddress entity.
-func (uauo *UserAddressUpdateOne) Save(ctx context.Context) (*UserAddress, error) {
-	return withHooks(ctx, uauo.sqlSave, uauo.mutation, uauo.hooks)
+func (_u *UserAddressUpdateOne) Save(ctx context.Context) (*UserAddress, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
 }
 
 // SaveX is like Save, but panics if an error occurs.
-func (uauo *UserAddressUpdateOne) SaveX(ctx context.Context) *UserAddress {
-	node, err := uauo.Save(ctx)
+func (_u *UserAddressUpdateOne) SaveX(ctx context.Context) *UserAddress {
+	node, err := _u.Save(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -121,26 +121,26 @@ func (uauo *UserAddressUpdateOne) SaveX(ctx context.Context) *UserAddress {
 }
 
 // Exec executes the query on the entity.
-func (uauo *UserAddressUpdateOne) Exec(ctx context.Context) error {
-	_, err := uauo.Save(ctx)
+func (_u *UserAddressUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
 	return err
 }
 
 // ExecX is like Exec, but panics if an error occurs.
-func (uauo *UserAddressUpdateOne) ExecX(ctx context.Context) {
-	if err := uauo.Exec(ctx); err != nil {
+func (_u *UserAddressUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
 		panic(err)
 	}
 }
 
-func (uauo *UserAddressUpdateOne) sqlSave(ctx context.Context) (_node *UserAddress, err error) {
+func (_u *UserAddressUpdateOne) sqlSave(ctx context.Context) (_node *UserAddress, err error) {
 	_spec := sqlgraph.NewUpdateSpec(useraddress.Table, useraddress.Columns, sqlgraph.NewFieldSpec(useraddress.FieldID, field.TypeInt))
-	id, ok := uauo.mutation.ID()
+	id, ok := _u.mutation.ID()
 	if !ok {
 		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "UserAddress.id" for update`)}
 	}
 	_spec.Node.ID.Value = id
-	if fields := uauo.fields; len(fields) > 0 {
+	if fields := _u.fields; len(fields) > 0 {
 		_spec.Node.Columns = make([]string, 0, len(fields))
 		_spec.Node.Columns = append(_spec.Node.Columns, useraddress.FieldID)
 		for _, f := range fields {
@@ -152,17 +152,17 @@ func (uauo *UserAddressUpdateOne) sqlSave(ctx context.Context) (_node *UserAddre
 			}
 		}
 	}
-	if ps := uauo.mutation.predicates; len(ps) > 0 {
+	if ps := _u.mutation.predicates; len(ps) > 0 {
 		_spec.Predicate = func(selector *sql.Selector) {
 			for i := range ps {
 				ps[i](selector)
 			}
 		}
 	}
-	_node = &UserAddress{config: uauo.config}
+	_node = &UserAddress{config: _u.config}
 	_spec.Assign = _node.assignValues
 	_spec.ScanValues = _node.scanValues
-	if err = sqlgraph.UpdateNode(ctx, uauo.driver, _spec); err != nil {
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
 		if _, ok := err.(*sqlgraph.NotFoundError); ok {
 			err = &NotFoundError{useraddress.Label}
 		} else if sqlgraph.IsConstraintError(err) {
@@ -170,6 +170,6 @@ func (uauo *UserAddressUpdateOne) sqlSave(ctx context.Context) (_node *UserAddre
 		}
 		return nil, err
 	}
-	uauo.mutation.done = true
+	_u.mutation.done = true
 	return _node, nil
 }
@@ -0,0 +1,280 @@
+// Package migrationguard inspects migration SQL for changes that cannot be
+// rolled out without downtime: dropping columns or tables the running code
+// still reads, renames, and index builds or rebuilds that lock a busy table.
+//
+// A reviewed statement is allowed by a comment right above it:
+//
+//	-- guard:allow locking-index
+//	CREATE INDEX idx_users_created_at ON users(created_at);
+package migrationguard
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"entgo.io/ent/dialect/sql/schema"
+)
+
+const (
+	RuleDropColumn   = "drop-column"
+	RuleDropTable    = "drop-table"
+	RuleRenameColumn = "rename-column"
+	RuleLockingIndex = "locking-index"
+	RuleTableRebuild = "table-rebuild"
+)
+
+// DefaultLockSensitiveTables are the tables every request touches.
+var DefaultLockSensitiveTables = []string{"users"}
+
+// Finding is one unsafe operation in a migration.
+type Finding struct {
+	Rule      string
+	Table     string
+	Statement string
+	Message   string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("[%s] %s", f.Rule, f.Message)
+}
+
+// Options tune the inspection. InUse reports whether the deployed code still
+// reads a column; column is empty for whole tables. Without it every drop is
+// treated as in use.
+type Options struct {
+	LockSensitiveTables []string
+	InUse               func(table, column string) bool
+}
+
+// Error is returned when a migration is blocked.
+type Error struct {
+	Findings []Finding
+}
+
+func (e *Error) Error() string {
+	lines := make([]string, 0, len(e.Findings)+1)
+	lines = append(lines, fmt.Sprintf("%d unsafe migration statement(s)", len(e.Findings)))
+	for _, f := range e.Findings {
+		lines = append(lines, "  "+f.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Check inspects script and returns an *Error listing the findings, or nil
+// when the script is safe.
+func Check(script string, opts Options) error {
+	if findings := Inspect(script, opts); len(findings) > 0 {
+		return &Error{Findings: findings}
+	}
+	return nil
+}
+
+// InUseBySchema reports columns and tables that are still declared by the
+// ent schema, and so still read by the code being deployed.
+func InUseBySchema(tables []*schema.Table) func(table, column string) bool {
+	declared := make(map[string]map[string]bool, len(tables))
+	for _, t := range tables {
+		cols := make(map[string]bool, len(t.Columns))
+		for _, c := range t.Columns {
+			cols[strings.ToLower(c.Name)] = true
+		}
+		declared[strings.ToLower(t.Name)] = cols
+	}
+	return func(table, column string) bool {
+		cols, ok := declared[strings.ToLower(table)]
+		if !ok || column == "" {
+			return ok
+		}
+		return cols[strings.ToLower(column)]
+	}
+}
+
+var (
+	allowRe       = regexp.MustCompile(`(?i)^--\s*guard:allow\s+(.+)$`)
+	alterTableRe  = regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+(\S+)\s+(.*)$`)
+	createIndexRe = regexp.MustCompile(`(?is)^CREATE\s+(?:UNIQUE\s+|FULLTEXT\s+|SPATIAL\s+)?INDEX\s+\S+\s+ON\s+([^\s(]+)`)
+	dropTableRe   = regexp.MustCompile(`(?is)^DROP\s+TABLE\s+(?:IF\s+EXISTS\s+)?(\S+)`)
+	dropColumnRe  = regexp.MustCompile(`(?is)^DROP\s+(?:COLUMN\s+)?(\S+)`)
+	addIndexRe    = regexp.MustCompile(`(?is)^ADD\s+(?:UNIQUE\s+|FULLTEXT\s+|SPATIAL\s+)?(?:INDEX|KEY)\b|^ADD\s+(?:CONSTRAINT\s+\S+\s+)?(?:UNIQUE|PRIMARY\s+KEY)\b`)
+	renameColRe   = regexp.MustCompile(`(?is)^(?:RENAME\s+COLUMN|CHANGE\s+(?:COLUMN\s+)?)\s*(\S+)`)
+	modifyColRe   = regexp.MustCompile(`(?is)^MODIFY\s+(?:COLUMN\s+)?(\S+)`)
+	lockNoneRe    = regexp.MustCompile(`(?i)\bLOCK\s*=\s*NONE\b`)
+	algInstantRe  = regexp.MustCompile(`(?i)\bALGORITHM\s*=\s*INSTANT\b`)
+	notColumnRe   = regexp.MustCompile(`(?i)^(INDEX|KEY|FOREIGN|PRIMARY|CONSTRAINT|CHECK|PARTITION)$`)
+)
+
+// Inspect returns the unsafe operations in script, skipping statements
+// allowed by a guard comment.
+func Inspect(script string, opts Options) []Finding {
+	sensitive := opts.LockSensitiveTables
+	if sensitive == nil {
+		sensitive = DefaultLockSensitiveTables
+	}
+	isSensitive := func(table string) bool {
+		for _, t := range sensitive {
+			if strings.EqualFold(t, table) {
+				return true
+			}
+		}
+		return false
+	}
+	inUse := opts.InUse
+	if inUse == nil {
+		inUse = func(string, string) bool { return true }
+	}
+
+	var findings []Finding
+	for _, stmt := range splitStatements(script) {
+		for _, f := range inspectStatement(stmt.sql, isSensitive, inUse) {
+			if !stmt.allowed[f.Rule] {
+				findings = append(findings, f)
+			}
+		}
+	}
+	return findings
+}
+
+func inspectStatement(stmt string, isSensitive func(string) bool, inUse func(string, string) bool) []Finding {
+	var findings []Finding
+	add := func(rule, table, format string, args ...any) {
+		findings = append(findings, Finding{Rule: rule, Table: table, Statement: stmt, Message: fmt.Sprintf(format, args...)})
+	}
+	online := lockNoneRe.MatchString(stmt)
+
+	if m := dropTableRe.FindStringSubmatch(stmt); m != nil {
+		table := unquote(m[1])
+		if inUse(table, "") {
+			add(RuleDropTable, table, "table %s is still in the schema the code reads; remove it from the code in an earlier deploy", table)
+		}
+		return findings
+	}
+
+	if m := createIndexRe.FindStringSubmatch(stmt); m != nil {
+		table := unquote(m[1])
+		if isSensitive(table) && !online {
+			add(RuleLockingIndex, table, "index build on %s locks writes; add ALGORITHM=INPLACE, LOCK=NONE", table)
+		}
+		return findings
+	}
+
+	m := alterTableRe.FindStringSubmatch(stmt)
+	if m == nil {
+		return nil
+	}
+	table := unquote(m[1])
+	for _, clause := range splitClauses(m[2]) {
+		switch {
+		case addIndexRe.MatchString(clause):
+			if isSensitive(table) && !online {
+				add(RuleLockingIndex, table, "index build on %s locks writes; add ALGORITHM=INPLACE, LOCK=NONE", table)
+			}
+		case renameColRe.MatchString(clause):
+			col := unquote(renameColRe.FindStringSubmatch(clause)[1])
+			add(RuleRenameColumn, table, "renaming %s.%s breaks the code still running during the deploy; add the new column and backfill instead", table, col)
+		case modifyColRe.MatchString(clause):
+			col := unquote(modifyColRe.FindStringSubmatch(clause)[1])
+			if isSensitive(table) && !online && !algInstantRe.MatchString(stmt) {
+				add(RuleTableRebuild, table, "changing %s.%s rebuilds %s under a lock; add ALGORITHM=INPLACE, LOCK=NONE or split the change", table, col, table)
+			}
+		case dropColumnRe.MatchString(clause):
+			col := unquote(dropColumnRe.FindStringSubmatch(clause)[1])
+			if notColumnRe.MatchString(col) {
+				continue
+			}
+			if inUse(table, col) {
+				add(RuleDropColumn, table, "column %s.%s is still in the schema the code reads; remove it from the code in an earlier deploy", table, col)
+			}
+		}
+	}
+	return findings
+}
+
+type statement struct {
+	sql     string
+	allowed map[string]bool
+}
+
+// splitStatements splits script on semicolons outside quotes and collects
+// the guard comments above each statement.
+func splitStatements(script string) []statement {
+	var (
+		stmts   []statement
+		buf     strings.Builder
+		allowed = map[string]bool{}
+		quote   rune
+	)
+	flush := func() {
+		if sql := strings.Join(strings.Fields(buf.String()), " "); sql != "" {
+			stmts = append(stmts, statement{sql: sql, allowed: allowed})
+			allowed = map[string]bool{}
+		}
+		buf.Reset()
+	}
+
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if quote == 0 && strings.HasPrefix(trimmed, "--") {
+			if m := allowRe.FindStringSubmatch(trimmed); m != nil {
+				for _, rule := range strings.FieldsFunc(m[1], func(r rune) bool { return r == ',' || r == ' ' }) {
+					allowed[strings.ToLower(rule)] = true
+				}
+			}
+			continue
+		}
+		for _, r := range line {
+			switch {
+			case quote != 0:
+				if r == quote {
+					quote = 0
+				}
+			case r == '\'' || r == '"':
+				quote = r
+			case r == ';':
+				flush()
+				continue
+			}
+			buf.WriteRune(r)
+		}
+		buf.WriteRune('\n')
+	}
+	flush()
+	return stmts
+}
+
+// splitClauses splits the body of an ALTER TABLE on commas outside
+// parentheses and quotes.
+func splitClauses(body string) []string {
+	var (
+		clauses []string
+		start   int
+		depth   int
+		quote   rune
+	)
+	for i, r := range body {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			clauses = append(clauses, strings.TrimSpace(body[start:i]))
+			start = i + 1
+		}
+	}
+	return append(clauses, strings.TrimSpace(body[start:]))
+}
+
+func unquote(name string) string {
+	name = strings.Trim(name, "`\"")
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = strings.Trim(name[i+1:], "`\"")
+	}
+	return name
+}
@@ -3,24 +3,56 @@ package database
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/abisalde/authentication-service/internal/auth/service"
 	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/tracing"
 	"github.com/redis/go-redis/v9"
 )
 
+const (
+	// unlinkThreshold is the batch size from which deletes switch to UNLINK so
+	// Redis reclaims memory in the background instead of blocking.
+	unlinkThreshold = 64
+	deleteBatchSize = 500
+	scanCount       = 500
+)
+
+var ErrMissingTTL = errors.New("refusing to write a cache key without expiration")
+
 type RedisCache struct {
 	client *redis.Client
+	// stop shuts down the embedded store, when the cache runs on one.
+	stop func()
+
+	requireTTL bool
+	defaultTTL time.Duration
 }
 
 func NewCacheService(client *redis.Client) *RedisCache {
 	return &RedisCache{client: client}
 }
 
+// ErrEmbeddedStore is returned to tools that inspect Redis from outside the
+// service, since the embedded store lives inside the service process.
+var ErrEmbeddedStore = errors.New("embedded mode keeps the session state inside the service process, there is no Redis to connect to")
+
 func InitRedis(ctx context.Context, cfg *configs.Config) (*RedisCache, error) {
+	if cfg.Embedded.Enabled {
+		rdb, stop, err := startEmbeddedRedis()
+		if err != nil {
+			slog.Error("Failed to start the embedded session store", "error", err)
+			return nil, err
+		}
+		slog.Info("Using the embedded in-memory session store")
+		tracing.InstrumentRedis(rdb)
+		return &RedisCache{client: rdb, stop: stop}, nil
+	}
+
 	rdb := redis.NewClient(&redis.Options{
 		Addr:     "redis:6379", // We change the Address to redis:6379 when connecting via Docker instead of cfg.Redis.Addr
 		Password: cfg.Redis.Password,
@@ -30,15 +62,39 @@ func InitRedis(ctx context.Context, cfg *configs.Config) (*RedisCache, error) {
 
 	_, err := rdb.Ping(ctx).Result()
 	if err != nil {
-		log.Printf("failed to connect to Redis: %v", err)
+		slog.Error("Failed to connect to Redis", "error", err)
 		return nil, err
 	}
 
-	log.Println("⚡️ Successfully connected to Redis Cache!")
+	slog.Info("Connected to Redis")
+	tracing.InstrumentRedis(rdb)
 	return &RedisCache{client: rdb}, nil
 }
 
+// WithTTLPolicy returns a cache that never writes keys without expiry. A Set
+// without TTL gets defaultTTL when one is configured, otherwise it fails with
+// ErrMissingTTL when requireTTL is set.
+func (r *RedisCache) WithTTLPolicy(requireTTL bool, defaultTTL time.Duration) *RedisCache {
+	return &RedisCache{
+		client:     r.client,
+		stop:       r.stop,
+		requireTTL: requireTTL,
+		defaultTTL: defaultTTL,
+	}
+}
+
 func (r *RedisCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	// go-redis writes any non-positive expiration other than KeepTTL as "no expiry".
+	if expiration <= 0 && expiration != redis.KeepTTL {
+		switch {
+		case r.defaultTTL > 0:
+			slog.Warn("Cache key written without TTL, applying the default", "key", key, "ttl", r.defaultTTL)
+			expiration = r.defaultTTL
+		case r.requireTTL:
+			return fmt.Errorf("%w: %s", ErrMissingTTL, key)
+		}
+	}
+
 	marshaledValue, err := json.Marshal(value)
 	if err != nil {
 		return fmt.Errorf("failed to marshal value for Redis: %w", err)
@@ -57,11 +113,65 @@ func (r *RedisCache) Get(ctx context.Context, key string, dest interface{}) erro
 }
 
 func (r *RedisCache) Delete(ctx context.Context, keys ...string) error {
-	return r.client.Del(ctx, keys...).Err()
+	if len(keys) == 0 {
+		return nil
+	}
+	if len(keys) < unlinkThreshold {
+		return r.client.Del(ctx, keys...).Err()
+	}
+	return r.unlinkBatches(ctx, keys)
+}
+
+// DeletePattern removes every key matching pattern. It walks the keyspace with
+// SCAN rather than KEYS so Redis is never blocked, and UNLINKs each page.
+func (r *RedisCache) DeletePattern(ctx context.Context, pattern string) (int64, error) {
+	var (
+		cursor  uint64
+		deleted int64
+	)
+
+	for {
+		keys, next, err := r.client.Scan(ctx, cursor, pattern, scanCount).Result()
+		if err != nil {
+			return deleted, fmt.Errorf("failed to scan pattern %q: %w", pattern, err)
+		}
+
+		if len(keys) > 0 {
+			n, err := r.client.Unlink(ctx, keys...).Result()
+			if err != nil {
+				return deleted, fmt.Errorf("failed to unlink keys for pattern %q: %w", pattern, err)
+			}
+			deleted += n
+		}
+
+		cursor = next
+		if cursor == 0 {
+			return deleted, nil
+		}
+	}
+}
+
+func (r *RedisCache) unlinkBatches(ctx context.Context, keys []string) error {
+	pipe := r.client.Pipeline()
+	for start := 0; start < len(keys); start += deleteBatchSize {
+		end := min(start+deleteBatchSize, len(keys))
+		pipe.Unlink(ctx, keys[start:end]...)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
 }
 
 func (r *RedisCache) RawClient() *redis.Client {
 	return r.client
 }
 
+// Close closes the client and, in embedded mode, the store behind it.
+func (r *RedisCache) Close() error {
+	err := r.client.Close()
+	if r.stop != nil {
+		r.stop()
+	}
+	return err
+}
+
 var _ service.CacheService = (*RedisCache)(nil)
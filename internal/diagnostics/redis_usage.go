@@ -0,0 +1,141 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultSampleSize = 100
+	scanBatchSize     = 1000
+)
+
+// KeyspaceCategory groups the Redis keys owned by one auth feature.
+type KeyspaceCategory struct {
+	Name    string
+	Pattern string
+	// Persistent marks keyspaces that intentionally live without a TTL.
+	Persistent bool
+	// Backup marks auth state that cannot be rebuilt from the database, which
+	// cmd/redisbackup exports. Caches, counters and locks are left out.
+	Backup bool
+}
+
+type KeyspaceUsage struct {
+	Category       string
+	Pattern        string
+	Keys           int64
+	SampledKeys    int64
+	SampledBytes   int64
+	EstimatedBytes int64
+}
+
+// DefaultKeyspaces lists every key prefix written by the auth service.
+var DefaultKeyspaces = []KeyspaceCategory{
+	{Name: "refresh_tokens", Pattern: "refresh_token:*", Backup: true},
+	{Name: "blacklist", Pattern: "blacklist:*", Backup: true},
+	{Name: "rate_limit", Pattern: "rate_limit:*"},
+	{Name: "client_quotas", Pattern: "quota:*", Backup: true},
+	{Name: "pending_users", Pattern: "pending_user:*", Backup: true},
+	{Name: "verification_codes", Pattern: "verification_code:*", Backup: true},
+	{Name: "username_cache", Pattern: "username_exists:*"},
+	{Name: "oauth_state", Pattern: "oauth:*"},
+	{Name: "one_time_tokens", Pattern: "one_time_token:*", Backup: true},
+	{Name: "token_revocations", Pattern: "token_revoked_before:*", Backup: true},
+	{Name: "token_revocation_reasons", Pattern: "token_revocation_reason:*", Backup: true},
+	{Name: "maintenance_grants", Pattern: "maintenance_grant:*", Backup: true},
+	{Name: "email_changes", Pattern: "email_change:*", Backup: true},
+	{Name: "relogin_campaigns", Pattern: "relogin_campaign:*", Backup: true},
+	{Name: "suspension_appeals", Pattern: "suspension_appeal:*", Backup: true},
+	{Name: "probation_sessions", Pattern: "probation_sessions:*", Backup: true},
+	{Name: "token_scopes", Pattern: "token_scopes:*", Backup: true},
+	{Name: "opaque_tokens", Pattern: "opaque_token:*", Backup: true},
+	{Name: "token_stats", Pattern: "token_stats:*"},
+	{Name: "suspicious_sessions", Pattern: "session_suspicious:*", Backup: true},
+	{Name: "session_uses", Pattern: "session_use:*"},
+	{Name: "session_activity", Pattern: "session_activity:*", Backup: true},
+	{Name: "session_tokens", Pattern: "session_tokens:*", Backup: true},
+	{Name: "ip_reputation", Pattern: "ip_reputation:*"},
+	{Name: "scheduler_locks", Pattern: "scheduler_lock:*"},
+	{Name: "scheduler_runs", Pattern: "scheduler_runs:*"},
+	{Name: "refresh_expiry", Pattern: "refresh_expiry", Backup: true},
+	{Name: "pending_verifications", Pattern: "pending_verifications", Backup: true},
+	{Name: "verification_nudges", Pattern: "verification_nudges", Backup: true},
+	{Name: "verification_nudge_caps", Pattern: "verification_nudge_cap:*"},
+	{Name: "security_lockouts", Pattern: "security_lockout:*"},
+	{Name: "siem_cursors", Pattern: "siem_cursors", Persistent: true, Backup: true},
+	{Name: "connected_apps", Pattern: "connected_apps:*", Backup: true},
+	{Name: "connected_app_tokens", Pattern: "connected_app_tokens:*", Backup: true},
+	{Name: "connected_app_revocations", Pattern: "connected_app_revoked:*", Backup: true},
+	{Name: "login_events", Pattern: "login_events", Persistent: true},
+	{Name: "login_events_dead_letter", Pattern: "login_events_dead_letter", Persistent: true},
+	{Name: "refresh_reminder_events", Pattern: "refresh_reminder_events", Persistent: true},
+	{Name: "token_revocation_events", Pattern: "token_revocation_events", Persistent: true},
+	{Name: "email_change_events", Pattern: "email_change_events", Persistent: true},
+	{Name: "account_action_events", Pattern: "account_action_events", Persistent: true},
+	{Name: "webhook_deliveries", Pattern: "webhook_deliveries", Persistent: true},
+	{Name: "session_events", Pattern: "session_events", Persistent: true},
+	{Name: "security_events", Pattern: "security_events", Persistent: true},
+}
+
+// RedisKeyspaceUsage walks each category with SCAN (never KEYS, so Redis is not
+// blocked) and samples MEMORY USAGE on up to sampleSize keys per category to
+// estimate the memory held by the whole category.
+func RedisKeyspaceUsage(ctx context.Context, client *redis.Client, categories []KeyspaceCategory, sampleSize int) ([]KeyspaceUsage, error) {
+	if client == nil {
+		return nil, fmt.Errorf("redis client not initialized")
+	}
+
+	if sampleSize <= 0 {
+		sampleSize = defaultSampleSize
+	}
+
+	usages := make([]KeyspaceUsage, 0, len(categories))
+	for _, category := range categories {
+		usage, err := scanCategory(ctx, client, category, sampleSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s keyspace: %w", category.Name, err)
+		}
+		usages = append(usages, usage)
+	}
+
+	sort.SliceStable(usages, func(i, j int) bool {
+		return usages[i].EstimatedBytes > usages[j].EstimatedBytes
+	})
+
+	return usages, nil
+}
+
+func scanCategory(ctx context.Context, client *redis.Client, category KeyspaceCategory, sampleSize int) (KeyspaceUsage, error) {
+	usage := KeyspaceUsage{Category: category.Name, Pattern: category.Pattern}
+
+	iter := client.Scan(ctx, 0, category.Pattern, scanBatchSize).Iterator()
+	for iter.Next(ctx) {
+		usage.Keys++
+
+		if usage.SampledKeys >= int64(sampleSize) {
+			continue
+		}
+
+		bytes, err := client.MemoryUsage(ctx, iter.Val()).Result()
+		if err != nil {
+			// The key may have expired between SCAN and MEMORY USAGE.
+			continue
+		}
+		usage.SampledKeys++
+		usage.SampledBytes += bytes
+	}
+
+	if err := iter.Err(); err != nil {
+		return usage, err
+	}
+
+	if usage.SampledKeys > 0 {
+		usage.EstimatedBytes = usage.SampledBytes * usage.Keys / usage.SampledKeys
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,71 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const defaultOffenderLimit = 20
+
+type TTLAudit struct {
+	Category string
+	Pattern  string
+	Keys     int64
+	NoExpiry int64
+	// Offenders holds up to the requested number of keys without a TTL.
+	Offenders []string
+}
+
+// RedisTTLAudit reports keys without an expiry in every non-persistent
+// category. Keys without a TTL in these keyspaces grow Redis without bound,
+// so any hit points at a code path that forgot to pass an expiration.
+func RedisTTLAudit(ctx context.Context, client *redis.Client, categories []KeyspaceCategory, offenderLimit int) ([]TTLAudit, error) {
+	if client == nil {
+		return nil, fmt.Errorf("redis client not initialized")
+	}
+
+	if offenderLimit <= 0 {
+		offenderLimit = defaultOffenderLimit
+	}
+
+	audits := make([]TTLAudit, 0, len(categories))
+	for _, category := range categories {
+		if category.Persistent {
+			continue
+		}
+
+		audit, err := auditCategory(ctx, client, category, offenderLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to audit %s keyspace: %w", category.Name, err)
+		}
+		audits = append(audits, audit)
+	}
+
+	return audits, nil
+}
+
+func auditCategory(ctx context.Context, client *redis.Client, category KeyspaceCategory, offenderLimit int) (TTLAudit, error) {
+	audit := TTLAudit{Category: category.Name, Pattern: category.Pattern}
+
+	iter := client.Scan(ctx, 0, category.Pattern, scanBatchSize).Iterator()
+	for iter.Next(ctx) {
+		audit.Keys++
+
+		ttl, err := client.TTL(ctx, iter.Val()).Result()
+		if err != nil {
+			continue
+		}
+
+		// go-redis reports a key without expiry as -1 (not -1s).
+		if ttl == -1 {
+			audit.NoExpiry++
+			if len(audit.Offenders) < offenderLimit {
+				audit.Offenders = append(audit.Offenders, iter.Val())
+			}
+		}
+	}
+
+	return audit, iter.Err()
+}
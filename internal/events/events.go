@@ -0,0 +1,66 @@
+// Package events versions the payloads this service appends to Redis
+// streams. Every payload carries the schema version it was written with and
+// the producer that wrote it; the JSON Schemas under schemas/ are the
+// contract consumers code against.
+package events
+
+import (
+	"os"
+	"runtime/debug"
+	"sync"
+)
+
+// Event types, one per schema family.
+const (
+	UserLastLogin        = "user_last_login"
+	TokenRevoked         = "token_revoked"
+	RefreshTokenExpiring = "refresh_token_expiring"
+	EmailChanged         = "email_changed"
+	AccountAction        = "account_action"
+	SessionCreated       = "session_created"
+	SessionRevoked       = "session_revoked"
+	UserSessionsRevoked  = "user_sessions_revoked"
+	SecurityEvent        = "security_event"
+)
+
+const serviceName = "authentication-service"
+
+// Producer identifies the process that wrote an event.
+type Producer struct {
+	Service  string `json:"service"`
+	Instance string `json:"instance,omitempty"`
+	Revision string `json:"revision,omitempty"`
+}
+
+// Metadata is embedded in every event payload. Payloads written before
+// versioning have neither field and are version 1 without metadata.
+type Metadata struct {
+	SchemaVersion int      `json:"schema_version"`
+	Producer      Producer `json:"producer"`
+}
+
+var (
+	producerOnce sync.Once
+	producer     Producer
+)
+
+// Stamp returns the metadata of a new event of eventType, written with the
+// latest registered schema version.
+func Stamp(eventType string) Metadata {
+	producerOnce.Do(func() {
+		producer = Producer{Service: serviceName}
+		producer.Instance, _ = os.Hostname()
+		if info, ok := debug.ReadBuildInfo(); ok {
+			for _, setting := range info.Settings {
+				if setting.Key == "vcs.revision" {
+					producer.Revision = setting.Value
+				}
+			}
+		}
+	})
+
+	return Metadata{
+		SchemaVersion: Default.Latest(eventType),
+		Producer:      producer,
+	}
+}
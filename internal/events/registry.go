@@ -0,0 +1,250 @@
+package events
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//go:embed schemas/*.json
+var schemaFiles embed.FS
+
+var schemaFileName = regexp.MustCompile(`^([a-z0-9_]+)\.v([0-9]+)\.json$`)
+
+var (
+	ErrUnknownSchema = errors.New("unknown event schema")
+	ErrInvalidEvent  = errors.New("event does not match its schema")
+	ErrIncompatible  = errors.New("schema version is not backward compatible")
+)
+
+// Schema is the subset of JSON Schema the event contracts use: typed
+// properties, arrays of one item type, required fields and the date-time
+// format. Properties not
+// listed are allowed so producers can add fields without a new version.
+type Schema struct {
+	Type        string             `json:"type"`
+	Format      string             `json:"format,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+}
+
+// Registry holds every version of every event schema.
+type Registry struct {
+	schemas map[string]map[int]*Schema
+}
+
+// Default is loaded from the schemas embedded in the binary.
+var Default = mustLoad(schemaFiles)
+
+func mustLoad(fsys fs.FS) *Registry {
+	r, err := Load(fsys)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Load reads schemas named <event_type>.v<version>.json from the schemas
+// directory of fsys.
+func Load(fsys fs.FS) (*Registry, error) {
+	entries, err := fs.ReadDir(fsys, "schemas")
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Registry{schemas: make(map[string]map[int]*Schema)}
+	for _, entry := range entries {
+		match := schemaFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("unexpected schema file %s", entry.Name())
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join("schemas", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var schema Schema
+		if err := json.Unmarshal(data, &schema); err != nil {
+			return nil, fmt.Errorf("schema %s: %w", entry.Name(), err)
+		}
+
+		version, _ := strconv.Atoi(match[2])
+		if r.schemas[match[1]] == nil {
+			r.schemas[match[1]] = make(map[int]*Schema)
+		}
+		r.schemas[match[1]][version] = &schema
+	}
+	return r, nil
+}
+
+// Types lists the registered event types, sorted.
+func (r *Registry) Types() []string {
+	types := make([]string, 0, len(r.schemas))
+	for eventType := range r.schemas {
+		types = append(types, eventType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Versions lists the registered versions of eventType, oldest first.
+func (r *Registry) Versions(eventType string) []int {
+	versions := make([]int, 0, len(r.schemas[eventType]))
+	for version := range r.schemas[eventType] {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+// Latest returns the newest version of eventType, 0 when it is unknown.
+func (r *Registry) Latest(eventType string) int {
+	versions := r.Versions(eventType)
+	if len(versions) == 0 {
+		return 0
+	}
+	return versions[len(versions)-1]
+}
+
+func (r *Registry) Lookup(eventType string, version int) (*Schema, error) {
+	schema, ok := r.schemas[eventType][version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s v%d", ErrUnknownSchema, eventType, version)
+	}
+	return schema, nil
+}
+
+// Validate checks a payload against the schema of the version it declares.
+func (r *Registry) Validate(eventType string, payload []byte) error {
+	var meta Metadata
+	if err := json.Unmarshal(payload, &meta); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+	schema, err := r.Lookup(eventType, meta.SchemaVersion)
+	if err != nil {
+		return err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(payload, &value); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+	return schema.validate("$", value)
+}
+
+// CheckCompatibility fails when a version of eventType drops or retypes a
+// property of the version before it, or stops requiring a field consumers
+// of that version rely on.
+func (r *Registry) CheckCompatibility(eventType string) error {
+	versions := r.Versions(eventType)
+	for i := 1; i < len(versions); i++ {
+		older := r.schemas[eventType][versions[i-1]]
+		newer := r.schemas[eventType][versions[i]]
+		if err := compatible("$", older, newer); err != nil {
+			return fmt.Errorf("%s v%d -> v%d: %w", eventType, versions[i-1], versions[i], err)
+		}
+	}
+	return nil
+}
+
+func compatible(at string, older, newer *Schema) error {
+	if older.Type != newer.Type {
+		return fmt.Errorf("%w: %s changed type from %s to %s", ErrIncompatible, at, older.Type, newer.Type)
+	}
+	if older.Format != "" && older.Format != newer.Format {
+		return fmt.Errorf("%w: %s changed format from %s to %q", ErrIncompatible, at, older.Format, newer.Format)
+	}
+	for _, name := range older.Required {
+		if !slices.Contains(newer.Required, name) {
+			return fmt.Errorf("%w: %s.%s is no longer required", ErrIncompatible, at, name)
+		}
+	}
+	for name, property := range older.Properties {
+		next, ok := newer.Properties[name]
+		if !ok {
+			return fmt.Errorf("%w: %s.%s was removed", ErrIncompatible, at, name)
+		}
+		if err := compatible(at+"."+name, property, next); err != nil {
+			return err
+		}
+	}
+	if older.Items != nil {
+		if newer.Items == nil {
+			return fmt.Errorf("%w: %s[] was removed", ErrIncompatible, at)
+		}
+		if err := compatible(at+"[]", older.Items, newer.Items); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Schema) validate(at string, value interface{}) error {
+	switch s.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%w: %s is not an object", ErrInvalidEvent, at)
+		}
+		for _, name := range s.Required {
+			if _, ok := object[name]; !ok {
+				return fmt.Errorf("%w: %s.%s is required", ErrInvalidEvent, at, name)
+			}
+		}
+		for name, property := range s.Properties {
+			if field, ok := object[name]; ok {
+				if err := property.validate(at+"."+name, field); err != nil {
+					return err
+				}
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%w: %s is not an array", ErrInvalidEvent, at)
+		}
+		if s.Items != nil {
+			for i, item := range items {
+				if err := s.Items.validate(fmt.Sprintf("%s[%d]", at, i), item); err != nil {
+					return err
+				}
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%w: %s is not a string", ErrInvalidEvent, at)
+		}
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, str); err != nil {
+				return fmt.Errorf("%w: %s is not a date-time", ErrInvalidEvent, at)
+			}
+		}
+	case "integer":
+		number, ok := value.(float64)
+		if !ok || number != float64(int64(number)) {
+			return fmt.Errorf("%w: %s is not an integer", ErrInvalidEvent, at)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("%w: %s is not a number", ErrInvalidEvent, at)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%w: %s is not a boolean", ErrInvalidEvent, at)
+		}
+	default:
+		return fmt.Errorf("%w: %s has unsupported type %q", ErrInvalidEvent, at, s.Type)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.76
+
+import (
+	"context"
+
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+// ForceRelogin is the resolver for the forceRelogin field.
+func (r *mutationResolver) ForceRelogin(ctx context.Context, input model.ForceReloginInput) (*model.ReloginCampaign, error) {
+	return r.usersHandler.ForceRelogin(ctx, input)
+}
+
+// SuspendUser is the resolver for the suspendUser field.
+func (r *mutationResolver) SuspendUser(ctx context.Context, userID string, reason model.SuspensionReason) (*model.User, error) {
+	return r.usersHandler.SuspendUser(ctx, userID, reason)
+}
+
+// LiftSuspension is the resolver for the liftSuspension field.
+func (r *mutationResolver) LiftSuspension(ctx context.Context, userID string) (*model.User, error) {
+	return r.usersHandler.LiftSuspension(ctx, userID)
+}
+
+// ReactivateUser is the resolver for the reactivateUser field.
+func (r *mutationResolver) ReactivateUser(ctx context.Context, userID string) (*model.User, error) {
+	return r.usersHandler.ReactivateUser(ctx, userID)
+}
+
+// ForceLogoutUser is the resolver for the forceLogoutUser field.
+func (r *mutationResolver) ForceLogoutUser(ctx context.Context, userID string) (bool, error) {
+	return r.usersHandler.ForceLogoutUser(ctx, userID)
+}
+
+// ChangeUserRole is the resolver for the changeUserRole field.
+func (r *mutationResolver) ChangeUserRole(ctx context.Context, userID string, role model.UserRole) (*model.User, error) {
+	return r.usersHandler.ChangeUserRole(ctx, userID, role)
+}
+
+// DeleteUser is the resolver for the deleteUser field.
+func (r *mutationResolver) DeleteUser(ctx context.Context, userID string) (*model.User, error) {
+	return r.usersHandler.DeleteUser(ctx, userID)
+}
+
+// RequestMaintenanceToken is the resolver for the requestMaintenanceToken field.
+func (r *mutationResolver) RequestMaintenanceToken(ctx context.Context, input model.MaintenanceTokenInput) (*model.MaintenanceGrant, error) {
+	return r.usersHandler.RequestMaintenanceToken(ctx, input)
+}
+
+// ApproveMaintenanceToken is the resolver for the approveMaintenanceToken field.
+func (r *mutationResolver) ApproveMaintenanceToken(ctx context.Context, id string) (*model.MaintenanceGrant, error) {
+	return r.usersHandler.ApproveMaintenanceToken(ctx, id)
+}
+
+// IssueMaintenanceToken is the resolver for the issueMaintenanceToken field.
+func (r *mutationResolver) IssueMaintenanceToken(ctx context.Context, id string) (*model.MaintenanceToken, error) {
+	return r.usersHandler.IssueMaintenanceToken(ctx, id)
+}
+
+// SetOrganizationBranding is the resolver for the setOrganizationBranding field.
+func (r *mutationResolver) SetOrganizationBranding(ctx context.Context, input model.OrganizationBrandingInput) (*model.Organization, error) {
+	return r.usersHandler.SetOrganizationBranding(ctx, input)
+}
+
+// SetUserOrganization is the resolver for the setUserOrganization field.
+func (r *mutationResolver) SetUserOrganization(ctx context.Context, userID string, organization *string) (*model.User, error) {
+	return r.usersHandler.SetUserOrganization(ctx, userID, organization)
+}
+
+// CreateServiceAccount is the resolver for the createServiceAccount field.
+func (r *mutationResolver) CreateServiceAccount(ctx context.Context, input model.CreateServiceAccountInput) (*model.ServiceAccountCredentials, error) {
+	return r.usersHandler.CreateServiceAccount(ctx, input)
+}
+
+// RotateServiceAccountKey is the resolver for the rotateServiceAccountKey field.
+func (r *mutationResolver) RotateServiceAccountKey(ctx context.Context, id string) (*model.ServiceAccountCredentials, error) {
+	return r.usersHandler.RotateServiceAccountKey(ctx, id)
+}
+
+// RevokeServiceAccountKey is the resolver for the revokeServiceAccountKey field.
+func (r *mutationResolver) RevokeServiceAccountKey(ctx context.Context, id string, keyID string) (*model.ServiceAccount, error) {
+	return r.usersHandler.RevokeServiceAccountKey(ctx, id, keyID)
+}
+
+// ScheduleAccountAction is the resolver for the scheduleAccountAction field.
+func (r *mutationResolver) ScheduleAccountAction(ctx context.Context, input model.ScheduleAccountActionInput) (*model.ScheduledAccountAction, error) {
+	return r.usersHandler.ScheduleAccountAction(ctx, input)
+}
+
+// CancelScheduledAccountAction is the resolver for the cancelScheduledAccountAction field.
+func (r *mutationResolver) CancelScheduledAccountAction(ctx context.Context, id string) (*model.ScheduledAccountAction, error) {
+	return r.usersHandler.CancelScheduledAccountAction(ctx, id)
+}
+
+// SendTestWebhook is the resolver for the sendTestWebhook field.
+func (r *mutationResolver) SendTestWebhook(ctx context.Context) (*model.WebhookDelivery, error) {
+	return r.usersHandler.SendTestWebhook(ctx)
+}
+
+// RedeliverWebhook is the resolver for the redeliverWebhook field.
+func (r *mutationResolver) RedeliverWebhook(ctx context.Context, id string) (*model.WebhookDelivery, error) {
+	return r.usersHandler.RedeliverWebhook(ctx, id)
+}
+
+// RedisKeyspaceUsage is the resolver for the redisKeyspaceUsage field.
+func (r *queryResolver) RedisKeyspaceUsage(ctx context.Context, sampleSize *int32) ([]*model.KeyspaceUsage, error) {
+	var size *int
+	if sampleSize != nil {
+		val := int(*sampleSize)
+		size = &val
+	}
+
+	return r.diagnostics.GetRedisKeyspaceUsage(ctx, size)
+}
+
+// ReloginCampaign is the resolver for the reloginCampaign field.
+func (r *queryResolver) ReloginCampaign(ctx context.Context, id string) (*model.ReloginCampaign, error) {
+	return r.usersHandler.GetReloginCampaign(ctx, id)
+}
+
+// OauthProviderHealth is the resolver for the oauthProviderHealth field.
+func (r *queryResolver) OauthProviderHealth(ctx context.Context) ([]*model.OAuthProviderHealth, error) {
+	return r.oauthHandler.ProviderHealth(ctx)
+}
+
+// TokenStats is the resolver for the tokenStats field.
+func (r *queryResolver) TokenStats(ctx context.Context, hours *int32) (*model.TokenStats, error) {
+	return r.diagnostics.GetTokenStats(ctx, hours)
+}
+
+// SuspensionAppeal is the resolver for the suspensionAppeal field.
+func (r *queryResolver) SuspensionAppeal(ctx context.Context, userID string) (*model.SuspensionAppeal, error) {
+	return r.usersHandler.GetSuspensionAppeal(ctx, userID)
+}
+
+// MaintenanceGrant is the resolver for the maintenanceGrant field.
+func (r *queryResolver) MaintenanceGrant(ctx context.Context, id string) (*model.MaintenanceGrant, error) {
+	return r.usersHandler.GetMaintenanceGrant(ctx, id)
+}
+
+// Organization is the resolver for the organization field.
+func (r *queryResolver) Organization(ctx context.Context, slug string) (*model.Organization, error) {
+	return r.usersHandler.GetOrganization(ctx, slug)
+}
+
+// ServiceAccounts is the resolver for the serviceAccounts field.
+func (r *queryResolver) ServiceAccounts(ctx context.Context, organization string) ([]*model.ServiceAccount, error) {
+	return r.usersHandler.ListServiceAccounts(ctx, organization)
+}
+
+// ScheduledAccountActions is the resolver for the scheduledAccountActions field.
+func (r *queryResolver) ScheduledAccountActions(ctx context.Context, userID string) ([]*model.ScheduledAccountAction, error) {
+	return r.usersHandler.ListScheduledAccountActions(ctx, userID)
+}
+
+// WebhookDeliveries is the resolver for the webhookDeliveries field.
+func (r *queryResolver) WebhookDeliveries(ctx context.Context, limit *int32) ([]*model.WebhookDelivery, error) {
+	var size int
+	if limit != nil {
+		size = int(*limit)
+	}
+	return r.usersHandler.ListWebhookDeliveries(ctx, size)
+}
@@ -0,0 +1,22 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.76
+
+import (
+	"context"
+
+	"github.com/abisalde/authentication-service/internal/graph/admin"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+// ID is the resolver for the id field.
+func (r *publicUserResolver) ID(ctx context.Context, obj *model.PublicUser) (string, error) {
+	return "0", nil
+}
+
+// PublicUser returns admin.PublicUserResolver implementation.
+func (r *Resolver) PublicUser() admin.PublicUserResolver { return &publicUserResolver{r} }
+
+type publicUserResolver struct{ *Resolver }
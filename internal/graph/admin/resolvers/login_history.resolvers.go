@@ -0,0 +1,16 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.76
+
+import (
+	"context"
+
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+// LoginAttempts is the resolver for the loginAttempts field.
+func (r *queryResolver) LoginAttempts(ctx context.Context, filter *model.LoginAttemptFilter, first *int32, after *string) (*model.LoginAttemptConnection, error) {
+	return r.usersHandler.GetLoginAttempts(ctx, filter, first, after)
+}
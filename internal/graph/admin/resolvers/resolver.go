@@ -0,0 +1,26 @@
+package resolvers
+
+import (
+	"github.com/abisalde/authentication-service/internal/auth/handler/http"
+	"github.com/abisalde/authentication-service/internal/auth/handler/oauth"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+)
+
+// This file will not be regenerated automatically.
+//
+// It serves as dependency injection for the admin schema, add any dependencies
+// you require here.
+
+type Resolver struct {
+	usersHandler *http.UsersHandler
+	oauthHandler *oauth.OAuthHandler
+	diagnostics  *http.DiagnosticsHandler
+}
+
+func NewResolver(authService *service.AuthService, oauthService *service.OAuthService) *Resolver {
+	return &Resolver{
+		usersHandler: http.NewUsersHandler(authService),
+		oauthHandler: oauth.NewOAuthHandler(oauthService),
+		diagnostics:  http.NewDiagnosticsHandler(authService),
+	}
+}
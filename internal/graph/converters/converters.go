@@ -1,8 +1,13 @@
 package converters
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/database/ent/useridentity"
 	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/pkg/useragent"
 )
 
 func UserToGraph(user *ent.User) *model.User {
@@ -11,8 +16,14 @@ func UserToGraph(user *ent.User) *model.User {
 		username = &user.Username
 	}
 
-	return &model.User{
+	var termsVersion *string
+	if user.TermsVersion != "" {
+		termsVersion = &user.TermsVersion
+	}
+
+	result := &model.User{
 		ID:        user.ID,
+		PublicID:  user.PublicID.String(),
 		Email:     user.Email,
 		Username:  username,
 		Provider:  model.AuthProvider(user.Provider),
@@ -31,7 +42,156 @@ func UserToGraph(user *ent.User) *model.User {
 		PhoneNumber:     user.PhoneNumber,
 		IsEmailVerified: user.IsEmailVerified,
 		TermsAcceptedAt: user.TermsAcceptedAt,
+		TermsVersion:    termsVersion,
 		MarketingOptIn:  user.MarketingOptIn,
 		LastLoginAt:     user.LastLoginAt,
+		OnboardingStep:  model.OnboardingStep(user.OnboardingStep),
+		SuspendedAt:     user.SuspendedAt,
+		DeletedAt:       user.DeletedAt,
+		DeactivatedAt:   user.DeactivatedAt,
+	}
+
+	if user.SuspensionReason != nil {
+		reason := model.SuspensionReason(*user.SuspensionReason)
+		result.SuspensionReason = &reason
+	}
+
+	return result
+}
+
+func LoginAttemptToGraph(attempt *ent.LoginAttempt) *model.LoginAttempt {
+	result := &model.LoginAttempt{
+		ID:        strconv.FormatInt(attempt.ID, 10),
+		Email:     attempt.Email,
+		IP:        attempt.IP,
+		DeviceID:  attempt.DeviceID,
+		Platform:  attempt.Platform,
+		UserAgent: attempt.UserAgent,
+		Device:    DeviceInfoToGraph(useragent.ParseWithHints(attempt.UserAgent, attempt.ClientHints)),
+		Method:    model.LoginMethod(attempt.Method),
+		Outcome:   model.LoginOutcome(attempt.Outcome),
+		RiskScore: int32(attempt.RiskScore),
+		CreatedAt: attempt.CreatedAt,
+	}
+
+	if u := attempt.Edges.User; u != nil {
+		publicID := u.PublicID.String()
+		result.UserID = &publicID
+	}
+	if attempt.FailureReason != nil {
+		reason := model.LoginFailureReason(*attempt.FailureReason)
+		result.FailureReason = &reason
+	}
+
+	return result
+}
+
+func DeviceInfoToGraph(info useragent.DeviceInfo) *model.DeviceInfo {
+	optional := func(value string) *string {
+		if value == "" {
+			return nil
+		}
+		return &value
+	}
+
+	result := &model.DeviceInfo{
+		Browser:        optional(info.Browser),
+		BrowserVersion: optional(info.BrowserVersion),
+		Os:             optional(info.OS),
+		OsVersion:      optional(info.OSVersion),
+		Model:          optional(info.Model),
+		Bot:            info.Bot,
+	}
+	if info.Type != "" {
+		deviceType := model.DeviceType(info.Type)
+		result.Type = &deviceType
+	}
+	return result
+}
+
+func OrganizationToGraph(org *ent.Organization) *model.Organization {
+	return &model.Organization{
+		ID:           strconv.FormatInt(org.ID, 10),
+		Slug:         org.Slug,
+		Name:         org.Name,
+		ProductName:  org.ProductName,
+		LogoURL:      org.LogoURL,
+		PrimaryColor: org.PrimaryColor,
+		SupportEmail: org.SupportEmail,
+		CreatedAt:    org.CreatedAt,
+		UpdatedAt:    org.UpdatedAt,
+	}
+}
+
+// ServiceAccountToGraph expects the organization and keys of the account to
+// be loaded.
+func ServiceAccountToGraph(u *ent.User) *model.ServiceAccount {
+	account := &model.ServiceAccount{
+		ID:        u.PublicID.String(),
+		Name:      u.FirstName,
+		Keys:      make([]*model.ServiceAccountKey, 0, len(u.Edges.ServiceAccountKeys)),
+		CreatedAt: u.CreatedAt,
+	}
+	if u.Edges.Organization != nil {
+		account.Organization = u.Edges.Organization.Slug
+	}
+	for _, key := range u.Edges.ServiceAccountKeys {
+		account.Keys = append(account.Keys, &model.ServiceAccountKey{
+			ID:         key.KeyID,
+			CreatedAt:  key.CreatedAt,
+			ExpiresAt:  key.ExpiresAt,
+			LastUsedAt: key.LastUsedAt,
+		})
+	}
+	return account
+}
+
+func ScheduledActionToGraph(action *ent.ScheduledAction) *model.ScheduledAccountAction {
+	result := &model.ScheduledAccountAction{
+		ID:         strconv.FormatInt(action.ID, 10),
+		Action:     model.AccountActionType(action.Action),
+		RunAt:      action.RunAt,
+		Status:     model.ScheduledActionStatus(action.Status),
+		CreatedAt:  action.CreatedAt,
+		FinishedAt: action.FinishedAt,
+	}
+	if action.Edges.User != nil {
+		result.UserID = action.Edges.User.PublicID.String()
+	}
+	if action.Note != "" {
+		result.Note = &action.Note
+	}
+	if action.Error != "" {
+		result.Error = &action.Error
+	}
+	if action.CreatedBy != nil {
+		createdBy := action.CreatedBy.String()
+		result.CreatedBy = &createdBy
+	}
+	if action.CancelledBy != nil {
+		cancelledBy := action.CancelledBy.String()
+		result.CancelledBy = &cancelledBy
+	}
+	return result
+}
+
+func LinkedAccountsToGraph(identities []*ent.UserIdentity) []*model.LinkedAccount {
+	accounts := make([]*model.LinkedAccount, 0, len(identities))
+	for _, identity := range identities {
+		account := &model.LinkedAccount{
+			ID:         strconv.FormatInt(identity.ID, 10),
+			Provider:   model.AuthProvider(identity.Provider),
+			CreatedAt:  identity.CreatedAt,
+			LastUsedAt: identity.LastUsedAt,
+		}
+		if identity.Email != "" {
+			account.Email = &identity.Email
+		}
+		if identity.Provider == useridentity.ProviderOAUTH {
+			name, _, _ := strings.Cut(identity.OauthID, ":")
+			account.ProviderName = &name
+		}
+		accounts = append(accounts, account)
 	}
+	return accounts
 }
@@ -2,50 +2,167 @@ package directives
 
 import (
 	"context"
+	"encoding/json"
+	"log/slog"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/cookies"
+	"github.com/abisalde/authentication-service/internal/auth/maintenance"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/database/ent"
 	"github.com/abisalde/authentication-service/internal/database/ent/user"
 	"github.com/abisalde/authentication-service/internal/graph/errors"
 	"github.com/abisalde/authentication-service/internal/graph/model"
 
+	"github.com/vektah/gqlparser/v2/ast"
 	"github.com/vektah/gqlparser/v2/gqlerror"
 )
 
 type AuthDirective struct {
+	decisions   *AuthDecisions
+	authService *service.AuthService
 }
 
 func NewAuthDirective() *AuthDirective {
 	return &AuthDirective{}
 }
 
+// WithDecisionCache reuses role checks across requests. Without it every
+// request checks the role again.
+func (a *AuthDirective) WithDecisionCache(decisions *AuthDecisions) *AuthDirective {
+	a.decisions = decisions
+	return a
+}
+
+// WithMaintenanceScope checks that the calls of maintenance tokens limited to
+// an organization stay in it. Without it such tokens are denied every call.
+func (a *AuthDirective) WithMaintenanceScope(authService *service.AuthService) *AuthDirective {
+	a.authService = authService
+	return a
+}
+
 func (a *AuthDirective) Auth(ctx context.Context, obj interface{}, next graphql.Resolver, requires *model.UserRole) (interface{}, error) {
 
 	currentUser := auth.GetCurrentUser(ctx)
 
 	if currentUser == nil {
+		if reason := auth.GetRevocation(ctx); reason != "" {
+			return nil, errors.SessionRevoked(reason)
+		}
 		return nil, errors.AuthenticationRequired
 	}
 
+	// Tokens issued before a suspension or deletion are already revoked;
+	// this also covers tokens that slipped through while Redis was
+	// unavailable.
+	if service.IsDeleted(currentUser) {
+		return nil, errors.AuthenticationRequired
+	}
+	if service.IsDeactivated(currentUser) {
+		return nil, errors.SessionRevoked(string(model.RevocationReasonAccountDeactivated))
+	}
+	if service.IsSuspended(currentUser) {
+		return nil, errors.AccountSuspended(service.SuspensionReason(currentUser))
+	}
+
+	if grant := auth.GetMaintenanceGrant(ctx); grant != nil {
+		return a.authorizeMaintenance(ctx, grant, currentUser.ID, next)
+	}
+
 	requiredRole := user.Role(requires.String())
 
-	if !hasRequiredRole(currentUser.Role, requiredRole) {
-		return nil, gqlerror.Errorf(
+	// The role and CSRF checks depend only on the request, not the field.
+	err := memoized(ctx, requiredRole, func() error {
+		return a.authorize(ctx, currentUser, requiredRole)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return next(ctx)
+}
+
+func (a *AuthDirective) authorize(ctx context.Context, currentUser *ent.User, requiredRole user.Role) error {
+	if !a.decisions.Allowed(currentUser.ID, currentUser.Role, requiredRole) {
+		return gqlerror.Errorf(
 			"Access denied: requires %s role",
 			requiredRole,
 		)
 	}
 
+	if requiresCSRF(ctx) {
+		if err := cookies.VerifyCSRF(ctx, currentUser); err != nil {
+			return errors.InvalidCSRFToken
+		}
+	}
+
+	return nil
+}
+
+// authorizeMaintenance lets a maintenance token call the top-level mutations
+// of its grant whatever their required role, and nothing else. A grant
+// limited to an organization only reaches its users and resources. Every
+// call is logged with the grant's reason.
+func (a *AuthDirective) authorizeMaintenance(ctx context.Context, grant *maintenance.Grant, userID int64, next graphql.Resolver) (interface{}, error) {
+	field := graphql.GetFieldContext(ctx)
+	if field == nil || field.Object != "Mutation" || !grant.Allows(field.Field.Name) {
+		return nil, gqlerror.Errorf("Access denied: maintenance token does not grant this operation")
+	}
+
+	if grant.OrganizationID != nil {
+		allowed := false
+		if a.authService != nil {
+			var err error
+			allowed, err = a.authService.MaintenanceCallInOrganization(ctx, *grant.OrganizationID, maintenanceArgs(field.Args))
+			if err != nil {
+				slog.Error("Failed to check the organization of a maintenance call", "grant", grant.ID, "field", field.Field.Name, "error", err)
+			}
+		}
+		if !allowed {
+			return nil, gqlerror.Errorf("Access denied: maintenance token is limited to the organization %s", grant.Organization)
+		}
+	}
+
+	slog.Info("Maintenance grant used", "grant", grant.ID, "user_id", userID, "field", field.Field.Name, "organization", grant.Organization, "reason", grant.Reason)
 	return next(ctx)
 }
 
-func hasRequiredRole(userRole, requiredRole user.Role) bool {
+// maintenanceArgs turns the field's arguments, input structs included, back
+// into plain maps keyed by their GraphQL names.
+func maintenanceArgs(args map[string]any) map[string]any {
+	raw, err := json.Marshal(args)
+	if err != nil {
+		return nil
+	}
+	var plain map[string]any
+	if err := json.Unmarshal(raw, &plain); err != nil {
+		return nil
+	}
+	return plain
+}
+
+// requiresCSRF reports whether the field is part of a mutation sent with
+// cookie credentials; bearer-token clients are not exposed to CSRF.
+func requiresCSRF(ctx context.Context) bool {
+	if _, ok := auth.GetCookieSession(ctx); !ok {
+		return false
+	}
 
-	roleHierarchy := map[user.Role]int{
-		user.RoleUSER:  1,
-		user.RoleADMIN: 2,
+	if !graphql.HasOperationContext(ctx) {
+		return false
 	}
 
+	op := graphql.GetOperationContext(ctx).Operation
+	return op != nil && op.Operation == ast.Mutation
+}
+
+var roleHierarchy = map[user.Role]int{
+	user.RoleUSER:  1,
+	user.RoleADMIN: 2,
+}
+
+func hasRequiredRole(userRole, requiredRole user.Role) bool {
 	userLevel := roleHierarchy[userRole]
 	if userLevel == 0 {
 		userLevel = 1
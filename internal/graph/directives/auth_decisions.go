@@ -0,0 +1,134 @@
+package directives
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/abisalde/authentication-service/internal/database/ent/user"
+	"github.com/abisalde/authentication-service/internal/metrics"
+)
+
+// maxAuthDecisions bounds the cross-request cache. Expired entries are swept
+// when it fills up, and the cache starts over if that is not enough.
+const maxAuthDecisions = 50000
+
+var authDecisionLookups = metrics.Default.NewCounterVec(
+	"auth_decisions_total",
+	"@auth role checks, by whether they came from the request memo, the decision cache or were computed.",
+	"source",
+)
+
+type authDecisionKey struct {
+	userID   int64
+	requires user.Role
+}
+
+type authDecision struct {
+	role    user.Role
+	allowed bool
+	expires time.Time
+}
+
+// AuthDecisions caches (user, required role) decisions across requests.
+// An entry also records the role it was decided for, so a user loaded with
+// another role, after a change made on another instance, is checked again.
+type AuthDecisions struct {
+	ttl     time.Duration
+	mu      sync.RWMutex
+	entries map[authDecisionKey]authDecision
+}
+
+func NewAuthDecisions(ttl time.Duration) *AuthDecisions {
+	return &AuthDecisions{ttl: ttl, entries: make(map[authDecisionKey]authDecision)}
+}
+
+// Allowed reports whether a user with role may access fields requiring
+// requires, computing and caching the decision on a miss.
+func (d *AuthDecisions) Allowed(userID int64, role, requires user.Role) bool {
+	if d == nil || d.ttl <= 0 {
+		authDecisionLookups.Inc("computed")
+		return hasRequiredRole(role, requires)
+	}
+
+	key := authDecisionKey{userID: userID, requires: requires}
+	now := time.Now()
+
+	d.mu.RLock()
+	entry, found := d.entries[key]
+	d.mu.RUnlock()
+	if found && entry.role == role && now.Before(entry.expires) {
+		authDecisionLookups.Inc("cache")
+		return entry.allowed
+	}
+
+	authDecisionLookups.Inc("computed")
+	allowed := hasRequiredRole(role, requires)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.entries) >= maxAuthDecisions {
+		d.sweep(now)
+	}
+	d.entries[key] = authDecision{role: role, allowed: allowed, expires: now.Add(d.ttl)}
+	return allowed
+}
+
+// Invalidate drops every decision cached for the user. It is registered as
+// a role change hook of the auth service.
+func (d *AuthDecisions) Invalidate(userID int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for key := range d.entries {
+		if key.userID == userID {
+			delete(d.entries, key)
+		}
+	}
+}
+
+func (d *AuthDecisions) sweep(now time.Time) {
+	for key, entry := range d.entries {
+		if !now.Before(entry.expires) {
+			delete(d.entries, key)
+		}
+	}
+	if len(d.entries) >= maxAuthDecisions {
+		d.entries = make(map[authDecisionKey]authDecision)
+	}
+}
+
+type authMemoKey struct{}
+
+// authMemo holds the outcome of the role and CSRF checks for each required
+// role within one operation. Fields resolve concurrently, hence the lock.
+type authMemo struct {
+	mu      sync.Mutex
+	results map[user.Role]error
+}
+
+// MemoizeAuth is an operation middleware giving every operation its own
+// memo, so the @auth checks run once per required role rather than once
+// per guarded field.
+func MemoizeAuth(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	return next(context.WithValue(ctx, authMemoKey{}, &authMemo{results: make(map[user.Role]error)}))
+}
+
+// memoized returns the outcome of check for requires, running it at most
+// once per operation. Without a memo in ctx check runs every time.
+func memoized(ctx context.Context, requires user.Role, check func() error) error {
+	memo, ok := ctx.Value(authMemoKey{}).(*authMemo)
+	if !ok {
+		return check()
+	}
+
+	memo.mu.Lock()
+	defer memo.mu.Unlock()
+	if err, found := memo.results[requires]; found {
+		authDecisionLookups.Inc("memo")
+		return err
+	}
+	err := check()
+	memo.results[requires] = err
+	return err
+}
@@ -3,7 +3,7 @@ package directives
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"reflect"
 	"strconv"
 
@@ -32,7 +32,7 @@ func (*DefaultDirective) Default(ctx context.Context, obj interface{}, next grap
 		return nil, fmt.Errorf("cannot apply @default, no field context")
 	}
 
-	log.Printf("@default invoked on field %s with default value %s", field.Field.Name, value)
+	slog.Debug("@default invoked", "field", field.Field.Name, "default", value)
 
 	var finalValue interface{}
 
@@ -44,7 +44,7 @@ func (*DefaultDirective) Default(ctx context.Context, obj interface{}, next grap
 		case reflect.Bool:
 			b, err := strconv.ParseBool(value)
 			if err != nil {
-				log.Printf("@default: failed to parse bool from %q: %v", value, err)
+				slog.Warn("@default: failed to parse a bool", "value", value, "error", err)
 				finalValue = false
 			} else {
 				finalValue = b
@@ -52,7 +52,7 @@ func (*DefaultDirective) Default(ctx context.Context, obj interface{}, next grap
 		case reflect.Int, reflect.Int32, reflect.Int64:
 			i, err := strconv.ParseInt(value, 10, 64)
 			if err != nil {
-				log.Printf("@default: failed to parse int from %q: %v", value, err)
+				slog.Warn("@default: failed to parse an int", "value", value, "error", err)
 				finalValue = nil
 			} else {
 
@@ -61,13 +61,13 @@ func (*DefaultDirective) Default(ctx context.Context, obj interface{}, next grap
 		case reflect.Float32, reflect.Float64:
 			f, err := strconv.ParseFloat(value, 64)
 			if err != nil {
-				log.Printf("@default: failed to parse float from %q: %v", value, err)
+				slog.Warn("@default: failed to parse a float", "value", value, "error", err)
 				finalValue = nil
 			} else {
 				finalValue = reflect.ValueOf(f).Convert(fieldType).Interface()
 			}
 		default:
-			log.Printf("@default: unknown type %s, fallback to string", fieldType.String())
+			slog.Warn("@default: unknown type, fallback to string", "type", fieldType.String())
 			finalValue = value
 		}
 	}
@@ -0,0 +1,40 @@
+package directives
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/onboarding"
+	"github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+type OnboardingDirective struct {
+	policy onboarding.Policy
+}
+
+func NewOnboardingDirective(policy onboarding.Policy) *OnboardingDirective {
+	return &OnboardingDirective{policy: policy}
+}
+
+func (o *OnboardingDirective) Onboarded(ctx context.Context, obj interface{}, next graphql.Resolver) (interface{}, error) {
+	if !o.policy.Enforce {
+		return next(ctx)
+	}
+
+	currentUser := auth.GetCurrentUser(ctx)
+	if currentUser == nil {
+		return nil, errors.AuthenticationRequired
+	}
+
+	if step := o.policy.NextStep(currentUser); step != onboarding.StepDone {
+		return nil, errors.NewTypedError(
+			"Complete your onboarding to continue",
+			model.ErrorTypeOnboardingRequired,
+			map[string]interface{}{"nextStep": step},
+		)
+	}
+
+	return next(ctx)
+}
@@ -3,62 +3,128 @@ package directives
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"reflect"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/abisalde/authentication-service/internal/auth"
-	"github.com/abisalde/authentication-service/internal/database"
-	"github.com/abisalde/authentication-service/internal/database/ent"
 	"github.com/abisalde/authentication-service/internal/graph/errors"
 	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/ratelimit"
+	app_logger "github.com/abisalde/authentication-service/pkg/logger"
+	"github.com/vektah/gqlparser/v2/ast"
 )
 
+// RateLimitDirective enforces @rateLimit with the limiter shared with the
+// HTTP middleware.
 type RateLimitDirective struct {
-	redisCache *database.RedisCache
+	limiter *ratelimit.Limiter
+	// policies holds every @rateLimit in the schema by "Type.field", filled
+	// once by Compile before the server starts.
+	policies map[string]ratelimit.Policy
 }
 
-func NewRateLimitDirective(redisCache *database.RedisCache) *RateLimitDirective {
+func NewRateLimitDirective(limiter *ratelimit.Limiter) *RateLimitDirective {
 	return &RateLimitDirective{
-		redisCache: redisCache,
+		limiter:  limiter,
+		policies: map[string]ratelimit.Policy{},
 	}
 }
 
+// Limiter returns the limiter the directive counts with.
+func (r *RateLimitDirective) Limiter() *ratelimit.Limiter {
+	return r.limiter
+}
+
+// Compile validates every @rateLimit in the schema and keeps the parsed
+// policies, so a bad window or key fails the server at startup rather than
+// the first request to the field.
+func (r *RateLimitDirective) Compile(schema *ast.Schema) error {
+	for _, def := range schema.Types {
+		if def.Kind != ast.Object || def.BuiltIn {
+			continue
+		}
+
+		for _, field := range def.Fields {
+			d := field.Directives.ForName("rateLimit")
+			if d == nil {
+				continue
+			}
+
+			name := def.Name + "." + field.Name
+			policy, err := r.compileDirective(d)
+			if err != nil {
+				return fmt.Errorf("@rateLimit on %s: %w", name, err)
+			}
+			r.policies[name] = policy
+		}
+	}
+	return nil
+}
+
+// Policies returns the compiled policies by "Type.field".
+func (r *RateLimitDirective) Policies() map[string]ratelimit.Policy {
+	return r.policies
+}
+
 func (r *RateLimitDirective) RateLimit(
 	ctx context.Context,
 	obj interface{},
 	next graphql.Resolver,
 	operation model.RateLimitMethods,
 	limit int32,
-	duration int32,
+	duration *int32,
+	window *string,
+	key *model.RateLimitKey,
+	burst *int32,
+	algorithm *model.RateLimitAlgorithm,
 ) (interface{}, error) {
 
 	auth.DebugContext(ctx)
 
-	if r.redisCache == nil {
-		log.Println("redisCache is nil")
-		return nil, fmt.Errorf("rate limiter not initialized")
-	}
-
 	user := auth.GetCurrentUser(ctx)
 	ip := auth.GetIPFromContext(ctx)
 
-	identifier := r.getIdentifier(user, ip)
+	// Automation is exempt from login throttling only; it spends its own
+	// login quota instead. Every other operation, such as verifying a code,
+	// stays limited so a leaked key cannot brute-force it.
+	if client := auth.GetAutomationClient(ctx); client != "" && operation == model.RateLimitMethodsLogin {
+		app_logger.LogAutomationActivity(client, operation.String(), ip)
+		exhausted, err := r.limiter.SpendQuota(ctx, client, model.QuotaDimensionLoginsPerHour)
+		if err != nil {
+			slog.Error("Failed to spend client quota", "client", client, "error", err)
+			return nil, errors.RateLimitExceeded
+		}
+		if exhausted != nil {
+			return nil, errors.QuotaExceeded(exhausted.Dimension, exhausted.Limit, exhausted.ResetsAt)
+		}
+		return next(ctx)
+	}
 
-	window := time.Duration(duration) * time.Second
-	expiration := time.Now().Unix() / int64(window.Seconds())
-	windowKey := fmt.Sprintf("rate_limit:%s:%s:%d", operation.String(), identifier, expiration)
+	policy, ok := r.compiledPolicy(ctx)
+	if !ok {
+		var err error
+		policy, err = r.compilePolicy(operation, int64(limit), duration, window, key, burst, algorithm)
+		if err != nil {
+			slog.Error("Invalid @rateLimit", "operation", operation.String(), "error", err)
+			return nil, ratelimit.ErrNotInitialized
+		}
+	}
 
-	pipe := r.redisCache.RawClient().TxPipeline()
-	incr := pipe.Incr(ctx, windowKey)
-	pipe.Expire(ctx, windowKey, window)
-	_, err := pipe.Exec(ctx)
-	if err != nil {
-		return nil, errors.RateLimitExceeded
+	client := ratelimit.Client{User: user, IP: ip}
+	if policy.Key == model.RateLimitKeyEmail {
+		client.Email = emailArgument(ctx)
 	}
 
-	count := incr.Val()
-	if count > int64(limit) {
+	allowed, err := r.limiter.Allow(ctx, policy, client)
+	if err == ratelimit.ErrNotInitialized {
+		slog.Error("Rate limiter is not initialized", "operation", operation.String())
+		return nil, err
+	}
+	if err != nil || !allowed {
 		return nil, errors.RateLimitExceeded
 	}
 
@@ -66,13 +132,159 @@ func (r *RateLimitDirective) RateLimit(
 
 }
 
-func (r *RateLimitDirective) getIdentifier(user *ent.User, ip string) string {
+func (r *RateLimitDirective) compiledPolicy(ctx context.Context) (ratelimit.Policy, bool) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || fc.Field.Field == nil {
+		return ratelimit.Policy{}, false
+	}
+	policy, ok := r.policies[fc.Object+"."+fc.Field.Name]
+	return policy, ok
+}
+
+func (r *RateLimitDirective) compileDirective(d *ast.Directive) (ratelimit.Policy, error) {
+	operation := model.RateLimitMethods(directiveArg(d, "operation"))
+
+	limit, err := strconv.ParseInt(directiveArg(d, "limit"), 10, 32)
+	if err != nil {
+		return ratelimit.Policy{}, fmt.Errorf("invalid limit: %w", err)
+	}
+
+	var duration, burst *int32
+	for name, target := range map[string]**int32{"duration": &duration, "burst": &burst} {
+		raw := directiveArg(d, name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			return ratelimit.Policy{}, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		n := int32(v)
+		*target = &n
+	}
+
+	var window *string
+	if raw := directiveArg(d, "window"); raw != "" {
+		window = &raw
+	}
+
+	var key *model.RateLimitKey
+	if raw := directiveArg(d, "key"); raw != "" {
+		k := model.RateLimitKey(raw)
+		key = &k
+	}
+
+	var algorithm *model.RateLimitAlgorithm
+	if raw := directiveArg(d, "algorithm"); raw != "" {
+		a := model.RateLimitAlgorithm(raw)
+		algorithm = &a
+	}
+
+	return r.compilePolicy(operation, limit, duration, window, key, burst, algorithm)
+}
+
+// directiveArg returns the literal value of a directive argument, or "" when
+// it is absent and the schema default applies.
+func directiveArg(d *ast.Directive, name string) string {
+	arg := d.Arguments.ForName(name)
+	if arg == nil || arg.Value == nil || arg.Value.Kind == ast.NullValue {
+		return ""
+	}
+	return arg.Value.Raw
+}
+
+func (r *RateLimitDirective) compilePolicy(
+	operation model.RateLimitMethods,
+	limit int64,
+	duration *int32,
+	window *string,
+	key *model.RateLimitKey,
+	burst *int32,
+	algorithm *model.RateLimitAlgorithm,
+) (ratelimit.Policy, error) {
+	policy := ratelimit.Policy{
+		Operation: operation,
+		Limit:     limit,
+		Key:       model.RateLimitKeyClient,
+		Algorithm: model.RateLimitAlgorithmFixedWindow,
+	}
+
+	if !operation.IsValid() {
+		return policy, fmt.Errorf("unknown operation %q", operation)
+	}
+	if limit < 1 {
+		return policy, fmt.Errorf("limit must be at least 1")
+	}
+
 	switch {
-	case user != nil:
-		return fmt.Sprintf("user:%v", user.ID)
-	case ip != "":
-		return fmt.Sprintf("ip:%s", ip)
+	case window != nil:
+		parsed, err := time.ParseDuration(*window)
+		if err != nil {
+			return policy, fmt.Errorf("invalid window: %w", err)
+		}
+		policy.Window = parsed
+	case duration != nil:
+		policy.Window = time.Duration(*duration) * time.Second
 	default:
-		return "anonymous"
+		return policy, fmt.Errorf("window or duration is required")
+	}
+	if policy.Window < time.Second {
+		return policy, fmt.Errorf("window must be at least 1s")
+	}
+
+	if key != nil {
+		if !key.IsValid() {
+			return policy, fmt.Errorf("unknown key %q", *key)
+		}
+		policy.Key = *key
+	}
+
+	if algorithm != nil {
+		policy.Algorithm = *algorithm
+	}
+	if !r.limiter.Supports(policy.Algorithm) {
+		return policy, fmt.Errorf("unknown algorithm %q", policy.Algorithm)
+	}
+
+	policy.Burst = limit
+	if burst != nil {
+		if policy.Algorithm != model.RateLimitAlgorithmTokenBucket {
+			return policy, fmt.Errorf("burst requires the TOKEN_BUCKET algorithm")
+		}
+		if *burst < 1 {
+			return policy, fmt.Errorf("burst must be at least 1")
+		}
+		policy.Burst = int64(*burst)
+	}
+
+	return policy, nil
+}
+
+// emailArgument finds the email the operation acts on, either as a
+// top-level argument or as the Email field of an input object.
+func emailArgument(ctx context.Context) string {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil {
+		return ""
+	}
+
+	if email, ok := fc.Args["email"].(string); ok {
+		return strings.ToLower(strings.TrimSpace(email))
+	}
+
+	for _, arg := range fc.Args {
+		v := reflect.Indirect(reflect.ValueOf(arg))
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+		field := v.FieldByName("Email")
+		if !field.IsValid() {
+			continue
+		}
+		field = reflect.Indirect(field)
+		if field.Kind() == reflect.String && field.String() != "" {
+			return strings.ToLower(strings.TrimSpace(field.String()))
+		}
 	}
+	return ""
 }
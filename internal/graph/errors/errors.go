@@ -60,6 +60,12 @@ var (
 			"code": model.ErrorTypeEmail,
 		},
 	}
+	SignInCountryBlocked = &gqlerror.Error{
+		Message: "Sign-ins from your country are not accepted",
+		Extensions: map[string]interface{}{
+			"code": model.ErrorTypeForbidden,
+		},
+	}
 	ErrSomethingWentWrong = NewTypedError("Something went wrong! Please try again", model.ErrorTypeBadRequest, map[string]interface{}{})
 	InvalidToken          = &gqlerror.Error{
 		Message: "Invalid token header",
@@ -100,10 +106,44 @@ var (
 		},
 	}
 
+	ReauthenticationRequired = &gqlerror.Error{
+		Message: "Your session moved to another network or device, sign in again to continue",
+		Extensions: map[string]interface{}{
+			"code": model.ErrorTypeReauthenticationRequired,
+		},
+	}
+
 	AccessTokenGeneration = &gqlerror.Error{
 		Message: "There's an error generating token, please try again",
 		Extensions: map[string]interface{}{
 			"code": model.ErrorTypeRefreshToken,
 		},
 	}
+
+	EmailVerificationRequired = &gqlerror.Error{
+		Message: "Verify your email address before signing in",
+		Extensions: map[string]interface{}{
+			"code": model.ErrorTypeForbidden,
+		},
+	}
+
+	CaptchaRequired = &gqlerror.Error{
+		Message: "Please complete the CAPTCHA and try again",
+		Extensions: map[string]interface{}{
+			"code": model.ErrorTypeForbidden,
+		},
+	}
+	SessionLimitReached = &gqlerror.Error{
+		Message: "New accounts can only be signed in on a few devices at once, sign out elsewhere and try again",
+		Extensions: map[string]interface{}{
+			"code": model.ErrorTypeForbidden,
+		},
+	}
+
+	InvalidCSRFToken = &gqlerror.Error{
+		Message: "Missing or invalid CSRF token, fetch a new one with the csrfToken query",
+		Extensions: map[string]interface{}{
+			"code": model.ErrorTypeForbidden,
+		},
+	}
 )
@@ -2,6 +2,7 @@ package errors
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/abisalde/authentication-service/internal/graph/model"
 	"github.com/vektah/gqlparser/v2/gqlerror"
@@ -42,3 +43,32 @@ func NewTypedError(message string, code model.ErrorType, extraExtensions map[str
 func InternalServerError(message string, args ...any) error {
 	return &typedError{err: fmt.Errorf(message, args...), errorType: model.ErrorTypeInternalServerError}
 }
+
+// AccountSuspended carries the suspension reason code so clients can point
+// the user at the appeal link sent by email.
+func AccountSuspended(reason string) *gqlerror.Error {
+	return NewTypedError("Your account has been suspended", model.ErrorTypeAccountSuspended, map[string]interface{}{
+		"reason": reason,
+	})
+}
+
+// SessionRevoked tells a client holding a revoked token why it was signed
+// out, so the UI can explain it instead of showing a generic login prompt.
+func SessionRevoked(reason string) *gqlerror.Error {
+	return NewTypedError("Your session has ended, sign in again", model.ErrorTypeSessionRevoked, map[string]interface{}{
+		"reason": reason,
+	})
+}
+
+// QuotaExceeded tells an API client which of its quotas ran out and when it
+// resets, so it can back off until then instead of retrying.
+func QuotaExceeded(dimension model.QuotaDimension, limit int64, resetsAt time.Time) *gqlerror.Error {
+	return NewTypedError("Your API client quota is exhausted", model.ErrorTypeRateLimited, map[string]interface{}{
+		"quota": map[string]interface{}{
+			"dimension": dimension,
+			"limit":     limit,
+			"remaining": 0,
+			"resetsAt":  resetsAt.UTC().Format(time.RFC3339),
+		},
+	})
+}
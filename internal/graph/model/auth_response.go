@@ -1,10 +1,12 @@
 package model
 
 type LoginResponse struct {
-	Token        string `json:"token"`
-	UserId       int64  `json:"userId"`
-	Email        string `json:"email"`
-	RefreshToken string `json:"refreshToken"`
+	Token        string  `json:"token"`
+	UserId       int64   `json:"userId"`
+	PublicId     string  `json:"publicId"`
+	Email        string  `json:"email"`
+	RefreshToken string  `json:"refreshToken"`
+	IDToken      *string `json:"idToken,omitempty"`
 }
 
 type RegisterResponse struct {
@@ -15,24 +15,269 @@ type AccountVerification struct {
 	Email string `json:"email"`
 }
 
+// A device session of the user, one per unexpired access token issued to it
+type ActiveSession struct {
+	// Token ID of the session, to pass to revokeSession
+	ID        string    `json:"id"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	// Whether this is the session making the request
+	Current bool `json:"current"`
+	// Whether the session was used from conflicting networks or devices
+	Suspicious bool `json:"suspicious"`
+}
+
+// Quotas of the API client making the request. Only limited dimensions are
+// listed.
+type APIClientQuota struct {
+	Client string        `json:"client"`
+	Usage  []*QuotaUsage `json:"usage"`
+}
+
+type ChangeEmailInput struct {
+	NewEmail string `json:"newEmail"`
+	// Current password, required for accounts that sign in with one
+	Password *string `json:"password,omitempty"`
+}
+
 type ChangePasswordInput struct {
 	OldPassword        string `json:"oldPassword"`
 	NewPassword        string `json:"newPassword"`
 	ConfirmNewPassword string `json:"confirmNewPassword"`
 }
 
+// An API client the user signed in through, which can act for the user until
+// its access is revoked
+type ConnectedApp struct {
+	Client string `json:"client"`
+	// Scopes of the tokens last issued to the client
+	Scopes    []string  `json:"scopes"`
+	GrantedAt time.Time `json:"grantedAt"`
+	// When the client last signed in or refreshed its token
+	LastUsedAt time.Time `json:"lastUsedAt"`
+}
+
+type CreateServiceAccountInput struct {
+	// Slug of the owning organization
+	Organization string `json:"organization"`
+	Name         string `json:"name"`
+}
+
+// The client behind a sign-in. Fields the client did not reveal are null
+type DeviceInfo struct {
+	Browser        *string     `json:"browser,omitempty"`
+	BrowserVersion *string     `json:"browserVersion,omitempty"`
+	Os             *string     `json:"os,omitempty"`
+	OsVersion      *string     `json:"osVersion,omitempty"`
+	Model          *string     `json:"model,omitempty"`
+	Type           *DeviceType `json:"type,omitempty"`
+	Bot            bool        `json:"bot"`
+}
+
+// Response for the email status pre-check
+type EmailStatus struct {
+	Email  string                  `json:"email"`
+	Status EmailRegistrationStatus `json:"status"`
+}
+
+// Cohort of users forced to sign in again. Filters are combined with AND;
+// an empty input targets every user.
+type ForceReloginInput struct {
+	// Users created before this time
+	CreatedBefore *time.Time `json:"createdBefore,omitempty"`
+	// Users whose last login is before this time, or who never logged in
+	LastLoginBefore *time.Time    `json:"lastLoginBefore,omitempty"`
+	Role            *UserRole     `json:"role,omitempty"`
+	Provider        *AuthProvider `json:"provider,omitempty"`
+	// Users revoked per batch
+	BatchSize *int32 `json:"batchSize,omitempty"`
+	// Pause between batches in milliseconds, throttles Redis and database load
+	PauseMs *int32 `json:"pauseMs,omitempty"`
+	// Count the cohort without revoking anything
+	DryRun *bool `json:"dryRun,omitempty"`
+}
+
+// Memory held by one group of auth keys in Redis
+type KeyspaceUsage struct {
+	// Feature owning the keys, e.g. blacklist or rate_limit
+	Category     string `json:"category"`
+	Pattern      string `json:"pattern"`
+	Keys         int    `json:"keys"`
+	SampledKeys  int    `json:"sampledKeys"`
+	SampledBytes int    `json:"sampledBytes"`
+	// Sampled bytes extrapolated to every key in the category
+	EstimatedBytes int `json:"estimatedBytes"`
+}
+
+type LinkOAuthAccountInput struct {
+	Platform OAuthPlatform `json:"platform"`
+	// A built-in provider. Either this or providerName is required.
+	Provider *OAuthProvider `json:"provider,omitempty"`
+	// The name of a provider registered through configuration, as listed by
+	// /capabilities.
+	ProviderName *string `json:"providerName,omitempty"`
+}
+
+// An OAuth account the user can sign in with
+type LinkedAccount struct {
+	ID       string       `json:"id"`
+	Provider AuthProvider `json:"provider"`
+	// Name of the provider registered through configuration, null for built-in
+	// providers
+	ProviderName *string `json:"providerName,omitempty"`
+	// Email the provider reported when the account was linked
+	Email      *string    `json:"email,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+// One sign-in attempt, successful or not
+type LoginAttempt struct {
+	ID string `json:"id"`
+	// Public ID of the account, null when the email matched no account
+	UserID    *string `json:"userId,omitempty"`
+	Email     string  `json:"email"`
+	IP        string  `json:"ip"`
+	DeviceID  string  `json:"deviceId"`
+	Platform  string  `json:"platform"`
+	UserAgent string  `json:"userAgent"`
+	// Browser, OS and form factor, parsed from the user agent and client hints
+	Device        *DeviceInfo         `json:"device"`
+	Method        LoginMethod         `json:"method"`
+	Outcome       LoginOutcome        `json:"outcome"`
+	FailureReason *LoginFailureReason `json:"failureReason,omitempty"`
+	// 0 for an expected login up to 100 for a very likely malicious one
+	RiskScore int32     `json:"riskScore"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type LoginAttemptConnection struct {
+	Edges    []*LoginAttemptEdge `json:"edges"`
+	PageInfo *PageInfo           `json:"pageInfo"`
+}
+
+type LoginAttemptEdge struct {
+	Node   *LoginAttempt `json:"node"`
+	Cursor string        `json:"cursor"`
+}
+
+// Login history filters, combined with AND
+type LoginAttemptFilter struct {
+	// Public ID of the account
+	UserID  *string       `json:"userId,omitempty"`
+	Email   *string       `json:"email,omitempty"`
+	IP      *string       `json:"ip,omitempty"`
+	Outcome *LoginOutcome `json:"outcome,omitempty"`
+	Since   *time.Time    `json:"since,omitempty"`
+	Until   *time.Time    `json:"until,omitempty"`
+}
+
 type LoginInput struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	// OpenID Connect nonce, echoed in the ID token
+	Nonce *string `json:"nonce,omitempty"`
+	// Required when the client's IP reputation calls for a CAPTCHA
+	CaptchaToken *string `json:"captchaToken,omitempty"`
+}
+
+// Break-glass request for a short-lived token limited to a few admin mutations
+type MaintenanceGrant struct {
+	ID string `json:"id"`
+	// Public ID of the requesting operator
+	OperatorID string   `json:"operatorId"`
+	Operations []string `json:"operations"`
+	Reason     string   `json:"reason"`
+	// Slug of the organization the token is limited to, null for every organization
+	Organization      *string                `json:"organization,omitempty"`
+	Status            MaintenanceGrantStatus `json:"status"`
+	Approvals         int32                  `json:"approvals"`
+	ApprovalsRequired int32                  `json:"approvalsRequired"`
+	CreatedAt         time.Time              `json:"createdAt"`
+	// End of the approval window, or of the token once issued
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type MaintenanceToken struct {
+	Token     string            `json:"token"`
+	ExpiresAt time.Time         `json:"expiresAt"`
+	Grant     *MaintenanceGrant `json:"grant"`
+}
+
+type MaintenanceTokenInput struct {
+	// Admin mutations the token may call, e.g. suspendUser
+	Operations []string `json:"operations"`
+	// Why the token is needed; logged with every approval and every use
+	Reason string `json:"reason"`
+	// Slug of the organization to limit the token to. Its calls may then only
+	// name users and organizations of that tenant.
+	Organization *string `json:"organization,omitempty"`
 }
 
 type Mutation struct {
 }
 
+// Failures of one category, e.g. exchange, userinfo, decode or timeout
+type OAuthErrorCount struct {
+	Category string `json:"category"`
+	Count    int32  `json:"count"`
+}
+
 type OAuthLoginInput struct {
-	Platform OAuthPlatform    `json:"platform"`
-	Provider OAuthProvider    `json:"provider"`
-	Mode     PasswordLessMode `json:"mode"`
+	Platform OAuthPlatform `json:"platform"`
+	// A built-in provider. Either this or providerName is required.
+	Provider *OAuthProvider `json:"provider,omitempty"`
+	// The name of a provider registered through configuration, as listed by
+	// /capabilities.
+	ProviderName *string          `json:"providerName,omitempty"`
+	Mode         PasswordLessMode `json:"mode"`
+}
+
+// Health of an OAuth provider over the sliding health window
+type OAuthProviderHealth struct {
+	Provider      string             `json:"provider"`
+	WindowSeconds int32              `json:"windowSeconds"`
+	Attempts      int32              `json:"attempts"`
+	Failures      int32              `json:"failures"`
+	FailureRate   float64            `json:"failureRate"`
+	AvgLatencyMs  int32              `json:"avgLatencyMs"`
+	P95LatencyMs  int32              `json:"p95LatencyMs"`
+	Errors        []*OAuthErrorCount `json:"errors"`
+	// Sign-in with this provider is advertised as temporarily unavailable
+	Degraded bool `json:"degraded"`
+}
+
+// Onboarding progress for the logged in user
+type OnboardingStatus struct {
+	NextStep       OnboardingStep   `json:"nextStep"`
+	CompletedSteps []OnboardingStep `json:"completedSteps"`
+	// Steps this deployment requires before granting app access
+	RequiredSteps []OnboardingStep `json:"requiredSteps"`
+	IsComplete    bool             `json:"isComplete"`
+}
+
+// Tenant whose branding its members see in auth emails and on redirect pages.
+// Empty branding fields fall back to the default brand.
+type Organization struct {
+	ID           string    `json:"id"`
+	Slug         string    `json:"slug"`
+	Name         string    `json:"name"`
+	ProductName  string    `json:"productName"`
+	LogoURL      string    `json:"logoUrl"`
+	PrimaryColor string    `json:"primaryColor"`
+	SupportEmail string    `json:"supportEmail"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+type OrganizationBrandingInput struct {
+	// Identifies the organization; an existing slug updates its branding
+	Slug        string  `json:"slug"`
+	Name        string  `json:"name"`
+	ProductName *string `json:"productName,omitempty"`
+	LogoURL     *string `json:"logoUrl,omitempty"`
+	// Hex color such as #1a73e8, used for the code and links
+	PrimaryColor *string `json:"primaryColor,omitempty"`
+	SupportEmail *string `json:"supportEmail,omitempty"`
 }
 
 type PageInfo struct {
@@ -50,6 +295,15 @@ type PasswordLessResponse struct {
 type Query struct {
 }
 
+// Use of one quota dimension in its current period
+type QuotaUsage struct {
+	Dimension QuotaDimension `json:"dimension"`
+	Limit     int            `json:"limit"`
+	Used      int            `json:"used"`
+	Remaining int            `json:"remaining"`
+	ResetsAt  time.Time      `json:"resetsAt"`
+}
+
 type RefreshTokenResponse struct {
 	Token string `json:"token"`
 }
@@ -57,20 +311,185 @@ type RefreshTokenResponse struct {
 type RegisterInput struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	// Required when the deployment collects the profile at registration
+	FirstName *string `json:"firstName,omitempty"`
+	// Required when the deployment collects the profile at registration
+	LastName *string `json:"lastName,omitempty"`
+	// Required when the deployment collects terms acceptance at registration
+	AcceptTerms *bool `json:"acceptTerms,omitempty"`
+	// ISO 3166-1 alpha-2 signup country, decides the consent requirements
+	Country *string `json:"country,omitempty"`
+	// Required when the signup country sets a minimum age; not stored
+	DateOfBirth *time.Time `json:"dateOfBirth,omitempty"`
+	// Defaults to the signup country's default when omitted
+	MarketingOptIn *bool `json:"marketingOptIn,omitempty"`
+	// Terms of service variant the user accepted, see registrationRequirements
+	TermsVersion *string `json:"termsVersion,omitempty"`
+	// Required when the client's IP reputation calls for a CAPTCHA
+	CaptchaToken *string `json:"captchaToken,omitempty"`
+}
+
+// Consents registration requires for a signup country
+type RegistrationRequirements struct {
+	// Null when the default requirements apply
+	Country *string `json:"country,omitempty"`
+	// Zero when no date of birth is required
+	MinimumAge            int32 `json:"minimumAge"`
+	MarketingOptInDefault bool  `json:"marketingOptInDefault"`
+	// Terms variant to show and send back as termsVersion, null when none is required
+	TermsVersion *string `json:"termsVersion,omitempty"`
+}
+
+// Progress of a forced re-login campaign
+type ReloginCampaign struct {
+	ID     string                `json:"id"`
+	Status ReloginCampaignStatus `json:"status"`
+	DryRun bool                  `json:"dryRun"`
+	// Users in the cohort
+	Matched int `json:"matched"`
+	// Users whose tokens have been revoked so far
+	Processed  int        `json:"processed"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	Error      *string    `json:"error,omitempty"`
 }
 
 type ResendVerificationCode struct {
 	Email string `json:"email"`
 }
 
+type ScheduleAccountActionInput struct {
+	// Public ID or legacy numeric ID of the account
+	UserID string            `json:"userId"`
+	Action AccountActionType `json:"action"`
+	// When the action runs; it must be in the future
+	RunAt time.Time `json:"runAt"`
+	Note  *string   `json:"note,omitempty"`
+}
+
+// Admin action on an account that runs at a later date, e.g. revoking the
+// sessions of a contractor when their contract ends
+type ScheduledAccountAction struct {
+	ID string `json:"id"`
+	// Public ID of the account
+	UserID string                `json:"userId"`
+	Action AccountActionType     `json:"action"`
+	RunAt  time.Time             `json:"runAt"`
+	Status ScheduledActionStatus `json:"status"`
+	Note   *string               `json:"note,omitempty"`
+	// Public ID of the admin who scheduled the action
+	CreatedBy *string `json:"createdBy,omitempty"`
+	// Public ID of the admin who cancelled the action
+	CancelledBy *string `json:"cancelledBy,omitempty"`
+	// Why the action failed
+	Error     *string   `json:"error,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	// When the action completed, failed or was cancelled
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// One item of the security checkup
+type SecurityCheck struct {
+	Kind   SecurityCheckKind   `json:"kind"`
+	Status SecurityCheckStatus `json:"status"`
+	// Points the check is worth: all of them on PASS, half on WARN
+	Weight int32 `json:"weight"`
+}
+
+// Security health of the logged in user's account, for a security settings
+// screen
+type SecurityCheckup struct {
+	// 0 to 100, the share of the points of the available checks earned
+	Score  int32            `json:"score"`
+	Checks []*SecurityCheck `json:"checks"`
+	// Null for accounts without a password and passwords set before it was recorded
+	PasswordChangedAt *time.Time `json:"passwordChangedAt,omitempty"`
+	PasswordAgeDays   *int32     `json:"passwordAgeDays,omitempty"`
+	// Always false: the service does not offer two-factor authentication yet
+	TwoFactorEnabled bool `json:"twoFactorEnabled"`
+	// Refresh sessions that can still issue access tokens
+	ActiveSessions int32 `json:"activeSessions"`
+	// Devices that signed in within 30 days from neither a known network nor a known device
+	UnknownDevices int32 `json:"unknownDevices"`
+	// Always false: the service does not offer recovery emails yet
+	HasRecoveryEmail bool `json:"hasRecoveryEmail"`
+	// Failed password attempts and risky sign-ins within 30 days
+	RecentSuspiciousEvents int32 `json:"recentSuspiciousEvents"`
+}
+
+// Non-human account an organization's automations sign in with. It has no
+// password and never receives email.
+type ServiceAccount struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Slug of the owning organization
+	Organization string               `json:"organization"`
+	Keys         []*ServiceAccountKey `json:"keys"`
+	CreatedAt    time.Time            `json:"createdAt"`
+}
+
+// A new key. The secret is only returned here and cannot be retrieved again.
+type ServiceAccountCredentials struct {
+	Account *ServiceAccount `json:"account"`
+	KeyID   string          `json:"keyId"`
+	Secret  string          `json:"secret"`
+}
+
+// Credential a service account trades for access tokens at
+// /service-accounts/token, sent as the client_id with its secret
+type ServiceAccountKey struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Set once the key was rotated out; the key stops working then
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+// Appeal submitted with the link from a suspension email
+type SuspensionAppeal struct {
+	UserID      string           `json:"userId"`
+	Reason      SuspensionReason `json:"reason"`
+	Message     string           `json:"message"`
+	SubmittedAt time.Time        `json:"submittedAt"`
+}
+
+// Token issuance and validation counters aggregated over the requested hours
+type TokenStats struct {
+	Hours            int32 `json:"hours"`
+	AccessIssued     int32 `json:"accessIssued"`
+	RefreshIssued    int32 `json:"refreshIssued"`
+	RefreshSucceeded int32 `json:"refreshSucceeded"`
+	RefreshFailed    int32 `json:"refreshFailed"`
+	// Access tokens blacklisted at logout that have not expired yet
+	BlacklistSize    int                          `json:"blacklistSize"`
+	ValidationErrors []*TokenValidationErrorCount `json:"validationErrors"`
+	PerHour          []*TokenStatsHour            `json:"perHour"`
+}
+
+// Token counters of one UTC hour
+type TokenStatsHour struct {
+	Hour             time.Time `json:"hour"`
+	AccessIssued     int32     `json:"accessIssued"`
+	RefreshIssued    int32     `json:"refreshIssued"`
+	RefreshSucceeded int32     `json:"refreshSucceeded"`
+	RefreshFailed    int32     `json:"refreshFailed"`
+	ValidationErrors int32     `json:"validationErrors"`
+}
+
+// Rejected tokens of one reason: invalid, expired, wrong_type, blacklisted,
+// revoked or unknown_user
+type TokenValidationErrorCount struct {
+	Reason string `json:"reason"`
+	Count  int32  `json:"count"`
+}
+
 type UpdateProfileInput struct {
-	FirstName       string            `json:"firstName"`
-	LastName        string            `json:"lastName"`
-	Username        *string           `json:"username,omitempty"`
-	Address         *UserAddressInput `json:"address,omitempty"`
-	PhoneNumber     *string           `json:"phoneNumber,omitempty"`
-	MarketingOptIn  bool              `json:"marketingOptIn"`
-	TermsAcceptedAt *time.Time        `json:"termsAcceptedAt,omitempty"`
+	FirstName      string            `json:"firstName"`
+	LastName       string            `json:"lastName"`
+	Username       *string           `json:"username,omitempty"`
+	Address        *UserAddressInput `json:"address,omitempty"`
+	PhoneNumber    *string           `json:"phoneNumber,omitempty"`
+	MarketingOptIn bool              `json:"marketingOptIn"`
 }
 
 // Represents a user's address.
@@ -111,23 +530,110 @@ type UsernameAvailability struct {
 	Username  string `json:"username"`
 }
 
+// One attempt to post an event to the refresh reminder webhook
+type WebhookDelivery struct {
+	ID        string `json:"id"`
+	EventType string `json:"eventType"`
+	URL       string `json:"url"`
+	// HTTP status of the receiver's response, null when none came back
+	StatusCode *int32  `json:"statusCode,omitempty"`
+	LatencyMs  int32   `json:"latencyMs"`
+	Error      *string `json:"error,omitempty"`
+	// Sent with sendTestWebhook, or a redelivery of such an event
+	Test bool `json:"test"`
+	// Delivery whose event this one sent again
+	RedeliveryOf *string   `json:"redeliveryOf,omitempty"`
+	DeliveredAt  time.Time `json:"deliveredAt"`
+	// JSON body that was posted
+	Payload string `json:"payload"`
+}
+
+type AccountActionType string
+
+const (
+	// Deactivate the account; the owner restores it by signing in again
+	AccountActionTypeDeactivate AccountActionType = "DEACTIVATE"
+	// Sign the user out on every device
+	AccountActionTypeRevokeSessions AccountActionType = "REVOKE_SESSIONS"
+	// Soft delete the account, which is purged after the deletion retention
+	AccountActionTypeDelete AccountActionType = "DELETE"
+)
+
+var AllAccountActionType = []AccountActionType{
+	AccountActionTypeDeactivate,
+	AccountActionTypeRevokeSessions,
+	AccountActionTypeDelete,
+}
+
+func (e AccountActionType) IsValid() bool {
+	switch e {
+	case AccountActionTypeDeactivate, AccountActionTypeRevokeSessions, AccountActionTypeDelete:
+		return true
+	}
+	return false
+}
+
+func (e AccountActionType) String() string {
+	return string(e)
+}
+
+func (e *AccountActionType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AccountActionType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AccountActionType", str)
+	}
+	return nil
+}
+
+func (e AccountActionType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *AccountActionType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e AccountActionType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
 type AuthProvider string
 
 const (
-	AuthProviderEmail    AuthProvider = "EMAIL"
-	AuthProviderGoogle   AuthProvider = "GOOGLE"
-	AuthProviderFacebook AuthProvider = "FACEBOOK"
+	AuthProviderEmail     AuthProvider = "EMAIL"
+	AuthProviderGoogle    AuthProvider = "GOOGLE"
+	AuthProviderFacebook  AuthProvider = "FACEBOOK"
+	AuthProviderApple     AuthProvider = "APPLE"
+	AuthProviderGithub    AuthProvider = "GITHUB"
+	AuthProviderMicrosoft AuthProvider = "MICROSOFT"
+	// A provider registered through configuration.
+	AuthProviderOauth AuthProvider = "OAUTH"
 )
 
 var AllAuthProvider = []AuthProvider{
 	AuthProviderEmail,
 	AuthProviderGoogle,
 	AuthProviderFacebook,
+	AuthProviderApple,
+	AuthProviderGithub,
+	AuthProviderMicrosoft,
+	AuthProviderOauth,
 }
 
 func (e AuthProvider) IsValid() bool {
 	switch e {
-	case AuthProviderEmail, AuthProviderGoogle, AuthProviderFacebook:
+	case AuthProviderEmail, AuthProviderGoogle, AuthProviderFacebook, AuthProviderApple, AuthProviderGithub, AuthProviderMicrosoft, AuthProviderOauth:
 		return true
 	}
 	return false
@@ -168,23 +674,152 @@ func (e AuthProvider) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+type DeviceType string
+
+const (
+	DeviceTypeDesktop DeviceType = "DESKTOP"
+	DeviceTypeMobile  DeviceType = "MOBILE"
+	DeviceTypeTablet  DeviceType = "TABLET"
+	DeviceTypeBot     DeviceType = "BOT"
+	DeviceTypeOther   DeviceType = "OTHER"
+)
+
+var AllDeviceType = []DeviceType{
+	DeviceTypeDesktop,
+	DeviceTypeMobile,
+	DeviceTypeTablet,
+	DeviceTypeBot,
+	DeviceTypeOther,
+}
+
+func (e DeviceType) IsValid() bool {
+	switch e {
+	case DeviceTypeDesktop, DeviceTypeMobile, DeviceTypeTablet, DeviceTypeBot, DeviceTypeOther:
+		return true
+	}
+	return false
+}
+
+func (e DeviceType) String() string {
+	return string(e)
+}
+
+func (e *DeviceType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DeviceType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DeviceType", str)
+	}
+	return nil
+}
+
+func (e DeviceType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *DeviceType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e DeviceType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// Registration state of an email address
+type EmailRegistrationStatus string
+
+const (
+	EmailRegistrationStatusAvailable  EmailRegistrationStatus = "AVAILABLE"
+	EmailRegistrationStatusRegistered EmailRegistrationStatus = "REGISTERED"
+	// Registered but the verification code was never confirmed
+	EmailRegistrationStatusPendingVerification EmailRegistrationStatus = "PENDING_VERIFICATION"
+	// Returned for every email when the deployment hides account existence
+	EmailRegistrationStatusUnknown EmailRegistrationStatus = "UNKNOWN"
+)
+
+var AllEmailRegistrationStatus = []EmailRegistrationStatus{
+	EmailRegistrationStatusAvailable,
+	EmailRegistrationStatusRegistered,
+	EmailRegistrationStatusPendingVerification,
+	EmailRegistrationStatusUnknown,
+}
+
+func (e EmailRegistrationStatus) IsValid() bool {
+	switch e {
+	case EmailRegistrationStatusAvailable, EmailRegistrationStatusRegistered, EmailRegistrationStatusPendingVerification, EmailRegistrationStatusUnknown:
+		return true
+	}
+	return false
+}
+
+func (e EmailRegistrationStatus) String() string {
+	return string(e)
+}
+
+func (e *EmailRegistrationStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = EmailRegistrationStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid EmailRegistrationStatus", str)
+	}
+	return nil
+}
+
+func (e EmailRegistrationStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *EmailRegistrationStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e EmailRegistrationStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
 type ErrorType string
 
 const (
-	ErrorTypeInternalServerError ErrorType = "INTERNAL_SERVER_ERROR"
-	ErrorTypeNotFound            ErrorType = "NOT_FOUND"
-	ErrorTypeBadRequest          ErrorType = "BAD_REQUEST"
-	ErrorTypeForbidden           ErrorType = "FORBIDDEN"
-	ErrorTypeConflict            ErrorType = "CONFLICT"
-	ErrorTypeRateLimited         ErrorType = "RATE_LIMITED"
-	ErrorTypePassword            ErrorType = "PASSWORD"
-	ErrorTypeEmail               ErrorType = "EMAIL"
-	ErrorTypeEmailExists         ErrorType = "EMAIL_EXISTS"
-	ErrorTypeWeakPassword        ErrorType = "WEAK_PASSWORD"
-	ErrorTypeInvalidInput        ErrorType = "INVALID_INPUT"
-	ErrorTypeToken               ErrorType = "TOKEN"
-	ErrorTypeUnauthenticated     ErrorType = "UNAUTHENTICATED"
-	ErrorTypeRefreshToken        ErrorType = "REFRESH_TOKEN"
+	ErrorTypeInternalServerError      ErrorType = "INTERNAL_SERVER_ERROR"
+	ErrorTypeNotFound                 ErrorType = "NOT_FOUND"
+	ErrorTypeBadRequest               ErrorType = "BAD_REQUEST"
+	ErrorTypeForbidden                ErrorType = "FORBIDDEN"
+	ErrorTypeConflict                 ErrorType = "CONFLICT"
+	ErrorTypeRateLimited              ErrorType = "RATE_LIMITED"
+	ErrorTypePassword                 ErrorType = "PASSWORD"
+	ErrorTypeEmail                    ErrorType = "EMAIL"
+	ErrorTypeEmailExists              ErrorType = "EMAIL_EXISTS"
+	ErrorTypeWeakPassword             ErrorType = "WEAK_PASSWORD"
+	ErrorTypeInvalidInput             ErrorType = "INVALID_INPUT"
+	ErrorTypeToken                    ErrorType = "TOKEN"
+	ErrorTypeUnauthenticated          ErrorType = "UNAUTHENTICATED"
+	ErrorTypeRefreshToken             ErrorType = "REFRESH_TOKEN"
+	ErrorTypeOnboardingRequired       ErrorType = "ONBOARDING_REQUIRED"
+	ErrorTypePayloadTooLarge          ErrorType = "PAYLOAD_TOO_LARGE"
+	ErrorTypeAccountSuspended         ErrorType = "ACCOUNT_SUSPENDED"
+	ErrorTypeReauthenticationRequired ErrorType = "REAUTHENTICATION_REQUIRED"
+	ErrorTypeSessionRevoked           ErrorType = "SESSION_REVOKED"
+	ErrorTypeOverloaded               ErrorType = "OVERLOADED"
 )
 
 var AllErrorType = []ErrorType{
@@ -202,11 +837,17 @@ var AllErrorType = []ErrorType{
 	ErrorTypeToken,
 	ErrorTypeUnauthenticated,
 	ErrorTypeRefreshToken,
+	ErrorTypeOnboardingRequired,
+	ErrorTypePayloadTooLarge,
+	ErrorTypeAccountSuspended,
+	ErrorTypeReauthenticationRequired,
+	ErrorTypeSessionRevoked,
+	ErrorTypeOverloaded,
 }
 
 func (e ErrorType) IsValid() bool {
 	switch e {
-	case ErrorTypeInternalServerError, ErrorTypeNotFound, ErrorTypeBadRequest, ErrorTypeForbidden, ErrorTypeConflict, ErrorTypeRateLimited, ErrorTypePassword, ErrorTypeEmail, ErrorTypeEmailExists, ErrorTypeWeakPassword, ErrorTypeInvalidInput, ErrorTypeToken, ErrorTypeUnauthenticated, ErrorTypeRefreshToken:
+	case ErrorTypeInternalServerError, ErrorTypeNotFound, ErrorTypeBadRequest, ErrorTypeForbidden, ErrorTypeConflict, ErrorTypeRateLimited, ErrorTypePassword, ErrorTypeEmail, ErrorTypeEmailExists, ErrorTypeWeakPassword, ErrorTypeInvalidInput, ErrorTypeToken, ErrorTypeUnauthenticated, ErrorTypeRefreshToken, ErrorTypeOnboardingRequired, ErrorTypePayloadTooLarge, ErrorTypeAccountSuspended, ErrorTypeReauthenticationRequired, ErrorTypeSessionRevoked, ErrorTypeOverloaded:
 		return true
 	}
 	return false
@@ -247,48 +888,54 @@ func (e ErrorType) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-type OAuthPlatform string
+type LoginFailureReason string
 
 const (
-	OAuthPlatformWeb    OAuthPlatform = "WEB"
-	OAuthPlatformMobile OAuthPlatform = "MOBILE"
+	LoginFailureReasonUnknownAccount   LoginFailureReason = "UNKNOWN_ACCOUNT"
+	LoginFailureReasonInvalidPassword  LoginFailureReason = "INVALID_PASSWORD"
+	LoginFailureReasonOauthFailed      LoginFailureReason = "OAUTH_FAILED"
+	LoginFailureReasonAccountSuspended LoginFailureReason = "ACCOUNT_SUSPENDED"
+	LoginFailureReasonInternalError    LoginFailureReason = "INTERNAL_ERROR"
 )
 
-var AllOAuthPlatform = []OAuthPlatform{
-	OAuthPlatformWeb,
-	OAuthPlatformMobile,
+var AllLoginFailureReason = []LoginFailureReason{
+	LoginFailureReasonUnknownAccount,
+	LoginFailureReasonInvalidPassword,
+	LoginFailureReasonOauthFailed,
+	LoginFailureReasonAccountSuspended,
+	LoginFailureReasonInternalError,
 }
 
-func (e OAuthPlatform) IsValid() bool {
+func (e LoginFailureReason) IsValid() bool {
 	switch e {
-	case OAuthPlatformWeb, OAuthPlatformMobile:
+	case LoginFailureReasonUnknownAccount, LoginFailureReasonInvalidPassword, LoginFailureReasonOauthFailed, LoginFailureReasonAccountSuspended, LoginFailureReasonInternalError:
 		return true
 	}
 	return false
 }
 
-func (e OAuthPlatform) String() string {
+func (e LoginFailureReason) String() string {
 	return string(e)
 }
 
-func (e *OAuthPlatform) UnmarshalGQL(v any) error {
+func (e *LoginFailureReason) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = OAuthPlatform(str)
+	*e = LoginFailureReason(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid OAuthPlatform", str)
+		return fmt.Errorf("%s is not a valid LoginFailureReason", str)
 	}
 	return nil
 }
 
-func (e OAuthPlatform) MarshalGQL(w io.Writer) {
+func (e LoginFailureReason) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *OAuthPlatform) UnmarshalJSON(b []byte) error {
+func (e *LoginFailureReason) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -296,55 +943,54 @@ func (e *OAuthPlatform) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e OAuthPlatform) MarshalJSON() ([]byte, error) {
+func (e LoginFailureReason) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-// Login & OAUTH/PasswordLess
-type OAuthProvider string
+type LoginMethod string
 
 const (
-	OAuthProviderGoogle   OAuthProvider = "GOOGLE"
-	OAuthProviderFacebook OAuthProvider = "FACEBOOK"
+	LoginMethodPassword LoginMethod = "PASSWORD"
+	LoginMethodOauth    LoginMethod = "OAUTH"
 )
 
-var AllOAuthProvider = []OAuthProvider{
-	OAuthProviderGoogle,
-	OAuthProviderFacebook,
+var AllLoginMethod = []LoginMethod{
+	LoginMethodPassword,
+	LoginMethodOauth,
 }
 
-func (e OAuthProvider) IsValid() bool {
+func (e LoginMethod) IsValid() bool {
 	switch e {
-	case OAuthProviderGoogle, OAuthProviderFacebook:
+	case LoginMethodPassword, LoginMethodOauth:
 		return true
 	}
 	return false
 }
 
-func (e OAuthProvider) String() string {
+func (e LoginMethod) String() string {
 	return string(e)
 }
 
-func (e *OAuthProvider) UnmarshalGQL(v any) error {
+func (e *LoginMethod) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = OAuthProvider(str)
+	*e = LoginMethod(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid OAuthProvider", str)
+		return fmt.Errorf("%s is not a valid LoginMethod", str)
 	}
 	return nil
 }
 
-func (e OAuthProvider) MarshalGQL(w io.Writer) {
+func (e LoginMethod) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *OAuthProvider) UnmarshalJSON(b []byte) error {
+func (e *LoginMethod) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -352,54 +998,54 @@ func (e *OAuthProvider) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e OAuthProvider) MarshalJSON() ([]byte, error) {
+func (e LoginMethod) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type PasswordLessMode string
+type LoginOutcome string
 
 const (
-	PasswordLessModeLogin    PasswordLessMode = "LOGIN"
-	PasswordLessModeRegister PasswordLessMode = "REGISTER"
+	LoginOutcomeSuccess LoginOutcome = "SUCCESS"
+	LoginOutcomeFailure LoginOutcome = "FAILURE"
 )
 
-var AllPasswordLessMode = []PasswordLessMode{
-	PasswordLessModeLogin,
-	PasswordLessModeRegister,
+var AllLoginOutcome = []LoginOutcome{
+	LoginOutcomeSuccess,
+	LoginOutcomeFailure,
 }
 
-func (e PasswordLessMode) IsValid() bool {
+func (e LoginOutcome) IsValid() bool {
 	switch e {
-	case PasswordLessModeLogin, PasswordLessModeRegister:
+	case LoginOutcomeSuccess, LoginOutcomeFailure:
 		return true
 	}
 	return false
 }
 
-func (e PasswordLessMode) String() string {
+func (e LoginOutcome) String() string {
 	return string(e)
 }
 
-func (e *PasswordLessMode) UnmarshalGQL(v any) error {
+func (e *LoginOutcome) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = PasswordLessMode(str)
+	*e = LoginOutcome(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid PasswordLessMode", str)
+		return fmt.Errorf("%s is not a valid LoginOutcome", str)
 	}
 	return nil
 }
 
-func (e PasswordLessMode) MarshalGQL(w io.Writer) {
+func (e LoginOutcome) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *PasswordLessMode) UnmarshalJSON(b []byte) error {
+func (e *LoginOutcome) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -407,65 +1053,56 @@ func (e *PasswordLessMode) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e PasswordLessMode) MarshalJSON() ([]byte, error) {
+func (e LoginOutcome) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-// Rate Limit Methods enum
-type RateLimitMethods string
+type MaintenanceGrantStatus string
 
 const (
-	RateLimitMethodsLogin                  RateLimitMethods = "LOGIN"
-	RateLimitMethodsRegister               RateLimitMethods = "REGISTER"
-	RateLimitMethodsUpdateProfile          RateLimitMethods = "UPDATE_PROFILE"
-	RateLimitMethodsChangePassword         RateLimitMethods = "CHANGE_PASSWORD"
-	RateLimitMethodsVerifyAccount          RateLimitMethods = "VERIFY_ACCOUNT"
-	RateLimitMethodsResendVerificationCode RateLimitMethods = "RESEND_VERIFICATION_CODE"
-	RateLimitMethodsRefreshToken           RateLimitMethods = "REFRESH_TOKEN"
+	MaintenanceGrantStatusPending  MaintenanceGrantStatus = "PENDING"
+	MaintenanceGrantStatusApproved MaintenanceGrantStatus = "APPROVED"
+	MaintenanceGrantStatusIssued   MaintenanceGrantStatus = "ISSUED"
 )
 
-var AllRateLimitMethods = []RateLimitMethods{
-	RateLimitMethodsLogin,
-	RateLimitMethodsRegister,
-	RateLimitMethodsUpdateProfile,
-	RateLimitMethodsChangePassword,
-	RateLimitMethodsVerifyAccount,
-	RateLimitMethodsResendVerificationCode,
-	RateLimitMethodsRefreshToken,
+var AllMaintenanceGrantStatus = []MaintenanceGrantStatus{
+	MaintenanceGrantStatusPending,
+	MaintenanceGrantStatusApproved,
+	MaintenanceGrantStatusIssued,
 }
 
-func (e RateLimitMethods) IsValid() bool {
+func (e MaintenanceGrantStatus) IsValid() bool {
 	switch e {
-	case RateLimitMethodsLogin, RateLimitMethodsRegister, RateLimitMethodsUpdateProfile, RateLimitMethodsChangePassword, RateLimitMethodsVerifyAccount, RateLimitMethodsResendVerificationCode, RateLimitMethodsRefreshToken:
+	case MaintenanceGrantStatusPending, MaintenanceGrantStatusApproved, MaintenanceGrantStatusIssued:
 		return true
 	}
 	return false
 }
 
-func (e RateLimitMethods) String() string {
+func (e MaintenanceGrantStatus) String() string {
 	return string(e)
 }
 
-func (e *RateLimitMethods) UnmarshalGQL(v any) error {
+func (e *MaintenanceGrantStatus) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = RateLimitMethods(str)
+	*e = MaintenanceGrantStatus(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid RateLimitMethods", str)
+		return fmt.Errorf("%s is not a valid MaintenanceGrantStatus", str)
 	}
 	return nil
 }
 
-func (e RateLimitMethods) MarshalGQL(w io.Writer) {
+func (e MaintenanceGrantStatus) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *RateLimitMethods) UnmarshalJSON(b []byte) error {
+func (e *MaintenanceGrantStatus) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -473,7 +1110,907 @@ func (e *RateLimitMethods) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e RateLimitMethods) MarshalJSON() ([]byte, error) {
+func (e MaintenanceGrantStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type OAuthPlatform string
+
+const (
+	OAuthPlatformWeb    OAuthPlatform = "WEB"
+	OAuthPlatformMobile OAuthPlatform = "MOBILE"
+)
+
+var AllOAuthPlatform = []OAuthPlatform{
+	OAuthPlatformWeb,
+	OAuthPlatformMobile,
+}
+
+func (e OAuthPlatform) IsValid() bool {
+	switch e {
+	case OAuthPlatformWeb, OAuthPlatformMobile:
+		return true
+	}
+	return false
+}
+
+func (e OAuthPlatform) String() string {
+	return string(e)
+}
+
+func (e *OAuthPlatform) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OAuthPlatform(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OAuthPlatform", str)
+	}
+	return nil
+}
+
+func (e OAuthPlatform) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *OAuthPlatform) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e OAuthPlatform) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// Login & OAUTH/PasswordLess
+type OAuthProvider string
+
+const (
+	OAuthProviderGoogle    OAuthProvider = "GOOGLE"
+	OAuthProviderFacebook  OAuthProvider = "FACEBOOK"
+	OAuthProviderApple     OAuthProvider = "APPLE"
+	OAuthProviderGithub    OAuthProvider = "GITHUB"
+	OAuthProviderMicrosoft OAuthProvider = "MICROSOFT"
+)
+
+var AllOAuthProvider = []OAuthProvider{
+	OAuthProviderGoogle,
+	OAuthProviderFacebook,
+	OAuthProviderApple,
+	OAuthProviderGithub,
+	OAuthProviderMicrosoft,
+}
+
+func (e OAuthProvider) IsValid() bool {
+	switch e {
+	case OAuthProviderGoogle, OAuthProviderFacebook, OAuthProviderApple, OAuthProviderGithub, OAuthProviderMicrosoft:
+		return true
+	}
+	return false
+}
+
+func (e OAuthProvider) String() string {
+	return string(e)
+}
+
+func (e *OAuthProvider) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OAuthProvider(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OAuthProvider", str)
+	}
+	return nil
+}
+
+func (e OAuthProvider) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *OAuthProvider) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e OAuthProvider) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// Onboarding steps in the order they must be completed
+type OnboardingStep string
+
+const (
+	OnboardingStepVerifyEmail     OnboardingStep = "VERIFY_EMAIL"
+	OnboardingStepCompleteProfile OnboardingStep = "COMPLETE_PROFILE"
+	OnboardingStepAcceptTerms     OnboardingStep = "ACCEPT_TERMS"
+	OnboardingStepDone            OnboardingStep = "DONE"
+)
+
+var AllOnboardingStep = []OnboardingStep{
+	OnboardingStepVerifyEmail,
+	OnboardingStepCompleteProfile,
+	OnboardingStepAcceptTerms,
+	OnboardingStepDone,
+}
+
+func (e OnboardingStep) IsValid() bool {
+	switch e {
+	case OnboardingStepVerifyEmail, OnboardingStepCompleteProfile, OnboardingStepAcceptTerms, OnboardingStepDone:
+		return true
+	}
+	return false
+}
+
+func (e OnboardingStep) String() string {
+	return string(e)
+}
+
+func (e *OnboardingStep) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OnboardingStep(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OnboardingStep", str)
+	}
+	return nil
+}
+
+func (e OnboardingStep) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *OnboardingStep) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e OnboardingStep) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type PasswordLessMode string
+
+const (
+	PasswordLessModeLogin    PasswordLessMode = "LOGIN"
+	PasswordLessModeRegister PasswordLessMode = "REGISTER"
+)
+
+var AllPasswordLessMode = []PasswordLessMode{
+	PasswordLessModeLogin,
+	PasswordLessModeRegister,
+}
+
+func (e PasswordLessMode) IsValid() bool {
+	switch e {
+	case PasswordLessModeLogin, PasswordLessModeRegister:
+		return true
+	}
+	return false
+}
+
+func (e PasswordLessMode) String() string {
+	return string(e)
+}
+
+func (e *PasswordLessMode) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PasswordLessMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PasswordLessMode", str)
+	}
+	return nil
+}
+
+func (e PasswordLessMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *PasswordLessMode) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e PasswordLessMode) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// What an API client quota counts
+type QuotaDimension string
+
+const (
+	// Requests, rolling over at midnight UTC
+	QuotaDimensionRequestsPerDay QuotaDimension = "REQUESTS_PER_DAY"
+	// Requests, rolling over on the first of the month UTC
+	QuotaDimensionRequestsPerMonth QuotaDimension = "REQUESTS_PER_MONTH"
+	// Sign-in attempts, rolling over every hour
+	QuotaDimensionLoginsPerHour QuotaDimension = "LOGINS_PER_HOUR"
+)
+
+var AllQuotaDimension = []QuotaDimension{
+	QuotaDimensionRequestsPerDay,
+	QuotaDimensionRequestsPerMonth,
+	QuotaDimensionLoginsPerHour,
+}
+
+func (e QuotaDimension) IsValid() bool {
+	switch e {
+	case QuotaDimensionRequestsPerDay, QuotaDimensionRequestsPerMonth, QuotaDimensionLoginsPerHour:
+		return true
+	}
+	return false
+}
+
+func (e QuotaDimension) String() string {
+	return string(e)
+}
+
+func (e *QuotaDimension) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = QuotaDimension(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid QuotaDimension", str)
+	}
+	return nil
+}
+
+func (e QuotaDimension) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *QuotaDimension) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e QuotaDimension) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// How a rate limit counts requests
+type RateLimitAlgorithm string
+
+const (
+	RateLimitAlgorithmFixedWindow   RateLimitAlgorithm = "FIXED_WINDOW"
+	RateLimitAlgorithmSlidingWindow RateLimitAlgorithm = "SLIDING_WINDOW"
+	RateLimitAlgorithmTokenBucket   RateLimitAlgorithm = "TOKEN_BUCKET"
+)
+
+var AllRateLimitAlgorithm = []RateLimitAlgorithm{
+	RateLimitAlgorithmFixedWindow,
+	RateLimitAlgorithmSlidingWindow,
+	RateLimitAlgorithmTokenBucket,
+}
+
+func (e RateLimitAlgorithm) IsValid() bool {
+	switch e {
+	case RateLimitAlgorithmFixedWindow, RateLimitAlgorithmSlidingWindow, RateLimitAlgorithmTokenBucket:
+		return true
+	}
+	return false
+}
+
+func (e RateLimitAlgorithm) String() string {
+	return string(e)
+}
+
+func (e *RateLimitAlgorithm) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RateLimitAlgorithm(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RateLimitAlgorithm", str)
+	}
+	return nil
+}
+
+func (e RateLimitAlgorithm) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *RateLimitAlgorithm) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e RateLimitAlgorithm) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// What a rate limit counts requests against
+type RateLimitKey string
+
+const (
+	// The signed-in user, or the client IP for anonymous requests
+	RateLimitKeyClient RateLimitKey = "CLIENT"
+	RateLimitKeyIP     RateLimitKey = "IP"
+	// The email argument of the operation, or the client when there is none
+	RateLimitKeyEmail RateLimitKey = "EMAIL"
+	// Every request to the operation
+	RateLimitKeyGlobal RateLimitKey = "GLOBAL"
+)
+
+var AllRateLimitKey = []RateLimitKey{
+	RateLimitKeyClient,
+	RateLimitKeyIP,
+	RateLimitKeyEmail,
+	RateLimitKeyGlobal,
+}
+
+func (e RateLimitKey) IsValid() bool {
+	switch e {
+	case RateLimitKeyClient, RateLimitKeyIP, RateLimitKeyEmail, RateLimitKeyGlobal:
+		return true
+	}
+	return false
+}
+
+func (e RateLimitKey) String() string {
+	return string(e)
+}
+
+func (e *RateLimitKey) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RateLimitKey(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RateLimitKey", str)
+	}
+	return nil
+}
+
+func (e RateLimitKey) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *RateLimitKey) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e RateLimitKey) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// Rate Limit Methods enum
+type RateLimitMethods string
+
+const (
+	RateLimitMethodsLogin                     RateLimitMethods = "LOGIN"
+	RateLimitMethodsRegister                  RateLimitMethods = "REGISTER"
+	RateLimitMethodsUpdateProfile             RateLimitMethods = "UPDATE_PROFILE"
+	RateLimitMethodsChangePassword            RateLimitMethods = "CHANGE_PASSWORD"
+	RateLimitMethodsVerifyAccount             RateLimitMethods = "VERIFY_ACCOUNT"
+	RateLimitMethodsResendVerificationCode    RateLimitMethods = "RESEND_VERIFICATION_CODE"
+	RateLimitMethodsRefreshToken              RateLimitMethods = "REFRESH_TOKEN"
+	RateLimitMethodsEmailStatus               RateLimitMethods = "EMAIL_STATUS"
+	RateLimitMethodsAppealSuspension          RateLimitMethods = "APPEAL_SUSPENSION"
+	RateLimitMethodsChangeEmail               RateLimitMethods = "CHANGE_EMAIL"
+	RateLimitMethodsCloseAccount              RateLimitMethods = "CLOSE_ACCOUNT"
+	RateLimitMethodsRevokeUnrecognizedSignIn  RateLimitMethods = "REVOKE_UNRECOGNIZED_SIGN_IN"
+	RateLimitMethodsStopVerificationReminders RateLimitMethods = "STOP_VERIFICATION_REMINDERS"
+	// The /api/capabilities endpoint
+	RateLimitMethodsCapabilities RateLimitMethods = "CAPABILITIES"
+	// The /api/branding endpoint
+	RateLimitMethodsBranding RateLimitMethods = "BRANDING"
+)
+
+var AllRateLimitMethods = []RateLimitMethods{
+	RateLimitMethodsLogin,
+	RateLimitMethodsRegister,
+	RateLimitMethodsUpdateProfile,
+	RateLimitMethodsChangePassword,
+	RateLimitMethodsVerifyAccount,
+	RateLimitMethodsResendVerificationCode,
+	RateLimitMethodsRefreshToken,
+	RateLimitMethodsEmailStatus,
+	RateLimitMethodsAppealSuspension,
+	RateLimitMethodsChangeEmail,
+	RateLimitMethodsCloseAccount,
+	RateLimitMethodsRevokeUnrecognizedSignIn,
+	RateLimitMethodsStopVerificationReminders,
+	RateLimitMethodsCapabilities,
+	RateLimitMethodsBranding,
+}
+
+func (e RateLimitMethods) IsValid() bool {
+	switch e {
+	case RateLimitMethodsLogin, RateLimitMethodsRegister, RateLimitMethodsUpdateProfile, RateLimitMethodsChangePassword, RateLimitMethodsVerifyAccount, RateLimitMethodsResendVerificationCode, RateLimitMethodsRefreshToken, RateLimitMethodsEmailStatus, RateLimitMethodsAppealSuspension, RateLimitMethodsChangeEmail, RateLimitMethodsCloseAccount, RateLimitMethodsRevokeUnrecognizedSignIn, RateLimitMethodsStopVerificationReminders, RateLimitMethodsCapabilities, RateLimitMethodsBranding:
+		return true
+	}
+	return false
+}
+
+func (e RateLimitMethods) String() string {
+	return string(e)
+}
+
+func (e *RateLimitMethods) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RateLimitMethods(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RateLimitMethods", str)
+	}
+	return nil
+}
+
+func (e RateLimitMethods) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *RateLimitMethods) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e RateLimitMethods) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ReloginCampaignStatus string
+
+const (
+	ReloginCampaignStatusRunning   ReloginCampaignStatus = "RUNNING"
+	ReloginCampaignStatusCompleted ReloginCampaignStatus = "COMPLETED"
+	ReloginCampaignStatusFailed    ReloginCampaignStatus = "FAILED"
+)
+
+var AllReloginCampaignStatus = []ReloginCampaignStatus{
+	ReloginCampaignStatusRunning,
+	ReloginCampaignStatusCompleted,
+	ReloginCampaignStatusFailed,
+}
+
+func (e ReloginCampaignStatus) IsValid() bool {
+	switch e {
+	case ReloginCampaignStatusRunning, ReloginCampaignStatusCompleted, ReloginCampaignStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e ReloginCampaignStatus) String() string {
+	return string(e)
+}
+
+func (e *ReloginCampaignStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReloginCampaignStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReloginCampaignStatus", str)
+	}
+	return nil
+}
+
+func (e ReloginCampaignStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ReloginCampaignStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ReloginCampaignStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// Why a session was signed out, sent as the reason of SESSION_REVOKED errors
+type RevocationReason string
+
+const (
+	// The user signed out, which ends the session on every device
+	RevocationReasonUserLogout RevocationReason = "USER_LOGOUT"
+	// An admin forced the user to sign in again
+	RevocationReasonForcedRelogin    RevocationReason = "FORCED_RELOGIN"
+	RevocationReasonAccountSuspended RevocationReason = "ACCOUNT_SUSPENDED"
+	// A refresh came from another network or device and was blocked
+	RevocationReasonSuspiciousRefresh RevocationReason = "SUSPICIOUS_REFRESH"
+	// The account's email address was changed
+	RevocationReasonEmailChanged RevocationReason = "EMAIL_CHANGED"
+	// The account was deleted, by an admin or its owner
+	RevocationReasonAccountDeleted RevocationReason = "ACCOUNT_DELETED"
+	// The owner deactivated the account
+	RevocationReasonAccountDeactivated RevocationReason = "ACCOUNT_DEACTIVATED"
+	// The session was used from two places at once and every session was ended
+	RevocationReasonSessionTakeover RevocationReason = "SESSION_TAKEOVER"
+	// A refresh token that only exists in the session store was used, so the store is presumed leaked
+	RevocationReasonCredentialTheft RevocationReason = "CREDENTIAL_THEFT"
+	// A key of the service account was revoked
+	RevocationReasonServiceAccountKeyRevoked RevocationReason = "SERVICE_ACCOUNT_KEY_REVOKED"
+	// The user followed the "this wasn't me" link of a new sign-in alert
+	RevocationReasonUnrecognizedSignIn RevocationReason = "UNRECOGNIZED_SIGN_IN"
+	// The session was not used for longer than the idle timeout
+	RevocationReasonSessionIdle RevocationReason = "SESSION_IDLE"
+	// An admin scheduled the sessions to end at this time
+	RevocationReasonScheduledRevocation RevocationReason = "SCHEDULED_REVOCATION"
+	// The user revoked the access of a connected app
+	RevocationReasonAppAccessRevoked RevocationReason = "APP_ACCESS_REVOKED"
+	// The user signed the session out from another of their sessions
+	RevocationReasonSessionSignedOut RevocationReason = "SESSION_SIGNED_OUT"
+)
+
+var AllRevocationReason = []RevocationReason{
+	RevocationReasonUserLogout,
+	RevocationReasonForcedRelogin,
+	RevocationReasonAccountSuspended,
+	RevocationReasonSuspiciousRefresh,
+	RevocationReasonEmailChanged,
+	RevocationReasonAccountDeleted,
+	RevocationReasonAccountDeactivated,
+	RevocationReasonSessionTakeover,
+	RevocationReasonCredentialTheft,
+	RevocationReasonServiceAccountKeyRevoked,
+	RevocationReasonUnrecognizedSignIn,
+	RevocationReasonSessionIdle,
+	RevocationReasonScheduledRevocation,
+	RevocationReasonAppAccessRevoked,
+	RevocationReasonSessionSignedOut,
+}
+
+func (e RevocationReason) IsValid() bool {
+	switch e {
+	case RevocationReasonUserLogout, RevocationReasonForcedRelogin, RevocationReasonAccountSuspended, RevocationReasonSuspiciousRefresh, RevocationReasonEmailChanged, RevocationReasonAccountDeleted, RevocationReasonAccountDeactivated, RevocationReasonSessionTakeover, RevocationReasonCredentialTheft, RevocationReasonServiceAccountKeyRevoked, RevocationReasonUnrecognizedSignIn, RevocationReasonSessionIdle, RevocationReasonScheduledRevocation, RevocationReasonAppAccessRevoked, RevocationReasonSessionSignedOut:
+		return true
+	}
+	return false
+}
+
+func (e RevocationReason) String() string {
+	return string(e)
+}
+
+func (e *RevocationReason) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RevocationReason(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RevocationReason", str)
+	}
+	return nil
+}
+
+func (e RevocationReason) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *RevocationReason) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e RevocationReason) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ScheduledActionStatus string
+
+const (
+	ScheduledActionStatusPending ScheduledActionStatus = "PENDING"
+	// Claimed by the scheduler and running
+	ScheduledActionStatusRunning   ScheduledActionStatus = "RUNNING"
+	ScheduledActionStatusCompleted ScheduledActionStatus = "COMPLETED"
+	ScheduledActionStatusFailed    ScheduledActionStatus = "FAILED"
+	ScheduledActionStatusCancelled ScheduledActionStatus = "CANCELLED"
+)
+
+var AllScheduledActionStatus = []ScheduledActionStatus{
+	ScheduledActionStatusPending,
+	ScheduledActionStatusRunning,
+	ScheduledActionStatusCompleted,
+	ScheduledActionStatusFailed,
+	ScheduledActionStatusCancelled,
+}
+
+func (e ScheduledActionStatus) IsValid() bool {
+	switch e {
+	case ScheduledActionStatusPending, ScheduledActionStatusRunning, ScheduledActionStatusCompleted, ScheduledActionStatusFailed, ScheduledActionStatusCancelled:
+		return true
+	}
+	return false
+}
+
+func (e ScheduledActionStatus) String() string {
+	return string(e)
+}
+
+func (e *ScheduledActionStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ScheduledActionStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ScheduledActionStatus", str)
+	}
+	return nil
+}
+
+func (e ScheduledActionStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ScheduledActionStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ScheduledActionStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type SecurityCheckKind string
+
+const (
+	SecurityCheckKindPasswordAge        SecurityCheckKind = "PASSWORD_AGE"
+	SecurityCheckKindTwoFactor          SecurityCheckKind = "TWO_FACTOR"
+	SecurityCheckKindActiveSessions     SecurityCheckKind = "ACTIVE_SESSIONS"
+	SecurityCheckKindUnknownDevices     SecurityCheckKind = "UNKNOWN_DEVICES"
+	SecurityCheckKindRecoveryEmail      SecurityCheckKind = "RECOVERY_EMAIL"
+	SecurityCheckKindSuspiciousActivity SecurityCheckKind = "SUSPICIOUS_ACTIVITY"
+)
+
+var AllSecurityCheckKind = []SecurityCheckKind{
+	SecurityCheckKindPasswordAge,
+	SecurityCheckKindTwoFactor,
+	SecurityCheckKindActiveSessions,
+	SecurityCheckKindUnknownDevices,
+	SecurityCheckKindRecoveryEmail,
+	SecurityCheckKindSuspiciousActivity,
+}
+
+func (e SecurityCheckKind) IsValid() bool {
+	switch e {
+	case SecurityCheckKindPasswordAge, SecurityCheckKindTwoFactor, SecurityCheckKindActiveSessions, SecurityCheckKindUnknownDevices, SecurityCheckKindRecoveryEmail, SecurityCheckKindSuspiciousActivity:
+		return true
+	}
+	return false
+}
+
+func (e SecurityCheckKind) String() string {
+	return string(e)
+}
+
+func (e *SecurityCheckKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SecurityCheckKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SecurityCheckKind", str)
+	}
+	return nil
+}
+
+func (e SecurityCheckKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SecurityCheckKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SecurityCheckKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type SecurityCheckStatus string
+
+const (
+	SecurityCheckStatusPass SecurityCheckStatus = "PASS"
+	SecurityCheckStatusWarn SecurityCheckStatus = "WARN"
+	SecurityCheckStatusFail SecurityCheckStatus = "FAIL"
+	// The service or the account does not support the check; it does not count towards the score
+	SecurityCheckStatusUnavailable SecurityCheckStatus = "UNAVAILABLE"
+)
+
+var AllSecurityCheckStatus = []SecurityCheckStatus{
+	SecurityCheckStatusPass,
+	SecurityCheckStatusWarn,
+	SecurityCheckStatusFail,
+	SecurityCheckStatusUnavailable,
+}
+
+func (e SecurityCheckStatus) IsValid() bool {
+	switch e {
+	case SecurityCheckStatusPass, SecurityCheckStatusWarn, SecurityCheckStatusFail, SecurityCheckStatusUnavailable:
+		return true
+	}
+	return false
+}
+
+func (e SecurityCheckStatus) String() string {
+	return string(e)
+}
+
+func (e *SecurityCheckStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SecurityCheckStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SecurityCheckStatus", str)
+	}
+	return nil
+}
+
+func (e SecurityCheckStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SecurityCheckStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SecurityCheckStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// Reason code sent with a suspension, also shown in the suspension email
+type SuspensionReason string
+
+const (
+	SuspensionReasonTermsViolation SuspensionReason = "TERMS_VIOLATION"
+	SuspensionReasonFraud          SuspensionReason = "FRAUD"
+	SuspensionReasonAbuse          SuspensionReason = "ABUSE"
+	SuspensionReasonSpam           SuspensionReason = "SPAM"
+	SuspensionReasonSecurity       SuspensionReason = "SECURITY"
+	SuspensionReasonOther          SuspensionReason = "OTHER"
+)
+
+var AllSuspensionReason = []SuspensionReason{
+	SuspensionReasonTermsViolation,
+	SuspensionReasonFraud,
+	SuspensionReasonAbuse,
+	SuspensionReasonSpam,
+	SuspensionReasonSecurity,
+	SuspensionReasonOther,
+}
+
+func (e SuspensionReason) IsValid() bool {
+	switch e {
+	case SuspensionReasonTermsViolation, SuspensionReasonFraud, SuspensionReasonAbuse, SuspensionReasonSpam, SuspensionReasonSecurity, SuspensionReasonOther:
+		return true
+	}
+	return false
+}
+
+func (e SuspensionReason) String() string {
+	return string(e)
+}
+
+func (e *SuspensionReason) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SuspensionReason(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SuspensionReason", str)
+	}
+	return nil
+}
+
+func (e SuspensionReason) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SuspensionReason) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SuspensionReason) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
@@ -1,25 +1,35 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/abisalde/authentication-service/pkg/verification"
+)
 
 type User struct {
-	ID              int64        `json:"id"`
-	Email           string       `json:"email"`
-	Username        *string      `json:"username"`
-	Provider        AuthProvider `json:"provider"`
-	FirstName       string       `json:"firstName"`
-	LastName        string       `json:"lastName"`
-	CreatedAt       time.Time    `json:"createdAt"`
-	UpdatedAt       time.Time    `json:"updatedAt"`
-	DeletedAt       *time.Time   `json:"deletedAt"`
-	OauthId         *string      `json:"oauthId"`
-	Address         *UserAddress `json:"address"`
-	PhoneNumber     string       `json:"phoneNumber"`
-	Role            UserRole     `json:"role"`
-	IsEmailVerified bool         `json:"isEmailVerified"`
-	TermsAcceptedAt *time.Time   `json:"termsAcceptedAt"`
-	MarketingOptIn  bool         `json:"marketingOptIn"`
-	LastLoginAt     *time.Time   `json:"lastLoginAt"`
+	ID               int64             `json:"id"`
+	PublicID         string            `json:"publicId"`
+	Email            string            `json:"email"`
+	Username         *string           `json:"username"`
+	Provider         AuthProvider      `json:"provider"`
+	FirstName        string            `json:"firstName"`
+	LastName         string            `json:"lastName"`
+	CreatedAt        time.Time         `json:"createdAt"`
+	UpdatedAt        time.Time         `json:"updatedAt"`
+	DeletedAt        *time.Time        `json:"deletedAt"`
+	DeactivatedAt    *time.Time        `json:"deactivatedAt"`
+	OauthId          *string           `json:"oauthId"`
+	Address          *UserAddress      `json:"address"`
+	PhoneNumber      string            `json:"phoneNumber"`
+	Role             UserRole          `json:"role"`
+	IsEmailVerified  bool              `json:"isEmailVerified"`
+	TermsAcceptedAt  *time.Time        `json:"termsAcceptedAt"`
+	TermsVersion     *string           `json:"termsVersion"`
+	MarketingOptIn   bool              `json:"marketingOptIn"`
+	LastLoginAt      *time.Time        `json:"lastLoginAt"`
+	OnboardingStep   OnboardingStep    `json:"onboardingStep"`
+	SuspendedAt      *time.Time        `json:"suspendedAt"`
+	SuspensionReason *SuspensionReason `json:"suspensionReason"`
 }
 
 type PublicUser struct {
@@ -28,20 +38,37 @@ type PublicUser struct {
 }
 
 type RegisterVerifiedUser struct {
-	Email           string  `json:"email"`
-	Password        string  `json:"password"`
-	IsEmailVerified bool    `json:"isEmailVerified"`
-	FirstName       string  `json:"firstName"`
-	LastName        string  `json:"lastName"`
-	OauthId         *string `json:"oauthId"`
+	Email           string     `json:"email"`
+	Password        string     `json:"password"`
+	IsEmailVerified bool       `json:"isEmailVerified"`
+	FirstName       string     `json:"firstName"`
+	LastName        string     `json:"lastName"`
+	OauthId         *string    `json:"oauthId"`
+	TermsAcceptedAt *time.Time `json:"termsAcceptedAt"`
+	TermsVersion    string     `json:"termsVersion"`
+	Country         string     `json:"country"`
+	MarketingOptIn  bool       `json:"marketingOptIn"`
 }
 
 type PendingUser struct {
-	Email            string    `json:"email"`
-	HashPassword     string    `json:"hash_password"`
-	VerificationCode string    `json:"code"`
-	CreatedAt        time.Time `json:"createdAt"`
-	ExpiresAt        time.Time `json:"expiresAt"`
+	Email        string `json:"email"`
+	HashPassword string `json:"hash_password"`
+	// VerificationCode is the plaintext code handed to CreatePendingUser and
+	// UpdatePendingUser; only its hash, Code, is cached.
+	VerificationCode string                  `json:"-"`
+	Code             verification.StoredCode `json:"code_hash"`
+	FirstName        string                  `json:"firstName"`
+	LastName         string                  `json:"lastName"`
+	TermsAcceptedAt  *time.Time              `json:"termsAcceptedAt"`
+	TermsVersion     string                  `json:"termsVersion,omitempty"`
+	Country          string                  `json:"country,omitempty"`
+	MarketingOptIn   bool                    `json:"marketingOptIn"`
+	CreatedAt        time.Time               `json:"createdAt"`
+	ExpiresAt        time.Time               `json:"expiresAt"`
+	// Nudges counts the reminders sent with a fresh code; NudgesStopped is
+	// set when the user asked for no more.
+	Nudges        int  `json:"nudges,omitempty"`
+	NudgesStopped bool `json:"nudgesStopped,omitempty"`
 }
 type PaginationInput struct {
 	Limit *int    `json:"limit"`
@@ -31,6 +31,36 @@ func (r *mutationResolver) Logout(ctx context.Context) (bool, error) {
 	return r.Resolver.loginHandler.ProcessLogout(ctx)
 }
 
+// LogoutAllDevices is the resolver for the logoutAllDevices field.
+func (r *mutationResolver) LogoutAllDevices(ctx context.Context) (bool, error) {
+	return r.profileHandler.LogoutAllDevices(ctx)
+}
+
+// RevokeSession is the resolver for the revokeSession field.
+func (r *mutationResolver) RevokeSession(ctx context.Context, id string) ([]*model.ActiveSession, error) {
+	return r.profileHandler.RevokeSession(ctx, id)
+}
+
+// RevokeOtherSessions is the resolver for the revokeOtherSessions field.
+func (r *mutationResolver) RevokeOtherSessions(ctx context.Context) ([]*model.ActiveSession, error) {
+	return r.profileHandler.RevokeOtherSessions(ctx)
+}
+
+// LinkOAuthAccount is the resolver for the linkOAuthAccount field.
+func (r *mutationResolver) LinkOAuthAccount(ctx context.Context, input model.LinkOAuthAccountInput) (*model.PasswordLessResponse, error) {
+	return r.oauthHandler.LinkOAuthAccount(ctx, input)
+}
+
+// UnlinkOAuthAccount is the resolver for the unlinkOAuthAccount field.
+func (r *mutationResolver) UnlinkOAuthAccount(ctx context.Context, id string) ([]*model.LinkedAccount, error) {
+	return r.oauthHandler.UnlinkOAuthAccount(ctx, id)
+}
+
+// RevokeConnectedApp is the resolver for the revokeConnectedApp field.
+func (r *mutationResolver) RevokeConnectedApp(ctx context.Context, client string) ([]*model.ConnectedApp, error) {
+	return r.profileHandler.RevokeConnectedApp(ctx, client)
+}
+
 // UpdateProfile is the resolver for the updateProfile field.
 func (r *mutationResolver) UpdateProfile(ctx context.Context, input model.UpdateProfileInput) (*model.User, error) {
 	return r.profileHandler.UpdateUserProfile(ctx, input)
@@ -41,6 +71,31 @@ func (r *mutationResolver) ChangePassword(ctx context.Context, input *model.Chan
 	return r.profileHandler.HandlePasswordChange(ctx, *input)
 }
 
+// ChangeEmail is the resolver for the changeEmail field.
+func (r *mutationResolver) ChangeEmail(ctx context.Context, input model.ChangeEmailInput) (bool, error) {
+	return r.profileHandler.ChangeEmail(ctx, input)
+}
+
+// ConfirmEmailChange is the resolver for the confirmEmailChange field.
+func (r *mutationResolver) ConfirmEmailChange(ctx context.Context, code string) (bool, error) {
+	return r.profileHandler.ConfirmEmailChange(ctx, code)
+}
+
+// DeactivateAccount is the resolver for the deactivateAccount field.
+func (r *mutationResolver) DeactivateAccount(ctx context.Context, password *string) (bool, error) {
+	return r.profileHandler.DeactivateAccount(ctx, password)
+}
+
+// DeleteAccount is the resolver for the deleteAccount field.
+func (r *mutationResolver) DeleteAccount(ctx context.Context, password *string) (bool, error) {
+	return r.profileHandler.DeleteAccount(ctx, password)
+}
+
+// AcceptTerms is the resolver for the acceptTerms field.
+func (r *mutationResolver) AcceptTerms(ctx context.Context) (*model.User, error) {
+	return r.profileHandler.AcceptTerms(ctx)
+}
+
 // VerifyAccount is the resolver for the verifyAccount field.
 func (r *mutationResolver) VerifyAccount(ctx context.Context, input model.AccountVerification) (bool, error) {
 	return r.Resolver.registerHandler.VerifyUserEmail(ctx, input)
@@ -52,15 +107,45 @@ func (r *mutationResolver) ResendVerificationCode(ctx context.Context, input mod
 }
 
 // RefreshToken is the resolver for the refreshToken field.
-func (r *mutationResolver) RefreshToken(ctx context.Context, token string, userID int32) (*model.RefreshTokenResponse, error) {
+func (r *mutationResolver) RefreshToken(ctx context.Context, token string, userID string) (*model.RefreshTokenResponse, error) {
 	return r.Resolver.tokenHandler.HandleRefreshToken(ctx, token, userID)
 }
 
+// AppealSuspension is the resolver for the appealSuspension field.
+func (r *mutationResolver) AppealSuspension(ctx context.Context, token string, message string) (bool, error) {
+	return r.Resolver.usersHandler.AppealSuspension(ctx, token, message)
+}
+
+// RevokeUnrecognizedSignIn is the resolver for the revokeUnrecognizedSignIn field.
+func (r *mutationResolver) RevokeUnrecognizedSignIn(ctx context.Context, token string) (bool, error) {
+	return r.Resolver.usersHandler.RevokeUnrecognizedSignIn(ctx, token)
+}
+
+// StopVerificationReminders is the resolver for the stopVerificationReminders field.
+func (r *mutationResolver) StopVerificationReminders(ctx context.Context, token string) (bool, error) {
+	return r.Resolver.registerHandler.StopVerificationReminders(ctx, token)
+}
+
 // ID is the resolver for the id field.
 func (r *publicUserResolver) ID(ctx context.Context, obj *model.PublicUser) (string, error) {
 	return "0", nil
 }
 
+// EmailStatus is the resolver for the emailStatus field.
+func (r *queryResolver) EmailStatus(ctx context.Context, email string, captchaToken *string) (*model.EmailStatus, error) {
+	return r.Resolver.registerHandler.EmailStatus(ctx, email, captchaToken)
+}
+
+// RegistrationRequirements is the resolver for the registrationRequirements field.
+func (r *queryResolver) RegistrationRequirements(ctx context.Context, country *string) (*model.RegistrationRequirements, error) {
+	return r.Resolver.registerHandler.RegistrationRequirements(ctx, country)
+}
+
+// APIClientQuota is the resolver for the apiClientQuota field.
+func (r *queryResolver) APIClientQuota(ctx context.Context) (*model.APIClientQuota, error) {
+	return r.Resolver.quotaHandler.GetAPIClientQuota(ctx)
+}
+
 // PublicUser returns graph.PublicUserResolver implementation.
 func (r *Resolver) PublicUser() graph.PublicUserResolver { return &publicUserResolver{r} }
 
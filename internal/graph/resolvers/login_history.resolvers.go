@@ -0,0 +1,16 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.76
+
+import (
+	"context"
+
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+// LoginActivity is the resolver for the loginActivity field.
+func (r *queryResolver) LoginActivity(ctx context.Context, first *int32, after *string) (*model.LoginAttemptConnection, error) {
+	return r.profileHandler.GetLoginActivity(ctx, first, after)
+}
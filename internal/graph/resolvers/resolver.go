@@ -5,6 +5,7 @@ import (
 	"github.com/abisalde/authentication-service/internal/auth/handler/oauth"
 	"github.com/abisalde/authentication-service/internal/auth/service"
 	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/ratelimit"
 )
 
 // This file will not be regenerated automatically.
@@ -19,15 +20,17 @@ type Resolver struct {
 	tokenHandler    *http.TokenHandler
 	oauthHandler    *oauth.OAuthHandler
 	usersHandler    *http.UsersHandler
+	quotaHandler    *http.QuotaHandler
 }
 
-func NewResolver(client *ent.Client, authService *service.AuthService, oauthService *service.OAuthService) *Resolver {
+func NewResolver(client *ent.Client, authService *service.AuthService, oauthService *service.OAuthService, limiter *ratelimit.Limiter) *Resolver {
 	registerHandler := http.NewRegisterHandler(authService)
 	loginHandler := http.NewLoginHandler(authService)
 	profileHandler := http.NewProfileHandler(authService)
 	usersHandler := http.NewUsersHandler(authService)
 	tokenHandler := http.NewTokenHandler(authService)
 	oauthHandler := oauth.NewOAuthHandler(oauthService)
+	quotaHandler := http.NewQuotaHandler(limiter)
 	return &Resolver{
 		client:          client,
 		registerHandler: registerHandler,
@@ -36,5 +39,6 @@ func NewResolver(client *ent.Client, authService *service.AuthService, oauthServ
 		usersHandler:    usersHandler,
 		oauthHandler:    oauthHandler,
 		tokenHandler:    tokenHandler,
+		quotaHandler:    quotaHandler,
 	}
 }
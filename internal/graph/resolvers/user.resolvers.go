@@ -15,15 +15,24 @@ func (r *queryResolver) Profile(ctx context.Context) (*model.User, error) {
 	return r.profileHandler.GetUserProfile(ctx)
 }
 
-// Users is the resolver for the users field.
-func (r *queryResolver) Users(ctx context.Context, role *model.UserRole, first *int32, after *string) (*model.UserConnection, error) {
-	var firstID *int
-	if first != nil {
-		val := int(*first)
-		firstID = &val
-	}
+// CsrfToken is the resolver for the csrfToken field.
+func (r *queryResolver) CsrfToken(ctx context.Context) (string, error) {
+	return r.profileHandler.GetCSRFToken(ctx)
+}
+
+// LinkedAccounts is the resolver for the linkedAccounts field.
+func (r *queryResolver) LinkedAccounts(ctx context.Context) ([]*model.LinkedAccount, error) {
+	return r.oauthHandler.LinkedAccounts(ctx)
+}
+
+// ConnectedApps is the resolver for the connectedApps field.
+func (r *queryResolver) ConnectedApps(ctx context.Context) ([]*model.ConnectedApp, error) {
+	return r.profileHandler.GetConnectedApps(ctx)
+}
 
-	return r.usersHandler.GetAllUsers(ctx, role, firstID, after)
+// ActiveSessions is the resolver for the activeSessions field.
+func (r *queryResolver) ActiveSessions(ctx context.Context) ([]*model.ActiveSession, error) {
+	return r.profileHandler.GetActiveSessions(ctx)
 }
 
 // CheckUsernameAvailability is the resolver for the checkUsernameAvailability field.
@@ -38,3 +47,13 @@ func (r *queryResolver) CheckUsernameAvailability(ctx context.Context, username
 		Username:  username,
 	}, nil
 }
+
+// OnboardingStatus is the resolver for the onboardingStatus field.
+func (r *queryResolver) OnboardingStatus(ctx context.Context) (*model.OnboardingStatus, error) {
+	return r.profileHandler.GetOnboardingStatus(ctx)
+}
+
+// SecurityCheckup is the resolver for the securityCheckup field.
+func (r *queryResolver) SecurityCheckup(ctx context.Context) (*model.SecurityCheckup, error) {
+	return r.profileHandler.GetSecurityCheckup(ctx)
+}
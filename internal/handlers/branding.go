@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// brandingMaxAge keeps branding edits visible on redirect pages within a
+// few minutes.
+const brandingMaxAge = 5 * time.Minute
+
+// BrandSource resolves the brand redirect pages render.
+type BrandSource interface {
+	DefaultBrand() service.Brand
+	BrandForOrganization(ctx context.Context, slug string) (service.Brand, error)
+}
+
+// BrandingHandler serves the brand of the organization named by the org
+// query parameter, or the default brand without one, so the pages users are
+// redirected to after verification and sign-in match their emails.
+func BrandingHandler(brands BrandSource) fiber.Handler {
+	cacheControl := fmt.Sprintf("public, max-age=%d", int(brandingMaxAge.Seconds()))
+
+	return func(c *fiber.Ctx) error {
+		slug := c.Query("org")
+		if slug == "" {
+			c.Set(fiber.HeaderCacheControl, cacheControl)
+			return c.JSON(brands.DefaultBrand())
+		}
+
+		brand, err := brands.BrandForOrganization(c.UserContext(), slug)
+		switch {
+		case err == service.ErrOrganizationNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "organization not found"})
+		case err != nil:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to load branding"})
+		}
+
+		c.Set(fiber.HeaderCacheControl, cacheControl)
+		return c.JSON(brand)
+	}
+}
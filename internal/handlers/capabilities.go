@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/cookies"
+	"github.com/abisalde/authentication-service/internal/auth/onboarding"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/ratelimit"
+	"github.com/abisalde/authentication-service/internal/utils/validator"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CapabilitiesSchemaVersion is bumped whenever the payload shape changes so
+// clients can tell a new field set apart from a changed deployment.
+const CapabilitiesSchemaVersion = 2
+
+// capabilitiesMaxAge is short enough for a degraded provider flag to reach
+// clients within a minute of the failure rate spiking.
+const capabilitiesMaxAge = time.Minute
+
+// capabilitiesSharedMaxAge has CDNs revalidate twice within capabilitiesMaxAge.
+const capabilitiesSharedMaxAge = 30 * time.Second
+
+// CapabilitiesRateLimit and BrandingRateLimit allow each client IP 60
+// requests a minute to the discovery endpoints, counted in Redis so every
+// instance shares the budget.
+var (
+	CapabilitiesRateLimit = discoveryRateLimit(model.RateLimitMethodsCapabilities)
+	BrandingRateLimit     = discoveryRateLimit(model.RateLimitMethodsBranding)
+)
+
+func discoveryRateLimit(operation model.RateLimitMethods) ratelimit.Policy {
+	return ratelimit.Policy{
+		Operation: operation,
+		Limit:     60,
+		Window:    time.Minute,
+		Key:       model.RateLimitKeyIP,
+		Algorithm: model.RateLimitAlgorithmFixedWindow,
+	}
+}
+
+// ProviderHealthSource reports providers whose sign-in is failing.
+type ProviderHealthSource interface {
+	DegradedProviders() []string
+}
+
+type Capabilities struct {
+	SchemaVersion  int                      `json:"schemaVersion"`
+	Providers      []string                 `json:"providers"`
+	PasswordLess   bool                     `json:"passwordLess"`
+	TwoFactor      bool                     `json:"twoFactor"`
+	PasswordPolicy validator.PasswordPolicy `json:"passwordPolicy"`
+	Token          TokenCapabilities        `json:"token"`
+	Onboarding     []string                 `json:"onboardingSteps"`
+	// DegradedProviders are configured but currently failing; clients should
+	// show them as temporarily unavailable.
+	DegradedProviders []string `json:"degradedProviders"`
+	Notices           []string `json:"notices"`
+}
+
+type TokenCapabilities struct {
+	Algorithm              string `json:"algorithm"`
+	AccessTokenTTLSeconds  int    `json:"accessTokenTtlSeconds"`
+	RefreshTokenTTLSeconds int    `json:"refreshTokenTtlSeconds"`
+}
+
+func BuildCapabilities(cfg *configs.Config) Capabilities {
+	providers := service.ConfiguredOAuthProviders(cfg)
+
+	steps := make([]string, 0)
+	if policy := onboarding.NewPolicy(cfg); policy.Enforce {
+		for _, step := range policy.RequiredSteps() {
+			steps = append(steps, string(step))
+		}
+	}
+
+	return Capabilities{
+		SchemaVersion:  CapabilitiesSchemaVersion,
+		Providers:      providers,
+		PasswordLess:   len(providers) > 0,
+		TwoFactor:      cfg.TwoFactor.Enabled,
+		PasswordPolicy: validator.CurrentPasswordPolicy,
+		Token: TokenCapabilities{
+			Algorithm:              jwt.SigningAlgorithm(),
+			AccessTokenTTLSeconds:  int(cookies.LoginAccessTokenExpiry.Seconds()),
+			RefreshTokenTTLSeconds: int(cookies.RefreshTokenExpiry.Seconds()),
+		},
+		Onboarding:        steps,
+		DegradedProviders: []string{},
+		Notices:           []string{},
+	}
+}
+
+// withDegradedProviders flags the configured providers that are degraded.
+func (c Capabilities) withDegradedProviders(degraded []string) Capabilities {
+	for _, provider := range degraded {
+		if !slices.Contains(c.Providers, provider) {
+			continue
+		}
+		c.DegradedProviders = append(c.DegradedProviders, provider)
+		c.Notices = append(c.Notices, fmt.Sprintf("%s sign-in temporarily unavailable", providerDisplayName(provider)))
+	}
+	return c
+}
+
+func providerDisplayName(provider string) string {
+	if provider == "" {
+		return provider
+	}
+	return strings.ToUpper(provider[:1]) + provider[1:]
+}
+
+// CapabilitiesHandler serves the deployment's auth capabilities. The payload
+// only changes with config, provider health and the signing keys, so it is
+// re-rendered only when the degraded provider set or the key set changes and
+// versioned by an ETag that lets clients and CDNs revalidate cheaply.
+func CapabilitiesHandler(cfg *configs.Config, health ProviderHealthSource) fiber.Handler {
+	base := BuildCapabilities(cfg)
+	cacheControl := publicCacheControl(capabilitiesMaxAge, capabilitiesSharedMaxAge)
+
+	var (
+		mu       sync.Mutex
+		rendered string
+		keys     *jwt.KeySet
+		body     []byte
+		etag     string
+	)
+
+	render := func() ([]byte, string, error) {
+		var degraded []string
+		if health != nil {
+			degraded = health.DegradedProviders()
+		}
+		ks := jwt.CurrentKeySet()
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		state := strings.Join(degraded, ",")
+		if body != nil && state == rendered && ks == keys {
+			return body, etag, nil
+		}
+
+		capabilities := base.withDegradedProviders(degraded)
+		capabilities.Token.Algorithm = jwt.SigningAlgorithm()
+		payload, err := json.Marshal(capabilities)
+		if err != nil {
+			return nil, "", err
+		}
+		rendered, keys, body, etag = state, ks, payload, contentETag(payload)
+		return body, etag, nil
+	}
+
+	return func(c *fiber.Ctx) error {
+		body, etag, err := render()
+		if err != nil {
+			slog.Error("Failed to render capabilities", "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "server_error"})
+		}
+		return sendCacheable(c, body, fiber.MIMEApplicationJSON, etag, cacheControl)
+	}
+}
@@ -1,11 +1,9 @@
 package handlers
 
 import (
-	"context"
 	"net/http"
 
 	"github.com/99designs/gqlgen/graphql/handler"
-	"github.com/abisalde/authentication-service/internal/auth"
 	app_logger "github.com/abisalde/authentication-service/pkg/logger"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/adaptor"
@@ -17,7 +15,7 @@ func GraphQLHandler(srv *handler.Server) fiber.Handler {
 		remoteAddr := c.Context().RemoteAddr().String()
 		app_logger.LogGraphQLRequest(clientIP, remoteAddr)
 
-		ctx := context.WithValue(c.UserContext(), auth.ClientIPKey, clientIP)
+		ctx := c.UserContext()
 
 		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			r = r.WithContext(ctx)
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// publicCacheControl lets clients keep a response for maxAge and shared
+// caches such as CDNs for sMaxAge. Keeping sMaxAge below maxAge makes CDNs
+// revalidate often, which costs a 304, while clients still see a change
+// within maxAge since the CDN reports the age of what it serves.
+func publicCacheControl(maxAge, sMaxAge time.Duration) string {
+	return fmt.Sprintf("public, max-age=%d, s-maxage=%d", int(maxAge.Seconds()), int(sMaxAge.Seconds()))
+}
+
+// contentETag is a strong ETag of the payload, so it changes exactly when
+// the payload does.
+func contentETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// sendCacheable sends body with its ETag and cache directives, or an empty
+// 304 when the request already holds that version.
+func sendCacheable(c *fiber.Ctx, body []byte, contentType, etag, cacheControl string) error {
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderCacheControl, cacheControl)
+
+	if etagMatches(c.Get(fiber.HeaderIfNoneMatch), etag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	return c.Send(body)
+}
+
+// etagMatches applies the weak comparison If-None-Match calls for: the
+// header may list several tags, mark them weak or be "*".
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
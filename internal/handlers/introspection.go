@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"net/url"
+	"strings"
+
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/automation"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/gofiber/fiber/v2"
+)
+
+// TokenIntrospector reports whether a token is active and returns its claims.
+type TokenIntrospector interface {
+	IntrospectToken(ctx context.Context, token string) (*jwt.Claims, bool)
+}
+
+// Introspection is the RFC 7662 response. Inactive tokens only carry
+// active=false, so callers learn nothing about why a token was rejected.
+type Introspection struct {
+	Active    bool   `json:"active"`
+	Subject   string `json:"sub,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Scope     string `json:"scope,omitempty"`
+	Role      string `json:"role,omitempty"`
+	Issuer    string `json:"iss,omitempty"`
+	TokenID   string `json:"jti,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	DeviceID  string `json:"device_id,omitempty"`
+}
+
+// IntrospectionHandler validates JWTs and opaque tokens for resource servers.
+// It is only served to trusted automation clients, identified by
+// AutomationMiddleware.
+func IntrospectionHandler(introspector TokenIntrospector) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if auth.GetAutomationClient(c.UserContext()) == "" {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_client"})
+		}
+		return introspect(c, introspector)
+	}
+}
+
+// OAuthIntrospectionHandler is the RFC 7662 endpoint for resource servers
+// registered with client credentials, sent with HTTP Basic auth or as
+// client_id and client_secret form fields.
+func OAuthIntrospectionHandler(introspector TokenIntrospector, clients *automation.Registry) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		clientID, clientSecret := clientCredentials(c)
+		client, ok := clients.IdentifyClient(clientID, clientSecret)
+		if !ok {
+			c.Set(fiber.HeaderWWWAuthenticate, `Basic realm="introspect"`)
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_client"})
+		}
+
+		c.SetUserContext(auth.WithIntrospectionClient(c.UserContext(), client))
+		return introspect(c, introspector)
+	}
+}
+
+// clientCredentials reads the client credentials of RFC 6749 section 2.3.1.
+// Basic credentials are form encoded before they are base64 encoded.
+func clientCredentials(c *fiber.Ctx) (string, string) {
+	header := c.Get(fiber.HeaderAuthorization)
+	if scheme, encoded, ok := strings.Cut(header, " "); ok && strings.EqualFold(scheme, "Basic") {
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return "", ""
+		}
+		id, secret, ok := strings.Cut(string(raw), ":")
+		if !ok {
+			return "", ""
+		}
+		id, idErr := url.QueryUnescape(id)
+		secret, secretErr := url.QueryUnescape(secret)
+		if idErr != nil || secretErr != nil {
+			return "", ""
+		}
+		return id, secret
+	}
+	return c.FormValue("client_id"), c.FormValue("client_secret")
+}
+
+func introspect(c *fiber.Ctx, introspector TokenIntrospector) error {
+	token := strings.TrimSpace(c.FormValue("token"))
+	if token == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_request"})
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	claims, active := introspector.IntrospectToken(c.UserContext(), token)
+	if !active {
+		return c.JSON(Introspection{Active: false})
+	}
+
+	scopes, err := jwt.ResolveScopes(c.UserContext(), claims)
+	if err != nil {
+		return c.JSON(Introspection{Active: false})
+	}
+
+	resp := Introspection{
+		Active:    true,
+		Subject:   claims.Subject,
+		TokenType: string(claims.Type),
+		Scope:     strings.Join(scopes, " "),
+		Role:      claims.RoleName(),
+		Issuer:    claims.Issuer,
+		TokenID:   claims.ID,
+	}
+	if claims.IssuedAt != nil {
+		resp.IssuedAt = claims.IssuedAt.Unix()
+	}
+	if claims.ExpiresAt != nil {
+		resp.ExpiresAt = claims.ExpiresAt.Unix()
+	}
+	if claims.Device != nil {
+		resp.DeviceID = claims.Device.ID
+	}
+	return c.JSON(resp)
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/gofiber/fiber/v2"
+)
+
+// jwksMaxAge lets verifiers cache the key set well inside the reload
+// interval, so a newly published key is fetched before it becomes primary.
+const jwksMaxAge = 5 * time.Minute
+
+// jwksSharedMaxAge keeps CDNs revalidating the key set more often than
+// verifiers refetch it.
+const jwksSharedMaxAge = time.Minute
+
+// keySetDocument renders a document derived from the active key set once per
+// key set. Reloading keys swaps the set, which renders a new document under a
+// new ETag, so caches pick up a rotation on their next revalidation.
+type keySetDocument struct {
+	render func(ks *jwt.KeySet) any
+
+	mu       sync.Mutex
+	rendered *jwt.KeySet
+	body     []byte
+	etag     string
+}
+
+func (d *keySetDocument) current() ([]byte, string) {
+	ks := jwt.CurrentKeySet()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.body != nil && d.rendered == ks {
+		return d.body, d.etag
+	}
+
+	body, err := json.Marshal(d.render(ks))
+	if err != nil {
+		panic(err)
+	}
+	d.rendered, d.body, d.etag = ks, body, contentETag(body)
+	return d.body, d.etag
+}
+
+// JWKSHandler publishes the public signing keys. The set is empty while
+// tokens are HS256: the shared secret is never exposed.
+func JWKSHandler() fiber.Handler {
+	cacheControl := publicCacheControl(jwksMaxAge, jwksSharedMaxAge)
+	doc := &keySetDocument{render: func(ks *jwt.KeySet) any {
+		if ks == nil {
+			return jwt.JWKS{Keys: []jwt.JWK{}}
+		}
+		return ks.JWKS()
+	}}
+
+	return func(c *fiber.Ctx) error {
+		body, etag := doc.current()
+		return sendCacheable(c, body, "application/jwk-set+json", etag, cacheControl)
+	}
+}
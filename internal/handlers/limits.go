@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+const payloadTooLargeMessage = "Request body is too large"
+
+// ErrorHandler renders oversized bodies rejected by the app's BodyLimit as
+// structured JSON and leaves every other error to Fiber's default handler.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) && fiberErr.Code == fiber.StatusRequestEntityTooLarge {
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+			"error":   string(model.ErrorTypePayloadTooLarge),
+			"message": payloadTooLargeMessage,
+		})
+	}
+	return fiber.DefaultErrorHandler(c, err)
+}
+
+// GraphQLBodyLimit rejects GraphQL requests above maxBytes with a 413 in
+// the GraphQL error shape, before the transport parses the body. Multipart
+// uploads are capped by the transport's own upload limit instead.
+func GraphQLBodyLimit(maxBytes int64) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if maxBytes <= 0 || strings.HasPrefix(string(c.Request().Header.ContentType()), fiber.MIMEMultipartForm) {
+			return c.Next()
+		}
+
+		size := int64(c.Request().Header.ContentLength())
+		if body := int64(len(c.Body())); body > size {
+			size = body
+		}
+		if size <= maxBytes {
+			return c.Next()
+		}
+
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+			"errors": []fiber.Map{{
+				"message":    payloadTooLargeMessage,
+				"extensions": fiber.Map{"code": model.ErrorTypePayloadTooLarge},
+			}},
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/gofiber/fiber/v2"
+)
+
+// OpenIDConfiguration is the OpenID Connect discovery document.
+type OpenIDConfiguration struct {
+	Issuer                                    string   `json:"issuer"`
+	AuthorizationEndpoint                     string   `json:"authorization_endpoint"`
+	JWKSURI                                   string   `json:"jwks_uri"`
+	IntrospectionEndpoint                     string   `json:"introspection_endpoint"`
+	ResponseTypesSupported                    []string `json:"response_types_supported"`
+	SubjectTypesSupported                     []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported          []string `json:"id_token_signing_alg_values_supported"`
+	ScopesSupported                           []string `json:"scopes_supported"`
+	ClaimsSupported                           []string `json:"claims_supported"`
+	IntrospectionEndpointAuthMethodsSupported []string `json:"introspection_endpoint_auth_methods_supported"`
+}
+
+// OpenIDConfigurationHandler serves /.well-known/openid-configuration. The
+// document is rendered again whenever the key set changes, so the signing
+// algorithm follows key rotations without a restart.
+func OpenIDConfigurationHandler(cfg *configs.Config) fiber.Handler {
+	issuer := service.OIDCIssuer(cfg)
+	cacheControl := publicCacheControl(jwksMaxAge, jwksSharedMaxAge)
+
+	doc := &keySetDocument{render: func(*jwt.KeySet) any {
+		return OpenIDConfiguration{
+			Issuer:                           issuer,
+			AuthorizationEndpoint:            cfg.OIDC.AuthorizationEndpoint,
+			JWKSURI:                          issuer + "/.well-known/jwks.json",
+			IntrospectionEndpoint:            issuer + "/oauth/introspect",
+			ResponseTypesSupported:           []string{"id_token", "id_token token"},
+			SubjectTypesSupported:            []string{"public"},
+			IDTokenSigningAlgValuesSupported: []string{jwt.IDTokenAlgorithm()},
+			ScopesSupported:                  []string{"openid", "email", "profile"},
+			ClaimsSupported:                  []string{"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "email", "email_verified", "name", "given_name", "family_name", "preferred_username"},
+			IntrospectionEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post"},
+		}
+	}}
+
+	return func(c *fiber.Ctx) error {
+		body, etag := doc.current()
+		return sendCacheable(c, body, fiber.MIMEApplicationJSON, etag, cacheControl)
+	}
+}
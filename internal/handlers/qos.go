@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/pkg/qos"
+	"github.com/gofiber/fiber/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+const overloadedMessage = "Service is under heavy load, please retry shortly"
+
+// qosFields classifies GraphQL root fields; fields not listed act on an
+// existing session and share the validation class.
+var qosFields = map[string]qos.Class{
+	"refreshToken": qos.Refresh,
+
+	"login":            qos.Login,
+	"passwordLessAuth": qos.Login,
+
+	"register":                  qos.Registration,
+	"verifyAccount":             qos.Registration,
+	"resendVerificationCode":    qos.Registration,
+	"emailStatus":               qos.Registration,
+	"registrationRequirements":  qos.Registration,
+	"checkUsernameAvailability": qos.Registration,
+
+	"loginActivity":       qos.Analytics,
+	"loginAttempts":       qos.Analytics,
+	"tokenStats":          qos.Analytics,
+	"redisKeyspaceUsage":  qos.Analytics,
+	"oauthProviderHealth": qos.Analytics,
+}
+
+// QoS admits every request through the scheduler, highest class first,
+// and answers shed requests with a 503 and Retry-After.
+func QoS(scheduler *qos.Scheduler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		release, err := scheduler.Acquire(c.UserContext(), ClassifyRequest(c))
+		if err != nil {
+			if !errors.Is(err, qos.ErrShed) {
+				return err
+			}
+			c.Set(fiber.HeaderRetryAfter, "1")
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"errors": []fiber.Map{{
+					"message":    overloadedMessage,
+					"extensions": fiber.Map{"code": model.ErrorTypeOverloaded},
+				}},
+			})
+		}
+		defer release()
+		return c.Next()
+	}
+}
+
+// ClassifyRequest returns the priority class of a request. A GraphQL
+// document takes the lowest class among its root fields, so bundling a
+// registration with a profile query does not jump the queue.
+func ClassifyRequest(c *fiber.Ctx) qos.Class {
+	path := c.Path()
+	switch {
+	case path == "/introspect", path == "/oauth/introspect", path == "/.well-known/jwks.json":
+		return qos.Validation
+	case path == "/metrics":
+		return qos.Analytics
+	case strings.HasPrefix(path, "/service/oauth/"):
+		return qos.Login
+	case path != "/graphql" && path != "/admin/graphql":
+		return qos.Validation
+	}
+
+	query, operation := graphQLOperation(c)
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil {
+		return qos.Validation
+	}
+
+	class := qos.Validation
+	for _, op := range doc.Operations {
+		if operation != "" && op.Name != operation {
+			continue
+		}
+		for _, selection := range op.SelectionSet {
+			field, ok := selection.(*ast.Field)
+			if !ok {
+				continue
+			}
+			if fieldClass, ok := qosFields[field.Name]; ok && fieldClass > class {
+				class = fieldClass
+			}
+		}
+		break
+	}
+	return class
+}
+
+func graphQLOperation(c *fiber.Ctx) (query, operation string) {
+	if c.Method() == fiber.MethodGet {
+		return c.Query("query"), c.Query("operationName")
+	}
+
+	var body struct {
+		Query         string `json:"query"`
+		OperationName string `json:"operationName"`
+	}
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return "", ""
+	}
+	return body.Query, body.OperationName
+}
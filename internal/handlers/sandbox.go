@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+
+	"github.com/abisalde/authentication-service/internal/auth/cookies"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/pkg/mail"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SandboxSecretHeader carries SANDBOX_BOOTSTRAP_SECRET on POST /sandbox/token.
+const SandboxSecretHeader = "X-Sandbox-Secret"
+
+// TestTokenIssuer mints tokens for sandbox identities.
+type TestTokenIssuer interface {
+	IssueTestTokens(ctx context.Context, email string) (*ent.User, *cookies.TokenPair, error)
+}
+
+type TestTokens struct {
+	UserID       int64  `json:"userId"`
+	PublicID     string `json:"publicId"`
+	Email        string `json:"email"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+	ExpiresIn    int    `json:"expiresIn"`
+}
+
+// SandboxMailHandler lists mail captured in sandbox mode, newest first.
+// Filter by recipient with ?to=.
+func SandboxMailHandler(c *fiber.Ctx) error {
+	return c.JSON(mail.SandboxOutbox.Messages(c.Query("to")))
+}
+
+func SandboxMailResetHandler(c *fiber.Ctx) error {
+	mail.SandboxOutbox.Reset()
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// SandboxTokenHandler returns login tokens for an allow-listed email, so
+// Postman collections and QA pipelines skip the verification flow. The
+// response matches the login mutation; no browser session is created.
+func SandboxTokenHandler(issuer TestTokenIssuer, secret string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		provided := c.Get(SandboxSecretHeader)
+		if secret == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(secret)) != 1 {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_secret"})
+		}
+
+		var body struct {
+			Email string `json:"email"`
+		}
+		if err := c.BodyParser(&body); err != nil || body.Email == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "email_required"})
+		}
+
+		user, tokens, err := issuer.IssueTestTokens(c.UserContext(), body.Email)
+		switch {
+		case errors.Is(err, service.ErrTestTokensDisabled):
+			return c.SendStatus(fiber.StatusNotFound)
+		case errors.Is(err, service.ErrNotTestIdentity):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "not_allow_listed"})
+		case errors.Is(err, service.ErrAlreadySuspended):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "account_suspended"})
+		case errors.Is(err, service.ErrAlreadyDeleted):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "account_deleted"})
+		case err != nil:
+			slog.Error("Failed to issue sandbox tokens", "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "server_error"})
+		}
+
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		return c.JSON(TestTokens{
+			UserID:       user.ID,
+			PublicID:     user.PublicID.String(),
+			Email:        user.Email,
+			Token:        tokens.AccessToken,
+			RefreshToken: tokens.RefreshToken,
+			ExpiresIn:    int(cookies.LoginAccessTokenExpiry.Seconds()),
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ServiceAccountIssuer trades a service account key for an access token.
+type ServiceAccountIssuer interface {
+	ExchangeServiceAccountKey(ctx context.Context, keyID, secret string) (string, time.Duration, error)
+}
+
+// ServiceAccountTokenHandler is the client_credentials grant for service
+// accounts. The key id and secret are the client credentials, sent with
+// HTTP Basic auth or as client_id and client_secret form fields.
+func ServiceAccountTokenHandler(issuer ServiceAccountIssuer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.FormValue("grant_type") != "client_credentials" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "unsupported_grant_type"})
+		}
+
+		c.Set(fiber.HeaderCacheControl, "no-store")
+
+		keyID, secret := clientCredentials(c)
+		token, ttl, err := issuer.ExchangeServiceAccountKey(c.UserContext(), keyID, secret)
+		switch {
+		case errors.Is(err, service.ErrInvalidServiceAccountKey):
+			c.Set(fiber.HeaderWWWAuthenticate, `Basic realm="service-accounts"`)
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_client"})
+		case err != nil:
+			slog.Error("Failed to issue a service account token", "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "server_error"})
+		}
+
+		return c.JSON(fiber.Map{
+			"access_token": token,
+			"token_type":   "Bearer",
+			"expires_in":   int(ttl.Seconds()),
+			"scope":        service.ServiceAccountScope,
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// Package lifecycle runs the long-lived parts of the server, such as the
+// Fiber app, the stream workers and the scheduler, and drains them in
+// order on shutdown.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const defaultShutdownTimeout = 30 * time.Second
+
+// Component is one part of the server. Run blocks until the component's
+// context is cancelled or it fails; Stop, when set, starts draining it
+// before that context is cancelled, for parts like HTTP servers that are
+// not stopped through a context.
+type Component struct {
+	Name string
+	Run  func(ctx context.Context) error
+	Stop func(ctx context.Context) error
+}
+
+// Manager runs components together. Once the context given to Run is done
+// or any component fails, every component is stopped in the reverse order
+// of Add, so the parts accepting work stop before the parts doing it.
+type Manager struct {
+	components      []Component
+	shutdownTimeout time.Duration
+}
+
+// New drains the components within shutdownTimeout; zero takes 30s.
+func New(shutdownTimeout time.Duration) *Manager {
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+	return &Manager{shutdownTimeout: shutdownTimeout}
+}
+
+// Add registers a component to start with Run.
+func (m *Manager) Add(c Component) *Manager {
+	m.components = append(m.components, c)
+	return m
+}
+
+// Go registers a component that only stops through its context, such as a
+// worker loop.
+func (m *Manager) Go(name string, run func(ctx context.Context) error) *Manager {
+	return m.Add(Component{Name: name, Run: run})
+}
+
+type running struct {
+	Component
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Run starts every component and blocks until they have been drained. It
+// returns the first component failure, or the drain error when components
+// failed to stop or did not stop within the shutdown timeout.
+func (m *Manager) Run(ctx context.Context) error {
+	group, groupCtx := errgroup.WithContext(ctx)
+
+	started := make([]*running, 0, len(m.components))
+	for _, c := range m.components {
+		// Components outlive ctx so they can be stopped one at a time.
+		componentCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		r := &running{Component: c, cancel: cancel, done: make(chan struct{})}
+		started = append(started, r)
+
+		group.Go(func() error {
+			defer close(r.done)
+			err := r.Run(componentCtx)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				return fmt.Errorf("%s: %w", r.Name, err)
+			}
+			if componentCtx.Err() == nil && groupCtx.Err() == nil {
+				slog.Warn("Component stopped on its own, shutting down", "component", r.Name)
+				return fmt.Errorf("%s stopped unexpectedly", r.Name)
+			}
+			return nil
+		})
+	}
+
+	<-groupCtx.Done()
+	if err := m.drain(started); err != nil {
+		// A component that did not stop may never return; leave it behind
+		// rather than hang the process.
+		return err
+	}
+	return group.Wait()
+}
+
+func (m *Manager) drain(started []*running) error {
+	slog.Info("Shutting down", "drain_timeout", m.shutdownTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), m.shutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(started) - 1; i >= 0; i-- {
+		r := started[i]
+		if r.Stop != nil {
+			if err := r.Stop(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("stopping %s: %w", r.Name, err))
+			}
+		}
+		r.cancel()
+
+		select {
+		case <-r.done:
+			slog.Info("Component stopped", "component", r.Name)
+		case <-ctx.Done():
+			errs = append(errs, fmt.Errorf("%s did not stop within %s", r.Name, m.shutdownTimeout))
+			// The rest still get cancelled so they can stop on their own.
+			for _, rest := range started[:i] {
+				rest.cancel()
+			}
+			return errors.Join(errs...)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	contentType            = "text/plain; version=0.0.4; charset=utf-8"
+	openMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+// Handler serves the default registry for Prometheus to scrape. Scrapers
+// that accept OpenMetrics also get histogram exemplars.
+func Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var buf bytes.Buffer
+		if strings.Contains(c.Get(fiber.HeaderAccept), "application/openmetrics-text") {
+			if _, err := Default.WriteOpenMetrics(&buf); err != nil {
+				return err
+			}
+			c.Set(fiber.HeaderContentType, openMetricsContentType)
+			return c.Send(buf.Bytes())
+		}
+
+		if _, err := Default.WriteTo(&buf); err != nil {
+			return err
+		}
+
+		c.Set(fiber.HeaderContentType, contentType)
+		return c.Send(buf.Bytes())
+	}
+}
@@ -0,0 +1,330 @@
+// Package metrics keeps in-process counters, gauges and histograms and
+// renders them in the Prometheus text exposition format, or in OpenMetrics
+// with histogram exemplars for scrapers that ask for it.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets suit latencies of outbound HTTP calls, in seconds.
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type collector interface {
+	write(w *bufio.Writer, openMetrics bool)
+}
+
+type Registry struct {
+	mu         sync.Mutex
+	names      []string
+	collectors map[string]collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]collector)}
+}
+
+// Default is the registry served on /metrics.
+var Default = NewRegistry()
+
+func (r *Registry) register(name string, c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.collectors[name]; exists {
+		panic(fmt.Sprintf("metrics: %s registered twice", name))
+	}
+	r.names = append(r.names, name)
+	r.collectors[name] = c
+}
+
+// WriteTo renders every registered metric, sorted by name.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	return r.write(w, false)
+}
+
+// WriteOpenMetrics renders every registered metric in the OpenMetrics
+// format, including histogram exemplars.
+func (r *Registry) WriteOpenMetrics(w io.Writer) (int64, error) {
+	return r.write(w, true)
+}
+
+func (r *Registry) write(w io.Writer, openMetrics bool) (int64, error) {
+	r.mu.Lock()
+	names := append([]string(nil), r.names...)
+	collectors := make([]collector, 0, len(names))
+	sort.Strings(names)
+	for _, name := range names {
+		collectors = append(collectors, r.collectors[name])
+	}
+	r.mu.Unlock()
+
+	cw := &countingWriter{w: w}
+	buf := bufio.NewWriter(cw)
+	for _, c := range collectors {
+		c.write(buf, openMetrics)
+	}
+	if openMetrics {
+		buf.WriteString("# EOF\n")
+	}
+	err := buf.Flush()
+	return cw.n, err
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type family struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+}
+
+func (f family) header(w *bufio.Writer, openMetrics bool) {
+	name := f.name
+	if openMetrics && f.kind == "counter" {
+		// OpenMetrics names the counter family without the _total suffix
+		// its samples carry.
+		name = strings.TrimSuffix(name, "_total")
+	}
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, f.help, name, f.kind)
+}
+
+func (f family) key(values []string) string {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d labels, got %d", f.name, len(f.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+func (f family) labelString(key string, extra ...string) string {
+	pairs := make([]string, 0, len(f.labels)+1)
+	if len(f.labels) > 0 {
+		for i, v := range strings.Split(key, "\xff") {
+			pairs = append(pairs, fmt.Sprintf("%s=%q", f.labels[i], v))
+		}
+	}
+	if len(extra) == 2 {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", extra[0], extra[1]))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// CounterVec is a monotonically increasing value per label set.
+type CounterVec struct {
+	family
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{family: family{name, help, "counter", labels}, values: make(map[string]float64)}
+	r.register(name, c)
+	return c
+}
+
+func (c *CounterVec) Inc(labels ...string) {
+	c.Add(1, labels...)
+}
+
+func (c *CounterVec) Add(delta float64, labels ...string) {
+	if delta < 0 {
+		return
+	}
+	key := c.key(labels)
+	c.mu.Lock()
+	c.values[key] += delta
+	c.mu.Unlock()
+}
+
+func (c *CounterVec) write(w *bufio.Writer, openMetrics bool) {
+	c.header(w, openMetrics)
+	name := c.name
+	if openMetrics && !strings.HasSuffix(name, "_total") {
+		name += "_total"
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s%s %s\n", name, c.labelString(key), formatFloat(c.values[key]))
+	}
+}
+
+// GaugeVec is a value per label set that can go up and down.
+type GaugeVec struct {
+	family
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	g := &GaugeVec{family: family{name, help, "gauge", labels}, values: make(map[string]float64)}
+	r.register(name, g)
+	return g
+}
+
+func (g *GaugeVec) Set(value float64, labels ...string) {
+	key := g.key(labels)
+	g.mu.Lock()
+	g.values[key] = value
+	g.mu.Unlock()
+}
+
+func (g *GaugeVec) Add(delta float64, labels ...string) {
+	key := g.key(labels)
+	g.mu.Lock()
+	g.values[key] += delta
+	g.mu.Unlock()
+}
+
+func (g *GaugeVec) write(w *bufio.Writer, openMetrics bool) {
+	g.header(w, openMetrics)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, key := range sortedKeys(g.values) {
+		fmt.Fprintf(w, "%s%s %s\n", g.name, g.labelString(key), formatFloat(g.values[key]))
+	}
+}
+
+// HistogramVec counts observations into cumulative buckets per label set.
+type HistogramVec struct {
+	family
+	buckets []float64
+	mu      sync.Mutex
+	series  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	counts    []uint64
+	count     uint64
+	sum       float64
+	exemplars []*exemplar
+}
+
+// exemplar links a bucket to one request that landed in it, so a latency
+// spike can be traced to a log line.
+type exemplar struct {
+	label string
+	id    string
+	value float64
+	at    time.Time
+}
+
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+
+	h := &HistogramVec{
+		family:  family{name, help, "histogram", labels},
+		buckets: sorted,
+		series:  make(map[string]*histogramSeries),
+	}
+	r.register(name, h)
+	return h
+}
+
+func (h *HistogramVec) Observe(value float64, labels ...string) {
+	h.ObserveWithExemplar(value, "", "", labels...)
+}
+
+// ObserveWithExemplar records the observation and keeps it as the exemplar
+// of its bucket, labelled exemplarLabel=id. An empty id records no
+// exemplar.
+func (h *HistogramVec) ObserveWithExemplar(value float64, exemplarLabel, id string, labels ...string) {
+	key := h.key(labels)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{
+			counts:    make([]uint64, len(h.buckets)),
+			exemplars: make([]*exemplar, len(h.buckets)+1),
+		}
+		h.series[key] = s
+	}
+	for i, upper := range h.buckets {
+		if value <= upper {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.sum += value
+
+	if id != "" {
+		// The first bucket holding the value, or +Inf past the last.
+		bucket := sort.SearchFloat64s(h.buckets, value)
+		s.exemplars[bucket] = &exemplar{label: exemplarLabel, id: id, value: value, at: time.Now()}
+	}
+}
+
+func (h *HistogramVec) write(w *bufio.Writer, openMetrics bool) {
+	h.header(w, openMetrics)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	keys := make([]string, 0, len(h.series))
+	for key := range h.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := h.series[key]
+		for i, upper := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d%s\n", h.name, h.labelString(key, "le", formatFloat(upper)), s.counts[i], s.exemplar(i, openMetrics))
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d%s\n", h.name, h.labelString(key, "le", "+Inf"), s.count, s.exemplar(len(h.buckets), openMetrics))
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.labelString(key), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labelString(key), s.count)
+	}
+}
+
+// exemplar renders the exemplar of bucket i, which the plain text format
+// has no syntax for.
+func (s *histogramSeries) exemplar(i int, openMetrics bool) string {
+	e := s.exemplars[i]
+	if !openMetrics || e == nil {
+		return ""
+	}
+	return fmt.Sprintf(" # {%s=%q} %s %s", e.label, e.id, formatFloat(e.value), strconv.FormatFloat(float64(e.at.UnixMilli())/1000, 'f', 3, 64))
+}
+
+func sortedKeys(values map[string]float64) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
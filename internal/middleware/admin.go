@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+
+	"github.com/abisalde/authentication-service/internal/auth/automation"
+	"github.com/gofiber/fiber/v2"
+)
+
+// AdminAccessMiddleware only routes requests from allowed networks, or with a
+// trusted client certificate or admin API key. Everyone else gets the same
+// 404 as an unknown path, so the admin schema is not advertised publicly.
+func AdminAccessMiddleware(allowedNetworks []string, registry *automation.Registry) (fiber.Handler, error) {
+	networks := make([]*net.IPNet, 0, len(allowedNetworks))
+	for _, cidr := range allowedNetworks {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid admin network %q: %w", cidr, err)
+		}
+		networks = append(networks, network)
+	}
+
+	return func(c *fiber.Ctx) error {
+		if ip := net.ParseIP(c.IP()); ip != nil {
+			for _, network := range networks {
+				if network.Contains(ip) {
+					return c.Next()
+				}
+			}
+		}
+
+		client, ok := registry.IdentifyKey(c.Get(registry.Header()))
+		if !ok {
+			client, ok = identifyPeerCertificate(c, registry)
+		}
+		if ok {
+			slog.Info("Admin API request", "client", client, "ip", c.IP())
+			return c.Next()
+		}
+
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error":   "backend NotFound",
+			"message": "service rules for the path non-existent",
+		})
+	}, nil
+}
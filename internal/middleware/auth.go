@@ -2,17 +2,20 @@ package middleware
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
-	"log"
 	"net"
 	"net/http"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/abisalde/authentication-service/internal/auth"
 	"github.com/abisalde/authentication-service/internal/auth/cookies"
 	"github.com/abisalde/authentication-service/internal/auth/service"
 	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/slo"
 	"github.com/abisalde/authentication-service/pkg/jwt"
 )
 
@@ -21,7 +24,6 @@ func AuthMiddleware(db *ent.Client, authService *service.AuthService) func(http.
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			ctx := r.Context()
 
-			ctx = context.WithValue(ctx, auth.FiberContextWeb, r)
 			ctx = context.WithValue(ctx, auth.HTTPResponseWriterKey, w)
 
 			authHeader := r.Header.Get("Authorization")
@@ -30,53 +32,31 @@ func AuthMiddleware(db *ent.Client, authService *service.AuthService) func(http.
 
 			token, err := stripTokeContext(authHeader)
 
+			viaCookie := false
 			if err != nil {
-				next.ServeHTTP(w, r)
-				return
+				cookie, cookieErr := r.Cookie(cookies.BrowserAccessTokenName)
+				if cookieErr != nil || cookie.Value == "" {
+					next.ServeHTTP(w, r)
+					return
+				}
+				token = cookie.Value
+				viaCookie = true
 			}
 
 			tokenString = token
 
-			if tokenString == "" {
-				cookie, err := r.Cookie(cookies.BrowserAccessTokenName)
-				if err == nil {
-					tokenString = cookie.Value
-				}
-			}
-
-			ctx = context.WithValue(ctx, auth.JWTTokenKey, tokenString)
-			ctx = context.WithValue(ctx, auth.FiberContextWeb, r)
+			ctx = auth.WithToken(ctx, tokenString)
 
 			if tokenString != "" {
-				if authService.IsTokenBlacklisted(ctx, tokenString) {
-					log.Println("Token is blacklisted")
-					next.ServeHTTP(w, r.WithContext(ctx))
-					return
-				}
-
-				claims, err := jwt.ValidateToken(tokenString)
-				if err != nil {
-					log.Printf("Token validation failed:  %v", err)
-					next.ServeHTTP(w, r.WithContext(ctx))
-					return
-				}
-
-				if claims.IsAccessToken() {
-					userID, parseErr := strconv.ParseInt(claims.Subject, 10, 64)
-
-					if parseErr != nil {
-						log.Printf("Invalid user ID in token claims: %v", parseErr)
-						next.ServeHTTP(w, r.WithContext(ctx))
-						return
-					}
-
-					user, err := db.User.Get(ctx, userID)
-					if err == nil {
-						ctx = context.WithValue(ctx, auth.CurrentUserKey, user)
-						realClientIP := GetClientIP(r)
-						ctx = context.WithValue(ctx, auth.ClientIPKey, realClientIP)
-					}
+				started := time.Now()
+				var reason string
+				ctx, reason = authenticateToken(ctx, r, authService, tokenString, viaCookie)
+				result := "valid"
+				if reason != "" {
+					authService.RecordTokenValidationError(ctx, reason)
+					result = reason
 				}
+				slo.ObserveValidation(time.Since(started), result, service.ServiceSideTokenFailure(reason), r.Header.Get(auth.RequestIDHeader))
 			}
 			auth.DebugContext(ctx)
 			next.ServeHTTP(w, r.WithContext(ctx))
@@ -84,6 +64,91 @@ func AuthMiddleware(db *ent.Client, authService *service.AuthService) func(http.
 	}
 }
 
+// authenticateToken resolves the user of an access token into ctx, returning
+// the validation failure reason when it is rejected. Tokens of other types
+// pass through without a user.
+func authenticateToken(ctx context.Context, r *http.Request, authService *service.AuthService, tokenString string, viaCookie bool) (context.Context, string) {
+	logger := authService.Logger()
+	if authService.IsTokenBlacklisted(ctx, tokenString) {
+		logger.Info("Token is blacklisted")
+		authService.RecordSecurityEvent(ctx, service.SecurityEvent{
+			Action:    service.SecurityDenyListHit,
+			IP:        remoteIP(r),
+			Reason:    service.DenyListRevokedToken,
+			RequestID: r.Header.Get(auth.RequestIDHeader),
+		})
+		return auth.WithRevocation(ctx, string(model.RevocationReasonUserLogout)), service.TokenBlacklisted
+	}
+
+	claims, err := jwt.ValidateTokenContext(ctx, tokenString)
+	if err != nil {
+		logger.Info("Token validation failed", "error", err)
+		return ctx, service.TokenValidationReason(err)
+	}
+
+	if !claims.IsAccessToken() {
+		return ctx, ""
+	}
+
+	user, err := authService.ResolveUserReference(ctx, claims.Subject)
+	switch {
+	case err != nil:
+		logger.Warn("Invalid user reference in token claims", "error", err)
+		return ctx, service.TokenUnknownUser
+	case service.IsDeleted(user) || service.IsDeactivated(user):
+		return auth.WithRevocation(ctx, string(closedAccountReason(user))), service.TokenRevoked
+	case authService.IsTokenRevokedForUser(ctx, user.ID, issuedAt(claims)):
+		logger.Info("Token revoked by a forced re-login", "user_id", user.ID)
+		return auth.WithRevocation(ctx, string(authService.RevocationReasonFor(ctx, user.ID))), service.TokenRevoked
+	case claims.Maintenance != "":
+		grant, err := authService.ActiveMaintenanceGrant(ctx, claims.Maintenance, user.ID)
+		if err != nil {
+			logger.Warn("Maintenance token rejected", "grant", claims.Maintenance, "error", err)
+			return ctx, service.TokenRevoked
+		}
+		ctx = auth.WithUser(ctx, user, claims)
+		return auth.WithMaintenanceGrant(ctx, grant), ""
+	}
+
+	if err := authService.UpdateSessionActivity(ctx, user.ID); err == service.ErrSessionIdle {
+		logger.Info("Token rejected, the session was idle", "user_id", user.ID)
+		return auth.WithRevocation(ctx, string(model.RevocationReasonSessionIdle)), service.TokenIdle
+	}
+
+	ctx = auth.WithUser(ctx, user, claims)
+	if viaCookie {
+		ctx = auth.WithCookieSession(ctx, sessionBinding(r, claims), r.Header.Get(cookies.CSRFHeader))
+	}
+	return ctx, ""
+}
+
+// issuedAt treats tokens without iat as issued at the epoch, so any forced
+// re-login revokes them.
+func issuedAt(claims *jwt.Claims) time.Time {
+	if claims.IssuedAt == nil {
+		return time.Unix(0, 0)
+	}
+	return claims.IssuedAt.Time
+}
+
+// sessionBinding ties CSRF tokens to the browser session cookie, falling back
+// to the access token's ID when the session cookie is absent.
+func sessionBinding(r *http.Request, claims *jwt.Claims) string {
+	if cookie, err := r.Cookie(cookies.BrowserSessionTokenName); err == nil && cookie.Value != "" {
+		sum := sha256.Sum256([]byte(cookie.Value))
+		return hex.EncodeToString(sum[:])
+	}
+	return claims.ID
+}
+
+// remoteIP is the peer address of the request without its port.
+func remoteIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
 func stripTokeContext(authHeader string) (string, error) {
 	authHeader = strings.TrimSpace(authHeader)
 	if authHeader == "" {
@@ -102,22 +167,10 @@ func stripTokeContext(authHeader string) (string, error) {
 	return authHeader, nil
 }
 
-func GetClientIP(r *http.Request) string {
-
-	log.Printf("This is the X-forwarded-Host I got here: %s", r.Header.Get("X-Forwarded-For"))
-
-	if xForwardedFor := r.Header.Get("X-Forwarded-For"); xForwardedFor != "" {
-		ips := strings.Split(xForwardedFor, ",")
-		if len(ips) > 0 {
-
-			return strings.TrimSpace(ips[0])
-		}
-	}
-
-	ip, _, err := net.SplitHostPort(r.RemoteAddr)
-	log.Printf("This is the error from... SPLIT HOST..:%v", err)
-	if err != nil {
-		return r.RemoteAddr
+// closedAccountReason tells the client why a closed account's session ended.
+func closedAccountReason(u *ent.User) model.RevocationReason {
+	if service.IsDeleted(u) {
+		return model.RevocationReasonAccountDeleted
 	}
-	return ip
+	return model.RevocationReasonAccountDeactivated
 }
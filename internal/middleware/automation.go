@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/automation"
+	"github.com/gofiber/fiber/v2"
+)
+
+func AutomationMiddleware(registry *automation.Registry) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !registry.Enabled() {
+			return c.Next()
+		}
+
+		client, ok := registry.IdentifyKey(c.Get(registry.Header()))
+		if !ok {
+			client, ok = identifyPeerCertificate(c, registry)
+		}
+
+		if ok {
+			c.SetUserContext(auth.WithAutomationClient(c.UserContext(), client))
+		}
+
+		return c.Next()
+	}
+}
+
+func identifyPeerCertificate(c *fiber.Ctx, registry *automation.Registry) (string, bool) {
+	state := c.Context().TLSConnectionState()
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+	return registry.IdentifyCertificate(state.VerifiedChains[0][0].Subject.CommonName)
+}
@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"github.com/abisalde/authentication-service/pkg/useragent"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ClientHintsMiddleware asks browsers for the high-entropy client hints,
+// so sign-ins record the real OS and browser versions that Chromium no
+// longer puts in the User-Agent.
+func ClientHintsMiddleware(c *fiber.Ctx) error {
+	c.Set(useragent.HeaderAcceptClientHints, useragent.AcceptCH)
+	c.Vary(useragent.HeaderPlatformVersion, useragent.HeaderFullVersionList, useragent.HeaderModel)
+	return c.Next()
+}
@@ -3,7 +3,7 @@ package middleware
 import (
 	"context"
 	"errors"
-	"log"
+	"log/slog"
 
 	"github.com/99designs/gqlgen/graphql"
 	customErrors "github.com/abisalde/authentication-service/internal/graph/errors"
@@ -26,7 +26,7 @@ func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
 		return graphql.DefaultErrorPresenter(ctx, err)
 	}
 
-	log.Printf("Internal error: %+v", err)
+	slog.Error("Internal error", "error", err)
 	return &gqlerror.Error{
 		Message: "Internal Server Error",
 		Extensions: map[string]interface{}{
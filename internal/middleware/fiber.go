@@ -7,8 +7,12 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+// FiberWebMiddleware exposes the Fiber context and the client IP to
+// everything downstream. It runs after the net/http AuthMiddleware, whose
+// values reach it through the fasthttp request context.
 func FiberWebMiddleware(c *fiber.Ctx) error {
 	ctx := context.WithValue(c.Context(), auth.FiberContextWeb, c)
+	ctx = auth.WithClientIP(ctx, c.IP())
 	c.SetUserContext(ctx)
 	return c.Next()
 }
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	HTTPSModeRedirect = "redirect"
+	HTTPSModeReject   = "reject"
+)
+
+// HTTPSMiddleware enforces TLS for requests arriving through the trusted
+// proxy. Fiber only honours X-Forwarded-Proto when the peer is a trusted
+// proxy, so a client can't spoof its way past the check. HSTS is only sent
+// on requests that actually arrived over TLS.
+func HTTPSMiddleware(cfg *configs.Config) fiber.Handler {
+	policy := cfg.HTTPS
+	hsts := hstsHeader(policy.HSTS.MaxAge, policy.HSTS.IncludeSubdomains)
+
+	return func(c *fiber.Ctx) error {
+		if c.Secure() {
+			if policy.HSTS.Enabled {
+				c.Set(fiber.HeaderStrictTransportSecurity, hsts)
+			}
+			return c.Next()
+		}
+
+		if !policy.Enforce {
+			return c.Next()
+		}
+
+		if policy.Mode == HTTPSModeReject {
+			return c.Status(fiber.StatusUpgradeRequired).JSON(fiber.Map{
+				"error":   "https required",
+				"message": "this endpoint is only available over HTTPS",
+			})
+		}
+
+		// RequestURI is the path and query even when the request line
+		// carried an absolute URL, which OriginalURL would repeat verbatim.
+		target := "https://" + c.Hostname() + string(c.Request().URI().RequestURI())
+		return c.Redirect(target, fiber.StatusPermanentRedirect)
+	}
+}
+
+func hstsHeader(maxAge time.Duration, includeSubdomains bool) string {
+	if maxAge <= 0 {
+		maxAge = 180 * 24 * time.Hour
+	}
+	header := fmt.Sprintf("max-age=%d", int(maxAge.Seconds()))
+	if includeSubdomains {
+		header += "; includeSubDomains"
+	}
+	return header
+}
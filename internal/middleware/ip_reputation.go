@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/reputation"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// IPReputationMiddleware refuses clients whose IP the reputation feed scores
+// at or above the block threshold. Other assessments are stored as a local,
+// which the request context built by FiberWebMiddleware exposes to the
+// login and registration step-up and to the risk engine.
+func IPReputationMiddleware(authService *service.AuthService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		assessment := authService.AssessIP(c.UserContext(), c.IP())
+		if assessment.Decision == reputation.DecisionBlock {
+			authService.RecordSecurityEvent(c.UserContext(), service.SecurityEvent{
+				Action:    service.SecurityDenyListHit,
+				IP:        c.IP(),
+				Reason:    service.DenyListIPReputation,
+				RequestID: c.Get(auth.RequestIDHeader),
+			})
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error":   "ip blocked",
+				"message": "requests from this network are not accepted",
+			})
+		}
+
+		c.Locals(auth.IPReputationKey, &assessment)
+		return c.Next()
+	}
+}
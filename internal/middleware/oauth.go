@@ -5,9 +5,11 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+// OAuthStateMiddleware reads the state from the query, or from the form
+// body of callbacks posted with response_mode=form_post.
 func OAuthStateMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		state := c.Query("state")
+		state := c.FormValue("state")
 		if state != "" {
 			c.Locals(auth.OAuthStateKey, state)
 		}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/ratelimit"
+	"github.com/gofiber/fiber/v2"
+)
+
+// QuotaMiddleware counts every request of an API client against its daily
+// and monthly quotas. It must run after AutomationMiddleware; other requests
+// pass through untouched.
+func QuotaMiddleware(limiter *ratelimit.Limiter) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		client := auth.GetAutomationClient(c.UserContext())
+		if client == "" {
+			return c.Next()
+		}
+		return spendQuota(c, limiter, client, ratelimit.RequestDimensions...)
+	}
+}
+
+func spendQuota(c *fiber.Ctx, limiter *ratelimit.Limiter, client string, dimensions ...model.QuotaDimension) error {
+	exhausted, err := limiter.SpendQuota(c.UserContext(), client, dimensions...)
+	if err != nil {
+		slog.Error("Failed to spend client quota", "client", client, "error", err)
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+			"error":   "rate limited",
+			"message": "too many requests, try again later",
+		})
+	}
+	if exhausted == nil {
+		return c.Next()
+	}
+
+	retryAfter := int(time.Until(exhausted.ResetsAt).Seconds()) + 1
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+	return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+		"error":   "quota exceeded",
+		"message": "API client quota exhausted, try again after it resets",
+		"quota": fiber.Map{
+			"dimension": exhausted.Dimension,
+			"limit":     exhausted.Limit,
+			"remaining": 0,
+			"resetsAt":  exhausted.ResetsAt.UTC().Format(time.RFC3339),
+		},
+	})
+}
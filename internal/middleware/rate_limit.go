@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"log/slog"
+
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/internal/ratelimit"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RateLimitMiddleware counts requests against policy with the limiter behind
+// @rateLimit, so an HTTP endpoint and the GraphQL operation it mirrors draw
+// from one budget per client instead of granting one each.
+func RateLimitMiddleware(limiter *ratelimit.Limiter, policy ratelimit.Policy) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+		// As with @rateLimit, automation skips only the login budget.
+		if client := auth.GetAutomationClient(ctx); client != "" && policy.Operation == model.RateLimitMethodsLogin {
+			return spendQuota(c, limiter, client, model.QuotaDimensionLoginsPerHour)
+		}
+
+		allowed, err := limiter.Allow(ctx, policy, ratelimit.Client{
+			User: auth.GetCurrentUser(ctx),
+			IP:   c.IP(),
+		})
+		if err != nil {
+			slog.Error("Failed to rate limit", "operation", policy.Operation.String(), "error", err)
+		}
+		if err != nil || !allowed {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error":   "rate limited",
+				"message": "too many requests, try again later",
+			})
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// maxRequestIDLength bounds IDs forwarded by proxies, which end up in
+// metrics exemplars.
+const maxRequestIDLength = 64
+
+// RequestIDMiddleware keeps the X-Request-ID sent by the proxy, or assigns
+// one, and echoes it in the response. It is written back to the request so
+// the net/http middlewares see it too.
+func RequestIDMiddleware(c *fiber.Ctx) error {
+	id := c.Get(auth.RequestIDHeader)
+	if id == "" || len(id) > maxRequestIDLength {
+		id = uuid.NewString()
+	}
+	c.Request().Header.Set(auth.RequestIDHeader, id)
+	c.Set(auth.RequestIDHeader, id)
+	return c.Next()
+}
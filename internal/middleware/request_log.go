@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestLogMiddleware writes one access log record per request through
+// logger, so the client IP is masked like every other logged address. Behind
+// TracingMiddleware the record carries the trace ID.
+func RequestLogMiddleware(logger *slog.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		started := time.Now()
+		err := c.Next()
+		attrs := []any{
+			"method", c.Method(),
+			"path", c.Path(),
+			"status", c.Response().StatusCode(),
+			"ip", c.IP(),
+			"duration", time.Since(started),
+		}
+		if span := trace.SpanContextFromContext(c.UserContext()); span.IsValid() {
+			attrs = append(attrs, "trace_id", span.TraceID().String())
+		}
+		logger.Info("Request", attrs...)
+		return err
+	}
+}
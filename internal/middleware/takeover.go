@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SessionTakeoverMiddleware samples where each access token is used from.
+// It runs after FiberWebMiddleware so the client IP honours the trusted
+// proxy headers. A session restricted as taken over continues as an
+// anonymous request carrying the revocation reason.
+func SessionTakeoverMiddleware(authService *service.AuthService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+		identity := auth.IdentityFrom(ctx)
+		if !identity.Authenticated() || identity.Maintenance != nil {
+			return c.Next()
+		}
+
+		err := authService.CheckSessionUse(ctx, identity.User, identity.Claims, c.IP(), c.Get(fiber.HeaderUserAgent))
+		if err == service.ErrSessionTakeover {
+			ctx = auth.WithUser(ctx, nil, nil)
+			ctx = auth.WithRevocation(ctx, string(model.RevocationReasonSessionTakeover))
+			c.SetUserContext(ctx)
+		}
+		return c.Next()
+	}
+}
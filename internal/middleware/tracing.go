@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"github.com/abisalde/authentication-service/internal/auth"
+	"github.com/abisalde/authentication-service/internal/tracing"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/gofiber/fiber/v2/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingMiddleware starts the server span of each request, continuing the
+// trace of an incoming traceparent header. The span is put on the request
+// context as well as the user context, so net/http middleware such as
+// AuthMiddleware and the context FiberWebMiddleware builds carry it too.
+func TracingMiddleware(c *fiber.Ctx) error {
+	ctx := otel.GetTextMapPropagator().Extract(c.Context(), headerCarrier{c})
+	ctx, span := tracing.Tracer().Start(ctx, c.Method(),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("http.request.method", c.Method()),
+			attribute.String("url.path", c.Path()),
+			attribute.String("user_agent.original", c.Get(fiber.HeaderUserAgent)),
+			attribute.String("http.request_id", c.Get(auth.RequestIDHeader)),
+		),
+	)
+	defer span.End()
+
+	adaptor.CopyContextToFiberContext(ctx, c.Context())
+	c.SetUserContext(trace.ContextWithSpan(c.UserContext(), span))
+
+	err := c.Next()
+
+	// The route is only known once the request was routed.
+	span.SetName(c.Method() + " " + c.Route().Path)
+	span.SetAttributes(attribute.String("http.route", c.Route().Path))
+
+	status := c.Response().StatusCode()
+	if err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			status = fiberErr.Code
+		} else {
+			status = fiber.StatusInternalServerError
+		}
+		span.RecordError(err)
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", status))
+	if status >= fiber.StatusInternalServerError {
+		span.SetStatus(codes.Error, utils.StatusMessage(status))
+	}
+	return err
+}
+
+// headerCarrier reads propagation headers from the request.
+type headerCarrier struct {
+	c *fiber.Ctx
+}
+
+func (h headerCarrier) Get(key string) string {
+	return h.c.Get(key)
+}
+
+func (h headerCarrier) Set(key, value string) {
+	h.c.Request().Header.Set(key, value)
+}
+
+func (h headerCarrier) Keys() []string {
+	keys := make([]string, 0)
+	h.c.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Algorithm decides whether one more request fits a policy. key identifies
+// the counted client for one operation; implementations derive their own
+// Redis keys from it.
+type Algorithm interface {
+	Allow(ctx context.Context, rdb *redis.Client, key string, policy Policy) (bool, error)
+}
+
+// fixedWindow counts requests in consecutive windows aligned to the epoch.
+// Cheap, but lets up to twice the limit through around a window boundary.
+type fixedWindow struct{}
+
+func (fixedWindow) Allow(ctx context.Context, rdb *redis.Client, key string, policy Policy) (bool, error) {
+	bucket := time.Now().Unix() / int64(policy.Window.Seconds())
+	windowKey := fmt.Sprintf("%s:%d", key, bucket)
+
+	pipe := rdb.TxPipeline()
+	incr := pipe.Incr(ctx, windowKey)
+	pipe.Expire(ctx, windowKey, policy.Window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+
+	return incr.Val() <= policy.Limit, nil
+}
+
+// slidingWindowScript keeps one sorted set member per admitted request and
+// drops those older than the window. Rejected requests are not recorded.
+var slidingWindowScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], 0, now - window)
+if redis.call('ZCARD', KEYS[1]) >= tonumber(ARGV[3]) then
+	return 0
+end
+redis.call('ZADD', KEYS[1], now, ARGV[4])
+redis.call('PEXPIRE', KEYS[1], window)
+return 1
+`)
+
+// slidingWindow admits at most limit requests in any window-long span.
+type slidingWindow struct{}
+
+func (slidingWindow) Allow(ctx context.Context, rdb *redis.Client, key string, policy Policy) (bool, error) {
+	now := time.Now().UnixMilli()
+	member := fmt.Sprintf("%d-%x", now, rand.Uint32())
+
+	allowed, err := slidingWindowScript.Run(ctx, rdb, []string{key + ":sliding"},
+		now, policy.Window.Milliseconds(), policy.Limit, member).Int()
+	if err != nil {
+		return false, err
+	}
+	return allowed == 1, nil
+}
+
+// tokenBucketScript refills the bucket for the time since the last request,
+// then takes a token if one is left.
+var tokenBucketScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local capacity = tonumber(ARGV[2])
+local rate = tonumber(ARGV[3])
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or capacity
+local ts = tonumber(state[2]) or now
+tokens = math.min(capacity, tokens + (now - ts) * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tokens, 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(capacity / rate))
+return allowed
+`)
+
+// tokenBucket refills limit tokens per window up to burst, so a client that
+// has been quiet can spend a burst at once and then continues at the
+// steady rate.
+type tokenBucket struct{}
+
+func (tokenBucket) Allow(ctx context.Context, rdb *redis.Client, key string, policy Policy) (bool, error) {
+	rate := float64(policy.Limit) / float64(policy.Window.Milliseconds())
+
+	allowed, err := tokenBucketScript.Run(ctx, rdb, []string{key + ":bucket"},
+		time.Now().UnixMilli(), policy.Burst, rate).Int()
+	if err != nil {
+		return false, err
+	}
+	return allowed == 1, nil
+}
@@ -0,0 +1,198 @@
+// Package ratelimit counts requests against rate limit policies in Redis.
+// The @rateLimit directive and the HTTP middleware share one Limiter, so a
+// client spends a single budget per operation whichever endpoint it calls.
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/alerting"
+	"github.com/abisalde/authentication-service/internal/auth/probation"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+var ErrNotInitialized = errors.New("rate limiter not initialized")
+
+// Policy is one compiled @rateLimit.
+type Policy struct {
+	Operation model.RateLimitMethods
+	Limit     int64
+	Window    time.Duration
+	Key       model.RateLimitKey
+	// Burst is the token bucket capacity; it equals Limit unless set.
+	Burst     int64
+	Algorithm model.RateLimitAlgorithm
+}
+
+// Client is who a request is counted against.
+type Client struct {
+	User *ent.User
+	IP   string
+	// Email is the address the operation acts on, for EMAIL policies.
+	Email string
+}
+
+type Limiter struct {
+	redisCache *database.RedisCache
+	alerts     *alerting.Dispatcher
+	// attackRejections within attackWindow on one operation raise an alert.
+	attackRejections int64
+	attackWindow     time.Duration
+	algorithms       map[model.RateLimitAlgorithm]Algorithm
+	probation        probation.Policy
+	quotas           Quotas
+	onReject         func(context.Context, Policy, Client)
+}
+
+func New(redisCache *database.RedisCache) *Limiter {
+	return &Limiter{
+		redisCache: redisCache,
+		algorithms: map[model.RateLimitAlgorithm]Algorithm{
+			model.RateLimitAlgorithmFixedWindow:   fixedWindow{},
+			model.RateLimitAlgorithmSlidingWindow: slidingWindow{},
+			model.RateLimitAlgorithmTokenBucket:   tokenBucket{},
+		},
+	}
+}
+
+// WithAttackAlerts raises an alert once an operation rejects rejections
+// requests across all clients within window, a sign of a sustained attack
+// rather than one noisy client.
+func (l *Limiter) WithAttackAlerts(alerts *alerting.Dispatcher, rejections int, window time.Duration) *Limiter {
+	if alerts == nil || rejections <= 0 || window < time.Second {
+		return l
+	}
+	l.alerts = alerts
+	l.attackRejections = int64(rejections)
+	l.attackWindow = window
+	return l
+}
+
+// WithProbation divides limits for signed-in users still on probation.
+func (l *Limiter) WithProbation(policy probation.Policy) *Limiter {
+	l.probation = policy
+	return l
+}
+
+// OnReject calls fn with every rejected request, after its counter was
+// updated. fn runs on the request path and should return quickly.
+func (l *Limiter) OnReject(fn func(context.Context, Policy, Client)) *Limiter {
+	l.onReject = fn
+	return l
+}
+
+// WithAlgorithm replaces the implementation behind an algorithm name. Call it
+// before policies are compiled.
+func (l *Limiter) WithAlgorithm(name model.RateLimitAlgorithm, algorithm Algorithm) *Limiter {
+	l.algorithms[name] = algorithm
+	return l
+}
+
+// Supports reports whether policies may use the algorithm.
+func (l *Limiter) Supports(algorithm model.RateLimitAlgorithm) bool {
+	_, ok := l.algorithms[algorithm]
+	return ok
+}
+
+// Allow counts one request of the client against the policy and reports
+// whether it fits. Rejections count towards the attack alert.
+func (l *Limiter) Allow(ctx context.Context, policy Policy, client Client) (bool, error) {
+	if l.redisCache == nil {
+		return false, ErrNotInitialized
+	}
+
+	if limited := l.probation.RateLimit(client.User, policy.Limit); limited != policy.Limit {
+		policy.Limit = limited
+		policy.Burst = l.probation.RateLimit(client.User, policy.Burst)
+	}
+
+	allowed, err := l.algorithms[policy.Algorithm].Allow(ctx, l.redisCache.RawClient(), CounterKey(policy, client), policy)
+	if err != nil {
+		return false, err
+	}
+
+	if !allowed {
+		l.recordRejection(ctx, policy.Operation)
+		if l.onReject != nil {
+			l.onReject(ctx, policy, client)
+		}
+	}
+	return allowed, nil
+}
+
+// CounterKey names the counter of the client for the policy's operation. The
+// key depends only on the operation and the client, never on the endpoint,
+// which is what lets both layers share it.
+func CounterKey(policy Policy, client Client) string {
+	return fmt.Sprintf("rate_limit:%s:%s", policy.Operation.String(), identifier(policy.Key, client))
+}
+
+func identifier(key model.RateLimitKey, client Client) string {
+	switch key {
+	case model.RateLimitKeyGlobal:
+		return "global"
+	case model.RateLimitKeyIP:
+		if client.IP != "" {
+			return fmt.Sprintf("ip:%s", client.IP)
+		}
+		return "anonymous"
+	case model.RateLimitKeyEmail:
+		// Hashed so addresses never show up in Redis key names.
+		if client.Email != "" {
+			sum := sha256.Sum256([]byte(client.Email))
+			return "email:" + hex.EncodeToString(sum[:12])
+		}
+	}
+
+	switch {
+	case client.User != nil:
+		return fmt.Sprintf("user:%v", client.User.ID)
+	case client.IP != "":
+		return fmt.Sprintf("ip:%s", client.IP)
+	default:
+		return "anonymous"
+	}
+}
+
+// recordRejection counts rejections per operation in a window shared by every
+// instance; only the request that reaches the threshold raises the alert.
+func (l *Limiter) recordRejection(ctx context.Context, operation model.RateLimitMethods) {
+	if l.alerts == nil {
+		return
+	}
+
+	bucket := time.Now().Unix() / int64(l.attackWindow.Seconds())
+	key := fmt.Sprintf("rate_limit:rejections:%s:%d", operation.String(), bucket)
+
+	pipe := l.redisCache.RawClient().TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, l.attackWindow)
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Error("Failed to record a rate limit rejection", "error", err)
+		return
+	}
+
+	if incr.Val() != l.attackRejections {
+		return
+	}
+
+	l.alerts.Fire(alerting.Alert{
+		Source:   "rate_limit",
+		Title:    fmt.Sprintf("Sustained rate limiting on %s", operation.String()),
+		Summary:  fmt.Sprintf("%d requests rejected within %s", l.attackRejections, l.attackWindow),
+		Severity: alerting.SeverityWarning,
+		DedupKey: "rate_limit:" + operation.String(),
+		Details: map[string]string{
+			"operation": operation.String(),
+			"window":    l.attackWindow.String(),
+		},
+	})
+}
@@ -0,0 +1,165 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/redis/go-redis/v9"
+)
+
+// quotaGrace keeps a counter readable a little past its period, so usage
+// read right at the rollover does not race the expiry.
+const quotaGrace = time.Hour
+
+// RequestDimensions are what every request of an API client spends.
+var RequestDimensions = []model.QuotaDimension{model.QuotaDimensionRequestsPerDay, model.QuotaDimensionRequestsPerMonth}
+
+// Quotas cap what each API client may do, whatever IP it calls from. API
+// clients are exempt from the IP rate limits, so quotas are what bounds
+// them.
+type Quotas struct {
+	Default configs.ClientQuota
+	Clients map[string]configs.ClientQuota
+}
+
+func QuotasFromConfig(cfg *configs.Config) Quotas {
+	return Quotas{Default: cfg.Automation.Quotas.Default, Clients: cfg.Automation.Quotas.Clients}
+}
+
+// For returns the quota of the client.
+func (q Quotas) For(client string) configs.ClientQuota {
+	if quota, ok := q.Clients[client]; ok {
+		return quota
+	}
+	return q.Default
+}
+
+// QuotaUsage is how much of one dimension a client used in the period that
+// ends at ResetsAt.
+type QuotaUsage struct {
+	Dimension model.QuotaDimension
+	Limit     int64
+	Used      int64
+	ResetsAt  time.Time
+}
+
+func (u QuotaUsage) Remaining() int64 {
+	return max(u.Limit-u.Used, 0)
+}
+
+// WithClientQuotas enforces quotas on API clients.
+func (l *Limiter) WithClientQuotas(quotas Quotas) *Limiter {
+	l.quotas = quotas
+	return l
+}
+
+// SpendQuota counts one use of each dimension by the API client. When one
+// is exhausted nothing is counted and its usage is returned; a nil usage
+// means the request fits.
+func (l *Limiter) SpendQuota(ctx context.Context, client string, dimensions ...model.QuotaDimension) (*QuotaUsage, error) {
+	usage := l.limitedDimensions(client, time.Now(), dimensions)
+	if len(usage) == 0 {
+		return nil, nil
+	}
+	if l.redisCache == nil {
+		return nil, ErrNotInitialized
+	}
+
+	rdb := l.redisCache.RawClient()
+	pipe := rdb.TxPipeline()
+	counts := make([]*redis.IntCmd, len(usage))
+	for i, u := range usage {
+		key := quotaKey(client, u.Dimension, u.ResetsAt)
+		counts[i] = pipe.Incr(ctx, key)
+		pipe.ExpireAt(ctx, key, u.ResetsAt.Add(quotaGrace))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	var exhausted *QuotaUsage
+	for i := range usage {
+		usage[i].Used = counts[i].Val()
+		if exhausted == nil && usage[i].Used > usage[i].Limit {
+			exhausted = &usage[i]
+		}
+	}
+	if exhausted == nil {
+		return nil, nil
+	}
+
+	// Rejected requests do not count, or a client retrying in a loop would
+	// stay locked out past the period.
+	pipe = rdb.TxPipeline()
+	for _, u := range usage {
+		pipe.Decr(ctx, quotaKey(client, u.Dimension, u.ResetsAt))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	exhausted.Used = exhausted.Limit
+	return exhausted, nil
+}
+
+// QuotaUsage reads the usage of every limited dimension of the client.
+func (l *Limiter) QuotaUsage(ctx context.Context, client string) ([]QuotaUsage, error) {
+	usage := l.limitedDimensions(client, time.Now(), model.AllQuotaDimension)
+	if len(usage) == 0 {
+		return usage, nil
+	}
+	if l.redisCache == nil {
+		return nil, ErrNotInitialized
+	}
+
+	keys := make([]string, len(usage))
+	for i, u := range usage {
+		keys[i] = quotaKey(client, u.Dimension, u.ResetsAt)
+	}
+	values, err := l.redisCache.RawClient().MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			usage[i].Used, _ = strconv.ParseInt(s, 10, 64)
+		}
+	}
+	return usage, nil
+}
+
+// limitedDimensions returns the dimensions the client has a limit for, with
+// the end of their current period.
+func (l *Limiter) limitedDimensions(client string, now time.Time, dimensions []model.QuotaDimension) []QuotaUsage {
+	quota := l.quotas.For(client)
+	now = now.UTC()
+
+	usage := make([]QuotaUsage, 0, len(dimensions))
+	for _, dimension := range dimensions {
+		u := QuotaUsage{Dimension: dimension}
+		switch dimension {
+		case model.QuotaDimensionRequestsPerDay:
+			u.Limit = quota.RequestsPerDay
+			u.ResetsAt = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+		case model.QuotaDimensionRequestsPerMonth:
+			u.Limit = quota.RequestsPerMonth
+			u.ResetsAt = time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case model.QuotaDimensionLoginsPerHour:
+			u.Limit = quota.LoginsPerHour
+			u.ResetsAt = now.Truncate(time.Hour).Add(time.Hour)
+		}
+		if u.Limit > 0 {
+			usage = append(usage, u)
+		}
+	}
+	return usage
+}
+
+// quotaKey names the counter of one period, so a new period starts from a
+// fresh key and the old one expires on its own.
+func quotaKey(client string, dimension model.QuotaDimension, resetsAt time.Time) string {
+	return fmt.Sprintf("quota:%s:%s:%d", client, dimension.String(), resetsAt.Unix())
+}
@@ -0,0 +1,251 @@
+// Package redisbackup exports the auth state held in Redis, such as refresh
+// tokens, blacklisted tokens and pending registrations, to encrypted files
+// and restores it, so losing Redis does not sign every user out.
+package redisbackup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	formatVersion = 1
+	scanBatchSize = 1000
+	restoreBatch  = 500
+)
+
+// Manifest opens every backup.
+type Manifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Patterns  []string  `json:"patterns"`
+}
+
+// Entry is one key as returned by DUMP, with its absolute expiry in Unix
+// milliseconds, or 0 when it never expires.
+type Entry struct {
+	Key       string `json:"key"`
+	ExpiresAt int64  `json:"expires_at,omitempty"`
+	Value     []byte `json:"value"`
+}
+
+type ExportOptions struct {
+	Patterns []string
+	// Batch reads keys in MULTI/EXEC transactions of this many keys. 0 reads
+	// every key in one transaction, the only point-in-time snapshot, at the
+	// cost of blocking Redis while it runs.
+	Batch int
+}
+
+type ImportOptions struct {
+	// Replace overwrites keys that already exist. By default they are kept,
+	// since they were written after the backup was taken.
+	Replace bool
+}
+
+type Stats struct {
+	// Keys counts the keys written to or restored from the backup.
+	Keys int
+	// Existing counts the keys an import kept because they already existed.
+	Existing int
+	// Expired counts the keys that expired before they were read or
+	// restored.
+	Expired int
+}
+
+// Export writes every key matching the patterns to w, encrypted with key.
+// Keys are listed with SCAN first, then read with DUMP and PTTL inside
+// transactions, so a key is never captured half-written.
+func Export(ctx context.Context, client *redis.Client, w io.Writer, key []byte, opts ExportOptions) (Stats, error) {
+	var stats Stats
+	if client == nil {
+		return stats, fmt.Errorf("redis client not initialized")
+	}
+
+	keys, err := scanKeys(ctx, client, opts.Patterns)
+	if err != nil {
+		return stats, err
+	}
+
+	sealed, err := NewEncryptWriter(w, key)
+	if err != nil {
+		return stats, err
+	}
+	encoder := json.NewEncoder(sealed)
+	if err := encoder.Encode(Manifest{Version: formatVersion, CreatedAt: time.Now().UTC(), Patterns: opts.Patterns}); err != nil {
+		return stats, err
+	}
+
+	batch := opts.Batch
+	if batch <= 0 {
+		batch = len(keys)
+	}
+	for start := 0; start < len(keys); start += batch {
+		entries, expired, err := dumpKeys(ctx, client, keys[start:min(start+batch, len(keys))])
+		if err != nil {
+			return stats, err
+		}
+		stats.Expired += expired
+
+		for _, entry := range entries {
+			if err := encoder.Encode(entry); err != nil {
+				return stats, err
+			}
+			stats.Keys++
+		}
+	}
+
+	return stats, sealed.Close()
+}
+
+// Import restores a backup written by Export. Keys that expired since the
+// backup are skipped; the others keep their original expiry.
+func Import(ctx context.Context, client *redis.Client, r io.Reader, key []byte, opts ImportOptions) (Manifest, Stats, error) {
+	var (
+		manifest Manifest
+		stats    Stats
+	)
+	if client == nil {
+		return manifest, stats, fmt.Errorf("redis client not initialized")
+	}
+
+	opened, err := NewDecryptReader(r, key)
+	if err != nil {
+		return manifest, stats, err
+	}
+	decoder := json.NewDecoder(opened)
+	if err := decoder.Decode(&manifest); err != nil {
+		return manifest, stats, fmt.Errorf("failed to read the backup manifest: %w", err)
+	}
+	if manifest.Version != formatVersion {
+		return manifest, stats, fmt.Errorf("unsupported backup version %d", manifest.Version)
+	}
+
+	entries := make([]Entry, 0, restoreBatch)
+	for {
+		var entry Entry
+		err := decoder.Decode(&entry)
+		if err != nil && err != io.EOF {
+			return manifest, stats, fmt.Errorf("failed to read backup entry: %w", err)
+		}
+		if err == nil {
+			entries = append(entries, entry)
+		}
+
+		if len(entries) == restoreBatch || (err == io.EOF && len(entries) > 0) {
+			if err := restoreKeys(ctx, client, entries, opts.Replace, &stats); err != nil {
+				return manifest, stats, err
+			}
+			entries = entries[:0]
+		}
+		if err == io.EOF {
+			return manifest, stats, nil
+		}
+	}
+}
+
+func scanKeys(ctx context.Context, client *redis.Client, patterns []string) ([]string, error) {
+	seen := make(map[string]struct{})
+	var keys []string
+	for _, pattern := range patterns {
+		iter := client.Scan(ctx, 0, pattern, scanBatchSize).Iterator()
+		for iter.Next(ctx) {
+			if _, ok := seen[iter.Val()]; !ok {
+				seen[iter.Val()] = struct{}{}
+				keys = append(keys, iter.Val())
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", pattern, err)
+		}
+	}
+	return keys, nil
+}
+
+func dumpKeys(ctx context.Context, client *redis.Client, keys []string) ([]Entry, int, error) {
+	dumps := make([]*redis.StringCmd, len(keys))
+	ttls := make([]*redis.DurationCmd, len(keys))
+	now := time.Now()
+	_, err := client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			dumps[i] = pipe.Dump(ctx, key)
+			ttls[i] = pipe.PTTL(ctx, key)
+		}
+		return nil
+	})
+	// DUMP answers nil for keys that expired since the scan.
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, 0, fmt.Errorf("failed to dump keys: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(keys))
+	expired := 0
+	for i, key := range keys {
+		value, err := dumps[i].Bytes()
+		ttl := ttls[i].Val()
+		if errors.Is(err, redis.Nil) || ttl == -2 {
+			expired++
+			continue
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to dump %s: %w", key, err)
+		}
+
+		entry := Entry{Key: key, Value: value}
+		if ttl > 0 {
+			entry.ExpiresAt = now.Add(ttl).UnixMilli()
+		}
+		entries = append(entries, entry)
+	}
+	return entries, expired, nil
+}
+
+func restoreKeys(ctx context.Context, client *redis.Client, entries []Entry, replace bool, stats *Stats) error {
+	now := time.Now()
+	cmds := make([]*redis.StatusCmd, len(entries))
+	_, err := client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, entry := range entries {
+			var ttl time.Duration
+			if entry.ExpiresAt > 0 {
+				ttl = time.UnixMilli(entry.ExpiresAt).Sub(now)
+				if ttl < time.Millisecond {
+					continue
+				}
+			}
+			if replace {
+				cmds[i] = pipe.RestoreReplace(ctx, entry.Key, ttl, string(entry.Value))
+			} else {
+				cmds[i] = pipe.Restore(ctx, entry.Key, ttl, string(entry.Value))
+			}
+		}
+		return nil
+	})
+	if err != nil && !isBusyKey(err) {
+		return fmt.Errorf("failed to restore keys: %w", err)
+	}
+
+	for i, cmd := range cmds {
+		switch {
+		case cmd == nil:
+			stats.Expired++
+		case isBusyKey(cmd.Err()):
+			stats.Existing++
+		case cmd.Err() != nil:
+			return fmt.Errorf("failed to restore %s: %w", entries[i].Key, cmd.Err())
+		default:
+			stats.Keys++
+		}
+	}
+	return nil
+}
+
+func isBusyKey(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "BUSYKEY")
+}
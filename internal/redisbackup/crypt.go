@@ -0,0 +1,207 @@
+package redisbackup
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Backup files start with a magic string and a random nonce base, followed
+// by frames of at most frameSize plaintext bytes. Each frame is a flag byte,
+// the big-endian ciphertext length and the AES-256-GCM ciphertext. The
+// frame's counter and whether it is the last one are authenticated, so
+// reordered, dropped or truncated frames fail to open.
+const (
+	fileMagic = "AUTHRDB1"
+	frameSize = 64 << 10
+	keySize   = 32
+
+	frameMore byte = 0
+	frameLast byte = 1
+)
+
+var (
+	ErrInvalidKey = errors.New("backup key must be 32 bytes, base64 encoded")
+	ErrNotBackup  = errors.New("not a Redis backup file")
+	ErrTruncated  = errors.New("backup file is truncated")
+	ErrCorrupt    = errors.New("backup file is corrupt or was encrypted with another key")
+)
+
+// ParseKey decodes a base64 encoded 32-byte key, as printed by
+// `openssl rand -base64 32`.
+func ParseKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != keySize {
+		return nil, ErrInvalidKey
+	}
+	return key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != keySize {
+		return nil, ErrInvalidKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+type sealer struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	base    []byte
+	counter uint64
+	buf     []byte
+	closed  bool
+}
+
+// NewEncryptWriter encrypts everything written to it into w. Close writes
+// the last frame and must be called for the file to open.
+func NewEncryptWriter(w io.Writer, key []byte) (io.WriteCloser, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	base := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(base); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(append([]byte(fileMagic), base...)); err != nil {
+		return nil, err
+	}
+	return &sealer{w: w, aead: aead, base: base, buf: make([]byte, 0, frameSize)}, nil
+}
+
+func (s *sealer) Write(p []byte) (int, error) {
+	if s.closed {
+		return 0, errors.New("write to closed backup")
+	}
+
+	written := 0
+	for len(p) > 0 {
+		n := min(frameSize-len(s.buf), len(p))
+		s.buf = append(s.buf, p[:n]...)
+		p = p[n:]
+		written += n
+
+		// A full buffer is only flushed once more data arrives, so the
+		// last frame is never empty unless the whole backup is.
+		if len(s.buf) == frameSize && len(p) > 0 {
+			if err := s.flush(frameMore); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+func (s *sealer) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return s.flush(frameLast)
+}
+
+func (s *sealer) flush(flag byte) error {
+	sealed := s.aead.Seal(nil, frameNonce(s.base, s.counter), s.buf, frameAAD(s.counter, flag))
+	s.counter++
+	s.buf = s.buf[:0]
+
+	header := make([]byte, 5)
+	header[0] = flag
+	binary.BigEndian.PutUint32(header[1:], uint32(len(sealed)))
+	if _, err := s.w.Write(header); err != nil {
+		return err
+	}
+	_, err := s.w.Write(sealed)
+	return err
+}
+
+type opener struct {
+	r       io.Reader
+	aead    cipher.AEAD
+	base    []byte
+	counter uint64
+	plain   []byte
+	done    bool
+}
+
+// NewDecryptReader reads a file written by NewEncryptWriter. Reads fail
+// with ErrCorrupt when a frame does not authenticate and ErrTruncated when
+// the file ends before its last frame.
+func NewDecryptReader(r io.Reader, key []byte) (io.Reader, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, len(fileMagic)+aead.NonceSize())
+	if _, err := io.ReadFull(r, header); err != nil || string(header[:len(fileMagic)]) != fileMagic {
+		return nil, ErrNotBackup
+	}
+	return &opener{r: r, aead: aead, base: header[len(fileMagic):]}, nil
+}
+
+func (o *opener) Read(p []byte) (int, error) {
+	for len(o.plain) == 0 {
+		if o.done {
+			return 0, io.EOF
+		}
+		if err := o.next(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, o.plain)
+	o.plain = o.plain[n:]
+	return n, nil
+}
+
+func (o *opener) next() error {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(o.r, header); err != nil {
+		return ErrTruncated
+	}
+	flag, size := header[0], binary.BigEndian.Uint32(header[1:])
+	if flag != frameMore && flag != frameLast || size > frameSize+uint32(o.aead.Overhead()) {
+		return ErrCorrupt
+	}
+
+	sealed := make([]byte, size)
+	if _, err := io.ReadFull(o.r, sealed); err != nil {
+		return ErrTruncated
+	}
+	plain, err := o.aead.Open(nil, frameNonce(o.base, o.counter), sealed, frameAAD(o.counter, flag))
+	if err != nil {
+		return fmt.Errorf("frame %d: %w", o.counter, ErrCorrupt)
+	}
+
+	o.counter++
+	o.plain = plain
+	o.done = flag == frameLast
+	return nil
+}
+
+func frameNonce(base []byte, counter uint64) []byte {
+	nonce := append([]byte(nil), base...)
+	tail := nonce[len(nonce)-8:]
+	binary.BigEndian.PutUint64(tail, binary.BigEndian.Uint64(tail)^counter)
+	return nonce
+}
+
+func frameAAD(counter uint64, flag byte) []byte {
+	aad := make([]byte, len(fileMagic)+9)
+	copy(aad, fileMagic)
+	binary.BigEndian.PutUint64(aad[len(fileMagic):], counter)
+	aad[len(aad)-1] = flag
+	return aad
+}
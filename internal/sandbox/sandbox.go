@@ -0,0 +1,131 @@
+package sandbox
+
+import (
+	"strings"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+)
+
+const (
+	DefaultVerificationCode = "0000"
+	// OAuthCodePrefix marks a stubbed OAuth callback; the rest of the code is
+	// the email of the user the provider would have returned.
+	OAuthCodePrefix = "sandbox:"
+)
+
+// Sandbox makes end-to-end tests and local frontends deterministic:
+// allow-listed emails get a fixed verification code and OAuth providers can be
+// stubbed so no real provider round trip is needed. It is refused in
+// production by configs.Load.
+type Sandbox struct {
+	enabled   bool
+	code      string
+	stubOAuth bool
+	tokens    bool
+	emails    map[string]bool
+	// ordered keeps the exact entries of emails in config order.
+	ordered []string
+	domains []string
+}
+
+func New(cfg *configs.Config) *Sandbox {
+	s := &Sandbox{code: DefaultVerificationCode, emails: make(map[string]bool)}
+	if cfg == nil || !cfg.Sandbox.Enabled {
+		return s
+	}
+
+	s.enabled = true
+	s.stubOAuth = cfg.Sandbox.StubOAuth
+	s.tokens = cfg.Sandbox.TestTokens
+	if cfg.Sandbox.VerificationCode != "" {
+		s.code = cfg.Sandbox.VerificationCode
+	}
+
+	for _, entry := range cfg.Sandbox.AllowedEmails {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+		case strings.HasPrefix(entry, "@"):
+			s.domains = append(s.domains, entry)
+		case !s.emails[entry]:
+			s.emails[entry] = true
+			s.ordered = append(s.ordered, entry)
+		}
+	}
+
+	return s
+}
+
+func (s *Sandbox) Enabled() bool {
+	return s != nil && s.enabled
+}
+
+// IsAllowed reports whether the email is an allow-listed test identity,
+// either exactly or through an "@domain" entry.
+func (s *Sandbox) IsAllowed(email string) bool {
+	if !s.Enabled() {
+		return false
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	if s.emails[email] {
+		return true
+	}
+	for _, domain := range s.domains {
+		if strings.HasSuffix(email, domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// VerificationCode returns the deterministic code for allow-listed emails.
+func (s *Sandbox) VerificationCode(email string) (string, bool) {
+	if !s.IsAllowed(email) {
+		return "", false
+	}
+	return s.code, true
+}
+
+func (s *Sandbox) StubOAuth() bool {
+	return s.Enabled() && s.stubOAuth
+}
+
+// TestTokens reports whether allow-listed emails can be issued tokens
+// directly, skipping registration and email verification.
+func (s *Sandbox) TestTokens() bool {
+	return s.Enabled() && s.tokens
+}
+
+// DefaultOAuthEmail is the first exact allow-listed email, used to prefill
+// stubbed provider redirects.
+func (s *Sandbox) DefaultOAuthEmail() string {
+	if len(s.ordered) == 0 {
+		return ""
+	}
+	return s.ordered[0]
+}
+
+// StubOAuthUser builds the provider profile for a stubbed callback code.
+func (s *Sandbox) StubOAuthUser(provider, code string) (*model.OAuthUserResponse, bool) {
+	if !s.StubOAuth() || !strings.HasPrefix(code, OAuthCodePrefix) {
+		return nil, false
+	}
+
+	email := strings.TrimPrefix(code, OAuthCodePrefix)
+	if !s.IsAllowed(email) {
+		return nil, false
+	}
+
+	local, _, _ := strings.Cut(email, "@")
+	name := local
+	return &model.OAuthUserResponse{
+		ID:              strings.ToLower(provider) + "-sandbox-" + strings.ToLower(email),
+		Email:           email,
+		Name:            &name,
+		FirstName:       local,
+		LastName:        "Sandbox",
+		IsEmailVerified: true,
+	}, true
+}
@@ -0,0 +1,119 @@
+package siem
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Format is how events are written for the collector.
+type Format string
+
+const (
+	// FormatCEF writes ArcSight Common Event Format lines.
+	FormatCEF Format = "cef"
+	// FormatJSONLines writes one JSON object per line.
+	FormatJSONLines Format = "jsonl"
+)
+
+const (
+	cefVendor  = "abisalde"
+	cefProduct = "authentication-service"
+	// cefCustomStrings is how many cs1..cs6 pairs CEF defines for fields
+	// without a standard key.
+	cefCustomStrings = 6
+)
+
+// ParseFormat accepts cef, jsonl and json; the empty string is CEF.
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "cef":
+		return FormatCEF, nil
+	case "jsonl", "json":
+		return FormatJSONLines, nil
+	default:
+		return "", fmt.Errorf("unknown SIEM format %q", s)
+	}
+}
+
+// Encode writes the event as one line, without the trailing newline.
+func (f Format) Encode(e Event) ([]byte, error) {
+	if f == FormatJSONLines {
+		return json.Marshal(e)
+	}
+	return []byte(cef(e)), nil
+}
+
+// ContentType is the media type of a batch of lines.
+func (f Format) ContentType() string {
+	if f == FormatJSONLines {
+		return "application/x-ndjson"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// cef maps the event onto standard extension keys: rt, externalId (the
+// stream entry, for deduplicating redeliveries), suid, suser, src, outcome
+// and reason. The request ID and other fields go into custom strings.
+func cef(e Event) string {
+	version := e.Revision
+	if version == "" {
+		version = "1"
+	}
+
+	var b strings.Builder
+	b.WriteString("CEF:0")
+	for _, field := range []string{cefVendor, cefProduct, version, e.Action, e.Name, strconv.Itoa(e.Severity)} {
+		b.WriteByte('|')
+		b.WriteString(cefHeaderEscaper.Replace(field))
+	}
+	b.WriteByte('|')
+
+	ext := []string{
+		"rt", strconv.FormatInt(e.Time.UnixMilli(), 10),
+		"externalId", e.Stream + ":" + e.ID,
+		"cat", e.Type,
+	}
+	if e.UserID != 0 {
+		ext = append(ext, "suid", strconv.FormatInt(e.UserID, 10))
+	}
+	for _, field := range [][2]string{{"suser", e.Email}, {"src", e.IP}, {"outcome", e.Outcome}, {"reason", e.Reason}} {
+		if field[1] != "" {
+			ext = append(ext, field[0], field[1])
+		}
+	}
+
+	custom := make([][2]string, 0, cefCustomStrings)
+	if e.RequestID != "" {
+		custom = append(custom, [2]string{"requestId", e.RequestID})
+	}
+	keys := make([]string, 0, len(e.Fields))
+	for key := range e.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		custom = append(custom, [2]string{key, e.Fields[key]})
+	}
+	for i, field := range custom[:min(len(custom), cefCustomStrings)] {
+		n := strconv.Itoa(i + 1)
+		ext = append(ext, "cs"+n+"Label", field[0], "cs"+n, field[1])
+	}
+
+	for i := 0; i < len(ext); i += 2 {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(ext[i])
+		b.WriteByte('=')
+		b.WriteString(cefExtensionEscaper.Replace(ext[i+1]))
+	}
+	return b.String()
+}
+
+var (
+	cefHeaderEscaper    = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ")
+	cefExtensionEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`)
+)
@@ -0,0 +1,360 @@
+// Package siem exports security and audit events to a SIEM collector for
+// the SOC. Events are read from the Redis streams the service appends them
+// to and delivered in batches; a stream's cursor only moves once the
+// collector accepted a batch, so while it is slow or down events wait in
+// their streams and are delivered at least once.
+package siem
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/events"
+	"github.com/abisalde/authentication-service/internal/metrics"
+	"github.com/redis/go-redis/v9"
+)
+
+// CursorsKey holds the ID of the last delivered entry of every stream.
+const CursorsKey = "siem_cursors"
+
+const (
+	defaultBatchSize  = 500
+	defaultMaxBatches = 20
+	defaultTimeout    = 10 * time.Second
+)
+
+// DefaultStreams are the streams exported: sign-ins, lockouts and deny-list
+// hits, revocations, scheduled account actions and email changes.
+var DefaultStreams = []string{
+	service.SecurityEventStreamKey,
+	service.RevocationStreamKey,
+	service.AccountActionStreamKey,
+	service.EmailChangeStreamKey,
+}
+
+var (
+	eventsExported = metrics.Default.NewCounterVec(
+		"siem_events_exported_total",
+		"Events delivered to the SIEM collector, by action.",
+		"action",
+	)
+	eventsSkipped = metrics.Default.NewCounterVec(
+		"siem_events_skipped_total",
+		"Stream entries skipped by the SIEM export because they could not be decoded.",
+		"stream",
+	)
+	batchDeliveries = metrics.Default.NewCounterVec(
+		"siem_batches_total",
+		"Batches sent to the SIEM collector, by sink and result: success or failure.",
+		"sink", "result",
+	)
+	deliveryDuration = metrics.Default.NewHistogramVec(
+		"siem_delivery_duration_seconds",
+		"Time taken to send a batch to the SIEM collector.",
+		nil, "sink",
+	)
+	exportLag = metrics.Default.NewGaugeVec(
+		"siem_export_lag_seconds",
+		"Age of the oldest event not yet delivered to the SIEM collector, as of the last export; 0 when caught up.",
+	)
+)
+
+// Event is a stream entry normalized for the collector. Action is the
+// security event's action or, for audit streams, the event type; fields
+// without a dedicated place are kept as strings in Fields.
+type Event struct {
+	ID        string            `json:"id"`
+	Stream    string            `json:"stream"`
+	Type      string            `json:"type"`
+	Action    string            `json:"action"`
+	Name      string            `json:"name"`
+	Severity  int               `json:"severity"`
+	Outcome   string            `json:"outcome,omitempty"`
+	Time      time.Time         `json:"timestamp"`
+	UserID    int64             `json:"user_id,omitempty"`
+	Email     string            `json:"email,omitempty"`
+	IP        string            `json:"ip,omitempty"`
+	Reason    string            `json:"reason,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
+	Revision  string            `json:"revision,omitempty"`
+	Fields    map[string]string `json:"fields,omitempty"`
+}
+
+// catalog names each action and rates it on the CEF 0-10 severity scale.
+var catalog = map[string]struct {
+	name     string
+	severity int
+	outcome  string
+}{
+	service.SecurityLoginSucceeded: {"Login succeeded", 3, "success"},
+	service.SecurityLoginFailed:    {"Login failed", 5, "failure"},
+	service.SecurityLockout:        {"Client locked out by rate limiting", 7, "failure"},
+	service.SecurityDenyListHit:    {"Request refused by a deny list", 8, "failure"},
+	events.TokenRevoked:            {"Tokens revoked", 5, "success"},
+	events.EmailChanged:            {"Email changed", 6, "success"},
+	events.AccountAction:           {"Scheduled account action", 5, ""},
+}
+
+// Exporter delivers new stream entries to a sink. Run Export from one
+// replica at a time, such as a scheduled job.
+type Exporter struct {
+	rdb        *redis.Client
+	sink       Sink
+	format     Format
+	streams    []string
+	batchSize  int
+	maxBatches int
+}
+
+// New builds the exporter from config. It returns nil when the export is
+// disabled.
+func New(cfg *configs.Config, rdb *redis.Client) (*Exporter, error) {
+	if cfg == nil || !cfg.SIEM.Enabled {
+		return nil, nil
+	}
+	if cfg.SIEM.Endpoint == "" {
+		return nil, fmt.Errorf("SIEM export is enabled without an endpoint")
+	}
+
+	format, err := ParseFormat(cfg.SIEM.Format)
+	if err != nil {
+		return nil, err
+	}
+	timeout := cfg.SIEM.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	sink, err := NewSink(cfg.SIEM.Endpoint, format, cfg.SIEM.Authorization, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return NewExporter(rdb, sink, format, cfg.SIEM.BatchSize, cfg.SIEM.MaxBatches), nil
+}
+
+// NewExporter exports DefaultStreams in batches of batchSize events, at most
+// maxBatches per Export. Zero values take the defaults.
+func NewExporter(rdb *redis.Client, sink Sink, format Format, batchSize, maxBatches int) *Exporter {
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	if maxBatches <= 0 {
+		maxBatches = defaultMaxBatches
+	}
+	return &Exporter{
+		rdb:        rdb,
+		sink:       sink,
+		format:     format,
+		streams:    DefaultStreams,
+		batchSize:  batchSize,
+		maxBatches: maxBatches,
+	}
+}
+
+// Export delivers batches until the streams are drained, a batch fails or
+// maxBatches were sent, and returns how many events were delivered. A
+// failed batch is sent again by the next Export.
+func (e *Exporter) Export(ctx context.Context) (int, error) {
+	exported := 0
+	for range e.maxBatches {
+		batch, cursors, more, err := e.read(ctx)
+		if err != nil {
+			return exported, err
+		}
+		if len(batch) > 0 {
+			if err := e.deliver(ctx, batch); err != nil {
+				exportLag.Set(time.Since(batch[0].Time).Seconds())
+				return exported, err
+			}
+		}
+		if len(cursors) > 0 {
+			if err := e.rdb.HSet(ctx, CursorsKey, cursors).Err(); err != nil {
+				return exported, fmt.Errorf("failed to save SIEM cursors: %w", err)
+			}
+		}
+		exported += len(batch)
+
+		if !more {
+			exportLag.Set(0)
+			return exported, nil
+		}
+		if len(batch) > 0 {
+			exportLag.Set(time.Since(batch[len(batch)-1].Time).Seconds())
+		}
+	}
+	return exported, nil
+}
+
+func (e *Exporter) deliver(ctx context.Context, batch []Event) error {
+	lines := make([][]byte, 0, len(batch))
+	for _, event := range batch {
+		line, err := e.format.Encode(event)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, line)
+	}
+
+	started := time.Now()
+	err := e.sink.Send(ctx, lines)
+	deliveryDuration.Observe(time.Since(started).Seconds(), e.sink.Name())
+	if err != nil {
+		batchDeliveries.Inc(e.sink.Name(), "failure")
+		return fmt.Errorf("failed to deliver %d events to the SIEM collector: %w", len(batch), err)
+	}
+	batchDeliveries.Inc(e.sink.Name(), "success")
+	for _, event := range batch {
+		eventsExported.Inc(event.Action)
+	}
+	return nil
+}
+
+// read returns the oldest undelivered entries of all streams, at most
+// batchSize, the cursors to save once they are delivered and whether
+// entries are left behind them.
+func (e *Exporter) read(ctx context.Context) ([]Event, map[string]string, bool, error) {
+	cursors, err := e.rdb.HGetAll(ctx, CursorsKey).Result()
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to load SIEM cursors: %w", err)
+	}
+
+	args := make([]string, 0, 2*len(e.streams))
+	args = append(args, e.streams...)
+	for _, stream := range e.streams {
+		cursor := cursors[stream]
+		if cursor == "" {
+			cursor = "0"
+		}
+		args = append(args, cursor)
+	}
+
+	streams, err := e.rdb.XRead(ctx, &redis.XReadArgs{Streams: args, Count: int64(e.batchSize), Block: -1}).Result()
+	if err == redis.Nil {
+		return nil, nil, false, nil
+	}
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to read events for the SIEM: %w", err)
+	}
+
+	type entry struct {
+		stream string
+		msg    redis.XMessage
+	}
+	var entries []entry
+	for _, stream := range streams {
+		for _, msg := range stream.Messages {
+			entries = append(entries, entry{stream.Stream, msg})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return streamIDLess(entries[i].msg.ID, entries[j].msg.ID)
+	})
+
+	more := len(entries) >= e.batchSize
+	entries = entries[:min(len(entries), e.batchSize)]
+
+	batch := make([]Event, 0, len(entries))
+	next := make(map[string]string)
+	for _, entry := range entries {
+		next[entry.stream] = entry.msg.ID
+		event, err := Decode(entry.stream, entry.msg)
+		if err != nil {
+			eventsSkipped.Inc(entry.stream)
+			continue
+		}
+		batch = append(batch, event)
+	}
+	return batch, next, more, nil
+}
+
+// Decode normalizes an entry of one of the service's event streams.
+func Decode(stream string, msg redis.XMessage) (Event, error) {
+	raw, _ := msg.Values["event"].(string)
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	decoder.UseNumber()
+	var payload map[string]interface{}
+	if err := decoder.Decode(&payload); err != nil {
+		return Event{}, fmt.Errorf("entry %s of %s: %w", msg.ID, stream, err)
+	}
+
+	event := Event{ID: msg.ID, Stream: stream, Fields: make(map[string]string)}
+	event.Time = streamIDTime(msg.ID)
+	if ts, ok := payload["timestamp"].(string); ok {
+		if parsed, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			event.Time = parsed
+		}
+	}
+	if producer, ok := payload["producer"].(map[string]interface{}); ok {
+		event.Revision, _ = producer["revision"].(string)
+	}
+
+	event.Type, _ = payload["event_type"].(string)
+	event.Action = event.Type
+	if event.Type == events.SecurityEvent {
+		event.Action, _ = payload["action"].(string)
+		delete(payload, "action")
+	}
+	if id, ok := payload["user_id"].(json.Number); ok {
+		event.UserID, _ = id.Int64()
+	}
+	event.Email, _ = payload["email"].(string)
+	event.IP, _ = payload["ip"].(string)
+	event.Reason, _ = payload["reason"].(string)
+	event.RequestID, _ = payload["request_id"].(string)
+
+	for _, key := range []string{"schema_version", "producer", "event_type", "timestamp", "user_id", "email", "ip", "reason", "request_id"} {
+		delete(payload, key)
+	}
+	for key, value := range payload {
+		event.Fields[key] = fieldString(value)
+	}
+
+	if entry, ok := catalog[event.Action]; ok {
+		event.Name, event.Severity, event.Outcome = entry.name, entry.severity, entry.outcome
+	} else {
+		event.Name, event.Severity = event.Action, 5
+	}
+	return event, nil
+}
+
+func fieldString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case nil:
+		return ""
+	default:
+		var b bytes.Buffer
+		_ = json.NewEncoder(&b).Encode(v)
+		return strings.TrimSpace(b.String())
+	}
+}
+
+// streamIDTime is when the entry was appended, from the millisecond part of
+// its ID.
+func streamIDTime(id string) time.Time {
+	ms, _, _ := strings.Cut(id, "-")
+	n, err := strconv.ParseInt(ms, 10, 64)
+	if err != nil {
+		return time.Now()
+	}
+	return time.UnixMilli(n)
+}
+
+func streamIDLess(a, b string) bool {
+	aMs, aSeq, _ := strings.Cut(a, "-")
+	bMs, bSeq, _ := strings.Cut(b, "-")
+	if aMs != bMs {
+		return len(aMs) < len(bMs) || len(aMs) == len(bMs) && aMs < bMs
+	}
+	return len(aSeq) < len(bSeq) || len(aSeq) == len(bSeq) && aSeq < bSeq
+}
@@ -0,0 +1,156 @@
+package siem
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Sink delivers a batch of encoded events to the collector. A batch either
+// fails as a whole or is delivered.
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, lines [][]byte) error
+}
+
+// NewSink picks the sink from the endpoint's scheme: http(s) posts batches,
+// tcp, udp and tls write RFC 5424 syslog messages.
+func NewSink(endpoint string, format Format, authorization string, timeout time.Duration) (Sink, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SIEM endpoint: %w", err)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		return NewHTTPSink(endpoint, format, authorization, timeout), nil
+	case "tcp", "udp", "tls":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid SIEM endpoint %q: missing host", endpoint)
+		}
+		return NewSyslogSink(u.Scheme, u.Host, timeout), nil
+	default:
+		return nil, fmt.Errorf("unsupported SIEM endpoint scheme %q", u.Scheme)
+	}
+}
+
+// HTTPSink posts each batch as newline separated lines.
+type HTTPSink struct {
+	url           string
+	contentType   string
+	authorization string
+	client        *http.Client
+}
+
+func NewHTTPSink(url string, format Format, authorization string, timeout time.Duration) *HTTPSink {
+	return &HTTPSink{
+		url:           url,
+		contentType:   format.ContentType(),
+		authorization: authorization,
+		client:        &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *HTTPSink) Name() string {
+	return "http"
+}
+
+func (s *HTTPSink) Send(ctx context.Context, lines [][]byte) error {
+	var body bytes.Buffer
+	for _, line := range lines {
+		body.Write(line)
+		body.WriteByte('\n')
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", s.contentType)
+	if s.authorization != "" {
+		req.Header.Set("Authorization", s.authorization)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("SIEM collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+// SyslogSink writes each event as an RFC 5424 message of the authpriv
+// facility. Over tcp and tls messages are framed by octet counting (RFC
+// 6587); over udp each is a datagram. A connection is opened per batch.
+type SyslogSink struct {
+	network  string
+	addr     string
+	timeout  time.Duration
+	hostname string
+}
+
+// syslogPriority is the authpriv facility, for security and authorization
+// messages, at notice severity. The event's own severity is in the message.
+const syslogPriority = 10*8 + 5
+
+func NewSyslogSink(network, addr string, timeout time.Duration) *SyslogSink {
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "-"
+	}
+	return &SyslogSink{network: network, addr: addr, timeout: timeout, hostname: hostname}
+}
+
+func (s *SyslogSink) Name() string {
+	return "syslog"
+}
+
+func (s *SyslogSink) Send(ctx context.Context, lines [][]byte) error {
+	dialer := &net.Dialer{Timeout: s.timeout}
+	var conn net.Conn
+	var err error
+	if s.network == "tls" {
+		conn, err = (&tls.Dialer{NetDialer: dialer}).DialContext(ctx, "tcp", s.addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, s.network, s.addr)
+	}
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if s.timeout > 0 {
+		_ = conn.SetWriteDeadline(time.Now().Add(s.timeout))
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	var buf bytes.Buffer
+	for _, line := range lines {
+		msg := fmt.Sprintf("<%d>1 %s %s %s - - - %s", syslogPriority, now, s.hostname, cefProduct, line)
+		if s.network == "udp" {
+			if _, err := conn.Write([]byte(msg)); err != nil {
+				return err
+			}
+			continue
+		}
+		buf.WriteString(strconv.Itoa(len(msg)))
+		buf.WriteByte(' ')
+		buf.WriteString(msg)
+	}
+	if buf.Len() == 0 {
+		return nil
+	}
+	_, err = conn.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,97 @@
+package slo
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Burn rate thresholds of the multiwindow alerts: a page spends 2% of a
+// 30 day budget within an hour, a ticket 5% within six hours.
+const (
+	pageBurnRate   = 14.4
+	ticketBurnRate = 6
+)
+
+var reportWindows = []struct {
+	name string
+	span time.Duration
+}{
+	{"5m", 5 * time.Minute},
+	{"30m", 30 * time.Minute},
+	{"1h", time.Hour},
+	{"6h", 6 * time.Hour},
+}
+
+// WindowStatus is the error budget spend over one window. BurnRate is the
+// error rate over the budgeted one; 1 spends the budget exactly on time.
+type WindowStatus struct {
+	Window    string  `json:"window"`
+	Events    uint64  `json:"events"`
+	Bad       uint64  `json:"bad"`
+	ErrorRate float64 `json:"errorRate"`
+	BurnRate  float64 `json:"burnRate"`
+}
+
+// Status summarizes one indicator. Alert is "page" when both the 1h and 5m
+// windows burn faster than 14.4, "ticket" when the 6h and 30m windows burn
+// faster than 6, and "ok" otherwise.
+type Status struct {
+	Indicator   Indicator      `json:"indicator"`
+	Description string         `json:"description"`
+	Objective   float64        `json:"objective"`
+	Alert       string         `json:"alert"`
+	Windows     []WindowStatus `json:"windows"`
+}
+
+type Report struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+	SLOs        []Status  `json:"slos"`
+}
+
+func (t *Tracker) Report() Report {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	o := t.objectives
+	return Report{
+		GeneratedAt: now.UTC(),
+		SLOs: []Status{
+			t.status(now, LoginLatency, "password sign-ins completing within "+o.LoginThreshold.String(), o.LoginTarget),
+			t.status(now, ValidationErrors, "access token validations not failing on the service side", o.ValidationTarget),
+			t.status(now, RefreshSuccess, "access token refreshes succeeding", o.RefreshTarget),
+		},
+	}
+}
+
+func (t *Tracker) status(now time.Time, indicator Indicator, description string, objective float64) Status {
+	s := Status{Indicator: indicator, Description: description, Objective: objective, Alert: "ok"}
+	burn := make(map[string]float64, len(reportWindows))
+	for _, w := range reportWindows {
+		good, bad := t.windows[indicator].sum(now, w.span)
+		ws := WindowStatus{Window: w.name, Events: good + bad, Bad: bad}
+		if ws.Events > 0 {
+			ws.ErrorRate = float64(bad) / float64(ws.Events)
+			ws.BurnRate = ws.ErrorRate / (1 - objective)
+		}
+		burn[w.name] = ws.BurnRate
+		s.Windows = append(s.Windows, ws)
+	}
+
+	switch {
+	case burn["1h"] >= pageBurnRate && burn["5m"] >= pageBurnRate:
+		s.Alert = "page"
+	case burn["6h"] >= ticketBurnRate && burn["30m"] >= ticketBurnRate:
+		s.Alert = "ticket"
+	}
+	return s
+}
+
+// Handler serves the burn rates of the default tracker for on-call
+// engineers.
+func Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return c.JSON(Default.Report())
+	}
+}
@@ -0,0 +1,164 @@
+// Package slo defines the service level objectives of the critical paths:
+// password sign-in latency, access token validation and refresh. Every
+// event is observed into a histogram with a request ID exemplar and counted
+// as good or bad per minute, from which /slo reports how fast each error
+// budget is burning. Counts are per process; Prometheus aggregates the
+// histograms across replicas.
+package slo
+
+import (
+	"sync"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/metrics"
+)
+
+type Indicator string
+
+const (
+	LoginLatency     Indicator = "login_latency"
+	ValidationErrors Indicator = "validation_errors"
+	RefreshSuccess   Indicator = "refresh_success"
+)
+
+// Objectives are the targets of the indicators: the share of sign-ins
+// faster than LoginThreshold, of validations that did not fail on the
+// service side and of refreshes that succeeded.
+type Objectives struct {
+	LoginTarget      float64
+	LoginThreshold   time.Duration
+	ValidationTarget float64
+	RefreshTarget    float64
+}
+
+func DefaultObjectives() Objectives {
+	return Objectives{
+		LoginTarget:      0.99,
+		LoginThreshold:   time.Second,
+		ValidationTarget: 0.999,
+		RefreshTarget:    0.995,
+	}
+}
+
+// ObjectivesFromConfig maps the slo section of the config onto Objectives,
+// keeping the defaults for anything left unset.
+func ObjectivesFromConfig(cfg *configs.Config) Objectives {
+	o := DefaultObjectives()
+	if t := cfg.SLO.LoginTarget; t > 0 && t < 1 {
+		o.LoginTarget = t
+	}
+	if cfg.SLO.LoginLatency > 0 {
+		o.LoginThreshold = cfg.SLO.LoginLatency
+	}
+	if t := cfg.SLO.ValidationTarget; t > 0 && t < 1 {
+		o.ValidationTarget = t
+	}
+	if t := cfg.SLO.RefreshTarget; t > 0 && t < 1 {
+		o.RefreshTarget = t
+	}
+	return o
+}
+
+const exemplarLabel = "request_id"
+
+var (
+	latencyBuckets    = []float64{0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+	validationBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25}
+
+	loginDuration = metrics.Default.NewHistogramVec(
+		"auth_login_duration_seconds",
+		"Password sign-in latency by outcome.",
+		latencyBuckets, "outcome")
+	validationDuration = metrics.Default.NewHistogramVec(
+		"auth_token_validation_duration_seconds",
+		"Access token validation latency by result, valid or the rejection reason.",
+		validationBuckets, "result")
+	refreshDuration = metrics.Default.NewHistogramVec(
+		"auth_token_refresh_duration_seconds",
+		"Access token refresh latency by outcome.",
+		latencyBuckets, "outcome")
+)
+
+// Tracker counts good and bad events of each indicator.
+type Tracker struct {
+	mu         sync.Mutex
+	objectives Objectives
+	windows    map[Indicator]*window
+	now        func() time.Time
+}
+
+func NewTracker(o Objectives) *Tracker {
+	return &Tracker{
+		objectives: o,
+		windows: map[Indicator]*window{
+			LoginLatency:     {},
+			ValidationErrors: {},
+			RefreshSuccess:   {},
+		},
+		now: time.Now,
+	}
+}
+
+// Default is the tracker the critical paths report to and /slo serves.
+var Default = NewTracker(DefaultObjectives())
+
+// SetObjectives replaces the targets; counts are kept.
+func (t *Tracker) SetObjectives(o Objectives) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.objectives = o
+}
+
+func (t *Tracker) Objectives() Objectives {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.objectives
+}
+
+func (t *Tracker) count(indicator Indicator, good bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.windows[indicator].add(t.now(), good)
+}
+
+// ObserveLogin records a password sign-in, successful or not. It is good
+// when it completed within the latency threshold.
+func (t *Tracker) ObserveLogin(elapsed time.Duration, succeeded bool, requestID string) {
+	loginDuration.ObserveWithExemplar(elapsed.Seconds(), exemplarLabel, requestID, outcome(succeeded))
+	t.count(LoginLatency, elapsed <= t.Objectives().LoginThreshold)
+}
+
+// ObserveValidation records an access token check. result is "valid" or
+// the rejection reason; serviceFault marks rejections that point at the
+// service, such as unverifiable signatures, rather than at a client holding
+// an expired or revoked token.
+func (t *Tracker) ObserveValidation(elapsed time.Duration, result string, serviceFault bool, requestID string) {
+	validationDuration.ObserveWithExemplar(elapsed.Seconds(), exemplarLabel, requestID, result)
+	t.count(ValidationErrors, !serviceFault)
+}
+
+// ObserveRefresh records an access token refresh.
+func (t *Tracker) ObserveRefresh(elapsed time.Duration, succeeded bool, requestID string) {
+	refreshDuration.ObserveWithExemplar(elapsed.Seconds(), exemplarLabel, requestID, outcome(succeeded))
+	t.count(RefreshSuccess, succeeded)
+}
+
+func ObserveLogin(elapsed time.Duration, succeeded bool, requestID string) {
+	Default.ObserveLogin(elapsed, succeeded, requestID)
+}
+
+func ObserveValidation(elapsed time.Duration, result string, serviceFault bool, requestID string) {
+	Default.ObserveValidation(elapsed, result, serviceFault, requestID)
+}
+
+func ObserveRefresh(elapsed time.Duration, succeeded bool, requestID string) {
+	Default.ObserveRefresh(elapsed, succeeded, requestID)
+}
+
+func outcome(succeeded bool) string {
+	if succeeded {
+		return "success"
+	}
+	return "failure"
+}
@@ -0,0 +1,44 @@
+package slo
+
+import "time"
+
+// windowMinutes covers the longest burn rate window.
+const windowMinutes = 6 * 60
+
+type minuteCount struct {
+	minute int64
+	good   uint64
+	bad    uint64
+}
+
+// window is a ring of per-minute counts; slots older than the ring are
+// reused.
+type window struct {
+	slots [windowMinutes]minuteCount
+}
+
+func (w *window) add(now time.Time, good bool) {
+	minute := now.Unix() / 60
+	slot := &w.slots[minute%windowMinutes]
+	if slot.minute != minute {
+		*slot = minuteCount{minute: minute}
+	}
+	if good {
+		slot.good++
+	} else {
+		slot.bad++
+	}
+}
+
+// sum totals the minutes within span of now, including the current one.
+func (w *window) sum(now time.Time, span time.Duration) (good, bad uint64) {
+	current := now.Unix() / 60
+	oldest := current - int64(span/time.Minute) + 1
+	for _, slot := range w.slots {
+		if slot.minute >= oldest && slot.minute <= current {
+			good += slot.good
+			bad += slot.bad
+		}
+	}
+	return good, bad
+}
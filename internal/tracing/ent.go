@@ -0,0 +1,131 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"entgo.io/ent/dialect"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Driver wraps an ent driver so every query made inside a trace gets a span.
+// Statements are recorded with their placeholders, never their arguments.
+func Driver(drv dialect.Driver) dialect.Driver {
+	return &tracedDriver{Driver: drv}
+}
+
+type tracedDriver struct {
+	dialect.Driver
+}
+
+func (d *tracedDriver) Exec(ctx context.Context, query string, args, v any) error {
+	ctx, span, ok := startQuery(ctx, d.Dialect(), query)
+	err := d.Driver.Exec(ctx, query, args, v)
+	if ok {
+		finish(span, err)
+	}
+	return err
+}
+
+func (d *tracedDriver) Query(ctx context.Context, query string, args, v any) error {
+	ctx, span, ok := startQuery(ctx, d.Dialect(), query)
+	err := d.Driver.Query(ctx, query, args, v)
+	if ok {
+		finish(span, err)
+	}
+	return err
+}
+
+func (d *tracedDriver) Tx(ctx context.Context) (dialect.Tx, error) {
+	tx, err := d.Driver.Tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return d.traceTx(ctx, tx), nil
+}
+
+// BeginTx is used by ent for transactions with options.
+func (d *tracedDriver) BeginTx(ctx context.Context, opts *sql.TxOptions) (dialect.Tx, error) {
+	drv, ok := d.Driver.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (dialect.Tx, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("Driver.BeginTx is not supported")
+	}
+	tx, err := drv.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return d.traceTx(ctx, tx), nil
+}
+
+// traceTx spans the transaction from its start to its commit or rollback;
+// its statements get spans of their own under the caller's span.
+func (d *tracedDriver) traceTx(ctx context.Context, tx dialect.Tx) dialect.Tx {
+	_, span, ok := startChild(ctx, "db.transaction", attribute.String("db.system", d.Dialect()))
+	return &tracedTx{Tx: tx, dialect: d.Dialect(), span: span, traced: ok}
+}
+
+type tracedTx struct {
+	dialect.Tx
+	dialect string
+	span    trace.Span
+	traced  bool
+}
+
+func (t *tracedTx) Exec(ctx context.Context, query string, args, v any) error {
+	ctx, span, ok := startQuery(ctx, t.dialect, query)
+	err := t.Tx.Exec(ctx, query, args, v)
+	if ok {
+		finish(span, err)
+	}
+	return err
+}
+
+func (t *tracedTx) Query(ctx context.Context, query string, args, v any) error {
+	ctx, span, ok := startQuery(ctx, t.dialect, query)
+	err := t.Tx.Query(ctx, query, args, v)
+	if ok {
+		finish(span, err)
+	}
+	return err
+}
+
+func (t *tracedTx) Commit() error {
+	err := t.Tx.Commit()
+	if t.traced {
+		t.span.SetAttributes(attribute.String("db.transaction.outcome", "commit"))
+		finish(t.span, err)
+	}
+	return err
+}
+
+func (t *tracedTx) Rollback() error {
+	err := t.Tx.Rollback()
+	if t.traced {
+		t.span.SetAttributes(attribute.String("db.transaction.outcome", "rollback"))
+		finish(t.span, err)
+	}
+	return err
+}
+
+func startQuery(ctx context.Context, system, query string) (context.Context, trace.Span, bool) {
+	operation := queryOperation(query)
+	return startChild(ctx, "db."+strings.ToLower(operation),
+		attribute.String("db.system", system),
+		attribute.String("db.operation", operation),
+		attribute.String("db.statement", query),
+	)
+}
+
+// queryOperation is the leading keyword of the statement, e.g. SELECT.
+func queryOperation(query string) string {
+	query = strings.TrimSpace(query)
+	if i := strings.IndexAny(query, " \n\t("); i > 0 {
+		query = query[:i]
+	}
+	return strings.ToUpper(query)
+}
@@ -0,0 +1,60 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AroundOperations starts the span of a GraphQL operation, ended by
+// AroundResponses once its response is written. Register both on the
+// server, AroundOperations before the other operation middleware so the
+// span covers them.
+func AroundOperations(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	op := graphql.GetOperationContext(ctx)
+
+	operation := "query"
+	name := op.OperationName
+	if op.Operation != nil {
+		operation = string(op.Operation.Operation)
+		if name == "" {
+			name = op.Operation.Name
+		}
+	}
+
+	attrs := []attribute.KeyValue{attribute.String("graphql.operation.type", operation)}
+	spanName := operation
+	if name != "" {
+		attrs = append(attrs, attribute.String("graphql.operation.name", name))
+		spanName = operation + " " + name
+	}
+
+	ctx, _ = Tracer().Start(ctx, spanName, trace.WithAttributes(attrs...))
+	return next(ctx)
+}
+
+// AroundResponses records the errors of a response on the operation span
+// and ends it; a subscription's span ends with its last response.
+func AroundResponses(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	resp := next(ctx)
+
+	span := trace.SpanFromContext(ctx)
+	if resp != nil && len(resp.Errors) > 0 {
+		span.SetAttributes(attribute.Int("graphql.errors", len(resp.Errors)))
+		if code, ok := resp.Errors[0].Extensions["code"]; ok {
+			span.SetAttributes(attribute.String("graphql.error.code", fmt.Sprint(code)))
+		}
+		span.SetStatus(codes.Error, resp.Errors[0].Message)
+	}
+
+	op := graphql.GetOperationContext(ctx)
+	if resp == nil || op.Operation == nil || op.Operation.Operation != ast.Subscription {
+		span.End()
+	}
+	return resp
+}
@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// InstrumentRedis gives every command the client sends inside a trace a
+// span. Keys and arguments are left out: keys hold emails and token IDs.
+func InstrumentRedis(rdb *redis.Client) {
+	rdb.AddHook(redisHook{})
+}
+
+type redisHook struct{}
+
+func (redisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (redisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span, ok := startChild(ctx, "redis."+cmd.Name(),
+			attribute.String("db.system", "redis"),
+			attribute.String("db.operation", strings.ToUpper(cmd.Name())),
+		)
+		err := next(ctx, cmd)
+		if ok {
+			finish(span, redisError(err))
+		}
+		return err
+	}
+}
+
+func (redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		names := make([]string, len(cmds))
+		for i, cmd := range cmds {
+			names[i] = strings.ToUpper(cmd.Name())
+		}
+		ctx, span, ok := startChild(ctx, "redis.pipeline",
+			attribute.String("db.system", "redis"),
+			attribute.StringSlice("db.redis.commands", names),
+		)
+		err := next(ctx, cmds)
+		if ok {
+			finish(span, redisError(err))
+		}
+		return err
+	}
+}
+
+// redisError drops redis.Nil, which only reports a missing key.
+func redisError(err error) error {
+	if err == redis.Nil {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,98 @@
+// Package tracing instruments the service with OpenTelemetry: HTTP
+// requests, GraphQL operations, SQL queries and Redis commands each get a
+// span, so a single request can be followed from the edge to the stores.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "github.com/abisalde/authentication-service"
+	defaultServiceName  = "authentication-service"
+)
+
+// Tracer returns the tracer of the service. It records nothing until Setup
+// installs an exporting provider, but still passes trace context along.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Setup installs the W3C trace context propagators and, when tracing is
+// enabled, a provider exporting over OTLP/HTTP. The returned shutdown
+// flushes the spans still buffered.
+func Setup(ctx context.Context, cfg *configs.Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if !cfg.Tracing.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var opts []otlptracehttp.Option
+	if cfg.Tracing.Endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Tracing.Endpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the OTLP exporter: %w", err)
+	}
+
+	serviceName := cfg.Tracing.ServiceName
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", serviceName),
+		attribute.String("deployment.environment", cfg.Env.CurrentEnv),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(Sampler(cfg.Tracing.SampleRatio)),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Sampler follows the sampling decision of the caller and keeps ratio of
+// the traces that start here. A ratio of 0 or less keeps them all.
+func Sampler(ratio float64) sdktrace.Sampler {
+	if ratio <= 0 || ratio >= 1 {
+		return sdktrace.ParentBased(sdktrace.AlwaysSample())
+	}
+	return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))
+}
+
+// startChild starts a span only inside a trace. SQL queries and Redis
+// commands of background jobs would otherwise each start a trace of their
+// own.
+func startChild(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span, bool) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, nil, false
+	}
+	ctx, span := Tracer().Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	return ctx, span, true
+}
+
+// finish records err on the span and ends it.
+func finish(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -2,7 +2,7 @@ package utils
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"strconv"
 
 	server "github.com/abisalde/authentication-service/cmd"
@@ -11,12 +11,12 @@ import (
 func getPort(c *server.AppConfig) int {
 	port, err := strconv.Atoi(c.HTTPPort)
 	if err != nil {
-		log.Printf("⚠️ Invalid port '%s', defaulting to 8080. Error: %v", c.HTTPPort, err)
+		slog.Warn("Invalid port, defaulting to 8080", "port", c.HTTPPort, "error", err)
 		return 8080
 	}
 
 	if port < 10 || port > 65535 {
-		log.Printf("⚠️ Port %d out of range (10-65535), defaulting to 8080", port)
+		slog.Warn("Port out of range (10-65535), defaulting to 8080", "port", port)
 		return 8080
 	}
 
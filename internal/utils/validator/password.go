@@ -9,13 +9,33 @@ import (
 	"github.com/vektah/gqlparser/v2/gqlerror"
 )
 
+// PasswordPolicy describes the rules ValidatePassword enforces so they can be
+// advertised to clients.
+type PasswordPolicy struct {
+	MinLength        int  `json:"minLength"`
+	MaxLength        int  `json:"maxLength"`
+	RequireUppercase bool `json:"requireUppercase"`
+	RequireLowercase bool `json:"requireLowercase"`
+	RequireDigit     bool `json:"requireDigit"`
+	RequireSymbol    bool `json:"requireSymbol"`
+}
+
+var CurrentPasswordPolicy = PasswordPolicy{
+	MinLength:        8,
+	MaxLength:        50,
+	RequireUppercase: true,
+	RequireLowercase: true,
+	RequireDigit:     true,
+	RequireSymbol:    true,
+}
+
 var (
 	ErrShortPassword         = errors.New("password must be at least 8 characters long")
 	ErrorPasswordCombination = errors.New("password must contain one uppercase, one lowercase, one number, and one special character")
 )
 
 func ValidatePassword(password string) *gqlerror.Error {
-	if len(password) < 8 {
+	if len(password) < CurrentPasswordPolicy.MinLength {
 		return customErrors.NewTypedError(ErrShortPassword.Error(), model.ErrorTypePassword, nil)
 	}
 	if !regexp.MustCompile(`[A-Z]`).MatchString(password) {
@@ -3,66 +3,254 @@ package worker
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/events"
+	"github.com/abisalde/authentication-service/internal/metrics"
+	app_logger "github.com/abisalde/authentication-service/pkg/logger"
 	"github.com/redis/go-redis/v9"
 )
 
+const (
+	// readBlock bounds each blocking read, so the worker notices shutdown
+	// within it even where cancelling ctx does not interrupt the read.
+	readBlock = 2 * time.Second
+	readBatch = 100
+
+	defaultClaimIdle     = 30 * time.Second
+	defaultMaxDeliveries = 5
+	deadLetterMaxLen     = 10000
+)
+
+var loginEvents = metrics.Default.NewCounterVec(
+	"login_events_total",
+	"Login events handled by the last-login worker, by outcome: applied, failed (left to retry) or dead_lettered.",
+	"outcome",
+)
+
+// LastLoginWorker applies login events in a consumer group, so restarts
+// resume after the last acknowledged event and replicas split the stream
+// between them.
 type LastLoginWorker struct {
-	redisClient *redis.Client
-	authService *service.AuthService
+	redisClient   *redis.Client
+	authService   *service.AuthService
+	group         string
+	consumer      string
+	claimIdle     time.Duration
+	maxDeliveries int64
+	logger        *slog.Logger
 }
 
-func NewLastLoginWorker(redisClient *redis.Client, authService *service.AuthService) *LastLoginWorker {
-	return &LastLoginWorker{
-		redisClient: redisClient,
-		authService: authService,
+// NewLastLoginWorker builds the worker; a nil logger logs to the default
+// one.
+func NewLastLoginWorker(redisClient *redis.Client, authService *service.AuthService, cfg *configs.Config, logger *slog.Logger) *LastLoginWorker {
+	w := &LastLoginWorker{
+		redisClient:   redisClient,
+		authService:   authService,
+		logger:        app_logger.Or(logger),
+		group:         cfg.LoginWorker.Group,
+		consumer:      cfg.LoginWorker.Consumer,
+		claimIdle:     cfg.LoginWorker.ClaimIdle,
+		maxDeliveries: cfg.LoginWorker.MaxDeliveries,
+	}
+	if w.group == "" {
+		w.group = service.LoginGroup
+	}
+	if w.consumer == "" {
+		w.consumer, _ = os.Hostname()
 	}
+	if w.consumer == "" {
+		w.consumer = "last-login-worker"
+	}
+	if w.claimIdle <= 0 {
+		w.claimIdle = defaultClaimIdle
+	}
+	if w.maxDeliveries <= 0 {
+		w.maxDeliveries = defaultMaxDeliveries
+	}
+	return w
 }
 
+// Start consumes login events until ctx is cancelled. Events already read
+// when it is cancelled are still applied and acknowledged, so a shutdown
+// drains the batch in hand. Events that fail stay pending and are claimed
+// again after ClaimIdle, by this consumer or another.
 func (w *LastLoginWorker) Start(ctx context.Context) {
-	lastID := "0"
+	processCtx := context.WithoutCancel(ctx)
+	if err := w.ensureGroup(ctx); err != nil {
+		w.logger.Error("Failed to create the login consumer group", "group", w.group, "error", err)
+	}
 
+	var lastClaim time.Time
 	for {
 
 		select {
 		case <-ctx.Done():
-			log.Println("LoginEventConsumer shutting down.")
+			w.logger.Info("Last login worker shutting down", "consumer", w.consumer)
 			return
 		default:
-			streams, err := w.redisClient.XRead(ctx, &redis.XReadArgs{
-				Streams: []string{service.LoginStreamKey, lastID},
-				Block:   0,
+			if time.Since(lastClaim) >= w.claimIdle {
+				w.reclaim(processCtx)
+				lastClaim = time.Now()
+			}
+
+			streams, err := w.redisClient.XReadGroup(ctx, &redis.XReadGroupArgs{
+				Group:    w.group,
+				Consumer: w.consumer,
+				Streams:  []string{service.LoginStreamKey, ">"},
+				Count:    readBatch,
+				Block:    readBlock,
 			}).Result()
 
+			if err == redis.Nil || ctx.Err() != nil {
+				continue
+			}
+			// The stream, and its group with it, may have been deleted.
+			if err != nil && isNoGroup(err) && w.ensureGroup(ctx) == nil {
+				continue
+			}
 			if err != nil {
-				log.Printf("Error reading from stream: %v", err)
-				time.Sleep(1 * time.Second)
+				w.logger.Error("Failed to read login events", "stream", service.LoginStreamKey, "error", err)
+				select {
+				case <-ctx.Done():
+				case <-time.After(1 * time.Second):
+				}
 				continue
 			}
 
 			for _, stream := range streams {
-				for _, msg := range stream.Messages {
-
-					eventString := msg.Values["event"].(string)
-
-					var loginEvent service.LoginEvent
-					if err := json.Unmarshal([]byte(eventString), &loginEvent); err != nil {
-						log.Printf("Failed to unmarshal event: %v", err)
-						continue
-					}
-					if loginEvent.EventType == "user_last_login" {
-						err := w.authService.UpdateLastLogin(ctx, loginEvent.UserID)
-						if err != nil {
-							log.Printf("Failed to update last login for user %v: %v", loginEvent.UserID, err)
-						}
-					}
-					lastID = msg.ID
-				}
+				w.process(processCtx, stream.Messages)
+			}
+		}
+
+	}
+}
+
+// ensureGroup creates the consumer group from the start of the stream, so
+// the first worker takes over the events still in it.
+func (w *LastLoginWorker) ensureGroup(ctx context.Context) error {
+	err := w.redisClient.XGroupCreateMkStream(ctx, service.LoginStreamKey, w.group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	return nil
+}
+
+func isNoGroup(err error) bool {
+	return strings.HasPrefix(err.Error(), "NOGROUP")
+}
+
+// process applies each event and acknowledges it. An event that fails is
+// left pending for reclaim; one that cannot be decoded never will apply and
+// goes straight to the dead-letter stream.
+func (w *LastLoginWorker) process(ctx context.Context, messages []redis.XMessage) {
+	for _, msg := range messages {
+		eventString, _ := msg.Values["event"].(string)
+
+		var loginEvent service.LoginEvent
+		if err := json.Unmarshal([]byte(eventString), &loginEvent); err != nil {
+			w.logger.Warn("Failed to decode login event", "event_id", msg.ID, "error", err)
+			w.deadLetter(ctx, msg, "unreadable event")
+			continue
+		}
+		if loginEvent.EventType == events.UserLastLogin {
+			if err := w.authService.UpdateLastLogin(ctx, loginEvent.UserID); err != nil {
+				w.logger.Warn("Failed to update last login, leaving the event to retry", "user_id", loginEvent.UserID, "event_id", msg.ID, "error", err)
+				loginEvents.Inc("failed")
+				continue
 			}
 		}
 
+		if err := w.redisClient.XAck(ctx, service.LoginStreamKey, w.group, msg.ID).Err(); err != nil {
+			w.logger.Warn("Failed to acknowledge login event", "event_id", msg.ID, "error", err)
+			continue
+		}
+		loginEvents.Inc("applied")
+	}
+}
+
+// reclaim retries the events left pending for ClaimIdle, whether by a
+// consumer that died or by this one before a restart. Those already
+// delivered MaxDeliveries times are dead-lettered instead.
+func (w *LastLoginWorker) reclaim(ctx context.Context) {
+	pending, err := w.redisClient.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: service.LoginStreamKey,
+		Group:  w.group,
+		Idle:   w.claimIdle,
+		Start:  "-",
+		End:    "+",
+		Count:  readBatch,
+	}).Result()
+	if err != nil {
+		if !isNoGroup(err) {
+			w.logger.Error("Failed to list pending login events", "error", err)
+		}
+		return
+	}
+
+	for _, p := range pending {
+		if p.RetryCount < w.maxDeliveries {
+			continue
+		}
+		msg := redis.XMessage{ID: p.ID}
+		entries, err := w.redisClient.XRange(ctx, service.LoginStreamKey, p.ID, p.ID).Result()
+		if err != nil {
+			w.logger.Warn("Failed to read pending login event", "event_id", p.ID, "error", err)
+			continue
+		}
+		if len(entries) > 0 {
+			msg = entries[0]
+		}
+		w.deadLetter(ctx, msg, fmt.Sprintf("failed %d deliveries", p.RetryCount))
+	}
+
+	messages, _, err := w.redisClient.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   service.LoginStreamKey,
+		Group:    w.group,
+		Consumer: w.consumer,
+		MinIdle:  w.claimIdle,
+		Start:    "0-0",
+		Count:    readBatch,
+	}).Result()
+	if err != nil {
+		w.logger.Error("Failed to claim pending login events", "error", err)
+		return
+	}
+	if len(messages) > 0 {
+		w.logger.Info("Claimed login events left pending", "events", len(messages))
+		w.process(ctx, messages)
+	}
+}
+
+// deadLetter moves the event to the dead-letter stream and acknowledges it
+// in one transaction, so it is neither lost nor retried again.
+func (w *LastLoginWorker) deadLetter(ctx context.Context, msg redis.XMessage, reason string) {
+	values := map[string]interface{}{
+		"id":       msg.ID,
+		"reason":   reason,
+		"consumer": w.consumer,
+	}
+	if event, found := msg.Values["event"]; found {
+		values["event"] = event
+	}
+
+	pipe := w.redisClient.TxPipeline()
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: service.LoginDeadLetterStreamKey,
+		MaxLen: deadLetterMaxLen,
+		Values: values,
+	})
+	pipe.XAck(ctx, service.LoginStreamKey, w.group, msg.ID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		w.logger.Error("Failed to dead-letter login event", "event_id", msg.ID, "error", err)
+		return
 	}
+	w.logger.Warn("Login event dead-lettered", "event_id", msg.ID, "stream", service.LoginDeadLetterStreamKey, "reason", reason)
+	loginEvents.Inc("dead_lettered")
 }
@@ -0,0 +1,3 @@
+-- Remove onboarding state machine column from users table
+DROP INDEX idx_users_onboarding_step ON users;
+ALTER TABLE users DROP COLUMN onboarding_step;
@@ -0,0 +1,13 @@
+-- Add onboarding state machine column to users table
+ALTER TABLE users ADD COLUMN onboarding_step ENUM('VERIFY_EMAIL', 'COMPLETE_PROFILE', 'ACCEPT_TERMS', 'DONE') NOT NULL DEFAULT 'VERIFY_EMAIL' AFTER last_login_at;
+
+-- Backfill existing users from the data they already have
+UPDATE users SET onboarding_step = CASE
+    WHEN is_email_verified = 0 THEN 'VERIFY_EMAIL'
+    WHEN first_name = '' OR last_name = '' THEN 'COMPLETE_PROFILE'
+    WHEN terms_accepted_at IS NULL THEN 'ACCEPT_TERMS'
+    ELSE 'DONE'
+END;
+
+-- Add index for onboarding funnel lookups
+CREATE INDEX idx_users_onboarding_step ON users(onboarding_step);
@@ -0,0 +1,3 @@
+-- Remove public UUID from users table
+DROP INDEX idx_users_public_id ON users;
+ALTER TABLE users DROP COLUMN public_id;
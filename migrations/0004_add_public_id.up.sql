@@ -0,0 +1,10 @@
+-- Add public UUID exposed by the APIs instead of the numeric primary key
+ALTER TABLE users ADD COLUMN public_id CHAR(36) NULL AFTER id;
+
+-- Backfill existing users
+UPDATE users SET public_id = UUID() WHERE public_id IS NULL;
+
+ALTER TABLE users MODIFY COLUMN public_id CHAR(36) NOT NULL;
+
+-- Add unique index for public id lookups
+CREATE UNIQUE INDEX idx_users_public_id ON users(public_id);
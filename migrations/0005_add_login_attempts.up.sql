@@ -0,0 +1,23 @@
+-- Login history: one row per sign-in attempt, successful or not
+CREATE TABLE login_attempts (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    user_id BIGINT NULL,
+    email VARCHAR(255) NOT NULL,
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    device_id VARCHAR(128) NOT NULL DEFAULT '',
+    platform VARCHAR(32) NOT NULL DEFAULT '',
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    method ENUM('PASSWORD', 'OAUTH') NOT NULL DEFAULT 'PASSWORD',
+    outcome ENUM('SUCCESS', 'FAILURE') NOT NULL,
+    failure_reason ENUM('UNKNOWN_ACCOUNT', 'INVALID_PASSWORD', 'OAUTH_FAILED', 'INTERNAL_ERROR') NULL,
+    risk_score BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT login_attempts_users_login_attempts FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE SET NULL
+);
+
+-- Indexes for per-user history, IP and email investigations, and retention pruning
+CREATE INDEX loginattempt_user_id_created_at ON login_attempts(user_id, created_at);
+CREATE INDEX loginattempt_ip_created_at ON login_attempts(ip, created_at);
+CREATE INDEX loginattempt_email_created_at ON login_attempts(email, created_at);
+CREATE INDEX loginattempt_created_at ON login_attempts(created_at);
@@ -0,0 +1,2 @@
+ALTER TABLE users DROP FOREIGN KEY users_organizations_users, DROP COLUMN organization_id;
+DROP TABLE IF EXISTS organizations;
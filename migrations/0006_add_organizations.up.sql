@@ -0,0 +1,23 @@
+-- Organizations: tenants whose branding replaces the default brand in auth emails
+CREATE TABLE organizations (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    slug VARCHAR(64) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    product_name VARCHAR(100) NOT NULL DEFAULT '',
+    logo_url VARCHAR(512) NOT NULL DEFAULT '',
+    primary_color VARCHAR(7) NOT NULL DEFAULT '',
+    support_email VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY slug (slug)
+);
+
+-- Membership. Adding an inplace foreign key requires the checks off; the
+-- column is new, so there is nothing to validate.
+SET foreign_key_checks = 0;
+ALTER TABLE users
+    ADD COLUMN organization_id BIGINT NULL,
+    ADD CONSTRAINT users_organizations_users FOREIGN KEY (organization_id) REFERENCES organizations (id) ON DELETE SET NULL,
+    ALGORITHM=INPLACE, LOCK=NONE;
+SET foreign_key_checks = 1;
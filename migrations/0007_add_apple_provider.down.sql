@@ -0,0 +1,2 @@
+-- Fails while Apple accounts exist; move them to another provider first.
+ALTER TABLE users MODIFY COLUMN provider ENUM('GOOGLE', 'FACEBOOK', 'EMAIL') NOT NULL DEFAULT 'EMAIL';
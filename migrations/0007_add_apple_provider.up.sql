@@ -0,0 +1,4 @@
+-- Sign in with Apple. Appending an ENUM value only touches table metadata.
+ALTER TABLE users
+    MODIFY COLUMN provider ENUM('GOOGLE', 'FACEBOOK', 'EMAIL', 'APPLE') NOT NULL DEFAULT 'EMAIL',
+    ALGORITHM=INSTANT;
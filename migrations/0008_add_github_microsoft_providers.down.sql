@@ -0,0 +1,3 @@
+-- Fails while GitHub, Microsoft or configured provider accounts exist; move
+-- them to another provider first.
+ALTER TABLE users MODIFY COLUMN provider ENUM('GOOGLE', 'FACEBOOK', 'EMAIL', 'APPLE') NOT NULL DEFAULT 'EMAIL';
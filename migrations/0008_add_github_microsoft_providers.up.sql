@@ -0,0 +1,5 @@
+-- GitHub, Microsoft and providers registered through configuration, which
+-- share OAUTH. Appending ENUM values only touches table metadata.
+ALTER TABLE users
+    MODIFY COLUMN provider ENUM('GOOGLE', 'FACEBOOK', 'EMAIL', 'APPLE', 'GITHUB', 'MICROSOFT', 'OAUTH') NOT NULL DEFAULT 'EMAIL',
+    ALGORITHM=INSTANT;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS service_account_keys;
+-- Service accounts become ordinary users without a password; delete them first.
+ALTER TABLE users DROP COLUMN kind;
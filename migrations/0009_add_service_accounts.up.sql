@@ -0,0 +1,19 @@
+-- Service accounts: organization-owned users that sign in with keys only.
+-- Adding a column with a default only touches table metadata.
+ALTER TABLE users
+    ADD COLUMN kind ENUM('HUMAN', 'SERVICE') NOT NULL DEFAULT 'HUMAN',
+    ALGORITHM=INSTANT;
+
+-- Keys keep a hash of the secret; the key_id is sent as the client_id
+CREATE TABLE service_account_keys (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    key_id VARCHAR(32) NOT NULL,
+    secret_hash VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NULL,
+    last_used_at TIMESTAMP NULL,
+    user_id BIGINT NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY key_id (key_id),
+    CONSTRAINT service_account_keys_users_service_account_keys FOREIGN KEY (user_id) REFERENCES users (id)
+);
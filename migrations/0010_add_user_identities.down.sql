@@ -0,0 +1,2 @@
+-- Accounts keep the provider they were created with; linked ones are lost.
+DROP TABLE IF EXISTS user_identities;
@@ -0,0 +1,19 @@
+-- Account linking: a user can sign in with several OAuth accounts.
+CREATE TABLE user_identities (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    provider ENUM('GOOGLE', 'FACEBOOK', 'APPLE', 'GITHUB', 'MICROSOFT', 'OAUTH') NOT NULL,
+    oauth_id VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NULL,
+    created_at TIMESTAMP NOT NULL,
+    last_used_at TIMESTAMP NULL,
+    user_id BIGINT NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY useridentity_provider_oauth_id (provider, oauth_id),
+    CONSTRAINT user_identities_users_identities FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+-- Sign-in now looks identities up, so every existing OAuth account gets one.
+INSERT INTO user_identities (provider, oauth_id, email, created_at, user_id)
+SELECT provider, oauth_id, email, created_at, id
+FROM users
+WHERE provider <> 'EMAIL' AND oauth_id IS NOT NULL AND oauth_id <> '';
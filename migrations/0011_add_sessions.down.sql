@@ -0,0 +1,2 @@
+-- Signs out everyone whose session was kept in SQL.
+DROP TABLE IF EXISTS sessions;
@@ -0,0 +1,12 @@
+-- Refresh sessions for deployments that run with sessions.store: sql.
+-- One row per user; a sign-in replaces it.
+CREATE TABLE sessions (
+    user_id BIGINT NOT NULL,
+    encrypted_token TEXT NOT NULL,
+    token_hash VARCHAR(255) NOT NULL,
+    started_at TIMESTAMP NULL,
+    last_active_at TIMESTAMP NULL,
+    expires_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id)
+);
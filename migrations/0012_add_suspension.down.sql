@@ -0,0 +1,5 @@
+-- Reinstates every suspended account. Attempts refused for a suspension
+-- lose their reason.
+UPDATE login_attempts SET failure_reason = NULL WHERE failure_reason = 'ACCOUNT_SUSPENDED';
+ALTER TABLE login_attempts MODIFY COLUMN failure_reason ENUM('UNKNOWN_ACCOUNT', 'INVALID_PASSWORD', 'OAUTH_FAILED', 'INTERNAL_ERROR') NULL;
+ALTER TABLE users DROP COLUMN suspension_reason, DROP COLUMN suspended_at;
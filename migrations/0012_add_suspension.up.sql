@@ -0,0 +1,9 @@
+-- Account suspension: suspended accounts keep their data but cannot sign in
+-- until an admin lifts the suspension. Both columns stay null otherwise.
+ALTER TABLE users
+    ADD COLUMN suspended_at TIMESTAMP NULL AFTER onboarding_step,
+    ADD COLUMN suspension_reason ENUM('TERMS_VIOLATION', 'FRAUD', 'ABUSE', 'SPAM', 'SECURITY', 'OTHER') NULL AFTER suspended_at,
+    ALGORITHM=INPLACE, LOCK=NONE;
+
+-- Sign-ins refused because the account is suspended
+ALTER TABLE login_attempts MODIFY COLUMN failure_reason ENUM('UNKNOWN_ACCOUNT', 'INVALID_PASSWORD', 'OAUTH_FAILED', 'ACCOUNT_SUSPENDED', 'INTERNAL_ERROR') NULL;
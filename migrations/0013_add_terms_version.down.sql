@@ -0,0 +1,2 @@
+-- Forgets which version of the terms each user accepted.
+ALTER TABLE users DROP COLUMN terms_version;
@@ -0,0 +1,5 @@
+-- Version of the terms the user accepted at registration. Accounts that
+-- accepted before versioning keep a null version, which reads as unknown.
+ALTER TABLE users
+    ADD COLUMN terms_version VARCHAR(32) NULL AFTER terms_accepted_at,
+    ALGORITHM=INPLACE, LOCK=NONE;
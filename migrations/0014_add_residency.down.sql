@@ -0,0 +1,2 @@
+-- Forgets the data region of every account.
+ALTER TABLE users DROP INDEX user_residency, DROP COLUMN residency;
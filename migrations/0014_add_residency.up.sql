@@ -0,0 +1,9 @@
+-- Data residency: the region whose database and Redis hold the account's
+-- PII and sessions. Existing accounts stay null until residency is
+-- configured and they are assigned a region.
+ALTER TABLE users
+    ADD COLUMN residency VARCHAR(16) NULL AFTER suspension_reason,
+    ALGORITHM=INPLACE, LOCK=NONE;
+
+-- Index for per-region exports and audits
+ALTER TABLE users ADD INDEX user_residency (residency), ALGORITHM=INPLACE, LOCK=NONE;
@@ -0,0 +1,2 @@
+-- Reactivates every deactivated account.
+ALTER TABLE users DROP COLUMN deactivated_at;
@@ -0,0 +1,5 @@
+-- Account deactivation: the owner's own pause. Deactivated accounts are
+-- signed out and hidden until the owner signs in again.
+ALTER TABLE users
+    ADD COLUMN deactivated_at TIMESTAMP NULL AFTER residency,
+    ALGORITHM=INPLACE, LOCK=NONE;
@@ -0,0 +1,2 @@
+-- Forgets when passwords were last changed.
+ALTER TABLE users DROP COLUMN password_changed_at;
@@ -0,0 +1,10 @@
+-- When the password was last set, for the security checkup's password age.
+ALTER TABLE users
+    ADD COLUMN password_changed_at TIMESTAMP NULL AFTER password_hash,
+    ALGORITHM=INPLACE, LOCK=NONE;
+
+-- Existing rows are deliberately not backfilled. When their password was
+-- last set is unknown: created_at would report passwords changed since as
+-- older than they are. The checkup reads null as an unknown age and asks
+-- the user to change it, which then records the date. Accounts without a
+-- password stay null for good.
@@ -0,0 +1,2 @@
+-- Pending scheduled actions are lost.
+DROP TABLE IF EXISTS scheduled_actions;
@@ -0,0 +1,21 @@
+-- Account actions an admin scheduled to run later, e.g. deactivating an
+-- account or ending its sessions at a set time.
+CREATE TABLE scheduled_actions (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    action ENUM('DEACTIVATE', 'REVOKE_SESSIONS', 'DELETE') NOT NULL,
+    run_at TIMESTAMP NOT NULL,
+    status ENUM('PENDING', 'RUNNING', 'COMPLETED', 'FAILED', 'CANCELLED') NOT NULL DEFAULT 'PENDING',
+    note VARCHAR(500) NULL,
+    created_by CHAR(36) NULL,
+    cancelled_by CHAR(36) NULL,
+    error VARCHAR(500) NULL,
+    created_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP NULL,
+    user_id BIGINT NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT scheduled_actions_users_scheduled_actions FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+-- Indexes for the scheduler's due-action scan and per-user listings
+CREATE INDEX scheduledaction_status_run_at ON scheduled_actions(status, run_at);
+CREATE INDEX scheduledaction_user_id_run_at ON scheduled_actions(user_id, run_at);
@@ -0,0 +1,2 @@
+-- Devices in the login history fall back to the user agent alone.
+ALTER TABLE login_attempts DROP COLUMN client_hints;
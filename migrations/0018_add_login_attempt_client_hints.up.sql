@@ -0,0 +1,3 @@
+-- Sec-CH-UA client hints sent with each sign-in attempt, parsed again with
+-- the user agent when listing devices. Older attempts have none.
+ALTER TABLE login_attempts ADD COLUMN client_hints JSON NULL AFTER user_agent;
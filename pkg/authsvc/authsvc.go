@@ -0,0 +1,344 @@
+// Package authsvc embeds the authentication domain in another Go program.
+// It runs the same registration, login, token and session code as the
+// GraphQL service, without the HTTP server: the host supplies the user store,
+// the cache and the mailer and calls the methods of Service directly.
+package authsvc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/abisalde/authentication-service/internal/auth"
+	authhttp "github.com/abisalde/authentication-service/internal/auth/handler/http"
+	"github.com/abisalde/authentication-service/internal/auth/repository"
+	"github.com/abisalde/authentication-service/internal/auth/service"
+	"github.com/abisalde/authentication-service/internal/auth/sessionstore"
+	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/internal/database"
+	"github.com/abisalde/authentication-service/internal/database/ent"
+	gqlerrors "github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/abisalde/authentication-service/pkg/mail"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrInvalidToken       = errors.New("access token is invalid")
+	ErrTokenRevoked       = errors.New("access token was revoked")
+	ErrInvalidCredentials = errors.New("email or password is invalid")
+	// ErrAlreadyEmbedded is returned by a second New. Token signing is
+	// configured process wide, so a program embeds a single Service.
+	ErrAlreadyEmbedded = errors.New("authsvc: a Service is already embedded in this program")
+)
+
+// embedded is set by the first New to succeed.
+var embedded atomic.Bool
+
+// Config is the service configuration.
+type Config struct {
+	cfg *configs.Config
+}
+
+// LoadConfig reads the service configuration for env, the same files the
+// server uses.
+func LoadConfig(env string) (*Config, error) {
+	cfg, err := configs.Load(env)
+	if err != nil {
+		return nil, err
+	}
+	return &Config{cfg: cfg}, nil
+}
+
+// DefaultConfig is the configuration with every setting at its default.
+func DefaultConfig() *Config {
+	return &Config{cfg: &configs.Config{}}
+}
+
+// Users stores accounts. Create it with NewUserRepository.
+type Users struct {
+	repo repository.UserRepository
+}
+
+// NewUserRepository stores users through db, which must hold the service's
+// schema. driver is the ent dialect, "mysql" or "sqlite3".
+func NewUserRepository(driver string, db *sql.DB) *Users {
+	return &Users{repo: repository.NewUserRepository(ent.NewClient(ent.Driver(entsql.OpenDB(driver, db))))}
+}
+
+// Cache keeps pending registrations, refresh tokens and revocations. Create
+// it with NewRedisCache.
+type Cache struct {
+	cache service.CacheService
+}
+
+// NewRedisCache keeps the cached state in rdb.
+func NewRedisCache(rdb *redis.Client) *Cache {
+	return &Cache{cache: database.NewCacheService(rdb)}
+}
+
+// Sessions keeps refresh sessions. Create it with NewMemorySessions or
+// NewSQLSessions; without one they live in the Cache.
+type Sessions struct {
+	store sessionstore.Store
+}
+
+// NewMemorySessions keeps sessions in the process. They are lost when it
+// exits, so it only suits a single instance.
+func NewMemorySessions() *Sessions {
+	return &Sessions{store: sessionstore.NewMemoryStore()}
+}
+
+// NewSQLSessions keeps sessions in the sessions table of db, creating it
+// if it is missing. db is the users' database or another MySQL (with
+// parseTime=true) or SQLite database.
+func NewSQLSessions(ctx context.Context, db *sql.DB) (*Sessions, error) {
+	store := sessionstore.NewSQLStore(db)
+	if err := store.Migrate(ctx); err != nil {
+		return nil, err
+	}
+	return &Sessions{store: store}, nil
+}
+
+// Mailer sends the verification and notification emails.
+type Mailer interface {
+	SendHTMLEmail(ctx context.Context, recipientEmail, senderEmail, subject, htmlBody string, overrideSenderEmail ...string) error
+}
+
+// Deps are the stores the embedding program provides. Users and Cache are
+// required; Sessions defaults to the Cache and Mailer to the one the config
+// selects.
+type Deps struct {
+	Users    *Users
+	Cache    *Cache
+	Sessions *Sessions
+	Mailer   Mailer
+}
+
+// User is the account an operation acted on.
+type User struct {
+	PublicID      string
+	Email         string
+	Username      string
+	FirstName     string
+	LastName      string
+	Role          string
+	EmailVerified bool
+	CreatedAt     time.Time
+	LastLoginAt   *time.Time
+}
+
+func newUser(u *ent.User) *User {
+	return &User{
+		PublicID:      u.PublicID.String(),
+		Email:         u.Email,
+		Username:      u.Username,
+		FirstName:     u.FirstName,
+		LastName:      u.LastName,
+		Role:          string(u.Role),
+		EmailVerified: u.IsEmailVerified,
+		CreatedAt:     u.CreatedAt,
+		LastLoginAt:   u.LastLoginAt,
+	}
+}
+
+// RegisterInput is a registration. Which of the optional fields are
+// required depends on the onboarding and consent policies of the config.
+type RegisterInput struct {
+	Email          string
+	Password       string
+	FirstName      *string
+	LastName       *string
+	AcceptTerms    *bool
+	Country        *string
+	DateOfBirth    *time.Time
+	MarketingOptIn *bool
+	TermsVersion   *string
+	CaptchaToken   *string
+}
+
+// Session is the token pair handed out by Login.
+type Session struct {
+	User         *User
+	AccessToken  string
+	RefreshToken string
+}
+
+type Service struct {
+	auth     *service.AuthService
+	register *authhttp.RegisterHandler
+	login    *authhttp.LoginHandler
+	tokens   *authhttp.TokenHandler
+}
+
+// New configures token signing from cfg and wires the auth domain onto deps.
+// Token settings are process wide, so only the first call succeeds; later
+// ones return ErrAlreadyEmbedded.
+func New(cfg *Config, deps Deps) (*Service, error) {
+	if cfg == nil || cfg.cfg == nil {
+		return nil, fmt.Errorf("authsvc: config is required")
+	}
+	if deps.Users == nil || deps.Cache == nil {
+		return nil, fmt.Errorf("authsvc: users and cache are required")
+	}
+	if !embedded.CompareAndSwap(false, true) {
+		return nil, ErrAlreadyEmbedded
+	}
+
+	svc, err := newService(cfg.cfg, deps)
+	if err != nil {
+		embedded.Store(false)
+		return nil, err
+	}
+	return svc, nil
+}
+
+func newService(cfg *configs.Config, deps Deps) (*Service, error) {
+	var mailer mail.Mailer = deps.Mailer
+	if mailer == nil {
+		mailer = mail.NewMailerService(cfg)
+	}
+
+	if err := jwt.Configure(jwt.OptionsFromConfig(cfg)); err != nil {
+		return nil, err
+	}
+	keys, err := jwt.LoadKeySet(cfg)
+	if err != nil {
+		return nil, err
+	}
+	jwt.SetKeySet(keys)
+	if rdb := deps.Cache.cache.RawClient(); rdb != nil {
+		jwt.SetScopeStore(jwt.NewRedisScopeStore(rdb))
+		jwt.SetClaimsStore(jwt.NewRedisClaimsStore(rdb))
+	}
+
+	authService := service.NewAuthService(deps.Users.repo, cfg, deps.Cache.cache, mailer)
+	if deps.Sessions != nil {
+		authService.SetSessionStore(deps.Sessions.store)
+	}
+	return &Service{
+		auth:     authService,
+		register: authhttp.NewRegisterHandler(authService),
+		login:    authhttp.NewLoginHandler(authService),
+		tokens:   authhttp.NewTokenHandler(authService),
+	}, nil
+}
+
+// Register validates the input against the consent and onboarding policies
+// and mails a verification code. The account exists once VerifyEmail
+// succeeds.
+func (s *Service) Register(ctx context.Context, input RegisterInput) error {
+	_, err := s.register.Register(ctx, model.RegisterInput{
+		Email:          input.Email,
+		Password:       input.Password,
+		FirstName:      input.FirstName,
+		LastName:       input.LastName,
+		AcceptTerms:    input.AcceptTerms,
+		Country:        input.Country,
+		DateOfBirth:    input.DateOfBirth,
+		MarketingOptIn: input.MarketingOptIn,
+		TermsVersion:   input.TermsVersion,
+		CaptchaToken:   input.CaptchaToken,
+	})
+	return err
+}
+
+// ResendVerificationCode mails a fresh code for a pending registration.
+func (s *Service) ResendVerificationCode(ctx context.Context, email string) error {
+	_, err := s.register.ResendVerificationCodeEmail(ctx, model.ResendVerificationCode{Email: email})
+	return err
+}
+
+// VerifyEmail creates the account of a pending registration.
+func (s *Service) VerifyEmail(ctx context.Context, email, code string) (*User, error) {
+	user, err := s.auth.VerifyCodeAndCreateUser(ctx, email, code)
+	if err != nil {
+		return nil, err
+	}
+	return newUser(user), nil
+}
+
+// Login checks the password and issues an access and refresh token. An
+// unknown email and a wrong password both return ErrInvalidCredentials.
+func (s *Service) Login(ctx context.Context, email, password string) (*Session, error) {
+	resp, err := s.login.EmailLogin(ctx, model.LoginInput{Email: email, Password: password})
+	if err == gqlerrors.InvalidCredentialsEmail || err == gqlerrors.InvalidCredentialsPassword {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.auth.FindUserProfileById(ctx, resp.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return &Session{User: newUser(user), AccessToken: resp.Token, RefreshToken: resp.RefreshToken}, nil
+}
+
+// Refresh mints a new access token for the user with publicID.
+func (s *Service) Refresh(ctx context.Context, publicID, refreshToken string) (string, error) {
+	resp, err := s.tokens.HandleRefreshToken(ctx, refreshToken, publicID)
+	if err != nil {
+		return "", err
+	}
+	return resp.Token, nil
+}
+
+// Authenticate returns the user an access token was issued to. It applies
+// the same blacklist and revocation checks as the server's middleware.
+func (s *Service) Authenticate(ctx context.Context, accessToken string) (*User, error) {
+	user, _, err := s.authenticate(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	return newUser(user), nil
+}
+
+func (s *Service) authenticate(ctx context.Context, accessToken string) (*ent.User, *jwt.Claims, error) {
+	if s.auth.IsTokenBlacklisted(ctx, accessToken) {
+		return nil, nil, ErrTokenRevoked
+	}
+
+	claims, err := jwt.ValidateTokenContext(ctx, accessToken)
+	if err != nil || !claims.IsAccessToken() || claims.Maintenance != "" {
+		return nil, nil, ErrInvalidToken
+	}
+
+	user, err := s.auth.ResolveUserReference(ctx, claims.Subject)
+	if err != nil || service.IsDeleted(user) || service.IsDeactivated(user) {
+		return nil, nil, ErrInvalidToken
+	}
+
+	issuedAt := time.Unix(0, 0)
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+	if s.auth.IsTokenRevokedForUser(ctx, user.ID, issuedAt) {
+		return nil, nil, ErrTokenRevoked
+	}
+	return user, claims, nil
+}
+
+// Logout ends the session of accessToken and drops the user's refresh token.
+func (s *Service) Logout(ctx context.Context, accessToken string) error {
+	user, claims, err := s.authenticate(ctx, accessToken)
+	if err != nil {
+		return err
+	}
+
+	ctx = auth.WithToken(ctx, accessToken)
+	ctx = auth.WithUser(ctx, user, claims)
+	_, err = s.login.ProcessLogout(ctx)
+	return err
+}
+
+// RevokeAllSessions signs the user with publicID out on every device.
+func (s *Service) RevokeAllSessions(ctx context.Context, publicID string) error {
+	return s.auth.ForceLogoutUser(ctx, publicID)
+}
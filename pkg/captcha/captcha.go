@@ -0,0 +1,70 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SiteVerify posts tokens to a siteverify endpoint. reCAPTCHA, hCaptcha and
+// Cloudflare Turnstile accept the same form fields and answer with a JSON
+// success flag.
+type SiteVerify struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewSiteVerify returns nil when either the endpoint or the secret is
+// missing, so callers can treat CAPTCHA as not configured.
+func NewSiteVerify(verifyURL, secret string) *SiteVerify {
+	if verifyURL == "" || secret == "" {
+		return nil
+	}
+	return &SiteVerify{
+		url:    verifyURL,
+		secret: secret,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Verify reports whether token, produced by the client-side widget, is
+// valid. An empty token is rejected without a round trip.
+func (v *SiteVerify) Verify(ctx context.Context, token, remoteIP string) (bool, error) {
+	if token == "" {
+		return false, nil
+	}
+
+	form := url.Values{"secret": {v.secret}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("captcha verify returned %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, err
+	}
+	return result.Success, nil
+}
@@ -0,0 +1,134 @@
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	StrategyDatabase  = "database"
+	StrategySnowflake = "snowflake"
+	StrategyULID      = "ulid"
+
+	nodeBits     = 10
+	sequenceBits = 12
+	randomBits   = 22
+
+	MaxNodeID   = 1<<nodeBits - 1
+	maxSequence = 1<<sequenceBits - 1
+)
+
+// Epoch is the custom epoch for time prefixed IDs. 41 bits of milliseconds
+// from here last until 2093.
+var Epoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+var ErrClockMovedBackwards = errors.New("clock moved backwards, refusing to generate id")
+
+// Generator hands out user IDs. A generator that returns ok=false leaves ID
+// assignment to the database.
+type Generator interface {
+	NextID() (id int64, ok bool, err error)
+}
+
+// MayCollide reports whether g draws IDs at random, so two of them can
+// collide and an insert that hits an existing ID is retried with a new one.
+func MayCollide(g Generator) bool {
+	random, ok := g.(interface{ MayCollide() bool })
+	return ok && random.MayCollide()
+}
+
+func New(strategy string, nodeID int64) (Generator, error) {
+	switch strings.ToLower(strings.TrimSpace(strategy)) {
+	case "", StrategyDatabase:
+		return Database{}, nil
+	case StrategySnowflake:
+		return NewSnowflake(nodeID)
+	case StrategyULID:
+		return NewULID(), nil
+	default:
+		return nil, fmt.Errorf("unknown id strategy %q", strategy)
+	}
+}
+
+// Database defers to the auto increment column.
+type Database struct{}
+
+func (Database) NextID() (int64, bool, error) {
+	return 0, false, nil
+}
+
+// Snowflake lays IDs out as 41 bits of milliseconds since Epoch, 10 bits of
+// node ID and a 12 bit per-millisecond sequence, so every node can mint IDs
+// independently without collisions as long as node IDs are unique.
+type Snowflake struct {
+	mu       sync.Mutex
+	nodeID   int64
+	lastMs   int64
+	sequence int64
+	now      func() time.Time
+}
+
+func NewSnowflake(nodeID int64) (*Snowflake, error) {
+	if nodeID < 0 || nodeID > MaxNodeID {
+		return nil, fmt.Errorf("snowflake node id must be between 0 and %d, got %d", MaxNodeID, nodeID)
+	}
+	return &Snowflake{nodeID: nodeID, now: time.Now}, nil
+}
+
+func (s *Snowflake) NextID() (int64, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ms := s.now().Sub(Epoch).Milliseconds()
+	if ms < s.lastMs {
+		return 0, false, ErrClockMovedBackwards
+	}
+
+	if ms == s.lastMs {
+		s.sequence = (s.sequence + 1) & maxSequence
+		if s.sequence == 0 {
+			for ms <= s.lastMs {
+				ms = s.now().Sub(Epoch).Milliseconds()
+			}
+		}
+	} else {
+		s.sequence = 0
+	}
+
+	s.lastMs = ms
+	return ms<<(nodeBits+sequenceBits) | s.nodeID<<sequenceBits | s.sequence, true, nil
+}
+
+// ULID borrows the layout of a ULID but is not one: it fits an int64 with a
+// millisecond timestamp prefix, which keeps IDs roughly time ordered, and
+// only 22 random bits. No coordination between nodes is needed, but IDs
+// minted in the same millisecond can collide, so inserts retry them. The
+// IDs are not secret either: the timestamp is readable and 22 bits are
+// quickly guessed, so only an account's public UUID is safe to expose.
+type ULID struct {
+	now func() time.Time
+}
+
+func NewULID() *ULID {
+	return &ULID{now: time.Now}
+}
+
+func (u *ULID) MayCollide() bool {
+	return true
+}
+
+func (u *ULID) NextID() (int64, bool, error) {
+	var buf [4]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return 0, false, fmt.Errorf("failed to read random bits: %w", err)
+	}
+
+	ms := u.now().Sub(Epoch).Milliseconds()
+	random := int64(binary.BigEndian.Uint32(buf[:])) & (1<<randomBits - 1)
+	return ms<<randomBits | random, true, nil
+}
@@ -0,0 +1,124 @@
+package jwt
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// roleCodes are the compact forms written to the rl claim. Codes are part of
+// the token format: append new roles, never renumber existing ones.
+var roleCodes = map[string]int{
+	"USER":  1,
+	"ADMIN": 2,
+}
+
+var ErrScopeReferenceUnresolved = errors.New("scope reference could not be resolved")
+
+// Extras are the optional claims carried by access tokens. When a token
+// would exceed the size budget, scopes are moved behind a reference and the
+// device claim is dropped before issuance fails.
+type Extras struct {
+	Device *DeviceClaim
+	Role   string
+	Scopes []string
+	// Maintenance names the grant of a break-glass token. It is never
+	// dropped to fit the size budget.
+	Maintenance string
+}
+
+// ScopeStore keeps scope sets behind the reference written to the sref
+// claim. References are derived from the set, so storing the same set twice
+// is idempotent.
+type ScopeStore interface {
+	Put(ctx context.Context, ref string, scopes []string, ttl time.Duration) error
+	Get(ctx context.Context, ref string) ([]string, error)
+}
+
+var (
+	scopeStoreMu sync.RWMutex
+	scopeStore   ScopeStore
+)
+
+// SetScopeStore enables reference tokens for scope sets. Without a store,
+// scopes are always embedded.
+func SetScopeStore(store ScopeStore) {
+	scopeStoreMu.Lock()
+	defer scopeStoreMu.Unlock()
+	scopeStore = store
+}
+
+func currentScopeStore() ScopeStore {
+	scopeStoreMu.RLock()
+	defer scopeStoreMu.RUnlock()
+	return scopeStore
+}
+
+func (c *Claims) setRole(role string, compact bool) {
+	if code, ok := roleCodes[role]; ok && compact {
+		c.RoleCode = code
+		return
+	}
+	c.Role = role
+}
+
+// RoleName returns the role claim, expanding the numeric code of compacted
+// tokens.
+func (c *Claims) RoleName() string {
+	if c.Role != "" || c.RoleCode == 0 {
+		return c.Role
+	}
+	for role, code := range roleCodes {
+		if code == c.RoleCode {
+			return role
+		}
+	}
+	return ""
+}
+
+// ResolveScopes returns the embedded scopes, or loads the set behind the
+// scope reference of compacted tokens.
+func ResolveScopes(ctx context.Context, c *Claims) ([]string, error) {
+	if c.ScopeRef == "" {
+		return c.Scopes, nil
+	}
+
+	store := currentScopeStore()
+	if store == nil {
+		return nil, ErrScopeReferenceUnresolved
+	}
+	scopes, err := store.Get(ctx, c.ScopeRef)
+	if err != nil {
+		return nil, ErrScopeReferenceUnresolved
+	}
+	return scopes, nil
+}
+
+// referenceScopes replaces the embedded scopes with a reference to the set
+// in the scope store. It reports false when no store is configured.
+func (c *Claims) referenceScopes(ctx context.Context, ttl time.Duration) (bool, error) {
+	store := currentScopeStore()
+	if store == nil || len(c.Scopes) == 0 {
+		return false, nil
+	}
+
+	ref := scopeReference(c.Scopes)
+	if err := store.Put(ctx, ref, c.Scopes, ttl); err != nil {
+		return false, err
+	}
+	c.Scopes = nil
+	c.ScopeRef = ref
+	return true, nil
+}
+
+func scopeReference(scopes []string) string {
+	sorted := append([]string(nil), scopes...)
+	sort.Strings(sorted)
+	sum := sha256.Sum256([]byte(strings.Join(sorted, " ")))
+	return hex.EncodeToString(sum[:])[:24]
+}
@@ -0,0 +1,61 @@
+package jwt
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/abisalde/authentication-service/internal/configs"
+)
+
+// OptionsFromConfig maps the jwt section of the config onto Options, falling
+// back to the package defaults for anything left unset. Durations count as
+// set when present, so 0s disables the clock skew or the leeway.
+func OptionsFromConfig(cfg *configs.Config) Options {
+	opts := DefaultOptions()
+	if cfg.JWT.Format != "" {
+		opts.Format = cfg.JWT.Format
+	}
+	if cfg.JWT.Issuer != "" {
+		opts.Issuer = cfg.JWT.Issuer
+	}
+	if cfg.JWT.ClockSkew != nil {
+		opts.ClockSkew = *cfg.JWT.ClockSkew
+	}
+	if cfg.JWT.Leeway != nil {
+		opts.Leeway = *cfg.JWT.Leeway
+	}
+	if cfg.JWT.MaxTokenBytes != 0 {
+		opts.MaxTokenBytes = cfg.JWT.MaxTokenBytes
+	}
+	opts.WarnTokenBytes = cfg.JWT.WarnTokenBytes
+	opts.CompactRoles = cfg.JWT.CompactRoles
+	opts.ReferenceScopes = cfg.JWT.ReferenceScopes
+	opts.AcceptedIssuers = cfg.JWT.AcceptedIssuers
+	opts.SkipIssuerCheck = cfg.JWT.SkipIssuerCheck
+	opts.AcceptHS256 = cfg.JWT.AcceptHS256
+	return opts
+}
+
+// LoadKeySet reads the configured PEM files into a key set. It returns nil
+// when no keys are configured, which keeps HS256 signing.
+func LoadKeySet(cfg *configs.Config) (*KeySet, error) {
+	files := cfg.JWT.SigningKeys.Keys
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]*SigningKey, 0, len(files))
+	for _, f := range files {
+		pemBytes, err := os.ReadFile(f.File)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %q: %w", f.ID, err)
+		}
+		key, err := ParseSigningKey(f.ID, f.Algorithm, pemBytes)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return NewKeySet(cfg.JWT.SigningKeys.Primary, keys...)
+}
@@ -0,0 +1,53 @@
+package jwt
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/abisalde/authentication-service/pkg/useragent"
+)
+
+const (
+	deviceIDLength    = 16
+	maxPlatformLength = 16
+)
+
+// DeviceClaim is the minimal device attribution carried in access tokens so
+// services that can't reach Redis can still correlate requests to a device.
+type DeviceClaim struct {
+	ID       string `json:"id"`
+	Platform string `json:"pf,omitempty"`
+}
+
+// NewDeviceClaim derives a device claim from a client supplied device ID,
+// falling back to the user agent. Only a truncated hash of either ends up in
+// the token.
+func NewDeviceClaim(deviceID, userAgent string) *DeviceClaim {
+	source := strings.TrimSpace(deviceID)
+	if source == "" {
+		source = strings.TrimSpace(userAgent)
+	}
+	if source == "" {
+		return nil
+	}
+
+	sum := sha256.Sum256([]byte(source))
+	return &DeviceClaim{
+		ID:       hex.EncodeToString(sum[:])[:deviceIDLength],
+		Platform: DetectPlatform(userAgent),
+	}
+}
+
+// DetectPlatform is the coarse platform of a User-Agent: ios, android,
+// windows, macos, linux or other.
+func DetectPlatform(userAgent string) string {
+	if userAgent == "" {
+		return ""
+	}
+	platform := useragent.Parse(userAgent).Platform()
+	if len(platform) > maxPlatformLength {
+		platform = platform[:maxPlatformLength]
+	}
+	return platform
+}
@@ -0,0 +1,45 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SignWith signs claims with key, naming it in the kid header. It is for
+// assertions presented to other services, such as the client secret Sign
+// in with Apple expects, not for the service's own tokens.
+func SignWith(key *SigningKey, claims jwt.Claims) (string, error) {
+	if !key.CanSign() {
+		return "", fmt.Errorf("signing key %q has no private key", key.ID)
+	}
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.ID
+	tokenString, err := token.SignedString(key.private)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return tokenString, nil
+}
+
+// ParseWithKeys verifies a token issued by another party into claims,
+// picking the key by kid with lookup, typically from the issuer's JWKS.
+func ParseWithKeys(tokenString string, claims jwt.Claims, lookup func(kid string) (*SigningKey, bool), opts ...jwt.ParserOption) error {
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := lookup(kid)
+		if !ok {
+			return nil, ErrUnknownKey
+		}
+		if token.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("token alg %v does not match key %q", token.Header["alg"], kid)
+		}
+		return key.public, nil
+	}, opts...)
+	if err != nil && errors.Is(err, ErrUnknownKey) {
+		return ErrUnknownKey
+	}
+	return err
+}
@@ -0,0 +1,81 @@
+package jwt
+
+import (
+	"errors"
+	"time"
+
+	customErrors "github.com/abisalde/authentication-service/internal/graph/errors"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// IDTokenClaims are the OpenID Connect ID token claims. ID tokens carry no
+// type claim, so they are never accepted as access or refresh tokens.
+type IDTokenClaims struct {
+	Nonce             string           `json:"nonce,omitempty"`
+	AuthTime          *jwt.NumericDate `json:"auth_time,omitempty"`
+	Email             string           `json:"email,omitempty"`
+	EmailVerified     bool             `json:"email_verified"`
+	Name              string           `json:"name,omitempty"`
+	GivenName         string           `json:"given_name,omitempty"`
+	FamilyName        string           `json:"family_name,omitempty"`
+	PreferredUsername string           `json:"preferred_username,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// GenerateIDToken signs an ID token for the audience with the same key as
+// access tokens. It is always a JWT, even when access tokens are opaque,
+// since relying parties verify it themselves.
+func GenerateIDToken(issuer, subject, audience string, expiration time.Duration, claims IDTokenClaims) (string, error) {
+	if issuer == "" || audience == "" {
+		return "", errors.New("id token issuer and audience must not be empty")
+	}
+	if err := loadSecret(); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		Issuer:    issuer,
+		Subject:   subject,
+		Audience:  jwt.ClaimStrings{audience},
+		ExpiresAt: jwt.NewNumericDate(now.Add(expiration)),
+		IssuedAt:  jwt.NewNumericDate(now),
+	}
+	if claims.AuthTime == nil {
+		claims.AuthTime = jwt.NewNumericDate(now)
+	}
+
+	return signClaims(&claims)
+}
+
+// ValidateIDToken verifies an ID token issued by GenerateIDToken for the
+// issuer and audience.
+func ValidateIDToken(tokenString, issuer, audience string) (*IDTokenClaims, error) {
+	if err := loadSecret(); err != nil {
+		return nil, err
+	}
+
+	opts := currentOptions()
+	token, err := jwt.ParseWithClaims(tokenString, &IDTokenClaims{}, verificationKey(opts),
+		jwt.WithLeeway(opts.Leeway), jwt.WithIssuer(issuer), jwt.WithAudience(audience), jwt.WithExpirationRequired())
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, customErrors.ExpiredToken
+		}
+		return nil, customErrors.InvalidToken
+	}
+
+	claims, ok := token.Claims.(*IDTokenClaims)
+	if !ok || !token.Valid {
+		return nil, customErrors.InvalidToken
+	}
+	return claims, nil
+}
+
+// IDTokenAlgorithm is the JWS alg of issued ID tokens.
+func IDTokenAlgorithm() string {
+	if ks := CurrentKeySet(); ks != nil {
+		return ks.Primary().Algorithm
+	}
+	return signingMethod.Alg()
+}
@@ -1,10 +1,11 @@
 package jwt
 
 import (
+	"context"
+	"crypto/rsa"
 	"errors"
 	"fmt"
 	"os"
-	"strconv"
 	"sync"
 	"time"
 
@@ -15,7 +16,14 @@ import (
 
 type TokenType string
 type Claims struct {
-	Type TokenType `json:"type"` //access or refresh
+	Type     TokenType    `json:"type"` //access or refresh
+	Device   *DeviceClaim `json:"dev,omitempty"`
+	Role     string       `json:"role,omitempty"`
+	RoleCode int          `json:"rl,omitempty"`
+	Scopes   []string     `json:"scp,omitempty"`
+	ScopeRef string       `json:"sref,omitempty"`
+	// Maintenance is the break-glass grant a maintenance token was minted for.
+	Maintenance string `json:"mnt,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -24,16 +32,125 @@ const (
 	TokenTypeRefresh TokenType = "refresh"
 )
 
+const (
+	DefaultIssuer    = "authentication-service"
+	DefaultClockSkew = 30 * time.Second
+	DefaultLeeway    = 30 * time.Second
+	// DefaultMaxTokenBytes keeps tokens comfortably inside common header limits.
+	DefaultMaxTokenBytes = 2048
+
+	maxClockSkew     = 5 * time.Minute
+	maxLeeway        = 5 * time.Minute
+	minMaxTokenBytes = 512
+	maxMaxTokenBytes = 8192
+)
+
+var ErrTokenTooLarge = errors.New("token exceeds configured size budget")
+
+// Options controls how tokens are stamped at issuance and checked at validation.
+type Options struct {
+	// Format is FormatJWT or FormatOpaque.
+	Format string
+	// Issuer is written to the iss claim of every issued token.
+	Issuer string
+	// ClockSkew back-dates nbf so tokens are usable on hosts whose clock runs behind.
+	ClockSkew time.Duration
+	// Leeway is the tolerance applied to exp/nbf/iat during validation.
+	Leeway time.Duration
+	// AcceptedIssuers are trusted in addition to Issuer.
+	AcceptedIssuers []string
+	// SkipIssuerCheck explicitly disables the iss check for multi-issuer setups.
+	SkipIssuerCheck bool
+	// MaxTokenBytes is the size budget for a signed token. Optional claims
+	// are dropped before a token is rejected for exceeding it.
+	MaxTokenBytes int
+	// WarnTokenBytes reports tokens approaching the budget; 0 means 80% of
+	// MaxTokenBytes.
+	WarnTokenBytes int
+	// CompactRoles writes known roles as numeric codes.
+	CompactRoles bool
+	// ReferenceScopes always moves scopes to the scope store instead of only
+	// when a token exceeds the budget.
+	ReferenceScopes bool
+	// AcceptHS256 keeps accepting tokens signed with JWT_SECRET once a key
+	// set is active, so sessions issued before the switch survive until
+	// they expire. Without a key set HS256 is always accepted.
+	AcceptHS256 bool
+}
+
 var (
 	secretOnce sync.Once
 	secretKey  []byte
 	loadError  error
 
-	issuer        = "authentication-service"
-	clockSkew     = 30 * time.Second
+	optionsMu     sync.RWMutex
+	options       = DefaultOptions()
 	signingMethod = jwt.SigningMethodHS256
 )
 
+func DefaultOptions() Options {
+	return Options{
+		Format:        FormatJWT,
+		Issuer:        DefaultIssuer,
+		ClockSkew:     DefaultClockSkew,
+		Leeway:        DefaultLeeway,
+		MaxTokenBytes: DefaultMaxTokenBytes,
+	}
+}
+
+func (o Options) Validate() error {
+	if o.Format != FormatJWT && o.Format != FormatOpaque {
+		return fmt.Errorf("jwt format must be %q or %q, got %q", FormatJWT, FormatOpaque, o.Format)
+	}
+	if o.Issuer == "" {
+		return errors.New("jwt issuer must not be empty")
+	}
+	if o.ClockSkew < 0 || o.ClockSkew > maxClockSkew {
+		return fmt.Errorf("jwt clock skew must be between 0 and %s, got %s", maxClockSkew, o.ClockSkew)
+	}
+	if o.Leeway < 0 || o.Leeway > maxLeeway {
+		return fmt.Errorf("jwt leeway must be between 0 and %s, got %s", maxLeeway, o.Leeway)
+	}
+	if o.MaxTokenBytes < minMaxTokenBytes || o.MaxTokenBytes > maxMaxTokenBytes {
+		return fmt.Errorf("jwt max token bytes must be between %d and %d, got %d", minMaxTokenBytes, maxMaxTokenBytes, o.MaxTokenBytes)
+	}
+	if o.WarnTokenBytes < 0 || o.WarnTokenBytes > o.MaxTokenBytes {
+		return fmt.Errorf("jwt warn token bytes must be between 0 and %d, got %d", o.MaxTokenBytes, o.WarnTokenBytes)
+	}
+	return nil
+}
+
+// Configure replaces the issuance and validation options. It is meant to be
+// called once at startup, before any token is issued or validated.
+func Configure(o Options) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+
+	optionsMu.Lock()
+	defer optionsMu.Unlock()
+	options = o
+	return nil
+}
+
+// SigningAlgorithm is the JWS alg used for issued tokens, or "opaque" when
+// tokens are references to server-side claims.
+func SigningAlgorithm() string {
+	if currentOptions().Format == FormatOpaque {
+		return FormatOpaque
+	}
+	if ks := CurrentKeySet(); ks != nil {
+		return ks.Primary().Algorithm
+	}
+	return signingMethod.Alg()
+}
+
+func currentOptions() Options {
+	optionsMu.RLock()
+	defer optionsMu.RUnlock()
+	return options
+}
+
 func loadSecret() error {
 	secretOnce.Do(func() {
 		val := os.Getenv("JWT_SECRET")
@@ -46,7 +163,23 @@ func loadSecret() error {
 	return loadError
 }
 
-func GenerateToken(userID int64, tokenType TokenType, expiration time.Duration) (string, error) {
+// GenerateToken issues a token for the subject, the user's public ID.
+func GenerateToken(subject string, tokenType TokenType, expiration time.Duration) (string, error) {
+	return GenerateTokenWithDevice(subject, tokenType, expiration, nil)
+}
+
+// GenerateTokenWithDevice embeds the device claim in access tokens. The claim
+// is dropped rather than failing issuance when it would push the token over
+// the size budget.
+func GenerateTokenWithDevice(subject string, tokenType TokenType, expiration time.Duration, device *DeviceClaim) (string, error) {
+	return GenerateTokenWithExtras(context.Background(), subject, tokenType, expiration, Extras{Device: device})
+}
+
+// GenerateTokenWithExtras embeds the optional claims in access tokens and
+// keeps the token inside the size budget: scopes move behind a reference
+// first, then the device claim is dropped, and only then is issuance
+// rejected with ErrTokenTooLarge.
+func GenerateTokenWithExtras(ctx context.Context, subject string, tokenType TokenType, expiration time.Duration, extras Extras) (string, error) {
 	if tokenType != TokenTypeAccess && tokenType != TokenTypeRefresh {
 		return "", customErrors.InvalidTokenType
 	}
@@ -55,24 +188,92 @@ func GenerateToken(userID int64, tokenType TokenType, expiration time.Duration)
 		return "", err
 	}
 
+	opts := currentOptions()
 	now := time.Now()
-	sub := strconv.FormatInt(userID, 10)
 	jti := uuid.NewString()
 
 	claims := &Claims{
 		Type: tokenType,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ID:        jti,
-			Subject:   sub,
+			Subject:   subject,
 			ExpiresAt: jwt.NewNumericDate(now.Add(expiration)),
 			IssuedAt:  jwt.NewNumericDate(now),
-			NotBefore: jwt.NewNumericDate(now.Add(-clockSkew)),
-			Issuer:    issuer,
+			NotBefore: jwt.NewNumericDate(now.Add(-opts.ClockSkew)),
+			Issuer:    opts.Issuer,
 		},
 	}
 
+	if tokenType == TokenTypeAccess {
+		claims.Device = extras.Device
+		claims.Scopes = extras.Scopes
+		claims.Maintenance = extras.Maintenance
+		if extras.Role != "" {
+			claims.setRole(extras.Role, opts.CompactRoles)
+		}
+	}
+
+	if opts.Format == FormatOpaque {
+		return issueOpaque(ctx, claims, expiration)
+	}
+
+	if opts.ReferenceScopes {
+		if _, err := claims.referenceScopes(ctx, expiration); err != nil {
+			return "", err
+		}
+	}
+
+	tokenString, err := signClaims(claims)
+	if err != nil {
+		return "", err
+	}
+
+	trimmed := false
+
+	if len(tokenString) > opts.MaxTokenBytes && claims.Scopes != nil {
+		referenced, err := claims.referenceScopes(ctx, expiration)
+		if err != nil {
+			return "", err
+		}
+		if referenced {
+			trimmed = true
+			if tokenString, err = signClaims(claims); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if len(tokenString) > opts.MaxTokenBytes && claims.Device != nil {
+		claims.Device = nil
+		trimmed = true
+		if tokenString, err = signClaims(claims); err != nil {
+			return "", err
+		}
+	}
+
+	recordTokenSize(tokenType, len(tokenString), trimmed, opts)
+
+	if len(tokenString) > opts.MaxTokenBytes {
+		return "", ErrTokenTooLarge
+	}
+
+	return tokenString, nil
+}
+
+// signClaims signs with the primary key of the key set, naming it in the kid
+// header, or with JWT_SECRET when no key set is active.
+func signClaims(claims jwt.Claims) (string, error) {
+	var key interface{} = secretKey
 	token := jwt.NewWithClaims(signingMethod, claims)
-	tokenString, err := token.SignedString(secretKey)
+
+	if ks := CurrentKeySet(); ks != nil {
+		primary := ks.Primary()
+		token = jwt.NewWithClaims(primary.method, claims)
+		token.Header["kid"] = primary.ID
+		key = primary.private
+	}
+
+	tokenString, err := token.SignedString(key)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign token: %w", err)
 	}
@@ -80,21 +281,88 @@ func GenerateToken(userID int64, tokenType TokenType, expiration time.Duration)
 }
 
 func ValidateToken(tokenString string) (*Claims, error) {
+	return ValidateTokenContext(context.Background(), tokenString)
+}
+
+// ValidateTokenContext validates JWTs locally and looks opaque tokens up in
+// the claims store. Opaque tokens stay valid after switching back to JWTs
+// until they expire.
+func ValidateTokenContext(ctx context.Context, tokenString string) (*Claims, error) {
+	if IsOpaqueToken(tokenString) {
+		return validateOpaque(ctx, tokenString)
+	}
 
 	if err := loadSecret(); err != nil {
 		return nil, err
 	}
 
+	opts := currentOptions()
+	leeway := leewayFrom(ctx, opts)
+
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, verificationKey(opts), jwt.WithLeeway(leeway))
+	return checkParsed(token, err, leeway, opts)
+}
+
+// KeyLookup returns the public key named by a token's kid header.
+type KeyLookup func(ctx context.Context, kid string) (*SigningKey, error)
+
+// ValidateTokenWithKeys verifies an RS/ES signed token with keys the caller
+// fetched from the auth service, for services that hold neither JWT_SECRET
+// nor the private keys. Issuer and leeway follow the configured Options.
+func ValidateTokenWithKeys(ctx context.Context, tokenString string, lookup KeyLookup) (*Claims, error) {
+	opts := currentOptions()
+	leeway := leewayFrom(ctx, opts)
+
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := lookup(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		if token.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("token alg %v does not match key %q", token.Header["alg"], kid)
+		}
+		return key.public, nil
+	}, jwt.WithLeeway(leeway))
+	return checkParsed(token, err, leeway, opts)
+}
 
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+// ValidateTokenWithPublicKey verifies an RS256, RS384 or RS512 signed token
+// against one RSA public key, for services handed the key out of band rather
+// than fetching the key set. HS256 tokens are checked against JWT_SECRET only
+// when AcceptHS256 is set, so services can drop the secret once every token
+// they see is RSA signed.
+func ValidateTokenWithPublicKey(ctx context.Context, tokenString string, public *rsa.PublicKey) (*Claims, error) {
+	opts := currentOptions()
+	leeway := leewayFrom(ctx, opts)
+
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodRSA:
+			return public, nil
+		case *jwt.SigningMethodHMAC:
+			if !opts.AcceptHS256 {
+				return nil, fmt.Errorf("HS256 tokens are not accepted")
+			}
+			if err := loadSecret(); err != nil {
+				return nil, err
+			}
+			return secretKey, nil
 		}
-		return secretKey, nil
-	}, jwt.WithLeeway(clockSkew))
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}, jwt.WithLeeway(leeway))
+	return checkParsed(token, err, leeway, opts)
+}
 
+func checkParsed(token *jwt.Token, err error, leeway time.Duration, opts Options) (*Claims, error) {
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
+			// The signature was verified before exp was checked, so the
+			// claims are genuine.
+			if token != nil {
+				claims, _ := token.Claims.(*Claims)
+				observeClockSkew(claims, true, leeway, opts)
+			}
 			return nil, customErrors.ExpiredToken
 		}
 		return nil, customErrors.InvalidToken
@@ -109,9 +377,70 @@ func ValidateToken(tokenString string) (*Claims, error) {
 		return nil, customErrors.InvalidTokenType
 	}
 
+	if !opts.SkipIssuerCheck && !opts.isTrustedIssuer(claims.Issuer) {
+		return nil, customErrors.InvalidToken
+	}
+
+	observeClockSkew(claims, false, leeway, opts)
+	return claims, nil
+}
+
+// verificationKey picks the key by the kid header. HMAC tokens are only ever
+// checked against JWT_SECRET, so a public key can never be used as an HMAC
+// secret.
+func verificationKey(opts Options) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		ks := CurrentKeySet()
+
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+			if ks != nil && !opts.AcceptHS256 {
+				return nil, fmt.Errorf("HS256 tokens are no longer accepted")
+			}
+			return secretKey, nil
+		}
+
+		if ks == nil {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		key, ok := ks.Key(kid)
+		if !ok {
+			return nil, ErrUnknownKey
+		}
+		if token.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("token alg %v does not match key %q", token.Header["alg"], kid)
+		}
+		return key.public, nil
+	}
+}
+
+func validateOpaque(ctx context.Context, tokenString string) (*Claims, error) {
+	claims, err := loadOpaque(ctx, tokenString)
+	if err != nil {
+		return nil, customErrors.InvalidToken
+	}
+	if claims.ExpiresAt != nil && !time.Now().Before(claims.ExpiresAt.Time) {
+		return nil, customErrors.ExpiredToken
+	}
+	if claims.Type != TokenTypeAccess && claims.Type != TokenTypeRefresh {
+		return nil, customErrors.InvalidTokenType
+	}
 	return claims, nil
 }
 
+func (o Options) isTrustedIssuer(iss string) bool {
+	if iss == o.Issuer {
+		return true
+	}
+	for _, accepted := range o.AcceptedIssuers {
+		if iss == accepted {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Claims) IsAccessToken() bool {
 	return c.Type == TokenTypeAccess
 }
@@ -121,6 +450,14 @@ func (c *Claims) IsRefreshToken() bool {
 }
 
 func GetTokenRemainingTTL(tokenString string) time.Duration {
+	if IsOpaqueToken(tokenString) {
+		claims, err := loadOpaque(context.Background(), tokenString)
+		if err != nil || claims.ExpiresAt == nil {
+			return 0
+		}
+		return time.Until(claims.ExpiresAt.Time)
+	}
+
 	claims := &Claims{}
 	_, _, err := new(jwt.Parser).ParseUnverified(tokenString, claims)
 	if err != nil || claims.ExpiresAt == nil {
@@ -128,3 +465,18 @@ func GetTokenRemainingTTL(tokenString string) time.Duration {
 	}
 	return time.Until(claims.ExpiresAt.Time)
 }
+
+// GetTokenID returns the jti and expiry of a JWT without validating it, or
+// an empty jti when it cannot be read. Opaque tokens have no jti to read.
+func GetTokenID(tokenString string) (string, time.Time) {
+	if IsOpaqueToken(tokenString) {
+		return "", time.Time{}
+	}
+
+	claims := &Claims{}
+	_, _, err := new(jwt.Parser).ParseUnverified(tokenString, claims)
+	if err != nil || claims.ExpiresAt == nil {
+		return "", time.Time{}
+	}
+	return claims.ID, claims.ExpiresAt.Time
+}
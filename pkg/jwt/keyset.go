@@ -0,0 +1,277 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrUnknownKey = errors.New("token signed with an unknown key")
+
+// SigningKey is one asymmetric key of a KeySet. Keys loaded from a public
+// key only verify tokens; they are published so tokens signed before a
+// rotation stay verifiable.
+type SigningKey struct {
+	ID        string
+	Algorithm string
+	private   crypto.Signer
+	public    crypto.PublicKey
+	method    jwt.SigningMethod
+}
+
+// CanSign reports whether the key holds a private key.
+func (k *SigningKey) CanSign() bool {
+	return k.private != nil
+}
+
+// KeySet holds every active key and the primary one new tokens are signed
+// with. Rotating means publishing the next key, making it primary once
+// verifiers have fetched it, and dropping the old key after its tokens
+// expired.
+type KeySet struct {
+	primary *SigningKey
+	keys    map[string]*SigningKey
+	order   []string
+}
+
+var keySet atomic.Pointer[KeySet]
+
+// SetKeySet switches signing to the primary key of ks. A nil set reverts to
+// HS256 with JWT_SECRET. It is safe to call while tokens are being issued,
+// so keys can be reloaded without a restart.
+func SetKeySet(ks *KeySet) {
+	keySet.Store(ks)
+}
+
+// CurrentKeySet returns the active key set, or nil when tokens are HS256.
+func CurrentKeySet() *KeySet {
+	return keySet.Load()
+}
+
+func NewKeySet(primary string, keys ...*SigningKey) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*SigningKey, len(keys))}
+	for _, key := range keys {
+		if _, dup := ks.keys[key.ID]; dup {
+			return nil, fmt.Errorf("duplicate signing key id %q", key.ID)
+		}
+		ks.keys[key.ID] = key
+		ks.order = append(ks.order, key.ID)
+	}
+
+	ks.primary = ks.keys[primary]
+	if ks.primary == nil {
+		return nil, fmt.Errorf("primary signing key %q is not in the key set", primary)
+	}
+	if !ks.primary.CanSign() {
+		return nil, fmt.Errorf("primary signing key %q has no private key", primary)
+	}
+	return ks, nil
+}
+
+func (ks *KeySet) Primary() *SigningKey {
+	return ks.primary
+}
+
+func (ks *KeySet) Key(id string) (*SigningKey, bool) {
+	key, ok := ks.keys[id]
+	return key, ok
+}
+
+// ParseSigningKey reads a PEM encoded PKCS#1, PKCS#8 or SEC 1 private key, or
+// a PKIX public key for verify-only keys. The algorithm must match the key
+// type: RS256/RS384/RS512 for RSA and ES256/ES384/ES512 for the matching
+// NIST curve.
+func ParseSigningKey(id, algorithm string, pemBytes []byte) (*SigningKey, error) {
+	if id == "" {
+		return nil, errors.New("signing key id must not be empty")
+	}
+
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("signing key %q: no PEM block found", id)
+	}
+
+	key := &SigningKey{ID: id, Algorithm: algorithm, method: jwt.GetSigningMethod(algorithm)}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		public, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %q: %w", id, err)
+		}
+		key.public = public
+	default:
+		private, err := parsePrivateKey(block)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %q: %w", id, err)
+		}
+		key.private = private
+		key.public = private.Public()
+	}
+
+	if err := key.checkAlgorithm(); err != nil {
+		return nil, fmt.Errorf("signing key %q: %w", id, err)
+	}
+	return key, nil
+}
+
+func parsePrivateKey(block *pem.Block) (crypto.Signer, error) {
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := parsed.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("unsupported private key type")
+		}
+		return signer, nil
+	}
+	return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+}
+
+func (k *SigningKey) checkAlgorithm() error {
+	switch public := k.public.(type) {
+	case *rsa.PublicKey:
+		if _, ok := k.method.(*jwt.SigningMethodRSA); !ok {
+			return fmt.Errorf("algorithm %q does not fit an RSA key", k.Algorithm)
+		}
+		if public.N.BitLen() < 2048 {
+			return errors.New("RSA keys must be at least 2048 bits")
+		}
+	case *ecdsa.PublicKey:
+		method, ok := k.method.(*jwt.SigningMethodECDSA)
+		if !ok || method.CurveBits != public.Curve.Params().BitSize {
+			return fmt.Errorf("algorithm %q does not fit a %s key", k.Algorithm, public.Curve.Params().Name)
+		}
+	default:
+		return errors.New("only RSA and ECDSA keys are supported")
+	}
+	return nil
+}
+
+// JWK is the public half of a signing key in RFC 7517 form.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+// JWKS is the document served at /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys, primary first, for verifiers to pick by kid.
+func (ks *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: make([]JWK, 0, len(ks.order))}
+	set.Keys = append(set.Keys, ks.primary.JWK())
+	for _, id := range ks.order {
+		if id != ks.primary.ID {
+			set.Keys = append(set.Keys, ks.keys[id].JWK())
+		}
+	}
+	return set
+}
+
+func (k *SigningKey) JWK() JWK {
+	jwk := JWK{Use: "sig", Algorithm: k.Algorithm, KeyID: k.ID}
+
+	switch public := k.public.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (public.Curve.Params().BitSize + 7) / 8
+		jwk.KeyType = "EC"
+		jwk.Curve = curveName(public.Curve)
+		jwk.X = base64.RawURLEncoding.EncodeToString(public.X.FillBytes(make([]byte, size)))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(public.Y.FillBytes(make([]byte, size)))
+	}
+	return jwk
+}
+
+// ParseJWK turns a published key back into a verify-only SigningKey, for
+// services that validate tokens against /.well-known/jwks.json.
+func ParseJWK(k JWK) (*SigningKey, error) {
+	if k.KeyID == "" {
+		return nil, errors.New("jwk has no kid")
+	}
+
+	key := &SigningKey{ID: k.KeyID, Algorithm: k.Algorithm, method: jwt.GetSigningMethod(k.Algorithm)}
+
+	switch k.KeyType {
+	case "RSA":
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil || len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("jwk %q: malformed RSA key", k.KeyID)
+		}
+		key.public = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	case "EC":
+		curve := curveByName(k.Curve)
+		x, errX := base64.RawURLEncoding.DecodeString(k.X)
+		y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+		if curve == nil || errX != nil || errY != nil {
+			return nil, fmt.Errorf("jwk %q: malformed EC key", k.KeyID)
+		}
+		public := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(public.X, public.Y) {
+			return nil, fmt.Errorf("jwk %q: point is not on %s", k.KeyID, k.Curve)
+		}
+		key.public = public
+	default:
+		return nil, fmt.Errorf("jwk %q: unsupported key type %q", k.KeyID, k.KeyType)
+	}
+
+	if err := key.checkAlgorithm(); err != nil {
+		return nil, fmt.Errorf("jwk %q: %w", k.KeyID, err)
+	}
+	return key, nil
+}
+
+func curveByName(name string) elliptic.Curve {
+	switch name {
+	case "P-256":
+		return elliptic.P256()
+	case "P-384":
+		return elliptic.P384()
+	case "P-521":
+		return elliptic.P521()
+	}
+	return nil
+}
+
+func curveName(curve elliptic.Curve) string {
+	switch curve {
+	case elliptic.P256():
+		return "P-256"
+	case elliptic.P384():
+		return "P-384"
+	case elliptic.P521():
+		return "P-521"
+	}
+	return curve.Params().Name
+}
@@ -0,0 +1,49 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const ScopeRefPrefix = "token_scopes:"
+
+// putScopesScript stores the set and only ever extends its TTL, so a short
+// lived token never expires a set that longer lived tokens still reference.
+var putScopesScript = redis.NewScript(`
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl < tonumber(ARGV[2]) then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+end
+return 1
+`)
+
+type RedisScopeStore struct {
+	client *redis.Client
+}
+
+func NewRedisScopeStore(client *redis.Client) *RedisScopeStore {
+	return &RedisScopeStore{client: client}
+}
+
+func (s *RedisScopeStore) Put(ctx context.Context, ref string, scopes []string, ttl time.Duration) error {
+	data, err := json.Marshal(scopes)
+	if err != nil {
+		return err
+	}
+	return putScopesScript.Run(ctx, s.client, []string{ScopeRefPrefix + ref}, data, ttl.Milliseconds()).Err()
+}
+
+func (s *RedisScopeStore) Get(ctx context.Context, ref string) ([]string, error) {
+	data, err := s.client.Get(ctx, ScopeRefPrefix+ref).Bytes()
+	if err != nil {
+		return nil, err
+	}
+	var scopes []string
+	if err := json.Unmarshal(data, &scopes); err != nil {
+		return nil, err
+	}
+	return scopes, nil
+}
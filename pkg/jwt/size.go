@@ -0,0 +1,56 @@
+package jwt
+
+import (
+	"log/slog"
+
+	"github.com/abisalde/authentication-service/internal/metrics"
+)
+
+// defaultWarnRatio is the share of MaxTokenBytes past which issued tokens
+// are reported as approaching the budget.
+const defaultWarnRatio = 0.8
+
+const (
+	budgetWarned   = "warned"
+	budgetTrimmed  = "trimmed"
+	budgetRejected = "rejected"
+)
+
+var (
+	tokenBytes = metrics.Default.NewHistogramVec(
+		"jwt_token_bytes",
+		"Size of issued tokens in bytes.",
+		[]float64{256, 512, 1024, 1536, 2048, 3072, 4096, 8192},
+		"type",
+	)
+	tokenBudgetTotal = metrics.Default.NewCounterVec(
+		"jwt_token_budget_total",
+		"Issued tokens that neared or exceeded the size budget, by outcome: warned, trimmed or rejected.",
+		"type", "outcome",
+	)
+)
+
+func (o Options) warnTokenBytes() int {
+	if o.WarnTokenBytes > 0 {
+		return o.WarnTokenBytes
+	}
+	return int(float64(o.MaxTokenBytes) * defaultWarnRatio)
+}
+
+// recordTokenSize reports the final size of an issued token. trimmed marks
+// tokens that only fit after optional claims were compacted or dropped.
+func recordTokenSize(tokenType TokenType, size int, trimmed bool, opts Options) {
+	tokenBytes.Observe(float64(size), string(tokenType))
+
+	switch {
+	case size > opts.MaxTokenBytes:
+		tokenBudgetTotal.Inc(string(tokenType), budgetRejected)
+		slog.Error("Token exceeds the size budget", "type", tokenType, "bytes", size, "budget", opts.MaxTokenBytes)
+	case trimmed:
+		tokenBudgetTotal.Inc(string(tokenType), budgetTrimmed)
+		slog.Warn("Token trimmed to fit the size budget", "type", tokenType, "bytes", size, "budget", opts.MaxTokenBytes)
+	case size > opts.warnTokenBytes():
+		tokenBudgetTotal.Inc(string(tokenType), budgetWarned)
+		slog.Warn("Token is approaching the size budget", "type", tokenType, "bytes", size, "budget", opts.MaxTokenBytes)
+	}
+}
@@ -0,0 +1,63 @@
+package jwt
+
+import (
+	"context"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/metrics"
+)
+
+var skewBuckets = []float64{1, 5, 15, 30, 60, 120, 300, 900}
+
+var (
+	futureIssuedAt = metrics.Default.NewHistogramVec(
+		"jwt_future_iat_seconds",
+		"How far ahead of the local clock the iat of validated tokens was, by issuer. Observations mean the issuer's clock runs ahead of this host.",
+		skewBuckets,
+		"issuer",
+	)
+	expiredBy = metrics.Default.NewHistogramVec(
+		"jwt_expired_by_seconds",
+		"How long past exp, leeway included, rejected tokens were, by issuer. Rejections within seconds point at clock drift rather than stale tokens.",
+		skewBuckets,
+		"issuer",
+	)
+)
+
+type leewayKey struct{}
+
+// ContextWithLeeway overrides the validation leeway for one consumer, such
+// as a resource server whose clock is known to drift. It is capped at the
+// same maximum as Options.Leeway.
+func ContextWithLeeway(ctx context.Context, leeway time.Duration) context.Context {
+	return context.WithValue(ctx, leewayKey{}, min(max(leeway, 0), maxLeeway))
+}
+
+func leewayFrom(ctx context.Context, opts Options) time.Duration {
+	if leeway, ok := ctx.Value(leewayKey{}).(time.Duration); ok {
+		return leeway
+	}
+	return opts.Leeway
+}
+
+// observeClockSkew records a validated token's iat when it lies in the
+// future, and how late an expired token arrived.
+func observeClockSkew(claims *Claims, expired bool, leeway time.Duration, opts Options) {
+	if claims == nil {
+		return
+	}
+
+	issuer := "other"
+	if opts.isTrustedIssuer(claims.Issuer) {
+		issuer = claims.Issuer
+	}
+
+	now := time.Now()
+	if expired && claims.ExpiresAt != nil {
+		expiredBy.Observe(now.Sub(claims.ExpiresAt.Time.Add(leeway)).Seconds(), issuer)
+		return
+	}
+	if claims.IssuedAt != nil && claims.IssuedAt.After(now) {
+		futureIssuedAt.Observe(claims.IssuedAt.Sub(now).Seconds(), issuer)
+	}
+}
@@ -1,13 +1,77 @@
+// Package app_logger builds the service's structured logger: leveled, text
+// or JSON, and redacting tokens, secrets, emails and IPs before anything is
+// written.
 package app_logger
 
 import (
-	"log"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
 )
 
+// Options configure the logger. Level is debug, info, warn or error and
+// Format text or json; unknown values fall back to info and text.
+type Options struct {
+	Level  string
+	Format string
+	Output io.Writer
+}
+
+// New returns a logger writing records of at least opts.Level, with every
+// attribute and message redacted.
+func New(opts Options) *slog.Logger {
+	out := opts.Output
+	if out == nil {
+		out = os.Stderr
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: ParseLevel(opts.Level), ReplaceAttr: redactAttr}
+	if strings.EqualFold(opts.Format, "json") {
+		return slog.New(slog.NewJSONHandler(out, handlerOpts))
+	}
+	return slog.New(slog.NewTextHandler(out, handlerOpts))
+}
+
+// Configure makes the logger of opts the default. Output of the standard log
+// package goes through it too, so calls not yet moved to slog are redacted
+// as well.
+func Configure(opts Options) *slog.Logger {
+	logger := New(opts)
+	slog.SetDefault(logger)
+	return logger
+}
+
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// Or returns logger, or the default logger when it is nil, for components
+// that may be built before one is injected.
+func Or(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return slog.Default()
+	}
+	return logger
+}
+
 func LogGraphQLRequest(clientIP, remoteAddr string) {
-	log.Printf("GraphQL request from IP: %s, RemoteAddr: %s", clientIP, remoteAddr)
+	slog.Debug("GraphQL request", "ip", clientIP, "remote_addr", remoteAddr)
 }
 
 func LogGraphQLField(object, field string) {
-	log.Printf("GraphQL: %s.%s called", object, field)
+	slog.Debug("GraphQL field called", "object", object, "field", field)
+}
+
+func LogAutomationActivity(client, operation, clientIP string) {
+	slog.Info("Automation client exempted from rate limiting", "client", client, "operation", operation, "ip", clientIP)
 }
@@ -0,0 +1,95 @@
+package app_logger
+
+import (
+	"log/slog"
+	"net"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	opaquePattern = regexp.MustCompile(`opq_[A-Za-z0-9_-]+`)
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	ipv4Pattern   = regexp.MustCompile(`\b(\d{1,3}\.\d{1,3}\.\d{1,3})\.\d{1,3}\b`)
+)
+
+// secretKeys name attributes whose values are dropped whole. Keys match
+// case-insensitively and by suffix, so access_token and refreshToken count;
+// codes only as code or *_code, so status codes are kept.
+var secretKeys = []string{"token", "state", "secret", "password", "authorization", "cookie"}
+
+// redactAttr is the ReplaceAttr of every handler: secrets by key are
+// dropped, emails and IPs by key are masked and any string, the message
+// included, has JWTs, opaque tokens, emails and IPv4 addresses masked
+// wherever they appear.
+func redactAttr(_ []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() == slog.KindGroup || a.Key == slog.TimeKey || a.Key == slog.LevelKey {
+		return a
+	}
+
+	key := strings.ToLower(a.Key)
+	switch {
+	case a.Key == slog.MessageKey:
+	case hasSuffix(key, secretKeys...), key == "code", hasSuffix(key, "verification_code", "auth_code"):
+		return slog.String(a.Key, redacted)
+	case hasSuffix(key, "email"):
+		return slog.String(a.Key, MaskEmail(a.Value.String()))
+	case key == "ip" || hasSuffix(key, "_ip", "remote_addr"):
+		return slog.String(a.Key, MaskIP(a.Value.String()))
+	}
+
+	if a.Value.Kind() == slog.KindString || a.Value.Kind() == slog.KindAny {
+		return slog.String(a.Key, Redact(a.Value.String()))
+	}
+	return a
+}
+
+// Redact masks JWTs, opaque tokens, emails and IPv4 addresses in free text.
+// Opaque tokens (opq_ followed by random base64url, see pkg/jwt) are bearer
+// credentials just like JWTs.
+func Redact(s string) string {
+	s = jwtPattern.ReplaceAllString(s, redacted)
+	s = opaquePattern.ReplaceAllString(s, redacted)
+	s = emailPattern.ReplaceAllStringFunc(s, MaskEmail)
+	return ipv4Pattern.ReplaceAllString(s, "$1.x")
+}
+
+// MaskEmail keeps the first letter of the local part and the domain, enough
+// to tell accounts apart in an incident without logging the address.
+func MaskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return redacted
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+// MaskIP keeps the network of an address: the /24 of IPv4 and the /48 of
+// IPv6.
+func MaskIP(addr string) string {
+	host := addr
+	if h, _, err := net.SplitHostPort(addr); err == nil {
+		host = h
+	}
+	ip := net.ParseIP(host)
+	switch {
+	case ip == nil:
+		return Redact(addr)
+	case ip.To4() != nil:
+		return ipv4Pattern.ReplaceAllString(ip.To4().String(), "$1.x")
+	default:
+		return ip.Mask(net.CIDRMask(48, 128)).String() + "/48"
+	}
+}
+
+func hasSuffix(key string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
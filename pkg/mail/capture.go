@@ -0,0 +1,77 @@
+package mail
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+const maxCapturedMessages = 200
+
+type CapturedMessage struct {
+	To        string    `json:"to"`
+	From      string    `json:"from"`
+	Subject   string    `json:"subject"`
+	HTMLBody  string    `json:"htmlBody"`
+	TextBody  string    `json:"textBody"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CaptureMailService keeps outgoing mail in memory instead of sending it, so
+// sandbox deployments and e2e tests can read verification codes back.
+type CaptureMailService struct {
+	mu       sync.RWMutex
+	messages []CapturedMessage
+}
+
+// SandboxOutbox is shared by every mailer built for a sandbox deployment.
+var SandboxOutbox = &CaptureMailService{}
+
+func (s *CaptureMailService) SendHTMLEmail(ctx context.Context, recipientEmail, subject, htmlBody, plainTextBody string, overrideSenderEmail ...string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	from := development
+	if len(overrideSenderEmail) > 0 && overrideSenderEmail[0] != "" {
+		from = overrideSenderEmail[0]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = append(s.messages, CapturedMessage{
+		To:        recipientEmail,
+		From:      from,
+		Subject:   subject,
+		HTMLBody:  htmlBody,
+		TextBody:  plainTextBody,
+		CreatedAt: time.Now(),
+	})
+	if len(s.messages) > maxCapturedMessages {
+		s.messages = s.messages[len(s.messages)-maxCapturedMessages:]
+	}
+
+	return nil
+}
+
+// Messages returns captured mail, newest first, optionally filtered by recipient.
+func (s *CaptureMailService) Messages(recipient string) []CapturedMessage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]CapturedMessage, 0, len(s.messages))
+	for i := len(s.messages) - 1; i >= 0; i-- {
+		if recipient == "" || strings.EqualFold(s.messages[i].To, recipient) {
+			result = append(result, s.messages[i])
+		}
+	}
+	return result
+}
+
+func (s *CaptureMailService) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = nil
+}
@@ -1,36 +1,44 @@
 package mail
 
 import (
-	"log"
+	"log/slog"
 
 	"github.com/abisalde/authentication-service/internal/configs"
+	"github.com/abisalde/authentication-service/pkg/workerpool"
 )
 
 var development = "princeabisal@gmail.com"
 
 func NewMailerService(cfg *configs.Config) Mailer {
 
+	if cfg.Sandbox.Enabled && cfg.Sandbox.CaptureMail {
+		slog.Info("Sandbox mode, outgoing mail is captured in memory")
+		return SandboxOutbox
+	}
+
+	pool := workerpool.New("mail", cfg.Workers.Mail.Size, cfg.Workers.Mail.Queue, workerpool.Policy(cfg.Workers.Mail.Policy))
+
 	switch cfg.Env.CurrentEnv {
 	case "production":
-		log.Println("INFO: Initializing Resend Mail Service for production environment.")
-		return NewResendMailService(cfg.Mail.EmailAPIKey, cfg.Mail.SenderEmail)
+		slog.Info("Initializing the Resend mail service for production")
+		return NewResendMailService(cfg.Mail.EmailAPIKey, cfg.Mail.SenderEmail).WithPool(pool)
 	case "development", "test":
-		log.Println("INFO: Initializing SMTP Mail Service for development/test environment.")
+		slog.Info("Initializing the SMTP mail service for development/test")
 		return NewSMTPMailService(
 			cfg.Mail.SMTPHost,
 			cfg.Mail.SMTPPort,
 			cfg.Mail.SMTPUsername,
 			cfg.Mail.SMTPPassword,
 			development,
-		)
+		).WithPool(pool)
 	default:
-		log.Println("DEFAULT: Initializing without an environment.")
+		slog.Info("Initializing the mail service without an environment")
 		return NewSMTPMailService(
 			cfg.Mail.SMTPHost,
 			cfg.Mail.SMTPPort,
 			cfg.Mail.SMTPUsername,
 			cfg.Mail.SMTPPassword,
 			development,
-		)
+		).WithPool(pool)
 	}
 }
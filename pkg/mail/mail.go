@@ -4,11 +4,12 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/smtp"
 
 	"github.com/abisalde/authentication-service/internal/graph/errors"
 	"github.com/abisalde/authentication-service/internal/graph/model"
+	"github.com/abisalde/authentication-service/pkg/workerpool"
 	"github.com/resend/resend-go/v2"
 )
 
@@ -22,11 +23,13 @@ type SMTPMailService struct {
 	smtpUsername string
 	smtpPassword string
 	senderEmail  string
+	pool         *workerpool.Pool
 }
 
 type ResendMailService struct {
 	client      *resend.Client
 	senderEmail string
+	pool        *workerpool.Pool
 }
 
 func NewSMTPMailService(smtpHost, smtpPort, smtpUsername, smtpPassword, defaultSenderEmail string) *SMTPMailService {
@@ -41,10 +44,10 @@ func NewSMTPMailService(smtpHost, smtpPort, smtpUsername, smtpPassword, defaultS
 
 func NewResendMailService(apiKey, defaultSenderEmail string) *ResendMailService {
 	if apiKey == "" {
-		log.Println("⚠️ WARNING: Resend API Key is empty. Email sending will likely fail.")
+		slog.Warn("Resend API key is empty, email sending will likely fail")
 	}
 	if defaultSenderEmail == "" {
-		log.Println("⚠️ WARNING:  Default sender email is empty for Resend. Emails might not be sent or might be rejected.")
+		slog.Warn("Default sender email is empty for Resend, emails might be rejected")
 	}
 	client := resend.NewClient(apiKey)
 	return &ResendMailService{
@@ -53,6 +56,27 @@ func NewResendMailService(apiKey, defaultSenderEmail string) *ResendMailService
 	}
 }
 
+// WithPool sends mail on the pool's workers instead of a goroutine per email.
+func (s *SMTPMailService) WithPool(pool *workerpool.Pool) *SMTPMailService {
+	s.pool = pool
+	return s
+}
+
+// WithPool sends mail on the pool's workers instead of a goroutine per email.
+func (s *ResendMailService) WithPool(pool *workerpool.Pool) *ResendMailService {
+	s.pool = pool
+	return s
+}
+
+// dispatch runs send on the pool, or on its own goroutine when none is set.
+func dispatch(ctx context.Context, pool *workerpool.Pool, send func()) error {
+	if pool == nil {
+		go send()
+		return nil
+	}
+	return pool.Submit(ctx, send)
+}
+
 func (s *ResendMailService) SendHTMLEmail(ctx context.Context, recipientEmail, subject, htmlBody, plainTextBody string, overrideSenderEmail ...string) error {
 
 	select {
@@ -65,9 +89,9 @@ func (s *ResendMailService) SendHTMLEmail(ctx context.Context, recipientEmail, s
 	fromEmail := s.senderEmail
 	if len(overrideSenderEmail) > 0 && overrideSenderEmail[0] != "" {
 		fromEmail = overrideSenderEmail[0]
-		log.Printf("DEBUG: Overriding sender email to: %s", fromEmail)
+		slog.Debug("Overriding the sender email", "email", fromEmail)
 	} else {
-		log.Printf("DEBUG: Using default sender email: %s", fromEmail)
+		slog.Debug("Using the default sender email", "email", fromEmail)
 	}
 
 	if fromEmail == "" {
@@ -88,24 +112,28 @@ func (s *ResendMailService) SendHTMLEmail(ctx context.Context, recipientEmail, s
 		err  error
 	}, 1)
 
-	go func() {
+	err := dispatch(ctx, s.pool, func() {
 		sent, err := s.client.Emails.Send(params)
 		resultChan <- struct {
 			sent *resend.SendEmailResponse
 			err  error
 		}{sent: sent, err: err}
-	}()
+	})
+	if err != nil {
+		slog.Error("Email not queued", "provider", "resend", "email", recipientEmail, "error", err)
+		return fmt.Errorf("🛠️ Failed to queue email via Resend API: %w", err)
+	}
 
 	select {
 	case res := <-resultChan:
 		if res.err != nil {
-			log.Printf("ERROR: ❌ Failed to send email via Resend API ❌: %v", res.err)
+			slog.Error("Failed to send email", "provider", "resend", "error", res.err)
 			return fmt.Errorf("🛠️ Failed to send HTML email via Resend API: %w", res.err)
 		}
-		log.Printf("DEBUG: ⚙️ Email sent successfully via Resend. Message ID: %s", res.sent.Id)
+		slog.Debug("Email sent", "provider", "resend", "message_id", res.sent.Id)
 		return nil
 	case <-ctx.Done():
-		log.Printf("WARNING: ⚠️ Email sending to %s was cancelled by context.", recipientEmail)
+		slog.Warn("Email sending was cancelled", "provider", "resend", "email", recipientEmail)
 		return fmt.Errorf("email sending canceled: %w", ctx.Err())
 	}
 }
@@ -121,9 +149,9 @@ func (s *SMTPMailService) SendHTMLEmail(ctx context.Context, recipientEmail, sub
 	fromEmail := s.senderEmail
 	if len(overrideSenderEmail) > 0 && overrideSenderEmail[0] != "" {
 		fromEmail = overrideSenderEmail[0]
-		log.Printf("DEBUG: Overriding sender email to: %s", fromEmail)
+		slog.Debug("Overriding the sender email", "email", fromEmail)
 	} else {
-		log.Printf("DEBUG: Using default sender email: %s", fromEmail)
+		slog.Debug("Using the default sender email", "email", fromEmail)
 	}
 
 	if fromEmail == "" {
@@ -166,7 +194,7 @@ func (s *SMTPMailService) SendHTMLEmail(ctx context.Context, recipientEmail, sub
 	auth := smtp.PlainAuth("", s.smtpUsername, s.smtpPassword, s.smtpHost)
 
 	errChan := make(chan error, 1)
-	go func() {
+	err := dispatch(ctx, s.pool, func() {
 		errChan <- smtp.SendMail(
 			fmt.Sprintf("%s:%s", s.smtpHost, s.smtpPort),
 			auth,
@@ -174,18 +202,22 @@ func (s *SMTPMailService) SendHTMLEmail(ctx context.Context, recipientEmail, sub
 			to,
 			message,
 		)
-	}()
+	})
+	if err != nil {
+		slog.Error("Email not queued", "provider", "smtp", "email", recipientEmail, "error", err)
+		return fmt.Errorf("SMTP: failed to queue email: %w", err)
+	}
 
 	select {
 	case err := <-errChan:
 		if err != nil {
-			log.Printf("ERROR: SMTP: Failed to send email: %v", err)
+			slog.Error("Failed to send email", "provider", "smtp", "error", err)
 			return fmt.Errorf("SMTP: failed to send HTML email with plain text fallback: %w", err)
 		}
-		log.Printf("INFO: SMTP: Email sent successfully to %s", recipientEmail)
+		slog.Info("Email sent", "provider", "smtp", "email", recipientEmail)
 		return nil
 	case <-ctx.Done():
-		log.Printf("WARNING: SMTP: Email sending to %s was cancelled by context.", recipientEmail)
+		slog.Warn("Email sending was cancelled", "provider", "smtp", "email", recipientEmail)
 		return fmt.Errorf("SMTP: email sending canceled: %w", ctx.Err())
 	}
 
@@ -0,0 +1,251 @@
+// Package qos admits requests into a fixed number of slots and, once they
+// are all busy, hands freed slots to waiting requests by class priority, so
+// a storm of cheap-to-send, expensive-to-serve requests cannot starve the
+// traffic of users who are already signed in.
+package qos
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/metrics"
+)
+
+// Class orders requests by priority, highest first.
+type Class int
+
+const (
+	Validation Class = iota
+	Refresh
+	Login
+	Registration
+	Analytics
+
+	numClasses
+)
+
+// Lowest is the class shed first.
+const Lowest = Analytics
+
+func (c Class) String() string {
+	switch c {
+	case Validation:
+		return "validation"
+	case Refresh:
+		return "refresh"
+	case Login:
+		return "login"
+	case Registration:
+		return "registration"
+	case Analytics:
+		return "analytics"
+	}
+	return "unknown"
+}
+
+const (
+	DefaultMaxConcurrent = 256
+	DefaultQueueTimeout  = 2 * time.Second
+	DefaultShedLowestAt  = 0.8
+)
+
+// ErrShed is returned for requests turned away under load.
+var ErrShed = errors.New("request shed under load")
+
+var (
+	admissions = metrics.Default.NewCounterVec(
+		"qos_requests_total",
+		"Requests by priority class and outcome: admitted, queued or shed.",
+		"class", "outcome",
+	)
+	queueWait = metrics.Default.NewHistogramVec(
+		"qos_queue_wait_seconds",
+		"Time queued requests waited for a slot, by priority class.",
+		metrics.DefaultBuckets,
+		"class",
+	)
+	inFlight = metrics.Default.NewGaugeVec(
+		"qos_in_flight",
+		"Requests holding a slot.",
+	)
+)
+
+// Config sizes a Scheduler. MaxQueue of 0 sheds every request that finds no
+// free slot; ShedLowestAt is the share of busy slots from which the lowest
+// class is shed without queueing, keeping headroom for the others.
+type Config struct {
+	MaxConcurrent int
+	MaxQueue      int
+	QueueTimeout  time.Duration
+	ShedLowestAt  float64
+}
+
+type waiterState int
+
+const (
+	waiting waiterState = iota
+	admitted
+	shed
+)
+
+type waiter struct {
+	ready chan struct{}
+	state waiterState
+}
+
+// Scheduler hands out slots to requests. Waiting requests are served
+// highest class first and in arrival order within a class; when the queue
+// is full, a newcomer evicts the newest waiter of a lower class or is shed
+// itself.
+type Scheduler struct {
+	cfg Config
+
+	mu      sync.Mutex
+	active  int
+	queued  int
+	waiting [numClasses]*list.List
+}
+
+func New(cfg Config) *Scheduler {
+	if cfg.MaxConcurrent <= 0 {
+		cfg.MaxConcurrent = DefaultMaxConcurrent
+	}
+	if cfg.MaxQueue < 0 {
+		cfg.MaxQueue = 0
+	}
+	if cfg.QueueTimeout <= 0 {
+		cfg.QueueTimeout = DefaultQueueTimeout
+	}
+	if cfg.ShedLowestAt <= 0 || cfg.ShedLowestAt > 1 {
+		cfg.ShedLowestAt = DefaultShedLowestAt
+	}
+
+	s := &Scheduler{cfg: cfg}
+	for i := range s.waiting {
+		s.waiting[i] = list.New()
+	}
+	return s
+}
+
+// Acquire waits for a slot for a request of class and returns the function
+// that gives it back. It fails with ErrShed when the request is turned away
+// or waited longer than the queue timeout, and with the context's error
+// when the caller gave up first.
+func (s *Scheduler) Acquire(ctx context.Context, class Class) (release func(), err error) {
+	if class < Validation || class >= numClasses {
+		class = Lowest
+	}
+
+	s.mu.Lock()
+	if class == Lowest && float64(s.active) >= s.cfg.ShedLowestAt*float64(s.cfg.MaxConcurrent) {
+		s.mu.Unlock()
+		admissions.Inc(class.String(), "shed")
+		return nil, ErrShed
+	}
+	if s.active < s.cfg.MaxConcurrent && s.queued == 0 {
+		s.active++
+		inFlight.Set(float64(s.active))
+		s.mu.Unlock()
+		admissions.Inc(class.String(), "admitted")
+		return s.release, nil
+	}
+	if s.queued >= s.cfg.MaxQueue && !s.evictBelow(class) {
+		s.mu.Unlock()
+		admissions.Inc(class.String(), "shed")
+		return nil, ErrShed
+	}
+
+	w := &waiter{ready: make(chan struct{})}
+	elem := s.waiting[class].PushBack(w)
+	s.queued++
+	s.mu.Unlock()
+	admissions.Inc(class.String(), "queued")
+
+	start := time.Now()
+	timer := time.NewTimer(s.cfg.QueueTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-w.ready:
+	case <-timer.C:
+		err = ErrShed
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	s.mu.Lock()
+	state := w.state
+	if state == waiting {
+		s.waiting[class].Remove(elem)
+		s.queued--
+		w.state = shed
+		state = shed
+	}
+	s.mu.Unlock()
+	queueWait.Observe(time.Since(start).Seconds(), class.String())
+
+	if state == admitted {
+		// The slot was handed over while the caller was giving up; keep it
+		// rather than lose it, the request is still there to be served.
+		return s.release, nil
+	}
+	if err == nil {
+		err = ErrShed
+	}
+	if errors.Is(err, ErrShed) {
+		admissions.Inc(class.String(), "shed")
+	}
+	return nil, err
+}
+
+// release passes the slot to the highest waiting class, or frees it.
+func (s *Scheduler) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, queue := range s.waiting {
+		if front := queue.Front(); front != nil {
+			w := queue.Remove(front).(*waiter)
+			s.queued--
+			w.state = admitted
+			close(w.ready)
+			return
+		}
+	}
+	s.active--
+	inFlight.Set(float64(s.active))
+}
+
+// evictBelow sheds the newest waiter of the lowest class below class to make
+// room in the queue. The caller holds s.mu.
+func (s *Scheduler) evictBelow(class Class) bool {
+	for c := numClasses - 1; c > class; c-- {
+		back := s.waiting[c].Back()
+		if back == nil {
+			continue
+		}
+		w := s.waiting[c].Remove(back).(*waiter)
+		s.queued--
+		w.state = shed
+		close(w.ready)
+		return true
+	}
+	return false
+}
+
+// Stats reports the slots in use and the requests waiting per class.
+func (s *Scheduler) Stats() (active int, queued map[Class]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	queued = make(map[Class]int, numClasses)
+	for c, queue := range s.waiting {
+		if n := queue.Len(); n > 0 {
+			queued[Class(c)] = n
+		}
+	}
+	return s.active, queued
+}
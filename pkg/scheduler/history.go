@@ -0,0 +1,110 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	HistoryPrefix = "scheduler_runs:"
+
+	// DefaultHistorySize is how many runs are kept per job.
+	DefaultHistorySize = 50
+
+	historyRetention = 30 * 24 * time.Hour
+)
+
+// History keeps the most recent runs of each job.
+type History interface {
+	Record(ctx context.Context, run Run) error
+	Runs(ctx context.Context, job string, limit int) ([]Run, error)
+}
+
+type MemoryHistory struct {
+	mu   sync.Mutex
+	size int
+	runs map[string][]Run
+}
+
+func NewMemoryHistory(size int) *MemoryHistory {
+	if size <= 0 {
+		size = DefaultHistorySize
+	}
+	return &MemoryHistory{size: size, runs: make(map[string][]Run)}
+}
+
+func (h *MemoryHistory) Record(_ context.Context, run Run) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	runs := append([]Run{run}, h.runs[run.Job]...)
+	if len(runs) > h.size {
+		runs = runs[:h.size]
+	}
+	h.runs[run.Job] = runs
+	return nil
+}
+
+func (h *MemoryHistory) Runs(_ context.Context, job string, limit int) ([]Run, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	runs := h.runs[job]
+	if limit > 0 && limit < len(runs) {
+		runs = runs[:limit]
+	}
+	return append([]Run(nil), runs...), nil
+}
+
+// RedisHistory shares run history across replicas in a capped list per job.
+type RedisHistory struct {
+	client *redis.Client
+	size   int
+}
+
+func NewRedisHistory(client *redis.Client, size int) *RedisHistory {
+	if size <= 0 {
+		size = DefaultHistorySize
+	}
+	return &RedisHistory{client: client, size: size}
+}
+
+func (h *RedisHistory) Record(ctx context.Context, run Run) error {
+	data, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+
+	key := HistoryPrefix + run.Job
+	pipe := h.client.TxPipeline()
+	pipe.LPush(ctx, key, data)
+	pipe.LTrim(ctx, key, 0, int64(h.size-1))
+	pipe.Expire(ctx, key, historyRetention)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (h *RedisHistory) Runs(ctx context.Context, job string, limit int) ([]Run, error) {
+	if limit <= 0 || limit > h.size {
+		limit = h.size
+	}
+
+	raw, err := h.client.LRange(ctx, HistoryPrefix+job, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]Run, 0, len(raw))
+	for _, item := range raw {
+		var run Run
+		if err := json.Unmarshal([]byte(item), &run); err != nil {
+			continue
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
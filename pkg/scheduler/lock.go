@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const LockPrefix = "scheduler_lock:"
+
+// Locker grants a job's lock to one caller at a time. The lock lapses after
+// ttl even when release is never called, so a crashed replica cannot block a
+// job for good.
+type Locker interface {
+	TryLock(ctx context.Context, job string, ttl time.Duration) (release func(), ok bool, err error)
+}
+
+// localLocker always grants the lock; the scheduler never runs a job
+// concurrently with itself in one process anyway.
+type localLocker struct{}
+
+func (localLocker) TryLock(context.Context, string, time.Duration) (func(), bool, error) {
+	return func() {}, true, nil
+}
+
+// releaseScript deletes the lock only while it still holds this holder's
+// token, so a run that outlived its ttl cannot release the next holder's lock.
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+type RedisLocker struct {
+	client *redis.Client
+}
+
+func NewRedisLocker(client *redis.Client) *RedisLocker {
+	return &RedisLocker{client: client}
+}
+
+func (l *RedisLocker) TryLock(ctx context.Context, job string, ttl time.Duration) (func(), bool, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, false, err
+	}
+	token := hex.EncodeToString(buf)
+	key := LockPrefix + job
+
+	ok, err := l.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	release := func() {
+		// The run's context may already be done; release on a fresh one.
+		releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = releaseScript.Run(releaseCtx, l.client, []string{key}, token).Err()
+	}
+	return release, true, nil
+}
@@ -0,0 +1,265 @@
+// Package scheduler runs periodic jobs such as janitors and pruners. Each run
+// takes a lock first, so with a shared Locker a job runs on one replica at a
+// time, and every run is recorded in a History.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abisalde/authentication-service/internal/metrics"
+)
+
+var (
+	ErrDuplicateJob = errors.New("scheduler: job already registered")
+	ErrStarted      = errors.New("scheduler: jobs cannot be registered after Start")
+)
+
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+	// OutcomeSkipped marks ticks where another replica held the lock.
+	OutcomeSkipped = "skipped"
+)
+
+var (
+	jobRuns = metrics.Default.NewCounterVec(
+		"scheduler_job_runs_total",
+		"Scheduled job ticks by outcome: success, failure or skipped.",
+		"job", "outcome",
+	)
+	jobDuration = metrics.Default.NewHistogramVec(
+		"scheduler_job_duration_seconds",
+		"Duration of scheduled job runs.",
+		[]float64{0.1, 0.5, 1, 5, 15, 60, 300, 900},
+		"job",
+	)
+)
+
+// Job is a named periodic task. Timeout bounds a single run and how long its
+// lock is held; it defaults to the job's interval.
+type Job struct {
+	Name     string
+	Schedule string
+	Timeout  time.Duration
+	Run      func(ctx context.Context) error
+
+	interval time.Duration
+}
+
+// Run is one execution of a job as kept in History.
+type Run struct {
+	Job       string        `json:"job"`
+	Instance  string        `json:"instance"`
+	StartedAt time.Time     `json:"startedAt"`
+	Duration  time.Duration `json:"duration"`
+	Error     string        `json:"error,omitempty"`
+}
+
+type Scheduler struct {
+	locker   Locker
+	history  History
+	instance string
+
+	mu      sync.Mutex
+	jobs    []*Job
+	started bool
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// New returns a scheduler. A nil locker runs every job on every replica and a
+// nil history keeps runs in memory.
+func New(locker Locker, history History) *Scheduler {
+	if locker == nil {
+		locker = localLocker{}
+	}
+	if history == nil {
+		history = NewMemoryHistory(DefaultHistorySize)
+	}
+	return &Scheduler{locker: locker, history: history, instance: instanceName()}
+}
+
+// Register adds a job. schedule is "@every <duration>", "@hourly" or
+// "@daily".
+func (s *Scheduler) Register(name, schedule string, run func(ctx context.Context) error) error {
+	return s.RegisterJob(Job{Name: name, Schedule: schedule, Run: run})
+}
+
+func (s *Scheduler) RegisterJob(job Job) error {
+	interval, err := ParseSchedule(job.Schedule)
+	if err != nil {
+		return fmt.Errorf("scheduler: job %s: %w", job.Name, err)
+	}
+	if job.Name == "" || job.Run == nil {
+		return fmt.Errorf("scheduler: job needs a name and a run function")
+	}
+	if job.Timeout <= 0 || job.Timeout > interval {
+		job.Timeout = interval
+	}
+	job.interval = interval
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return ErrStarted
+	}
+	for _, existing := range s.jobs {
+		if existing.Name == job.Name {
+			return ErrDuplicateJob
+		}
+	}
+	s.jobs = append(s.jobs, &job)
+	return nil
+}
+
+// ParseSchedule turns a schedule descriptor into its interval.
+func ParseSchedule(schedule string) (time.Duration, error) {
+	switch schedule = strings.TrimSpace(schedule); schedule {
+	case "@hourly":
+		return time.Hour, nil
+	case "@daily":
+		return 24 * time.Hour, nil
+	}
+
+	raw, ok := strings.CutPrefix(schedule, "@every ")
+	if !ok {
+		return 0, fmt.Errorf("unsupported schedule %q", schedule)
+	}
+	interval, err := time.ParseDuration(strings.TrimSpace(raw))
+	if err != nil {
+		return 0, fmt.Errorf("invalid schedule %q: %w", schedule, err)
+	}
+	if interval < time.Second {
+		return 0, fmt.Errorf("schedule %q is shorter than a second", schedule)
+	}
+	return interval, nil
+}
+
+// Start runs every registered job on its own ticker until ctx is done or
+// Stop is called. The first run happens one interval after Start.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return
+	}
+	s.started = true
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+}
+
+// Stop cancels running jobs and waits for them to return.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	s.wg.Wait()
+}
+
+// History returns the most recent runs of a job, newest first.
+func (s *Scheduler) History(ctx context.Context, job string, limit int) ([]Run, error) {
+	return s.history.Runs(ctx, job, limit)
+}
+
+func (s *Scheduler) loop(ctx context.Context, job *Job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.RunOnce(ctx, job.Name)
+		}
+	}
+}
+
+// RunOnce runs a registered job now, unless another replica holds its lock.
+// It reports the outcome.
+func (s *Scheduler) RunOnce(ctx context.Context, name string) string {
+	job := s.job(name)
+	if job == nil {
+		return OutcomeSkipped
+	}
+
+	release, ok, err := s.locker.TryLock(ctx, job.Name, job.Timeout)
+	if err != nil {
+		slog.Error("scheduler: failed to lock the job", "job", job.Name, "error", err)
+		jobRuns.Inc(job.Name, OutcomeSkipped)
+		return OutcomeSkipped
+	}
+	if !ok {
+		jobRuns.Inc(job.Name, OutcomeSkipped)
+		return OutcomeSkipped
+	}
+	defer release()
+
+	run := Run{Job: job.Name, Instance: s.instance, StartedAt: time.Now()}
+	err = s.execute(ctx, job)
+	run.Duration = time.Since(run.StartedAt)
+
+	outcome := OutcomeSuccess
+	if err != nil {
+		outcome = OutcomeFailure
+		run.Error = err.Error()
+		slog.Error("scheduler: job failed", "job", job.Name, "error", err)
+	}
+
+	jobRuns.Inc(job.Name, outcome)
+	jobDuration.Observe(run.Duration.Seconds(), job.Name)
+	if err := s.history.Record(ctx, run); err != nil {
+		slog.Error("scheduler: failed to record the run", "job", job.Name, "error", err)
+	}
+	return outcome
+}
+
+func (s *Scheduler) execute(ctx context.Context, job *Job) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, job.Timeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run(ctx)
+}
+
+func (s *Scheduler) job(name string) *Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, job := range s.jobs {
+		if job.Name == name {
+			return job
+		}
+	}
+	return nil
+}
+
+func instanceName() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
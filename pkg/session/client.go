@@ -0,0 +1,208 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	loginMutation = `mutation Login($input: LoginInput!) {
+	login(input: $input) { token refreshToken publicId }
+}`
+	refreshMutation = `mutation Refresh($token: String!, $userID: ID!) {
+	refreshToken(token: $token, userID: $userID) { token }
+}`
+	logoutMutation = `mutation Logout { logout }`
+
+	// refreshRejectedCode is the GraphQL error code for an unknown or
+	// replaced refresh token.
+	refreshRejectedCode = "REFRESH_TOKEN"
+)
+
+// Client runs the mobile token lifecycle against the GraphQL endpoint with
+// bearer tokens only: sign in once, keep the refresh token in Store and mint
+// short-lived access tokens from it as they run out.
+//
+// The service keeps one refresh token per user, so signing in again, on this
+// or another device, replaces it; the next refresh here then fails with
+// ErrSessionExpired and the app should ask the user to sign in.
+type Client struct {
+	endpoint   string
+	deviceID   string
+	store      Store
+	httpClient *http.Client
+	skew       time.Duration
+
+	// mu serialises refreshes so concurrent requests share one round trip.
+	mu sync.Mutex
+}
+
+func NewClient(endpoint, deviceID string, store Store) *Client {
+	return &Client{
+		endpoint:   endpoint,
+		deviceID:   deviceID,
+		store:      store,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+		skew:       DefaultRefreshSkew,
+	}
+}
+
+func (c *Client) WithHTTPClient(httpClient *http.Client) *Client {
+	c.httpClient = httpClient
+	return c
+}
+
+func (c *Client) Login(ctx context.Context, email, password string) (*Tokens, error) {
+	var data struct {
+		Login struct {
+			Token        string `json:"token"`
+			RefreshToken string `json:"refreshToken"`
+			PublicID     string `json:"publicId"`
+		} `json:"login"`
+	}
+
+	vars := map[string]any{"input": map[string]string{"email": email, "password": password}}
+	if err := c.do(ctx, "", loginMutation, vars, &data); err != nil {
+		return nil, err
+	}
+
+	tokens, err := NewTokens(data.Login.PublicID, data.Login.Token, data.Login.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.store.Save(ctx, tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// AccessToken returns a stored access token, refreshing it first when it is
+// within the refresh skew of expiring.
+func (c *Client) AccessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tokens, err := c.store.Load(ctx)
+	if err != nil {
+		return "", err
+	}
+	if !tokens.NeedsRefresh(time.Now(), c.skew) {
+		return tokens.AccessToken, nil
+	}
+
+	tokens, err = c.refresh(ctx, tokens)
+	if err != nil {
+		return "", err
+	}
+	return tokens.AccessToken, nil
+}
+
+func (c *Client) refresh(ctx context.Context, tokens *Tokens) (*Tokens, error) {
+	var data struct {
+		RefreshToken struct {
+			Token string `json:"token"`
+		} `json:"refreshToken"`
+	}
+
+	vars := map[string]any{"token": tokens.RefreshToken, "userID": tokens.UserID}
+	err := c.do(ctx, "", refreshMutation, vars, &data)
+
+	var gqlErr *GraphQLError
+	if errors.As(err, &gqlErr) && gqlErr.Code == refreshRejectedCode {
+		_ = c.store.Clear(ctx)
+		return nil, ErrSessionExpired
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	refreshed, err := NewTokens(tokens.UserID, data.RefreshToken.Token, tokens.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.store.Save(ctx, refreshed); err != nil {
+		return nil, err
+	}
+	return refreshed, nil
+}
+
+// Logout revokes the session on the service and clears local tokens even
+// when the service cannot be reached.
+func (c *Client) Logout(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tokens, err := c.store.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	var data struct {
+		Logout bool `json:"logout"`
+	}
+	logoutErr := c.do(ctx, tokens.AccessToken, logoutMutation, nil, &data)
+
+	if err := c.store.Clear(ctx); err != nil {
+		return err
+	}
+	return logoutErr
+}
+
+// GraphQLError is the first error the endpoint returned for an operation.
+type GraphQLError struct {
+	Message string
+	Code    string
+}
+
+func (e *GraphQLError) Error() string {
+	return fmt.Sprintf("session: %s (%s)", e.Message, e.Code)
+}
+
+func (c *Client) do(ctx context.Context, accessToken, query string, vars map[string]any, out any) error {
+	body, err := json.Marshal(map[string]any{"query": query, "variables": vars})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.deviceID != "" {
+		req.Header.Set(DeviceIDHeader, c.deviceID)
+	}
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message    string `json:"message"`
+			Extensions struct {
+				Code string `json:"code"`
+			} `json:"extensions"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return fmt.Errorf("session: unexpected response (%d): %w", resp.StatusCode, err)
+	}
+
+	if len(payload.Errors) > 0 {
+		return &GraphQLError{Message: payload.Errors[0].Message, Code: payload.Errors[0].Extensions.Code}
+	}
+	return json.Unmarshal(payload.Data, out)
+}
@@ -0,0 +1,117 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Session event types the auth service appends to SessionEventStream.
+const (
+	// EventSessionCreated: the user signed in, replacing their previous
+	// refresh session.
+	EventSessionCreated = "session_created"
+	// EventSessionRevoked: the user's refresh session ended, e.g. on logout,
+	// and its access tokens were blacklisted.
+	EventSessionRevoked = "session_revoked"
+	// EventUserSessionsRevoked: every token of the user issued so far was
+	// revoked, e.g. a forced re-login, a suspension or an email
+	// change. Drop anything cached for the user.
+	EventUserSessionsRevoked = "user_sessions_revoked"
+)
+
+// SessionEventStream is the Redis stream the auth service publishes session
+// events to.
+const SessionEventStream = "session_events"
+
+const (
+	subscriberBlock      = 5 * time.Second
+	subscriberRetryDelay = time.Second
+)
+
+// SessionEvent mirrors the session_created, session_revoked and
+// user_sessions_revoked payloads. Subject is the sub claim of the user's
+// tokens; it is empty if the service could not look the user up. TokenIDs
+// are the jti claims of the access tokens blacklisted with the session.
+type SessionEvent struct {
+	ID        string    `json:"-"`
+	Type      string    `json:"event_type"`
+	UserID    int64     `json:"user_id"`
+	Subject   string    `json:"user_public_id"`
+	Reason    string    `json:"reason,omitempty"`
+	TokenIDs  []string  `json:"token_ids,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Subscriber tails the auth service's session events in the Redis it
+// shares with the service, so backends caching sessions, introspection
+// results or users can drop them as soon as a user signs out everywhere
+// instead of when their cache expires. Every subscriber sees every event.
+type Subscriber struct {
+	client redis.Cmdable
+	lastID string
+	logger *slog.Logger
+}
+
+// NewSubscriber delivers the events published after Run starts.
+func NewSubscriber(client redis.Cmdable) *Subscriber {
+	return &Subscriber{client: client, lastID: "$", logger: slog.Default()}
+}
+
+// WithLogger sets where retried reads and skipped events are reported.
+func (s *Subscriber) WithLogger(logger *slog.Logger) *Subscriber {
+	s.logger = logger
+	return s
+}
+
+// After resumes after the event with the given ID, e.g. the last one
+// handled before a restart, so none are missed.
+func (s *Subscriber) After(id string) *Subscriber {
+	s.lastID = id
+	return s
+}
+
+// Run calls handle with each event, in order, until ctx ends, and returns
+// ctx's error. Redis errors are retried; payloads that do not decode are
+// skipped.
+func (s *Subscriber) Run(ctx context.Context, handle func(context.Context, SessionEvent)) error {
+	for ctx.Err() == nil {
+		streams, err := s.client.XRead(ctx, &redis.XReadArgs{
+			Streams: []string{SessionEventStream, s.lastID},
+			Block:   subscriberBlock,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				s.logger.Warn("Failed to read session events, retrying", "error", err)
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(subscriberRetryDelay):
+			}
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				s.lastID = msg.ID
+
+				payload, _ := msg.Values["event"].(string)
+				var event SessionEvent
+				if err := json.Unmarshal([]byte(payload), &event); err != nil {
+					s.logger.Warn("Skipping session event that does not decode", "event_id", msg.ID, "error", err)
+					continue
+				}
+				event.ID = msg.ID
+				handle(ctx, event)
+			}
+		}
+	}
+	return ctx.Err()
+}
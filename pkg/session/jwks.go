@@ -0,0 +1,243 @@
+package session
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/abisalde/authentication-service/pkg/jwt"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultJWKSRefresh matches the max-age the auth service sends with its key
+// set, so a key published ahead of a rotation is known before it signs.
+const DefaultJWKSRefresh = 5 * time.Minute
+
+// BlacklistedTokenIDPrefix is where the auth service blacklists the jti of
+// each access token of a revoked session until it expires.
+const BlacklistedTokenIDPrefix = "blacklist:jti:"
+
+// minJWKSRefetch bounds how often an unknown kid can trigger a fetch, so
+// tokens with made-up kids cannot hammer the auth service.
+const minJWKSRefetch = 30 * time.Second
+
+// JWKSProvider keeps the auth service's public signing keys from
+// /.well-known/jwks.json. Keys are refreshed in the background and on an
+// unknown kid, so rotations need no restart of the backends.
+type JWKSProvider struct {
+	endpoint   string
+	httpClient *http.Client
+	logger     *slog.Logger
+
+	refreshMu sync.Mutex
+	mu        sync.RWMutex
+	keys      map[string]*jwt.SigningKey
+	fetchedAt time.Time
+	// etag versions keys; refreshes send it so an unchanged set costs a 304.
+	etag string
+}
+
+func NewJWKSProvider(endpoint string) *JWKSProvider {
+	return &JWKSProvider{
+		endpoint:   endpoint,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		logger:     slog.Default(),
+		keys:       make(map[string]*jwt.SigningKey),
+	}
+}
+
+func (p *JWKSProvider) WithHTTPClient(httpClient *http.Client) *JWKSProvider {
+	p.httpClient = httpClient
+	return p
+}
+
+// WithLogger sets where failed background refreshes are reported.
+func (p *JWKSProvider) WithLogger(logger *slog.Logger) *JWKSProvider {
+	p.logger = logger
+	return p
+}
+
+// Start refreshes the key set every interval until ctx is done. A failed
+// refresh keeps the keys from the last successful one.
+func (p *JWKSProvider) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultJWKSRefresh
+	}
+	p.refresh(ctx)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.refresh(ctx)
+			}
+		}
+	}()
+}
+
+func (p *JWKSProvider) refresh(ctx context.Context) {
+	if err := p.Refresh(ctx); err != nil && ctx.Err() == nil {
+		p.logger.Warn("Failed to refresh signing keys, keeping the cached ones", "endpoint", p.endpoint, "error", err)
+	}
+}
+
+// Refresh replaces the cached keys with the published set. Keys that fail to
+// parse are skipped rather than failing the whole set. An unchanged set is
+// not downloaded again.
+func (p *JWKSProvider) Refresh(ctx context.Context) error {
+	p.refreshMu.Lock()
+	defer p.refreshMu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	p.mu.RLock()
+	if p.etag != "" {
+		req.Header.Set("If-None-Match", p.etag)
+	}
+	p.mu.RUnlock()
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		p.mu.Lock()
+		p.fetchedAt = time.Now()
+		p.mu.Unlock()
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("session: jwks returned %d", resp.StatusCode)
+	}
+
+	var set jwt.JWKS
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return err
+	}
+
+	keys := make(map[string]*jwt.SigningKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if key, err := jwt.ParseJWK(jwk); err == nil {
+			keys[key.ID] = key
+		}
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.fetchedAt = time.Now()
+	p.etag = resp.Header.Get("ETag")
+	p.mu.Unlock()
+	return nil
+}
+
+// Key returns the key named kid, fetching the set again when the kid is
+// unknown and the last fetch is old enough.
+func (p *JWKSProvider) Key(ctx context.Context, kid string) (*jwt.SigningKey, error) {
+	key, fetchedAt := p.lookup(kid)
+	if key != nil {
+		return key, nil
+	}
+
+	if time.Since(fetchedAt) >= minJWKSRefetch {
+		if err := p.Refresh(ctx); err != nil {
+			return nil, err
+		}
+		if key, _ = p.lookup(kid); key != nil {
+			return key, nil
+		}
+	}
+	return nil, jwt.ErrUnknownKey
+}
+
+func (p *JWKSProvider) lookup(kid string) (*jwt.SigningKey, time.Time) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.keys[kid], p.fetchedAt
+}
+
+// Validator verifies access tokens locally against the published keys or a
+// pinned RSA public key, for backends that must not hold JWT_SECRET. Like
+// ValidateAccessToken it applies the issuer, leeway and AcceptHS256
+// configured with jwt.Configure.
+type Validator struct {
+	keys        *JWKSProvider
+	publicKey   *rsa.PublicKey
+	revocations redis.Cmdable
+}
+
+func NewValidator(keys *JWKSProvider) *Validator {
+	return &Validator{keys: keys}
+}
+
+// NewPublicKeyValidator verifies RS256, RS384 and RS512 tokens against one
+// public key, e.g. read from a secret manager, whatever their kid. Rotating
+// the key means restarting with the new one; use NewValidator to follow
+// rotations. HS256 tokens pass only while AcceptHS256 is set.
+func NewPublicKeyValidator(public *rsa.PublicKey) (*Validator, error) {
+	if public == nil || public.N == nil {
+		return nil, errors.New("session: public key must not be nil")
+	}
+	if public.N.BitLen() < 2048 {
+		return nil, errors.New("session: RSA keys must be at least 2048 bits")
+	}
+	return &Validator{publicKey: public}, nil
+}
+
+// WithRevocations checks tokens against the blacklist the auth service keeps
+// in the Redis it shares with the backend, so a signed-out session is
+// rejected before its access tokens expire. The blacklist is keyed by jti,
+// so a token without one, which could never be revoked, is refused too.
+// Without it, a valid signature is enough.
+func (v *Validator) WithRevocations(client redis.Cmdable) *Validator {
+	v.revocations = client
+	return v
+}
+
+// ValidateAccessToken picks the key by the token's kid unless a public key
+// is pinned. When deviceID is set, a token bound to a different device is
+// rejected. A failed blacklist lookup rejects the token rather than letting
+// a revoked one through.
+func (v *Validator) ValidateAccessToken(ctx context.Context, token, deviceID string) (*jwt.Claims, error) {
+	var claims *jwt.Claims
+	var err error
+	if v.publicKey != nil {
+		claims, err = jwt.ValidateTokenWithPublicKey(ctx, token, v.publicKey)
+	} else {
+		claims, err = jwt.ValidateTokenWithKeys(ctx, token, v.keys.Key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if claims, err = checkAccessClaims(claims, deviceID); err != nil {
+		return nil, err
+	}
+
+	if v.revocations != nil {
+		if claims.ID == "" {
+			return nil, ErrMalformedTokens
+		}
+		revoked, err := v.revocations.Exists(ctx, BlacklistedTokenIDPrefix+claims.ID).Result()
+		if err != nil {
+			return nil, err
+		}
+		if revoked > 0 {
+			return nil, ErrTokenRevoked
+		}
+	}
+	return claims, nil
+}
@@ -0,0 +1,56 @@
+package session
+
+import (
+	"context"
+	"sync"
+)
+
+// Store persists tokens between app launches. On a device, back it with the
+// iOS Keychain (accessible after first unlock, this device only) or
+// Keystore-encrypted storage on Android. Never keep the refresh token in
+// plain preferences, files, logs or anything synced to backups.
+type Store interface {
+	// Load returns ErrNoTokens when nothing is stored.
+	Load(ctx context.Context) (*Tokens, error)
+	Save(ctx context.Context, tokens *Tokens) error
+	Clear(ctx context.Context) error
+}
+
+// MemoryStore keeps tokens for the life of the process, for tests and
+// short-lived tools.
+type MemoryStore struct {
+	mu     sync.Mutex
+	tokens *Tokens
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (m *MemoryStore) Load(ctx context.Context) (*Tokens, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.tokens == nil {
+		return nil, ErrNoTokens
+	}
+	tokens := *m.tokens
+	return &tokens, nil
+}
+
+func (m *MemoryStore) Save(ctx context.Context, tokens *Tokens) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	saved := *tokens
+	m.tokens = &saved
+	return nil
+}
+
+func (m *MemoryStore) Clear(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tokens = nil
+	return nil
+}
@@ -0,0 +1,82 @@
+package session
+
+import (
+	"errors"
+	"time"
+
+	"github.com/abisalde/authentication-service/pkg/jwt"
+)
+
+// DeviceIDHeader carries a stable per-install device ID. Access tokens minted
+// for a request with the header are bound to that device.
+const DeviceIDHeader = "X-Device-ID"
+
+// DefaultRefreshSkew refreshes access tokens this long before they expire, so
+// a request never leaves the device with a token about to lapse.
+const DefaultRefreshSkew = time.Minute
+
+var (
+	ErrNoTokens        = errors.New("session: no tokens stored")
+	ErrSessionExpired  = errors.New("session: refresh token rejected, sign in again")
+	ErrDeviceMismatch  = errors.New("session: access token bound to another device")
+	ErrNotAccessToken  = errors.New("session: not an access token")
+	ErrTokenRevoked    = errors.New("session: access token revoked")
+	ErrMalformedTokens = errors.New("session: malformed access token")
+)
+
+// Tokens is what a mobile client keeps between launches: a long-lived
+// refresh token and the short-lived access token minted from it.
+type Tokens struct {
+	UserID          string    `json:"userId"`
+	AccessToken     string    `json:"accessToken"`
+	RefreshToken    string    `json:"refreshToken"`
+	AccessExpiresAt time.Time `json:"accessExpiresAt"`
+}
+
+// NewTokens reads the access token expiry without verifying the signature;
+// the client only needs it to schedule refreshes.
+func NewTokens(userID, accessToken, refreshToken string) (*Tokens, error) {
+	ttl := jwt.GetTokenRemainingTTL(accessToken)
+	if ttl <= 0 {
+		return nil, ErrMalformedTokens
+	}
+
+	return &Tokens{
+		UserID:          userID,
+		AccessToken:     accessToken,
+		RefreshToken:    refreshToken,
+		AccessExpiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+// NeedsRefresh reports whether the access token expires within skew of now.
+func (t *Tokens) NeedsRefresh(now time.Time, skew time.Duration) bool {
+	return t.AccessToken == "" || !now.Add(skew).Before(t.AccessExpiresAt)
+}
+
+// ValidateAccessToken verifies an access token locally, without a round trip
+// to the auth service, for backends serving mobile apps. It needs the same
+// JWT configuration as the auth service. When deviceID is set, a token bound
+// to a different device is rejected; unbound tokens are accepted.
+func ValidateAccessToken(token, deviceID string) (*jwt.Claims, error) {
+	claims, err := jwt.ValidateToken(token)
+	if err != nil {
+		return nil, err
+	}
+	return checkAccessClaims(claims, deviceID)
+}
+
+func checkAccessClaims(claims *jwt.Claims, deviceID string) (*jwt.Claims, error) {
+	if !claims.IsAccessToken() {
+		return nil, ErrNotAccessToken
+	}
+
+	if deviceID != "" && claims.Device != nil {
+		expected := jwt.NewDeviceClaim(deviceID, "")
+		if expected == nil || expected.ID != claims.Device.ID {
+			return nil, ErrDeviceMismatch
+		}
+	}
+
+	return claims, nil
+}
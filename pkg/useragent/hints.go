@@ -0,0 +1,199 @@
+package useragent
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Client hint request headers. Browsers send the first three on every
+// request; the others only after the server asks for them in Accept-CH.
+const (
+	HeaderUA                = "Sec-CH-UA"
+	HeaderMobile            = "Sec-CH-UA-Mobile"
+	HeaderPlatform          = "Sec-CH-UA-Platform"
+	HeaderPlatformVersion   = "Sec-CH-UA-Platform-Version"
+	HeaderFullVersionList   = "Sec-CH-UA-Full-Version-List"
+	HeaderModel             = "Sec-CH-UA-Model"
+	HeaderAcceptClientHints = "Accept-CH"
+)
+
+// AcceptCH lists the high-entropy hints the service asks browsers for.
+const AcceptCH = HeaderPlatformVersion + ", " + HeaderFullVersionList + ", " + HeaderModel
+
+const maxHintLength = 256
+
+// ClientHints holds the raw Sec-CH-UA headers of a request, kept as sent
+// so they can be stored next to the User-Agent and parsed again later.
+type ClientHints struct {
+	UA              string `json:"ua,omitempty"`
+	Mobile          string `json:"mobile,omitempty"`
+	Platform        string `json:"platform,omitempty"`
+	PlatformVersion string `json:"platformVersion,omitempty"`
+	FullVersionList string `json:"fullVersionList,omitempty"`
+	Model           string `json:"model,omitempty"`
+}
+
+// HintsFrom reads the client hint headers through get, e.g. http.Header.Get.
+func HintsFrom(get func(header string) string) ClientHints {
+	read := func(header string) string {
+		return truncate(strings.TrimSpace(get(header)), maxHintLength)
+	}
+	return ClientHints{
+		UA:              read(HeaderUA),
+		Mobile:          read(HeaderMobile),
+		Platform:        read(HeaderPlatform),
+		PlatformVersion: read(HeaderPlatformVersion),
+		FullVersionList: read(HeaderFullVersionList),
+		Model:           read(HeaderModel),
+	}
+}
+
+func (h ClientHints) IsZero() bool {
+	return h == ClientHints{}
+}
+
+func (h ClientHints) apply(info *DeviceInfo) {
+	if name, version := h.browser(); name != "" && !info.Bot {
+		info.Browser = name
+		// The full version list beats the User-Agent's frozen "124.0.0.0",
+		// which beats a major-only Sec-CH-UA.
+		if version != "" && (h.FullVersionList != "" || majorVersion(info.BrowserVersion) != version) {
+			info.BrowserVersion = version
+		}
+	}
+
+	if platform := unquote(h.Platform); platform != "" {
+		// iPadOS reports its platform as iOS.
+		if os := platformOS(platform); os != info.OS && !(os == osIOS && info.OS == osIPadOS) {
+			info.OS, info.OSVersion = os, ""
+		}
+	}
+	if version := hintedOSVersion(info.OS, unquote(h.PlatformVersion)); version != "" {
+		info.OSVersion = version
+	}
+
+	if model := unquote(h.Model); model != "" {
+		info.Model = truncate(model, maxNameLength)
+	}
+	if info.Bot {
+		return
+	}
+	switch h.Mobile {
+	case "?1":
+		info.Type = DeviceMobile
+	case "?0":
+		if info.Type == DeviceMobile || info.Type == "" {
+			info.Type = DeviceDesktop
+		}
+	}
+}
+
+// browser picks the most specific brand from the brand list: a real brand
+// over "Chromium", skipping the GREASE entries like "Not-A.Brand".
+func (h ClientHints) browser() (string, string) {
+	list := h.FullVersionList
+	if list == "" {
+		list = h.UA
+	}
+
+	var name, version string
+	for _, entry := range splitBrands(list) {
+		brand, brandVersion := entry[0], entry[1]
+		if isGrease(brand) {
+			continue
+		}
+		if brand == "Chromium" {
+			if name == "" {
+				name, version = brand, brandVersion
+			}
+			continue
+		}
+		name, version = brandName(brand), brandVersion
+		break
+	}
+	return name, version
+}
+
+// splitBrands parses a structured header list such as
+// `"Chromium";v="124", "Google Chrome";v="124"`.
+func splitBrands(list string) [][2]string {
+	var brands [][2]string
+	for _, item := range strings.Split(list, ",") {
+		brand, params, _ := strings.Cut(strings.TrimSpace(item), ";")
+		brand = unquote(brand)
+		if brand == "" {
+			continue
+		}
+		var version string
+		for _, param := range strings.Split(params, ";") {
+			if key, value, found := strings.Cut(strings.TrimSpace(param), "="); found && key == "v" {
+				version = truncate(unquote(value), maxVersionLength)
+			}
+		}
+		brands = append(brands, [2]string{brand, version})
+	}
+	return brands
+}
+
+func isGrease(brand string) bool {
+	lower := strings.ToLower(brand)
+	return strings.Contains(lower, "not") && strings.Contains(lower, "brand")
+}
+
+func brandName(brand string) string {
+	switch brand {
+	case "Google Chrome":
+		return "Chrome"
+	case "Microsoft Edge":
+		return "Edge"
+	case "Opera GX":
+		return "Opera"
+	case "YaBrowser":
+		return "Yandex"
+	}
+	return truncate(brand, maxNameLength)
+}
+
+func platformOS(platform string) string {
+	switch platform {
+	case "Windows":
+		return osWindows
+	case "macOS":
+		return osMacOS
+	case "Android":
+		return osAndroid
+	case "iOS":
+		return osIOS
+	case "Linux":
+		return osLinux
+	case "Chrome OS", "Chromium OS":
+		return osChromeOS
+	}
+	return truncate(platform, maxNameLength)
+}
+
+// hintedOSVersion turns a platform version into what people call the
+// release. On Windows it is the UniversalApiContract version: 13 and up is
+// Windows 11, 1 to 10 is Windows 10.
+func hintedOSVersion(os, version string) string {
+	if os == "" || version == "" {
+		return ""
+	}
+	if os != osWindows {
+		return strings.TrimSuffix(strings.TrimSuffix(version, ".0"), ".0")
+	}
+	major, err := strconv.Atoi(majorVersion(version))
+	switch {
+	case err != nil:
+		return ""
+	case major >= 13:
+		return "11"
+	case major > 0:
+		return "10"
+	}
+	return ""
+}
+
+func unquote(s string) string {
+	return strings.Trim(strings.TrimSpace(s), `"`)
+}
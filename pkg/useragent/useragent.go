@@ -0,0 +1,388 @@
+// Package useragent turns User-Agent strings and Sec-CH-UA client hints
+// into structured device information: browser and OS with their versions,
+// the form factor, and whether the client is a bot.
+package useragent
+
+import (
+	"strings"
+)
+
+type DeviceType string
+
+const (
+	DeviceDesktop DeviceType = "DESKTOP"
+	DeviceMobile  DeviceType = "MOBILE"
+	DeviceTablet  DeviceType = "TABLET"
+	DeviceBot     DeviceType = "BOT"
+	DeviceOther   DeviceType = "OTHER"
+)
+
+// DeviceInfo describes a client. Fields that could not be determined are
+// empty; versions are as precise as the client reported them.
+type DeviceInfo struct {
+	Browser        string     `json:"browser,omitempty"`
+	BrowserVersion string     `json:"browserVersion,omitempty"`
+	OS             string     `json:"os,omitempty"`
+	OSVersion      string     `json:"osVersion,omitempty"`
+	Model          string     `json:"model,omitempty"`
+	Type           DeviceType `json:"type,omitempty"`
+	Bot            bool       `json:"bot,omitempty"`
+}
+
+// Family is the lowercase browser or client name, e.g. "chrome" or
+// "okhttp", stable across versions.
+func (d DeviceInfo) Family() string {
+	return strings.ReplaceAll(strings.ToLower(d.Browser), " ", "-")
+}
+
+// Platform maps the OS onto the coarse platforms of device claims: ios,
+// android, windows, macos, linux or other.
+func (d DeviceInfo) Platform() string {
+	switch d.OS {
+	case osIOS, osIPadOS:
+		return "ios"
+	case osAndroid:
+		return "android"
+	case osWindows:
+		return "windows"
+	case osMacOS:
+		return "macos"
+	case osLinux:
+		return "linux"
+	}
+	return "other"
+}
+
+// String renders the device for people, e.g. "Firefox 128 on Linux".
+func (d DeviceInfo) String() string {
+	browser := d.Browser
+	if major := majorVersion(d.BrowserVersion); browser != "" && major != "" {
+		browser += " " + major
+	}
+	os := d.OS
+	if os != "" && d.OSVersion != "" {
+		os += " " + d.OSVersion
+	}
+
+	switch {
+	case browser == "":
+		return os
+	case os == "":
+		return browser
+	}
+	return browser + " on " + os
+}
+
+const (
+	osWindows  = "Windows"
+	osMacOS    = "macOS"
+	osIOS      = "iOS"
+	osIPadOS   = "iPadOS"
+	osAndroid  = "Android"
+	osLinux    = "Linux"
+	osChromeOS = "ChromeOS"
+)
+
+// Parse reads a User-Agent header.
+func Parse(userAgent string) DeviceInfo {
+	return ParseWithHints(userAgent, ClientHints{})
+}
+
+// ParseWithHints reads a User-Agent header and lets client hints override
+// what it says. Chromium freezes the OS and browser versions in the
+// User-Agent, so the hints are the only source of precise values there.
+func ParseWithHints(userAgent string, hints ClientHints) DeviceInfo {
+	ua := strings.TrimSpace(userAgent)
+	var info DeviceInfo
+	if ua != "" {
+		info.Browser, info.BrowserVersion = parseBrowser(ua)
+		info.OS, info.OSVersion = parseOS(ua)
+		info.Type = parseDeviceType(ua, info.OS)
+		info.Model = parseModel(ua, info.OS)
+		if name, found := detectBot(ua); found {
+			info.Bot = true
+			info.Type = DeviceBot
+			info.Browser, info.BrowserVersion = name, tokenVersion(ua, name)
+		}
+	}
+
+	hints.apply(&info)
+	if info.Type == "" && (info.Browser != "" || info.OS != "") {
+		info.Type = DeviceOther
+	}
+	return info
+}
+
+// browsers is checked in order: Chromium derivatives carry "Chrome/" and
+// Chrome carries "Safari/", so the more specific tokens come first.
+var browsers = []struct {
+	token, name string
+}{
+	{"edga/", "Edge"},
+	{"edgios/", "Edge"},
+	{"edg/", "Edge"},
+	{"edge/", "Edge"},
+	{"opr/", "Opera"},
+	{"opt/", "Opera"},
+	{"opera/", "Opera"},
+	{"samsungbrowser/", "Samsung Internet"},
+	{"yabrowser/", "Yandex"},
+	{"ucbrowser/", "UC Browser"},
+	{"vivaldi/", "Vivaldi"},
+	{"fxios/", "Firefox"},
+	{"firefox/", "Firefox"},
+	{"crios/", "Chrome"},
+	{"headlesschrome/", "Chrome"},
+	{"chromium/", "Chromium"},
+	{"chrome/", "Chrome"},
+	{"msie ", "Internet Explorer"},
+	{"trident/", "Internet Explorer"},
+	{"okhttp/", "okhttp"},
+	{"cfnetwork/", "CFNetwork"},
+	{"dart/", "Dart"},
+	{"curl/", "curl"},
+	{"wget/", "Wget"},
+	{"python-requests/", "python-requests"},
+	{"go-http-client/", "Go-http-client"},
+	{"version/", "Safari"},
+	{"safari/", "Safari"},
+}
+
+func parseBrowser(ua string) (string, string) {
+	lower := strings.ToLower(ua)
+	for _, b := range browsers {
+		if !strings.Contains(lower, b.token) {
+			continue
+		}
+		switch b.token {
+		case "version/":
+			// Safari puts its own version in Version/, other WebKit
+			// browsers matched above.
+			if !strings.Contains(lower, "safari/") {
+				continue
+			}
+			return b.name, tokenVersion(ua, "Version")
+		case "trident/":
+			return b.name, tokenVersion(ua, "rv:")
+		case "msie ":
+			return b.name, tokenVersion(ua, "MSIE ")
+		}
+		return b.name, tokenVersion(ua, strings.TrimSuffix(b.token, "/"))
+	}
+
+	// Unknown clients usually lead with "name/version".
+	name, rest, found := strings.Cut(ua, "/")
+	if !found || strings.EqualFold(name, "mozilla") || strings.ContainsAny(name, " ;()") {
+		return "", ""
+	}
+	version, _, _ := strings.Cut(rest, " ")
+	return truncate(name, maxNameLength), truncate(version, maxVersionLength)
+}
+
+func parseOS(ua string) (string, string) {
+	lower := strings.ToLower(ua)
+	switch {
+	case strings.Contains(lower, "windows phone"):
+		return "Windows Phone", tokenVersion(ua, "Windows Phone OS ", "Windows Phone ")
+	case strings.Contains(lower, "windows"):
+		return osWindows, windowsVersion(tokenVersion(ua, "Windows NT "))
+	case strings.Contains(lower, "ipad"):
+		return osIPadOS, dotted(tokenVersion(ua, "CPU OS ", "OS "))
+	case strings.Contains(lower, "iphone"), strings.Contains(lower, "ipod"):
+		return osIOS, dotted(tokenVersion(ua, "iPhone OS ", "CPU OS "))
+	case containsWord(lower, "ios"):
+		return osIOS, tokenVersion(ua, "iOS ", "iOS/")
+	case strings.Contains(lower, "android"):
+		return osAndroid, tokenVersion(ua, "Android ")
+	case strings.Contains(lower, "cros "):
+		return osChromeOS, chromeOSVersion(ua)
+	case strings.Contains(lower, "mac os x"), strings.Contains(lower, "macintosh"):
+		return osMacOS, dotted(tokenVersion(ua, "Mac OS X "))
+	case strings.Contains(lower, "linux"):
+		return osLinux, ""
+	}
+	return "", ""
+}
+
+func parseDeviceType(ua, os string) DeviceType {
+	lower := strings.ToLower(ua)
+	switch {
+	case os == osIPadOS, strings.Contains(lower, "tablet"):
+		return DeviceTablet
+	case strings.Contains(lower, "mobile"), os == osIOS, os == "Windows Phone":
+		return DeviceMobile
+	case os == osAndroid:
+		// Android tablets leave "Mobile" out.
+		return DeviceTablet
+	case os == osWindows, os == osMacOS, os == osLinux, os == osChromeOS:
+		return DeviceDesktop
+	}
+	return ""
+}
+
+// parseModel reads the model Android browsers put before " Build/" or the
+// closing parenthesis, e.g. "Pixel 8". Reduced User-Agents say "K".
+func parseModel(ua, os string) string {
+	if os != osAndroid {
+		return ""
+	}
+	start := strings.Index(ua, "(")
+	end := strings.Index(ua, ")")
+	if start < 0 || end < start {
+		return ""
+	}
+	parts := strings.Split(ua[start+1:end], ";")
+	model := strings.TrimSpace(parts[len(parts)-1])
+	model, _, _ = strings.Cut(model, " Build/")
+	if model == "K" || strings.HasPrefix(strings.ToLower(model), "android") || strings.HasPrefix(model, "rv:") {
+		return ""
+	}
+	return truncate(model, maxNameLength)
+}
+
+var botTokens = []struct {
+	token, name string
+}{
+	{"googlebot", "Googlebot"},
+	{"bingbot", "Bingbot"},
+	{"yandexbot", "YandexBot"},
+	{"duckduckbot", "DuckDuckBot"},
+	{"baiduspider", "Baiduspider"},
+	{"applebot", "Applebot"},
+	{"facebookexternalhit", "facebookexternalhit"},
+	{"twitterbot", "Twitterbot"},
+	{"slackbot", "Slackbot"},
+	{"discordbot", "Discordbot"},
+	{"linkedinbot", "LinkedInBot"},
+	{"gptbot", "GPTBot"},
+	{"slurp", "Yahoo! Slurp"},
+	{"headlesschrome", "HeadlessChrome"},
+	{"phantomjs", "PhantomJS"},
+	{"bot", ""},
+	{"crawler", ""},
+	{"spider", ""},
+}
+
+func detectBot(ua string) (string, bool) {
+	lower := strings.ToLower(ua)
+	for _, b := range botTokens {
+		if !strings.Contains(lower, b.token) {
+			continue
+		}
+		if b.name != "" {
+			return b.name, true
+		}
+		return genericBotName(ua, b.token), true
+	}
+	return "", false
+}
+
+// genericBotName picks the word holding token, e.g. "AhrefsBot" from
+// "Mozilla/5.0 (compatible; AhrefsBot/7.0; +http://ahrefs.com/robot/)".
+func genericBotName(ua, token string) string {
+	for _, word := range strings.FieldsFunc(ua, func(r rune) bool {
+		return r == ' ' || r == ';' || r == '(' || r == ')' || r == ','
+	}) {
+		name, _, _ := strings.Cut(word, "/")
+		if strings.Contains(strings.ToLower(name), token) && !strings.HasPrefix(name, "+") && !strings.Contains(name, ":") {
+			return truncate(name, maxNameLength)
+		}
+	}
+	return "Bot"
+}
+
+const (
+	maxNameLength    = 32
+	maxVersionLength = 24
+)
+
+// tokenVersion returns the version following the first of prefixes found
+// in ua, matched case-insensitively, e.g. "128.0" for "Firefox".
+func tokenVersion(ua string, prefixes ...string) string {
+	lower := strings.ToLower(ua)
+	for _, prefix := range prefixes {
+		p := strings.ToLower(prefix)
+		if !strings.HasSuffix(p, " ") && !strings.HasSuffix(p, ":") && !strings.HasSuffix(p, "/") {
+			p += "/"
+		}
+		i := strings.Index(lower, p)
+		if i < 0 {
+			continue
+		}
+		if version := leadingVersion(ua[i+len(p):]); version != "" {
+			return version
+		}
+	}
+	return ""
+}
+
+// leadingVersion returns the digits, dots and underscores s starts with.
+func leadingVersion(s string) string {
+	end := strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= '0' && r <= '9' || r == '.' || r == '_')
+	})
+	if end < 0 {
+		end = len(s)
+	}
+	return truncate(s[:end], maxVersionLength)
+}
+
+// containsWord reports whether word appears in s between non-letters.
+func containsWord(s, word string) bool {
+	for i := 0; ; {
+		j := strings.Index(s[i:], word)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(word)
+		if (start == 0 || !isLetter(s[start-1])) && (end == len(s) || !isLetter(s[end])) {
+			return true
+		}
+		i = start + 1
+	}
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// windowsVersion maps NT versions onto marketing names. Windows 11 still
+// reports NT 10.0; only the platform version hint tells them apart.
+func windowsVersion(nt string) string {
+	switch nt {
+	case "10.0":
+		return "10"
+	case "6.3":
+		return "8.1"
+	case "6.2":
+		return "8"
+	case "6.1":
+		return "7"
+	}
+	return nt
+}
+
+func chromeOSVersion(ua string) string {
+	i := strings.Index(strings.ToLower(ua), "cros ")
+	fields := strings.Fields(ua[i:])
+	if len(fields) < 3 {
+		return ""
+	}
+	return leadingVersion(fields[2])
+}
+
+func dotted(version string) string {
+	return strings.ReplaceAll(version, "_", ".")
+}
+
+func majorVersion(version string) string {
+	major, _, _ := strings.Cut(version, ".")
+	return major
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
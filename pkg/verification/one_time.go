@@ -0,0 +1,172 @@
+package verification
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Purpose binds a one-time token to the flow that issued it, so a magic link
+// can never be replayed as an unsubscribe link and vice versa.
+type Purpose string
+
+const (
+	PurposeEmailVerify   Purpose = "email_verify"
+	PurposeMagicLink     Purpose = "magic_link"
+	PurposeUnsubscribe   Purpose = "unsubscribe"
+	PurposeSessionRevoke Purpose = "session_revoke"
+	PurposeAppeal        Purpose = "suspension_appeal"
+)
+
+const oneTimeTokenPrefix = "one_time_token:"
+
+var (
+	ErrTokenExpired   = errors.New("token expired")
+	ErrTokenConsumed  = errors.New("token already used")
+	ErrPurposeInvalid = errors.New("token purpose mismatch")
+)
+
+type OneTimePayload struct {
+	ID        string            `json:"jti"`
+	Purpose   Purpose           `json:"pur"`
+	Subject   string            `json:"sub"`
+	Data      map[string]string `json:"dat,omitempty"`
+	ExpiresAt int64             `json:"exp"`
+}
+
+// OneTimeTokens issues signed, purpose bound tokens whose single use is
+// enforced by a Redis marker that lives for the token's TTL.
+type OneTimeTokens struct {
+	client redis.Cmdable
+}
+
+func NewOneTimeTokens(client redis.Cmdable) *OneTimeTokens {
+	return &OneTimeTokens{client: client}
+}
+
+func (o *OneTimeTokens) Issue(ctx context.Context, purpose Purpose, subject string, data map[string]string, ttl time.Duration) (string, error) {
+	if purpose == "" || subject == "" || ttl <= 0 {
+		return "", fmt.Errorf("one-time token requires a purpose, subject and positive ttl")
+	}
+
+	payload := OneTimePayload{
+		ID:        uuid.NewString(),
+		Purpose:   purpose,
+		Subject:   subject,
+		Data:      data,
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode token payload: %w", err)
+	}
+
+	signature, err := signOneTime(purpose, raw)
+	if err != nil {
+		return "", err
+	}
+
+	if err := o.client.Set(ctx, oneTimeTokenPrefix+payload.ID, string(purpose), ttl).Err(); err != nil {
+		return "", fmt.Errorf("failed to store token: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw) + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Consume verifies the token for the expected purpose and marks it used.
+// Only the first caller gets the payload back; replays get ErrTokenConsumed.
+func (o *OneTimeTokens) Consume(ctx context.Context, token string, purpose Purpose) (*OneTimePayload, error) {
+	payload, err := o.verify(token, purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := o.client.GetDel(ctx, oneTimeTokenPrefix+payload.ID).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrTokenConsumed
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume token: %w", err)
+	}
+	if stored != string(purpose) {
+		return nil, ErrPurposeInvalid
+	}
+
+	return payload, nil
+}
+
+// Revoke invalidates an unused token, e.g. when a newer link is sent.
+func (o *OneTimeTokens) Revoke(ctx context.Context, token string, purpose Purpose) error {
+	payload, err := o.verify(token, purpose)
+	if err != nil && !errors.Is(err, ErrTokenExpired) {
+		return err
+	}
+	if payload == nil {
+		return nil
+	}
+	return o.client.Del(ctx, oneTimeTokenPrefix+payload.ID).Err()
+}
+
+func (o *OneTimeTokens) verify(token string, purpose Purpose) (*OneTimePayload, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var payload OneTimePayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	// The signature is checked against the purpose the token claims, so a
+	// genuine token from another flow is told apart from a forged one.
+	expected, err := signOneTime(payload.Purpose, raw)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(signature, expected) {
+		return nil, ErrInvalidToken
+	}
+	if payload.Purpose != purpose {
+		return nil, ErrPurposeInvalid
+	}
+	if time.Now().Unix() > payload.ExpiresAt {
+		return &payload, ErrTokenExpired
+	}
+
+	return &payload, nil
+}
+
+// signOneTime keys the MAC with the purpose, so signatures are not
+// transferable between flows and a token's purpose cannot be rewritten.
+func signOneTime(purpose Purpose, raw []byte) ([]byte, error) {
+	if err := loadSecret(); err != nil {
+		return nil, err
+	}
+
+	keyMac := hmac.New(sha256.New, refreshTokenHash)
+	keyMac.Write([]byte("one-time-token:" + string(purpose)))
+
+	h := hmac.New(sha256.New, keyMac.Sum(nil))
+	h.Write(raw)
+	return h.Sum(nil), nil
+}
@@ -58,7 +58,14 @@ func HashToken(token string) (string, error) {
 }
 
 func VerifyTokenHash(token, storedHash string) (bool, error) {
-	return subtle.ConstantTimeCompare([]byte(token), []byte(storedHash)) == 1, nil
+	return Equal(token, storedHash), nil
+}
+
+// Equal compares secrets such as token hashes, codes and state values in
+// constant time, so response timing doesn't reveal how much of a guess
+// matched.
+func Equal(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
 }
 
 func EncryptToken(token string) (string, error) {
@@ -0,0 +1,185 @@
+// Package workerpool runs background jobs on a fixed number of goroutines
+// behind a bounded queue, so traffic spikes are shed instead of spawning an
+// unbounded number of goroutines.
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/abisalde/authentication-service/internal/metrics"
+)
+
+// Policy decides what happens when a job arrives and the queue is full.
+type Policy string
+
+const (
+	// PolicyDrop rejects the job immediately and records the drop.
+	PolicyDrop Policy = "drop"
+	// PolicyBlock waits for queue space until the caller's context ends.
+	PolicyBlock Policy = "block"
+)
+
+const (
+	DefaultWorkers   = 4
+	DefaultQueueSize = 128
+)
+
+var (
+	ErrQueueFull = errors.New("worker pool queue is full")
+	ErrClosed    = errors.New("worker pool is closed")
+)
+
+var (
+	queueDepth = metrics.Default.NewGaugeVec(
+		"worker_pool_queue_depth",
+		"Jobs waiting for a free worker.",
+		"pool",
+	)
+	busyWorkers = metrics.Default.NewGaugeVec(
+		"worker_pool_busy_workers",
+		"Workers currently running a job.",
+		"pool",
+	)
+	jobsTotal = metrics.Default.NewCounterVec(
+		"worker_pool_jobs_total",
+		"Jobs submitted to the pool by outcome: accepted or dropped.",
+		"pool", "outcome",
+	)
+)
+
+type Pool struct {
+	name   string
+	policy Policy
+	jobs   chan func()
+	wg     sync.WaitGroup
+
+	// done is closed by Close to wake submitters blocked on a full queue.
+	// jobs is only closed once every submitter has returned.
+	done    chan struct{}
+	submits sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// New starts workers goroutines draining a queue of queueSize jobs. Non
+// positive sizes fall back to the defaults and unknown policies to drop.
+func New(name string, workers, queueSize int, policy Policy) *Pool {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+	if policy != PolicyBlock {
+		policy = PolicyDrop
+	}
+
+	p := &Pool{
+		name:   name,
+		policy: policy,
+		jobs:   make(chan func(), queueSize),
+		done:   make(chan struct{}),
+	}
+
+	queueDepth.Set(0, name)
+	busyWorkers.Set(0, name)
+
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+
+	return p
+}
+
+func (p *Pool) Name() string {
+	return p.name
+}
+
+// Submit queues the job. It returns ErrQueueFull when the job was shed and
+// ErrClosed once the pool is shutting down, including to a submitter still
+// waiting for queue space when Close is called.
+func (p *Pool) Submit(ctx context.Context, job func()) error {
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return ErrClosed
+	}
+	p.submits.Add(1)
+	p.mu.RUnlock()
+	defer p.submits.Done()
+
+	// Count the job before it is visible to workers so the depth gauge never
+	// dips below zero.
+	queueDepth.Add(1, p.name)
+
+	if p.policy == PolicyBlock {
+		select {
+		case p.jobs <- job:
+			jobsTotal.Inc(p.name, "accepted")
+			return nil
+		case <-ctx.Done():
+			p.dropped()
+			return ErrQueueFull
+		case <-p.done:
+			queueDepth.Add(-1, p.name)
+			return ErrClosed
+		}
+	}
+
+	select {
+	case p.jobs <- job:
+		jobsTotal.Inc(p.name, "accepted")
+		return nil
+	default:
+		p.dropped()
+		return ErrQueueFull
+	}
+}
+
+// Close stops accepting jobs and waits for queued ones to finish.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	p.mu.Unlock()
+
+	close(p.done)
+	p.submits.Wait()
+	close(p.jobs)
+
+	p.wg.Wait()
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+
+	for job := range p.jobs {
+		queueDepth.Add(-1, p.name)
+		busyWorkers.Add(1, p.name)
+		p.run(job)
+		busyWorkers.Add(-1, p.name)
+	}
+}
+
+func (p *Pool) run(job func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("worker pool: job panicked", "pool", p.name, "panic", r)
+		}
+	}()
+	job()
+}
+
+func (p *Pool) dropped() {
+	queueDepth.Add(-1, p.name)
+	jobsTotal.Inc(p.name, "dropped")
+	slog.Warn("worker pool: queue full, job dropped", "pool", p.name)
+}
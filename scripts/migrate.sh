@@ -7,6 +7,8 @@ MIGRATION_DIR="$PROJECT_ROOT/migrations"
 CONFIG_DIR="$PROJECT_ROOT/internal/configs"
 SECRETS_DIR="$PROJECT_ROOT/secrets"
 TIMEOUT_SECONDS=60  # Increased timeout
+# Migrations before this version shipped before the guard existed
+GUARD_SINCE="0006"
 
 # --------------------------
 # Functions
@@ -82,6 +84,19 @@ wait_for_db() {
   done
 }
 
+check_migrations() {
+  local guard_args=(-since "$GUARD_SINCE")
+  if [ "$ALLOW_UNSAFE" = "true" ]; then
+    guard_args+=(-allow-unsafe)
+  fi
+
+  log "🛡️ Checking migrations for unsafe operations"
+  (cd "$PROJECT_ROOT" && go run ./cmd/migrateguard "${guard_args[@]}" "$MIGRATION_DIR"/*.up.sql) || {
+    log "❌ Unsafe migration blocked; rerun with --allow-unsafe once it has been reviewed"
+    exit 1
+  }
+}
+
 run_migrations() {
   local migration_files=($(ls "$MIGRATION_DIR"/*.up.sql | sort))
   for file in "${migration_files[@]}"; do
@@ -101,10 +116,22 @@ log "🚀 Starting database migration"
 
 # Determine environment
 ENVIRONMENT="dev"
-if [ "$1" = "--prod" ] || [ "$1" = "-p" ]; then
-  ENVIRONMENT="prod"
-  log "🔧 Production mode selected"
-fi
+ALLOW_UNSAFE="${MIGRATION_ALLOW_UNSAFE:-false}"
+for arg in "$@"; do
+  case "$arg" in
+    --prod|-p)
+      ENVIRONMENT="prod"
+      log "🔧 Production mode selected"
+      ;;
+    --allow-unsafe)
+      ALLOW_UNSAFE="true"
+      log "⚠️ Unsafe migrations allowed for this run"
+      ;;
+  esac
+done
+
+# Block unsafe migrations before touching the database
+check_migrations
 
 # Load configuration
 load_config "$ENVIRONMENT"